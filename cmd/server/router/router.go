@@ -4,15 +4,20 @@ import (
 	"api/internal/di"
 	adminHandler "api/internal/domains/admin/handler"
 	analyticsHandler "api/internal/domains/analytics/handler"
+	auditLogsHandler "api/internal/domains/audit/audit_logs/handler"
 	staff_activity_logs "api/internal/domains/audit/staff_activity_logs/handler"
 	haircutEvents "api/internal/domains/haircut/event/handler"
 	barberServicesHandler "api/internal/domains/haircut/haircut_service"
 	haircut "api/internal/domains/haircut/portfolio"
 
 	bookingsHandler "api/internal/domains/booking/handler"
+	courtRentalHandler "api/internal/domains/court_rental/handler"
+	giftCardHandler "api/internal/domains/gift_card/handler"
+	referralHandler "api/internal/domains/referral/handler"
 	careerHandler "api/internal/domains/career/handler"
 	courtHandler "api/internal/domains/court/handler"
 	creditPackageHandler "api/internal/domains/credit_package/handler"
+	developmentHandler "api/internal/domains/development/handler"
 	discountHandler "api/internal/domains/discount/handler"
 	enrollmentHandler "api/internal/domains/enrollment/handler"
 	eventHandler "api/internal/domains/event/handler"
@@ -27,16 +32,21 @@ import (
 	notificationHandler "api/internal/domains/notification/handler"
 	payment "api/internal/domains/payment/handler"
 	paymentMiddleware "api/internal/domains/payment/middleware"
+	payrollHandler "api/internal/domains/payroll/handler"
 	playground "api/internal/domains/playground/handler"
 	practice "api/internal/domains/practice/handler"
+	privacyHandler "api/internal/domains/privacy/handler"
 	programHandler "api/internal/domains/program"
+	safetyHandler "api/internal/domains/safety/handler"
 	schedule "api/internal/domains/schedule/handler"
+	staffAvailabilityHandler "api/internal/domains/staff_availability/handler"
 	subsidyHandler "api/internal/domains/subsidy/handler"
 	teamsHandler "api/internal/domains/team"
 	uploadHandler "api/internal/domains/upload/handler"
 	userHandler "api/internal/domains/user/handler"
 	waiverHandler "api/internal/domains/waiver/handler"
 	websitePromoHandler "api/internal/domains/website_promo/handler"
+	"api/internal/jobs"
 	"api/internal/middlewares"
 	contextUtils "api/utils/context"
 
@@ -72,12 +82,16 @@ func RegisterRoutes(router *chi.Mux, container *di.Container) {
 		"/courts":     RegisterCourtsRoutes,
 		"/practices":  RegisterPracticesRoutes,
 		"/bookings":   RegisterBookingsRoutes,
+		"/court-rentals": RegisterCourtRentalsRoutes,
+		"/gift-cards": RegisterGiftCardsRoutes,
+		"/referrals":  RegisterReferralRoutes,
 
 		// Users & Staff routes
 		"/users":     RegisterUserRoutes,
 		"/customers": RegisterCustomerRoutes,
 		"/athletes":  RegisterAthleteRoutes,
 		"/staffs":    RegisterStaffRoutes,
+		"/audit-logs": RegisterAuditLogRoutes,
 		"/upload":    RegisterUploadRoutes,
 
 		// Haircut routes
@@ -89,10 +103,14 @@ func RegisterRoutes(router *chi.Mux, container *di.Container) {
 		"/subscriptions": RegisterSubscriptionRoutes,
 		"/credit_packages": RegisterCreditPackageRoutes,
 		"/subsidies": RegisterSubsidyRoutes,
+		"/receipts": RegisterReceiptsRoutes,
 
 		// Payment & Reporting routes
 		"/admin/payments": RegisterPaymentReportsRoutes,
 		"/admin/collections": RegisterCollectionsRoutes,
+		"/admin/privacy-requests":   RegisterPrivacyRequestRoutes,
+		"/admin/payroll":            RegisterPayrollRoutes,
+		"/admin/staff-availability": RegisterStaffAvailabilityRoutes,
 
 		// Webhooks
 		"/webhooks": RegisterWebhooksRoutes,
@@ -121,6 +139,12 @@ func RegisterRoutes(router *chi.Mux, container *di.Container) {
 
 		// Family routes (parent-child linking)
 		"/family": RegisterFamilyRoutes,
+
+		// Athlete development routes (evaluations, goals, progress reports)
+		"/development": RegisterDevelopmentRoutes,
+
+		// Incident reports and medical information
+		"/safety": RegisterSafetyRoutes,
 	}
 
 	for path, handler := range routeMappings {
@@ -165,6 +189,9 @@ func RegisterCustomerRoutes(container *di.Container) func(chi.Router) {
 		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT)).Post("/{id}/suspend", suspensionHandler.SuspendUser)
 		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT)).Post("/{id}/unsuspend", suspensionHandler.UnsuspendUser)
 		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT)).Post("/{id}/collect-arrears", suspensionHandler.CollectArrears)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT)).Post("/{id}/suspension-schedule", suspensionHandler.ScheduleSuspensionAction)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT)).Get("/{id}/suspension-schedule", suspensionHandler.ListScheduledSuspensionActions)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT)).Delete("/{id}/suspension-schedule/{action_id}", suspensionHandler.CancelScheduledSuspensionAction)
 		r.With(middlewares.JWTAuthMiddleware(true)).Get("/{id}/suspension", suspensionHandler.GetSuspensionInfo)
 	}
 }
@@ -257,6 +284,15 @@ func RegisterMembershipPlansRoutes(container *di.Container) func(chi.Router) {
 		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin)).Put("/{id}", h.UpdateMembershipPlan)
 		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin)).Delete("/{id}", h.DeleteMembershipPlan)
 		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin)).Patch("/{id}/visibility", h.ToggleMembershipPlanVisibility)
+
+		r.Get("/{id}/freeze-policy", h.GetFreezePolicy)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin)).Put("/{id}/freeze-policy", h.SetFreezePolicy)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin)).Delete("/{id}/freeze-policy", h.DeleteFreezePolicy)
+
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin)).Get("/{id}/prices", h.GetPlanPrices)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin)).Post("/{id}/prices", h.SchedulePriceChange)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin)).Delete("/{id}/prices/{price_id}", h.CancelPriceChange)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin)).Get("/{id}/prices/{price_id}/migrations", h.GetPlanPriceMigrations)
 	}
 }
 
@@ -267,6 +303,13 @@ func RegisterGamesRoutes(container *di.Container) func(chi.Router) {
 		r.Get("/", h.GetGames)
 		r.Get("/{id}", h.GetGameById)
 
+		// Live scoring: anyone can follow along, coaches and staff keep score
+		r.Get("/{id}/live", h.GetLiveGame)
+		r.Get("/{id}/live/stream", h.StreamLiveGame)
+		r.Get("/teams/{team_id}/live/stream", h.StreamTeamLiveGames)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleCoach, contextUtils.RoleReceptionist)).Post("/{id}/live/events", h.RecordLiveEvent)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleCoach, contextUtils.RoleReceptionist)).Post("/{id}/live/finalize", h.FinalizeLiveGame)
+
 		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleCoach)).Post("/", h.CreateGame)
 		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleCoach)).Put("/{id}", h.UpdateGame)
 		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleCoach)).Delete("/{id}", h.DeleteGame)
@@ -290,8 +333,14 @@ func RegisterPlaygroundRoutes(container *di.Container) func(chi.Router) {
 	systemHandlers := playground.NewSystemsHandlers(container)
 	return func(r chi.Router) {
 		r.Get("/", h.GetSessions)
+		r.With(middlewares.JWTAuthMiddleware(true)).Get("/me", h.GetMySessions)
 		r.Get("/{id}", h.GetSession)
 		r.With(middlewares.JWTAuthMiddleware(true)).Post("/", h.CreateSession)
+		r.With(middlewares.JWTAuthMiddleware(true)).Post("/{id}/cancel", h.CancelSession)
+		r.With(middlewares.JWTAuthMiddleware(true)).Post("/{id}/extend", h.ExtendSession)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleReceptionist)).Post("/walk-in", h.WalkIn)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleReceptionist)).Post("/{id}/start", h.StartSession)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleReceptionist)).Post("/{id}/stop", h.StopSession)
 		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin)).Delete("/{id}", h.DeleteSession)
 
 		r.Route("/systems", func(r chi.Router) {
@@ -335,6 +384,10 @@ func RegisterLocationsRoutes(container *di.Container) func(chi.Router) {
 		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin)).Post("/", h.CreateLocation)
 		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin)).Put("/{id}", h.UpdateLocation)
 		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin)).Delete("/{id}", h.DeleteLocation)
+
+		r.Get("/{id}/blackouts", h.GetBlackoutDates)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin)).Post("/{id}/blackouts", h.CreateBlackoutDate)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin)).Delete("/{id}/blackouts/{blackout_id}", h.DeleteBlackoutDate)
 	}
 }
 func RegisterCourtsRoutes(container *di.Container) func(chi.Router) {
@@ -374,6 +427,16 @@ func RegisterStaffRoutes(container *di.Container) func(chi.Router) {
 	}
 }
 
+func RegisterAuditLogRoutes(container *di.Container) func(chi.Router) {
+	h := auditLogsHandler.NewHandler(container)
+
+	return func(r chi.Router) {
+		r.Use(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT))
+		r.Get("/", h.SearchAuditLogs)
+		r.Get("/verify", h.VerifyAuditLogChain)
+	}
+}
+
 func RegisterEventRoutes(container *di.Container) func(chi.Router) {
 	handler := eventHandler.NewEventsHandler(container)
 	notificationHandler := eventHandler.NewEventNotificationHandler(container)
@@ -449,6 +512,14 @@ func RegisterCheckoutRoutes(container *di.Container) func(chi.Router) {
 		r.With(middlewares.JWTAuthMiddleware(true)).Post("/events/{id}", h.CheckoutEvent)
 		r.With(middlewares.JWTAuthMiddleware(true)).Get("/events/{id}/options", h.GetEventEnrollmentOptions)
 		r.With(middlewares.JWTAuthMiddleware(true)).Post("/events/{id}/enhanced", h.CheckoutEventEnhanced)
+		r.With(middlewares.JWTAuthMiddleware(true)).Post("/court_rentals", h.CheckoutCourtRental)
+		r.With(middlewares.JWTAuthMiddleware(true)).Post("/playground_sessions", h.CheckoutPlaygroundSession)
+		r.With(middlewares.JWTAuthMiddleware(true)).Post("/gift_cards", h.CheckoutGiftCard)
+
+		// Price breakdowns with automatic discounts, before checkout
+		r.With(middlewares.JWTAuthMiddleware(true)).Get("/membership_plans/{id}/preview", h.PreviewMembershipPrice)
+		r.With(middlewares.JWTAuthMiddleware(true)).Get("/programs/{id}/preview", h.PreviewProgramPrice)
+		r.With(middlewares.JWTAuthMiddleware(true)).Get("/events/{id}/preview", h.PreviewEventPrice)
 
 		// Checkout verification endpoint - called by frontend after redirect from Stripe
 		// This ensures enrollment is complete even if webhooks fail
@@ -510,6 +581,14 @@ func RegisterDiscountRoutes(container *di.Container) func(chi.Router) {
 		r.Get("/", h.GetDiscounts)
 		r.Get("/{id}", h.GetDiscount)
 		r.With(middlewares.JWTAuthMiddleware(true)).Post("/apply", h.ApplyDiscount)
+
+		// Automatic discount rules
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin)).Get("/rules", h.GetDiscountRules)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin)).Get("/rules/{id}", h.GetDiscountRule)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin)).Post("/rules", h.CreateDiscountRule)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin)).Put("/rules/{id}", h.UpdateDiscountRule)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin)).Delete("/rules/{id}", h.DeleteDiscountRule)
+
 		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin)).Post("/", h.CreateDiscount)
 		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin)).Put("/{id}", h.UpdateDiscount)
 		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin)).Delete("/{id}", h.DeleteDiscount)
@@ -538,6 +617,9 @@ func RegisterSecureRoutes(container *di.Container) func(chi.Router) {
 		r.Route("/credits", RegisterSecureCreditRoutes(container))
 		r.Route("/notifications", RegisterSecureNotificationRoutes(container))
 		r.Route("/mobile", RegisterSecureMobileRoutes(container))
+		r.Route("/privacy", RegisterSecurePrivacyRoutes(container))
+		r.Route("/timesheets", RegisterSecureTimesheetRoutes(container))
+		r.Route("/availability", RegisterSecureAvailabilityRoutes(container))
 	}
 }
 
@@ -560,8 +642,11 @@ func RegisterSecureGameRoutes(container *di.Container) func(chi.Router) {
 // RegisterSecureCustomerRoutes registers secure customer routes that require authentication.
 func RegisterSecureCustomerRoutes(container *di.Container) func(chi.Router) {
 	h := userHandler.NewCustomersHandler(container)
+	freezeHandler := userHandler.NewMembershipFreezeHandler(container)
 	return func(r chi.Router) {
 		r.With(middlewares.JWTAuthMiddleware(true)).Get("/memberships", h.GetUserMembershipHistory)
+		r.With(middlewares.JWTAuthMiddleware(true)).Post("/memberships/{subscription_id}/freeze", freezeHandler.RequestFreeze)
+		r.With(middlewares.JWTAuthMiddleware(true)).Delete("/memberships/{subscription_id}/freeze", freezeHandler.EndFreeze)
 		r.With(middlewares.JWTAuthMiddleware(true)).Delete("/delete-account", h.DeleteMyAccount)
 		r.With(middlewares.JWTAuthMiddleware(true)).Post("/recover-account", h.RecoverAccount)
 	}
@@ -598,6 +683,7 @@ func RegisterSecureCreditRoutes(container *di.Container) func(chi.Router) {
 		r.With(middlewares.JWTAuthMiddleware(true)).Get("/", h.GetCustomerCredits)
 		r.With(middlewares.JWTAuthMiddleware(true)).Get("/transactions", h.GetCustomerCreditTransactions)
 		r.With(middlewares.JWTAuthMiddleware(true)).Get("/weekly-usage", h.GetWeeklyUsage)
+		r.With(middlewares.JWTAuthMiddleware(true)).Get("/grants", h.GetCustomerCreditGrants)
 	}
 }
 
@@ -606,6 +692,53 @@ func RegisterBookingsRoutes(container *di.Container) func(chi.Router) {
 	h := bookingsHandler.NewHandler(container)
 	return func(r chi.Router) {
 		r.With(middlewares.JWTAuthMiddleware(true)).Get("/upcoming", h.GetMyUpcomingBookings)
+		r.With(middlewares.JWTAuthMiddleware(true)).Get("/availability", h.GetAvailability)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleCoach, contextUtils.RoleBarber, contextUtils.RoleReceptionist)).Get("/conflicts", h.GetConflicts)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin)).Get("/overrides", h.GetConflictOverrides)
+	}
+}
+
+// RegisterCourtRentalsRoutes registers the court rental routes.
+func RegisterCourtRentalsRoutes(container *di.Container) func(chi.Router) {
+	h := courtRentalHandler.NewHandler(container)
+	return func(r chi.Router) {
+		r.With(middlewares.JWTAuthMiddleware(true)).Get("/slots", h.GetSlots)
+		r.With(middlewares.JWTAuthMiddleware(true)).Get("/me", h.GetMyRentals)
+		r.With(middlewares.JWTAuthMiddleware(true)).Post("/{id}/cancel", h.CancelRental)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleReceptionist)).Get("/", h.GetRentals)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin)).Get("/rates", h.GetRates)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin)).Post("/rates", h.CreateRate)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin)).Put("/rates/{id}", h.UpdateRate)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin)).Delete("/rates/{id}", h.DeleteRate)
+	}
+}
+
+func RegisterGiftCardsRoutes(container *di.Container) func(chi.Router) {
+	h := giftCardHandler.NewHandler(container)
+	return func(r chi.Router) {
+		r.With(middlewares.JWTAuthMiddleware(true)).Get("/balance", h.GetMyBalance)
+		r.With(middlewares.JWTAuthMiddleware(true)).Get("/purchased", h.GetMyPurchases)
+		// Limit guessing at codes - 5 attempts per minute per user
+		r.With(middlewares.JWTAuthMiddleware(true), middlewares.RateLimitMiddleware(5.0/60.0, 5, time.Minute)).Post("/redeem", h.Redeem)
+		r.With(middlewares.JWTAuthMiddleware(true)).Post("/haircuts/{id}/pay", h.PayHaircut)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin)).Get("/liability", h.GetLiability)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleReceptionist)).Get("/", h.GetGiftCards)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleReceptionist)).Post("/", h.IssueGiftCard)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleReceptionist)).Get("/{id}", h.GetGiftCard)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin)).Post("/{id}/void", h.VoidGiftCard)
+	}
+}
+
+func RegisterReferralRoutes(container *di.Container) func(chi.Router) {
+	h := referralHandler.NewHandler(container)
+	return func(r chi.Router) {
+		r.With(middlewares.JWTAuthMiddleware(true)).Get("/me", h.GetMyReferrals)
+		// Public so the sign-up page can show who sent the invite; limited to slow down guessing
+		r.With(middlewares.RateLimitMiddleware(10.0/60.0, 10, time.Minute)).Get("/codes/{code}", h.GetReferrer)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin)).Get("/", h.GetReferrals)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin)).Get("/stats", h.GetStats)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin)).Get("/settings", h.GetSettings)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin)).Put("/settings", h.UpdateSettings)
 	}
 }
 
@@ -652,14 +785,19 @@ func RegisterAdminRoutes(container *di.Container) func(chi.Router) {
 		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT, contextUtils.RoleReceptionist)).Get("/customers/{id}/credits", creditHandler.GetAnyCustomerCredits)
 		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT, contextUtils.RoleReceptionist)).Get("/customers/{id}/credits/transactions", creditHandler.GetAnyCustomerCreditTransactions)
 		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT, contextUtils.RoleReceptionist)).Get("/customers/{id}/credits/weekly-usage", creditHandler.GetAnyCustomerWeeklyUsage)
-		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT)).Post("/customers/{id}/credits/add", creditHandler.AddCustomerCredits)
-		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT)).Post("/customers/{id}/credits/deduct", creditHandler.DeductCustomerCredits)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT, contextUtils.RoleReceptionist)).Get("/customers/{id}/credits/ledger", creditHandler.GetAnyCustomerCreditLedger)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT), middlewares.RequireIdempotencyKey).Post("/customers/{id}/credits/add", creditHandler.AddCustomerCredits)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT), middlewares.RequireIdempotencyKey).Post("/customers/{id}/credits/deduct", creditHandler.DeductCustomerCredits)
 		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT, contextUtils.RoleReceptionist)).Get("/events/{id}/credit-transactions", creditHandler.GetEventCreditTransactions)
 		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT)).Put("/events/{id}/credit-cost", creditHandler.UpdateEventCreditCost)
 
 		// Credit refund audit logs - admin only
 		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT)).Get("/credit-refund-logs", creditHandler.GetCreditRefundLogs)
 
+		// Credit reconciliation - admin only
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT)).Get("/credit-reconciliation", creditHandler.GetCreditReconciliationIssues)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT)).Post("/credit-reconciliation/{id}/resolve", creditHandler.ResolveCreditReconciliationIssue)
+
 		// Firebase cleanup - IT and SuperAdmin only (sensitive operation)
 		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleSuperAdmin, contextUtils.RoleIT)).Post("/firebase/cleanup", firebaseCleanupHandler.CleanupOrphanedFirebaseUsers)
 
@@ -693,6 +831,7 @@ func RegisterSecureNotificationRoutes(container *di.Container) func(chi.Router)
 	return func(r chi.Router) {
 		r.With(middlewares.JWTAuthMiddleware(true)).Post("/register", h.RegisterPushToken)
 		r.With(middlewares.JWTAuthMiddleware(true)).Post("/send", h.SendTeamNotification)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT)).Get("/{id}/delivery", h.GetDeliveryStats)
 	}
 }
 
@@ -705,6 +844,43 @@ func RegisterSecureMobileRoutes(container *di.Container) func(chi.Router) {
 	}
 }
 
+// RegisterSecurePrivacyRoutes registers personal-data export and erasure requests for the logged-in user.
+func RegisterSecurePrivacyRoutes(container *di.Container) func(chi.Router) {
+	h := privacyHandler.NewHandler(container)
+	return func(r chi.Router) {
+		r.Use(middlewares.JWTAuthMiddleware(true))
+		r.Post("/exports", h.RequestExport)
+		r.Post("/erasure", h.RequestErasure)
+		r.Get("/requests", h.GetMyRequests)
+		r.Get("/requests/{id}/download", h.DownloadExport)
+	}
+}
+
+// RegisterSecureTimesheetRoutes registers the logged-in staff member's timesheets and session check-ins.
+func RegisterSecureTimesheetRoutes(container *di.Container) func(chi.Router) {
+	h := payrollHandler.NewHandler(container)
+	return func(r chi.Router) {
+		r.Use(middlewares.JWTAuthMiddleware(false, contextUtils.RoleCoach, contextUtils.RoleInstructor, contextUtils.RoleReceptionist, contextUtils.RoleBarber, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT))
+		r.Get("/", h.GetMyTimesheets)
+		r.Get("/{id}", h.GetMyTimesheet)
+		r.Post("/{id}/submit", h.SubmitTimesheet)
+		r.Post("/check-in", h.CheckIn)
+		r.Post("/check-out", h.CheckOut)
+	}
+}
+
+// RegisterSecureAvailabilityRoutes registers the logged-in coach's weekly availability and time off.
+func RegisterSecureAvailabilityRoutes(container *di.Container) func(chi.Router) {
+	h := staffAvailabilityHandler.NewHandler(container)
+	return func(r chi.Router) {
+		r.Use(middlewares.JWTAuthMiddleware(false, contextUtils.RoleCoach, contextUtils.RoleInstructor, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT))
+		r.Get("/", h.GetMyAvailability)
+		r.Put("/weekly", h.SetMyWeeklyAvailability)
+		r.Post("/time-off", h.AddMyTimeOff)
+		r.Delete("/time-off/{id}", h.DeleteMyTimeOff)
+	}
+}
+
 // RegisterCreditPackageRoutes registers credit package routes (public viewing, admin management)
 func RegisterCreditPackageRoutes(container *di.Container) func(chi.Router) {
 	h := creditPackageHandler.NewCreditPackageHandler(container)
@@ -759,6 +935,9 @@ func RegisterSubsidyRoutes(container *di.Container) func(chi.Router) {
 // RegisterPaymentReportsRoutes registers payment reporting routes
 func RegisterPaymentReportsRoutes(container *di.Container) func(chi.Router) {
 	h := payment.NewPaymentReportsHandler(container)
+	refunds := payment.NewRefundsHandler(container)
+	taxes := payment.NewTaxesHandler(container)
+	receipts := payment.NewReceiptsHandler(container)
 	return func(r chi.Router) {
 		// All routes require admin authentication - receptionist can view
 		r.Use(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT, contextUtils.RoleReceptionist))
@@ -775,6 +954,21 @@ func RegisterPaymentReportsRoutes(container *di.Container) func(chi.Router) {
 		// Export
 		r.Get("/export", h.ExportPaymentTransactions)
 
+		// Refunds and disputes - refunding is admin only, not receptionist
+		r.Get("/transactions/{id}/refunds", refunds.ListTransactionRefunds)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT)).Post("/transactions/{id}/refund", refunds.RefundTransaction)
+		r.Get("/disputes", refunds.ListDisputes)
+
+		// Sales tax - changing rates is admin only, not receptionist
+		r.Get("/tax-rates", taxes.ListTaxRates)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT)).Post("/tax-rates", taxes.CreateTaxRate)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT)).Delete("/tax-rates/{id}", taxes.DeactivateTaxRate)
+		r.Get("/tax-remittance", taxes.GetTaxRemittanceReport)
+
+		// PDF receipts and annual statements
+		r.Get("/transactions/{id}/receipt", receipts.GetTransactionReceipt)
+		r.Get("/customers/{id}/statements/{year}", receipts.GetCustomerStatement)
+
 		// Backfill URLs from Stripe (admin only, not receptionist)
 		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT)).Post("/backfill-urls", h.BackfillPaymentURLs)
 
@@ -783,6 +977,17 @@ func RegisterPaymentReportsRoutes(container *di.Container) func(chi.Router) {
 	}
 }
 
+// RegisterReceiptsRoutes registers customer receipt and statement downloads
+func RegisterReceiptsRoutes(container *di.Container) func(chi.Router) {
+	h := payment.NewReceiptsHandler(container)
+	return func(r chi.Router) {
+		r.Use(middlewares.JWTAuthMiddleware(true))
+
+		r.Get("/transactions/{id}", h.GetMyReceipt)
+		r.Get("/statements/{year}", h.GetMyStatement)
+	}
+}
+
 // RegisterWaiverRoutes registers waiver upload and management routes
 func RegisterWaiverRoutes(container *di.Container) func(chi.Router) {
 	h := waiverHandler.NewWaiverHandler(container)
@@ -837,6 +1042,7 @@ func RegisterWebsitePromoRoutes(container *di.Container) func(chi.Router) {
 // RegisterCollectionsRoutes registers debt collection routes for admin
 func RegisterCollectionsRoutes(container *di.Container) func(chi.Router) {
 	h := payment.NewCollectionsHandler(container)
+	dunningHandler := payment.NewDunningHandler(container)
 	return func(r chi.Router) {
 		// All routes require admin authentication
 		r.Use(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT))
@@ -847,12 +1053,18 @@ func RegisterCollectionsRoutes(container *di.Container) func(chi.Router) {
 		r.Get("/customers/{customer_id}/payment-methods", h.GetCustomerPaymentMethods)
 
 		// Collection actions
-		r.Post("/charge-card", h.ChargeCard)
+		r.With(middlewares.RequireIdempotencyKey).Post("/charge-card", h.ChargeCard)
 		r.Post("/send-payment-link", h.SendPaymentLink)
 		r.Post("/record-manual", h.RecordManualPayment)
 
 		// Collection history
 		r.Get("/attempts", h.GetCollectionAttempts)
+
+		// Dunning of failed membership payments
+		r.Get("/dunning-steps", dunningHandler.ListDunningSteps)
+		r.Put("/dunning-steps", dunningHandler.ReplaceDunningSteps)
+		r.Get("/customers/{customer_id}/dunning", dunningHandler.GetCustomerDunning)
+		r.Get("/queue", dunningHandler.GetCollectionsQueue)
 	}
 }
 
@@ -914,6 +1126,10 @@ func RegisterFamilyRoutes(container *di.Container) func(chi.Router) {
 		// Parent routes - authenticated users only
 		r.With(middlewares.JWTAuthMiddleware(true)).Get("/children", h.GetChildren)
 		r.With(middlewares.JWTAuthMiddleware(true)).Get("/children/{childId}", h.GetChildDetail)
+		r.With(middlewares.JWTAuthMiddleware(true)).Get("/children/{childId}/progress-reports", h.GetChildProgressReports)
+		r.With(middlewares.JWTAuthMiddleware(true)).Get("/children/{childId}/progress-reports/{reportId}/pdf", h.GetChildProgressReportPDF)
+		r.With(middlewares.JWTAuthMiddleware(true)).Get("/children/{childId}/medical", h.GetChildMedicalInfo)
+		r.With(middlewares.JWTAuthMiddleware(true)).Put("/children/{childId}/medical", h.UpdateChildMedicalInfo)
 		r.With(middlewares.JWTAuthMiddleware(true)).Get("/parent", h.GetParent)
 		r.With(middlewares.JWTAuthMiddleware(true)).Get("/siblings", h.GetSiblings)
 
@@ -921,3 +1137,140 @@ func RegisterFamilyRoutes(container *di.Container) func(chi.Router) {
 		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT)).Delete("/admin/link/{id}", h.AdminUnlink)
 	}
 }
+
+// RegisterPrivacyRequestRoutes registers the staff queue for data export and erasure requests
+func RegisterPrivacyRequestRoutes(container *di.Container) func(chi.Router) {
+	h := privacyHandler.NewHandler(container)
+	return func(r chi.Router) {
+		r.Use(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT))
+		r.Get("/", h.GetDataRequests)
+		r.Get("/{id}", h.GetDataRequest)
+		r.Post("/{id}/approve", h.ApproveErasure)
+		r.Post("/{id}/reject", h.RejectErasure)
+		r.Post("/{id}/retry", h.RetryDataRequest)
+	}
+}
+
+// RegisterPayrollRoutes registers pay rates, pay periods, timesheet approval and payroll exports
+func RegisterPayrollRoutes(container *di.Container) func(chi.Router) {
+	h := payrollHandler.NewHandler(container)
+	return func(r chi.Router) {
+		r.Use(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT))
+		r.Route("/rates", func(r chi.Router) {
+			r.Get("/", h.GetPayRates)
+			r.Post("/", h.CreatePayRate)
+			r.Put("/{id}", h.UpdatePayRate)
+			r.Delete("/{id}", h.DeletePayRate)
+		})
+		r.Put("/employees/{staff_id}", h.SetEmployeeID)
+		r.Route("/periods", func(r chi.Router) {
+			r.Get("/", h.GetPayPeriods)
+			r.Post("/", h.CreatePayPeriod)
+			r.Post("/{id}/generate", h.GeneratePayPeriod)
+			r.Post("/{id}/close", h.ClosePayPeriod)
+			r.Post("/{id}/reopen", h.ReopenPayPeriod)
+			r.Get("/{id}/export", h.ExportPayPeriod)
+		})
+		r.Route("/timesheets", func(r chi.Router) {
+			r.Get("/", h.GetTimesheets)
+			r.Get("/{id}", h.GetTimesheet)
+			r.Post("/{id}/approve", h.ApproveTimesheet)
+			r.Post("/{id}/reject", h.RejectTimesheet)
+			r.Post("/{id}/reopen", h.ReopenTimesheet)
+			r.Post("/{id}/adjustments", h.AddAdjustment)
+		})
+		r.Delete("/adjustments/{id}", h.DeleteAdjustment)
+	}
+}
+
+// RegisterStaffAvailabilityRoutes registers coach availability and time off, staff conflict checks,
+// available coach suggestions and the schedule override audit trail
+func RegisterStaffAvailabilityRoutes(container *di.Container) func(chi.Router) {
+	h := staffAvailabilityHandler.NewHandler(container)
+	return func(r chi.Router) {
+		r.Use(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT))
+		r.Get("/conflicts", h.GetStaffConflicts)
+		r.Get("/suggestions", h.SuggestStaff)
+		r.Get("/overrides", h.GetScheduleOverrides)
+		r.Delete("/time-off/{id}", h.DeleteStaffTimeOff)
+		r.Get("/{staff_id}", h.GetStaffAvailability)
+		r.Put("/{staff_id}/weekly", h.SetStaffWeeklyAvailability)
+		r.Post("/{staff_id}/time-off", h.AddStaffTimeOff)
+	}
+}
+
+// RegisterDevelopmentRoutes registers athlete evaluations, skill trends, development goals and
+// progress reports for coaches and admins
+func RegisterDevelopmentRoutes(container *di.Container) func(chi.Router) {
+	h := developmentHandler.NewHandler(container)
+	return func(r chi.Router) {
+		r.Use(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT, contextUtils.RoleCoach, contextUtils.RoleInstructor))
+		r.Route("/templates", func(r chi.Router) {
+			r.Get("/", h.GetTemplates)
+			r.Get("/{id}", h.GetTemplate)
+
+			// Templates are managed by admins only
+			r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT)).Post("/", h.CreateTemplate)
+			r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT)).Put("/{id}", h.UpdateTemplate)
+		})
+		r.Route("/athletes/{athlete_id}", func(r chi.Router) {
+			r.Get("/evaluations", h.GetAthleteEvaluations)
+			r.Post("/evaluations", h.CreateEvaluation)
+			r.Get("/trends", h.GetAthleteTrends)
+			r.Get("/goals", h.GetAthleteGoals)
+			r.Post("/goals", h.CreateGoal)
+			r.Get("/progress-reports", h.GetAthleteProgressReports)
+		})
+		r.Route("/evaluations", func(r chi.Router) {
+			r.Get("/{id}", h.GetEvaluation)
+			r.Put("/{id}", h.UpdateEvaluation)
+			r.Post("/{id}/publish", h.PublishEvaluation)
+			r.Delete("/{id}", h.DeleteEvaluation)
+		})
+		r.Put("/goals/{id}", h.UpdateGoal)
+		r.Delete("/goals/{id}", h.DeleteGoal)
+		r.Get("/progress-reports/{id}/pdf", h.GetProgressReportPDF)
+	}
+}
+
+// RegisterSafetyRoutes registers incident reports and access to users' medical information.
+// Reads of injury and medical details are logged per athlete by the service.
+func RegisterSafetyRoutes(container *di.Container) func(chi.Router) {
+	h := safetyHandler.NewHandler(container)
+	return func(r chi.Router) {
+		r.Use(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT, contextUtils.RoleCoach, contextUtils.RoleInstructor))
+		r.Route("/incidents", func(r chi.Router) {
+			r.Get("/", h.GetIncidents)
+			r.Post("/", h.CreateIncident)
+			r.Get("/{id}", h.GetIncident)
+			r.Put("/{id}", h.UpdateIncident)
+			r.Post("/{id}/follow-ups", h.AddFollowUp)
+			r.Post("/{id}/notify", h.NotifyParents)
+			r.Post("/{id}/attachments", h.UploadAttachment)
+			r.Get("/{id}/attachments/{attachment_id}", h.GetAttachment)
+			r.Delete("/{id}/attachments/{attachment_id}", h.DeleteAttachment)
+		})
+		r.Route("/medical/{user_id}", func(r chi.Router) {
+			r.Get("/", h.GetMedicalInfo)
+
+			// Medical details are edited and audited by admins only
+			r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT)).Put("/", h.UpdateMedicalInfo)
+			r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT)).Get("/access-log", h.GetAccessLog)
+		})
+	}
+}
+
+// RegisterBackgroundJobRoutes registers the admin API for background jobs. It takes the running
+// scheduler rather than the container, since triggering and pausing act on its jobs.
+func RegisterBackgroundJobRoutes(scheduler *jobs.Scheduler) func(chi.Router) {
+	h := adminHandler.NewJobsHandler(scheduler)
+	return func(r chi.Router) {
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT)).Get("/", h.GetJobs)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT)).Get("/{name}/runs", h.GetJobRuns)
+
+		// Starting and pausing jobs - IT and SuperAdmin only (jobs move money and delete accounts)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleSuperAdmin, contextUtils.RoleIT)).Post("/{name}/run", h.TriggerJob)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleSuperAdmin, contextUtils.RoleIT)).Post("/{name}/pause", h.PauseJob)
+		r.With(middlewares.JWTAuthMiddleware(false, contextUtils.RoleSuperAdmin, contextUtils.RoleIT)).Post("/{name}/resume", h.ResumeJob)
+	}
+}
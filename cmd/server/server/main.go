@@ -47,7 +47,7 @@ func main() {
 	diContainer := di.NewContainer()
 	defer diContainer.Cleanup()
 
-	// Set database connection for JWT middleware suspension checks
+	// Set database connection for JWT middleware suspension checks and idempotency keys
 	middlewares.SetDB(diContainer.DB)
 
 	// Initialize and start scheduled jobs
@@ -57,16 +57,42 @@ func main() {
 	scheduler.RegisterJob(jobs.NewAccountDeletionJob(diContainer))
 	scheduler.RegisterJob(jobs.NewReservationCleanupJob(diContainer))
 	scheduler.RegisterJob(jobs.NewCheckoutReconciliationJob(diContainer)) // Safety net for missed webhook payments
+	scheduler.RegisterJob(jobs.NewDunningJob(diContainer))
+	scheduler.RegisterJob(jobs.NewSuspensionJob(diContainer))
+	scheduler.RegisterJob(jobs.NewMembershipFreezeJob(diContainer))
+	scheduler.RegisterJob(jobs.NewPlanPriceJob(diContainer))
+	scheduler.RegisterJob(jobs.NewGiftCardJob(diContainer))
+	scheduler.RegisterJob(jobs.NewReferralRewardJob(diContainer))
+	scheduler.RegisterJob(jobs.NewCreditReconciliationJob(diContainer))
+	scheduler.RegisterJob(jobs.NewPushReceiptJob(diContainer))
+	scheduler.RegisterJob(jobs.NewDataRequestJob(diContainer))
+	scheduler.RegisterJob(jobs.NewIdempotencyKeyCleanupJob(diContainer))
+	scheduler.RegisterJob(jobs.NewGameStatusJob(diContainer))
+	scheduler.RegisterJob(jobs.NewPayrollTimesheetJob(diContainer))
+	scheduler.RegisterJob(jobs.NewProgressReportJob(diContainer))
+
+	// Manual-only jobs, started from the admin jobs API (with dry_run to preview)
+	firebaseCleanup := jobs.NewFirebaseCleanupJob(diContainer)
+	firebaseCleanup.SetDryRun(false)
+	scheduler.RegisterJob(firebaseCleanup)
+	firebaseRecovery := jobs.NewFirebaseRecoveryJob(diContainer)
+	firebaseRecovery.SetDryRun(false)
+	scheduler.RegisterJob(firebaseRecovery)
 
 	scheduler.Start()
 	defer scheduler.Stop()
 
+	// Fan live game scores out to SSE subscribers on this instance
+	diContainer.LiveGames.Start()
+
 	server := &http.Server{
 		Addr:         ":80",
-		Handler:      setupServer(diContainer, swaggerUrl),
+		Handler:      setupServer(diContainer, scheduler, swaggerUrl),
 		ReadTimeout:  10 * time.Second,
-		WriteTimeout: 10 * time.Second,
+		WriteTimeout: 10 * time.Second, // Live game streams lift this for their own responses
 	}
+	// Close live game streams so shutdown doesn't wait on them
+	server.RegisterOnShutdown(diContainer.LiveGames.Stop)
 
 	// Graceful shutdown
 	go func() {
@@ -96,11 +122,12 @@ func main() {
 //
 // Parameters:
 //   - container: Dependency injection container that holds application services like db connections and Gcp service
+//   - scheduler: The running job scheduler, exposed through the admin jobs API
 //   - swaggerUrl: The URL where Swagger documentation will be served from
 //
 // Returns:
 //   - An http.Handler that can be used with an HTTP server
-func setupServer(container *di.Container, swaggerUrl string) http.Handler {
+func setupServer(container *di.Container, scheduler *jobs.Scheduler, swaggerUrl string) http.Handler {
 	r := chi.NewRouter()
 	setupMiddlewares(r)
 
@@ -119,6 +146,7 @@ func setupServer(container *di.Container, swaggerUrl string) http.Handler {
 	setupHealthCheckRoutes(r, container)
 
 	router.RegisterRoutes(r, container)
+	r.Route("/admin/jobs", router.RegisterBackgroundJobRoutes(scheduler))
 	return r
 }
 
@@ -129,6 +157,7 @@ func setupServer(container *di.Container, swaggerUrl string) http.Handler {
 //   - Automatic JSON content type header for responses
 //   - CORS configuration allowing requests from specific origins with
 //     support for credentials, authorized methods, and custom headers
+//   - Idempotency-Key handling so mutating requests can be retried safely
 //
 // Parameters:
 //   - router: The Chi router instance to which middleware will be attached
@@ -140,11 +169,14 @@ func setupMiddlewares(router *chi.Mux) {
 	router.Use(cors.New(cors.Options{
 		AllowedOrigins:   []string{"https://riseadmindashboard.com", "https://rise-web-461776259687.us-central1.run.app", "http://localhost:3000","http://localhost:3001", "https://www.rise-basketball.com", "https://www.risesportscomplex.com", "https://www.riseup-hoops.com"}, // Added all production domains
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS"}, // Added PATCH method
-		AllowedHeaders:   []string{"Content-Type", "Authorization"},
-		ExposedHeaders:   []string{"Authorization"},
+		AllowedHeaders:   []string{"Content-Type", "Authorization", middlewares.IdempotencyKeyHeader, "Last-Event-ID"},
+		ExposedHeaders:   []string{"Authorization", middlewares.IdempotentReplayedHeader},
 		AllowCredentials: true,
 		Debug:            true,
 	}).Handler)
+
+	// After CORS so replayed responses carry the CORS headers too
+	router.Use(middlewares.IdempotencyMiddleware)
 }
 
 // setupHealthCheckRoutes configures health check endpoints for load balancer integration
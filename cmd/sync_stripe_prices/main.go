@@ -2,25 +2,57 @@ package main
 
 import (
 	"database/sql"
+	"flag"
 	"fmt"
 	"log"
 	"os"
 	"strings"
 
 	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
 	"github.com/stripe/stripe-go/v81"
 	"github.com/stripe/stripe-go/v81/price"
-	_ "github.com/lib/pq"
 )
 
 type Plan struct {
 	ID            string
+	Name          string
+	StripePriceID string
+	UnitAmount    sql.NullInt64
+	Currency      sql.NullString
+	Interval      sql.NullString
+}
+
+type PlanPrice struct {
+	ID            string
+	PlanID        string
 	StripePriceID string
+	UnitAmount    int64
+	Currency      string
+	Interval      string
+	Status        string
+}
+
+// drift is one mismatch between the database and Stripe
+type drift struct {
+	PlanName string
+	Message  string
 }
-// This script syncs Stripe pricing data (unit_amount, currency, interval) for all membership plans
-// that have a valid stripe_price_id. It fetches each price from Stripe using the API, then updates
-// the corresponding row in the membership_plans table with the real Stripe price details.
+
+// This script compares the prices of membership plans in the database with Stripe, both ways,
+// and reports every mismatch:
+//   - plans pointing at a Stripe price that is missing or archived
+//   - plan amount, currency or interval that differ from the Stripe price
+//   - price versions (membership.plan_prices) that differ from Stripe or from the plan
+//   - active recurring prices on a plan's Stripe product that no plan or price version knows
+//
+// Nothing is changed unless -pull is given, which copies the Stripe amount, currency and
+// interval onto plans whose own price differs. Price changes are made through the plan's
+// price versions, not by editing Stripe by hand. Exits with status 1 when drift is found.
 func main() {
+	pull := flag.Bool("pull", false, "copy Stripe amount, currency and interval onto plans that differ")
+	flag.Parse()
+
 	err := godotenv.Load("config/.env.local")
 	if err != nil {
 		log.Fatalf("Error loading .env.local: %v", err)
@@ -37,49 +69,199 @@ func main() {
 	}
 	defer db.Close()
 
-	// Fetch all plans with stripe_price_id
+	plans := loadPlans(db)
+	versions := loadPlanPrices(db)
+
+	known := map[string]bool{}
+	for _, plan := range plans {
+		known[plan.StripePriceID] = true
+	}
+	for _, version := range versions {
+		known[version.StripePriceID] = true
+	}
+
+	var drifts []drift
+	stripePrices := map[string]*stripe.Price{}
+	products := map[string]string{} // Stripe product ID -> plan name
+
+	for _, plan := range plans {
+		stripePrice, err := price.Get(plan.StripePriceID, nil)
+		if err != nil {
+			drifts = append(drifts, drift{plan.Name, fmt.Sprintf("price %s could not be fetched from Stripe: %v", plan.StripePriceID, err)})
+			continue
+		}
+		stripePrices[stripePrice.ID] = stripePrice
+		if stripePrice.Product != nil {
+			products[stripePrice.Product.ID] = plan.Name
+		}
+
+		if !stripePrice.Active {
+			drifts = append(drifts, drift{plan.Name, fmt.Sprintf("price %s is archived in Stripe but new subscribers are sold it", stripePrice.ID)})
+		}
+
+		unitAmount, currency, interval := stripeDetails(stripePrice)
+		var differences []string
+		if !plan.UnitAmount.Valid || plan.UnitAmount.Int64 != unitAmount {
+			differences = append(differences, fmt.Sprintf("unit_amount %s vs %d", nullInt(plan.UnitAmount), unitAmount))
+		}
+		if !strings.EqualFold(plan.Currency.String, currency) {
+			differences = append(differences, fmt.Sprintf("currency %q vs %q", plan.Currency.String, currency))
+		}
+		if plan.Interval.String != interval {
+			differences = append(differences, fmt.Sprintf("interval %q vs %q", plan.Interval.String, interval))
+		}
+		if len(differences) == 0 {
+			continue
+		}
+
+		drifts = append(drifts, drift{plan.Name, fmt.Sprintf("plan differs from Stripe price %s (db vs stripe): %s", stripePrice.ID, strings.Join(differences, ", "))})
+
+		if *pull {
+			_, err = db.Exec(`
+				UPDATE membership.membership_plans
+				SET unit_amount = $1, currency = $2, interval = $3, updated_at = CURRENT_TIMESTAMP
+				WHERE id = $4
+			`, unitAmount, currency, interval, plan.ID)
+			if err != nil {
+				log.Printf("Failed to update plan %s: %v", plan.ID, err)
+			} else {
+				fmt.Printf("Pulled %s → $%.2f %s / %s\n", plan.Name, float64(unitAmount)/100, currency, interval)
+			}
+		}
+	}
+
+	planNames := map[string]string{}
+	activeVersions := map[string]string{}
+	for _, plan := range plans {
+		planNames[plan.ID] = plan.Name
+	}
+
+	for _, version := range versions {
+		planName := planNames[version.PlanID]
+		if version.Status == "active" {
+			activeVersions[version.PlanID] = version.StripePriceID
+		}
+
+		stripePrice, ok := stripePrices[version.StripePriceID]
+		if !ok {
+			stripePrice, err = price.Get(version.StripePriceID, nil)
+			if err != nil {
+				drifts = append(drifts, drift{planName, fmt.Sprintf("%s price version %s could not be fetched from Stripe: %v", version.Status, version.StripePriceID, err)})
+				continue
+			}
+		}
+
+		unitAmount, currency, interval := stripeDetails(stripePrice)
+		if version.UnitAmount != unitAmount || !strings.EqualFold(version.Currency, currency) || version.Interval != interval {
+			drifts = append(drifts, drift{planName, fmt.Sprintf("%s price version %s is $%.2f %s / %s but Stripe has $%.2f %s / %s",
+				version.Status, version.StripePriceID, float64(version.UnitAmount)/100, version.Currency, version.Interval,
+				float64(unitAmount)/100, currency, interval)})
+		}
+		if version.Status == "scheduled" && !stripePrice.Active {
+			drifts = append(drifts, drift{planName, fmt.Sprintf("scheduled price version %s is archived in Stripe", version.StripePriceID)})
+		}
+	}
+
+	for _, plan := range plans {
+		if active, ok := activeVersions[plan.ID]; ok && active != plan.StripePriceID {
+			drifts = append(drifts, drift{plan.Name, fmt.Sprintf("plan sells %s but its active price version is %s", plan.StripePriceID, active)})
+		}
+	}
+
+	// Stripe -> database: recurring prices added to a plan's product outside of price versions
+	for productID, planName := range products {
+		params := &stripe.PriceListParams{
+			Product: stripe.String(productID),
+			Active:  stripe.Bool(true),
+			Type:    stripe.String(string(stripe.PriceTypeRecurring)),
+		}
+		iter := price.List(params)
+		for iter.Next() {
+			stripePrice := iter.Price()
+			if !known[stripePrice.ID] {
+				unitAmount, currency, interval := stripeDetails(stripePrice)
+				drifts = append(drifts, drift{planName, fmt.Sprintf("active Stripe price %s ($%.2f %s / %s) is not a price version of any plan",
+					stripePrice.ID, float64(unitAmount)/100, currency, interval)})
+			}
+		}
+		if err := iter.Err(); err != nil {
+			log.Printf("Failed to list prices of Stripe product %s: %v", productID, err)
+		}
+	}
+
+	if len(drifts) == 0 {
+		fmt.Printf("No drift: %d plans and %d price versions match Stripe\n", len(plans), len(versions))
+		return
+	}
+
+	fmt.Printf("Found %d mismatches between the database and Stripe:\n", len(drifts))
+	for _, d := range drifts {
+		fmt.Printf("  [%s] %s\n", d.PlanName, d.Message)
+	}
+	os.Exit(1)
+}
+
+func loadPlans(db *sql.DB) []Plan {
 	rows, err := db.Query(`
-		SELECT id, stripe_price_id 
-		FROM membership.membership_plans 
-		WHERE stripe_price_id IS NOT NULL
+		SELECT id, name, stripe_price_id, unit_amount, currency, interval
+		FROM membership.membership_plans
+		WHERE stripe_price_id IS NOT NULL AND stripe_price_id <> ''
+		ORDER BY name
 	`)
 	if err != nil {
 		log.Fatalf("Query failed: %v", err)
 	}
 	defer rows.Close()
 
+	var plans []Plan
 	for rows.Next() {
 		var plan Plan
-		if err := rows.Scan(&plan.ID, &plan.StripePriceID); err != nil {
+		if err := rows.Scan(&plan.ID, &plan.Name, &plan.StripePriceID, &plan.UnitAmount, &plan.Currency, &plan.Interval); err != nil {
 			log.Printf("Failed to scan row: %v", err)
 			continue
 		}
+		plans = append(plans, plan)
+	}
+	return plans
+}
 
-		// Call Stripe API
-		priceObj, err := price.Get(plan.StripePriceID, nil)
-		if err != nil {
-			log.Printf("Failed to fetch Stripe price for %s: %v", plan.StripePriceID, err)
-			continue
-		}
+func loadPlanPrices(db *sql.DB) []PlanPrice {
+	rows, err := db.Query(`
+		SELECT id, membership_plan_id, stripe_price_id, unit_amount, currency, interval, status
+		FROM membership.plan_prices
+		WHERE status <> 'cancelled'
+		ORDER BY effective_at
+	`)
+	if err != nil {
+		log.Fatalf("Query failed: %v", err)
+	}
+	defer rows.Close()
 
-		unitAmount := int(priceObj.UnitAmount)
-		currency := string(priceObj.Currency)
-		interval := ""
-		if priceObj.Recurring != nil {
-			interval = string(priceObj.Recurring.Interval)
+	var versions []PlanPrice
+	for rows.Next() {
+		var version PlanPrice
+		if err := rows.Scan(&version.ID, &version.PlanID, &version.StripePriceID, &version.UnitAmount,
+			&version.Currency, &version.Interval, &version.Status); err != nil {
+			log.Printf("Failed to scan row: %v", err)
+			continue
 		}
+		versions = append(versions, version)
+	}
+	return versions
+}
 
-		// Update DB row
-		_, err = db.Exec(`
-			UPDATE membership.membership_plans 
-			SET unit_amount = $1, currency = $2, interval = $3 
-			WHERE id = $4
-		`, unitAmount, strings.ToUpper(currency), interval, plan.ID)
+// stripeDetails returns the amount, upper-case currency and interval of a Stripe price
+func stripeDetails(stripePrice *stripe.Price) (int64, string, string) {
+	interval := ""
+	if stripePrice.Recurring != nil {
+		interval = string(stripePrice.Recurring.Interval)
+	}
+	return stripePrice.UnitAmount, strings.ToUpper(string(stripePrice.Currency)), interval
+}
 
-		if err != nil {
-			log.Printf("Failed to update plan %s: %v", plan.ID, err)
-		} else {
-			fmt.Printf("Updated %s → $%.2f %s / %s\n", plan.ID, float64(unitAmount)/100, strings.ToUpper(currency), interval)
-		}
+func nullInt(value sql.NullInt64) string {
+	if !value.Valid {
+		return "NULL"
 	}
+	return fmt.Sprintf("%d", value.Int64)
 }
@@ -34,6 +34,10 @@ type config struct {
 	ChatBotServiceUrl                string
 	FrontendBaseURL                  string // Frontend URL for email verification (supports Universal Links for mobile app)
 	Environment                      string // "production", "staging", or "development"
+	BusinessName                     string // Legal name printed on receipts and statements
+	BusinessAddress                  string // Address printed on receipts and statements
+	ExpoPushBaseURL                  string // Expo push API, overridable so a local stub can stand in
+	ExpoAccessToken                  string // Optional Expo access token for projects with enhanced push security
 }
 
 var Env = initConfig()
@@ -59,6 +63,16 @@ func initConfig() *config {
 		environment = "development"
 	}
 
+	expoPushBaseURL := getEnv("EXPO_PUSH_BASE_URL")
+	if expoPushBaseURL == "" {
+		expoPushBaseURL = "https://exp.host/--/api/v2"
+	}
+
+	businessName := getEnv("BUSINESS_NAME")
+	if businessName == "" {
+		businessName = "Rise Sports Complex"
+	}
+
 	return &config{
 		DbConnUrl:     getEnv("DATABASE_URL"),
 		HubSpotApiKey: getEnv("HUBSPOT_API_KEY"),
@@ -73,6 +87,10 @@ func initConfig() *config {
 		ChatBotServiceUrl:                getEnv("CHAT_BOT_SERVICE_URL"),
 		FrontendBaseURL:                  getEnv("FRONTEND_BASE_URL"),
 		Environment:                      environment,
+		BusinessName:                     businessName,
+		BusinessAddress:                  getEnv("BUSINESS_ADDRESS"),
+		ExpoPushBaseURL:                  expoPushBaseURL,
+		ExpoAccessToken:                  getEnv("EXPO_ACCESS_TOKEN"),
 	}
 }
 
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- IANA timezone for each facility. Recurrence expansion, availability,
+-- weekly credit windows and notification formatting are computed in this zone.
+ALTER TABLE location.locations
+    ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT 'America/Edmonton';
+
+ALTER TABLE location.locations
+    ADD CONSTRAINT chk_locations_timezone_not_blank CHECK (btrim(timezone) <> '');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE location.locations DROP CONSTRAINT IF EXISTS chk_locations_timezone_not_blank;
+ALTER TABLE location.locations DROP COLUMN IF EXISTS timezone;
+-- +goose StatementEnd
@@ -0,0 +1,49 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Stored RFC 5545 rule for each recurring event series. The id is the
+-- recurrence_id shared by the series' rows in events.events. Series created
+-- before this table existed have no row and are edited with the rule sent by
+-- the client.
+CREATE TABLE IF NOT EXISTS events.recurrence_rules
+(
+    id         UUID PRIMARY KEY,
+    rrule      TEXT        NOT NULL,
+    dtstart    DATE        NOT NULL,
+    exdates    DATE[]      NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Holiday and closure calendar per location. Occurrences generated on a
+-- blackout date are either skipped or created and flagged for review.
+CREATE TABLE IF NOT EXISTS location.blackout_dates
+(
+    id          UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    location_id UUID        NOT NULL REFERENCES location.locations (id) ON DELETE CASCADE,
+    name        VARCHAR(150) NOT NULL,
+    starts_on   DATE        NOT NULL,
+    ends_on     DATE        NOT NULL,
+    action      VARCHAR(10) NOT NULL DEFAULT 'skip',
+    created_by  UUID REFERENCES users.users (id) ON DELETE SET NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_blackout_dates_range CHECK (ends_on >= starts_on),
+    CONSTRAINT chk_blackout_dates_action CHECK (action IN ('skip', 'flag'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_blackout_dates_location_range
+    ON location.blackout_dates (location_id, starts_on, ends_on);
+
+ALTER TABLE events.events
+    ADD COLUMN blackout_id UUID REFERENCES location.blackout_dates (id) ON DELETE SET NULL;
+
+ALTER TABLE practice.practices
+    ADD COLUMN blackout_id UUID REFERENCES location.blackout_dates (id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE practice.practices DROP COLUMN IF EXISTS blackout_id;
+ALTER TABLE events.events DROP COLUMN IF EXISTS blackout_id;
+DROP TABLE IF EXISTS location.blackout_dates;
+DROP TABLE IF EXISTS events.recurrence_rules;
+-- +goose StatementEnd
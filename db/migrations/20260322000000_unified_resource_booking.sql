@@ -0,0 +1,179 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE SCHEMA IF NOT EXISTS booking;
+
+-- Audit trail of admin overrides of court booking conflicts. conflicts holds
+-- the clashing bookings as they were when the override was made.
+CREATE TABLE IF NOT EXISTS booking.conflict_overrides
+(
+    id            UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    resource_type VARCHAR(30) NOT NULL,
+    resource_id   UUID        NOT NULL,
+    activity_type VARCHAR(30) NOT NULL,
+    activity_id   UUID,
+    starts_at     TIMESTAMPTZ NOT NULL,
+    ends_at       TIMESTAMPTZ NOT NULL,
+    conflicts     JSONB       NOT NULL DEFAULT '[]',
+    reason        TEXT        NOT NULL,
+    overridden_by UUID REFERENCES users.users (id) ON DELETE SET NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_conflict_overrides_resource
+    ON booking.conflict_overrides (resource_type, resource_id, starts_at);
+
+-- Same check as before, except that a transaction which recorded an admin
+-- override sets booking.court_override and may book across activity types.
+-- Double bookings within one table are still rejected by the exclusion
+-- constraints on events and practices.
+CREATE OR REPLACE FUNCTION location.check_court_availability()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_court_id UUID;
+    v_start_time TIMESTAMPTZ;
+    v_end_time TIMESTAMPTZ;
+    v_table_name TEXT;
+BEGIN
+    IF COALESCE(current_setting('booking.court_override', true), '') = 'on' THEN
+        RETURN NEW;
+    END IF;
+
+    -- Get the table name that triggered this
+    v_table_name := TG_TABLE_SCHEMA || '.' || TG_TABLE_NAME;
+
+    -- Handle different column names across tables
+    IF v_table_name = 'events.events' THEN
+        v_court_id := NEW.court_id;
+        v_start_time := NEW.start_at;
+        v_end_time := NEW.end_at;
+    ELSIF v_table_name = 'practice.practices' THEN
+        v_court_id := NEW.court_id;
+        v_start_time := NEW.start_time;
+        -- Handle NULL end_time (default to 2 hours)
+        v_end_time := COALESCE(NEW.end_time, NEW.start_time + interval '2 hours');
+    ELSIF v_table_name = 'game.games' THEN
+        v_court_id := NEW.court_id;
+        v_start_time := NEW.start_time;
+        -- Handle NULL end_time (default to 2 hours)
+        v_end_time := COALESCE(NEW.end_time, NEW.start_time + interval '2 hours');
+    END IF;
+
+    -- Skip check if no court assigned
+    IF v_court_id IS NULL THEN
+        RETURN NEW;
+    END IF;
+
+    -- Check events table (skip if we're inserting into events)
+    IF v_table_name != 'events.events' THEN
+        IF EXISTS (
+            SELECT 1 FROM events.events e
+            WHERE e.court_id = v_court_id
+              AND e.is_cancelled = false
+              AND tstzrange(e.start_at, e.end_at, '[)') && tstzrange(v_start_time, v_end_time, '[)')
+        ) THEN
+            RAISE EXCEPTION 'Court is already booked by an event during this time';
+        END IF;
+    END IF;
+
+    -- Check practices table (skip if we're inserting into practices)
+    IF v_table_name != 'practice.practices' THEN
+        IF EXISTS (
+            SELECT 1 FROM practice.practices p
+            WHERE p.court_id = v_court_id
+              AND (p.status IS NULL OR p.status != 'canceled')
+              AND tstzrange(p.start_time, COALESCE(p.end_time, p.start_time + interval '2 hours'), '[)') && tstzrange(v_start_time, v_end_time, '[)')
+              AND (TG_OP = 'INSERT' OR p.id != NEW.id)
+        ) THEN
+            RAISE EXCEPTION 'Court is already booked by a practice during this time';
+        END IF;
+    END IF;
+
+    -- Check games table (skip if we're inserting into games)
+    IF v_table_name != 'game.games' THEN
+        IF EXISTS (
+            SELECT 1 FROM game.games g
+            WHERE g.court_id = v_court_id
+              AND (g.status IS NULL OR g.status != 'canceled')
+              AND tstzrange(g.start_time, COALESCE(g.end_time, g.start_time + interval '2 hours'), '[)') && tstzrange(v_start_time, v_end_time, '[)')
+              AND (TG_OP = 'INSERT' OR g.id != NEW.id)
+        ) THEN
+            RAISE EXCEPTION 'Court is already booked by a game during this time';
+        END IF;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION location.check_court_availability()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_court_id UUID;
+    v_start_time TIMESTAMPTZ;
+    v_end_time TIMESTAMPTZ;
+    v_table_name TEXT;
+BEGIN
+    v_table_name := TG_TABLE_SCHEMA || '.' || TG_TABLE_NAME;
+
+    IF v_table_name = 'events.events' THEN
+        v_court_id := NEW.court_id;
+        v_start_time := NEW.start_at;
+        v_end_time := NEW.end_at;
+    ELSIF v_table_name = 'practice.practices' THEN
+        v_court_id := NEW.court_id;
+        v_start_time := NEW.start_time;
+        v_end_time := COALESCE(NEW.end_time, NEW.start_time + interval '2 hours');
+    ELSIF v_table_name = 'game.games' THEN
+        v_court_id := NEW.court_id;
+        v_start_time := NEW.start_time;
+        v_end_time := COALESCE(NEW.end_time, NEW.start_time + interval '2 hours');
+    END IF;
+
+    IF v_court_id IS NULL THEN
+        RETURN NEW;
+    END IF;
+
+    IF v_table_name != 'events.events' THEN
+        IF EXISTS (
+            SELECT 1 FROM events.events e
+            WHERE e.court_id = v_court_id
+              AND e.is_cancelled = false
+              AND tstzrange(e.start_at, e.end_at, '[)') && tstzrange(v_start_time, v_end_time, '[)')
+        ) THEN
+            RAISE EXCEPTION 'Court is already booked by an event during this time';
+        END IF;
+    END IF;
+
+    IF v_table_name != 'practice.practices' THEN
+        IF EXISTS (
+            SELECT 1 FROM practice.practices p
+            WHERE p.court_id = v_court_id
+              AND (p.status IS NULL OR p.status != 'canceled')
+              AND tstzrange(p.start_time, COALESCE(p.end_time, p.start_time + interval '2 hours'), '[)') && tstzrange(v_start_time, v_end_time, '[)')
+              AND (TG_OP = 'INSERT' OR p.id != NEW.id)
+        ) THEN
+            RAISE EXCEPTION 'Court is already booked by a practice during this time';
+        END IF;
+    END IF;
+
+    IF v_table_name != 'game.games' THEN
+        IF EXISTS (
+            SELECT 1 FROM game.games g
+            WHERE g.court_id = v_court_id
+              AND (g.status IS NULL OR g.status != 'canceled')
+              AND tstzrange(g.start_time, COALESCE(g.end_time, g.start_time + interval '2 hours'), '[)') && tstzrange(v_start_time, v_end_time, '[)')
+              AND (TG_OP = 'INSERT' OR g.id != NEW.id)
+        ) THEN
+            RAISE EXCEPTION 'Court is already booked by a game during this time';
+        END IF;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP SCHEMA IF EXISTS booking CASCADE;
+-- +goose StatementEnd
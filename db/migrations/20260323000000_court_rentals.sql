@@ -0,0 +1,254 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Hourly rental rates for a court, one row per day of week and time band
+-- (e.g. "Peak" 17:00-22:00). Times are facility wall-clock times and
+-- day_of_week follows 0 = Sunday ... 6 = Saturday.
+CREATE TABLE IF NOT EXISTS location.court_rental_rates
+(
+    id                    UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    court_id              UUID        NOT NULL REFERENCES location.courts (id) ON DELETE CASCADE,
+    name                  VARCHAR(50) NOT NULL,
+    day_of_week           INTEGER     NOT NULL CHECK (day_of_week BETWEEN 0 AND 6),
+    start_time            TIME        NOT NULL,
+    end_time              TIME        NOT NULL,
+    member_rate_cents     INTEGER     NOT NULL CHECK (member_rate_cents >= 0),
+    non_member_rate_cents INTEGER     NOT NULL CHECK (non_member_rate_cents >= 0),
+    credits_per_hour      INTEGER CHECK (credits_per_hour > 0),
+    is_active             BOOLEAN     NOT NULL DEFAULT true,
+    created_at            TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at            TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT court_rental_rates_time_order CHECK (end_time > start_time)
+);
+
+CREATE INDEX IF NOT EXISTS idx_court_rental_rates_court
+    ON location.court_rental_rates (court_id, day_of_week);
+
+-- A customer's rental of a court. A rental is held while the customer pays
+-- and becomes confirmed once payment succeeds. Holds that pass
+-- hold_expires_at stop blocking the court and are marked expired.
+CREATE TABLE IF NOT EXISTS location.court_rentals
+(
+    id              UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    court_id        UUID        NOT NULL REFERENCES location.courts (id) ON DELETE CASCADE,
+    customer_id     UUID        NOT NULL REFERENCES users.users (id) ON DELETE CASCADE,
+    start_at        TIMESTAMPTZ NOT NULL,
+    end_at          TIMESTAMPTZ NOT NULL,
+    status          VARCHAR(20) NOT NULL DEFAULT 'held'
+        CHECK (status IN ('held', 'confirmed', 'canceled', 'expired')),
+    payment_method  VARCHAR(20) NOT NULL CHECK (payment_method IN ('stripe', 'credits')),
+    is_member       BOOLEAN     NOT NULL DEFAULT false,
+    amount_cents    INTEGER     NOT NULL DEFAULT 0 CHECK (amount_cents >= 0),
+    credits_used    INTEGER     NOT NULL DEFAULT 0 CHECK (credits_used >= 0),
+    currency        VARCHAR(3)  NOT NULL DEFAULT 'cad',
+    hold_expires_at TIMESTAMPTZ NOT NULL,
+    paid_at         TIMESTAMPTZ,
+    canceled_at     TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT court_rentals_time_order CHECK (end_at > start_at),
+    CONSTRAINT no_overlapping_court_rentals
+        EXCLUDE USING GIST (
+            court_id WITH =,
+            tstzrange(start_at, end_at, '[)') WITH &&
+        ) WHERE (status IN ('held', 'confirmed'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_court_rentals_customer
+    ON location.court_rentals (customer_id, start_at);
+CREATE INDEX IF NOT EXISTS idx_court_rentals_held
+    ON location.court_rentals (hold_expires_at) WHERE status = 'held';
+
+-- Rentals take part in the cross-table court check. An admin override may
+-- book over events, practices and games, but never over a customer's rental.
+CREATE OR REPLACE FUNCTION location.check_court_availability()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_court_id UUID;
+    v_start_time TIMESTAMPTZ;
+    v_end_time TIMESTAMPTZ;
+    v_table_name TEXT;
+    v_override BOOLEAN;
+BEGIN
+    v_override := COALESCE(current_setting('booking.court_override', true), '') = 'on';
+
+    -- Get the table name that triggered this
+    v_table_name := TG_TABLE_SCHEMA || '.' || TG_TABLE_NAME;
+
+    -- Handle different column names across tables
+    IF v_table_name = 'events.events' THEN
+        v_court_id := NEW.court_id;
+        v_start_time := NEW.start_at;
+        v_end_time := NEW.end_at;
+    ELSIF v_table_name = 'practice.practices' THEN
+        v_court_id := NEW.court_id;
+        v_start_time := NEW.start_time;
+        -- Handle NULL end_time (default to 2 hours)
+        v_end_time := COALESCE(NEW.end_time, NEW.start_time + interval '2 hours');
+    ELSIF v_table_name = 'game.games' THEN
+        v_court_id := NEW.court_id;
+        v_start_time := NEW.start_time;
+        -- Handle NULL end_time (default to 2 hours)
+        v_end_time := COALESCE(NEW.end_time, NEW.start_time + interval '2 hours');
+    ELSIF v_table_name = 'location.court_rentals' THEN
+        -- Canceled and expired rentals no longer hold the court
+        IF NEW.status NOT IN ('held', 'confirmed') THEN
+            RETURN NEW;
+        END IF;
+        v_court_id := NEW.court_id;
+        v_start_time := NEW.start_at;
+        v_end_time := NEW.end_at;
+    END IF;
+
+    -- Skip check if no court assigned
+    IF v_court_id IS NULL THEN
+        RETURN NEW;
+    END IF;
+
+    -- Check events table (skip if we're inserting into events)
+    IF v_table_name != 'events.events' AND (NOT v_override OR v_table_name = 'location.court_rentals') THEN
+        IF EXISTS (
+            SELECT 1 FROM events.events e
+            WHERE e.court_id = v_court_id
+              AND e.is_cancelled = false
+              AND tstzrange(e.start_at, e.end_at, '[)') && tstzrange(v_start_time, v_end_time, '[)')
+        ) THEN
+            RAISE EXCEPTION 'Court is already booked by an event during this time';
+        END IF;
+    END IF;
+
+    -- Check practices table (skip if we're inserting into practices)
+    IF v_table_name != 'practice.practices' AND (NOT v_override OR v_table_name = 'location.court_rentals') THEN
+        IF EXISTS (
+            SELECT 1 FROM practice.practices p
+            WHERE p.court_id = v_court_id
+              AND (p.status IS NULL OR p.status != 'canceled')
+              AND tstzrange(p.start_time, COALESCE(p.end_time, p.start_time + interval '2 hours'), '[)') && tstzrange(v_start_time, v_end_time, '[)')
+              AND (TG_OP = 'INSERT' OR p.id != NEW.id)
+        ) THEN
+            RAISE EXCEPTION 'Court is already booked by a practice during this time';
+        END IF;
+    END IF;
+
+    -- Check games table (skip if we're inserting into games)
+    IF v_table_name != 'game.games' AND (NOT v_override OR v_table_name = 'location.court_rentals') THEN
+        IF EXISTS (
+            SELECT 1 FROM game.games g
+            WHERE g.court_id = v_court_id
+              AND (g.status IS NULL OR g.status != 'canceled')
+              AND tstzrange(g.start_time, COALESCE(g.end_time, g.start_time + interval '2 hours'), '[)') && tstzrange(v_start_time, v_end_time, '[)')
+              AND (TG_OP = 'INSERT' OR g.id != NEW.id)
+        ) THEN
+            RAISE EXCEPTION 'Court is already booked by a game during this time';
+        END IF;
+    END IF;
+
+    -- Check rentals, which are never overridden (skip if we're inserting into rentals;
+    -- the exclusion constraint covers rental-on-rental)
+    IF v_table_name != 'location.court_rentals' THEN
+        IF EXISTS (
+            SELECT 1 FROM location.court_rentals r
+            WHERE r.court_id = v_court_id
+              AND (r.status = 'confirmed' OR (r.status = 'held' AND r.hold_expires_at > now()))
+              AND tstzrange(r.start_at, r.end_at, '[)') && tstzrange(v_start_time, v_end_time, '[)')
+        ) THEN
+            RAISE EXCEPTION 'Court is already booked by a rental during this time';
+        END IF;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER check_rental_court_availability
+    BEFORE INSERT OR UPDATE ON location.court_rentals
+    FOR EACH ROW
+    EXECUTE FUNCTION location.check_court_availability();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS check_rental_court_availability ON location.court_rentals;
+
+CREATE OR REPLACE FUNCTION location.check_court_availability()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_court_id UUID;
+    v_start_time TIMESTAMPTZ;
+    v_end_time TIMESTAMPTZ;
+    v_table_name TEXT;
+BEGIN
+    IF COALESCE(current_setting('booking.court_override', true), '') = 'on' THEN
+        RETURN NEW;
+    END IF;
+
+    -- Get the table name that triggered this
+    v_table_name := TG_TABLE_SCHEMA || '.' || TG_TABLE_NAME;
+
+    -- Handle different column names across tables
+    IF v_table_name = 'events.events' THEN
+        v_court_id := NEW.court_id;
+        v_start_time := NEW.start_at;
+        v_end_time := NEW.end_at;
+    ELSIF v_table_name = 'practice.practices' THEN
+        v_court_id := NEW.court_id;
+        v_start_time := NEW.start_time;
+        -- Handle NULL end_time (default to 2 hours)
+        v_end_time := COALESCE(NEW.end_time, NEW.start_time + interval '2 hours');
+    ELSIF v_table_name = 'game.games' THEN
+        v_court_id := NEW.court_id;
+        v_start_time := NEW.start_time;
+        -- Handle NULL end_time (default to 2 hours)
+        v_end_time := COALESCE(NEW.end_time, NEW.start_time + interval '2 hours');
+    END IF;
+
+    -- Skip check if no court assigned
+    IF v_court_id IS NULL THEN
+        RETURN NEW;
+    END IF;
+
+    -- Check events table (skip if we're inserting into events)
+    IF v_table_name != 'events.events' THEN
+        IF EXISTS (
+            SELECT 1 FROM events.events e
+            WHERE e.court_id = v_court_id
+              AND e.is_cancelled = false
+              AND tstzrange(e.start_at, e.end_at, '[)') && tstzrange(v_start_time, v_end_time, '[)')
+        ) THEN
+            RAISE EXCEPTION 'Court is already booked by an event during this time';
+        END IF;
+    END IF;
+
+    -- Check practices table (skip if we're inserting into practices)
+    IF v_table_name != 'practice.practices' THEN
+        IF EXISTS (
+            SELECT 1 FROM practice.practices p
+            WHERE p.court_id = v_court_id
+              AND (p.status IS NULL OR p.status != 'canceled')
+              AND tstzrange(p.start_time, COALESCE(p.end_time, p.start_time + interval '2 hours'), '[)') && tstzrange(v_start_time, v_end_time, '[)')
+              AND (TG_OP = 'INSERT' OR p.id != NEW.id)
+        ) THEN
+            RAISE EXCEPTION 'Court is already booked by a practice during this time';
+        END IF;
+    END IF;
+
+    -- Check games table (skip if we're inserting into games)
+    IF v_table_name != 'game.games' THEN
+        IF EXISTS (
+            SELECT 1 FROM game.games g
+            WHERE g.court_id = v_court_id
+              AND (g.status IS NULL OR g.status != 'canceled')
+              AND tstzrange(g.start_time, COALESCE(g.end_time, g.start_time + interval '2 hours'), '[)') && tstzrange(v_start_time, v_end_time, '[)')
+              AND (TG_OP = 'INSERT' OR g.id != NEW.id)
+        ) THEN
+            RAISE EXCEPTION 'Court is already booked by a game during this time';
+        END IF;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TABLE IF EXISTS location.court_rentals;
+DROP TABLE IF EXISTS location.court_rental_rates;
+-- +goose StatementEnd
@@ -0,0 +1,6 @@
+-- +goose Up
+ALTER TYPE credit_transaction_type ADD VALUE IF NOT EXISTS 'court_rental';
+
+-- +goose Down
+-- Note: PostgreSQL does not support removing enum values directly.
+-- To reverse this, you would need to recreate the enum type without 'court_rental'.
@@ -0,0 +1,103 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Booking rules per system. Sessions start on the slot grid from opens_at and last a
+-- whole number of slots, up to max_slots, inside the system's opening hours.
+ALTER TABLE playground.systems
+    ADD COLUMN location_id          UUID REFERENCES location.locations (id) ON DELETE SET NULL,
+    ADD COLUMN slot_minutes         INT  NOT NULL DEFAULT 30,
+    ADD COLUMN max_slots            INT  NOT NULL DEFAULT 4,
+    ADD COLUMN opens_at             TIME NOT NULL DEFAULT '09:00',
+    ADD COLUMN closes_at            TIME NOT NULL DEFAULT '21:00',
+    ADD COLUMN max_active_bookings  INT  NOT NULL DEFAULT 2,
+    ADD COLUMN price_per_slot_cents INT  NOT NULL DEFAULT 0,
+    ADD COLUMN credits_per_slot     INT,
+    ADD CONSTRAINT check_slot_minutes CHECK (slot_minutes BETWEEN 5 AND 240),
+    ADD CONSTRAINT check_max_slots CHECK (max_slots >= 1),
+    ADD CONSTRAINT check_opening_hours CHECK (closes_at > opens_at),
+    ADD CONSTRAINT check_max_active_bookings CHECK (max_active_bookings >= 1),
+    ADD CONSTRAINT check_price_per_slot CHECK (price_per_slot_cents >= 0),
+    ADD CONSTRAINT check_credits_per_slot CHECK (credits_per_slot IS NULL OR credits_per_slot > 0);
+
+-- A session is held while the customer pays by card and confirmed once paid. Staff
+-- move it to in_progress and completed as the customer actually arrives and leaves.
+-- Existing sessions were booked without payment and stay confirmed.
+ALTER TABLE playground.sessions
+    ADD COLUMN status          VARCHAR(20) NOT NULL DEFAULT 'confirmed',
+    ADD COLUMN payment_method  VARCHAR(20) NOT NULL DEFAULT 'free',
+    ADD COLUMN amount_cents    INT         NOT NULL DEFAULT 0,
+    ADD COLUMN credits_used    INT         NOT NULL DEFAULT 0,
+    ADD COLUMN currency        VARCHAR(3)  NOT NULL DEFAULT 'cad',
+    ADD COLUMN hold_expires_at TIMESTAMPTZ,
+    ADD COLUMN paid_at         TIMESTAMPTZ,
+    ADD COLUMN actual_start_at TIMESTAMPTZ,
+    ADD COLUMN actual_end_at   TIMESTAMPTZ,
+    ADD COLUMN canceled_at     TIMESTAMPTZ,
+    ADD CONSTRAINT check_session_status
+        CHECK (status IN ('held', 'confirmed', 'in_progress', 'completed', 'canceled', 'expired')),
+    ADD CONSTRAINT check_session_payment_method
+        CHECK (payment_method IN ('free', 'stripe', 'credits', 'in_person')),
+    ADD CONSTRAINT check_session_amounts CHECK (amount_cents >= 0 AND credits_used >= 0),
+    ADD CONSTRAINT check_session_hold CHECK (status <> 'held' OR hold_expires_at IS NOT NULL);
+
+-- Back-to-back sessions may share a boundary, and canceled or expired sessions no
+-- longer occupy the system.
+ALTER TABLE playground.sessions DROP CONSTRAINT unique_schedule;
+ALTER TABLE playground.sessions
+    ADD CONSTRAINT unique_schedule EXCLUDE USING GIST (
+        system_id WITH =,
+        tstzrange(start_time, end_time, '[)') WITH &&
+    ) WHERE (status IN ('held', 'confirmed', 'in_progress'));
+
+CREATE INDEX idx_playground_sessions_customer_active
+    ON playground.sessions (customer_id, end_time) WHERE status IN ('held', 'confirmed', 'in_progress');
+CREATE INDEX idx_playground_sessions_hold_expiry
+    ON playground.sessions (hold_expires_at) WHERE status = 'held';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS playground.idx_playground_sessions_hold_expiry;
+DROP INDEX IF EXISTS playground.idx_playground_sessions_customer_active;
+
+DELETE FROM playground.sessions WHERE status IN ('held', 'canceled', 'expired');
+
+ALTER TABLE playground.sessions DROP CONSTRAINT unique_schedule;
+ALTER TABLE playground.sessions
+    ADD CONSTRAINT unique_schedule EXCLUDE USING GIST (
+        system_id WITH =,
+        tstzrange(start_time, end_time, '[]') WITH &&
+    );
+
+ALTER TABLE playground.sessions
+    DROP CONSTRAINT check_session_hold,
+    DROP CONSTRAINT check_session_amounts,
+    DROP CONSTRAINT check_session_payment_method,
+    DROP CONSTRAINT check_session_status,
+    DROP COLUMN canceled_at,
+    DROP COLUMN actual_end_at,
+    DROP COLUMN actual_start_at,
+    DROP COLUMN paid_at,
+    DROP COLUMN hold_expires_at,
+    DROP COLUMN currency,
+    DROP COLUMN credits_used,
+    DROP COLUMN amount_cents,
+    DROP COLUMN payment_method,
+    DROP COLUMN status;
+
+ALTER TABLE playground.systems
+    DROP CONSTRAINT check_credits_per_slot,
+    DROP CONSTRAINT check_price_per_slot,
+    DROP CONSTRAINT check_max_active_bookings,
+    DROP CONSTRAINT check_opening_hours,
+    DROP CONSTRAINT check_max_slots,
+    DROP CONSTRAINT check_slot_minutes,
+    DROP COLUMN credits_per_slot,
+    DROP COLUMN price_per_slot_cents,
+    DROP COLUMN max_active_bookings,
+    DROP COLUMN closes_at,
+    DROP COLUMN opens_at,
+    DROP COLUMN max_slots,
+    DROP COLUMN slot_minutes,
+    DROP COLUMN location_id;
+-- +goose StatementEnd
@@ -0,0 +1,6 @@
+-- +goose Up
+ALTER TYPE credit_transaction_type ADD VALUE IF NOT EXISTS 'playground_session';
+
+-- +goose Down
+-- Note: PostgreSQL does not support removing enum values directly.
+-- To reverse this, you would need to recreate the enum type without 'playground_session'.
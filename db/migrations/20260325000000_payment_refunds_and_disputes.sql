@@ -0,0 +1,55 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- One row per Stripe refund, whether staff issued it through the API or in the Stripe
+-- dashboard. payment_transactions.refunded_amount stays the running total.
+CREATE TABLE payments.refunds (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    transaction_id   UUID           NOT NULL REFERENCES payments.payment_transactions (id) ON DELETE CASCADE,
+    stripe_refund_id TEXT,
+    amount           DECIMAL(10, 2) NOT NULL,
+    reason           TEXT,
+    source           TEXT           NOT NULL, -- 'admin' (refund API) or 'stripe' (reconciled from a webhook)
+    access_revoked   BOOLEAN        NOT NULL DEFAULT FALSE,
+    issued_by        UUID           REFERENCES users.users (id) ON DELETE SET NULL,
+    created_at       TIMESTAMPTZ    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT positive_refund_amount CHECK (amount > 0),
+    CONSTRAINT valid_refund_source CHECK (source IN ('admin', 'stripe'))
+);
+
+CREATE UNIQUE INDEX idx_refunds_unique_stripe_refund
+    ON payments.refunds (stripe_refund_id) WHERE stripe_refund_id IS NOT NULL;
+CREATE INDEX idx_refunds_transaction_id ON payments.refunds (transaction_id);
+
+-- Disputes (chargebacks) opened by a customer's bank. Evidence must be submitted in
+-- Stripe before evidence_due_by or the dispute is lost by default.
+CREATE TABLE payments.disputes (
+    id                       UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    stripe_dispute_id        TEXT           NOT NULL UNIQUE,
+    transaction_id           UUID           REFERENCES payments.payment_transactions (id) ON DELETE SET NULL,
+    stripe_charge_id         TEXT           NOT NULL,
+    stripe_payment_intent_id TEXT,
+    amount                   DECIMAL(10, 2) NOT NULL,
+    currency                 TEXT           NOT NULL,
+    reason                   TEXT           NOT NULL,
+    status                   TEXT           NOT NULL,
+    evidence_due_by          TIMESTAMPTZ,
+    closed_at                TIMESTAMPTZ,
+    created_at               TIMESTAMPTZ    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at               TIMESTAMPTZ    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_disputes_open_evidence_due
+    ON payments.disputes (evidence_due_by) WHERE closed_at IS NULL;
+CREATE INDEX idx_disputes_transaction_id ON payments.disputes (transaction_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS payments.disputes;
+DROP TABLE IF EXISTS payments.refunds;
+
+-- +goose StatementEnd
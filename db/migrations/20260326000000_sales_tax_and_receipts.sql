@@ -0,0 +1,56 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Province or territory of each facility. Decides which sales taxes are charged
+-- for purchases made at the facility.
+ALTER TABLE location.locations
+    ADD COLUMN province CHAR(2) NOT NULL DEFAULT 'AB';
+
+ALTER TABLE location.locations
+    ADD CONSTRAINT chk_locations_province CHECK (
+        province IN ('AB', 'BC', 'MB', 'NB', 'NL', 'NS', 'NT', 'NU', 'ON', 'PE', 'QC', 'SK', 'YT')
+    );
+
+-- Sales tax rates charged per province. Each row mirrors a Stripe tax rate, which
+-- cannot change its percentage once created, so a rate change deactivates the old
+-- row and adds a new one. Deactivated rows stay for receipts and remittance reports.
+CREATE TABLE IF NOT EXISTS payments.tax_rates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    province CHAR(2) NOT NULL,
+    name VARCHAR(10) NOT NULL CHECK (name IN ('GST', 'PST', 'HST', 'QST', 'RST')),
+    rate DECIMAL(6, 3) NOT NULL CHECK (rate > 0 AND rate < 100),
+    registration_number VARCHAR(50),
+    stripe_tax_rate_id VARCHAR(255) NOT NULL UNIQUE,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- A province charges each tax at most once
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tax_rates_active_province_name
+    ON payments.tax_rates (province, name) WHERE is_active;
+
+-- Tax breakdown of a payment, one row per tax charged. Name, province and rate are
+-- copied so the breakdown survives rate changes.
+CREATE TABLE IF NOT EXISTS payments.transaction_taxes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    transaction_id UUID NOT NULL REFERENCES payments.payment_transactions(id) ON DELETE CASCADE,
+    tax_rate_id UUID REFERENCES payments.tax_rates(id) ON DELETE SET NULL,
+    name VARCHAR(50) NOT NULL,
+    province VARCHAR(10) NOT NULL DEFAULT '',
+    rate DECIMAL(6, 3) NOT NULL,
+    taxable_amount DECIMAL(10, 2) NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (transaction_id, name, province)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_taxes_transaction ON payments.transaction_taxes (transaction_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS payments.transaction_taxes;
+DROP TABLE IF EXISTS payments.tax_rates;
+ALTER TABLE location.locations DROP CONSTRAINT IF EXISTS chk_locations_province;
+ALTER TABLE location.locations DROP COLUMN IF EXISTS province;
+-- +goose StatementEnd
@@ -0,0 +1,76 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Dunning schedule for failed membership payments. Each step runs once per failed
+-- invoice, day_offset days after the payment failed.
+CREATE TABLE payments.dunning_steps (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    day_offset INT         NOT NULL,
+    action     TEXT        NOT NULL, -- 'remind', 'restrict_booking', 'suspend' or 'collections'
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT valid_dunning_step_day CHECK (day_offset BETWEEN 0 AND 365),
+    CONSTRAINT valid_dunning_step_action CHECK (action IN ('remind', 'restrict_booking', 'suspend', 'collections')),
+    CONSTRAINT unique_dunning_step UNIQUE (day_offset, action)
+);
+
+INSERT INTO payments.dunning_steps (day_offset, action)
+VALUES (1, 'remind'),
+       (3, 'remind'),
+       (7, 'remind'),
+       (10, 'restrict_booking'),
+       (14, 'suspend'),
+       (21, 'collections');
+
+-- One case per failed membership invoice. 'open' cases move through the schedule,
+-- 'collections' cases wait for staff, and a paid invoice resolves the case.
+CREATE TABLE payments.dunning_cases (
+    id                     UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    customer_id            UUID           NOT NULL REFERENCES users.users (id) ON DELETE CASCADE,
+    stripe_invoice_id      TEXT           NOT NULL UNIQUE,
+    stripe_subscription_id TEXT,
+    amount_due             DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    currency               TEXT           NOT NULL DEFAULT 'cad',
+    failed_at              TIMESTAMPTZ    NOT NULL,
+    status                 TEXT           NOT NULL DEFAULT 'open',
+    booking_restricted     BOOLEAN        NOT NULL DEFAULT FALSE,
+    suspended              BOOLEAN        NOT NULL DEFAULT FALSE, -- the customer was suspended by this case
+    resolved_at            TIMESTAMPTZ,
+    created_at             TIMESTAMPTZ    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at             TIMESTAMPTZ    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT valid_dunning_case_status CHECK (status IN ('open', 'collections', 'resolved'))
+);
+
+CREATE INDEX idx_dunning_cases_customer_id ON payments.dunning_cases (customer_id);
+CREATE INDEX idx_dunning_cases_unresolved ON payments.dunning_cases (status, failed_at) WHERE status <> 'resolved';
+CREATE INDEX idx_dunning_cases_subscription ON payments.dunning_cases (stripe_subscription_id) WHERE status <> 'resolved';
+
+-- Timeline of a case. A step is recorded before it runs, so the unique constraint
+-- keeps two job runs from applying the same step twice.
+CREATE TABLE payments.dunning_events (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    case_id    UUID        NOT NULL REFERENCES payments.dunning_cases (id) ON DELETE CASCADE,
+    action     TEXT        NOT NULL,
+    day_offset INT,
+    detail     TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT valid_dunning_event_action CHECK (
+        action IN ('payment_failed', 'remind', 'restrict_booking', 'suspend', 'collections', 'resolved')
+    ),
+    CONSTRAINT unique_dunning_event_step UNIQUE (case_id, action, day_offset)
+);
+
+CREATE INDEX idx_dunning_events_case_id ON payments.dunning_events (case_id, created_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS payments.dunning_events;
+DROP TABLE IF EXISTS payments.dunning_cases;
+DROP TABLE IF EXISTS payments.dunning_steps;
+
+-- +goose StatementEnd
@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Suspensions and unsuspensions staff schedule ahead of time. The suspension job runs
+-- each action once run_at has passed, with the same workflow as the admin endpoints.
+CREATE TABLE users.scheduled_suspension_actions (
+    id                          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id                     UUID        NOT NULL REFERENCES users.users (id) ON DELETE CASCADE,
+    action                      TEXT        NOT NULL, -- 'suspend' or 'unsuspend'
+    run_at                      TIMESTAMPTZ NOT NULL,
+    suspension_reason           TEXT,                 -- suspend only
+    suspension_duration_seconds BIGINT,               -- suspend only, NULL = indefinite
+    extend_membership           BOOLEAN     NOT NULL DEFAULT FALSE, -- unsuspend only
+    collect_arrears             BOOLEAN     NOT NULL DEFAULT FALSE, -- unsuspend only
+    status                      TEXT        NOT NULL DEFAULT 'pending',
+    scheduled_by                UUID        NOT NULL REFERENCES staff.staff (id) ON DELETE CASCADE,
+    executed_at                 TIMESTAMPTZ,
+    failure_reason              TEXT,
+    created_at                  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at                  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT valid_scheduled_suspension_action CHECK (action IN ('suspend', 'unsuspend')),
+    CONSTRAINT valid_scheduled_suspension_status CHECK (status IN ('pending', 'completed', 'failed', 'cancelled')),
+    CONSTRAINT scheduled_suspension_has_reason CHECK (action <> 'suspend' OR suspension_reason IS NOT NULL)
+);
+
+CREATE INDEX idx_scheduled_suspension_actions_user_id ON users.scheduled_suspension_actions (user_id, run_at);
+CREATE INDEX idx_scheduled_suspension_actions_due ON users.scheduled_suspension_actions (run_at) WHERE status = 'pending';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS users.scheduled_suspension_actions;
+
+-- +goose StatementEnd
@@ -0,0 +1,61 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Rules for customer self-service freezes of a membership plan. Plans without a
+-- policy cannot be frozen by customers.
+CREATE TABLE membership.freeze_policies (
+    membership_plan_id           UUID PRIMARY KEY REFERENCES membership.membership_plans (id) ON DELETE CASCADE,
+    min_days                     INTEGER     NOT NULL,
+    max_days                     INTEGER     NOT NULL,
+    max_freezes_per_year         INTEGER     NOT NULL,
+    fee_amount                   INTEGER     NOT NULL DEFAULT 0, -- in cents, charged when the freeze is requested
+    injury_requires_medical_note BOOLEAN     NOT NULL DEFAULT TRUE,
+    created_at                   TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at                   TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT valid_freeze_length CHECK (min_days > 0 AND max_days >= min_days),
+    CONSTRAINT valid_freezes_per_year CHECK (max_freezes_per_year > 0),
+    CONSTRAINT valid_freeze_fee CHECK (fee_amount >= 0)
+);
+
+-- Freezes of Stripe subscription memberships. The membership is frozen from start_date
+-- and resumes on end_date; the freeze job starts and ends them on those days.
+CREATE TABLE users.membership_freezes (
+    id                          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    customer_id                 UUID        NOT NULL REFERENCES users.users (id) ON DELETE CASCADE,
+    customer_membership_plan_id UUID        NOT NULL REFERENCES users.customer_membership_plans (id) ON DELETE CASCADE,
+    stripe_subscription_id      TEXT        NOT NULL,
+    reason                      TEXT        NOT NULL, -- 'vacation', 'injury' or 'other'
+    notes                       TEXT,
+    medical_note_url            TEXT,
+    start_date                  DATE        NOT NULL,
+    end_date                    DATE        NOT NULL,
+    status                      TEXT        NOT NULL DEFAULT 'scheduled',
+    fee_amount                  INTEGER     NOT NULL DEFAULT 0, -- in cents
+    stripe_fee_invoice_id       TEXT,
+    credits_forfeited           INTEGER     NOT NULL DEFAULT 0,
+    started_at                  TIMESTAMPTZ,
+    ended_at                    TIMESTAMPTZ,
+    created_at                  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at                  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT valid_membership_freeze_reason CHECK (reason IN ('vacation', 'injury', 'other')),
+    CONSTRAINT valid_membership_freeze_status CHECK (status IN ('scheduled', 'active', 'completed', 'cancelled')),
+    CONSTRAINT valid_membership_freeze_dates CHECK (end_date > start_date)
+);
+
+CREATE INDEX idx_membership_freezes_customer_id ON users.membership_freezes (customer_id, start_date);
+CREATE INDEX idx_membership_freezes_due ON users.membership_freezes (status, start_date, end_date) WHERE status IN ('scheduled', 'active');
+
+-- A membership has at most one upcoming or running freeze
+CREATE UNIQUE INDEX idx_membership_freezes_one_open ON users.membership_freezes (customer_membership_plan_id) WHERE status IN ('scheduled', 'active');
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS users.membership_freezes;
+DROP TABLE IF EXISTS membership.freeze_policies;
+
+-- +goose StatementEnd
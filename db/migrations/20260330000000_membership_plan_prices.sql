@@ -0,0 +1,78 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Price versions of a membership plan. The active version is mirrored onto
+-- membership_plans (stripe_price_id, unit_amount, currency, interval) and is what new
+-- subscribers pay; a scheduled version replaces it at effective_at.
+CREATE TABLE membership.plan_prices (
+    id                      UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    membership_plan_id      UUID        NOT NULL REFERENCES membership.membership_plans (id) ON DELETE CASCADE,
+    stripe_price_id         TEXT        NOT NULL UNIQUE,
+    unit_amount             INTEGER     NOT NULL, -- in cents
+    currency                TEXT        NOT NULL,
+    interval                TEXT        NOT NULL,
+    interval_count          INTEGER     NOT NULL DEFAULT 1,
+    effective_at            TIMESTAMPTZ NOT NULL,
+    -- 'grandfather' keeps existing subscribers on their price, 'migrate' moves them to
+    -- this price at their first renewal on or after effective_at
+    existing_subscribers    TEXT        NOT NULL DEFAULT 'grandfather',
+    notice_days             INTEGER     NOT NULL DEFAULT 30,
+    status                  TEXT        NOT NULL DEFAULT 'scheduled',
+    activated_at            TIMESTAMPTZ,
+    migrations_scheduled_at TIMESTAMPTZ,
+    created_by              UUID REFERENCES users.users (id) ON DELETE SET NULL,
+    created_at              TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at              TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT valid_plan_price_amount CHECK (unit_amount > 0),
+    CONSTRAINT valid_plan_price_existing_subscribers CHECK (existing_subscribers IN ('grandfather', 'migrate')),
+    CONSTRAINT valid_plan_price_notice_days CHECK (notice_days >= 0),
+    CONSTRAINT valid_plan_price_status CHECK (status IN ('scheduled', 'active', 'superseded', 'cancelled'))
+);
+
+CREATE INDEX idx_plan_prices_membership_plan_id ON membership.plan_prices (membership_plan_id, effective_at);
+
+-- A plan has one current price and at most one pending price change
+CREATE UNIQUE INDEX idx_plan_prices_one_active ON membership.plan_prices (membership_plan_id) WHERE status = 'active';
+CREATE UNIQUE INDEX idx_plan_prices_one_scheduled ON membership.plan_prices (membership_plan_id) WHERE status = 'scheduled';
+
+-- Existing subscribers moved to a new price version through a Stripe subscription schedule
+CREATE TABLE membership.plan_price_migrations (
+    id                          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    plan_price_id               UUID        NOT NULL REFERENCES membership.plan_prices (id) ON DELETE CASCADE,
+    customer_membership_plan_id UUID        NOT NULL REFERENCES users.customer_membership_plans (id) ON DELETE CASCADE,
+    customer_id                 UUID        NOT NULL REFERENCES users.users (id) ON DELETE CASCADE,
+    stripe_subscription_id      TEXT        NOT NULL,
+    stripe_schedule_id          TEXT,
+    from_stripe_price_id        TEXT,
+    switch_at                   TIMESTAMPTZ,
+    status                      TEXT        NOT NULL,
+    failure_reason              TEXT,
+    notified_at                 TIMESTAMPTZ,
+    created_at                  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at                  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT valid_plan_price_migration_status CHECK (status IN ('scheduled', 'completed', 'skipped', 'failed', 'cancelled')),
+    CONSTRAINT unique_plan_price_migration UNIQUE (plan_price_id, customer_membership_plan_id)
+);
+
+CREATE INDEX idx_plan_price_migrations_due ON membership.plan_price_migrations (switch_at) WHERE status = 'scheduled';
+
+-- Every plan starts with its current price as the active version
+INSERT INTO membership.plan_prices (membership_plan_id, stripe_price_id, unit_amount, currency, interval,
+                                    effective_at, status, activated_at, notice_days)
+SELECT id, stripe_price_id, unit_amount, UPPER(COALESCE(currency, 'cad')), COALESCE(interval, 'month'),
+       created_at, 'active', created_at, 0
+FROM membership.membership_plans
+WHERE stripe_price_id <> ''
+  AND unit_amount > 0;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS membership.plan_price_migrations;
+DROP TABLE IF EXISTS membership.plan_prices;
+
+-- +goose StatementEnd
@@ -0,0 +1,57 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Discounts the system applies automatically at checkout, without a code.
+--   sibling:        the customer's parent already has min_siblings other children enrolled
+--   early_bird:     bought before early_bird_deadline
+--   member_pricing: the customer holds an active membership (of required_membership_plan_id, if set)
+--   bundle:         the purchase makes min_programs active program enrollments
+-- Eligible rules are applied in priority order (lowest first). Stackable rules combine with
+-- each other; a rule that isn't stackable only ever applies on its own.
+CREATE TABLE discount_rules (
+    id                          UUID PRIMARY KEY      DEFAULT gen_random_uuid(),
+    name                        VARCHAR(100) NOT NULL,
+    description                 TEXT,
+    rule_type                   TEXT         NOT NULL,
+    applies_to                  TEXT         NOT NULL, -- 'membership', 'program' or 'event'
+    target_id                   UUID,                  -- a single plan, program or event; NULL for all of them
+    discount_type               discount_type NOT NULL,
+    discount_percent            INTEGER      NOT NULL DEFAULT 0,
+    amount_off                  INTEGER      NOT NULL DEFAULT 0, -- in cents
+    min_siblings                INTEGER,
+    early_bird_deadline         TIMESTAMPTZ,
+    required_membership_plan_id UUID REFERENCES membership.membership_plans (id) ON DELETE CASCADE,
+    min_programs                INTEGER,
+    priority                    INTEGER      NOT NULL DEFAULT 100,
+    stackable                   BOOLEAN      NOT NULL DEFAULT TRUE,
+    is_active                   BOOLEAN      NOT NULL DEFAULT TRUE,
+    valid_from                  TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    valid_to                    TIMESTAMPTZ,
+    created_at                  TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at                  TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT valid_discount_rule_type CHECK (rule_type IN ('sibling', 'early_bird', 'member_pricing', 'bundle')),
+    CONSTRAINT valid_discount_rule_applies_to CHECK (applies_to IN ('membership', 'program', 'event')),
+    CONSTRAINT valid_discount_rule_value CHECK (
+        (discount_type = 'percentage' AND discount_percent BETWEEN 1 AND 100 AND amount_off = 0) OR
+        (discount_type = 'fixed_amount' AND amount_off > 0 AND discount_percent = 0)
+    ),
+    CONSTRAINT valid_discount_rule_condition CHECK (
+        (rule_type = 'sibling' AND min_siblings >= 1) OR
+        (rule_type = 'early_bird' AND early_bird_deadline IS NOT NULL) OR
+        (rule_type = 'member_pricing') OR
+        (rule_type = 'bundle' AND min_programs >= 2 AND applies_to = 'program')
+    ),
+    CONSTRAINT valid_discount_rule_dates CHECK (valid_to IS NULL OR valid_to > valid_from)
+);
+
+CREATE INDEX idx_discount_rules_applies_to ON discount_rules (applies_to, priority) WHERE is_active;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS discount_rules;
+
+-- +goose StatementEnd
@@ -0,0 +1,110 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE SCHEMA IF NOT EXISTS gift_cards;
+
+-- Prepaid value a customer can spend at checkout, in cents. It only ever changes
+-- together with a gift_cards.ledger_entries row.
+ALTER TABLE users.users
+    ADD COLUMN stored_value_balance INTEGER NOT NULL DEFAULT 0,
+    ADD CONSTRAINT stored_value_balance_non_negative CHECK (stored_value_balance >= 0);
+
+-- A gift card bought online or issued by staff for a physical certificate. Cards
+-- bought online stay pending until their checkout is paid. remaining_cents is the
+-- value not yet redeemed into a customer's balance; it can be redeemed in parts.
+-- Codes are stored uppercase without spaces or dashes. A card without expires_at
+-- never expires.
+CREATE TABLE IF NOT EXISTS gift_cards.gift_cards
+(
+    id                         UUID PRIMARY KEY      DEFAULT gen_random_uuid(),
+    code                       VARCHAR(32)  NOT NULL UNIQUE,
+    initial_cents              INTEGER      NOT NULL CHECK (initial_cents > 0),
+    remaining_cents            INTEGER      NOT NULL CHECK (remaining_cents >= 0),
+    currency                   VARCHAR(3)   NOT NULL DEFAULT 'cad',
+    status                     VARCHAR(20)  NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'active', 'redeemed', 'expired', 'void')),
+    purchaser_id               UUID REFERENCES users.users (id) ON DELETE SET NULL,
+    recipient_name             VARCHAR(100),
+    recipient_email            VARCHAR(255),
+    message                    TEXT,
+    stripe_checkout_session_id TEXT UNIQUE,
+    issued_by                  UUID REFERENCES users.users (id) ON DELETE SET NULL,
+    issued_at                  TIMESTAMPTZ,
+    expires_at                 TIMESTAMPTZ,
+    voided_by                  UUID REFERENCES users.users (id) ON DELETE SET NULL,
+    voided_at                  TIMESTAMPTZ,
+    void_reason                TEXT,
+    created_at                 TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at                 TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT gift_cards_remaining_within_initial CHECK (remaining_cents <= initial_cents)
+);
+
+CREATE INDEX IF NOT EXISTS idx_gift_cards_status ON gift_cards.gift_cards (status, created_at);
+CREATE INDEX IF NOT EXISTS idx_gift_cards_purchaser ON gift_cards.gift_cards (purchaser_id);
+CREATE INDEX IF NOT EXISTS idx_gift_cards_expiring
+    ON gift_cards.gift_cards (expires_at) WHERE status = 'active' AND expires_at IS NOT NULL;
+
+-- Balance set aside for a checkout that has not been paid yet. Its spend entry has
+-- already taken the amount off the customer's balance. The payment webhook captures
+-- the hold; a hold still held after expires_at goes back to the balance.
+CREATE TABLE IF NOT EXISTS gift_cards.balance_holds
+(
+    id           UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    customer_id  UUID        NOT NULL REFERENCES users.users (id) ON DELETE CASCADE,
+    amount_cents INTEGER     NOT NULL CHECK (amount_cents > 0),
+    description  TEXT        NOT NULL,
+    status       VARCHAR(20) NOT NULL DEFAULT 'held'
+        CHECK (status IN ('held', 'captured', 'released')),
+    expires_at   TIMESTAMPTZ NOT NULL,
+    captured_at  TIMESTAMPTZ,
+    released_at  TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_balance_holds_held
+    ON gift_cards.balance_holds (expires_at) WHERE status = 'held';
+
+-- Every change to gift card value and customer balances. amount_cents is always
+-- positive; entry_type says where the value went:
+--   issue:   a card was paid for or issued (card +)
+--   redeem:  card value moved into a customer's balance (card -, balance +)
+--   spend:   balance went toward a purchase (balance -)
+--   release: a spend whose checkout was never paid went back (balance +)
+--   expire:  unredeemed card value lapsed at expires_at (card -)
+--   void:    staff voided a card's unredeemed value (card -)
+CREATE TABLE IF NOT EXISTS gift_cards.ledger_entries
+(
+    id               UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    entry_type       VARCHAR(20) NOT NULL
+        CHECK (entry_type IN ('issue', 'redeem', 'spend', 'release', 'expire', 'void')),
+    gift_card_id     UUID REFERENCES gift_cards.gift_cards (id) ON DELETE RESTRICT,
+    customer_id      UUID REFERENCES users.users (id) ON DELETE SET NULL,
+    hold_id          UUID REFERENCES gift_cards.balance_holds (id) ON DELETE SET NULL,
+    haircut_event_id UUID REFERENCES haircut.events (id) ON DELETE SET NULL,
+    amount_cents     INTEGER     NOT NULL CHECK (amount_cents > 0),
+    description      TEXT        NOT NULL,
+    created_by       UUID REFERENCES users.users (id) ON DELETE SET NULL,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_customer ON gift_cards.ledger_entries (customer_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_gift_card ON gift_cards.ledger_entries (gift_card_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_created_at ON gift_cards.ledger_entries (created_at);
+
+-- A haircut is paid from the balance at most once
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ledger_entries_haircut_spend
+    ON gift_cards.ledger_entries (haircut_event_id) WHERE entry_type = 'spend';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS gift_cards.ledger_entries;
+DROP TABLE IF EXISTS gift_cards.balance_holds;
+DROP TABLE IF EXISTS gift_cards.gift_cards;
+DROP SCHEMA IF EXISTS gift_cards;
+
+ALTER TABLE users.users
+    DROP CONSTRAINT IF EXISTS stored_value_balance_non_negative,
+    DROP COLUMN IF EXISTS stored_value_balance;
+-- +goose StatementEnd
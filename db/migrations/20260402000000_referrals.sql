@@ -0,0 +1,119 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE SCHEMA IF NOT EXISTS referrals;
+
+-- What each side of a qualified referral earns. The settings table has a single row.
+-- reward_amount is a number of credits for 'credits' and cents for 'coupon' and
+-- 'balance'; it is ignored for 'none'.
+CREATE TABLE IF NOT EXISTS referrals.settings
+(
+    id                     BOOLEAN PRIMARY KEY  DEFAULT TRUE CHECK (id),
+    enabled                BOOLEAN     NOT NULL DEFAULT TRUE,
+    referrer_reward_type   VARCHAR(20) NOT NULL DEFAULT 'balance'
+        CHECK (referrer_reward_type IN ('none', 'credits', 'coupon', 'balance')),
+    referrer_reward_amount INTEGER     NOT NULL DEFAULT 2000 CHECK (referrer_reward_amount >= 0),
+    referee_reward_type    VARCHAR(20) NOT NULL DEFAULT 'coupon'
+        CHECK (referee_reward_type IN ('none', 'credits', 'coupon', 'balance')),
+    referee_reward_amount  INTEGER     NOT NULL DEFAULT 1000 CHECK (referee_reward_amount >= 0),
+    updated_by             UUID REFERENCES users.users (id) ON DELETE SET NULL,
+    updated_at             TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO referrals.settings (id)
+VALUES (TRUE)
+ON CONFLICT DO NOTHING;
+
+-- A customer's referral code, created the first time they ask for it. Codes are
+-- stored uppercase.
+CREATE TABLE IF NOT EXISTS referrals.codes
+(
+    customer_id UUID PRIMARY KEY REFERENCES users.users (id) ON DELETE CASCADE,
+    code        VARCHAR(16) NOT NULL UNIQUE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Devices customers registered from, as reported by the app. Used to catch people
+-- referring themselves from the same device.
+CREATE TABLE IF NOT EXISTS referrals.devices
+(
+    customer_id UUID         NOT NULL REFERENCES users.users (id) ON DELETE CASCADE,
+    device_id   VARCHAR(200) NOT NULL,
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (customer_id, device_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_referral_devices_device ON referrals.devices (device_id);
+
+-- A customer who registered with someone's code. A referral is pending until the
+-- referee's first membership invoice is paid, then qualified until its rewards are
+-- issued. Referrals that look like self-referrals are rejected with a reason:
+-- 'same_email', 'same_device' or 'same_payment_method'.
+CREATE TABLE IF NOT EXISTS referrals.referrals
+(
+    id                    UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    referrer_id           UUID        NOT NULL REFERENCES users.users (id) ON DELETE CASCADE,
+    referee_id            UUID        NOT NULL UNIQUE REFERENCES users.users (id) ON DELETE CASCADE,
+    code                  VARCHAR(16) NOT NULL,
+    status                VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'qualified', 'rewarded', 'rejected')),
+    rejection_reason      VARCHAR(30)
+        CHECK (rejection_reason IN ('same_email', 'same_device', 'same_payment_method')),
+    qualifying_invoice_id TEXT,
+    qualified_at          TIMESTAMPTZ,
+    rewarded_at           TIMESTAMPTZ,
+    created_at            TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at            TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT referrals_not_self CHECK (referrer_id <> referee_id),
+    CONSTRAINT referrals_rejection_reason CHECK ((status = 'rejected') = (rejection_reason IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_referrals_referrer ON referrals.referrals (referrer_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_referrals_status ON referrals.referrals (status, created_at);
+
+-- A reward for one side of a qualified referral, written when the referral qualifies
+-- and issued right after. Rewards that could not be issued are retried by a job.
+-- discount_code is the code of a 'coupon' reward.
+CREATE TABLE IF NOT EXISTS referrals.rewards
+(
+    id            UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    referral_id   UUID        NOT NULL REFERENCES referrals.referrals (id) ON DELETE CASCADE,
+    customer_id   UUID        NOT NULL REFERENCES users.users (id) ON DELETE CASCADE,
+    recipient     VARCHAR(10) NOT NULL CHECK (recipient IN ('referrer', 'referee')),
+    reward_type   VARCHAR(20) NOT NULL CHECK (reward_type IN ('credits', 'coupon', 'balance')),
+    amount        INTEGER     NOT NULL CHECK (amount > 0),
+    status        VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'issued')),
+    discount_code TEXT,
+    attempts      INTEGER     NOT NULL DEFAULT 0,
+    last_error    TEXT,
+    issued_at     TIMESTAMPTZ,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_referral_reward UNIQUE (referral_id, recipient)
+);
+
+CREATE INDEX IF NOT EXISTS idx_referral_rewards_pending ON referrals.rewards (created_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_referral_rewards_customer ON referrals.rewards (customer_id);
+
+-- Referral rewards can be paid into the stored-value balance:
+--   credit: value granted to a customer's balance without a gift card (balance +)
+ALTER TABLE gift_cards.ledger_entries
+    DROP CONSTRAINT IF EXISTS ledger_entries_entry_type_check,
+    ADD CONSTRAINT ledger_entries_entry_type_check
+        CHECK (entry_type IN ('issue', 'redeem', 'spend', 'release', 'expire', 'void', 'credit'));
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE gift_cards.ledger_entries
+    DROP CONSTRAINT IF EXISTS ledger_entries_entry_type_check,
+    ADD CONSTRAINT ledger_entries_entry_type_check
+        CHECK (entry_type IN ('issue', 'redeem', 'spend', 'release', 'expire', 'void'));
+
+DROP TABLE IF EXISTS referrals.rewards;
+DROP TABLE IF EXISTS referrals.referrals;
+DROP TABLE IF EXISTS referrals.devices;
+DROP TABLE IF EXISTS referrals.codes;
+DROP TABLE IF EXISTS referrals.settings;
+DROP SCHEMA IF EXISTS referrals;
+-- +goose StatementEnd
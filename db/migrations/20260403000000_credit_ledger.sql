@@ -0,0 +1,166 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE users.credit_packages
+    ADD COLUMN credit_validity_days INTEGER CHECK (credit_validity_days > 0);
+
+COMMENT ON COLUMN users.credit_packages.credit_validity_days IS 'Number of days credits bought with a package can be used. NULL never expires.';
+
+-- Credits given to a customer at once: a purchase, a membership allocation, a refund or
+-- a staff adjustment. remaining is what has not been spent, held or expired yet. Credits
+-- are used from the grant that expires first, oldest first, and grants without
+-- expires_at last. remaining only ever changes together with ledger entries.
+CREATE TABLE IF NOT EXISTS users.credit_grants
+(
+    id          UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    customer_id UUID        NOT NULL REFERENCES users.users (id) ON DELETE CASCADE,
+    source      VARCHAR(20) NOT NULL
+        CHECK (source IN ('opening_balance', 'purchase', 'membership', 'refund', 'adjustment', 'reward')),
+    amount      INTEGER     NOT NULL CHECK (amount > 0),
+    remaining   INTEGER     NOT NULL CHECK (remaining >= 0),
+    description TEXT        NOT NULL,
+    expires_at  TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT credit_grants_remaining_within_amount CHECK (remaining <= amount)
+);
+
+CREATE INDEX IF NOT EXISTS idx_credit_grants_open
+    ON users.credit_grants (customer_id, expires_at NULLS LAST, created_at) WHERE remaining > 0;
+CREATE INDEX IF NOT EXISTS idx_credit_grants_expiring
+    ON users.credit_grants (expires_at) WHERE remaining > 0 AND expires_at IS NOT NULL;
+
+-- Credits set aside for a booking that is not confirmed yet. They are taken from the
+-- customer's grants when the hold is placed; capturing spends them and releasing puts
+-- them back on the same grants. A hold still held after expires_at is settled by the
+-- credit reconciliation job.
+CREATE TABLE IF NOT EXISTS users.credit_holds
+(
+    id          UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    customer_id UUID        NOT NULL REFERENCES users.users (id) ON DELETE CASCADE,
+    amount      INTEGER     NOT NULL CHECK (amount > 0),
+    reference   TEXT        NOT NULL, -- what the credits pay for, e.g. 'event:<id>'
+    event_id    UUID REFERENCES events.events (id) ON DELETE SET NULL,
+    description TEXT        NOT NULL,
+    status      VARCHAR(20) NOT NULL DEFAULT 'held'
+        CHECK (status IN ('held', 'captured', 'released')),
+    expires_at  TIMESTAMPTZ NOT NULL,
+    captured_at TIMESTAMPTZ,
+    released_at TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_credit_holds_held ON users.credit_holds (expires_at) WHERE status = 'held';
+CREATE INDEX IF NOT EXISTS idx_credit_holds_customer ON users.credit_holds (customer_id, created_at);
+
+-- Double-entry record of every credit movement. Each transaction_id has one debit and
+-- one credit entry per grant touched, and its amounts sum to zero. A customer's credits
+-- live in five accounts:
+--   issued:    where granted credits come from (only ever negative)
+--   available: the spendable balance
+--   held:      set aside by a credit hold
+--   spent:     paid for bookings
+--   expired:   lapsed at their grant's expires_at
+-- entry_type says why credits moved:
+--   opening_balance: balance carried over when the ledger started (issued -> available)
+--   grant:           credits given to the customer (issued -> available)
+--   consume:         credits paid for something (available -> spent)
+--   hold:            a hold was placed (available -> held)
+--   capture:         a hold was confirmed (held -> spent)
+--   release:         a hold was cancelled (held -> available)
+--   refund:          spent credits came back (spent -> available, or issued -> available
+--                    for spending recorded before the ledger)
+--   expire:          unused credits lapsed (available -> expired)
+-- The customer's balance is the sum of their available entries. Entries are never changed.
+CREATE TABLE IF NOT EXISTS users.credit_ledger_entries
+(
+    id             UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    transaction_id UUID        NOT NULL,
+    customer_id    UUID        NOT NULL REFERENCES users.users (id) ON DELETE CASCADE,
+    account        VARCHAR(20) NOT NULL
+        CHECK (account IN ('issued', 'available', 'held', 'spent', 'expired')),
+    entry_type     VARCHAR(20) NOT NULL
+        CHECK (entry_type IN ('opening_balance', 'grant', 'consume', 'hold', 'capture', 'release', 'refund', 'expire')),
+    amount         INTEGER     NOT NULL CHECK (amount <> 0),
+    grant_id       UUID REFERENCES users.credit_grants (id) ON DELETE CASCADE,
+    hold_id        UUID REFERENCES users.credit_holds (id) ON DELETE CASCADE,
+    reference      TEXT,
+    description    TEXT        NOT NULL,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_credit_ledger_entries_customer
+    ON users.credit_ledger_entries (customer_id, account);
+CREATE INDEX IF NOT EXISTS idx_credit_ledger_entries_transaction
+    ON users.credit_ledger_entries (transaction_id);
+CREATE INDEX IF NOT EXISTS idx_credit_ledger_entries_reference
+    ON users.credit_ledger_entries (customer_id, reference) WHERE reference IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_credit_ledger_entries_created_at
+    ON users.credit_ledger_entries (created_at);
+
+CREATE OR REPLACE FUNCTION users.reject_credit_ledger_update()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'Credit ledger entries cannot be changed; post a correcting transaction instead';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER credit_ledger_entries_append_only
+    BEFORE UPDATE ON users.credit_ledger_entries
+    FOR EACH ROW
+    EXECUTE FUNCTION users.reject_credit_ledger_update();
+
+-- Differences found by the nightly credit reconciliation. kind says what disagreed:
+--   balance:     users.customer_credits against the available entries
+--   grants:      the remaining credits on grants against the available entries
+--   holds:       open holds against the held entries
+--   transaction: a ledger transaction whose entries do not sum to zero
+-- expected is what the ledger says; actual is what was found.
+CREATE TABLE IF NOT EXISTS users.credit_reconciliation_issues
+(
+    id             UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    customer_id    UUID        NOT NULL REFERENCES users.users (id) ON DELETE CASCADE,
+    kind           VARCHAR(20) NOT NULL
+        CHECK (kind IN ('balance', 'grants', 'holds', 'transaction')),
+    transaction_id UUID,
+    expected       INTEGER     NOT NULL,
+    actual         INTEGER     NOT NULL,
+    detected_at    TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    resolved_by    UUID REFERENCES users.users (id) ON DELETE SET NULL,
+    resolved_at    TIMESTAMPTZ,
+    resolution     TEXT
+);
+
+-- A difference stays open once until staff resolve it
+CREATE UNIQUE INDEX IF NOT EXISTS idx_credit_reconciliation_issues_open
+    ON users.credit_reconciliation_issues (customer_id, kind, COALESCE(transaction_id, '00000000-0000-0000-0000-000000000000'))
+    WHERE resolved_at IS NULL;
+
+-- Carry existing balances over as grants that never expire
+WITH grants AS (
+    INSERT INTO users.credit_grants (customer_id, source, amount, remaining, description)
+    SELECT customer_id, 'opening_balance', credits, credits, 'Balance before the credit ledger'
+    FROM users.customer_credits
+    WHERE credits > 0
+    RETURNING id, customer_id, amount, gen_random_uuid() AS transaction_id
+)
+INSERT INTO users.credit_ledger_entries (transaction_id, customer_id, account, entry_type, amount, grant_id, description)
+SELECT transaction_id, customer_id, 'issued', 'opening_balance', -amount, id, 'Balance before the credit ledger'
+FROM grants
+UNION ALL
+SELECT transaction_id, customer_id, 'available', 'opening_balance', amount, id, 'Balance before the credit ledger'
+FROM grants;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS users.credit_reconciliation_issues;
+DROP TRIGGER IF EXISTS credit_ledger_entries_append_only ON users.credit_ledger_entries;
+DROP FUNCTION IF EXISTS users.reject_credit_ledger_update();
+DROP TABLE IF EXISTS users.credit_ledger_entries;
+DROP TABLE IF EXISTS users.credit_holds;
+DROP TABLE IF EXISTS users.credit_grants;
+
+ALTER TABLE users.credit_packages
+    DROP COLUMN IF EXISTS credit_validity_days;
+-- +goose StatementEnd
@@ -0,0 +1,56 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- One push send: a team blast, a message to a user or an event announcement. Every
+-- device the notification went to has a ticket in notifications.push_tickets.
+CREATE TABLE IF NOT EXISTS notifications.push_notifications
+(
+    id         UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    source     VARCHAR(20) NOT NULL CHECK (source IN ('team', 'user', 'event')),
+    title      TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_push_notifications_created_at
+    ON notifications.push_notifications (created_at DESC);
+
+-- The ticket Expo returned for one device. status is whether Expo accepted the message;
+-- receipt_status stays NULL until the receipt job has fetched the receipt telling
+-- whether Apple or Google delivered it. token_pruned marks tickets whose token was
+-- removed because the device is no longer registered.
+CREATE TABLE IF NOT EXISTS notifications.push_tickets
+(
+    id                 UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    notification_id    UUID        NOT NULL REFERENCES notifications.push_notifications (id) ON DELETE CASCADE,
+    push_token_id      INTEGER REFERENCES notifications.push_tokens (id) ON DELETE SET NULL,
+    expo_push_token    TEXT        NOT NULL,
+    ticket_id          TEXT,
+    status             VARCHAR(10) NOT NULL CHECK (status IN ('ok', 'error')),
+    error              TEXT,
+    receipt_status     VARCHAR(10) CHECK (receipt_status IN ('ok', 'error')),
+    receipt_error      TEXT,
+    receipt_checked_at TIMESTAMPTZ,
+    token_pruned       BOOLEAN     NOT NULL DEFAULT false,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_push_tickets_notification_id
+    ON notifications.push_tickets (notification_id);
+CREATE INDEX IF NOT EXISTS idx_push_tickets_pending_receipt
+    ON notifications.push_tickets (created_at) WHERE ticket_id IS NOT NULL AND receipt_status IS NULL;
+
+ALTER TABLE events.notification_history
+    ADD COLUMN IF NOT EXISTS push_notification_id UUID REFERENCES notifications.push_notifications (id) ON DELETE SET NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE events.notification_history
+    DROP COLUMN IF EXISTS push_notification_id;
+
+DROP TABLE IF EXISTS notifications.push_tickets;
+DROP TABLE IF EXISTS notifications.push_notifications;
+
+-- +goose StatementEnd
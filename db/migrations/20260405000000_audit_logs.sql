@@ -0,0 +1,58 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Structured record of every admin mutation. Each entry holds who made the change, from
+-- where, what it was made to and a diff of the fields that changed. Entries form a hash
+-- chain: hash covers the entry and prev_hash, the hash of the entry before it, so
+-- editing or removing an entry breaks the chain from that point on. Entries are only
+-- ever inserted, under a transaction-scoped advisory lock that keeps the chain linear;
+-- prev_hash is unique so a writer that read a stale head fails instead of forking it.
+CREATE TABLE IF NOT EXISTS audit.audit_logs
+(
+    id          BIGSERIAL PRIMARY KEY,
+    actor_id    UUID, -- no foreign key: removing a user must not rewrite their entries
+    actor_role  VARCHAR(20),
+    ip_address  VARCHAR(45),
+    entity_type VARCHAR(50),
+    entity_id   TEXT,
+    action      VARCHAR(50) NOT NULL,
+    diff        JSONB       NOT NULL DEFAULT '{}'::jsonb, -- {"field": {"before": ..., "after": ...}}
+    description TEXT        NOT NULL DEFAULT '',
+    prev_hash   TEXT        NOT NULL UNIQUE,
+    hash        TEXT        NOT NULL UNIQUE,
+    created_at  TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_entity
+    ON audit.audit_logs (entity_type, entity_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor
+    ON audit.audit_logs (actor_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at
+    ON audit.audit_logs (created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_diff
+    ON audit.audit_logs USING GIN (diff);
+
+CREATE OR REPLACE FUNCTION audit.prevent_audit_log_changes()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    RAISE EXCEPTION 'audit.audit_logs is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_logs_append_only
+    BEFORE UPDATE OR DELETE
+    ON audit.audit_logs
+    FOR EACH ROW
+EXECUTE FUNCTION audit.prevent_audit_log_changes();
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TRIGGER IF EXISTS audit_logs_append_only ON audit.audit_logs;
+DROP FUNCTION IF EXISTS audit.prevent_audit_log_changes();
+DROP TABLE IF EXISTS audit.audit_logs;
+
+-- +goose StatementEnd
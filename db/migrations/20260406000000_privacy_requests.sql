@@ -0,0 +1,67 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE SCHEMA IF NOT EXISTS privacy;
+
+-- A data subject request: a customer asking for a copy of their personal data (export)
+-- or for it to be erased (erasure). Customers' erasure requests wait in pending_review
+-- until staff approve or reject them; exports and erasures opened by the account
+-- deletion job start as pending. The data request job picks up pending requests.
+-- A finished export keeps its ZIP at export_object until export_expires_at, after
+-- which the file is deleted and the request becomes expired.
+CREATE TABLE IF NOT EXISTS privacy.data_requests
+(
+    id                UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    user_id           UUID        NOT NULL, -- no foreign key: the request outlives the data it erased
+    request_type      VARCHAR(20) NOT NULL CHECK (request_type IN ('export', 'erasure')),
+    status            VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending_review', 'pending', 'processing', 'completed', 'failed', 'rejected', 'expired')),
+    requested_by      UUID, -- NULL when opened by the system
+    reason            TEXT,
+    reviewed_by       UUID,
+    reviewed_at       TIMESTAMPTZ,
+    review_notes      TEXT,
+    export_object     TEXT,
+    export_expires_at TIMESTAMPTZ,
+    error             TEXT,
+    attempts          INTEGER     NOT NULL DEFAULT 0,
+    started_at        TIMESTAMPTZ,
+    completed_at      TIMESTAMPTZ,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A user has at most one open request of each type
+CREATE UNIQUE INDEX IF NOT EXISTS idx_data_requests_open
+    ON privacy.data_requests (user_id, request_type) WHERE status IN ('pending_review', 'pending', 'processing');
+CREATE INDEX IF NOT EXISTS idx_data_requests_status ON privacy.data_requests (status, created_at);
+CREATE INDEX IF NOT EXISTS idx_data_requests_user ON privacy.data_requests (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_data_requests_export_expiry
+    ON privacy.data_requests (export_expires_at) WHERE export_object IS NOT NULL;
+
+-- Progress of an erasure, one row per system the user's data is removed from. Steps run
+-- in a fixed order and a completed or skipped step is not run again when a failed
+-- erasure is retried.
+CREATE TABLE IF NOT EXISTS privacy.erasure_steps
+(
+    request_id UUID        NOT NULL REFERENCES privacy.data_requests (id) ON DELETE CASCADE,
+    step       VARCHAR(20) NOT NULL
+        CHECK (step IN ('stripe', 'hubspot', 'storage', 'push_tokens', 'firebase', 'database')),
+    status     VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'completed', 'failed', 'skipped')),
+    detail     TEXT,
+    attempts   INTEGER     NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (request_id, step)
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS privacy.erasure_steps;
+DROP TABLE IF EXISTS privacy.data_requests;
+DROP SCHEMA IF EXISTS privacy;
+
+-- +goose StatementEnd
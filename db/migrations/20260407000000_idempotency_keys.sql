@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Responses to mutating requests sent with an Idempotency-Key header. scope is the
+-- caller the key belongs to (a user ID, or a hash of the credentials for requests
+-- without a valid token) so one caller can never replay another's response.
+-- request_hash fingerprints the method, path and body; a retry with the same key but a
+-- different payload is rejected. Rows stay 'processing' while the first request runs.
+CREATE TABLE IF NOT EXISTS public.idempotency_keys
+(
+    scope            TEXT         NOT NULL,
+    idempotency_key  VARCHAR(255) NOT NULL,
+    method           VARCHAR(10)  NOT NULL,
+    path             TEXT         NOT NULL,
+    request_hash     CHAR(64)     NOT NULL,
+    status           VARCHAR(10)  NOT NULL DEFAULT 'processing' CHECK (status IN ('processing', 'completed')),
+    response_status  INTEGER,
+    response_headers JSONB,
+    response_body    BYTEA,
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at     TIMESTAMPTZ,
+    expires_at       TIMESTAMPTZ  NOT NULL,
+    PRIMARY KEY (scope, idempotency_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at
+    ON public.idempotency_keys (expires_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS public.idempotency_keys;
+
+-- +goose StatementEnd
@@ -0,0 +1,55 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE SCHEMA IF NOT EXISTS scheduler;
+
+-- One run of a background job. trigger says whether the schedule started it, it was
+-- an automatic retry after a failure, or staff started it from the admin jobs API.
+-- counts holds whatever the job reported (e.g. {"deleted": 3}). instance_id is the
+-- server instance that ran it.
+CREATE TABLE IF NOT EXISTS scheduler.job_runs
+(
+    id           UUID PRIMARY KEY      DEFAULT gen_random_uuid(),
+    job_name     VARCHAR(100) NOT NULL,
+    trigger      VARCHAR(10)  NOT NULL CHECK (trigger IN ('scheduled', 'retry', 'manual')),
+    dry_run      BOOLEAN      NOT NULL DEFAULT FALSE,
+    status       VARCHAR(10)  NOT NULL DEFAULT 'running' CHECK (status IN ('running', 'succeeded', 'failed')),
+    triggered_by UUID REFERENCES users.users (id) ON DELETE SET NULL,
+    instance_id  TEXT         NOT NULL,
+    counts       JSONB        NOT NULL DEFAULT '{}'::jsonb,
+    error        TEXT,
+    started_at   TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at  TIMESTAMPTZ,
+    duration_ms  BIGINT
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_runs_job_started
+    ON scheduler.job_runs (job_name, started_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_job_runs_started_at
+    ON scheduler.job_runs (started_at);
+
+-- Per-job state shared by every instance. A paused job is skipped by the schedule but
+-- can still be started by hand. consecutive_failures drives the retry backoff and is
+-- reset by the next successful run.
+CREATE TABLE IF NOT EXISTS scheduler.job_states
+(
+    job_name             VARCHAR(100) PRIMARY KEY,
+    paused               BOOLEAN     NOT NULL DEFAULT FALSE,
+    paused_by            UUID REFERENCES users.users (id) ON DELETE SET NULL,
+    paused_at            TIMESTAMPTZ,
+    consecutive_failures INTEGER     NOT NULL DEFAULT 0,
+    last_success_at      TIMESTAMPTZ,
+    updated_at           TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS scheduler.job_states;
+DROP TABLE IF EXISTS scheduler.job_runs;
+DROP SCHEMA IF EXISTS scheduler;
+
+-- +goose StatementEnd
@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Play-by-play log for live scoring. The live score, period, clock, fouls and
+-- timeouts are all derived from this log, and the final score on game.games is
+-- committed from it when the game is finalized.
+CREATE TABLE IF NOT EXISTS game.game_events (
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    game_id        UUID NOT NULL REFERENCES game.games(id) ON DELETE CASCADE,
+    sequence       INT NOT NULL CHECK (sequence > 0),
+    event_type     TEXT NOT NULL CHECK (event_type IN ('period_start', 'period_end', 'clock', 'score', 'foul', 'timeout', 'void', 'final')),
+    team_id        UUID REFERENCES athletic.teams(id),
+    athlete_id     UUID REFERENCES athletic.athletes(id) ON DELETE SET NULL,
+    points         INT NOT NULL DEFAULT 0 CHECK (points BETWEEN 0 AND 3),
+    period         INT NOT NULL DEFAULT 0 CHECK (period >= 0),
+    clock_seconds  INT CHECK (clock_seconds >= 0),
+    clock_running  BOOLEAN,
+    voids_sequence INT,
+    note           TEXT,
+    recorded_by    UUID REFERENCES users.users(id) ON DELETE SET NULL,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (game_id, sequence),
+    CHECK (event_type NOT IN ('score', 'foul', 'timeout') OR team_id IS NOT NULL),
+    CHECK (event_type <> 'score' OR points > 0),
+    CHECK (event_type <> 'void' OR voids_sequence IS NOT NULL)
+);
+
+COMMENT ON TABLE game.game_events IS 'Append-only play-by-play log for live game scoring';
+COMMENT ON COLUMN game.game_events.period IS 'Period the event happened in (0 before the first period starts)';
+COMMENT ON COLUMN game.game_events.clock_seconds IS 'Game clock (seconds remaining in the period) when the event was recorded';
+COMMENT ON COLUMN game.game_events.voids_sequence IS 'For void events, the sequence of the event being struck from the log';
+
+CREATE INDEX IF NOT EXISTS idx_game_events_final
+    ON game.game_events (game_id) WHERE event_type = 'final';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS game.game_events;
+
+-- +goose StatementEnd
@@ -0,0 +1,170 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE SCHEMA IF NOT EXISTS payroll;
+
+-- What a staff member is paid. A rate applies to all of the staff member's work, or
+-- only to the sessions of one program, which takes precedence over their default rate.
+CREATE TABLE IF NOT EXISTS payroll.pay_rates
+(
+    id             UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    staff_id       UUID        NOT NULL REFERENCES staff.staff (id) ON DELETE CASCADE,
+    rate_type      TEXT        NOT NULL CHECK (rate_type IN ('hourly', 'per_session')),
+    program_id     UUID REFERENCES program.programs (id) ON DELETE CASCADE,
+    amount_cents   INTEGER     NOT NULL CHECK (amount_cents >= 0),
+    effective_from DATE        NOT NULL,
+    effective_to   DATE,
+    created_by     UUID REFERENCES users.users (id) ON DELETE SET NULL,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT pay_rates_effective_range CHECK (effective_to IS NULL OR effective_to >= effective_from),
+    CONSTRAINT pay_rates_no_overlap EXCLUDE USING GIST (
+        staff_id WITH =,
+        (COALESCE(program_id, '00000000-0000-0000-0000-000000000000'::uuid)) WITH =,
+        daterange(effective_from, effective_to, '[]') WITH &&
+    )
+);
+
+COMMENT ON COLUMN payroll.pay_rates.program_id IS 'NULL for the staff member''s default rate';
+COMMENT ON COLUMN payroll.pay_rates.effective_to IS 'Last day the rate applies (inclusive); NULL while current';
+
+-- Payroll details kept for the payroll provider
+CREATE TABLE IF NOT EXISTS payroll.employees
+(
+    staff_id             UUID PRIMARY KEY REFERENCES staff.staff (id) ON DELETE CASCADE,
+    external_employee_id TEXT        NOT NULL UNIQUE,
+    updated_by           UUID REFERENCES users.users (id) ON DELETE SET NULL,
+    updated_at           TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Pay periods cover whole days in the organization's time zone, both ends inclusive
+CREATE TABLE IF NOT EXISTS payroll.pay_periods
+(
+    id         UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    starts_on  DATE        NOT NULL,
+    ends_on    DATE        NOT NULL,
+    status     TEXT        NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'closed')),
+    closed_at  TIMESTAMPTZ,
+    closed_by  UUID REFERENCES users.users (id) ON DELETE SET NULL,
+    created_by UUID REFERENCES users.users (id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT pay_periods_range CHECK (ends_on >= starts_on),
+    CONSTRAINT pay_periods_no_overlap EXCLUDE USING GIST (daterange(starts_on, ends_on, '[]') WITH &&)
+);
+
+-- The sessions staff are paid for: events they were added to, practices they booked and
+-- games they scheduled. Practices and games without an end time last two hours.
+CREATE OR REPLACE VIEW payroll.staff_assignments AS
+SELECT es.staff_id,
+       'event'::text                    AS source_type,
+       e.id                             AS source_id,
+       e.program_id,
+       COALESCE(p.name, 'Event')::text  AS description,
+       e.start_at                       AS scheduled_start,
+       e.end_at                         AS scheduled_end
+FROM events.staff es
+         JOIN events.events e ON e.id = es.event_id
+         LEFT JOIN program.programs p ON p.id = e.program_id
+WHERE NOT e.is_cancelled
+UNION ALL
+SELECT pr.booked_by,
+       'practice'::text,
+       pr.id,
+       NULL::uuid,
+       ('Practice: ' || t.name)::text,
+       pr.start_time,
+       COALESCE(pr.end_time, pr.start_time + INTERVAL '2 hours')
+FROM practice.practices pr
+         JOIN athletic.teams t ON t.id = pr.team_id
+         JOIN staff.staff s ON s.id = pr.booked_by
+WHERE pr.status IS DISTINCT FROM 'canceled'
+UNION ALL
+SELECT g.created_by,
+       'game'::text,
+       g.id,
+       NULL::uuid,
+       ('Game: ' || home.name || ' vs ' || away.name)::text,
+       g.start_time,
+       COALESCE(g.end_time, g.start_time + INTERVAL '2 hours')
+FROM game.games g
+         JOIN athletic.teams home ON home.id = g.home_team_id
+         JOIN athletic.teams away ON away.id = g.away_team_id
+         JOIN staff.staff s ON s.id = g.created_by
+WHERE g.status IS DISTINCT FROM 'canceled';
+
+-- When staff actually arrived at and left the sessions they were assigned to
+CREATE TABLE IF NOT EXISTS payroll.check_ins
+(
+    id             UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    staff_id       UUID        NOT NULL REFERENCES staff.staff (id) ON DELETE CASCADE,
+    source_type    TEXT        NOT NULL CHECK (source_type IN ('event', 'practice', 'game')),
+    source_id      UUID        NOT NULL,
+    checked_in_at  TIMESTAMPTZ NOT NULL,
+    checked_out_at TIMESTAMPTZ,
+    CONSTRAINT check_ins_unique UNIQUE (staff_id, source_type, source_id),
+    CONSTRAINT check_ins_order CHECK (checked_out_at IS NULL OR checked_out_at > checked_in_at)
+);
+
+-- One timesheet per staff member per pay period. Generated timesheets are drafts the
+-- staff member submits and an admin approves; only drafts and rejected timesheets are
+-- regenerated.
+CREATE TABLE IF NOT EXISTS payroll.timesheets
+(
+    id               UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    pay_period_id    UUID        NOT NULL REFERENCES payroll.pay_periods (id) ON DELETE CASCADE,
+    staff_id         UUID        NOT NULL REFERENCES staff.staff (id) ON DELETE CASCADE,
+    status           TEXT        NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'submitted', 'approved', 'rejected')),
+    submitted_at     TIMESTAMPTZ,
+    reviewed_at      TIMESTAMPTZ,
+    reviewed_by      UUID REFERENCES users.users (id) ON DELETE SET NULL,
+    rejection_reason TEXT,
+    generated_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT timesheets_unique UNIQUE (pay_period_id, staff_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_timesheets_staff ON payroll.timesheets (staff_id);
+
+-- A session worked, priced with the rate that applied to it
+CREATE TABLE IF NOT EXISTS payroll.timesheet_entries
+(
+    id              UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    timesheet_id    UUID        NOT NULL REFERENCES payroll.timesheets (id) ON DELETE CASCADE,
+    source_type     TEXT        NOT NULL CHECK (source_type IN ('event', 'practice', 'game')),
+    source_id       UUID        NOT NULL,
+    program_id      UUID,
+    description     TEXT        NOT NULL,
+    scheduled_start TIMESTAMPTZ NOT NULL,
+    scheduled_end   TIMESTAMPTZ NOT NULL,
+    checked_in_at   TIMESTAMPTZ,
+    checked_out_at  TIMESTAMPTZ,
+    worked_minutes  INTEGER     NOT NULL CHECK (worked_minutes >= 0),
+    rate_id         UUID REFERENCES payroll.pay_rates (id) ON DELETE SET NULL,
+    rate_type       TEXT CHECK (rate_type IN ('hourly', 'per_session')),
+    rate_cents      INTEGER,
+    amount_cents    INTEGER     NOT NULL DEFAULT 0,
+    CONSTRAINT timesheet_entries_unique UNIQUE (timesheet_id, source_type, source_id)
+);
+
+-- Manual corrections to a timesheet's pay (bonuses, missed sessions, deductions)
+CREATE TABLE IF NOT EXISTS payroll.adjustments
+(
+    id           UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    timesheet_id UUID        NOT NULL REFERENCES payroll.timesheets (id) ON DELETE CASCADE,
+    amount_cents INTEGER     NOT NULL CHECK (amount_cents <> 0),
+    reason       TEXT        NOT NULL,
+    created_by   UUID REFERENCES users.users (id) ON DELETE SET NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_adjustments_timesheet ON payroll.adjustments (timesheet_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP SCHEMA IF EXISTS payroll CASCADE;
+
+-- +goose StatementEnd
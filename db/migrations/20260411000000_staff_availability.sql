@@ -0,0 +1,67 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Weekly hours a coach or instructor can be scheduled, as wall-clock times in the
+-- facility's timezone. A window with a location only applies to sessions there.
+-- Staff who declare no windows can be scheduled at any time.
+CREATE TABLE IF NOT EXISTS staff.availability
+(
+    id          UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    staff_id    UUID        NOT NULL REFERENCES staff.staff (id) ON DELETE CASCADE,
+    day_of_week INTEGER     NOT NULL CHECK (day_of_week >= 0 AND day_of_week <= 6), -- 0=Sunday, 6=Saturday
+    start_time  TIME        NOT NULL,
+    end_time    TIME        NOT NULL,
+    location_id UUID REFERENCES location.locations (id) ON DELETE CASCADE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT check_availability_time_order CHECK (end_time > start_time)
+);
+
+CREATE INDEX IF NOT EXISTS idx_staff_availability_staff_day
+    ON staff.availability (staff_id, day_of_week);
+
+-- Periods a staff member cannot be scheduled at all.
+CREATE TABLE IF NOT EXISTS staff.time_off
+(
+    id         UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    staff_id   UUID        NOT NULL REFERENCES staff.staff (id) ON DELETE CASCADE,
+    starts_at  TIMESTAMPTZ NOT NULL,
+    ends_at    TIMESTAMPTZ NOT NULL,
+    reason     TEXT,
+    created_by UUID REFERENCES users.users (id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT check_time_off_order CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_staff_time_off_staff
+    ON staff.time_off (staff_id, starts_at);
+
+-- Audit trail of admins scheduling staff over conflicts. conflicts holds the
+-- clashing sessions, time off and availability as they were at the time.
+CREATE TABLE IF NOT EXISTS staff.schedule_overrides
+(
+    id            UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    staff_id      UUID        NOT NULL REFERENCES staff.staff (id) ON DELETE CASCADE,
+    activity_type VARCHAR(30) NOT NULL,
+    activity_id   UUID,
+    starts_at     TIMESTAMPTZ NOT NULL,
+    ends_at       TIMESTAMPTZ NOT NULL,
+    conflicts     JSONB       NOT NULL DEFAULT '[]',
+    reason        TEXT        NOT NULL,
+    overridden_by UUID REFERENCES users.users (id) ON DELETE SET NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_staff_schedule_overrides_staff
+    ON staff.schedule_overrides (staff_id, created_at DESC);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS staff.schedule_overrides;
+DROP TABLE IF EXISTS staff.time_off;
+DROP TABLE IF EXISTS staff.availability;
+
+-- +goose StatementEnd
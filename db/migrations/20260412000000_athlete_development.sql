@@ -0,0 +1,110 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Reusable evaluation forms, e.g. "U12 Skills Assessment". A template scoped to a
+-- program is offered for that program's athletes; one without is offered everywhere.
+CREATE TABLE IF NOT EXISTS athletic.evaluation_templates
+(
+    id          UUID PRIMARY KEY      DEFAULT gen_random_uuid(),
+    name        VARCHAR(150) NOT NULL,
+    description TEXT,
+    program_id  UUID REFERENCES program.programs (id) ON DELETE SET NULL,
+    is_active   BOOLEAN      NOT NULL DEFAULT TRUE,
+    created_by  UUID REFERENCES users.users (id) ON DELETE SET NULL,
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Skills rated on a template, each with its own rating scale.
+CREATE TABLE IF NOT EXISTS athletic.evaluation_skills
+(
+    id          UUID PRIMARY KEY      DEFAULT gen_random_uuid(),
+    template_id UUID         NOT NULL REFERENCES athletic.evaluation_templates (id) ON DELETE CASCADE,
+    name        VARCHAR(100) NOT NULL,
+    category    VARCHAR(50),
+    description TEXT,
+    scale_min   INTEGER      NOT NULL DEFAULT 1,
+    scale_max   INTEGER      NOT NULL DEFAULT 5,
+    sort_order  INTEGER      NOT NULL DEFAULT 0,
+    CONSTRAINT check_evaluation_skill_scale CHECK (scale_max > scale_min),
+    CONSTRAINT unique_evaluation_skill_name UNIQUE (template_id, name)
+);
+
+-- A coach's assessment of one athlete against a template. Drafts are only visible
+-- to staff; families see an evaluation once it is published.
+CREATE TABLE IF NOT EXISTS athletic.evaluations
+(
+    id           UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    athlete_id   UUID        NOT NULL REFERENCES athletic.athletes (id) ON DELETE CASCADE,
+    template_id  UUID        NOT NULL REFERENCES athletic.evaluation_templates (id),
+    program_id   UUID REFERENCES program.programs (id) ON DELETE SET NULL,
+    season       VARCHAR(50),
+    evaluated_on DATE        NOT NULL,
+    summary      TEXT,
+    status       VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'published')),
+    coach_id     UUID REFERENCES users.users (id) ON DELETE SET NULL,
+    published_at TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_evaluations_athlete
+    ON athletic.evaluations (athlete_id, evaluated_on DESC);
+
+CREATE TABLE IF NOT EXISTS athletic.evaluation_scores
+(
+    evaluation_id UUID    NOT NULL REFERENCES athletic.evaluations (id) ON DELETE CASCADE,
+    skill_id      UUID    NOT NULL REFERENCES athletic.evaluation_skills (id),
+    rating        INTEGER NOT NULL,
+    comment       TEXT,
+    PRIMARY KEY (evaluation_id, skill_id)
+);
+
+-- Development goals a coach sets with an athlete, optionally tied to a skill rating.
+CREATE TABLE IF NOT EXISTS athletic.development_goals
+(
+    id            UUID PRIMARY KEY      DEFAULT gen_random_uuid(),
+    athlete_id    UUID         NOT NULL REFERENCES athletic.athletes (id) ON DELETE CASCADE,
+    skill_id      UUID REFERENCES athletic.evaluation_skills (id) ON DELETE SET NULL,
+    title         VARCHAR(150) NOT NULL,
+    description   TEXT,
+    target_rating INTEGER,
+    target_date   DATE,
+    status        VARCHAR(20)  NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'achieved', 'dropped')),
+    created_by    UUID REFERENCES users.users (id) ON DELETE SET NULL,
+    achieved_at   TIMESTAMPTZ,
+    created_at    TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at    TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_development_goals_athlete
+    ON athletic.development_goals (athlete_id, status);
+
+-- Periodic progress reports. The report content is rendered from the evaluations
+-- and goals on request; this records which periods were issued and emailed.
+CREATE TABLE IF NOT EXISTS athletic.progress_reports
+(
+    id               UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    athlete_id       UUID        NOT NULL REFERENCES athletic.athletes (id) ON DELETE CASCADE,
+    period_start     DATE        NOT NULL,
+    period_end       DATE        NOT NULL,
+    evaluation_count INTEGER     NOT NULL DEFAULT 0,
+    emailed_at       TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT check_progress_report_period CHECK (period_end >= period_start),
+    CONSTRAINT unique_progress_report_period UNIQUE (athlete_id, period_start)
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS athletic.progress_reports;
+DROP TABLE IF EXISTS athletic.development_goals;
+DROP TABLE IF EXISTS athletic.evaluation_scores;
+DROP TABLE IF EXISTS athletic.evaluations;
+DROP TABLE IF EXISTS athletic.evaluation_skills;
+DROP TABLE IF EXISTS athletic.evaluation_templates;
+
+-- +goose StatementEnd
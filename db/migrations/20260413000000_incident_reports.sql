@@ -0,0 +1,144 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE SCHEMA IF NOT EXISTS safety;
+
+-- Medical details families keep on file next to the emergency contact on
+-- users.users. Only admins and the athlete's assigned coaches can read them, and
+-- every read is recorded in safety.medical_access_log.
+CREATE TABLE IF NOT EXISTS users.medical_info
+(
+    user_id            UUID PRIMARY KEY REFERENCES users.users (id) ON DELETE CASCADE,
+    allergies          TEXT,
+    medical_conditions TEXT,
+    medications        TEXT,
+    physician_name     VARCHAR(100),
+    physician_phone    VARCHAR(25),
+    notes              TEXT,
+    updated_by         UUID REFERENCES users.users (id) ON DELETE SET NULL,
+    updated_at         TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- An injury or other incident, optionally tied to the event, game or practice it
+-- happened at. Incidents are the club's record of what happened and are closed
+-- rather than deleted.
+CREATE TABLE IF NOT EXISTS safety.incidents
+(
+    id            UUID PRIMARY KEY      DEFAULT gen_random_uuid(),
+    incident_type VARCHAR(20)  NOT NULL CHECK (incident_type IN ('injury', 'illness', 'behavioral', 'property', 'other')),
+    severity      VARCHAR(20)  NOT NULL CHECK (severity IN ('minor', 'moderate', 'serious', 'critical')),
+    status        VARCHAR(20)  NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'follow_up', 'resolved', 'closed')),
+    occurred_at   TIMESTAMPTZ  NOT NULL,
+    event_id      UUID REFERENCES events.events (id) ON DELETE SET NULL,
+    game_id       UUID REFERENCES game.games (id) ON DELETE SET NULL,
+    practice_id   UUID REFERENCES practice.practices (id) ON DELETE SET NULL,
+    location_id   UUID REFERENCES location.locations (id) ON DELETE SET NULL,
+    summary       VARCHAR(200) NOT NULL,
+    description   TEXT,
+    action_taken  TEXT,
+    reported_by   UUID REFERENCES users.users (id) ON DELETE SET NULL,
+    resolved_at   TIMESTAMPTZ,
+    created_at    TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at    TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT check_incident_single_activity CHECK (num_nonnulls(event_id, game_id, practice_id) <= 1)
+);
+
+CREATE INDEX IF NOT EXISTS idx_incidents_occurred_at
+    ON safety.incidents (occurred_at DESC);
+CREATE INDEX IF NOT EXISTS idx_incidents_open
+    ON safety.incidents (status) WHERE status IN ('open', 'follow_up');
+
+-- Athletes involved in an incident. References users rather than athletes so the
+-- record survives an athlete profile being removed.
+CREATE TABLE IF NOT EXISTS safety.incident_athletes
+(
+    incident_id        UUID NOT NULL REFERENCES safety.incidents (id) ON DELETE CASCADE,
+    athlete_id         UUID NOT NULL REFERENCES users.users (id) ON DELETE CASCADE,
+    injury             TEXT,
+    body_part          VARCHAR(50),
+    treatment          TEXT,
+    parent_notified_at TIMESTAMPTZ,
+    PRIMARY KEY (incident_id, athlete_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_incident_athletes_athlete
+    ON safety.incident_athletes (athlete_id);
+
+-- Timeline of notes and status changes after the incident was reported.
+CREATE TABLE IF NOT EXISTS safety.incident_follow_ups
+(
+    id          UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    incident_id UUID        NOT NULL REFERENCES safety.incidents (id) ON DELETE CASCADE,
+    status      VARCHAR(20) NOT NULL,
+    note        TEXT        NOT NULL,
+    created_by  UUID REFERENCES users.users (id) ON DELETE SET NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_incident_follow_ups_incident
+    ON safety.incident_follow_ups (incident_id, created_at);
+
+-- Photos and documents attached to an incident. Files are stored privately and
+-- only served through the API.
+CREATE TABLE IF NOT EXISTS safety.incident_attachments
+(
+    id              UUID PRIMARY KEY      DEFAULT gen_random_uuid(),
+    incident_id     UUID         NOT NULL REFERENCES safety.incidents (id) ON DELETE CASCADE,
+    object_path     TEXT         NOT NULL,
+    file_name       VARCHAR(255) NOT NULL,
+    content_type    VARCHAR(100) NOT NULL,
+    file_size_bytes BIGINT       NOT NULL,
+    uploaded_by     UUID REFERENCES users.users (id) ON DELETE SET NULL,
+    created_at      TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_incident_attachments_incident
+    ON safety.incident_attachments (incident_id);
+
+-- Every read of a user's medical details, incident reports included. Entries are
+-- only ever inserted.
+CREATE TABLE IF NOT EXISTS safety.medical_access_log
+(
+    id          BIGSERIAL PRIMARY KEY,
+    viewer_id   UUID, -- no foreign key: removing a user must not rewrite who read what
+    viewer_role VARCHAR(20),
+    ip_address  VARCHAR(45),
+    subject_id  UUID        NOT NULL, -- whose medical details were read
+    resource    VARCHAR(30) NOT NULL CHECK (resource IN ('medical_info', 'incident', 'incident_attachment')),
+    resource_id UUID,
+    accessed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_medical_access_log_subject
+    ON safety.medical_access_log (subject_id, accessed_at DESC);
+
+CREATE OR REPLACE FUNCTION safety.prevent_access_log_changes()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    RAISE EXCEPTION 'safety.medical_access_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER medical_access_log_append_only
+    BEFORE UPDATE OR DELETE
+    ON safety.medical_access_log
+    FOR EACH ROW
+EXECUTE FUNCTION safety.prevent_access_log_changes();
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TRIGGER IF EXISTS medical_access_log_append_only ON safety.medical_access_log;
+DROP FUNCTION IF EXISTS safety.prevent_access_log_changes();
+DROP TABLE IF EXISTS safety.medical_access_log;
+DROP TABLE IF EXISTS safety.incident_attachments;
+DROP TABLE IF EXISTS safety.incident_follow_ups;
+DROP TABLE IF EXISTS safety.incident_athletes;
+DROP TABLE IF EXISTS safety.incidents;
+DROP TABLE IF EXISTS users.medical_info;
+DROP SCHEMA IF EXISTS safety;
+
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A series has no table of its own: it is the set of events.events rows sharing
+-- a recurrence_id, so recurrence_rules cannot reference it with a foreign key.
+-- This trigger gives it the same ON DELETE CASCADE behaviour by removing a rule
+-- once the last occurrence of its series is deleted.
+CREATE OR REPLACE FUNCTION events.delete_orphaned_recurrence_rules()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    DELETE
+    FROM events.recurrence_rules rr
+    WHERE rr.id IN (SELECT DISTINCT o.recurrence_id FROM deleted_events o WHERE o.recurrence_id IS NOT NULL)
+      AND NOT EXISTS (SELECT 1 FROM events.events e WHERE e.recurrence_id = rr.id);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER recurrence_rules_cascade
+    AFTER DELETE ON events.events
+    REFERENCING OLD TABLE AS deleted_events
+    FOR EACH STATEMENT
+    EXECUTE FUNCTION events.delete_orphaned_recurrence_rules();
+
+-- Rules whose series was already deleted before the trigger existed
+DELETE
+FROM events.recurrence_rules rr
+WHERE NOT EXISTS (SELECT 1 FROM events.events e WHERE e.recurrence_id = rr.id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS recurrence_rules_cascade ON events.events;
+DROP FUNCTION IF EXISTS events.delete_orphaned_recurrence_rules();
+-- +goose StatementEnd
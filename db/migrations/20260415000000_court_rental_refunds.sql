@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A card payment can complete after its hold lapsed and someone else booked the
+-- court. Such a rental is never confirmed: it is refund_due until the payment is
+-- refunded, and stays refund_due for staff to refund by hand if Stripe refuses.
+ALTER TABLE location.court_rentals
+    DROP CONSTRAINT IF EXISTS court_rentals_status_check;
+
+ALTER TABLE location.court_rentals
+    ADD CONSTRAINT court_rentals_status_check
+        CHECK (status IN ('held', 'confirmed', 'canceled', 'expired', 'refund_due', 'refunded'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE location.court_rentals
+SET status = 'canceled'
+WHERE status IN ('refund_due', 'refunded');
+
+ALTER TABLE location.court_rentals
+    DROP CONSTRAINT IF EXISTS court_rentals_status_check;
+
+ALTER TABLE location.court_rentals
+    ADD CONSTRAINT court_rentals_status_check
+        CHECK (status IN ('held', 'confirmed', 'canceled', 'expired'));
+-- +goose StatementEnd
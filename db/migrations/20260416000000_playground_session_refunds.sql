@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A card payment can complete after its hold lapsed and the system was booked by
+-- someone else, or the customer reached their limit of upcoming sessions. Such a
+-- session is never confirmed: it is refund_due until the payment is refunded.
+ALTER TABLE playground.sessions
+    DROP CONSTRAINT IF EXISTS check_session_status;
+
+ALTER TABLE playground.sessions
+    ADD CONSTRAINT check_session_status
+        CHECK (status IN ('held', 'confirmed', 'in_progress', 'completed', 'canceled', 'expired', 'refund_due',
+                          'refunded'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE playground.sessions
+SET status = 'canceled'
+WHERE status IN ('refund_due', 'refunded');
+
+ALTER TABLE playground.sessions
+    DROP CONSTRAINT IF EXISTS check_session_status;
+
+ALTER TABLE playground.sessions
+    ADD CONSTRAINT check_session_status
+        CHECK (status IN ('held', 'confirmed', 'in_progress', 'completed', 'canceled', 'expired'));
+-- +goose StatementEnd
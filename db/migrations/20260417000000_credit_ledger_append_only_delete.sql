@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Ledger entries could still be deleted, which rewrites a customer's balance as surely
+-- as updating them. The only deletion allowed is the cascade from deleting the customer:
+-- by the time it reaches the ledger the customer's row is already gone.
+CREATE OR REPLACE FUNCTION users.reject_credit_ledger_delete()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM users.users WHERE id = OLD.customer_id) THEN
+        RAISE EXCEPTION 'Credit ledger entries cannot be deleted; post a correcting transaction instead';
+    END IF;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER credit_ledger_entries_no_delete
+    BEFORE DELETE ON users.credit_ledger_entries
+    FOR EACH ROW
+    EXECUTE FUNCTION users.reject_credit_ledger_delete();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS credit_ledger_entries_no_delete ON users.credit_ledger_entries;
+DROP FUNCTION IF EXISTS users.reject_credit_ledger_delete();
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Staff write free text into activity descriptions (names, reasons) that has to be
+-- erasable, which entries of the hash chain are not. Entries recorded alongside a staff
+-- activity now point at it instead of copying its description. No foreign key: erasing
+-- or removing the activity must not touch the chain.
+ALTER TABLE audit.audit_logs
+    ADD COLUMN IF NOT EXISTS staff_activity_id UUID;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE audit.audit_logs
+    DROP COLUMN IF EXISTS staff_activity_id;
+-- +goose StatementEnd
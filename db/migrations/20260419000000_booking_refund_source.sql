@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Refunds of bookings that could not be confirmed after payment are issued by the
+-- server itself, not by staff or in the Stripe dashboard.
+ALTER TABLE payments.refunds
+    DROP CONSTRAINT IF EXISTS valid_refund_source;
+
+ALTER TABLE payments.refunds
+    ADD CONSTRAINT valid_refund_source CHECK (source IN ('admin', 'stripe', 'system'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE payments.refunds
+SET source = 'stripe'
+WHERE source = 'system';
+
+ALTER TABLE payments.refunds
+    DROP CONSTRAINT IF EXISTS valid_refund_source;
+
+ALTER TABLE payments.refunds
+    ADD CONSTRAINT valid_refund_source CHECK (source IN ('admin', 'stripe'));
+-- +goose StatementEnd
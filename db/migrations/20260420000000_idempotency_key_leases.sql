@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- A processing key is held until locked_until, which the server renews while the
+-- request runs. Only a key whose lease ran out, because the server holding it died,
+-- can be taken by a retry.
+ALTER TABLE public.idempotency_keys
+    ADD COLUMN IF NOT EXISTS locked_until TIMESTAMPTZ;
+
+UPDATE public.idempotency_keys
+SET locked_until = created_at + INTERVAL '5 minutes'
+WHERE status = 'processing';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE public.idempotency_keys
+    DROP COLUMN IF EXISTS locked_until;
+
+-- +goose StatementEnd
@@ -4,7 +4,9 @@ const (
 	UniqueViolation           = "23505" // Postgres error code for unique violation
 	ForeignKeyViolation       = "23503" // Postgres error code for foreign key violation
 	NotNullViolation          = "23502" // Postgres error code for not null violation
+	CheckViolation            = "23514" // Postgres error code for check constraint violation
 	InvalidTextRepresentation = "22P02" // Error code for invalid input syntax, including enums
 	TxSerializationError      = "40001" // Postgres error code for serialization failure
 	RaiseException            = "P0001" // Postgres error code for RAISE EXCEPTION (used by triggers)
+	ExclusionViolation        = "23P01" // Postgres error code for exclusion constraint violation
 )
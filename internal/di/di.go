@@ -5,8 +5,16 @@ import (
 	analyticsDb "api/internal/domains/analytics/persistence/sqlc/generated"
 	careersDb "api/internal/domains/career/persistence/sqlc/generated"
 	familyDb "api/internal/domains/family/persistence/sqlc/generated"
+	bookingDb "api/internal/domains/booking/persistence/sqlc/generated"
+	courtRentalDb "api/internal/domains/court_rental/persistence/sqlc/generated"
+	giftCardDb "api/internal/domains/gift_card/persistence/sqlc/generated"
+	referralDb "api/internal/domains/referral/persistence/sqlc/generated"
+	auditLogsDb "api/internal/domains/audit/audit_logs/persistence/sqlc/generated"
+	privacyDb "api/internal/domains/privacy/persistence/sqlc/generated"
+	payrollDb "api/internal/domains/payroll/persistence/sqlc/generated"
 	staffActivityLogsDb "api/internal/domains/audit/staff_activity_logs/persistence/sqlc/generated"
 	courtDb "api/internal/domains/court/persistence/sqlc/generated"
+	developmentDb "api/internal/domains/development/persistence/sqlc/generated"
 	discountDb "api/internal/domains/discount/persistence/sqlc/generated"
 	enrollmentDb "api/internal/domains/enrollment/persistence/sqlc/generated"
 	eventDb "api/internal/domains/event/persistence/sqlc/generated"
@@ -20,6 +28,8 @@ import (
 	playgroundDb "api/internal/domains/playground/persistence/sqlc/generated"
 	practiceDb "api/internal/domains/practice/persistence/sqlc/generated"
 	programDb "api/internal/domains/program/persistence/sqlc/generated"
+	safetyDb "api/internal/domains/safety/persistence/sqlc/generated"
+	staffAvailabilityDb "api/internal/domains/staff_availability/persistence/sqlc/generated"
 	subsidyDb "api/internal/domains/subsidy/persistence/sqlc/generated"
 	teamDb "api/internal/domains/team/persistence/sqlc/generated"
 
@@ -27,6 +37,7 @@ import (
 
 	userDb "api/internal/domains/user/persistence/sqlc/generated"
 
+	"api/internal/domains/game/live"
 	"api/internal/services/gcp"
 	"api/internal/services/hubspot"
 	"database/sql"
@@ -37,6 +48,7 @@ type Container struct {
 	Queries         *QueriesType
 	HubspotService  *hubspot.Service
 	FirebaseService *gcp.Service
+	LiveGames       *live.Broadcaster // Fans live game scoring updates out to SSE subscribers
 }
 
 type QueriesType struct {
@@ -62,6 +74,16 @@ type QueriesType struct {
 	AnalyticsDb         *analyticsDb.Queries
 	CareersDb           *careersDb.Queries
 	FamilyDb            *familyDb.Queries
+	BookingDb           *bookingDb.Queries
+	CourtRentalDb       *courtRentalDb.Queries
+	GiftCardDb          *giftCardDb.Queries
+	ReferralDb          *referralDb.Queries
+	AuditLogsDb         *auditLogsDb.Queries
+	PrivacyDb           *privacyDb.Queries
+	PayrollDb           *payrollDb.Queries
+	StaffAvailabilityDb *staffAvailabilityDb.Queries
+	DevelopmentDb       *developmentDb.Queries
+	SafetyDb            *safetyDb.Queries
 }
 
 // NewContainer initializes and returns a Container with database, queries, HubSpot, and Firebase services.
@@ -88,6 +110,7 @@ func NewContainer() *Container {
 		Queries:         queries,
 		HubspotService:  hubspotService,
 		FirebaseService: firebaseService,
+		LiveGames:       live.NewBroadcaster(config.Env.DbConnUrl),
 	}
 }
 
@@ -123,6 +146,16 @@ func initializeQueries(db *sql.DB) *QueriesType {
 		AnalyticsDb:         analyticsDb.New(db),
 		CareersDb:           careersDb.New(db),
 		FamilyDb:            familyDb.New(db),
+		BookingDb:           bookingDb.New(db),
+		CourtRentalDb:       courtRentalDb.New(db),
+		GiftCardDb:          giftCardDb.New(db),
+		ReferralDb:          referralDb.New(db),
+		AuditLogsDb:         auditLogsDb.New(db),
+		PrivacyDb:           privacyDb.New(db),
+		PayrollDb:           payrollDb.New(db),
+		StaffAvailabilityDb: staffAvailabilityDb.New(db),
+		DevelopmentDb:       developmentDb.New(db),
+		SafetyDb:            safetyDb.New(db),
 	}
 }
 
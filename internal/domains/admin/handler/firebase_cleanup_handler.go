@@ -105,7 +105,7 @@ func (h *FirebaseCleanupHandler) RecoverMissingFirebaseUsers(w http.ResponseWrit
 	job.SetTargetEmail(targetEmail)
 
 	// Run the recovery
-	result, err := job.Run(r.Context())
+	result, err := job.RunWithResult(r.Context())
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]string{
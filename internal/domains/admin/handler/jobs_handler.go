@@ -0,0 +1,237 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"api/internal/jobs"
+	errLib "api/internal/libs/errors"
+	responseHandlers "api/internal/libs/responses"
+	contextUtils "api/utils/context"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+// JobRunResponse is one recorded run of a background job
+type JobRunResponse struct {
+	ID          uuid.UUID        `json:"id"`
+	JobName     string           `json:"job_name"`
+	Trigger     string           `json:"trigger" example:"scheduled"` // scheduled, retry or manual
+	DryRun      bool             `json:"dry_run"`
+	Status      string           `json:"status" example:"succeeded"` // running, succeeded or failed
+	TriggeredBy *uuid.UUID       `json:"triggered_by,omitempty"`
+	InstanceID  string           `json:"instance_id"`
+	Counts      map[string]int64 `json:"counts"`
+	Error       *string          `json:"error,omitempty"`
+	StartedAt   time.Time        `json:"started_at"`
+	FinishedAt  *time.Time       `json:"finished_at,omitempty"`
+	DurationMs  *int64           `json:"duration_ms,omitempty"`
+}
+
+// JobResponse describes a background job and its latest run
+type JobResponse struct {
+	Name                string          `json:"name"`
+	Schedule            string          `json:"schedule,omitempty" example:"*/15 * * * *"` // Empty for jobs that only run by hand
+	NextRunAt           *time.Time      `json:"next_run_at,omitempty"`
+	Paused              bool            `json:"paused"`
+	PausedBy            *uuid.UUID      `json:"paused_by,omitempty"`
+	PausedAt            *time.Time      `json:"paused_at,omitempty"`
+	ConsecutiveFailures int             `json:"consecutive_failures"`
+	LastSuccessAt       *time.Time      `json:"last_success_at,omitempty"`
+	SupportsDryRun      bool            `json:"supports_dry_run"`
+	LastRun             *JobRunResponse `json:"last_run,omitempty"`
+}
+
+// JobListResponse lists the background jobs and which instance answered
+type JobListResponse struct {
+	InstanceID string        `json:"instance_id"`
+	IsLeader   bool          `json:"is_leader"` // Whether the answering instance runs the schedules
+	Jobs       []JobResponse `json:"jobs"`
+}
+
+func newJobRunResponse(run jobs.JobRun) JobRunResponse {
+	return JobRunResponse{
+		ID:          run.ID,
+		JobName:     run.JobName,
+		Trigger:     run.Trigger,
+		DryRun:      run.DryRun,
+		Status:      run.Status,
+		TriggeredBy: run.TriggeredBy,
+		InstanceID:  run.InstanceID,
+		Counts:      run.Counts,
+		Error:       run.Error,
+		StartedAt:   run.StartedAt,
+		FinishedAt:  run.FinishedAt,
+		DurationMs:  run.DurationMs,
+	}
+}
+
+func newJobResponse(info jobs.JobInfo) JobResponse {
+	response := JobResponse{
+		Name:                info.Name,
+		Schedule:            info.Schedule,
+		NextRunAt:           info.NextRunAt,
+		Paused:              info.Paused,
+		PausedBy:            info.PausedBy,
+		PausedAt:            info.PausedAt,
+		ConsecutiveFailures: info.ConsecutiveFailures,
+		LastSuccessAt:       info.LastSuccessAt,
+		SupportsDryRun:      info.SupportsDryRun,
+	}
+	if info.LastRun != nil {
+		lastRun := newJobRunResponse(*info.LastRun)
+		response.LastRun = &lastRun
+	}
+	return response
+}
+
+type JobsHandler struct {
+	scheduler *jobs.Scheduler
+}
+
+func NewJobsHandler(scheduler *jobs.Scheduler) *JobsHandler {
+	return &JobsHandler{scheduler: scheduler}
+}
+
+// GetJobs lists the background jobs with their schedule, state and latest run
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} JobListResponse "Jobs"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /admin/jobs [get]
+func (h *JobsHandler) GetJobs(w http.ResponseWriter, r *http.Request) {
+	infos, err := h.scheduler.ListJobs(r.Context())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	response := JobListResponse{
+		InstanceID: h.scheduler.InstanceID(),
+		IsLeader:   h.scheduler.IsLeader(),
+		Jobs:       make([]JobResponse, len(infos)),
+	}
+	for i, info := range infos {
+		response.Jobs[i] = newJobResponse(info)
+	}
+	responseHandlers.RespondWithSuccess(w, response, http.StatusOK)
+}
+
+// GetJobRuns lists a job's run history, newest first
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Param name path string true "Job name" example(GiftCard)
+// @Param limit query int false "Page size (max 100, default 20)"
+// @Param offset query int false "Offset"
+// @Success 200 {array} JobRunResponse "Runs"
+// @Failure 404 {object} map[string]interface{} "Not Found: Job not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /admin/jobs/{name}/runs [get]
+func (h *JobsHandler) GetJobRuns(w http.ResponseWriter, r *http.Request) {
+	limit, offset := int32(20), int32(0)
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsed, err := strconv.Atoi(limitStr); err == nil && parsed > 0 && parsed <= 100 {
+			limit = int32(parsed)
+		}
+	}
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if parsed, err := strconv.Atoi(offsetStr); err == nil && parsed >= 0 {
+			offset = int32(parsed)
+		}
+	}
+
+	runs, err := h.scheduler.ListRuns(r.Context(), chi.URLParam(r, "name"), limit, offset)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	response := make([]JobRunResponse, len(runs))
+	for i, run := range runs {
+		response[i] = newJobRunResponse(run)
+	}
+	responseHandlers.RespondWithSuccess(w, response, http.StatusOK)
+}
+
+// TriggerJob starts a run of a job now, in the background
+// @Description Paused jobs can still be started by hand. Poll the job's runs for the outcome.
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Param name path string true "Job name" example(FirebaseCleanup)
+// @Param dry_run query bool false "Report what the job would do without doing it (only for jobs that support it)"
+// @Success 202 {object} JobRunResponse "Run started"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Job does not support dry runs"
+// @Failure 404 {object} map[string]interface{} "Not Found: Job not found"
+// @Failure 409 {object} map[string]interface{} "Conflict: Job is already running"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /admin/jobs/{name}/run [post]
+func (h *JobsHandler) TriggerJob(w http.ResponseWriter, r *http.Request) {
+	staffID, err := contextUtils.GetUserID(r.Context())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	dryRun := false
+	if dryRunStr := r.URL.Query().Get("dry_run"); dryRunStr != "" {
+		parsed, parseErr := strconv.ParseBool(dryRunStr)
+		if parseErr != nil {
+			responseHandlers.RespondWithError(w, errLib.New("dry_run must be true or false", http.StatusBadRequest))
+			return
+		}
+		dryRun = parsed
+	}
+
+	run, err := h.scheduler.Trigger(r.Context(), chi.URLParam(r, "name"), dryRun, staffID)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, newJobRunResponse(run), http.StatusAccepted)
+}
+
+// PauseJob stops a job's schedule on every instance until it is resumed
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Param name path string true "Job name" example(Dunning)
+// @Success 200 {object} JobResponse "Paused"
+// @Failure 404 {object} map[string]interface{} "Not Found: Job not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /admin/jobs/{name}/pause [post]
+func (h *JobsHandler) PauseJob(w http.ResponseWriter, r *http.Request) {
+	h.setPaused(w, r, true)
+}
+
+// ResumeJob puts a paused job back on its schedule
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Param name path string true "Job name" example(Dunning)
+// @Success 200 {object} JobResponse "Resumed"
+// @Failure 404 {object} map[string]interface{} "Not Found: Job not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /admin/jobs/{name}/resume [post]
+func (h *JobsHandler) ResumeJob(w http.ResponseWriter, r *http.Request) {
+	h.setPaused(w, r, false)
+}
+
+func (h *JobsHandler) setPaused(w http.ResponseWriter, r *http.Request, paused bool) {
+	staffID, err := contextUtils.GetUserID(r.Context())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	info, err := h.scheduler.SetPaused(r.Context(), chi.URLParam(r, "name"), paused, staffID)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, newJobResponse(info), http.StatusOK)
+}
@@ -0,0 +1,70 @@
+package audit_logs
+
+import (
+	"encoding/json"
+	"time"
+
+	values "api/internal/domains/audit/audit_logs/values"
+
+	"github.com/google/uuid"
+)
+
+type AuditLogResponse struct {
+	ID              int64           `json:"id"`
+	ActorID         *uuid.UUID      `json:"actor_id,omitempty"`
+	ActorFirstName  string          `json:"actor_first_name,omitempty"`
+	ActorLastName   string          `json:"actor_last_name,omitempty"`
+	ActorRole       string          `json:"actor_role,omitempty"`
+	IPAddress       string          `json:"ip_address,omitempty"`
+	EntityType      string          `json:"entity_type,omitempty"`
+	EntityID        string          `json:"entity_id,omitempty"`
+	Action          string          `json:"action"`
+	Diff            json.RawMessage `json:"diff" swaggertype:"object"`
+	Description     string          `json:"description,omitempty"`
+	StaffActivityID *uuid.UUID      `json:"staff_activity_id,omitempty"`
+	Hash            string          `json:"hash"`
+	CreatedAt       time.Time       `json:"created_at"`
+}
+
+func NewAuditLogResponse(details values.AuditLog) AuditLogResponse {
+	response := AuditLogResponse{
+		ID:             details.ID,
+		ActorFirstName: details.ActorFirstName,
+		ActorLastName:  details.ActorLastName,
+		ActorRole:      details.ActorRole,
+		IPAddress:      details.IPAddress,
+		EntityType:     details.EntityType,
+		EntityID:       details.EntityID,
+		Action:         details.Action,
+		Diff:           details.Diff,
+		Description:    details.Description,
+		Hash:           details.Hash,
+		CreatedAt:      details.CreatedAt,
+	}
+	if details.ActorID.Valid {
+		actorID := details.ActorID.UUID
+		response.ActorID = &actorID
+	}
+	if details.StaffActivityID.Valid {
+		activityID := details.StaffActivityID.UUID
+		response.StaffActivityID = &activityID
+		response.Description = details.StaffActivityDescription
+	}
+	return response
+}
+
+type ChainVerificationResponse struct {
+	Valid      bool   `json:"valid"`
+	Checked    int    `json:"checked"`
+	BrokenAtID int64  `json:"broken_at_id,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+func NewChainVerificationResponse(result values.ChainVerification) ChainVerificationResponse {
+	return ChainVerificationResponse{
+		Valid:      result.Valid,
+		Checked:    result.Checked,
+		BrokenAtID: result.BrokenAtID,
+		Reason:     result.Reason,
+	}
+}
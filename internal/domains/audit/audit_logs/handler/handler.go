@@ -0,0 +1,124 @@
+package audit_logs
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"api/internal/di"
+	dto "api/internal/domains/audit/audit_logs/dto"
+	service "api/internal/domains/audit/audit_logs/service"
+	values "api/internal/domains/audit/audit_logs/values"
+	errLib "api/internal/libs/errors"
+	responseHandlers "api/internal/libs/responses"
+	"api/internal/libs/validators"
+)
+
+type Handler struct {
+	Service *service.Service
+}
+
+func NewHandler(container *di.Container) *Handler {
+	return &Handler{Service: service.NewService(container)}
+}
+
+// SearchAuditLogs searches the structured audit log.
+// @Tags audit_logs
+// @Summary Search audit logs
+// @Description Retrieves audit log entries, newest first, filtered by entity, actor, action, changed field and date range
+// @Param entity_type query string false "Entity type, e.g. user, subscription, event"
+// @Param entity_id query string false "Entity ID"
+// @Param actor_id query string false "ID of the user who made the change" format(uuid)
+// @Param action query string false "Action, e.g. create, update, suspend"
+// @Param field query string false "Only entries where this field changed"
+// @Param from query string false "Entries at or after this time (RFC3339)"
+// @Param to query string false "Entries before this time (RFC3339)"
+// @Param limit query int false "Number of records to return (default: 50, max: 200)"
+// @Param offset query int false "Number of records to skip (default: 0)"
+// @Produce json
+// @Success 200 {array} dto.AuditLogResponse "Audit log entries"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid filter"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Security Bearer
+// @Router /audit-logs [get]
+func (h *Handler) SearchAuditLogs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	filter := values.SearchFilter{
+		EntityType: query.Get("entity_type"),
+		EntityID:   query.Get("entity_id"),
+		Action:     query.Get("action"),
+		Field:      query.Get("field"),
+		Limit:      50,
+	}
+
+	if actorIDStr := query.Get("actor_id"); actorIDStr != "" {
+		actorID, err := validators.ParseUUID(actorIDStr)
+		if err != nil {
+			responseHandlers.RespondWithError(w, err)
+			return
+		}
+		filter.ActorID = actorID
+	}
+
+	for param, target := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		if value := query.Get(param); value != "" {
+			parsed, parseErr := time.Parse(time.RFC3339, value)
+			if parseErr != nil {
+				responseHandlers.RespondWithError(w, errLib.New("Invalid '"+param+"' format, use RFC3339", http.StatusBadRequest))
+				return
+			}
+			*target = &parsed
+		}
+	}
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, parseErr := strconv.ParseInt(limitStr, 10, 32)
+		if parseErr != nil || limit < 1 || limit > 200 {
+			responseHandlers.RespondWithError(w, errLib.New("'limit' must be between 1 and 200", http.StatusBadRequest))
+			return
+		}
+		filter.Limit = int32(limit)
+	}
+
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		offset, parseErr := strconv.ParseInt(offsetStr, 10, 32)
+		if parseErr != nil || offset < 0 {
+			responseHandlers.RespondWithError(w, errLib.New("'offset' must be a non-negative number", http.StatusBadRequest))
+			return
+		}
+		filter.Offset = int32(offset)
+	}
+
+	logs, err := h.Service.Search(r.Context(), filter)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	response := make([]dto.AuditLogResponse, len(logs))
+	for i, entry := range logs {
+		response[i] = dto.NewAuditLogResponse(entry)
+	}
+
+	responseHandlers.RespondWithSuccess(w, response, http.StatusOK)
+}
+
+// VerifyAuditLogChain checks the audit log has not been tampered with.
+// @Tags audit_logs
+// @Summary Verify the audit log hash chain
+// @Description Recomputes every entry's hash and checks it links to the entry before it. Reports the first entry that does not match.
+// @Produce json
+// @Success 200 {object} dto.ChainVerificationResponse "Verification result"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Security Bearer
+// @Router /audit-logs/verify [get]
+func (h *Handler) VerifyAuditLogChain(w http.ResponseWriter, r *http.Request) {
+	result, err := h.Service.VerifyChain(r.Context())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	responseHandlers.RespondWithSuccess(w, dto.NewChainVerificationResponse(result), http.StatusOK)
+}
@@ -0,0 +1,156 @@
+package audit_logs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"api/internal/di"
+	db "api/internal/domains/audit/audit_logs/persistence/sqlc/generated"
+	values "api/internal/domains/audit/audit_logs/values"
+	errLib "api/internal/libs/errors"
+
+	"github.com/google/uuid"
+)
+
+type Repository struct {
+	Queries *db.Queries
+}
+
+func NewRepository(container *di.Container) *Repository {
+	return &Repository{
+		Queries: container.Queries.AuditLogsDb,
+	}
+}
+
+// Insert appends an entry to the hash chain. It must run inside tx so the entry is
+// committed or rolled back together with the change it records; the advisory lock it
+// takes is held until tx ends and keeps concurrent writers from forking the chain.
+func (r *Repository) Insert(ctx context.Context, tx *sql.Tx, entry values.AuditLog) (int64, *errLib.CommonError) {
+	qtx := r.Queries.WithTx(tx)
+
+	if err := qtx.AcquireAuditChainLock(ctx); err != nil {
+		log.Printf("Failed to lock audit log chain: %v", err)
+		return 0, errLib.New("Internal server error when recording audit log", http.StatusInternalServerError)
+	}
+
+	prevHash, err := qtx.GetLatestAuditLogHash(ctx)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("Failed to get latest audit log hash: %v", err)
+		return 0, errLib.New("Internal server error when recording audit log", http.StatusInternalServerError)
+	}
+
+	// Postgres keeps microseconds; hash what will be read back
+	entry.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	entry.PrevHash = prevHash
+
+	hash, err := values.ComputeHash(prevHash, entry)
+	if err != nil {
+		log.Printf("Failed to hash audit log for %s %s: %v", entry.EntityType, entry.EntityID, err)
+		return 0, errLib.New("Internal server error when recording audit log", http.StatusInternalServerError)
+	}
+
+	id, err := qtx.InsertAuditLog(ctx, db.InsertAuditLogParams{
+		ActorID:         entry.ActorID,
+		ActorRole:       sql.NullString{String: entry.ActorRole, Valid: entry.ActorRole != ""},
+		IpAddress:       sql.NullString{String: entry.IPAddress, Valid: entry.IPAddress != ""},
+		EntityType:      sql.NullString{String: entry.EntityType, Valid: entry.EntityType != ""},
+		EntityID:        sql.NullString{String: entry.EntityID, Valid: entry.EntityID != ""},
+		Action:          entry.Action,
+		Diff:            entry.Diff,
+		Description:     entry.Description,
+		StaffActivityID: entry.StaffActivityID,
+		PrevHash:        prevHash,
+		Hash:            hash,
+		CreatedAt:       entry.CreatedAt,
+	})
+	if err != nil {
+		log.Printf("Failed to insert audit log for %s %s: %v", entry.EntityType, entry.EntityID, err)
+		return 0, errLib.New("Internal server error when recording audit log", http.StatusInternalServerError)
+	}
+
+	return id, nil
+}
+
+func (r *Repository) Search(ctx context.Context, filter values.SearchFilter) ([]values.AuditLog, *errLib.CommonError) {
+	params := db.SearchAuditLogsParams{
+		Limit:      filter.Limit,
+		Offset:     filter.Offset,
+		EntityType: sql.NullString{String: filter.EntityType, Valid: filter.EntityType != ""},
+		EntityID:   sql.NullString{String: filter.EntityID, Valid: filter.EntityID != ""},
+		ActorID:    uuid.NullUUID{UUID: filter.ActorID, Valid: filter.ActorID != uuid.Nil},
+		Action:     sql.NullString{String: filter.Action, Valid: filter.Action != ""},
+		Field:      sql.NullString{String: filter.Field, Valid: filter.Field != ""},
+	}
+	if filter.From != nil {
+		params.From = sql.NullTime{Time: *filter.From, Valid: true}
+	}
+	if filter.To != nil {
+		params.To = sql.NullTime{Time: *filter.To, Valid: true}
+	}
+
+	rows, err := r.Queries.SearchAuditLogs(ctx, params)
+	if err != nil {
+		log.Printf("Failed to search audit logs: %v", err)
+		return nil, errLib.New("Internal server error when searching audit logs", http.StatusInternalServerError)
+	}
+
+	logs := make([]values.AuditLog, len(rows))
+	for i, row := range rows {
+		logs[i] = values.AuditLog{
+			ID:             row.ID,
+			ActorID:        row.ActorID,
+			ActorRole:      row.ActorRole.String,
+			IPAddress:      row.IpAddress.String,
+			EntityType:     row.EntityType.String,
+			EntityID:       row.EntityID.String,
+			Action:         row.Action,
+			Diff:           row.Diff,
+			Description:    row.Description,
+			PrevHash:       row.PrevHash,
+			Hash:           row.Hash,
+			CreatedAt:      row.CreatedAt,
+			ActorFirstName: row.ActorFirstName.String,
+			ActorLastName:  row.ActorLastName.String,
+			// Gone once the staff activity is erased; the entry itself stays intact
+			StaffActivityID:          row.StaffActivityID,
+			StaffActivityDescription: row.StaffActivityDescription.String,
+		}
+	}
+	return logs, nil
+}
+
+// ListAfter returns up to limit entries following afterID in chain order
+func (r *Repository) ListAfter(ctx context.Context, afterID int64, limit int32) ([]values.AuditLog, *errLib.CommonError) {
+	rows, err := r.Queries.ListAuditLogsAfter(ctx, db.ListAuditLogsAfterParams{
+		ID:    afterID,
+		Limit: limit,
+	})
+	if err != nil {
+		log.Printf("Failed to list audit logs after %d: %v", afterID, err)
+		return nil, errLib.New("Internal server error when reading audit logs", http.StatusInternalServerError)
+	}
+
+	logs := make([]values.AuditLog, len(rows))
+	for i, row := range rows {
+		logs[i] = values.AuditLog{
+			ID:              row.ID,
+			ActorID:         row.ActorID,
+			ActorRole:       row.ActorRole.String,
+			IPAddress:       row.IpAddress.String,
+			EntityType:      row.EntityType.String,
+			EntityID:        row.EntityID.String,
+			Action:          row.Action,
+			Diff:            row.Diff,
+			Description:     row.Description,
+			StaffActivityID: row.StaffActivityID,
+			PrevHash:        row.PrevHash,
+			Hash:            row.Hash,
+			CreatedAt:       row.CreatedAt,
+		}
+	}
+	return logs, nil
+}
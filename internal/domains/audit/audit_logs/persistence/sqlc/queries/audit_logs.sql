@@ -0,0 +1,65 @@
+-- name: AcquireAuditChainLock :exec
+-- Serialises writers of the hash chain until the surrounding transaction ends.
+SELECT pg_advisory_xact_lock(hashtext('audit.audit_logs'));
+
+-- name: GetLatestAuditLogHash :one
+SELECT hash
+FROM audit.audit_logs
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: InsertAuditLog :one
+INSERT INTO audit.audit_logs (actor_id, actor_role, ip_address, entity_type, entity_id, action, diff, description,
+                              staff_activity_id, prev_hash, hash, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+RETURNING id;
+
+-- name: SearchAuditLogs :many
+SELECT al.id,
+       al.actor_id,
+       al.actor_role,
+       al.ip_address,
+       al.entity_type,
+       al.entity_id,
+       al.action,
+       al.diff,
+       al.description,
+       al.staff_activity_id,
+       sal.activity_description AS staff_activity_description,
+       al.prev_hash,
+       al.hash,
+       al.created_at,
+       u.first_name AS actor_first_name,
+       u.last_name  AS actor_last_name
+FROM audit.audit_logs al
+         LEFT JOIN users.users u ON u.id = al.actor_id
+         LEFT JOIN audit.staff_activity_logs sal ON sal.id = al.staff_activity_id
+WHERE (sqlc.narg('entity_type')::text IS NULL OR al.entity_type = sqlc.narg('entity_type')::text)
+  AND (sqlc.narg('entity_id')::text IS NULL OR al.entity_id = sqlc.narg('entity_id')::text)
+  AND (sqlc.narg('actor_id')::uuid IS NULL OR al.actor_id = sqlc.narg('actor_id')::uuid)
+  AND (sqlc.narg('action')::text IS NULL OR al.action = sqlc.narg('action')::text)
+  AND (sqlc.narg('field')::text IS NULL OR al.diff ? sqlc.narg('field')::text)
+  AND (sqlc.narg('from')::timestamptz IS NULL OR al.created_at >= sqlc.narg('from')::timestamptz)
+  AND (sqlc.narg('to')::timestamptz IS NULL OR al.created_at < sqlc.narg('to')::timestamptz)
+ORDER BY al.id DESC
+LIMIT $1 OFFSET $2;
+
+-- name: ListAuditLogsAfter :many
+-- Walks the chain in order for verification.
+SELECT id,
+       actor_id,
+       actor_role,
+       ip_address,
+       entity_type,
+       entity_id,
+       action,
+       diff,
+       description,
+       staff_activity_id,
+       prev_hash,
+       hash,
+       created_at
+FROM audit.audit_logs
+WHERE id > $1
+ORDER BY id
+LIMIT $2;
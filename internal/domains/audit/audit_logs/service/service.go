@@ -0,0 +1,138 @@
+package audit_logs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"api/internal/di"
+	repo "api/internal/domains/audit/audit_logs/persistence"
+	values "api/internal/domains/audit/audit_logs/values"
+	errLib "api/internal/libs/errors"
+	contextUtils "api/utils/context"
+	txUtils "api/utils/db"
+
+	"github.com/google/uuid"
+)
+
+// verifyPageSize is how many entries are read at a time while walking the chain
+const verifyPageSize = 1000
+
+type Service struct {
+	repo *repo.Repository
+	db   *sql.DB
+}
+
+func NewService(container *di.Container) *Service {
+	return &Service{
+		repo: repo.NewRepository(container),
+		db:   container.DB,
+	}
+}
+
+// Record appends a change to the audit log. Pass the transaction the change is made in so
+// both commit or roll back together; with a nil tx the entry is written in its own.
+// The actor defaults to the user in ctx, and their role and IP are taken from ctx;
+// changes made outside a request (jobs, webhooks) are recorded without an actor.
+func (s *Service) Record(ctx context.Context, tx *sql.Tx, entry values.Entry) *errLib.CommonError {
+	if tx == nil {
+		return txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+			return s.Record(ctx, tx, entry)
+		})
+	}
+
+	changes, err := values.Diff(entry.Before, entry.After)
+	if err != nil {
+		log.Printf("Failed to diff audit snapshots of %s %s: %v", entry.EntityType, entry.EntityID, err)
+		return errLib.New("Internal server error when recording audit log", http.StatusInternalServerError)
+	}
+	diff, err := json.Marshal(changes)
+	if err != nil {
+		log.Printf("Failed to encode audit diff of %s %s: %v", entry.EntityType, entry.EntityID, err)
+		return errLib.New("Internal server error when recording audit log", http.StatusInternalServerError)
+	}
+
+	actorID := entry.ActorID
+	if actorID == uuid.Nil {
+		actorID, _ = contextUtils.GetUserID(ctx)
+	}
+	role, _ := contextUtils.GetUserRole(ctx)
+
+	action := entry.Action
+	if action == "" {
+		action = values.ActionUpdate
+	}
+
+	_, insertErr := s.repo.Insert(ctx, tx, values.AuditLog{
+		ActorID:     uuid.NullUUID{UUID: actorID, Valid: actorID != uuid.Nil},
+		ActorRole:   string(role),
+		IPAddress:   contextUtils.GetIPAddress(ctx),
+		EntityType:  entry.EntityType,
+		EntityID:    entry.EntityID,
+		Action:      action,
+		Diff:        diff,
+		Description: entry.Description,
+		StaffActivityID: uuid.NullUUID{
+			UUID:  entry.StaffActivityID,
+			Valid: entry.StaffActivityID != uuid.Nil,
+		},
+	})
+	return insertErr
+}
+
+func (s *Service) Search(ctx context.Context, filter values.SearchFilter) ([]values.AuditLog, *errLib.CommonError) {
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, errLib.New("'from' must be before 'to'", http.StatusBadRequest)
+	}
+	return s.repo.Search(ctx, filter)
+}
+
+// VerifyChain walks the whole audit log in order and checks that every entry points at
+// the hash of the one before it and that its own hash still matches its contents.
+func (s *Service) VerifyChain(ctx context.Context) (values.ChainVerification, *errLib.CommonError) {
+	result := values.ChainVerification{Valid: true}
+
+	var (
+		lastID   int64
+		prevHash string
+	)
+	for {
+		page, err := s.repo.ListAfter(ctx, lastID, verifyPageSize)
+		if err != nil {
+			return result, err
+		}
+
+		for _, entry := range page {
+			result.Checked++
+
+			if entry.PrevHash != prevHash {
+				return brokenAt(result, entry.ID, "previous hash does not match the entry before it"), nil
+			}
+
+			hash, hashErr := values.ComputeHash(prevHash, entry)
+			if hashErr != nil {
+				return brokenAt(result, entry.ID, fmt.Sprintf("diff could not be read: %v", hashErr)), nil
+			}
+			if hash != entry.Hash {
+				return brokenAt(result, entry.ID, "hash does not match the entry contents"), nil
+			}
+
+			prevHash = entry.Hash
+			lastID = entry.ID
+		}
+
+		if len(page) < verifyPageSize {
+			return result, nil
+		}
+	}
+}
+
+func brokenAt(result values.ChainVerification, id int64, reason string) values.ChainVerification {
+	result.Valid = false
+	result.BrokenAtID = id
+	result.Reason = reason
+	return result
+}
@@ -0,0 +1,207 @@
+package audit_logs
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Redacted replaces the values of personal fields in a diff
+const Redacted = "[redacted]"
+
+// Diff compares two snapshots field by field and returns the fields that changed.
+// Snapshots are compared by their JSON form, so struct tags decide the field names.
+// Snapshots that are not JSON objects are compared as a whole under "value".
+// Fields tagged `audit:"personal"`, at any depth, are compared but their values are
+// replaced with Redacted.
+func Diff(before, after interface{}) (map[string]FieldChange, error) {
+	beforeValue, err := normalize(before)
+	if err != nil {
+		return nil, err
+	}
+	afterValue, err := normalize(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := diffValues(beforeValue, afterValue)
+
+	personal := map[string]bool{}
+	personalFields(reflect.TypeOf(before), personal, map[reflect.Type]bool{})
+	personalFields(reflect.TypeOf(after), personal, map[reflect.Type]bool{})
+	if len(personal) == 0 {
+		return changes, nil
+	}
+	for field, change := range changes {
+		if personal[field] {
+			changes[field] = FieldChange{Before: redactValue(change.Before), After: redactValue(change.After)}
+			continue
+		}
+		changes[field] = FieldChange{Before: redact(change.Before, personal), After: redact(change.After, personal)}
+	}
+	return changes, nil
+}
+
+func diffValues(beforeValue, afterValue interface{}) map[string]FieldChange {
+	changes := map[string]FieldChange{}
+
+	beforeFields, beforeIsObject := beforeValue.(map[string]interface{})
+	afterFields, afterIsObject := afterValue.(map[string]interface{})
+	if (beforeIsObject || beforeValue == nil) && (afterIsObject || afterValue == nil) {
+		for field, value := range beforeFields {
+			if other, ok := afterFields[field]; !ok || !reflect.DeepEqual(value, other) {
+				changes[field] = FieldChange{Before: value, After: other}
+			}
+		}
+		for field, value := range afterFields {
+			if _, ok := beforeFields[field]; !ok {
+				changes[field] = FieldChange{Before: nil, After: value}
+			}
+		}
+		return changes
+	}
+
+	if !reflect.DeepEqual(beforeValue, afterValue) {
+		changes["value"] = FieldChange{Before: beforeValue, After: afterValue}
+	}
+	return changes
+}
+
+// personalFields collects the JSON names of the fields of t tagged `audit:"personal"`,
+// looking through pointers, slices, maps and nested structs
+func personalFields(t reflect.Type, names map[string]bool, seen map[reflect.Type]bool) {
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct || seen[t] {
+		return
+	}
+	seen[t] = true
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Name
+		if tag := field.Tag.Get("json"); tag != "" {
+			if tag == "-" {
+				continue
+			}
+			if tagName := strings.Split(tag, ",")[0]; tagName != "" {
+				name = tagName
+			}
+		}
+		if field.Tag.Get("audit") == "personal" {
+			names[name] = true
+		}
+		personalFields(field.Type, names, seen)
+	}
+}
+
+// redact replaces the values of personal fields found anywhere in a normalized value
+func redact(value interface{}, personal map[string]bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if personal[key] {
+				redacted[key] = redactValue(item)
+			} else {
+				redacted[key] = redact(item, personal)
+			}
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = redact(item, personal)
+		}
+		return redacted
+	default:
+		return value
+	}
+}
+
+// redactValue keeps whether a personal field was set, but not what it was set to
+func redactValue(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	return Redacted
+}
+
+// CanonicalJSON re-encodes a JSON document with object keys sorted so equal documents
+// hash the same regardless of how the database returns them
+func CanonicalJSON(raw []byte) ([]byte, error) {
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return []byte("{}"), nil
+	}
+
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+// ComputeHash returns the hash of an entry chained onto prevHash. It covers every
+// stored column except the id and the hash itself. The staff activity reference was
+// added later and is only hashed when set, so earlier entries keep their hash.
+func ComputeHash(prevHash string, log AuditLog) (string, error) {
+	diff, err := CanonicalJSON(log.Diff)
+	if err != nil {
+		return "", err
+	}
+
+	actorID := ""
+	if log.ActorID.Valid {
+		actorID = log.ActorID.UUID.String()
+	}
+
+	payload := strings.Join([]string{
+		prevHash,
+		actorID,
+		log.ActorRole,
+		log.IPAddress,
+		log.EntityType,
+		log.EntityID,
+		log.Action,
+		string(diff),
+		log.Description,
+		log.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}, "\n")
+	if log.StaffActivityID.Valid {
+		payload += "\n" + log.StaffActivityID.UUID.String()
+	}
+
+	sum := sha256.Sum256([]byte(payload))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// normalize turns a snapshot into the generic form encoding/json decodes to, keeping
+// numbers as json.Number so large values are compared exactly
+func normalize(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var normalized interface{}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
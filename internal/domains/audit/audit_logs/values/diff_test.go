@@ -0,0 +1,153 @@
+package audit_logs
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+type snapshot struct {
+	Status  string `json:"status"`
+	Balance int    `json:"balance"`
+	Note    string `json:"note,omitempty"`
+}
+
+func TestDiffOnlyKeepsChangedFields(t *testing.T) {
+	changes, err := Diff(snapshot{Status: "active", Balance: 10}, snapshot{Status: "active", Balance: 25})
+
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, json.Number("10"), changes["balance"].Before)
+	require.Equal(t, json.Number("25"), changes["balance"].After)
+}
+
+func TestDiffAddedAndRemovedFields(t *testing.T) {
+	changes, err := Diff(snapshot{Status: "active", Note: "vip"}, snapshot{Status: "active"})
+
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, "vip", changes["note"].Before)
+	require.Nil(t, changes["note"].After)
+}
+
+func TestDiffCreation(t *testing.T) {
+	changes, err := Diff(nil, snapshot{Status: "active", Balance: 5})
+
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	require.Nil(t, changes["status"].Before)
+	require.Equal(t, "active", changes["status"].After)
+}
+
+func TestDiffNonObjectValues(t *testing.T) {
+	changes, err := Diff(3, 4)
+
+	require.NoError(t, err)
+	require.Equal(t, FieldChange{Before: json.Number("3"), After: json.Number("4")}, changes["value"])
+
+	unchanged, err := Diff("same", "same")
+	require.NoError(t, err)
+	require.Empty(t, unchanged)
+}
+
+func TestCanonicalJSONSortsKeys(t *testing.T) {
+	a, err := CanonicalJSON([]byte(`{"b": 1, "a": {"d": 2, "c": 1.50}}`))
+	require.NoError(t, err)
+	b, err := CanonicalJSON([]byte(`{"a":{"c":1.50,"d":2},"b":1}`))
+	require.NoError(t, err)
+
+	require.Equal(t, string(a), string(b))
+	require.Equal(t, `{"a":{"c":1.50,"d":2},"b":1}`, string(a))
+}
+
+func TestCanonicalJSONEmpty(t *testing.T) {
+	out, err := CanonicalJSON(nil)
+
+	require.NoError(t, err)
+	require.Equal(t, "{}", string(out))
+}
+
+func TestComputeHashChains(t *testing.T) {
+	log := AuditLog{
+		ActorID:    uuid.NullUUID{UUID: uuid.MustParse("6f1c1b0e-2d5e-4c7a-9a43-1f0b7c1d2e3f"), Valid: true},
+		ActorRole:  "ADMIN",
+		EntityType: "user",
+		EntityID:   "42",
+		Action:     ActionUpdate,
+		Diff:       json.RawMessage(`{"status":{"after":"suspended","before":"active"}}`),
+		CreatedAt:  time.Date(2026, 4, 5, 12, 0, 0, 123456000, time.UTC),
+	}
+
+	first, err := ComputeHash("", log)
+	require.NoError(t, err)
+	require.Len(t, first, 64)
+
+	again, err := ComputeHash("", log)
+	require.NoError(t, err)
+	require.Equal(t, first, again)
+
+	chained, err := ComputeHash(first, log)
+	require.NoError(t, err)
+	require.NotEqual(t, first, chained)
+
+	// the database may hand the diff back with different spacing and key order
+	log.Diff = json.RawMessage(`{"status": {"before": "active", "after": "suspended"}}`)
+	reordered, err := ComputeHash("", log)
+	require.NoError(t, err)
+	require.Equal(t, first, reordered)
+
+	log.EntityID = "43"
+	tampered, err := ComputeHash("", log)
+	require.NoError(t, err)
+	require.NotEqual(t, first, tampered)
+
+	// entries written before the staff activity reference existed keep their hash
+	log.EntityID = "42"
+	log.StaffActivityID = uuid.NullUUID{UUID: uuid.New(), Valid: true}
+	referenced, err := ComputeHash("", log)
+	require.NoError(t, err)
+	require.NotEqual(t, first, referenced)
+}
+
+type comment struct {
+	Rating int32   `json:"rating"`
+	Text   *string `json:"text,omitempty" audit:"personal"`
+}
+
+type personalSnapshot struct {
+	Status   string    `json:"status"`
+	Reason   string    `json:"reason,omitempty" audit:"personal"`
+	Comments []comment `json:"comments"`
+}
+
+func TestDiffRedactsPersonalFields(t *testing.T) {
+	before := "slow to warm up"
+	after := "sprained ankle at practice"
+	changes, err := Diff(
+		personalSnapshot{Status: "active", Comments: []comment{{Rating: 3, Text: &before}}},
+		&personalSnapshot{Status: "suspended", Reason: "medical leave", Comments: []comment{{Rating: 4, Text: &after}}},
+	)
+
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	require.Equal(t, FieldChange{Before: "active", After: "suspended"}, changes["status"])
+	require.Equal(t, FieldChange{Before: nil, After: Redacted}, changes["reason"], "a personal field is recorded as changed without its value")
+	require.Equal(t, FieldChange{
+		Before: []interface{}{map[string]interface{}{"rating": json.Number("3"), "text": Redacted}},
+		After:  []interface{}{map[string]interface{}{"rating": json.Number("4"), "text": Redacted}},
+	}, changes["comments"])
+}
+
+func TestDiffPersonalFieldUnchanged(t *testing.T) {
+	changes, err := Diff(
+		personalSnapshot{Status: "active", Reason: "medical leave"},
+		personalSnapshot{Status: "suspended", Reason: "medical leave"},
+	)
+
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Contains(t, changes, "status")
+}
@@ -0,0 +1,84 @@
+package audit_logs
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Common actions. Flows may use their own verbs; these cover the generic cases.
+const (
+	ActionCreate   = "create"
+	ActionUpdate   = "update"
+	ActionDelete   = "delete"
+	ActionActivity = "activity"
+)
+
+// Entry is a change to be recorded. Before and After are snapshots of the entity, any
+// value that marshals to JSON; the stored diff only keeps the fields that changed.
+// Leave Before nil for creations and After nil for deletions.
+//
+// Entries can never be changed or erased, so they must not hold personal data: tag
+// snapshot fields with free text about a person `audit:"personal"` so their values are
+// left out of the diff, and keep the Description to IDs. StaffActivityID points at the
+// staff activity holding the description instead, which can be erased.
+type Entry struct {
+	ActorID         uuid.UUID
+	EntityType      string
+	EntityID        string
+	Action          string
+	Before          interface{}
+	After           interface{}
+	Description     string
+	StaffActivityID uuid.UUID
+}
+
+// FieldChange is the before and after value of a single field
+type FieldChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditLog is a stored entry of the hash chain
+type AuditLog struct {
+	ID          int64
+	ActorID     uuid.NullUUID
+	ActorRole   string
+	IPAddress   string
+	EntityType  string
+	EntityID    string
+	Action      string
+	Diff        json.RawMessage
+	Description string
+	// StaffActivityID is the staff activity the entry was recorded with. Its description
+	// is read from there, into StaffActivityDescription, when searching.
+	StaffActivityID          uuid.NullUUID
+	StaffActivityDescription string
+	PrevHash                 string
+	Hash                     string
+	CreatedAt                time.Time
+	ActorFirstName           string
+	ActorLastName            string
+}
+
+type SearchFilter struct {
+	EntityType string
+	EntityID   string
+	ActorID    uuid.UUID
+	Action     string
+	Field      string
+	From       *time.Time
+	To         *time.Time
+	Limit      int32
+	Offset     int32
+}
+
+// ChainVerification is the outcome of walking the hash chain. BrokenAtID is the first
+// entry that does not match, zero when the chain is intact.
+type ChainVerification struct {
+	Checked    int
+	Valid      bool
+	BrokenAtID int64
+	Reason     string
+}
@@ -30,17 +30,18 @@ func (r *Repository) WithTx(tx *sql.Tx) *Repository {
 	}
 }
 
-func (r *Repository) InsertStaffActivity(ctx context.Context, tx *sql.Tx, staffId uuid.UUID, activityDescription string) *errLib.CommonError {
+func (r *Repository) InsertStaffActivity(ctx context.Context, tx *sql.Tx, staffId uuid.UUID, activityDescription string) (uuid.UUID, *errLib.CommonError) {
 
-	if err := r.Queries.WithTx(tx).InsertStaffActivity(ctx, db.InsertStaffActivityParams{
+	id, err := r.Queries.WithTx(tx).InsertStaffActivity(ctx, db.InsertStaffActivityParams{
 		StaffID:             staffId,
 		ActivityDescription: activityDescription,
-	}); err != nil {
+	})
+	if err != nil {
 		log.Printf("Failed to insert staff activity for staff: %+v. Error: %v", staffId, err.Error())
-		return errLib.New("Internal server error when inserting staff activity", http.StatusInternalServerError)
+		return uuid.Nil, errLib.New("Internal server error when inserting staff activity", http.StatusInternalServerError)
 	}
 
-	return nil
+	return id, nil
 }
 
 func (r *Repository) GetStaffActivityLogs(ctx context.Context, staffId uuid.UUID, searchDescription string, limit, offset int32) ([]values.StaffActivityLog, *errLib.CommonError) {
@@ -14,13 +14,17 @@ import (
 )
 
 const getStaffActivityLogs = `-- name: GetStaffActivityLogs :many
-SELECT sal.id, sal.staff_id, sal.activity_description, sal.created_at,
-       u.first_name, u.last_name, u.email
+SELECT sal.id,
+       sal.staff_id,
+       sal.activity_description,
+       sal.created_at,
+       u.first_name,
+       u.last_name,
+       u.email
 FROM audit.staff_activity_logs sal
-JOIN staff.staff s ON sal.staff_id = s.id
-JOIN users.users u ON s.id = u.id
-WHERE
-    ($3::uuid IS NULL OR sal.staff_id = $3::uuid)
+         JOIN staff.staff s ON sal.staff_id = s.id
+         JOIN users.users u ON s.id = u.id
+WHERE ($3::uuid IS NULL OR sal.staff_id = $3::uuid)
   AND (
     $4::text IS NULL
         OR sal.activity_description ILIKE '%' || $4::text || '%'
@@ -82,9 +86,10 @@ func (q *Queries) GetStaffActivityLogs(ctx context.Context, arg GetStaffActivity
 	return items, nil
 }
 
-const insertStaffActivity = `-- name: InsertStaffActivity :exec
+const insertStaffActivity = `-- name: InsertStaffActivity :one
 INSERT INTO audit.staff_activity_logs (staff_id, activity_description)
 VALUES ($1, $2)
+RETURNING id
 `
 
 type InsertStaffActivityParams struct {
@@ -92,7 +97,9 @@ type InsertStaffActivityParams struct {
 	ActivityDescription string    `json:"activity_description"`
 }
 
-func (q *Queries) InsertStaffActivity(ctx context.Context, arg InsertStaffActivityParams) error {
-	_, err := q.db.ExecContext(ctx, insertStaffActivity, arg.StaffID, arg.ActivityDescription)
-	return err
+func (q *Queries) InsertStaffActivity(ctx context.Context, arg InsertStaffActivityParams) (uuid.UUID, error) {
+	row := q.db.QueryRowContext(ctx, insertStaffActivity, arg.StaffID, arg.ActivityDescription)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
 }
@@ -1,6 +1,7 @@
--- name: InsertStaffActivity :exec
+-- name: InsertStaffActivity :one
 INSERT INTO audit.staff_activity_logs (staff_id, activity_description)
-VALUES ($1, $2);
+VALUES ($1, $2)
+RETURNING id;
 
 -- name: GetStaffActivityLogs :many
 SELECT sal.id,
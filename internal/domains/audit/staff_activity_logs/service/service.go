@@ -5,30 +5,68 @@ import (
 	"database/sql"
 
 	"api/internal/di"
+	auditLogs "api/internal/domains/audit/audit_logs/service"
+	auditValues "api/internal/domains/audit/audit_logs/values"
 	repo "api/internal/domains/audit/staff_activity_logs/persistence"
 	values "api/internal/domains/audit/staff_activity_logs/values"
 	errLib "api/internal/libs/errors"
+	txUtils "api/utils/db"
 
 	"github.com/google/uuid"
 )
 
 type Service struct {
-	repo *repo.Repository
-	db   *sql.DB
+	repo      *repo.Repository
+	auditLogs *auditLogs.Service
+	db        *sql.DB
 }
 
 func NewService(container *di.Container) *Service {
 	return &Service{
-		repo: repo.NewRepository(container),
-		db:   container.DB,
+		repo:      repo.NewRepository(container),
+		auditLogs: auditLogs.NewService(container),
+		db:        container.DB,
 	}
 }
 
 // InsertStaffActivity inserts a new staff activity log into the database.
 // It is not implemented in HTTP handler currently, only as a service for other domains.
 // It should be used in the context of a transaction for atomicity purposes.
+// The activity is also recorded in the structured audit log; use RecordChange to
+// include the entity it concerns and what changed.
 func (s *Service) InsertStaffActivity(ctx context.Context, tx *sql.Tx, staffId uuid.UUID, activityDescription string) *errLib.CommonError {
-	return s.repo.InsertStaffActivity(ctx, tx, staffId, activityDescription)
+	return s.RecordChange(ctx, tx, staffId, auditValues.Entry{
+		Action:      auditValues.ActionActivity,
+		Description: activityDescription,
+	})
+}
+
+// RecordChange records a staff change in both the staff activity log and the structured
+// audit log, inside tx so they commit with the change itself. With a nil tx both are
+// written in a transaction of their own. The audit log entry points at the staff
+// activity rather than copying its description, which stays erasable. A nil staffId
+// records the change without a staff activity entry (e.g. automatic suspensions); the
+// description is then kept in the audit log itself, so it must only hold IDs, and the
+// entry is attributed to the user in ctx, if any.
+func (s *Service) RecordChange(ctx context.Context, tx *sql.Tx, staffId uuid.UUID, entry auditValues.Entry) *errLib.CommonError {
+	if tx == nil {
+		return txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+			return s.RecordChange(ctx, tx, staffId, entry)
+		})
+	}
+
+	if staffId != uuid.Nil && entry.Description != "" {
+		activityID, err := s.repo.InsertStaffActivity(ctx, tx, staffId, entry.Description)
+		if err != nil {
+			return err
+		}
+		// Staff descriptions may name people, so the audit log only points at them
+		entry.Description = ""
+		entry.StaffActivityID = activityID
+	}
+
+	entry.ActorID = staffId
+	return s.auditLogs.Record(ctx, tx, entry)
 }
 
 func (s *Service) GetStaffActivityLogs(ctx context.Context, staffId uuid.UUID, searchDescription string, limit, offset int32) ([]values.StaffActivityLog, *errLib.CommonError) {
@@ -0,0 +1,173 @@
+package booking
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	values "api/internal/domains/booking/values"
+	errLib "api/internal/libs/errors"
+	"api/internal/libs/validators"
+
+	"github.com/google/uuid"
+)
+
+// MaxAvailabilityWindow bounds how far apart from and to may be in one query.
+const MaxAvailabilityWindow = 31 * 24 * time.Hour
+
+type BookingResponseDto struct {
+	ActivityType values.ActivityType `json:"activity_type"`
+	ActivityID   uuid.UUID           `json:"activity_id"`
+	Title        string              `json:"title"`
+	StartAt      time.Time           `json:"start_at"`
+	EndAt        time.Time           `json:"end_at"`
+}
+
+type ConflictResponseDto struct {
+	BookingResponseDto
+	Overridable bool `json:"overridable"`
+}
+
+type ResourceAvailabilityResponseDto struct {
+	ResourceType values.ResourceType  `json:"resource_type"`
+	ResourceID   uuid.UUID            `json:"resource_id"`
+	Name         string               `json:"name"`
+	LocationID   *uuid.UUID           `json:"location_id,omitempty"`
+	Available    bool                 `json:"available"`
+	Bookings     []BookingResponseDto `json:"bookings"`
+}
+
+type ConflictOverrideResponseDto struct {
+	ID               uuid.UUID             `json:"id"`
+	ResourceType     values.ResourceType   `json:"resource_type"`
+	ResourceID       uuid.UUID             `json:"resource_id"`
+	ActivityType     values.ActivityType   `json:"activity_type"`
+	ActivityID       *uuid.UUID            `json:"activity_id,omitempty"`
+	StartAt          time.Time             `json:"start_at"`
+	EndAt            time.Time             `json:"end_at"`
+	Conflicts        []ConflictResponseDto `json:"conflicts"`
+	Reason           string                `json:"reason"`
+	OverriddenBy     *uuid.UUID            `json:"overridden_by,omitempty"`
+	OverriddenByName string                `json:"overridden_by_name,omitempty"`
+	CreatedAt        time.Time             `json:"created_at"`
+}
+
+func newBookingResponse(b values.Booking) BookingResponseDto {
+	return BookingResponseDto{
+		ActivityType: b.ActivityType,
+		ActivityID:   b.ActivityID,
+		Title:        b.Title,
+		StartAt:      b.StartAt,
+		EndAt:        b.EndAt,
+	}
+}
+
+func NewConflictResponses(conflicts []values.Conflict) []ConflictResponseDto {
+	res := make([]ConflictResponseDto, len(conflicts))
+	for i, c := range conflicts {
+		res[i] = ConflictResponseDto{BookingResponseDto: newBookingResponse(c.Booking), Overridable: c.Overridable}
+	}
+	return res
+}
+
+func NewResourceAvailabilityResponse(a values.ResourceAvailability) ResourceAvailabilityResponseDto {
+	bookings := make([]BookingResponseDto, len(a.Bookings))
+	for i, b := range a.Bookings {
+		bookings[i] = newBookingResponse(b)
+	}
+	return ResourceAvailabilityResponseDto{
+		ResourceType: a.Type,
+		ResourceID:   a.ID,
+		Name:         a.Name,
+		LocationID:   a.LocationID,
+		Available:    a.Available,
+		Bookings:     bookings,
+	}
+}
+
+func NewConflictOverrideResponse(o values.ConflictOverride) ConflictOverrideResponseDto {
+	return ConflictOverrideResponseDto{
+		ID:               o.ID,
+		ResourceType:     o.ResourceType,
+		ResourceID:       o.ResourceID,
+		ActivityType:     o.ActivityType,
+		ActivityID:       o.ActivityID,
+		StartAt:          o.StartAt,
+		EndAt:            o.EndAt,
+		Conflicts:        NewConflictResponses(o.Conflicts),
+		Reason:           o.Reason,
+		OverriddenBy:     o.OverriddenBy,
+		OverriddenByName: o.OverriddenByName,
+		CreatedAt:        o.CreatedAt,
+	}
+}
+
+// ParseAvailabilityQuery reads resource_type, location_id, from and to (RFC 3339).
+func ParseAvailabilityQuery(query url.Values) (values.AvailabilityFilter, *errLib.CommonError) {
+	resourceType, from, to, err := parseWindow(query)
+	if err != nil {
+		return values.AvailabilityFilter{}, err
+	}
+
+	filter := values.AvailabilityFilter{ResourceType: resourceType, From: from, To: to}
+	if locationStr := query.Get("location_id"); locationStr != "" {
+		if filter.LocationID, err = validators.ParseUUID(locationStr); err != nil {
+			return values.AvailabilityFilter{}, err
+		}
+	}
+	return filter, nil
+}
+
+// ParseConflictQuery reads resource_type, resource_id, from, to and the optional
+// activity_type and activity_id of an activity being rescheduled.
+func ParseConflictQuery(query url.Values) (values.Request, *errLib.CommonError) {
+	resourceType, from, to, err := parseWindow(query)
+	if err != nil {
+		return values.Request{}, err
+	}
+
+	resourceID, err := validators.ParseUUID(query.Get("resource_id"))
+	if err != nil {
+		return values.Request{}, err
+	}
+
+	req := values.Request{
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		ActivityType: values.ActivityType(query.Get("activity_type")),
+		StartAt:      from,
+		EndAt:        to,
+	}
+	if activityStr := query.Get("activity_id"); activityStr != "" {
+		if req.ActivityID, err = validators.ParseUUID(activityStr); err != nil {
+			return values.Request{}, err
+		}
+	}
+	return req, nil
+}
+
+func parseWindow(query url.Values) (values.ResourceType, time.Time, time.Time, *errLib.CommonError) {
+	resourceType := values.ResourceType(query.Get("resource_type"))
+	if !resourceType.Valid() {
+		return "", time.Time{}, time.Time{}, errLib.New(fmt.Sprintf("Invalid resource_type. Expected one of: %s, %s, %s",
+			values.ResourceCourt, values.ResourcePlaygroundSystem, values.ResourceBarberChair), http.StatusBadRequest)
+	}
+
+	from, err := time.Parse(time.RFC3339, query.Get("from"))
+	if err != nil {
+		return "", time.Time{}, time.Time{}, errLib.New("Invalid from: must be an RFC 3339 timestamp", http.StatusBadRequest)
+	}
+	to, err := time.Parse(time.RFC3339, query.Get("to"))
+	if err != nil {
+		return "", time.Time{}, time.Time{}, errLib.New("Invalid to: must be an RFC 3339 timestamp", http.StatusBadRequest)
+	}
+	if !to.After(from) {
+		return "", time.Time{}, time.Time{}, errLib.New("to must be after from", http.StatusBadRequest)
+	}
+	if to.Sub(from) > MaxAvailabilityWindow {
+		return "", time.Time{}, time.Time{}, errLib.New("The requested window cannot exceed 31 days", http.StatusBadRequest)
+	}
+
+	return resourceType, from, to, nil
+}
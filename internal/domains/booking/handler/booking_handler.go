@@ -2,13 +2,19 @@ package booking
 
 import (
 	"api/internal/di"
+	bookingDto "api/internal/domains/booking/dto"
+	bookingService "api/internal/domains/booking/service"
+	courtRentalDto "api/internal/domains/court_rental/dto"
+	courtRentalService "api/internal/domains/court_rental/service"
 	hairDto "api/internal/domains/haircut/event/dto"
 	hairRepo "api/internal/domains/haircut/event/persistence"
 	playgroundDto "api/internal/domains/playground/dto/session"
 	playgroundService "api/internal/domains/playground/services"
 	responseHandlers "api/internal/libs/responses"
+	"api/internal/libs/validators"
 	contextUtils "api/utils/context"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,25 +22,30 @@ import (
 
 // Handler aggregates bookings from multiple domains.
 type Handler struct {
-	HaircutRepo       *hairRepo.Repository
-	PlaygroundService *playgroundService.Service
+	HaircutRepo        *hairRepo.Repository
+	PlaygroundService  *playgroundService.Service
+	BookingService     *bookingService.Service
+	CourtRentalService *courtRentalService.Service
 }
 
 // NewHandler creates a new Handler instance.
 func NewHandler(container *di.Container) *Handler {
 	return &Handler{
-		HaircutRepo:       hairRepo.NewEventsRepository(container),
-		PlaygroundService: playgroundService.NewService(container),
+		HaircutRepo:        hairRepo.NewEventsRepository(container),
+		PlaygroundService:  playgroundService.NewService(container),
+		BookingService:     bookingService.NewService(container),
+		CourtRentalService: courtRentalService.NewService(container),
 	}
 }
 
 // UpcomingBookingsResponse represents combined upcoming bookings.
 type UpcomingBookingsResponse struct {
-	Haircuts   []hairDto.EventResponseDto  `json:"haircuts"`
-	Playground []playgroundDto.ResponseDto `json:"playground"`
+	Haircuts     []hairDto.EventResponseDto         `json:"haircuts"`
+	Playground   []playgroundDto.ResponseDto        `json:"playground"`
+	CourtRentals []courtRentalDto.RentalResponseDto `json:"court_rentals"`
 }
 
-// GetMyUpcomingBookings returns upcoming haircut and playground bookings for the logged-in customer.
+// GetMyUpcomingBookings returns upcoming haircut, playground and court rental bookings for the logged-in customer.
 // @Tags bookings
 // @Produce json
 // @Security Bearer
@@ -74,9 +85,130 @@ func (h *Handler) GetMyUpcomingBookings(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
+	rentals, err := h.CourtRentalService.GetMyUpcomingRentals(r.Context(), customerID)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
 	resp := UpcomingBookingsResponse{
-		Haircuts:   haircutBookings,
-		Playground: playgroundBookings,
+		Haircuts:     haircutBookings,
+		Playground:   playgroundBookings,
+		CourtRentals: courtRentalDto.NewRentalResponses(rentals),
+	}
+	responseHandlers.RespondWithSuccess(w, resp, http.StatusOK)
+}
+
+// GetAvailability lists courts, playground systems or barber chairs with what occupies each one in a time window.
+// @Tags bookings
+// @Produce json
+// @Security Bearer
+// @Param resource_type query string true "court, playground_system or barber_chair"
+// @Param location_id query string false "Only courts at this location"
+// @Param from query string true "Window start (RFC 3339)"
+// @Param to query string true "Window end (RFC 3339), at most 31 days after from"
+// @Success 200 {array} bookingDto.ResourceAvailabilityResponseDto "Resources and their bookings"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid query"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /bookings/availability [get]
+func (h *Handler) GetAvailability(w http.ResponseWriter, r *http.Request) {
+	filter, err := bookingDto.ParseAvailabilityQuery(r.URL.Query())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	availability, err := h.BookingService.GetAvailability(r.Context(), filter)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	resp := make([]bookingDto.ResourceAvailabilityResponseDto, len(availability))
+	for i, a := range availability {
+		resp[i] = bookingDto.NewResourceAvailabilityResponse(a)
+	}
+	responseHandlers.RespondWithSuccess(w, resp, http.StatusOK)
+}
+
+// GetConflicts returns the bookings that would clash with booking a resource for a time window.
+// @Description Pass activity_type and activity_id when rescheduling so the activity does not clash with itself.
+// @Description Overridable conflicts can be booked over by an admin who supplies an override reason.
+// @Tags bookings
+// @Produce json
+// @Security Bearer
+// @Param resource_type query string true "court, playground_system or barber_chair"
+// @Param resource_id query string true "Resource ID"
+// @Param from query string true "Booking start (RFC 3339)"
+// @Param to query string true "Booking end (RFC 3339)"
+// @Param activity_type query string false "event, practice, game, playground_session or haircut"
+// @Param activity_id query string false "ID of the activity being rescheduled"
+// @Success 200 {array} bookingDto.ConflictResponseDto "Conflicting bookings (empty when free)"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid query"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /bookings/conflicts [get]
+func (h *Handler) GetConflicts(w http.ResponseWriter, r *http.Request) {
+	req, err := bookingDto.ParseConflictQuery(r.URL.Query())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	conflicts, err := h.BookingService.GetConflicts(r.Context(), req)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	responseHandlers.RespondWithSuccess(w, bookingDto.NewConflictResponses(conflicts), http.StatusOK)
+}
+
+// GetConflictOverrides lists the booking conflicts admins have overridden, newest first.
+// @Tags bookings
+// @Produce json
+// @Security Bearer
+// @Param resource_id query string false "Only overrides on this resource"
+// @Param limit query int false "Number of records (default 20, max 100)"
+// @Param offset query int false "Number of records to skip"
+// @Success 200 {array} bookingDto.ConflictOverrideResponseDto "Override audit trail"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid resource_id"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /bookings/overrides [get]
+func (h *Handler) GetConflictOverrides(w http.ResponseWriter, r *http.Request) {
+	resourceID := uuid.Nil
+	if idStr := r.URL.Query().Get("resource_id"); idStr != "" {
+		id, err := validators.ParseUUID(idStr)
+		if err != nil {
+			responseHandlers.RespondWithError(w, err)
+			return
+		}
+		resourceID = id
+	}
+
+	limit := 20
+	offset := 0
+
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsed, parseErr := strconv.Atoi(limitStr); parseErr == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if parsed, parseErr := strconv.Atoi(offsetStr); parseErr == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+
+	overrides, err := h.BookingService.GetConflictOverrides(r.Context(), resourceID, int32(limit), int32(offset))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	resp := make([]bookingDto.ConflictOverrideResponseDto, len(overrides))
+	for i, o := range overrides {
+		resp[i] = bookingDto.NewConflictOverrideResponse(o)
 	}
 	responseHandlers.RespondWithSuccess(w, resp, http.StatusOK)
 }
@@ -0,0 +1,255 @@
+package booking
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"api/internal/di"
+	db "api/internal/domains/booking/persistence/sqlc/generated"
+	values "api/internal/domains/booking/values"
+	errLib "api/internal/libs/errors"
+
+	"github.com/google/uuid"
+)
+
+// Repository reads bookings across the court, playground and haircut tables.
+type Repository struct {
+	Queries *db.Queries
+	Tx      *sql.Tx
+}
+
+func NewRepository(container *di.Container) *Repository {
+	return &Repository{Queries: container.Queries.BookingDb}
+}
+
+func (r *Repository) GetTx() *sql.Tx { return r.Tx }
+
+func (r *Repository) WithTx(tx *sql.Tx) *Repository {
+	return &Repository{Queries: r.Queries.WithTx(tx), Tx: tx}
+}
+
+func nullUUID(id uuid.UUID) uuid.NullUUID {
+	return uuid.NullUUID{UUID: id, Valid: id != uuid.Nil}
+}
+
+// ListResources returns the resources of a type. Courts can be narrowed to a location.
+func (r *Repository) ListResources(ctx context.Context, resourceType values.ResourceType, locationID uuid.UUID) ([]values.Resource, *errLib.CommonError) {
+	var resources []values.Resource
+
+	switch resourceType {
+	case values.ResourceCourt:
+		rows, err := r.Queries.ListCourts(ctx, nullUUID(locationID))
+		if err != nil {
+			log.Printf("Failed to list courts: %v", err)
+			return nil, errLib.New("Failed to list courts", http.StatusInternalServerError)
+		}
+		for _, row := range rows {
+			loc := row.LocationID
+			resources = append(resources, values.Resource{Type: resourceType, ID: row.ID, Name: row.Name, LocationID: &loc})
+		}
+	case values.ResourcePlaygroundSystem:
+		rows, err := r.Queries.ListPlaygroundSystems(ctx)
+		if err != nil {
+			log.Printf("Failed to list playground systems: %v", err)
+			return nil, errLib.New("Failed to list playground systems", http.StatusInternalServerError)
+		}
+		for _, row := range rows {
+			resources = append(resources, values.Resource{Type: resourceType, ID: row.ID, Name: row.Name})
+		}
+	case values.ResourceBarberChair:
+		rows, err := r.Queries.ListBarbers(ctx)
+		if err != nil {
+			log.Printf("Failed to list barbers: %v", err)
+			return nil, errLib.New("Failed to list barbers", http.StatusInternalServerError)
+		}
+		for _, row := range rows {
+			resources = append(resources, values.Resource{Type: resourceType, ID: row.ID, Name: row.Name})
+		}
+	default:
+		return nil, errLib.New("Unknown resource type", http.StatusBadRequest)
+	}
+
+	return resources, nil
+}
+
+// GetBookings returns the bookings overlapping [from, to) on one resource, or on every
+// resource of the type when resourceID is uuid.Nil. excludeID leaves out one activity.
+func (r *Repository) GetBookings(ctx context.Context, resourceType values.ResourceType, resourceID, locationID uuid.UUID, from, to time.Time, excludeID uuid.UUID) ([]values.Booking, *errLib.CommonError) {
+	var bookings []values.Booking
+
+	switch resourceType {
+	case values.ResourceCourt:
+		rows, err := r.Queries.GetCourtBookings(ctx, db.GetCourtBookingsParams{
+			CourtID:    nullUUID(resourceID),
+			LocationID: nullUUID(locationID),
+			From:       from,
+			To:         to,
+			ExcludeID:  excludeID,
+		})
+		if err != nil {
+			log.Printf("Failed to get court bookings: %v", err)
+			return nil, errLib.New("Failed to get court bookings", http.StatusInternalServerError)
+		}
+		for _, row := range rows {
+			bookings = append(bookings, mapRow(db.GetBarberBookingsRow(row)))
+		}
+	case values.ResourcePlaygroundSystem:
+		rows, err := r.Queries.GetPlaygroundBookings(ctx, db.GetPlaygroundBookingsParams{
+			SystemID:  nullUUID(resourceID),
+			From:      from,
+			To:        to,
+			ExcludeID: excludeID,
+		})
+		if err != nil {
+			log.Printf("Failed to get playground bookings: %v", err)
+			return nil, errLib.New("Failed to get playground bookings", http.StatusInternalServerError)
+		}
+		for _, row := range rows {
+			bookings = append(bookings, mapRow(db.GetBarberBookingsRow(row)))
+		}
+	case values.ResourceBarberChair:
+		rows, err := r.Queries.GetBarberBookings(ctx, db.GetBarberBookingsParams{
+			BarberID:  nullUUID(resourceID),
+			From:      from,
+			To:        to,
+			ExcludeID: excludeID,
+		})
+		if err != nil {
+			log.Printf("Failed to get barber bookings: %v", err)
+			return nil, errLib.New("Failed to get barber bookings", http.StatusInternalServerError)
+		}
+		for _, row := range rows {
+			bookings = append(bookings, mapRow(row))
+		}
+	default:
+		return nil, errLib.New("Unknown resource type", http.StatusBadRequest)
+	}
+
+	return bookings, nil
+}
+
+func mapRow(row db.GetBarberBookingsRow) values.Booking {
+	return values.Booking{
+		ActivityType: values.ActivityType(row.ActivityType),
+		ActivityID:   row.ActivityID,
+		ResourceID:   row.ResourceID,
+		Title:        row.Title,
+		StartAt:      row.StartAt,
+		EndAt:        row.EndAt,
+	}
+}
+
+// EnableCourtOverride lets the current transaction book a court over bookings of
+// other activity types. It only applies to the transaction it is called in.
+func (r *Repository) EnableCourtOverride(ctx context.Context) *errLib.CommonError {
+	if err := r.Queries.EnableCourtOverride(ctx); err != nil {
+		log.Printf("Failed to enable court override: %v", err)
+		return errLib.New("Failed to apply booking override", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// conflictRecord is the JSON shape of a conflict stored with an override.
+type conflictRecord struct {
+	ActivityType string    `json:"activity_type"`
+	ActivityID   uuid.UUID `json:"activity_id"`
+	Title        string    `json:"title"`
+	StartAt      time.Time `json:"start_at"`
+	EndAt        time.Time `json:"end_at"`
+}
+
+func (r *Repository) CreateConflictOverride(ctx context.Context, override values.ConflictOverride) *errLib.CommonError {
+	records := make([]conflictRecord, len(override.Conflicts))
+	for i, c := range override.Conflicts {
+		records[i] = conflictRecord{
+			ActivityType: string(c.ActivityType),
+			ActivityID:   c.ActivityID,
+			Title:        c.Title,
+			StartAt:      c.StartAt,
+			EndAt:        c.EndAt,
+		}
+	}
+	conflicts, err := json.Marshal(records)
+	if err != nil {
+		return errLib.New("Failed to record booking override", http.StatusInternalServerError)
+	}
+
+	params := db.CreateConflictOverrideParams{
+		ResourceType: string(override.ResourceType),
+		ResourceID:   override.ResourceID,
+		ActivityType: string(override.ActivityType),
+		StartsAt:     override.StartAt,
+		EndsAt:       override.EndAt,
+		Conflicts:    conflicts,
+		Reason:       override.Reason,
+	}
+	if override.ActivityID != nil {
+		params.ActivityID = uuid.NullUUID{UUID: *override.ActivityID, Valid: true}
+	}
+	if override.OverriddenBy != nil {
+		params.OverriddenBy = uuid.NullUUID{UUID: *override.OverriddenBy, Valid: true}
+	}
+
+	if _, err = r.Queries.CreateConflictOverride(ctx, params); err != nil {
+		log.Printf("Failed to record booking override: %v", err)
+		return errLib.New("Failed to record booking override", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (r *Repository) ListConflictOverrides(ctx context.Context, resourceID uuid.UUID, limit, offset int32) ([]values.ConflictOverride, *errLib.CommonError) {
+	rows, err := r.Queries.ListConflictOverrides(ctx, db.ListConflictOverridesParams{
+		ResourceID: nullUUID(resourceID),
+		Limit:      limit,
+		Offset:     offset,
+	})
+	if err != nil {
+		log.Printf("Failed to list booking overrides: %v", err)
+		return nil, errLib.New("Failed to list booking overrides", http.StatusInternalServerError)
+	}
+
+	overrides := make([]values.ConflictOverride, len(rows))
+	for i, row := range rows {
+		var records []conflictRecord
+		if err := json.Unmarshal(row.Conflicts, &records); err != nil {
+			log.Printf("Failed to decode conflicts of override %s: %v", row.ID, err)
+		}
+		conflicts := make([]values.Conflict, len(records))
+		for j, rec := range records {
+			conflicts[j] = values.Conflict{Booking: values.Booking{
+				ActivityType: values.ActivityType(rec.ActivityType),
+				ActivityID:   rec.ActivityID,
+				ResourceID:   row.ResourceID,
+				Title:        rec.Title,
+				StartAt:      rec.StartAt,
+				EndAt:        rec.EndAt,
+			}, Overridable: true}
+		}
+
+		overrides[i] = values.ConflictOverride{
+			ID:               row.ID,
+			ResourceType:     values.ResourceType(row.ResourceType),
+			ResourceID:       row.ResourceID,
+			ActivityType:     values.ActivityType(row.ActivityType),
+			StartAt:          row.StartsAt,
+			EndAt:            row.EndsAt,
+			Conflicts:        conflicts,
+			Reason:           row.Reason,
+			OverriddenByName: row.OverriddenByName,
+			CreatedAt:        row.CreatedAt,
+		}
+		if row.ActivityID.Valid {
+			id := row.ActivityID.UUID
+			overrides[i].ActivityID = &id
+		}
+		if row.OverriddenBy.Valid {
+			id := row.OverriddenBy.UUID
+			overrides[i].OverriddenBy = &id
+		}
+	}
+	return overrides, nil
+}
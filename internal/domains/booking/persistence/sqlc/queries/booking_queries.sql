@@ -0,0 +1,130 @@
+-- name: GetCourtBookings :many
+SELECT b.activity_type::text AS activity_type,
+       b.activity_id,
+       c.id                  AS resource_id,
+       b.title::text         AS title,
+       b.start_at,
+       b.end_at
+FROM (SELECT 'event'                  AS activity_type,
+             e.id                     AS activity_id,
+             e.court_id               AS resource_id,
+             COALESCE(p.name, 'Event') AS title,
+             e.start_at,
+             e.end_at
+      FROM events.events e
+               LEFT JOIN program.programs p ON p.id = e.program_id
+      WHERE e.court_id IS NOT NULL
+        AND e.is_cancelled = false
+      UNION ALL
+      SELECT 'practice',
+             pr.id,
+             pr.court_id,
+             'Practice: ' || t.name,
+             pr.start_time,
+             COALESCE(pr.end_time, pr.start_time + interval '2 hours')
+      FROM practice.practices pr
+               JOIN athletic.teams t ON t.id = pr.team_id
+      WHERE pr.court_id IS NOT NULL
+        AND (pr.status IS NULL OR pr.status != 'canceled')
+      UNION ALL
+      SELECT 'game',
+             g.id,
+             g.court_id,
+             ht.name || ' vs ' || at.name,
+             g.start_time,
+             COALESCE(g.end_time, g.start_time + interval '2 hours')
+      FROM game.games g
+               JOIN athletic.teams ht ON ht.id = g.home_team_id
+               JOIN athletic.teams at ON at.id = g.away_team_id
+      WHERE g.court_id IS NOT NULL
+        AND (g.status IS NULL OR g.status != 'canceled')
+      UNION ALL
+      SELECT 'court_rental',
+             r.id,
+             r.court_id,
+             'Court rental',
+             r.start_at,
+             r.end_at
+      FROM location.court_rentals r
+      WHERE r.status = 'confirmed'
+         OR (r.status = 'held' AND r.hold_expires_at > now())) b
+         JOIN location.courts c ON c.id = b.resource_id
+WHERE (sqlc.narg('court_id')::uuid IS NULL OR b.resource_id = sqlc.narg('court_id')::uuid)
+  AND (sqlc.narg('location_id')::uuid IS NULL OR c.location_id = sqlc.narg('location_id')::uuid)
+  AND tstzrange(b.start_at, b.end_at, '[)') && tstzrange(sqlc.arg('from')::timestamptz, sqlc.arg('to')::timestamptz, '[)')
+  AND b.activity_id <> sqlc.arg('exclude_id')::uuid
+ORDER BY b.start_at;
+
+-- name: ListCourts :many
+SELECT c.id, c.name, c.location_id
+FROM location.courts c
+WHERE (sqlc.narg('location_id')::uuid IS NULL OR c.location_id = sqlc.narg('location_id')::uuid)
+ORDER BY c.name;
+
+-- name: GetPlaygroundBookings :many
+SELECT 'playground_session'::text                       AS activity_type,
+       s.id                                             AS activity_id,
+       s.system_id                                      AS resource_id,
+       ('Playground: ' || u.first_name || ' ' || u.last_name)::text AS title,
+       s.start_time                                     AS start_at,
+       s.end_time                                       AS end_at
+FROM playground.sessions s
+         JOIN users.users u ON u.id = s.customer_id
+WHERE (sqlc.narg('system_id')::uuid IS NULL OR s.system_id = sqlc.narg('system_id')::uuid)
+  AND (s.status IN ('confirmed', 'in_progress') OR (s.status = 'held' AND s.hold_expires_at > now()))
+  AND tstzrange(s.start_time, s.end_time, '[)') && tstzrange(sqlc.arg('from')::timestamptz, sqlc.arg('to')::timestamptz, '[)')
+  AND s.id <> sqlc.arg('exclude_id')::uuid
+ORDER BY s.start_time;
+
+-- name: ListPlaygroundSystems :many
+SELECT id, name
+FROM playground.systems
+ORDER BY name;
+
+-- name: GetBarberBookings :many
+SELECT 'haircut'::text                        AS activity_type,
+       e.id                                   AS activity_id,
+       e.barber_id                            AS resource_id,
+       COALESCE(hs.name, 'Haircut')::text     AS title,
+       e.begin_date_time                      AS start_at,
+       e.end_date_time                        AS end_at
+FROM haircut.events e
+         LEFT JOIN haircut.haircut_services hs ON hs.id = e.service_type_id
+WHERE (sqlc.narg('barber_id')::uuid IS NULL OR e.barber_id = sqlc.narg('barber_id')::uuid)
+  AND tstzrange(e.begin_date_time, e.end_date_time, '[]') && tstzrange(sqlc.arg('from')::timestamptz, sqlc.arg('to')::timestamptz, '[]')
+  AND e.id <> sqlc.arg('exclude_id')::uuid
+ORDER BY e.begin_date_time;
+
+-- name: ListBarbers :many
+SELECT DISTINCT u.id, (u.first_name || ' ' || u.last_name)::text AS name
+FROM haircut.barber_services bs
+         JOIN users.users u ON u.id = bs.barber_id
+ORDER BY name;
+
+-- name: EnableCourtOverride :exec
+SELECT set_config('booking.court_override', 'on', true);
+
+-- name: CreateConflictOverride :one
+INSERT INTO booking.conflict_overrides (resource_type, resource_id, activity_type, activity_id, starts_at, ends_at,
+                                        conflicts, reason, overridden_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: ListConflictOverrides :many
+SELECT o.id,
+       o.resource_type,
+       o.resource_id,
+       o.activity_type,
+       o.activity_id,
+       o.starts_at,
+       o.ends_at,
+       o.conflicts,
+       o.reason,
+       o.overridden_by,
+       COALESCE(u.first_name || ' ' || u.last_name, '')::text AS overridden_by_name,
+       o.created_at
+FROM booking.conflict_overrides o
+         LEFT JOIN users.users u ON u.id = o.overridden_by
+WHERE (sqlc.narg('resource_id')::uuid IS NULL OR o.resource_id = sqlc.narg('resource_id')::uuid)
+ORDER BY o.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
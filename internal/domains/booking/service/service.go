@@ -0,0 +1,193 @@
+package booking
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"api/internal/di"
+	staffActivityLogs "api/internal/domains/audit/staff_activity_logs/service"
+	dto "api/internal/domains/booking/dto"
+	repo "api/internal/domains/booking/persistence"
+	values "api/internal/domains/booking/values"
+	errLib "api/internal/libs/errors"
+	contextUtils "api/utils/context"
+
+	"github.com/google/uuid"
+)
+
+// Service treats courts, playground systems and barber chairs as bookable resources.
+// Activity services call Reserve inside their write transaction so clashes come back
+// as a structured 409 instead of a database error.
+type Service struct {
+	repo                     *repo.Repository
+	staffActivityLogsService *staffActivityLogs.Service
+	db                       *sql.DB
+}
+
+func NewService(container *di.Container) *Service {
+	return &Service{
+		repo:                     repo.NewRepository(container),
+		staffActivityLogsService: staffActivityLogs.NewService(container),
+		db:                       container.DB,
+	}
+}
+
+// GetAvailability lists every resource of the requested type with the bookings
+// overlapping the window. A resource with no bookings is available.
+func (s *Service) GetAvailability(ctx context.Context, filter values.AvailabilityFilter) ([]values.ResourceAvailability, *errLib.CommonError) {
+	resources, err := s.repo.ListResources(ctx, filter.ResourceType, filter.LocationID)
+	if err != nil {
+		return nil, err
+	}
+
+	bookings, err := s.repo.GetBookings(ctx, filter.ResourceType, uuid.Nil, filter.LocationID, filter.From, filter.To, uuid.Nil)
+	if err != nil {
+		return nil, err
+	}
+
+	byResource := make(map[uuid.UUID][]values.Booking, len(resources))
+	for _, b := range bookings {
+		byResource[b.ResourceID] = append(byResource[b.ResourceID], b)
+	}
+
+	availability := make([]values.ResourceAvailability, len(resources))
+	for i, resource := range resources {
+		availability[i] = values.ResourceAvailability{
+			Resource:  resource,
+			Available: len(byResource[resource.ID]) == 0,
+			Bookings:  byResource[resource.ID],
+		}
+	}
+	return availability, nil
+}
+
+// GetConflicts returns the bookings that would clash with the request.
+func (s *Service) GetConflicts(ctx context.Context, req values.Request) ([]values.Conflict, *errLib.CommonError) {
+	return findConflicts(ctx, s.repo, req)
+}
+
+// Reserve checks a booking inside the caller's transaction, before the activity row is
+// written. Conflicts are returned as a 409 whose details name each clashing activity.
+// An admin can pass an OverrideReason to book over conflicts with other activity types
+// on a court; the override is recorded and logged, and the database trigger is told
+// to allow it for this transaction only.
+func (s *Service) Reserve(ctx context.Context, tx *sql.Tx, req values.Request) *errLib.CommonError {
+	if req.ResourceID == uuid.Nil {
+		return nil
+	}
+	if !req.EndAt.After(req.StartAt) {
+		return errLib.New("Booking end time must be after start time", http.StatusBadRequest)
+	}
+
+	txRepo := s.repo.WithTx(tx)
+
+	conflicts, err := findConflicts(ctx, txRepo, req)
+	if err != nil {
+		return err
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	if req.OverrideReason == "" {
+		return conflictError(conflicts)
+	}
+
+	for _, c := range conflicts {
+		if !c.Overridable {
+			return errLib.NewWithDetails(
+				fmt.Sprintf("%s cannot be overridden: it is the same kind of booking on the same resource", c.Title),
+				http.StatusConflict,
+				dto.NewConflictResponses(conflicts),
+			)
+		}
+	}
+
+	role, err := contextUtils.GetUserRole(ctx)
+	if err != nil {
+		return err
+	}
+	if role != contextUtils.RoleAdmin && role != contextUtils.RoleSuperAdmin {
+		return errLib.New("Only admins can override booking conflicts", http.StatusForbidden)
+	}
+
+	staffID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	override := values.ConflictOverride{
+		ResourceType: req.ResourceType,
+		ResourceID:   req.ResourceID,
+		ActivityType: req.ActivityType,
+		StartAt:      req.StartAt,
+		EndAt:        req.EndAt,
+		Conflicts:    conflicts,
+		Reason:       req.OverrideReason,
+		OverriddenBy: &staffID,
+	}
+	if req.ActivityID != uuid.Nil {
+		override.ActivityID = &req.ActivityID
+	}
+
+	if err = txRepo.CreateConflictOverride(ctx, override); err != nil {
+		return err
+	}
+	if err = txRepo.EnableCourtOverride(ctx); err != nil {
+		return err
+	}
+
+	return s.staffActivityLogsService.InsertStaffActivity(ctx, tx, staffID,
+		fmt.Sprintf("Overrode %s booking conflict with %s: %s", req.ResourceType, describe(conflicts), req.OverrideReason))
+}
+
+// GetConflictOverrides lists recorded overrides, newest first.
+func (s *Service) GetConflictOverrides(ctx context.Context, resourceID uuid.UUID, limit, offset int32) ([]values.ConflictOverride, *errLib.CommonError) {
+	return s.repo.ListConflictOverrides(ctx, resourceID, limit, offset)
+}
+
+func findConflicts(ctx context.Context, r *repo.Repository, req values.Request) ([]values.Conflict, *errLib.CommonError) {
+	bookings, err := r.GetBookings(ctx, req.ResourceType, req.ResourceID, uuid.Nil, req.StartAt, req.EndAt, req.ActivityID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toConflicts(req, bookings), nil
+}
+
+// toConflicts marks which bookings an admin may override: only clashes between
+// different activity types on a court. Same-type clashes and every playground or
+// barber clash are blocked by exclusion constraints, and rentals have been paid for.
+func toConflicts(req values.Request, bookings []values.Booking) []values.Conflict {
+	conflicts := make([]values.Conflict, len(bookings))
+	for i, b := range bookings {
+		conflicts[i] = values.Conflict{
+			Booking: b,
+			Overridable: req.ResourceType == values.ResourceCourt &&
+				b.ActivityType != req.ActivityType &&
+				b.ActivityType != values.ActivityCourtRental &&
+				req.ActivityType != values.ActivityCourtRental,
+		}
+	}
+	return conflicts
+}
+
+func conflictError(conflicts []values.Conflict) *errLib.CommonError {
+	return errLib.NewWithDetails(
+		fmt.Sprintf("This resource is already booked by %s", describe(conflicts)),
+		http.StatusConflict,
+		dto.NewConflictResponses(conflicts),
+	)
+}
+
+func describe(conflicts []values.Conflict) string {
+	names := make([]string, len(conflicts))
+	for i, c := range conflicts {
+		names[i] = fmt.Sprintf("%s %q (%s - %s)", c.ActivityType, c.Title,
+			c.StartAt.UTC().Format("Jan 2 15:04"), c.EndAt.UTC().Format("15:04 MST"))
+	}
+	return strings.Join(names, ", ")
+}
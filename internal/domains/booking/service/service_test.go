@@ -0,0 +1,72 @@
+package booking
+
+import (
+	"testing"
+	"time"
+
+	values "api/internal/domains/booking/values"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToConflicts(t *testing.T) {
+	start := time.Date(2026, 3, 2, 18, 0, 0, 0, time.UTC)
+	bookings := []values.Booking{
+		{ActivityType: values.ActivityEvent, ActivityID: uuid.New(), Title: "Open Gym", StartAt: start, EndAt: start.Add(time.Hour)},
+		{ActivityType: values.ActivityPractice, ActivityID: uuid.New(), Title: "U14 Practice", StartAt: start, EndAt: start.Add(2 * time.Hour)},
+	}
+
+	t.Run("Court clashes with other activity types are overridable", func(t *testing.T) {
+		req := values.Request{ResourceType: values.ResourceCourt, ActivityType: values.ActivityGame}
+
+		conflicts := toConflicts(req, bookings)
+
+		assert.Len(t, conflicts, 2)
+		assert.True(t, conflicts[0].Overridable)
+		assert.True(t, conflicts[1].Overridable)
+	})
+
+	t.Run("Court clashes with the same activity type are not", func(t *testing.T) {
+		req := values.Request{ResourceType: values.ResourceCourt, ActivityType: values.ActivityPractice}
+
+		conflicts := toConflicts(req, bookings)
+
+		assert.True(t, conflicts[0].Overridable)
+		assert.False(t, conflicts[1].Overridable)
+	})
+
+	t.Run("Barber clashes are never overridable", func(t *testing.T) {
+		req := values.Request{ResourceType: values.ResourceBarberChair, ActivityType: values.ActivityHaircut}
+		haircut := []values.Booking{{ActivityType: values.ActivityHaircut, Title: "Haircut", StartAt: start, EndAt: start.Add(30 * time.Minute)}}
+
+		conflicts := toConflicts(req, haircut)
+
+		assert.False(t, conflicts[0].Overridable)
+	})
+}
+
+func TestConflictError(t *testing.T) {
+	start := time.Date(2026, 3, 2, 18, 0, 0, 0, time.UTC)
+	conflicts := []values.Conflict{{Booking: values.Booking{
+		ActivityType: values.ActivityEvent, Title: "Open Gym", StartAt: start, EndAt: start.Add(time.Hour),
+	}}}
+
+	err := conflictError(conflicts)
+
+	assert.Equal(t, 409, err.HTTPCode)
+	assert.Contains(t, err.Message, `event "Open Gym" (Mar 2 18:00 - 19:00 UTC)`)
+	assert.NotNil(t, err.Details)
+}
+
+func TestToConflictsNeverOverridesRentals(t *testing.T) {
+	start := time.Date(2026, 3, 2, 18, 0, 0, 0, time.UTC)
+	rental := []values.Booking{{ActivityType: values.ActivityCourtRental, Title: "Court rental", StartAt: start, EndAt: start.Add(time.Hour)}}
+	event := []values.Booking{{ActivityType: values.ActivityEvent, Title: "Open Gym", StartAt: start, EndAt: start.Add(time.Hour)}}
+
+	conflicts := toConflicts(values.Request{ResourceType: values.ResourceCourt, ActivityType: values.ActivityPractice}, rental)
+	assert.False(t, conflicts[0].Overridable)
+
+	conflicts = toConflicts(values.Request{ResourceType: values.ResourceCourt, ActivityType: values.ActivityCourtRental}, event)
+	assert.False(t, conflicts[0].Overridable)
+}
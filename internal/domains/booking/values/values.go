@@ -0,0 +1,107 @@
+package values
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ResourceType identifies a kind of bookable facility resource.
+type ResourceType string
+
+const (
+	ResourceCourt            ResourceType = "court"
+	ResourcePlaygroundSystem ResourceType = "playground_system"
+	ResourceBarberChair      ResourceType = "barber_chair"
+)
+
+func (t ResourceType) Valid() bool {
+	switch t {
+	case ResourceCourt, ResourcePlaygroundSystem, ResourceBarberChair:
+		return true
+	}
+	return false
+}
+
+// ActivityType identifies what occupies a resource.
+type ActivityType string
+
+const (
+	ActivityEvent             ActivityType = "event"
+	ActivityPractice          ActivityType = "practice"
+	ActivityGame              ActivityType = "game"
+	ActivityPlaygroundSession ActivityType = "playground_session"
+	ActivityHaircut           ActivityType = "haircut"
+	ActivityCourtRental       ActivityType = "court_rental"
+)
+
+// Resource is a single bookable court, playground system or barber chair.
+type Resource struct {
+	Type       ResourceType
+	ID         uuid.UUID
+	Name       string
+	LocationID *uuid.UUID
+}
+
+// Booking is an activity occupying a resource for a time range.
+type Booking struct {
+	ActivityType ActivityType
+	ActivityID   uuid.UUID
+	ResourceID   uuid.UUID
+	Title        string
+	StartAt      time.Time
+	EndAt        time.Time
+}
+
+// Conflict is an existing booking that clashes with a requested one. Only clashes
+// between different activity types on a court can be overridden; double bookings
+// within one activity type are rejected by the database, and a customer's court
+// rental is never overridden.
+type Conflict struct {
+	Booking
+	Overridable bool
+}
+
+// AvailabilityFilter selects the resources to report on and the window to check.
+type AvailabilityFilter struct {
+	ResourceType ResourceType
+	LocationID   uuid.UUID
+	From         time.Time
+	To           time.Time
+}
+
+// ResourceAvailability lists what occupies a resource during the requested window.
+type ResourceAvailability struct {
+	Resource
+	Available bool
+	Bookings  []Booking
+}
+
+// Request describes a booking to check against a resource. ActivityID is the
+// activity being rescheduled, if any, so it does not conflict with itself.
+type Request struct {
+	ResourceType ResourceType
+	ResourceID   uuid.UUID
+	ActivityType ActivityType
+	ActivityID   uuid.UUID
+	StartAt      time.Time
+	EndAt        time.Time
+	// OverrideReason, when set by an admin, books over overridable conflicts.
+	OverrideReason string
+}
+
+// ConflictOverride is an audit record of an admin booking over conflicts.
+type ConflictOverride struct {
+	ID               uuid.UUID
+	ResourceType     ResourceType
+	ResourceID       uuid.UUID
+	ActivityType     ActivityType
+	ActivityID       *uuid.UUID
+	StartAt          time.Time
+	EndAt            time.Time
+	Conflicts        []Conflict
+	Reason           string
+	OverriddenBy     *uuid.UUID
+	OverriddenByName string
+	CreatedAt        time.Time
+}
@@ -0,0 +1,139 @@
+package court_rental
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	values "api/internal/domains/court_rental/values"
+	errLib "api/internal/libs/errors"
+	"api/internal/libs/validators"
+
+	"github.com/google/uuid"
+)
+
+// RateRequestDto creates or updates an hourly rate band of a court.
+type RateRequestDto struct {
+	CourtID            uuid.UUID `json:"court_id" example:"f0e21457-75d4-4de6-b765-5ee13221fd72"`
+	Name               string    `json:"name" validate:"required,notwhitespace,max=50" example:"Peak"`
+	DayOfWeek          *int      `json:"day_of_week" validate:"required,min=0,max=6" example:"5"` // 0=Sunday, 6=Saturday
+	StartTime          string    `json:"start_time" validate:"required" example:"17:00"`          // HH:MM facility time
+	EndTime            string    `json:"end_time" validate:"required" example:"22:00"`            // HH:MM facility time
+	MemberRateCents    int32     `json:"member_rate_cents" validate:"min=0" example:"5000"`
+	NonMemberRateCents int32     `json:"non_member_rate_cents" validate:"min=0" example:"6000"`
+	CreditsPerHour     *int32    `json:"credits_per_hour,omitempty" validate:"omitempty,gt=0" example:"2"`
+	IsActive           *bool     `json:"is_active,omitempty" example:"true"` // Optional, defaults to true
+}
+
+func (dto *RateRequestDto) toDetails() (values.RateDetails, *errLib.CommonError) {
+	if err := validators.ValidateDto(dto); err != nil {
+		return values.RateDetails{}, err
+	}
+
+	startTime, err := time.Parse("15:04", dto.StartTime)
+	if err != nil {
+		return values.RateDetails{}, errLib.New("invalid start_time format, expected HH:MM", http.StatusBadRequest)
+	}
+	endTime, err := time.Parse("15:04", dto.EndTime)
+	if err != nil {
+		return values.RateDetails{}, errLib.New("invalid end_time format, expected HH:MM", http.StatusBadRequest)
+	}
+
+	isActive := true
+	if dto.IsActive != nil {
+		isActive = *dto.IsActive
+	}
+
+	return values.RateDetails{
+		Name:               dto.Name,
+		DayOfWeek:          time.Weekday(*dto.DayOfWeek),
+		StartTime:          startTime,
+		EndTime:            endTime,
+		MemberRateCents:    dto.MemberRateCents,
+		NonMemberRateCents: dto.NonMemberRateCents,
+		CreditsPerHour:     dto.CreditsPerHour,
+		IsActive:           isActive,
+	}, nil
+}
+
+func (dto *RateRequestDto) ToCreateValues() (values.CreateRateValues, *errLib.CommonError) {
+	if dto.CourtID == uuid.Nil {
+		return values.CreateRateValues{}, errLib.New("court_id is required", http.StatusBadRequest)
+	}
+	details, err := dto.toDetails()
+	if err != nil {
+		return values.CreateRateValues{}, err
+	}
+	return values.CreateRateValues{CourtID: dto.CourtID, RateDetails: details}, nil
+}
+
+// ToUpdateValues converts the request for the rate with the given id. court_id is ignored;
+// a rate cannot move to another court.
+func (dto *RateRequestDto) ToUpdateValues(idStr string) (values.UpdateRateValues, *errLib.CommonError) {
+	id, err := validators.ParseUUID(idStr)
+	if err != nil {
+		return values.UpdateRateValues{}, err
+	}
+	details, err := dto.toDetails()
+	if err != nil {
+		return values.UpdateRateValues{}, err
+	}
+	return values.UpdateRateValues{ID: id, RateDetails: details}, nil
+}
+
+// RentalRequestDto asks to rent a court and pay by card or credits.
+type RentalRequestDto struct {
+	CourtID       uuid.UUID `json:"court_id" example:"f0e21457-75d4-4de6-b765-5ee13221fd72"`
+	StartAt       time.Time `json:"start_at" validate:"required" example:"2026-03-06T18:00:00-05:00"`
+	EndAt         time.Time `json:"end_at" validate:"required" example:"2026-03-06T19:30:00-05:00"`
+	PaymentMethod string    `json:"payment_method" validate:"required,oneof=stripe credits" example:"stripe"`
+}
+
+func (dto *RentalRequestDto) ToHoldRequest(customerID uuid.UUID) (values.HoldRequest, *errLib.CommonError) {
+	if err := validators.ValidateDto(dto); err != nil {
+		return values.HoldRequest{}, err
+	}
+	if dto.CourtID == uuid.Nil {
+		return values.HoldRequest{}, errLib.New("court_id is required", http.StatusBadRequest)
+	}
+	return values.HoldRequest{
+		CourtID:       dto.CourtID,
+		CustomerID:    customerID,
+		StartAt:       dto.StartAt,
+		EndAt:         dto.EndAt,
+		PaymentMethod: values.PaymentMethod(dto.PaymentMethod),
+	}, nil
+}
+
+// ParseSlotQuery reads date (YYYY-MM-DD, facility calendar day), duration in minutes
+// (default 60) and the optional location_id and court_id.
+func ParseSlotQuery(query url.Values) (values.SlotFilter, *errLib.CommonError) {
+	date, err := time.Parse("2006-01-02", query.Get("date"))
+	if err != nil {
+		return values.SlotFilter{}, errLib.New("Invalid date: expected YYYY-MM-DD", http.StatusBadRequest)
+	}
+
+	filter := values.SlotFilter{Date: date, Duration: time.Hour}
+	if durationStr := query.Get("duration"); durationStr != "" {
+		minutes, convErr := strconv.Atoi(durationStr)
+		if convErr != nil || minutes <= 0 {
+			return values.SlotFilter{}, errLib.New(fmt.Sprintf("Invalid duration %q: expected minutes", durationStr), http.StatusBadRequest)
+		}
+		filter.Duration = time.Duration(minutes) * time.Minute
+	}
+
+	var parseErr *errLib.CommonError
+	if locationStr := query.Get("location_id"); locationStr != "" {
+		if filter.LocationID, parseErr = validators.ParseUUID(locationStr); parseErr != nil {
+			return values.SlotFilter{}, parseErr
+		}
+	}
+	if courtStr := query.Get("court_id"); courtStr != "" {
+		if filter.CourtID, parseErr = validators.ParseUUID(courtStr); parseErr != nil {
+			return values.SlotFilter{}, parseErr
+		}
+	}
+	return filter, nil
+}
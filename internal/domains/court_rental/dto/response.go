@@ -0,0 +1,121 @@
+package court_rental
+
+import (
+	"time"
+
+	values "api/internal/domains/court_rental/values"
+
+	"github.com/google/uuid"
+)
+
+type RateResponseDto struct {
+	ID                 uuid.UUID `json:"id"`
+	CourtID            uuid.UUID `json:"court_id"`
+	Name               string    `json:"name"`
+	DayOfWeek          int       `json:"day_of_week"`
+	StartTime          string    `json:"start_time"`
+	EndTime            string    `json:"end_time"`
+	MemberRateCents    int32     `json:"member_rate_cents"`
+	NonMemberRateCents int32     `json:"non_member_rate_cents"`
+	CreditsPerHour     *int32    `json:"credits_per_hour,omitempty"`
+	IsActive           bool      `json:"is_active"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+type SlotResponseDto struct {
+	CourtID     uuid.UUID `json:"court_id"`
+	CourtName   string    `json:"court_name"`
+	StartAt     time.Time `json:"start_at"`
+	EndAt       time.Time `json:"end_at"`
+	AmountCents int32     `json:"amount_cents"`
+	Credits     *int32    `json:"credits,omitempty"`
+	Bands       []string  `json:"bands"`
+}
+
+type RentalResponseDto struct {
+	ID            uuid.UUID  `json:"id"`
+	CourtID       uuid.UUID  `json:"court_id"`
+	CourtName     string     `json:"court_name"`
+	LocationID    uuid.UUID  `json:"location_id"`
+	LocationName  string     `json:"location_name"`
+	CustomerID    uuid.UUID  `json:"customer_id"`
+	CustomerName  string     `json:"customer_name"`
+	StartAt       time.Time  `json:"start_at"`
+	EndAt         time.Time  `json:"end_at"`
+	Status        string     `json:"status"`
+	PaymentMethod string     `json:"payment_method"`
+	IsMember      bool       `json:"is_member"`
+	AmountCents   int32      `json:"amount_cents"`
+	CreditsUsed   int32      `json:"credits_used"`
+	Currency      string     `json:"currency"`
+	HoldExpiresAt *time.Time `json:"hold_expires_at,omitempty"`
+	PaidAt        *time.Time `json:"paid_at,omitempty"`
+	CanceledAt    *time.Time `json:"canceled_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+func NewRateResponse(v values.Rate) RateResponseDto {
+	return RateResponseDto{
+		ID:                 v.ID,
+		CourtID:            v.CourtID,
+		Name:               v.Name,
+		DayOfWeek:          int(v.DayOfWeek),
+		StartTime:          v.StartTime.Format("15:04"),
+		EndTime:            v.EndTime.Format("15:04"),
+		MemberRateCents:    v.MemberRateCents,
+		NonMemberRateCents: v.NonMemberRateCents,
+		CreditsPerHour:     v.CreditsPerHour,
+		IsActive:           v.IsActive,
+		CreatedAt:          v.CreatedAt,
+		UpdatedAt:          v.UpdatedAt,
+	}
+}
+
+func NewSlotResponse(v values.Slot) SlotResponseDto {
+	return SlotResponseDto{
+		CourtID:     v.CourtID,
+		CourtName:   v.CourtName,
+		StartAt:     v.StartAt,
+		EndAt:       v.EndAt,
+		AmountCents: v.AmountCents,
+		Credits:     v.Credits,
+		Bands:       v.Bands,
+	}
+}
+
+func NewRentalResponse(v values.Rental) RentalResponseDto {
+	res := RentalResponseDto{
+		ID:            v.ID,
+		CourtID:       v.CourtID,
+		CourtName:     v.CourtName,
+		LocationID:    v.LocationID,
+		LocationName:  v.LocationName,
+		CustomerID:    v.CustomerID,
+		CustomerName:  v.CustomerName,
+		StartAt:       v.StartAt,
+		EndAt:         v.EndAt,
+		Status:        v.Status,
+		PaymentMethod: string(v.PaymentMethod),
+		IsMember:      v.IsMember,
+		AmountCents:   v.AmountCents,
+		CreditsUsed:   v.CreditsUsed,
+		Currency:      v.Currency,
+		PaidAt:        v.PaidAt,
+		CanceledAt:    v.CanceledAt,
+		CreatedAt:     v.CreatedAt,
+	}
+	// Only an unpaid hold has an expiry the customer needs to know about
+	if v.Status == values.StatusHeld {
+		res.HoldExpiresAt = &v.HoldExpiresAt
+	}
+	return res
+}
+
+func NewRentalResponses(rentals []values.Rental) []RentalResponseDto {
+	res := make([]RentalResponseDto, len(rentals))
+	for i, v := range rentals {
+		res[i] = NewRentalResponse(v)
+	}
+	return res
+}
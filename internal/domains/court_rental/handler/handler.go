@@ -0,0 +1,294 @@
+package court_rental
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"api/internal/di"
+	dto "api/internal/domains/court_rental/dto"
+	service "api/internal/domains/court_rental/service"
+	values "api/internal/domains/court_rental/values"
+	responseHandlers "api/internal/libs/responses"
+	"api/internal/libs/validators"
+	contextUtils "api/utils/context"
+
+	"github.com/go-chi/chi"
+)
+
+type Handler struct {
+	Service *service.Service
+}
+
+func NewHandler(container *di.Container) *Handler {
+	return &Handler{Service: service.NewService(container)}
+}
+
+// GetSlots lists the free start times on rentable courts for a day, priced for the caller.
+// @Description Members are quoted member rates. credits is omitted when the slot cannot be paid for with credits.
+// @Tags court-rentals
+// @Produce json
+// @Security Bearer
+// @Param date query string true "Facility calendar day (YYYY-MM-DD)"
+// @Param duration query int false "Rental length in minutes, a multiple of 30 up to 240 (default 60)"
+// @Param location_id query string false "Only courts at this location"
+// @Param court_id query string false "Only this court"
+// @Success 200 {array} dto.SlotResponseDto "Free slots"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid query"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /court-rentals/slots [get]
+func (h *Handler) GetSlots(w http.ResponseWriter, r *http.Request) {
+	filter, err := dto.ParseSlotQuery(r.URL.Query())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	customerID, err := contextUtils.GetUserID(r.Context())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	if filter.IsMember, err = h.Service.IsMember(r.Context(), customerID); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	slots, err := h.Service.GetSlots(r.Context(), filter)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	resp := make([]dto.SlotResponseDto, len(slots))
+	for i, s := range slots {
+		resp[i] = dto.NewSlotResponse(s)
+	}
+	responseHandlers.RespondWithSuccess(w, resp, http.StatusOK)
+}
+
+// GetMyRentals lists the logged-in customer's upcoming court rentals, including unpaid holds.
+// @Tags court-rentals
+// @Produce json
+// @Security Bearer
+// @Success 200 {array} dto.RentalResponseDto "Upcoming rentals"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /court-rentals/me [get]
+func (h *Handler) GetMyRentals(w http.ResponseWriter, r *http.Request) {
+	customerID, err := contextUtils.GetUserID(r.Context())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	rentals, err := h.Service.GetMyUpcomingRentals(r.Context(), customerID)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewRentalResponses(rentals), http.StatusOK)
+}
+
+// GetRentals lists court rentals for staff.
+// @Tags court-rentals
+// @Produce json
+// @Security Bearer
+// @Param court_id query string false "Only rentals of this court"
+// @Param customer_id query string false "Only rentals by this customer"
+// @Param status query string false "held, confirmed, canceled, expired, refund_due or refunded"
+// @Param after query string false "Only rentals ending after this time (RFC 3339)"
+// @Param limit query int false "Number of records (default 20, max 100)"
+// @Param offset query int false "Number of records to skip"
+// @Success 200 {array} dto.RentalResponseDto "Rentals"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid query"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /court-rentals [get]
+func (h *Handler) GetRentals(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := values.ListFilter{Status: query.Get("status"), Limit: 20}
+
+	if courtStr := query.Get("court_id"); courtStr != "" {
+		id, err := validators.ParseUUID(courtStr)
+		if err != nil {
+			responseHandlers.RespondWithError(w, err)
+			return
+		}
+		filter.CourtID = id
+	}
+	if customerStr := query.Get("customer_id"); customerStr != "" {
+		id, err := validators.ParseUUID(customerStr)
+		if err != nil {
+			responseHandlers.RespondWithError(w, err)
+			return
+		}
+		filter.CustomerID = id
+	}
+	if afterStr := query.Get("after"); afterStr != "" {
+		if after, parseErr := time.Parse(time.RFC3339, afterStr); parseErr == nil {
+			filter.After = after
+		}
+	}
+	if limitStr := query.Get("limit"); limitStr != "" {
+		if parsed, parseErr := strconv.Atoi(limitStr); parseErr == nil && parsed > 0 && parsed <= 100 {
+			filter.Limit = int32(parsed)
+		}
+	}
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		if parsed, parseErr := strconv.Atoi(offsetStr); parseErr == nil && parsed >= 0 {
+			filter.Offset = int32(parsed)
+		}
+	}
+
+	rentals, err := h.Service.GetRentals(r.Context(), filter)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewRentalResponses(rentals), http.StatusOK)
+}
+
+// CancelRental cancels a court rental.
+// @Description Customers can cancel their own unpaid hold, or a rental paid with credits before it starts; the credits are refunded.
+// @Description Staff can cancel any rental.
+// @Tags court-rentals
+// @Security Bearer
+// @Param id path string true "Rental ID"
+// @Success 204 "No Content: Rental canceled"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Rental has started"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Card-paid rentals are canceled by staff"
+// @Failure 404 {object} map[string]interface{} "Not Found: Rental not found"
+// @Failure 409 {object} map[string]interface{} "Conflict: Rental already canceled or expired"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /court-rentals/{id}/cancel [post]
+func (h *Handler) CancelRental(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	if err = h.Service.CancelRental(r.Context(), id); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, nil, http.StatusNoContent)
+}
+
+// GetRates lists the rate bands of a court.
+// @Tags court-rentals
+// @Produce json
+// @Security Bearer
+// @Param court_id query string true "Court ID"
+// @Success 200 {array} dto.RateResponseDto "Rate bands"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid court_id"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /court-rentals/rates [get]
+func (h *Handler) GetRates(w http.ResponseWriter, r *http.Request) {
+	courtID, err := validators.ParseUUID(r.URL.Query().Get("court_id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	rates, err := h.Service.GetRates(r.Context(), courtID)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	resp := make([]dto.RateResponseDto, len(rates))
+	for i, rate := range rates {
+		resp[i] = dto.NewRateResponse(rate)
+	}
+	responseHandlers.RespondWithSuccess(w, resp, http.StatusOK)
+}
+
+// CreateRate adds an hourly rate band to a court.
+// @Description Times are facility wall-clock HH:MM. Bands on the same day cannot overlap or cross midnight.
+// @Tags court-rentals
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param rate body dto.RateRequestDto true "Rate band"
+// @Success 201 {object} dto.RateResponseDto "Rate created"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid input"
+// @Failure 404 {object} map[string]interface{} "Not Found: Court not found"
+// @Failure 409 {object} map[string]interface{} "Conflict: Overlaps another band"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /court-rentals/rates [post]
+func (h *Handler) CreateRate(w http.ResponseWriter, r *http.Request) {
+	var req dto.RateRequestDto
+	if err := validators.ParseJSON(r.Body, &req); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	v, err := req.ToCreateValues()
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	rate, err := h.Service.CreateRate(r.Context(), v)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewRateResponse(rate), http.StatusCreated)
+}
+
+// UpdateRate replaces a rate band. Existing rentals keep the price they were booked at.
+// @Tags court-rentals
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Rate ID"
+// @Param rate body dto.RateRequestDto true "Rate band"
+// @Success 200 {object} dto.RateResponseDto "Rate updated"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid input"
+// @Failure 404 {object} map[string]interface{} "Not Found: Rate not found"
+// @Failure 409 {object} map[string]interface{} "Conflict: Overlaps another band"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /court-rentals/rates/{id} [put]
+func (h *Handler) UpdateRate(w http.ResponseWriter, r *http.Request) {
+	var req dto.RateRequestDto
+	if err := validators.ParseJSON(r.Body, &req); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	v, err := req.ToUpdateValues(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	rate, err := h.Service.UpdateRate(r.Context(), v)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewRateResponse(rate), http.StatusOK)
+}
+
+// DeleteRate removes a rate band.
+// @Tags court-rentals
+// @Security Bearer
+// @Param id path string true "Rate ID"
+// @Success 204 "No Content: Rate deleted"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid ID"
+// @Failure 404 {object} map[string]interface{} "Not Found: Rate not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /court-rentals/rates/{id} [delete]
+func (h *Handler) DeleteRate(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	if err = h.Service.DeleteRate(r.Context(), id); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, nil, http.StatusNoContent)
+}
@@ -0,0 +1,385 @@
+package court_rental
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	databaseErrors "api/internal/constants"
+	"api/internal/di"
+	db "api/internal/domains/court_rental/persistence/sqlc/generated"
+	values "api/internal/domains/court_rental/values"
+	errLib "api/internal/libs/errors"
+	"api/utils/timezone"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type Repository struct {
+	Queries *db.Queries
+	Tx      *sql.Tx
+}
+
+func NewRepository(container *di.Container) *Repository {
+	return &Repository{Queries: container.Queries.CourtRentalDb}
+}
+
+func (r *Repository) GetTx() *sql.Tx { return r.Tx }
+
+func (r *Repository) WithTx(tx *sql.Tx) *Repository {
+	return &Repository{Queries: r.Queries.WithTx(tx), Tx: tx}
+}
+
+func nullUUID(id uuid.UUID) uuid.NullUUID {
+	return uuid.NullUUID{UUID: id, Valid: id != uuid.Nil}
+}
+
+func nullInt32(v *int32) sql.NullInt32 {
+	if v == nil {
+		return sql.NullInt32{}
+	}
+	return sql.NullInt32{Int32: *v, Valid: true}
+}
+
+func mapRate(row db.LocationCourtRentalRate) values.Rate {
+	rate := values.Rate{
+		ID:        row.ID,
+		CourtID:   row.CourtID,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+		RateDetails: values.RateDetails{
+			Name:               row.Name,
+			DayOfWeek:          time.Weekday(row.DayOfWeek),
+			StartTime:          row.StartTime,
+			EndTime:            row.EndTime,
+			MemberRateCents:    row.MemberRateCents,
+			NonMemberRateCents: row.NonMemberRateCents,
+			IsActive:           row.IsActive,
+		},
+	}
+	if row.CreditsPerHour.Valid {
+		credits := row.CreditsPerHour.Int32
+		rate.CreditsPerHour = &credits
+	}
+	return rate
+}
+
+func rateError(err error, action string) *errLib.CommonError {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case databaseErrors.ForeignKeyViolation:
+			return errLib.New("Court not found", http.StatusNotFound)
+		case databaseErrors.CheckViolation:
+			return errLib.New("Invalid rental rate: end_time must be after start_time and rates cannot be negative", http.StatusBadRequest)
+		}
+	}
+	log.Printf("Failed to %s rental rate: %v", action, err)
+	return errLib.New("Failed to "+action+" rental rate", http.StatusInternalServerError)
+}
+
+func (r *Repository) CreateRate(ctx context.Context, v values.CreateRateValues) (values.Rate, *errLib.CommonError) {
+	row, err := r.Queries.CreateRentalRate(ctx, db.CreateRentalRateParams{
+		CourtID:            v.CourtID,
+		Name:               v.Name,
+		DayOfWeek:          int32(v.DayOfWeek),
+		StartTime:          v.StartTime,
+		EndTime:            v.EndTime,
+		MemberRateCents:    v.MemberRateCents,
+		NonMemberRateCents: v.NonMemberRateCents,
+		CreditsPerHour:     nullInt32(v.CreditsPerHour),
+		IsActive:           v.IsActive,
+	})
+	if err != nil {
+		return values.Rate{}, rateError(err, "create")
+	}
+	return mapRate(row), nil
+}
+
+func (r *Repository) UpdateRate(ctx context.Context, v values.UpdateRateValues) (values.Rate, *errLib.CommonError) {
+	row, err := r.Queries.UpdateRentalRate(ctx, db.UpdateRentalRateParams{
+		ID:                 v.ID,
+		Name:               v.Name,
+		DayOfWeek:          int32(v.DayOfWeek),
+		StartTime:          v.StartTime,
+		EndTime:            v.EndTime,
+		MemberRateCents:    v.MemberRateCents,
+		NonMemberRateCents: v.NonMemberRateCents,
+		CreditsPerHour:     nullInt32(v.CreditsPerHour),
+		IsActive:           v.IsActive,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return values.Rate{}, errLib.New("Rental rate not found", http.StatusNotFound)
+		}
+		return values.Rate{}, rateError(err, "update")
+	}
+	return mapRate(row), nil
+}
+
+func (r *Repository) DeleteRate(ctx context.Context, id uuid.UUID) *errLib.CommonError {
+	affected, err := r.Queries.DeleteRentalRate(ctx, id)
+	if err != nil {
+		log.Printf("Failed to delete rental rate %s: %v", id, err)
+		return errLib.New("Failed to delete rental rate", http.StatusInternalServerError)
+	}
+	if affected == 0 {
+		return errLib.New("Rental rate not found", http.StatusNotFound)
+	}
+	return nil
+}
+
+func (r *Repository) GetRate(ctx context.Context, id uuid.UUID) (values.Rate, *errLib.CommonError) {
+	row, err := r.Queries.GetRentalRateById(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return values.Rate{}, errLib.New("Rental rate not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to get rental rate %s: %v", id, err)
+		return values.Rate{}, errLib.New("Failed to get rental rate", http.StatusInternalServerError)
+	}
+	return mapRate(row), nil
+}
+
+func (r *Repository) ListRates(ctx context.Context, courtID uuid.UUID) ([]values.Rate, *errLib.CommonError) {
+	rows, err := r.Queries.ListRentalRates(ctx, courtID)
+	if err != nil {
+		log.Printf("Failed to list rental rates of court %s: %v", courtID, err)
+		return nil, errLib.New("Failed to list rental rates", http.StatusInternalServerError)
+	}
+	rates := make([]values.Rate, len(rows))
+	for i, row := range rows {
+		rates[i] = mapRate(row)
+	}
+	return rates, nil
+}
+
+// ListCourtRates returns the active rate bands of each rentable court, narrowed to
+// one court or one location when the IDs are set.
+func (r *Repository) ListCourtRates(ctx context.Context, courtID, locationID uuid.UUID) ([]values.CourtRates, *errLib.CommonError) {
+	rows, err := r.Queries.ListActiveRentalRates(ctx, db.ListActiveRentalRatesParams{
+		CourtID:    nullUUID(courtID),
+		LocationID: nullUUID(locationID),
+	})
+	if err != nil {
+		log.Printf("Failed to list active rental rates: %v", err)
+		return nil, errLib.New("Failed to list rental rates", http.StatusInternalServerError)
+	}
+
+	var courts []values.CourtRates
+	index := make(map[uuid.UUID]int)
+	for _, row := range rows {
+		i, ok := index[row.CourtID]
+		if !ok {
+			i = len(courts)
+			index[row.CourtID] = i
+			courts = append(courts, values.CourtRates{
+				CourtID:    row.CourtID,
+				CourtName:  row.CourtName,
+				LocationID: row.LocationID,
+				Timezone:   timezone.Load(row.Timezone),
+			})
+		}
+
+		rate := mapRate(db.LocationCourtRentalRate{
+			ID:                 row.ID,
+			CourtID:            row.CourtID,
+			Name:               row.Name,
+			DayOfWeek:          row.DayOfWeek,
+			StartTime:          row.StartTime,
+			EndTime:            row.EndTime,
+			MemberRateCents:    row.MemberRateCents,
+			NonMemberRateCents: row.NonMemberRateCents,
+			CreditsPerHour:     row.CreditsPerHour,
+			IsActive:           true,
+		})
+		courts[i].Rates = append(courts[i].Rates, rate.RateDetails)
+	}
+	return courts, nil
+}
+
+func (r *Repository) IsActiveMember(ctx context.Context, customerID uuid.UUID) (bool, *errLib.CommonError) {
+	isMember, err := r.Queries.IsActiveMember(ctx, customerID)
+	if err != nil {
+		log.Printf("Failed to check membership of %s: %v", customerID, err)
+		return false, errLib.New("Failed to check membership", http.StatusInternalServerError)
+	}
+	return isMember, nil
+}
+
+// ExpireStaleHolds marks unpaid holds past their expiry as expired so they stop
+// blocking the court. courtID uuid.Nil expires holds on every court.
+func (r *Repository) ExpireStaleHolds(ctx context.Context, courtID uuid.UUID) (int64, *errLib.CommonError) {
+	affected, err := r.Queries.ExpireStaleHolds(ctx, nullUUID(courtID))
+	if err != nil {
+		log.Printf("Failed to expire court rental holds: %v", err)
+		return 0, errLib.New("Failed to expire court rental holds", http.StatusInternalServerError)
+	}
+	return affected, nil
+}
+
+func (r *Repository) CreateRental(ctx context.Context, params db.CreateRentalParams) (uuid.UUID, *errLib.CommonError) {
+	row, err := r.Queries.CreateRental(ctx, params)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) {
+			switch pqErr.Code {
+			case databaseErrors.ExclusionViolation:
+				return uuid.Nil, errLib.New("This court has just been booked for that time", http.StatusConflict)
+			case databaseErrors.RaiseException:
+				// Court booking conflict from trigger
+				return uuid.Nil, errLib.New(pqErr.Message, http.StatusConflict)
+			}
+		}
+		log.Printf("Failed to create court rental: %v", err)
+		return uuid.Nil, errLib.New("Failed to create court rental", http.StatusInternalServerError)
+	}
+	return row.ID, nil
+}
+
+// LockRental reads a rental's row for update.
+func (r *Repository) LockRental(ctx context.Context, id uuid.UUID) (db.LocationCourtRental, *errLib.CommonError) {
+	row, err := r.Queries.GetRentalForUpdate(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return row, errLib.New("Court rental not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to lock court rental %s: %v", id, err)
+		return row, errLib.New("Failed to get court rental", http.StatusInternalServerError)
+	}
+	return row, nil
+}
+
+func (r *Repository) ConfirmRental(ctx context.Context, id uuid.UUID) *errLib.CommonError {
+	affected, err := r.Queries.ConfirmRental(ctx, id)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && (pqErr.Code == databaseErrors.ExclusionViolation || pqErr.Code == databaseErrors.RaiseException) {
+			return errLib.New("The court is no longer free for this rental", http.StatusConflict)
+		}
+		log.Printf("Failed to confirm court rental %s: %v", id, err)
+		return errLib.New("Failed to confirm court rental", http.StatusInternalServerError)
+	}
+	if affected == 0 {
+		return errLib.New("Court rental is not awaiting payment", http.StatusConflict)
+	}
+	return nil
+}
+
+// MarkRentalRefundDue records that a rental was paid for but could not be confirmed,
+// so its payment has to be refunded.
+func (r *Repository) MarkRentalRefundDue(ctx context.Context, id uuid.UUID) *errLib.CommonError {
+	affected, err := r.Queries.MarkRentalRefundDue(ctx, id)
+	if err != nil {
+		log.Printf("Failed to mark court rental %s as refund due: %v", id, err)
+		return errLib.New("Failed to update court rental", http.StatusInternalServerError)
+	}
+	if affected == 0 {
+		return errLib.New("Court rental is not awaiting payment", http.StatusConflict)
+	}
+	return nil
+}
+
+// MarkRentalRefunded records that the payment of a refund_due rental was refunded.
+// It is a no-op for any other rental.
+func (r *Repository) MarkRentalRefunded(ctx context.Context, id uuid.UUID) *errLib.CommonError {
+	if _, err := r.Queries.MarkRentalRefunded(ctx, id); err != nil {
+		log.Printf("Failed to mark court rental %s as refunded: %v", id, err)
+		return errLib.New("Failed to update court rental", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (r *Repository) CancelRental(ctx context.Context, id uuid.UUID) *errLib.CommonError {
+	affected, err := r.Queries.CancelRental(ctx, id)
+	if err != nil {
+		log.Printf("Failed to cancel court rental %s: %v", id, err)
+		return errLib.New("Failed to cancel court rental", http.StatusInternalServerError)
+	}
+	if affected == 0 {
+		return errLib.New("Court rental is already canceled or expired", http.StatusConflict)
+	}
+	return nil
+}
+
+func (r *Repository) GetRental(ctx context.Context, id uuid.UUID) (values.Rental, *errLib.CommonError) {
+	row, err := r.Queries.GetRentalById(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return values.Rental{}, errLib.New("Court rental not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to get court rental %s: %v", id, err)
+		return values.Rental{}, errLib.New("Failed to get court rental", http.StatusInternalServerError)
+	}
+	return mapRental(db.ListRentalsRow(row)), nil
+}
+
+func (r *Repository) ListCustomerUpcomingRentals(ctx context.Context, customerID uuid.UUID, after time.Time) ([]values.Rental, *errLib.CommonError) {
+	rows, err := r.Queries.ListCustomerUpcomingRentals(ctx, db.ListCustomerUpcomingRentalsParams{
+		CustomerID: customerID,
+		After:      after,
+	})
+	if err != nil {
+		log.Printf("Failed to list upcoming court rentals of %s: %v", customerID, err)
+		return nil, errLib.New("Failed to list court rentals", http.StatusInternalServerError)
+	}
+	rentals := make([]values.Rental, len(rows))
+	for i, row := range rows {
+		rentals[i] = mapRental(db.ListRentalsRow(row))
+	}
+	return rentals, nil
+}
+
+func (r *Repository) ListRentals(ctx context.Context, filter values.ListFilter) ([]values.Rental, *errLib.CommonError) {
+	params := db.ListRentalsParams{
+		CourtID:    nullUUID(filter.CourtID),
+		CustomerID: nullUUID(filter.CustomerID),
+		Status:     sql.NullString{String: filter.Status, Valid: filter.Status != ""},
+		After:      sql.NullTime{Time: filter.After, Valid: !filter.After.IsZero()},
+		Limit:      filter.Limit,
+		Offset:     filter.Offset,
+	}
+	rows, err := r.Queries.ListRentals(ctx, params)
+	if err != nil {
+		log.Printf("Failed to list court rentals: %v", err)
+		return nil, errLib.New("Failed to list court rentals", http.StatusInternalServerError)
+	}
+	rentals := make([]values.Rental, len(rows))
+	for i, row := range rows {
+		rentals[i] = mapRental(row)
+	}
+	return rentals, nil
+}
+
+func mapRental(row db.ListRentalsRow) values.Rental {
+	rental := values.Rental{
+		ID:            row.ID,
+		CourtID:       row.CourtID,
+		CourtName:     row.CourtName,
+		LocationID:    row.LocationID,
+		LocationName:  row.LocationName,
+		CustomerID:    row.CustomerID,
+		CustomerName:  row.CustomerName,
+		StartAt:       row.StartAt,
+		EndAt:         row.EndAt,
+		Status:        row.Status,
+		PaymentMethod: values.PaymentMethod(row.PaymentMethod),
+		IsMember:      row.IsMember,
+		AmountCents:   row.AmountCents,
+		CreditsUsed:   row.CreditsUsed,
+		Currency:      row.Currency,
+		HoldExpiresAt: row.HoldExpiresAt,
+		CreatedAt:     row.CreatedAt,
+	}
+	if row.PaidAt.Valid {
+		rental.PaidAt = &row.PaidAt.Time
+	}
+	if row.CanceledAt.Valid {
+		rental.CanceledAt = &row.CanceledAt.Time
+	}
+	return rental
+}
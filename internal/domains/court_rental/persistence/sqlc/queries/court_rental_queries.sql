@@ -0,0 +1,157 @@
+-- name: CreateRentalRate :one
+INSERT INTO location.court_rental_rates (court_id, name, day_of_week, start_time, end_time, member_rate_cents,
+                                         non_member_rate_cents, credits_per_hour, is_active)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: UpdateRentalRate :one
+UPDATE location.court_rental_rates
+SET name                  = $2,
+    day_of_week           = $3,
+    start_time            = $4,
+    end_time              = $5,
+    member_rate_cents     = $6,
+    non_member_rate_cents = $7,
+    credits_per_hour      = $8,
+    is_active             = $9,
+    updated_at            = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteRentalRate :execrows
+DELETE
+FROM location.court_rental_rates
+WHERE id = $1;
+
+-- name: GetRentalRateById :one
+SELECT *
+FROM location.court_rental_rates
+WHERE id = $1;
+
+-- name: ListRentalRates :many
+SELECT *
+FROM location.court_rental_rates
+WHERE court_id = $1
+ORDER BY day_of_week, start_time;
+
+-- name: ListActiveRentalRates :many
+SELECT r.id,
+       r.court_id,
+       r.name,
+       r.day_of_week,
+       r.start_time,
+       r.end_time,
+       r.member_rate_cents,
+       r.non_member_rate_cents,
+       r.credits_per_hour,
+       c.name        AS court_name,
+       c.location_id,
+       l.timezone
+FROM location.court_rental_rates r
+         JOIN location.courts c ON c.id = r.court_id
+         JOIN location.locations l ON l.id = c.location_id
+WHERE r.is_active = true
+  AND (sqlc.narg('court_id')::uuid IS NULL OR r.court_id = sqlc.narg('court_id')::uuid)
+  AND (sqlc.narg('location_id')::uuid IS NULL OR c.location_id = sqlc.narg('location_id')::uuid)
+ORDER BY c.name, r.day_of_week, r.start_time;
+
+-- name: IsActiveMember :one
+SELECT EXISTS (SELECT 1
+               FROM users.customer_membership_plans
+               WHERE customer_id = $1
+                 AND status = 'active')::bool AS is_member;
+
+-- name: ExpireStaleHolds :execrows
+UPDATE location.court_rentals
+SET status     = 'expired',
+    updated_at = CURRENT_TIMESTAMP
+WHERE status = 'held'
+  AND hold_expires_at <= CURRENT_TIMESTAMP
+  AND (sqlc.narg('court_id')::uuid IS NULL OR court_id = sqlc.narg('court_id')::uuid);
+
+-- name: CreateRental :one
+INSERT INTO location.court_rentals (court_id, customer_id, start_at, end_at, payment_method, is_member, amount_cents,
+                                    credits_used, currency, hold_expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING *;
+
+-- name: GetRentalForUpdate :one
+SELECT *
+FROM location.court_rentals
+WHERE id = $1
+    FOR UPDATE;
+
+-- name: ConfirmRental :execrows
+UPDATE location.court_rentals
+SET status     = 'confirmed',
+    paid_at    = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND status IN ('held', 'expired');
+
+-- name: MarkRentalRefundDue :execrows
+UPDATE location.court_rentals
+SET status     = 'refund_due',
+    paid_at    = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND status IN ('held', 'expired', 'canceled');
+
+-- name: MarkRentalRefunded :execrows
+UPDATE location.court_rentals
+SET status     = 'refunded',
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND status = 'refund_due';
+
+-- name: CancelRental :execrows
+UPDATE location.court_rentals
+SET status      = 'canceled',
+    canceled_at = CURRENT_TIMESTAMP,
+    updated_at  = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND status IN ('held', 'confirmed');
+
+-- name: GetRentalById :one
+SELECT cr.*,
+       c.name                                 AS court_name,
+       c.location_id,
+       l.name                                 AS location_name,
+       (u.first_name || ' ' || u.last_name)::text AS customer_name
+FROM location.court_rentals cr
+         JOIN location.courts c ON c.id = cr.court_id
+         JOIN location.locations l ON l.id = c.location_id
+         JOIN users.users u ON u.id = cr.customer_id
+WHERE cr.id = $1;
+
+-- name: ListCustomerUpcomingRentals :many
+SELECT cr.*,
+       c.name                                 AS court_name,
+       c.location_id,
+       l.name                                 AS location_name,
+       (u.first_name || ' ' || u.last_name)::text AS customer_name
+FROM location.court_rentals cr
+         JOIN location.courts c ON c.id = cr.court_id
+         JOIN location.locations l ON l.id = c.location_id
+         JOIN users.users u ON u.id = cr.customer_id
+WHERE cr.customer_id = sqlc.arg('customer_id')
+  AND cr.end_at > sqlc.arg('after')::timestamptz
+  AND (cr.status = 'confirmed' OR (cr.status = 'held' AND cr.hold_expires_at > CURRENT_TIMESTAMP))
+ORDER BY cr.start_at;
+
+-- name: ListRentals :many
+SELECT cr.*,
+       c.name                                 AS court_name,
+       c.location_id,
+       l.name                                 AS location_name,
+       (u.first_name || ' ' || u.last_name)::text AS customer_name
+FROM location.court_rentals cr
+         JOIN location.courts c ON c.id = cr.court_id
+         JOIN location.locations l ON l.id = c.location_id
+         JOIN users.users u ON u.id = cr.customer_id
+WHERE (sqlc.narg('court_id')::uuid IS NULL OR cr.court_id = sqlc.narg('court_id')::uuid)
+  AND (sqlc.narg('customer_id')::uuid IS NULL OR cr.customer_id = sqlc.narg('customer_id')::uuid)
+  AND (sqlc.narg('status')::text IS NULL OR cr.status = sqlc.narg('status')::text)
+  AND (sqlc.narg('after')::timestamptz IS NULL OR cr.end_at > sqlc.narg('after')::timestamptz)
+ORDER BY cr.start_at
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
@@ -0,0 +1,677 @@
+package court_rental
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"api/internal/di"
+	staffActivityLogs "api/internal/domains/audit/staff_activity_logs/service"
+	bookingService "api/internal/domains/booking/service"
+	bookingValues "api/internal/domains/booking/values"
+	repo "api/internal/domains/court_rental/persistence"
+	db "api/internal/domains/court_rental/persistence/sqlc/generated"
+	values "api/internal/domains/court_rental/values"
+	creditRepo "api/internal/domains/user/persistence/repositories"
+	dbUser "api/internal/domains/user/persistence/sqlc/generated"
+	userValues "api/internal/domains/user/values"
+	errLib "api/internal/libs/errors"
+	contextUtils "api/utils/context"
+	txUtils "api/utils/db"
+	"api/utils/timezone"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// SlotStep is the grid rentals start on, in facility time.
+	SlotStep = 30 * time.Minute
+	// MaxDuration is the longest a single rental may last.
+	MaxDuration = 4 * time.Hour
+	// HoldDuration is how long a court stays held while the customer pays. It matches
+	// the shortest expiry Stripe allows on a checkout session.
+	HoldDuration = 30 * time.Minute
+)
+
+// Service rents courts to customers by the half hour. A rental is first held, which
+// blocks the court like any other booking, and confirmed once paid by card or credits.
+type Service struct {
+	repo                     *repo.Repository
+	creditRepo               *creditRepo.CustomerCreditRepository
+	bookingService           *bookingService.Service
+	staffActivityLogsService *staffActivityLogs.Service
+	db                       *sql.DB
+}
+
+func NewService(container *di.Container) *Service {
+	return &Service{
+		repo:                     repo.NewRepository(container),
+		creditRepo:               creditRepo.NewCustomerCreditRepository(container),
+		bookingService:           bookingService.NewService(container),
+		staffActivityLogsService: staffActivityLogs.NewService(container),
+		db:                       container.DB,
+	}
+}
+
+// IsMember reports whether the customer pays member rates.
+func (s *Service) IsMember(ctx context.Context, customerID uuid.UUID) (bool, *errLib.CommonError) {
+	return s.repo.IsActiveMember(ctx, customerID)
+}
+
+// GetSlots lists the free, priced start times on each rentable court for one day.
+func (s *Service) GetSlots(ctx context.Context, filter values.SlotFilter) ([]values.Slot, *errLib.CommonError) {
+	if err := validateDuration(filter.Duration); err != nil {
+		return nil, err
+	}
+
+	courts, err := s.repo.ListCourtRates(ctx, filter.CourtID, filter.LocationID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	slots := []values.Slot{}
+	bookingsByLocation := make(map[uuid.UUID]map[uuid.UUID][]bookingValues.Booking)
+
+	for _, court := range courts {
+		dayStart, dayEnd := timezone.DayBounds(timezone.At(filter.Date, 0, 0, 0, court.Timezone), court.Timezone)
+
+		bookings, ok := bookingsByLocation[court.LocationID]
+		if !ok {
+			availability, err := s.bookingService.GetAvailability(ctx, bookingValues.AvailabilityFilter{
+				ResourceType: bookingValues.ResourceCourt,
+				LocationID:   court.LocationID,
+				From:         dayStart,
+				To:           dayEnd,
+			})
+			if err != nil {
+				return nil, err
+			}
+			bookings = make(map[uuid.UUID][]bookingValues.Booking, len(availability))
+			for _, a := range availability {
+				bookings[a.ID] = a.Bookings
+			}
+			bookingsByLocation[court.LocationID] = bookings
+		}
+
+		for start := dayStart; !start.Add(filter.Duration).After(dayEnd); start = start.Add(SlotStep) {
+			end := start.Add(filter.Duration)
+			if start.Before(now) || overlapsAny(bookings[court.CourtID], start, end) {
+				continue
+			}
+			price, err := quote(court, start, end, filter.IsMember)
+			if err != nil {
+				continue
+			}
+			slots = append(slots, values.Slot{
+				CourtID:   court.CourtID,
+				CourtName: court.CourtName,
+				StartAt:   start,
+				EndAt:     end,
+				Quote:     price,
+			})
+		}
+	}
+
+	return slots, nil
+}
+
+// HoldForCheckout holds a court for the customer to pay for by card. A free rental is
+// confirmed straight away.
+func (s *Service) HoldForCheckout(ctx context.Context, req values.HoldRequest) (values.Rental, *errLib.CommonError) {
+	var rental values.Rental
+	err := txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		r := s.repo.WithTx(tx)
+
+		id, _, _, err := s.hold(ctx, tx, req)
+		if err != nil {
+			return err
+		}
+
+		rental, err = r.GetRental(ctx, id)
+		if err != nil {
+			return err
+		}
+		if rental.AmountCents > 0 {
+			return nil
+		}
+
+		if err = r.ConfirmRental(ctx, id); err != nil {
+			return err
+		}
+		rental, err = r.GetRental(ctx, id)
+		return err
+	})
+	if err != nil {
+		return values.Rental{}, err
+	}
+	return rental, nil
+}
+
+// BookWithCredits rents a court and pays for it from the customer's credit balance,
+// counting toward their weekly credit limit.
+func (s *Service) BookWithCredits(ctx context.Context, req values.HoldRequest) (values.Rental, *errLib.CommonError) {
+	req.PaymentMethod = values.PaymentCredits
+
+	var rental values.Rental
+	err := txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		r := s.repo.WithTx(tx)
+		credits := s.creditRepo.WithTx(tx)
+
+		id, price, loc, err := s.hold(ctx, tx, req)
+		if err != nil {
+			return err
+		}
+		cost := *price.Credits
+
+		hasSufficient, err := credits.HasSufficientCredits(ctx, req.CustomerID, cost)
+		if err != nil {
+			return err
+		}
+		if !hasSufficient {
+			return errLib.New("Insufficient credits", http.StatusBadRequest)
+		}
+
+		// Weekly limits reset at Monday midnight in the court facility's timezone
+		weekStart := timezone.WeekStart(time.Now(), loc)
+
+		canUseCredits, err := credits.CanUseCreditsWithinWeeklyLimit(ctx, req.CustomerID, cost, weekStart)
+		if err != nil {
+			return err
+		}
+		if !canUseCredits {
+			return errLib.New("Weekly credit limit exceeded", http.StatusBadRequest)
+		}
+
+		description := fmt.Sprintf("Court rental payment (%s)", id)
+		if err = credits.DeductCredits(ctx, req.CustomerID, cost, userValues.CourtRentalCreditReference(id), description); err != nil {
+			return err
+		}
+
+		if err = credits.LogCreditTransaction(ctx, req.CustomerID, -cost, dbUser.CreditTransactionTypeCourtRental, nil, description); err != nil {
+			log.Printf("Failed to log credit transaction: %v", err)
+		}
+
+		if err = credits.UpdateWeeklyUsage(ctx, req.CustomerID, cost, weekStart); err != nil {
+			log.Printf("Failed to update weekly usage tracking: %v", err)
+		}
+
+		if err = r.ConfirmRental(ctx, id); err != nil {
+			return err
+		}
+		rental, err = r.GetRental(ctx, id)
+		return err
+	})
+	if err != nil {
+		return values.Rental{}, err
+	}
+	return rental, nil
+}
+
+// hold prices the requested time and writes a held rental for it inside tx. It returns
+// the rental's ID, its quote and the court's timezone.
+func (s *Service) hold(ctx context.Context, tx *sql.Tx, req values.HoldRequest) (uuid.UUID, values.Quote, *time.Location, *errLib.CommonError) {
+	r := s.repo.WithTx(tx)
+
+	if _, err := r.ExpireStaleHolds(ctx, req.CourtID); err != nil {
+		return uuid.Nil, values.Quote{}, nil, err
+	}
+
+	courts, err := r.ListCourtRates(ctx, req.CourtID, uuid.Nil)
+	if err != nil {
+		return uuid.Nil, values.Quote{}, nil, err
+	}
+	if len(courts) == 0 {
+		return uuid.Nil, values.Quote{}, nil, errLib.New("This court is not available for rental", http.StatusNotFound)
+	}
+	court := courts[0]
+
+	if err = validateRange(req.StartAt, req.EndAt, court.Timezone, time.Now()); err != nil {
+		return uuid.Nil, values.Quote{}, nil, err
+	}
+
+	isMember, err := r.IsActiveMember(ctx, req.CustomerID)
+	if err != nil {
+		return uuid.Nil, values.Quote{}, nil, err
+	}
+
+	price, err := quote(court, req.StartAt, req.EndAt, isMember)
+	if err != nil {
+		return uuid.Nil, values.Quote{}, nil, err
+	}
+
+	amount, creditsUsed := price.AmountCents, int32(0)
+	if req.PaymentMethod == values.PaymentCredits {
+		if price.Credits == nil {
+			return uuid.Nil, values.Quote{}, nil, errLib.New("This time cannot be paid for with credits", http.StatusBadRequest)
+		}
+		amount, creditsUsed = 0, *price.Credits
+	}
+
+	if err = s.bookingService.Reserve(ctx, tx, bookingValues.Request{
+		ResourceType: bookingValues.ResourceCourt,
+		ResourceID:   req.CourtID,
+		ActivityType: bookingValues.ActivityCourtRental,
+		StartAt:      req.StartAt,
+		EndAt:        req.EndAt,
+	}); err != nil {
+		return uuid.Nil, values.Quote{}, nil, err
+	}
+
+	id, err := r.CreateRental(ctx, db.CreateRentalParams{
+		CourtID:       req.CourtID,
+		CustomerID:    req.CustomerID,
+		StartAt:       req.StartAt,
+		EndAt:         req.EndAt,
+		PaymentMethod: string(req.PaymentMethod),
+		IsMember:      isMember,
+		AmountCents:   amount,
+		CreditsUsed:   creditsUsed,
+		Currency:      "cad",
+		HoldExpiresAt: time.Now().Add(HoldDuration),
+	})
+	if err != nil {
+		return uuid.Nil, values.Quote{}, nil, err
+	}
+
+	return id, price, court.Timezone, nil
+}
+
+// ConfirmPaid confirms a rental once its card payment completes, and returns the
+// rental's status. It is safe to call more than once. A hold that lapsed while the
+// customer paid no longer blocks the court, so the court is checked again before
+// confirming it. When the court has been taken since, or the hold was canceled, the
+// rental is marked refund_due: the caller refunds the payment and then calls
+// MarkRefunded. A refunded rental was already refunded and needs nothing more.
+func (s *Service) ConfirmPaid(ctx context.Context, rentalID, customerID uuid.UUID) (status string, err *errLib.CommonError) {
+	err = txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		r := s.repo.WithTx(tx)
+
+		rental, err := r.LockRental(ctx, rentalID)
+		if err != nil {
+			return err
+		}
+		if rental.CustomerID != customerID {
+			return errLib.New("Court rental belongs to another customer", http.StatusBadRequest)
+		}
+
+		switch rental.Status {
+		case values.StatusConfirmed, values.StatusRefundDue, values.StatusRefunded:
+			status = rental.Status
+			return nil
+		case values.StatusCanceled:
+			log.Printf("[COURT RENTAL] Rental %s was paid for after its hold was canceled; refund due", rentalID)
+			status = values.StatusRefundDue
+			return r.MarkRentalRefundDue(ctx, rentalID)
+		}
+
+		if rental.Status == values.StatusExpired || !rental.HoldExpiresAt.After(time.Now()) {
+			if err = s.bookingService.Reserve(ctx, tx, bookingValues.Request{
+				ResourceType: bookingValues.ResourceCourt,
+				ResourceID:   rental.CourtID,
+				ActivityType: bookingValues.ActivityCourtRental,
+				ActivityID:   rentalID,
+				StartAt:      rental.StartAt,
+				EndAt:        rental.EndAt,
+			}); err != nil {
+				if err.HTTPCode != http.StatusConflict {
+					return err
+				}
+				log.Printf("[COURT RENTAL] Rental %s was paid for after its hold lapsed and the court was taken: %s", rentalID, err.Message)
+				status = values.StatusRefundDue
+				return r.MarkRentalRefundDue(ctx, rentalID)
+			}
+		}
+
+		if err = r.ConfirmRental(ctx, rentalID); err != nil {
+			return err
+		}
+		status = values.StatusConfirmed
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return status, nil
+}
+
+// MarkRefunded records that the payment of a rental ConfirmPaid could not confirm was
+// refunded.
+func (s *Service) MarkRefunded(ctx context.Context, rentalID uuid.UUID) *errLib.CommonError {
+	return s.repo.MarkRentalRefunded(ctx, rentalID)
+}
+
+// ReleaseHold frees a held court when checkout could not be started.
+func (s *Service) ReleaseHold(ctx context.Context, rentalID uuid.UUID) *errLib.CommonError {
+	return txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		r := s.repo.WithTx(tx)
+
+		rental, err := r.LockRental(ctx, rentalID)
+		if err != nil {
+			return err
+		}
+		if rental.Status != values.StatusHeld {
+			return nil
+		}
+		return r.CancelRental(ctx, rentalID)
+	})
+}
+
+// CancelRental cancels a rental. Customers can drop their own unpaid hold or a future
+// rental paid with credits, which are refunded; staff can cancel any rental.
+func (s *Service) CancelRental(ctx context.Context, rentalID uuid.UUID) *errLib.CommonError {
+	userID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return err
+	}
+	isStaff, err := contextUtils.IsStaff(ctx)
+	if err != nil {
+		return err
+	}
+
+	return txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		r := s.repo.WithTx(tx)
+
+		row, err := r.LockRental(ctx, rentalID)
+		if err != nil {
+			return err
+		}
+		if !isStaff && row.CustomerID != userID {
+			return errLib.New("Court rental not found", http.StatusNotFound)
+		}
+
+		paid := row.Status == values.StatusConfirmed
+		if !isStaff && paid {
+			if row.PaymentMethod != string(values.PaymentCredits) {
+				return errLib.New("Card-paid court rentals can only be canceled by staff", http.StatusForbidden)
+			}
+			if !row.StartAt.After(time.Now()) {
+				return errLib.New("Court rentals cannot be canceled once they have started", http.StatusBadRequest)
+			}
+		}
+
+		if err = r.CancelRental(ctx, rentalID); err != nil {
+			return err
+		}
+
+		rental, err := r.GetRental(ctx, rentalID)
+		if err != nil {
+			return err
+		}
+
+		if paid && row.CreditsUsed > 0 {
+			if err = s.refundCredits(ctx, tx, rental); err != nil {
+				return err
+			}
+		}
+
+		if !isStaff {
+			return nil
+		}
+		return s.staffActivityLogsService.InsertStaffActivity(ctx, tx, userID,
+			fmt.Sprintf("Canceled court rental of %s on %s (%s)", rental.CustomerName, rental.CourtName,
+				rental.StartAt.UTC().Format("Jan 2 15:04 MST")))
+	})
+}
+
+func (s *Service) refundCredits(ctx context.Context, tx *sql.Tx, rental values.Rental) *errLib.CommonError {
+	credits := s.creditRepo.WithTx(tx)
+
+	description := fmt.Sprintf("Refund for canceled court rental (%s)", rental.ID)
+	refunded, err := credits.RefundCredits(ctx, rental.CustomerID, rental.CreditsUsed,
+		userValues.CourtRentalCreditReference(rental.ID), description)
+	if err != nil {
+		return err
+	}
+	if refunded == 0 {
+		return nil // refunded already
+	}
+
+	if err := credits.LogCreditTransaction(ctx, rental.CustomerID, refunded, dbUser.CreditTransactionTypeRefund, nil, description); err != nil {
+		log.Printf("Failed to log credit refund transaction: %v", err)
+	}
+
+	// Give the credits back to the week they were spent in
+	spentAt := rental.CreatedAt
+	if rental.PaidAt != nil {
+		spentAt = *rental.PaidAt
+	}
+	weekStart := timezone.WeekStart(spentAt, timezone.ForLocation(ctx, tx, rental.LocationID))
+	if err := credits.ReduceWeeklyUsage(ctx, rental.CustomerID, refunded, weekStart); err != nil {
+		log.Printf("Failed to reduce weekly usage tracking: %v", err)
+	}
+
+	return nil
+}
+
+func (s *Service) GetRental(ctx context.Context, id uuid.UUID) (values.Rental, *errLib.CommonError) {
+	return s.repo.GetRental(ctx, id)
+}
+
+// GetMyUpcomingRentals lists the customer's held and confirmed rentals that have not ended.
+func (s *Service) GetMyUpcomingRentals(ctx context.Context, customerID uuid.UUID) ([]values.Rental, *errLib.CommonError) {
+	return s.repo.ListCustomerUpcomingRentals(ctx, customerID, time.Now())
+}
+
+func (s *Service) GetRentals(ctx context.Context, filter values.ListFilter) ([]values.Rental, *errLib.CommonError) {
+	return s.repo.ListRentals(ctx, filter)
+}
+
+func (s *Service) GetRates(ctx context.Context, courtID uuid.UUID) ([]values.Rate, *errLib.CommonError) {
+	return s.repo.ListRates(ctx, courtID)
+}
+
+func (s *Service) CreateRate(ctx context.Context, v values.CreateRateValues) (values.Rate, *errLib.CommonError) {
+	var created values.Rate
+	err := txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		r := s.repo.WithTx(tx)
+
+		existing, err := r.ListRates(ctx, v.CourtID)
+		if err != nil {
+			return err
+		}
+		if err = validateRate(v.RateDetails, uuid.Nil, existing); err != nil {
+			return err
+		}
+
+		created, err = r.CreateRate(ctx, v)
+		if err != nil {
+			return err
+		}
+		return s.logStaffActivity(ctx, tx, fmt.Sprintf("Created court rental rate '%s' on %s", v.Name, v.DayOfWeek))
+	})
+	if err != nil {
+		return values.Rate{}, err
+	}
+	return created, nil
+}
+
+func (s *Service) UpdateRate(ctx context.Context, v values.UpdateRateValues) (values.Rate, *errLib.CommonError) {
+	var updated values.Rate
+	err := txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		r := s.repo.WithTx(tx)
+
+		current, err := r.GetRate(ctx, v.ID)
+		if err != nil {
+			return err
+		}
+		existing, err := r.ListRates(ctx, current.CourtID)
+		if err != nil {
+			return err
+		}
+		if err = validateRate(v.RateDetails, v.ID, existing); err != nil {
+			return err
+		}
+
+		updated, err = r.UpdateRate(ctx, v)
+		if err != nil {
+			return err
+		}
+		return s.logStaffActivity(ctx, tx, fmt.Sprintf("Updated court rental rate '%s' on %s", v.Name, v.DayOfWeek))
+	})
+	if err != nil {
+		return values.Rate{}, err
+	}
+	return updated, nil
+}
+
+func (s *Service) DeleteRate(ctx context.Context, id uuid.UUID) *errLib.CommonError {
+	return txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		r := s.repo.WithTx(tx)
+
+		rate, err := r.GetRate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err = r.DeleteRate(ctx, id); err != nil {
+			return err
+		}
+		return s.logStaffActivity(ctx, tx, fmt.Sprintf("Deleted court rental rate '%s' on %s", rate.Name, rate.DayOfWeek))
+	})
+}
+
+func (s *Service) logStaffActivity(ctx context.Context, tx *sql.Tx, description string) *errLib.CommonError {
+	staffID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return err
+	}
+	return s.staffActivityLogsService.InsertStaffActivity(ctx, tx, staffID, description)
+}
+
+// quote prices [start, end) against the court's rate bands, charging each band's hourly
+// rate pro rata for the minutes the rental spends in it.
+func quote(court values.CourtRates, start, end time.Time, member bool) (values.Quote, *errLib.CommonError) {
+	loc := court.Timezone
+	var centMinutes, creditMinutes, covered int64
+	creditsAccepted := true
+	var bands []string
+	seen := make(map[string]bool)
+
+	for day, _ := timezone.DayBounds(start, loc); day.Before(end); day = day.AddDate(0, 0, 1) {
+		for _, rate := range court.Rates {
+			if rate.DayOfWeek != day.Weekday() {
+				continue
+			}
+
+			from := laterOf(start, timezone.At(day, rate.StartTime.Hour(), rate.StartTime.Minute(), 0, loc))
+			to := earlierOf(end, timezone.At(day, rate.EndTime.Hour(), rate.EndTime.Minute(), 0, loc))
+			if !to.After(from) {
+				continue
+			}
+
+			minutes := int64(to.Sub(from) / time.Minute)
+			covered += minutes
+
+			hourly := rate.NonMemberRateCents
+			if member {
+				hourly = rate.MemberRateCents
+			}
+			centMinutes += minutes * int64(hourly)
+
+			if rate.CreditsPerHour == nil {
+				creditsAccepted = false
+			} else {
+				creditMinutes += minutes * int64(*rate.CreditsPerHour)
+			}
+
+			if !seen[rate.Name] {
+				seen[rate.Name] = true
+				bands = append(bands, rate.Name)
+			}
+		}
+	}
+
+	if covered < int64(end.Sub(start)/time.Minute) {
+		return values.Quote{}, errLib.New("The court is not open for rental for the whole of this time", http.StatusBadRequest)
+	}
+
+	q := values.Quote{AmountCents: int32((centMinutes + 30) / 60), Bands: bands}
+	if creditsAccepted {
+		credits := int32((creditMinutes + 59) / 60)
+		q.Credits = &credits
+	}
+	return q, nil
+}
+
+func validateDuration(d time.Duration) *errLib.CommonError {
+	if d < SlotStep || d > MaxDuration || d%SlotStep != 0 {
+		return errLib.New(fmt.Sprintf("Rental length must be a multiple of %d minutes, up to %d hours",
+			int(SlotStep.Minutes()), int(MaxDuration.Hours())), http.StatusBadRequest)
+	}
+	return nil
+}
+
+func validateRange(start, end time.Time, loc *time.Location, now time.Time) *errLib.CommonError {
+	if err := validateDuration(end.Sub(start)); err != nil {
+		return err
+	}
+	local := start.In(loc)
+	if local.Second() != 0 || local.Nanosecond() != 0 || local.Minute()%int(SlotStep.Minutes()) != 0 {
+		return errLib.New("Rentals must start on the hour or half hour", http.StatusBadRequest)
+	}
+	if !start.After(now) {
+		return errLib.New("Rentals must start in the future", http.StatusBadRequest)
+	}
+	return nil
+}
+
+// validateRate rejects a band that is empty or overlaps another active band of the same
+// court on the same day. Bands cannot cross midnight; split them into two days instead.
+func validateRate(rate values.RateDetails, id uuid.UUID, existing []values.Rate) *errLib.CommonError {
+	if rate.DayOfWeek < time.Sunday || rate.DayOfWeek > time.Saturday {
+		return errLib.New("day_of_week must be between 0 (Sunday) and 6 (Saturday)", http.StatusBadRequest)
+	}
+	start, end := minuteOfDay(rate.StartTime), minuteOfDay(rate.EndTime)
+	if end <= start {
+		return errLib.New("end_time must be after start_time; split bands that cross midnight", http.StatusBadRequest)
+	}
+	if rate.MemberRateCents < 0 || rate.NonMemberRateCents < 0 {
+		return errLib.New("Rates cannot be negative", http.StatusBadRequest)
+	}
+	if rate.CreditsPerHour != nil && *rate.CreditsPerHour <= 0 {
+		return errLib.New("credits_per_hour must be greater than 0", http.StatusBadRequest)
+	}
+	if !rate.IsActive {
+		return nil
+	}
+
+	for _, other := range existing {
+		if other.ID == id || !other.IsActive || other.DayOfWeek != rate.DayOfWeek {
+			continue
+		}
+		if start < minuteOfDay(other.EndTime) && minuteOfDay(other.StartTime) < end {
+			return errLib.New(fmt.Sprintf("Overlaps the '%s' rate on %s", other.Name, other.DayOfWeek), http.StatusConflict)
+		}
+	}
+	return nil
+}
+
+func minuteOfDay(t time.Time) int {
+	return t.Hour()*60 + t.Minute()
+}
+
+func overlapsAny(bookings []bookingValues.Booking, start, end time.Time) bool {
+	for _, b := range bookings {
+		if b.StartAt.Before(end) && start.Before(b.EndAt) {
+			return true
+		}
+	}
+	return false
+}
+
+func laterOf(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func earlierOf(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
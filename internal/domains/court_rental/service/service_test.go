@@ -0,0 +1,79 @@
+package court_rental
+
+import (
+	"testing"
+	"time"
+
+	values "api/internal/domains/court_rental/values"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func clock(h, m int) time.Time {
+	return time.Date(0, 1, 1, h, m, 0, 0, time.UTC)
+}
+
+func TestQuote(t *testing.T) {
+	loc, err := time.LoadLocation("America/Toronto")
+	require.NoError(t, err)
+
+	credits := int32(2)
+	court := values.CourtRates{
+		Timezone: loc,
+		Rates: []values.RateDetails{
+			{Name: "Off-peak", DayOfWeek: time.Monday, StartTime: clock(8, 0), EndTime: clock(17, 0),
+				MemberRateCents: 3000, NonMemberRateCents: 4000, CreditsPerHour: &credits},
+			{Name: "Peak", DayOfWeek: time.Monday, StartTime: clock(17, 0), EndTime: clock(22, 0),
+				MemberRateCents: 5000, NonMemberRateCents: 6000},
+		},
+	}
+
+	t.Run("Charges each band pro rata", func(t *testing.T) {
+		start := time.Date(2026, 3, 2, 16, 0, 0, 0, loc)
+
+		q, err := quote(court, start, start.Add(90*time.Minute), false)
+
+		require.Nil(t, err)
+		assert.Equal(t, int32(4000+3000), q.AmountCents)
+		assert.Equal(t, []string{"Off-peak", "Peak"}, q.Bands)
+		assert.Nil(t, q.Credits, "peak time has no credit price")
+	})
+
+	t.Run("Uses member rates and prices credits", func(t *testing.T) {
+		start := time.Date(2026, 3, 2, 9, 0, 0, 0, loc)
+
+		q, err := quote(court, start, start.Add(90*time.Minute), true)
+
+		require.Nil(t, err)
+		assert.Equal(t, int32(4500), q.AmountCents)
+		require.NotNil(t, q.Credits)
+		assert.Equal(t, int32(3), *q.Credits)
+	})
+
+	t.Run("Rejects time outside the bands", func(t *testing.T) {
+		start := time.Date(2026, 3, 2, 21, 30, 0, 0, loc)
+
+		_, err := quote(court, start, start.Add(time.Hour), false)
+
+		require.NotNil(t, err)
+		assert.Equal(t, 400, err.HTTPCode)
+	})
+}
+
+func TestValidateRate(t *testing.T) {
+	existing := []values.Rate{{RateDetails: values.RateDetails{
+		Name: "Peak", DayOfWeek: time.Friday, StartTime: clock(17, 0), EndTime: clock(22, 0), IsActive: true,
+	}}}
+
+	overlapping := values.RateDetails{Name: "Evening", DayOfWeek: time.Friday, StartTime: clock(20, 0), EndTime: clock(23, 0), IsActive: true}
+	assert.NotNil(t, validateRate(overlapping, uuid.New(), existing))
+
+	otherDay := overlapping
+	otherDay.DayOfWeek = time.Saturday
+	assert.Nil(t, validateRate(otherDay, uuid.New(), existing))
+
+	crossesMidnight := values.RateDetails{DayOfWeek: time.Saturday, StartTime: clock(22, 0), EndTime: clock(1, 0)}
+	assert.NotNil(t, validateRate(crossesMidnight, uuid.New(), existing))
+}
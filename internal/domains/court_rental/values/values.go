@@ -0,0 +1,134 @@
+package values
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PaymentMethod string
+
+const (
+	PaymentStripe  PaymentMethod = "stripe"
+	PaymentCredits PaymentMethod = "credits"
+)
+
+// Rental statuses. A rental is held while the customer pays and confirmed once paid.
+// A card payment that completes after the court was taken leaves the rental
+// refund_due until the payment is refunded.
+const (
+	StatusHeld      = "held"
+	StatusConfirmed = "confirmed"
+	StatusCanceled  = "canceled"
+	StatusExpired   = "expired"
+	StatusRefundDue = "refund_due"
+	StatusRefunded  = "refunded"
+)
+
+// RateDetails is an hourly rate for one time band of one day of the week. Start and
+// end are facility wall-clock times; only their hour and minute are used.
+type RateDetails struct {
+	Name               string
+	DayOfWeek          time.Weekday
+	StartTime          time.Time
+	EndTime            time.Time
+	MemberRateCents    int32
+	NonMemberRateCents int32
+	// CreditsPerHour is nil when the band cannot be paid for with credits.
+	CreditsPerHour *int32
+	IsActive       bool
+}
+
+type CreateRateValues struct {
+	CourtID uuid.UUID
+	RateDetails
+}
+
+type UpdateRateValues struct {
+	ID uuid.UUID
+	RateDetails
+}
+
+type Rate struct {
+	ID        uuid.UUID
+	CourtID   uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	RateDetails
+}
+
+// CourtRates is a court's active rate bands with the timezone they are written in.
+type CourtRates struct {
+	CourtID    uuid.UUID
+	CourtName  string
+	LocationID uuid.UUID
+	Timezone   *time.Location
+	Rates      []RateDetails
+}
+
+// Quote is the price of renting a court for a time range.
+type Quote struct {
+	AmountCents int32
+	// Credits is nil when part of the rental falls in a band without a credit price.
+	Credits *int32
+	// Bands names the rate bands the rental spans, e.g. "Off-peak", "Peak".
+	Bands []string
+}
+
+// SlotFilter selects the courts and calendar day (at the facility) to search.
+type SlotFilter struct {
+	LocationID uuid.UUID
+	CourtID    uuid.UUID
+	Date       time.Time
+	Duration   time.Duration
+	IsMember   bool
+}
+
+// Slot is a free, rentable time range on a court with its price.
+type Slot struct {
+	CourtID   uuid.UUID
+	CourtName string
+	StartAt   time.Time
+	EndAt     time.Time
+	Quote
+}
+
+// HoldRequest asks to rent a court for a time range.
+type HoldRequest struct {
+	CourtID       uuid.UUID
+	CustomerID    uuid.UUID
+	StartAt       time.Time
+	EndAt         time.Time
+	PaymentMethod PaymentMethod
+}
+
+type Rental struct {
+	ID            uuid.UUID
+	CourtID       uuid.UUID
+	CourtName     string
+	LocationID    uuid.UUID
+	LocationName  string
+	CustomerID    uuid.UUID
+	CustomerName  string
+	StartAt       time.Time
+	EndAt         time.Time
+	Status        string
+	PaymentMethod PaymentMethod
+	IsMember      bool
+	AmountCents   int32
+	CreditsUsed   int32
+	Currency      string
+	HoldExpiresAt time.Time
+	PaidAt        *time.Time
+	CanceledAt    *time.Time
+	CreatedAt     time.Time
+}
+
+type ListFilter struct {
+	CourtID    uuid.UUID
+	CustomerID uuid.UUID
+	Status     string
+	After      time.Time
+	Limit      int32
+	Offset     int32
+}
@@ -21,6 +21,8 @@ type CreateCreditPackageRequest struct {
 	CreditAllocation int32 `json:"credit_allocation" validate:"required,min=1" example:"10"`
 	// Weekly limit on credit usage (0 = unlimited)
 	WeeklyCreditLimit int32 `json:"weekly_credit_limit" validate:"min=0" example:"5"`
+	// Days the credits can be used after purchase (omit for credits that never expire)
+	CreditValidityDays *int32 `json:"credit_validity_days" validate:"omitempty,min=1" example:"90"`
 	// Price in cents (required if stripe_price_id not provided)
 	UnitAmount *int64 `json:"unit_amount" example:"2999"`
 	// Currency code (defaults to "cad" if not provided)
@@ -51,6 +53,8 @@ type UpdateCreditPackageRequest struct {
 	CreditAllocation int32 `json:"credit_allocation" validate:"required,min=1" example:"10"`
 	// Weekly limit on credit usage (0 = unlimited)
 	WeeklyCreditLimit int32 `json:"weekly_credit_limit" validate:"min=0" example:"5"`
+	// Days the credits can be used after purchase (omit for credits that never expire)
+	CreditValidityDays *int32 `json:"credit_validity_days" validate:"omitempty,min=1" example:"90"`
 }
 
 type CreditPackageIDParam struct {
@@ -6,16 +6,17 @@ import (
 )
 
 type CreditPackageResponse struct {
-	ID                uuid.UUID `json:"id"`
-	Name              string    `json:"name"`
-	Description       *string   `json:"description,omitempty"`
-	StripePriceID     string    `json:"stripe_price_id"`
-	CreditAllocation  int32     `json:"credit_allocation"`
-	WeeklyCreditLimit int32     `json:"weekly_credit_limit"`
-	Price             float64   `json:"price"`              // Price in dollars (e.g., 49.99)
-	Currency          string    `json:"currency"`           // e.g., "CAD"
-	CreatedAt         time.Time `json:"created_at"`
-	UpdatedAt         time.Time `json:"updated_at"`
+	ID                 uuid.UUID `json:"id"`
+	Name               string    `json:"name"`
+	Description        *string   `json:"description,omitempty"`
+	StripePriceID      string    `json:"stripe_price_id"`
+	CreditAllocation   int32     `json:"credit_allocation"`
+	WeeklyCreditLimit  int32     `json:"weekly_credit_limit"`
+	CreditValidityDays *int32    `json:"credit_validity_days"` // nil when credits never expire
+	Price              float64   `json:"price"`                // Price in dollars (e.g., 49.99)
+	Currency           string    `json:"currency"`             // e.g., "CAD"
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
 }
 
 type CustomerActiveCreditPackageResponse struct {
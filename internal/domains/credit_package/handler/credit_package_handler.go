@@ -74,6 +74,7 @@ func (h *CreditPackageHandler) GetCreditPackageByID(w http.ResponseWriter, r *ht
 // @Produce json
 // @Security Bearer
 // @Param id path string true "Credit Package ID" Format(uuid)
+// @Param use_balance query bool false "Apply the customer's gift card balance"
 // @Success 200 {object} paymentDto.CheckoutResponseDto "Checkout URL generated successfully"
 // @Failure 400 {object} map[string]interface{} "Bad Request: Invalid ID or customer has remaining credits"
 // @Failure 404 {object} map[string]interface{} "Not Found: Package not found"
@@ -90,7 +91,9 @@ func (h *CreditPackageHandler) CheckoutCreditPackage(w http.ResponseWriter, r *h
 	// Get success and cancel URLs based on request origin
 	successURL, cancelURL := stripe.GetCheckoutURLs(r)
 
-	checkoutURL, err := h.Service.CheckoutCreditPackage(r.Context(), id, successURL, cancelURL)
+	useBalance := r.URL.Query().Get("use_balance") == "true"
+
+	checkoutURL, err := h.Service.CheckoutCreditPackage(r.Context(), id, useBalance, successURL, cancelURL)
 	if err != nil {
 		responseHandlers.RespondWithError(w, err)
 		return
@@ -68,11 +68,12 @@ func (r *CreditPackageRepository) GetAll(ctx context.Context) ([]dto.CreditPacka
 
 func (r *CreditPackageRepository) Create(ctx context.Context, req dto.CreateCreditPackageRequest) (*dto.CreditPackageResponse, *errLib.CommonError) {
 	pkg, err := r.Queries.CreateCreditPackage(ctx, db.CreateCreditPackageParams{
-		Name:              req.Name,
-		Description:       sql.NullString{String: req.Description, Valid: req.Description != ""},
-		StripePriceID:     req.StripePriceID,
-		CreditAllocation:  req.CreditAllocation,
-		WeeklyCreditLimit: req.WeeklyCreditLimit,
+		Name:               req.Name,
+		Description:        sql.NullString{String: req.Description, Valid: req.Description != ""},
+		StripePriceID:      req.StripePriceID,
+		CreditAllocation:   req.CreditAllocation,
+		WeeklyCreditLimit:  req.WeeklyCreditLimit,
+		CreditValidityDays: toNullInt32(req.CreditValidityDays),
 	})
 	if err != nil {
 		log.Printf("Failed to create credit package: %v", err)
@@ -84,12 +85,13 @@ func (r *CreditPackageRepository) Create(ctx context.Context, req dto.CreateCred
 
 func (r *CreditPackageRepository) Update(ctx context.Context, id uuid.UUID, req dto.UpdateCreditPackageRequest) (*dto.CreditPackageResponse, *errLib.CommonError) {
 	pkg, err := r.Queries.UpdateCreditPackage(ctx, db.UpdateCreditPackageParams{
-		ID:                id,
-		Name:              req.Name,
-		Description:       sql.NullString{String: req.Description, Valid: req.Description != ""},
-		StripePriceID:     req.StripePriceID,
-		CreditAllocation:  req.CreditAllocation,
-		WeeklyCreditLimit: req.WeeklyCreditLimit,
+		ID:                 id,
+		Name:               req.Name,
+		Description:        sql.NullString{String: req.Description, Valid: req.Description != ""},
+		StripePriceID:      req.StripePriceID,
+		CreditAllocation:   req.CreditAllocation,
+		WeeklyCreditLimit:  req.WeeklyCreditLimit,
+		CreditValidityDays: toNullInt32(req.CreditValidityDays),
 	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -152,14 +154,27 @@ func (r *CreditPackageRepository) mapToResponse(pkg *db.UsersCreditPackage) *dto
 		description = &pkg.Description.String
 	}
 
+	var validityDays *int32
+	if pkg.CreditValidityDays.Valid {
+		validityDays = &pkg.CreditValidityDays.Int32
+	}
+
 	return &dto.CreditPackageResponse{
-		ID:                pkg.ID,
-		Name:              pkg.Name,
-		Description:       description,
-		StripePriceID:     pkg.StripePriceID,
-		CreditAllocation:  pkg.CreditAllocation,
-		WeeklyCreditLimit: pkg.WeeklyCreditLimit,
-		CreatedAt:         pkg.CreatedAt,
-		UpdatedAt:         pkg.UpdatedAt,
+		ID:                 pkg.ID,
+		Name:               pkg.Name,
+		Description:        description,
+		StripePriceID:      pkg.StripePriceID,
+		CreditAllocation:   pkg.CreditAllocation,
+		WeeklyCreditLimit:  pkg.WeeklyCreditLimit,
+		CreditValidityDays: validityDays,
+		CreatedAt:          pkg.CreatedAt,
+		UpdatedAt:          pkg.UpdatedAt,
+	}
+}
+
+func toNullInt32(v *int32) sql.NullInt32 {
+	if v == nil {
+		return sql.NullInt32{}
 	}
+	return sql.NullInt32{Int32: *v, Valid: true}
 }
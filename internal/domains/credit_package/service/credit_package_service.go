@@ -9,6 +9,9 @@ import (
 	"api/internal/di"
 	dto "api/internal/domains/credit_package/dto"
 	repo "api/internal/domains/credit_package/persistence/repository"
+	giftCardService "api/internal/domains/gift_card/service"
+	giftCardValues "api/internal/domains/gift_card/values"
+	paymentService "api/internal/domains/payment/services"
 	"api/internal/domains/payment/services/stripe"
 	userServices "api/internal/domains/user/services"
 	errLib "api/internal/libs/errors"
@@ -22,6 +25,8 @@ type CreditPackageService struct {
 	CreditService     *userServices.CustomerCreditService
 	StripeService     *stripe.PriceService
 	ProductService    *stripe.ProductService
+	TaxService        *paymentService.TaxService
+	GiftCardService   *giftCardService.Service
 	DB                *sql.DB
 }
 
@@ -31,6 +36,8 @@ func NewCreditPackageService(container *di.Container) *CreditPackageService {
 		CreditService:     userServices.NewCustomerCreditService(container),
 		StripeService:     stripe.NewPriceService(),
 		ProductService:    stripe.NewProductService(),
+		TaxService:        paymentService.NewTaxService(container),
+		GiftCardService:   giftCardService.NewService(container),
 		DB:                container.DB,
 	}
 }
@@ -99,7 +106,8 @@ func (s *CreditPackageService) GetPackageByID(ctx context.Context, id uuid.UUID)
 }
 
 // CheckoutCreditPackage creates a Stripe checkout session for purchasing a credit package
-func (s *CreditPackageService) CheckoutCreditPackage(ctx context.Context, packageID uuid.UUID, successURL string, cancelURL string) (string, *errLib.CommonError) {
+// and, when useBalance is set, pays what it can from the customer's gift card balance
+func (s *CreditPackageService) CheckoutCreditPackage(ctx context.Context, packageID uuid.UUID, useBalance bool, successURL string, cancelURL string) (string, *errLib.CommonError) {
 	// Get customer ID from context
 	customerID, err := contextUtils.GetUserID(ctx)
 	if err != nil {
@@ -126,18 +134,69 @@ func (s *CreditPackageService) CheckoutCreditPackage(ctx context.Context, packag
 	// Get existing Stripe customer ID to reuse (industry standard: one user = one Stripe customer)
 	existingCustomerID := s.getExistingStripeCustomerID(ctx, customerID)
 
+	// Credit packages are not tied to a facility, so they are taxed in the default province
+	taxRateIDs, err := s.TaxService.TaxRateIDsForLocation(ctx, uuid.NullUUID{})
+	if err != nil {
+		return "", err
+	}
+
+	var hold giftCardValues.Hold
+	var stripeCouponID *string
+	var holdMetadata map[string]string
+	if useBalance {
+		if hold, stripeCouponID, err = s.holdBalance(ctx, customerID, pkg.StripePriceID); err != nil {
+			return "", err
+		}
+		if stripeCouponID != nil {
+			holdMetadata = map[string]string{stripe.StoredValueHoldMetadataKey: hold.ID.String()}
+		}
+	}
+
 	// Create Stripe checkout session for one-time payment
 	// Pass packageID in metadata so webhook can identify the purchase
 	packageIDStr := packageID.String()
-	checkoutURL, err := stripe.CreateOneTimePayment(ctx, pkg.StripePriceID, 1, &packageIDStr, nil, successURL, cancelURL, existingCustomerID)
+	checkoutURL, err := stripe.CreateOneTimePaymentWithMetadata(ctx, pkg.StripePriceID, 1, &packageIDStr, stripeCouponID, holdMetadata, hold.ExpiresAt, successURL, cancelURL, existingCustomerID, taxRateIDs)
 	if err != nil {
 		log.Printf("Failed to create Stripe checkout session: %v", err)
+		s.releaseHold(ctx, hold)
 		return "", err
 	}
 
 	return checkoutURL, nil
 }
 
+// holdBalance holds as much of the customer's gift card balance as covers the package
+// price and returns the single-use coupon taking it off the checkout. The coupon is nil
+// when the customer has no balance.
+func (s *CreditPackageService) holdBalance(ctx context.Context, customerID uuid.UUID, stripePriceID string) (giftCardValues.Hold, *string, *errLib.CommonError) {
+	price, err := s.StripeService.GetPrice(stripePriceID)
+	if err != nil {
+		return giftCardValues.Hold{}, nil, err
+	}
+
+	hold, err := s.GiftCardService.HoldBalance(ctx, customerID, int32(price.UnitAmount), "Credit package")
+	if err != nil || hold.ID == uuid.Nil {
+		return hold, nil, err
+	}
+
+	couponID, err := stripe.CreateStoredValueCoupon(ctx, int64(hold.AmountCents), string(price.Currency), hold.ExpiresAt)
+	if err != nil {
+		s.releaseHold(ctx, hold)
+		return giftCardValues.Hold{}, nil, err
+	}
+	return hold, &couponID, nil
+}
+
+// releaseHold returns balance held for a checkout that could not be started
+func (s *CreditPackageService) releaseHold(ctx context.Context, hold giftCardValues.Hold) {
+	if hold.ID == uuid.Nil {
+		return
+	}
+	if err := s.GiftCardService.ReleaseHold(ctx, hold.ID); err != nil {
+		log.Printf("Failed to release gift card balance hold %s: %v", hold.ID, err)
+	}
+}
+
 // GetCustomerActivePackage returns the customer's currently active credit package
 func (s *CreditPackageService) GetCustomerActivePackage(ctx context.Context, customerID uuid.UUID) (*dto.CustomerActiveCreditPackageResponse, *errLib.CommonError) {
 	return s.CreditPackageRepo.GetCustomerActivePackage(ctx, customerID)
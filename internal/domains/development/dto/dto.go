@@ -0,0 +1,437 @@
+package development
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	values "api/internal/domains/development/values"
+	errLib "api/internal/libs/errors"
+	"api/internal/libs/validators"
+	"api/utils/timezone"
+
+	"github.com/google/uuid"
+)
+
+const (
+	dateLayout = "2006-01-02"
+
+	defaultScaleMin = 1
+	defaultScaleMax = 5
+	// maxScaleSteps bounds how many ratings a skill's scale can have
+	maxScaleSteps = 100
+)
+
+// SkillRequestDto is one rated skill on a template. The scale defaults to 1-5.
+type SkillRequestDto struct {
+	Name        string  `json:"name" validate:"required,notwhitespace,max=100" example:"Ball handling"`
+	Category    *string `json:"category,omitempty" validate:"omitempty,max=50" example:"Offense"`
+	Description *string `json:"description,omitempty" validate:"omitempty,max=500" example:"Control with both hands at speed"`
+	ScaleMin    *int32  `json:"scale_min,omitempty" example:"1"`
+	ScaleMax    *int32  `json:"scale_max,omitempty" example:"5"`
+}
+
+// TemplateRequestDto creates or edits an evaluation template. On edits, leaving out
+// skills keeps the current ones; skills can only be replaced until the template is used.
+type TemplateRequestDto struct {
+	Name        string            `json:"name" validate:"required,notwhitespace,max=150" example:"U12 Skills Assessment"`
+	Description *string           `json:"description,omitempty" validate:"omitempty,max=2000"`
+	ProgramID   *uuid.UUID        `json:"program_id,omitempty"`
+	IsActive    *bool             `json:"is_active,omitempty" example:"true"`
+	Skills      []SkillRequestDto `json:"skills,omitempty" validate:"omitempty,dive"`
+}
+
+type ScoreRequestDto struct {
+	SkillID uuid.UUID `json:"skill_id" validate:"required"`
+	Rating  int32     `json:"rating" example:"4"`
+	Comment *string   `json:"comment,omitempty" validate:"omitempty,max=1000" example:"Much stronger off the left hand"`
+}
+
+// EvaluationRequestDto submits or edits a coach evaluation. Set publish to make it
+// visible to the athlete's family; published evaluations stay published.
+type EvaluationRequestDto struct {
+	TemplateID  uuid.UUID         `json:"template_id" example:"f47ac10b-58cc-4372-a567-0e02b2c3d479"` // Ignored on edits
+	ProgramID   *uuid.UUID        `json:"program_id,omitempty"`                                       // Defaults to the template's program
+	Season      *string           `json:"season,omitempty" validate:"omitempty,max=50" example:"Fall 2026"`
+	EvaluatedOn string            `json:"evaluated_on" validate:"required" example:"2026-10-05"` // YYYY-MM-DD
+	Summary     *string           `json:"summary,omitempty" validate:"omitempty,max=5000"`
+	Scores      []ScoreRequestDto `json:"scores" validate:"required,min=1,dive"`
+	Publish     bool              `json:"publish" example:"false"`
+}
+
+// GoalRequestDto sets or edits a development goal. target_rating needs a skill_id.
+type GoalRequestDto struct {
+	SkillID      *uuid.UUID `json:"skill_id,omitempty"`
+	Title        string     `json:"title" validate:"required,notwhitespace,max=150" example:"Make 7 of 10 free throws"`
+	Description  *string    `json:"description,omitempty" validate:"omitempty,max=2000"`
+	TargetRating *int32     `json:"target_rating,omitempty" example:"4"`
+	TargetDate   *string    `json:"target_date,omitempty" example:"2026-12-15"` // YYYY-MM-DD
+	Status       string     `json:"status,omitempty" validate:"omitempty,oneof=active achieved dropped" example:"active"`
+}
+
+// ToValues validates a template. requireSkills is set when creating one.
+func (dto TemplateRequestDto) ToValues(requireSkills bool) (values.TemplateDetails, *errLib.CommonError) {
+	if err := validators.ValidateDto(&dto); err != nil {
+		return values.TemplateDetails{}, err
+	}
+	if requireSkills && len(dto.Skills) == 0 {
+		return values.TemplateDetails{}, errLib.New("A template needs at least one skill", http.StatusBadRequest)
+	}
+
+	details := values.TemplateDetails{
+		Name:        dto.Name,
+		Description: dto.Description,
+		ProgramID:   dto.ProgramID,
+		IsActive:    dto.IsActive == nil || *dto.IsActive,
+	}
+
+	if dto.Skills != nil {
+		details.Skills = make([]values.Skill, len(dto.Skills))
+	}
+	for i, skill := range dto.Skills {
+		scaleMin, scaleMax := int32(defaultScaleMin), int32(defaultScaleMax)
+		if skill.ScaleMin != nil {
+			scaleMin = *skill.ScaleMin
+		}
+		if skill.ScaleMax != nil {
+			scaleMax = *skill.ScaleMax
+		}
+		if scaleMin < 0 || scaleMax <= scaleMin || scaleMax-scaleMin > maxScaleSteps {
+			return values.TemplateDetails{}, errLib.New(fmt.Sprintf("skills[%d]: scale_max must be greater than scale_min, both from 0 and at most %d apart", i, maxScaleSteps), http.StatusBadRequest)
+		}
+
+		details.Skills[i] = values.Skill{
+			Name:        skill.Name,
+			Category:    skill.Category,
+			Description: skill.Description,
+			ScaleMin:    scaleMin,
+			ScaleMax:    scaleMax,
+		}
+	}
+	return details, nil
+}
+
+// ToValues validates an evaluation. Ratings are checked against the template's
+// scales by the service.
+func (dto EvaluationRequestDto) ToValues() (values.EvaluationDetails, *errLib.CommonError) {
+	if err := validators.ValidateDto(&dto); err != nil {
+		return values.EvaluationDetails{}, err
+	}
+
+	evaluatedOn, err := validators.ParseDate(dto.EvaluatedOn)
+	if err != nil {
+		return values.EvaluationDetails{}, err
+	}
+	if evaluatedOn.After(today()) {
+		return values.EvaluationDetails{}, errLib.New("evaluated_on cannot be in the future", http.StatusBadRequest)
+	}
+
+	scores := make([]values.ScoreInput, len(dto.Scores))
+	seen := make(map[uuid.UUID]bool, len(dto.Scores))
+	for i, score := range dto.Scores {
+		if seen[score.SkillID] {
+			return values.EvaluationDetails{}, errLib.New(fmt.Sprintf("scores[%d]: skill is rated more than once", i), http.StatusBadRequest)
+		}
+		seen[score.SkillID] = true
+		scores[i] = values.ScoreInput{SkillID: score.SkillID, Rating: score.Rating, Comment: score.Comment}
+	}
+
+	return values.EvaluationDetails{
+		TemplateID:  dto.TemplateID,
+		ProgramID:   dto.ProgramID,
+		Season:      dto.Season,
+		EvaluatedOn: evaluatedOn,
+		Summary:     dto.Summary,
+		Scores:      scores,
+		Publish:     dto.Publish,
+	}, nil
+}
+
+func (dto GoalRequestDto) ToValues() (values.GoalDetails, *errLib.CommonError) {
+	if err := validators.ValidateDto(&dto); err != nil {
+		return values.GoalDetails{}, err
+	}
+	if dto.TargetRating != nil && dto.SkillID == nil {
+		return values.GoalDetails{}, errLib.New("target_rating needs a skill_id", http.StatusBadRequest)
+	}
+
+	details := values.GoalDetails{
+		SkillID:      dto.SkillID,
+		Title:        dto.Title,
+		Description:  dto.Description,
+		TargetRating: dto.TargetRating,
+		Status:       values.GoalStatus(dto.Status),
+	}
+	if details.Status == "" {
+		details.Status = values.GoalActive
+	}
+
+	if dto.TargetDate != nil {
+		targetDate, err := validators.ParseDate(*dto.TargetDate)
+		if err != nil {
+			return values.GoalDetails{}, err
+		}
+		details.TargetDate = &targetDate
+	}
+	return details, nil
+}
+
+// today is the current date at the facility, as midnight UTC like parsed dates
+func today() time.Time {
+	now := time.Now().In(timezone.Default())
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+type SkillResponseDto struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Category    *string   `json:"category,omitempty"`
+	Description *string   `json:"description,omitempty"`
+	ScaleMin    int32     `json:"scale_min"`
+	ScaleMax    int32     `json:"scale_max"`
+}
+
+type TemplateResponseDto struct {
+	ID          uuid.UUID          `json:"id"`
+	Name        string             `json:"name"`
+	Description *string            `json:"description,omitempty"`
+	ProgramID   *uuid.UUID         `json:"program_id,omitempty"`
+	IsActive    bool               `json:"is_active"`
+	Skills      []SkillResponseDto `json:"skills"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
+
+type ScoreResponseDto struct {
+	SkillID   uuid.UUID `json:"skill_id"`
+	SkillName string    `json:"skill_name"`
+	Category  *string   `json:"category,omitempty"`
+	ScaleMin  int32     `json:"scale_min"`
+	ScaleMax  int32     `json:"scale_max"`
+	Rating    int32     `json:"rating"`
+	Comment   *string   `json:"comment,omitempty" audit:"personal"`
+}
+
+type EvaluationResponseDto struct {
+	ID           uuid.UUID          `json:"id"`
+	AthleteID    uuid.UUID          `json:"athlete_id"`
+	TemplateID   uuid.UUID          `json:"template_id"`
+	TemplateName string             `json:"template_name"`
+	ProgramID    *uuid.UUID         `json:"program_id,omitempty"`
+	ProgramName  *string            `json:"program_name,omitempty"`
+	Season       *string            `json:"season,omitempty"`
+	EvaluatedOn  string             `json:"evaluated_on"`
+	Summary      *string            `json:"summary,omitempty" audit:"personal"`
+	Status       string             `json:"status"`
+	CoachID      *uuid.UUID         `json:"coach_id,omitempty"`
+	CoachName    string             `json:"coach_name,omitempty" audit:"personal"`
+	PublishedAt  *time.Time         `json:"published_at,omitempty"`
+	Scores       []ScoreResponseDto `json:"scores"`
+	CreatedAt    time.Time          `json:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at"`
+}
+
+type TrendPointResponseDto struct {
+	EvaluationID uuid.UUID `json:"evaluation_id"`
+	EvaluatedOn  string    `json:"evaluated_on"`
+	Rating       int32     `json:"rating"`
+}
+
+type SkillTrendResponseDto struct {
+	SkillID   uuid.UUID               `json:"skill_id"`
+	SkillName string                  `json:"skill_name"`
+	Category  *string                 `json:"category,omitempty"`
+	ScaleMin  int32                   `json:"scale_min"`
+	ScaleMax  int32                   `json:"scale_max"`
+	First     int32                   `json:"first"`
+	Latest    int32                   `json:"latest"`
+	Change    int32                   `json:"change"`
+	Direction string                  `json:"direction" example:"improving"`
+	Points    []TrendPointResponseDto `json:"points"`
+}
+
+type GoalResponseDto struct {
+	ID           uuid.UUID  `json:"id"`
+	AthleteID    uuid.UUID  `json:"athlete_id"`
+	SkillID      *uuid.UUID `json:"skill_id,omitempty"`
+	SkillName    *string    `json:"skill_name,omitempty"`
+	Title        string     `json:"title" audit:"personal"`
+	Description  *string    `json:"description,omitempty" audit:"personal"`
+	TargetRating *int32     `json:"target_rating,omitempty"`
+	TargetDate   *string    `json:"target_date,omitempty"`
+	Status       string     `json:"status"`
+	AchievedAt   *time.Time `json:"achieved_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+type ProgressReportResponseDto struct {
+	ID              uuid.UUID  `json:"id"`
+	AthleteID       uuid.UUID  `json:"athlete_id"`
+	PeriodStart     string     `json:"period_start"`
+	PeriodEnd       string     `json:"period_end"`
+	EvaluationCount int32      `json:"evaluation_count"`
+	EmailedAt       *time.Time `json:"emailed_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// DevelopmentResponseDto is an athlete's progress as their family sees it: recent
+// published evaluations, skill trends and goals.
+type DevelopmentResponseDto struct {
+	Evaluations []EvaluationResponseDto `json:"evaluations"`
+	Trends      []SkillTrendResponseDto `json:"trends"`
+	Goals       []GoalResponseDto       `json:"goals"`
+}
+
+func NewTemplateResponse(t values.Template) TemplateResponseDto {
+	skills := make([]SkillResponseDto, len(t.Skills))
+	for i, s := range t.Skills {
+		skills[i] = SkillResponseDto{
+			ID:          s.ID,
+			Name:        s.Name,
+			Category:    s.Category,
+			Description: s.Description,
+			ScaleMin:    s.ScaleMin,
+			ScaleMax:    s.ScaleMax,
+		}
+	}
+	return TemplateResponseDto{
+		ID:          t.ID,
+		Name:        t.Name,
+		Description: t.Description,
+		ProgramID:   t.ProgramID,
+		IsActive:    t.IsActive,
+		Skills:      skills,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+	}
+}
+
+func NewTemplateResponses(templates []values.Template) []TemplateResponseDto {
+	resp := make([]TemplateResponseDto, len(templates))
+	for i, t := range templates {
+		resp[i] = NewTemplateResponse(t)
+	}
+	return resp
+}
+
+func NewEvaluationResponse(e values.Evaluation) EvaluationResponseDto {
+	scores := make([]ScoreResponseDto, len(e.Scores))
+	for i, s := range e.Scores {
+		scores[i] = ScoreResponseDto{
+			SkillID:   s.SkillID,
+			SkillName: s.SkillName,
+			Category:  s.Category,
+			ScaleMin:  s.ScaleMin,
+			ScaleMax:  s.ScaleMax,
+			Rating:    s.Rating,
+			Comment:   s.Comment,
+		}
+	}
+	return EvaluationResponseDto{
+		ID:           e.ID,
+		AthleteID:    e.AthleteID,
+		TemplateID:   e.TemplateID,
+		TemplateName: e.TemplateName,
+		ProgramID:    e.ProgramID,
+		ProgramName:  e.ProgramName,
+		Season:       e.Season,
+		EvaluatedOn:  e.EvaluatedOn.Format(dateLayout),
+		Summary:      e.Summary,
+		Status:       string(e.Status),
+		CoachID:      e.CoachID,
+		CoachName:    e.CoachName,
+		PublishedAt:  e.PublishedAt,
+		Scores:       scores,
+		CreatedAt:    e.CreatedAt,
+		UpdatedAt:    e.UpdatedAt,
+	}
+}
+
+func NewEvaluationResponses(evaluations []values.Evaluation) []EvaluationResponseDto {
+	resp := make([]EvaluationResponseDto, len(evaluations))
+	for i, e := range evaluations {
+		resp[i] = NewEvaluationResponse(e)
+	}
+	return resp
+}
+
+func NewTrendResponses(trends []values.SkillTrend) []SkillTrendResponseDto {
+	resp := make([]SkillTrendResponseDto, len(trends))
+	for i, t := range trends {
+		points := make([]TrendPointResponseDto, len(t.Points))
+		for j, p := range t.Points {
+			points[j] = TrendPointResponseDto{
+				EvaluationID: p.EvaluationID,
+				EvaluatedOn:  p.EvaluatedOn.Format(dateLayout),
+				Rating:       p.Rating,
+			}
+		}
+		resp[i] = SkillTrendResponseDto{
+			SkillID:   t.SkillID,
+			SkillName: t.SkillName,
+			Category:  t.Category,
+			ScaleMin:  t.ScaleMin,
+			ScaleMax:  t.ScaleMax,
+			First:     t.First,
+			Latest:    t.Latest,
+			Change:    t.Change,
+			Direction: string(t.Direction),
+			Points:    points,
+		}
+	}
+	return resp
+}
+
+func NewGoalResponse(g values.Goal) GoalResponseDto {
+	resp := GoalResponseDto{
+		ID:           g.ID,
+		AthleteID:    g.AthleteID,
+		SkillID:      g.SkillID,
+		SkillName:    g.SkillName,
+		Title:        g.Title,
+		Description:  g.Description,
+		TargetRating: g.TargetRating,
+		Status:       string(g.Status),
+		AchievedAt:   g.AchievedAt,
+		CreatedAt:    g.CreatedAt,
+		UpdatedAt:    g.UpdatedAt,
+	}
+	if g.TargetDate != nil {
+		targetDate := g.TargetDate.Format(dateLayout)
+		resp.TargetDate = &targetDate
+	}
+	return resp
+}
+
+func NewGoalResponses(goals []values.Goal) []GoalResponseDto {
+	resp := make([]GoalResponseDto, len(goals))
+	for i, g := range goals {
+		resp[i] = NewGoalResponse(g)
+	}
+	return resp
+}
+
+func NewProgressReportResponses(reports []values.ProgressReport) []ProgressReportResponseDto {
+	resp := make([]ProgressReportResponseDto, len(reports))
+	for i, r := range reports {
+		resp[i] = ProgressReportResponseDto{
+			ID:              r.ID,
+			AthleteID:       r.AthleteID,
+			PeriodStart:     r.PeriodStart.Format(dateLayout),
+			PeriodEnd:       r.PeriodEnd.Format(dateLayout),
+			EvaluationCount: r.EvaluationCount,
+			EmailedAt:       r.EmailedAt,
+			CreatedAt:       r.CreatedAt,
+		}
+	}
+	return resp
+}
+
+func NewDevelopmentResponse(d values.Development) *DevelopmentResponseDto {
+	return &DevelopmentResponseDto{
+		Evaluations: NewEvaluationResponses(d.Evaluations),
+		Trends:      NewTrendResponses(d.Trends),
+		Goals:       NewGoalResponses(d.Goals),
+	}
+}
@@ -0,0 +1,580 @@
+package development
+
+import (
+	"net/http"
+	"strconv"
+
+	"api/internal/di"
+	dto "api/internal/domains/development/dto"
+	service "api/internal/domains/development/service"
+	responseHandlers "api/internal/libs/responses"
+	"api/internal/libs/validators"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+type Handler struct {
+	Service *service.Service
+}
+
+func NewHandler(container *di.Container) *Handler {
+	return &Handler{Service: service.NewService(container)}
+}
+
+// GetTemplates lists evaluation templates with their skills.
+// @Tags Athlete Development
+// @Produce json
+// @Security Bearer
+// @Param include_inactive query bool false "Include templates no longer in use"
+// @Param program_id query string false "Only templates for this program, plus general ones"
+// @Success 200 {array} dto.TemplateResponseDto "Evaluation templates"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid program ID"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /development/templates [get]
+func (h *Handler) GetTemplates(w http.ResponseWriter, r *http.Request) {
+	includeInactive := r.URL.Query().Get("include_inactive") == "true"
+
+	var programID *uuid.UUID
+	if idStr := r.URL.Query().Get("program_id"); idStr != "" {
+		id, err := validators.ParseUUID(idStr)
+		if err != nil {
+			responseHandlers.RespondWithError(w, err)
+			return
+		}
+		programID = &id
+	}
+
+	templates, err := h.Service.ListTemplates(r.Context(), includeInactive, programID)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewTemplateResponses(templates), http.StatusOK)
+}
+
+// GetTemplate returns an evaluation template with its skills.
+// @Tags Athlete Development
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Template ID"
+// @Success 200 {object} dto.TemplateResponseDto "Evaluation template"
+// @Failure 404 {object} map[string]interface{} "Not Found: Template not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /development/templates/{id} [get]
+func (h *Handler) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	template, err := h.Service.GetTemplate(r.Context(), id)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewTemplateResponse(template), http.StatusOK)
+}
+
+// CreateTemplate creates an evaluation template.
+// @Description Each skill is rated on its own scale, 1 to 5 unless scale_min and scale_max are given.
+// @Tags Athlete Development
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body dto.TemplateRequestDto true "Template"
+// @Success 201 {object} dto.TemplateResponseDto "Template created"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid template"
+// @Failure 404 {object} map[string]interface{} "Not Found: Program not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /development/templates [post]
+func (h *Handler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	var body dto.TemplateRequestDto
+	if err := validators.ParseJSON(r.Body, &body); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	details, err := body.ToValues(true)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	created, err := h.Service.CreateTemplate(r.Context(), details)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewTemplateResponse(created), http.StatusCreated)
+}
+
+// UpdateTemplate edits an evaluation template.
+// @Description Leave out skills to keep the current ones. Skills cannot be replaced once coaches
+// @Description have used the template; deactivate it and create a new one instead.
+// @Tags Athlete Development
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Template ID"
+// @Param request body dto.TemplateRequestDto true "Template"
+// @Success 200 {object} dto.TemplateResponseDto "Template updated"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid template"
+// @Failure 404 {object} map[string]interface{} "Not Found: Template or program not found"
+// @Failure 409 {object} map[string]interface{} "Conflict: Template already used"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /development/templates/{id} [put]
+func (h *Handler) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	var body dto.TemplateRequestDto
+	if err = validators.ParseJSON(r.Body, &body); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	details, err := body.ToValues(false)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	updated, err := h.Service.UpdateTemplate(r.Context(), id, details)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewTemplateResponse(updated), http.StatusOK)
+}
+
+// GetAthleteEvaluations lists an athlete's evaluations, drafts included, newest first.
+// @Tags Athlete Development
+// @Produce json
+// @Security Bearer
+// @Param athlete_id path string true "Athlete ID"
+// @Param limit query int false "Number of records (default 20, max 100)"
+// @Param offset query int false "Number of records to skip"
+// @Success 200 {array} dto.EvaluationResponseDto "Evaluations"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Not one of the coach's athletes"
+// @Failure 404 {object} map[string]interface{} "Not Found: Athlete not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /development/athletes/{athlete_id}/evaluations [get]
+func (h *Handler) GetAthleteEvaluations(w http.ResponseWriter, r *http.Request) {
+	athleteID, err := validators.ParseUUID(chi.URLParam(r, "athlete_id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	limit := 20
+	offset := 0
+
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsed, parseErr := strconv.Atoi(limitStr); parseErr == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if parsed, parseErr := strconv.Atoi(offsetStr); parseErr == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+
+	evaluations, err := h.Service.ListEvaluations(r.Context(), athleteID, int32(limit), int32(offset))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewEvaluationResponses(evaluations), http.StatusOK)
+}
+
+// CreateEvaluation records a coach's evaluation of an athlete.
+// @Description Saved as a draft unless publish is set. Ratings must fall within each skill's scale;
+// @Description skills can be left unrated.
+// @Tags Athlete Development
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param athlete_id path string true "Athlete ID"
+// @Param request body dto.EvaluationRequestDto true "Evaluation"
+// @Success 201 {object} dto.EvaluationResponseDto "Evaluation created"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid evaluation"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Not one of the coach's athletes"
+// @Failure 404 {object} map[string]interface{} "Not Found: Athlete, template or program not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /development/athletes/{athlete_id}/evaluations [post]
+func (h *Handler) CreateEvaluation(w http.ResponseWriter, r *http.Request) {
+	athleteID, err := validators.ParseUUID(chi.URLParam(r, "athlete_id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	var body dto.EvaluationRequestDto
+	if err = validators.ParseJSON(r.Body, &body); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	details, err := body.ToValues()
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	created, err := h.Service.CreateEvaluation(r.Context(), athleteID, details)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewEvaluationResponse(created), http.StatusCreated)
+}
+
+// GetEvaluation returns an evaluation with its scores.
+// @Tags Athlete Development
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Evaluation ID"
+// @Success 200 {object} dto.EvaluationResponseDto "Evaluation"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Not one of the coach's athletes"
+// @Failure 404 {object} map[string]interface{} "Not Found: Evaluation not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /development/evaluations/{id} [get]
+func (h *Handler) GetEvaluation(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	evaluation, err := h.Service.GetEvaluation(r.Context(), id)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewEvaluationResponse(evaluation), http.StatusOK)
+}
+
+// UpdateEvaluation edits an evaluation and replaces its scores.
+// @Description Coaches can edit their own evaluations and admins any. template_id is ignored, and
+// @Description published evaluations stay published.
+// @Tags Athlete Development
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Evaluation ID"
+// @Param request body dto.EvaluationRequestDto true "Evaluation"
+// @Success 200 {object} dto.EvaluationResponseDto "Evaluation updated"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid evaluation"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Not the evaluation's coach"
+// @Failure 404 {object} map[string]interface{} "Not Found: Evaluation not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /development/evaluations/{id} [put]
+func (h *Handler) UpdateEvaluation(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	var body dto.EvaluationRequestDto
+	if err = validators.ParseJSON(r.Body, &body); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	details, err := body.ToValues()
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	updated, err := h.Service.UpdateEvaluation(r.Context(), id, details)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewEvaluationResponse(updated), http.StatusOK)
+}
+
+// PublishEvaluation makes a draft evaluation visible to the athlete's family.
+// @Tags Athlete Development
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Evaluation ID"
+// @Success 200 {object} dto.EvaluationResponseDto "Evaluation published"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Not the evaluation's coach"
+// @Failure 404 {object} map[string]interface{} "Not Found: Evaluation not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /development/evaluations/{id}/publish [post]
+func (h *Handler) PublishEvaluation(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	published, err := h.Service.PublishEvaluation(r.Context(), id)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewEvaluationResponse(published), http.StatusOK)
+}
+
+// DeleteEvaluation removes an evaluation.
+// @Description Coaches can delete their own drafts; published evaluations can only be deleted by an admin.
+// @Tags Athlete Development
+// @Security Bearer
+// @Param id path string true "Evaluation ID"
+// @Success 204 "No Content"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Not allowed to delete this evaluation"
+// @Failure 404 {object} map[string]interface{} "Not Found: Evaluation not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /development/evaluations/{id} [delete]
+func (h *Handler) DeleteEvaluation(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	if err = h.Service.DeleteEvaluation(r.Context(), id); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, nil, http.StatusNoContent)
+}
+
+// GetAthleteTrends returns the rating history of every skill an athlete has been rated on.
+// @Description Built from published evaluations, oldest rating first.
+// @Tags Athlete Development
+// @Produce json
+// @Security Bearer
+// @Param athlete_id path string true "Athlete ID"
+// @Param template_id query string false "Only ratings from this template"
+// @Success 200 {array} dto.SkillTrendResponseDto "Skill trends"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Not one of the coach's athletes"
+// @Failure 404 {object} map[string]interface{} "Not Found: Athlete not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /development/athletes/{athlete_id}/trends [get]
+func (h *Handler) GetAthleteTrends(w http.ResponseWriter, r *http.Request) {
+	athleteID, err := validators.ParseUUID(chi.URLParam(r, "athlete_id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	var templateID *uuid.UUID
+	if idStr := r.URL.Query().Get("template_id"); idStr != "" {
+		id, err := validators.ParseUUID(idStr)
+		if err != nil {
+			responseHandlers.RespondWithError(w, err)
+			return
+		}
+		templateID = &id
+	}
+
+	trends, err := h.Service.GetTrends(r.Context(), athleteID, templateID)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewTrendResponses(trends), http.StatusOK)
+}
+
+// GetAthleteGoals lists an athlete's development goals, active ones first.
+// @Tags Athlete Development
+// @Produce json
+// @Security Bearer
+// @Param athlete_id path string true "Athlete ID"
+// @Success 200 {array} dto.GoalResponseDto "Goals"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Not one of the coach's athletes"
+// @Failure 404 {object} map[string]interface{} "Not Found: Athlete not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /development/athletes/{athlete_id}/goals [get]
+func (h *Handler) GetAthleteGoals(w http.ResponseWriter, r *http.Request) {
+	athleteID, err := validators.ParseUUID(chi.URLParam(r, "athlete_id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	goals, err := h.Service.ListGoals(r.Context(), athleteID)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewGoalResponses(goals), http.StatusOK)
+}
+
+// CreateGoal sets a development goal for an athlete.
+// @Tags Athlete Development
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param athlete_id path string true "Athlete ID"
+// @Param request body dto.GoalRequestDto true "Goal"
+// @Success 201 {object} dto.GoalResponseDto "Goal created"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid goal"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Not one of the coach's athletes"
+// @Failure 404 {object} map[string]interface{} "Not Found: Athlete or skill not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /development/athletes/{athlete_id}/goals [post]
+func (h *Handler) CreateGoal(w http.ResponseWriter, r *http.Request) {
+	athleteID, err := validators.ParseUUID(chi.URLParam(r, "athlete_id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	var body dto.GoalRequestDto
+	if err = validators.ParseJSON(r.Body, &body); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	details, err := body.ToValues()
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	created, err := h.Service.CreateGoal(r.Context(), athleteID, details)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewGoalResponse(created), http.StatusCreated)
+}
+
+// UpdateGoal edits a development goal, including marking it achieved or dropped.
+// @Tags Athlete Development
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Goal ID"
+// @Param request body dto.GoalRequestDto true "Goal"
+// @Success 200 {object} dto.GoalResponseDto "Goal updated"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid goal"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Not one of the coach's athletes"
+// @Failure 404 {object} map[string]interface{} "Not Found: Goal or skill not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /development/goals/{id} [put]
+func (h *Handler) UpdateGoal(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	var body dto.GoalRequestDto
+	if err = validators.ParseJSON(r.Body, &body); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	details, err := body.ToValues()
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	updated, err := h.Service.UpdateGoal(r.Context(), id, details)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewGoalResponse(updated), http.StatusOK)
+}
+
+// DeleteGoal removes a development goal.
+// @Tags Athlete Development
+// @Security Bearer
+// @Param id path string true "Goal ID"
+// @Success 204 "No Content"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Not one of the coach's athletes"
+// @Failure 404 {object} map[string]interface{} "Not Found: Goal not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /development/goals/{id} [delete]
+func (h *Handler) DeleteGoal(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	if err = h.Service.DeleteGoal(r.Context(), id); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, nil, http.StatusNoContent)
+}
+
+// GetAthleteProgressReports lists the progress reports issued to an athlete, newest first.
+// @Tags Athlete Development
+// @Produce json
+// @Security Bearer
+// @Param athlete_id path string true "Athlete ID"
+// @Success 200 {array} dto.ProgressReportResponseDto "Progress reports"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Not one of the coach's athletes"
+// @Failure 404 {object} map[string]interface{} "Not Found: Athlete not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /development/athletes/{athlete_id}/progress-reports [get]
+func (h *Handler) GetAthleteProgressReports(w http.ResponseWriter, r *http.Request) {
+	athleteID, err := validators.ParseUUID(chi.URLParam(r, "athlete_id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	reports, err := h.Service.ListProgressReports(r.Context(), athleteID)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewProgressReportResponses(reports), http.StatusOK)
+}
+
+// GetProgressReportPDF downloads a progress report
+// @Summary Download a progress report
+// @Description Download the PDF of an athlete's periodic progress report: the period's published evaluations, skill trends and goals
+// @Tags Athlete Development
+// @Produce application/pdf
+// @Param id path string true "Progress report ID"
+// @Success 200 {file} file "PDF progress report"
+// @Failure 400 {object} map[string]string "Invalid progress report ID"
+// @Failure 403 {object} map[string]string "Not one of the coach's athletes"
+// @Failure 404 {object} map[string]string "Progress report not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /development/progress-reports/{id}/pdf [get]
+func (h *Handler) GetProgressReportPDF(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	document, filename, err := h.Service.GetProgressReportPDF(r.Context(), id)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	writePDF(w, filename, document)
+}
+
+// writePDF sends a rendered document as a file download.
+func writePDF(w http.ResponseWriter, filename string, document []byte) {
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", "attachment; filename="+filename)
+	w.Header().Set("Content-Length", strconv.Itoa(len(document)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(document)
+}
@@ -0,0 +1,742 @@
+package development
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"api/internal/di"
+	db "api/internal/domains/development/persistence/sqlc/generated"
+	values "api/internal/domains/development/values"
+	errLib "api/internal/libs/errors"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Repository stores evaluation templates, coach evaluations, development goals and
+// the progress reports issued from them.
+type Repository struct {
+	Queries *db.Queries
+	Tx      *sql.Tx
+}
+
+func NewRepository(container *di.Container) *Repository {
+	return &Repository{Queries: container.Queries.DevelopmentDb}
+}
+
+func (r *Repository) GetTx() *sql.Tx { return r.Tx }
+
+func (r *Repository) WithTx(tx *sql.Tx) *Repository {
+	return &Repository{Queries: r.Queries.WithTx(tx), Tx: tx}
+}
+
+func nullUUID(id *uuid.UUID) uuid.NullUUID {
+	if id == nil {
+		return uuid.NullUUID{}
+	}
+	return uuid.NullUUID{UUID: *id, Valid: true}
+}
+
+func optionalUUID(id uuid.NullUUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
+	}
+	return &id.UUID
+}
+
+func nullString(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *s, Valid: true}
+}
+
+func optionalString(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
+}
+
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
+
+func optionalTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func nullDate(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+func constraintOf(err error) string {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Constraint
+	}
+	return ""
+}
+
+func (r *Repository) IsAthlete(ctx context.Context, athleteID uuid.UUID) (bool, *errLib.CommonError) {
+	exists, err := r.Queries.IsAthlete(ctx, athleteID)
+	if err != nil {
+		log.Printf("Failed to look up athlete %s: %v", athleteID, err)
+		return false, errLib.New("Failed to look up athlete", http.StatusInternalServerError)
+	}
+	return exists, nil
+}
+
+// CoachCanEvaluate reports whether a coach coaches the athlete's team or is staffed
+// on a program the athlete is enrolled in.
+func (r *Repository) CoachCanEvaluate(ctx context.Context, coachID, athleteID uuid.UUID) (bool, *errLib.CommonError) {
+	allowed, err := r.Queries.CoachCanEvaluate(ctx, db.CoachCanEvaluateParams{AthleteID: athleteID, CoachID: coachID})
+	if err != nil {
+		log.Printf("Failed to check coach %s access to athlete %s: %v", coachID, athleteID, err)
+		return false, errLib.New("Failed to check access to athlete", http.StatusInternalServerError)
+	}
+	return allowed, nil
+}
+
+func (r *Repository) GetAthleteContact(ctx context.Context, athleteID uuid.UUID) (values.AthleteContact, *errLib.CommonError) {
+	row, err := r.Queries.GetAthleteContact(ctx, athleteID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return values.AthleteContact{}, errLib.New("Athlete not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to get athlete %s: %v", athleteID, err)
+		return values.AthleteContact{}, errLib.New("Failed to get athlete", http.StatusInternalServerError)
+	}
+	return values.AthleteContact{
+		ID:              row.ID,
+		FirstName:       row.FirstName,
+		LastName:        row.LastName,
+		Email:           optionalString(row.Email),
+		ParentFirstName: optionalString(row.ParentFirstName),
+		ParentEmail:     optionalString(row.ParentEmail),
+	}, nil
+}
+
+// ----- Templates -----
+
+func (r *Repository) ListTemplates(ctx context.Context, activeOnly bool, programID *uuid.UUID) ([]values.Template, *errLib.CommonError) {
+	params := db.ListTemplatesParams{ProgramID: nullUUID(programID)}
+	if activeOnly {
+		params.IsActive = sql.NullBool{Bool: true, Valid: true}
+	}
+
+	rows, err := r.Queries.ListTemplates(ctx, params)
+	if err != nil {
+		log.Printf("Failed to list evaluation templates: %v", err)
+		return nil, errLib.New("Failed to list evaluation templates", http.StatusInternalServerError)
+	}
+
+	templates := make([]values.Template, len(rows))
+	ids := make([]uuid.UUID, len(rows))
+	for i, row := range rows {
+		templates[i] = mapTemplate(row)
+		ids[i] = row.ID
+	}
+	if err := r.attachSkills(ctx, templates, ids); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (r *Repository) GetTemplate(ctx context.Context, id uuid.UUID) (values.Template, *errLib.CommonError) {
+	row, err := r.Queries.GetTemplateById(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return values.Template{}, errLib.New("Evaluation template not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to get evaluation template %s: %v", id, err)
+		return values.Template{}, errLib.New("Failed to get evaluation template", http.StatusInternalServerError)
+	}
+
+	templates := []values.Template{mapTemplate(row)}
+	if err := r.attachSkills(ctx, templates, []uuid.UUID{id}); err != nil {
+		return values.Template{}, err
+	}
+	return templates[0], nil
+}
+
+func (r *Repository) attachSkills(ctx context.Context, templates []values.Template, ids []uuid.UUID) *errLib.CommonError {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	rows, err := r.Queries.ListSkillsByTemplateIds(ctx, ids)
+	if err != nil {
+		log.Printf("Failed to list evaluation skills: %v", err)
+		return errLib.New("Failed to list evaluation skills", http.StatusInternalServerError)
+	}
+
+	byTemplate := make(map[uuid.UUID][]values.Skill)
+	for _, row := range rows {
+		byTemplate[row.TemplateID] = append(byTemplate[row.TemplateID], mapSkill(row))
+	}
+	for i := range templates {
+		templates[i].Skills = byTemplate[templates[i].ID]
+		if templates[i].Skills == nil {
+			templates[i].Skills = []values.Skill{}
+		}
+	}
+	return nil
+}
+
+func (r *Repository) CreateTemplate(ctx context.Context, details values.TemplateDetails, createdBy uuid.UUID) (uuid.UUID, *errLib.CommonError) {
+	row, err := r.Queries.CreateTemplate(ctx, db.CreateTemplateParams{
+		Name:        details.Name,
+		Description: nullString(details.Description),
+		ProgramID:   nullUUID(details.ProgramID),
+		IsActive:    details.IsActive,
+		CreatedBy:   uuid.NullUUID{UUID: createdBy, Valid: createdBy != uuid.Nil},
+	})
+	if err != nil {
+		if constraintOf(err) == "evaluation_templates_program_id_fkey" {
+			return uuid.Nil, errLib.New("Program not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to create evaluation template: %v", err)
+		return uuid.Nil, errLib.New("Failed to create evaluation template", http.StatusInternalServerError)
+	}
+	return row.ID, nil
+}
+
+func (r *Repository) UpdateTemplate(ctx context.Context, id uuid.UUID, details values.TemplateDetails) *errLib.CommonError {
+	_, err := r.Queries.UpdateTemplate(ctx, db.UpdateTemplateParams{
+		ID:          id,
+		Name:        details.Name,
+		Description: nullString(details.Description),
+		ProgramID:   nullUUID(details.ProgramID),
+		IsActive:    details.IsActive,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errLib.New("Evaluation template not found", http.StatusNotFound)
+		}
+		if constraintOf(err) == "evaluation_templates_program_id_fkey" {
+			return errLib.New("Program not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to update evaluation template %s: %v", id, err)
+		return errLib.New("Failed to update evaluation template", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (r *Repository) TemplateHasEvaluations(ctx context.Context, id uuid.UUID) (bool, *errLib.CommonError) {
+	used, err := r.Queries.TemplateHasEvaluations(ctx, id)
+	if err != nil {
+		log.Printf("Failed to check evaluations of template %s: %v", id, err)
+		return false, errLib.New("Failed to check evaluation template", http.StatusInternalServerError)
+	}
+	return used, nil
+}
+
+// ReplaceSkills swaps a template's skills for a new set. Callers must make sure no
+// evaluation has scored the old skills.
+func (r *Repository) ReplaceSkills(ctx context.Context, templateID uuid.UUID, skills []values.Skill) *errLib.CommonError {
+	if err := r.Queries.DeleteSkillsByTemplate(ctx, templateID); err != nil {
+		log.Printf("Failed to clear skills of template %s: %v", templateID, err)
+		return errLib.New("Failed to update evaluation skills", http.StatusInternalServerError)
+	}
+
+	for i, skill := range skills {
+		_, err := r.Queries.CreateSkill(ctx, db.CreateSkillParams{
+			TemplateID:  templateID,
+			Name:        skill.Name,
+			Category:    nullString(skill.Category),
+			Description: nullString(skill.Description),
+			ScaleMin:    skill.ScaleMin,
+			ScaleMax:    skill.ScaleMax,
+			SortOrder:   int32(i),
+		})
+		if err != nil {
+			switch constraintOf(err) {
+			case "unique_evaluation_skill_name":
+				return errLib.New("Skill names must be unique within a template: "+skill.Name, http.StatusBadRequest)
+			case "check_evaluation_skill_scale":
+				return errLib.New("Rating scale maximum must be greater than its minimum: "+skill.Name, http.StatusBadRequest)
+			}
+			log.Printf("Failed to create skill %q on template %s: %v", skill.Name, templateID, err)
+			return errLib.New("Failed to update evaluation skills", http.StatusInternalServerError)
+		}
+	}
+	return nil
+}
+
+func mapTemplate(row db.AthleticEvaluationTemplate) values.Template {
+	return values.Template{
+		ID:          row.ID,
+		Name:        row.Name,
+		Description: optionalString(row.Description),
+		ProgramID:   optionalUUID(row.ProgramID),
+		IsActive:    row.IsActive,
+		CreatedBy:   optionalUUID(row.CreatedBy),
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+	}
+}
+
+func mapSkill(row db.AthleticEvaluationSkill) values.Skill {
+	return values.Skill{
+		ID:          row.ID,
+		TemplateID:  row.TemplateID,
+		Name:        row.Name,
+		Category:    optionalString(row.Category),
+		Description: optionalString(row.Description),
+		ScaleMin:    row.ScaleMin,
+		ScaleMax:    row.ScaleMax,
+		SortOrder:   row.SortOrder,
+	}
+}
+
+// ----- Evaluations -----
+
+func (r *Repository) GetEvaluation(ctx context.Context, id uuid.UUID) (values.Evaluation, *errLib.CommonError) {
+	row, err := r.Queries.GetEvaluationById(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return values.Evaluation{}, errLib.New("Evaluation not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to get evaluation %s: %v", id, err)
+		return values.Evaluation{}, errLib.New("Failed to get evaluation", http.StatusInternalServerError)
+	}
+
+	evaluations := []values.Evaluation{mapEvaluation(db.ListEvaluationsByAthleteRow(row))}
+	if err := r.attachScores(ctx, evaluations); err != nil {
+		return values.Evaluation{}, err
+	}
+	return evaluations[0], nil
+}
+
+// ListEvaluations returns an athlete's evaluations with their scores, newest first.
+func (r *Repository) ListEvaluations(ctx context.Context, filter values.EvaluationFilter) ([]values.Evaluation, *errLib.CommonError) {
+	rows, err := r.Queries.ListEvaluationsByAthlete(ctx, db.ListEvaluationsByAthleteParams{
+		AthleteID:     filter.AthleteID,
+		IncludeDrafts: filter.IncludeDrafts,
+		From:          nullDate(filter.From),
+		To:            nullDate(filter.To),
+		Limit:         filter.Limit,
+		Offset:        filter.Offset,
+	})
+	if err != nil {
+		log.Printf("Failed to list evaluations of athlete %s: %v", filter.AthleteID, err)
+		return nil, errLib.New("Failed to list evaluations", http.StatusInternalServerError)
+	}
+
+	evaluations := make([]values.Evaluation, len(rows))
+	for i, row := range rows {
+		evaluations[i] = mapEvaluation(row)
+	}
+	if err := r.attachScores(ctx, evaluations); err != nil {
+		return nil, err
+	}
+	return evaluations, nil
+}
+
+func (r *Repository) attachScores(ctx context.Context, evaluations []values.Evaluation) *errLib.CommonError {
+	if len(evaluations) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(evaluations))
+	for i, e := range evaluations {
+		ids[i] = e.ID
+	}
+
+	rows, err := r.Queries.ListScoresByEvaluationIds(ctx, ids)
+	if err != nil {
+		log.Printf("Failed to list evaluation scores: %v", err)
+		return errLib.New("Failed to list evaluation scores", http.StatusInternalServerError)
+	}
+
+	byEvaluation := make(map[uuid.UUID][]values.Score)
+	for _, row := range rows {
+		byEvaluation[row.EvaluationID] = append(byEvaluation[row.EvaluationID], values.Score{
+			SkillID:   row.SkillID,
+			SkillName: row.SkillName,
+			Category:  optionalString(row.Category),
+			ScaleMin:  row.ScaleMin,
+			ScaleMax:  row.ScaleMax,
+			Rating:    row.Rating,
+			Comment:   optionalString(row.Comment),
+		})
+	}
+	for i := range evaluations {
+		evaluations[i].Scores = byEvaluation[evaluations[i].ID]
+		if evaluations[i].Scores == nil {
+			evaluations[i].Scores = []values.Score{}
+		}
+	}
+	return nil
+}
+
+func (r *Repository) CreateEvaluation(ctx context.Context, athleteID, coachID uuid.UUID, details values.EvaluationDetails, publishedAt *time.Time) (uuid.UUID, *errLib.CommonError) {
+	status := values.EvaluationDraft
+	if publishedAt != nil {
+		status = values.EvaluationPublished
+	}
+
+	row, err := r.Queries.CreateEvaluation(ctx, db.CreateEvaluationParams{
+		AthleteID:   athleteID,
+		TemplateID:  details.TemplateID,
+		ProgramID:   nullUUID(details.ProgramID),
+		Season:      nullString(details.Season),
+		EvaluatedOn: details.EvaluatedOn,
+		Summary:     nullString(details.Summary),
+		Status:      string(status),
+		CoachID:     uuid.NullUUID{UUID: coachID, Valid: coachID != uuid.Nil},
+		PublishedAt: nullTime(publishedAt),
+	})
+	if err != nil {
+		switch constraintOf(err) {
+		case "evaluations_athlete_id_fkey":
+			return uuid.Nil, errLib.New("Athlete not found", http.StatusNotFound)
+		case "evaluations_template_id_fkey":
+			return uuid.Nil, errLib.New("Evaluation template not found", http.StatusNotFound)
+		case "evaluations_program_id_fkey":
+			return uuid.Nil, errLib.New("Program not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to create evaluation for athlete %s: %v", athleteID, err)
+		return uuid.Nil, errLib.New("Failed to create evaluation", http.StatusInternalServerError)
+	}
+	return row.ID, nil
+}
+
+func (r *Repository) UpdateEvaluation(ctx context.Context, id uuid.UUID, details values.EvaluationDetails, publishedAt *time.Time) *errLib.CommonError {
+	status := values.EvaluationDraft
+	if publishedAt != nil {
+		status = values.EvaluationPublished
+	}
+
+	_, err := r.Queries.UpdateEvaluation(ctx, db.UpdateEvaluationParams{
+		ID:          id,
+		ProgramID:   nullUUID(details.ProgramID),
+		Season:      nullString(details.Season),
+		EvaluatedOn: details.EvaluatedOn,
+		Summary:     nullString(details.Summary),
+		Status:      string(status),
+		PublishedAt: nullTime(publishedAt),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errLib.New("Evaluation not found", http.StatusNotFound)
+		}
+		if constraintOf(err) == "evaluations_program_id_fkey" {
+			return errLib.New("Program not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to update evaluation %s: %v", id, err)
+		return errLib.New("Failed to update evaluation", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (r *Repository) ReplaceScores(ctx context.Context, evaluationID uuid.UUID, scores []values.ScoreInput) *errLib.CommonError {
+	if err := r.Queries.DeleteScoresByEvaluation(ctx, evaluationID); err != nil {
+		log.Printf("Failed to clear scores of evaluation %s: %v", evaluationID, err)
+		return errLib.New("Failed to save evaluation scores", http.StatusInternalServerError)
+	}
+
+	for _, score := range scores {
+		err := r.Queries.CreateScore(ctx, db.CreateScoreParams{
+			EvaluationID: evaluationID,
+			SkillID:      score.SkillID,
+			Rating:       score.Rating,
+			Comment:      nullString(score.Comment),
+		})
+		if err != nil {
+			log.Printf("Failed to save score for skill %s on evaluation %s: %v", score.SkillID, evaluationID, err)
+			return errLib.New("Failed to save evaluation scores", http.StatusInternalServerError)
+		}
+	}
+	return nil
+}
+
+func (r *Repository) DeleteEvaluation(ctx context.Context, id uuid.UUID) *errLib.CommonError {
+	affected, err := r.Queries.DeleteEvaluation(ctx, id)
+	if err != nil {
+		log.Printf("Failed to delete evaluation %s: %v", id, err)
+		return errLib.New("Failed to delete evaluation", http.StatusInternalServerError)
+	}
+	if affected == 0 {
+		return errLib.New("Evaluation not found", http.StatusNotFound)
+	}
+	return nil
+}
+
+// ListRatings returns an athlete's published ratings, oldest first. A zero to
+// includes every evaluation.
+func (r *Repository) ListRatings(ctx context.Context, athleteID uuid.UUID, templateID *uuid.UUID, to time.Time) ([]values.Rating, *errLib.CommonError) {
+	rows, err := r.Queries.ListPublishedScoresByAthlete(ctx, db.ListPublishedScoresByAthleteParams{
+		AthleteID:  athleteID,
+		TemplateID: nullUUID(templateID),
+		To:         nullDate(to),
+	})
+	if err != nil {
+		log.Printf("Failed to list ratings of athlete %s: %v", athleteID, err)
+		return nil, errLib.New("Failed to list skill ratings", http.StatusInternalServerError)
+	}
+
+	ratings := make([]values.Rating, len(rows))
+	for i, row := range rows {
+		ratings[i] = values.Rating{
+			EvaluationID: row.EvaluationID,
+			EvaluatedOn:  row.EvaluatedOn,
+			SkillID:      row.SkillID,
+			SkillName:    row.SkillName,
+			Category:     optionalString(row.Category),
+			ScaleMin:     row.ScaleMin,
+			ScaleMax:     row.ScaleMax,
+			Rating:       row.Rating,
+		}
+	}
+	return ratings, nil
+}
+
+func mapEvaluation(row db.ListEvaluationsByAthleteRow) values.Evaluation {
+	return values.Evaluation{
+		ID:           row.ID,
+		AthleteID:    row.AthleteID,
+		TemplateID:   row.TemplateID,
+		TemplateName: row.TemplateName,
+		ProgramID:    optionalUUID(row.ProgramID),
+		ProgramName:  optionalString(row.ProgramName),
+		Season:       optionalString(row.Season),
+		EvaluatedOn:  row.EvaluatedOn,
+		Summary:      optionalString(row.Summary),
+		Status:       values.EvaluationStatus(row.Status),
+		CoachID:      optionalUUID(row.CoachID),
+		CoachName:    row.CoachName,
+		PublishedAt:  optionalTime(row.PublishedAt),
+		CreatedAt:    row.CreatedAt,
+		UpdatedAt:    row.UpdatedAt,
+	}
+}
+
+// ----- Goals -----
+
+func (r *Repository) GetGoal(ctx context.Context, id uuid.UUID) (values.Goal, *errLib.CommonError) {
+	row, err := r.Queries.GetGoalById(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return values.Goal{}, errLib.New("Goal not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to get goal %s: %v", id, err)
+		return values.Goal{}, errLib.New("Failed to get goal", http.StatusInternalServerError)
+	}
+	return mapGoal(db.ListGoalsByAthleteRow(row)), nil
+}
+
+// ListGoals returns an athlete's goals, active ones first. A nil status lists all.
+func (r *Repository) ListGoals(ctx context.Context, athleteID uuid.UUID, status *values.GoalStatus) ([]values.Goal, *errLib.CommonError) {
+	params := db.ListGoalsByAthleteParams{AthleteID: athleteID}
+	if status != nil {
+		params.Status = sql.NullString{String: string(*status), Valid: true}
+	}
+
+	rows, err := r.Queries.ListGoalsByAthlete(ctx, params)
+	if err != nil {
+		log.Printf("Failed to list goals of athlete %s: %v", athleteID, err)
+		return nil, errLib.New("Failed to list goals", http.StatusInternalServerError)
+	}
+
+	goals := make([]values.Goal, len(rows))
+	for i, row := range rows {
+		goals[i] = mapGoal(row)
+	}
+	return goals, nil
+}
+
+func (r *Repository) CreateGoal(ctx context.Context, athleteID, createdBy uuid.UUID, details values.GoalDetails, achievedAt *time.Time) (uuid.UUID, *errLib.CommonError) {
+	row, err := r.Queries.CreateGoal(ctx, db.CreateGoalParams{
+		AthleteID:    athleteID,
+		SkillID:      nullUUID(details.SkillID),
+		Title:        details.Title,
+		Description:  nullString(details.Description),
+		TargetRating: nullInt32(details.TargetRating),
+		TargetDate:   nullTime(details.TargetDate),
+		Status:       string(details.Status),
+		CreatedBy:    uuid.NullUUID{UUID: createdBy, Valid: createdBy != uuid.Nil},
+		AchievedAt:   nullTime(achievedAt),
+	})
+	if err != nil {
+		switch constraintOf(err) {
+		case "development_goals_athlete_id_fkey":
+			return uuid.Nil, errLib.New("Athlete not found", http.StatusNotFound)
+		case "development_goals_skill_id_fkey":
+			return uuid.Nil, errLib.New("Skill not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to create goal for athlete %s: %v", athleteID, err)
+		return uuid.Nil, errLib.New("Failed to create goal", http.StatusInternalServerError)
+	}
+	return row.ID, nil
+}
+
+func (r *Repository) UpdateGoal(ctx context.Context, id uuid.UUID, details values.GoalDetails, achievedAt *time.Time) *errLib.CommonError {
+	_, err := r.Queries.UpdateGoal(ctx, db.UpdateGoalParams{
+		ID:           id,
+		SkillID:      nullUUID(details.SkillID),
+		Title:        details.Title,
+		Description:  nullString(details.Description),
+		TargetRating: nullInt32(details.TargetRating),
+		TargetDate:   nullTime(details.TargetDate),
+		Status:       string(details.Status),
+		AchievedAt:   nullTime(achievedAt),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errLib.New("Goal not found", http.StatusNotFound)
+		}
+		if constraintOf(err) == "development_goals_skill_id_fkey" {
+			return errLib.New("Skill not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to update goal %s: %v", id, err)
+		return errLib.New("Failed to update goal", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (r *Repository) DeleteGoal(ctx context.Context, id uuid.UUID) *errLib.CommonError {
+	affected, err := r.Queries.DeleteGoal(ctx, id)
+	if err != nil {
+		log.Printf("Failed to delete goal %s: %v", id, err)
+		return errLib.New("Failed to delete goal", http.StatusInternalServerError)
+	}
+	if affected == 0 {
+		return errLib.New("Goal not found", http.StatusNotFound)
+	}
+	return nil
+}
+
+func nullInt32(n *int32) sql.NullInt32 {
+	if n == nil {
+		return sql.NullInt32{}
+	}
+	return sql.NullInt32{Int32: *n, Valid: true}
+}
+
+func mapGoal(row db.ListGoalsByAthleteRow) values.Goal {
+	goal := values.Goal{
+		ID:          row.ID,
+		AthleteID:   row.AthleteID,
+		SkillID:     optionalUUID(row.SkillID),
+		SkillName:   optionalString(row.SkillName),
+		Title:       row.Title,
+		Description: optionalString(row.Description),
+		TargetDate:  optionalTime(row.TargetDate),
+		Status:      values.GoalStatus(row.Status),
+		CreatedBy:   optionalUUID(row.CreatedBy),
+		AchievedAt:  optionalTime(row.AchievedAt),
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+	}
+	if row.TargetRating.Valid {
+		goal.TargetRating = &row.TargetRating.Int32
+	}
+	return goal
+}
+
+// ----- Progress reports -----
+
+// ListAthletesEvaluatedBetween returns how many published evaluations each athlete
+// has dated within [from, to].
+func (r *Repository) ListAthletesEvaluatedBetween(ctx context.Context, from, to time.Time) (map[uuid.UUID]int32, *errLib.CommonError) {
+	rows, err := r.Queries.ListAthletesEvaluatedBetween(ctx, db.ListAthletesEvaluatedBetweenParams{From: from, To: to})
+	if err != nil {
+		log.Printf("Failed to list athletes evaluated between %s and %s: %v", from.Format(time.DateOnly), to.Format(time.DateOnly), err)
+		return nil, errLib.New("Failed to list evaluated athletes", http.StatusInternalServerError)
+	}
+
+	counts := make(map[uuid.UUID]int32, len(rows))
+	for _, row := range rows {
+		counts[row.AthleteID] = row.EvaluationCount
+	}
+	return counts, nil
+}
+
+// CreateProgressReport records a report period for an athlete. It returns false
+// when the athlete already has a report for the period.
+func (r *Repository) CreateProgressReport(ctx context.Context, athleteID uuid.UUID, from, to time.Time, evaluations int32) (bool, *errLib.CommonError) {
+	_, err := r.Queries.CreateProgressReport(ctx, db.CreateProgressReportParams{
+		AthleteID:       athleteID,
+		PeriodStart:     from,
+		PeriodEnd:       to,
+		EvaluationCount: evaluations,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		log.Printf("Failed to create progress report for athlete %s: %v", athleteID, err)
+		return false, errLib.New("Failed to create progress report", http.StatusInternalServerError)
+	}
+	return true, nil
+}
+
+func (r *Repository) ListUnsentProgressReports(ctx context.Context, limit int32) ([]values.ProgressReport, *errLib.CommonError) {
+	rows, err := r.Queries.ListUnsentProgressReports(ctx, limit)
+	if err != nil {
+		log.Printf("Failed to list unsent progress reports: %v", err)
+		return nil, errLib.New("Failed to list progress reports", http.StatusInternalServerError)
+	}
+	return mapProgressReports(rows), nil
+}
+
+func (r *Repository) MarkProgressReportEmailed(ctx context.Context, id uuid.UUID) *errLib.CommonError {
+	if err := r.Queries.MarkProgressReportEmailed(ctx, id); err != nil {
+		log.Printf("Failed to mark progress report %s emailed: %v", id, err)
+		return errLib.New("Failed to update progress report", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (r *Repository) GetProgressReport(ctx context.Context, id uuid.UUID) (values.ProgressReport, *errLib.CommonError) {
+	row, err := r.Queries.GetProgressReportById(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return values.ProgressReport{}, errLib.New("Progress report not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to get progress report %s: %v", id, err)
+		return values.ProgressReport{}, errLib.New("Failed to get progress report", http.StatusInternalServerError)
+	}
+	return mapProgressReport(row), nil
+}
+
+func (r *Repository) ListProgressReports(ctx context.Context, athleteID uuid.UUID) ([]values.ProgressReport, *errLib.CommonError) {
+	rows, err := r.Queries.ListProgressReportsByAthlete(ctx, athleteID)
+	if err != nil {
+		log.Printf("Failed to list progress reports of athlete %s: %v", athleteID, err)
+		return nil, errLib.New("Failed to list progress reports", http.StatusInternalServerError)
+	}
+	return mapProgressReports(rows), nil
+}
+
+func mapProgressReports(rows []db.AthleticProgressReport) []values.ProgressReport {
+	reports := make([]values.ProgressReport, len(rows))
+	for i, row := range rows {
+		reports[i] = mapProgressReport(row)
+	}
+	return reports
+}
+
+func mapProgressReport(row db.AthleticProgressReport) values.ProgressReport {
+	return values.ProgressReport{
+		ID:              row.ID,
+		AthleteID:       row.AthleteID,
+		PeriodStart:     row.PeriodStart,
+		PeriodEnd:       row.PeriodEnd,
+		EvaluationCount: row.EvaluationCount,
+		EmailedAt:       optionalTime(row.EmailedAt),
+		CreatedAt:       row.CreatedAt,
+	}
+}
@@ -0,0 +1,245 @@
+-- name: CreateTemplate :one
+INSERT INTO athletic.evaluation_templates (name, description, program_id, is_active, created_by)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: UpdateTemplate :one
+UPDATE athletic.evaluation_templates
+SET name        = $2,
+    description = $3,
+    program_id  = $4,
+    is_active   = $5,
+    updated_at  = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
+
+-- name: GetTemplateById :one
+SELECT *
+FROM athletic.evaluation_templates
+WHERE id = $1;
+
+-- name: ListTemplates :many
+SELECT *
+FROM athletic.evaluation_templates
+WHERE (sqlc.narg('is_active')::boolean IS NULL OR is_active = sqlc.narg('is_active')::boolean)
+  AND (sqlc.narg('program_id')::uuid IS NULL OR program_id IS NULL OR program_id = sqlc.narg('program_id')::uuid)
+ORDER BY name;
+
+-- name: TemplateHasEvaluations :one
+SELECT EXISTS (SELECT 1 FROM athletic.evaluations WHERE template_id = $1);
+
+-- name: CreateSkill :one
+INSERT INTO athletic.evaluation_skills (template_id, name, category, description, scale_min, scale_max, sort_order)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: DeleteSkillsByTemplate :exec
+DELETE
+FROM athletic.evaluation_skills
+WHERE template_id = $1;
+
+-- name: ListSkillsByTemplateIds :many
+SELECT *
+FROM athletic.evaluation_skills
+WHERE template_id = ANY (sqlc.arg('template_ids')::uuid[])
+ORDER BY template_id, sort_order, name;
+
+-- name: IsAthlete :one
+SELECT EXISTS (SELECT 1 FROM athletic.athletes WHERE id = $1);
+
+-- name: CoachCanEvaluate :one
+-- A coach can evaluate athletes on a team they coach, and athletes enrolled in a
+-- program whose events they are staffed on.
+SELECT EXISTS (SELECT 1
+               FROM athletic.athletes a
+                        JOIN athletic.teams t ON t.id = a.team_id
+               WHERE a.id = sqlc.arg('athlete_id')
+                 AND t.coach_id = sqlc.arg('coach_id'))
+           OR EXISTS (SELECT 1
+                      FROM program.customer_enrollment ce
+                               JOIN events.events e ON e.program_id = ce.program_id
+                               JOIN events.staff es ON es.event_id = e.id
+                      WHERE ce.customer_id = sqlc.arg('athlete_id')
+                        AND ce.is_cancelled = false
+                        AND es.staff_id = sqlc.arg('coach_id'));
+
+-- name: GetAthleteContact :one
+SELECT u.id,
+       u.first_name,
+       u.last_name,
+       u.email,
+       p.first_name AS parent_first_name,
+       p.email      AS parent_email
+FROM athletic.athletes a
+         JOIN users.users u ON u.id = a.id
+         LEFT JOIN users.users p ON p.id = u.parent_id
+WHERE a.id = $1;
+
+-- name: CreateEvaluation :one
+INSERT INTO athletic.evaluations (athlete_id, template_id, program_id, season, evaluated_on, summary, status,
+                                  coach_id, published_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: UpdateEvaluation :one
+UPDATE athletic.evaluations
+SET program_id   = $2,
+    season       = $3,
+    evaluated_on = $4,
+    summary      = $5,
+    status       = $6,
+    published_at = $7,
+    updated_at   = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteEvaluation :execrows
+DELETE
+FROM athletic.evaluations
+WHERE id = $1;
+
+-- name: GetEvaluationById :one
+SELECT e.*,
+       t.name                                      AS template_name,
+       p.name                                      AS program_name,
+       COALESCE(c.first_name || ' ' || c.last_name, '')::text AS coach_name
+FROM athletic.evaluations e
+         JOIN athletic.evaluation_templates t ON t.id = e.template_id
+         LEFT JOIN program.programs p ON p.id = e.program_id
+         LEFT JOIN users.users c ON c.id = e.coach_id
+WHERE e.id = $1;
+
+-- name: ListEvaluationsByAthlete :many
+SELECT e.*,
+       t.name                                      AS template_name,
+       p.name                                      AS program_name,
+       COALESCE(c.first_name || ' ' || c.last_name, '')::text AS coach_name
+FROM athletic.evaluations e
+         JOIN athletic.evaluation_templates t ON t.id = e.template_id
+         LEFT JOIN program.programs p ON p.id = e.program_id
+         LEFT JOIN users.users c ON c.id = e.coach_id
+WHERE e.athlete_id = sqlc.arg('athlete_id')
+  AND (sqlc.arg('include_drafts')::boolean OR e.status = 'published')
+  AND (sqlc.narg('from')::date IS NULL OR e.evaluated_on >= sqlc.narg('from')::date)
+  AND (sqlc.narg('to')::date IS NULL OR e.evaluated_on <= sqlc.narg('to')::date)
+ORDER BY e.evaluated_on DESC, e.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CreateScore :exec
+INSERT INTO athletic.evaluation_scores (evaluation_id, skill_id, rating, comment)
+VALUES ($1, $2, $3, $4);
+
+-- name: DeleteScoresByEvaluation :exec
+DELETE
+FROM athletic.evaluation_scores
+WHERE evaluation_id = $1;
+
+-- name: ListScoresByEvaluationIds :many
+SELECT sc.evaluation_id,
+       sc.skill_id,
+       sc.rating,
+       sc.comment,
+       sk.name AS skill_name,
+       sk.category,
+       sk.scale_min,
+       sk.scale_max
+FROM athletic.evaluation_scores sc
+         JOIN athletic.evaluation_skills sk ON sk.id = sc.skill_id
+WHERE sc.evaluation_id = ANY (sqlc.arg('evaluation_ids')::uuid[])
+ORDER BY sc.evaluation_id, sk.sort_order, sk.name;
+
+-- name: ListPublishedScoresByAthlete :many
+-- Every published rating an athlete has received, oldest first, for skill trends.
+SELECT e.id AS evaluation_id,
+       e.evaluated_on,
+       sc.skill_id,
+       sc.rating,
+       sk.name AS skill_name,
+       sk.category,
+       sk.scale_min,
+       sk.scale_max
+FROM athletic.evaluations e
+         JOIN athletic.evaluation_scores sc ON sc.evaluation_id = e.id
+         JOIN athletic.evaluation_skills sk ON sk.id = sc.skill_id
+WHERE e.athlete_id = sqlc.arg('athlete_id')
+  AND e.status = 'published'
+  AND (sqlc.narg('template_id')::uuid IS NULL OR e.template_id = sqlc.narg('template_id')::uuid)
+  AND (sqlc.narg('to')::date IS NULL OR e.evaluated_on <= sqlc.narg('to')::date)
+ORDER BY e.evaluated_on, e.created_at, sk.sort_order;
+
+-- name: CreateGoal :one
+INSERT INTO athletic.development_goals (athlete_id, skill_id, title, description, target_rating, target_date,
+                                        status, created_by, achieved_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: UpdateGoal :one
+UPDATE athletic.development_goals
+SET skill_id      = $2,
+    title         = $3,
+    description   = $4,
+    target_rating = $5,
+    target_date   = $6,
+    status        = $7,
+    achieved_at   = $8,
+    updated_at    = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteGoal :execrows
+DELETE
+FROM athletic.development_goals
+WHERE id = $1;
+
+-- name: GetGoalById :one
+SELECT g.*, sk.name AS skill_name
+FROM athletic.development_goals g
+         LEFT JOIN athletic.evaluation_skills sk ON sk.id = g.skill_id
+WHERE g.id = $1;
+
+-- name: ListGoalsByAthlete :many
+SELECT g.*, sk.name AS skill_name
+FROM athletic.development_goals g
+         LEFT JOIN athletic.evaluation_skills sk ON sk.id = g.skill_id
+WHERE g.athlete_id = sqlc.arg('athlete_id')
+  AND (sqlc.narg('status')::varchar IS NULL OR g.status = sqlc.narg('status')::varchar)
+ORDER BY CASE g.status WHEN 'active' THEN 0 WHEN 'achieved' THEN 1 ELSE 2 END,
+         g.target_date NULLS LAST, g.created_at;
+
+-- name: ListAthletesEvaluatedBetween :many
+-- Athletes with published evaluations dated in a report period.
+SELECT athlete_id, COUNT(*)::int AS evaluation_count
+FROM athletic.evaluations
+WHERE status = 'published'
+  AND evaluated_on BETWEEN sqlc.arg('from')::date AND sqlc.arg('to')::date
+GROUP BY athlete_id;
+
+-- name: CreateProgressReport :one
+-- Returns no row when the athlete already has a report for the period.
+INSERT INTO athletic.progress_reports (athlete_id, period_start, period_end, evaluation_count)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (athlete_id, period_start) DO NOTHING
+RETURNING *;
+
+-- name: ListUnsentProgressReports :many
+SELECT *
+FROM athletic.progress_reports
+WHERE emailed_at IS NULL
+ORDER BY created_at
+LIMIT $1;
+
+-- name: MarkProgressReportEmailed :exec
+UPDATE athletic.progress_reports
+SET emailed_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: GetProgressReportById :one
+SELECT *
+FROM athletic.progress_reports
+WHERE id = $1;
+
+-- name: ListProgressReportsByAthlete :many
+SELECT *
+FROM athletic.progress_reports
+WHERE athlete_id = $1
+ORDER BY period_start DESC;
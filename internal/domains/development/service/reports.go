@@ -0,0 +1,275 @@
+package development
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	values "api/internal/domains/development/values"
+	errLib "api/internal/libs/errors"
+	"api/utils/email"
+	"api/utils/timezone"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// reportEmailBatchSize bounds how many progress reports one run emails
+	reportEmailBatchSize = 200
+	// maxEmailHighlights bounds the skill lines in a progress report email
+	maxEmailHighlights = 8
+)
+
+// GenerateProgressReports issues last month's progress report to every athlete with
+// a published evaluation dated in that month. Running it again is harmless; athletes
+// who already have the month's report are skipped.
+func (s *Service) GenerateProgressReports(ctx context.Context, now time.Time) (int, *errLib.CommonError) {
+	from, to := reportPeriod(now, timezone.Default())
+
+	counts, err := s.records.ListAthletesEvaluatedBetween(ctx, from, to)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for athleteID, evaluations := range counts {
+		isNew, err := s.records.CreateProgressReport(ctx, athleteID, from, to, evaluations)
+		if err != nil {
+			return created, err
+		}
+		if isNew {
+			created++
+		}
+	}
+	return created, nil
+}
+
+// EmailProgressReports emails issued reports that have not been sent yet to the
+// athlete's parent, or to the athlete when they have no parent. Reports with nobody
+// to email are marked handled; families can still download them from the app.
+func (s *Service) EmailProgressReports(ctx context.Context) (int, *errLib.CommonError) {
+	reports, err := s.repo.ListUnsentProgressReports(ctx, reportEmailBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, report := range reports {
+		content, err := s.reportContent(ctx, report)
+		if err != nil {
+			log.Printf("[DEVELOPMENT] Failed to build progress report %s: %s", report.ID, err.Message)
+			continue
+		}
+
+		to, firstName := recipient(content.Athlete)
+		if to == "" {
+			log.Printf("[DEVELOPMENT] No email address for progress report %s of athlete %s", report.ID, report.AthleteID)
+		} else {
+			highlights, goals := emailHighlights(content)
+			if err := email.SendProgressReportEmail(to, firstName, content.Athlete.FirstName, periodLabel(report),
+				int(report.EvaluationCount), highlights, goals); err != nil {
+				continue
+			}
+			sent++
+		}
+
+		if err := s.repo.MarkProgressReportEmailed(ctx, report.ID); err != nil {
+			return sent, err
+		}
+	}
+	return sent, nil
+}
+
+// ListProgressReports returns the progress reports issued to an athlete, newest first.
+func (s *Service) ListProgressReports(ctx context.Context, athleteID uuid.UUID) ([]values.ProgressReport, *errLib.CommonError) {
+	if _, _, err := s.authorizeStaff(ctx, athleteID); err != nil {
+		return nil, err
+	}
+	return s.records.ListProgressReports(ctx, athleteID)
+}
+
+// ListAthleteProgressReports is ListProgressReports for families. Callers must have
+// checked the family's access to the athlete.
+func (s *Service) ListAthleteProgressReports(ctx context.Context, athleteID uuid.UUID) ([]values.ProgressReport, *errLib.CommonError) {
+	return s.records.ListProgressReports(ctx, athleteID)
+}
+
+// GetProgressReportPDF renders a progress report for staff working with the athlete.
+func (s *Service) GetProgressReportPDF(ctx context.Context, reportID uuid.UUID) ([]byte, string, *errLib.CommonError) {
+	report, err := s.records.GetProgressReport(ctx, reportID)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, _, err = s.authorizeStaff(ctx, report.AthleteID); err != nil {
+		return nil, "", err
+	}
+	return s.renderReport(ctx, report)
+}
+
+// GetAthleteProgressReportPDF renders one of an athlete's progress reports for their
+// family. Callers must have checked the family's access to the athlete.
+func (s *Service) GetAthleteProgressReportPDF(ctx context.Context, athleteID, reportID uuid.UUID) ([]byte, string, *errLib.CommonError) {
+	report, err := s.records.GetProgressReport(ctx, reportID)
+	if err != nil {
+		return nil, "", err
+	}
+	if report.AthleteID != athleteID {
+		return nil, "", errLib.New("Progress report not found", http.StatusNotFound)
+	}
+	return s.renderReport(ctx, report)
+}
+
+func (s *Service) renderReport(ctx context.Context, report values.ProgressReport) ([]byte, string, *errLib.CommonError) {
+	content, err := s.reportContent(ctx, report)
+	if err != nil {
+		return nil, "", err
+	}
+	filename := fmt.Sprintf("progress-report-%s.pdf", report.PeriodStart.Format("2006-01"))
+	return renderProgressReport(content), filename, nil
+}
+
+// reportContent gathers what a report prints. Evaluations and trends are read as of
+// the end of the period, so a report looks the same whenever it is downloaded.
+func (s *Service) reportContent(ctx context.Context, report values.ProgressReport) (values.ReportContent, *errLib.CommonError) {
+	athlete, err := s.repo.GetAthleteContact(ctx, report.AthleteID)
+	if err != nil {
+		return values.ReportContent{}, err
+	}
+
+	evaluations, err := s.repo.ListEvaluations(ctx, values.EvaluationFilter{
+		AthleteID: report.AthleteID,
+		From:      report.PeriodStart,
+		To:        report.PeriodEnd,
+		Limit:     reportEvaluationLimit,
+	})
+	if err != nil {
+		return values.ReportContent{}, err
+	}
+
+	ratings, err := s.repo.ListRatings(ctx, report.AthleteID, nil, report.PeriodEnd)
+	if err != nil {
+		return values.ReportContent{}, err
+	}
+
+	goals, err := s.repo.ListGoals(ctx, report.AthleteID, nil)
+	if err != nil {
+		return values.ReportContent{}, err
+	}
+
+	return values.ReportContent{
+		Report:      report,
+		Athlete:     athlete,
+		Evaluations: evaluations,
+		Trends:      buildTrends(ratings),
+		Goals:       withoutDropped(goals),
+	}, nil
+}
+
+// reportPeriod is the calendar month before now in loc, as dates at midnight UTC.
+func reportPeriod(now time.Time, loc *time.Location) (time.Time, time.Time) {
+	local := now.In(loc)
+	thisMonth := time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return thisMonth.AddDate(0, -1, 0), thisMonth.AddDate(0, 0, -1)
+}
+
+func periodLabel(report values.ProgressReport) string {
+	if report.PeriodStart.Year() == report.PeriodEnd.Year() && report.PeriodStart.Month() == report.PeriodEnd.Month() {
+		return report.PeriodStart.Format("January 2006")
+	}
+	return report.PeriodStart.Format("Jan 2, 2006") + " - " + report.PeriodEnd.Format("Jan 2, 2006")
+}
+
+// recipient is the address and first name a report is emailed to.
+func recipient(athlete values.AthleteContact) (string, string) {
+	if athlete.ParentEmail != nil && *athlete.ParentEmail != "" {
+		firstName := ""
+		if athlete.ParentFirstName != nil {
+			firstName = *athlete.ParentFirstName
+		}
+		return *athlete.ParentEmail, firstName
+	}
+	if athlete.Email != nil && *athlete.Email != "" {
+		return *athlete.Email, athlete.FirstName
+	}
+	return "", ""
+}
+
+// buildTrends groups ratings, oldest first, into one trend per skill in the order
+// skills were first rated.
+func buildTrends(ratings []values.Rating) []values.SkillTrend {
+	trends := make([]values.SkillTrend, 0)
+	index := make(map[uuid.UUID]int)
+
+	for _, rating := range ratings {
+		i, ok := index[rating.SkillID]
+		if !ok {
+			i = len(trends)
+			index[rating.SkillID] = i
+			trends = append(trends, values.SkillTrend{
+				SkillID:   rating.SkillID,
+				SkillName: rating.SkillName,
+				Category:  rating.Category,
+				ScaleMin:  rating.ScaleMin,
+				ScaleMax:  rating.ScaleMax,
+				First:     rating.Rating,
+			})
+		}
+		trends[i].Points = append(trends[i].Points, values.TrendPoint{
+			EvaluationID: rating.EvaluationID,
+			EvaluatedOn:  rating.EvaluatedOn,
+			Rating:       rating.Rating,
+		})
+		trends[i].Latest = rating.Rating
+	}
+
+	for i := range trends {
+		trends[i].Change = trends[i].Latest - trends[i].First
+		switch {
+		case trends[i].Change > 0:
+			trends[i].Direction = values.TrendImproving
+		case trends[i].Change < 0:
+			trends[i].Direction = values.TrendDeclining
+		default:
+			trends[i].Direction = values.TrendSteady
+		}
+	}
+	return trends
+}
+
+// emailHighlights summarises a report's skill ratings and open goals as email lines.
+// Only skills rated during the period are listed.
+func emailHighlights(content values.ReportContent) ([]string, []string) {
+	rated := make(map[uuid.UUID]bool)
+	for _, evaluation := range content.Evaluations {
+		for _, score := range evaluation.Scores {
+			rated[score.SkillID] = true
+		}
+	}
+
+	var highlights []string
+	for _, trend := range content.Trends {
+		if !rated[trend.SkillID] || len(highlights) == maxEmailHighlights {
+			continue
+		}
+		if len(trend.Points) > 1 && trend.Change != 0 {
+			highlights = append(highlights, fmt.Sprintf("%s: %d → %d (out of %d)", trend.SkillName, trend.First, trend.Latest, trend.ScaleMax))
+		} else {
+			highlights = append(highlights, fmt.Sprintf("%s: %d (out of %d)", trend.SkillName, trend.Latest, trend.ScaleMax))
+		}
+	}
+
+	var goals []string
+	for _, goal := range content.Goals {
+		if goal.Status != values.GoalActive {
+			continue
+		}
+		line := goal.Title
+		if goal.TargetDate != nil {
+			line += " (by " + goal.TargetDate.Format("Jan 2") + ")"
+		}
+		goals = append(goals, line)
+	}
+	return highlights, goals
+}
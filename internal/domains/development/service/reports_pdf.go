@@ -0,0 +1,230 @@
+package development
+
+import (
+	"fmt"
+	"strings"
+
+	values "api/internal/domains/development/values"
+	"api/internal/libs/pdf"
+)
+
+const (
+	marginLeft   = 54.0
+	marginRight  = pdf.PageWidth - 54.0
+	marginTop    = pdf.PageHeight - 54.0
+	marginBottom = 72.0
+	lineHeight   = 16.0
+)
+
+// reportWriter lays out a progress report top to bottom, starting new pages as
+// they fill up.
+type reportWriter struct {
+	doc  *pdf.Document
+	page *pdf.Page
+	y    float64
+}
+
+func (w *reportWriter) ensure(needed float64) {
+	if w.y-needed < marginBottom {
+		w.page = w.doc.AddPage()
+		w.y = marginTop
+	}
+}
+
+func (w *reportWriter) heading(s string) {
+	w.ensure(lineHeight * 3)
+	w.y -= lineHeight * 0.5
+	w.page.Text(marginLeft, w.y, pdf.Bold, 12, s)
+	w.y -= 6
+	w.page.Line(marginLeft, w.y, marginRight, w.y)
+	w.y -= lineHeight
+}
+
+// paragraph prints s word-wrapped to the page width.
+func (w *reportWriter) paragraph(x float64, font pdf.Font, size float64, s string) {
+	for _, line := range wrap(s, font, size, marginRight-x) {
+		w.ensure(lineHeight)
+		w.page.Text(x, w.y, font, size, line)
+		w.y -= lineHeight * 0.85
+	}
+}
+
+func renderProgressReport(content values.ReportContent) []byte {
+	doc := pdf.New()
+	w := &reportWriter{doc: doc, page: doc.AddPage(), y: marginTop}
+
+	w.page.Text(marginLeft, w.y, pdf.Bold, 16, "Progress Report")
+	w.page.TextRight(marginRight, w.y, pdf.Regular, 10, periodLabel(content.Report))
+	w.y -= lineHeight * 1.5
+	w.page.Text(marginLeft, w.y, pdf.Bold, 12, content.Athlete.FirstName+" "+content.Athlete.LastName)
+	w.y -= lineHeight * 1.5
+
+	w.heading("Evaluations this period")
+	if len(content.Evaluations) == 0 {
+		w.paragraph(marginLeft, pdf.Regular, 10, "No evaluations were published this period.")
+	}
+	for _, evaluation := range content.Evaluations {
+		drawEvaluation(w, evaluation)
+	}
+
+	w.heading("Skill trends")
+	if len(content.Trends) == 0 {
+		w.paragraph(marginLeft, pdf.Regular, 10, "No skills have been rated yet.")
+	} else {
+		drawTrends(w, content.Trends)
+	}
+
+	if len(content.Goals) > 0 {
+		w.heading("Goals")
+		for _, goal := range content.Goals {
+			drawGoal(w, goal)
+		}
+	}
+
+	return doc.Bytes()
+}
+
+func drawEvaluation(w *reportWriter, evaluation values.Evaluation) {
+	w.ensure(lineHeight * 4)
+	w.page.Text(marginLeft, w.y, pdf.Bold, 10, fit(evaluation.TemplateName, pdf.Bold, 10, marginRight-marginLeft-120))
+	w.page.TextRight(marginRight, w.y, pdf.Regular, 9, evaluation.EvaluatedOn.Format("Jan 2, 2006"))
+	w.y -= lineHeight * 0.85
+
+	var byline []string
+	if evaluation.CoachName != "" {
+		byline = append(byline, "Coach "+evaluation.CoachName)
+	}
+	if evaluation.ProgramName != nil {
+		byline = append(byline, *evaluation.ProgramName)
+	}
+	if evaluation.Season != nil {
+		byline = append(byline, *evaluation.Season)
+	}
+	if len(byline) > 0 {
+		w.page.Text(marginLeft, w.y, pdf.Regular, 9, fit(strings.Join(byline, " - "), pdf.Regular, 9, marginRight-marginLeft))
+		w.y -= lineHeight
+	}
+
+	const ratingRight = marginRight
+	for _, score := range evaluation.Scores {
+		w.ensure(lineHeight)
+		w.page.Text(marginLeft+12, w.y, pdf.Regular, 9, fit(score.SkillName, pdf.Regular, 9, ratingRight-marginLeft-80))
+		w.page.TextRight(ratingRight, w.y, pdf.Bold, 9, fmt.Sprintf("%d / %d", score.Rating, score.ScaleMax))
+		w.y -= lineHeight * 0.85
+		if score.Comment != nil && strings.TrimSpace(*score.Comment) != "" {
+			w.paragraph(marginLeft+24, pdf.Regular, 8, *score.Comment)
+		}
+	}
+
+	if evaluation.Summary != nil && strings.TrimSpace(*evaluation.Summary) != "" {
+		w.y -= 4
+		w.paragraph(marginLeft+12, pdf.Regular, 9, *evaluation.Summary)
+	}
+	w.y -= lineHeight * 0.5
+}
+
+func drawTrends(w *reportWriter, trends []values.SkillTrend) {
+	const (
+		firstRight  = marginRight - 150
+		latestRight = marginRight - 75
+		changeRight = marginRight
+	)
+
+	header := func() {
+		w.page.Text(marginLeft, w.y, pdf.Bold, 9, "Skill")
+		w.page.TextRight(firstRight, w.y, pdf.Bold, 9, "First")
+		w.page.TextRight(latestRight, w.y, pdf.Bold, 9, "Latest")
+		w.page.TextRight(changeRight, w.y, pdf.Bold, 9, "Change")
+		w.y -= lineHeight
+	}
+	header()
+
+	for _, trend := range trends {
+		if w.y-lineHeight < marginBottom {
+			w.ensure(lineHeight)
+			header()
+		}
+		w.page.Text(marginLeft, w.y, pdf.Regular, 9, fit(trend.SkillName, pdf.Regular, 9, firstRight-marginLeft-50))
+		w.page.TextRight(firstRight, w.y, pdf.Regular, 9, fmt.Sprintf("%d / %d", trend.First, trend.ScaleMax))
+		w.page.TextRight(latestRight, w.y, pdf.Regular, 9, fmt.Sprintf("%d / %d", trend.Latest, trend.ScaleMax))
+		w.page.TextRight(changeRight, w.y, pdf.Regular, 9, formatChange(trend))
+		w.y -= lineHeight * 0.85
+	}
+	w.y -= lineHeight * 0.5
+}
+
+func drawGoal(w *reportWriter, goal values.Goal) {
+	w.ensure(lineHeight * 2)
+	status := "In progress"
+	if goal.Status == values.GoalAchieved {
+		status = "Achieved"
+	}
+	w.page.Text(marginLeft, w.y, pdf.Bold, 9, fit(goal.Title, pdf.Bold, 9, marginRight-marginLeft-90))
+	w.page.TextRight(marginRight, w.y, pdf.Regular, 9, status)
+	w.y -= lineHeight * 0.85
+
+	var details []string
+	if goal.SkillName != nil {
+		target := *goal.SkillName
+		if goal.TargetRating != nil {
+			target += fmt.Sprintf(" to %d", *goal.TargetRating)
+		}
+		details = append(details, target)
+	}
+	if goal.TargetDate != nil {
+		details = append(details, "by "+goal.TargetDate.Format("Jan 2, 2006"))
+	}
+	if len(details) > 0 {
+		w.page.Text(marginLeft+12, w.y, pdf.Regular, 8, strings.Join(details, " "))
+		w.y -= lineHeight * 0.85
+	}
+	if goal.Description != nil && strings.TrimSpace(*goal.Description) != "" {
+		w.paragraph(marginLeft+12, pdf.Regular, 8, *goal.Description)
+	}
+	w.y -= 4
+}
+
+func formatChange(trend values.SkillTrend) string {
+	if len(trend.Points) < 2 {
+		return "-"
+	}
+	if trend.Change > 0 {
+		return fmt.Sprintf("+%d", trend.Change)
+	}
+	return fmt.Sprintf("%d", trend.Change)
+}
+
+// wrap splits s into lines at most width points wide, breaking between words.
+func wrap(s string, font pdf.Font, size float64, width float64) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(s, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if line != "" && pdf.TextWidth(font, size, candidate) > width {
+				lines = append(lines, line)
+				candidate = word
+			}
+			line = fit(candidate, font, size, width)
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// fit shortens s with an ellipsis so it is at most width points wide
+func fit(s string, font pdf.Font, size float64, width float64) string {
+	if pdf.TextWidth(font, size, s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && pdf.TextWidth(font, size, string(runes)+"...") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return strings.TrimSpace(string(runes)) + "..."
+}
@@ -0,0 +1,634 @@
+package development
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"api/internal/di"
+	auditValues "api/internal/domains/audit/audit_logs/values"
+	staffActivityLogs "api/internal/domains/audit/staff_activity_logs/service"
+	dto "api/internal/domains/development/dto"
+	repo "api/internal/domains/development/persistence"
+	values "api/internal/domains/development/values"
+	errLib "api/internal/libs/errors"
+	contextUtils "api/utils/context"
+	txUtils "api/utils/db"
+
+	"github.com/google/uuid"
+)
+
+const (
+	entityTemplate   = "evaluation_template"
+	entityEvaluation = "evaluation"
+	entityGoal       = "development_goal"
+	actionPublish    = "publish"
+
+	// familyEvaluationLimit is how many recent evaluations families see with a child's profile
+	familyEvaluationLimit = 5
+	// reportEvaluationLimit bounds the evaluations printed on one progress report
+	reportEvaluationLimit = 50
+)
+
+// Service manages athlete development: evaluation templates, coach evaluations,
+// skill trends, goals, and the progress reports families receive.
+//
+// Coaches can work with athletes on teams they coach or in programs they are
+// staffed on; admins with every athlete. Families only ever see published
+// evaluations, through the family domain.
+type Service struct {
+	repo                     *repo.Repository
+	records                  developmentRecords
+	staffActivityLogsService *staffActivityLogs.Service
+	db                       *sql.DB
+}
+
+// developmentRecords are the reads that decide who sees an athlete's development and
+// the progress reports issued from it. Tests replace them to run without a database.
+type developmentRecords interface {
+	IsAthlete(ctx context.Context, athleteID uuid.UUID) (bool, *errLib.CommonError)
+	CoachCanEvaluate(ctx context.Context, coachID, athleteID uuid.UUID) (bool, *errLib.CommonError)
+	ListAthletesEvaluatedBetween(ctx context.Context, from, to time.Time) (map[uuid.UUID]int32, *errLib.CommonError)
+	CreateProgressReport(ctx context.Context, athleteID uuid.UUID, from, to time.Time, evaluations int32) (bool, *errLib.CommonError)
+	ListProgressReports(ctx context.Context, athleteID uuid.UUID) ([]values.ProgressReport, *errLib.CommonError)
+	GetProgressReport(ctx context.Context, id uuid.UUID) (values.ProgressReport, *errLib.CommonError)
+}
+
+func NewService(container *di.Container) *Service {
+	r := repo.NewRepository(container)
+	return &Service{
+		repo:                     r,
+		records:                  r,
+		staffActivityLogsService: staffActivityLogs.NewService(container),
+		db:                       container.DB,
+	}
+}
+
+func isAdmin(role contextUtils.CtxRole) bool {
+	return role == contextUtils.RoleAdmin || role == contextUtils.RoleSuperAdmin || role == contextUtils.RoleIT
+}
+
+// authorizeStaff checks the athlete exists and the caller may see and edit their
+// development records. It returns the caller's ID and whether they are an admin.
+func (s *Service) authorizeStaff(ctx context.Context, athleteID uuid.UUID) (uuid.UUID, bool, *errLib.CommonError) {
+	userID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return uuid.Nil, false, err
+	}
+	role, err := contextUtils.GetUserRole(ctx)
+	if err != nil {
+		return uuid.Nil, false, err
+	}
+
+	exists, err := s.records.IsAthlete(ctx, athleteID)
+	if err != nil {
+		return uuid.Nil, false, err
+	}
+	if !exists {
+		return uuid.Nil, false, errLib.New("Athlete not found", http.StatusNotFound)
+	}
+
+	if isAdmin(role) {
+		return userID, true, nil
+	}
+
+	allowed, err := s.records.CoachCanEvaluate(ctx, userID, athleteID)
+	if err != nil {
+		return uuid.Nil, false, err
+	}
+	if !allowed {
+		return uuid.Nil, false, errLib.New("You can only work with athletes on your teams or in programs you coach", http.StatusForbidden)
+	}
+	return userID, false, nil
+}
+
+// ----- Templates -----
+
+func (s *Service) ListTemplates(ctx context.Context, includeInactive bool, programID *uuid.UUID) ([]values.Template, *errLib.CommonError) {
+	return s.repo.ListTemplates(ctx, !includeInactive, programID)
+}
+
+func (s *Service) GetTemplate(ctx context.Context, id uuid.UUID) (values.Template, *errLib.CommonError) {
+	return s.repo.GetTemplate(ctx, id)
+}
+
+func (s *Service) CreateTemplate(ctx context.Context, details values.TemplateDetails) (values.Template, *errLib.CommonError) {
+	actorID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return values.Template{}, err
+	}
+
+	var created values.Template
+	err = txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		txRepo := s.repo.WithTx(tx)
+
+		id, txErr := txRepo.CreateTemplate(ctx, details, actorID)
+		if txErr != nil {
+			return txErr
+		}
+		if txErr = txRepo.ReplaceSkills(ctx, id, details.Skills); txErr != nil {
+			return txErr
+		}
+		if created, txErr = txRepo.GetTemplate(ctx, id); txErr != nil {
+			return txErr
+		}
+
+		return s.staffActivityLogsService.RecordChange(ctx, tx, actorID, auditValues.Entry{
+			EntityType:  entityTemplate,
+			EntityID:    id.String(),
+			Action:      auditValues.ActionCreate,
+			After:       dto.NewTemplateResponse(created),
+			Description: fmt.Sprintf("Created evaluation template %q with %d skills", created.Name, len(created.Skills)),
+		})
+	})
+	if err != nil {
+		return values.Template{}, err
+	}
+	return created, nil
+}
+
+// UpdateTemplate edits a template. Skills can only be replaced while no evaluation
+// uses the template, so past ratings keep the scale they were given on.
+func (s *Service) UpdateTemplate(ctx context.Context, id uuid.UUID, details values.TemplateDetails) (values.Template, *errLib.CommonError) {
+	actorID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return values.Template{}, err
+	}
+
+	var updated values.Template
+	err = txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		txRepo := s.repo.WithTx(tx)
+
+		before, txErr := txRepo.GetTemplate(ctx, id)
+		if txErr != nil {
+			return txErr
+		}
+
+		if details.Skills != nil {
+			used, txErr := txRepo.TemplateHasEvaluations(ctx, id)
+			if txErr != nil {
+				return txErr
+			}
+			if used {
+				return errLib.New("Skills cannot be changed once coaches have used the template; create a new template instead", http.StatusConflict)
+			}
+			if len(details.Skills) == 0 {
+				return errLib.New("A template needs at least one skill", http.StatusBadRequest)
+			}
+		}
+
+		if txErr = txRepo.UpdateTemplate(ctx, id, details); txErr != nil {
+			return txErr
+		}
+		if details.Skills != nil {
+			if txErr = txRepo.ReplaceSkills(ctx, id, details.Skills); txErr != nil {
+				return txErr
+			}
+		}
+		if updated, txErr = txRepo.GetTemplate(ctx, id); txErr != nil {
+			return txErr
+		}
+
+		return s.staffActivityLogsService.RecordChange(ctx, tx, actorID, auditValues.Entry{
+			EntityType:  entityTemplate,
+			EntityID:    id.String(),
+			Action:      auditValues.ActionUpdate,
+			Before:      dto.NewTemplateResponse(before),
+			After:       dto.NewTemplateResponse(updated),
+			Description: fmt.Sprintf("Updated evaluation template %q", updated.Name),
+		})
+	})
+	if err != nil {
+		return values.Template{}, err
+	}
+	return updated, nil
+}
+
+// ----- Evaluations -----
+
+// ListEvaluations returns an athlete's evaluations, drafts included, newest first.
+func (s *Service) ListEvaluations(ctx context.Context, athleteID uuid.UUID, limit, offset int32) ([]values.Evaluation, *errLib.CommonError) {
+	if _, _, err := s.authorizeStaff(ctx, athleteID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListEvaluations(ctx, values.EvaluationFilter{
+		AthleteID:     athleteID,
+		IncludeDrafts: true,
+		Limit:         limit,
+		Offset:        offset,
+	})
+}
+
+func (s *Service) GetEvaluation(ctx context.Context, id uuid.UUID) (values.Evaluation, *errLib.CommonError) {
+	evaluation, err := s.repo.GetEvaluation(ctx, id)
+	if err != nil {
+		return values.Evaluation{}, err
+	}
+	if _, _, err = s.authorizeStaff(ctx, evaluation.AthleteID); err != nil {
+		return values.Evaluation{}, err
+	}
+	return evaluation, nil
+}
+
+func (s *Service) CreateEvaluation(ctx context.Context, athleteID uuid.UUID, details values.EvaluationDetails) (values.Evaluation, *errLib.CommonError) {
+	coachID, _, err := s.authorizeStaff(ctx, athleteID)
+	if err != nil {
+		return values.Evaluation{}, err
+	}
+
+	template, err := s.repo.GetTemplate(ctx, details.TemplateID)
+	if err != nil {
+		return values.Evaluation{}, err
+	}
+	if !template.IsActive {
+		return values.Evaluation{}, errLib.New("This evaluation template is no longer in use", http.StatusBadRequest)
+	}
+	if err = validateScores(template, details.Scores); err != nil {
+		return values.Evaluation{}, err
+	}
+	if details.ProgramID == nil {
+		details.ProgramID = template.ProgramID
+	}
+
+	var publishedAt *time.Time
+	if details.Publish {
+		now := time.Now()
+		publishedAt = &now
+	}
+
+	var created values.Evaluation
+	err = txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		txRepo := s.repo.WithTx(tx)
+
+		id, txErr := txRepo.CreateEvaluation(ctx, athleteID, coachID, details, publishedAt)
+		if txErr != nil {
+			return txErr
+		}
+		if txErr = txRepo.ReplaceScores(ctx, id, details.Scores); txErr != nil {
+			return txErr
+		}
+		if created, txErr = txRepo.GetEvaluation(ctx, id); txErr != nil {
+			return txErr
+		}
+
+		return s.staffActivityLogsService.RecordChange(ctx, tx, coachID, auditValues.Entry{
+			EntityType:  entityEvaluation,
+			EntityID:    id.String(),
+			Action:      auditValues.ActionCreate,
+			After:       dto.NewEvaluationResponse(created),
+			Description: fmt.Sprintf("Evaluated athlete %s with %q (%s)", athleteID, template.Name, created.Status),
+		})
+	})
+	if err != nil {
+		return values.Evaluation{}, err
+	}
+	return created, nil
+}
+
+// UpdateEvaluation edits an evaluation's details and replaces its scores. Coaches
+// can edit their own evaluations; admins any. Published evaluations stay published.
+func (s *Service) UpdateEvaluation(ctx context.Context, id uuid.UUID, details values.EvaluationDetails) (values.Evaluation, *errLib.CommonError) {
+	existing, err := s.repo.GetEvaluation(ctx, id)
+	if err != nil {
+		return values.Evaluation{}, err
+	}
+	actorID, admin, err := s.authorizeStaff(ctx, existing.AthleteID)
+	if err != nil {
+		return values.Evaluation{}, err
+	}
+	if !admin && !isAuthor(existing, actorID) {
+		return values.Evaluation{}, errLib.New("Only the coach who wrote this evaluation can edit it", http.StatusForbidden)
+	}
+
+	template, err := s.repo.GetTemplate(ctx, existing.TemplateID)
+	if err != nil {
+		return values.Evaluation{}, err
+	}
+	if err = validateScores(template, details.Scores); err != nil {
+		return values.Evaluation{}, err
+	}
+	if details.ProgramID == nil {
+		details.ProgramID = template.ProgramID
+	}
+
+	publishedAt := existing.PublishedAt
+	if publishedAt == nil && details.Publish {
+		now := time.Now()
+		publishedAt = &now
+	}
+
+	var updated values.Evaluation
+	err = txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		txRepo := s.repo.WithTx(tx)
+
+		if txErr := txRepo.UpdateEvaluation(ctx, id, details, publishedAt); txErr != nil {
+			return txErr
+		}
+		if txErr := txRepo.ReplaceScores(ctx, id, details.Scores); txErr != nil {
+			return txErr
+		}
+
+		var txErr *errLib.CommonError
+		if updated, txErr = txRepo.GetEvaluation(ctx, id); txErr != nil {
+			return txErr
+		}
+
+		return s.staffActivityLogsService.RecordChange(ctx, tx, actorID, auditValues.Entry{
+			EntityType:  entityEvaluation,
+			EntityID:    id.String(),
+			Action:      auditValues.ActionUpdate,
+			Before:      dto.NewEvaluationResponse(existing),
+			After:       dto.NewEvaluationResponse(updated),
+			Description: fmt.Sprintf("Updated %q evaluation of athlete %s", template.Name, existing.AthleteID),
+		})
+	})
+	if err != nil {
+		return values.Evaluation{}, err
+	}
+	return updated, nil
+}
+
+// PublishEvaluation makes a draft evaluation visible to the athlete's family.
+func (s *Service) PublishEvaluation(ctx context.Context, id uuid.UUID) (values.Evaluation, *errLib.CommonError) {
+	existing, err := s.repo.GetEvaluation(ctx, id)
+	if err != nil {
+		return values.Evaluation{}, err
+	}
+	actorID, admin, err := s.authorizeStaff(ctx, existing.AthleteID)
+	if err != nil {
+		return values.Evaluation{}, err
+	}
+	if !admin && !isAuthor(existing, actorID) {
+		return values.Evaluation{}, errLib.New("Only the coach who wrote this evaluation can publish it", http.StatusForbidden)
+	}
+	if existing.Status == values.EvaluationPublished {
+		return existing, nil
+	}
+
+	now := time.Now()
+	details := values.EvaluationDetails{
+		ProgramID:   existing.ProgramID,
+		Season:      existing.Season,
+		EvaluatedOn: existing.EvaluatedOn,
+		Summary:     existing.Summary,
+	}
+
+	var published values.Evaluation
+	err = txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		txRepo := s.repo.WithTx(tx)
+
+		if txErr := txRepo.UpdateEvaluation(ctx, id, details, &now); txErr != nil {
+			return txErr
+		}
+
+		var txErr *errLib.CommonError
+		if published, txErr = txRepo.GetEvaluation(ctx, id); txErr != nil {
+			return txErr
+		}
+
+		return s.staffActivityLogsService.RecordChange(ctx, tx, actorID, auditValues.Entry{
+			EntityType:  entityEvaluation,
+			EntityID:    id.String(),
+			Action:      actionPublish,
+			Before:      dto.NewEvaluationResponse(existing),
+			After:       dto.NewEvaluationResponse(published),
+			Description: fmt.Sprintf("Published %q evaluation of athlete %s", existing.TemplateName, existing.AthleteID),
+		})
+	})
+	if err != nil {
+		return values.Evaluation{}, err
+	}
+	return published, nil
+}
+
+// DeleteEvaluation removes an evaluation. Coaches can delete their own drafts;
+// published evaluations can only be removed by an admin.
+func (s *Service) DeleteEvaluation(ctx context.Context, id uuid.UUID) *errLib.CommonError {
+	existing, err := s.repo.GetEvaluation(ctx, id)
+	if err != nil {
+		return err
+	}
+	actorID, admin, err := s.authorizeStaff(ctx, existing.AthleteID)
+	if err != nil {
+		return err
+	}
+	if !admin {
+		if !isAuthor(existing, actorID) {
+			return errLib.New("Only the coach who wrote this evaluation can delete it", http.StatusForbidden)
+		}
+		if existing.Status == values.EvaluationPublished {
+			return errLib.New("Published evaluations can only be deleted by an admin", http.StatusForbidden)
+		}
+	}
+
+	return txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		if txErr := s.repo.WithTx(tx).DeleteEvaluation(ctx, id); txErr != nil {
+			return txErr
+		}
+
+		return s.staffActivityLogsService.RecordChange(ctx, tx, actorID, auditValues.Entry{
+			EntityType:  entityEvaluation,
+			EntityID:    id.String(),
+			Action:      auditValues.ActionDelete,
+			Before:      dto.NewEvaluationResponse(existing),
+			Description: fmt.Sprintf("Deleted %s %q evaluation of athlete %s", existing.Status, existing.TemplateName, existing.AthleteID),
+		})
+	})
+}
+
+func isAuthor(evaluation values.Evaluation, userID uuid.UUID) bool {
+	return evaluation.CoachID != nil && *evaluation.CoachID == userID
+}
+
+// validateScores checks every score is for one of the template's skills and within
+// that skill's scale. Skills can be left unrated.
+func validateScores(template values.Template, scores []values.ScoreInput) *errLib.CommonError {
+	skills := make(map[uuid.UUID]values.Skill, len(template.Skills))
+	for _, skill := range template.Skills {
+		skills[skill.ID] = skill
+	}
+
+	for i, score := range scores {
+		skill, ok := skills[score.SkillID]
+		if !ok {
+			return errLib.New(fmt.Sprintf("scores[%d]: skill is not on the %q template", i, template.Name), http.StatusBadRequest)
+		}
+		if score.Rating < skill.ScaleMin || score.Rating > skill.ScaleMax {
+			return errLib.New(fmt.Sprintf("scores[%d]: %s must be rated from %d to %d", i, skill.Name, skill.ScaleMin, skill.ScaleMax), http.StatusBadRequest)
+		}
+	}
+	return nil
+}
+
+// ----- Trends -----
+
+// GetTrends returns the rating history of each skill an athlete has been rated on
+// in published evaluations, optionally limited to one template.
+func (s *Service) GetTrends(ctx context.Context, athleteID uuid.UUID, templateID *uuid.UUID) ([]values.SkillTrend, *errLib.CommonError) {
+	if _, _, err := s.authorizeStaff(ctx, athleteID); err != nil {
+		return nil, err
+	}
+
+	ratings, err := s.repo.ListRatings(ctx, athleteID, templateID, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	return buildTrends(ratings), nil
+}
+
+// ----- Goals -----
+
+func (s *Service) ListGoals(ctx context.Context, athleteID uuid.UUID) ([]values.Goal, *errLib.CommonError) {
+	if _, _, err := s.authorizeStaff(ctx, athleteID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListGoals(ctx, athleteID, nil)
+}
+
+func (s *Service) CreateGoal(ctx context.Context, athleteID uuid.UUID, details values.GoalDetails) (values.Goal, *errLib.CommonError) {
+	actorID, _, err := s.authorizeStaff(ctx, athleteID)
+	if err != nil {
+		return values.Goal{}, err
+	}
+
+	var created values.Goal
+	err = txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		txRepo := s.repo.WithTx(tx)
+
+		id, txErr := txRepo.CreateGoal(ctx, athleteID, actorID, details, achievedAt(details.Status, nil))
+		if txErr != nil {
+			return txErr
+		}
+		if created, txErr = txRepo.GetGoal(ctx, id); txErr != nil {
+			return txErr
+		}
+
+		return s.staffActivityLogsService.RecordChange(ctx, tx, actorID, auditValues.Entry{
+			EntityType:  entityGoal,
+			EntityID:    id.String(),
+			Action:      auditValues.ActionCreate,
+			After:       dto.NewGoalResponse(created),
+			Description: fmt.Sprintf("Set goal %q for athlete %s", created.Title, athleteID),
+		})
+	})
+	if err != nil {
+		return values.Goal{}, err
+	}
+	return created, nil
+}
+
+func (s *Service) UpdateGoal(ctx context.Context, id uuid.UUID, details values.GoalDetails) (values.Goal, *errLib.CommonError) {
+	existing, err := s.repo.GetGoal(ctx, id)
+	if err != nil {
+		return values.Goal{}, err
+	}
+	actorID, _, err := s.authorizeStaff(ctx, existing.AthleteID)
+	if err != nil {
+		return values.Goal{}, err
+	}
+
+	var updated values.Goal
+	err = txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		txRepo := s.repo.WithTx(tx)
+
+		if txErr := txRepo.UpdateGoal(ctx, id, details, achievedAt(details.Status, existing.AchievedAt)); txErr != nil {
+			return txErr
+		}
+
+		var txErr *errLib.CommonError
+		if updated, txErr = txRepo.GetGoal(ctx, id); txErr != nil {
+			return txErr
+		}
+
+		return s.staffActivityLogsService.RecordChange(ctx, tx, actorID, auditValues.Entry{
+			EntityType:  entityGoal,
+			EntityID:    id.String(),
+			Action:      auditValues.ActionUpdate,
+			Before:      dto.NewGoalResponse(existing),
+			After:       dto.NewGoalResponse(updated),
+			Description: fmt.Sprintf("Updated goal %q for athlete %s (%s)", updated.Title, updated.AthleteID, updated.Status),
+		})
+	})
+	if err != nil {
+		return values.Goal{}, err
+	}
+	return updated, nil
+}
+
+func (s *Service) DeleteGoal(ctx context.Context, id uuid.UUID) *errLib.CommonError {
+	existing, err := s.repo.GetGoal(ctx, id)
+	if err != nil {
+		return err
+	}
+	actorID, _, err := s.authorizeStaff(ctx, existing.AthleteID)
+	if err != nil {
+		return err
+	}
+
+	return txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		if txErr := s.repo.WithTx(tx).DeleteGoal(ctx, id); txErr != nil {
+			return txErr
+		}
+
+		return s.staffActivityLogsService.RecordChange(ctx, tx, actorID, auditValues.Entry{
+			EntityType:  entityGoal,
+			EntityID:    id.String(),
+			Action:      auditValues.ActionDelete,
+			Before:      dto.NewGoalResponse(existing),
+			Description: fmt.Sprintf("Deleted goal %q for athlete %s", existing.Title, existing.AthleteID),
+		})
+	})
+}
+
+// achievedAt keeps when a goal was first achieved, clearing it if the goal is
+// reopened or dropped.
+func achievedAt(status values.GoalStatus, previous *time.Time) *time.Time {
+	if status != values.GoalAchieved {
+		return nil
+	}
+	if previous != nil {
+		return previous
+	}
+	now := time.Now()
+	return &now
+}
+
+// ----- Family views -----
+
+// GetDevelopment returns what an athlete's family sees: recent published
+// evaluations, skill trends and goals that were not dropped. Callers must have
+// checked the family's access to the athlete.
+func (s *Service) GetDevelopment(ctx context.Context, athleteID uuid.UUID) (values.Development, *errLib.CommonError) {
+	evaluations, err := s.repo.ListEvaluations(ctx, values.EvaluationFilter{AthleteID: athleteID, Limit: familyEvaluationLimit})
+	if err != nil {
+		return values.Development{}, err
+	}
+
+	ratings, err := s.repo.ListRatings(ctx, athleteID, nil, time.Time{})
+	if err != nil {
+		return values.Development{}, err
+	}
+
+	goals, err := s.repo.ListGoals(ctx, athleteID, nil)
+	if err != nil {
+		return values.Development{}, err
+	}
+
+	return values.Development{
+		Evaluations: evaluations,
+		Trends:      buildTrends(ratings),
+		Goals:       withoutDropped(goals),
+	}, nil
+}
+
+func withoutDropped(goals []values.Goal) []values.Goal {
+	kept := make([]values.Goal, 0, len(goals))
+	for _, goal := range goals {
+		if goal.Status != values.GoalDropped {
+			kept = append(kept, goal)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,219 @@
+package development
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	values "api/internal/domains/development/values"
+	errLib "api/internal/libs/errors"
+	contextUtils "api/utils/context"
+	"api/utils/timezone"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildTrends(t *testing.T) {
+	shooting, passing := uuid.New(), uuid.New()
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+	rating := func(skill uuid.UUID, name string, on time.Time, r int32) values.Rating {
+		return values.Rating{SkillID: skill, SkillName: name, EvaluatedOn: on, ScaleMin: 1, ScaleMax: 5, Rating: r}
+	}
+
+	trends := buildTrends([]values.Rating{
+		rating(passing, "Passing", day(1), 4),
+		rating(shooting, "Shooting", day(1), 2),
+		rating(shooting, "Shooting", day(15), 4),
+		rating(passing, "Passing", day(15), 3),
+	})
+
+	assert.Len(t, trends, 2)
+	assert.Equal(t, "Passing", trends[0].SkillName, "skills keep the order they were first rated in")
+	assert.Equal(t, int32(-1), trends[0].Change)
+	assert.Equal(t, values.TrendDeclining, trends[0].Direction)
+	assert.Equal(t, int32(2), trends[1].Change)
+	assert.Equal(t, values.TrendImproving, trends[1].Direction)
+	assert.Len(t, trends[1].Points, 2)
+
+	single := buildTrends([]values.Rating{rating(shooting, "Shooting", day(1), 3)})
+	assert.Equal(t, values.TrendSteady, single[0].Direction)
+	assert.NotNil(t, buildTrends(nil), "no ratings is an empty list")
+}
+
+func TestReportPeriod(t *testing.T) {
+	toronto, err := time.LoadLocation("America/Toronto")
+	assert.NoError(t, err)
+
+	from, to := reportPeriod(time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC), toronto)
+	assert.Equal(t, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC), to)
+
+	// Still December locally on the evening of the 31st
+	from, to = reportPeriod(time.Date(2027, 1, 1, 2, 0, 0, 0, time.UTC), toronto)
+	assert.Equal(t, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2026, 11, 30, 0, 0, 0, 0, time.UTC), to)
+}
+
+func TestValidateScores(t *testing.T) {
+	skill := values.Skill{ID: uuid.New(), Name: "Shooting", ScaleMin: 1, ScaleMax: 5}
+	template := values.Template{Name: "Skills", Skills: []values.Skill{skill}}
+
+	assert.Nil(t, validateScores(template, []values.ScoreInput{{SkillID: skill.ID, Rating: 5}}))
+	assert.Nil(t, validateScores(template, nil), "skills can be left unrated")
+	assert.NotNil(t, validateScores(template, []values.ScoreInput{{SkillID: skill.ID, Rating: 6}}), "above the scale")
+	assert.NotNil(t, validateScores(template, []values.ScoreInput{{SkillID: skill.ID, Rating: 0}}), "below the scale")
+	assert.NotNil(t, validateScores(template, []values.ScoreInput{{SkillID: uuid.New(), Rating: 3}}), "skill from another template")
+}
+
+type fakeDevelopmentRecords struct {
+	athletes map[uuid.UUID]bool
+	coached  map[[2]uuid.UUID]bool // coach, athlete
+	reports  map[uuid.UUID]values.ProgressReport
+	// evaluated and issued drive report generation
+	evaluated map[uuid.UUID]int32
+	issued    map[uuid.UUID]bool
+	from, to  time.Time
+}
+
+func (f *fakeDevelopmentRecords) IsAthlete(_ context.Context, athleteID uuid.UUID) (bool, *errLib.CommonError) {
+	return f.athletes[athleteID], nil
+}
+
+func (f *fakeDevelopmentRecords) CoachCanEvaluate(_ context.Context, coachID, athleteID uuid.UUID) (bool, *errLib.CommonError) {
+	return f.coached[[2]uuid.UUID{coachID, athleteID}], nil
+}
+
+func (f *fakeDevelopmentRecords) ListAthletesEvaluatedBetween(_ context.Context, from, to time.Time) (map[uuid.UUID]int32, *errLib.CommonError) {
+	f.from, f.to = from, to
+	return f.evaluated, nil
+}
+
+func (f *fakeDevelopmentRecords) CreateProgressReport(_ context.Context, athleteID uuid.UUID, _, _ time.Time, _ int32) (bool, *errLib.CommonError) {
+	if f.issued[athleteID] {
+		return false, nil
+	}
+	f.issued[athleteID] = true
+	return true, nil
+}
+
+func (f *fakeDevelopmentRecords) ListProgressReports(_ context.Context, athleteID uuid.UUID) ([]values.ProgressReport, *errLib.CommonError) {
+	var reports []values.ProgressReport
+	for _, report := range f.reports {
+		if report.AthleteID == athleteID {
+			reports = append(reports, report)
+		}
+	}
+	return reports, nil
+}
+
+func (f *fakeDevelopmentRecords) GetProgressReport(_ context.Context, id uuid.UUID) (values.ProgressReport, *errLib.CommonError) {
+	report, ok := f.reports[id]
+	if !ok {
+		return values.ProgressReport{}, errLib.New("Progress report not found", http.StatusNotFound)
+	}
+	return report, nil
+}
+
+func asUser(userID uuid.UUID, role contextUtils.CtxRole) context.Context {
+	ctx := context.WithValue(context.Background(), contextUtils.UserIDKey, userID)
+	return context.WithValue(ctx, contextUtils.RoleKey, role)
+}
+
+func TestAuthorizeStaff(t *testing.T) {
+	athlete, otherAthlete := uuid.New(), uuid.New()
+	coach, admin := uuid.New(), uuid.New()
+	service := &Service{records: &fakeDevelopmentRecords{
+		athletes: map[uuid.UUID]bool{athlete: true, otherAthlete: true},
+		coached:  map[[2]uuid.UUID]bool{{coach, athlete}: true},
+	}}
+
+	userID, isAdmin, err := service.authorizeStaff(asUser(coach, contextUtils.RoleCoach), athlete)
+	require.Nil(t, err, "a coach works with athletes on their teams and programs")
+	assert.Equal(t, coach, userID)
+	assert.False(t, isAdmin)
+
+	_, _, err = service.authorizeStaff(asUser(coach, contextUtils.RoleCoach), otherAthlete)
+	require.NotNil(t, err, "but not with anyone else")
+	assert.Equal(t, http.StatusForbidden, err.HTTPCode)
+
+	_, isAdmin, err = service.authorizeStaff(asUser(admin, contextUtils.RoleAdmin), otherAthlete)
+	require.Nil(t, err, "admins work with every athlete")
+	assert.True(t, isAdmin)
+
+	_, _, err = service.authorizeStaff(asUser(admin, contextUtils.RoleAdmin), uuid.New())
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusNotFound, err.HTTPCode)
+
+	_, _, err = service.authorizeStaff(asUser(uuid.New(), contextUtils.RoleAthlete), athlete)
+	require.NotNil(t, err, "families only see development through the family domain")
+	assert.Equal(t, http.StatusForbidden, err.HTTPCode)
+
+	_, _, err = service.authorizeStaff(context.Background(), athlete)
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, err.HTTPCode)
+}
+
+func TestEvaluationsNeedAnAssignedCoach(t *testing.T) {
+	athlete, coach := uuid.New(), uuid.New()
+	service := &Service{records: &fakeDevelopmentRecords{athletes: map[uuid.UUID]bool{athlete: true}}}
+	ctx := asUser(coach, contextUtils.RoleCoach)
+
+	_, err := service.CreateEvaluation(ctx, athlete, values.EvaluationDetails{TemplateID: uuid.New()})
+	require.NotNil(t, err, "an unassigned coach cannot submit an evaluation")
+	assert.Equal(t, http.StatusForbidden, err.HTTPCode)
+
+	_, err = service.ListEvaluations(ctx, athlete, 10, 0)
+	require.NotNil(t, err, "nor see the athlete's evaluations")
+	assert.Equal(t, http.StatusForbidden, err.HTTPCode)
+
+	_, err = service.ListProgressReports(ctx, athlete)
+	require.NotNil(t, err, "nor their progress reports")
+	assert.Equal(t, http.StatusForbidden, err.HTTPCode)
+}
+
+func TestProgressReportsForFamilies(t *testing.T) {
+	child, sibling := uuid.New(), uuid.New()
+	childReport := values.ProgressReport{ID: uuid.New(), AthleteID: child}
+	siblingReport := values.ProgressReport{ID: uuid.New(), AthleteID: sibling}
+	records := &fakeDevelopmentRecords{reports: map[uuid.UUID]values.ProgressReport{
+		childReport.ID:   childReport,
+		siblingReport.ID: siblingReport,
+	}}
+	service := &Service{records: records}
+
+	reports, err := service.ListAthleteProgressReports(context.Background(), child)
+	require.Nil(t, err)
+	assert.Equal(t, []values.ProgressReport{childReport}, reports)
+
+	// The family domain checks access to the child in the path, so a report of any
+	// other athlete has to be refused here
+	_, _, err = service.GetAthleteProgressReportPDF(context.Background(), child, siblingReport.ID)
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusNotFound, err.HTTPCode)
+}
+
+func TestGenerateProgressReports(t *testing.T) {
+	issuedAlready, evaluated := uuid.New(), uuid.New()
+	records := &fakeDevelopmentRecords{
+		evaluated: map[uuid.UUID]int32{issuedAlready: 2, evaluated: 1},
+		issued:    map[uuid.UUID]bool{issuedAlready: true},
+	}
+	service := &Service{records: records}
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	created, err := service.GenerateProgressReports(context.Background(), now)
+	require.Nil(t, err)
+	assert.Equal(t, 1, created, "athletes who already have the month's report are skipped")
+	assert.True(t, records.issued[evaluated])
+
+	from, to := reportPeriod(now, timezone.Default())
+	assert.Equal(t, from, records.from, "reports cover last month")
+	assert.Equal(t, to, records.to)
+
+	created, err = service.GenerateProgressReports(context.Background(), now)
+	require.Nil(t, err)
+	assert.Zero(t, created, "running it again is harmless")
+}
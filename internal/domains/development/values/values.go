@@ -0,0 +1,238 @@
+package values
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EvaluationStatus is whether families can see an evaluation yet.
+type EvaluationStatus string
+
+const (
+	EvaluationDraft     EvaluationStatus = "draft"
+	EvaluationPublished EvaluationStatus = "published"
+)
+
+// GoalStatus tracks a development goal from being set to being met or dropped.
+type GoalStatus string
+
+const (
+	GoalActive   GoalStatus = "active"
+	GoalAchieved GoalStatus = "achieved"
+	GoalDropped  GoalStatus = "dropped"
+)
+
+func (s GoalStatus) Valid() bool {
+	switch s {
+	case GoalActive, GoalAchieved, GoalDropped:
+		return true
+	}
+	return false
+}
+
+// TrendDirection summarises how a skill's rating moved between the first and latest
+// evaluation.
+type TrendDirection string
+
+const (
+	TrendImproving TrendDirection = "improving"
+	TrendSteady    TrendDirection = "steady"
+	TrendDeclining TrendDirection = "declining"
+)
+
+// Skill is one rated line on an evaluation template. Ratings run from ScaleMin to
+// ScaleMax inclusive.
+type Skill struct {
+	ID          uuid.UUID
+	TemplateID  uuid.UUID
+	Name        string
+	Category    *string
+	Description *string
+	ScaleMin    int32
+	ScaleMax    int32
+	SortOrder   int32
+}
+
+// Template is a reusable evaluation form. A template with a ProgramID is meant for
+// that program's athletes.
+type Template struct {
+	ID          uuid.UUID
+	Name        string
+	Description *string
+	ProgramID   *uuid.UUID
+	IsActive    bool
+	Skills      []Skill
+	CreatedBy   *uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// TemplateDetails is what admins set when creating or editing a template. Skills
+// is nil when an edit leaves the skills alone.
+type TemplateDetails struct {
+	Name        string
+	Description *string
+	ProgramID   *uuid.UUID
+	IsActive    bool
+	Skills      []Skill
+}
+
+// Score is the rating an evaluation gave one skill.
+type Score struct {
+	SkillID   uuid.UUID
+	SkillName string
+	Category  *string
+	ScaleMin  int32
+	ScaleMax  int32
+	Rating    int32
+	Comment   *string
+}
+
+// Evaluation is a coach's assessment of an athlete against a template.
+type Evaluation struct {
+	ID           uuid.UUID
+	AthleteID    uuid.UUID
+	TemplateID   uuid.UUID
+	TemplateName string
+	ProgramID    *uuid.UUID
+	ProgramName  *string
+	Season       *string
+	EvaluatedOn  time.Time
+	Summary      *string
+	Status       EvaluationStatus
+	CoachID      *uuid.UUID
+	CoachName    string
+	PublishedAt  *time.Time
+	Scores       []Score
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// ScoreInput is a rating submitted for one skill.
+type ScoreInput struct {
+	SkillID uuid.UUID
+	Rating  int32
+	Comment *string
+}
+
+// EvaluationDetails is what a coach submits. TemplateID is ignored on edits; an
+// evaluation stays on the template it was started with.
+type EvaluationDetails struct {
+	TemplateID  uuid.UUID
+	ProgramID   *uuid.UUID
+	Season      *string
+	EvaluatedOn time.Time
+	Summary     *string
+	Scores      []ScoreInput
+	Publish     bool
+}
+
+// EvaluationFilter narrows an athlete's evaluations. Zero dates are open-ended.
+type EvaluationFilter struct {
+	AthleteID     uuid.UUID
+	IncludeDrafts bool
+	From          time.Time
+	To            time.Time
+	Limit         int32
+	Offset        int32
+}
+
+// Rating is one published rating of a skill, used to build trends.
+type Rating struct {
+	EvaluationID uuid.UUID
+	EvaluatedOn  time.Time
+	SkillID      uuid.UUID
+	SkillName    string
+	Category     *string
+	ScaleMin     int32
+	ScaleMax     int32
+	Rating       int32
+}
+
+// TrendPoint is a skill's rating on one evaluation.
+type TrendPoint struct {
+	EvaluationID uuid.UUID
+	EvaluatedOn  time.Time
+	Rating       int32
+}
+
+// SkillTrend is a skill's rating history, oldest first.
+type SkillTrend struct {
+	SkillID   uuid.UUID
+	SkillName string
+	Category  *string
+	ScaleMin  int32
+	ScaleMax  int32
+	Points    []TrendPoint
+	First     int32
+	Latest    int32
+	Change    int32
+	Direction TrendDirection
+}
+
+// Goal is a development goal set for an athlete, optionally against a skill.
+type Goal struct {
+	ID           uuid.UUID
+	AthleteID    uuid.UUID
+	SkillID      *uuid.UUID
+	SkillName    *string
+	Title        string
+	Description  *string
+	TargetRating *int32
+	TargetDate   *time.Time
+	Status       GoalStatus
+	CreatedBy    *uuid.UUID
+	AchievedAt   *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// GoalDetails is what a coach sets on a goal.
+type GoalDetails struct {
+	SkillID      *uuid.UUID
+	Title        string
+	Description  *string
+	TargetRating *int32
+	TargetDate   *time.Time
+	Status       GoalStatus
+}
+
+// Development is everything families see about an athlete's progress.
+type Development struct {
+	Evaluations []Evaluation
+	Trends      []SkillTrend
+	Goals       []Goal
+}
+
+// ProgressReport records a report period issued to an athlete's family.
+type ProgressReport struct {
+	ID              uuid.UUID
+	AthleteID       uuid.UUID
+	PeriodStart     time.Time
+	PeriodEnd       time.Time
+	EvaluationCount int32
+	EmailedAt       *time.Time
+	CreatedAt       time.Time
+}
+
+// AthleteContact is who a progress report is addressed to. Reports go to the
+// parent when the athlete has one.
+type AthleteContact struct {
+	ID              uuid.UUID
+	FirstName       string
+	LastName        string
+	Email           *string
+	ParentFirstName *string
+	ParentEmail     *string
+}
+
+// ReportContent is what a progress report prints: the period's evaluations, skill
+// trends up to the end of the period, and the athlete's goals.
+type ReportContent struct {
+	Report      ProgressReport
+	Athlete     AthleteContact
+	Evaluations []Evaluation
+	Trends      []SkillTrend
+	Goals       []Goal
+}
@@ -0,0 +1,245 @@
+package discount
+
+import (
+	"net/http"
+	"time"
+
+	values "api/internal/domains/discount/values"
+	errLib "api/internal/libs/errors"
+	"api/internal/libs/validators"
+
+	"github.com/google/uuid"
+)
+
+// RuleRequestDto creates or updates an automatic discount rule. amount_off is in cents.
+type RuleRequestDto struct {
+	Name                     string  `json:"name" validate:"required,notwhitespace,max=100"`
+	Description              string  `json:"description"`
+	RuleType                 string  `json:"rule_type" validate:"required,oneof=sibling early_bird member_pricing bundle"`
+	AppliesTo                string  `json:"applies_to" validate:"required,oneof=membership program event"`
+	TargetID                 *string `json:"target_id,omitempty" validate:"omitempty,uuid"`
+	DiscountType             string  `json:"discount_type" validate:"required,oneof=percentage fixed_amount"`
+	DiscountPercent          int     `json:"discount_percent" validate:"omitempty,gte=0,lte=100"`
+	AmountOff                int64   `json:"amount_off" validate:"omitempty,gte=0"`
+	MinSiblings              *int    `json:"min_siblings,omitempty" validate:"omitempty,gte=1"`
+	EarlyBirdDeadline        string  `json:"early_bird_deadline,omitempty"`
+	RequiredMembershipPlanID *string `json:"required_membership_plan_id,omitempty" validate:"omitempty,uuid"`
+	MinPrograms              *int    `json:"min_programs,omitempty" validate:"omitempty,gte=2"`
+	Priority                 *int    `json:"priority,omitempty" validate:"omitempty,gte=0"`
+	Stackable                *bool   `json:"stackable,omitempty"`
+	IsActive                 bool    `json:"is_active"`
+	ValidFrom                string  `json:"valid_from,omitempty"`
+	ValidTo                  string  `json:"valid_to,omitempty"`
+}
+
+func (dto *RuleRequestDto) toValues() (values.RuleCreateValues, *errLib.CommonError) {
+	if err := validators.ValidateDto(dto); err != nil {
+		return values.RuleCreateValues{}, err
+	}
+
+	if dto.DiscountType == string(values.TypePercentage) && (dto.DiscountPercent <= 0 || dto.AmountOff != 0) {
+		return values.RuleCreateValues{}, errLib.New("percentage rules need a discount_percent between 1 and 100 and no amount_off", http.StatusBadRequest)
+	}
+	if dto.DiscountType == string(values.TypeFixedAmount) && (dto.AmountOff <= 0 || dto.DiscountPercent != 0) {
+		return values.RuleCreateValues{}, errLib.New("fixed_amount rules need an amount_off greater than 0 and no discount_percent", http.StatusBadRequest)
+	}
+
+	details := values.RuleCreateValues{
+		Name:            dto.Name,
+		Description:     dto.Description,
+		RuleType:        values.RuleType(dto.RuleType),
+		AppliesTo:       values.ItemType(dto.AppliesTo),
+		DiscountType:    values.DiscountType(dto.DiscountType),
+		DiscountPercent: dto.DiscountPercent,
+		AmountOff:       dto.AmountOff,
+		Priority:        100,
+		Stackable:       true,
+		IsActive:        dto.IsActive,
+		ValidFrom:       time.Now(),
+	}
+	if dto.Priority != nil {
+		details.Priority = *dto.Priority
+	}
+	if dto.Stackable != nil {
+		details.Stackable = *dto.Stackable
+	}
+
+	var err *errLib.CommonError
+	if details.TargetID, err = parseOptionalUUID(dto.TargetID); err != nil {
+		return values.RuleCreateValues{}, err
+	}
+
+	switch details.RuleType {
+	case values.RuleSibling:
+		if dto.MinSiblings == nil {
+			return values.RuleCreateValues{}, errLib.New("min_siblings is required for sibling rules", http.StatusBadRequest)
+		}
+		details.MinSiblings = dto.MinSiblings
+	case values.RuleEarlyBird:
+		if dto.EarlyBirdDeadline == "" {
+			return values.RuleCreateValues{}, errLib.New("early_bird_deadline is required for early_bird rules", http.StatusBadRequest)
+		}
+		deadline, parseErr := time.Parse(time.RFC3339, dto.EarlyBirdDeadline)
+		if parseErr != nil {
+			return values.RuleCreateValues{}, errLib.New("Invalid early_bird_deadline format. Expected RFC3339", http.StatusBadRequest)
+		}
+		details.EarlyBirdDeadline = &deadline
+	case values.RuleMemberPricing:
+		if details.RequiredMembershipPlanID, err = parseOptionalUUID(dto.RequiredMembershipPlanID); err != nil {
+			return values.RuleCreateValues{}, err
+		}
+	case values.RuleBundle:
+		if details.AppliesTo != values.ItemProgram {
+			return values.RuleCreateValues{}, errLib.New("bundle rules apply to programs", http.StatusBadRequest)
+		}
+		if dto.MinPrograms == nil {
+			return values.RuleCreateValues{}, errLib.New("min_programs is required for bundle rules", http.StatusBadRequest)
+		}
+		details.MinPrograms = dto.MinPrograms
+	}
+
+	if dto.ValidFrom != "" {
+		validFrom, parseErr := time.Parse(time.RFC3339, dto.ValidFrom)
+		if parseErr != nil {
+			return values.RuleCreateValues{}, errLib.New("Invalid valid_from format. Expected RFC3339", http.StatusBadRequest)
+		}
+		details.ValidFrom = validFrom
+	}
+	if dto.ValidTo != "" {
+		validTo, parseErr := time.Parse(time.RFC3339, dto.ValidTo)
+		if parseErr != nil {
+			return values.RuleCreateValues{}, errLib.New("Invalid valid_to format. Expected RFC3339", http.StatusBadRequest)
+		}
+		if !validTo.After(details.ValidFrom) {
+			return values.RuleCreateValues{}, errLib.New("valid_to must be after valid_from", http.StatusBadRequest)
+		}
+		details.ValidTo = &validTo
+	}
+
+	return details, nil
+}
+
+func (dto *RuleRequestDto) ToCreateValues() (values.RuleCreateValues, *errLib.CommonError) {
+	return dto.toValues()
+}
+
+func (dto *RuleRequestDto) ToUpdateValues(idStr string) (values.RuleUpdateValues, *errLib.CommonError) {
+	id, err := validators.ParseUUID(idStr)
+	if err != nil {
+		return values.RuleUpdateValues{}, err
+	}
+	details, err := dto.toValues()
+	if err != nil {
+		return values.RuleUpdateValues{}, err
+	}
+	return values.RuleUpdateValues{ID: id, RuleCreateValues: details}, nil
+}
+
+func parseOptionalUUID(idStr *string) (*uuid.UUID, *errLib.CommonError) {
+	if idStr == nil || *idStr == "" {
+		return nil, nil
+	}
+	id, err := validators.ParseUUID(*idStr)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+type RuleResponseDto struct {
+	ID                       uuid.UUID  `json:"id"`
+	Name                     string     `json:"name"`
+	Description              string     `json:"description,omitempty"`
+	RuleType                 string     `json:"rule_type"`
+	AppliesTo                string     `json:"applies_to"`
+	TargetID                 *uuid.UUID `json:"target_id,omitempty"`
+	DiscountType             string     `json:"discount_type"`
+	DiscountPercent          int        `json:"discount_percent,omitempty"`
+	AmountOff                int64      `json:"amount_off,omitempty"`
+	MinSiblings              *int       `json:"min_siblings,omitempty"`
+	EarlyBirdDeadline        *time.Time `json:"early_bird_deadline,omitempty"`
+	RequiredMembershipPlanID *uuid.UUID `json:"required_membership_plan_id,omitempty"`
+	MinPrograms              *int       `json:"min_programs,omitempty"`
+	Priority                 int        `json:"priority"`
+	Stackable                bool       `json:"stackable"`
+	IsActive                 bool       `json:"is_active"`
+	ValidFrom                time.Time  `json:"valid_from"`
+	ValidTo                  *time.Time `json:"valid_to,omitempty"`
+	CreatedAt                time.Time  `json:"created_at"`
+	UpdatedAt                time.Time  `json:"updated_at"`
+}
+
+func NewRuleResponse(rule values.RuleReadValues) RuleResponseDto {
+	return RuleResponseDto{
+		ID:                       rule.ID,
+		Name:                     rule.Name,
+		Description:              rule.Description,
+		RuleType:                 string(rule.RuleType),
+		AppliesTo:                string(rule.AppliesTo),
+		TargetID:                 rule.TargetID,
+		DiscountType:             string(rule.DiscountType),
+		DiscountPercent:          rule.DiscountPercent,
+		AmountOff:                rule.AmountOff,
+		MinSiblings:              rule.MinSiblings,
+		EarlyBirdDeadline:        rule.EarlyBirdDeadline,
+		RequiredMembershipPlanID: rule.RequiredMembershipPlanID,
+		MinPrograms:              rule.MinPrograms,
+		Priority:                 rule.Priority,
+		Stackable:                rule.Stackable,
+		IsActive:                 rule.IsActive,
+		ValidFrom:                rule.ValidFrom,
+		ValidTo:                  rule.ValidTo,
+		CreatedAt:                rule.CreatedAt,
+		UpdatedAt:                rule.UpdatedAt,
+	}
+}
+
+// AdjustmentResponseDto is one discount in a price breakdown. Amounts are in cents.
+type AdjustmentResponseDto struct {
+	Source   string    `json:"source"`
+	ID       uuid.UUID `json:"id"`
+	Name     string    `json:"name"`
+	RuleType string    `json:"rule_type,omitempty"`
+	Amount   int64     `json:"amount"`
+	Reason   string    `json:"reason,omitempty"`
+}
+
+// PriceQuoteResponseDto is what the customer would pay at checkout, before tax. Amounts are in cents.
+type PriceQuoteResponseDto struct {
+	ItemType      string                  `json:"item_type"`
+	ItemID        uuid.UUID               `json:"item_id"`
+	Currency      string                  `json:"currency"`
+	BasePrice     int64                   `json:"base_price"`
+	Applied       []AdjustmentResponseDto `json:"applied"`
+	NotApplied    []AdjustmentResponseDto `json:"not_applied"`
+	DiscountTotal int64                   `json:"discount_total"`
+	Total         int64                   `json:"total"`
+}
+
+func NewPriceQuoteResponse(quote values.PriceQuote) PriceQuoteResponseDto {
+	return PriceQuoteResponseDto{
+		ItemType:      string(quote.Item.Type),
+		ItemID:        quote.Item.ID,
+		Currency:      quote.Currency,
+		BasePrice:     quote.BasePrice,
+		Applied:       newAdjustmentResponses(quote.Applied),
+		NotApplied:    newAdjustmentResponses(quote.NotApplied),
+		DiscountTotal: quote.DiscountTotal,
+		Total:         quote.Total,
+	}
+}
+
+func newAdjustmentResponses(adjustments []values.Adjustment) []AdjustmentResponseDto {
+	res := make([]AdjustmentResponseDto, len(adjustments))
+	for i, adjustment := range adjustments {
+		res[i] = AdjustmentResponseDto{
+			Source:   adjustment.Source,
+			ID:       adjustment.ID,
+			Name:     adjustment.Name,
+			RuleType: string(adjustment.RuleType),
+			Amount:   adjustment.Amount,
+			Reason:   adjustment.Reason,
+		}
+	}
+	return res
+}
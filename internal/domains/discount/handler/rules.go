@@ -0,0 +1,144 @@
+package discount
+
+import (
+	"net/http"
+
+	dto "api/internal/domains/discount/dto"
+	responseHandlers "api/internal/libs/responses"
+	"api/internal/libs/validators"
+
+	"github.com/go-chi/chi"
+)
+
+// CreateDiscountRule creates an automatic discount rule
+// @Summary Create an automatic discount rule
+// @Description Creates a rule that discounts checkouts automatically, without a code: sibling, early_bird, member_pricing or bundle. Stackable rules combine in priority order; a rule that isn't stackable only applies on its own, whichever saves the customer more.
+// @Tags discounts
+// @Accept json
+// @Produce json
+// @Param request body dto.RuleRequestDto true "Discount rule details"
+// @Success 201 {object} dto.RuleResponseDto "Discount rule created successfully"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid input"
+// @Failure 404 {object} map[string]interface{} "Not Found: Membership plan not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Security Bearer
+// @Router /discounts/rules [post]
+func (h *Handler) CreateDiscountRule(w http.ResponseWriter, r *http.Request) {
+	var req dto.RuleRequestDto
+	if err := validators.ParseJSON(r.Body, &req); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	details, err := req.ToCreateValues()
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	created, err := h.Service.CreateRule(r.Context(), details)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewRuleResponse(created), http.StatusCreated)
+}
+
+// GetDiscountRules lists the automatic discount rules
+// @Summary List automatic discount rules
+// @Tags discounts
+// @Produce json
+// @Success 200 {array} dto.RuleResponseDto "Discount rules retrieved successfully"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Security Bearer
+// @Router /discounts/rules [get]
+func (h *Handler) GetDiscountRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.Service.GetRules(r.Context())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	resp := make([]dto.RuleResponseDto, len(rules))
+	for i, rule := range rules {
+		resp[i] = dto.NewRuleResponse(rule)
+	}
+	responseHandlers.RespondWithSuccess(w, resp, http.StatusOK)
+}
+
+// GetDiscountRule retrieves an automatic discount rule by ID
+// @Summary Get automatic discount rule by ID
+// @Tags discounts
+// @Produce json
+// @Param id path string true "Discount rule ID" format(uuid)
+// @Success 200 {object} dto.RuleResponseDto "Discount rule retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid discount rule ID format"
+// @Failure 404 {object} map[string]interface{} "Not Found: Discount rule not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Security Bearer
+// @Router /discounts/rules/{id} [get]
+func (h *Handler) GetDiscountRule(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	rule, err := h.Service.GetRule(r.Context(), id)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewRuleResponse(rule), http.StatusOK)
+}
+
+// UpdateDiscountRule updates an automatic discount rule
+// @Summary Update an automatic discount rule
+// @Tags discounts
+// @Accept json
+// @Produce json
+// @Param id path string true "Discount rule ID" format(uuid)
+// @Param request body dto.RuleRequestDto true "Discount rule details"
+// @Success 200 {object} dto.RuleResponseDto "Discount rule updated successfully"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid input"
+// @Failure 404 {object} map[string]interface{} "Not Found: Discount rule or membership plan not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Security Bearer
+// @Router /discounts/rules/{id} [put]
+func (h *Handler) UpdateDiscountRule(w http.ResponseWriter, r *http.Request) {
+	var req dto.RuleRequestDto
+	if err := validators.ParseJSON(r.Body, &req); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	details, err := req.ToUpdateValues(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	updated, err := h.Service.UpdateRule(r.Context(), details)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewRuleResponse(updated), http.StatusOK)
+}
+
+// DeleteDiscountRule deletes an automatic discount rule
+// @Summary Delete an automatic discount rule
+// @Tags discounts
+// @Param id path string true "Discount rule ID" format(uuid)
+// @Success 204 "Discount rule deleted successfully"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid discount rule ID format"
+// @Failure 404 {object} map[string]interface{} "Not Found: Discount rule not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Security Bearer
+// @Router /discounts/rules/{id} [delete]
+func (h *Handler) DeleteDiscountRule(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	if err := h.Service.DeleteRule(r.Context(), id); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, nil, http.StatusNoContent)
+}
@@ -0,0 +1,236 @@
+package discount
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	db "api/internal/domains/discount/persistence/sqlc/generated"
+	values "api/internal/domains/discount/values"
+	errLib "api/internal/libs/errors"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+func mapDbRule(r db.DiscountRule) values.RuleReadValues {
+	rule := values.RuleReadValues{
+		ID: r.ID,
+		RuleCreateValues: values.RuleCreateValues{
+			Name:            r.Name,
+			Description:     r.Description.String,
+			RuleType:        values.RuleType(r.RuleType),
+			AppliesTo:       values.ItemType(r.AppliesTo),
+			DiscountType:    values.DiscountType(r.DiscountType),
+			DiscountPercent: int(r.DiscountPercent),
+			AmountOff:       int64(r.AmountOff),
+			Priority:        int(r.Priority),
+			Stackable:       r.Stackable,
+			IsActive:        r.IsActive,
+			ValidFrom:       r.ValidFrom,
+		},
+		CreatedAt: r.CreatedAt,
+		UpdatedAt: r.UpdatedAt,
+	}
+	if r.TargetID.Valid {
+		rule.TargetID = &r.TargetID.UUID
+	}
+	if r.MinSiblings.Valid {
+		minSiblings := int(r.MinSiblings.Int32)
+		rule.MinSiblings = &minSiblings
+	}
+	if r.EarlyBirdDeadline.Valid {
+		rule.EarlyBirdDeadline = &r.EarlyBirdDeadline.Time
+	}
+	if r.RequiredMembershipPlanID.Valid {
+		rule.RequiredMembershipPlanID = &r.RequiredMembershipPlanID.UUID
+	}
+	if r.MinPrograms.Valid {
+		minPrograms := int(r.MinPrograms.Int32)
+		rule.MinPrograms = &minPrograms
+	}
+	if r.ValidTo.Valid {
+		rule.ValidTo = &r.ValidTo.Time
+	}
+	return rule
+}
+
+func nullUUID(id *uuid.UUID) uuid.NullUUID {
+	if id == nil {
+		return uuid.NullUUID{}
+	}
+	return uuid.NullUUID{UUID: *id, Valid: true}
+}
+
+func nullInt32(n *int) sql.NullInt32 {
+	if n == nil {
+		return sql.NullInt32{}
+	}
+	return sql.NullInt32{Int32: int32(*n), Valid: true}
+}
+
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
+
+// ruleWriteError maps a failed insert or update of a rule to an API error
+func ruleWriteError(err error, action string) *errLib.CommonError {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch {
+		case pqErr.Constraint == "discount_rules_required_membership_plan_id_fkey":
+			return errLib.New("Membership plan not found", http.StatusNotFound)
+		case pqErr.Code == "23514":
+			return errLib.New("Discount rule settings are not valid for its rule type", http.StatusBadRequest)
+		}
+	}
+	log.Printf("Failed to %s discount rule: %v", action, err)
+	return errLib.New("Internal server error", http.StatusInternalServerError)
+}
+
+func (r *Repository) CreateRule(ctx context.Context, details values.RuleCreateValues) (values.RuleReadValues, *errLib.CommonError) {
+	rule, err := r.Queries.CreateDiscountRule(ctx, db.CreateDiscountRuleParams{
+		Name:                     details.Name,
+		Description:              sql.NullString{String: details.Description, Valid: details.Description != ""},
+		RuleType:                 string(details.RuleType),
+		AppliesTo:                string(details.AppliesTo),
+		TargetID:                 nullUUID(details.TargetID),
+		DiscountType:             string(details.DiscountType),
+		DiscountPercent:          int32(details.DiscountPercent),
+		AmountOff:                int32(details.AmountOff),
+		MinSiblings:              nullInt32(details.MinSiblings),
+		EarlyBirdDeadline:        nullTime(details.EarlyBirdDeadline),
+		RequiredMembershipPlanID: nullUUID(details.RequiredMembershipPlanID),
+		MinPrograms:              nullInt32(details.MinPrograms),
+		Priority:                 int32(details.Priority),
+		Stackable:                details.Stackable,
+		IsActive:                 details.IsActive,
+		ValidFrom:                details.ValidFrom,
+		ValidTo:                  nullTime(details.ValidTo),
+	})
+	if err != nil {
+		return values.RuleReadValues{}, ruleWriteError(err, "create")
+	}
+	return mapDbRule(rule), nil
+}
+
+func (r *Repository) GetRuleByID(ctx context.Context, id uuid.UUID) (values.RuleReadValues, *errLib.CommonError) {
+	rule, err := r.Queries.GetDiscountRuleById(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return values.RuleReadValues{}, errLib.New("Discount rule not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to get discount rule: %v", err)
+		return values.RuleReadValues{}, errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+	return mapDbRule(rule), nil
+}
+
+func (r *Repository) ListRules(ctx context.Context) ([]values.RuleReadValues, *errLib.CommonError) {
+	rules, err := r.Queries.ListDiscountRules(ctx)
+	if err != nil {
+		log.Printf("Failed to list discount rules: %v", err)
+		return nil, errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+	res := make([]values.RuleReadValues, len(rules))
+	for i, rule := range rules {
+		res[i] = mapDbRule(rule)
+	}
+	return res, nil
+}
+
+// ListActiveRules returns the rules that can apply to a purchase of the item right now,
+// in priority order
+func (r *Repository) ListActiveRules(ctx context.Context, item values.PriceItem) ([]values.RuleReadValues, *errLib.CommonError) {
+	rules, err := r.Queries.ListActiveDiscountRules(ctx, db.ListActiveDiscountRulesParams{
+		AppliesTo: string(item.Type),
+		TargetID:  uuid.NullUUID{UUID: item.ID, Valid: true},
+	})
+	if err != nil {
+		log.Printf("Failed to list active discount rules for %s %s: %v", item.Type, item.ID, err)
+		return nil, errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+	res := make([]values.RuleReadValues, len(rules))
+	for i, rule := range rules {
+		res[i] = mapDbRule(rule)
+	}
+	return res, nil
+}
+
+func (r *Repository) UpdateRule(ctx context.Context, details values.RuleUpdateValues) (values.RuleReadValues, *errLib.CommonError) {
+	rule, err := r.Queries.UpdateDiscountRule(ctx, db.UpdateDiscountRuleParams{
+		Name:                     details.Name,
+		Description:              sql.NullString{String: details.Description, Valid: details.Description != ""},
+		RuleType:                 string(details.RuleType),
+		AppliesTo:                string(details.AppliesTo),
+		TargetID:                 nullUUID(details.TargetID),
+		DiscountType:             string(details.DiscountType),
+		DiscountPercent:          int32(details.DiscountPercent),
+		AmountOff:                int32(details.AmountOff),
+		MinSiblings:              nullInt32(details.MinSiblings),
+		EarlyBirdDeadline:        nullTime(details.EarlyBirdDeadline),
+		RequiredMembershipPlanID: nullUUID(details.RequiredMembershipPlanID),
+		MinPrograms:              nullInt32(details.MinPrograms),
+		Priority:                 int32(details.Priority),
+		Stackable:                details.Stackable,
+		IsActive:                 details.IsActive,
+		ValidFrom:                details.ValidFrom,
+		ValidTo:                  nullTime(details.ValidTo),
+		ID:                       details.ID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return values.RuleReadValues{}, errLib.New("Discount rule not found", http.StatusNotFound)
+		}
+		return values.RuleReadValues{}, ruleWriteError(err, "update")
+	}
+	return mapDbRule(rule), nil
+}
+
+func (r *Repository) DeleteRule(ctx context.Context, id uuid.UUID) *errLib.CommonError {
+	rows, err := r.Queries.DeleteDiscountRule(ctx, id)
+	if err != nil {
+		log.Printf("Failed to delete discount rule: %v", err)
+		return errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+	if rows == 0 {
+		return errLib.New("Discount rule not found", http.StatusNotFound)
+	}
+	return nil
+}
+
+func (r *Repository) CountEnrolledSiblings(ctx context.Context, customerID uuid.UUID) (int, *errLib.CommonError) {
+	count, err := r.Queries.CountEnrolledSiblings(ctx, customerID)
+	if err != nil {
+		log.Printf("Failed to count enrolled siblings of %s: %v", customerID, err)
+		return 0, errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+	return int(count), nil
+}
+
+func (r *Repository) CountOtherProgramEnrollments(ctx context.Context, customerID, programID uuid.UUID) (int, *errLib.CommonError) {
+	count, err := r.Queries.CountActiveProgramEnrollments(ctx, db.CountActiveProgramEnrollmentsParams{
+		CustomerID: customerID,
+		ProgramID:  programID,
+	})
+	if err != nil {
+		log.Printf("Failed to count program enrollments of %s: %v", customerID, err)
+		return 0, errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+	return int(count), nil
+}
+
+func (r *Repository) ListActiveMembershipPlanIDs(ctx context.Context, customerID uuid.UUID) ([]uuid.UUID, *errLib.CommonError) {
+	planIDs, err := r.Queries.ListActiveMembershipPlanIds(ctx, customerID)
+	if err != nil {
+		log.Printf("Failed to list active membership plans of %s: %v", customerID, err)
+		return nil, errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+	return planIDs, nil
+}
@@ -470,6 +470,29 @@ type Discount struct {
 	StripePromotionCodeID sql.NullString `json:"stripe_promotion_code_id"`
 }
 
+type DiscountRule struct {
+	ID                       uuid.UUID      `json:"id"`
+	Name                     string         `json:"name"`
+	Description              sql.NullString `json:"description"`
+	RuleType                 string         `json:"rule_type"`
+	AppliesTo                string         `json:"applies_to"`
+	TargetID                 uuid.NullUUID  `json:"target_id"`
+	DiscountType             string         `json:"discount_type"`
+	DiscountPercent          int32          `json:"discount_percent"`
+	AmountOff                int32          `json:"amount_off"`
+	MinSiblings              sql.NullInt32  `json:"min_siblings"`
+	EarlyBirdDeadline        sql.NullTime   `json:"early_bird_deadline"`
+	RequiredMembershipPlanID uuid.NullUUID  `json:"required_membership_plan_id"`
+	MinPrograms              sql.NullInt32  `json:"min_programs"`
+	Priority                 int32          `json:"priority"`
+	Stackable                bool           `json:"stackable"`
+	IsActive                 bool           `json:"is_active"`
+	ValidFrom                time.Time      `json:"valid_from"`
+	ValidTo                  sql.NullTime   `json:"valid_to"`
+	CreatedAt                time.Time      `json:"created_at"`
+	UpdatedAt                time.Time      `json:"updated_at"`
+}
+
 type EventsAttendance struct {
 	ID          uuid.UUID    `json:"id"`
 	EventID     uuid.UUID    `json:"event_id"`
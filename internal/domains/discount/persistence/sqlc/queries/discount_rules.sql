@@ -0,0 +1,83 @@
+-- name: CreateDiscountRule :one
+INSERT INTO discount_rules (
+    name, description, rule_type, applies_to, target_id, discount_type, discount_percent, amount_off,
+    min_siblings, early_bird_deadline, required_membership_plan_id, min_programs,
+    priority, stackable, is_active, valid_from, valid_to
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17
+) RETURNING *;
+
+-- name: GetDiscountRuleById :one
+SELECT * FROM discount_rules WHERE id = $1;
+
+-- name: ListDiscountRules :many
+SELECT * FROM discount_rules ORDER BY applies_to, priority, created_at;
+
+-- name: ListActiveDiscountRules :many
+-- Rules that can apply to a purchase of the given item right now
+SELECT * FROM discount_rules
+WHERE applies_to = $1
+  AND (target_id IS NULL OR target_id = $2)
+  AND is_active = true
+  AND valid_from <= now()
+  AND (valid_to IS NULL OR valid_to > now())
+ORDER BY priority, created_at;
+
+-- name: UpdateDiscountRule :one
+UPDATE discount_rules
+SET name = $1,
+    description = $2,
+    rule_type = $3,
+    applies_to = $4,
+    target_id = $5,
+    discount_type = $6,
+    discount_percent = $7,
+    amount_off = $8,
+    min_siblings = $9,
+    early_bird_deadline = $10,
+    required_membership_plan_id = $11,
+    min_programs = $12,
+    priority = $13,
+    stackable = $14,
+    is_active = $15,
+    valid_from = $16,
+    valid_to = $17,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $18
+RETURNING *;
+
+-- name: DeleteDiscountRule :execrows
+DELETE FROM discount_rules WHERE id = $1;
+
+-- name: CountEnrolledSiblings :one
+-- Other children of the customer's parent holding an active membership or a paid program enrollment
+SELECT COUNT(*)::int
+FROM users.users sibling
+         JOIN users.users customer ON customer.parent_id = sibling.parent_id
+WHERE customer.id = $1
+  AND sibling.id <> customer.id
+  AND sibling.deleted_at IS NULL
+  AND (EXISTS (SELECT 1
+               FROM users.customer_membership_plans cmp
+               WHERE cmp.customer_id = sibling.id
+                 AND cmp.status = 'active')
+    OR EXISTS (SELECT 1
+               FROM program.customer_enrollment ce
+               WHERE ce.customer_id = sibling.id
+                 AND ce.is_cancelled = false
+                 AND ce.payment_status = 'paid'));
+
+-- name: CountActiveProgramEnrollments :one
+-- Paid program enrollments of the customer, leaving out the given program
+SELECT COUNT(*)::int
+FROM program.customer_enrollment
+WHERE customer_id = $1
+  AND program_id <> $2
+  AND is_cancelled = false
+  AND payment_status = 'paid';
+
+-- name: ListActiveMembershipPlanIds :many
+SELECT membership_plan_id
+FROM users.customer_membership_plans
+WHERE customer_id = $1
+  AND status = 'active';
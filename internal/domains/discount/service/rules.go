@@ -0,0 +1,333 @@
+package discount
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	repo "api/internal/domains/discount/persistence/repository"
+	values "api/internal/domains/discount/values"
+	errLib "api/internal/libs/errors"
+	contextUtils "api/utils/context"
+
+	"github.com/google/uuid"
+)
+
+// Automatic discount rules are applied at checkout without a code. Stacking and precedence:
+//  1. Only rules whose condition the customer meets are eligible.
+//  2. Stackable rules combine, in priority order (lowest first), each taking its discount
+//     off the price left by the ones before it.
+//  3. A rule that isn't stackable only applies on its own. The customer gets whichever
+//     saves more: all stackable rules together, or the best rule that isn't stackable.
+//  4. A discount code never combines with automatic rules, since Stripe checkout takes a
+//     single coupon. The customer gets whichever saves more.
+//  5. The price never goes below zero.
+
+func (s *Service) GetRule(ctx context.Context, id uuid.UUID) (values.RuleReadValues, *errLib.CommonError) {
+	return s.repo.GetRuleByID(ctx, id)
+}
+
+func (s *Service) GetRules(ctx context.Context) ([]values.RuleReadValues, *errLib.CommonError) {
+	return s.repo.ListRules(ctx)
+}
+
+func (s *Service) CreateRule(ctx context.Context, details values.RuleCreateValues) (values.RuleReadValues, *errLib.CommonError) {
+	var created values.RuleReadValues
+	err := s.executeInTx(ctx, func(txRepo *repo.Repository) *errLib.CommonError {
+		var err *errLib.CommonError
+		created, err = txRepo.CreateRule(ctx, details)
+		if err != nil {
+			return err
+		}
+		staffID, err := contextUtils.GetUserID(ctx)
+		if err != nil {
+			return err
+		}
+		return s.staffActivityLogsService.InsertStaffActivity(
+			ctx,
+			txRepo.GetTx(),
+			staffID,
+			fmt.Sprintf("Created %s discount rule '%s' for %s", details.RuleType, details.Name, details.AppliesTo),
+		)
+	})
+	if err != nil {
+		return values.RuleReadValues{}, err
+	}
+	return created, nil
+}
+
+func (s *Service) UpdateRule(ctx context.Context, details values.RuleUpdateValues) (values.RuleReadValues, *errLib.CommonError) {
+	var updated values.RuleReadValues
+	err := s.executeInTx(ctx, func(txRepo *repo.Repository) *errLib.CommonError {
+		var err *errLib.CommonError
+		updated, err = txRepo.UpdateRule(ctx, details)
+		if err != nil {
+			return err
+		}
+		staffID, err := contextUtils.GetUserID(ctx)
+		if err != nil {
+			return err
+		}
+		return s.staffActivityLogsService.InsertStaffActivity(
+			ctx,
+			txRepo.GetTx(),
+			staffID,
+			fmt.Sprintf("Updated %s discount rule '%s' for %s", details.RuleType, details.Name, details.AppliesTo),
+		)
+	})
+	if err != nil {
+		return values.RuleReadValues{}, err
+	}
+	return updated, nil
+}
+
+func (s *Service) DeleteRule(ctx context.Context, id uuid.UUID) *errLib.CommonError {
+	return s.executeInTx(ctx, func(txRepo *repo.Repository) *errLib.CommonError {
+		if err := txRepo.DeleteRule(ctx, id); err != nil {
+			return err
+		}
+		staffID, err := contextUtils.GetUserID(ctx)
+		if err != nil {
+			return err
+		}
+		return s.staffActivityLogsService.InsertStaffActivity(
+			ctx,
+			txRepo.GetTx(),
+			staffID,
+			fmt.Sprintf("Deleted discount rule with ID: %s", id),
+		)
+	})
+}
+
+// QuotePrice prices an item for the logged-in customer, before tax. basePrice is in cents.
+// The code, when given, must be valid for the customer and the item.
+func (s *Service) QuotePrice(ctx context.Context, item values.PriceItem, basePrice int64, currency string, code *string) (values.PriceQuote, *errLib.CommonError) {
+	customerID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return values.PriceQuote{}, err
+	}
+
+	rules, err := s.repo.ListActiveRules(ctx, item)
+	if err != nil {
+		return values.PriceQuote{}, err
+	}
+
+	facts, err := s.ruleFacts(ctx, customerID, item, rules)
+	if err != nil {
+		return values.PriceQuote{}, err
+	}
+
+	quote := priceWithRules(item, currency, basePrice, rules, facts, time.Now())
+
+	if code != nil {
+		var membershipPlanID *uuid.UUID
+		if item.Type == values.ItemMembership {
+			membershipPlanID = &item.ID
+		}
+		validated, err := s.ValidateDiscount(ctx, *code, membershipPlanID)
+		if err != nil {
+			return values.PriceQuote{}, err
+		}
+		if err := codeAppliesTo(validated, item.Type); err != nil {
+			return values.PriceQuote{}, err
+		}
+		quote = withCode(quote, validated)
+	}
+
+	return quote, nil
+}
+
+// ruleFacts looks up the parts of the customer's account that the rules' conditions need
+func (s *Service) ruleFacts(ctx context.Context, customerID uuid.UUID, item values.PriceItem, rules []values.RuleReadValues) (values.RuleFacts, *errLib.CommonError) {
+	var facts values.RuleFacts
+	needed := map[values.RuleType]bool{}
+	for _, rule := range rules {
+		needed[rule.RuleType] = true
+	}
+
+	var err *errLib.CommonError
+	if needed[values.RuleSibling] {
+		if facts.EnrolledSiblings, err = s.repo.CountEnrolledSiblings(ctx, customerID); err != nil {
+			return values.RuleFacts{}, err
+		}
+	}
+	if needed[values.RuleBundle] && item.Type == values.ItemProgram {
+		if facts.OtherProgramEnrollments, err = s.repo.CountOtherProgramEnrollments(ctx, customerID, item.ID); err != nil {
+			return values.RuleFacts{}, err
+		}
+	}
+	if needed[values.RuleMemberPricing] {
+		if facts.MembershipPlanIDs, err = s.repo.ListActiveMembershipPlanIDs(ctx, customerID); err != nil {
+			return values.RuleFacts{}, err
+		}
+	}
+	return facts, nil
+}
+
+// codeAppliesTo checks that a discount code can be used for the kind of item
+func codeAppliesTo(code values.ReadValues, itemType values.ItemType) *errLib.CommonError {
+	if itemType == values.ItemMembership {
+		if code.AppliesTo != values.AppliesToSubscription && code.AppliesTo != values.AppliesToBoth {
+			return errLib.New("This discount code does not apply to subscriptions", http.StatusBadRequest)
+		}
+		return nil
+	}
+	if code.AppliesTo != values.AppliesToOneTime && code.AppliesTo != values.AppliesToBoth {
+		return errLib.New("This discount code does not apply to one-time payments", http.StatusBadRequest)
+	}
+	return nil
+}
+
+// priceWithRules applies the automatic rules the customer qualifies for to basePrice
+func priceWithRules(item values.PriceItem, currency string, basePrice int64, rules []values.RuleReadValues, facts values.RuleFacts, now time.Time) values.PriceQuote {
+	quote := values.PriceQuote{Item: item, Currency: currency, BasePrice: basePrice}
+
+	var eligible []values.RuleReadValues
+	for _, rule := range rules {
+		if ruleConditionMet(rule, facts, now) {
+			eligible = append(eligible, rule)
+		}
+	}
+	sort.SliceStable(eligible, func(i, j int) bool { return eligible[i].Priority < eligible[j].Priority })
+
+	var stacked []values.Adjustment
+	remaining := basePrice
+	for _, rule := range eligible {
+		if !rule.Stackable {
+			continue
+		}
+		amount := percentOrAmount(rule.DiscountType, rule.DiscountPercent, rule.AmountOff, remaining)
+		remaining -= amount
+		stacked = append(stacked, ruleAdjustment(rule, amount))
+	}
+	stackedTotal := basePrice - remaining
+
+	best := -1
+	var exclusive []values.Adjustment
+	for _, rule := range eligible {
+		if rule.Stackable {
+			continue
+		}
+		adjustment := ruleAdjustment(rule, percentOrAmount(rule.DiscountType, rule.DiscountPercent, rule.AmountOff, basePrice))
+		exclusive = append(exclusive, adjustment)
+		if best < 0 || adjustment.Amount > exclusive[best].Amount {
+			best = len(exclusive) - 1
+		}
+	}
+
+	if best >= 0 && exclusive[best].Amount > stackedTotal {
+		chosen := exclusive[best]
+		quote.Applied = []values.Adjustment{chosen}
+		for _, adjustment := range stacked {
+			adjustment.Reason = fmt.Sprintf("Can't be combined with %s, which saves more", chosen.Name)
+			quote.NotApplied = append(quote.NotApplied, adjustment)
+		}
+		for _, adjustment := range exclusive {
+			if adjustment.ID == chosen.ID {
+				continue
+			}
+			adjustment.Reason = fmt.Sprintf("%s saves more", chosen.Name)
+			quote.NotApplied = append(quote.NotApplied, adjustment)
+		}
+	} else {
+		for _, adjustment := range stacked {
+			if adjustment.Amount > 0 {
+				quote.Applied = append(quote.Applied, adjustment)
+			}
+		}
+		for _, adjustment := range exclusive {
+			adjustment.Reason = "Can't be combined with other discounts, which save more"
+			quote.NotApplied = append(quote.NotApplied, adjustment)
+		}
+	}
+
+	for _, adjustment := range quote.Applied {
+		quote.DiscountTotal += adjustment.Amount
+	}
+	quote.Total = basePrice - quote.DiscountTotal
+	return quote
+}
+
+// withCode swaps the automatic rules for the discount code when the code saves more
+func withCode(quote values.PriceQuote, code values.ReadValues) values.PriceQuote {
+	var amountOff int64
+	if code.DiscountAmount != nil {
+		amountOff = int64(math.Round(*code.DiscountAmount * 100))
+	}
+	codeAdjustment := values.Adjustment{
+		Source: values.AdjustmentCode,
+		ID:     code.ID,
+		Name:   code.Name,
+		Amount: percentOrAmount(code.DiscountType, code.DiscountPercent, amountOff, quote.BasePrice),
+	}
+
+	if codeAdjustment.Amount <= quote.DiscountTotal {
+		codeAdjustment.Reason = "Automatic discounts save more than this code"
+		quote.NotApplied = append(quote.NotApplied, codeAdjustment)
+		return quote
+	}
+
+	for _, adjustment := range quote.Applied {
+		adjustment.Reason = "Discount codes can't be combined with automatic discounts"
+		quote.NotApplied = append(quote.NotApplied, adjustment)
+	}
+	quote.Applied = []values.Adjustment{codeAdjustment}
+	quote.DiscountTotal = codeAdjustment.Amount
+	quote.Total = quote.BasePrice - codeAdjustment.Amount
+	quote.Code = &code
+	return quote
+}
+
+func ruleConditionMet(rule values.RuleReadValues, facts values.RuleFacts, now time.Time) bool {
+	switch rule.RuleType {
+	case values.RuleSibling:
+		return rule.MinSiblings != nil && facts.EnrolledSiblings >= *rule.MinSiblings
+	case values.RuleEarlyBird:
+		return rule.EarlyBirdDeadline != nil && now.Before(*rule.EarlyBirdDeadline)
+	case values.RuleMemberPricing:
+		if rule.RequiredMembershipPlanID == nil {
+			return len(facts.MembershipPlanIDs) > 0
+		}
+		for _, planID := range facts.MembershipPlanIDs {
+			if planID == *rule.RequiredMembershipPlanID {
+				return true
+			}
+		}
+		return false
+	case values.RuleBundle:
+		// The purchase itself counts towards the bundle
+		return rule.MinPrograms != nil && facts.OtherProgramEnrollments+1 >= *rule.MinPrograms
+	}
+	return false
+}
+
+func ruleAdjustment(rule values.RuleReadValues, amount int64) values.Adjustment {
+	return values.Adjustment{
+		Source:   values.AdjustmentRule,
+		ID:       rule.ID,
+		Name:     rule.Name,
+		RuleType: rule.RuleType,
+		Amount:   amount,
+	}
+}
+
+// percentOrAmount is the discount taken off price, rounded to the cent and never more than price
+func percentOrAmount(discountType values.DiscountType, percent int, amountOff int64, price int64) int64 {
+	if price <= 0 {
+		return 0
+	}
+	amount := amountOff
+	if discountType == values.TypePercentage {
+		amount = (price*int64(percent) + 50) / 100
+	}
+	if amount > price {
+		return price
+	}
+	if amount < 0 {
+		return 0
+	}
+	return amount
+}
@@ -0,0 +1,178 @@
+package discount
+
+import (
+	"testing"
+	"time"
+
+	values "api/internal/domains/discount/values"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceWithRules(t *testing.T) {
+	now := time.Date(2026, 8, 1, 12, 0, 0, 0, time.UTC)
+	item := values.PriceItem{Type: values.ItemProgram, ID: uuid.New()}
+	one, two := 1, 2
+	deadline := now.Add(24 * time.Hour)
+
+	rule := func(name string, ruleType values.RuleType, priority int, stackable bool, percent int, amountOff int64) values.RuleReadValues {
+		r := values.RuleReadValues{ID: uuid.New()}
+		r.Name = name
+		r.RuleType = ruleType
+		r.Priority = priority
+		r.Stackable = stackable
+		if percent > 0 {
+			r.DiscountType = values.TypePercentage
+			r.DiscountPercent = percent
+		} else {
+			r.DiscountType = values.TypeFixedAmount
+			r.AmountOff = amountOff
+		}
+		r.MinSiblings = &one
+		r.MinPrograms = &two
+		r.EarlyBirdDeadline = &deadline
+		return r
+	}
+
+	t.Run("Skips rules whose condition isn't met", func(t *testing.T) {
+		rules := []values.RuleReadValues{
+			rule("Sibling", values.RuleSibling, 10, true, 10, 0),
+			rule("Bundle", values.RuleBundle, 20, true, 0, 1500),
+			rule("Members", values.RuleMemberPricing, 30, true, 50, 0),
+		}
+		quote := priceWithRules(item, "CAD", 10000, rules, values.RuleFacts{}, now)
+
+		assert.Empty(t, quote.Applied)
+		assert.Equal(t, int64(10000), quote.Total)
+	})
+
+	t.Run("Stacks rules in priority order on the remaining price", func(t *testing.T) {
+		rules := []values.RuleReadValues{
+			rule("Bundle", values.RuleBundle, 20, true, 0, 1500),
+			rule("Sibling", values.RuleSibling, 10, true, 10, 0),
+		}
+		facts := values.RuleFacts{EnrolledSiblings: 1, OtherProgramEnrollments: 1}
+		quote := priceWithRules(item, "CAD", 10000, rules, facts, now)
+
+		if assert.Len(t, quote.Applied, 2) {
+			assert.Equal(t, "Sibling", quote.Applied[0].Name)
+			assert.Equal(t, int64(1000), quote.Applied[0].Amount)
+			assert.Equal(t, "Bundle", quote.Applied[1].Name)
+			assert.Equal(t, int64(1500), quote.Applied[1].Amount)
+		}
+		assert.Equal(t, int64(2500), quote.DiscountTotal)
+		assert.Equal(t, int64(7500), quote.Total)
+	})
+
+	t.Run("Takes the best rule that isn't stackable when it saves more", func(t *testing.T) {
+		rules := []values.RuleReadValues{
+			rule("Sibling", values.RuleSibling, 10, true, 10, 0),
+			rule("Early bird", values.RuleEarlyBird, 20, false, 25, 0),
+			rule("Early bird flat", values.RuleEarlyBird, 30, false, 0, 2000),
+		}
+		quote := priceWithRules(item, "CAD", 10000, rules, values.RuleFacts{EnrolledSiblings: 1}, now)
+
+		if assert.Len(t, quote.Applied, 1) {
+			assert.Equal(t, "Early bird", quote.Applied[0].Name)
+		}
+		assert.Len(t, quote.NotApplied, 2)
+		assert.Equal(t, int64(7500), quote.Total)
+	})
+
+	t.Run("Keeps stacked rules when they save more", func(t *testing.T) {
+		rules := []values.RuleReadValues{
+			rule("Sibling", values.RuleSibling, 10, true, 20, 0),
+			rule("Bundle", values.RuleBundle, 20, true, 0, 1500),
+			rule("Early bird", values.RuleEarlyBird, 30, false, 25, 0),
+		}
+		facts := values.RuleFacts{EnrolledSiblings: 1, OtherProgramEnrollments: 1}
+		quote := priceWithRules(item, "CAD", 10000, rules, facts, now)
+
+		assert.Len(t, quote.Applied, 2)
+		if assert.Len(t, quote.NotApplied, 1) {
+			assert.Equal(t, "Early bird", quote.NotApplied[0].Name)
+		}
+		assert.Equal(t, int64(6500), quote.Total)
+	})
+
+	t.Run("Never goes below zero", func(t *testing.T) {
+		rules := []values.RuleReadValues{
+			rule("Sibling", values.RuleSibling, 10, true, 0, 3000),
+			rule("Bundle", values.RuleBundle, 20, true, 0, 3000),
+		}
+		facts := values.RuleFacts{EnrolledSiblings: 1, OtherProgramEnrollments: 1}
+		quote := priceWithRules(item, "CAD", 4000, rules, facts, now)
+
+		assert.Equal(t, int64(4000), quote.DiscountTotal)
+		assert.Equal(t, int64(0), quote.Total)
+	})
+
+	t.Run("Member pricing can require a plan", func(t *testing.T) {
+		planID := uuid.New()
+		members := rule("Members", values.RuleMemberPricing, 10, true, 50, 0)
+		members.RequiredMembershipPlanID = &planID
+
+		other := priceWithRules(item, "CAD", 2000, []values.RuleReadValues{members}, values.RuleFacts{MembershipPlanIDs: []uuid.UUID{uuid.New()}}, now)
+		assert.Equal(t, int64(2000), other.Total)
+
+		matching := priceWithRules(item, "CAD", 2000, []values.RuleReadValues{members}, values.RuleFacts{MembershipPlanIDs: []uuid.UUID{planID}}, now)
+		assert.Equal(t, int64(1000), matching.Total)
+	})
+
+	t.Run("Early bird ends at its deadline", func(t *testing.T) {
+		rules := []values.RuleReadValues{rule("Early bird", values.RuleEarlyBird, 10, true, 10, 0)}
+		quote := priceWithRules(item, "CAD", 10000, rules, values.RuleFacts{}, deadline)
+
+		assert.Empty(t, quote.Applied)
+	})
+}
+
+func TestWithCode(t *testing.T) {
+	item := values.PriceItem{Type: values.ItemEvent, ID: uuid.New()}
+	sibling := values.Adjustment{Source: values.AdjustmentRule, ID: uuid.New(), Name: "Sibling", Amount: 1000}
+	quote := values.PriceQuote{
+		Item:          item,
+		BasePrice:     10000,
+		Applied:       []values.Adjustment{sibling},
+		DiscountTotal: 1000,
+		Total:         9000,
+	}
+
+	code := func(percent int, amount *float64) values.ReadValues {
+		c := values.ReadValues{ID: uuid.New()}
+		c.Name = "SPRING"
+		if amount != nil {
+			c.DiscountType = values.TypeFixedAmount
+			c.DiscountAmount = amount
+		} else {
+			c.DiscountType = values.TypePercentage
+			c.DiscountPercent = percent
+		}
+		return c
+	}
+
+	t.Run("Code replaces rules when it saves more", func(t *testing.T) {
+		amount := 25.0
+		withBetterCode := withCode(quote, code(0, &amount))
+
+		if assert.Len(t, withBetterCode.Applied, 1) {
+			assert.Equal(t, values.AdjustmentCode, withBetterCode.Applied[0].Source)
+			assert.Equal(t, int64(2500), withBetterCode.Applied[0].Amount)
+		}
+		assert.Len(t, withBetterCode.NotApplied, 1)
+		assert.NotNil(t, withBetterCode.Code)
+		assert.Equal(t, int64(7500), withBetterCode.Total)
+	})
+
+	t.Run("Rules stay when the code saves less or the same", func(t *testing.T) {
+		withWorseCode := withCode(quote, code(10, nil))
+
+		assert.Equal(t, []values.Adjustment{sibling}, withWorseCode.Applied)
+		assert.Nil(t, withWorseCode.Code)
+		if assert.Len(t, withWorseCode.NotApplied, 1) {
+			assert.Equal(t, values.AdjustmentCode, withWorseCode.NotApplied[0].Source)
+		}
+		assert.Equal(t, int64(9000), withWorseCode.Total)
+	})
+}
@@ -143,6 +143,29 @@ func (s *Service) GetDiscountByName(ctx context.Context, name string) (values.Re
 }
 
 func (s *Service) CreateDiscount(ctx context.Context, details values.CreateValues) (values.ReadValues, *errLib.CommonError) {
+	return s.createDiscount(ctx, details, func(txRepo *repo.Repository) *errLib.CommonError {
+		staffID, err := contextUtils.GetUserID(ctx)
+		if err != nil {
+			return err
+		}
+		return s.staffActivityLogsService.InsertStaffActivity(
+			ctx,
+			txRepo.GetTx(),
+			staffID,
+			fmt.Sprintf("Created discount '%s' (%s: %d%%)", details.Name, details.DiscountType, details.DiscountPercent),
+		)
+	})
+}
+
+// IssueDiscount creates a discount the system hands out itself, such as a referral
+// reward. No staff member is involved, so no staff activity is logged.
+func (s *Service) IssueDiscount(ctx context.Context, details values.CreateValues) (values.ReadValues, *errLib.CommonError) {
+	return s.createDiscount(ctx, details, nil)
+}
+
+// createDiscount creates the discount's Stripe coupon and promotion code, then stores
+// it. logActivity, when set, runs in the same transaction as the insert.
+func (s *Service) createDiscount(ctx context.Context, details values.CreateValues, logActivity func(txRepo *repo.Repository) *errLib.CommonError) (values.ReadValues, *errLib.CommonError) {
 	// Create Stripe coupon first
 	stripeCouponID, err := s.createStripeCoupon(details)
 	if err != nil {
@@ -177,16 +200,10 @@ func (s *Service) CreateDiscount(ctx context.Context, details values.CreateValue
 		if err2 != nil {
 			return err2
 		}
-		staffID, err := contextUtils.GetUserID(ctx)
-		if err != nil {
-			return err
+		if logActivity == nil {
+			return nil
 		}
-		return s.staffActivityLogsService.InsertStaffActivity(
-			ctx,
-			txRepo.GetTx(),
-			staffID,
-			fmt.Sprintf("Created discount '%s' (%s: %d%%)", details.Name, details.DiscountType, details.DiscountPercent),
-		)
+		return logActivity(txRepo)
 	})
 	if txErr != nil {
 		// If database transaction fails, clean up both the promotion code and coupon
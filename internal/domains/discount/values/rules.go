@@ -0,0 +1,98 @@
+package discount
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RuleType string
+
+const (
+	RuleSibling       RuleType = "sibling"
+	RuleEarlyBird     RuleType = "early_bird"
+	RuleMemberPricing RuleType = "member_pricing"
+	RuleBundle        RuleType = "bundle"
+)
+
+// ItemType is what a customer is buying
+type ItemType string
+
+const (
+	ItemMembership ItemType = "membership"
+	ItemProgram    ItemType = "program"
+	ItemEvent      ItemType = "event"
+)
+
+type RuleCreateValues struct {
+	Name                     string
+	Description              string
+	RuleType                 RuleType
+	AppliesTo                ItemType
+	TargetID                 *uuid.UUID // A single plan, program or event; nil for all of AppliesTo
+	DiscountType             DiscountType
+	DiscountPercent          int
+	AmountOff                int64 // in cents, for fixed amount rules
+	MinSiblings              *int
+	EarlyBirdDeadline        *time.Time
+	RequiredMembershipPlanID *uuid.UUID
+	MinPrograms              *int
+	Priority                 int
+	Stackable                bool
+	IsActive                 bool
+	ValidFrom                time.Time
+	ValidTo                  *time.Time
+}
+
+type RuleUpdateValues struct {
+	ID uuid.UUID
+	RuleCreateValues
+}
+
+type RuleReadValues struct {
+	ID uuid.UUID
+	RuleCreateValues
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// PriceItem identifies the plan, program or event being priced
+type PriceItem struct {
+	Type ItemType
+	ID   uuid.UUID
+}
+
+// RuleFacts is what the customer's account says about the conditions of the rules
+type RuleFacts struct {
+	EnrolledSiblings        int
+	OtherProgramEnrollments int
+	MembershipPlanIDs       []uuid.UUID
+}
+
+const (
+	AdjustmentRule = "rule"
+	AdjustmentCode = "code"
+)
+
+// Adjustment is one discount in a price breakdown. Amounts are in cents.
+type Adjustment struct {
+	Source   string // AdjustmentRule or AdjustmentCode
+	ID       uuid.UUID
+	Name     string
+	RuleType RuleType
+	Amount   int64
+	Reason   string // Why an eligible discount was left out
+}
+
+// PriceQuote is the breakdown of what a customer pays for an item, before tax.
+// Amounts are in cents.
+type PriceQuote struct {
+	Item          PriceItem
+	Currency      string
+	BasePrice     int64
+	Applied       []Adjustment
+	NotApplied    []Adjustment
+	DiscountTotal int64
+	Total         int64
+	Code          *ReadValues // The discount code, when it is what the customer gets
+}
@@ -3,6 +3,7 @@ package enrollment
 import (
 	"api/internal/di"
 	repository "api/internal/domains/enrollment/persistence/repository"
+	staffAvailabilityDto "api/internal/domains/staff_availability/dto"
 	errLib "api/internal/libs/errors"
 	responseHandlers "api/internal/libs/responses"
 	"api/internal/libs/validators"
@@ -22,13 +23,17 @@ func NewEventStaffsHandler(container *di.Container) *StaffsHandler {
 // AssignStaffToEvent assigns a staff member to an event.
 // @Summary Assign a staff member to an event
 // @Description Assign a staff member to an event using event_id and staff_id in the request body.
+// @Description Coaches and instructors who are booked elsewhere, on time off or outside their declared availability
+// @Description are rejected with 409 unless staff_override_reason is given in the optional body.
 // @Tags event_staff
 // @Accept json
 // @Produce json
 // @Param event_id path string true "Event ID"
 // @Param staff_id path string true "Staff ID"
+// @Param request body staffAvailabilityDto.OverrideRequestDto false "Override of staff scheduling conflicts"
 // @Success 200 {object} map[string]interface{} "Staff successfully assigned to event"
 // @Failure 400 {object} map[string]interface{} "Bad Request: Invalid input"
+// @Failure 409 {object} map[string]interface{} "Conflict: Staff member is not available"
 // @Failure 500 {object} map[string]interface{} "Internal Server Error"
 // @Router /events/{event_id}/staffs/{staff_id} [post]
 func (h *StaffsHandler) AssignStaffToEvent(w http.ResponseWriter, r *http.Request) {
@@ -45,7 +50,19 @@ func (h *StaffsHandler) AssignStaffToEvent(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	if err = h.Repo.AssignStaffToEvent(r.Context(), eventId, staffId); err != nil {
+	var body staffAvailabilityDto.OverrideRequestDto
+	if r.ContentLength != 0 {
+		if err = validators.ParseJSON(r.Body, &body); err != nil {
+			responseHandlers.RespondWithError(w, err)
+			return
+		}
+		if err = validators.ValidateDto(&body); err != nil {
+			responseHandlers.RespondWithError(w, err)
+			return
+		}
+	}
+
+	if err = h.Repo.AssignStaffToEvent(r.Context(), eventId, staffId, body.StaffOverrideReason); err != nil {
 		responseHandlers.RespondWithError(w, err)
 		return
 	}
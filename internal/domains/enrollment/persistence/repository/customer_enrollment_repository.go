@@ -62,6 +62,24 @@ func (r *CustomerEnrollmentRepository) UnEnrollCustomerFromEvent(c context.Conte
 	return nil
 }
 
+func (r *CustomerEnrollmentRepository) UnEnrollCustomerFromProgram(c context.Context, programID, customerID uuid.UUID) *errLib.CommonError {
+	row, err := r.Queries.UnEnrollCustomerFromProgram(c, dbEnrollment.UnEnrollCustomerFromProgramParams{
+		CustomerID: customerID,
+		ProgramID:  programID,
+	})
+
+	if err != nil {
+		log.Println("error unenrolling customer from program: ", err)
+		return errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+
+	if row == 0 {
+		return errLib.New("Enrollment not found", http.StatusNotFound)
+	}
+
+	return nil
+}
+
 func (r *CustomerEnrollmentRepository) RemoveCustomerFromEvent(c context.Context, eventID, customerID uuid.UUID) *errLib.CommonError {
 	row, err := r.Queries.RemoveCustomerFromEvent(c, dbEnrollment.RemoveCustomerFromEventParams{
 		CustomerID: customerID,
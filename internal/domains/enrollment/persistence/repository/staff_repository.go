@@ -4,9 +4,13 @@ import (
 	"api/internal/di"
 	db "api/internal/domains/enrollment/persistence/sqlc/generated"
 	"api/internal/domains/event/service"
+	staffAvailability "api/internal/domains/staff_availability/service"
+	staffAvailabilityValues "api/internal/domains/staff_availability/values"
 	errLib "api/internal/libs/errors"
+	txUtils "api/utils/db"
 
 	"context"
+	"database/sql"
 	"log"
 	"net/http"
 
@@ -14,34 +18,55 @@ import (
 )
 
 type StaffsRepository struct {
-	Queries      *db.Queries
-	EventService *service.Service
+	Queries             *db.Queries
+	EventService        *service.Service
+	AvailabilityService *staffAvailability.Service
+	Db                  *sql.DB
 }
 
 func NewEventStaffsRepository(container *di.Container) *StaffsRepository {
 	return &StaffsRepository{
-		Queries:      container.Queries.EnrollmentDb,
-		EventService: service.NewEventService(container),
+		Queries:             container.Queries.EnrollmentDb,
+		EventService:        service.NewEventService(container),
+		AvailabilityService: staffAvailability.NewService(container),
+		Db:                  container.DB,
 	}
 }
 
-func (r *StaffsRepository) AssignStaffToEvent(ctx context.Context, eventId, staffId uuid.UUID) *errLib.CommonError {
+// AssignStaffToEvent assigns a staff member to an event once their schedule is checked
+// for clashes. overrideReason lets an admin assign them over conflicts.
+func (r *StaffsRepository) AssignStaffToEvent(ctx context.Context, eventId, staffId uuid.UUID, overrideReason string) *errLib.CommonError {
 
-	if _, err := r.EventService.GetEvent(ctx, eventId); err != nil {
+	event, err := r.EventService.GetEvent(ctx, eventId)
+	if err != nil {
 		return err
 	}
 
-	dbParams := db.AssignStaffToEventParams{
-		EventID: eventId,
-		StaffID: staffId,
-	}
-
-	if _, err := r.Queries.AssignStaffToEvent(ctx, dbParams); err != nil {
-		log.Printf("Failed to assign staff %+v to event: %+v. Error: %v", staffId, eventId, err.Error())
-		return errLib.New("Internal server error", http.StatusInternalServerError)
-	}
-
-	return nil
+	return txUtils.ExecuteInTx(ctx, r.Db, func(tx *sql.Tx) *errLib.CommonError {
+		if err := r.AvailabilityService.Check(ctx, tx, staffAvailabilityValues.Request{
+			StaffID:        staffId,
+			ActivityType:   staffAvailabilityValues.ActivityEvent,
+			ActivityID:     eventId,
+			LocationID:     event.Location.ID,
+			StartAt:        event.StartAt,
+			EndAt:          event.EndAt,
+			OverrideReason: overrideReason,
+		}); err != nil {
+			return err
+		}
+
+		dbParams := db.AssignStaffToEventParams{
+			EventID: eventId,
+			StaffID: staffId,
+		}
+
+		if _, err := r.Queries.WithTx(tx).AssignStaffToEvent(ctx, dbParams); err != nil {
+			log.Printf("Failed to assign staff %+v to event: %+v. Error: %v", staffId, eventId, err.Error())
+			return errLib.New("Internal server error", http.StatusInternalServerError)
+		}
+
+		return nil
+	})
 }
 
 func (r *StaffsRepository) UnassignedStaffFromEvent(ctx context.Context, eventId, staffId uuid.UUID) *errLib.CommonError {
@@ -344,6 +344,27 @@ func (q *Queries) UnEnrollCustomerFromEvent(ctx context.Context, arg UnEnrollCus
 	return result.RowsAffected()
 }
 
+const unEnrollCustomerFromProgram = `-- name: UnEnrollCustomerFromProgram :execrows
+UPDATE program.customer_enrollment
+SET is_cancelled = true,
+    updated_at   = CURRENT_TIMESTAMP
+WHERE customer_id = $1
+  AND program_id = $2
+`
+
+type UnEnrollCustomerFromProgramParams struct {
+	CustomerID uuid.UUID `json:"customer_id"`
+	ProgramID  uuid.UUID `json:"program_id"`
+}
+
+func (q *Queries) UnEnrollCustomerFromProgram(ctx context.Context, arg UnEnrollCustomerFromProgramParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, unEnrollCustomerFromProgram, arg.CustomerID, arg.ProgramID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateMembershipPlanByCustomerId = `-- name: UpdateMembershipPlanByCustomerId :execrows
 UPDATE users.customer_membership_plans
 SET status = $1, updated_at = CURRENT_TIMESTAMP
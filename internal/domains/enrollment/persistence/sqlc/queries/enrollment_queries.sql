@@ -33,6 +33,13 @@ SET is_cancelled = true
 WHERE customer_id = $1
   AND event_id = $2;
 
+-- name: UnEnrollCustomerFromProgram :execrows
+UPDATE program.customer_enrollment
+SET is_cancelled = true,
+    updated_at   = CURRENT_TIMESTAMP
+WHERE customer_id = $1
+  AND program_id = $2;
+
 -- name: RemoveCustomerFromEvent :execrows
 DELETE FROM events.customer_enrollment
 WHERE customer_id = $1
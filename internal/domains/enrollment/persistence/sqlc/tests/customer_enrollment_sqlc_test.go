@@ -59,8 +59,9 @@ require.NoError(t, err)
 
 	// Create a location
 	createdLocation, err := locationQueries.CreateLocation(context.Background(), locationDb.CreateLocationParams{
-		Name:    "Main Conference Room",
-		Address: "123 Main St",
+		Name:     "Main Conference Room",
+		Address:  "123 Main St",
+		Timezone: "America/Edmonton",
 	})
 	require.NoError(t, err)
 
@@ -165,8 +166,9 @@ func TestEnrollCustomerInProgramEvents(t *testing.T) {
 	require.NoError(t, err)
 
 	createdLocation, err := locationQueries.CreateLocation(context.Background(), locationDb.CreateLocationParams{
-		Name:    "Main Conference Room",
-		Address: "123 Main St",
+		Name:     "Main Conference Room",
+		Address:  "123 Main St",
+		Timezone: "America/Edmonton",
 	})
 	require.NoError(t, err)
 
@@ -78,6 +78,8 @@ type (
 		PriceID                   *string           `json:"price_id,omitempty"`
 		CreditCost                *int32            `json:"credit_cost,omitempty"`
 		RegistrationRequired      bool              `json:"registration_required"`
+		RecurrenceID              *uuid.UUID        `json:"recurrence_id,omitempty"`
+		BlackoutID                *uuid.UUID        `json:"blackout_id,omitempty"` // Set when the occurrence falls on a flagged location blackout
 		DateResponseDto
 		*Participants
 	}
@@ -93,6 +95,8 @@ func NewEventResponseDto(event values.ReadEventValues, includePeople bool, inclu
 		PriceID:                   event.PriceID,
 		CreditCost:                event.CreditCost,
 		RegistrationRequired:      event.RegistrationRequired,
+		RecurrenceID:              event.RecurrenceID,
+		BlackoutID:                event.BlackoutID,
 		Location: LocationInfo{
 			ID:      event.Location.ID,
 			Name:    event.Location.Name,
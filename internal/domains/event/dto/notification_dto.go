@@ -43,30 +43,32 @@ func (dto *SendNotificationRequestDto) Validate() *errLib.CommonError {
 
 // SendNotificationResponseDto is the response after sending notifications
 type SendNotificationResponseDto struct {
-	NotificationID uuid.UUID `json:"notification_id"`
-	RecipientCount int       `json:"recipient_count"`
-	EmailSent      int       `json:"email_sent"`
-	EmailFailed    int       `json:"email_failed"`
-	PushSent       int       `json:"push_sent"`
-	PushFailed     int       `json:"push_failed"`
+	NotificationID     uuid.UUID  `json:"notification_id"`
+	PushNotificationID *uuid.UUID `json:"push_notification_id,omitempty"`
+	RecipientCount     int        `json:"recipient_count"`
+	EmailSent          int        `json:"email_sent"`
+	EmailFailed        int        `json:"email_failed"`
+	PushSent           int        `json:"push_sent"`
+	PushFailed         int        `json:"push_failed"`
 }
 
 // NotificationHistoryDto represents a notification in the history
 type NotificationHistoryDto struct {
-	ID                  uuid.UUID `json:"id"`
-	EventID             uuid.UUID `json:"event_id"`
-	SentBy              uuid.UUID `json:"sent_by"`
-	SentByName          string    `json:"sent_by_name"`
-	Channel             string    `json:"channel"`
-	Subject             string    `json:"subject,omitempty"`
-	Message             string    `json:"message"`
-	IncludeEventDetails bool      `json:"include_event_details"`
-	RecipientCount      int       `json:"recipient_count"`
-	EmailSuccessCount   int       `json:"email_success_count"`
-	EmailFailureCount   int       `json:"email_failure_count"`
-	PushSuccessCount    int       `json:"push_success_count"`
-	PushFailureCount    int       `json:"push_failure_count"`
-	CreatedAt           string    `json:"created_at"`
+	ID                  uuid.UUID  `json:"id"`
+	EventID             uuid.UUID  `json:"event_id"`
+	SentBy              uuid.UUID  `json:"sent_by"`
+	SentByName          string     `json:"sent_by_name"`
+	Channel             string     `json:"channel"`
+	Subject             string     `json:"subject,omitempty"`
+	Message             string     `json:"message"`
+	IncludeEventDetails bool       `json:"include_event_details"`
+	RecipientCount      int        `json:"recipient_count"`
+	EmailSuccessCount   int        `json:"email_success_count"`
+	EmailFailureCount   int        `json:"email_failure_count"`
+	PushSuccessCount    int        `json:"push_success_count"`
+	PushFailureCount    int        `json:"push_failure_count"`
+	PushNotificationID  *uuid.UUID `json:"push_notification_id,omitempty"`
+	CreatedAt           string     `json:"created_at"`
 }
 
 // EventCustomerDto represents an enrolled customer for an event
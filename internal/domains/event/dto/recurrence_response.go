@@ -17,6 +17,7 @@ type (
 		SessionStart      string    `json:"session_start_at"`
 		SessionEnd        string    `json:"session_end_at"`
 		Day               string    `json:"day"`
+		RRule             string    `json:"rrule,omitempty"`
 		Team              *Team     `json:"team,omitempty"`
 		Location          Location  `json:"location"`
 		Program           Program   `json:"program"`
@@ -48,6 +49,7 @@ func NewRecurrenceResponseDto(recurrence values.ReadRecurrenceValues) Recurrence
 		SessionStart:      recurrence.StartTime,
 		SessionEnd:        recurrence.EndTime,
 		Day:               strings.ToUpper(recurrence.DayOfWeek.String()),
+		RRule:             recurrence.RRule,
 		Location: Location{
 			ID:      recurrence.Location.ID,
 			Name:    recurrence.Location.Name,
@@ -9,14 +9,18 @@ import (
 	values "api/internal/domains/event/values"
 	errLib "api/internal/libs/errors"
 	"api/internal/libs/validators"
+	"api/utils/recurrence"
 
 	"github.com/google/uuid"
 )
 
 type RecurrenceRequestDto struct {
-	Day                       string      `json:"day" example:"THURSDAY"`
+	Day string `json:"day" example:"THURSDAY"` // Used when rrule is empty
+	// RRule is an RFC 5545 rule (FREQ=DAILY|WEEKLY|MONTHLY with INTERVAL, BYDAY, BYMONTHDAY, COUNT, UNTIL).
+	RRule                     string      `json:"rrule" example:"FREQ=WEEKLY;BYDAY=MO,WE"`
+	ExDates                   []string    `json:"exdates" example:"[\"2025-12-24\"]"` // Dates (YYYY-MM-DD) to skip
 	RecurrenceStartAt         string      `json:"recurrence_start_at" validate:"required" example:"2023-10-05T07:00:00Z"`
-	RecurrenceEndAt           string      `json:"recurrence_end_at" validate:"required" example:"2023-10-05T07:00:00Z"`
+	RecurrenceEndAt           string      `json:"recurrence_end_at" example:"2023-10-05T07:00:00Z"` // Optional when rrule sets COUNT or UNTIL
 	EventStartTime            string      `json:"event_start_at" validate:"required" example:"23:00:00+00:00"`
 	EventEndTime              string      `json:"event_end_at" validate:"required" example:"23:00:00+00:00"`
 	ProgramID                 uuid.UUID   `json:"program_id" example:"f0e21457-75d4-4de6-b765-5ee13221fd72"`
@@ -48,6 +52,8 @@ type EventRequestDto struct {
 	UnitAmount       *int64 `json:"unit_amount" example:"2500"`        // Price in cents (e.g., 2500 = $25.00)
 	Currency         string `json:"currency" example:"cad"`           // "cad" or "usd", defaults to "cad"
 	SkipNotification *bool  `json:"skip_notification" example:"false"` // Skip auto-notification on update (for minor changes)
+	// Admin only: book the court over conflicting practices or games
+	OverrideReason string `json:"override_reason" example:"Playoff game takes priority"`
 }
 
 type DeleteRequestDto struct {
@@ -153,9 +159,21 @@ func (dto RecurrenceRequestDto) ToBaseRecurrenceValues() (values.BaseRecurrenceV
 		return values.BaseRecurrenceValues{}, err
 	}
 
-	recurrenceEndAt, err := validators.ParseDateTime(dto.RecurrenceEndAt)
-	if err != nil {
-		return values.BaseRecurrenceValues{}, err
+	var rule recurrence.Rule
+	if dto.RRule != "" {
+		var parseErr error
+		if rule, parseErr = recurrence.Parse(dto.RRule); parseErr != nil {
+			return values.BaseRecurrenceValues{}, errLib.New(fmt.Sprintf("Invalid rrule: %s", parseErr.Error()), http.StatusBadRequest)
+		}
+	}
+
+	var recurrenceEndAt time.Time
+	if dto.RecurrenceEndAt != "" {
+		if recurrenceEndAt, err = validators.ParseDateTime(dto.RecurrenceEndAt); err != nil {
+			return values.BaseRecurrenceValues{}, err
+		}
+	} else if rule.Count == 0 && rule.Until.IsZero() {
+		return values.BaseRecurrenceValues{}, errLib.New("recurrence_end_at is required unless rrule sets COUNT or UNTIL", http.StatusBadRequest)
 	}
 
 	eventStartTime, err := validators.ParseTime(dto.EventStartTime)
@@ -168,18 +186,64 @@ func (dto RecurrenceRequestDto) ToBaseRecurrenceValues() (values.BaseRecurrenceV
 		return values.BaseRecurrenceValues{}, err
 	}
 
-	day, err := validateWeekday(dto.Day)
-	if err != nil {
-		return values.BaseRecurrenceValues{}, err
+	var day time.Weekday
+	if dto.RRule == "" || dto.Day != "" {
+		if day, err = validateWeekday(dto.Day); err != nil {
+			return values.BaseRecurrenceValues{}, err
+		}
 	}
 
-	return values.BaseRecurrenceValues{
+	exDates := make([]time.Time, 0, len(dto.ExDates))
+	for _, exDate := range dto.ExDates {
+		date, err := validators.ParseDate(exDate)
+		if err != nil {
+			return values.BaseRecurrenceValues{}, err
+		}
+		exDates = append(exDates, date)
+	}
+
+	base := values.BaseRecurrenceValues{
 		DayOfWeek:       day,
 		FirstOccurrence: recurrenceStartAt,
 		LastOccurrence:  recurrenceEndAt,
 		StartTime:       eventStartTime,
 		EndTime:         eventEndTime,
-	}, nil
+		ExDates:         exDates,
+	}
+	if dto.RRule != "" {
+		base.RRule = rule.String()
+	}
+
+	return base, nil
+}
+
+// ParseEditScope parses the scope and date query parameters of a recurrence update or
+// delete. The scope defaults to "all"; "occurrence" and "following" require a date
+// (YYYY-MM-DD, the facility's calendar date of the first affected occurrence).
+func ParseEditScope(scopeStr, dateStr string) (values.EditScope, time.Time, *errLib.CommonError) {
+	scope := values.EditScopeAll
+	if scopeStr != "" {
+		scope = values.EditScope(strings.ToLower(scopeStr))
+	}
+
+	if !scope.Valid() {
+		return "", time.Time{}, errLib.New("Invalid scope. Expected one of: occurrence, following, all", http.StatusBadRequest)
+	}
+
+	if scope == values.EditScopeAll {
+		return scope, time.Time{}, nil
+	}
+
+	if dateStr == "" {
+		return "", time.Time{}, errLib.New(fmt.Sprintf("date is required when scope is %s", scope), http.StatusBadRequest)
+	}
+
+	date, err := validators.ParseDate(dateStr)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return scope, date, nil
 }
 
 func validateWeekday(day string) (time.Weekday, *errLib.CommonError) {
@@ -234,6 +298,7 @@ func (dto EventRequestDto) ToCreateEventValues(creator uuid.UUID) (values.Create
 			RegistrationRequired:      registrationRequired,
 			UnitAmount:                dto.UnitAmount,
 			Currency:                  dto.Currency,
+			OverrideReason:            dto.OverrideReason,
 		},
 	}
 
@@ -280,6 +345,7 @@ func (dto EventRequestDto) ToUpdateEventValues(idStr string, updater uuid.UUID)
 			RegistrationRequired:      registrationRequired,
 			UnitAmount:                dto.UnitAmount,
 			Currency:                  dto.Currency,
+			OverrideReason:            dto.OverrideReason,
 		},
 	}
 
@@ -135,12 +135,13 @@ func (h *EventNotificationHandler) SendNotification(w http.ResponseWriter, r *ht
 	}
 
 	response := dto.SendNotificationResponseDto{
-		NotificationID: result.NotificationID,
-		RecipientCount: result.RecipientCount,
-		EmailSent:      result.EmailSent,
-		EmailFailed:    result.EmailFailed,
-		PushSent:       result.PushSent,
-		PushFailed:     result.PushFailed,
+		NotificationID:     result.NotificationID,
+		PushNotificationID: result.PushNotificationID,
+		RecipientCount:     result.RecipientCount,
+		EmailSent:          result.EmailSent,
+		EmailFailed:        result.EmailFailed,
+		PushSent:           result.PushSent,
+		PushFailed:         result.PushFailed,
 	}
 
 	responseHandlers.RespondWithSuccess(w, response, http.StatusOK)
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	dto "api/internal/domains/event/dto"
+	values "api/internal/domains/event/values"
 	responseHandlers "api/internal/libs/responses"
 	"api/internal/libs/validators"
 	contextUtils "api/utils/context"
@@ -58,10 +59,16 @@ func (h *EventsHandler) CreateRecurrences(w http.ResponseWriter, r *http.Request
 }
 
 // UpdateRecurrences updates existing events by filters.
+// @Description The scope controls which occurrences change: a single occurrence, the occurrence on date and every
+// @Description one after it (the series is split), or the whole series. Individually edited occurrences and those
+// @Description with active enrollments are never regenerated.
 // @Tags events
 // @Accept json
 // @Produce json
 // @Security Bearer
+// @Param id path string true "Recurrence ID"
+// @Param scope query string false "occurrence, following or all (default all)"
+// @Param date query string false "Occurrence date (YYYY-MM-DD), required for occurrence and following"
 // @Param event body dto.RecurrenceRequestDto true "Update events details"
 // @Success 204 {object} map[string]interface{} "No Content: Events updated successfully"
 // @Failure 400 {object} map[string]interface{} "Bad Request: Invalid input"
@@ -98,6 +105,12 @@ func (h *EventsHandler) UpdateRecurrences(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	params.Scope, params.OccurrenceDate, err = dto.ParseEditScope(r.URL.Query().Get("scope"), r.URL.Query().Get("date"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
 	if err = h.EventsService.UpdateRecurringEvents(r.Context(), params); err != nil {
 		responseHandlers.RespondWithError(w, err)
 		return
@@ -107,11 +120,14 @@ func (h *EventsHandler) UpdateRecurrences(w http.ResponseWriter, r *http.Request
 }
 
 // DeleteRecurrence deletes existing events by recurrence ID.
+// @Description Deleting a single occurrence records its date as an exception on the series' rule.
 // @Tags events
 // @Accept json
 // @Produce json
 // @Security Bearer
 // @Param id path string true "Recurrence ID"
+// @Param scope query string false "occurrence, following or all (default all)"
+// @Param date query string false "Occurrence date (YYYY-MM-DD), required for occurrence and following"
 // @Success 204 {object} map[string]interface{} "No Content: Events deleted successfully"
 // @Failure 400 {object} map[string]interface{} "Bad Request: Invalid input"
 // @Failure 404 {object} map[string]interface{} "Not Found: Events not found"
@@ -132,7 +148,18 @@ func (h *EventsHandler) DeleteRecurrence(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if err = h.EventsService.DeleteUnmodifiedEventsByRecurrenceID(r.Context(), staffID, recurrenceID); err != nil {
+	scope, date, err := dto.ParseEditScope(r.URL.Query().Get("scope"), r.URL.Query().Get("date"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	if err = h.EventsService.DeleteRecurrence(r.Context(), values.DeleteRecurrenceValues{
+		ID:             recurrenceID,
+		DeletedBy:      staffID,
+		Scope:          scope,
+		OccurrenceDate: date,
+	}); err != nil {
 		responseHandlers.RespondWithError(w, err)
 		return
 	}
@@ -118,6 +118,7 @@ func (r *EventsRepository) GetEventMembershipPlanIDs(ctx context.Context, eventI
 func (r *EventsRepository) CreateEvents(ctx context.Context, eventDetails []values.CreateEventValues) *errLib.CommonError {
 	var (
 		locationIDs, programIDs, courtIDs, teamIDs, createdByIds, recurrenceIds []uuid.UUID
+		blackoutIDs                                                             []uuid.UUID
 		startAtArray, endAtArray                                                []time.Time
 		isCancelledArray, isDateTimeModifiedArray, registrationRequiredArray    []bool
 		priceIDs                                                                []string
@@ -134,7 +135,12 @@ func (r *EventsRepository) CreateEvents(ctx context.Context, eventDetails []valu
 		locationIDs = append(locationIDs, event.LocationID)
 		programIDs = append(programIDs, event.ProgramID)
 		courtIDs = append(courtIDs, event.CourtID)
-		recurrenceIds = append(recurrenceIds, recurrenceId)
+		if event.RecurrenceID != uuid.Nil {
+			recurrenceIds = append(recurrenceIds, event.RecurrenceID)
+		} else {
+			recurrenceIds = append(recurrenceIds, recurrenceId)
+		}
+		blackoutIDs = append(blackoutIDs, event.BlackoutID)
 		teamIDs = append(teamIDs, event.TeamID)
 		startAtArray = append(startAtArray, event.StartAt)
 		endAtArray = append(endAtArray, event.EndAt)
@@ -167,6 +173,7 @@ func (r *EventsRepository) CreateEvents(ctx context.Context, eventDetails []valu
 		PriceIds:                priceIDs,
 		CreditCosts:               creditCosts,
 		RegistrationRequiredArray: registrationRequiredArray,
+		BlackoutIds:               blackoutIDs,
 	}
 
 	impactedRows, dbErr := r.Queries.CreateEvents(ctx, dbParams)
@@ -254,6 +261,8 @@ func (r *EventsRepository) GetEvent(ctx context.Context, id uuid.UUID) (values.R
 		PriceID:                   nullStringToPtr(dbEvent.PriceID),
 		CreditCost:                nullInt32ToPtr(dbEvent.CreditCost),
 		RegistrationRequired:      dbEvent.RegistrationRequired,
+		RecurrenceID:              nullUUIDToPtr(dbEvent.RecurrenceID),
+		BlackoutID:                nullUUIDToPtr(dbEvent.BlackoutID),
 	}
 
 	if dbEvent.CourtID.Valid && dbEvent.CourtName.Valid {
@@ -404,6 +413,8 @@ func (r *EventsRepository) GetEvents(ctx context.Context, filter values.GetEvent
 			PriceID:                   nullStringToPtr(row.PriceID),
 			CreditCost:                nullInt32ToPtr(row.CreditCost),
 			RegistrationRequired:      row.RegistrationRequired,
+			RecurrenceID:              nullUUIDToPtr(row.RecurrenceID),
+			BlackoutID:                nullUUIDToPtr(row.BlackoutID),
 		}
 
 		if row.TeamID.Valid && row.TeamName.Valid {
@@ -542,3 +553,97 @@ func (r *EventsRepository) DeleteUnmodifiedEventsByRecurrenceID(c context.Contex
 
 	return nil
 }
+
+// DeleteUnmodifiedEventsByRecurrenceIDFrom removes the occurrences of a series starting at
+// or after from that were not individually edited and have no active enrollments.
+func (r *EventsRepository) DeleteUnmodifiedEventsByRecurrenceIDFrom(c context.Context, id uuid.UUID, from time.Time) *errLib.CommonError {
+	err := r.Queries.DeleteUnmodifiedEventsByRecurrenceIDFrom(c, db.DeleteUnmodifiedEventsByRecurrenceIDFromParams{
+		RecurrenceID: uuid.NullUUID{UUID: id, Valid: true},
+		From:         from,
+	})
+	if err != nil {
+		log.Printf("Failed to delete events with recurrence Id: %s from %s. Error: %s", id, from, err.Error())
+		return errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+// DeleteRecurrenceOccurrence removes a single occurrence of a series. Occurrences with
+// active enrollments are kept and reported as a conflict.
+func (r *EventsRepository) DeleteRecurrenceOccurrence(c context.Context, recurrenceID, eventID uuid.UUID) *errLib.CommonError {
+	affected, err := r.Queries.DeleteRecurrenceOccurrence(c, db.DeleteRecurrenceOccurrenceParams{
+		ID:           eventID,
+		RecurrenceID: uuid.NullUUID{UUID: recurrenceID, Valid: true},
+	})
+	if err != nil {
+		log.Printf("Failed to delete occurrence %s of recurrence %s. Error: %s", eventID, recurrenceID, err.Error())
+		return errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+
+	if affected == 0 {
+		return errLib.New("The occurrence has active enrollments; cancel it instead of deleting it", http.StatusConflict)
+	}
+
+	return nil
+}
+
+// GetRecurrenceEvents returns the remaining occurrences of a series ordered by start time.
+func (r *EventsRepository) GetRecurrenceEvents(c context.Context, recurrenceID uuid.UUID) ([]db.GetRecurrenceEventsRow, *errLib.CommonError) {
+	rows, err := r.Queries.GetRecurrenceEvents(c, uuid.NullUUID{UUID: recurrenceID, Valid: true})
+	if err != nil {
+		log.Printf("Failed to get events for recurrence %s. Error: %s", recurrenceID, err.Error())
+		return nil, errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+
+	return rows, nil
+}
+
+// GetRecurrenceRule returns the stored rule of a series. found is false for series
+// created before rules were stored.
+func (r *EventsRepository) GetRecurrenceRule(c context.Context, recurrenceID uuid.UUID) (rule values.RecurrenceRule, found bool, err *errLib.CommonError) {
+	row, dbErr := r.Queries.GetRecurrenceRule(c, recurrenceID)
+	if dbErr != nil {
+		if errors.Is(dbErr, sql.ErrNoRows) {
+			return values.RecurrenceRule{}, false, nil
+		}
+		log.Printf("Failed to get rule for recurrence %s. Error: %s", recurrenceID, dbErr.Error())
+		return values.RecurrenceRule{}, false, errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+
+	exdates := make([]time.Time, 0, len(row.Exdates))
+	for _, d := range row.Exdates {
+		date, parseErr := time.Parse("2006-01-02", d)
+		if parseErr != nil {
+			log.Printf("Ignoring malformed exdate %q on recurrence %s", d, recurrenceID)
+			continue
+		}
+		exdates = append(exdates, date)
+	}
+
+	return values.RecurrenceRule{
+		ID:      row.ID,
+		RRule:   row.Rrule,
+		DTStart: row.Dtstart,
+		ExDates: exdates,
+	}, true, nil
+}
+
+func (r *EventsRepository) SaveRecurrenceRule(c context.Context, rule values.RecurrenceRule) *errLib.CommonError {
+	exdates := make([]string, len(rule.ExDates))
+	for i, d := range rule.ExDates {
+		exdates[i] = d.Format("2006-01-02")
+	}
+
+	if err := r.Queries.UpsertRecurrenceRule(c, db.UpsertRecurrenceRuleParams{
+		ID:      rule.ID,
+		Rrule:   rule.RRule,
+		Dtstart: rule.DTStart,
+		Exdates: exdates,
+	}); err != nil {
+		log.Printf("Failed to save rule for recurrence %s. Error: %s", rule.ID, err.Error())
+		return errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+
+	return nil
+}
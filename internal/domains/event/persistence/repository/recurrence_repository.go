@@ -100,6 +100,7 @@ func (r *RecurrencesRepository) GetEventsRecurrences(ctx context.Context, progra
 				EndTime:         row.EndTime,
 				FirstOccurrence: row.FirstOccurrence,
 				LastOccurrence:  row.LastOccurrence,
+				RRule:           row.Rrule,
 			},
 			Location: struct {
 				ID      uuid.UUID
@@ -79,7 +79,8 @@ WITH unnested_data AS (
         unnest($11::text[])         AS cancellation_reason,
         unnest($12::text[])                    AS price_id,
         unnest($13::int[])                  AS credit_cost,
-        unnest($14::bool[])  AS registration_required
+        unnest($14::bool[])  AS registration_required,
+        unnest($15::uuid[])                 AS blackout_id
 )
 INSERT INTO events.events (
     location_id,
@@ -96,7 +97,8 @@ INSERT INTO events.events (
     cancellation_reason,
     price_id,
     credit_cost,
-    registration_required
+    registration_required,
+    blackout_id
 )
 SELECT
     location_id,
@@ -113,7 +115,8 @@ SELECT
     NULLIF(cancellation_reason, ''),
     NULLIF(price_id, ''),
     NULLIF(credit_cost, 0),
-    registration_required
+    registration_required,
+    NULLIF(blackout_id, '00000000-0000-0000-0000-000000000000'::uuid)
 FROM unnested_data
 ON CONFLICT ON CONSTRAINT no_overlapping_events DO NOTHING
 `
@@ -133,6 +136,7 @@ type CreateEventsParams struct {
 	PriceIds                  []string    `json:"price_ids"`
 	CreditCosts               []int32     `json:"credit_costs"`
 	RegistrationRequiredArray []bool      `json:"registration_required_array"`
+	BlackoutIds               []uuid.UUID `json:"blackout_ids"`
 }
 
 func (q *Queries) CreateEvents(ctx context.Context, arg CreateEventsParams) (int64, error) {
@@ -151,6 +155,7 @@ func (q *Queries) CreateEvents(ctx context.Context, arg CreateEventsParams) (int
 		pq.Array(arg.PriceIds),
 		pq.Array(arg.CreditCosts),
 		pq.Array(arg.RegistrationRequiredArray),
+		pq.Array(arg.BlackoutIds),
 	)
 	if err != nil {
 		return 0, err
@@ -170,7 +175,7 @@ func (q *Queries) DeleteEventsByIds(ctx context.Context, ids []uuid.UUID) error
 }
 
 const getEventById = `-- name: GetEventById :one
-SELECT e.id, e.location_id, e.program_id, e.team_id, e.start_at, e.end_at, e.created_by, e.updated_by, e.is_cancelled, e.cancellation_reason, e.created_at, e.updated_at, e.is_date_time_modified, e.recurrence_id, e.court_id, e.price_id, e.credit_cost, e.registration_required,
+SELECT e.id, e.location_id, e.program_id, e.team_id, e.start_at, e.end_at, e.created_by, e.updated_by, e.is_cancelled, e.cancellation_reason, e.created_at, e.updated_at, e.is_date_time_modified, e.recurrence_id, e.court_id, e.price_id, e.credit_cost, e.registration_required, e.blackout_id,
 
        creator.first_name AS creator_first_name,
        creator.last_name  AS creator_last_name,
@@ -215,6 +220,7 @@ type GetEventByIdRow struct {
 	PriceID              sql.NullString     `json:"price_id"`
 	CreditCost           sql.NullInt32      `json:"credit_cost"`
 	RegistrationRequired bool               `json:"registration_required"`
+	BlackoutID           uuid.NullUUID      `json:"blackout_id"`
 	CreatorFirstName     string             `json:"creator_first_name"`
 	CreatorLastName      string             `json:"creator_last_name"`
 	UpdaterFirstName     string             `json:"updater_first_name"`
@@ -251,6 +257,7 @@ func (q *Queries) GetEventById(ctx context.Context, id uuid.UUID) (GetEventByIdR
 		&i.PriceID,
 		&i.CreditCost,
 		&i.RegistrationRequired,
+		&i.BlackoutID,
 		&i.CreatorFirstName,
 		&i.CreatorLastName,
 		&i.UpdaterFirstName,
@@ -416,7 +423,7 @@ func (q *Queries) GetEventStaffs(ctx context.Context, eventID uuid.UUID) ([]GetE
 }
 
 const getEvents = `-- name: GetEvents :many
-SELECT DISTINCT e.id, e.location_id, e.program_id, e.team_id, e.start_at, e.end_at, e.created_by, e.updated_by, e.is_cancelled, e.cancellation_reason, e.created_at, e.updated_at, e.is_date_time_modified, e.recurrence_id, e.court_id, e.price_id, e.credit_cost, e.registration_required,
+SELECT DISTINCT e.id, e.location_id, e.program_id, e.team_id, e.start_at, e.end_at, e.created_by, e.updated_by, e.is_cancelled, e.cancellation_reason, e.created_at, e.updated_at, e.is_date_time_modified, e.recurrence_id, e.court_id, e.price_id, e.credit_cost, e.registration_required, e.blackout_id,
 
                 creator.first_name AS creator_first_name,
                 creator.last_name  AS creator_last_name,
@@ -496,6 +503,7 @@ type GetEventsRow struct {
 	PriceID              sql.NullString     `json:"price_id"`
 	CreditCost           sql.NullInt32      `json:"credit_cost"`
 	RegistrationRequired bool               `json:"registration_required"`
+	BlackoutID           uuid.NullUUID      `json:"blackout_id"`
 	CreatorFirstName     string             `json:"creator_first_name"`
 	CreatorLastName      string             `json:"creator_last_name"`
 	UpdaterFirstName     string             `json:"updater_first_name"`
@@ -553,6 +561,7 @@ func (q *Queries) GetEvents(ctx context.Context, arg GetEventsParams) ([]GetEven
 			&i.PriceID,
 			&i.CreditCost,
 			&i.RegistrationRequired,
+			&i.BlackoutID,
 			&i.CreatorFirstName,
 			&i.CreatorLastName,
 			&i.UpdaterFirstName,
@@ -624,6 +624,7 @@ type EventsEvent struct {
 	PriceID              sql.NullString `json:"price_id"`
 	CreditCost           sql.NullInt32  `json:"credit_cost"`
 	RegistrationRequired bool           `json:"registration_required"`
+	BlackoutID           uuid.NullUUID  `json:"blackout_id"`
 }
 
 type EventsEventMembershipAccess struct {
@@ -633,6 +634,15 @@ type EventsEventMembershipAccess struct {
 	CreatedAt        sql.NullTime `json:"created_at"`
 }
 
+type EventsRecurrenceRule struct {
+	ID        uuid.UUID   `json:"id"`
+	Rrule     string      `json:"rrule"`
+	Dtstart   time.Time   `json:"dtstart"`
+	Exdates   []time.Time `json:"exdates"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
 type EventsStaff struct {
 	EventID uuid.UUID `json:"event_id"`
 	StaffID uuid.UUID `json:"staff_id"`
@@ -709,6 +719,8 @@ type LocationLocation struct {
 	Address   string    `json:"address"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	Timezone  string    `json:"timezone"`
+	Province  string    `json:"province"`
 }
 
 type MembershipDiscountRestrictedMembershipPlan struct {
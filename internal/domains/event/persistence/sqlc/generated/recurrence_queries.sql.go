@@ -11,8 +11,33 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+const deleteRecurrenceOccurrence = `-- name: DeleteRecurrenceOccurrence :execrows
+DELETE
+FROM events.events
+WHERE id = $1
+  AND recurrence_id = $2
+  AND NOT EXISTS (SELECT 1
+                  FROM events.customer_enrollment ce
+                  WHERE ce.event_id = events.events.id
+                    AND ce.is_cancelled = false)
+`
+
+type DeleteRecurrenceOccurrenceParams struct {
+	ID           uuid.UUID     `json:"id"`
+	RecurrenceID uuid.NullUUID `json:"recurrence_id"`
+}
+
+func (q *Queries) DeleteRecurrenceOccurrence(ctx context.Context, arg DeleteRecurrenceOccurrenceParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteRecurrenceOccurrence, arg.ID, arg.RecurrenceID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const deleteUnmodifiedEventsByRecurrenceID = `-- name: DeleteUnmodifiedEventsByRecurrenceID :exec
 DELETE
 FROM events.events
@@ -30,10 +55,32 @@ func (q *Queries) DeleteUnmodifiedEventsByRecurrenceID(ctx context.Context, recu
 	return err
 }
 
+const deleteUnmodifiedEventsByRecurrenceIDFrom = `-- name: DeleteUnmodifiedEventsByRecurrenceIDFrom :exec
+DELETE
+FROM events.events
+WHERE recurrence_id = $1
+  AND is_date_time_modified = false
+  AND start_at >= GREATEST(CURRENT_TIMESTAMP, $2::timestamptz)
+  AND NOT EXISTS (SELECT 1
+                  FROM events.customer_enrollment ce
+                  WHERE ce.event_id = events.events.id
+                    AND ce.is_cancelled = false)
+`
+
+type DeleteUnmodifiedEventsByRecurrenceIDFromParams struct {
+	RecurrenceID uuid.NullUUID `json:"recurrence_id"`
+	From         time.Time     `json:"from"`
+}
+
+func (q *Queries) DeleteUnmodifiedEventsByRecurrenceIDFrom(ctx context.Context, arg DeleteUnmodifiedEventsByRecurrenceIDFromParams) error {
+	_, err := q.db.ExecContext(ctx, deleteUnmodifiedEventsByRecurrenceIDFrom, arg.RecurrenceID, arg.From)
+	return err
+}
+
 const getEventsRecurrence = `-- name: GetEventsRecurrence :many
-SELECT trim(to_char(start_at, 'Day')) AS day_of_week, -- More readable
-       to_char(start_at, 'HH24:MI')   AS start_time,
-       to_char(end_at, 'HH24:MI')     AS end_time,
+SELECT trim(to_char(start_at AT TIME ZONE l.timezone, 'Day')) AS day_of_week, -- More readable
+       to_char(start_at AT TIME ZONE l.timezone, 'HH24:MI')   AS start_time,
+       to_char(end_at AT TIME ZONE l.timezone, 'HH24:MI')     AS end_time,
        recurrence_id,
        p.id                           AS program_id,
        p.name                         AS program_name,
@@ -49,13 +96,15 @@ SELECT trim(to_char(start_at, 'Day')) AS day_of_week, -- More readable
 
        COUNT(*)                       AS event_count,
        MIN(start_at)::timestamp       AS first_occurrence,
-       MAX(end_at)::timestamp         AS last_occurrence
+       MAX(end_at)::timestamp         AS last_occurrence,
+       COALESCE(rr.rrule, '')::text   AS rrule
 FROM events.events e
          LEFT JOIN events.staff es ON e.id = es.event_id
          LEFT JOIN events.customer_enrollment ce ON e.id = ce.event_id
          JOIN program.programs p ON e.program_id = p.id
          LEFT JOIN athletic.teams t ON e.team_id = t.id
          JOIN location.locations l ON e.location_id = l.id
+         LEFT JOIN events.recurrence_rules rr ON rr.id = e.recurrence_id
 WHERE ($1::uuid IS NULL OR program_id = $1::uuid)
   AND ($2::uuid IS NULL OR e.team_id = $2)
   AND ($3::uuid IS NULL OR location_id = $3::uuid)
@@ -66,9 +115,9 @@ WHERE ($1::uuid IS NULL OR program_id = $1::uuid)
   AND ($8 = p.type OR $8 IS NULL)
   AND ($9::uuid IS NULL OR ce.customer_id = $9::uuid OR
        es.staff_id = $9::uuid)
-GROUP BY to_char(start_at, 'Day'),
-         to_char(start_at, 'HH24:MI'),
-         to_char(end_at, 'HH24:MI'),
+GROUP BY to_char(start_at AT TIME ZONE l.timezone, 'Day'),
+         to_char(start_at AT TIME ZONE l.timezone, 'HH24:MI'),
+         to_char(end_at AT TIME ZONE l.timezone, 'HH24:MI'),
          p.id,
          p.name,
          p.description,
@@ -78,6 +127,7 @@ GROUP BY to_char(start_at, 'Day'),
          location_id,
          l.name,
          l.address,
+         rr.rrule,
         recurrence_id
 `
 
@@ -110,6 +160,7 @@ type GetEventsRecurrenceRow struct {
 	EventCount         int64              `json:"event_count"`
 	FirstOccurrence    time.Time          `json:"first_occurrence"`
 	LastOccurrence     time.Time          `json:"last_occurrence"`
+	Rrule              string             `json:"rrule"`
 }
 
 func (q *Queries) GetEventsRecurrence(ctx context.Context, arg GetEventsRecurrenceParams) ([]GetEventsRecurrenceRow, error) {
@@ -148,6 +199,49 @@ func (q *Queries) GetEventsRecurrence(ctx context.Context, arg GetEventsRecurren
 			&i.EventCount,
 			&i.FirstOccurrence,
 			&i.LastOccurrence,
+			&i.Rrule,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRecurrenceEvents = `-- name: GetRecurrenceEvents :many
+SELECT id, start_at, end_at, is_date_time_modified
+FROM events.events
+WHERE recurrence_id = $1
+ORDER BY start_at
+`
+
+type GetRecurrenceEventsRow struct {
+	ID                 uuid.UUID `json:"id"`
+	StartAt            time.Time `json:"start_at"`
+	EndAt              time.Time `json:"end_at"`
+	IsDateTimeModified bool      `json:"is_date_time_modified"`
+}
+
+func (q *Queries) GetRecurrenceEvents(ctx context.Context, recurrenceID uuid.NullUUID) ([]GetRecurrenceEventsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getRecurrenceEvents, recurrenceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRecurrenceEventsRow
+	for rows.Next() {
+		var i GetRecurrenceEventsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.StartAt,
+			&i.EndAt,
+			&i.IsDateTimeModified,
 		); err != nil {
 			return nil, err
 		}
@@ -161,3 +255,55 @@ func (q *Queries) GetEventsRecurrence(ctx context.Context, arg GetEventsRecurren
 	}
 	return items, nil
 }
+
+const getRecurrenceRule = `-- name: GetRecurrenceRule :one
+SELECT id, rrule, dtstart, exdates::text[] AS exdates
+FROM events.recurrence_rules
+WHERE id = $1
+`
+
+type GetRecurrenceRuleRow struct {
+	ID      uuid.UUID `json:"id"`
+	Rrule   string    `json:"rrule"`
+	Dtstart time.Time `json:"dtstart"`
+	Exdates []string  `json:"exdates"`
+}
+
+func (q *Queries) GetRecurrenceRule(ctx context.Context, id uuid.UUID) (GetRecurrenceRuleRow, error) {
+	row := q.db.QueryRowContext(ctx, getRecurrenceRule, id)
+	var i GetRecurrenceRuleRow
+	err := row.Scan(
+		&i.ID,
+		&i.Rrule,
+		&i.Dtstart,
+		pq.Array(&i.Exdates),
+	)
+	return i, err
+}
+
+const upsertRecurrenceRule = `-- name: UpsertRecurrenceRule :exec
+INSERT INTO events.recurrence_rules (id, rrule, dtstart, exdates)
+VALUES ($1, $2, $3, $4::text[]::date[])
+ON CONFLICT (id) DO UPDATE
+    SET rrule      = EXCLUDED.rrule,
+        dtstart    = EXCLUDED.dtstart,
+        exdates    = EXCLUDED.exdates,
+        updated_at = CURRENT_TIMESTAMP
+`
+
+type UpsertRecurrenceRuleParams struct {
+	ID      uuid.UUID `json:"id"`
+	Rrule   string    `json:"rrule"`
+	Dtstart time.Time `json:"dtstart"`
+	Exdates []string  `json:"exdates"`
+}
+
+func (q *Queries) UpsertRecurrenceRule(ctx context.Context, arg UpsertRecurrenceRuleParams) error {
+	_, err := q.db.ExecContext(ctx, upsertRecurrenceRule,
+		arg.ID,
+		arg.Rrule,
+		arg.Dtstart,
+		pq.Array(arg.Exdates),
+	)
+	return err
+}
@@ -14,7 +14,8 @@ WITH unnested_data AS (
         unnest(sqlc.arg('cancellation_reasons')::text[])         AS cancellation_reason,
         unnest(sqlc.arg('price_ids')::text[])                    AS price_id,
         unnest(sqlc.arg('credit_costs')::int[])                  AS credit_cost,
-        unnest(sqlc.arg('registration_required_array')::bool[])  AS registration_required
+        unnest(sqlc.arg('registration_required_array')::bool[])  AS registration_required,
+        unnest(sqlc.arg('blackout_ids')::uuid[])                 AS blackout_id
 )
 INSERT INTO events.events (
     location_id,
@@ -31,7 +32,8 @@ INSERT INTO events.events (
     cancellation_reason,
     price_id,
     credit_cost,
-    registration_required
+    registration_required,
+    blackout_id
 )
 SELECT
     location_id,
@@ -48,7 +50,8 @@ SELECT
     NULLIF(cancellation_reason, ''),
     NULLIF(price_id, ''),
     NULLIF(credit_cost, 0),
-    registration_required
+    registration_required,
+    NULLIF(blackout_id, '00000000-0000-0000-0000-000000000000'::uuid)
 FROM unnested_data
 ON CONFLICT ON CONSTRAINT no_overlapping_events DO NOTHING;
 
@@ -1,7 +1,7 @@
 -- name: GetEventsRecurrence :many
-SELECT trim(to_char(start_at, 'Day')) AS day_of_week, -- More readable
-       to_char(start_at, 'HH24:MI')   AS start_time,
-       to_char(end_at, 'HH24:MI')     AS end_time,
+SELECT trim(to_char(start_at AT TIME ZONE l.timezone, 'Day')) AS day_of_week, -- More readable
+       to_char(start_at AT TIME ZONE l.timezone, 'HH24:MI')   AS start_time,
+       to_char(end_at AT TIME ZONE l.timezone, 'HH24:MI')     AS end_time,
        recurrence_id,
        p.id                           AS program_id,
        p.name                         AS program_name,
@@ -17,13 +17,15 @@ SELECT trim(to_char(start_at, 'Day')) AS day_of_week, -- More readable
 
        COUNT(*)                       AS event_count,
        MIN(start_at)::timestamp       AS first_occurrence,
-       MAX(end_at)::timestamp         AS last_occurrence
+       MAX(end_at)::timestamp         AS last_occurrence,
+       COALESCE(rr.rrule, '')::text   AS rrule
 FROM events.events e
          LEFT JOIN events.staff es ON e.id = es.event_id
          LEFT JOIN events.customer_enrollment ce ON e.id = ce.event_id
          JOIN program.programs p ON e.program_id = p.id
          LEFT JOIN athletic.teams t ON e.team_id = t.id
          JOIN location.locations l ON e.location_id = l.id
+         LEFT JOIN events.recurrence_rules rr ON rr.id = e.recurrence_id
 WHERE (sqlc.narg('program_id')::uuid IS NULL OR program_id = sqlc.narg('program_id')::uuid)
   AND (sqlc.narg('team_id')::uuid IS NULL OR e.team_id = sqlc.narg('team_id'))
   AND (sqlc.narg('location_id')::uuid IS NULL OR location_id = sqlc.narg('location_id')::uuid)
@@ -34,9 +36,9 @@ WHERE (sqlc.narg('program_id')::uuid IS NULL OR program_id = sqlc.narg('program_
   AND (sqlc.narg('type') = p.type OR sqlc.narg('type') IS NULL)
   AND (sqlc.narg('user_id')::uuid IS NULL OR ce.customer_id = sqlc.narg('user_id')::uuid OR
        es.staff_id = sqlc.narg('user_id')::uuid)
-GROUP BY to_char(start_at, 'Day'),
-         to_char(start_at, 'HH24:MI'),
-         to_char(end_at, 'HH24:MI'),
+GROUP BY to_char(start_at AT TIME ZONE l.timezone, 'Day'),
+         to_char(start_at AT TIME ZONE l.timezone, 'HH24:MI'),
+         to_char(end_at AT TIME ZONE l.timezone, 'HH24:MI'),
          p.id,
          p.name,
          p.description,
@@ -46,6 +48,7 @@ GROUP BY to_char(start_at, 'Day'),
          location_id,
          l.name,
          l.address,
+         rr.rrule,
         recurrence_id;
 
 -- name: DeleteUnmodifiedEventsByRecurrenceID :exec
@@ -57,4 +60,44 @@ WHERE recurrence_id = $1
   AND NOT EXISTS (SELECT 1
                   FROM events.customer_enrollment ce
                   WHERE ce.event_id = events.events.id
-                    AND ce.is_cancelled = false);
\ No newline at end of file
+                    AND ce.is_cancelled = false);
+-- name: DeleteUnmodifiedEventsByRecurrenceIDFrom :exec
+DELETE
+FROM events.events
+WHERE recurrence_id = sqlc.arg('recurrence_id')
+  AND is_date_time_modified = false
+  AND start_at >= GREATEST(CURRENT_TIMESTAMP, sqlc.arg('from')::timestamptz)
+  AND NOT EXISTS (SELECT 1
+                  FROM events.customer_enrollment ce
+                  WHERE ce.event_id = events.events.id
+                    AND ce.is_cancelled = false);
+
+-- name: DeleteRecurrenceOccurrence :execrows
+DELETE
+FROM events.events
+WHERE id = $1
+  AND recurrence_id = $2
+  AND NOT EXISTS (SELECT 1
+                  FROM events.customer_enrollment ce
+                  WHERE ce.event_id = events.events.id
+                    AND ce.is_cancelled = false);
+
+-- name: GetRecurrenceEvents :many
+SELECT id, start_at, end_at, is_date_time_modified
+FROM events.events
+WHERE recurrence_id = $1
+ORDER BY start_at;
+
+-- name: GetRecurrenceRule :one
+SELECT id, rrule, dtstart, exdates::text[] AS exdates
+FROM events.recurrence_rules
+WHERE id = $1;
+
+-- name: UpsertRecurrenceRule :exec
+INSERT INTO events.recurrence_rules (id, rrule, dtstart, exdates)
+VALUES (sqlc.arg('id'), sqlc.arg('rrule'), sqlc.arg('dtstart'), sqlc.arg('exdates')::text[]::date[])
+ON CONFLICT (id) DO UPDATE
+    SET rrule      = EXCLUDED.rrule,
+        dtstart    = EXCLUDED.dtstart,
+        exdates    = EXCLUDED.exdates,
+        updated_at = CURRENT_TIMESTAMP;
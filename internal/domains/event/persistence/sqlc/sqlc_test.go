@@ -57,8 +57,9 @@ func TestCreateEvents(t *testing.T) {
 	require.NoError(t, err)
 
 	createdLocation, err := locationQueries.CreateLocation(context.Background(), locationDb.CreateLocationParams{
-		Name:    "Main Conference Room",
-		Address: "123 Main St",
+		Name:     "Main Conference Room",
+		Address:  "123 Main St",
+		Timezone: "America/Edmonton",
 	})
 
 	require.NoError(t, err)
@@ -151,8 +152,9 @@ func TestUpdateEvent(t *testing.T) {
 	require.NoError(t, err)
 
 	createdLocation, err := locationQueries.CreateLocation(context.Background(), locationDb.CreateLocationParams{
-		Name:    "Main Conference Room",
-		Address: "123 Main St",
+		Name:     "Main Conference Room",
+		Address:  "123 Main St",
+		Timezone: "America/Edmonton",
 	})
 
 	require.NoError(t, err)
@@ -237,8 +239,9 @@ func TestDeleteEvent(t *testing.T) {
 	require.NoError(t, err)
 
 	createLocationParams := locationDb.CreateLocationParams{
-		Name:    "Main Conference Room",
-		Address: "123 Main St",
+		Name:     "Main Conference Room",
+		Address:  "123 Main St",
+		Timezone: "America/Edmonton",
 	}
 
 	createdLocation, err := locationQueries.CreateLocation(context.Background(), createLocationParams)
@@ -313,8 +316,9 @@ func TestGetEventsByCourt(t *testing.T) {
 	require.NoError(t, err)
 
 	createdLocation, err := locationQueries.CreateLocation(context.Background(), locationDb.CreateLocationParams{
-		Name:    "Main Court",
-		Address: "123 Street",
+		Name:     "Main Court",
+		Address:  "123 Street",
+		Timezone: "America/Edmonton",
 	})
 	require.NoError(t, err)
 
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	values "api/internal/domains/event/values"
+
+	"github.com/google/uuid"
+)
+
+// eventAuditState is the part of an event recorded in the audit log when it changes
+type eventAuditState struct {
+	StartAt                   time.Time  `json:"start_at"`
+	EndAt                     time.Time  `json:"end_at"`
+	ProgramID                 uuid.UUID  `json:"program_id"`
+	LocationID                uuid.UUID  `json:"location_id"`
+	CourtID                   *uuid.UUID `json:"court_id"`
+	TeamID                    *uuid.UUID `json:"team_id"`
+	RequiredMembershipPlanIDs []string   `json:"required_membership_plan_ids"`
+	PriceID                   string     `json:"price_id"`
+	CreditCost                *int32     `json:"credit_cost"`
+	RegistrationRequired      bool       `json:"registration_required"`
+}
+
+func eventAuditStateOf(event values.ReadEventValues) eventAuditState {
+	state := eventAuditState{
+		StartAt:                   event.StartAt.UTC(),
+		EndAt:                     event.EndAt.UTC(),
+		ProgramID:                 event.Program.ID,
+		LocationID:                event.Location.ID,
+		RequiredMembershipPlanIDs: sortedIDs(event.RequiredMembershipPlanIDs),
+		CreditCost:                event.CreditCost,
+		RegistrationRequired:      event.RegistrationRequired,
+	}
+	if event.Court != nil {
+		state.CourtID = &event.Court.ID
+	}
+	if event.Team != nil {
+		state.TeamID = &event.Team.ID
+	}
+	if event.PriceID != nil {
+		state.PriceID = *event.PriceID
+	}
+	return state
+}
+
+func eventAuditStateFromDetails(details values.EventDetails) eventAuditState {
+	state := eventAuditState{
+		StartAt:                   details.StartAt.UTC(),
+		EndAt:                     details.EndAt.UTC(),
+		ProgramID:                 details.ProgramID,
+		LocationID:                details.LocationID,
+		RequiredMembershipPlanIDs: sortedIDs(details.RequiredMembershipPlanIDs),
+		PriceID:                   details.PriceID,
+		CreditCost:                details.CreditCost,
+		RegistrationRequired:      details.RegistrationRequired,
+	}
+	if details.CourtID != uuid.Nil {
+		state.CourtID = &details.CourtID
+	}
+	if details.TeamID != uuid.Nil {
+		state.TeamID = &details.TeamID
+	}
+	return state
+}
+
+// sortedIDs lists ids in a stable order so reordering them alone is not a change
+func sortedIDs(ids []uuid.UUID) []string {
+	sorted := make([]string, len(ids))
+	for i, id := range ids {
+		sorted[i] = id.String()
+	}
+	sort.Strings(sorted)
+	return sorted
+}
@@ -1,10 +1,8 @@
 package service
 
 import (
-	"bytes"
 	"context"
 	"database/sql"
-	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
@@ -13,21 +11,26 @@ import (
 
 	"api/internal/di"
 	dto "api/internal/domains/event/dto"
+	notificationService "api/internal/domains/notification/services"
+	notificationValues "api/internal/domains/notification/values"
 	errLib "api/internal/libs/errors"
 	"api/utils/email"
+	"api/utils/timezone"
 
 	"github.com/google/uuid"
 )
 
 // EventNotificationService handles sending notifications to event attendees
 type EventNotificationService struct {
-	db *sql.DB
+	db   *sql.DB
+	push *notificationService.NotificationService
 }
 
 // NewEventNotificationService creates a new EventNotificationService
 func NewEventNotificationService(container *di.Container) *EventNotificationService {
 	return &EventNotificationService{
-		db: container.DB,
+		db:   container.DB,
+		push: notificationService.NewNotificationService(container),
 	}
 }
 
@@ -57,12 +60,13 @@ type EventCustomerPushToken struct {
 
 // NotificationResult tracks the results of sending notifications
 type NotificationResult struct {
-	NotificationID uuid.UUID
-	RecipientCount int
-	EmailSent      int
-	EmailFailed    int
-	PushSent       int
-	PushFailed     int
+	NotificationID     uuid.UUID
+	PushNotificationID *uuid.UUID // Push delivery tracking, nil when no push was sent
+	RecipientCount     int
+	EmailSent          int
+	EmailFailed        int
+	PushSent           int
+	PushFailed         int
 }
 
 // GetEventCustomers retrieves all enrolled customers for an event
@@ -184,13 +188,15 @@ func (s *EventNotificationService) CheckCoachHasAccessToEvent(ctx context.Contex
 	return hasAccess, nil
 }
 
-// GetEventDetails retrieves event details for including in notifications
+// GetEventDetails retrieves event details for including in notifications.
+// The returned start time is expressed in the facility's timezone.
 func (s *EventNotificationService) GetEventDetails(ctx context.Context, eventID uuid.UUID) (name string, startAt time.Time, locationName string, err *errLib.CommonError) {
 	query := `
 		SELECT
 			COALESCE(p.name, t.name, 'Event') AS event_name,
 			e.start_at,
-			COALESCE(l.name, '') AS location_name
+			COALESCE(l.name, '') AS location_name,
+			COALESCE(l.timezone, '') AS location_timezone
 		FROM events.events e
 		LEFT JOIN program.programs p ON e.program_id = p.id
 		LEFT JOIN athletic.teams t ON e.team_id = t.id
@@ -198,9 +204,9 @@ func (s *EventNotificationService) GetEventDetails(ctx context.Context, eventID
 		WHERE e.id = $1
 	`
 
-	var eventName, locName string
+	var eventName, locName, locTimezone string
 	var start time.Time
-	dbErr := s.db.QueryRowContext(ctx, query, eventID).Scan(&eventName, &start, &locName)
+	dbErr := s.db.QueryRowContext(ctx, query, eventID).Scan(&eventName, &start, &locName, &locTimezone)
 	if dbErr != nil {
 		if dbErr == sql.ErrNoRows {
 			return "", time.Time{}, "", errLib.New("Event not found", http.StatusNotFound)
@@ -208,7 +214,7 @@ func (s *EventNotificationService) GetEventDetails(ctx context.Context, eventID
 		return "", time.Time{}, "", errLib.New("Failed to get event details", http.StatusInternalServerError)
 	}
 
-	return eventName, start, locName, nil
+	return eventName, start.In(timezone.Load(locTimezone)), locName, nil
 }
 
 // SendNotification sends notifications to event attendees
@@ -267,7 +273,8 @@ func (s *EventNotificationService) SendNotification(
 
 	// Send push notifications if channel is push or both
 	if request.Channel == string(dto.ChannelPush) || request.Channel == string(dto.ChannelBoth) {
-		pushSuccess, pushFailed := s.sendPushNotifications(ctx, eventID, request.Subject, request.Message)
+		pushNotificationID, pushSuccess, pushFailed := s.sendPushNotifications(ctx, eventID, request.Subject, request.Message)
+		result.PushNotificationID = pushNotificationID
 		result.PushSent = pushSuccess
 		result.PushFailed = pushFailed
 	}
@@ -321,12 +328,16 @@ func (s *EventNotificationService) sendEmails(customers []EventCustomer, subject
 	return success, failed
 }
 
-// sendPushNotifications sends push notifications to customers enrolled in the event
-func (s *EventNotificationService) sendPushNotifications(ctx context.Context, eventID uuid.UUID, title, body string) (success int, failed int) {
+// sendPushNotifications sends push notifications to customers enrolled in the event and
+// returns the push notification their delivery is tracked under
+func (s *EventNotificationService) sendPushNotifications(ctx context.Context, eventID uuid.UUID, title, body string) (pushNotificationID *uuid.UUID, success int, failed int) {
 	// Get push tokens for enrolled customers
 	query := `
 		SELECT
-			pt.expo_push_token
+			pt.id,
+			pt.user_id,
+			pt.expo_push_token,
+			COALESCE(pt.device_type, '')
 		FROM events.customer_enrollment ce
 		JOIN notifications.push_tokens pt ON pt.user_id = ce.customer_id
 		WHERE ce.event_id = $1
@@ -337,93 +348,30 @@ func (s *EventNotificationService) sendPushNotifications(ctx context.Context, ev
 	rows, err := s.db.QueryContext(ctx, query, eventID)
 	if err != nil {
 		log.Printf("[EVENT-NOTIFICATION] Error getting push tokens: %v", err)
-		return 0, 0
+		return nil, 0, 0
 	}
 	defer rows.Close()
 
-	var tokens []string
+	var tokens []notificationValues.PushToken
 	for rows.Next() {
-		var token string
-		if err := rows.Scan(&token); err != nil {
+		var token notificationValues.PushToken
+		if err := rows.Scan(&token.ID, &token.UserID, &token.ExpoPushToken, &token.DeviceType); err != nil {
 			continue
 		}
 		tokens = append(tokens, token)
 	}
 
 	if len(tokens) == 0 {
-		return 0, 0
+		return nil, 0, 0
 	}
 
-	// Prepare Expo messages
-	type ExpoMessage struct {
-		To    string `json:"to"`
-		Title string `json:"title"`
-		Body  string `json:"body"`
-		Sound string `json:"sound"`
+	delivery, pushErr := s.push.SendPush(ctx, notificationValues.PushSourceEvent, title, body, nil, tokens)
+	if pushErr != nil {
+		log.Printf("[EVENT-NOTIFICATION] Failed to send push notifications: %v", pushErr)
+		return nil, 0, len(tokens)
 	}
 
-	messages := make([]ExpoMessage, len(tokens))
-	for i, token := range tokens {
-		messages[i] = ExpoMessage{
-			To:    token,
-			Title: title,
-			Body:  body,
-			Sound: "default",
-		}
-	}
-
-	// Send to Expo
-	jsonData, err := json.Marshal(messages)
-	if err != nil {
-		log.Printf("[EVENT-NOTIFICATION] Failed to marshal push messages: %v", err)
-		return 0, len(tokens)
-	}
-
-	client := &http.Client{Timeout: 30 * time.Second}
-	req, err := http.NewRequest("POST", "https://exp.host/--/api/v2/push/send", bytes.NewBuffer(jsonData))
-	if err != nil {
-		log.Printf("[EVENT-NOTIFICATION] Failed to create Expo request: %v", err)
-		return 0, len(tokens)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
-
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Printf("[EVENT-NOTIFICATION] Failed to send to Expo: %v", err)
-		return 0, len(tokens)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("[EVENT-NOTIFICATION] Expo returned status %d", resp.StatusCode)
-		return 0, len(tokens)
-	}
-
-	// Parse response to count successes/failures
-	type ExpoResult struct {
-		Status string `json:"status"`
-	}
-	type ExpoResponse struct {
-		Data []ExpoResult `json:"data"`
-	}
-
-	var expoResp ExpoResponse
-	if err := json.NewDecoder(resp.Body).Decode(&expoResp); err != nil {
-		log.Printf("[EVENT-NOTIFICATION] Failed to decode Expo response: %v", err)
-		return len(tokens), 0 // Assume success if we got 200
-	}
-
-	for _, result := range expoResp.Data {
-		if result.Status == "ok" {
-			success++
-		} else {
-			failed++
-		}
-	}
-
-	return success, failed
+	return &delivery.NotificationID, delivery.Accepted, delivery.Failed
 }
 
 // recordNotificationHistory saves the notification to history
@@ -438,11 +386,13 @@ func (s *EventNotificationService) recordNotificationHistory(
 	err := s.db.QueryRowContext(ctx, `
 		INSERT INTO events.notification_history (
 			event_id, sent_by, channel, subject, message, include_event_details,
-			recipient_count, email_success_count, email_failure_count, push_success_count, push_failure_count
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			recipient_count, email_success_count, email_failure_count, push_success_count, push_failure_count,
+			push_notification_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 		RETURNING id
 	`, eventID, senderID, request.Channel, request.Subject, request.Message, request.IncludeEventDetails,
-		result.RecipientCount, result.EmailSent, result.EmailFailed, result.PushSent, result.PushFailed).Scan(&id)
+		result.RecipientCount, result.EmailSent, result.EmailFailed, result.PushSent, result.PushFailed,
+		result.PushNotificationID).Scan(&id)
 
 	return id, err
 }
@@ -464,6 +414,7 @@ func (s *EventNotificationService) GetNotificationHistory(ctx context.Context, e
 			nh.email_failure_count,
 			nh.push_success_count,
 			nh.push_failure_count,
+			nh.push_notification_id,
 			nh.created_at
 		FROM events.notification_history nh
 		JOIN users.users u ON nh.sent_by = u.id
@@ -487,7 +438,7 @@ func (s *EventNotificationService) GetNotificationHistory(ctx context.Context, e
 			&h.ID, &h.EventID, &h.SentBy, &h.SentByName, &h.Channel,
 			&subject, &h.Message, &h.IncludeEventDetails, &h.RecipientCount,
 			&h.EmailSuccessCount, &h.EmailFailureCount, &h.PushSuccessCount, &h.PushFailureCount,
-			&createdAt,
+			&h.PushNotificationID, &createdAt,
 		); err != nil {
 			log.Printf("[EVENT-NOTIFICATION] Error scanning history row: %v", err)
 			continue
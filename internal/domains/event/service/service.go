@@ -10,14 +10,21 @@ import (
 	"time"
 
 	"api/internal/di"
+	auditValues "api/internal/domains/audit/audit_logs/values"
 	staffActivityLogs "api/internal/domains/audit/staff_activity_logs/service"
+	bookingService "api/internal/domains/booking/service"
+	bookingValues "api/internal/domains/booking/values"
 	dto "api/internal/domains/event/dto"
 	repo "api/internal/domains/event/persistence/repository"
+	dbEvent "api/internal/domains/event/persistence/sqlc/generated"
 	values "api/internal/domains/event/values"
+	locationRepo "api/internal/domains/location/persistence"
 	stripeService "api/internal/domains/payment/services/stripe"
 	errLib "api/internal/libs/errors"
 	contextUtils "api/utils/context"
 	txUtils "api/utils/db"
+	"api/utils/recurrence"
+	"api/utils/timezone"
 
 	"github.com/google/uuid"
 )
@@ -25,6 +32,8 @@ import (
 type Service struct {
 	eventsRepository         *repo.EventsRepository
 	recurrencesRepository    *repo.RecurrencesRepository
+	locationRepository       *locationRepo.Repository
+	bookingService           *bookingService.Service
 	staffActivityLogsService *staffActivityLogs.Service
 	productService           *stripeService.ProductService
 	notificationService      *EventNotificationService
@@ -35,6 +44,8 @@ func NewEventService(container *di.Container) *Service {
 	return &Service{
 		eventsRepository:         repo.NewEventsRepository(container),
 		recurrencesRepository:    repo.NewRecurrencesRepository(container),
+		locationRepository:       locationRepo.NewLocationRepository(container),
+		bookingService:           bookingService.NewService(container),
 		staffActivityLogsService: staffActivityLogs.NewService(container),
 		productService:           stripeService.NewProductService(),
 		notificationService:      NewEventNotificationService(container),
@@ -64,7 +75,7 @@ func (s *Service) lookupNames(ctx context.Context, programID, locationID, teamID
 
 	if locationID != uuid.Nil {
 		var name sql.NullString
-		_ = s.db.QueryRowContext(ctx, "SELECT name FROM facilities.locations WHERE id = $1", locationID).Scan(&name)
+		_ = s.db.QueryRowContext(ctx, "SELECT name FROM location.locations WHERE id = $1", locationID).Scan(&name)
 		if name.Valid {
 			locationName = name.String
 		}
@@ -83,10 +94,7 @@ func (s *Service) lookupNames(ctx context.Context, programID, locationID, teamID
 
 // formatEventDescription creates a human-readable activity description for an event
 func (s *Service) formatEventDescription(ctx context.Context, action string, details values.EventDetails) string {
-	loc, _ := time.LoadLocation("America/Edmonton")
-	if loc == nil {
-		loc = time.FixedZone("MST", -7*60*60)
-	}
+	loc := timezone.ForLocation(ctx, s.db, details.LocationID)
 
 	programName, locationName, teamName := s.lookupNames(ctx, details.ProgramID, details.LocationID, details.TeamID)
 
@@ -163,11 +171,16 @@ func (s *Service) CreateEvents(ctx context.Context, details values.CreateRecurre
 		log.Printf("[STRIPE] Created Stripe product %s with price %s for recurring event", productID, priceID)
 	}
 
+	loc := timezone.ForLocation(ctx, s.db, details.LocationID)
+
+	blackouts, err := s.getBlackouts(ctx, details.LocationID, details.FirstOccurrence, details.LastOccurrence)
+	if err != nil {
+		return nil, err
+	}
+
 	events, err := generateEventsFromRecurrence(
-		details.FirstOccurrence,
-		details.LastOccurrence,
-		details.StartTime,
-		details.EndTime,
+		details.BaseRecurrenceValues,
+		blackouts,
 		details.CreatedBy,
 		details.ProgramID,
 		details.LocationID,
@@ -175,26 +188,53 @@ func (s *Service) CreateEvents(ctx context.Context, details values.CreateRecurre
 		details.TeamID,
 		details.RequiredMembershipPlanIDs,
 		details.PriceID,
-		details.DayOfWeek,
 		details.CreditCost,
 		details.RegistrationRequired,
+		loc,
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	if len(events) == 0 {
+		return nil, errLib.New("The recurrence does not produce any occurrences", http.StatusBadRequest)
+	}
+
+	rule, err := resolveRule(details.BaseRecurrenceValues)
+	if err != nil {
+		return nil, err
+	}
+
+	recurrenceID := uuid.New()
+	for i := range events {
+		events[i].RecurrenceID = recurrenceID
+	}
+
 	txErr := s.executeInTx(ctx, func(txRepo *repo.EventsRepository) *errLib.CommonError {
+		if err = s.reserveCourts(ctx, txRepo, events); err != nil {
+			return err
+		}
+
 		// Create events
 		if err = txRepo.CreateEvents(ctx, events); err != nil {
 			return err
 		}
 
+		if err = txRepo.SaveRecurrenceRule(ctx, values.RecurrenceRule{
+			ID:      recurrenceID,
+			RRule:   rule.String(),
+			DTStart: recurrence.Date(details.FirstOccurrence),
+			ExDates: details.ExDates,
+		}); err != nil {
+			return err
+		}
+
 		return s.staffActivityLogsService.InsertStaffActivity(
 			ctx,
 			txRepo.GetTx(),
 			details.CreatedBy,
 			s.formatEventDescription(ctx, "Created recurring", values.EventDetails{
-				StartAt:    details.FirstOccurrence,
+				StartAt:    events[0].StartAt,
 				ProgramID:  details.ProgramID,
 				LocationID: details.LocationID,
 				TeamID:     details.TeamID,
@@ -211,8 +251,8 @@ func (s *Service) CreateEvents(ctx context.Context, details values.CreateRecurre
 		CreatedBy:  details.CreatedBy,
 		ProgramID:  details.ProgramID,
 		LocationID: details.LocationID,
-		After:      details.FirstOccurrence,
-		Before:     details.LastOccurrence.AddDate(0, 0, 1), // Add 1 day to include last occurrence
+		After:      events[0].StartAt,
+		Before:     events[len(events)-1].EndAt,
 	})
 	if fetchErr != nil {
 		return nil, fetchErr
@@ -256,6 +296,10 @@ func (s *Service) CreateEvent(ctx context.Context, details values.CreateEventVal
 	}
 
 	err := s.executeInTx(ctx, func(txRepo *repo.EventsRepository) *errLib.CommonError {
+		if err := s.bookingService.Reserve(ctx, txRepo.GetTx(), courtBooking(uuid.Nil, details.EventDetails)); err != nil {
+			return err
+		}
+
 		// Create the event
 		if err := txRepo.CreateEvents(ctx, []values.CreateEventValues{details}); err != nil {
 			return err
@@ -301,15 +345,18 @@ func (s *Service) CreateEvent(ctx context.Context, details values.CreateEventVal
 			return err
 		}
 
+		staffID := uuid.Nil
 		if isStaff {
-			if err := s.staffActivityLogsService.InsertStaffActivity(
-				ctx,
-				txRepo.GetTx(),
-				details.CreatedBy,
-				s.formatEventDescription(ctx, "Created", details.EventDetails),
-			); err != nil {
-				return err
-			}
+			staffID = details.CreatedBy
+		}
+		if err := s.staffActivityLogsService.RecordChange(ctx, txRepo.GetTx(), staffID, auditValues.Entry{
+			EntityType:  "event",
+			EntityID:    createdEvent.ID.String(),
+			Action:      auditValues.ActionCreate,
+			After:       eventAuditStateFromDetails(details.EventDetails),
+			Description: s.formatEventDescription(ctx, "Created", details.EventDetails),
+		}); err != nil {
+			return err
 		}
 
 		// Capture the created event data to return
@@ -334,6 +381,15 @@ func (s *Service) UpdateEvent(ctx context.Context, details values.UpdateEventVal
 
 	// Perform the actual update
 	updateErr := s.executeInTx(ctx, func(txRepo *repo.EventsRepository) *errLib.CommonError {
+		before, err := txRepo.GetEvent(ctx, details.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.bookingService.Reserve(ctx, txRepo.GetTx(), courtBooking(details.ID, details.EventDetails)); err != nil {
+			return err
+		}
+
 		if err := txRepo.UpdateEvent(ctx, details); err != nil {
 			return err
 		}
@@ -348,15 +404,18 @@ func (s *Service) UpdateEvent(ctx context.Context, details values.UpdateEventVal
 			return err
 		}
 
+		staffID := uuid.Nil
 		if isStaff {
-			return s.staffActivityLogsService.InsertStaffActivity(
-				ctx,
-				txRepo.GetTx(),
-				details.UpdatedBy,
-				s.formatEventDescription(ctx, "Updated", details.EventDetails),
-			)
+			staffID = details.UpdatedBy
 		}
-		return nil
+		return s.staffActivityLogsService.RecordChange(ctx, txRepo.GetTx(), staffID, auditValues.Entry{
+			EntityType:  "event",
+			EntityID:    details.ID.String(),
+			Action:      auditValues.ActionUpdate,
+			Before:      eventAuditStateOf(before),
+			After:       eventAuditStateFromDetails(details.EventDetails),
+			Description: s.formatEventDescription(ctx, "Updated", details.EventDetails),
+		})
 	})
 
 	if updateErr != nil {
@@ -411,16 +470,48 @@ func (s *Service) UpdateRecurringEvents(ctx context.Context, details values.Upda
 		log.Printf("[STRIPE] Created Stripe product %s with price %s for updated recurring event", productID, priceID)
 	}
 
+	switch details.Scope {
+	case values.EditScopeOccurrence:
+		return s.updateRecurrenceOccurrence(ctx, details)
+	case values.EditScopeFollowing:
+		return s.updateFollowingOccurrences(ctx, details)
+	default:
+		return s.updateAllOccurrences(ctx, details)
+	}
+}
+
+// updateAllOccurrences regenerates a whole series from the new rule. Occurrences that
+// were edited individually, have active enrollments, or are in the past are kept, and
+// no new occurrence is generated on a date that still has one.
+func (s *Service) updateAllOccurrences(ctx context.Context, details values.UpdateRecurrenceValues) *errLib.CommonError {
+	blackouts, err := s.getBlackouts(ctx, details.LocationID, details.FirstOccurrence, details.LastOccurrence)
+	if err != nil {
+		return err
+	}
+
+	rule, err := resolveRule(details.BaseRecurrenceValues)
+	if err != nil {
+		return err
+	}
+
 	return s.executeInTx(ctx, func(txRepo *repo.EventsRepository) *errLib.CommonError {
-		if err := txRepo.DeleteUnmodifiedEventsByRecurrenceID(ctx, details.ID); err != nil {
+		loc := timezone.ForLocation(ctx, txRepo.GetTx(), details.LocationID)
+
+		stored, found, err := txRepo.GetRecurrenceRule(ctx, details.ID)
+		if err != nil {
+			return err
+		}
+		if found {
+			details.ExDates = mergeDates(stored.ExDates, details.ExDates)
+		}
+
+		if err = txRepo.DeleteUnmodifiedEventsByRecurrenceID(ctx, details.ID); err != nil {
 			return err
 		}
 
-		eventsToCreate, err := generateEventsFromRecurrence(
-			details.FirstOccurrence,
-			details.LastOccurrence,
-			details.StartTime,
-			details.EndTime,
+		generated, err := generateEventsFromRecurrence(
+			details.BaseRecurrenceValues,
+			blackouts,
 			details.UpdatedBy,
 			details.ProgramID,
 			details.LocationID,
@@ -428,15 +519,38 @@ func (s *Service) UpdateRecurringEvents(ctx context.Context, details values.Upda
 			details.TeamID,
 			details.RequiredMembershipPlanIDs,
 			details.PriceID,
-			details.DayOfWeek,
 			details.CreditCost,
 			details.RegistrationRequired,
+			loc,
 		)
 		if err != nil {
 			return err
 		}
 
-		if err = txRepo.CreateEvents(ctx, eventsToCreate); err != nil {
+		eventsToCreate, err := s.withoutExistingOccurrences(ctx, txRepo, details.ID, generated, time.Time{}, loc)
+		if err != nil {
+			return err
+		}
+
+		for i := range eventsToCreate {
+			eventsToCreate[i].RecurrenceID = details.ID
+		}
+
+		if len(eventsToCreate) > 0 {
+			if err = s.reserveCourts(ctx, txRepo, eventsToCreate); err != nil {
+				return err
+			}
+			if err = txRepo.CreateEvents(ctx, eventsToCreate); err != nil {
+				return err
+			}
+		}
+
+		if err = txRepo.SaveRecurrenceRule(ctx, values.RecurrenceRule{
+			ID:      details.ID,
+			RRule:   rule.String(),
+			DTStart: recurrence.Date(details.FirstOccurrence),
+			ExDates: details.ExDates,
+		}); err != nil {
 			return err
 		}
 
@@ -454,26 +568,374 @@ func (s *Service) UpdateRecurringEvents(ctx context.Context, details values.Upda
 	})
 }
 
+// updateFollowingOccurrences splits a series at details.OccurrenceDate: the original
+// series ends the day before, and the occurrences from that date on are replaced by a
+// new series built from the request.
+func (s *Service) updateFollowingOccurrences(ctx context.Context, details values.UpdateRecurrenceValues) *errLib.CommonError {
+	splitDate := recurrence.Date(details.OccurrenceDate)
+
+	blackouts, err := s.getBlackouts(ctx, details.LocationID, splitDate, details.LastOccurrence)
+	if err != nil {
+		return err
+	}
+
+	rule, err := resolveRule(details.BaseRecurrenceValues)
+	if err != nil {
+		return err
+	}
+
+	return s.executeInTx(ctx, func(txRepo *repo.EventsRepository) *errLib.CommonError {
+		loc := timezone.ForLocation(ctx, txRepo.GetTx(), details.LocationID)
+
+		if err := s.truncateRecurrence(ctx, txRepo, details.ID, splitDate, loc); err != nil {
+			return err
+		}
+
+		generated, err := generateEventsFromRecurrence(
+			details.BaseRecurrenceValues,
+			blackouts,
+			details.UpdatedBy,
+			details.ProgramID,
+			details.LocationID,
+			details.CourtID,
+			details.TeamID,
+			details.RequiredMembershipPlanIDs,
+			details.PriceID,
+			details.CreditCost,
+			details.RegistrationRequired,
+			loc,
+		)
+		if err != nil {
+			return err
+		}
+
+		eventsToCreate, err := s.withoutExistingOccurrences(ctx, txRepo, details.ID, generated, splitDate, loc)
+		if err != nil {
+			return err
+		}
+
+		if len(eventsToCreate) == 0 {
+			return errLib.New("The new rule does not produce any occurrences on or after the given date", http.StatusBadRequest)
+		}
+
+		newRecurrenceID := uuid.New()
+		for i := range eventsToCreate {
+			eventsToCreate[i].RecurrenceID = newRecurrenceID
+		}
+
+		if err = s.reserveCourts(ctx, txRepo, eventsToCreate); err != nil {
+			return err
+		}
+		if err = txRepo.CreateEvents(ctx, eventsToCreate); err != nil {
+			return err
+		}
+
+		if err = txRepo.SaveRecurrenceRule(ctx, values.RecurrenceRule{
+			ID:      newRecurrenceID,
+			RRule:   rule.String(),
+			DTStart: recurrence.Date(eventsToCreate[0].StartAt.In(loc)),
+			ExDates: details.ExDates,
+		}); err != nil {
+			return err
+		}
+
+		return s.staffActivityLogsService.InsertStaffActivity(
+			ctx,
+			txRepo.GetTx(),
+			details.UpdatedBy,
+			s.formatEventDescription(ctx, "Split recurring", values.EventDetails{
+				StartAt:    eventsToCreate[0].StartAt,
+				ProgramID:  details.ProgramID,
+				LocationID: details.LocationID,
+				TeamID:     details.TeamID,
+			})+fmt.Sprintf(" (series %s continues as %s)", details.ID, newRecurrenceID),
+		)
+	})
+}
+
+// updateRecurrenceOccurrence applies the request's times and details to the single
+// occurrence on details.OccurrenceDate. The occurrence is marked as modified, so later
+// whole-series edits leave it alone.
+func (s *Service) updateRecurrenceOccurrence(ctx context.Context, details values.UpdateRecurrenceValues) *errLib.CommonError {
+	loc := timezone.ForLocation(ctx, s.db, details.LocationID)
+
+	occurrence, err := s.findOccurrence(ctx, s.eventsRepository, details.ID, details.OccurrenceDate, loc)
+	if err != nil {
+		return err
+	}
+
+	startHour, startMinute, _, startDays, parseErr := timezone.ParseWallClock(details.StartTime, details.OccurrenceDate, loc)
+	if parseErr != nil {
+		return errLib.New("Invalid start time format - must be HH:MM:SS with an optional UTC offset (e.g. 09:00:00 or 15:00:00+00:00)", http.StatusBadRequest)
+	}
+	endHour, endMinute, _, _, parseErr := timezone.ParseWallClock(details.EndTime, details.OccurrenceDate, loc)
+	if parseErr != nil {
+		return errLib.New("Invalid end time format - must be HH:MM:SS with an optional UTC offset (e.g. 17:00:00 or 23:00:00+00:00)", http.StatusBadRequest)
+	}
+
+	startAt, endAt := occurrenceBounds(details.OccurrenceDate.AddDate(0, 0, startDays), startHour, startMinute, endHour, endMinute, loc)
+
+	return s.UpdateEvent(ctx, values.UpdateEventValues{
+		ID:        occurrence.ID,
+		UpdatedBy: details.UpdatedBy,
+		EventDetails: values.EventDetails{
+			StartAt:                   startAt,
+			EndAt:                     endAt,
+			ProgramID:                 details.ProgramID,
+			LocationID:                details.LocationID,
+			CourtID:                   details.CourtID,
+			TeamID:                    details.TeamID,
+			RequiredMembershipPlanIDs: details.RequiredMembershipPlanIDs,
+			PriceID:                   details.PriceID,
+			CreditCost:                details.CreditCost,
+			RegistrationRequired:      details.RegistrationRequired,
+		},
+	})
+}
+
+// DeleteRecurrence deletes occurrences of a series according to the requested scope.
+// Deleting a single occurrence records it as an exception (EXDATE) on the stored rule.
+func (s *Service) DeleteRecurrence(ctx context.Context, details values.DeleteRecurrenceValues) *errLib.CommonError {
+	switch details.Scope {
+	case values.EditScopeOccurrence, values.EditScopeFollowing:
+	default:
+		return s.DeleteUnmodifiedEventsByRecurrenceID(ctx, details.DeletedBy, details.ID)
+	}
+
+	date := recurrence.Date(details.OccurrenceDate)
+
+	return s.executeInTx(ctx, func(txRepo *repo.EventsRepository) *errLib.CommonError {
+		rows, err := txRepo.GetRecurrenceEvents(ctx, details.ID)
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return errLib.New("Recurrence not found", http.StatusNotFound)
+		}
+
+		loc := timezone.ForLocation(ctx, txRepo.GetTx(), s.recurrenceLocation(ctx, txRepo, rows[0].ID))
+
+		var desc string
+		if details.Scope == values.EditScopeFollowing {
+			if err = s.truncateRecurrence(ctx, txRepo, details.ID, date, loc); err != nil {
+				return err
+			}
+			desc = fmt.Sprintf("Deleted occurrences of recurring series %s from %s", details.ID, date.Format("Jan 2, 2006"))
+		} else {
+			occurrence, err := s.findOccurrence(ctx, txRepo, details.ID, date, loc)
+			if err != nil {
+				return err
+			}
+			if err = txRepo.DeleteRecurrenceOccurrence(ctx, details.ID, occurrence.ID); err != nil {
+				return err
+			}
+			if err = s.addExDate(ctx, txRepo, details.ID, date, rows, loc); err != nil {
+				return err
+			}
+			desc = fmt.Sprintf("Deleted the %s occurrence of recurring series %s", date.Format("Jan 2, 2006"), details.ID)
+		}
+
+		return s.staffActivityLogsService.InsertStaffActivity(ctx, txRepo.GetTx(), details.DeletedBy, desc)
+	})
+}
+
+// truncateRecurrence ends a series the day before date: its stored rule gets an UNTIL,
+// and unmodified occurrences from date on without active enrollments are deleted.
+func (s *Service) truncateRecurrence(ctx context.Context, txRepo *repo.EventsRepository, recurrenceID uuid.UUID, date time.Time, loc *time.Location) *errLib.CommonError {
+	stored, found, err := txRepo.GetRecurrenceRule(ctx, recurrenceID)
+	if err != nil {
+		return err
+	}
+
+	if found {
+		rule, parseErr := recurrence.Parse(stored.RRule)
+		if parseErr != nil {
+			log.Printf("[RECURRENCE] Stored rule %q of %s is invalid: %v", stored.RRule, recurrenceID, parseErr)
+			return errLib.New("Internal server error", http.StatusInternalServerError)
+		}
+		stored.RRule = rule.EndingBefore(stored.DTStart, date).String()
+		if err = txRepo.SaveRecurrenceRule(ctx, stored); err != nil {
+			return err
+		}
+	}
+
+	return txRepo.DeleteUnmodifiedEventsByRecurrenceIDFrom(ctx, recurrenceID, timezone.At(date, 0, 0, 0, loc))
+}
+
+// addExDate records date as an exception on the series' stored rule. Series created
+// before rules were stored get one derived from their occurrences, so the deleted
+// date is not regenerated by a later edit of the whole series.
+func (s *Service) addExDate(ctx context.Context, txRepo *repo.EventsRepository, recurrenceID uuid.UUID, date time.Time, occurrences []dbEvent.GetRecurrenceEventsRow, loc *time.Location) *errLib.CommonError {
+	stored, found, err := txRepo.GetRecurrenceRule(ctx, recurrenceID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		stored = legacyRecurrenceRule(recurrenceID, occurrences, loc)
+	}
+
+	stored.ExDates = mergeDates(stored.ExDates, []time.Time{date})
+	return txRepo.SaveRecurrenceRule(ctx, stored)
+}
+
+// legacyRecurrenceRule rebuilds the weekly rule of a series created before rules were
+// stored from its occurrences, ordered by start time. Individually edited occurrences
+// are skipped when picking the weekday since they may have been moved.
+func legacyRecurrenceRule(recurrenceID uuid.UUID, occurrences []dbEvent.GetRecurrenceEventsRow, loc *time.Location) values.RecurrenceRule {
+	first := occurrences[0]
+	for _, occurrence := range occurrences {
+		if !occurrence.IsDateTimeModified {
+			first = occurrence
+			break
+		}
+	}
+
+	rule := recurrence.WeeklyOn(first.StartAt.In(loc).Weekday())
+	rule.Until = recurrence.Date(occurrences[len(occurrences)-1].StartAt.In(loc))
+
+	return values.RecurrenceRule{
+		ID:      recurrenceID,
+		RRule:   rule.String(),
+		DTStart: recurrence.Date(occurrences[0].StartAt.In(loc)),
+	}
+}
+
+// findOccurrence returns the occurrence of a series whose start falls on date in loc.
+func (s *Service) findOccurrence(ctx context.Context, eventsRepo *repo.EventsRepository, recurrenceID uuid.UUID, date time.Time, loc *time.Location) (values.ReadEventValues, *errLib.CommonError) {
+	rows, err := eventsRepo.GetRecurrenceEvents(ctx, recurrenceID)
+	if err != nil {
+		return values.ReadEventValues{}, err
+	}
+
+	target := recurrence.Date(date)
+	for _, row := range rows {
+		if recurrence.Date(row.StartAt.In(loc)).Equal(target) {
+			return values.ReadEventValues{ID: row.ID, StartAt: row.StartAt, EndAt: row.EndAt}, nil
+		}
+	}
+
+	return values.ReadEventValues{}, errLib.New(fmt.Sprintf("No occurrence of this recurrence on %s", target.Format("2006-01-02")), http.StatusNotFound)
+}
+
+func (s *Service) recurrenceLocation(ctx context.Context, txRepo *repo.EventsRepository, eventID uuid.UUID) uuid.UUID {
+	var locationID uuid.UUID
+	if err := txRepo.GetTx().QueryRowContext(ctx, "SELECT location_id FROM events.events WHERE id = $1", eventID).Scan(&locationID); err != nil {
+		log.Printf("[RECURRENCE] Failed to look up location of event %s: %v", eventID, err)
+	}
+	return locationID
+}
+
+// withoutExistingOccurrences drops generated events that start in the past, before
+// from, or on a date where the series still has an occurrence (edited, enrolled or
+// past occurrences survive a regeneration).
+func (s *Service) withoutExistingOccurrences(ctx context.Context, txRepo *repo.EventsRepository, recurrenceID uuid.UUID, generated []values.CreateEventValues, from time.Time, loc *time.Location) ([]values.CreateEventValues, *errLib.CommonError) {
+	rows, err := txRepo.GetRecurrenceEvents(ctx, recurrenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	taken := make(map[time.Time]bool, len(rows))
+	for _, row := range rows {
+		taken[recurrence.Date(row.StartAt.In(loc))] = true
+	}
+
+	now := time.Now()
+	kept := make([]values.CreateEventValues, 0, len(generated))
+	for _, event := range generated {
+		date := recurrence.Date(event.StartAt.In(loc))
+		if event.StartAt.Before(now) || taken[date] || (!from.IsZero() && date.Before(from)) {
+			continue
+		}
+		kept = append(kept, event)
+	}
+
+	return kept, nil
+}
+
+// reserveCourts checks every generated occurrence's court before the series is written.
+// Overrides are not offered for whole series; conflicting occurrences can be excluded
+// with exdates or booked over one at a time.
+func (s *Service) reserveCourts(ctx context.Context, txRepo *repo.EventsRepository, events []values.CreateEventValues) *errLib.CommonError {
+	for _, event := range events {
+		details := event.EventDetails
+		details.OverrideReason = ""
+		if err := s.bookingService.Reserve(ctx, txRepo.GetTx(), courtBooking(uuid.Nil, details)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func courtBooking(eventID uuid.UUID, details values.EventDetails) bookingValues.Request {
+	return bookingValues.Request{
+		ResourceType:   bookingValues.ResourceCourt,
+		ResourceID:     details.CourtID,
+		ActivityType:   bookingValues.ActivityEvent,
+		ActivityID:     eventID,
+		StartAt:        details.StartAt,
+		EndAt:          details.EndAt,
+		OverrideReason: details.OverrideReason,
+	}
+}
+
+// getBlackouts loads the location's blackout dates overlapping the recurrence bounds.
+// A zero last date loads every blackout from first on.
+func (s *Service) getBlackouts(ctx context.Context, locationID uuid.UUID, first, last time.Time) ([]recurrence.Blackout, *errLib.CommonError) {
+	if locationID == uuid.Nil {
+		return nil, nil
+	}
+
+	to := time.Time{}
+	if !last.IsZero() {
+		to = recurrence.Date(last)
+	}
+
+	dates, err := s.locationRepository.GetBlackoutDates(ctx, locationID, recurrence.Date(first), to)
+	if err != nil {
+		return nil, err
+	}
+
+	blackouts := make([]recurrence.Blackout, len(dates))
+	for i, date := range dates {
+		blackouts[i] = date.ToRecurrence()
+	}
+	return blackouts, nil
+}
+
+func mergeDates(a, b []time.Time) []time.Time {
+	seen := make(map[time.Time]bool, len(a)+len(b))
+	merged := make([]time.Time, 0, len(a)+len(b))
+	for _, d := range append(append([]time.Time{}, a...), b...) {
+		d = recurrence.Date(d)
+		if !seen[d] {
+			seen[d] = true
+			merged = append(merged, d)
+		}
+	}
+	return merged
+}
+
 func (s *Service) DeleteUnmodifiedEventsByRecurrenceID(ctx context.Context, staffId, id uuid.UUID) *errLib.CommonError {
 	// Lookup recurrence details for audit log
 	var programName, locationName string
 	var firstOccurrence, lastOccurrence time.Time
 
 	row := s.db.QueryRowContext(ctx, `
-		SELECT p.name, l.name, r.first_occurrence, r.last_occurrence
+		SELECT p.name, l.name, l.timezone, r.first_occurrence, r.last_occurrence
 		FROM events.recurrences r
 		LEFT JOIN program.programs p ON r.program_id = p.id
-		LEFT JOIN facilities.locations l ON r.location_id = l.id
+		LEFT JOIN location.locations l ON r.location_id = l.id
 		WHERE r.id = $1
 	`, id)
-	var pName, lName sql.NullString
-	_ = row.Scan(&pName, &lName, &firstOccurrence, &lastOccurrence)
+	var pName, lName, lTimezone sql.NullString
+	_ = row.Scan(&pName, &lName, &lTimezone, &firstOccurrence, &lastOccurrence)
 	if pName.Valid {
 		programName = pName.String
 	}
 	if lName.Valid {
 		locationName = lName.String
 	}
+	loc := timezone.Load(lTimezone.String)
 
 	return s.executeInTx(ctx, func(txRepo *repo.EventsRepository) *errLib.CommonError {
 		if err := txRepo.DeleteUnmodifiedEventsByRecurrenceID(ctx, id); err != nil {
@@ -481,11 +943,6 @@ func (s *Service) DeleteUnmodifiedEventsByRecurrenceID(ctx context.Context, staf
 		}
 
 		// Build description
-		loc, _ := time.LoadLocation("America/Edmonton")
-		if loc == nil {
-			loc = time.FixedZone("MST", -7*60*60)
-		}
-
 		desc := "Deleted recurring events"
 		if programName != "" {
 			desc += fmt.Sprintf(" for '%s'", programName)
@@ -535,10 +992,7 @@ func (s *Service) DeleteEvents(ctx context.Context, ids []uuid.UUID) *errLib.Com
 				desc = event.Location.Name
 			}
 		}
-		loc, _ := time.LoadLocation("America/Edmonton")
-		if loc == nil {
-			loc = time.FixedZone("MST", -7*60*60)
-		}
+		loc := timezone.ForLocation(ctx, s.db, event.Location.ID)
 		desc += " on " + event.StartAt.In(loc).Format("Jan 2, 2006 at 3:04 PM")
 		descriptions = append(descriptions, desc)
 	}
@@ -584,50 +1038,80 @@ func (s *Service) GetEventsRecurrences(ctx context.Context, filter values.GetEve
 		filter.ParticipantID, filter.TeamID, filter.CreatedBy, filter.UpdatedBy, filter.Before, filter.After)
 }
 
+// resolveRule returns the RFC 5545 rule of a recurrence. Requests without an RRule
+// repeat weekly on DayOfWeek. An explicit end date is folded into the rule as UNTIL so
+// the stored rule describes the whole series.
+func resolveRule(rec values.BaseRecurrenceValues) (recurrence.Rule, *errLib.CommonError) {
+	rule := recurrence.WeeklyOn(rec.DayOfWeek)
+	if rec.RRule != "" {
+		parsed, err := recurrence.Parse(rec.RRule)
+		if err != nil {
+			return recurrence.Rule{}, errLib.New(fmt.Sprintf("Invalid rrule: %s", err.Error()), http.StatusBadRequest)
+		}
+		rule = parsed
+	}
+
+	if !rec.LastOccurrence.IsZero() && rule.Count == 0 {
+		last := recurrence.Date(rec.LastOccurrence)
+		if rule.Until.IsZero() || last.Before(rule.Until) {
+			rule.Until = last
+		}
+	}
+
+	return rule, nil
+}
+
+// generateEventsFromRecurrence expands a recurrence into individual events.
+//
+// Start and end times are wall-clock times at the facility, so every occurrence is
+// built in loc: an 18:00 practice stays at 18:00 local time on both sides of a DST
+// change instead of drifting by an hour. The recurrence bounds are treated as
+// calendar dates. Occurrences on a skip blackout are dropped; those on a flag
+// blackout are kept with BlackoutID set.
 func generateEventsFromRecurrence(
-	firstOccurrence, lastOccurrence time.Time,
-	startTimeStr, endTimeStr string,
+	rec values.BaseRecurrenceValues,
+	blackouts []recurrence.Blackout,
 	mutater, programID, locationID, courtID, teamID uuid.UUID,
 	membershipPlanIDs []uuid.UUID,
 	priceID string,
-	day time.Weekday,
 	creditCost *int32,
 	registrationRequired bool,
+	loc *time.Location,
 ) ([]values.CreateEventValues, *errLib.CommonError) {
-	const timeLayout = "15:04:05Z07:00"
-
-	if firstOccurrence.After(lastOccurrence) {
+	if !rec.LastOccurrence.IsZero() && rec.FirstOccurrence.After(rec.LastOccurrence) {
 		return nil, errLib.New("Recurrence start date must be before the end date", http.StatusBadRequest)
 	}
 
-	startTime, err := time.Parse(timeLayout, startTimeStr)
+	startHour, startMinute, _, startDays, err := timezone.ParseWallClock(rec.StartTime, rec.FirstOccurrence, loc)
 	if err != nil {
-		return nil, errLib.New("Invalid start time format - must be HH:MM:SS±HH:MM (e.g. 09:00:00+00:00)", http.StatusBadRequest)
+		return nil, errLib.New("Invalid start time format - must be HH:MM:SS with an optional UTC offset (e.g. 09:00:00 or 15:00:00+00:00)", http.StatusBadRequest)
 	}
 
-	endTime, err := time.Parse(timeLayout, endTimeStr)
+	endHour, endMinute, _, _, err := timezone.ParseWallClock(rec.EndTime, rec.FirstOccurrence, loc)
 	if err != nil {
-		return nil, errLib.New("Invalid end time format - must be HH:MM:SS±HH:MM (e.g. 17:00:00+00:00)", http.StatusBadRequest)
+		return nil, errLib.New("Invalid end time format - must be HH:MM:SS with an optional UTC offset (e.g. 17:00:00 or 23:00:00+00:00)", http.StatusBadRequest)
 	}
 
-	// If the end time is before start time (crosses midnight), add a day to end time
-	if endTime.Before(startTime) {
-		endTime = endTime.AddDate(0, 0, 1)
+	rule, ruleErr := resolveRule(rec)
+	if ruleErr != nil {
+		return nil, ruleErr
 	}
 
-	adjustTime := func(date time.Time, t time.Time) time.Time {
-		return time.Date(date.Year(), date.Month(), date.Day(), t.Hour(), t.Minute(), 0, 0, date.Location())
+	dates, err := rule.Expand(rec.FirstOccurrence, rec.LastOccurrence, rec.ExDates)
+	if err != nil {
+		return nil, errLib.New(fmt.Sprintf("Invalid recurrence: %s", err.Error()), http.StatusBadRequest)
+	}
+	// Times given with an offset can fall on the day before or after the rule's dates
+	for i := range dates {
+		dates[i] = dates[i].AddDate(0, 0, startDays)
 	}
 
 	var events []values.CreateEventValues
-	addEvent := func(date time.Time) {
-		start := adjustTime(date, startTime)
-		end := adjustTime(date, endTime)
-		if end.Before(start) {
-			end = end.AddDate(0, 0, 1)
-		}
+	for _, occurrence := range recurrence.ApplyBlackouts(dates, blackouts) {
+		start, end := occurrenceBounds(occurrence.Date, startHour, startMinute, endHour, endMinute, loc)
 		events = append(events, values.CreateEventValues{
-			CreatedBy: mutater,
+			CreatedBy:  mutater,
+			BlackoutID: occurrence.BlackoutID,
 			EventDetails: values.EventDetails{
 				StartAt:                   start,
 				EndAt:                     end,
@@ -643,16 +1127,21 @@ func generateEventsFromRecurrence(
 		})
 	}
 
-	for date := firstOccurrence; !date.After(lastOccurrence); date = date.AddDate(0, 0, 1) {
-		if date.Weekday() == day {
-			addEvent(date)
-			date = date.AddDate(0, 0, 6) // Skip to next week
-		}
-	}
-
 	return events, nil
 }
 
+// occurrenceBounds returns the start and end instants of an occurrence on date. If the
+// end time is not after the start time the occurrence crosses midnight and ends the
+// next day.
+func occurrenceBounds(date time.Time, startHour, startMinute, endHour, endMinute int, loc *time.Location) (time.Time, time.Time) {
+	start := timezone.At(date, startHour, startMinute, 0, loc)
+	end := timezone.At(date, endHour, endMinute, 0, loc)
+	if !end.After(start) {
+		end = timezone.At(date.AddDate(0, 0, 1), endHour, endMinute, 0, loc)
+	}
+	return start, end
+}
+
 // detectChanges compares an existing event with new update values to identify changes
 func (s *Service) detectChanges(ctx context.Context, existing values.ReadEventValues, new values.UpdateEventValues) values.EventChanges {
 	changes := values.DetectEventChanges(existing, new)
@@ -667,11 +1156,8 @@ func (s *Service) detectChanges(ctx context.Context, existing values.ReadEventVa
 }
 
 // buildChangeMessage creates a human-readable message describing what changed
-func (s *Service) buildChangeMessage(existing values.ReadEventValues, changes values.EventChanges) string {
-	loc, _ := time.LoadLocation("America/Edmonton")
-	if loc == nil {
-		loc = time.FixedZone("MST", -7*60*60)
-	}
+func (s *Service) buildChangeMessage(ctx context.Context, existing values.ReadEventValues, changes values.EventChanges) string {
+	loc := timezone.ForLocation(ctx, s.db, existing.Location.ID)
 
 	var parts []string
 
@@ -691,7 +1177,7 @@ func (s *Service) buildChangeMessage(existing values.ReadEventValues, changes va
 
 // notifyEnrolledUsersOfChanges sends notifications to all enrolled users about event changes
 func (s *Service) notifyEnrolledUsersOfChanges(ctx context.Context, eventID uuid.UUID, existing values.ReadEventValues, changes values.EventChanges) {
-	message := s.buildChangeMessage(existing, changes)
+	message := s.buildChangeMessage(ctx, existing, changes)
 	if message == "" {
 		return
 	}
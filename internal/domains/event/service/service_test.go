@@ -5,7 +5,9 @@ import (
 	"testing"
 	"time"
 
+	dbEvent "api/internal/domains/event/persistence/sqlc/generated"
 	values "api/internal/domains/event/values"
+	"api/utils/recurrence"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -29,10 +31,8 @@ func TestGenerateEventsFromRecurrence(t *testing.T) {
 		}
 
 		events, err := generateEventsFromRecurrence(
-			recurrence.FirstOccurrence,
-			recurrence.LastOccurrence,
-			recurrence.StartTime,
-			recurrence.EndTime,
+			recurrence.BaseRecurrenceValues,
+			nil,
 			recurrence.CreatedBy,
 			recurrence.ProgramID,
 			recurrence.LocationID,
@@ -40,9 +40,9 @@ func TestGenerateEventsFromRecurrence(t *testing.T) {
 			recurrence.TeamID,
 			recurrence.RequiredMembershipPlanIDs,
 			recurrence.PriceID,
-			recurrence.DayOfWeek,
 			nil,
 			true,
+			time.UTC,
 		)
 
 		assert.Nil(t, err)
@@ -76,10 +76,8 @@ func TestGenerateEventsFromRecurrence(t *testing.T) {
 		}
 
 		events, err := generateEventsFromRecurrence(
-			recurrence.FirstOccurrence,
-			recurrence.LastOccurrence,
-			recurrence.StartTime,
-			recurrence.EndTime,
+			recurrence.BaseRecurrenceValues,
+			nil,
 			recurrence.CreatedBy,
 			recurrence.ProgramID,
 			recurrence.LocationID,
@@ -87,9 +85,9 @@ func TestGenerateEventsFromRecurrence(t *testing.T) {
 			recurrence.TeamID,
 			recurrence.RequiredMembershipPlanIDs,
 			recurrence.PriceID,
-			recurrence.DayOfWeek,
 			nil,
 			true,
+			time.UTC,
 		)
 
 		assert.Nil(t, events)
@@ -116,10 +114,8 @@ func TestGenerateEventsFromRecurrence(t *testing.T) {
 		}
 
 		events, err := generateEventsFromRecurrence(
-			recurrence.FirstOccurrence,
-			recurrence.LastOccurrence,
-			recurrence.StartTime,
-			recurrence.EndTime,
+			recurrence.BaseRecurrenceValues,
+			nil,
 			recurrence.CreatedBy,
 			recurrence.ProgramID,
 			recurrence.LocationID,
@@ -127,15 +123,15 @@ func TestGenerateEventsFromRecurrence(t *testing.T) {
 			recurrence.TeamID,
 			recurrence.RequiredMembershipPlanIDs,
 			recurrence.PriceID,
-			recurrence.DayOfWeek,
 			nil,
 			true,
+			time.UTC,
 		)
 
 		assert.Nil(t, events)
 		assert.NotNil(t, err)
 		assert.Equal(t, http.StatusBadRequest, err.HTTPCode)
-		assert.Contains(t, err.Message, "Invalid start time format - must be HH:MM:SS±HH:MM (e.g. 09:00:00+00:00)")
+		assert.Contains(t, err.Message, "Invalid start time format - must be HH:MM:SS with an optional UTC offset")
 	})
 
 	t.Run("End time before start time (crosses midnight)", func(t *testing.T) {
@@ -156,10 +152,8 @@ func TestGenerateEventsFromRecurrence(t *testing.T) {
 		}
 
 		events, err := generateEventsFromRecurrence(
-			recurrence.FirstOccurrence,
-			recurrence.LastOccurrence,
-			recurrence.StartTime,
-			recurrence.EndTime,
+			recurrence.BaseRecurrenceValues,
+			nil,
 			recurrence.CreatedBy,
 			recurrence.ProgramID,
 			recurrence.LocationID,
@@ -167,9 +161,9 @@ func TestGenerateEventsFromRecurrence(t *testing.T) {
 			recurrence.TeamID,
 			recurrence.RequiredMembershipPlanIDs,
 			recurrence.PriceID,
-			recurrence.DayOfWeek,
 			nil,
 			true,
+			time.UTC,
 		)
 
 		assert.Nil(t, err)
@@ -197,10 +191,8 @@ func TestGenerateEventsFromRecurrence(t *testing.T) {
 		}
 
 		events, err := generateEventsFromRecurrence(
-			recurrence.FirstOccurrence,
-			recurrence.LastOccurrence,
-			recurrence.StartTime,
-			recurrence.EndTime,
+			recurrence.BaseRecurrenceValues,
+			nil,
 			recurrence.CreatedBy,
 			recurrence.ProgramID,
 			recurrence.LocationID,
@@ -208,9 +200,9 @@ func TestGenerateEventsFromRecurrence(t *testing.T) {
 			recurrence.TeamID,
 			recurrence.RequiredMembershipPlanIDs,
 			recurrence.PriceID,
-			recurrence.DayOfWeek,
 			nil,
 			true,
+			time.UTC,
 		)
 
 		assert.NotNil(t, err)
@@ -235,10 +227,8 @@ func TestGenerateEventsFromRecurrence(t *testing.T) {
 		}
 
 		events, err := generateEventsFromRecurrence(
-			recurrence.FirstOccurrence,
-			recurrence.LastOccurrence,
-			recurrence.StartTime,
-			recurrence.EndTime,
+			recurrence.BaseRecurrenceValues,
+			nil,
 			recurrence.CreatedBy,
 			recurrence.ProgramID,
 			recurrence.LocationID,
@@ -246,12 +236,237 @@ func TestGenerateEventsFromRecurrence(t *testing.T) {
 			recurrence.TeamID,
 			recurrence.RequiredMembershipPlanIDs,
 			recurrence.PriceID,
-			recurrence.DayOfWeek,
 			nil,
 			true,
+			time.UTC,
 		)
 
 		assert.Nil(t, err)
 		assert.Empty(t, events)
 	})
+
+	t.Run("Wall clock time is kept across DST transitions", func(t *testing.T) {
+		edmonton, loadErr := time.LoadLocation("America/Edmonton")
+		assert.NoError(t, loadErr)
+
+		// DST ends in Calgary on Sunday, November 2 2025
+		events, err := generateEventsFromRecurrence(
+			values.BaseRecurrenceValues{
+				DayOfWeek:       time.Monday,
+				FirstOccurrence: time.Date(2025, 10, 20, 0, 0, 0, 0, time.UTC), // Monday
+				LastOccurrence:  time.Date(2025, 11, 10, 0, 0, 0, 0, time.UTC), // Monday
+				StartTime:       "18:00:00",
+				EndTime:         "19:30:00",
+			},
+			nil,
+			uuid.New(),
+			uuid.New(),
+			uuid.New(),
+			uuid.Nil,
+			uuid.Nil,
+			nil,
+			"",
+			nil,
+			true,
+			edmonton,
+		)
+
+		assert.Nil(t, err)
+		assert.Len(t, events, 4)
+		for _, event := range events {
+			local := event.StartAt.In(edmonton)
+			assert.Equal(t, "18:00", local.Format("15:04"))
+			assert.Equal(t, "19:30", event.EndAt.In(edmonton).Format("15:04"))
+			assert.Equal(t, time.Monday, local.Weekday())
+		}
+
+		// Same local time, different UTC instant once MDT becomes MST
+		assert.Equal(t, 0, events[1].StartAt.UTC().Hour())
+		assert.Equal(t, 1, events[2].StartAt.UTC().Hour())
+	})
+
+	t.Run("UTC offset is converted to facility time on the first occurrence", func(t *testing.T) {
+		edmonton, loadErr := time.LoadLocation("America/Edmonton")
+		assert.NoError(t, loadErr)
+
+		// Midnight UTC on a Monday is Sunday evening in Calgary
+		events, err := generateEventsFromRecurrence(
+			values.BaseRecurrenceValues{
+				DayOfWeek:       time.Monday,
+				FirstOccurrence: time.Date(2025, 10, 20, 0, 0, 0, 0, time.UTC),
+				LastOccurrence:  time.Date(2025, 11, 10, 0, 0, 0, 0, time.UTC),
+				StartTime:       "00:00:00+00:00",
+				EndTime:         "01:30:00+00:00",
+			},
+			nil,
+			uuid.New(),
+			uuid.New(),
+			uuid.New(),
+			uuid.Nil,
+			uuid.Nil,
+			nil,
+			"",
+			nil,
+			true,
+			edmonton,
+		)
+
+		assert.Nil(t, err)
+		assert.Len(t, events, 4)
+		for _, event := range events {
+			local := event.StartAt.In(edmonton)
+			assert.Equal(t, "18:00", local.Format("15:04"))
+			assert.Equal(t, "19:30", event.EndAt.In(edmonton).Format("15:04"))
+			assert.Equal(t, time.Sunday, local.Weekday())
+		}
+	})
+
+	t.Run("Wall clock time is kept across spring forward", func(t *testing.T) {
+		toronto, loadErr := time.LoadLocation("America/Toronto")
+		assert.NoError(t, loadErr)
+
+		// DST starts in Toronto on Sunday, March 9 2025
+		events, err := generateEventsFromRecurrence(
+			values.BaseRecurrenceValues{
+				DayOfWeek:       time.Sunday,
+				FirstOccurrence: time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC), // Sunday
+				LastOccurrence:  time.Date(2025, 3, 16, 0, 0, 0, 0, time.UTC),
+				StartTime:       "09:00:00",
+				EndTime:         "10:00:00",
+			},
+			nil,
+			uuid.New(),
+			uuid.New(),
+			uuid.New(),
+			uuid.Nil,
+			uuid.Nil,
+			nil,
+			"",
+			nil,
+			false,
+			toronto,
+		)
+
+		assert.Nil(t, err)
+		assert.Len(t, events, 3)
+		for _, event := range events {
+			assert.Equal(t, "09:00", event.StartAt.In(toronto).Format("15:04"))
+			assert.Equal(t, time.Hour, event.EndAt.Sub(event.StartAt))
+		}
+	})
+	t.Run("RRULE with exceptions and blackouts", func(t *testing.T) {
+		flagged := uuid.New()
+		blackouts := []recurrence.Blackout{
+			{ID: uuid.New(), StartsOn: time.Date(2025, 12, 22, 0, 0, 0, 0, time.UTC), EndsOn: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC), Action: recurrence.BlackoutSkip},
+			{ID: flagged, StartsOn: time.Date(2026, 1, 7, 0, 0, 0, 0, time.UTC), EndsOn: time.Date(2026, 1, 7, 0, 0, 0, 0, time.UTC), Action: recurrence.BlackoutFlag},
+		}
+
+		events, err := generateEventsFromRecurrence(
+			values.BaseRecurrenceValues{
+				FirstOccurrence: time.Date(2025, 12, 15, 0, 0, 0, 0, time.UTC), // Monday
+				LastOccurrence:  time.Date(2026, 1, 9, 0, 0, 0, 0, time.UTC),
+				StartTime:       "18:00:00",
+				EndTime:         "19:00:00",
+				RRule:           "FREQ=WEEKLY;BYDAY=MO,WE",
+				ExDates:         []time.Time{time.Date(2025, 12, 17, 0, 0, 0, 0, time.UTC)},
+			},
+			blackouts,
+			uuid.New(),
+			uuid.New(),
+			uuid.New(),
+			uuid.Nil,
+			uuid.Nil,
+			nil,
+			"",
+			nil,
+			false,
+			time.UTC,
+		)
+
+		assert.Nil(t, err)
+		// Dec 15, (Dec 17 excluded), Dec 22-31 skipped, Jan 5, Jan 7 (flagged)
+		assert.Len(t, events, 3)
+		assert.Equal(t, "2025-12-15", events[0].StartAt.Format("2006-01-02"))
+		assert.Equal(t, "2026-01-05", events[1].StartAt.Format("2006-01-02"))
+		assert.Equal(t, uuid.Nil, events[1].BlackoutID)
+		assert.Equal(t, "2026-01-07", events[2].StartAt.Format("2006-01-02"))
+		assert.Equal(t, flagged, events[2].BlackoutID)
+	})
+
+	t.Run("COUNT bounds a rule without an end date", func(t *testing.T) {
+		events, err := generateEventsFromRecurrence(
+			values.BaseRecurrenceValues{
+				FirstOccurrence: time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC),
+				StartTime:       "10:00:00",
+				EndTime:         "11:00:00",
+				RRule:           "FREQ=MONTHLY;BYDAY=1MO;COUNT=3",
+			},
+			nil,
+			uuid.New(),
+			uuid.New(),
+			uuid.New(),
+			uuid.Nil,
+			uuid.Nil,
+			nil,
+			"",
+			nil,
+			false,
+			time.UTC,
+		)
+
+		assert.Nil(t, err)
+		assert.Len(t, events, 3)
+		assert.Equal(t, "2025-11-03", events[2].StartAt.Format("2006-01-02"))
+	})
+
+	t.Run("Invalid rrule", func(t *testing.T) {
+		_, err := generateEventsFromRecurrence(
+			values.BaseRecurrenceValues{
+				FirstOccurrence: time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC),
+				LastOccurrence:  time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC),
+				StartTime:       "10:00:00",
+				EndTime:         "11:00:00",
+				RRule:           "FREQ=YEARLY",
+			},
+			nil,
+			uuid.New(),
+			uuid.New(),
+			uuid.New(),
+			uuid.Nil,
+			uuid.Nil,
+			nil,
+			"",
+			nil,
+			false,
+			time.UTC,
+		)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusBadRequest, err.HTTPCode)
+		assert.Contains(t, err.Message, "Invalid rrule")
+	})
+}
+
+func TestLegacyRecurrenceRule(t *testing.T) {
+	edmonton, loadErr := time.LoadLocation("America/Edmonton")
+	assert.NoError(t, loadErr)
+
+	id := uuid.New()
+	// Tuesdays at 18:00 in Calgary are Wednesdays in UTC; the moved occurrence is skipped.
+	rule := legacyRecurrenceRule(id, []dbEvent.GetRecurrenceEventsRow{
+		{StartAt: time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC), IsDateTimeModified: true},
+		{StartAt: time.Date(2025, 9, 10, 0, 0, 0, 0, time.UTC)},
+		{StartAt: time.Date(2025, 9, 17, 0, 0, 0, 0, time.UTC)},
+	}, edmonton)
+
+	assert.Equal(t, id, rule.ID)
+	assert.Equal(t, "FREQ=WEEKLY;BYDAY=TU;UNTIL=20250916", rule.RRule)
+	assert.Equal(t, time.Date(2025, 8, 31, 0, 0, 0, 0, time.UTC), rule.DTStart)
+	assert.Empty(t, rule.ExDates)
+
+	parsed, err := recurrence.Parse(rule.RRule)
+	assert.NoError(t, err)
+	dates, err := parsed.Expand(time.Date(2025, 9, 9, 0, 0, 0, 0, time.UTC), time.Time{}, nil)
+	assert.NoError(t, err)
+	assert.Len(t, dates, 2)
 }
@@ -22,10 +22,17 @@ type EventDetails struct {
 	// Fields for Stripe auto-creation (when PriceID is not provided)
 	UnitAmount *int64 // Price in cents
 	Currency   string // "cad" or "usd"
+	// OverrideReason lets an admin book the court over a practice or game
+	OverrideReason string
 }
 
 type CreateEventValues struct {
 	CreatedBy uuid.UUID
+	// RecurrenceID groups the occurrences of a series. When nil and several events are
+	// created together, the repository assigns them a shared ID.
+	RecurrenceID uuid.UUID
+	// BlackoutID is set when the occurrence falls on a flag-type location blackout.
+	BlackoutID uuid.UUID
 	EventDetails
 }
 
@@ -85,6 +92,9 @@ type ReadEventValues struct {
 	CreditCost                *int32
 	RegistrationRequired      bool
 
+	RecurrenceID *uuid.UUID
+	BlackoutID   *uuid.UUID
+
 	Customers []Customer
 
 	Staffs []Staff
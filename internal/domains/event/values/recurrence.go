@@ -9,9 +9,28 @@ import (
 type BaseRecurrenceValues struct {
 	DayOfWeek       time.Weekday
 	FirstOccurrence time.Time
-	LastOccurrence  time.Time
-	StartTime       string
-	EndTime         string
+	// LastOccurrence may be zero when RRule is bounded by COUNT or UNTIL.
+	LastOccurrence time.Time
+	StartTime      string
+	EndTime        string
+	// RRule is an RFC 5545 rule such as "FREQ=WEEKLY;BYDAY=MO,WE". When empty the
+	// recurrence repeats weekly on DayOfWeek.
+	RRule string
+	// ExDates are calendar dates on which no occurrence is generated.
+	ExDates []time.Time
+}
+
+// EditScope selects which occurrences of a series an update or delete applies to.
+type EditScope string
+
+const (
+	EditScopeOccurrence EditScope = "occurrence"
+	EditScopeFollowing  EditScope = "following"
+	EditScopeAll        EditScope = "all"
+)
+
+func (s EditScope) Valid() bool {
+	return s == EditScopeOccurrence || s == EditScopeFollowing || s == EditScopeAll
 }
 
 type ReadRecurrenceValues struct {
@@ -55,12 +74,16 @@ type CreateRecurrenceValues struct {
 
 type UpdateRecurrenceValues struct {
 	BaseRecurrenceValues
-	ID                        uuid.UUID
-	ProgramID                 uuid.UUID
-	TeamID                    uuid.UUID
-	LocationID                uuid.UUID
-	CourtID                   uuid.UUID
-	UpdatedBy                 uuid.UUID
+	ID         uuid.UUID
+	ProgramID  uuid.UUID
+	TeamID     uuid.UUID
+	LocationID uuid.UUID
+	CourtID    uuid.UUID
+	UpdatedBy  uuid.UUID
+	// Scope defaults to EditScopeAll. For the occurrence and following scopes,
+	// OccurrenceDate is the facility calendar date of the first affected occurrence.
+	Scope                     EditScope
+	OccurrenceDate            time.Time
 	RequiredMembershipPlanIDs []uuid.UUID
 	PriceID                   string
 	CreditCost                *int32
@@ -69,3 +92,19 @@ type UpdateRecurrenceValues struct {
 	UnitAmount *int64 // Price in cents
 	Currency   string // "cad" or "usd"
 }
+
+type DeleteRecurrenceValues struct {
+	ID             uuid.UUID
+	DeletedBy      uuid.UUID
+	Scope          EditScope
+	OccurrenceDate time.Time
+}
+
+// RecurrenceRule is the stored definition of a series, used to split it or add
+// exceptions without the client resending the whole rule.
+type RecurrenceRule struct {
+	ID      uuid.UUID
+	RRule   string
+	DTStart time.Time
+	ExDates []time.Time
+}
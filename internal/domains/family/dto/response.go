@@ -3,6 +3,8 @@ package family
 import (
 	"time"
 
+	developmentDto "api/internal/domains/development/dto"
+
 	"github.com/google/uuid"
 )
 
@@ -69,6 +71,8 @@ type ChildDetailResponse struct {
 	MembershipStartDate *time.Time                  `json:"membership_start_date,omitempty"`
 	Programs            []ProgramEnrollmentResponse `json:"programs"`
 	LinkedAt            time.Time                   `json:"linked_at"`
+	// Development holds published coach evaluations, skill trends and goals
+	Development *developmentDto.DevelopmentResponseDto `json:"development,omitempty"`
 }
 
 // PendingRequestResponse represents a pending link request
@@ -2,10 +2,12 @@ package family
 
 import (
 	"net/http"
+	"strconv"
 
 	"api/internal/di"
 	dto "api/internal/domains/family/dto"
 	service "api/internal/domains/family/service"
+	safetyDto "api/internal/domains/safety/dto"
 	responseHandlers "api/internal/libs/responses"
 	"api/internal/libs/validators"
 
@@ -183,7 +185,7 @@ func (h *Handler) GetSiblings(w http.ResponseWriter, r *http.Request) {
 
 // GetChildDetail gets detailed profile for a specific child.
 // @Summary Get child detail
-// @Description Gets detailed profile for a specific child, including membership, team, and program enrollments
+// @Description Gets detailed profile for a specific child, including membership, team, program enrollments and development (published coach evaluations, skill trends and goals). Athletes can view their own profile.
 // @Tags family
 // @Produce json
 // @Param childId path string true "Child user ID"
@@ -209,6 +211,141 @@ func (h *Handler) GetChildDetail(w http.ResponseWriter, r *http.Request) {
 	responseHandlers.RespondWithSuccess(w, result, http.StatusOK)
 }
 
+// GetChildProgressReports lists a child's progress reports.
+// @Summary Get child progress reports
+// @Description Lists the monthly progress reports issued to a child, newest first. Athletes can list their own.
+// @Tags family
+// @Produce json
+// @Param childId path string true "Child user ID"
+// @Success 200 {array} map[string]interface{} "Progress reports"
+// @Failure 400 {object} map[string]interface{} "Invalid child ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Not this child's parent"
+// @Router /family/children/{childId}/progress-reports [get]
+func (h *Handler) GetChildProgressReports(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "childId")
+	id, err := validators.ParseUUID(idStr)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	result, svcErr := h.Service.GetChildProgressReports(r.Context(), id)
+	if svcErr != nil {
+		responseHandlers.RespondWithError(w, svcErr)
+		return
+	}
+
+	responseHandlers.RespondWithSuccess(w, result, http.StatusOK)
+}
+
+// GetChildProgressReportPDF downloads one of a child's progress reports.
+// @Summary Download a child progress report
+// @Description Downloads the PDF of a child's progress report: the period's published evaluations, skill trends and goals
+// @Tags family
+// @Produce application/pdf
+// @Param childId path string true "Child user ID"
+// @Param reportId path string true "Progress report ID"
+// @Success 200 {file} file "PDF progress report"
+// @Failure 400 {object} map[string]interface{} "Invalid child or report ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Not this child's parent"
+// @Failure 404 {object} map[string]interface{} "Progress report not found"
+// @Router /family/children/{childId}/progress-reports/{reportId}/pdf [get]
+func (h *Handler) GetChildProgressReportPDF(w http.ResponseWriter, r *http.Request) {
+	childID, err := validators.ParseUUID(chi.URLParam(r, "childId"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	reportID, err := validators.ParseUUID(chi.URLParam(r, "reportId"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	document, filename, svcErr := h.Service.GetChildProgressReportPDF(r.Context(), childID, reportID)
+	if svcErr != nil {
+		responseHandlers.RespondWithError(w, svcErr)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", "attachment; filename="+filename)
+	w.Header().Set("Content-Length", strconv.Itoa(len(document)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(document)
+}
+
+// GetChildMedicalInfo returns a child's emergency contact and medical details.
+// @Summary Get child medical information
+// @Description Returns the emergency contact, allergies, conditions, medications and physician on file for a child. Athletes can read their own. Every read is logged.
+// @Tags family
+// @Produce json
+// @Param childId path string true "Child user ID"
+// @Success 200 {object} map[string]interface{} "Medical information"
+// @Failure 400 {object} map[string]interface{} "Invalid child ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Not this child's parent"
+// @Router /family/children/{childId}/medical [get]
+func (h *Handler) GetChildMedicalInfo(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "childId"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	result, svcErr := h.Service.GetChildMedicalInfo(r.Context(), id)
+	if svcErr != nil {
+		responseHandlers.RespondWithError(w, svcErr)
+		return
+	}
+
+	responseHandlers.RespondWithSuccess(w, result, http.StatusOK)
+}
+
+// UpdateChildMedicalInfo replaces a child's medical details.
+// @Summary Update child medical information
+// @Description Replaces the allergies, conditions, medications, physician and notes on file for a child. The emergency contact is edited on the profile. Athletes can update their own.
+// @Tags family
+// @Accept json
+// @Produce json
+// @Param childId path string true "Child user ID"
+// @Param request body map[string]interface{} true "Medical details"
+// @Success 200 {object} map[string]interface{} "Medical information updated"
+// @Failure 400 {object} map[string]interface{} "Invalid child ID or medical details"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Not this child's parent"
+// @Router /family/children/{childId}/medical [put]
+func (h *Handler) UpdateChildMedicalInfo(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "childId"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	var body safetyDto.MedicalInfoRequestDto
+	if err = validators.ParseJSON(r.Body, &body); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	details, err := body.ToValues()
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	result, svcErr := h.Service.UpdateChildMedicalInfo(r.Context(), id, details)
+	if svcErr != nil {
+		responseHandlers.RespondWithError(w, svcErr)
+		return
+	}
+
+	responseHandlers.RespondWithSuccess(w, result, http.StatusOK)
+}
+
 // AdminUnlink removes a parent-child link (admin only).
 // @Summary Admin unlink parent-child
 // @Description Removes the parent-child link for a user (admin only)
@@ -11,9 +11,14 @@ import (
 	"time"
 
 	"api/internal/di"
+	developmentDto "api/internal/domains/development/dto"
+	developmentService "api/internal/domains/development/service"
 	dto "api/internal/domains/family/dto"
 	repo "api/internal/domains/family/persistence"
 	db "api/internal/domains/family/persistence/sqlc/generated"
+	safetyDto "api/internal/domains/safety/dto"
+	safetyService "api/internal/domains/safety/service"
+	safetyValues "api/internal/domains/safety/values"
 	errLib "api/internal/libs/errors"
 	contextUtils "api/utils/context"
 	txUtils "api/utils/db"
@@ -28,14 +33,27 @@ const (
 )
 
 type Service struct {
-	repo *repo.Repository
-	db   *sql.DB
+	repo        *repo.Repository
+	children    childAccounts
+	development *developmentService.Service
+	safety      *safetyService.Service
+	db          *sql.DB
+}
+
+// childAccounts looks up a child's account to check who their parent is. Tests replace
+// it to run without a database.
+type childAccounts interface {
+	GetUserById(ctx context.Context, id uuid.UUID) (db.GetUserByIdRow, *errLib.CommonError)
 }
 
 func NewService(container *di.Container) *Service {
+	r := repo.NewFamilyRepository(container)
 	return &Service{
-		repo: repo.NewFamilyRepository(container),
-		db:   container.DB,
+		repo:        r,
+		children:    r,
+		development: developmentService.NewService(container),
+		safety:      safetyService.NewService(container),
+		db:          container.DB,
 	}
 }
 
@@ -562,15 +580,11 @@ func (s *Service) GetSiblings(ctx context.Context) ([]dto.SiblingResponse, *errL
 	return result, nil
 }
 
-// GetChildDetail gets detailed profile for a specific child
+// GetChildDetail gets detailed profile for a specific child. Athletes can also
+// view their own profile this way.
 func (s *Service) GetChildDetail(ctx context.Context, childID uuid.UUID) (*dto.ChildDetailResponse, *errLib.CommonError) {
-	parentID, err := contextUtils.GetUserID(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	// Verify the caller is this child's parent
-	if err := s.VerifyParentChildAccess(ctx, parentID, childID); err != nil {
+	// Verify the caller is this child's parent, or the child
+	if err := s.verifyChildOrSelf(ctx, childID); err != nil {
 		return nil, err
 	}
 
@@ -623,9 +637,75 @@ func (s *Service) GetChildDetail(ctx context.Context, childID uuid.UUID) (*dto.C
 		response.MembershipStartDate = &child.MembershipStartDate.Time
 	}
 
+	development, err := s.development.GetDevelopment(ctx, childID)
+	if err != nil {
+		return nil, err
+	}
+	response.Development = developmentDto.NewDevelopmentResponse(development)
+
 	return response, nil
 }
 
+// GetChildProgressReports lists the progress reports issued to a child, newest first
+func (s *Service) GetChildProgressReports(ctx context.Context, childID uuid.UUID) ([]developmentDto.ProgressReportResponseDto, *errLib.CommonError) {
+	if err := s.verifyChildOrSelf(ctx, childID); err != nil {
+		return nil, err
+	}
+
+	reports, err := s.development.ListAthleteProgressReports(ctx, childID)
+	if err != nil {
+		return nil, err
+	}
+	return developmentDto.NewProgressReportResponses(reports), nil
+}
+
+// GetChildProgressReportPDF renders one of a child's progress reports
+func (s *Service) GetChildProgressReportPDF(ctx context.Context, childID, reportID uuid.UUID) ([]byte, string, *errLib.CommonError) {
+	if err := s.verifyChildOrSelf(ctx, childID); err != nil {
+		return nil, "", err
+	}
+	return s.development.GetAthleteProgressReportPDF(ctx, childID, reportID)
+}
+
+// GetChildMedicalInfo returns a child's emergency contact and medical details. The
+// read is recorded in the child's medical access log.
+func (s *Service) GetChildMedicalInfo(ctx context.Context, childID uuid.UUID) (safetyDto.MedicalInfoResponseDto, *errLib.CommonError) {
+	if err := s.verifyChildOrSelf(ctx, childID); err != nil {
+		return safetyDto.MedicalInfoResponseDto{}, err
+	}
+
+	info, err := s.safety.GetFamilyMedicalInfo(ctx, childID)
+	if err != nil {
+		return safetyDto.MedicalInfoResponseDto{}, err
+	}
+	return safetyDto.NewMedicalInfoResponse(info), nil
+}
+
+// UpdateChildMedicalInfo replaces a child's medical details
+func (s *Service) UpdateChildMedicalInfo(ctx context.Context, childID uuid.UUID, details safetyValues.MedicalDetails) (safetyDto.MedicalInfoResponseDto, *errLib.CommonError) {
+	if err := s.verifyChildOrSelf(ctx, childID); err != nil {
+		return safetyDto.MedicalInfoResponseDto{}, err
+	}
+
+	info, err := s.safety.UpdateFamilyMedicalInfo(ctx, childID, details)
+	if err != nil {
+		return safetyDto.MedicalInfoResponseDto{}, err
+	}
+	return safetyDto.NewMedicalInfoResponse(info), nil
+}
+
+// verifyChildOrSelf allows the child themselves or their parent through
+func (s *Service) verifyChildOrSelf(ctx context.Context, childID uuid.UUID) *errLib.CommonError {
+	callerID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return err
+	}
+	if callerID == childID {
+		return nil
+	}
+	return s.VerifyParentChildAccess(ctx, callerID, childID)
+}
+
 // AdminUnlink removes a parent-child link (admin only)
 func (s *Service) AdminUnlink(ctx context.Context, childID uuid.UUID) *errLib.CommonError {
 	// Verify child exists and has a parent
@@ -666,7 +746,7 @@ func nullStringToString(ns sql.NullString) string {
 // Returns the childID if access is granted, or an error if not.
 // This is used by other handlers to support the child_id query parameter.
 func (s *Service) VerifyParentChildAccess(ctx context.Context, parentID, childID uuid.UUID) *errLib.CommonError {
-	child, err := s.repo.GetUserById(ctx, childID)
+	child, err := s.children.GetUserById(ctx, childID)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,82 @@
+package family
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	db "api/internal/domains/family/persistence/sqlc/generated"
+	safetyValues "api/internal/domains/safety/values"
+	errLib "api/internal/libs/errors"
+	contextUtils "api/utils/context"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeChildAccounts map[uuid.UUID]db.GetUserByIdRow
+
+func (f fakeChildAccounts) GetUserById(_ context.Context, id uuid.UUID) (db.GetUserByIdRow, *errLib.CommonError) {
+	user, ok := f[id]
+	if !ok {
+		return db.GetUserByIdRow{}, errLib.New("User not found", http.StatusNotFound)
+	}
+	return user, nil
+}
+
+func asUser(userID uuid.UUID) context.Context {
+	return context.WithValue(context.Background(), contextUtils.UserIDKey, userID)
+}
+
+func newFamily() (*Service, uuid.UUID, uuid.UUID) {
+	parent, child := uuid.New(), uuid.New()
+	service := &Service{children: fakeChildAccounts{
+		child: {ID: child, ParentID: uuid.NullUUID{UUID: parent, Valid: true}},
+	}}
+	return service, parent, child
+}
+
+func TestVerifyChildOrSelf(t *testing.T) {
+	service, parent, child := newFamily()
+
+	assert.Nil(t, service.verifyChildOrSelf(asUser(parent), child), "a parent sees their child")
+	assert.Nil(t, service.verifyChildOrSelf(asUser(child), child), "a child sees themselves")
+
+	err := service.verifyChildOrSelf(asUser(uuid.New()), child)
+	require.NotNil(t, err, "another parent does not")
+	assert.Equal(t, http.StatusForbidden, err.HTTPCode)
+
+	err = service.verifyChildOrSelf(context.Background(), child)
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, err.HTTPCode)
+}
+
+func TestChildProgressReportsNeedParentAccess(t *testing.T) {
+	service, _, child := newFamily()
+	stranger := asUser(uuid.New())
+
+	_, err := service.GetChildProgressReports(stranger, child)
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusForbidden, err.HTTPCode)
+
+	_, _, err = service.GetChildProgressReportPDF(stranger, child, uuid.New())
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusForbidden, err.HTTPCode)
+
+	_, err = service.GetChildProgressReports(stranger, uuid.New())
+	require.NotNil(t, err, "an unknown child is not found")
+	assert.Equal(t, http.StatusNotFound, err.HTTPCode)
+}
+
+func TestChildMedicalInfoNeedsParentAccess(t *testing.T) {
+	service, _, child := newFamily()
+
+	_, err := service.GetChildMedicalInfo(asUser(uuid.New()), child)
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusForbidden, err.HTTPCode)
+
+	_, err = service.UpdateChildMedicalInfo(asUser(uuid.New()), child, safetyValues.MedicalDetails{})
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusForbidden, err.HTTPCode)
+}
@@ -0,0 +1,171 @@
+package game
+
+import (
+	values "api/internal/domains/game/values"
+	errLib "api/internal/libs/errors"
+	"api/internal/libs/validators"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LiveEventRequestDto is a play recorded by a scorekeeper during a live game.
+type LiveEventRequestDto struct {
+	Type          string     `json:"type" validate:"required,oneof=period_start period_end clock score foul timeout void" example:"score"`
+	TeamID        *uuid.UUID `json:"team_id"`                                                // Required for score, foul and timeout
+	AthleteID     *uuid.UUID `json:"athlete_id"`                                             // Optional player credited with the play
+	Points        int32      `json:"points" validate:"gte=0,lte=3" example:"2"`              // 1, 2 or 3 for score events
+	Period        *int32     `json:"period" validate:"omitempty,gte=1" example:"1"`          // period_start only; defaults to the next period
+	ClockSeconds  *int32     `json:"clock_seconds" validate:"omitempty,gte=0" example:"425"` // Seconds left on the clock
+	ClockRunning  *bool      `json:"clock_running"`                                          // clock events: start (true) or stop (false) the clock
+	VoidsSequence *int32     `json:"voids_sequence" validate:"omitempty,gte=1" example:"12"` // void events: the event to strike from the log
+	Note          string     `json:"note" validate:"max=500"`
+}
+
+// ToValue validates the request and converts it to a domain value for the given game.
+func (dto LiveEventRequestDto) ToValue(gameID uuid.UUID) (values.RecordGameEventValue, *errLib.CommonError) {
+	if err := validators.ValidateDto(&dto); err != nil {
+		return values.RecordGameEventValue{}, err
+	}
+	return values.RecordGameEventValue{
+		GameID:        gameID,
+		Type:          dto.Type,
+		TeamID:        dto.TeamID,
+		AthleteID:     dto.AthleteID,
+		Points:        dto.Points,
+		Period:        dto.Period,
+		ClockSeconds:  dto.ClockSeconds,
+		ClockRunning:  dto.ClockRunning,
+		VoidsSequence: dto.VoidsSequence,
+		Note:          dto.Note,
+	}, nil
+}
+
+// FinalizeLiveGameRequestDto optionally annotates the end of a live game.
+type FinalizeLiveGameRequestDto struct {
+	Note string `json:"note" validate:"max=500"`
+}
+
+// GameEventResponseDto is one entry in a game's play-by-play log.
+type GameEventResponseDto struct {
+	Sequence      int32      `json:"sequence" example:"12"`
+	Type          string     `json:"type" example:"score"`
+	TeamID        *uuid.UUID `json:"team_id,omitempty"`
+	AthleteID     *uuid.UUID `json:"athlete_id,omitempty"`
+	Points        int32      `json:"points,omitempty" example:"2"`
+	Period        int32      `json:"period" example:"1"`
+	ClockSeconds  *int32     `json:"clock_seconds,omitempty" example:"425"`
+	ClockRunning  *bool      `json:"clock_running,omitempty"`
+	VoidsSequence *int32     `json:"voids_sequence,omitempty"`
+	Voided        bool       `json:"voided"` // Struck from the log by a later void event
+	Note          string     `json:"note,omitempty"`
+	RecordedBy    *uuid.UUID `json:"recorded_by,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// TeamLiveStateResponseDto is one side's running totals.
+type TeamLiveStateResponseDto struct {
+	TeamID      uuid.UUID `json:"team_id"`
+	Score       int32     `json:"score" example:"54"`
+	Fouls       int32     `json:"fouls" example:"9"`
+	PeriodFouls int32     `json:"period_fouls" example:"3"`
+	Timeouts    int32     `json:"timeouts" example:"2"`
+}
+
+// LiveStateResponseDto is a game's live score and clock.
+type LiveStateResponseDto struct {
+	Period         int32                    `json:"period" example:"3"`
+	PeriodActive   bool                     `json:"period_active"`
+	ClockSeconds   *int32                   `json:"clock_seconds,omitempty" example:"425"`
+	ClockRunning   bool                     `json:"clock_running"` // Count down from clock_seconds since clock_updated_at while true
+	ClockUpdatedAt *time.Time               `json:"clock_updated_at,omitempty"`
+	Home           TeamLiveStateResponseDto `json:"home"`
+	Away           TeamLiveStateResponseDto `json:"away"`
+	LastSequence   int32                    `json:"last_sequence" example:"57"`
+	Final          bool                     `json:"final"`
+}
+
+// LiveGameResponseDto is a game's live state with its full play-by-play log.
+type LiveGameResponseDto struct {
+	GameID uuid.UUID              `json:"game_id"`
+	Status string                 `json:"status" example:"in_progress"`
+	State  LiveStateResponseDto   `json:"state"`
+	Events []GameEventResponseDto `json:"events"`
+}
+
+// LiveUpdateResponseDto is sent to subscribers whenever an event is recorded.
+type LiveUpdateResponseDto struct {
+	GameID     uuid.UUID            `json:"game_id"`
+	HomeTeamID uuid.UUID            `json:"home_team_id"`
+	AwayTeamID uuid.UUID            `json:"away_team_id"`
+	Event      GameEventResponseDto `json:"event"`
+	State      LiveStateResponseDto `json:"state"`
+}
+
+// NewLiveGameResponse maps a game's live state and log for API responses.
+func NewLiveGameResponse(game values.LiveGame) LiveGameResponseDto {
+	voided := values.VoidedSequences(game.Events)
+	events := make([]GameEventResponseDto, len(game.Events))
+	for i, event := range game.Events {
+		events[i] = newGameEventResponse(event, voided[event.Sequence])
+	}
+	return LiveGameResponseDto{
+		GameID: game.State.GameID,
+		Status: game.Status,
+		State:  newLiveStateResponse(game.State),
+		Events: events,
+	}
+}
+
+// NewLiveUpdateResponse maps a live update for API responses and subscribers.
+func NewLiveUpdateResponse(update values.LiveUpdate) LiveUpdateResponseDto {
+	return LiveUpdateResponseDto{
+		GameID:     update.GameID,
+		HomeTeamID: update.HomeTeamID,
+		AwayTeamID: update.AwayTeamID,
+		Event:      newGameEventResponse(update.Event, false),
+		State:      newLiveStateResponse(update.State),
+	}
+}
+
+func newGameEventResponse(event values.GameEvent, voided bool) GameEventResponseDto {
+	return GameEventResponseDto{
+		Sequence:      event.Sequence,
+		Type:          event.Type,
+		TeamID:        event.TeamID,
+		AthleteID:     event.AthleteID,
+		Points:        event.Points,
+		Period:        event.Period,
+		ClockSeconds:  event.ClockSeconds,
+		ClockRunning:  event.ClockRunning,
+		VoidsSequence: event.VoidsSequence,
+		Voided:        voided,
+		Note:          event.Note,
+		RecordedBy:    event.RecordedBy,
+		CreatedAt:     event.CreatedAt,
+	}
+}
+
+func newLiveStateResponse(state values.LiveState) LiveStateResponseDto {
+	return LiveStateResponseDto{
+		Period:         state.Period,
+		PeriodActive:   state.PeriodActive,
+		ClockSeconds:   state.ClockSeconds,
+		ClockRunning:   state.ClockRunning,
+		ClockUpdatedAt: state.ClockUpdatedAt,
+		Home:           newTeamLiveStateResponse(state.Home),
+		Away:           newTeamLiveStateResponse(state.Away),
+		LastSequence:   state.LastSequence,
+		Final:          state.Final,
+	}
+}
+
+func newTeamLiveStateResponse(team values.TeamLiveState) TeamLiveStateResponseDto {
+	return TeamLiveStateResponseDto{
+		TeamID:      team.TeamID,
+		Score:       team.Score,
+		Fouls:       team.Fouls,
+		PeriodFouls: team.PeriodFouls,
+		Timeouts:    team.Timeouts,
+	}
+}
@@ -22,6 +22,11 @@ type RequestDto struct {
 	CourtID          uuid.UUID  `json:"court_id"`                                             // ID of the court where the game is played
 	Status           string     `json:"status" validate:"oneof=scheduled completed canceled"` // Game status must be one of the allowed values
 	SkipNotification *bool      `json:"skip_notification"`                                    // Skip auto-notification on update
+
+	// Admin only: book the court over an event or practice, recording why
+	OverrideReason string `json:"override_reason"`
+	// Admin only: schedule the creating coach over their schedule conflicts, recording why
+	StaffOverrideReason string `json:"staff_override_reason"`
 }
 
 // ToCreateGameValue converts a validated RequestDto into a CreateGameValue used in the domain layer.
@@ -48,6 +53,9 @@ func (dto *RequestDto) ToCreateGameValue() (values.CreateGameValue, *errLib.Comm
 		LocationID: dto.LocationID,
 		CourtID:    dto.CourtID,
 		Status:     dto.Status,
+
+		OverrideReason:      dto.OverrideReason,
+		StaffOverrideReason: dto.StaffOverrideReason,
 	}
 
 	return details, nil
@@ -93,6 +101,9 @@ func (dto *RequestDto) ToUpdateGameValue(idStr string) (values.UpdateGameValue,
 			LocationID: dto.LocationID,
 			CourtID:    dto.CourtID,
 			Status:     dto.Status,
+
+			OverrideReason:      dto.OverrideReason,
+			StaffOverrideReason: dto.StaffOverrideReason,
 		},
 	}
 
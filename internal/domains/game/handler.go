@@ -4,6 +4,7 @@ import (
 	"api/internal/di"
 	familyService "api/internal/domains/family/service"
 	dto "api/internal/domains/game/dto"
+	"api/internal/domains/game/live"
 	service "api/internal/domains/game/services"
 	"api/internal/domains/game/values"
 	errLib "api/internal/libs/errors"
@@ -21,12 +22,14 @@ import (
 type Handler struct {
 	Service       *service.Service
 	FamilyService *familyService.Service
+	Live          *live.Broadcaster
 }
 
 func NewHandler(container *di.Container) *Handler {
 	return &Handler{
 		Service:       service.NewService(container),
 		FamilyService: familyService.NewService(container),
+		Live:          container.LiveGames,
 	}
 }
 
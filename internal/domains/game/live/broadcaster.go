@@ -0,0 +1,182 @@
+package live
+
+import (
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	values "api/internal/domains/game/values"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Channel is the Postgres NOTIFY channel live game updates are published on.
+const Channel = "game_live"
+
+// subscriberBuffer is how many updates a subscriber can fall behind before it is
+// dropped. Dropped subscribers reconnect and catch up from the event log.
+const subscriberBuffer = 64
+
+// Subscription receives the live updates for one game or for every game a team plays.
+type Subscription struct {
+	gameID  uuid.UUID
+	teamID  uuid.UUID
+	updates chan values.LiveUpdate
+}
+
+// Updates delivers the subscription's updates. It is closed when the subscriber
+// falls too far behind, updates may have been missed, or the broadcaster stops.
+func (s *Subscription) Updates() <-chan values.LiveUpdate {
+	return s.updates
+}
+
+func (s *Subscription) matches(update values.LiveUpdate) bool {
+	if s.gameID != uuid.Nil {
+		return update.GameID == s.gameID
+	}
+	return update.HomeTeamID == s.teamID || update.AwayTeamID == s.teamID
+}
+
+// Broadcaster fans live game updates out to the subscribers connected to this
+// instance. Updates arrive over Postgres LISTEN/NOTIFY, so an event recorded
+// through any instance reaches subscribers on all of them.
+type Broadcaster struct {
+	connStr string
+
+	mu   sync.Mutex
+	subs map[*Subscription]struct{}
+
+	listener *pq.Listener
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewBroadcaster creates a broadcaster that listens on the given database.
+func NewBroadcaster(connStr string) *Broadcaster {
+	return &Broadcaster{
+		connStr: connStr,
+		subs:    make(map[*Subscription]struct{}),
+	}
+}
+
+// SubscribeGame subscribes to a single game's updates.
+func (b *Broadcaster) SubscribeGame(gameID uuid.UUID) *Subscription {
+	return b.subscribe(&Subscription{gameID: gameID, updates: make(chan values.LiveUpdate, subscriberBuffer)})
+}
+
+// SubscribeTeam subscribes to the updates of every game a team plays.
+func (b *Broadcaster) SubscribeTeam(teamID uuid.UUID) *Subscription {
+	return b.subscribe(&Subscription{teamID: teamID, updates: make(chan values.LiveUpdate, subscriberBuffer)})
+}
+
+func (b *Broadcaster) subscribe(sub *Subscription) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs[sub] = struct{}{}
+	return sub
+}
+
+// Unsubscribe removes a subscription. Safe to call more than once.
+func (b *Broadcaster) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.remove(sub)
+}
+
+// remove closes and forgets a subscription. Callers hold b.mu.
+func (b *Broadcaster) remove(sub *Subscription) {
+	if _, ok := b.subs[sub]; ok {
+		delete(b.subs, sub)
+		close(sub.updates)
+	}
+}
+
+// Publish delivers an update to this instance's matching subscribers. A subscriber
+// whose buffer is full is dropped rather than holding up everyone else.
+func (b *Broadcaster) Publish(update values.LiveUpdate) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subs {
+		if !sub.matches(update) {
+			continue
+		}
+		select {
+		case sub.updates <- update:
+		default:
+			log.Printf("[LIVE-GAMES] Dropping slow subscriber for game %s", update.GameID)
+			b.remove(sub)
+		}
+	}
+}
+
+// dropAll disconnects every subscriber so they reconnect and catch up from the log.
+func (b *Broadcaster) dropAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subs {
+		b.remove(sub)
+	}
+}
+
+// Start begins listening for updates published by any instance.
+func (b *Broadcaster) Start() {
+	b.listener = pq.NewListener(b.connStr, 10*time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("[LIVE-GAMES] Listener error: %v", err)
+		}
+	})
+	if err := b.listener.Listen(Channel); err != nil {
+		log.Printf("[LIVE-GAMES] Failed to listen on %s: %v", Channel, err)
+	}
+
+	b.stop = make(chan struct{})
+	b.done = make(chan struct{})
+	go b.run()
+	log.Printf("[LIVE-GAMES] Listening for live game updates")
+}
+
+// Stop stops listening and disconnects every subscriber.
+func (b *Broadcaster) Stop() {
+	if b.listener == nil {
+		return
+	}
+	close(b.stop)
+	<-b.done
+	if err := b.listener.Close(); err != nil {
+		log.Printf("[LIVE-GAMES] Failed to close listener: %v", err)
+	}
+	b.dropAll()
+}
+
+func (b *Broadcaster) run() {
+	defer close(b.done)
+	ping := time.NewTicker(90 * time.Second)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-b.stop:
+			return
+		case notification := <-b.listener.Notify:
+			if notification == nil {
+				// The connection was re-established and notifications may have been lost
+				b.dropAll()
+				continue
+			}
+			var update values.LiveUpdate
+			if err := json.Unmarshal([]byte(notification.Extra), &update); err != nil {
+				log.Printf("[LIVE-GAMES] Ignoring malformed update: %v", err)
+				continue
+			}
+			b.Publish(update)
+		case <-ping.C:
+			go func() {
+				if err := b.listener.Ping(); err != nil {
+					log.Printf("[LIVE-GAMES] Listener ping failed: %v", err)
+				}
+			}()
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package live
+
+import (
+	"testing"
+
+	values "api/internal/domains/game/values"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishRoutesByGameAndTeam(t *testing.T) {
+	b := NewBroadcaster("")
+	gameID, home, away := uuid.New(), uuid.New(), uuid.New()
+
+	game := b.SubscribeGame(gameID)
+	homeTeam := b.SubscribeTeam(home)
+	other := b.SubscribeGame(uuid.New())
+
+	update := values.LiveUpdate{GameID: gameID, HomeTeamID: home, AwayTeamID: away}
+	b.Publish(update)
+
+	require.Equal(t, update, <-game.Updates())
+	require.Equal(t, update, <-homeTeam.Updates())
+	require.Empty(t, other.Updates())
+}
+
+func TestSlowSubscriberIsDropped(t *testing.T) {
+	b := NewBroadcaster("")
+	gameID := uuid.New()
+	sub := b.SubscribeGame(gameID)
+
+	for i := 0; i <= subscriberBuffer; i++ {
+		b.Publish(values.LiveUpdate{GameID: gameID})
+	}
+
+	received := 0
+	for range sub.Updates() {
+		received++
+	}
+	require.Equal(t, subscriberBuffer, received)
+
+	// Unsubscribing a dropped subscription is harmless
+	b.Unsubscribe(sub)
+}
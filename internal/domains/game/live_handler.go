@@ -0,0 +1,323 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	dto "api/internal/domains/game/dto"
+	errLib "api/internal/libs/errors"
+	responseHandlers "api/internal/libs/responses"
+	"api/internal/libs/validators"
+
+	"github.com/go-chi/chi"
+)
+
+// sseKeepAlive is how often an idle stream sends a comment so proxies keep it open.
+const sseKeepAlive = 20 * time.Second
+
+// GetLiveGame returns a game's live score, clock and play-by-play log.
+// @Summary Get a game's live score
+// @Description Returns the live state derived from the game's play-by-play log, along with the log itself. Voided events are kept in the log and flagged.
+// @Tags games
+// @Produce json
+// @Param id path string true "Game ID"
+// @Success 200 {object} dto.LiveGameResponseDto "Live game"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid ID"
+// @Failure 404 {object} map[string]interface{} "Not Found: Game not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /games/{id}/live [get]
+func (h *Handler) GetLiveGame(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	game, err := h.Service.GetLiveGame(r.Context(), id)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewLiveGameResponse(game), http.StatusOK)
+}
+
+// StreamLiveGame streams a game's live updates as Server-Sent Events.
+// @Summary Stream a game's live score
+// @Description Server-Sent Events stream. Opens with a `snapshot` event (dto.LiveGameResponseDto) and then sends an `update` event (dto.LiveUpdateResponseDto) for every play, with the play's sequence as the event ID.
+// @Description Reconnecting clients send Last-Event-ID (or `after`) and the snapshot then only lists the plays they missed.
+// @Tags games
+// @Produce text/event-stream
+// @Param id path string true "Game ID"
+// @Param after query int false "Only include plays after this sequence in the snapshot"
+// @Success 200 {object} dto.LiveUpdateResponseDto "Event stream"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid ID"
+// @Failure 404 {object} map[string]interface{} "Not Found: Game not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /games/{id}/live/stream [get]
+func (h *Handler) StreamLiveGame(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	after := int32(0)
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("after")
+	}
+	if lastEventID != "" {
+		parsed, parseErr := strconv.ParseInt(lastEventID, 10, 32)
+		if parseErr != nil || parsed < 0 {
+			responseHandlers.RespondWithError(w, errLib.New("Last-Event-ID must be a play sequence", http.StatusBadRequest))
+			return
+		}
+		after = int32(parsed)
+	}
+
+	// Subscribe before reading the log so no play falls between the two
+	sub := h.Live.SubscribeGame(id)
+	defer h.Live.Unsubscribe(sub)
+
+	game, err := h.Service.GetLiveGame(r.Context(), id)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	snapshot := dto.NewLiveGameResponse(game)
+	missed := snapshot.Events[:0]
+	for _, event := range snapshot.Events {
+		if event.Sequence > after {
+			missed = append(missed, event)
+		}
+	}
+	snapshot.Events = missed
+
+	stream := startEventStream(w)
+	lastSequence := game.State.LastSequence
+	if stream.send("snapshot", strconv.Itoa(int(lastSequence)), snapshot) != nil {
+		return
+	}
+
+	keepAlive := time.NewTicker(sseKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if stream.keepAlive() != nil {
+				return
+			}
+		case update, ok := <-sub.Updates():
+			if !ok {
+				// Dropped; the client reconnects and catches up from Last-Event-ID
+				return
+			}
+			if update.Event.Sequence <= lastSequence {
+				continue
+			}
+			lastSequence = update.Event.Sequence
+			if stream.send("update", strconv.Itoa(int(lastSequence)), dto.NewLiveUpdateResponse(update)) != nil {
+				return
+			}
+		}
+	}
+}
+
+// StreamTeamLiveGames streams live updates for every game a team plays as Server-Sent Events.
+// @Summary Stream a team's live games
+// @Description Server-Sent Events stream. Opens with a `snapshot` event (dto.LiveGameResponseDto) for each game the team is playing, then sends an `update` event (dto.LiveUpdateResponseDto) for every play in any of the team's games, including games that start later.
+// @Tags games
+// @Produce text/event-stream
+// @Param team_id path string true "Team ID"
+// @Success 200 {object} dto.LiveUpdateResponseDto "Event stream"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid ID"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /games/teams/{team_id}/live/stream [get]
+func (h *Handler) StreamTeamLiveGames(w http.ResponseWriter, r *http.Request) {
+	teamID, err := validators.ParseUUID(chi.URLParam(r, "team_id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	sub := h.Live.SubscribeTeam(teamID)
+	defer h.Live.Unsubscribe(sub)
+
+	games, err := h.Service.GetTeamLiveGames(r.Context(), teamID)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	stream := startEventStream(w)
+	lastSequences := make(map[string]int32, len(games))
+	for _, game := range games {
+		lastSequences[game.State.GameID.String()] = game.State.LastSequence
+		if stream.send("snapshot", "", dto.NewLiveGameResponse(game)) != nil {
+			return
+		}
+	}
+
+	keepAlive := time.NewTicker(sseKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if stream.keepAlive() != nil {
+				return
+			}
+		case update, ok := <-sub.Updates():
+			if !ok {
+				return
+			}
+			gameKey := update.GameID.String()
+			if update.Event.Sequence <= lastSequences[gameKey] {
+				continue
+			}
+			lastSequences[gameKey] = update.Event.Sequence
+			if stream.send("update", "", dto.NewLiveUpdateResponse(update)) != nil {
+				return
+			}
+		}
+	}
+}
+
+// RecordLiveEvent appends a play to a game's play-by-play log.
+// @Summary Record a live play
+// @Description Records a period start/end, clock change, basket, team foul or timeout, or voids an earlier play to correct a mistake. The first play starts a scheduled game. Coaches may only score their own teams' games.
+// @Tags games
+// @Accept json
+// @Produce json
+// @Param id path string true "Game ID"
+// @Param event body dto.LiveEventRequestDto true "Play"
+// @Security Bearer
+// @Success 201 {object} dto.LiveUpdateResponseDto "Play recorded"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid play"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Not allowed to keep score for this game"
+// @Failure 404 {object} map[string]interface{} "Not Found: Game or voided play not found"
+// @Failure 409 {object} map[string]interface{} "Conflict: Play does not fit the game state (e.g. game finalized)"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /games/{id}/live/events [post]
+func (h *Handler) RecordLiveEvent(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	var requestDto dto.LiveEventRequestDto
+	if err := validators.ParseJSON(r.Body, &requestDto); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	event, err := requestDto.ToValue(id)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	update, err := h.Service.RecordLiveEvent(r.Context(), event)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewLiveUpdateResponse(update), http.StatusCreated)
+}
+
+// FinalizeLiveGame ends a live game and commits its final score from the play-by-play log.
+// @Summary Finalize a live game
+// @Description Ends live scoring and stores the score derived from the play-by-play log on the game, marking it completed. Live games nobody finalizes are finalized automatically two hours after their last play once past their end time.
+// @Tags games
+// @Accept json
+// @Produce json
+// @Param id path string true "Game ID"
+// @Param body body dto.FinalizeLiveGameRequestDto false "Optional note"
+// @Security Bearer
+// @Success 200 {object} dto.LiveUpdateResponseDto "Game finalized"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid input"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Not allowed to keep score for this game"
+// @Failure 404 {object} map[string]interface{} "Not Found: Game not found"
+// @Failure 409 {object} map[string]interface{} "Conflict: Already finalized or no plays recorded"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /games/{id}/live/finalize [post]
+func (h *Handler) FinalizeLiveGame(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	var requestDto dto.FinalizeLiveGameRequestDto
+	if r.ContentLength != 0 {
+		if err := validators.ParseJSON(r.Body, &requestDto); err != nil {
+			responseHandlers.RespondWithError(w, err)
+			return
+		}
+		if err := validators.ValidateDto(&requestDto); err != nil {
+			responseHandlers.RespondWithError(w, err)
+			return
+		}
+	}
+
+	update, err := h.Service.FinalizeLiveGame(r.Context(), id, requestDto.Note)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewLiveUpdateResponse(update), http.StatusOK)
+}
+
+// eventStream writes Server-Sent Events to a response.
+type eventStream struct {
+	w          http.ResponseWriter
+	controller *http.ResponseController
+}
+
+// startEventStream sends the event stream headers. The server's write timeout is
+// lifted for this response since streams stay open for the whole game.
+func startEventStream(w http.ResponseWriter) *eventStream {
+	controller := http.NewResponseController(w)
+	_ = controller.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // Stop proxies from buffering the stream
+	w.WriteHeader(http.StatusOK)
+
+	stream := &eventStream{w: w, controller: controller}
+	_, _ = fmt.Fprint(w, "retry: 3000\n\n")
+	_ = controller.Flush()
+	return stream
+}
+
+func (s *eventStream) send(event, id string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if id != "" {
+		if _, err := fmt.Fprintf(s.w, "id: %s\n", id); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+	return s.controller.Flush()
+}
+
+func (s *eventStream) keepAlive() error {
+	if _, err := fmt.Fprint(s.w, ": keep-alive\n\n"); err != nil {
+		return err
+	}
+	return s.controller.Flush()
+}
@@ -0,0 +1,180 @@
+package game
+
+import (
+	databaseErrors "api/internal/constants"
+	db "api/internal/domains/game/persistence/sqlc/generated"
+	values "api/internal/domains/game/values"
+	errLib "api/internal/libs/errors"
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// LockGameForScoring locks a game so only one event is appended to its log at a time.
+// Must be called inside a transaction.
+func (r *Repository) LockGameForScoring(ctx context.Context, id uuid.UUID) (values.GameTeams, *errLib.CommonError) {
+	row, err := r.Queries.LockGameForScoring(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return values.GameTeams{}, errLib.New("Game not found", http.StatusNotFound)
+		}
+		log.Printf("Error locking game %s for scoring: %v", id, err)
+		return values.GameTeams{}, errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+	return values.GameTeams{ID: row.ID, HomeTeamID: row.HomeTeamID, AwayTeamID: row.AwayTeamID, Status: row.Status.String}, nil
+}
+
+// GetGameTeams fetches a game's teams and status without locking it.
+func (r *Repository) GetGameTeams(ctx context.Context, id uuid.UUID) (values.GameTeams, *errLib.CommonError) {
+	row, err := r.Queries.GetGameTeams(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return values.GameTeams{}, errLib.New("Game not found", http.StatusNotFound)
+		}
+		log.Printf("Error getting game %s teams: %v", id, err)
+		return values.GameTeams{}, errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+	return values.GameTeams{ID: row.ID, HomeTeamID: row.HomeTeamID, AwayTeamID: row.AwayTeamID, Status: row.Status.String}, nil
+}
+
+// ListGameEvents returns a game's play-by-play log in sequence order.
+func (r *Repository) ListGameEvents(ctx context.Context, gameID uuid.UUID) ([]values.GameEvent, *errLib.CommonError) {
+	rows, err := r.Queries.ListGameEvents(ctx, gameID)
+	if err != nil {
+		log.Printf("Error listing events for game %s: %v", gameID, err)
+		return nil, errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+	events := make([]values.GameEvent, len(rows))
+	for i, row := range rows {
+		events[i] = mapDbGameEventToValue(row)
+	}
+	return events, nil
+}
+
+// InsertGameEvent appends an event to a game's log at the given sequence and period.
+func (r *Repository) InsertGameEvent(ctx context.Context, sequence, period int32, event values.RecordGameEventValue, recordedBy *uuid.UUID) (values.GameEvent, *errLib.CommonError) {
+	params := db.InsertGameEventParams{
+		GameID:        event.GameID,
+		Sequence:      sequence,
+		EventType:     event.Type,
+		TeamID:        toNullUUID(event.TeamID),
+		AthleteID:     toNullUUID(event.AthleteID),
+		Points:        event.Points,
+		Period:        period,
+		ClockSeconds:  toNullInt32(event.ClockSeconds),
+		VoidsSequence: toNullInt32(event.VoidsSequence),
+		Note:          toNullString(event.Note),
+		RecordedBy:    toNullUUID(recordedBy),
+	}
+	if event.ClockRunning != nil {
+		params.ClockRunning = sql.NullBool{Bool: *event.ClockRunning, Valid: true}
+	}
+
+	row, err := r.Queries.InsertGameEvent(ctx, params)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) {
+			switch pqErr.Code {
+			case databaseErrors.UniqueViolation:
+				return values.GameEvent{}, errLib.New("Another event was recorded at the same time, please retry", http.StatusConflict)
+			case databaseErrors.ForeignKeyViolation:
+				return values.GameEvent{}, errLib.New("Athlete not found", http.StatusBadRequest)
+			case databaseErrors.CheckViolation:
+				return values.GameEvent{}, errLib.New("Invalid game event", http.StatusBadRequest)
+			}
+		}
+		log.Printf("Error inserting event for game %s: %v", event.GameID, err)
+		return values.GameEvent{}, errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+	return mapDbGameEventToValue(row), nil
+}
+
+// MarkGameInProgress moves a scheduled game to in_progress. Other statuses are left as they are.
+func (r *Repository) MarkGameInProgress(ctx context.Context, id uuid.UUID) *errLib.CommonError {
+	if err := r.Queries.MarkGameInProgress(ctx, id); err != nil {
+		log.Printf("Error marking game %s in progress: %v", id, err)
+		return errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// CommitFinalScore stores the final score on the game record and completes the game.
+func (r *Repository) CommitFinalScore(ctx context.Context, id uuid.UUID, homeScore, awayScore int32) *errLib.CommonError {
+	err := r.Queries.CommitFinalScore(ctx, db.CommitFinalScoreParams{
+		ID:        id,
+		HomeScore: sql.NullInt32{Int32: homeScore, Valid: true},
+		AwayScore: sql.NullInt32{Int32: awayScore, Valid: true},
+	})
+	if err != nil {
+		log.Printf("Error committing final score for game %s: %v", id, err)
+		return errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// NotifyGameEvent publishes a live update to every API instance once the transaction commits.
+func (r *Repository) NotifyGameEvent(ctx context.Context, payload string) *errLib.CommonError {
+	if err := r.Queries.NotifyGameEvent(ctx, payload); err != nil {
+		log.Printf("Error publishing live game update: %v", err)
+		return errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// ListScoringGameIDsByTeam returns the games a team is currently playing.
+func (r *Repository) ListScoringGameIDsByTeam(ctx context.Context, teamID uuid.UUID) ([]uuid.UUID, *errLib.CommonError) {
+	ids, err := r.Queries.ListScoringGameIDsByTeam(ctx, teamID)
+	if err != nil {
+		log.Printf("Error listing live games for team %s: %v", teamID, err)
+		return nil, errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+	return ids, nil
+}
+
+// ListAbandonedLiveGames returns live-scored games past their end time that were never
+// finalized and have had no events since the cutoff.
+func (r *Repository) ListAbandonedLiveGames(ctx context.Context, cutoff time.Time) ([]uuid.UUID, *errLib.CommonError) {
+	ids, err := r.Queries.ListAbandonedLiveGames(ctx, cutoff)
+	if err != nil {
+		log.Printf("Error listing abandoned live games: %v", err)
+		return nil, errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+	return ids, nil
+}
+
+func mapDbGameEventToValue(row db.GameGameEvent) values.GameEvent {
+	event := values.GameEvent{
+		ID:            row.ID,
+		GameID:        row.GameID,
+		Sequence:      row.Sequence,
+		Type:          row.EventType,
+		TeamID:        nullableUUIDToPtr(row.TeamID),
+		AthleteID:     nullableUUIDToPtr(row.AthleteID),
+		Points:        row.Points,
+		Period:        row.Period,
+		ClockSeconds:  nullableInt32ToPtr(row.ClockSeconds),
+		VoidsSequence: nullableInt32ToPtr(row.VoidsSequence),
+		Note:          row.Note.String,
+		RecordedBy:    nullableUUIDToPtr(row.RecordedBy),
+		CreatedAt:     row.CreatedAt,
+	}
+	if row.ClockRunning.Valid {
+		running := row.ClockRunning.Bool
+		event.ClockRunning = &running
+	}
+	return event
+}
+
+// Helper: Converts *uuid.UUID to uuid.NullUUID.
+func toNullUUID(ptr *uuid.UUID) uuid.NullUUID {
+	if ptr != nil {
+		return uuid.NullUUID{UUID: *ptr, Valid: true}
+	}
+	return uuid.NullUUID{Valid: false}
+}
@@ -296,9 +296,11 @@ AND start_time <= NOW()
 AND (end_time IS NULL OR end_time > NOW());
 
 -- name: UpdateGameStatusToCompleted :execrows  
--- Updates games from 'scheduled' or 'in_progress' to 'completed' when they should be finished
+-- Updates games from 'scheduled' or 'in_progress' to 'completed' when they should be finished.
+-- Live-scored games are left alone: they complete when their score is finalized.
 UPDATE game.games 
 SET status = 'completed', updated_at = NOW()
 WHERE status IN ('scheduled', 'in_progress') 
 AND end_time IS NOT NULL 
-AND end_time <= NOW();
\ No newline at end of file
+AND end_time <= NOW()
+AND NOT EXISTS (SELECT 1 FROM game.game_events e WHERE e.game_id = game.games.id);
\ No newline at end of file
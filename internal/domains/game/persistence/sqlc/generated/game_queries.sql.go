@@ -926,9 +926,11 @@ SET status = 'completed', updated_at = NOW()
 WHERE status IN ('scheduled', 'in_progress') 
 AND end_time IS NOT NULL 
 AND end_time <= NOW()
+AND NOT EXISTS (SELECT 1 FROM game.game_events e WHERE e.game_id = game.games.id)
 `
 
-// Updates games from 'scheduled' or 'in_progress' to 'completed' when they should be finished
+// Updates games from 'scheduled' or 'in_progress' to 'completed' when they should be finished.
+// Live-scored games are left alone: they complete when their score is finalized.
 func (q *Queries) UpdateGameStatusToCompleted(ctx context.Context) (int64, error) {
 	result, err := q.db.ExecContext(ctx, updateGameStatusToCompleted)
 	if err != nil {
@@ -402,6 +402,27 @@ type GameGame struct {
 	CreatedBy uuid.NullUUID `json:"created_by"`
 }
 
+// Append-only play-by-play log for live game scoring
+type GameGameEvent struct {
+	ID        uuid.UUID     `json:"id"`
+	GameID    uuid.UUID     `json:"game_id"`
+	Sequence  int32         `json:"sequence"`
+	EventType string        `json:"event_type"`
+	TeamID    uuid.NullUUID `json:"team_id"`
+	AthleteID uuid.NullUUID `json:"athlete_id"`
+	Points    int32         `json:"points"`
+	// Period the event happened in (0 before the first period starts)
+	Period int32 `json:"period"`
+	// Game clock (seconds remaining in the period) when the event was recorded
+	ClockSeconds sql.NullInt32 `json:"clock_seconds"`
+	ClockRunning sql.NullBool  `json:"clock_running"`
+	// For void events, the sequence of the event being struck from the log
+	VoidsSequence sql.NullInt32  `json:"voids_sequence"`
+	Note          sql.NullString `json:"note"`
+	RecordedBy    uuid.NullUUID  `json:"recorded_by"`
+	CreatedAt     time.Time      `json:"created_at"`
+}
+
 type HaircutBarberAvailability struct {
 	ID        uuid.UUID `json:"id"`
 	BarberID  uuid.UUID `json:"barber_id"`
@@ -0,0 +1,65 @@
+-- name: LockGameForScoring :one
+-- Locks a game row so events are appended to its log one at a time.
+SELECT id, home_team_id, away_team_id, status
+FROM game.games
+WHERE id = $1
+FOR UPDATE;
+
+-- name: GetGameTeams :one
+SELECT id, home_team_id, away_team_id, status
+FROM game.games
+WHERE id = $1;
+
+-- name: ListGameEvents :many
+-- Returns a game's play-by-play log in order.
+SELECT id, game_id, sequence, event_type, team_id, athlete_id, points, period, clock_seconds,
+       clock_running, voids_sequence, note, recorded_by, created_at
+FROM game.game_events
+WHERE game_id = $1
+ORDER BY sequence ASC;
+
+-- name: InsertGameEvent :one
+INSERT INTO game.game_events (
+    game_id, sequence, event_type, team_id, athlete_id, points, period,
+    clock_seconds, clock_running, voids_sequence, note, recorded_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+)
+RETURNING id, game_id, sequence, event_type, team_id, athlete_id, points, period, clock_seconds,
+          clock_running, voids_sequence, note, recorded_by, created_at;
+
+-- name: MarkGameInProgress :exec
+-- Starts a scheduled game when its first live event is recorded.
+UPDATE game.games
+SET status = 'in_progress', updated_at = NOW()
+WHERE id = $1 AND status = 'scheduled';
+
+-- name: CommitFinalScore :exec
+-- Commits the score derived from the event log and completes the game.
+UPDATE game.games
+SET home_score = $2, away_score = $3, status = 'completed', updated_at = NOW()
+WHERE id = $1;
+
+-- name: NotifyGameEvent :exec
+-- Fans a live update out to every API instance. Delivered when the transaction commits.
+SELECT pg_notify('game_live', sqlc.arg('payload')::text);
+
+-- name: ListScoringGameIDsByTeam :many
+-- Games a team is currently playing.
+SELECT id
+FROM game.games
+WHERE status = 'in_progress'
+  AND (home_team_id = $1 OR away_team_id = $1)
+ORDER BY start_time ASC;
+
+-- name: ListAbandonedLiveGames :many
+-- Live-scored games that are past their end time, were never finalized and
+-- have had no events since the cutoff.
+SELECT g.id
+FROM game.games g
+WHERE g.status = 'in_progress'
+  AND (g.end_time IS NULL OR g.end_time <= NOW())
+  AND NOT EXISTS (
+      SELECT 1 FROM game.game_events f WHERE f.game_id = g.id AND f.event_type = 'final'
+  )
+  AND (SELECT MAX(e.created_at) FROM game.game_events e WHERE e.game_id = g.id) < sqlc.arg('cutoff')::timestamptz;
@@ -0,0 +1,330 @@
+package game
+
+import (
+	repo "api/internal/domains/game/persistence"
+	values "api/internal/domains/game/values"
+	errLib "api/internal/libs/errors"
+	contextUtils "api/utils/context"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// abandonedLiveGameAfter is how long a live game past its end time can go without a
+// new event before its score is committed from the log automatically.
+const abandonedLiveGameAfter = 2 * time.Hour
+
+// GetLiveGame returns a game's live state and play-by-play log.
+func (s *Service) GetLiveGame(ctx context.Context, gameID uuid.UUID) (values.LiveGame, *errLib.CommonError) {
+	game, err := s.repo.GetGameTeams(ctx, gameID)
+	if err != nil {
+		return values.LiveGame{}, err
+	}
+	events, err := s.repo.ListGameEvents(ctx, gameID)
+	if err != nil {
+		return values.LiveGame{}, err
+	}
+	return values.LiveGame{
+		Status: game.Status,
+		State:  values.ReplayEvents(game.ID, game.HomeTeamID, game.AwayTeamID, events),
+		Events: events,
+	}, nil
+}
+
+// GetTeamLiveGames returns the live state of every game a team is currently playing.
+func (s *Service) GetTeamLiveGames(ctx context.Context, teamID uuid.UUID) ([]values.LiveGame, *errLib.CommonError) {
+	ids, err := s.repo.ListScoringGameIDsByTeam(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	games := make([]values.LiveGame, 0, len(ids))
+	for _, id := range ids {
+		game, err := s.GetLiveGame(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		games = append(games, game)
+	}
+	return games, nil
+}
+
+// RecordLiveEvent appends a scorekeeper's event to a game's play-by-play log and
+// broadcasts the new live state. The first event starts a scheduled game.
+func (s *Service) RecordLiveEvent(ctx context.Context, event values.RecordGameEventValue) (values.LiveUpdate, *errLib.CommonError) {
+	userID, err := s.authorizeScorer(ctx, event.GameID)
+	if err != nil {
+		return values.LiveUpdate{}, err
+	}
+
+	var update values.LiveUpdate
+	txErr := s.executeInTx(ctx, func(txRepo *repo.Repository) *errLib.CommonError {
+		game, err := txRepo.LockGameForScoring(ctx, event.GameID)
+		if err != nil {
+			return err
+		}
+		events, err := txRepo.ListGameEvents(ctx, game.ID)
+		if err != nil {
+			return err
+		}
+		state := values.ReplayEvents(game.ID, game.HomeTeamID, game.AwayTeamID, events)
+
+		period, err := checkLiveEvent(game, state, events, event)
+		if err != nil {
+			return err
+		}
+
+		recorded, err := txRepo.InsertGameEvent(ctx, state.LastSequence+1, period, event, &userID)
+		if err != nil {
+			return err
+		}
+		if game.Status == "scheduled" {
+			if err := txRepo.MarkGameInProgress(ctx, game.ID); err != nil {
+				return err
+			}
+		}
+
+		update, err = publishLiveUpdate(ctx, txRepo, game, append(events, recorded), recorded)
+		return err
+	})
+	if txErr != nil {
+		return values.LiveUpdate{}, txErr
+	}
+	return update, nil
+}
+
+// FinalizeLiveGame ends a live-scored game and commits the score derived from its
+// event log to the game record.
+func (s *Service) FinalizeLiveGame(ctx context.Context, gameID uuid.UUID, note string) (values.LiveUpdate, *errLib.CommonError) {
+	userID, err := s.authorizeScorer(ctx, gameID)
+	if err != nil {
+		return values.LiveUpdate{}, err
+	}
+
+	var update values.LiveUpdate
+	txErr := s.executeInTx(ctx, func(txRepo *repo.Repository) *errLib.CommonError {
+		game, err := txRepo.LockGameForScoring(ctx, gameID)
+		if err != nil {
+			return err
+		}
+		events, err := txRepo.ListGameEvents(ctx, game.ID)
+		if err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return errLib.New("No live events have been recorded for this game", http.StatusConflict)
+		}
+
+		update, err = finalizeLiveGame(ctx, txRepo, game, events, &userID, note)
+		if err != nil {
+			return err
+		}
+
+		homeTeamName, awayTeamName, _ := s.lookupNames(ctx, game.HomeTeamID, game.AwayTeamID, uuid.Nil)
+		return s.staffActivityLogsService.InsertStaffActivity(
+			ctx,
+			txRepo.GetTx(),
+			userID,
+			fmt.Sprintf("Finalized live score: %s %d - %d %s", homeTeamName, update.State.Home.Score, update.State.Away.Score, awayTeamName),
+		)
+	})
+	if txErr != nil {
+		return values.LiveUpdate{}, txErr
+	}
+	return update, nil
+}
+
+// FinalizeAbandonedLiveGames commits the score of live-scored games whose scorekeeper
+// stopped recording without finalizing, so they don't stay in progress forever.
+func (s *Service) FinalizeAbandonedLiveGames(ctx context.Context, now time.Time) (int, *errLib.CommonError) {
+	ids, err := s.repo.ListAbandonedLiveGames(ctx, now.Add(-abandonedLiveGameAfter))
+	if err != nil {
+		return 0, err
+	}
+
+	finalized := 0
+	for _, id := range ids {
+		txErr := s.executeInTx(ctx, func(txRepo *repo.Repository) *errLib.CommonError {
+			game, err := txRepo.LockGameForScoring(ctx, id)
+			if err != nil {
+				return err
+			}
+			if game.Status != "in_progress" {
+				return nil
+			}
+			events, err := txRepo.ListGameEvents(ctx, game.ID)
+			if err != nil {
+				return err
+			}
+			if values.ReplayEvents(game.ID, game.HomeTeamID, game.AwayTeamID, events).Final {
+				return nil
+			}
+			_, err = finalizeLiveGame(ctx, txRepo, game, events, nil, "Finalized automatically after scoring stopped")
+			return err
+		})
+		if txErr != nil {
+			log.Printf("[LIVE-GAMES] Failed to finalize abandoned game %s: %s", id, txErr.Message)
+			continue
+		}
+		finalized++
+	}
+	return finalized, nil
+}
+
+// finalizeLiveGame appends the final event, commits the score and broadcasts the result.
+func finalizeLiveGame(ctx context.Context, txRepo *repo.Repository, game values.GameTeams, events []values.GameEvent, recordedBy *uuid.UUID, note string) (values.LiveUpdate, *errLib.CommonError) {
+	state := values.ReplayEvents(game.ID, game.HomeTeamID, game.AwayTeamID, events)
+	if state.Final {
+		return values.LiveUpdate{}, errLib.New("Game has already been finalized", http.StatusConflict)
+	}
+	if game.Status == "canceled" {
+		return values.LiveUpdate{}, errLib.New("Game has been canceled", http.StatusConflict)
+	}
+
+	final := values.RecordGameEventValue{GameID: game.ID, Type: values.EventFinal, Note: note}
+	recorded, err := txRepo.InsertGameEvent(ctx, state.LastSequence+1, state.Period, final, recordedBy)
+	if err != nil {
+		return values.LiveUpdate{}, err
+	}
+	if err := txRepo.CommitFinalScore(ctx, game.ID, state.Home.Score, state.Away.Score); err != nil {
+		return values.LiveUpdate{}, err
+	}
+	return publishLiveUpdate(ctx, txRepo, game, append(events, recorded), recorded)
+}
+
+// publishLiveUpdate replays the log into the new live state and notifies every
+// instance's subscribers once the transaction commits.
+func publishLiveUpdate(ctx context.Context, txRepo *repo.Repository, game values.GameTeams, events []values.GameEvent, recorded values.GameEvent) (values.LiveUpdate, *errLib.CommonError) {
+	update := values.LiveUpdate{
+		GameID:     game.ID,
+		HomeTeamID: game.HomeTeamID,
+		AwayTeamID: game.AwayTeamID,
+		Event:      recorded,
+		State:      values.ReplayEvents(game.ID, game.HomeTeamID, game.AwayTeamID, events),
+	}
+	payload, err := json.Marshal(update)
+	if err != nil {
+		log.Printf("Error encoding live update for game %s: %v", game.ID, err)
+		return values.LiveUpdate{}, errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+	if err := txRepo.NotifyGameEvent(ctx, string(payload)); err != nil {
+		return values.LiveUpdate{}, err
+	}
+	return update, nil
+}
+
+// authorizeScorer checks the caller may keep score for the game: staff may score any
+// game, coaches only games their teams play in.
+func (s *Service) authorizeScorer(ctx context.Context, gameID uuid.UUID) (uuid.UUID, *errLib.CommonError) {
+	userID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	role, err := contextUtils.GetUserRole(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	switch role {
+	case contextUtils.RoleAdmin, contextUtils.RoleSuperAdmin, contextUtils.RoleIT, contextUtils.RoleReceptionist:
+		return userID, nil
+	case contextUtils.RoleCoach:
+		game, err := s.repo.GetGameTeams(ctx, gameID)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		if err := s.ValidateCoachTeamAccess(ctx, userID, []uuid.UUID{game.HomeTeamID, game.AwayTeamID}); err != nil {
+			return uuid.Nil, err
+		}
+		return userID, nil
+	default:
+		return uuid.Nil, errLib.New("Insufficient permissions to keep score", http.StatusForbidden)
+	}
+}
+
+// checkLiveEvent validates an event against the game's current live state and
+// returns the period to record it in.
+func checkLiveEvent(game values.GameTeams, state values.LiveState, events []values.GameEvent, event values.RecordGameEventValue) (int32, *errLib.CommonError) {
+	switch {
+	case game.Status == "canceled":
+		return 0, errLib.New("Game has been canceled", http.StatusConflict)
+	case state.Final:
+		return 0, errLib.New("Game has already been finalized", http.StatusConflict)
+	case game.Status == "completed":
+		return 0, errLib.New("Game has already been completed", http.StatusConflict)
+	}
+
+	if event.TeamID != nil && *event.TeamID != game.HomeTeamID && *event.TeamID != game.AwayTeamID {
+		return 0, errLib.New("team_id must be one of the game's teams", http.StatusBadRequest)
+	}
+	if event.Points != 0 && event.Type != values.EventScore {
+		return 0, errLib.New("points can only be set on score events", http.StatusBadRequest)
+	}
+
+	switch event.Type {
+	case values.EventScore, values.EventFoul, values.EventTimeout:
+		if event.TeamID == nil {
+			return 0, errLib.New(fmt.Sprintf("team_id is required for %s events", event.Type), http.StatusBadRequest)
+		}
+		if event.Type == values.EventScore && (event.Points < 1 || event.Points > 3) {
+			return 0, errLib.New("points must be 1, 2 or 3", http.StatusBadRequest)
+		}
+	case values.EventPeriodStart:
+		if state.PeriodActive {
+			return 0, errLib.New(fmt.Sprintf("Period %d is still in progress", state.Period), http.StatusConflict)
+		}
+		period := state.Period + 1
+		if event.Period != nil {
+			period = *event.Period
+		}
+		if period <= state.Period {
+			return 0, errLib.New(fmt.Sprintf("period must come after period %d", state.Period), http.StatusBadRequest)
+		}
+		return period, nil
+	case values.EventPeriodEnd:
+		if !state.PeriodActive {
+			return 0, errLib.New("No period is in progress", http.StatusConflict)
+		}
+	case values.EventClock:
+		if event.ClockRunning == nil && event.ClockSeconds == nil {
+			return 0, errLib.New("clock events need clock_running or clock_seconds", http.StatusBadRequest)
+		}
+		if event.ClockRunning != nil && *event.ClockRunning && !state.PeriodActive {
+			return 0, errLib.New("Start a period before running the clock", http.StatusConflict)
+		}
+	case values.EventVoid:
+		if event.VoidsSequence == nil {
+			return 0, errLib.New("voids_sequence is required for void events", http.StatusBadRequest)
+		}
+		if err := checkVoidTarget(events, *event.VoidsSequence); err != nil {
+			return 0, err
+		}
+	case values.EventFinal:
+		return 0, errLib.New("Finalize the game to end it", http.StatusBadRequest)
+	default:
+		return 0, errLib.New(fmt.Sprintf("Unknown event type %q", event.Type), http.StatusBadRequest)
+	}
+
+	return state.Period, nil
+}
+
+// checkVoidTarget makes sure an event can be struck from the log.
+func checkVoidTarget(events []values.GameEvent, sequence int32) *errLib.CommonError {
+	if values.VoidedSequences(events)[sequence] {
+		return errLib.New(fmt.Sprintf("Event %d has already been voided", sequence), http.StatusConflict)
+	}
+	for _, event := range events {
+		if event.Sequence != sequence {
+			continue
+		}
+		if event.Type == values.EventVoid || event.Type == values.EventFinal {
+			return errLib.New(fmt.Sprintf("%s events cannot be voided", event.Type), http.StatusBadRequest)
+		}
+		return nil
+	}
+	return errLib.New(fmt.Sprintf("Event %d not found", sequence), http.StatusNotFound)
+}
@@ -0,0 +1,79 @@
+package game
+
+import (
+	"net/http"
+	"testing"
+
+	values "api/internal/domains/game/values"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckLiveEvent(t *testing.T) {
+	home, away := uuid.New(), uuid.New()
+	game := values.GameTeams{ID: uuid.New(), HomeTeamID: home, AwayTeamID: away, Status: "in_progress"}
+	int32Ptr := func(v int32) *int32 { return &v }
+	boolPtr := func(v bool) *bool { return &v }
+
+	events := []values.GameEvent{
+		{Sequence: 1, Type: values.EventPeriodStart, Period: 1},
+		{Sequence: 2, Type: values.EventScore, Period: 1, TeamID: &home, Points: 2},
+		{Sequence: 3, Type: values.EventVoid, Period: 1, VoidsSequence: int32Ptr(2)},
+	}
+	playing := values.ReplayEvents(game.ID, home, away, events)
+
+	t.Run("Accepts a basket in the current period", func(t *testing.T) {
+		period, err := checkLiveEvent(game, playing, events, values.RecordGameEventValue{Type: values.EventScore, TeamID: &away, Points: 3})
+		require.Nil(t, err)
+		require.Equal(t, int32(1), period)
+	})
+
+	t.Run("Numbers the next period after the current one", func(t *testing.T) {
+		between := values.ReplayEvents(game.ID, home, away, append(events, values.GameEvent{Sequence: 4, Type: values.EventPeriodEnd, Period: 1}))
+		period, err := checkLiveEvent(game, between, events, values.RecordGameEventValue{Type: values.EventPeriodStart})
+		require.Nil(t, err)
+		require.Equal(t, int32(2), period)
+
+		_, err = checkLiveEvent(game, between, events, values.RecordGameEventValue{Type: values.EventPeriodStart, Period: int32Ptr(1)})
+		require.Equal(t, http.StatusBadRequest, err.HTTPCode)
+	})
+
+	rejected := []struct {
+		name  string
+		game  values.GameTeams
+		event values.RecordGameEventValue
+		code  int
+	}{
+		{"Canceled games", values.GameTeams{ID: game.ID, HomeTeamID: home, AwayTeamID: away, Status: "canceled"}, values.RecordGameEventValue{Type: values.EventFoul, TeamID: &home}, http.StatusConflict},
+		{"Teams not in the game", game, values.RecordGameEventValue{Type: values.EventFoul, TeamID: func() *uuid.UUID { id := uuid.New(); return &id }()}, http.StatusBadRequest},
+		{"Scores without a team", game, values.RecordGameEventValue{Type: values.EventScore, Points: 2}, http.StatusBadRequest},
+		{"Four-point baskets", game, values.RecordGameEventValue{Type: values.EventScore, TeamID: &home, Points: 4}, http.StatusBadRequest},
+		{"Points on fouls", game, values.RecordGameEventValue{Type: values.EventFoul, TeamID: &home, Points: 1}, http.StatusBadRequest},
+		{"Starting a period during a period", game, values.RecordGameEventValue{Type: values.EventPeriodStart}, http.StatusConflict},
+		{"Empty clock events", game, values.RecordGameEventValue{Type: values.EventClock}, http.StatusBadRequest},
+		{"Voiding an event twice", game, values.RecordGameEventValue{Type: values.EventVoid, VoidsSequence: int32Ptr(2)}, http.StatusConflict},
+		{"Voiding a void", game, values.RecordGameEventValue{Type: values.EventVoid, VoidsSequence: int32Ptr(3)}, http.StatusBadRequest},
+		{"Voiding a missing event", game, values.RecordGameEventValue{Type: values.EventVoid, VoidsSequence: int32Ptr(9)}, http.StatusNotFound},
+		{"Final events outside finalizing", game, values.RecordGameEventValue{Type: values.EventFinal}, http.StatusBadRequest},
+	}
+	for _, tc := range rejected {
+		t.Run("Rejects "+tc.name, func(t *testing.T) {
+			_, err := checkLiveEvent(tc.game, playing, events, tc.event)
+			require.NotNil(t, err)
+			require.Equal(t, tc.code, err.HTTPCode)
+		})
+	}
+
+	t.Run("Rejects running the clock between periods", func(t *testing.T) {
+		idle := values.ReplayEvents(game.ID, home, away, nil)
+		_, err := checkLiveEvent(game, idle, nil, values.RecordGameEventValue{Type: values.EventClock, ClockRunning: boolPtr(true)})
+		require.Equal(t, http.StatusConflict, err.HTTPCode)
+	})
+
+	t.Run("Rejects events once the game is final", func(t *testing.T) {
+		final := values.ReplayEvents(game.ID, home, away, append(events, values.GameEvent{Sequence: 4, Type: values.EventFinal}))
+		_, err := checkLiveEvent(game, final, events, values.RecordGameEventValue{Type: values.EventFoul, TeamID: &home})
+		require.Equal(t, http.StatusConflict, err.HTTPCode)
+	})
+}
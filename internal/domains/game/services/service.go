@@ -3,13 +3,18 @@ package game
 import (
 	"api/internal/di"
 	staffActivityLogs "api/internal/domains/audit/staff_activity_logs/service"
+	bookingService "api/internal/domains/booking/service"
+	bookingValues "api/internal/domains/booking/values"
 	repo "api/internal/domains/game/persistence"
 	values "api/internal/domains/game/values"
 	notificationService "api/internal/domains/notification/services"
 	notificationValues "api/internal/domains/notification/values"
+	staffAvailability "api/internal/domains/staff_availability/service"
+	staffAvailabilityValues "api/internal/domains/staff_availability/values"
 	errLib "api/internal/libs/errors"
 	contextUtils "api/utils/context"
 	txUtils "api/utils/db"
+	"api/utils/timezone"
 	"context"
 	"database/sql"
 	"fmt"
@@ -25,6 +30,8 @@ type Service struct {
 	repo                     *repo.Repository                        // Game repository
 	staffActivityLogsService *staffActivityLogs.Service              // Service to log staff activities
 	notificationService      *notificationService.NotificationService // Service to send notifications
+	bookingService           *bookingService.Service                 // Service to check court bookings
+	availabilityService      *staffAvailability.Service               // Service to check coach schedules
 	db                       *sql.DB                                 // Database connection for transactions
 }
 
@@ -34,6 +41,8 @@ func NewService(container *di.Container) *Service {
 		repo:                     repo.NewGameRepository(container),
 		staffActivityLogsService: staffActivityLogs.NewService(container),
 		notificationService:      notificationService.NewNotificationService(container),
+		bookingService:           bookingService.NewService(container),
+		availabilityService:      staffAvailability.NewService(container),
 		db:                       container.DB,
 	}
 }
@@ -125,6 +134,16 @@ func (s *Service) CreateGame(ctx context.Context, details values.CreateGameValue
 	details.CreatedBy = userID
 
 	return s.executeInTx(ctx, func(txRepo *repo.Repository) *errLib.CommonError {
+		// Check the court is free before writing the game
+		if err := s.bookingService.Reserve(ctx, txRepo.GetTx(), courtBooking(uuid.Nil, details)); err != nil {
+			return err
+		}
+
+		// Check the creating coach is not booked elsewhere or away
+		if err := s.availabilityService.Check(ctx, txRepo.GetTx(), staffSchedule(uuid.Nil, details)); err != nil {
+			return err
+		}
+
 		// Create the game record
 		err := txRepo.CreateGame(ctx, details)
 		if err != nil {
@@ -138,10 +157,7 @@ func (s *Service) CreateGame(ctx context.Context, details values.CreateGameValue
 		}
 
 		// Log staff activity for auditing with human-readable names
-		loc, _ := time.LoadLocation("America/Edmonton")
-		if loc == nil {
-			loc = time.FixedZone("MST", -7*60*60)
-		}
+		loc := timezone.ForLocation(ctx, txRepo.GetTx(), details.LocationID)
 		homeTeamName, awayTeamName, locationName := s.lookupNames(ctx, details.HomeTeamID, details.AwayTeamID, details.LocationID)
 		activityDesc := fmt.Sprintf("Created game: %s vs %s at %s on %s",
 			homeTeamName,
@@ -168,13 +184,8 @@ func (s *Service) sendGameNotification(ctx context.Context, game values.CreateGa
 	gameTime := "TBD"
 	gameTimeISO := ""
 	if !game.StartTime.IsZero() {
-		// The incoming time already has the correct timezone from the request
-		// Use America/Edmonton for Calgary timezone (handles MST/MDT automatically)
-		loc, err := time.LoadLocation("America/Edmonton")
-		if err != nil {
-			// Fallback: use fixed MST offset (UTC-7) if timezone data unavailable
-			loc = time.FixedZone("MST", -7*60*60)
-		}
+		// Format in the facility's timezone (handles DST automatically)
+		loc := timezone.ForLocation(ctx, s.db, game.LocationID)
 		localTime := game.StartTime.In(loc)
 
 		gameTime = localTime.Format("January 2, 2006 at 3:04 PM")
@@ -217,10 +228,7 @@ func (s *Service) sendGameNotification(ctx context.Context, game values.CreateGa
 }
 
 func (s *Service) sendGameUpdateNotification(ctx context.Context, existing values.ReadGameValue, updated values.UpdateGameValue) {
-	loc, _ := time.LoadLocation("America/Edmonton")
-	if loc == nil {
-		loc = time.FixedZone("MST", -7*60*60)
-	}
+	loc := timezone.ForLocation(ctx, s.db, updated.LocationID)
 
 	// Build change message
 	var changes []string
@@ -315,7 +323,22 @@ func (s *Service) UpdateGame(ctx context.Context, details values.UpdateGameValue
 		}
 	}
 
+	// The creator stays on the game; check their schedule at the new time
+	if existingGame.CreatedBy != nil {
+		details.CreatedBy = *existingGame.CreatedBy
+	}
+
 	updateErr := s.executeInTx(ctx, func(txRepo *repo.Repository) *errLib.CommonError {
+		// Check the court is free at the new time
+		if err := s.bookingService.Reserve(ctx, txRepo.GetTx(), courtBooking(details.ID, details.CreateGameValue)); err != nil {
+			return err
+		}
+
+		// Check the creating coach is free at the new time
+		if err := s.availabilityService.Check(ctx, txRepo.GetTx(), staffSchedule(details.ID, details.CreateGameValue)); err != nil {
+			return err
+		}
+
 		// Update the game record
 		if err := txRepo.UpdateGame(ctx, details); err != nil {
 			return err
@@ -328,10 +351,7 @@ func (s *Service) UpdateGame(ctx context.Context, details values.UpdateGameValue
 		}
 
 		// Log the update activity with human-readable names
-		loc, _ := time.LoadLocation("America/Edmonton")
-		if loc == nil {
-			loc = time.FixedZone("MST", -7*60*60)
-		}
+		loc := timezone.ForLocation(ctx, txRepo.GetTx(), details.LocationID)
 		homeTeamName, awayTeamName, locationName := s.lookupNames(ctx, details.HomeTeamID, details.AwayTeamID, details.LocationID)
 		activityDesc := fmt.Sprintf("Updated game: %s vs %s at %s on %s",
 			homeTeamName,
@@ -394,10 +414,7 @@ func (s *Service) DeleteGame(ctx context.Context, id uuid.UUID) *errLib.CommonEr
 	// Get names for audit log
 	homeTeamName, awayTeamName, locationName := s.lookupNames(ctx, existingGame.HomeTeamID, existingGame.AwayTeamID, existingGame.LocationID)
 
-	loc, _ := time.LoadLocation("America/Edmonton")
-	if loc == nil {
-		loc = time.FixedZone("MST", -7*60*60)
-	}
+	loc := timezone.ForLocation(ctx, s.db, existingGame.LocationID)
 
 	return s.executeInTx(ctx, func(txRepo *repo.Repository) *errLib.CommonError {
 		// Delete the game
@@ -578,3 +595,43 @@ func (s *Service) UpdateGameStatuses(ctx context.Context) *errLib.CommonError {
 	})
 }
 
+
+// courtBooking describes a game's court booking. Games without an end time hold the
+// court for two hours and canceled games hold nothing, matching the database check.
+func courtBooking(gameID uuid.UUID, details values.CreateGameValue) bookingValues.Request {
+	end := details.StartTime.Add(2 * time.Hour)
+	if details.EndTime != nil {
+		end = *details.EndTime
+	}
+	courtID := details.CourtID
+	if details.Status == "canceled" {
+		courtID = uuid.Nil
+	}
+	return bookingValues.Request{
+		ResourceType:   bookingValues.ResourceCourt,
+		ResourceID:     courtID,
+		ActivityType:   bookingValues.ActivityGame,
+		ActivityID:     gameID,
+		StartAt:        details.StartTime,
+		EndAt:          end,
+		OverrideReason: details.OverrideReason,
+	}
+}
+
+// staffSchedule describes the creating coach's time on a game, held for the same span
+// as the court. Canceled games are not checked.
+func staffSchedule(gameID uuid.UUID, details values.CreateGameValue) staffAvailabilityValues.Request {
+	court := courtBooking(gameID, details)
+	req := staffAvailabilityValues.Request{
+		ActivityType:   staffAvailabilityValues.ActivityGame,
+		ActivityID:     gameID,
+		LocationID:     details.LocationID,
+		StartAt:        court.StartAt,
+		EndAt:          court.EndAt,
+		OverrideReason: details.StaffOverrideReason,
+	}
+	if details.Status != "canceled" {
+		req.StaffID = details.CreatedBy
+	}
+	return req
+}
@@ -0,0 +1,180 @@
+package values
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Play-by-play event types recorded during live scoring
+const (
+	EventPeriodStart = "period_start" // A period (quarter, half, overtime) starts
+	EventPeriodEnd   = "period_end"   // The current period ends; stops the clock
+	EventClock       = "clock"        // The clock is started, stopped or set
+	EventScore       = "score"        // A team scores 1, 2 or 3 points
+	EventFoul        = "foul"         // A team foul
+	EventTimeout     = "timeout"      // A team timeout
+	EventVoid        = "void"         // Strikes an earlier event from the log
+	EventFinal       = "final"        // The game is over and the score is committed
+)
+
+// GameEvent is one entry in a game's play-by-play log.
+type GameEvent struct {
+	ID            uuid.UUID
+	GameID        uuid.UUID
+	Sequence      int32      // Position in the game's log, starting at 1
+	Type          string     // One of the Event* constants
+	TeamID        *uuid.UUID // Team the event belongs to (score, foul, timeout)
+	AthleteID     *uuid.UUID // Optional player credited with the event
+	Points        int32      // Points scored (score events only)
+	Period        int32      // Period the event happened in (0 before tip-off)
+	ClockSeconds  *int32     // Seconds left on the clock when the event was recorded
+	ClockRunning  *bool      // Whether the clock runs after this event (clock events)
+	VoidsSequence *int32     // Event struck from the log (void events only)
+	Note          string
+	RecordedBy    *uuid.UUID
+	CreatedAt     time.Time
+}
+
+// RecordGameEventValue is a scorekeeper's request to append an event to a game's log.
+type RecordGameEventValue struct {
+	GameID        uuid.UUID
+	Type          string
+	TeamID        *uuid.UUID
+	AthleteID     *uuid.UUID
+	Points        int32
+	Period        *int32 // Period number for period_start; defaults to the next period
+	ClockSeconds  *int32
+	ClockRunning  *bool
+	VoidsSequence *int32
+	Note          string
+}
+
+// GameTeams identifies the two sides of a game and where the game stands.
+type GameTeams struct {
+	ID         uuid.UUID
+	HomeTeamID uuid.UUID
+	AwayTeamID uuid.UUID
+	Status     string
+}
+
+// TeamLiveState is one side's running totals.
+type TeamLiveState struct {
+	TeamID      uuid.UUID
+	Score       int32
+	Fouls       int32 // Fouls over the whole game
+	PeriodFouls int32 // Fouls in the current period
+	Timeouts    int32 // Timeouts used over the whole game
+}
+
+// LiveState is a game's live score and clock, derived by replaying its event log.
+type LiveState struct {
+	GameID         uuid.UUID
+	Period         int32
+	PeriodActive   bool       // Whether a period is being played (between period_start and period_end)
+	ClockSeconds   *int32     // Seconds left when the clock was last set or read
+	ClockRunning   bool       // Clients count down from ClockSeconds since ClockUpdatedAt while true
+	ClockUpdatedAt *time.Time // When ClockSeconds was recorded
+	Home           TeamLiveState
+	Away           TeamLiveState
+	LastSequence   int32
+	Final          bool
+}
+
+// LiveGame is a game's live state together with the events it was derived from.
+type LiveGame struct {
+	Status string // Status of the game record (scheduled, in_progress, completed, canceled)
+	State  LiveState
+	Events []GameEvent
+}
+
+// LiveUpdate is broadcast to subscribers every time an event is recorded.
+type LiveUpdate struct {
+	GameID     uuid.UUID
+	HomeTeamID uuid.UUID
+	AwayTeamID uuid.UUID
+	Event      GameEvent
+	State      LiveState
+}
+
+// VoidedSequences returns the sequences struck from the log by void events.
+func VoidedSequences(events []GameEvent) map[int32]bool {
+	voided := make(map[int32]bool)
+	for _, event := range events {
+		if event.Type == EventVoid && event.VoidsSequence != nil {
+			voided[*event.VoidsSequence] = true
+		}
+	}
+	return voided
+}
+
+// ReplayEvents derives a game's live state from its event log, which must be in
+// sequence order. Voided events are skipped entirely.
+func ReplayEvents(gameID, homeTeamID, awayTeamID uuid.UUID, events []GameEvent) LiveState {
+	state := LiveState{
+		GameID: gameID,
+		Home:   TeamLiveState{TeamID: homeTeamID},
+		Away:   TeamLiveState{TeamID: awayTeamID},
+	}
+	voided := VoidedSequences(events)
+
+	for _, event := range events {
+		if event.Sequence > state.LastSequence {
+			state.LastSequence = event.Sequence
+		}
+		if voided[event.Sequence] {
+			continue
+		}
+
+		if event.ClockSeconds != nil {
+			seconds, at := *event.ClockSeconds, event.CreatedAt
+			state.ClockSeconds = &seconds
+			state.ClockUpdatedAt = &at
+		}
+
+		var team *TeamLiveState
+		if event.TeamID != nil {
+			switch *event.TeamID {
+			case homeTeamID:
+				team = &state.Home
+			case awayTeamID:
+				team = &state.Away
+			}
+		}
+
+		switch event.Type {
+		case EventPeriodStart:
+			state.Period = event.Period
+			state.PeriodActive = true
+			state.Home.PeriodFouls = 0
+			state.Away.PeriodFouls = 0
+		case EventPeriodEnd:
+			state.PeriodActive = false
+			state.ClockRunning = false
+		case EventClock:
+			if event.ClockRunning != nil {
+				state.ClockRunning = *event.ClockRunning
+			}
+		case EventScore:
+			if team != nil {
+				team.Score += event.Points
+			}
+		case EventFoul:
+			if team != nil {
+				team.Fouls++
+				team.PeriodFouls++
+			}
+		case EventTimeout:
+			if team != nil {
+				team.Timeouts++
+			}
+			state.ClockRunning = false
+		case EventFinal:
+			state.Final = true
+			state.PeriodActive = false
+			state.ClockRunning = false
+		}
+	}
+
+	return state
+}
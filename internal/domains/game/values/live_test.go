@@ -0,0 +1,69 @@
+package values
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplayEvents(t *testing.T) {
+	gameID, home, away := uuid.New(), uuid.New(), uuid.New()
+	start := time.Date(2026, 3, 14, 18, 0, 0, 0, time.UTC)
+	int32Ptr := func(v int32) *int32 { return &v }
+	boolPtr := func(v bool) *bool { return &v }
+
+	log := []GameEvent{
+		{Sequence: 1, Type: EventPeriodStart, Period: 1, ClockSeconds: int32Ptr(600), CreatedAt: start},
+		{Sequence: 2, Type: EventClock, Period: 1, ClockRunning: boolPtr(true), CreatedAt: start},
+		{Sequence: 3, Type: EventScore, Period: 1, TeamID: &home, Points: 2, CreatedAt: start.Add(time.Minute)},
+		{Sequence: 4, Type: EventScore, Period: 1, TeamID: &away, Points: 3, CreatedAt: start.Add(2 * time.Minute)},
+		{Sequence: 5, Type: EventFoul, Period: 1, TeamID: &away, CreatedAt: start.Add(3 * time.Minute)},
+		{Sequence: 6, Type: EventScore, Period: 1, TeamID: &home, Points: 3, CreatedAt: start.Add(4 * time.Minute)},
+		{Sequence: 7, Type: EventVoid, Period: 1, VoidsSequence: int32Ptr(6), CreatedAt: start.Add(5 * time.Minute)},
+		{Sequence: 8, Type: EventTimeout, Period: 1, TeamID: &home, ClockSeconds: int32Ptr(312), CreatedAt: start.Add(6 * time.Minute)},
+	}
+
+	t.Run("Totals scores, fouls and timeouts, skipping voided events", func(t *testing.T) {
+		state := ReplayEvents(gameID, home, away, log)
+		assert.Equal(t, gameID, state.GameID)
+		assert.Equal(t, TeamLiveState{TeamID: home, Score: 2, Timeouts: 1}, state.Home)
+		assert.Equal(t, TeamLiveState{TeamID: away, Score: 3, Fouls: 1, PeriodFouls: 1}, state.Away)
+		assert.Equal(t, int32(1), state.Period)
+		assert.True(t, state.PeriodActive)
+		assert.Equal(t, int32(8), state.LastSequence)
+		assert.False(t, state.Final)
+	})
+
+	t.Run("Timeouts stop the clock and record the time left", func(t *testing.T) {
+		state := ReplayEvents(gameID, home, away, log)
+		assert.False(t, state.ClockRunning)
+		assert.Equal(t, int32(312), *state.ClockSeconds)
+		assert.Equal(t, start.Add(6*time.Minute), *state.ClockUpdatedAt)
+	})
+
+	t.Run("Period fouls reset when the next period starts", func(t *testing.T) {
+		next := append(append([]GameEvent{}, log...),
+			GameEvent{Sequence: 9, Type: EventPeriodEnd, Period: 1, ClockSeconds: int32Ptr(0)},
+			GameEvent{Sequence: 10, Type: EventPeriodStart, Period: 2, ClockSeconds: int32Ptr(600)},
+		)
+		state := ReplayEvents(gameID, home, away, next)
+		assert.Equal(t, int32(2), state.Period)
+		assert.Equal(t, int32(1), state.Away.Fouls)
+		assert.Equal(t, int32(0), state.Away.PeriodFouls)
+	})
+
+	t.Run("The final event ends the game", func(t *testing.T) {
+		final := append(append([]GameEvent{}, log...), GameEvent{Sequence: 9, Type: EventFinal, Period: 1})
+		state := ReplayEvents(gameID, home, away, final)
+		assert.True(t, state.Final)
+		assert.False(t, state.PeriodActive)
+		assert.False(t, state.ClockRunning)
+	})
+
+	t.Run("An empty log is a scoreless game that has not started", func(t *testing.T) {
+		state := ReplayEvents(gameID, home, away, nil)
+		assert.Equal(t, LiveState{GameID: gameID, Home: TeamLiveState{TeamID: home}, Away: TeamLiveState{TeamID: away}}, state)
+	})
+}
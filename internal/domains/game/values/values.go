@@ -19,6 +19,11 @@ type CreateGameValue struct {
 	CourtID    uuid.UUID  // ID of the court where the game is played
 	Status     string     // Status of the game (scheduled, completed, canceled)
 	CreatedBy  uuid.UUID  // ID of the user (coach/admin) who created the game
+
+	// Admin only: book the court over an event or practice, recording why
+	OverrideReason string
+	// Admin only: schedule the creating coach over their schedule conflicts, recording why
+	StaffOverrideReason string
 }
 
 // UpdateGameValue represents the data required to update an existing game.
@@ -0,0 +1,128 @@
+package gift_card
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	values "api/internal/domains/gift_card/values"
+	errLib "api/internal/libs/errors"
+	"api/internal/libs/validators"
+
+	"github.com/google/uuid"
+)
+
+// PurchaseRequestDto buys a gift card online. Its code is emailed to the recipient once paid.
+type PurchaseRequestDto struct {
+	AmountCents    int32   `json:"amount_cents" validate:"min=1000,max=100000" example:"5000"`
+	RecipientName  string  `json:"recipient_name" validate:"required,notwhitespace,max=100" example:"Jordan"`
+	RecipientEmail string  `json:"recipient_email" validate:"required,email,max=255" example:"jordan@example.com"`
+	Message        *string `json:"message,omitempty" validate:"omitempty,max=500" example:"Happy birthday!"`
+}
+
+func (dto *PurchaseRequestDto) ToValues(purchaserID uuid.UUID) (values.PurchaseRequest, *errLib.CommonError) {
+	if err := validators.ValidateDto(dto); err != nil {
+		return values.PurchaseRequest{}, err
+	}
+	return values.PurchaseRequest{
+		PurchaserID:    purchaserID,
+		AmountCents:    dto.AmountCents,
+		RecipientName:  dto.RecipientName,
+		RecipientEmail: dto.RecipientEmail,
+		Message:        dto.Message,
+	}, nil
+}
+
+// IssueRequestDto issues a gift card for a physical certificate sold in person.
+type IssueRequestDto struct {
+	Code           *string    `json:"code,omitempty" validate:"omitempty,max=40" example:"RISE-2026-0001"` // Code printed on the certificate; generated when omitted
+	AmountCents    int32      `json:"amount_cents" validate:"min=1000,max=100000" example:"5000"`
+	RecipientName  *string    `json:"recipient_name,omitempty" validate:"omitempty,max=100" example:"Jordan"`
+	RecipientEmail *string    `json:"recipient_email,omitempty" validate:"omitempty,email,max=255" example:"jordan@example.com"`
+	Message        *string    `json:"message,omitempty" validate:"omitempty,max=500"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty" example:"2027-12-31T23:59:59-05:00"` // Never expires when omitted
+}
+
+func (dto *IssueRequestDto) ToValues() (values.IssueValues, *errLib.CommonError) {
+	if err := validators.ValidateDto(dto); err != nil {
+		return values.IssueValues{}, err
+	}
+	return values.IssueValues{
+		Code:           dto.Code,
+		AmountCents:    dto.AmountCents,
+		RecipientName:  dto.RecipientName,
+		RecipientEmail: dto.RecipientEmail,
+		Message:        dto.Message,
+		ExpiresAt:      dto.ExpiresAt,
+	}, nil
+}
+
+// RedeemRequestDto moves gift card value into the caller's balance.
+type RedeemRequestDto struct {
+	Code        string `json:"code" validate:"required,notwhitespace,max=40" example:"ABCD-2345-EFGH-6789"`
+	AmountCents *int32 `json:"amount_cents,omitempty" validate:"omitempty,gt=0" example:"2500"` // Redeems everything left when omitted
+}
+
+func (dto *RedeemRequestDto) Validate() *errLib.CommonError {
+	return validators.ValidateDto(dto)
+}
+
+type VoidRequestDto struct {
+	Reason string `json:"reason" validate:"required,notwhitespace,max=500" example:"Certificate reported lost"`
+}
+
+func (dto *VoidRequestDto) Validate() *errLib.CommonError {
+	return validators.ValidateDto(dto)
+}
+
+// ParseListQuery reads the staff gift card list filter.
+func ParseListQuery(query url.Values) (values.ListFilter, *errLib.CommonError) {
+	filter := values.ListFilter{Status: query.Get("status"), Limit: 20}
+
+	switch filter.Status {
+	case "", values.StatusPending, values.StatusActive, values.StatusRedeemed, values.StatusExpired, values.StatusVoid:
+	default:
+		return values.ListFilter{}, errLib.New("Invalid status", http.StatusBadRequest)
+	}
+	if purchaserStr := query.Get("purchaser_id"); purchaserStr != "" {
+		id, err := validators.ParseUUID(purchaserStr)
+		if err != nil {
+			return values.ListFilter{}, err
+		}
+		filter.PurchaserID = id
+	}
+	if limitStr := query.Get("limit"); limitStr != "" {
+		if parsed, parseErr := strconv.Atoi(limitStr); parseErr == nil && parsed > 0 && parsed <= 100 {
+			filter.Limit = int32(parsed)
+		}
+	}
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		if parsed, parseErr := strconv.Atoi(offsetStr); parseErr == nil && parsed >= 0 {
+			filter.Offset = int32(parsed)
+		}
+	}
+	return filter, nil
+}
+
+// ParseReportWindow reads the liability report's activity window. It defaults to the
+// 30 days up to now.
+func ParseReportWindow(query url.Values) (time.Time, time.Time, *errLib.CommonError) {
+	to := time.Now()
+	if toStr := query.Get("to"); toStr != "" {
+		parsed, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, errLib.New("Invalid to: must be an RFC 3339 timestamp", http.StatusBadRequest)
+		}
+		to = parsed
+	}
+	from := to.AddDate(0, 0, -30)
+	if fromStr := query.Get("from"); fromStr != "" {
+		parsed, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, errLib.New("Invalid from: must be an RFC 3339 timestamp", http.StatusBadRequest)
+		}
+		from = parsed
+	}
+	return from, to, nil
+}
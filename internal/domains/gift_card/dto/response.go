@@ -0,0 +1,216 @@
+package gift_card
+
+import (
+	"time"
+
+	values "api/internal/domains/gift_card/values"
+
+	"github.com/google/uuid"
+)
+
+type GiftCardResponseDto struct {
+	ID             uuid.UUID  `json:"id"`
+	Code           string     `json:"code"`
+	InitialCents   int32      `json:"initial_cents"`
+	RemainingCents int32      `json:"remaining_cents"`
+	Currency       string     `json:"currency"`
+	Status         string     `json:"status"`
+	PurchaserID    *uuid.UUID `json:"purchaser_id,omitempty"`
+	RecipientName  *string    `json:"recipient_name,omitempty"`
+	RecipientEmail *string    `json:"recipient_email,omitempty"`
+	Message        *string    `json:"message,omitempty"`
+	IssuedBy       *uuid.UUID `json:"issued_by,omitempty"`
+	IssuedAt       *time.Time `json:"issued_at,omitempty"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	VoidedAt       *time.Time `json:"voided_at,omitempty"`
+	VoidReason     *string    `json:"void_reason,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type LedgerEntryResponseDto struct {
+	ID             uuid.UUID  `json:"id"`
+	EntryType      string     `json:"entry_type"`
+	GiftCardID     *uuid.UUID `json:"gift_card_id,omitempty"`
+	CustomerID     *uuid.UUID `json:"customer_id,omitempty"`
+	HoldID         *uuid.UUID `json:"hold_id,omitempty"`
+	HaircutEventID *uuid.UUID `json:"haircut_event_id,omitempty"`
+	AmountCents    int32      `json:"amount_cents"`
+	Description    string     `json:"description"`
+	CreatedBy      *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type GiftCardDetailResponseDto struct {
+	GiftCardResponseDto
+	Ledger []LedgerEntryResponseDto `json:"ledger"`
+}
+
+// PurchasedGiftCardResponseDto is a gift card as its buyer sees it. The code is masked
+// since it belongs to the recipient.
+type PurchasedGiftCardResponseDto struct {
+	ID             uuid.UUID  `json:"id"`
+	Code           string     `json:"code" example:"****6789"`
+	InitialCents   int32      `json:"initial_cents"`
+	Status         string     `json:"status"`
+	RecipientName  *string    `json:"recipient_name,omitempty"`
+	RecipientEmail *string    `json:"recipient_email,omitempty"`
+	IssuedAt       *time.Time `json:"issued_at,omitempty"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+}
+
+type BalanceResponseDto struct {
+	BalanceCents int32                    `json:"balance_cents"`
+	Entries      []LedgerEntryResponseDto `json:"entries"`
+}
+
+type RedemptionResponseDto struct {
+	Code               string `json:"code" example:"****6789"`
+	RedeemedCents      int32  `json:"redeemed_cents"`
+	CardRemainingCents int32  `json:"card_remaining_cents"`
+	BalanceCents       int32  `json:"balance_cents"`
+}
+
+type HaircutPaymentResponseDto struct {
+	EventID        uuid.UUID `json:"event_id"`
+	ServiceName    string    `json:"service_name"`
+	PriceCents     int32     `json:"price_cents"`
+	PaidCents      int32     `json:"paid_cents"`
+	RemainingCents int32     `json:"remaining_cents"` // Left to pay at the shop
+	BalanceCents   int32     `json:"balance_cents"`
+}
+
+type LedgerActivityResponseDto struct {
+	EntryType   string `json:"entry_type"`
+	Entries     int64  `json:"entries"`
+	AmountCents int64  `json:"amount_cents"`
+}
+
+type LiabilityReportResponseDto struct {
+	From                 time.Time                   `json:"from"`
+	To                   time.Time                   `json:"to"`
+	ActiveCards          int64                       `json:"active_cards"`
+	CardCents            int64                       `json:"card_cents"`             // Unredeemed value on active cards
+	CustomersWithBalance int64                       `json:"customers_with_balance"` // Customers holding balance
+	BalanceCents         int64                       `json:"balance_cents"`          // Value in customer balances
+	HeldCents            int64                       `json:"held_cents"`             // Balance set aside for unpaid checkouts
+	OutstandingCents     int64                       `json:"outstanding_cents"`      // Total owed to holders
+	Activity             []LedgerActivityResponseDto `json:"activity"`               // Ledger totals in [from, to)
+}
+
+func NewGiftCardResponse(v values.GiftCard) GiftCardResponseDto {
+	return GiftCardResponseDto{
+		ID:             v.ID,
+		Code:           values.FormatCode(v.Code),
+		InitialCents:   v.InitialCents,
+		RemainingCents: v.RemainingCents,
+		Currency:       v.Currency,
+		Status:         v.Status,
+		PurchaserID:    v.PurchaserID,
+		RecipientName:  v.RecipientName,
+		RecipientEmail: v.RecipientEmail,
+		Message:        v.Message,
+		IssuedBy:       v.IssuedBy,
+		IssuedAt:       v.IssuedAt,
+		ExpiresAt:      v.ExpiresAt,
+		VoidedAt:       v.VoidedAt,
+		VoidReason:     v.VoidReason,
+		CreatedAt:      v.CreatedAt,
+	}
+}
+
+func NewGiftCardResponses(cards []values.GiftCard) []GiftCardResponseDto {
+	resp := make([]GiftCardResponseDto, len(cards))
+	for i, c := range cards {
+		resp[i] = NewGiftCardResponse(c)
+	}
+	return resp
+}
+
+func NewGiftCardDetailResponse(card values.GiftCard, entries []values.LedgerEntry) GiftCardDetailResponseDto {
+	return GiftCardDetailResponseDto{
+		GiftCardResponseDto: NewGiftCardResponse(card),
+		Ledger:              NewLedgerEntryResponses(entries),
+	}
+}
+
+func NewLedgerEntryResponses(entries []values.LedgerEntry) []LedgerEntryResponseDto {
+	resp := make([]LedgerEntryResponseDto, len(entries))
+	for i, e := range entries {
+		resp[i] = LedgerEntryResponseDto{
+			ID:             e.ID,
+			EntryType:      string(e.EntryType),
+			GiftCardID:     e.GiftCardID,
+			CustomerID:     e.CustomerID,
+			HoldID:         e.HoldID,
+			HaircutEventID: e.HaircutEventID,
+			AmountCents:    e.AmountCents,
+			Description:    e.Description,
+			CreatedBy:      e.CreatedBy,
+			CreatedAt:      e.CreatedAt,
+		}
+	}
+	return resp
+}
+
+func NewPurchasedGiftCardResponses(cards []values.GiftCard) []PurchasedGiftCardResponseDto {
+	resp := make([]PurchasedGiftCardResponseDto, len(cards))
+	for i, c := range cards {
+		resp[i] = PurchasedGiftCardResponseDto{
+			ID:             c.ID,
+			Code:           values.MaskCode(c.Code),
+			InitialCents:   c.InitialCents,
+			Status:         c.Status,
+			RecipientName:  c.RecipientName,
+			RecipientEmail: c.RecipientEmail,
+			IssuedAt:       c.IssuedAt,
+			ExpiresAt:      c.ExpiresAt,
+		}
+	}
+	return resp
+}
+
+func NewBalanceResponse(v values.Balance) BalanceResponseDto {
+	return BalanceResponseDto{BalanceCents: v.BalanceCents, Entries: NewLedgerEntryResponses(v.Entries)}
+}
+
+func NewRedemptionResponse(v values.Redemption) RedemptionResponseDto {
+	return RedemptionResponseDto{
+		Code:               values.MaskCode(v.Card.Code),
+		RedeemedCents:      v.RedeemedCents,
+		CardRemainingCents: v.Card.RemainingCents,
+		BalanceCents:       v.BalanceCents,
+	}
+}
+
+func NewHaircutPaymentResponse(v values.HaircutPayment) HaircutPaymentResponseDto {
+	return HaircutPaymentResponseDto{
+		EventID:        v.EventID,
+		ServiceName:    v.ServiceName,
+		PriceCents:     v.PriceCents,
+		PaidCents:      v.PaidCents,
+		RemainingCents: v.RemainingCents,
+		BalanceCents:   v.BalanceCents,
+	}
+}
+
+func NewLiabilityReportResponse(v values.LiabilityReport) LiabilityReportResponseDto {
+	activity := make([]LedgerActivityResponseDto, len(v.Activity))
+	for i, a := range v.Activity {
+		activity[i] = LedgerActivityResponseDto{
+			EntryType:   string(a.EntryType),
+			Entries:     a.Entries,
+			AmountCents: a.AmountCents,
+		}
+	}
+	return LiabilityReportResponseDto{
+		From:                 v.From,
+		To:                   v.To,
+		ActiveCards:          v.ActiveCards,
+		CardCents:            v.CardCents,
+		CustomersWithBalance: v.CustomersWithBalance,
+		BalanceCents:         v.BalanceCents,
+		HeldCents:            v.HeldCents,
+		OutstandingCents:     v.OutstandingCents,
+		Activity:             activity,
+	}
+}
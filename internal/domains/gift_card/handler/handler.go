@@ -0,0 +1,285 @@
+package gift_card
+
+import (
+	"net/http"
+
+	"api/internal/di"
+	dto "api/internal/domains/gift_card/dto"
+	service "api/internal/domains/gift_card/service"
+	responseHandlers "api/internal/libs/responses"
+	"api/internal/libs/validators"
+	contextUtils "api/utils/context"
+
+	"github.com/go-chi/chi"
+)
+
+type Handler struct {
+	Service *service.Service
+}
+
+func NewHandler(container *di.Container) *Handler {
+	return &Handler{Service: service.NewService(container)}
+}
+
+// GetMyBalance returns the logged-in customer's gift card balance and latest balance movements.
+// @Tags gift-cards
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} dto.BalanceResponseDto "Balance"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /gift-cards/balance [get]
+func (h *Handler) GetMyBalance(w http.ResponseWriter, r *http.Request) {
+	customerID, err := contextUtils.GetUserID(r.Context())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	balance, err := h.Service.GetBalance(r.Context(), customerID)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewBalanceResponse(balance), http.StatusOK)
+}
+
+// GetMyPurchases lists the gift cards the logged-in customer bought online.
+// @Tags gift-cards
+// @Produce json
+// @Security Bearer
+// @Success 200 {array} dto.PurchasedGiftCardResponseDto "Gift cards bought"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /gift-cards/purchased [get]
+func (h *Handler) GetMyPurchases(w http.ResponseWriter, r *http.Request) {
+	customerID, err := contextUtils.GetUserID(r.Context())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	cards, err := h.Service.ListPurchased(r.Context(), customerID)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewPurchasedGiftCardResponses(cards), http.StatusOK)
+}
+
+// Redeem moves value from a gift card into the logged-in customer's balance.
+// @Description Codes are accepted with or without dashes and spaces. A card can be redeemed in parts.
+// @Tags gift-cards
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param redemption body dto.RedeemRequestDto true "Code and amount"
+// @Success 200 {object} dto.RedemptionResponseDto "Redeemed"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Card redeemed, expired or void, or amount exceeds what is left"
+// @Failure 404 {object} map[string]interface{} "Not Found: Gift card not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /gift-cards/redeem [post]
+func (h *Handler) Redeem(w http.ResponseWriter, r *http.Request) {
+	var req dto.RedeemRequestDto
+	if err := validators.ParseJSON(r.Body, &req); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	customerID, err := contextUtils.GetUserID(r.Context())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	redemption, err := h.Service.Redeem(r.Context(), customerID, req.Code, req.AmountCents)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewRedemptionResponse(redemption), http.StatusOK)
+}
+
+// PayHaircut pays a haircut appointment from the logged-in customer's balance.
+// @Description Pays as much of the service price as the balance covers; the rest is paid at the shop.
+// @Tags gift-cards
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Haircut event ID"
+// @Success 200 {object} dto.HaircutPaymentResponseDto "Paid"
+// @Failure 400 {object} map[string]interface{} "Bad Request: No balance"
+// @Failure 404 {object} map[string]interface{} "Not Found: Appointment not found"
+// @Failure 409 {object} map[string]interface{} "Conflict: Already paid with balance"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /gift-cards/haircuts/{id}/pay [post]
+func (h *Handler) PayHaircut(w http.ResponseWriter, r *http.Request) {
+	eventID, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	customerID, err := contextUtils.GetUserID(r.Context())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	payment, err := h.Service.PayHaircut(r.Context(), customerID, eventID)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewHaircutPaymentResponse(payment), http.StatusOK)
+}
+
+// GetGiftCards lists gift cards for staff.
+// @Tags gift-cards
+// @Produce json
+// @Security Bearer
+// @Param status query string false "pending, active, redeemed, expired or void"
+// @Param purchaser_id query string false "Only cards bought by this customer"
+// @Param limit query int false "Number of records (default 20, max 100)"
+// @Param offset query int false "Number of records to skip"
+// @Success 200 {array} dto.GiftCardResponseDto "Gift cards"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid query"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /gift-cards [get]
+func (h *Handler) GetGiftCards(w http.ResponseWriter, r *http.Request) {
+	filter, err := dto.ParseListQuery(r.URL.Query())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	cards, err := h.Service.ListCards(r.Context(), filter)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewGiftCardResponses(cards), http.StatusOK)
+}
+
+// GetGiftCard returns a gift card with its ledger.
+// @Tags gift-cards
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Gift card ID"
+// @Success 200 {object} dto.GiftCardDetailResponseDto "Gift card"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid ID"
+// @Failure 404 {object} map[string]interface{} "Not Found: Gift card not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /gift-cards/{id} [get]
+func (h *Handler) GetGiftCard(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	card, entries, err := h.Service.GetCard(r.Context(), id)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewGiftCardDetailResponse(card, entries), http.StatusOK)
+}
+
+// IssueGiftCard issues an active gift card for a physical certificate sold in person.
+// @Description Pass the code printed on the certificate, or omit it to have one generated.
+// @Tags gift-cards
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param gift_card body dto.IssueRequestDto true "Gift card"
+// @Success 201 {object} dto.GiftCardResponseDto "Gift card issued"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid input"
+// @Failure 409 {object} map[string]interface{} "Conflict: Code already in use"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /gift-cards [post]
+func (h *Handler) IssueGiftCard(w http.ResponseWriter, r *http.Request) {
+	var req dto.IssueRequestDto
+	if err := validators.ParseJSON(r.Body, &req); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	v, err := req.ToValues()
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	card, err := h.Service.IssueCard(r.Context(), v)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewGiftCardResponse(card), http.StatusCreated)
+}
+
+// VoidGiftCard cancels what is left on an active gift card.
+// @Description Value already redeemed into a customer's balance is not affected.
+// @Tags gift-cards
+// @Accept json
+// @Security Bearer
+// @Param id path string true "Gift card ID"
+// @Param void body dto.VoidRequestDto true "Reason"
+// @Success 204 "No Content: Gift card voided"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Card is not active"
+// @Failure 404 {object} map[string]interface{} "Not Found: Gift card not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /gift-cards/{id}/void [post]
+func (h *Handler) VoidGiftCard(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	var req dto.VoidRequestDto
+	if err = validators.ParseJSON(r.Body, &req); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	if err = req.Validate(); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	if err = h.Service.VoidCard(r.Context(), id, req.Reason); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, nil, http.StatusNoContent)
+}
+
+// GetLiability reports the value owed to gift card holders.
+// @Description Outstanding liability is unredeemed card value plus customer balances plus balance held for unpaid checkouts.
+// @Description activity totals ledger entries by type between from and to.
+// @Tags gift-cards
+// @Produce json
+// @Security Bearer
+// @Param from query string false "Activity window start (RFC 3339, default 30 days before to)"
+// @Param to query string false "Activity window end (RFC 3339, default now)"
+// @Success 200 {object} dto.LiabilityReportResponseDto "Liability report"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid window"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /gift-cards/liability [get]
+func (h *Handler) GetLiability(w http.ResponseWriter, r *http.Request) {
+	from, to, err := dto.ParseReportWindow(r.URL.Query())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	report, err := h.Service.GetLiabilityReport(r.Context(), from, to)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewLiabilityReportResponse(report), http.StatusOK)
+}
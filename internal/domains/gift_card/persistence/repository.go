@@ -0,0 +1,422 @@
+package gift_card
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	databaseErrors "api/internal/constants"
+	"api/internal/di"
+	db "api/internal/domains/gift_card/persistence/sqlc/generated"
+	values "api/internal/domains/gift_card/values"
+	errLib "api/internal/libs/errors"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type Repository struct {
+	Queries *db.Queries
+	Tx      *sql.Tx
+}
+
+func NewRepository(container *di.Container) *Repository {
+	return &Repository{Queries: container.Queries.GiftCardDb}
+}
+
+func (r *Repository) GetTx() *sql.Tx { return r.Tx }
+
+func (r *Repository) WithTx(tx *sql.Tx) *Repository {
+	return &Repository{Queries: r.Queries.WithTx(tx), Tx: tx}
+}
+
+func nullUUID(id uuid.UUID) uuid.NullUUID {
+	return uuid.NullUUID{UUID: id, Valid: id != uuid.Nil}
+}
+
+func nullString(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *s, Valid: true}
+}
+
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
+
+func uuidPtr(id uuid.NullUUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
+	}
+	return &id.UUID
+}
+
+func stringPtr(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
+}
+
+func timePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func mapCard(row db.GiftCardsGiftCard) values.GiftCard {
+	return values.GiftCard{
+		ID:             row.ID,
+		Code:           row.Code,
+		InitialCents:   row.InitialCents,
+		RemainingCents: row.RemainingCents,
+		Currency:       row.Currency,
+		Status:         row.Status,
+		PurchaserID:    uuidPtr(row.PurchaserID),
+		RecipientName:  stringPtr(row.RecipientName),
+		RecipientEmail: stringPtr(row.RecipientEmail),
+		Message:        stringPtr(row.Message),
+		IssuedBy:       uuidPtr(row.IssuedBy),
+		IssuedAt:       timePtr(row.IssuedAt),
+		ExpiresAt:      timePtr(row.ExpiresAt),
+		VoidedAt:       timePtr(row.VoidedAt),
+		VoidReason:     stringPtr(row.VoidReason),
+		CreatedAt:      row.CreatedAt,
+		UpdatedAt:      row.UpdatedAt,
+	}
+}
+
+func mapCards(rows []db.GiftCardsGiftCard) []values.GiftCard {
+	cards := make([]values.GiftCard, len(rows))
+	for i, row := range rows {
+		cards[i] = mapCard(row)
+	}
+	return cards
+}
+
+func mapEntries(rows []db.GiftCardsLedgerEntry) []values.LedgerEntry {
+	entries := make([]values.LedgerEntry, len(rows))
+	for i, row := range rows {
+		entries[i] = values.LedgerEntry{
+			ID:             row.ID,
+			EntryType:      values.EntryType(row.EntryType),
+			GiftCardID:     uuidPtr(row.GiftCardID),
+			CustomerID:     uuidPtr(row.CustomerID),
+			HoldID:         uuidPtr(row.HoldID),
+			HaircutEventID: uuidPtr(row.HaircutEventID),
+			AmountCents:    row.AmountCents,
+			Description:    row.Description,
+			CreatedBy:      uuidPtr(row.CreatedBy),
+			CreatedAt:      row.CreatedAt,
+		}
+	}
+	return entries
+}
+
+func cardNotFound(err error, id string) *errLib.CommonError {
+	if errors.Is(err, sql.ErrNoRows) {
+		return errLib.New("Gift card not found", http.StatusNotFound)
+	}
+	log.Printf("Failed to get gift card %s: %v", id, err)
+	return errLib.New("Failed to get gift card", http.StatusInternalServerError)
+}
+
+func (r *Repository) CreateCard(ctx context.Context, params db.CreateGiftCardParams) (values.GiftCard, *errLib.CommonError) {
+	row, err := r.Queries.CreateGiftCard(ctx, params)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == databaseErrors.UniqueViolation {
+			return values.GiftCard{}, errLib.New("A gift card with this code already exists", http.StatusConflict)
+		}
+		log.Printf("Failed to create gift card: %v", err)
+		return values.GiftCard{}, errLib.New("Failed to create gift card", http.StatusInternalServerError)
+	}
+	return mapCard(row), nil
+}
+
+func (r *Repository) GetCard(ctx context.Context, id uuid.UUID) (values.GiftCard, *errLib.CommonError) {
+	row, err := r.Queries.GetGiftCardById(ctx, id)
+	if err != nil {
+		return values.GiftCard{}, cardNotFound(err, id.String())
+	}
+	return mapCard(row), nil
+}
+
+// LockCard reads a gift card's row for update.
+func (r *Repository) LockCard(ctx context.Context, id uuid.UUID) (values.GiftCard, *errLib.CommonError) {
+	row, err := r.Queries.GetGiftCardByIdForUpdate(ctx, id)
+	if err != nil {
+		return values.GiftCard{}, cardNotFound(err, id.String())
+	}
+	return mapCard(row), nil
+}
+
+// LockCardByCode reads the row of the gift card with a normalized code for update.
+func (r *Repository) LockCardByCode(ctx context.Context, code string) (values.GiftCard, *errLib.CommonError) {
+	row, err := r.Queries.GetGiftCardByCodeForUpdate(ctx, code)
+	if err != nil {
+		return values.GiftCard{}, cardNotFound(err, "by code")
+	}
+	return mapCard(row), nil
+}
+
+func (r *Repository) ListCards(ctx context.Context, filter values.ListFilter) ([]values.GiftCard, *errLib.CommonError) {
+	rows, err := r.Queries.ListGiftCards(ctx, db.ListGiftCardsParams{
+		Status:      sql.NullString{String: filter.Status, Valid: filter.Status != ""},
+		PurchaserID: nullUUID(filter.PurchaserID),
+		Limit:       filter.Limit,
+		Offset:      filter.Offset,
+	})
+	if err != nil {
+		log.Printf("Failed to list gift cards: %v", err)
+		return nil, errLib.New("Failed to list gift cards", http.StatusInternalServerError)
+	}
+	return mapCards(rows), nil
+}
+
+func (r *Repository) ListPurchasedCards(ctx context.Context, customerID uuid.UUID) ([]values.GiftCard, *errLib.CommonError) {
+	rows, err := r.Queries.ListPurchasedGiftCards(ctx, nullUUID(customerID))
+	if err != nil {
+		log.Printf("Failed to list gift cards bought by %s: %v", customerID, err)
+		return nil, errLib.New("Failed to list gift cards", http.StatusInternalServerError)
+	}
+	return mapCards(rows), nil
+}
+
+// ActivateCard activates a pending card, reporting false when it was not pending.
+func (r *Repository) ActivateCard(ctx context.Context, id uuid.UUID, checkoutSessionID string) (bool, *errLib.CommonError) {
+	affected, err := r.Queries.ActivateGiftCard(ctx, db.ActivateGiftCardParams{
+		ID:                      id,
+		StripeCheckoutSessionID: sql.NullString{String: checkoutSessionID, Valid: checkoutSessionID != ""},
+	})
+	if err != nil {
+		log.Printf("Failed to activate gift card %s: %v", id, err)
+		return false, errLib.New("Failed to activate gift card", http.StatusInternalServerError)
+	}
+	return affected > 0, nil
+}
+
+func (r *Repository) UpdateRemaining(ctx context.Context, id uuid.UUID, remainingCents int32, status string) *errLib.CommonError {
+	if err := r.Queries.UpdateGiftCardRemaining(ctx, db.UpdateGiftCardRemainingParams{
+		ID:             id,
+		RemainingCents: remainingCents,
+		Status:         status,
+	}); err != nil {
+		log.Printf("Failed to update gift card %s: %v", id, err)
+		return errLib.New("Failed to update gift card", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (r *Repository) VoidCard(ctx context.Context, id, staffID uuid.UUID, reason string) *errLib.CommonError {
+	if err := r.Queries.VoidGiftCard(ctx, db.VoidGiftCardParams{
+		ID:         id,
+		VoidedBy:   nullUUID(staffID),
+		VoidReason: sql.NullString{String: reason, Valid: reason != ""},
+	}); err != nil {
+		log.Printf("Failed to void gift card %s: %v", id, err)
+		return errLib.New("Failed to void gift card", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (r *Repository) ListExpiredCardIDs(ctx context.Context, now time.Time) ([]uuid.UUID, *errLib.CommonError) {
+	ids, err := r.Queries.ListExpiredGiftCardIds(ctx, now)
+	if err != nil {
+		log.Printf("Failed to list expired gift cards: %v", err)
+		return nil, errLib.New("Failed to list expired gift cards", http.StatusInternalServerError)
+	}
+	return ids, nil
+}
+
+func (r *Repository) DeleteAbandonedCards(ctx context.Context, createdBefore time.Time) (int64, *errLib.CommonError) {
+	affected, err := r.Queries.DeleteAbandonedGiftCards(ctx, createdBefore)
+	if err != nil {
+		log.Printf("Failed to delete abandoned gift card purchases: %v", err)
+		return 0, errLib.New("Failed to delete abandoned gift card purchases", http.StatusInternalServerError)
+	}
+	return affected, nil
+}
+
+func (r *Repository) GetBalance(ctx context.Context, customerID uuid.UUID) (int32, *errLib.CommonError) {
+	balance, err := r.Queries.GetStoredValueBalance(ctx, customerID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, errLib.New("Customer not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to get stored value balance of %s: %v", customerID, err)
+		return 0, errLib.New("Failed to get gift card balance", http.StatusInternalServerError)
+	}
+	return balance, nil
+}
+
+// LockBalance reads a customer's balance for update.
+func (r *Repository) LockBalance(ctx context.Context, customerID uuid.UUID) (int32, *errLib.CommonError) {
+	balance, err := r.Queries.GetStoredValueBalanceForUpdate(ctx, customerID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, errLib.New("Customer not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to lock stored value balance of %s: %v", customerID, err)
+		return 0, errLib.New("Failed to get gift card balance", http.StatusInternalServerError)
+	}
+	return balance, nil
+}
+
+// AddBalance adds amountCents, or takes it off when negative, and returns the new balance.
+func (r *Repository) AddBalance(ctx context.Context, customerID uuid.UUID, amountCents int32) (int32, *errLib.CommonError) {
+	balance, err := r.Queries.AddStoredValueBalance(ctx, db.AddStoredValueBalanceParams{
+		Amount: amountCents,
+		ID:     customerID,
+	})
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == databaseErrors.CheckViolation {
+			return 0, errLib.New("Insufficient gift card balance", http.StatusBadRequest)
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, errLib.New("Customer not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to update stored value balance of %s: %v", customerID, err)
+		return 0, errLib.New("Failed to update gift card balance", http.StatusInternalServerError)
+	}
+	return balance, nil
+}
+
+func (r *Repository) GetCustomerContact(ctx context.Context, customerID uuid.UUID) (db.GetCustomerContactRow, *errLib.CommonError) {
+	row, err := r.Queries.GetCustomerContact(ctx, customerID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return row, errLib.New("Customer not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to get contact details of %s: %v", customerID, err)
+		return row, errLib.New("Failed to get customer", http.StatusInternalServerError)
+	}
+	return row, nil
+}
+
+func (r *Repository) CreateEntry(ctx context.Context, params db.CreateLedgerEntryParams) *errLib.CommonError {
+	if _, err := r.Queries.CreateLedgerEntry(ctx, params); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == databaseErrors.UniqueViolation {
+			return errLib.New("This haircut has already been paid with gift card balance", http.StatusConflict)
+		}
+		log.Printf("Failed to write gift card ledger entry: %v", err)
+		return errLib.New("Failed to record gift card transaction", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (r *Repository) ListCustomerEntries(ctx context.Context, customerID uuid.UUID, limit int32) ([]values.LedgerEntry, *errLib.CommonError) {
+	rows, err := r.Queries.ListCustomerLedgerEntries(ctx, db.ListCustomerLedgerEntriesParams{
+		CustomerID: nullUUID(customerID),
+		Limit:      limit,
+	})
+	if err != nil {
+		log.Printf("Failed to list gift card ledger of %s: %v", customerID, err)
+		return nil, errLib.New("Failed to list gift card transactions", http.StatusInternalServerError)
+	}
+	return mapEntries(rows), nil
+}
+
+func (r *Repository) ListCardEntries(ctx context.Context, cardID uuid.UUID) ([]values.LedgerEntry, *errLib.CommonError) {
+	rows, err := r.Queries.ListGiftCardLedgerEntries(ctx, nullUUID(cardID))
+	if err != nil {
+		log.Printf("Failed to list ledger of gift card %s: %v", cardID, err)
+		return nil, errLib.New("Failed to list gift card transactions", http.StatusInternalServerError)
+	}
+	return mapEntries(rows), nil
+}
+
+func (r *Repository) SumActivity(ctx context.Context, from, to time.Time) ([]values.LedgerActivity, *errLib.CommonError) {
+	rows, err := r.Queries.SumLedgerEntriesByType(ctx, db.SumLedgerEntriesByTypeParams{From: from, To: to})
+	if err != nil {
+		log.Printf("Failed to sum gift card ledger: %v", err)
+		return nil, errLib.New("Failed to build gift card liability report", http.StatusInternalServerError)
+	}
+	activity := make([]values.LedgerActivity, len(rows))
+	for i, row := range rows {
+		activity[i] = values.LedgerActivity{
+			EntryType:   values.EntryType(row.EntryType),
+			Entries:     row.Entries,
+			AmountCents: row.AmountCents,
+		}
+	}
+	return activity, nil
+}
+
+func (r *Repository) GetOutstandingLiability(ctx context.Context) (db.GetOutstandingLiabilityRow, *errLib.CommonError) {
+	row, err := r.Queries.GetOutstandingLiability(ctx)
+	if err != nil {
+		log.Printf("Failed to get gift card liability: %v", err)
+		return row, errLib.New("Failed to build gift card liability report", http.StatusInternalServerError)
+	}
+	return row, nil
+}
+
+func (r *Repository) CreateHold(ctx context.Context, params db.CreateBalanceHoldParams) (db.GiftCardsBalanceHold, *errLib.CommonError) {
+	row, err := r.Queries.CreateBalanceHold(ctx, params)
+	if err != nil {
+		log.Printf("Failed to hold gift card balance of %s: %v", params.CustomerID, err)
+		return row, errLib.New("Failed to apply gift card balance", http.StatusInternalServerError)
+	}
+	return row, nil
+}
+
+// LockHold reads a balance hold's row for update.
+func (r *Repository) LockHold(ctx context.Context, id uuid.UUID) (db.GiftCardsBalanceHold, *errLib.CommonError) {
+	row, err := r.Queries.GetBalanceHoldForUpdate(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return row, errLib.New("Gift card balance hold not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to lock gift card balance hold %s: %v", id, err)
+		return row, errLib.New("Failed to get gift card balance hold", http.StatusInternalServerError)
+	}
+	return row, nil
+}
+
+func (r *Repository) CaptureHold(ctx context.Context, id uuid.UUID) *errLib.CommonError {
+	if err := r.Queries.CaptureBalanceHold(ctx, id); err != nil {
+		log.Printf("Failed to capture gift card balance hold %s: %v", id, err)
+		return errLib.New("Failed to capture gift card balance", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (r *Repository) ReleaseHold(ctx context.Context, id uuid.UUID) *errLib.CommonError {
+	if err := r.Queries.ReleaseBalanceHold(ctx, id); err != nil {
+		log.Printf("Failed to release gift card balance hold %s: %v", id, err)
+		return errLib.New("Failed to release gift card balance", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (r *Repository) ListExpiredHoldIDs(ctx context.Context, expiredBefore time.Time) ([]uuid.UUID, *errLib.CommonError) {
+	ids, err := r.Queries.ListExpiredBalanceHoldIds(ctx, expiredBefore)
+	if err != nil {
+		log.Printf("Failed to list expired gift card balance holds: %v", err)
+		return nil, errLib.New("Failed to list expired gift card balance holds", http.StatusInternalServerError)
+	}
+	return ids, nil
+}
+
+func (r *Repository) GetHaircutCharge(ctx context.Context, eventID uuid.UUID) (db.GetHaircutChargeRow, *errLib.CommonError) {
+	row, err := r.Queries.GetHaircutCharge(ctx, eventID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return row, errLib.New("Haircut appointment not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to get price of haircut %s: %v", eventID, err)
+		return row, errLib.New("Failed to get haircut appointment", http.StatusInternalServerError)
+	}
+	return row, nil
+}
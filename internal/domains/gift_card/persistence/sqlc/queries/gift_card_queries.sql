@@ -0,0 +1,183 @@
+-- name: CreateGiftCard :one
+INSERT INTO gift_cards.gift_cards (code, initial_cents, remaining_cents, currency, status, purchaser_id, recipient_name,
+                                   recipient_email, message, issued_by, issued_at, expires_at)
+VALUES ($1, $2, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING *;
+
+-- name: GetGiftCardById :one
+SELECT *
+FROM gift_cards.gift_cards
+WHERE id = $1;
+
+-- name: GetGiftCardByIdForUpdate :one
+SELECT *
+FROM gift_cards.gift_cards
+WHERE id = $1
+    FOR UPDATE;
+
+-- name: GetGiftCardByCodeForUpdate :one
+SELECT *
+FROM gift_cards.gift_cards
+WHERE code = $1
+    FOR UPDATE;
+
+-- name: ListGiftCards :many
+SELECT *
+FROM gift_cards.gift_cards
+WHERE (sqlc.narg('status')::text IS NULL OR status = sqlc.narg('status')::text)
+  AND (sqlc.narg('purchaser_id')::uuid IS NULL OR purchaser_id = sqlc.narg('purchaser_id')::uuid)
+ORDER BY created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListPurchasedGiftCards :many
+-- Cards a customer bought online, newest first. Unpaid purchases are left out.
+SELECT *
+FROM gift_cards.gift_cards
+WHERE purchaser_id = $1
+  AND status != 'pending'
+ORDER BY created_at DESC;
+
+-- name: ActivateGiftCard :execrows
+UPDATE gift_cards.gift_cards
+SET status                     = 'active',
+    stripe_checkout_session_id = $2,
+    issued_at                  = CURRENT_TIMESTAMP,
+    updated_at                 = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND status = 'pending';
+
+-- name: UpdateGiftCardRemaining :exec
+UPDATE gift_cards.gift_cards
+SET remaining_cents = $2,
+    status          = $3,
+    updated_at      = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: VoidGiftCard :exec
+UPDATE gift_cards.gift_cards
+SET status          = 'void',
+    remaining_cents = 0,
+    voided_by       = $2,
+    voided_at       = CURRENT_TIMESTAMP,
+    void_reason     = $3,
+    updated_at      = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: ListExpiredGiftCardIds :many
+SELECT id
+FROM gift_cards.gift_cards
+WHERE status = 'active'
+  AND expires_at <= sqlc.arg('now')::timestamptz;
+
+-- name: DeleteAbandonedGiftCards :execrows
+-- Purchases whose checkout was abandoned never become cards
+DELETE
+FROM gift_cards.gift_cards
+WHERE status = 'pending'
+  AND created_at < $1;
+
+-- name: GetStoredValueBalance :one
+SELECT stored_value_balance
+FROM users.users
+WHERE id = $1;
+
+-- name: GetStoredValueBalanceForUpdate :one
+SELECT stored_value_balance
+FROM users.users
+WHERE id = $1
+    FOR UPDATE;
+
+-- name: AddStoredValueBalance :one
+-- Adds amount (negative to take it off) to the customer's balance. The balance
+-- cannot go below zero.
+UPDATE users.users
+SET stored_value_balance = stored_value_balance + sqlc.arg('amount')::int
+WHERE id = sqlc.arg('id')
+RETURNING stored_value_balance;
+
+-- name: GetCustomerContact :one
+SELECT first_name, email
+FROM users.users
+WHERE id = $1;
+
+-- name: CreateLedgerEntry :one
+INSERT INTO gift_cards.ledger_entries (entry_type, gift_card_id, customer_id, hold_id, haircut_event_id, amount_cents,
+                                       description, created_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: ListCustomerLedgerEntries :many
+SELECT *
+FROM gift_cards.ledger_entries
+WHERE customer_id = $1
+  AND entry_type IN ('redeem', 'spend', 'release')
+ORDER BY created_at DESC
+LIMIT $2;
+
+-- name: ListGiftCardLedgerEntries :many
+SELECT *
+FROM gift_cards.ledger_entries
+WHERE gift_card_id = $1
+ORDER BY created_at;
+
+-- name: SumLedgerEntriesByType :many
+-- Ledger activity of each entry type in [from, to)
+SELECT entry_type,
+       COUNT(*)::bigint                       AS entries,
+       COALESCE(SUM(amount_cents), 0)::bigint AS amount_cents
+FROM gift_cards.ledger_entries
+WHERE created_at >= sqlc.arg('from')
+  AND created_at < sqlc.arg('to')
+GROUP BY entry_type
+ORDER BY entry_type;
+
+-- name: GetOutstandingLiability :one
+-- Value owed to gift card holders right now: unredeemed card value, customer
+-- balances and balance set aside for unpaid checkouts.
+SELECT (SELECT COUNT(*) FROM gift_cards.gift_cards WHERE status = 'active')::bigint AS active_cards,
+       (SELECT COALESCE(SUM(remaining_cents), 0)
+        FROM gift_cards.gift_cards
+        WHERE status = 'active')::bigint                                                AS card_cents,
+       (SELECT COUNT(*) FROM users.users WHERE stored_value_balance > 0)::bigint        AS customers_with_balance,
+       (SELECT COALESCE(SUM(stored_value_balance), 0) FROM users.users)::bigint         AS balance_cents,
+       (SELECT COALESCE(SUM(amount_cents), 0)
+        FROM gift_cards.balance_holds
+        WHERE status = 'held')::bigint                                                  AS held_cents;
+
+-- name: CreateBalanceHold :one
+INSERT INTO gift_cards.balance_holds (customer_id, amount_cents, description, expires_at)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetBalanceHoldForUpdate :one
+SELECT *
+FROM gift_cards.balance_holds
+WHERE id = $1
+    FOR UPDATE;
+
+-- name: CaptureBalanceHold :exec
+UPDATE gift_cards.balance_holds
+SET status      = 'captured',
+    captured_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: ReleaseBalanceHold :exec
+UPDATE gift_cards.balance_holds
+SET status      = 'released',
+    released_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: ListExpiredBalanceHoldIds :many
+SELECT id
+FROM gift_cards.balance_holds
+WHERE status = 'held'
+  AND expires_at < $1;
+
+-- name: GetHaircutCharge :one
+SELECT e.customer_id,
+       e.begin_date_time,
+       s.name               AS service_name,
+       (s.price * 100)::int AS price_cents
+FROM haircut.events e
+         JOIN haircut.haircut_services s ON s.id = e.service_type_id
+WHERE e.id = $1;
@@ -0,0 +1,740 @@
+package gift_card
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"time"
+
+	"api/internal/di"
+	staffActivityLogs "api/internal/domains/audit/staff_activity_logs/service"
+	repo "api/internal/domains/gift_card/persistence"
+	db "api/internal/domains/gift_card/persistence/sqlc/generated"
+	values "api/internal/domains/gift_card/values"
+	errLib "api/internal/libs/errors"
+	contextUtils "api/utils/context"
+	txUtils "api/utils/db"
+	"api/utils/email"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MinPurchaseCents and MaxPurchaseCents bound the value of a gift card.
+	MinPurchaseCents = 1000
+	MaxPurchaseCents = 100000
+	// HoldDuration is how long balance stays set aside while the customer pays. The
+	// checkout session expires with the hold, and Stripe wants at least 30 minutes.
+	HoldDuration = 35 * time.Minute
+	// HoldGrace is how long after its checkout expired a hold is released, so a late
+	// payment webhook still finds it held.
+	HoldGrace = time.Hour
+	// PurchaseTTL is how long an unpaid online purchase is kept before it is deleted.
+	PurchaseTTL = 48 * time.Hour
+
+	codeLength   = 16
+	codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	minCodeLen   = 6
+	maxCodeLen   = 32
+)
+
+// Service sells gift cards and keeps the stored-value balance they are redeemed into.
+// Every change to card value or a balance is written to the ledger in the same
+// transaction.
+type Service struct {
+	repo                     *repo.Repository
+	staffActivityLogsService *staffActivityLogs.Service
+	db                       *sql.DB
+}
+
+func NewService(container *di.Container) *Service {
+	return &Service{
+		repo:                     repo.NewRepository(container),
+		staffActivityLogsService: staffActivityLogs.NewService(container),
+		db:                       container.DB,
+	}
+}
+
+// CreatePurchase records a gift card bought online. It stays pending until its
+// checkout is paid.
+func (s *Service) CreatePurchase(ctx context.Context, req values.PurchaseRequest) (values.GiftCard, *errLib.CommonError) {
+	if err := validateAmount(req.AmountCents); err != nil {
+		return values.GiftCard{}, err
+	}
+	code, err := generateCode()
+	if err != nil {
+		return values.GiftCard{}, err
+	}
+
+	return s.repo.CreateCard(ctx, db.CreateGiftCardParams{
+		Code:           code,
+		InitialCents:   req.AmountCents,
+		Currency:       "cad",
+		Status:         values.StatusPending,
+		PurchaserID:    uuid.NullUUID{UUID: req.PurchaserID, Valid: true},
+		RecipientName:  sql.NullString{String: req.RecipientName, Valid: true},
+		RecipientEmail: sql.NullString{String: req.RecipientEmail, Valid: true},
+		Message:        nullString(req.Message),
+	})
+}
+
+// IssuePurchased activates a gift card whose checkout was paid and emails its code to
+// the recipient. It does nothing for a card that is no longer pending, so payment
+// webhooks can be retried.
+func (s *Service) IssuePurchased(ctx context.Context, cardID uuid.UUID, checkoutSessionID string) *errLib.CommonError {
+	var card values.GiftCard
+	var issued bool
+
+	err := txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		r := s.repo.WithTx(tx)
+
+		var err *errLib.CommonError
+		if issued, err = r.ActivateCard(ctx, cardID, checkoutSessionID); err != nil || !issued {
+			return err
+		}
+		if card, err = r.GetCard(ctx, cardID); err != nil {
+			return err
+		}
+		return r.CreateEntry(ctx, db.CreateLedgerEntryParams{
+			EntryType:   string(values.EntryIssue),
+			GiftCardID:  uuid.NullUUID{UUID: card.ID, Valid: true},
+			CustomerID:  nullUUIDPtr(card.PurchaserID),
+			AmountCents: card.InitialCents,
+			Description: "Bought online",
+		})
+	})
+	if err != nil || !issued {
+		return err
+	}
+
+	s.sendPurchaseEmails(ctx, card)
+	return nil
+}
+
+func (s *Service) sendPurchaseEmails(ctx context.Context, card values.GiftCard) {
+	if card.RecipientEmail == nil || card.PurchaserID == nil {
+		return
+	}
+	purchaser, err := s.repo.GetCustomerContact(ctx, *card.PurchaserID)
+	if err != nil {
+		log.Printf("Gift card %s issued but its purchaser could not be loaded: %s", card.ID, err.Message)
+		return
+	}
+
+	recipientName := deref(card.RecipientName)
+	amount := formatCents(card.InitialCents)
+	expiresOn := ""
+	if card.ExpiresAt != nil {
+		expiresOn = card.ExpiresAt.Format("January 2, 2006")
+	}
+
+	email.SendGiftCardEmail(*card.RecipientEmail, recipientName, purchaser.FirstName, amount,
+		values.FormatCode(card.Code), deref(card.Message), expiresOn)
+	if purchaser.Email.Valid {
+		email.SendGiftCardReceiptEmail(purchaser.Email.String, purchaser.FirstName, recipientName, *card.RecipientEmail, amount)
+	}
+}
+
+// IssueCard issues an active gift card for a physical certificate sold in person.
+func (s *Service) IssueCard(ctx context.Context, v values.IssueValues) (values.GiftCard, *errLib.CommonError) {
+	if err := validateAmount(v.AmountCents); err != nil {
+		return values.GiftCard{}, err
+	}
+	if v.ExpiresAt != nil && !v.ExpiresAt.After(time.Now()) {
+		return values.GiftCard{}, errLib.New("Expiry must be in the future", http.StatusBadRequest)
+	}
+
+	var code string
+	if v.Code != nil {
+		code = values.NormalizeCode(*v.Code)
+		if err := validateCode(code); err != nil {
+			return values.GiftCard{}, err
+		}
+	} else {
+		var err *errLib.CommonError
+		if code, err = generateCode(); err != nil {
+			return values.GiftCard{}, err
+		}
+	}
+
+	staffID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return values.GiftCard{}, err
+	}
+
+	var card values.GiftCard
+	err = txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		r := s.repo.WithTx(tx)
+
+		now := time.Now()
+		var err *errLib.CommonError
+		card, err = r.CreateCard(ctx, db.CreateGiftCardParams{
+			Code:           code,
+			InitialCents:   v.AmountCents,
+			Currency:       "cad",
+			Status:         values.StatusActive,
+			RecipientName:  nullString(v.RecipientName),
+			RecipientEmail: nullString(v.RecipientEmail),
+			Message:        nullString(v.Message),
+			IssuedBy:       uuid.NullUUID{UUID: staffID, Valid: true},
+			IssuedAt:       sql.NullTime{Time: now, Valid: true},
+			ExpiresAt:      nullTime(v.ExpiresAt),
+		})
+		if err != nil {
+			return err
+		}
+		if err = r.CreateEntry(ctx, db.CreateLedgerEntryParams{
+			EntryType:   string(values.EntryIssue),
+			GiftCardID:  uuid.NullUUID{UUID: card.ID, Valid: true},
+			AmountCents: card.InitialCents,
+			Description: "Issued in person",
+			CreatedBy:   uuid.NullUUID{UUID: staffID, Valid: true},
+		}); err != nil {
+			return err
+		}
+		return s.staffActivityLogsService.InsertStaffActivity(ctx, tx, staffID,
+			fmt.Sprintf("Issued gift card %s worth %s", values.MaskCode(card.Code), formatCents(card.InitialCents)))
+	})
+	if err != nil {
+		return values.GiftCard{}, err
+	}
+	return card, nil
+}
+
+// Redeem moves value from a gift card into the customer's balance. A nil amount
+// redeems everything left on the card.
+func (s *Service) Redeem(ctx context.Context, customerID uuid.UUID, code string, amountCents *int32) (values.Redemption, *errLib.CommonError) {
+	var redemption values.Redemption
+
+	err := txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		r := s.repo.WithTx(tx)
+
+		card, err := r.LockCardByCode(ctx, values.NormalizeCode(code))
+		if err != nil {
+			return err
+		}
+		if err = checkRedeemable(card, time.Now()); err != nil {
+			return err
+		}
+		amount, err := redeemAmount(card.RemainingCents, amountCents)
+		if err != nil {
+			return err
+		}
+
+		card.RemainingCents -= amount
+		if card.RemainingCents == 0 {
+			card.Status = values.StatusRedeemed
+		}
+		if err = r.UpdateRemaining(ctx, card.ID, card.RemainingCents, card.Status); err != nil {
+			return err
+		}
+		balance, err := r.AddBalance(ctx, customerID, amount)
+		if err != nil {
+			return err
+		}
+		if err = r.CreateEntry(ctx, db.CreateLedgerEntryParams{
+			EntryType:   string(values.EntryRedeem),
+			GiftCardID:  uuid.NullUUID{UUID: card.ID, Valid: true},
+			CustomerID:  uuid.NullUUID{UUID: customerID, Valid: true},
+			AmountCents: amount,
+			Description: fmt.Sprintf("Redeemed gift card %s", values.MaskCode(card.Code)),
+			CreatedBy:   uuid.NullUUID{UUID: customerID, Valid: true},
+		}); err != nil {
+			return err
+		}
+
+		redemption = values.Redemption{Card: card, RedeemedCents: amount, BalanceCents: balance}
+		return nil
+	})
+	if err != nil {
+		return values.Redemption{}, err
+	}
+	return redemption, nil
+}
+
+// CreditBalance adds value to the customer's balance that no gift card paid for, such
+// as a referral reward, and returns the new balance.
+func (s *Service) CreditBalance(ctx context.Context, customerID uuid.UUID, amountCents int32, description string) (int32, *errLib.CommonError) {
+	if amountCents <= 0 {
+		return 0, errLib.New("Credit amount must be positive", http.StatusBadRequest)
+	}
+
+	var balance int32
+	err := txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		r := s.repo.WithTx(tx)
+
+		var err *errLib.CommonError
+		if balance, err = r.AddBalance(ctx, customerID, amountCents); err != nil {
+			return err
+		}
+		return r.CreateEntry(ctx, db.CreateLedgerEntryParams{
+			EntryType:   string(values.EntryCredit),
+			CustomerID:  uuid.NullUUID{UUID: customerID, Valid: true},
+			AmountCents: amountCents,
+			Description: description,
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+	return balance, nil
+}
+
+// GetBalance returns the customer's balance and their latest balance movements.
+func (s *Service) GetBalance(ctx context.Context, customerID uuid.UUID) (values.Balance, *errLib.CommonError) {
+	balance, err := s.repo.GetBalance(ctx, customerID)
+	if err != nil {
+		return values.Balance{}, err
+	}
+	entries, err := s.repo.ListCustomerEntries(ctx, customerID, 50)
+	if err != nil {
+		return values.Balance{}, err
+	}
+	return values.Balance{CustomerID: customerID, BalanceCents: balance, Entries: entries}, nil
+}
+
+// HoldBalance sets aside as much of the customer's balance as covers amountDueCents
+// for a checkout. The hold must be captured once the checkout is paid; otherwise it is
+// released back to the balance. A customer without balance gets a zero Hold.
+func (s *Service) HoldBalance(ctx context.Context, customerID uuid.UUID, amountDueCents int32, description string) (values.Hold, *errLib.CommonError) {
+	var hold values.Hold
+
+	err := txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		r := s.repo.WithTx(tx)
+
+		balance, err := r.LockBalance(ctx, customerID)
+		if err != nil {
+			return err
+		}
+		amount := min(balance, amountDueCents)
+		if amount <= 0 {
+			return nil
+		}
+
+		row, err := r.CreateHold(ctx, db.CreateBalanceHoldParams{
+			CustomerID:  customerID,
+			AmountCents: amount,
+			Description: description,
+			ExpiresAt:   time.Now().Add(HoldDuration),
+		})
+		if err != nil {
+			return err
+		}
+		if _, err = r.AddBalance(ctx, customerID, -amount); err != nil {
+			return err
+		}
+		if err = r.CreateEntry(ctx, db.CreateLedgerEntryParams{
+			EntryType:   string(values.EntrySpend),
+			CustomerID:  uuid.NullUUID{UUID: customerID, Valid: true},
+			HoldID:      uuid.NullUUID{UUID: row.ID, Valid: true},
+			AmountCents: amount,
+			Description: description,
+			CreatedBy:   uuid.NullUUID{UUID: customerID, Valid: true},
+		}); err != nil {
+			return err
+		}
+
+		hold = values.Hold{ID: row.ID, AmountCents: amount, ExpiresAt: row.ExpiresAt}
+		return nil
+	})
+	if err != nil {
+		return values.Hold{}, err
+	}
+	return hold, nil
+}
+
+// CaptureHold keeps the balance held for a paid checkout. It does nothing for a hold
+// already captured. A hold released before the payment arrived is spent again from
+// whatever balance the customer has.
+func (s *Service) CaptureHold(ctx context.Context, holdID uuid.UUID) *errLib.CommonError {
+	return txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		r := s.repo.WithTx(tx)
+
+		hold, err := r.LockHold(ctx, holdID)
+		if err != nil {
+			return err
+		}
+
+		switch hold.Status {
+		case values.HoldCaptured:
+			return nil
+		case values.HoldReleased:
+			balance, err := r.LockBalance(ctx, hold.CustomerID)
+			if err != nil {
+				return err
+			}
+			amount := min(balance, hold.AmountCents)
+			if amount < hold.AmountCents {
+				log.Printf("Gift card balance hold %s was paid after its release; %d of %d cents could be taken again",
+					hold.ID, amount, hold.AmountCents)
+			}
+			if amount > 0 {
+				if _, err = r.AddBalance(ctx, hold.CustomerID, -amount); err != nil {
+					return err
+				}
+				if err = r.CreateEntry(ctx, db.CreateLedgerEntryParams{
+					EntryType:   string(values.EntrySpend),
+					CustomerID:  uuid.NullUUID{UUID: hold.CustomerID, Valid: true},
+					HoldID:      uuid.NullUUID{UUID: hold.ID, Valid: true},
+					AmountCents: amount,
+					Description: hold.Description,
+				}); err != nil {
+					return err
+				}
+			}
+		}
+
+		return r.CaptureHold(ctx, hold.ID)
+	})
+}
+
+// ReleaseHold returns held balance whose checkout was not paid. It does nothing for a
+// hold that is no longer held.
+func (s *Service) ReleaseHold(ctx context.Context, holdID uuid.UUID) *errLib.CommonError {
+	return txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		r := s.repo.WithTx(tx)
+
+		hold, err := r.LockHold(ctx, holdID)
+		if err != nil {
+			return err
+		}
+		if hold.Status != values.HoldHeld {
+			return nil
+		}
+
+		if err = r.ReleaseHold(ctx, hold.ID); err != nil {
+			return err
+		}
+		if _, err = r.AddBalance(ctx, hold.CustomerID, hold.AmountCents); err != nil {
+			return err
+		}
+		return r.CreateEntry(ctx, db.CreateLedgerEntryParams{
+			EntryType:   string(values.EntryRelease),
+			CustomerID:  uuid.NullUUID{UUID: hold.CustomerID, Valid: true},
+			HoldID:      uuid.NullUUID{UUID: hold.ID, Valid: true},
+			AmountCents: hold.AmountCents,
+			Description: hold.Description,
+		})
+	})
+}
+
+// PayHaircut pays as much of a haircut as the customer's balance covers. The rest is
+// paid at the shop.
+func (s *Service) PayHaircut(ctx context.Context, customerID, eventID uuid.UUID) (values.HaircutPayment, *errLib.CommonError) {
+	var payment values.HaircutPayment
+
+	err := txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		r := s.repo.WithTx(tx)
+
+		charge, err := r.GetHaircutCharge(ctx, eventID)
+		if err != nil {
+			return err
+		}
+		if charge.CustomerID != customerID {
+			return errLib.New("Haircut appointment not found", http.StatusNotFound)
+		}
+
+		balance, err := r.LockBalance(ctx, customerID)
+		if err != nil {
+			return err
+		}
+		paid := min(balance, charge.PriceCents)
+		if paid <= 0 {
+			return errLib.New("No gift card balance to pay with", http.StatusBadRequest)
+		}
+
+		if balance, err = r.AddBalance(ctx, customerID, -paid); err != nil {
+			return err
+		}
+		if err = r.CreateEntry(ctx, db.CreateLedgerEntryParams{
+			EntryType:      string(values.EntrySpend),
+			CustomerID:     uuid.NullUUID{UUID: customerID, Valid: true},
+			HaircutEventID: uuid.NullUUID{UUID: eventID, Valid: true},
+			AmountCents:    paid,
+			Description:    fmt.Sprintf("Haircut: %s", charge.ServiceName),
+			CreatedBy:      uuid.NullUUID{UUID: customerID, Valid: true},
+		}); err != nil {
+			return err
+		}
+
+		payment = values.HaircutPayment{
+			EventID:        eventID,
+			ServiceName:    charge.ServiceName,
+			PriceCents:     charge.PriceCents,
+			PaidCents:      paid,
+			RemainingCents: charge.PriceCents - paid,
+			BalanceCents:   balance,
+		}
+		return nil
+	})
+	if err != nil {
+		return values.HaircutPayment{}, err
+	}
+	return payment, nil
+}
+
+// VoidCard cancels what is left on an active gift card, e.g. a lost or refunded
+// certificate. Value already redeemed into a balance is not touched.
+func (s *Service) VoidCard(ctx context.Context, id uuid.UUID, reason string) *errLib.CommonError {
+	staffID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		r := s.repo.WithTx(tx)
+
+		card, err := r.LockCard(ctx, id)
+		if err != nil {
+			return err
+		}
+		if card.Status != values.StatusActive {
+			return errLib.New(fmt.Sprintf("Cannot void a %s gift card", card.Status), http.StatusBadRequest)
+		}
+
+		if err = r.VoidCard(ctx, card.ID, staffID, reason); err != nil {
+			return err
+		}
+		if err = r.CreateEntry(ctx, db.CreateLedgerEntryParams{
+			EntryType:   string(values.EntryVoid),
+			GiftCardID:  uuid.NullUUID{UUID: card.ID, Valid: true},
+			AmountCents: card.RemainingCents,
+			Description: voidDescription(reason),
+			CreatedBy:   uuid.NullUUID{UUID: staffID, Valid: true},
+		}); err != nil {
+			return err
+		}
+		return s.staffActivityLogsService.InsertStaffActivity(ctx, tx, staffID,
+			fmt.Sprintf("Voided gift card %s with %s remaining", values.MaskCode(card.Code), formatCents(card.RemainingCents)))
+	})
+}
+
+// ExpireCards lapses the unredeemed value of cards past their expiry and returns how
+// many expired.
+func (s *Service) ExpireCards(ctx context.Context, now time.Time) (int, *errLib.CommonError) {
+	ids, err := s.repo.ListExpiredCardIDs(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, id := range ids {
+		lapsed := false
+		err := txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+			r := s.repo.WithTx(tx)
+
+			card, err := r.LockCard(ctx, id)
+			if err != nil {
+				return err
+			}
+			if card.Status != values.StatusActive || card.ExpiresAt == nil || card.ExpiresAt.After(now) {
+				return nil
+			}
+
+			if err = r.UpdateRemaining(ctx, card.ID, 0, values.StatusExpired); err != nil {
+				return err
+			}
+			lapsed = true
+			return r.CreateEntry(ctx, db.CreateLedgerEntryParams{
+				EntryType:   string(values.EntryExpire),
+				GiftCardID:  uuid.NullUUID{UUID: card.ID, Valid: true},
+				AmountCents: card.RemainingCents,
+				Description: "Expired unredeemed",
+			})
+		})
+		if err != nil {
+			log.Printf("Failed to expire gift card %s: %s", id, err.Message)
+			continue
+		}
+		if lapsed {
+			expired++
+		}
+	}
+	return expired, nil
+}
+
+// ReleaseExpiredHolds releases holds whose checkout expired more than HoldGrace ago
+// and returns how many were released.
+func (s *Service) ReleaseExpiredHolds(ctx context.Context, now time.Time) (int, *errLib.CommonError) {
+	ids, err := s.repo.ListExpiredHoldIDs(ctx, now.Add(-HoldGrace))
+	if err != nil {
+		return 0, err
+	}
+
+	released := 0
+	for _, id := range ids {
+		if err := s.ReleaseHold(ctx, id); err != nil {
+			log.Printf("Failed to release gift card balance hold %s: %s", id, err.Message)
+			continue
+		}
+		released++
+	}
+	return released, nil
+}
+
+// DeleteAbandonedPurchases deletes online purchases left unpaid for PurchaseTTL.
+func (s *Service) DeleteAbandonedPurchases(ctx context.Context, now time.Time) (int64, *errLib.CommonError) {
+	return s.repo.DeleteAbandonedCards(ctx, now.Add(-PurchaseTTL))
+}
+
+// GetCard returns a gift card with its ledger.
+func (s *Service) GetCard(ctx context.Context, id uuid.UUID) (values.GiftCard, []values.LedgerEntry, *errLib.CommonError) {
+	card, err := s.repo.GetCard(ctx, id)
+	if err != nil {
+		return values.GiftCard{}, nil, err
+	}
+	entries, err := s.repo.ListCardEntries(ctx, id)
+	if err != nil {
+		return values.GiftCard{}, nil, err
+	}
+	return card, entries, nil
+}
+
+func (s *Service) ListCards(ctx context.Context, filter values.ListFilter) ([]values.GiftCard, *errLib.CommonError) {
+	return s.repo.ListCards(ctx, filter)
+}
+
+// ListPurchased lists the paid gift cards a customer bought online.
+func (s *Service) ListPurchased(ctx context.Context, customerID uuid.UUID) ([]values.GiftCard, *errLib.CommonError) {
+	return s.repo.ListPurchasedCards(ctx, customerID)
+}
+
+// GetLiabilityReport returns the value owed to gift card holders now, with the
+// ledger activity of [from, to).
+func (s *Service) GetLiabilityReport(ctx context.Context, from, to time.Time) (values.LiabilityReport, *errLib.CommonError) {
+	if !to.After(from) {
+		return values.LiabilityReport{}, errLib.New("'to' must be after 'from'", http.StatusBadRequest)
+	}
+
+	outstanding, err := s.repo.GetOutstandingLiability(ctx)
+	if err != nil {
+		return values.LiabilityReport{}, err
+	}
+	activity, err := s.repo.SumActivity(ctx, from, to)
+	if err != nil {
+		return values.LiabilityReport{}, err
+	}
+
+	return values.LiabilityReport{
+		From:                 from,
+		To:                   to,
+		ActiveCards:          outstanding.ActiveCards,
+		CardCents:            outstanding.CardCents,
+		CustomersWithBalance: outstanding.CustomersWithBalance,
+		BalanceCents:         outstanding.BalanceCents,
+		HeldCents:            outstanding.HeldCents,
+		OutstandingCents:     outstanding.CardCents + outstanding.BalanceCents + outstanding.HeldCents,
+		Activity:             activity,
+	}, nil
+}
+
+func generateCode() (string, *errLib.CommonError) {
+	max := big.NewInt(int64(len(codeAlphabet)))
+	code := make([]byte, codeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			log.Printf("Failed to generate gift card code: %v", err)
+			return "", errLib.New("Failed to generate gift card code", http.StatusInternalServerError)
+		}
+		code[i] = codeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+func validateCode(code string) *errLib.CommonError {
+	if len(code) < minCodeLen || len(code) > maxCodeLen {
+		return errLib.New(fmt.Sprintf("Gift card codes must be %d to %d characters", minCodeLen, maxCodeLen), http.StatusBadRequest)
+	}
+	for _, r := range code {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return errLib.New("Gift card codes may only contain letters and digits", http.StatusBadRequest)
+		}
+	}
+	return nil
+}
+
+func validateAmount(cents int32) *errLib.CommonError {
+	if cents < MinPurchaseCents || cents > MaxPurchaseCents {
+		return errLib.New(fmt.Sprintf("Gift cards must be worth %s to %s",
+			formatCents(MinPurchaseCents), formatCents(MaxPurchaseCents)), http.StatusBadRequest)
+	}
+	return nil
+}
+
+// checkRedeemable reports why a card's value cannot be redeemed at now, if it can't.
+// Pending cards have not been paid for and are treated as unknown.
+func checkRedeemable(card values.GiftCard, now time.Time) *errLib.CommonError {
+	switch card.Status {
+	case values.StatusPending:
+		return errLib.New("Gift card not found", http.StatusNotFound)
+	case values.StatusRedeemed:
+		return errLib.New("This gift card has already been fully redeemed", http.StatusBadRequest)
+	case values.StatusExpired:
+		return errLib.New("This gift card has expired", http.StatusBadRequest)
+	case values.StatusVoid:
+		return errLib.New("This gift card is no longer valid", http.StatusBadRequest)
+	}
+	if card.ExpiresAt != nil && !card.ExpiresAt.After(now) {
+		return errLib.New("This gift card has expired", http.StatusBadRequest)
+	}
+	return nil
+}
+
+// redeemAmount is how much of a card's remaining value to redeem. A nil request takes
+// all of it.
+func redeemAmount(remainingCents int32, requested *int32) (int32, *errLib.CommonError) {
+	if requested == nil {
+		return remainingCents, nil
+	}
+	if *requested <= 0 {
+		return 0, errLib.New("Amount must be positive", http.StatusBadRequest)
+	}
+	if *requested > remainingCents {
+		return 0, errLib.New(fmt.Sprintf("Only %s is left on this gift card", formatCents(remainingCents)), http.StatusBadRequest)
+	}
+	return *requested, nil
+}
+
+func voidDescription(reason string) string {
+	if reason == "" {
+		return "Voided"
+	}
+	return "Voided: " + reason
+}
+
+func formatCents(cents int32) string {
+	return fmt.Sprintf("$%.2f", float64(cents)/100)
+}
+
+func nullString(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *s, Valid: true}
+}
+
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
+
+func nullUUIDPtr(id *uuid.UUID) uuid.NullUUID {
+	if id == nil {
+		return uuid.NullUUID{}
+	}
+	return uuid.NullUUID{UUID: *id, Valid: true}
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
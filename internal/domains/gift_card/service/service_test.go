@@ -0,0 +1,84 @@
+package gift_card
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	values "api/internal/domains/gift_card/values"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodes(t *testing.T) {
+	t.Run("Normalizes what customers type", func(t *testing.T) {
+		assert.Equal(t, "ABCD2345EFGH6789", values.NormalizeCode(" abcd-2345 efgh-6789 "))
+	})
+
+	t.Run("Formats in groups of four", func(t *testing.T) {
+		assert.Equal(t, "ABCD-2345-EFGH-6789", values.FormatCode("ABCD2345EFGH6789"))
+		assert.Equal(t, "ABCD-EF", values.FormatCode("ABCDEF"))
+	})
+
+	t.Run("Masks all but the last four characters", func(t *testing.T) {
+		assert.Equal(t, "****6789", values.MaskCode("ABCD2345EFGH6789"))
+	})
+
+	t.Run("Generates codes that survive normalizing", func(t *testing.T) {
+		code, err := generateCode()
+		require.Nil(t, err)
+		assert.Len(t, code, codeLength)
+		assert.Equal(t, code, values.NormalizeCode(values.FormatCode(code)))
+		assert.Nil(t, validateCode(code))
+	})
+
+	t.Run("Rejects printed codes with other characters", func(t *testing.T) {
+		assert.NotNil(t, validateCode("ABC_1234"))
+		assert.NotNil(t, validateCode("ABC"))
+	})
+}
+
+func TestRedeemAmount(t *testing.T) {
+	amount := func(cents int32) *int32 { return &cents }
+
+	t.Run("Redeems everything by default", func(t *testing.T) {
+		got, err := redeemAmount(2500, nil)
+		require.Nil(t, err)
+		assert.Equal(t, int32(2500), got)
+	})
+
+	t.Run("Redeems part of a card", func(t *testing.T) {
+		got, err := redeemAmount(2500, amount(1000))
+		require.Nil(t, err)
+		assert.Equal(t, int32(1000), got)
+	})
+
+	t.Run("Rejects more than is left", func(t *testing.T) {
+		_, err := redeemAmount(2500, amount(2501))
+		require.NotNil(t, err)
+		assert.Equal(t, http.StatusBadRequest, err.HTTPCode)
+	})
+
+	t.Run("Rejects non-positive amounts", func(t *testing.T) {
+		_, err := redeemAmount(2500, amount(0))
+		assert.NotNil(t, err)
+	})
+}
+
+func TestCheckRedeemable(t *testing.T) {
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	past, future := now.Add(-time.Minute), now.Add(time.Minute)
+
+	assert.Nil(t, checkRedeemable(values.GiftCard{Status: values.StatusActive}, now))
+	assert.Nil(t, checkRedeemable(values.GiftCard{Status: values.StatusActive, ExpiresAt: &future}, now))
+
+	err := checkRedeemable(values.GiftCard{Status: values.StatusPending}, now)
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusNotFound, err.HTTPCode)
+
+	for _, status := range []string{values.StatusRedeemed, values.StatusExpired, values.StatusVoid} {
+		assert.NotNil(t, checkRedeemable(values.GiftCard{Status: status}, now), status)
+	}
+	assert.NotNil(t, checkRedeemable(values.GiftCard{Status: values.StatusActive, ExpiresAt: &past}, now))
+}
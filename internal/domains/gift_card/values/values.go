@@ -0,0 +1,182 @@
+package values
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Gift card statuses. A card bought online is pending until its checkout is paid
+// and redeemed once all of its value has moved into customer balances.
+const (
+	StatusPending  = "pending"
+	StatusActive   = "active"
+	StatusRedeemed = "redeemed"
+	StatusExpired  = "expired"
+	StatusVoid     = "void"
+)
+
+// Balance hold statuses. A hold is captured when its checkout is paid and released
+// when it is not.
+const (
+	HoldHeld     = "held"
+	HoldCaptured = "captured"
+	HoldReleased = "released"
+)
+
+// EntryType says where the value of a ledger entry went.
+type EntryType string
+
+const (
+	EntryIssue   EntryType = "issue"
+	EntryRedeem  EntryType = "redeem"
+	EntrySpend   EntryType = "spend"
+	EntryRelease EntryType = "release"
+	EntryExpire  EntryType = "expire"
+	EntryVoid    EntryType = "void"
+	EntryCredit  EntryType = "credit"
+)
+
+// PurchaseRequest buys a gift card online for someone, delivered by email.
+type PurchaseRequest struct {
+	PurchaserID    uuid.UUID
+	AmountCents    int32
+	RecipientName  string
+	RecipientEmail string
+	Message        *string
+}
+
+// IssueValues issues a gift card for a physical certificate sold at the front desk.
+type IssueValues struct {
+	// Code is the code printed on the certificate; nil generates one.
+	Code           *string
+	AmountCents    int32
+	RecipientName  *string
+	RecipientEmail *string
+	Message        *string
+	ExpiresAt      *time.Time
+}
+
+type GiftCard struct {
+	ID             uuid.UUID
+	Code           string
+	InitialCents   int32
+	RemainingCents int32
+	Currency       string
+	Status         string
+	PurchaserID    *uuid.UUID
+	RecipientName  *string
+	RecipientEmail *string
+	Message        *string
+	IssuedBy       *uuid.UUID
+	IssuedAt       *time.Time
+	ExpiresAt      *time.Time
+	VoidedAt       *time.Time
+	VoidReason     *string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+type LedgerEntry struct {
+	ID             uuid.UUID
+	EntryType      EntryType
+	GiftCardID     *uuid.UUID
+	CustomerID     *uuid.UUID
+	HoldID         *uuid.UUID
+	HaircutEventID *uuid.UUID
+	AmountCents    int32
+	Description    string
+	CreatedBy      *uuid.UUID
+	CreatedAt      time.Time
+}
+
+// Balance is a customer's stored value with their latest balance movements.
+type Balance struct {
+	CustomerID   uuid.UUID
+	BalanceCents int32
+	Entries      []LedgerEntry
+}
+
+// Redemption is the result of moving gift card value into a customer's balance.
+type Redemption struct {
+	Card          GiftCard
+	RedeemedCents int32
+	BalanceCents  int32
+}
+
+// Hold is balance set aside for a checkout. A zero ID means no balance was held.
+type Hold struct {
+	ID          uuid.UUID
+	AmountCents int32
+	ExpiresAt   time.Time
+}
+
+// HaircutPayment is the part of a haircut paid from the customer's balance. The
+// rest, if any, is paid at the shop.
+type HaircutPayment struct {
+	EventID        uuid.UUID
+	ServiceName    string
+	PriceCents     int32
+	PaidCents      int32
+	RemainingCents int32
+	BalanceCents   int32
+}
+
+type ListFilter struct {
+	Status      string
+	PurchaserID uuid.UUID
+	Limit       int32
+	Offset      int32
+}
+
+// LedgerActivity totals the ledger entries of one type in a reporting period.
+type LedgerActivity struct {
+	EntryType   EntryType
+	Entries     int64
+	AmountCents int64
+}
+
+// LiabilityReport is the value owed to gift card holders now, with the ledger
+// activity of [From, To).
+type LiabilityReport struct {
+	From                 time.Time
+	To                   time.Time
+	ActiveCards          int64
+	CardCents            int64
+	CustomersWithBalance int64
+	BalanceCents         int64
+	HeldCents            int64
+	OutstandingCents     int64
+	Activity             []LedgerActivity
+}
+
+// NormalizeCode uppercases a code and strips the spaces and dashes it is displayed with.
+func NormalizeCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(code)))
+}
+
+// FormatCode displays a code in dash-separated groups of four.
+func FormatCode(code string) string {
+	var b strings.Builder
+	for i, r := range code {
+		if i > 0 && i%4 == 0 {
+			b.WriteByte('-')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// MaskCode hides all but the last four characters of a code.
+func MaskCode(code string) string {
+	if len(code) <= 4 {
+		return code
+	}
+	return "****" + code[len(code)-4:]
+}
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	contextUtils "api/utils/context"
+	"api/utils/timezone"
 )
 
 // EventsHandler provides HTTP handlers for managing events.
@@ -246,14 +247,17 @@ func (h *EventsHandler) GetAvailableTimeSlots(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	date, parseErr := time.Parse("2006-01-02", dateStr)
+	// Barbers are not tied to a location, so the date is interpreted in the default facility timezone.
+	loc := timezone.Default()
+
+	date, parseErr := time.ParseInLocation("2006-01-02", dateStr, loc)
 	if parseErr != nil {
 		responseHandlers.RespondWithError(w, errLib.New("invalid date format, expected YYYY-MM-DD", http.StatusBadRequest))
 		return
 	}
 
 	// Validate date is not in the past
-	today := time.Now().Truncate(24 * time.Hour)
+	today, _ := timezone.DayBounds(time.Now(), loc)
 	if date.Before(today) {
 		responseHandlers.RespondWithError(w, errLib.New("cannot get availability for past dates", http.StatusBadRequest))
 		return
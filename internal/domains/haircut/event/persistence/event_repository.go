@@ -6,6 +6,7 @@ import (
 	db "api/internal/domains/haircut/event/persistence/sqlc/generated"
 	service "api/internal/domains/haircut/haircut_service/persistence"
 	errLib "api/internal/libs/errors"
+	"api/utils/timezone"
 	"context"
 	"database/sql"
 	"errors"
@@ -280,10 +281,13 @@ func (r *Repository) GetAvailableTimeSlots(ctx context.Context, barberID uuid.UU
 		return []string{}, nil
 	}
 
-	// Get existing bookings for this date
+	// Get existing bookings for this date. The day is bounded in the date's own
+	// location so bookings late in the evening are not attributed to the next UTC day.
+	dayStart, dayEnd := timezone.DayBounds(date, date.Location())
 	bookings, err := r.Queries.GetBarberBookingsForDate(ctx, db.GetBarberBookingsForDateParams{
-		BarberID:      barberID,
-		BeginDateTime: date,
+		BarberID: barberID,
+		DayEnd:   dayEnd,
+		DayStart: dayStart,
 	})
 
 	if err != nil {
@@ -206,14 +206,16 @@ func (q *Queries) GetBarberAvailabilityByID(ctx context.Context, id uuid.UUID) (
 const getBarberBookingsForDate = `-- name: GetBarberBookingsForDate :many
 SELECT begin_date_time, end_date_time
 FROM haircut.events
-WHERE barber_id = $1 
-  AND DATE(begin_date_time) = $2
+WHERE barber_id = $1
+  AND begin_date_time < $2
+  AND end_date_time > $3
 ORDER BY begin_date_time
 `
 
 type GetBarberBookingsForDateParams struct {
-	BarberID      uuid.UUID `json:"barber_id"`
-	BeginDateTime time.Time `json:"begin_date_time"`
+	BarberID uuid.UUID `json:"barber_id"`
+	DayEnd   time.Time `json:"day_end"`
+	DayStart time.Time `json:"day_start"`
 }
 
 type GetBarberBookingsForDateRow struct {
@@ -222,7 +224,7 @@ type GetBarberBookingsForDateRow struct {
 }
 
 func (q *Queries) GetBarberBookingsForDate(ctx context.Context, arg GetBarberBookingsForDateParams) ([]GetBarberBookingsForDateRow, error) {
-	rows, err := q.db.QueryContext(ctx, getBarberBookingsForDate, arg.BarberID, arg.BeginDateTime)
+	rows, err := q.db.QueryContext(ctx, getBarberBookingsForDate, arg.BarberID, arg.DayEnd, arg.DayStart)
 	if err != nil {
 		return nil, err
 	}
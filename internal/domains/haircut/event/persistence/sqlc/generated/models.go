@@ -573,6 +573,8 @@ type LocationLocation struct {
 	Address   string    `json:"address"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	Timezone  string    `json:"timezone"`
+	Province  string    `json:"province"`
 }
 
 type MembershipDiscountRestrictedMembershipPlan struct {
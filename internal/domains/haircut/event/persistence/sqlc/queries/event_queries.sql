@@ -58,8 +58,9 @@ ORDER BY day_of_week, start_time;
 -- name: GetBarberBookingsForDate :many
 SELECT begin_date_time, end_date_time
 FROM haircut.events
-WHERE barber_id = $1 
-  AND DATE(begin_date_time) = $2
+WHERE barber_id = $1
+  AND begin_date_time < sqlc.arg('day_end')
+  AND end_date_time > sqlc.arg('day_start')
 ORDER BY begin_date_time;
 
 -- name: CreateBarberAvailability :one
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"api/internal/di"
+	auditLogs "api/internal/domains/audit/audit_logs/service"
 	"api/internal/security"
 )
 
@@ -294,7 +295,7 @@ func (h *HealthHandler) WebhookRetryStats(w http.ResponseWriter, r *http.Request
 // @Success 200 {object} map[string]interface{} "Security audit results"
 // @Router /health/security-audit [get]
 func (h *HealthHandler) SecurityAudit(w http.ResponseWriter, r *http.Request) {
-	audit := security.NewSecurityAudit()
+	audit := security.NewSecurityAudit(auditLogs.NewService(h.Container))
 	result := audit.PerformComprehensiveAudit(r.Context())
 	
 	// Set appropriate status code based on security level
@@ -18,6 +18,8 @@ type AthleteRegistrationRequestDto struct {
 	EmergencyContactName            string                    `json:"emergency_contact_name" validate:"omitempty,max=100"`
 	EmergencyContactPhone           string                    `json:"emergency_contact_phone" validate:"omitempty,max=25"`
 	EmergencyContactRelationship    string                    `json:"emergency_contact_relationship" validate:"omitempty,max=50"`
+	ReferralCode                    *string                   `json:"referral_code,omitempty" validate:"omitempty,max=20" example:"K7QX3MPA"` // Code of the customer who invited them
+	DeviceID                        *string                   `json:"device_id,omitempty" validate:"omitempty,max=200"`                       // App install ID, used to catch self-referrals
 }
 
 // ToAthlete validates the DTO and converts waiver signing details into value objects.
@@ -75,5 +77,7 @@ func (dto AthleteRegistrationRequestDto) ToAthlete(email string) (values.Athlete
 		EmergencyContactName:         dto.EmergencyContactName,
 		EmergencyContactPhone:        dto.EmergencyContactPhone,
 		EmergencyContactRelationship: dto.EmergencyContactRelationship,
+		ReferralCode:                 dto.ReferralCode,
+		DeviceID:                     dto.DeviceID,
 	}, nil
 }
@@ -12,9 +12,11 @@ import (
 
 type ParentRegistrationRequestDto struct {
 	commonDto.UserBaseInfoRequestDto
-	PhoneNumber                string `json:"phone_number" validate:"e164" example:"+15141234567"`
-	HasConsentToSmS            bool   `json:"has_consent_to_sms"`
-	HasConsentToEmailMarketing bool   `json:"has_consent_to_email_marketing"`
+	PhoneNumber                string  `json:"phone_number" validate:"e164" example:"+15141234567"`
+	HasConsentToSmS            bool    `json:"has_consent_to_sms"`
+	HasConsentToEmailMarketing bool    `json:"has_consent_to_email_marketing"`
+	ReferralCode               *string `json:"referral_code,omitempty" validate:"omitempty,max=20" example:"K7QX3MPA"` // Code of the customer who invited them
+	DeviceID                   *string `json:"device_id,omitempty" validate:"omitempty,max=200"`                       // App install ID, used to catch self-referrals
 }
 
 // ToParent validates the DTO and converts waiver signing details into value objects.
@@ -49,5 +51,7 @@ func (dto ParentRegistrationRequestDto) ToParent(email string) (values.ParentReg
 		Phone:                      dto.PhoneNumber,
 		HasConsentToSms:            dto.HasConsentToSmS,
 		HasConsentToEmailMarketing: dto.HasConsentToEmailMarketing,
+		ReferralCode:               dto.ReferralCode,
+		DeviceID:                   dto.DeviceID,
 	}, nil
 }
@@ -1555,8 +1555,9 @@ type UsersUser struct {
 	// Timestamp when the user last changed their email address.
 	EmailChangedAt sql.NullTime `json:"email_changed_at"`
 	// Timestamp when account was archived. Archived accounts are permanently deleted after 30 days.
-	ArchivedAt  sql.NullTime   `json:"archived_at"`
-	AccountType sql.NullString `json:"account_type"`
+	ArchivedAt         sql.NullTime   `json:"archived_at"`
+	AccountType        sql.NullString `json:"account_type"`
+	StoredValueBalance int32          `json:"stored_value_balance"`
 }
 
 // Tracks weekly credit consumption per customer for membership limit enforcement
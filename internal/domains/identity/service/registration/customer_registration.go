@@ -7,6 +7,8 @@ import (
 	"api/internal/domains/identity/persistence/repository/user"
 	"api/internal/domains/identity/service/email_verification"
 	"api/internal/domains/identity/values"
+	referralService "api/internal/domains/referral/service"
+	referralValues "api/internal/domains/referral/values"
 	errLib "api/internal/libs/errors"
 	"context"
 	"database/sql"
@@ -22,6 +24,7 @@ type CustomerRegistrationService struct {
 	UserRepo            *user.UsersRepository
 	WaiverSigningRepo   *repo.WaiverSigningRepository
 	VerificationService *email_verification.EmailVerificationService
+	ReferralService     *referralService.Service
 	DB                  *sql.DB
 }
 
@@ -31,6 +34,7 @@ func NewCustomerRegistrationService(container *di.Container) *CustomerRegistrati
 		UserRepo:            user.NewUserRepository(container),
 		WaiverSigningRepo:   repo.NewWaiverSigningRepository(container),
 		VerificationService: email_verification.NewEmailVerificationService(container),
+		ReferralService:     referralService.NewService(container),
 		DB:                  container.DB,
 	}
 }
@@ -95,6 +99,8 @@ func (s *CustomerRegistrationService) RegisterAthlete(
 		return identity.UserReadInfo{}, errLib.New("Failed to commit transaction", http.StatusInternalServerError)
 	}
 
+	s.recordReferral(ctx, createdUserInfo.ID, customer.Email, customer.ReferralCode, customer.DeviceID)
+
 	// Send email verification instead of signup confirmation
 	if createdUserInfo.Email != nil {
 		// Generate verification token
@@ -163,6 +169,8 @@ func (s *CustomerRegistrationService) RegisterParent(
 		return response, errLib.New("Failed to commit transaction", http.StatusInternalServerError)
 	}
 
+	s.recordReferral(ctx, userInfo.ID, customer.Email, customer.ReferralCode, customer.DeviceID)
+
 	// Send email verification instead of signup confirmation
 	if userInfo.Email != nil {
 		// Generate verification token
@@ -191,6 +199,19 @@ func (s *CustomerRegistrationService) RegisterParent(
 	return userInfo, nil
 }
 
+// recordReferral records the referral a new customer signed up with. A bad or unknown
+// code does not fail the registration.
+func (s *CustomerRegistrationService) recordReferral(ctx context.Context, customerID uuid.UUID, email string, code, deviceID *string) {
+	if err := s.ReferralService.RecordRegistration(ctx, referralValues.Registration{
+		CustomerID: customerID,
+		Email:      email,
+		Code:       code,
+		DeviceID:   deviceID,
+	}); err != nil {
+		log.Printf("Failed to record referral of new customer %s: %s", customerID, err.Message)
+	}
+}
+
 func validateWaivers(customerWaivers []identity.CustomerWaiverSigning, requiredWaivers []identity.Waiver) *errLib.CommonError {
 	for _, waiver := range requiredWaivers {
 		found := false
@@ -71,6 +71,8 @@ type AthleteRegistrationRequestInfo struct {
 	EmergencyContactName         string
 	EmergencyContactPhone        string
 	EmergencyContactRelationship string
+	ReferralCode                 *string
+	DeviceID                     *string
 }
 
 type ParentRegistrationRequestInfo struct {
@@ -79,6 +81,8 @@ type ParentRegistrationRequestInfo struct {
 	Phone                      string
 	HasConsentToSms            bool
 	HasConsentToEmailMarketing bool
+	ReferralCode               *string
+	DeviceID                   *string
 }
 
 type ChildRegistrationRequestInfo struct {
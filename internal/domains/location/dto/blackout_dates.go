@@ -0,0 +1,79 @@
+package location
+
+import (
+	"net/http"
+	"time"
+
+	values "api/internal/domains/location/values"
+	errLib "api/internal/libs/errors"
+	"api/internal/libs/validators"
+	"api/utils/recurrence"
+
+	"github.com/google/uuid"
+)
+
+// BlackoutDateRequestDto creates a holiday or closure at a location. Recurring events
+// and practices generated on these dates are skipped, or created and flagged for
+// review when action is "flag".
+type BlackoutDateRequestDto struct {
+	Name     string `json:"name" validate:"required,notwhitespace,max=150" example:"Christmas closure"`
+	StartsOn string `json:"starts_on" validate:"required" example:"2025-12-24"`
+	EndsOn   string `json:"ends_on" validate:"required" example:"2025-12-26"`
+	Action   string `json:"action" validate:"omitempty,oneof=skip flag" example:"skip"` // Defaults to "skip"
+}
+
+type BlackoutDateResponseDto struct {
+	ID         uuid.UUID `json:"id"`
+	LocationID uuid.UUID `json:"location_id"`
+	Name       string    `json:"name"`
+	StartsOn   string    `json:"starts_on"`
+	EndsOn     string    `json:"ends_on"`
+	Action     string    `json:"action"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (dto *BlackoutDateRequestDto) ToCreateValues(locationID, creator uuid.UUID) (values.CreateBlackoutDateValues, *errLib.CommonError) {
+	if err := validators.ValidateDto(dto); err != nil {
+		return values.CreateBlackoutDateValues{}, err
+	}
+
+	startsOn, err := validators.ParseDate(dto.StartsOn)
+	if err != nil {
+		return values.CreateBlackoutDateValues{}, err
+	}
+
+	endsOn, err := validators.ParseDate(dto.EndsOn)
+	if err != nil {
+		return values.CreateBlackoutDateValues{}, err
+	}
+
+	if endsOn.Before(startsOn) {
+		return values.CreateBlackoutDateValues{}, errLib.New("ends_on must not be before starts_on", http.StatusBadRequest)
+	}
+
+	action := recurrence.BlackoutSkip
+	if dto.Action != "" {
+		action = recurrence.BlackoutAction(dto.Action)
+	}
+
+	return values.CreateBlackoutDateValues{
+		LocationID: locationID,
+		Name:       dto.Name,
+		StartsOn:   startsOn,
+		EndsOn:     endsOn,
+		Action:     action,
+		CreatedBy:  creator,
+	}, nil
+}
+
+func NewBlackoutDateResponse(b values.BlackoutDate) BlackoutDateResponseDto {
+	return BlackoutDateResponseDto{
+		ID:         b.ID,
+		LocationID: b.LocationID,
+		Name:       b.Name,
+		StartsOn:   b.StartsOn.Format("2006-01-02"),
+		EndsOn:     b.EndsOn.Format("2006-01-02"),
+		Action:     string(b.Action),
+		CreatedAt:  b.CreatedAt,
+	}
+}
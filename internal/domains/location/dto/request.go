@@ -4,6 +4,7 @@ import (
 	values "api/internal/domains/location/values"
 	errLib "api/internal/libs/errors"
 	"api/internal/libs/validators"
+	"api/utils/timezone"
 )
 
 // RequestDto represents the data transfer object for facility-related requests.
@@ -11,6 +12,10 @@ import (
 type RequestDto struct {
 	Name    string `json:"name" validate:"required,notwhitespace"`
 	Address string `json:"address" validate:"required,notwhitespace"`
+	// Timezone is an IANA zone name. Defaults to America/Edmonton on create and is left unchanged on update when omitted.
+	Timezone string `json:"timezone" validate:"omitempty,timezone" example:"America/Edmonton"`
+	// Province is the two-letter province or territory code used for sales tax. Defaults to AB on create and is left unchanged on update when omitted.
+	Province string `json:"province" validate:"omitempty,oneof=AB BC MB NB NL NS NT NU ON PE QC SK YT" example:"AB"`
 }
 
 // ToCreateDetails converts the FacilityRequestDto into a FacilityCreate value object.
@@ -21,10 +26,22 @@ func (dto *RequestDto) ToCreateDetails() (values.CreateDetails, *errLib.CommonEr
 		return values.CreateDetails{}, err
 	}
 
+	tz := dto.Timezone
+	if tz == "" {
+		tz = timezone.DefaultZone
+	}
+
+	province := dto.Province
+	if province == "" {
+		province = values.DefaultProvince
+	}
+
 	return values.CreateDetails{
 		BaseDetails: values.BaseDetails{
-			Name:    dto.Name,
-			Address: dto.Address,
+			Name:     dto.Name,
+			Address:  dto.Address,
+			Timezone: tz,
+			Province: province,
 		},
 	}, nil
 }
@@ -45,8 +62,10 @@ func (dto *RequestDto) ToUpdateDetails(idStr string) (values.UpdateDetails, *err
 	return values.UpdateDetails{
 		ID: id,
 		BaseDetails: values.BaseDetails{
-			Name:    dto.Name,
-			Address: dto.Address,
+			Name:     dto.Name,
+			Address:  dto.Address,
+			Timezone: dto.Timezone,
+			Province: dto.Province,
 		},
 	}, nil
 }
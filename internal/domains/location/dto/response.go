@@ -6,15 +6,19 @@ import (
 )
 
 type ResponseDto struct {
-	ID      uuid.UUID `json:"id"`
-	Name    string    `json:"name"`
-	Address string    `json:"address"`
+	ID       uuid.UUID `json:"id"`
+	Name     string    `json:"name"`
+	Address  string    `json:"address"`
+	Timezone string    `json:"timezone"`
+	Province string    `json:"province"`
 }
 
 func NewLocationResponse(facility values.ReadValues) ResponseDto {
 	return ResponseDto{
-		ID:      facility.ID,
-		Name:    facility.Name,
-		Address: facility.Address,
+		ID:       facility.ID,
+		Name:     facility.Name,
+		Address:  facility.Address,
+		Timezone: facility.Timezone,
+		Province: facility.Province,
 	}
 }
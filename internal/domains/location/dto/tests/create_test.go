@@ -82,3 +82,50 @@ func TestDecodeCreateFacilityRequestBody(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateDetailsTimezone(t *testing.T) {
+	tests := []struct {
+		name          string
+		timezone      string
+		expectError   bool
+		expectedZone  string
+		expectedError string
+	}{
+		{
+			name:         "Defaults to America/Edmonton",
+			timezone:     "",
+			expectedZone: "America/Edmonton",
+		},
+		{
+			name:         "Accepts IANA zone",
+			timezone:     "America/Toronto",
+			expectedZone: "America/Toronto",
+		},
+		{
+			name:          "Rejects unknown zone",
+			timezone:      "Mountain Time",
+			expectError:   true,
+			expectedError: "timezone: must be a valid IANA timezone",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requestDto := dto.RequestDto{
+				Name:     "Facility A",
+				Address:  "Address A",
+				Timezone: tt.timezone,
+			}
+
+			details, err := requestDto.ToCreateDetails()
+			if tt.expectError {
+				assert.NotNil(t, err)
+				assert.Contains(t, err.Message, tt.expectedError)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expectedZone, details.Timezone)
+		})
+	}
+}
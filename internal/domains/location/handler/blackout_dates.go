@@ -0,0 +1,141 @@
+package location
+
+import (
+	"net/http"
+	"time"
+
+	dto "api/internal/domains/location/dto"
+	responseHandlers "api/internal/libs/responses"
+	"api/internal/libs/validators"
+	contextUtils "api/utils/context"
+
+	"github.com/go-chi/chi"
+)
+
+// GetBlackoutDates lists the holiday and closure dates of a location.
+// @Tags locations
+// @Accept json
+// @Produce json
+// @Param id path string true "Location UUID"
+// @Param from query string false "Only blackouts ending on or after this date (YYYY-MM-DD)"
+// @Param to query string false "Only blackouts starting on or before this date (YYYY-MM-DD)"
+// @Success 200 {array} dto.BlackoutDateResponseDto "Blackout dates"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid input"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /locations/{id}/blackouts [get]
+func (h *Handler) GetBlackoutDates(w http.ResponseWriter, r *http.Request) {
+	locationID, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	var from, to time.Time
+	if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+		if from, err = validators.ParseDate(fromStr); err != nil {
+			responseHandlers.RespondWithError(w, err)
+			return
+		}
+	}
+	if toStr := r.URL.Query().Get("to"); toStr != "" {
+		if to, err = validators.ParseDate(toStr); err != nil {
+			responseHandlers.RespondWithError(w, err)
+			return
+		}
+	}
+
+	blackouts, err := h.Service.GetBlackoutDates(r.Context(), locationID, from, to)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	result := make([]dto.BlackoutDateResponseDto, len(blackouts))
+	for i, blackout := range blackouts {
+		result[i] = dto.NewBlackoutDateResponse(blackout)
+	}
+
+	responseHandlers.RespondWithSuccess(w, result, http.StatusOK)
+}
+
+// CreateBlackoutDate adds a holiday or closure to a location's calendar.
+// Recurring events and practices generated afterwards skip these dates, or are
+// created and flagged when the blackout's action is "flag".
+// @Tags locations
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Location UUID"
+// @Param body body dto.BlackoutDateRequestDto true "Blackout details"
+// @Success 201 {object} dto.BlackoutDateResponseDto "Blackout created"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid input"
+// @Failure 404 {object} map[string]interface{} "Not Found: Location not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /locations/{id}/blackouts [post]
+func (h *Handler) CreateBlackoutDate(w http.ResponseWriter, r *http.Request) {
+	locationID, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	staffID, err := contextUtils.GetUserID(r.Context())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	var requestDto dto.BlackoutDateRequestDto
+	if err = validators.ParseJSON(r.Body, &requestDto); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	details, err := requestDto.ToCreateValues(locationID, staffID)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	blackout, err := h.Service.CreateBlackoutDate(r.Context(), details)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	responseHandlers.RespondWithSuccess(w, dto.NewBlackoutDateResponse(blackout), http.StatusCreated)
+}
+
+// DeleteBlackoutDate removes a blackout from a location. Occurrences already
+// flagged by it lose the flag; skipped occurrences are not recreated.
+// @Tags locations
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Location UUID"
+// @Param blackout_id path string true "Blackout UUID"
+// @Success 204 "No Content: Blackout deleted"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid UUID"
+// @Failure 404 {object} map[string]interface{} "Not Found: Blackout not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /locations/{id}/blackouts/{blackout_id} [delete]
+func (h *Handler) DeleteBlackoutDate(w http.ResponseWriter, r *http.Request) {
+	locationID, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	blackoutID, err := validators.ParseUUID(chi.URLParam(r, "blackout_id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	if err = h.Service.DeleteBlackoutDate(r.Context(), locationID, blackoutID); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	responseHandlers.RespondWithSuccess(w, nil, http.StatusNoContent)
+}
@@ -50,11 +50,7 @@ func (h *Handler) CreateLocation(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	responseBody := dto.ResponseDto{
-		ID:      location.ID,
-		Name:    location.Name,
-		Address: location.Address,
-	}
+	responseBody := dto.NewLocationResponse(location)
 
 	responseHandlers.RespondWithSuccess(w, responseBody, http.StatusCreated)
 }
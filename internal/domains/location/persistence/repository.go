@@ -6,11 +6,13 @@ import (
 	db "api/internal/domains/location/persistence/sqlc/generated"
 	values "api/internal/domains/location/values"
 	errLib "api/internal/libs/errors"
+	"api/utils/recurrence"
 	"context"
 	"database/sql"
 	"errors"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/lib/pq"
@@ -43,8 +45,10 @@ func (r *Repository) CreateLocation(c context.Context, Location values.CreateDet
 	var response values.ReadValues
 
 	dbParams := db.CreateLocationParams{
-		Name:    Location.Name,
-		Address: Location.Address,
+		Name:     Location.Name,
+		Address:  Location.Address,
+		Timezone: Location.Timezone,
+		Province: Location.Province,
 	}
 
 	dbLocation, err := r.Queries.CreateLocation(c, dbParams)
@@ -64,8 +68,10 @@ func (r *Repository) CreateLocation(c context.Context, Location values.CreateDet
 	return values.ReadValues{
 		ID: dbLocation.ID,
 		BaseDetails: values.BaseDetails{
-			Name:    dbLocation.Name,
-			Address: dbLocation.Address,
+			Name:     dbLocation.Name,
+			Address:  dbLocation.Address,
+			Timezone: dbLocation.Timezone,
+			Province: dbLocation.Province,
 		},
 	}, nil
 }
@@ -86,8 +92,10 @@ func (r *Repository) GetLocationByID(c context.Context, id uuid.UUID) (values.Re
 	return values.ReadValues{
 		ID: Location.ID,
 		BaseDetails: values.BaseDetails{
-			Name:    Location.Name,
-			Address: Location.Address,
+			Name:     Location.Name,
+			Address:  Location.Address,
+			Timezone: Location.Timezone,
+			Province: Location.Province,
 		},
 	}, nil
 }
@@ -105,8 +113,10 @@ func (r *Repository) GetLocations(ctx context.Context) ([]values.ReadValues, *er
 		courses[i] = values.ReadValues{
 			ID: dbLocation.ID,
 			BaseDetails: values.BaseDetails{
-				Name:    dbLocation.Name,
-				Address: dbLocation.Address,
+				Name:     dbLocation.Name,
+				Address:  dbLocation.Address,
+				Timezone: dbLocation.Timezone,
+				Province: dbLocation.Province,
 			},
 		}
 	}
@@ -120,6 +130,14 @@ func (r *Repository) UpdateLocation(c context.Context, Location values.UpdateDet
 		ID:      Location.ID,
 		Name:    Location.Name,
 		Address: Location.Address,
+		Timezone: sql.NullString{
+			String: Location.Timezone,
+			Valid:  Location.Timezone != "",
+		},
+		Province: sql.NullString{
+			String: Location.Province,
+			Valid:  Location.Province != "",
+		},
 	}
 
 	row, err := r.Queries.UpdateLocation(c, dbParams)
@@ -162,3 +180,82 @@ func (r *Repository) DeleteLocation(c context.Context, id uuid.UUID) *errLib.Com
 
 	return nil
 }
+
+func (r *Repository) CreateBlackoutDate(c context.Context, details values.CreateBlackoutDateValues) (values.BlackoutDate, *errLib.CommonError) {
+	dbBlackout, err := r.Queries.CreateBlackoutDate(c, db.CreateBlackoutDateParams{
+		LocationID: details.LocationID,
+		Name:       details.Name,
+		StartsOn:   details.StartsOn,
+		EndsOn:     details.EndsOn,
+		Action:     string(details.Action),
+		CreatedBy:  uuid.NullUUID{UUID: details.CreatedBy, Valid: details.CreatedBy != uuid.Nil},
+	})
+
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) {
+			switch pqErr.Code {
+			case databaseErrors.ForeignKeyViolation:
+				return values.BlackoutDate{}, errLib.New("Location not found", http.StatusNotFound)
+			case databaseErrors.CheckViolation:
+				return values.BlackoutDate{}, errLib.New("Blackout end date must not be before its start date", http.StatusBadRequest)
+			}
+		}
+		log.Printf("Error creating blackout date for location %s: %v", details.LocationID, err)
+		return values.BlackoutDate{}, errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+
+	return mapDbBlackoutDate(dbBlackout), nil
+}
+
+// GetBlackoutDates returns the blackouts at a location overlapping [from, to]. Zero
+// bounds are open-ended.
+func (r *Repository) GetBlackoutDates(c context.Context, locationID uuid.UUID, from, to time.Time) ([]values.BlackoutDate, *errLib.CommonError) {
+	dbBlackouts, err := r.Queries.ListBlackoutDates(c, db.ListBlackoutDatesParams{
+		LocationID: locationID,
+		From:       sql.NullTime{Time: from, Valid: !from.IsZero()},
+		To:         sql.NullTime{Time: to, Valid: !to.IsZero()},
+	})
+
+	if err != nil {
+		log.Printf("Error retrieving blackout dates for location %s: %v", locationID, err)
+		return nil, errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+
+	blackouts := make([]values.BlackoutDate, len(dbBlackouts))
+	for i, dbBlackout := range dbBlackouts {
+		blackouts[i] = mapDbBlackoutDate(dbBlackout)
+	}
+
+	return blackouts, nil
+}
+
+func (r *Repository) DeleteBlackoutDate(c context.Context, locationID, id uuid.UUID) *errLib.CommonError {
+	row, err := r.Queries.DeleteBlackoutDate(c, db.DeleteBlackoutDateParams{
+		ID:         id,
+		LocationID: locationID,
+	})
+
+	if err != nil {
+		log.Printf("Error deleting blackout date %s: %v", id, err)
+		return errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+
+	if row == 0 {
+		return errLib.New("Blackout date not found", http.StatusNotFound)
+	}
+
+	return nil
+}
+
+func mapDbBlackoutDate(b db.LocationBlackoutDate) values.BlackoutDate {
+	return values.BlackoutDate{
+		ID:         b.ID,
+		LocationID: b.LocationID,
+		Name:       b.Name,
+		StartsOn:   b.StartsOn,
+		EndsOn:     b.EndsOn,
+		Action:     recurrence.BlackoutAction(b.Action),
+		CreatedAt:  b.CreatedAt,
+	}
+}
@@ -0,0 +1,18 @@
+-- name: CreateBlackoutDate :one
+INSERT INTO location.blackout_dates (location_id, name, starts_on, ends_on, action, created_by)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: ListBlackoutDates :many
+SELECT *
+FROM location.blackout_dates
+WHERE location_id = $1
+  AND (sqlc.narg('from')::date IS NULL OR ends_on >= sqlc.narg('from')::date)
+  AND (sqlc.narg('to')::date IS NULL OR starts_on <= sqlc.narg('to')::date)
+ORDER BY starts_on;
+
+-- name: DeleteBlackoutDate :execrows
+DELETE
+FROM location.blackout_dates
+WHERE id = $1
+  AND location_id = $2;
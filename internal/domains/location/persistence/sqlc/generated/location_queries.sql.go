@@ -7,30 +7,48 @@ package db_location
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/google/uuid"
 )
 
 const createLocation = `-- name: CreateLocation :one
-INSERT INTO location.locations (name, address)
-    VALUES ($1, $2)
-    RETURNING id, name, address
+INSERT INTO location.locations (name, address, timezone, province)
+VALUES ($1, $2, $3, $4)
+RETURNING id, name, address, created_at, updated_at, timezone, province
 `
 
 type CreateLocationParams struct {
-	Name    string `json:"name"`
-	Address string `json:"address"`
+	Name     string `json:"name"`
+	Address  string `json:"address"`
+	Timezone string `json:"timezone"`
+	Province string `json:"province"`
 }
 
 func (q *Queries) CreateLocation(ctx context.Context, arg CreateLocationParams) (LocationLocation, error) {
-	row := q.db.QueryRowContext(ctx, createLocation, arg.Name, arg.Address)
+	row := q.db.QueryRowContext(ctx, createLocation,
+		arg.Name,
+		arg.Address,
+		arg.Timezone,
+		arg.Province,
+	)
 	var i LocationLocation
-	err := row.Scan(&i.ID, &i.Name, &i.Address)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Address,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Timezone,
+		&i.Province,
+	)
 	return i, err
 }
 
 const deleteLocation = `-- name: DeleteLocation :execrows
-DELETE FROM location.locations WHERE id = $1
+DELETE
+FROM location.locations
+WHERE id = $1
 `
 
 func (q *Queries) DeleteLocation(ctx context.Context, id uuid.UUID) (int64, error) {
@@ -42,18 +60,29 @@ func (q *Queries) DeleteLocation(ctx context.Context, id uuid.UUID) (int64, erro
 }
 
 const getLocationById = `-- name: GetLocationById :one
-SELECT id, name, address from location.locations WHERE id = $1
+SELECT id, name, address, created_at, updated_at, timezone, province
+from location.locations
+WHERE id = $1
 `
 
 func (q *Queries) GetLocationById(ctx context.Context, id uuid.UUID) (LocationLocation, error) {
 	row := q.db.QueryRowContext(ctx, getLocationById, id)
 	var i LocationLocation
-	err := row.Scan(&i.ID, &i.Name, &i.Address)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Address,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Timezone,
+		&i.Province,
+	)
 	return i, err
 }
 
 const getLocations = `-- name: GetLocations :many
-SELECT id, name, address from location.locations
+SELECT id, name, address, created_at, updated_at, timezone, province
+from location.locations
 `
 
 func (q *Queries) GetLocations(ctx context.Context) ([]LocationLocation, error) {
@@ -65,7 +94,15 @@ func (q *Queries) GetLocations(ctx context.Context) ([]LocationLocation, error)
 	var items []LocationLocation
 	for rows.Next() {
 		var i LocationLocation
-		if err := rows.Scan(&i.ID, &i.Name, &i.Address); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Address,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Timezone,
+			&i.Province,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -81,18 +118,29 @@ func (q *Queries) GetLocations(ctx context.Context) ([]LocationLocation, error)
 
 const updateLocation = `-- name: UpdateLocation :execrows
 UPDATE location.locations
-    SET name = $1, address = $2
-    WHERE id = $3
+SET name     = $1,
+    address  = $2,
+    timezone = COALESCE($4, timezone),
+    province = COALESCE($5, province)
+WHERE id = $3
 `
 
 type UpdateLocationParams struct {
-	Name    string    `json:"name"`
-	Address string    `json:"address"`
-	ID      uuid.UUID `json:"id"`
+	Name     string         `json:"name"`
+	Address  string         `json:"address"`
+	ID       uuid.UUID      `json:"id"`
+	Timezone sql.NullString `json:"timezone"`
+	Province sql.NullString `json:"province"`
 }
 
 func (q *Queries) UpdateLocation(ctx context.Context, arg UpdateLocationParams) (int64, error) {
-	result, err := q.db.ExecContext(ctx, updateLocation, arg.Name, arg.Address, arg.ID)
+	result, err := q.db.ExecContext(ctx, updateLocation,
+		arg.Name,
+		arg.Address,
+		arg.ID,
+		arg.Timezone,
+		arg.Province,
+	)
 	if err != nil {
 		return 0, err
 	}
@@ -292,10 +292,25 @@ type Game struct {
 	VideoLink sql.NullString `json:"video_link"`
 }
 
+type LocationBlackoutDate struct {
+	ID         uuid.UUID     `json:"id"`
+	LocationID uuid.UUID     `json:"location_id"`
+	Name       string        `json:"name"`
+	StartsOn   time.Time     `json:"starts_on"`
+	EndsOn     time.Time     `json:"ends_on"`
+	Action     string        `json:"action"`
+	CreatedBy  uuid.NullUUID `json:"created_by"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
 type LocationLocation struct {
-	ID      uuid.UUID `json:"id"`
-	Name    string    `json:"name"`
-	Address string    `json:"address"`
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Address   string    `json:"address"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Timezone  string    `json:"timezone"`
+	Province  string    `json:"province"`
 }
 
 type MembershipMembership struct {
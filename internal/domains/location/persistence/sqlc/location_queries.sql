@@ -1,6 +1,6 @@
 -- name: CreateLocation :one
-INSERT INTO location.locations (name, address)
-VALUES ($1, $2)
+INSERT INTO location.locations (name, address, timezone, province)
+VALUES ($1, $2, $3, $4)
 RETURNING *;
 
 -- name: GetLocationById :one
@@ -14,12 +14,14 @@ from location.locations;
 
 -- name: UpdateLocation :execrows
 UPDATE location.locations
-SET name    = $1,
-    address = $2
+SET name     = $1,
+    address  = $2,
+    timezone = COALESCE(sqlc.narg('timezone'), timezone),
+    province = COALESCE(sqlc.narg('province'), province)
 WHERE id = $3
 ;
 
 -- name: DeleteLocation :execrows
 DELETE
 FROM location.locations
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
@@ -27,8 +27,9 @@ func TestCreateLocation(t *testing.T) {
 	defer cleanup()
 
 	params := db.CreateLocationParams{
-		Name:    "Test Location",
-		Address: "123 Test St",
+		Name:     "Test Location",
+		Address:  "123 Test St",
+		Timezone: "America/Edmonton",
 	}
 
 	createdLocation, err := queries.CreateLocation(context.Background(), params)
@@ -49,8 +50,9 @@ func TestUpdateLocationValid(t *testing.T) {
 	defer cleanup()
 
 	params := db.CreateLocationParams{
-		Name:    "Test Location",
-		Address: "123 Test St",
+		Name:     "Test Location",
+		Address:  "123 Test St",
+		Timezone: "America/Edmonton",
 	}
 
 	createdLocation, err := queries.CreateLocation(context.Background(), params)
@@ -85,8 +87,9 @@ func TestCreateLocationUniqueNameConstraint(t *testing.T) {
 	defer cleanup()
 
 	params := db.CreateLocationParams{
-		Name:    "Test Location",
-		Address: "123 Test St",
+		Name:     "Test Location",
+		Address:  "123 Test St",
+		Timezone: "America/Edmonton",
 	}
 
 	createdLocation, err := queries.CreateLocation(context.Background(), params)
@@ -115,8 +118,9 @@ func TestGetAllLocations(t *testing.T) {
 	// Create some locations
 	for i := 1; i <= 5; i++ {
 		createLocationParams := db.CreateLocationParams{
-			Name:    fmt.Sprintf("Course %d", i),
-			Address: fmt.Sprintf("Address %d", i),
+			Name:     fmt.Sprintf("Course %d", i),
+			Address:  fmt.Sprintf("Address %d", i),
+			Timezone: "America/Edmonton",
 		}
 		createdLocation, err := queries.CreateLocation(context.Background(), createLocationParams)
 		require.NoError(t, err)
@@ -161,8 +165,9 @@ func TestDeleteLocation(t *testing.T) {
 
 	// Create a course to delete
 	params := db.CreateLocationParams{
-		Name:    "Go Course",
-		Address: "Learn Go programming",
+		Name:     "Go Course",
+		Address:  "Learn Go programming",
+		Timezone: "America/Edmonton",
 	}
 
 	createdLocation, err := queries.CreateLocation(context.Background(), params)
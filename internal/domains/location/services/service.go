@@ -11,6 +11,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -132,3 +133,53 @@ func (s *Service) DeleteLocation(ctx context.Context, id uuid.UUID) *errLib.Comm
 		)
 	})
 }
+
+func (s *Service) GetBlackoutDates(ctx context.Context, locationID uuid.UUID, from, to time.Time) ([]values.BlackoutDate, *errLib.CommonError) {
+	return s.repo.GetBlackoutDates(ctx, locationID, from, to)
+}
+
+func (s *Service) CreateBlackoutDate(ctx context.Context, details values.CreateBlackoutDateValues) (values.BlackoutDate, *errLib.CommonError) {
+	var created values.BlackoutDate
+
+	err := s.executeInTx(ctx, func(txRepo *repo.Repository) *errLib.CommonError {
+		var err *errLib.CommonError
+		if created, err = txRepo.CreateBlackoutDate(ctx, details); err != nil {
+			return err
+		}
+
+		return s.staffActivityLogsService.InsertStaffActivity(
+			ctx,
+			txRepo.GetTx(),
+			details.CreatedBy,
+			fmt.Sprintf("Added %s blackout '%s' (%s to %s) at location %s",
+				details.Action, details.Name,
+				details.StartsOn.Format("Jan 2, 2006"), details.EndsOn.Format("Jan 2, 2006"),
+				details.LocationID),
+		)
+	})
+
+	if err != nil {
+		return values.BlackoutDate{}, err
+	}
+	return created, nil
+}
+
+func (s *Service) DeleteBlackoutDate(ctx context.Context, locationID, id uuid.UUID) *errLib.CommonError {
+	return s.executeInTx(ctx, func(txRepo *repo.Repository) *errLib.CommonError {
+		if err := txRepo.DeleteBlackoutDate(ctx, locationID, id); err != nil {
+			return err
+		}
+
+		staffID, err := contextUtils.GetUserID(ctx)
+		if err != nil {
+			return err
+		}
+
+		return s.staffActivityLogsService.InsertStaffActivity(
+			ctx,
+			txRepo.GetTx(),
+			staffID,
+			fmt.Sprintf("Removed blackout %s from location %s", id, locationID),
+		)
+	})
+}
@@ -0,0 +1,41 @@
+package facility
+
+import (
+	"time"
+
+	"api/utils/recurrence"
+
+	"github.com/google/uuid"
+)
+
+// CreateBlackoutDateValues describes a closure or holiday at a location. StartsOn and
+// EndsOn are calendar dates at the facility, both inclusive.
+type CreateBlackoutDateValues struct {
+	LocationID uuid.UUID
+	Name       string
+	StartsOn   time.Time
+	EndsOn     time.Time
+	Action     recurrence.BlackoutAction
+	CreatedBy  uuid.UUID
+}
+
+type BlackoutDate struct {
+	ID         uuid.UUID
+	LocationID uuid.UUID
+	Name       string
+	StartsOn   time.Time
+	EndsOn     time.Time
+	Action     recurrence.BlackoutAction
+	CreatedAt  time.Time
+}
+
+// ToRecurrence converts the blackout into the form used when expanding recurrences.
+func (b BlackoutDate) ToRecurrence() recurrence.Blackout {
+	return recurrence.Blackout{
+		ID:       b.ID,
+		Name:     b.Name,
+		StartsOn: b.StartsOn,
+		EndsOn:   b.EndsOn,
+		Action:   b.Action,
+	}
+}
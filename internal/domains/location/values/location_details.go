@@ -4,9 +4,18 @@ import (
 	"github.com/google/uuid"
 )
 
+// DefaultProvince is the province used for facilities that have not configured one.
+// All of our existing facilities are in Calgary.
+const DefaultProvince = "AB"
+
 type BaseDetails struct {
 	Name    string
 	Address string
+	// Timezone is the facility's IANA zone, e.g. "America/Edmonton".
+	Timezone string
+	// Province is the two-letter Canadian province or territory code that decides
+	// which sales taxes are charged for the facility, e.g. "AB".
+	Province string
 }
 
 type CreateDetails struct {
@@ -2,6 +2,7 @@ package membership_plan
 
 import (
 	"net/http"
+	"time"
 
 	values "api/internal/domains/membership/values"
 	errLib "api/internal/libs/errors"
@@ -105,3 +106,80 @@ func (dto PlanRequestDto) ToUpdateValueObjects(planIdStr string) (values.PlanUpd
 		},
 	}, nil
 }
+
+// FreezePolicyRequestDto is the request body for setting a plan's self-service freeze rules
+type FreezePolicyRequestDto struct {
+	MinDays                   int32 `json:"min_days" validate:"required,gt=0" example:"7"`
+	MaxDays                   int32 `json:"max_days" validate:"required,gtefield=MinDays" example:"60"`
+	MaxFreezesPerYear         int32 `json:"max_freezes_per_year" validate:"required,gt=0" example:"2"`
+	FeeAmount                 int32 `json:"fee_amount" validate:"gte=0" example:"1500"`  // in cents, 0 = free
+	InjuryRequiresMedicalNote *bool `json:"injury_requires_medical_note" example:"true"` // defaults to true
+}
+
+func (dto FreezePolicyRequestDto) ToValueObjects(planIdStr string) (values.FreezePolicyValues, *errLib.CommonError) {
+
+	var vo values.FreezePolicyValues
+
+	planId, err := validators.ParseUUID(planIdStr)
+	if err != nil {
+		return vo, err
+	}
+
+	if err = validators.ValidateDto(&dto); err != nil {
+		return vo, err
+	}
+
+	requiresMedicalNote := true
+	if dto.InjuryRequiresMedicalNote != nil {
+		requiresMedicalNote = *dto.InjuryRequiresMedicalNote
+	}
+
+	return values.FreezePolicyValues{
+		MembershipPlanID:          planId,
+		MinDays:                   dto.MinDays,
+		MaxDays:                   dto.MaxDays,
+		MaxFreezesPerYear:         dto.MaxFreezesPerYear,
+		FeeAmount:                 dto.FeeAmount,
+		InjuryRequiresMedicalNote: requiresMedicalNote,
+	}, nil
+}
+
+// PlanPriceChangeRequestDto is the request body for scheduling a new price for a plan.
+// The billing interval and currency stay those of the plan's current price.
+type PlanPriceChangeRequestDto struct {
+	UnitAmount          int64      `json:"unit_amount" validate:"required,gt=0" example:"6500"`                                  // new price in cents
+	EffectiveAt         *time.Time `json:"effective_at" example:"2026-06-01T00:00:00-06:00"`                                     // defaults to now
+	ExistingSubscribers string     `json:"existing_subscribers" validate:"required,oneof=grandfather migrate" example:"migrate"` // grandfather or migrate
+	NoticeDays          *int32     `json:"notice_days" validate:"omitempty,gte=0" example:"30"`                                  // defaults to 30
+}
+
+func (dto PlanPriceChangeRequestDto) ToValueObjects(planIdStr string) (values.PlanPriceChangeValues, *errLib.CommonError) {
+
+	var vo values.PlanPriceChangeValues
+
+	planId, err := validators.ParseUUID(planIdStr)
+	if err != nil {
+		return vo, err
+	}
+
+	if err = validators.ValidateDto(&dto); err != nil {
+		return vo, err
+	}
+
+	noticeDays := int32(30)
+	if dto.NoticeDays != nil {
+		noticeDays = *dto.NoticeDays
+	}
+
+	vo = values.PlanPriceChangeValues{
+		MembershipPlanID:    planId,
+		UnitAmount:          dto.UnitAmount,
+		ExistingSubscribers: dto.ExistingSubscribers,
+		NoticeDays:          noticeDays,
+	}
+	if dto.EffectiveAt != nil {
+		vo.EffectiveAt = dto.EffectiveAt.UTC()
+	}
+
+	return vo, nil
+}
@@ -58,3 +58,87 @@ func getPtrIfNotEmpty(s string) *string {
 	}
 	return &s
 }
+
+type FreezePolicyResponse struct {
+	MembershipPlanID          uuid.UUID `json:"membership_plan_id"`
+	MinDays                   int32     `json:"min_days"`
+	MaxDays                   int32     `json:"max_days"`
+	MaxFreezesPerYear         int32     `json:"max_freezes_per_year"`
+	FeeAmount                 int32     `json:"fee_amount"`
+	Fee                       string    `json:"fee"`
+	InjuryRequiresMedicalNote bool      `json:"injury_requires_medical_note"`
+	UpdatedAt                 time.Time `json:"updated_at"`
+}
+
+func NewFreezePolicyResponse(policy values.FreezePolicyValues) FreezePolicyResponse {
+	return FreezePolicyResponse{
+		MembershipPlanID:          policy.MembershipPlanID,
+		MinDays:                   policy.MinDays,
+		MaxDays:                   policy.MaxDays,
+		MaxFreezesPerYear:         policy.MaxFreezesPerYear,
+		FeeAmount:                 policy.FeeAmount,
+		Fee:                       fmt.Sprintf("$%.2f", float64(policy.FeeAmount)/100),
+		InjuryRequiresMedicalNote: policy.InjuryRequiresMedicalNote,
+		UpdatedAt:                 policy.UpdatedAt,
+	}
+}
+
+type PlanPriceResponse struct {
+	ID                  uuid.UUID  `json:"id"`
+	MembershipPlanID    uuid.UUID  `json:"membership_plan_id"`
+	StripePriceID       string     `json:"stripe_price_id"`
+	UnitAmount          int32      `json:"unit_amount"`
+	Price               string     `json:"price"`
+	Currency            string     `json:"currency"`
+	Interval            string     `json:"interval"`
+	IntervalCount       int32      `json:"interval_count"`
+	EffectiveAt         time.Time  `json:"effective_at"`
+	ExistingSubscribers string     `json:"existing_subscribers"`
+	NoticeDays          int32      `json:"notice_days"`
+	Status              string     `json:"status"`
+	ActivatedAt         *time.Time `json:"activated_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
+
+func NewPlanPriceResponse(price values.PlanPriceValues) PlanPriceResponse {
+	return PlanPriceResponse{
+		ID:                  price.ID,
+		MembershipPlanID:    price.MembershipPlanID,
+		StripePriceID:       price.StripePriceID,
+		UnitAmount:          price.UnitAmount,
+		Price:               fmt.Sprintf("$%.2f", float64(price.UnitAmount)/100),
+		Currency:            strings.ToUpper(price.Currency),
+		Interval:            price.Interval,
+		IntervalCount:       price.IntervalCount,
+		EffectiveAt:         price.EffectiveAt,
+		ExistingSubscribers: price.ExistingSubscribers,
+		NoticeDays:          price.NoticeDays,
+		Status:              price.Status,
+		ActivatedAt:         price.ActivatedAt,
+		CreatedAt:           price.CreatedAt,
+	}
+}
+
+type PlanPriceMigrationResponse struct {
+	ID                   uuid.UUID  `json:"id"`
+	CustomerID           uuid.UUID  `json:"customer_id"`
+	StripeSubscriptionID string     `json:"stripe_subscription_id"`
+	FromStripePriceID    *string    `json:"from_stripe_price_id,omitempty"`
+	SwitchAt             *time.Time `json:"switch_at,omitempty"`
+	Status               string     `json:"status"`
+	FailureReason        *string    `json:"failure_reason,omitempty"`
+	NotifiedAt           *time.Time `json:"notified_at,omitempty"`
+}
+
+func NewPlanPriceMigrationResponse(migration values.PlanPriceMigrationValues) PlanPriceMigrationResponse {
+	return PlanPriceMigrationResponse{
+		ID:                   migration.ID,
+		CustomerID:           migration.CustomerID,
+		StripeSubscriptionID: migration.StripeSubscriptionID,
+		FromStripePriceID:    getPtrIfNotEmpty(migration.FromStripePriceID),
+		SwitchAt:             migration.SwitchAt,
+		Status:               migration.Status,
+		FailureReason:        getPtrIfNotEmpty(migration.FailureReason),
+		NotifiedAt:           migration.NotifiedAt,
+	}
+}
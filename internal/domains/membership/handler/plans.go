@@ -214,3 +214,236 @@ func (h *PlansHandlers) ToggleMembershipPlanVisibility(w http.ResponseWriter, r
 
 	responseHandlers.RespondWithSuccess(w, responseBody, http.StatusOK)
 }
+
+// GetFreezePolicy returns the self-service freeze rules of a membership plan.
+// @Tags membership-plans
+// @Produce json
+// @Param id path string true "Plan ID"
+// @Success 200 {object} membership_plan.FreezePolicyResponse "Freeze policy"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid ID"
+// @Failure 404 {object} map[string]interface{} "Not Found: Plan has no freeze policy"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /memberships/plans/{id}/freeze-policy [get]
+func (h *PlansHandlers) GetFreezePolicy(w http.ResponseWriter, r *http.Request) {
+
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	policy, err := h.Service.GetFreezePolicy(r.Context(), id)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	responseHandlers.RespondWithSuccess(w, membership_plan.NewFreezePolicyResponse(policy), http.StatusOK)
+}
+
+// SetFreezePolicy creates or replaces the self-service freeze rules of a membership plan.
+// @Tags admin-membership-plans
+// @Accept json
+// @Produce json
+// @Param id path string true "Plan ID"
+// @Param policy body membership_plan.FreezePolicyRequestDto true "Freeze policy"
+// @Security Bearer
+// @Success 200 {object} membership_plan.FreezePolicyResponse "Freeze policy saved"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid input"
+// @Failure 404 {object} map[string]interface{} "Not Found: Membership plan not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /memberships/plans/{id}/freeze-policy [put]
+func (h *PlansHandlers) SetFreezePolicy(w http.ResponseWriter, r *http.Request) {
+
+	var requestDto membership_plan.FreezePolicyRequestDto
+
+	if err := validators.ParseJSON(r.Body, &requestDto); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	policy, err := requestDto.ToValueObjects(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	saved, err := h.Service.SetFreezePolicy(r.Context(), policy)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	responseHandlers.RespondWithSuccess(w, membership_plan.NewFreezePolicyResponse(saved), http.StatusOK)
+}
+
+// DeleteFreezePolicy removes the freeze rules of a membership plan, so customers can no longer freeze it.
+// Freezes already scheduled still run.
+// @Tags admin-membership-plans
+// @Param id path string true "Plan ID"
+// @Security Bearer
+// @Success 204 "No Content: Freeze policy removed"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid ID"
+// @Failure 404 {object} map[string]interface{} "Not Found: Plan has no freeze policy"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /memberships/plans/{id}/freeze-policy [delete]
+func (h *PlansHandlers) DeleteFreezePolicy(w http.ResponseWriter, r *http.Request) {
+
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	if err = h.Service.DeleteFreezePolicy(r.Context(), id); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	responseHandlers.RespondWithSuccess(w, nil, http.StatusNoContent)
+}
+
+// GetPlanPrices lists the price versions of a membership plan, latest first.
+// @Tags admin-membership-plans
+// @Produce json
+// @Param id path string true "Plan ID"
+// @Security Bearer
+// @Success 200 {array} membership_plan.PlanPriceResponse "Price versions"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid ID"
+// @Failure 404 {object} map[string]interface{} "Not Found: Membership plan not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /memberships/plans/{id}/prices [get]
+func (h *PlansHandlers) GetPlanPrices(w http.ResponseWriter, r *http.Request) {
+
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	prices, err := h.Service.ListPlanPrices(r.Context(), id)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	result := make([]membership_plan.PlanPriceResponse, len(prices))
+	for i, price := range prices {
+		result[i] = membership_plan.NewPlanPriceResponse(price)
+	}
+
+	responseHandlers.RespondWithSuccess(w, result, http.StatusOK)
+}
+
+// SchedulePriceChange creates a new price for a membership plan, effective at effective_at or straight away.
+// New subscribers pay the new price from then on. Existing subscribers either keep their price
+// (grandfather) or move to the new price at their first renewal on or after effective_at (migrate),
+// after an email notice_days ahead.
+// @Tags admin-membership-plans
+// @Accept json
+// @Produce json
+// @Param id path string true "Plan ID"
+// @Param change body membership_plan.PlanPriceChangeRequestDto true "Price change"
+// @Security Bearer
+// @Success 201 {object} membership_plan.PlanPriceResponse "Price change scheduled"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid input or notice period too short"
+// @Failure 404 {object} map[string]interface{} "Not Found: Membership plan not found"
+// @Failure 409 {object} map[string]interface{} "Conflict: Plan already has a scheduled price change"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /memberships/plans/{id}/prices [post]
+func (h *PlansHandlers) SchedulePriceChange(w http.ResponseWriter, r *http.Request) {
+
+	var requestDto membership_plan.PlanPriceChangeRequestDto
+
+	if err := validators.ParseJSON(r.Body, &requestDto); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	change, err := requestDto.ToValueObjects(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	price, err := h.Service.SchedulePriceChange(r.Context(), change)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	responseHandlers.RespondWithSuccess(w, membership_plan.NewPlanPriceResponse(price), http.StatusCreated)
+}
+
+// CancelPriceChange cancels a price change of a membership plan that hasn't taken effect yet.
+// Subscribers already scheduled to move to it keep their current price.
+// @Tags admin-membership-plans
+// @Param id path string true "Plan ID"
+// @Param price_id path string true "Price version ID"
+// @Security Bearer
+// @Success 204 "No Content: Price change cancelled"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid ID"
+// @Failure 404 {object} map[string]interface{} "Not Found: Price version not found"
+// @Failure 409 {object} map[string]interface{} "Conflict: Price change already took effect"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /memberships/plans/{id}/prices/{price_id} [delete]
+func (h *PlansHandlers) CancelPriceChange(w http.ResponseWriter, r *http.Request) {
+
+	planID, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	priceID, err := validators.ParseUUID(chi.URLParam(r, "price_id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	if err = h.Service.CancelPriceChange(r.Context(), planID, priceID); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	responseHandlers.RespondWithSuccess(w, nil, http.StatusNoContent)
+}
+
+// GetPlanPriceMigrations lists the subscribers moved, or scheduled to move, to a price version.
+// @Tags admin-membership-plans
+// @Produce json
+// @Param id path string true "Plan ID"
+// @Param price_id path string true "Price version ID"
+// @Security Bearer
+// @Success 200 {array} membership_plan.PlanPriceMigrationResponse "Subscriber migrations"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid ID"
+// @Failure 404 {object} map[string]interface{} "Not Found: Price version not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /memberships/plans/{id}/prices/{price_id}/migrations [get]
+func (h *PlansHandlers) GetPlanPriceMigrations(w http.ResponseWriter, r *http.Request) {
+
+	planID, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	priceID, err := validators.ParseUUID(chi.URLParam(r, "price_id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	migrations, err := h.Service.ListPlanPriceMigrations(r.Context(), planID, priceID)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	result := make([]membership_plan.PlanPriceMigrationResponse, len(migrations))
+	for i, migration := range migrations {
+		result[i] = membership_plan.NewPlanPriceMigrationResponse(migration)
+	}
+
+	responseHandlers.RespondWithSuccess(w, result, http.StatusOK)
+}
@@ -358,3 +358,168 @@ func (r *PlansRepository) ToggleMembershipPlanVisibility(ctx context.Context, id
 
 	return plan, nil
 }
+
+func (r *PlansRepository) GetFreezePolicy(ctx context.Context, planID uuid.UUID) (values.FreezePolicyValues, *errLib.CommonError) {
+	dbPolicy, err := r.Queries.GetFreezePolicy(ctx, planID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return values.FreezePolicyValues{}, errLib.New("Membership plan has no freeze policy", http.StatusNotFound)
+		}
+		log.Printf("Failed to get freeze policy for plan %s: %v", planID, err)
+		return values.FreezePolicyValues{}, errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+
+	return toFreezePolicyValues(dbPolicy), nil
+}
+
+func (r *PlansRepository) UpsertFreezePolicy(ctx context.Context, policy values.FreezePolicyValues) (values.FreezePolicyValues, *errLib.CommonError) {
+	dbPolicy, err := r.Queries.UpsertFreezePolicy(ctx, db.UpsertFreezePolicyParams{
+		MembershipPlanID:          policy.MembershipPlanID,
+		MinDays:                   policy.MinDays,
+		MaxDays:                   policy.MaxDays,
+		MaxFreezesPerYear:         policy.MaxFreezesPerYear,
+		FeeAmount:                 policy.FeeAmount,
+		InjuryRequiresMedicalNote: policy.InjuryRequiresMedicalNote,
+	})
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Constraint == "freeze_policies_membership_plan_id_fkey" {
+			return values.FreezePolicyValues{}, errLib.New("Membership plan not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to save freeze policy for plan %s: %v", policy.MembershipPlanID, err)
+		return values.FreezePolicyValues{}, errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+
+	return toFreezePolicyValues(dbPolicy), nil
+}
+
+func (r *PlansRepository) DeleteFreezePolicy(ctx context.Context, planID uuid.UUID) *errLib.CommonError {
+	row, err := r.Queries.DeleteFreezePolicy(ctx, planID)
+	if err != nil {
+		log.Printf("Failed to delete freeze policy for plan %s: %v", planID, err)
+		return errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+
+	if row == 0 {
+		return errLib.New("Membership plan has no freeze policy", http.StatusNotFound)
+	}
+
+	return nil
+}
+
+func toFreezePolicyValues(dbPolicy db.MembershipFreezePolicy) values.FreezePolicyValues {
+	return values.FreezePolicyValues{
+		MembershipPlanID:          dbPolicy.MembershipPlanID,
+		MinDays:                   dbPolicy.MinDays,
+		MaxDays:                   dbPolicy.MaxDays,
+		MaxFreezesPerYear:         dbPolicy.MaxFreezesPerYear,
+		FeeAmount:                 dbPolicy.FeeAmount,
+		InjuryRequiresMedicalNote: dbPolicy.InjuryRequiresMedicalNote,
+		CreatedAt:                 dbPolicy.CreatedAt,
+		UpdatedAt:                 dbPolicy.UpdatedAt,
+	}
+}
+
+func (r *PlansRepository) ListPlanPrices(ctx context.Context, planID uuid.UUID) ([]values.PlanPriceValues, *errLib.CommonError) {
+	dbPrices, err := r.Queries.ListPlanPrices(ctx, planID)
+	if err != nil {
+		log.Printf("Failed to list prices of plan %s: %v", planID, err)
+		return nil, errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+
+	prices := make([]values.PlanPriceValues, len(dbPrices))
+	for i, dbPrice := range dbPrices {
+		prices[i] = toPlanPriceValues(dbPrice)
+	}
+
+	return prices, nil
+}
+
+func (r *PlansRepository) GetPlanPrice(ctx context.Context, planID, priceID uuid.UUID) (values.PlanPriceValues, *errLib.CommonError) {
+	dbPrice, err := r.Queries.GetPlanPrice(ctx, priceID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return values.PlanPriceValues{}, errLib.New("Plan price not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to get plan price %s: %v", priceID, err)
+		return values.PlanPriceValues{}, errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+
+	if dbPrice.MembershipPlanID != planID {
+		return values.PlanPriceValues{}, errLib.New("Plan price not found", http.StatusNotFound)
+	}
+
+	return toPlanPriceValues(dbPrice), nil
+}
+
+func (r *PlansRepository) CreatePlanPrice(ctx context.Context, params db.CreatePlanPriceParams) (values.PlanPriceValues, *errLib.CommonError) {
+	dbPrice, err := r.Queries.CreatePlanPrice(ctx, params)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) {
+			switch pqErr.Constraint {
+			case "idx_plan_prices_one_scheduled":
+				return values.PlanPriceValues{}, errLib.New("Membership plan already has a scheduled price change", http.StatusConflict)
+			case "plan_prices_membership_plan_id_fkey":
+				return values.PlanPriceValues{}, errLib.New("Membership plan not found", http.StatusNotFound)
+			}
+		}
+		log.Printf("Failed to create price of plan %s: %v", params.MembershipPlanID, err)
+		return values.PlanPriceValues{}, errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+
+	return toPlanPriceValues(dbPrice), nil
+}
+
+func (r *PlansRepository) ListPlanPriceMigrations(ctx context.Context, priceID uuid.UUID) ([]values.PlanPriceMigrationValues, *errLib.CommonError) {
+	dbMigrations, err := r.Queries.ListPlanPriceMigrations(ctx, priceID)
+	if err != nil {
+		log.Printf("Failed to list migrations of plan price %s: %v", priceID, err)
+		return nil, errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+
+	migrations := make([]values.PlanPriceMigrationValues, len(dbMigrations))
+	for i, dbMigration := range dbMigrations {
+		migration := values.PlanPriceMigrationValues{
+			ID:                   dbMigration.ID,
+			CustomerID:           dbMigration.CustomerID,
+			StripeSubscriptionID: dbMigration.StripeSubscriptionID,
+			FromStripePriceID:    dbMigration.FromStripePriceID.String,
+			Status:               dbMigration.Status,
+			FailureReason:        dbMigration.FailureReason.String,
+		}
+		if dbMigration.SwitchAt.Valid {
+			migration.SwitchAt = &dbMigration.SwitchAt.Time
+		}
+		if dbMigration.NotifiedAt.Valid {
+			migration.NotifiedAt = &dbMigration.NotifiedAt.Time
+		}
+		migrations[i] = migration
+	}
+
+	return migrations, nil
+}
+
+func toPlanPriceValues(dbPrice db.MembershipPlanPrice) values.PlanPriceValues {
+	price := values.PlanPriceValues{
+		ID:                  dbPrice.ID,
+		MembershipPlanID:    dbPrice.MembershipPlanID,
+		StripePriceID:       dbPrice.StripePriceID,
+		UnitAmount:          dbPrice.UnitAmount,
+		Currency:            dbPrice.Currency,
+		Interval:            dbPrice.Interval,
+		IntervalCount:       dbPrice.IntervalCount,
+		EffectiveAt:         dbPrice.EffectiveAt,
+		ExistingSubscribers: dbPrice.ExistingSubscribers,
+		NoticeDays:          dbPrice.NoticeDays,
+		Status:              dbPrice.Status,
+		CreatedAt:           dbPrice.CreatedAt,
+	}
+	if dbPrice.ActivatedAt.Valid {
+		price.ActivatedAt = &dbPrice.ActivatedAt.Time
+	}
+	if dbPrice.MigrationsScheduledAt.Valid {
+		price.MigrationsScheduledAt = &dbPrice.MigrationsScheduledAt.Time
+	}
+	return price
+}
@@ -1068,6 +1068,17 @@ type MembershipDiscountRestrictedMembershipPlan struct {
 	CreatedAt        time.Time `json:"created_at"`
 }
 
+type MembershipFreezePolicy struct {
+	MembershipPlanID          uuid.UUID `json:"membership_plan_id"`
+	MinDays                   int32     `json:"min_days"`
+	MaxDays                   int32     `json:"max_days"`
+	MaxFreezesPerYear         int32     `json:"max_freezes_per_year"`
+	FeeAmount                 int32     `json:"fee_amount"`
+	InjuryRequiresMedicalNote bool      `json:"injury_requires_medical_note"`
+	CreatedAt                 time.Time `json:"created_at"`
+	UpdatedAt                 time.Time `json:"updated_at"`
+}
+
 type MembershipMembership struct {
 	ID          uuid.UUID `json:"id"`
 	Name        string    `json:"name"`
@@ -1098,6 +1109,41 @@ type MembershipMembershipPlan struct {
 	IsVisible         bool          `json:"is_visible"`
 }
 
+type MembershipPlanPrice struct {
+	ID                    uuid.UUID     `json:"id"`
+	MembershipPlanID      uuid.UUID     `json:"membership_plan_id"`
+	StripePriceID         string        `json:"stripe_price_id"`
+	UnitAmount            int32         `json:"unit_amount"`
+	Currency              string        `json:"currency"`
+	Interval              string        `json:"interval"`
+	IntervalCount         int32         `json:"interval_count"`
+	EffectiveAt           time.Time     `json:"effective_at"`
+	ExistingSubscribers   string        `json:"existing_subscribers"`
+	NoticeDays            int32         `json:"notice_days"`
+	Status                string        `json:"status"`
+	ActivatedAt           sql.NullTime  `json:"activated_at"`
+	MigrationsScheduledAt sql.NullTime  `json:"migrations_scheduled_at"`
+	CreatedBy             uuid.NullUUID `json:"created_by"`
+	CreatedAt             time.Time     `json:"created_at"`
+	UpdatedAt             time.Time     `json:"updated_at"`
+}
+
+type MembershipPlanPriceMigration struct {
+	ID                       uuid.UUID      `json:"id"`
+	PlanPriceID              uuid.UUID      `json:"plan_price_id"`
+	CustomerMembershipPlanID uuid.UUID      `json:"customer_membership_plan_id"`
+	CustomerID               uuid.UUID      `json:"customer_id"`
+	StripeSubscriptionID     string         `json:"stripe_subscription_id"`
+	StripeScheduleID         sql.NullString `json:"stripe_schedule_id"`
+	FromStripePriceID        sql.NullString `json:"from_stripe_price_id"`
+	SwitchAt                 sql.NullTime   `json:"switch_at"`
+	Status                   string         `json:"status"`
+	FailureReason            sql.NullString `json:"failure_reason"`
+	NotifiedAt               sql.NullTime   `json:"notified_at"`
+	CreatedAt                time.Time      `json:"created_at"`
+	UpdatedAt                time.Time      `json:"updated_at"`
+}
+
 type NotificationsPushToken struct {
 	ID            int32          `json:"id"`
 	UserID        uuid.UUID      `json:"user_id"`
@@ -1561,8 +1607,9 @@ type UsersUser struct {
 	// Timestamp when the user last changed their email address.
 	EmailChangedAt sql.NullTime `json:"email_changed_at"`
 	// Timestamp when account was archived. Archived accounts are permanently deleted after 30 days.
-	ArchivedAt  sql.NullTime   `json:"archived_at"`
-	AccountType sql.NullString `json:"account_type"`
+	ArchivedAt         sql.NullTime   `json:"archived_at"`
+	AccountType        sql.NullString `json:"account_type"`
+	StoredValueBalance int32          `json:"stored_value_balance"`
 }
 
 // Tracks weekly credit consumption per customer for membership limit enforcement
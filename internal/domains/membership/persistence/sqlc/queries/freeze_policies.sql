@@ -0,0 +1,20 @@
+-- name: GetFreezePolicy :one
+SELECT *
+FROM membership.freeze_policies
+WHERE membership_plan_id = $1;
+
+-- name: UpsertFreezePolicy :one
+INSERT INTO membership.freeze_policies (membership_plan_id, min_days, max_days, max_freezes_per_year,
+                                        fee_amount, injury_requires_medical_note)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (membership_plan_id) DO UPDATE
+    SET min_days                     = EXCLUDED.min_days,
+        max_days                     = EXCLUDED.max_days,
+        max_freezes_per_year         = EXCLUDED.max_freezes_per_year,
+        fee_amount                   = EXCLUDED.fee_amount,
+        injury_requires_medical_note = EXCLUDED.injury_requires_medical_note,
+        updated_at                   = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: DeleteFreezePolicy :execrows
+DELETE FROM membership.freeze_policies WHERE membership_plan_id = $1;
@@ -0,0 +1,138 @@
+-- name: CreatePlanPrice :one
+INSERT INTO membership.plan_prices (membership_plan_id, stripe_price_id, unit_amount, currency, interval,
+                                    interval_count, effective_at, existing_subscribers, notice_days, created_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING *;
+
+-- name: CreateActivePlanPrice :one
+-- Records a plan's current price as its active version
+INSERT INTO membership.plan_prices (membership_plan_id, stripe_price_id, unit_amount, currency, interval,
+                                    interval_count, effective_at, status, activated_at, notice_days)
+VALUES ($1, $2, $3, $4, $5, $6, CURRENT_TIMESTAMP, 'active', CURRENT_TIMESTAMP, 0)
+RETURNING *;
+
+-- name: GetActivePlanPrice :one
+SELECT *
+FROM membership.plan_prices
+WHERE membership_plan_id = $1
+  AND status = 'active';
+
+-- name: GetPlanPrice :one
+SELECT *
+FROM membership.plan_prices
+WHERE id = $1;
+
+-- name: ListPlanPrices :many
+SELECT *
+FROM membership.plan_prices
+WHERE membership_plan_id = $1
+ORDER BY effective_at DESC;
+
+-- name: CancelPlanPrice :execrows
+UPDATE membership.plan_prices
+SET status     = 'cancelled',
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND status = 'scheduled';
+
+-- name: ListDuePlanPrices :many
+-- Scheduled price versions whose effective date has come
+SELECT *
+FROM membership.plan_prices
+WHERE status = 'scheduled'
+  AND effective_at <= NOW()
+ORDER BY effective_at
+LIMIT $1;
+
+-- name: SupersedeActivePlanPrice :exec
+UPDATE membership.plan_prices
+SET status     = 'superseded',
+    updated_at = CURRENT_TIMESTAMP
+WHERE membership_plan_id = $1
+  AND status = 'active';
+
+-- name: ActivatePlanPrice :execrows
+UPDATE membership.plan_prices
+SET status       = 'active',
+    activated_at = CURRENT_TIMESTAMP,
+    updated_at   = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND status = 'scheduled';
+
+-- name: SetMembershipPlanPrice :exec
+-- Points the plan at its active price version, so new subscribers pay it
+UPDATE membership.membership_plans
+SET stripe_price_id = $2,
+    unit_amount     = $3,
+    currency        = $4,
+    interval        = $5,
+    updated_at      = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: ListPlanPricesDueForMigration :many
+-- Price versions that move existing subscribers and whose notice period has started
+SELECT *
+FROM membership.plan_prices
+WHERE existing_subscribers = 'migrate'
+  AND status IN ('scheduled', 'active')
+  AND migrations_scheduled_at IS NULL
+  AND effective_at - make_interval(days => notice_days) <= NOW()
+ORDER BY effective_at;
+
+-- name: MarkPlanPriceMigrationsScheduled :exec
+UPDATE membership.plan_prices
+SET migrations_scheduled_at = CURRENT_TIMESTAMP,
+    updated_at              = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: ListPlanPriceMigrationCandidates :many
+-- Subscribers of the plan that haven't been handled for this price version yet
+SELECT cmp.id,
+       cmp.customer_id,
+       cmp.stripe_subscription_id,
+       u.first_name,
+       u.email
+FROM users.customer_membership_plans cmp
+         JOIN users.users u ON u.id = cmp.customer_id
+WHERE cmp.membership_plan_id = sqlc.arg(membership_plan_id)
+  AND cmp.status IN ('active', 'past_due', 'paused')
+  AND cmp.stripe_subscription_id IS NOT NULL
+  AND NOT EXISTS (SELECT 1
+                  FROM membership.plan_price_migrations ppm
+                  WHERE ppm.plan_price_id = sqlc.arg(plan_price_id)
+                    AND ppm.customer_membership_plan_id = cmp.id)
+ORDER BY cmp.created_at
+LIMIT sqlc.arg(row_limit);
+
+-- name: CreatePlanPriceMigration :one
+INSERT INTO membership.plan_price_migrations (plan_price_id, customer_membership_plan_id, customer_id,
+                                              stripe_subscription_id, stripe_schedule_id, from_stripe_price_id,
+                                              switch_at, status, failure_reason)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: SetPlanPriceMigrationNotified :exec
+UPDATE membership.plan_price_migrations
+SET notified_at = CURRENT_TIMESTAMP,
+    updated_at  = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: ListPlanPriceMigrations :many
+SELECT *
+FROM membership.plan_price_migrations
+WHERE plan_price_id = $1
+ORDER BY created_at;
+
+-- name: CancelPlanPriceMigration :exec
+UPDATE membership.plan_price_migrations
+SET status     = 'cancelled',
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: CompleteDuePlanPriceMigrations :execrows
+-- The subscription schedule has switched these subscribers to the new price
+UPDATE membership.plan_price_migrations
+SET status     = 'completed',
+    updated_at = CURRENT_TIMESTAMP
+WHERE status = 'scheduled'
+  AND switch_at <= NOW();
@@ -13,6 +13,7 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"net/http"
 
 	"github.com/google/uuid"
 )
@@ -205,6 +206,18 @@ func (s *PlanService) CreateMembershipPlan(ctx context.Context, details values.P
 
 func (s *PlanService) UpdateMembershipPlan(ctx context.Context, details values.PlanUpdateValues) *errLib.CommonError {
 
+	current, err := s.repo.GetMembershipPlanById(ctx, details.ID)
+	if err != nil {
+		return err
+	}
+
+	// Prices change through price versions, so subscribers can be grandfathered or given notice
+	if details.StripePriceID == "" {
+		details.StripePriceID = current.StripePriceID
+	} else if details.StripePriceID != current.StripePriceID {
+		return errLib.New("stripe_price_id cannot be changed here; schedule a price change for the plan instead", http.StatusBadRequest)
+	}
+
 	return s.executeInTx(ctx, func(txRepo *repo.PlansRepository) *errLib.CommonError {
 		if err := txRepo.UpdateMembershipPlan(ctx, details); err != nil {
 			return err
@@ -317,3 +330,59 @@ func (s *PlanService) ToggleMembershipPlanVisibility(ctx context.Context, id uui
 
 	return plan, err
 }
+
+// GetFreezePolicy returns the self-service freeze rules of a plan
+func (s *PlanService) GetFreezePolicy(ctx context.Context, planID uuid.UUID) (values.FreezePolicyValues, *errLib.CommonError) {
+	return s.repo.GetFreezePolicy(ctx, planID)
+}
+
+// SetFreezePolicy creates or replaces the self-service freeze rules of a plan
+func (s *PlanService) SetFreezePolicy(ctx context.Context, policy values.FreezePolicyValues) (values.FreezePolicyValues, *errLib.CommonError) {
+
+	var saved values.FreezePolicyValues
+
+	err := s.executeInTx(ctx, func(txRepo *repo.PlansRepository) *errLib.CommonError {
+		result, err := txRepo.UpsertFreezePolicy(ctx, policy)
+		if err != nil {
+			return err
+		}
+
+		saved = result
+
+		staffID, err := contextUtils.GetUserID(ctx)
+		if err != nil {
+			return err
+		}
+
+		return s.staffActivityLogsService.InsertStaffActivity(
+			ctx,
+			txRepo.GetTx(),
+			staffID,
+			fmt.Sprintf("Set freeze policy of membership plan %s: %d-%d days, %d per year, fee %d cents",
+				policy.MembershipPlanID, policy.MinDays, policy.MaxDays, policy.MaxFreezesPerYear, policy.FeeAmount),
+		)
+	})
+
+	return saved, err
+}
+
+// DeleteFreezePolicy removes the freeze rules of a plan, so customers can no longer freeze it
+func (s *PlanService) DeleteFreezePolicy(ctx context.Context, planID uuid.UUID) *errLib.CommonError {
+	return s.executeInTx(ctx, func(txRepo *repo.PlansRepository) *errLib.CommonError {
+		if err := txRepo.DeleteFreezePolicy(ctx, planID); err != nil {
+			return err
+		}
+
+		staffID, err := contextUtils.GetUserID(ctx)
+		if err != nil {
+			return err
+		}
+
+		return s.staffActivityLogsService.InsertStaffActivity(
+			ctx,
+			txRepo.GetTx(),
+			staffID,
+			fmt.Sprintf("Removed freeze policy of membership plan %s", planID),
+		)
+	})
+}
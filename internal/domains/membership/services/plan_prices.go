@@ -0,0 +1,437 @@
+package membership
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	repo "api/internal/domains/membership/persistence/repositories"
+	db "api/internal/domains/membership/persistence/sqlc/generated"
+	values "api/internal/domains/membership/values"
+	stripeService "api/internal/domains/payment/services/stripe"
+	errLib "api/internal/libs/errors"
+	contextUtils "api/utils/context"
+	"api/utils/email"
+	"api/utils/timezone"
+
+	"github.com/google/uuid"
+)
+
+const (
+	PlanPriceStatusScheduled  = "scheduled"
+	PlanPriceStatusActive     = "active"
+	PlanPriceStatusSuperseded = "superseded"
+	PlanPriceStatusCancelled  = "cancelled"
+
+	PriceMigrationStatusScheduled = "scheduled"
+	PriceMigrationStatusSkipped   = "skipped"
+	PriceMigrationStatusFailed    = "failed"
+)
+
+// priceChangeBatchSize caps how many price versions or subscribers one run of the job handles
+const priceChangeBatchSize = 100
+
+// ListPlanPrices lists the price versions of a plan, latest first
+func (s *PlanService) ListPlanPrices(ctx context.Context, planID uuid.UUID) ([]values.PlanPriceValues, *errLib.CommonError) {
+	if _, err := s.repo.GetMembershipPlanById(ctx, planID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListPlanPrices(ctx, planID)
+}
+
+// ListPlanPriceMigrations lists the subscribers moved, or due to be moved, to a price version
+func (s *PlanService) ListPlanPriceMigrations(ctx context.Context, planID, priceID uuid.UUID) ([]values.PlanPriceMigrationValues, *errLib.CommonError) {
+	if _, err := s.repo.GetPlanPrice(ctx, planID, priceID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListPlanPriceMigrations(ctx, priceID)
+}
+
+// SchedulePriceChange creates a new Stripe price for a plan and schedules it to replace the
+// plan's price at change.EffectiveAt, or straight away when no date is given. New
+// subscribers pay the new price from then on. Existing subscribers are either grandfathered
+// or moved at their first renewal after the change, with change.NoticeDays of notice.
+func (s *PlanService) SchedulePriceChange(ctx context.Context, change values.PlanPriceChangeValues) (values.PlanPriceValues, *errLib.CommonError) {
+	staffID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return values.PlanPriceValues{}, err
+	}
+
+	plan, err := s.repo.GetMembershipPlanById(ctx, change.MembershipPlanID)
+	if err != nil {
+		return values.PlanPriceValues{}, err
+	}
+
+	currentPrice, err := s.stripeService.GetPrice(plan.StripePriceID)
+	if err != nil {
+		return values.PlanPriceValues{}, err
+	}
+	if currentPrice.Recurring == nil || currentPrice.Product == nil {
+		return values.PlanPriceValues{}, errLib.New("The plan's current Stripe price is not a recurring price", http.StatusConflict)
+	}
+
+	now := time.Now().UTC()
+	if change.EffectiveAt.IsZero() {
+		change.EffectiveAt = now
+	}
+	if err = validatePriceChange(change, currentPrice.UnitAmount, now); err != nil {
+		return values.PlanPriceValues{}, err
+	}
+
+	currency := strings.ToUpper(string(currentPrice.Currency))
+	interval := string(currentPrice.Recurring.Interval)
+	intervalCount := currentPrice.Recurring.IntervalCount
+
+	newPriceID, err := s.productService.CreateRecurringPrice(
+		currentPrice.Product.ID,
+		change.UnitAmount,
+		currency,
+		interval,
+		intervalCount,
+		fmt.Sprintf("%s from %s", plan.Name, change.EffectiveAt.In(timezone.Default()).Format("2006-01-02")),
+	)
+	if err != nil {
+		return values.PlanPriceValues{}, err
+	}
+
+	var created values.PlanPriceValues
+
+	txErr := s.executeInTx(ctx, func(txRepo *repo.PlansRepository) *errLib.CommonError {
+		// Plans priced before versions existed get their current price recorded first,
+		// so subscribers who stay on it can still be matched to the plan
+		_, dbErr := txRepo.Queries.GetActivePlanPrice(ctx, plan.ID)
+		if errors.Is(dbErr, sql.ErrNoRows) {
+			_, dbErr = txRepo.Queries.CreateActivePlanPrice(ctx, db.CreateActivePlanPriceParams{
+				MembershipPlanID: plan.ID,
+				StripePriceID:    currentPrice.ID,
+				UnitAmount:       int32(currentPrice.UnitAmount),
+				Currency:         currency,
+				Interval:         interval,
+				IntervalCount:    int32(intervalCount),
+			})
+		}
+		if dbErr != nil {
+			log.Printf("Failed to record current price of plan %s: %v", plan.ID, dbErr)
+			return errLib.New("Failed to record the plan's current price", http.StatusInternalServerError)
+		}
+
+		result, err := txRepo.CreatePlanPrice(ctx, db.CreatePlanPriceParams{
+			MembershipPlanID:    plan.ID,
+			StripePriceID:       newPriceID,
+			UnitAmount:          int32(change.UnitAmount),
+			Currency:            currency,
+			Interval:            interval,
+			IntervalCount:       int32(intervalCount),
+			EffectiveAt:         change.EffectiveAt,
+			ExistingSubscribers: change.ExistingSubscribers,
+			NoticeDays:          change.NoticeDays,
+			CreatedBy:           uuid.NullUUID{UUID: staffID, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		created = result
+
+		return s.staffActivityLogsService.InsertStaffActivity(
+			ctx,
+			txRepo.GetTx(),
+			staffID,
+			fmt.Sprintf("Scheduled price change of membership plan '%s' from %d to %d cents on %s (existing subscribers: %s)",
+				plan.Name, currentPrice.UnitAmount, change.UnitAmount, change.EffectiveAt.Format(time.RFC3339), change.ExistingSubscribers),
+		)
+	})
+	if txErr != nil {
+		s.productService.DeactivatePrice(newPriceID)
+		return values.PlanPriceValues{}, txErr
+	}
+
+	if !created.EffectiveAt.After(now) {
+		// Otherwise the price job activates it on its next run
+		if dbPrice, dbErr := s.repo.Queries.GetPlanPrice(ctx, created.ID); dbErr != nil {
+			log.Printf("Failed to get plan price %s to activate it: %v", created.ID, dbErr)
+		} else if err := s.activatePlanPrice(ctx, dbPrice); err != nil {
+			log.Printf("Failed to activate plan price %s straight away: %s", created.ID, err.Message)
+		}
+		return s.repo.GetPlanPrice(ctx, plan.ID, created.ID)
+	}
+
+	return created, nil
+}
+
+// CancelPriceChange cancels a price change that hasn't taken effect yet. Subscribers who
+// were already scheduled to move to it stay on their current price.
+func (s *PlanService) CancelPriceChange(ctx context.Context, planID, priceID uuid.UUID) *errLib.CommonError {
+	staffID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	price, err := s.repo.GetPlanPrice(ctx, planID, priceID)
+	if err != nil {
+		return err
+	}
+	if price.Status != PlanPriceStatusScheduled {
+		return errLib.New("Only price changes that haven't taken effect can be cancelled", http.StatusConflict)
+	}
+
+	// Claim the price first so the job doesn't schedule more subscribers onto it
+	txErr := s.executeInTx(ctx, func(txRepo *repo.PlansRepository) *errLib.CommonError {
+		cancelled, dbErr := txRepo.Queries.CancelPlanPrice(ctx, priceID)
+		if dbErr != nil {
+			log.Printf("Failed to cancel plan price %s: %v", priceID, dbErr)
+			return errLib.New("Failed to cancel price change", http.StatusInternalServerError)
+		}
+		if cancelled == 0 {
+			return errLib.New("Only price changes that haven't taken effect can be cancelled", http.StatusConflict)
+		}
+
+		return s.staffActivityLogsService.InsertStaffActivity(
+			ctx,
+			txRepo.GetTx(),
+			staffID,
+			fmt.Sprintf("Cancelled price change of membership plan %s to %d cents on %s",
+				planID, price.UnitAmount, price.EffectiveAt.Format(time.RFC3339)),
+		)
+	})
+	if txErr != nil {
+		return txErr
+	}
+
+	migrations, dbErr := s.repo.Queries.ListPlanPriceMigrations(ctx, priceID)
+	if dbErr != nil {
+		log.Printf("Failed to list migrations of cancelled plan price %s: %v", priceID, dbErr)
+		return errLib.New("Price change cancelled, but its subscriber schedules could not be released", http.StatusInternalServerError)
+	}
+
+	released := 0
+	for _, migration := range migrations {
+		if migration.Status != PriceMigrationStatusScheduled {
+			continue
+		}
+		if err := stripeService.ReleaseSubscriptionSchedule(migration.StripeScheduleID.String); err != nil {
+			log.Printf("[PRICES] Failed to release schedule %s of subscription %s: %s",
+				migration.StripeScheduleID.String, migration.StripeSubscriptionID, err.Message)
+			continue
+		}
+		if err := s.repo.Queries.CancelPlanPriceMigration(ctx, migration.ID); err != nil {
+			log.Printf("[PRICES] Failed to mark migration %s cancelled: %v", migration.ID, err)
+		}
+		released++
+	}
+
+	s.productService.DeactivatePrice(price.StripePriceID)
+	log.Printf("[PRICES] Cancelled plan price %s, released %d subscriber schedules", priceID, released)
+	return nil
+}
+
+// RunPriceChanges puts due price versions into effect, schedules existing subscribers onto
+// migrating price versions once their notice period starts, and records finished moves
+func (s *PlanService) RunPriceChanges(ctx context.Context) error {
+	due, err := s.repo.Queries.ListDuePlanPrices(ctx, priceChangeBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to list due plan prices: %w", err)
+	}
+
+	activated := 0
+	for _, price := range due {
+		if err := s.activatePlanPrice(ctx, price); err != nil {
+			log.Printf("[PRICES] Failed to activate plan price %s: %s", price.ID, err.Message)
+			continue
+		}
+		activated++
+	}
+
+	migrating, err := s.repo.Queries.ListPlanPricesDueForMigration(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list plan prices due for migration: %w", err)
+	}
+
+	for _, price := range migrating {
+		s.scheduleMigrations(ctx, price)
+	}
+
+	completed, err := s.repo.Queries.CompleteDuePlanPriceMigrations(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to complete plan price migrations: %w", err)
+	}
+
+	log.Printf("[PRICES] Price changes: due=%d, activated=%d, migrating=%d, migrations completed=%d",
+		len(due), activated, len(migrating), completed)
+	return nil
+}
+
+// activatePlanPrice makes a price version the plan's current price
+func (s *PlanService) activatePlanPrice(ctx context.Context, price db.MembershipPlanPrice) *errLib.CommonError {
+	txErr := s.executeInTx(ctx, func(txRepo *repo.PlansRepository) *errLib.CommonError {
+		queries := txRepo.Queries
+
+		if err := queries.SupersedeActivePlanPrice(ctx, price.MembershipPlanID); err != nil {
+			log.Printf("Failed to supersede current price of plan %s: %v", price.MembershipPlanID, err)
+			return errLib.New("Failed to supersede current price", http.StatusInternalServerError)
+		}
+
+		activated, err := queries.ActivatePlanPrice(ctx, price.ID)
+		if err != nil {
+			log.Printf("Failed to activate plan price %s: %v", price.ID, err)
+			return errLib.New("Failed to activate price", http.StatusInternalServerError)
+		}
+		if activated == 0 {
+			return errLib.New("Price change is no longer scheduled", http.StatusConflict)
+		}
+
+		if err := queries.SetMembershipPlanPrice(ctx, db.SetMembershipPlanPriceParams{
+			ID:            price.MembershipPlanID,
+			StripePriceID: price.StripePriceID,
+			UnitAmount:    sql.NullInt32{Int32: price.UnitAmount, Valid: true},
+			Currency:      sql.NullString{String: price.Currency, Valid: true},
+			Interval:      sql.NullString{String: price.Interval, Valid: true},
+		}); err != nil {
+			log.Printf("Failed to point plan %s at price %s: %v", price.MembershipPlanID, price.StripePriceID, err)
+			return errLib.New("Failed to update plan price", http.StatusInternalServerError)
+		}
+
+		return nil
+	})
+	if txErr != nil {
+		if txErr.HTTPCode == http.StatusConflict {
+			return nil // cancelled in the meantime
+		}
+		return txErr
+	}
+
+	log.Printf("[PRICES] Plan %s now costs %d cents (price %s)", price.MembershipPlanID, price.UnitAmount, price.StripePriceID)
+	return nil
+}
+
+// scheduleMigrations moves the plan's existing subscribers onto a migrating price version
+// at their first renewal on or after its effective date, and lets them know. Subscribers
+// who join before the price takes effect are picked up by later runs.
+func (s *PlanService) scheduleMigrations(ctx context.Context, price db.MembershipPlanPrice) {
+	plan, planErr := s.repo.GetMembershipPlanById(ctx, price.MembershipPlanID)
+	if planErr != nil {
+		log.Printf("[PRICES] Failed to get plan %s: %s", price.MembershipPlanID, planErr.Message)
+		return
+	}
+
+	candidates, err := s.repo.Queries.ListPlanPriceMigrationCandidates(ctx, db.ListPlanPriceMigrationCandidatesParams{
+		MembershipPlanID: price.MembershipPlanID,
+		PlanPriceID:      price.ID,
+		RowLimit:         priceChangeBatchSize,
+	})
+	if err != nil {
+		log.Printf("[PRICES] Failed to list subscribers of plan %s: %v", price.MembershipPlanID, err)
+		return
+	}
+
+	newPrice := formatPlanPrice(price.UnitAmount, price.Currency, price.Interval, price.IntervalCount)
+
+	scheduled, skipped, failed := 0, 0, 0
+	for _, candidate := range candidates {
+		subscriptionID := candidate.StripeSubscriptionID.String
+		params := db.CreatePlanPriceMigrationParams{
+			PlanPriceID:              price.ID,
+			CustomerMembershipPlanID: candidate.ID,
+			CustomerID:               candidate.CustomerID,
+			StripeSubscriptionID:     subscriptionID,
+		}
+
+		schedule, switchAt, stripeErr := stripeService.SchedulePriceChange(ctx, subscriptionID, price.StripePriceID, price.EffectiveAt, price.ID.String()+":"+candidate.ID.String())
+		switch {
+		case stripeErr == nil:
+			params.Status = PriceMigrationStatusScheduled
+			params.StripeScheduleID = sql.NullString{String: schedule.ID, Valid: true}
+			params.SwitchAt = sql.NullTime{Time: switchAt, Valid: true}
+			if len(schedule.Phases) > 0 && len(schedule.Phases[0].Items) > 0 && schedule.Phases[0].Items[0].Price != nil {
+				params.FromStripePriceID = sql.NullString{String: schedule.Phases[0].Items[0].Price.ID, Valid: true}
+			}
+			scheduled++
+		case stripeErr.HTTPCode == http.StatusConflict:
+			params.Status = PriceMigrationStatusSkipped
+			params.FailureReason = sql.NullString{String: stripeErr.Message, Valid: true}
+			skipped++
+		default:
+			params.Status = PriceMigrationStatusFailed
+			params.FailureReason = sql.NullString{String: stripeErr.Message, Valid: true}
+			failed++
+		}
+
+		migration, err := s.repo.Queries.CreatePlanPriceMigration(ctx, params)
+		if err != nil {
+			log.Printf("[PRICES] Failed to record migration of subscription %s: %v", subscriptionID, err)
+			if stripeErr == nil {
+				// Untracked schedules would change the price without notice or a way to cancel
+				stripeService.ReleaseSubscriptionSchedule(schedule.ID)
+			}
+			continue
+		}
+
+		if migration.Status != PriceMigrationStatusScheduled || !candidate.Email.Valid || candidate.Email.String == "" {
+			continue
+		}
+		effectiveDate := switchAt.In(timezone.Default()).Format("January 2, 2006")
+		if err := email.SendPlanPriceChangeEmail(candidate.Email.String, candidate.FirstName, plan.Name, newPrice, effectiveDate); err != nil {
+			continue
+		}
+		if err := s.repo.Queries.SetPlanPriceMigrationNotified(ctx, migration.ID); err != nil {
+			log.Printf("[PRICES] Failed to record notice of migration %s: %v", migration.ID, err)
+		}
+	}
+
+	// Keep looking for new subscribers until the new price is what they sign up at
+	if price.Status == PlanPriceStatusActive && len(candidates) < priceChangeBatchSize {
+		if err := s.repo.Queries.MarkPlanPriceMigrationsScheduled(ctx, price.ID); err != nil {
+			log.Printf("[PRICES] Failed to mark migrations of plan price %s scheduled: %v", price.ID, err)
+		}
+	}
+
+	log.Printf("[PRICES] Plan price %s migrations: subscribers=%d, scheduled=%d, skipped=%d, failed=%d",
+		price.ID, len(candidates), scheduled, skipped, failed)
+}
+
+// validatePriceChange checks a price change against the plan's current price
+func validatePriceChange(change values.PlanPriceChangeValues, currentAmount int64, now time.Time) *errLib.CommonError {
+	if change.UnitAmount <= 0 {
+		return errLib.New("unit_amount must be positive", http.StatusBadRequest)
+	}
+	if change.UnitAmount == currentAmount {
+		return errLib.New("unit_amount must differ from the plan's current price", http.StatusBadRequest)
+	}
+
+	switch change.ExistingSubscribers {
+	case values.ExistingSubscribersGrandfather, values.ExistingSubscribersMigrate:
+	default:
+		return errLib.New("existing_subscribers must be grandfather or migrate", http.StatusBadRequest)
+	}
+
+	if change.NoticeDays < 0 {
+		return errLib.New("notice_days cannot be negative", http.StatusBadRequest)
+	}
+	if change.EffectiveAt.Before(now) {
+		return errLib.New("effective_at cannot be in the past", http.StatusBadRequest)
+	}
+
+	if change.ExistingSubscribers == values.ExistingSubscribersMigrate {
+		earliest := now.AddDate(0, 0, int(change.NoticeDays))
+		if change.EffectiveAt.Before(earliest) {
+			return errLib.New(fmt.Sprintf("Existing subscribers must get %d days notice: effective_at must be on or after %s",
+				change.NoticeDays, earliest.Format(time.RFC3339)), http.StatusBadRequest)
+		}
+	}
+
+	return nil
+}
+
+// formatPlanPrice formats a recurring price for customers, e.g. "$59.99 CAD every 2 weeks"
+func formatPlanPrice(unitAmount int32, currency, interval string, intervalCount int32) string {
+	amount := fmt.Sprintf("$%.2f %s", float64(unitAmount)/100, strings.ToUpper(currency))
+	if intervalCount <= 1 {
+		return fmt.Sprintf("%s per %s", amount, interval)
+	}
+	return fmt.Sprintf("%s every %d %ss", amount, intervalCount, interval)
+}
@@ -0,0 +1,50 @@
+package membership
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	values "api/internal/domains/membership/values"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePriceChange(t *testing.T) {
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	change := func(amount int64, effectiveAt time.Time, existing string, noticeDays int32) values.PlanPriceChangeValues {
+		return values.PlanPriceChangeValues{
+			UnitAmount:          amount,
+			EffectiveAt:         effectiveAt,
+			ExistingSubscribers: existing,
+			NoticeDays:          noticeDays,
+		}
+	}
+
+	t.Run("Grandfathered change can take effect straight away", func(t *testing.T) {
+		assert.Nil(t, validatePriceChange(change(6500, now, values.ExistingSubscribersGrandfather, 30), 5900, now))
+	})
+
+	t.Run("Migrating change needs the full notice period", func(t *testing.T) {
+		err := validatePriceChange(change(6500, now.AddDate(0, 0, 29), values.ExistingSubscribersMigrate, 30), 5900, now)
+		require.NotNil(t, err)
+		assert.Equal(t, http.StatusBadRequest, err.HTTPCode)
+
+		assert.Nil(t, validatePriceChange(change(6500, now.AddDate(0, 0, 30), values.ExistingSubscribersMigrate, 30), 5900, now))
+	})
+
+	t.Run("Rejects invalid changes", func(t *testing.T) {
+		for name, c := range map[string]values.PlanPriceChangeValues{
+			"same price":       change(5900, now, values.ExistingSubscribersGrandfather, 0),
+			"zero price":       change(0, now, values.ExistingSubscribersGrandfather, 0),
+			"unknown handling": change(6500, now, "keep", 0),
+			"negative notice":  change(6500, now, values.ExistingSubscribersGrandfather, -1),
+			"in the past":      change(6500, now.Add(-time.Hour), values.ExistingSubscribersGrandfather, 0),
+		} {
+			err := validatePriceChange(c, 5900, now)
+			require.NotNil(t, err, name)
+			assert.Equal(t, http.StatusBadRequest, err.HTTPCode, name)
+		}
+	})
+}
@@ -42,3 +42,61 @@ type PlanReadValues struct {
 	Currency   string
 	Interval   string
 }
+
+// FreezePolicyValues are the rules for customer self-service freezes of a plan
+type FreezePolicyValues struct {
+	MembershipPlanID          uuid.UUID
+	MinDays                   int32
+	MaxDays                   int32
+	MaxFreezesPerYear         int32
+	FeeAmount                 int32 // in cents
+	InjuryRequiresMedicalNote bool
+	CreatedAt                 time.Time
+	UpdatedAt                 time.Time
+}
+
+const (
+	// ExistingSubscribersGrandfather keeps existing subscribers on the price they pay
+	ExistingSubscribersGrandfather = "grandfather"
+	// ExistingSubscribersMigrate moves existing subscribers to the new price at their next renewal
+	ExistingSubscribersMigrate = "migrate"
+)
+
+// PlanPriceChangeValues describe a new price version of a plan
+type PlanPriceChangeValues struct {
+	MembershipPlanID    uuid.UUID
+	UnitAmount          int64 // in cents
+	EffectiveAt         time.Time
+	ExistingSubscribers string // ExistingSubscribersGrandfather or ExistingSubscribersMigrate
+	NoticeDays          int32
+}
+
+// PlanPriceValues are a price version of a plan
+type PlanPriceValues struct {
+	ID                    uuid.UUID
+	MembershipPlanID      uuid.UUID
+	StripePriceID         string
+	UnitAmount            int32 // in cents
+	Currency              string
+	Interval              string
+	IntervalCount         int32
+	EffectiveAt           time.Time
+	ExistingSubscribers   string
+	NoticeDays            int32
+	Status                string
+	ActivatedAt           *time.Time
+	MigrationsScheduledAt *time.Time
+	CreatedAt             time.Time
+}
+
+// PlanPriceMigrationValues track moving one subscriber to a new price version
+type PlanPriceMigrationValues struct {
+	ID                   uuid.UUID
+	CustomerID           uuid.UUID
+	StripeSubscriptionID string
+	FromStripePriceID    string
+	SwitchAt             *time.Time
+	Status               string
+	FailureReason        string
+	NotifiedAt           *time.Time
+}
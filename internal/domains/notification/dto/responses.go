@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"time"
+
+	values "api/internal/domains/notification/values"
+
+	"github.com/google/uuid"
+)
+
+type PushDeliveryStatsResponseDto struct {
+	NotificationID uuid.UUID `json:"notification_id"`
+	Source         string    `json:"source"`
+	Title          string    `json:"title"`
+	CreatedAt      time.Time `json:"created_at"`
+	Sent           int       `json:"sent"`
+	Accepted       int       `json:"accepted"`
+	Delivered      int       `json:"delivered"`
+	Failed         int       `json:"failed"`
+	Pending        int       `json:"pending"`
+	Pruned         int       `json:"pruned"`
+}
+
+func NewPushDeliveryStatsResponse(stats values.PushDeliveryStats) PushDeliveryStatsResponseDto {
+	return PushDeliveryStatsResponseDto{
+		NotificationID: stats.NotificationID,
+		Source:         stats.Source,
+		Title:          stats.Title,
+		CreatedAt:      stats.CreatedAt,
+		Sent:           stats.Sent,
+		Accepted:       stats.Accepted,
+		Delivered:      stats.Delivered,
+		Failed:         stats.Failed,
+		Pending:        stats.Pending,
+		Pruned:         stats.Pruned,
+	}
+}
@@ -9,6 +9,8 @@ import (
 	responseHandlers "api/internal/libs/responses"
 	"api/internal/libs/validators"
 	contextUtils "api/utils/context"
+
+	"github.com/go-chi/chi"
 )
 
 type NotificationHandler struct {
@@ -108,4 +110,33 @@ func (h *NotificationHandler) SendTeamNotification(w http.ResponseWriter, r *htt
 	}
 
 	responseHandlers.RespondWithSuccess(w, response, http.StatusOK)
-}
\ No newline at end of file
+}
+
+// GetDeliveryStats returns what happened to every device a push notification went to
+// @Summary Get push notification delivery stats
+// @Description Counts the devices a push notification was sent to, how many Expo accepted, how many were delivered or failed, how many still wait for a receipt and how many dead tokens were pruned
+// @Tags notifications
+// @Produce json
+// @Param id path string true "Push notification ID"
+// @Security Bearer
+// @Success 200 {object} dto.PushDeliveryStatsResponseDto "Delivery stats"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Not Found: Push notification not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /secure/notifications/{id}/delivery [get]
+func (h *NotificationHandler) GetDeliveryStats(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	stats, err := h.service.GetDeliveryStats(r.Context(), id)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	responseHandlers.RespondWithSuccess(w, dto.NewPushDeliveryStatsResponse(stats), http.StatusOK)
+}
@@ -0,0 +1,136 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	generated "api/internal/domains/notification/persistence/sqlc/generated"
+	values "api/internal/domains/notification/values"
+	errLib "api/internal/libs/errors"
+	txUtils "api/utils/db"
+
+	"github.com/google/uuid"
+)
+
+func (r *PushTokenRepository) CreatePushNotification(ctx context.Context, source, title string) (uuid.UUID, *errLib.CommonError) {
+	notification, err := r.queries.CreatePushNotification(ctx, generated.CreatePushNotificationParams{
+		Source: source,
+		Title:  title,
+	})
+	if err != nil {
+		log.Printf("Failed to create %s push notification: %v", source, err)
+		return uuid.Nil, errLib.New("Failed to record push notification", http.StatusInternalServerError)
+	}
+	return notification.ID, nil
+}
+
+func (r *PushTokenRepository) CreatePushTicket(ctx context.Context, ticket values.PushTicket) *errLib.CommonError {
+	err := r.queries.CreatePushTicket(ctx, generated.CreatePushTicketParams{
+		NotificationID: ticket.NotificationID,
+		PushTokenID:    sql.NullInt32{Int32: int32(ticket.PushTokenID), Valid: ticket.PushTokenID != 0},
+		ExpoPushToken:  ticket.ExpoPushToken,
+		TicketID:       sql.NullString{String: ticket.TicketID, Valid: ticket.TicketID != ""},
+		Status:         ticket.Status,
+		Error:          sql.NullString{String: ticket.Error, Valid: ticket.Error != ""},
+	})
+	if err != nil {
+		log.Printf("Failed to record push ticket for notification %s: %v", ticket.NotificationID, err)
+		return errLib.New("Failed to record push ticket", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (r *PushTokenRepository) GetPendingPushReceipts(ctx context.Context, createdAfter, createdBefore time.Time, limit int32) ([]values.PendingPushReceipt, *errLib.CommonError) {
+	rows, err := r.queries.GetPendingPushReceipts(ctx, generated.GetPendingPushReceiptsParams{
+		CreatedAfter:  createdAfter,
+		CreatedBefore: createdBefore,
+		Limit:         limit,
+	})
+	if err != nil {
+		log.Printf("Failed to get pending push receipts: %v", err)
+		return nil, errLib.New("Failed to get pending push receipts", http.StatusInternalServerError)
+	}
+
+	pending := make([]values.PendingPushReceipt, len(rows))
+	for i, row := range rows {
+		pending[i] = values.PendingPushReceipt{
+			NotificationID: row.NotificationID,
+			ExpoPushToken:  row.ExpoPushToken,
+			TicketID:       row.TicketID.String,
+		}
+	}
+	return pending, nil
+}
+
+func (r *PushTokenRepository) RecordPushReceipt(ctx context.Context, ticketID, status, receiptError string) *errLib.CommonError {
+	err := r.queries.RecordPushReceipt(ctx, generated.RecordPushReceiptParams{
+		ReceiptStatus: sql.NullString{String: status, Valid: true},
+		ReceiptError:  sql.NullString{String: receiptError, Valid: receiptError != ""},
+		TicketID:      sql.NullString{String: ticketID, Valid: true},
+	})
+	if err != nil {
+		log.Printf("Failed to record receipt of push ticket %s: %v", ticketID, err)
+		return errLib.New("Failed to record push receipt", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// PruneDeadToken removes a token Expo reported as no longer registered and marks the
+// notification's tickets for it as pruned. Registrations of the token made after the
+// notification's ticket was issued are kept. It returns how many registrations were removed.
+func (r *PushTokenRepository) PruneDeadToken(ctx context.Context, notificationID uuid.UUID, token string) (int64, *errLib.CommonError) {
+	var removed int64
+	txErr := txUtils.ExecuteInTx(ctx, r.db, func(tx *sql.Tx) *errLib.CommonError {
+		qtx := r.queries.WithTx(tx)
+
+		var err error
+		if removed, err = qtx.DeletePushTokensByToken(ctx, generated.DeletePushTokensByTokenParams{
+			ExpoPushToken:  token,
+			NotificationID: notificationID,
+		}); err != nil {
+			log.Printf("Failed to delete dead push token: %v", err)
+			return errLib.New("Failed to prune push token", http.StatusInternalServerError)
+		}
+		if removed == 0 {
+			return nil
+		}
+
+		if err = qtx.MarkPushTicketsTokenPruned(ctx, generated.MarkPushTicketsTokenPrunedParams{
+			ExpoPushToken:  token,
+			NotificationID: notificationID,
+		}); err != nil {
+			log.Printf("Failed to mark push tickets of notification %s as pruned: %v", notificationID, err)
+			return errLib.New("Failed to prune push token", http.StatusInternalServerError)
+		}
+		return nil
+	})
+	return removed, txErr
+}
+
+func (r *PushTokenRepository) GetPushDeliveryStats(ctx context.Context, notificationID uuid.UUID) (values.PushDeliveryStats, *errLib.CommonError) {
+	row, err := r.queries.GetPushNotificationDeliveryStats(ctx, notificationID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return values.PushDeliveryStats{}, errLib.New("Push notification not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to get delivery stats of push notification %s: %v", notificationID, err)
+		return values.PushDeliveryStats{}, errLib.New("Failed to get push delivery stats", http.StatusInternalServerError)
+	}
+
+	return values.PushDeliveryStats{
+		NotificationID: row.ID,
+		Source:         row.Source,
+		Title:          row.Title,
+		CreatedAt:      row.CreatedAt,
+		Sent:           int(row.Sent),
+		Accepted:       int(row.Accepted),
+		Delivered:      int(row.Delivered),
+		Failed:         int(row.Failed),
+		Pending:        int(row.Pending),
+		Pruned:         int(row.Pruned),
+	}, nil
+}
@@ -598,6 +598,28 @@ type MembershipMembershipPlan struct {
 	WeeklyCreditLimit sql.NullInt32 `json:"weekly_credit_limit"`
 }
 
+type NotificationsPushNotification struct {
+	ID        uuid.UUID `json:"id"`
+	Source    string    `json:"source"`
+	Title     string    `json:"title"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type NotificationsPushTicket struct {
+	ID               uuid.UUID      `json:"id"`
+	NotificationID   uuid.UUID      `json:"notification_id"`
+	PushTokenID      sql.NullInt32  `json:"push_token_id"`
+	ExpoPushToken    string         `json:"expo_push_token"`
+	TicketID         sql.NullString `json:"ticket_id"`
+	Status           string         `json:"status"`
+	Error            sql.NullString `json:"error"`
+	ReceiptStatus    sql.NullString `json:"receipt_status"`
+	ReceiptError     sql.NullString `json:"receipt_error"`
+	ReceiptCheckedAt sql.NullTime   `json:"receipt_checked_at"`
+	TokenPruned      bool           `json:"token_pruned"`
+	CreatedAt        time.Time      `json:"created_at"`
+}
+
 type NotificationsPushToken struct {
 	ID            int32          `json:"id"`
 	UserID        uuid.UUID      `json:"user_id"`
@@ -0,0 +1,61 @@
+-- name: CreatePushNotification :one
+INSERT INTO notifications.push_notifications (source, title)
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: CreatePushTicket :exec
+INSERT INTO notifications.push_tickets (notification_id, push_token_id, expo_push_token, ticket_id, status, error)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: GetPendingPushReceipts :many
+-- Tickets Expo accepted whose receipt has not been fetched yet. Receipts only become
+-- available a while after sending and Expo keeps them for a day, so tickets outside
+-- that window are skipped.
+SELECT id, notification_id, push_token_id, expo_push_token, ticket_id
+FROM notifications.push_tickets
+WHERE ticket_id IS NOT NULL
+  AND receipt_status IS NULL
+  AND created_at > sqlc.arg('created_after')
+  AND created_at <= sqlc.arg('created_before')
+ORDER BY created_at
+LIMIT sqlc.arg('limit');
+
+-- name: RecordPushReceipt :exec
+UPDATE notifications.push_tickets
+SET receipt_status     = $1,
+    receipt_error      = $2,
+    receipt_checked_at = CURRENT_TIMESTAMP
+WHERE ticket_id = $3;
+
+-- name: DeletePushTokensByToken :execrows
+-- A token is removed for every user it was registered for once Expo reports the device
+-- is no longer registered. Registrations made after the notification's ticket was issued
+-- are kept: the app registered the token again, so it works now.
+DELETE FROM notifications.push_tokens pt
+WHERE pt.expo_push_token = sqlc.arg('expo_push_token')
+  AND COALESCE(pt.updated_at, pt.created_at) <= (SELECT MAX(t.created_at)
+                                                FROM notifications.push_tickets t
+                                                WHERE t.notification_id = sqlc.arg('notification_id')
+                                                  AND t.expo_push_token = sqlc.arg('expo_push_token'));
+
+-- name: MarkPushTicketsTokenPruned :exec
+UPDATE notifications.push_tickets
+SET token_pruned = true
+WHERE expo_push_token = $1
+  AND notification_id = $2;
+
+-- name: GetPushNotificationDeliveryStats :one
+SELECT n.id,
+       n.source,
+       n.title,
+       n.created_at,
+       COUNT(t.id)::int                                                                           AS sent,
+       COUNT(t.id) FILTER (WHERE t.status = 'ok')::int                                            AS accepted,
+       COUNT(t.id) FILTER (WHERE t.receipt_status = 'ok')::int                                    AS delivered,
+       COUNT(t.id) FILTER (WHERE t.status = 'error' OR t.receipt_status = 'error')::int           AS failed,
+       COUNT(t.id) FILTER (WHERE t.status = 'ok' AND t.receipt_status IS NULL)::int               AS pending,
+       COUNT(t.id) FILTER (WHERE t.token_pruned)::int                                             AS pruned
+FROM notifications.push_notifications n
+         LEFT JOIN notifications.push_tickets t ON t.notification_id = n.id
+WHERE n.id = $1
+GROUP BY n.id;
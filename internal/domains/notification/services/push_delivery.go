@@ -0,0 +1,159 @@
+package notification
+
+import (
+	"context"
+	"log"
+	"time"
+
+	values "api/internal/domains/notification/values"
+	errLib "api/internal/libs/errors"
+	"api/internal/services/expo"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// receiptDelay is how long after sending receipts are asked for; Expo needs a while
+	// to hear back from Apple and Google
+	receiptDelay = 15 * time.Minute
+
+	// receiptRetention is how long Expo keeps receipts around
+	receiptRetention = 24 * time.Hour
+
+	// receiptBatchSize caps the tickets whose receipts are fetched per run
+	receiptBatchSize = 5000
+)
+
+// pushDeliveries records what happened to the push messages sent
+type pushDeliveries interface {
+	CreatePushNotification(ctx context.Context, source, title string) (uuid.UUID, *errLib.CommonError)
+	CreatePushTicket(ctx context.Context, ticket values.PushTicket) *errLib.CommonError
+	GetPendingPushReceipts(ctx context.Context, createdAfter, createdBefore time.Time, limit int32) ([]values.PendingPushReceipt, *errLib.CommonError)
+	RecordPushReceipt(ctx context.Context, ticketID, status, receiptError string) *errLib.CommonError
+	PruneDeadToken(ctx context.Context, notificationID uuid.UUID, token string) (int64, *errLib.CommonError)
+	GetPushDeliveryStats(ctx context.Context, notificationID uuid.UUID) (values.PushDeliveryStats, *errLib.CommonError)
+}
+
+// SendPush sends a notification to every token, records the ticket Expo returned for
+// each device and prunes tokens Expo reports as no longer registered. Rejected messages
+// do not fail the send; they show up in the notification's delivery stats.
+func (s *NotificationService) SendPush(ctx context.Context, source, title, body string, data map[string]interface{}, tokens []values.PushToken) (values.PushDeliveryResult, *errLib.CommonError) {
+	var result values.PushDeliveryResult
+	if len(tokens) == 0 {
+		return result, nil
+	}
+
+	notificationID, err := s.deliveries.CreatePushNotification(ctx, source, title)
+	if err != nil {
+		return result, err
+	}
+	result.NotificationID = notificationID
+
+	messages := make([]expo.Message, len(tokens))
+	for i, token := range tokens {
+		messages[i] = expo.Message{
+			To:    token.ExpoPushToken,
+			Title: title,
+			Body:  body,
+			Data:  data,
+			Sound: "default",
+		}
+	}
+
+	tickets := s.expo.SendMessages(ctx, messages)
+	for i, ticket := range tickets {
+		token := tokens[i]
+		pushTicket := values.PushTicket{
+			NotificationID: notificationID,
+			PushTokenID:    token.ID,
+			ExpoPushToken:  token.ExpoPushToken,
+			TicketID:       ticket.ID,
+			Status:         "ok",
+		}
+		if ticket.Status == "ok" {
+			result.Accepted++
+		} else {
+			pushTicket.Status = "error"
+			pushTicket.Error = ticket.ErrorCode()
+			result.Failed++
+			log.Printf("[NOTIFICATION] Expo rejected push to user %s (%s): %s", token.UserID, token.DeviceType, pushTicket.Error)
+		}
+
+		if recordErr := s.deliveries.CreatePushTicket(ctx, pushTicket); recordErr != nil {
+			continue
+		}
+
+		if pushTicket.Error == expo.ErrDeviceNotRegistered && s.pruneToken(ctx, notificationID, token.ExpoPushToken) {
+			result.Pruned++
+		}
+	}
+
+	log.Printf("[NOTIFICATION] Push notification %s (%s): accepted=%d, failed=%d, pruned=%d",
+		notificationID, source, result.Accepted, result.Failed, result.Pruned)
+	return result, nil
+}
+
+// CheckReceipts fetches the receipts of tickets sent long enough ago, records whether
+// each message reached the device and prunes tokens that are no longer registered.
+// Tickets whose receipt is not ready yet are tried again on the next run.
+func (s *NotificationService) CheckReceipts(ctx context.Context) (values.PushReceiptCheckResult, *errLib.CommonError) {
+	var result values.PushReceiptCheckResult
+
+	now := time.Now()
+	pending, err := s.deliveries.GetPendingPushReceipts(ctx, now.Add(-receiptRetention), now.Add(-receiptDelay), receiptBatchSize)
+	if err != nil {
+		return result, err
+	}
+	if len(pending) == 0 {
+		return result, nil
+	}
+
+	ticketIDs := make([]string, len(pending))
+	for i, p := range pending {
+		ticketIDs[i] = p.TicketID
+	}
+	receipts := s.expo.GetReceipts(ctx, ticketIDs)
+
+	for _, p := range pending {
+		receipt, ok := receipts[p.TicketID]
+		if !ok {
+			continue
+		}
+
+		status, receiptError := "ok", ""
+		if receipt.Status != "ok" {
+			status, receiptError = "error", receipt.ErrorCode()
+		}
+		if recordErr := s.deliveries.RecordPushReceipt(ctx, p.TicketID, status, receiptError); recordErr != nil {
+			continue
+		}
+
+		result.Checked++
+		if status == "ok" {
+			result.Delivered++
+			continue
+		}
+		result.Failed++
+
+		if receiptError == expo.ErrDeviceNotRegistered && s.pruneToken(ctx, p.NotificationID, p.ExpoPushToken) {
+			result.Pruned++
+		}
+	}
+	return result, nil
+}
+
+// GetDeliveryStats counts what happened to every device a notification went to
+func (s *NotificationService) GetDeliveryStats(ctx context.Context, notificationID uuid.UUID) (values.PushDeliveryStats, *errLib.CommonError) {
+	return s.deliveries.GetPushDeliveryStats(ctx, notificationID)
+}
+
+// pruneToken removes a token that is no longer registered and reports whether any
+// registration was removed
+func (s *NotificationService) pruneToken(ctx context.Context, notificationID uuid.UUID, token string) bool {
+	removed, err := s.deliveries.PruneDeadToken(ctx, notificationID, token)
+	if err != nil {
+		log.Printf("[NOTIFICATION] Failed to prune dead push token for notification %s: %v", notificationID, err)
+		return false
+	}
+	return removed > 0
+}
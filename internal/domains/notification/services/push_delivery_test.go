@@ -0,0 +1,142 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	values "api/internal/domains/notification/values"
+	errLib "api/internal/libs/errors"
+	"api/internal/services/expo"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePushDeliveries struct {
+	notificationID uuid.UUID
+	tickets        []values.PushTicket
+	pending        []values.PendingPushReceipt
+	receipts       map[string]string
+	pruned         []string
+	reregistered   map[string]bool // tokens registered again after the ticket was issued
+}
+
+func (f *fakePushDeliveries) CreatePushNotification(context.Context, string, string) (uuid.UUID, *errLib.CommonError) {
+	return f.notificationID, nil
+}
+
+func (f *fakePushDeliveries) CreatePushTicket(_ context.Context, ticket values.PushTicket) *errLib.CommonError {
+	f.tickets = append(f.tickets, ticket)
+	return nil
+}
+
+func (f *fakePushDeliveries) GetPendingPushReceipts(context.Context, time.Time, time.Time, int32) ([]values.PendingPushReceipt, *errLib.CommonError) {
+	return f.pending, nil
+}
+
+func (f *fakePushDeliveries) RecordPushReceipt(_ context.Context, ticketID, status, receiptError string) *errLib.CommonError {
+	f.receipts[ticketID] = status + ":" + receiptError
+	return nil
+}
+
+func (f *fakePushDeliveries) PruneDeadToken(_ context.Context, notificationID uuid.UUID, token string) (int64, *errLib.CommonError) {
+	if notificationID != f.notificationID {
+		return 0, errLib.New("wrong notification", http.StatusInternalServerError)
+	}
+	f.pruned = append(f.pruned, token)
+	if f.reregistered[token] {
+		return 0, nil
+	}
+	return 1, nil
+}
+
+func (f *fakePushDeliveries) GetPushDeliveryStats(context.Context, uuid.UUID) (values.PushDeliveryStats, *errLib.CommonError) {
+	return values.PushDeliveryStats{}, nil
+}
+
+func newTestExpo(t *testing.T, handler http.HandlerFunc) *expo.Service {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return &expo.Service{Client: server.Client(), BaseURL: server.URL}
+}
+
+func TestSendPushPrunesUnregisteredDevices(t *testing.T) {
+	expoService := newTestExpo(t, func(w http.ResponseWriter, r *http.Request) {
+		var messages []expo.Message
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&messages))
+
+		tickets := make([]expo.Ticket, len(messages))
+		for i, message := range messages {
+			switch message.To {
+			case "dead", "reregistered":
+				tickets[i] = expo.Ticket{Status: "error", Message: "not registered", Details: &expo.Details{Error: expo.ErrDeviceNotRegistered}}
+			case "throttled":
+				tickets[i] = expo.Ticket{Status: "error", Details: &expo.Details{Error: "MessageRateExceeded"}}
+			default:
+				tickets[i] = expo.Ticket{Status: "ok", ID: "ticket-" + message.To}
+			}
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{"data": tickets}))
+	})
+
+	deliveries := &fakePushDeliveries{notificationID: uuid.New(), reregistered: map[string]bool{"reregistered": true}}
+	service := &NotificationService{deliveries: deliveries, expo: expoService}
+
+	tokens := []values.PushToken{
+		{ID: 1, ExpoPushToken: "live"},
+		{ID: 2, ExpoPushToken: "dead"},
+		{ID: 3, ExpoPushToken: "throttled"},
+		{ID: 4, ExpoPushToken: "reregistered"},
+	}
+	result, err := service.SendPush(context.Background(), values.PushSourceUser, "Title", "Body", nil, tokens)
+	require.Nil(t, err)
+
+	assert.Equal(t, values.PushDeliveryResult{NotificationID: deliveries.notificationID, Accepted: 1, Failed: 3, Pruned: 1}, result)
+	assert.Equal(t, []string{"dead", "reregistered"}, deliveries.pruned, "only unregistered devices are pruned")
+
+	require.Len(t, deliveries.tickets, len(tokens))
+	assert.Equal(t, "ticket-live", deliveries.tickets[0].TicketID)
+	assert.Equal(t, expo.ErrDeviceNotRegistered, deliveries.tickets[1].Error)
+	assert.Equal(t, "MessageRateExceeded", deliveries.tickets[2].Error)
+}
+
+func TestCheckReceiptsPrunesUnregisteredDevices(t *testing.T) {
+	expoService := newTestExpo(t, func(w http.ResponseWriter, r *http.Request) {
+		receipts := map[string]expo.Receipt{
+			"ticket-delivered":    {Status: "ok"},
+			"ticket-dead":         {Status: "error", Details: &expo.Details{Error: expo.ErrDeviceNotRegistered}},
+			"ticket-reregistered": {Status: "error", Details: &expo.Details{Error: expo.ErrDeviceNotRegistered}},
+			"ticket-too-big":      {Status: "error", Details: &expo.Details{Error: "MessageTooBig"}},
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{"data": receipts}))
+	})
+
+	notificationID := uuid.New()
+	deliveries := &fakePushDeliveries{
+		notificationID: notificationID,
+		receipts:       map[string]string{},
+		reregistered:   map[string]bool{"reregistered": true},
+		pending: []values.PendingPushReceipt{
+			{NotificationID: notificationID, ExpoPushToken: "delivered", TicketID: "ticket-delivered"},
+			{NotificationID: notificationID, ExpoPushToken: "dead", TicketID: "ticket-dead"},
+			{NotificationID: notificationID, ExpoPushToken: "reregistered", TicketID: "ticket-reregistered"},
+			{NotificationID: notificationID, ExpoPushToken: "too-big", TicketID: "ticket-too-big"},
+			{NotificationID: notificationID, ExpoPushToken: "not-ready", TicketID: "ticket-not-ready"},
+		},
+	}
+	service := &NotificationService{deliveries: deliveries, expo: expoService}
+
+	result, err := service.CheckReceipts(context.Background())
+	require.Nil(t, err)
+
+	assert.Equal(t, values.PushReceiptCheckResult{Checked: 4, Delivered: 1, Failed: 3, Pruned: 1}, result)
+	assert.Equal(t, []string{"dead", "reregistered"}, deliveries.pruned)
+	assert.Equal(t, "ok:", deliveries.receipts["ticket-delivered"])
+	assert.Equal(t, "error:"+expo.ErrDeviceNotRegistered, deliveries.receipts["ticket-dead"])
+	assert.NotContains(t, deliveries.receipts, "ticket-not-ready", "a receipt that is not ready is asked for again next run")
+}
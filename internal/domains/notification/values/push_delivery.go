@@ -0,0 +1,61 @@
+package notification
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Sources a push notification can be sent from
+const (
+	PushSourceTeam  = "team"
+	PushSourceUser  = "user"
+	PushSourceEvent = "event"
+)
+
+// PushTicket is the ticket Expo returned for one device a notification went to
+type PushTicket struct {
+	NotificationID uuid.UUID
+	PushTokenID    int // 0 when the token is not a registered push token
+	ExpoPushToken  string
+	TicketID       string
+	Status         string
+	Error          string
+}
+
+// PendingPushReceipt is a ticket whose delivery receipt has not been fetched yet
+type PendingPushReceipt struct {
+	NotificationID uuid.UUID
+	ExpoPushToken  string
+	TicketID       string
+}
+
+// PushDeliveryResult summarises what Expo answered for one notification
+type PushDeliveryResult struct {
+	NotificationID uuid.UUID
+	Accepted       int
+	Failed         int
+	Pruned         int
+}
+
+// PushReceiptCheckResult summarises one run over pending delivery receipts
+type PushReceiptCheckResult struct {
+	Checked   int
+	Delivered int
+	Failed    int
+	Pruned    int
+}
+
+// PushDeliveryStats counts what happened to every device a notification went to
+type PushDeliveryStats struct {
+	NotificationID uuid.UUID
+	Source         string
+	Title          string
+	CreatedAt      time.Time
+	Sent           int
+	Accepted       int
+	Delivered      int
+	Failed         int
+	Pending        int
+	Pruned         int
+}
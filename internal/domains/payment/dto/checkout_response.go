@@ -1,5 +1,24 @@
 package payment
 
+import (
+	courtRentalDto "api/internal/domains/court_rental/dto"
+	playgroundDto "api/internal/domains/playground/dto/session"
+)
+
 type CheckoutResponseDto struct {
 	PaymentURL string `json:"payment_url"`
 }
+
+// CourtRentalCheckoutResponseDto returns the rental with a payment URL when it still
+// has to be paid for by card.
+type CourtRentalCheckoutResponseDto struct {
+	PaymentURL string                           `json:"payment_url,omitempty"`
+	Rental     courtRentalDto.RentalResponseDto `json:"rental"`
+}
+
+// PlaygroundCheckoutResponseDto returns the playground session with a payment URL when
+// it still has to be paid for by card.
+type PlaygroundCheckoutResponseDto struct {
+	PaymentURL string                    `json:"payment_url,omitempty"`
+	Session    playgroundDto.ResponseDto `json:"session"`
+}
@@ -0,0 +1,140 @@
+package payment
+
+import (
+	"time"
+
+	db "api/internal/domains/payment/persistence/sqlc/generated"
+
+	"github.com/google/uuid"
+)
+
+// DunningStepResponse is one step of the dunning schedule
+type DunningStepResponse struct {
+	ID        uuid.UUID `json:"id"`
+	DayOffset int32     `json:"day_offset"`
+	Action    string    `json:"action"`
+}
+
+// DunningEventResponse is an entry in a dunning case's timeline
+type DunningEventResponse struct {
+	Action    string    `json:"action"`
+	DayOffset *int32    `json:"day_offset,omitempty"`
+	Detail    *string   `json:"detail,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// DunningCaseResponse is a failed membership invoice and what dunning has done about it
+type DunningCaseResponse struct {
+	ID                   uuid.UUID              `json:"id"`
+	StripeInvoiceID      string                 `json:"stripe_invoice_id"`
+	StripeSubscriptionID *string                `json:"stripe_subscription_id,omitempty"`
+	AmountDue            float64                `json:"amount_due"`
+	Currency             string                 `json:"currency"`
+	Status               string                 `json:"status"` // open, collections or resolved
+	FailedAt             time.Time              `json:"failed_at"`
+	BookingRestricted    bool                   `json:"booking_restricted"`
+	Suspended            bool                   `json:"suspended"`
+	ResolvedAt           *time.Time             `json:"resolved_at,omitempty"`
+	NextStep             *DunningStepResponse   `json:"next_step,omitempty"`
+	NextStepDueAt        *time.Time             `json:"next_step_due_at,omitempty"`
+	Timeline             []DunningEventResponse `json:"timeline"`
+}
+
+// DunningCollectionsItemResponse is a case waiting for staff in the collections queue
+type DunningCollectionsItemResponse struct {
+	CaseID               uuid.UUID `json:"case_id"`
+	CustomerID           uuid.UUID `json:"customer_id"`
+	FirstName            string    `json:"first_name"`
+	LastName             string    `json:"last_name"`
+	Email                *string   `json:"email,omitempty"`
+	Phone                *string   `json:"phone,omitempty"`
+	StripeInvoiceID      string    `json:"stripe_invoice_id"`
+	StripeSubscriptionID *string   `json:"stripe_subscription_id,omitempty"`
+	AmountDue            float64   `json:"amount_due"`
+	Currency             string    `json:"currency"`
+	FailedAt             time.Time `json:"failed_at"`
+	HandedOffAt          time.Time `json:"handed_off_at"`
+	Suspended            bool      `json:"suspended"`
+}
+
+// ToDunningStepResponse converts a database model to a response DTO
+func ToDunningStepResponse(step db.PaymentsDunningStep) DunningStepResponse {
+	return DunningStepResponse{
+		ID:        step.ID,
+		DayOffset: step.DayOffset,
+		Action:    step.Action,
+	}
+}
+
+// ToDunningCaseResponse converts a case and its timeline to a response DTO
+func ToDunningCaseResponse(dunningCase db.PaymentsDunningCase, events []db.PaymentsDunningEvent, nextStep *db.PaymentsDunningStep, nextDueAt *time.Time) DunningCaseResponse {
+	amount, _ := dunningCase.AmountDue.Float64()
+
+	response := DunningCaseResponse{
+		ID:                dunningCase.ID,
+		StripeInvoiceID:   dunningCase.StripeInvoiceID,
+		AmountDue:         amount,
+		Currency:          dunningCase.Currency,
+		Status:            dunningCase.Status,
+		FailedAt:          dunningCase.FailedAt,
+		BookingRestricted: dunningCase.BookingRestricted,
+		Suspended:         dunningCase.Suspended,
+		NextStepDueAt:     nextDueAt,
+		Timeline:          make([]DunningEventResponse, 0, len(events)),
+	}
+	if dunningCase.StripeSubscriptionID.Valid {
+		response.StripeSubscriptionID = &dunningCase.StripeSubscriptionID.String
+	}
+	if dunningCase.ResolvedAt.Valid {
+		response.ResolvedAt = &dunningCase.ResolvedAt.Time
+	}
+	if nextStep != nil {
+		step := ToDunningStepResponse(*nextStep)
+		response.NextStep = &step
+	}
+
+	for _, event := range events {
+		entry := DunningEventResponse{
+			Action:    event.Action,
+			CreatedAt: event.CreatedAt,
+		}
+		if event.DayOffset.Valid {
+			entry.DayOffset = &event.DayOffset.Int32
+		}
+		if event.Detail.Valid {
+			entry.Detail = &event.Detail.String
+		}
+		response.Timeline = append(response.Timeline, entry)
+	}
+
+	return response
+}
+
+// ToDunningCollectionsItemResponse converts a collections queue row to a response DTO
+func ToDunningCollectionsItemResponse(row db.ListDunningCollectionsQueueRow) DunningCollectionsItemResponse {
+	amount, _ := row.AmountDue.Float64()
+
+	response := DunningCollectionsItemResponse{
+		CaseID:          row.ID,
+		CustomerID:      row.CustomerID,
+		FirstName:       row.FirstName,
+		LastName:        row.LastName,
+		StripeInvoiceID: row.StripeInvoiceID,
+		AmountDue:       amount,
+		Currency:        row.Currency,
+		FailedAt:        row.FailedAt,
+		HandedOffAt:     row.HandedOffAt,
+		Suspended:       row.Suspended,
+	}
+	if row.Email.Valid {
+		response.Email = &row.Email.String
+	}
+	if row.Phone.Valid {
+		response.Phone = &row.Phone.String
+	}
+	if row.StripeSubscriptionID.Valid {
+		response.StripeSubscriptionID = &row.StripeSubscriptionID.String
+	}
+
+	return response
+}
@@ -0,0 +1,105 @@
+package payment
+
+import (
+	"time"
+
+	db "api/internal/domains/payment/persistence/sqlc/generated"
+
+	"github.com/google/uuid"
+)
+
+// RefundResponse is the API response for a refund of a payment transaction
+type RefundResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	TransactionID  uuid.UUID  `json:"transaction_id"`
+	StripeRefundID *string    `json:"stripe_refund_id,omitempty"`
+	Amount         float64    `json:"amount"`
+	Reason         *string    `json:"reason,omitempty"`
+	Source         string     `json:"source"` // 'admin', 'stripe' or 'system'
+	AccessRevoked  bool       `json:"access_revoked"`
+	IssuedBy       *uuid.UUID `json:"issued_by,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// RefundResultResponse is returned after staff refund a payment. RevocationError is set
+// when the money was refunded but access could not be revoked.
+type RefundResultResponse struct {
+	Refund          RefundResponse             `json:"refund"`
+	Transaction     PaymentTransactionResponse `json:"transaction"`
+	RevocationError string                     `json:"revocation_error,omitempty"`
+}
+
+// DisputeResponse is the API response for a Stripe dispute
+type DisputeResponse struct {
+	ID                    uuid.UUID  `json:"id"`
+	StripeDisputeID       string     `json:"stripe_dispute_id"`
+	TransactionID         *uuid.UUID `json:"transaction_id,omitempty"`
+	StripeChargeID        string     `json:"stripe_charge_id"`
+	StripePaymentIntentID *string    `json:"stripe_payment_intent_id,omitempty"`
+	Amount                float64    `json:"amount"`
+	Currency              string     `json:"currency"`
+	Reason                string     `json:"reason"`
+	Status                string     `json:"status"`
+	EvidenceDueBy         *time.Time `json:"evidence_due_by,omitempty"`
+	ClosedAt              *time.Time `json:"closed_at,omitempty"`
+	CreatedAt             time.Time  `json:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at"`
+}
+
+// ToRefundResponse converts a database model to a response DTO
+func ToRefundResponse(refund db.PaymentsRefund) RefundResponse {
+	amount, _ := refund.Amount.Float64()
+
+	response := RefundResponse{
+		ID:            refund.ID,
+		TransactionID: refund.TransactionID,
+		Amount:        amount,
+		Source:        refund.Source,
+		AccessRevoked: refund.AccessRevoked,
+		CreatedAt:     refund.CreatedAt,
+	}
+
+	if refund.StripeRefundID.Valid {
+		response.StripeRefundID = &refund.StripeRefundID.String
+	}
+	if refund.Reason.Valid {
+		response.Reason = &refund.Reason.String
+	}
+	if refund.IssuedBy.Valid {
+		response.IssuedBy = &refund.IssuedBy.UUID
+	}
+
+	return response
+}
+
+// ToDisputeResponse converts a database model to a response DTO
+func ToDisputeResponse(dispute db.PaymentsDispute) DisputeResponse {
+	amount, _ := dispute.Amount.Float64()
+
+	response := DisputeResponse{
+		ID:              dispute.ID,
+		StripeDisputeID: dispute.StripeDisputeID,
+		StripeChargeID:  dispute.StripeChargeID,
+		Amount:          amount,
+		Currency:        dispute.Currency,
+		Reason:          dispute.Reason,
+		Status:          dispute.Status,
+		CreatedAt:       dispute.CreatedAt,
+		UpdatedAt:       dispute.UpdatedAt,
+	}
+
+	if dispute.TransactionID.Valid {
+		response.TransactionID = &dispute.TransactionID.UUID
+	}
+	if dispute.StripePaymentIntentID.Valid {
+		response.StripePaymentIntentID = &dispute.StripePaymentIntentID.String
+	}
+	if dispute.EvidenceDueBy.Valid {
+		response.EvidenceDueBy = &dispute.EvidenceDueBy.Time
+	}
+	if dispute.ClosedAt.Valid {
+		response.ClosedAt = &dispute.ClosedAt.Time
+	}
+
+	return response
+}
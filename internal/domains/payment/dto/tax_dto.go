@@ -0,0 +1,65 @@
+package payment
+
+import (
+	"time"
+
+	db "api/internal/domains/payment/persistence/sqlc/generated"
+
+	"github.com/google/uuid"
+)
+
+// TaxRateResponse is the API response for a sales tax rate
+type TaxRateResponse struct {
+	ID                 uuid.UUID `json:"id"`
+	Province           string    `json:"province"`
+	Name               string    `json:"name"`
+	Rate               float64   `json:"rate"` // Percentage, e.g. 5 for 5%
+	RegistrationNumber *string   `json:"registration_number,omitempty"`
+	StripeTaxRateID    string    `json:"stripe_tax_rate_id"`
+	IsActive           bool      `json:"is_active"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// RemittanceLineResponse is the tax owed for one tax over a reporting period
+type RemittanceLineResponse struct {
+	Province         string  `json:"province"`
+	Name             string  `json:"name"`
+	Rate             float64 `json:"rate"`
+	TransactionCount int64   `json:"transaction_count"`
+	TaxableAmount    float64 `json:"taxable_amount"`
+	TaxCollected     float64 `json:"tax_collected"`
+	TaxRefunded      float64 `json:"tax_refunded"`
+	NetTax           float64 `json:"net_tax"`
+}
+
+// RemittanceReportResponse is the tax to remit for a period. Tax on refunds issued in
+// the period is deducted even when the sale was made in an earlier period.
+type RemittanceReportResponse struct {
+	StartDate time.Time                `json:"start_date"`
+	EndDate   time.Time                `json:"end_date"`
+	Lines     []RemittanceLineResponse `json:"lines"`
+	NetTax    float64                  `json:"net_tax"`
+}
+
+// ToTaxRateResponse converts a database model to a response DTO
+func ToTaxRateResponse(rate db.PaymentsTaxRate) TaxRateResponse {
+	percentage, _ := rate.Rate.Float64()
+
+	response := TaxRateResponse{
+		ID:              rate.ID,
+		Province:        rate.Province,
+		Name:            rate.Name,
+		Rate:            percentage,
+		StripeTaxRateID: rate.StripeTaxRateID,
+		IsActive:        rate.IsActive,
+		CreatedAt:       rate.CreatedAt,
+		UpdatedAt:       rate.UpdatedAt,
+	}
+
+	if rate.RegistrationNumber.Valid {
+		response.RegistrationNumber = &rate.RegistrationNumber.String
+	}
+
+	return response
+}
@@ -5,6 +5,9 @@ import (
 	"net/http"
 
 	"api/internal/di"
+	courtRentalDto "api/internal/domains/court_rental/dto"
+	giftCardDto "api/internal/domains/gift_card/dto"
+	playgroundDto "api/internal/domains/playground/dto/session"
 	dto "api/internal/domains/payment/dto"
 	service "api/internal/domains/payment/services"
 	"api/internal/domains/payment/services/stripe"
@@ -37,6 +40,7 @@ func NewCheckoutHandlers(container *di.Container) *CheckoutHandlers {
 // @Produce json
 // @Param id path string true "Membership plan ID"
 // @Param discount_code query string false "Discount code to apply"
+// @Param use_balance query bool false "Apply the customer's gift card balance"
 // @Success 200 {object} dto.CheckoutResponseDto "Payment link generated successfully"
 // @Failure 400 {object} map[string]interface{} "Bad Request: Invalid input"
 // @Failure 500 {object} map[string]interface{} "Internal Server Error: Failed to process checkout"
@@ -68,7 +72,9 @@ func (h *CheckoutHandlers) CheckoutMembership(w http.ResponseWriter, r *http.Req
 	// Get success and cancel URLs based on request origin
 	successURL, cancelURL := stripe.GetCheckoutURLs(r)
 
-	if paymentLink, err := h.Service.CheckoutMembershipPlan(r.Context(), membershipPlanId, discountCode, successURL, cancelURL); err != nil {
+	useBalance := r.URL.Query().Get("use_balance") == "true"
+
+	if paymentLink, err := h.Service.CheckoutMembershipPlan(r.Context(), membershipPlanId, discountCode, useBalance, successURL, cancelURL); err != nil {
 		responseDto.PaymentURL = paymentLink
 		responseHandlers.RespondWithError(w, err)
 	} else {
@@ -129,6 +135,7 @@ func (h *CheckoutHandlers) CheckoutProgram(w http.ResponseWriter, r *http.Reques
 // @Produce json
 // @Param id path string true "Event ID" format(uuid)
 // @Param discount_code query string false "Discount code to apply"
+// @Param use_balance query bool false "Apply the customer's gift card balance"
 // @Success 200 {object} dto.CheckoutResponseDto "Payment link generated successfully"
 // @Failure 400 {object} map[string]interface{} "Bad Request: Invalid input or missing event ID"
 // @Failure 404 {object} map[string]interface{} "Not Found: Event not found"
@@ -162,7 +169,9 @@ func (h *CheckoutHandlers) CheckoutEvent(w http.ResponseWriter, r *http.Request)
 	// Get success and cancel URLs based on request origin
 	successURL, cancelURL := stripe.GetCheckoutURLs(r)
 
-	if paymentLink, err := h.Service.CheckoutEvent(r.Context(), eventID, discountCode, successURL, cancelURL); err != nil {
+	useBalance := r.URL.Query().Get("use_balance") == "true"
+
+	if paymentLink, err := h.Service.CheckoutEvent(r.Context(), eventID, discountCode, useBalance, successURL, cancelURL); err != nil {
 		responseHandlers.RespondWithError(w, err)
 		return
 	} else {
@@ -212,6 +221,7 @@ func (h *CheckoutHandlers) GetEventEnrollmentOptions(w http.ResponseWriter, r *h
 // @Produce json
 // @Param id path string true "Event ID" format(uuid)
 // @Param discount_code query string false "Discount code to apply"
+// @Param use_balance query bool false "Apply the customer's gift card balance"
 // @Param request body map[string]interface{} false "Payment method request" example({"payment_method":"stripe"}) enum("stripe","credits")
 // @Success 200 {object} dto.CheckoutResponseDto "Payment link generated or free enrollment completed"
 // @Failure 400 {object} map[string]interface{} "Bad Request: Invalid input or missing event ID"
@@ -282,7 +292,9 @@ func (h *CheckoutHandlers) CheckoutEventEnhanced(w http.ResponseWriter, r *http.
 	// Get success and cancel URLs based on request origin
 	successURL, cancelURL := stripe.GetCheckoutURLs(r)
 
-	if paymentLink, err := h.Service.CheckoutEventEnhanced(r.Context(), eventID, discountCode, successURL, cancelURL); err != nil {
+	useBalance := r.URL.Query().Get("use_balance") == "true"
+
+	if paymentLink, err := h.Service.CheckoutEventEnhanced(r.Context(), eventID, discountCode, useBalance, successURL, cancelURL); err != nil {
 		responseHandlers.RespondWithError(w, err)
 		return
 	} else {
@@ -301,6 +313,145 @@ func (h *CheckoutHandlers) CheckoutEventEnhanced(w http.ResponseWriter, r *http.
 	}
 }
 
+// CheckoutCourtRental rents a court for the logged-in customer.
+// @Description Paying with credits confirms the rental at once. Paying by card holds the court for 30 minutes
+// @Description and returns a Stripe payment URL; the rental is confirmed when the payment completes.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param request body courtRentalDto.RentalRequestDto true "Court, time and payment method"
+// @Success 200 {object} dto.CourtRentalCheckoutResponseDto "Rental held with payment link, or confirmed"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid time, insufficient credits or weekly limit exceeded"
+// @Failure 404 {object} map[string]interface{} "Not Found: Court is not available for rental"
+// @Failure 409 {object} map[string]interface{} "Conflict: Court is already booked"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error: Failed to process checkout"
+// @Security Bearer
+// @Router /checkout/court_rentals [post]
+func (h *CheckoutHandlers) CheckoutCourtRental(w http.ResponseWriter, r *http.Request) {
+	customerID, err := contextUtils.GetUserID(r.Context())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	var requestDto courtRentalDto.RentalRequestDto
+	if err = validators.ParseJSON(r.Body, &requestDto); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	req, err := requestDto.ToHoldRequest(customerID)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	// Get success and cancel URLs based on request origin
+	successURL, cancelURL := stripe.GetCheckoutURLs(r)
+
+	paymentLink, rental, err := h.Service.CheckoutCourtRental(r.Context(), req, successURL, cancelURL)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	responseHandlers.RespondWithSuccess(w, dto.CourtRentalCheckoutResponseDto{
+		PaymentURL: paymentLink,
+		Rental:     courtRentalDto.NewRentalResponse(rental),
+	}, http.StatusOK)
+}
+
+// CheckoutPlaygroundSession books a playground session for the logged-in customer.
+// @Description Paying with credits confirms the session at once. Paying by card holds the system for 30 minutes
+// @Description and returns a Stripe payment URL; the session is confirmed when the payment completes.
+// @Description Sessions on free systems are confirmed without payment.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param request body playgroundDto.BookRequestDto true "System, time and payment method"
+// @Success 200 {object} dto.PlaygroundCheckoutResponseDto "Session held with payment link, or confirmed"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Breaks the system's rules, insufficient credits or weekly limit exceeded"
+// @Failure 404 {object} map[string]interface{} "Not Found: System not found"
+// @Failure 409 {object} map[string]interface{} "Conflict: System is already booked or too many upcoming sessions"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error: Failed to process checkout"
+// @Security Bearer
+// @Router /checkout/playground_sessions [post]
+func (h *CheckoutHandlers) CheckoutPlaygroundSession(w http.ResponseWriter, r *http.Request) {
+	customerID, err := contextUtils.GetUserID(r.Context())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	var requestDto playgroundDto.BookRequestDto
+	if err = validators.ParseJSON(r.Body, &requestDto); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	req, err := requestDto.ToBookValue(customerID)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	// Get success and cancel URLs based on request origin
+	successURL, cancelURL := stripe.GetCheckoutURLs(r)
+
+	paymentLink, session, err := h.Service.CheckoutPlaygroundSession(r.Context(), req, successURL, cancelURL)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	responseHandlers.RespondWithSuccess(w, dto.PlaygroundCheckoutResponseDto{
+		PaymentURL: paymentLink,
+		Session:    playgroundDto.NewResponse(session),
+	}, http.StatusOK)
+}
+
+// CheckoutGiftCard buys a gift card for someone else.
+// @Description Returns a Stripe payment URL. Once paid, the card's code is emailed to the recipient
+// @Description and a receipt to the buyer. Gift cards are not taxed when sold.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param request body giftCardDto.PurchaseRequestDto true "Amount and recipient"
+// @Success 200 {object} dto.CheckoutResponseDto "Payment link generated successfully"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid input"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error: Failed to process checkout"
+// @Security Bearer
+// @Router /checkout/gift_cards [post]
+func (h *CheckoutHandlers) CheckoutGiftCard(w http.ResponseWriter, r *http.Request) {
+	customerID, err := contextUtils.GetUserID(r.Context())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	var requestDto giftCardDto.PurchaseRequestDto
+	if err = validators.ParseJSON(r.Body, &requestDto); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	req, err := requestDto.ToValues(customerID)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	// Get success and cancel URLs based on request origin
+	successURL, cancelURL := stripe.GetCheckoutURLs(r)
+
+	paymentLink, err := h.Service.CheckoutGiftCard(r.Context(), req, successURL, cancelURL)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.CheckoutResponseDto{PaymentURL: paymentLink}, http.StatusOK)
+}
+
 // TestSlackAlert manually triggers a Slack alert for testing
 func (h *CheckoutHandlers) TestSlackAlert(w http.ResponseWriter, r *http.Request) {
 	// Use structured logger to trigger Slack alert with critical component
@@ -93,6 +93,7 @@ func (h *CollectionsHandler) GetCustomerPaymentMethods(w http.ResponseWriter, r
 // @Tags Collections
 // @Accept json
 // @Produce json
+// @Param Idempotency-Key header string true "Unique key for this submission; a retry with the same key returns the first response"
 // @Param body body service.ChargeCardRequest true "Charge card request"
 // @Success 200 {object} service.CollectionResult "Charge result"
 // @Failure 400 {object} map[string]string "Bad request"
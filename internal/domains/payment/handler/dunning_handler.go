@@ -0,0 +1,138 @@
+package payment
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"api/internal/di"
+	dto "api/internal/domains/payment/dto"
+	service "api/internal/domains/payment/services"
+	errLib "api/internal/libs/errors"
+	responses "api/internal/libs/responses"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+type DunningHandler struct {
+	service *service.DunningService
+}
+
+func NewDunningHandler(container *di.Container) *DunningHandler {
+	return &DunningHandler{
+		service: service.NewDunningService(container),
+	}
+}
+
+// ListDunningSteps returns the dunning schedule
+// @Summary Get the dunning schedule
+// @Description List the steps taken on failed membership payments, in the order they run
+// @Tags Collections
+// @Produce json
+// @Success 200 {array} dto.DunningStepResponse "Dunning steps"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /admin/collections/dunning-steps [get]
+func (h *DunningHandler) ListDunningSteps(w http.ResponseWriter, r *http.Request) {
+	steps, err := h.service.ListSteps(r.Context())
+	if err != nil {
+		responses.RespondWithError(w, err)
+		return
+	}
+
+	response := make([]dto.DunningStepResponse, 0, len(steps))
+	for _, step := range steps {
+		response = append(response, dto.ToDunningStepResponse(step))
+	}
+
+	responses.RespondWithSuccess(w, response, http.StatusOK)
+}
+
+// ReplaceDunningSteps replaces the dunning schedule
+// @Summary Update the dunning schedule
+// @Description Replace the steps taken on failed membership payments. Each step runs day_offset days after the payment failed: remind emails the customer, restrict_booking blocks new bookings, suspend suspends the account and collections hands the case to staff. Open cases follow the new schedule from the next run.
+// @Tags Collections
+// @Accept json
+// @Produce json
+// @Param body body service.ReplaceDunningStepsRequest true "Dunning schedule"
+// @Success 200 {array} dto.DunningStepResponse "Dunning steps"
+// @Failure 400 {object} map[string]string "Invalid schedule"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /admin/collections/dunning-steps [put]
+func (h *DunningHandler) ReplaceDunningSteps(w http.ResponseWriter, r *http.Request) {
+	var req service.ReplaceDunningStepsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		responses.RespondWithError(w, errLib.New("Invalid request body", http.StatusBadRequest))
+		return
+	}
+
+	steps, err := h.service.ReplaceSteps(r.Context(), req.Steps)
+	if err != nil {
+		responses.RespondWithError(w, err)
+		return
+	}
+
+	response := make([]dto.DunningStepResponse, 0, len(steps))
+	for _, step := range steps {
+		response = append(response, dto.ToDunningStepResponse(step))
+	}
+
+	responses.RespondWithSuccess(w, response, http.StatusOK)
+}
+
+// GetCustomerDunning returns a customer's dunning timeline
+// @Summary Get a customer's dunning timeline
+// @Description List the customer's failed membership payments, most recent first, with every dunning step taken and the next one due
+// @Tags Collections
+// @Produce json
+// @Param customer_id path string true "Customer ID"
+// @Success 200 {array} dto.DunningCaseResponse "Dunning cases"
+// @Failure 400 {object} map[string]string "Invalid customer ID"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /admin/collections/customers/{customer_id}/dunning [get]
+func (h *DunningHandler) GetCustomerDunning(w http.ResponseWriter, r *http.Request) {
+	customerID, err := uuid.Parse(chi.URLParam(r, "customer_id"))
+	if err != nil {
+		responses.RespondWithError(w, errLib.New("Invalid customer ID", http.StatusBadRequest))
+		return
+	}
+
+	timelines, svcErr := h.service.CustomerTimeline(r.Context(), customerID)
+	if svcErr != nil {
+		responses.RespondWithError(w, svcErr)
+		return
+	}
+
+	response := make([]dto.DunningCaseResponse, 0, len(timelines))
+	for _, timeline := range timelines {
+		response = append(response, dto.ToDunningCaseResponse(timeline.Case, timeline.Events, timeline.NextStep, timeline.NextDueAt))
+	}
+
+	responses.RespondWithSuccess(w, response, http.StatusOK)
+}
+
+// GetCollectionsQueue returns the cases handed to collections
+// @Summary Get the collections queue
+// @Description List unpaid membership invoices that went through the whole dunning schedule, oldest first
+// @Tags Collections
+// @Produce json
+// @Success 200 {array} dto.DunningCollectionsItemResponse "Collections queue"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /admin/collections/queue [get]
+func (h *DunningHandler) GetCollectionsQueue(w http.ResponseWriter, r *http.Request) {
+	queue, err := h.service.CollectionsQueue(r.Context())
+	if err != nil {
+		responses.RespondWithError(w, err)
+		return
+	}
+
+	response := make([]dto.DunningCollectionsItemResponse, 0, len(queue))
+	for _, row := range queue {
+		response = append(response, dto.ToDunningCollectionsItemResponse(row))
+	}
+
+	responses.RespondWithSuccess(w, response, http.StatusOK)
+}
@@ -0,0 +1,86 @@
+package payment
+
+import (
+	"net/http"
+
+	discountDto "api/internal/domains/discount/dto"
+	discountValues "api/internal/domains/discount/values"
+	responseHandlers "api/internal/libs/responses"
+	"api/internal/libs/validators"
+
+	"github.com/go-chi/chi"
+)
+
+// PreviewMembershipPrice shows what the customer would pay for a membership plan.
+// @Summary Preview the price of a membership plan
+// @Description Breaks down the price the logged-in customer would pay at checkout, before tax: automatic discounts (sibling, early bird, member pricing, bundles), the discount code when given, and discounts that were left out with the reason why. Amounts are in cents.
+// @Tags payments
+// @Produce json
+// @Param id path string true "Membership plan ID" format(uuid)
+// @Param discount_code query string false "Discount code to compare with the automatic discounts"
+// @Success 200 {object} discountDto.PriceQuoteResponseDto "Price breakdown"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid input or discount code not valid for this item"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Discount code usage limit reached"
+// @Failure 404 {object} map[string]interface{} "Not Found: Discount code not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Security Bearer
+// @Router /checkout/membership_plans/{id}/preview [get]
+func (h *CheckoutHandlers) PreviewMembershipPrice(w http.ResponseWriter, r *http.Request) {
+	h.previewPrice(w, r, discountValues.ItemMembership)
+}
+
+// PreviewProgramPrice shows what the customer would pay for a program.
+// @Summary Preview the price of a program
+// @Description Breaks down the price the logged-in customer would pay at checkout, before tax: automatic discounts (sibling, early bird, member pricing, bundles), the discount code when given, and discounts that were left out with the reason why. Amounts are in cents.
+// @Tags payments
+// @Produce json
+// @Param id path string true "Program ID" format(uuid)
+// @Param discount_code query string false "Discount code to compare with the automatic discounts"
+// @Success 200 {object} discountDto.PriceQuoteResponseDto "Price breakdown"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid input, program paid per event, or discount code not valid for this item"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Discount code usage limit reached"
+// @Failure 404 {object} map[string]interface{} "Not Found: Discount code not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Security Bearer
+// @Router /checkout/programs/{id}/preview [get]
+func (h *CheckoutHandlers) PreviewProgramPrice(w http.ResponseWriter, r *http.Request) {
+	h.previewPrice(w, r, discountValues.ItemProgram)
+}
+
+// PreviewEventPrice shows what the customer would pay for an event.
+// @Summary Preview the price of an event
+// @Description Breaks down the price the logged-in customer would pay at checkout, before tax. Events that are free for the customer's membership have a price of 0. Amounts are in cents.
+// @Tags payments
+// @Produce json
+// @Param id path string true "Event ID" format(uuid)
+// @Param discount_code query string false "Discount code to compare with the automatic discounts"
+// @Success 200 {object} discountDto.PriceQuoteResponseDto "Price breakdown"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid input, event not for sale, or discount code not valid for this item"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Discount code usage limit reached"
+// @Failure 404 {object} map[string]interface{} "Not Found: Event or discount code not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Security Bearer
+// @Router /checkout/events/{id}/preview [get]
+func (h *CheckoutHandlers) PreviewEventPrice(w http.ResponseWriter, r *http.Request) {
+	h.previewPrice(w, r, discountValues.ItemEvent)
+}
+
+func (h *CheckoutHandlers) previewPrice(w http.ResponseWriter, r *http.Request, itemType discountValues.ItemType) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	var discountCode *string
+	if code := r.URL.Query().Get("discount_code"); code != "" {
+		discountCode = &code
+	}
+
+	quote, err := h.Service.PreviewPrice(r.Context(), discountValues.PriceItem{Type: itemType, ID: id}, discountCode)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, discountDto.NewPriceQuoteResponse(quote), http.StatusOK)
+}
@@ -0,0 +1,149 @@
+package payment
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"api/internal/di"
+	service "api/internal/domains/payment/services"
+	errLib "api/internal/libs/errors"
+	responses "api/internal/libs/responses"
+	contextUtils "api/utils/context"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+type ReceiptsHandler struct {
+	service *service.ReceiptService
+}
+
+func NewReceiptsHandler(container *di.Container) *ReceiptsHandler {
+	return &ReceiptsHandler{
+		service: service.NewReceiptService(container),
+	}
+}
+
+// GetMyReceipt downloads the receipt of one of the customer's payments
+// @Summary Download a payment receipt
+// @Description Download the PDF receipt of a completed payment made by the logged-in customer, with its sales tax breakdown
+// @Tags Receipts
+// @Produce application/pdf
+// @Param id path string true "Payment transaction ID"
+// @Success 200 {file} file "PDF receipt"
+// @Failure 400 {object} map[string]string "Invalid transaction ID"
+// @Failure 404 {object} map[string]string "Payment not found"
+// @Failure 409 {object} map[string]string "Payment is not completed"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /receipts/transactions/{id} [get]
+func (h *ReceiptsHandler) GetMyReceipt(w http.ResponseWriter, r *http.Request) {
+	customerID, err := contextUtils.GetUserID(r.Context())
+	if err != nil {
+		responses.RespondWithError(w, err)
+		return
+	}
+
+	h.receipt(w, r, &customerID)
+}
+
+// GetMyStatement downloads the customer's annual statement
+// @Summary Download an annual statement
+// @Description Download a PDF of every payment the logged-in customer made in a calendar year, with the sales tax paid
+// @Tags Receipts
+// @Produce application/pdf
+// @Param year path int true "Calendar year"
+// @Success 200 {file} file "PDF statement"
+// @Failure 400 {object} map[string]string "Invalid year"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /receipts/statements/{year} [get]
+func (h *ReceiptsHandler) GetMyStatement(w http.ResponseWriter, r *http.Request) {
+	customerID, err := contextUtils.GetUserID(r.Context())
+	if err != nil {
+		responses.RespondWithError(w, err)
+		return
+	}
+
+	h.statement(w, r, customerID)
+}
+
+// GetTransactionReceipt downloads the receipt of any payment
+// @Summary Download a payment receipt (admin)
+// @Description Download the PDF receipt of any completed payment, with its sales tax breakdown
+// @Tags Payments - Admin
+// @Produce application/pdf
+// @Param id path string true "Payment transaction ID"
+// @Success 200 {file} file "PDF receipt"
+// @Failure 400 {object} map[string]string "Invalid transaction ID"
+// @Failure 404 {object} map[string]string "Payment not found"
+// @Failure 409 {object} map[string]string "Payment is not completed"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /admin/payments/transactions/{id}/receipt [get]
+func (h *ReceiptsHandler) GetTransactionReceipt(w http.ResponseWriter, r *http.Request) {
+	h.receipt(w, r, nil)
+}
+
+// GetCustomerStatement downloads a customer's annual statement
+// @Summary Download a customer's annual statement (admin)
+// @Description Download a PDF of every payment a customer made in a calendar year, with the sales tax paid
+// @Tags Payments - Admin
+// @Produce application/pdf
+// @Param id path string true "Customer ID"
+// @Param year path int true "Calendar year"
+// @Success 200 {file} file "PDF statement"
+// @Failure 400 {object} map[string]string "Invalid customer ID or year"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /admin/payments/customers/{id}/statements/{year} [get]
+func (h *ReceiptsHandler) GetCustomerStatement(w http.ResponseWriter, r *http.Request) {
+	customerID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		responses.RespondWithError(w, errLib.New("Invalid customer ID", http.StatusBadRequest))
+		return
+	}
+
+	h.statement(w, r, customerID)
+}
+
+func (h *ReceiptsHandler) receipt(w http.ResponseWriter, r *http.Request, customerID *uuid.UUID) {
+	transactionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		responses.RespondWithError(w, errLib.New("Invalid transaction ID", http.StatusBadRequest))
+		return
+	}
+
+	document, svcErr := h.service.TransactionReceipt(r.Context(), transactionID, customerID)
+	if svcErr != nil {
+		responses.RespondWithError(w, svcErr)
+		return
+	}
+
+	writePDF(w, fmt.Sprintf("receipt_%s.pdf", transactionID), document)
+}
+
+func (h *ReceiptsHandler) statement(w http.ResponseWriter, r *http.Request, customerID uuid.UUID) {
+	year, err := strconv.Atoi(chi.URLParam(r, "year"))
+	if err != nil {
+		responses.RespondWithError(w, errLib.New("Invalid year", http.StatusBadRequest))
+		return
+	}
+
+	document, svcErr := h.service.AnnualStatement(r.Context(), customerID, year)
+	if svcErr != nil {
+		responses.RespondWithError(w, svcErr)
+		return
+	}
+
+	writePDF(w, fmt.Sprintf("statement_%d.pdf", year), document)
+}
+
+func writePDF(w http.ResponseWriter, filename string, document []byte) {
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", "attachment; filename="+filename)
+	w.Header().Set("Content-Length", strconv.Itoa(len(document)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(document)
+}
@@ -0,0 +1,125 @@
+package payment
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"api/internal/di"
+	dto "api/internal/domains/payment/dto"
+	service "api/internal/domains/payment/services"
+	errLib "api/internal/libs/errors"
+	responses "api/internal/libs/responses"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+type RefundsHandler struct {
+	service *service.RefundsService
+}
+
+func NewRefundsHandler(container *di.Container) *RefundsHandler {
+	return &RefundsHandler{
+		service: service.NewRefundsService(container),
+	}
+}
+
+// RefundTransaction refunds a payment through Stripe
+// @Summary Refund a payment
+// @Description Refund all or part of a payment transaction through Stripe. Full refunds revoke the enrollment, membership or booking unless revoke_access is false; partial refunds only revoke when revoke_access is true.
+// @Tags Payments - Admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Payment transaction ID"
+// @Param body body service.RefundRequest true "Refund request (omit amount for a full refund)"
+// @Success 200 {object} dto.RefundResultResponse "Refund issued"
+// @Failure 400 {object} map[string]string "Invalid amount or missing reason"
+// @Failure 404 {object} map[string]string "Payment transaction not found"
+// @Failure 409 {object} map[string]string "Payment is not refundable"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /admin/payments/transactions/{id}/refund [post]
+func (h *RefundsHandler) RefundTransaction(w http.ResponseWriter, r *http.Request) {
+	transactionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		responses.RespondWithError(w, errLib.New("Invalid transaction ID", http.StatusBadRequest))
+		return
+	}
+
+	var req service.RefundRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		responses.RespondWithError(w, errLib.New("Invalid request body", http.StatusBadRequest))
+		return
+	}
+
+	result, svcErr := h.service.RefundTransaction(r.Context(), transactionID, req)
+	if svcErr != nil {
+		responses.RespondWithError(w, svcErr)
+		return
+	}
+
+	responses.RespondWithSuccess(w, dto.RefundResultResponse{
+		Refund:          dto.ToRefundResponse(result.Refund),
+		Transaction:     dto.ToPaymentTransactionResponse(result.Transaction),
+		RevocationError: result.RevocationError,
+	}, http.StatusOK)
+}
+
+// ListTransactionRefunds lists the refunds of a payment
+// @Summary List refunds of a payment
+// @Description List refunds of a payment transaction, both issued through the API and reconciled from Stripe
+// @Tags Payments - Admin
+// @Produce json
+// @Param id path string true "Payment transaction ID"
+// @Success 200 {array} dto.RefundResponse "Refunds"
+// @Failure 400 {object} map[string]string "Invalid transaction ID"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /admin/payments/transactions/{id}/refunds [get]
+func (h *RefundsHandler) ListTransactionRefunds(w http.ResponseWriter, r *http.Request) {
+	transactionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		responses.RespondWithError(w, errLib.New("Invalid transaction ID", http.StatusBadRequest))
+		return
+	}
+
+	refunds, svcErr := h.service.ListTransactionRefunds(r.Context(), transactionID)
+	if svcErr != nil {
+		responses.RespondWithError(w, svcErr)
+		return
+	}
+
+	response := make([]dto.RefundResponse, 0, len(refunds))
+	for _, refund := range refunds {
+		response = append(response, dto.ToRefundResponse(refund))
+	}
+
+	responses.RespondWithSuccess(w, response, http.StatusOK)
+}
+
+// ListDisputes lists Stripe disputes
+// @Summary List disputes
+// @Description List payment disputes, soonest evidence deadline first
+// @Tags Payments - Admin
+// @Produce json
+// @Param open query bool false "Only disputes that are still open"
+// @Success 200 {array} dto.DisputeResponse "Disputes"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /admin/payments/disputes [get]
+func (h *RefundsHandler) ListDisputes(w http.ResponseWriter, r *http.Request) {
+	openOnly := r.URL.Query().Get("open") == "true"
+
+	disputes, svcErr := h.service.ListDisputes(r.Context(), openOnly)
+	if svcErr != nil {
+		responses.RespondWithError(w, svcErr)
+		return
+	}
+
+	response := make([]dto.DisputeResponse, 0, len(disputes))
+	for _, dispute := range disputes {
+		response = append(response, dto.ToDisputeResponse(dispute))
+	}
+
+	responses.RespondWithSuccess(w, response, http.StatusOK)
+}
@@ -0,0 +1,189 @@
+package payment
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"api/internal/di"
+	dto "api/internal/domains/payment/dto"
+	service "api/internal/domains/payment/services"
+	errLib "api/internal/libs/errors"
+	responses "api/internal/libs/responses"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+type TaxesHandler struct {
+	service *service.TaxService
+}
+
+func NewTaxesHandler(container *di.Container) *TaxesHandler {
+	return &TaxesHandler{
+		service: service.NewTaxService(container),
+	}
+}
+
+// ListTaxRates lists sales tax rates
+// @Summary List sales tax rates
+// @Description List the sales tax rates charged per province, active rates only unless include_inactive is true
+// @Tags Payments - Admin
+// @Produce json
+// @Param include_inactive query bool false "Include deactivated rates"
+// @Success 200 {array} dto.TaxRateResponse "Tax rates"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /admin/payments/tax-rates [get]
+func (h *TaxesHandler) ListTaxRates(w http.ResponseWriter, r *http.Request) {
+	includeInactive := r.URL.Query().Get("include_inactive") == "true"
+
+	rates, err := h.service.ListTaxRates(r.Context(), includeInactive)
+	if err != nil {
+		responses.RespondWithError(w, err)
+		return
+	}
+
+	response := make([]dto.TaxRateResponse, 0, len(rates))
+	for _, rate := range rates {
+		response = append(response, dto.ToTaxRateResponse(rate))
+	}
+
+	responses.RespondWithSuccess(w, response, http.StatusOK)
+}
+
+// CreateTaxRate adds a sales tax rate
+// @Summary Add a sales tax rate
+// @Description Create a sales tax rate in Stripe and charge it on new checkouts at facilities in the province. To change a rate, deactivate the current one and add a new one.
+// @Tags Payments - Admin
+// @Accept json
+// @Produce json
+// @Param body body service.CreateTaxRateRequest true "Tax rate"
+// @Success 201 {object} dto.TaxRateResponse "Tax rate created"
+// @Failure 400 {object} map[string]string "Invalid province, tax or rate"
+// @Failure 409 {object} map[string]string "The province already charges this tax"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /admin/payments/tax-rates [post]
+func (h *TaxesHandler) CreateTaxRate(w http.ResponseWriter, r *http.Request) {
+	var req service.CreateTaxRateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		responses.RespondWithError(w, errLib.New("Invalid request body", http.StatusBadRequest))
+		return
+	}
+
+	rate, err := h.service.CreateTaxRate(r.Context(), req)
+	if err != nil {
+		responses.RespondWithError(w, err)
+		return
+	}
+
+	responses.RespondWithSuccess(w, dto.ToTaxRateResponse(rate), http.StatusCreated)
+}
+
+// DeactivateTaxRate stops charging a sales tax rate
+// @Summary Deactivate a sales tax rate
+// @Description Stop charging a sales tax rate on new checkouts. Receipts and reports of past payments are unaffected.
+// @Tags Payments - Admin
+// @Param id path string true "Tax rate ID"
+// @Success 204 "Tax rate deactivated"
+// @Failure 400 {object} map[string]string "Invalid tax rate ID"
+// @Failure 404 {object} map[string]string "Tax rate not found"
+// @Failure 409 {object} map[string]string "Tax rate is already inactive"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /admin/payments/tax-rates/{id} [delete]
+func (h *TaxesHandler) DeactivateTaxRate(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		responses.RespondWithError(w, errLib.New("Invalid tax rate ID", http.StatusBadRequest))
+		return
+	}
+
+	if svcErr := h.service.DeactivateTaxRate(r.Context(), id); svcErr != nil {
+		responses.RespondWithError(w, svcErr)
+		return
+	}
+
+	responses.RespondWithSuccess(w, nil, http.StatusNoContent)
+}
+
+// GetTaxRemittanceReport reports the sales tax to remit for a period
+// @Summary Sales tax remittance report
+// @Description Tax collected on sales in [start_date, end_date), less tax returned by refunds issued in the period, per province and tax. Use format=csv for a download.
+// @Tags Payments - Admin
+// @Produce json
+// @Produce text/csv
+// @Param start_date query string true "Start date (RFC3339 format)"
+// @Param end_date query string true "End date, exclusive (RFC3339 format)"
+// @Param format query string false "json (default) or csv"
+// @Success 200 {object} dto.RemittanceReportResponse "Remittance report"
+// @Failure 400 {object} map[string]string "Invalid dates"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /admin/payments/tax-remittance [get]
+func (h *TaxesHandler) GetTaxRemittanceReport(w http.ResponseWriter, r *http.Request) {
+	startDate, err := time.Parse(time.RFC3339, r.URL.Query().Get("start_date"))
+	if err != nil {
+		responses.RespondWithError(w, errLib.New("Invalid or missing start_date (use RFC3339)", http.StatusBadRequest))
+		return
+	}
+	endDate, err := time.Parse(time.RFC3339, r.URL.Query().Get("end_date"))
+	if err != nil {
+		responses.RespondWithError(w, errLib.New("Invalid or missing end_date (use RFC3339)", http.StatusBadRequest))
+		return
+	}
+
+	report, svcErr := h.service.GetRemittanceReport(r.Context(), startDate, endDate)
+	if svcErr != nil {
+		responses.RespondWithError(w, svcErr)
+		return
+	}
+
+	if r.URL.Query().Get("format") == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=tax_remittance_%s_%s.csv",
+			startDate.Format("2006-01-02"), endDate.Format("2006-01-02")))
+
+		w.Write([]byte("Province,Tax,Rate,Transactions,Taxable Amount,Tax Collected,Tax Refunded,Net Tax\n"))
+		for _, line := range report.Lines {
+			w.Write([]byte(fmt.Sprintf("%s,%s,%s,%d,%s,%s,%s,%s\n",
+				line.Province,
+				line.Name,
+				line.Rate.String(),
+				line.TransactionCount,
+				line.TaxableAmount.StringFixed(2),
+				line.TaxCollected.StringFixed(2),
+				line.TaxRefunded.StringFixed(2),
+				line.NetTax.StringFixed(2),
+			)))
+		}
+		w.Write([]byte(fmt.Sprintf(",Total,,,,,,%s\n", report.NetTax.StringFixed(2))))
+
+		log.Printf("[TAXES] Exported remittance report for %s to %s", startDate.Format(time.RFC3339), endDate.Format(time.RFC3339))
+		return
+	}
+
+	response := dto.RemittanceReportResponse{
+		StartDate: report.StartDate,
+		EndDate:   report.EndDate,
+		Lines:     make([]dto.RemittanceLineResponse, 0, len(report.Lines)),
+		NetTax:    report.NetTax.InexactFloat64(),
+	}
+	for _, line := range report.Lines {
+		response.Lines = append(response.Lines, dto.RemittanceLineResponse{
+			Province:         line.Province,
+			Name:             line.Name,
+			Rate:             line.Rate.InexactFloat64(),
+			TransactionCount: line.TransactionCount,
+			TaxableAmount:    line.TaxableAmount.InexactFloat64(),
+			TaxCollected:     line.TaxCollected.InexactFloat64(),
+			TaxRefunded:      line.TaxRefunded.InexactFloat64(),
+			NetTax:           line.NetTax.InexactFloat64(),
+		})
+	}
+
+	responses.RespondWithSuccess(w, response, http.StatusOK)
+}
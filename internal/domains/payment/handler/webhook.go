@@ -35,6 +35,8 @@ func NewWebhookHandlers(container *di.Container) *WebhookHandlers {
 
 // HandleStripeWebhook processes incoming Stripe webhook payment events.
 // @Description - checkout.session.completed: Logs completed checkout sessions
+// @Description - charge.refunded: Reconciles refunds made outside the refund API
+// @Description - charge.dispute.created / charge.dispute.closed: Tracks disputes and alerts staff
 // @Tags payments
 // @Accept json
 // @Produce json
@@ -115,6 +117,13 @@ func (h *WebhookHandlers) HandleStripeWebhook(w http.ResponseWriter, r *http.Req
 		webhookErr = h.Service.HandleInvoicePaymentSucceededWithSubsidy(ctx, *event)
 	case "invoice.payment_failed":
 		webhookErr = h.Service.HandleInvoicePaymentFailed(ctx, *event)
+	case "charge.refunded":
+		// Reconciles refunds made in the Stripe dashboard
+		webhookErr = h.Service.HandleChargeRefunded(ctx, *event)
+	case "charge.dispute.created":
+		webhookErr = h.Service.HandleChargeDisputeCreated(ctx, *event)
+	case "charge.dispute.closed":
+		webhookErr = h.Service.HandleChargeDisputeClosed(ctx, *event)
 	case "payment_method.attached":
 		webhookErr = h.Service.HandlePaymentMethodAttached(ctx, *event)
 	case "payment_method.detached":
@@ -1164,6 +1164,54 @@ type PaymentsCollectionAttempt struct {
 }
 
 // Tracking of payment links sent to customers for collection
+type PaymentsDispute struct {
+	ID                    uuid.UUID       `json:"id"`
+	StripeDisputeID       string          `json:"stripe_dispute_id"`
+	TransactionID         uuid.NullUUID   `json:"transaction_id"`
+	StripeChargeID        string          `json:"stripe_charge_id"`
+	StripePaymentIntentID sql.NullString  `json:"stripe_payment_intent_id"`
+	Amount                decimal.Decimal `json:"amount"`
+	Currency              string          `json:"currency"`
+	Reason                string          `json:"reason"`
+	Status                string          `json:"status"`
+	EvidenceDueBy         sql.NullTime    `json:"evidence_due_by"`
+	ClosedAt              sql.NullTime    `json:"closed_at"`
+	CreatedAt             time.Time       `json:"created_at"`
+	UpdatedAt             time.Time       `json:"updated_at"`
+}
+
+type PaymentsDunningCase struct {
+	ID                   uuid.UUID       `json:"id"`
+	CustomerID           uuid.UUID       `json:"customer_id"`
+	StripeInvoiceID      string          `json:"stripe_invoice_id"`
+	StripeSubscriptionID sql.NullString  `json:"stripe_subscription_id"`
+	AmountDue            decimal.Decimal `json:"amount_due"`
+	Currency             string          `json:"currency"`
+	FailedAt             time.Time       `json:"failed_at"`
+	Status               string          `json:"status"`
+	BookingRestricted    bool            `json:"booking_restricted"`
+	Suspended            bool            `json:"suspended"`
+	ResolvedAt           sql.NullTime    `json:"resolved_at"`
+	CreatedAt            time.Time       `json:"created_at"`
+	UpdatedAt            time.Time       `json:"updated_at"`
+}
+
+type PaymentsDunningEvent struct {
+	ID        uuid.UUID      `json:"id"`
+	CaseID    uuid.UUID      `json:"case_id"`
+	Action    string         `json:"action"`
+	DayOffset sql.NullInt32  `json:"day_offset"`
+	Detail    sql.NullString `json:"detail"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+type PaymentsDunningStep struct {
+	ID        uuid.UUID `json:"id"`
+	DayOffset int32     `json:"day_offset"`
+	Action    string    `json:"action"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type PaymentsPaymentLink struct {
 	ID                   uuid.UUID       `json:"id"`
 	CustomerID           uuid.UUID       `json:"customer_id"`
@@ -1231,6 +1279,42 @@ type PaymentsPaymentTransaction struct {
 	InvoicePdfUrl sql.NullString `json:"invoice_pdf_url"`
 }
 
+type PaymentsRefund struct {
+	ID             uuid.UUID       `json:"id"`
+	TransactionID  uuid.UUID       `json:"transaction_id"`
+	StripeRefundID sql.NullString  `json:"stripe_refund_id"`
+	Amount         decimal.Decimal `json:"amount"`
+	Reason         sql.NullString  `json:"reason"`
+	Source         string          `json:"source"`
+	AccessRevoked  bool            `json:"access_revoked"`
+	IssuedBy       uuid.NullUUID   `json:"issued_by"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+type PaymentsTaxRate struct {
+	ID                 uuid.UUID       `json:"id"`
+	Province           string          `json:"province"`
+	Name               string          `json:"name"`
+	Rate               decimal.Decimal `json:"rate"`
+	RegistrationNumber sql.NullString  `json:"registration_number"`
+	StripeTaxRateID    string          `json:"stripe_tax_rate_id"`
+	IsActive           bool            `json:"is_active"`
+	CreatedAt          time.Time       `json:"created_at"`
+	UpdatedAt          time.Time       `json:"updated_at"`
+}
+
+type PaymentsTransactionTax struct {
+	ID            uuid.UUID       `json:"id"`
+	TransactionID uuid.UUID       `json:"transaction_id"`
+	TaxRateID     uuid.NullUUID   `json:"tax_rate_id"`
+	Name          string          `json:"name"`
+	Province      string          `json:"province"`
+	Rate          decimal.Decimal `json:"rate"`
+	TaxableAmount decimal.Decimal `json:"taxable_amount"`
+	Amount        decimal.Decimal `json:"amount"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
 type PlaygroundSession struct {
 	ID         uuid.UUID `json:"id"`
 	SystemID   uuid.UUID `json:"system_id"`
@@ -1556,8 +1640,9 @@ type UsersUser struct {
 	// Timestamp when the user last changed their email address.
 	EmailChangedAt sql.NullTime `json:"email_changed_at"`
 	// Timestamp when account was archived. Archived accounts are permanently deleted after 30 days.
-	ArchivedAt  sql.NullTime   `json:"archived_at"`
-	AccountType sql.NullString `json:"account_type"`
+	ArchivedAt         sql.NullTime   `json:"archived_at"`
+	AccountType        sql.NullString `json:"account_type"`
+	StoredValueBalance int32          `json:"stored_value_balance"`
 }
 
 // Tracks weekly credit consumption per customer for membership limit enforcement
@@ -0,0 +1,138 @@
+-- name: ListDunningSteps :many
+SELECT * FROM payments.dunning_steps
+ORDER BY day_offset, action;
+
+-- name: DeleteDunningSteps :exec
+DELETE FROM payments.dunning_steps;
+
+-- name: CreateDunningStep :one
+INSERT INTO payments.dunning_steps (day_offset, action)
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: CreateDunningCase :one
+-- Returns no rows when the invoice already has a case.
+INSERT INTO payments.dunning_cases (customer_id, stripe_invoice_id, stripe_subscription_id, amount_due, currency, failed_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (stripe_invoice_id) DO NOTHING
+RETURNING *;
+
+-- name: ListOpenDunningCases :many
+SELECT * FROM payments.dunning_cases
+WHERE status = 'open'
+ORDER BY failed_at
+LIMIT $1;
+
+-- name: ListCustomerDunningCases :many
+SELECT * FROM payments.dunning_cases
+WHERE customer_id = $1
+ORDER BY failed_at DESC;
+
+-- name: ListDunningEvents :many
+SELECT * FROM payments.dunning_events
+WHERE case_id = $1
+ORDER BY created_at, id;
+
+-- name: ListCustomerDunningEvents :many
+SELECT e.* FROM payments.dunning_events e
+JOIN payments.dunning_cases c ON c.id = e.case_id
+WHERE c.customer_id = $1
+ORDER BY e.created_at, e.id;
+
+-- name: CreateDunningEvent :execrows
+-- Returns 0 when the step was already recorded for the case.
+INSERT INTO payments.dunning_events (case_id, action, day_offset, detail)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (case_id, action, day_offset) DO NOTHING;
+
+-- name: ReleaseDunningEvent :exec
+-- Forgets a step that failed so the next run retries it.
+DELETE FROM payments.dunning_events
+WHERE case_id = $1
+  AND action = $2
+  AND day_offset = $3;
+
+-- name: RestrictDunningCaseBooking :exec
+UPDATE payments.dunning_cases
+SET booking_restricted = true,
+    updated_at         = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: MarkDunningCaseSuspended :exec
+UPDATE payments.dunning_cases
+SET suspended  = true,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: MoveDunningCaseToCollections :exec
+UPDATE payments.dunning_cases
+SET status     = 'collections',
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND status = 'open';
+
+-- name: ResolveDunningCases :many
+-- A paid invoice resolves its own case and, since Stripe only retries the latest
+-- invoice, any earlier unresolved case of the same subscription.
+UPDATE payments.dunning_cases
+SET status             = 'resolved',
+    booking_restricted = false,
+    resolved_at        = CURRENT_TIMESTAMP,
+    updated_at         = CURRENT_TIMESTAMP
+WHERE status <> 'resolved'
+  AND (stripe_invoice_id = sqlc.arg('stripe_invoice_id')
+    OR (sqlc.narg('stripe_subscription_id')::text IS NOT NULL
+        AND stripe_subscription_id = sqlc.narg('stripe_subscription_id')))
+RETURNING *;
+
+-- name: HasUnresolvedDunningSuspension :one
+SELECT EXISTS (
+    SELECT 1 FROM payments.dunning_cases
+    WHERE customer_id = $1
+      AND status <> 'resolved'
+      AND suspended
+) AS suspended;
+
+-- name: HasDunningBookingRestriction :one
+SELECT EXISTS (
+    SELECT 1 FROM payments.dunning_cases
+    WHERE customer_id = $1
+      AND status <> 'resolved'
+      AND booking_restricted
+) AS restricted;
+
+-- name: GetDunningContact :one
+-- Who to remind about a case, and the membership the failed invoice was for.
+SELECT u.first_name,
+       u.email,
+       u.suspended_at,
+       COALESCE((SELECT mp.name
+                 FROM users.customer_membership_plans cmp
+                 JOIN membership.membership_plans mp ON mp.id = cmp.membership_plan_id
+                 WHERE cmp.customer_id = u.id
+                   AND cmp.stripe_subscription_id = sqlc.narg('stripe_subscription_id')
+                 ORDER BY cmp.created_at DESC
+                 LIMIT 1), '')::text AS membership_plan
+FROM users.users u
+WHERE u.id = sqlc.arg('customer_id');
+
+-- name: ListDunningCollectionsQueue :many
+-- Cases handed to collections, oldest first, with who to contact.
+SELECT c.id,
+       c.customer_id,
+       c.stripe_invoice_id,
+       c.stripe_subscription_id,
+       c.amount_due,
+       c.currency,
+       c.failed_at,
+       c.booking_restricted,
+       c.suspended,
+       c.updated_at AS handed_off_at,
+       u.first_name,
+       u.last_name,
+       u.email,
+       u.phone
+FROM payments.dunning_cases c
+JOIN users.users u ON u.id = c.customer_id
+WHERE c.status = 'collections'
+ORDER BY c.failed_at;
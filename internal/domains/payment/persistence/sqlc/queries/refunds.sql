@@ -0,0 +1,41 @@
+-- name: GetPaymentTransactionForUpdate :one
+SELECT * FROM payments.payment_transactions
+WHERE id = $1
+FOR UPDATE;
+
+-- name: GetPaymentTransactionByStripePaymentIntent :one
+SELECT * FROM payments.payment_transactions
+WHERE stripe_payment_intent_id = $1
+LIMIT 1;
+
+-- name: CreateRefund :one
+INSERT INTO payments.refunds (transaction_id, stripe_refund_id, amount, reason, source, issued_by)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: MarkRefundAccessRevoked :exec
+UPDATE payments.refunds
+SET access_revoked = true
+WHERE id = $1;
+
+-- name: ListTransactionRefunds :many
+SELECT * FROM payments.refunds
+WHERE transaction_id = $1
+ORDER BY created_at;
+
+-- name: UpsertDispute :one
+INSERT INTO payments.disputes (stripe_dispute_id, transaction_id, stripe_charge_id, stripe_payment_intent_id,
+                               amount, currency, reason, status, evidence_due_by, closed_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+ON CONFLICT (stripe_dispute_id) DO UPDATE
+    SET transaction_id  = COALESCE(payments.disputes.transaction_id, EXCLUDED.transaction_id),
+        status          = EXCLUDED.status,
+        evidence_due_by = EXCLUDED.evidence_due_by,
+        closed_at       = COALESCE(payments.disputes.closed_at, EXCLUDED.closed_at),
+        updated_at      = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: ListDisputes :many
+SELECT * FROM payments.disputes
+WHERE (NOT sqlc.arg('open_only')::boolean OR closed_at IS NULL)
+ORDER BY evidence_due_by NULLS LAST, created_at DESC;
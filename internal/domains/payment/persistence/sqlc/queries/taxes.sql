@@ -0,0 +1,101 @@
+-- name: CreateTaxRate :one
+INSERT INTO payments.tax_rates (province, name, rate, registration_number, stripe_tax_rate_id)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetTaxRate :one
+SELECT * FROM payments.tax_rates
+WHERE id = $1;
+
+-- name: GetTaxRateByStripeID :one
+SELECT * FROM payments.tax_rates
+WHERE stripe_tax_rate_id = $1;
+
+-- name: DeactivateTaxRate :execrows
+UPDATE payments.tax_rates
+SET is_active  = false,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND is_active;
+
+-- name: ListTaxRates :many
+SELECT * FROM payments.tax_rates
+WHERE (sqlc.arg('include_inactive')::boolean OR is_active)
+ORDER BY province, name, created_at DESC;
+
+-- name: ListActiveTaxRatesForLocation :many
+-- Rates charged at a facility. Purchases that are not tied to a facility, and unknown
+-- facilities, are taxed in the default province.
+SELECT * FROM payments.tax_rates
+WHERE is_active
+  AND province = COALESCE(
+        (SELECT l.province FROM location.locations l WHERE l.id = sqlc.narg('location_id')),
+        sqlc.arg('default_province')::text)
+ORDER BY name;
+
+-- name: ListRegistrationNumbers :many
+SELECT DISTINCT name, registration_number::text AS registration_number
+FROM payments.tax_rates
+WHERE registration_number IS NOT NULL
+  AND is_active
+ORDER BY name;
+
+-- name: CreateTransactionTax :exec
+INSERT INTO payments.transaction_taxes (transaction_id, tax_rate_id, name, province, rate, taxable_amount, amount)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (transaction_id, name, province) DO NOTHING;
+
+-- name: ListTransactionTaxes :many
+SELECT * FROM payments.transaction_taxes
+WHERE transaction_id = $1
+ORDER BY name;
+
+-- name: ListCustomerStatementTransactions :many
+-- Payments a customer made in [start, end). Failed and pending payments are left out.
+SELECT * FROM payments.payment_transactions
+WHERE customer_id = $1
+  AND transaction_date >= sqlc.arg('start_date')
+  AND transaction_date < sqlc.arg('end_date')
+  AND payment_status IN ('completed', 'refunded', 'partially_refunded')
+ORDER BY transaction_date;
+
+-- name: ListCustomerStatementTaxes :many
+SELECT tt.* FROM payments.transaction_taxes tt
+JOIN payments.payment_transactions t ON t.id = tt.transaction_id
+WHERE t.customer_id = $1
+  AND t.transaction_date >= sqlc.arg('start_date')
+  AND t.transaction_date < sqlc.arg('end_date')
+  AND t.payment_status IN ('completed', 'refunded', 'partially_refunded')
+ORDER BY tt.name;
+
+-- name: GetTaxCollected :many
+-- Tax charged on sales in [start, end), by tax.
+SELECT tt.province,
+       tt.name,
+       tt.rate,
+       COUNT(DISTINCT t.id)                  AS transaction_count,
+       COALESCE(SUM(tt.taxable_amount), 0)::numeric AS taxable_amount,
+       COALESCE(SUM(tt.amount), 0)::numeric         AS tax_amount
+FROM payments.transaction_taxes tt
+JOIN payments.payment_transactions t ON t.id = tt.transaction_id
+WHERE t.transaction_date >= sqlc.arg('start_date')
+  AND t.transaction_date < sqlc.arg('end_date')
+  AND t.payment_status IN ('completed', 'refunded', 'partially_refunded')
+GROUP BY tt.province, tt.name, tt.rate
+ORDER BY tt.province, tt.name, tt.rate;
+
+-- name: GetTaxRefunded :many
+-- Tax given back by refunds issued in [start, end), by tax. A partial refund returns
+-- each tax in proportion to the share of the payment refunded.
+SELECT tt.province,
+       tt.name,
+       tt.rate,
+       COALESCE(SUM(ROUND(tt.amount * r.amount / t.customer_paid, 2)), 0)::numeric AS tax_amount
+FROM payments.refunds r
+JOIN payments.payment_transactions t ON t.id = r.transaction_id
+JOIN payments.transaction_taxes tt ON tt.transaction_id = t.id
+WHERE r.created_at >= sqlc.arg('start_date')
+  AND r.created_at < sqlc.arg('end_date')
+  AND t.customer_paid > 0
+GROUP BY tt.province, tt.name, tt.rate
+ORDER BY tt.province, tt.name, tt.rate;
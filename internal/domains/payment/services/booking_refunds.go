@@ -0,0 +1,124 @@
+package payment
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+
+	db "api/internal/domains/payment/persistence/sqlc/generated"
+	stripeService "api/internal/domains/payment/services/stripe"
+	errLib "api/internal/libs/errors"
+	txUtils "api/utils/db"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stripe/stripe-go/v81"
+)
+
+// refundSourceSystem marks refunds the server issues itself rather than staff
+const refundSourceSystem = "system"
+
+// RefundUnconfirmedBooking refunds a checkout whose booking could not be confirmed,
+// because its hold lapsed and the time was taken or the hold was canceled, records the
+// refund against the tracked payment and marks the booking refunded. track, if set,
+// tracks the payment when it is not tracked yet. The payment stays locked while Stripe
+// is called, so the charge.refunded webhook finds the refund already recorded.
+//
+// A failed refund is only logged: the booking stays refund_due for staff to refund by
+// hand, and the webhook still succeeds so Stripe stops retrying.
+func (s *RefundsService) RefundUnconfirmedBooking(ctx context.Context, checkout *stripe.CheckoutSession, bookingType string, bookingID uuid.UUID, track func(), markRefunded func(context.Context, uuid.UUID) *errLib.CommonError) {
+	if checkout.PaymentIntent == nil || checkout.PaymentIntent.ID == "" || checkout.AmountTotal <= 0 {
+		log.Printf("[BOOKING REFUND] %s %s needs a manual refund: checkout %s has no payment intent", bookingType, bookingID, checkout.ID)
+		return
+	}
+
+	const reason = "Booking could not be confirmed after payment"
+	var stripeRefundID string
+
+	err := txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		q := s.queries.WithTx(tx)
+
+		payment, err := s.lockCheckoutTransaction(ctx, q, checkout.ID)
+		if err != nil {
+			return err
+		}
+		if payment == nil && track != nil {
+			track()
+			if payment, err = s.lockCheckoutTransaction(ctx, q, checkout.ID); err != nil {
+				return err
+			}
+		}
+
+		cents := checkout.AmountTotal
+		if payment != nil {
+			remaining, _, err := refundAmountCents(payment.CustomerPaid, payment.RefundedAmount, nil)
+			if err != nil {
+				// Refunded by an earlier attempt whose booking was not marked refunded
+				return nil
+			}
+			cents = remaining
+		}
+
+		stripeRefund, err := stripeService.CreateRefund(ctx, checkout.PaymentIntent.ID, cents, reason, map[string]string{
+			"bookingType": bookingType,
+			"bookingID":   bookingID.String(),
+		}, bookingType+":"+bookingID.String())
+		if err != nil {
+			return err
+		}
+		stripeRefundID = stripeRefund.ID
+
+		if payment == nil {
+			log.Printf("[BOOKING REFUND] %s %s has no tracked payment; refund %s is not recorded", bookingType, bookingID, stripeRefund.ID)
+			return nil
+		}
+
+		amount := decimal.New(cents, -2)
+		if _, dbErr := q.CreateRefund(ctx, db.CreateRefundParams{
+			TransactionID:  payment.ID,
+			StripeRefundID: sql.NullString{String: stripeRefund.ID, Valid: true},
+			Amount:         amount,
+			Reason:         sql.NullString{String: reason, Valid: true},
+			Source:         refundSourceSystem,
+		}); dbErr != nil {
+			log.Printf("[BOOKING REFUND] Stripe refund %s issued but not recorded for transaction %s: %v", stripeRefund.ID, payment.ID, dbErr)
+			return errLib.New("Failed to record refund", http.StatusInternalServerError)
+		}
+
+		total, _ := payment.RefundedAmount.Add(amount).Float64()
+		if trackErr := s.paymentTracking.WithTx(tx).RecordRefund(ctx, payment.ID, total, reason); trackErr != nil {
+			return errLib.New("Failed to record refund", http.StatusInternalServerError)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("[BOOKING REFUND] %s %s needs a manual refund: refunding %s failed: %s", bookingType, bookingID, checkout.PaymentIntent.ID, err.Message)
+		return
+	}
+
+	if stripeRefundID != "" {
+		log.Printf("[BOOKING REFUND] Refunded %s %s (refund %s)", bookingType, bookingID, stripeRefundID)
+	}
+	if err = markRefunded(ctx, bookingID); err != nil {
+		log.Printf("[BOOKING REFUND] Failed to mark %s %s as refunded: %s", bookingType, bookingID, err.Message)
+	}
+}
+
+// lockCheckoutTransaction locks the tracked payment of a checkout, or returns nil when
+// it is not tracked.
+func (s *RefundsService) lockCheckoutTransaction(ctx context.Context, q *db.Queries, checkoutSessionID string) (*db.PaymentsPaymentTransaction, *errLib.CommonError) {
+	tracked, dbErr := q.GetPaymentTransactionByStripeCheckoutSession(ctx, sql.NullString{String: checkoutSessionID, Valid: true})
+	if errors.Is(dbErr, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if dbErr == nil {
+		tracked, dbErr = q.GetPaymentTransactionForUpdate(ctx, tracked.ID)
+	}
+	if dbErr != nil {
+		log.Printf("[BOOKING REFUND] Failed to lock transaction of checkout %s: %v", checkoutSessionID, dbErr)
+		return nil, errLib.New("Failed to load payment transaction", http.StatusInternalServerError)
+	}
+	return &tracked, nil
+}
@@ -10,10 +10,14 @@ import (
 	"time"
 
 	"api/internal/di"
+	courtRentalService "api/internal/domains/court_rental/service"
+	courtRentalValues "api/internal/domains/court_rental/values"
 	enrollment "api/internal/domains/enrollment/service"
 	dbEnrollment "api/internal/domains/enrollment/persistence/sqlc/generated"
 	eventService "api/internal/domains/event/service"
 	membership "api/internal/domains/membership/persistence/repositories"
+	playgroundService "api/internal/domains/playground/services"
+	playgroundValues "api/internal/domains/playground/values"
 	repository "api/internal/domains/payment/persistence/repositories"
 	"api/internal/domains/payment/services/stripe"
 	subsidyService "api/internal/domains/subsidy/service"
@@ -21,7 +25,11 @@ import (
 	errLib "api/internal/libs/errors"
 	contextUtils "api/utils/context"
 	discountService "api/internal/domains/discount/service"
+	discountValues "api/internal/domains/discount/values"
+	giftCardService "api/internal/domains/gift_card/service"
+	giftCardValues "api/internal/domains/gift_card/values"
 	email "api/utils/email"
+	"api/utils/timezone"
 	"github.com/google/uuid"
 )
 
@@ -38,6 +46,11 @@ type Service struct {
 	EnrollmentService   *enrollment.CustomerEnrollmentService
 	EventService        *eventService.Service
 	CreditService       *userServices.CustomerCreditService
+	CourtRentalService  *courtRentalService.Service
+	PlaygroundService   *playgroundService.Service
+	GiftCardService     *giftCardService.Service
+	TaxService          *TaxService
+	Dunning             *DunningService
 	DB                  *sql.DB
 	activeCheckouts     sync.Map // key: "customerID:type:itemID" → *checkoutLock
 }
@@ -51,6 +64,11 @@ func NewPurchaseService(container *di.Container) *Service {
 		EnrollmentService:   enrollment.NewCustomerEnrollmentService(container),
 		EventService:        eventService.NewEventService(container),
 		CreditService:       userServices.NewCustomerCreditService(container),
+		CourtRentalService:  courtRentalService.NewService(container),
+		PlaygroundService:   playgroundService.NewService(container),
+		GiftCardService:     giftCardService.NewService(container),
+		TaxService:          NewTaxService(container),
+		Dunning:             NewDunningService(container),
 		DB:                  container.DB,
 	}
 
@@ -108,26 +126,7 @@ func (s *Service) getExistingStripeCustomerID(ctx context.Context, userID uuid.U
 	return &stripeCustomerID.String
 }
 
-// queueFailedRefund inserts a failed refund into the recovery queue for manual resolution
-func (s *Service) queueFailedRefund(ctx context.Context, customerID, eventID uuid.UUID, creditAmount int32, refundErr *errLib.CommonError) {
-	log.Printf("[REFUND_QUEUE] Queueing failed refund: customer=%s, event=%s, amount=%d, error=%s",
-		customerID, eventID, creditAmount, refundErr.Error())
-
-	query := `INSERT INTO payment.failed_refunds (customer_id, event_id, credit_amount, error_message, status)
-		VALUES ($1, $2, $3, $4, 'pending')`
-
-	_, dbErr := s.DB.ExecContext(ctx, query, customerID, eventID, creditAmount, refundErr.Error())
-	if dbErr != nil {
-		// If we can't even queue the refund, log it prominently for manual intervention
-		log.Printf("[REFUND_QUEUE] CRITICAL: Failed to queue refund for recovery: customer=%s, event=%s, amount=%d, queue_error=%v",
-			customerID, eventID, creditAmount, dbErr)
-	} else {
-		log.Printf("[REFUND_QUEUE] Successfully queued failed refund for recovery: customer=%s, event=%s, amount=%d",
-			customerID, eventID, creditAmount)
-	}
-}
-
-func (s *Service) CheckoutMembershipPlan(ctx context.Context, membershipPlanID uuid.UUID, discountCode *string, successURL string, cancelURL string) (string, *errLib.CommonError) {
+func (s *Service) CheckoutMembershipPlan(ctx context.Context, membershipPlanID uuid.UUID, discountCode *string, useBalance bool, successURL string, cancelURL string) (string, *errLib.CommonError) {
 	// Get customer ID from context
 	customerID, ctxErr := contextUtils.GetUserID(ctx)
 	if ctxErr != nil {
@@ -155,22 +154,6 @@ func (s *Service) CheckoutMembershipPlan(ctx context.Context, membershipPlanID u
 		return "", err
 	}
 
-	// Handle discount code if provided — validate only, don't increment usage until checkout succeeds
-	var stripeCouponID *string
-	if discountCode != nil {
-		validated, err := s.DiscountService.ValidateDiscount(ctx, *discountCode, &membershipPlanID)
-		if err != nil {
-			return "", err
-		}
-
-		// Validate that discount applies to subscriptions
-		if validated.AppliesTo != "subscription" && validated.AppliesTo != "both" {
-			return "", errLib.New("This discount code does not apply to subscriptions", http.StatusBadRequest)
-		}
-
-		stripeCouponID = validated.StripeCouponID
-	}
-
 	// Check if customer has active subsidy
 	subsidy, subsidyErr := s.SubsidyService.GetActiveSubsidy(ctx, customerID)
 	if subsidyErr != nil {
@@ -184,13 +167,7 @@ func (s *Service) CheckoutMembershipPlan(ctx context.Context, membershipPlanID u
 		"membershipPlanID": membershipPlanID.String(),
 	}
 
-	// Store discount info in metadata so webhook can increment usage after successful payment
-	if discountCode != nil {
-		validated, _ := s.DiscountService.ValidateDiscount(ctx, *discountCode, &membershipPlanID)
-		metadata["discount_code"] = *discountCode
-		metadata["discount_id"] = validated.ID.String()
-	}
-
+	var stripeCouponID *string
 	if subsidy != nil && subsidy.RemainingBalance > 0 {
 		// Reject if customer provided both a discount code and has an active subsidy
 		if discountCode != nil {
@@ -211,6 +188,15 @@ func (s *Service) CheckoutMembershipPlan(ctx context.Context, membershipPlanID u
 			stripeCouponID = &subsidyCouponID
 			log.Printf("Created subsidy coupon: %s for $%.2f", subsidyCouponID, subsidy.RemainingBalance)
 		}
+	} else {
+		// Automatic discounts, or the discount code when it saves more. The code is only validated;
+		// the webhook increments its usage from the metadata after successful payment.
+		item := discountValues.PriceItem{Type: discountValues.ItemMembership, ID: membershipPlanID}
+		couponID, err := s.checkoutCoupon(ctx, item, requirements.StripePriceID, discountCode, metadata)
+		if err != nil {
+			return "", err
+		}
+		stripeCouponID = couponID
 	}
 
 	// Get existing Stripe customer ID or recover if deleted (industry standard: one user = one Stripe customer)
@@ -226,16 +212,38 @@ func (s *Service) CheckoutMembershipPlan(ctx context.Context, membershipPlanID u
 		}
 	}
 
+	// Memberships are not tied to a facility, so they are taxed in the default province
+	taxRateIDs, taxErr := s.TaxService.TaxRateIDsForLocation(ctx, uuid.NullUUID{})
+	if taxErr != nil {
+		return "", taxErr
+	}
+
 	// Check if membership has any joining fee
 	if requirements.StripeJoiningFeeID != "" {
+		// Gift card balance only pays the joining fee; the plan renews on the card
+		var hold giftCardValues.Hold
+		if useBalance {
+			hold, err = s.holdJoiningFeeBalance(ctx, customerID, requirements.StripeJoiningFeeID)
+			if err != nil {
+				return "", err
+			}
+			for k, v := range holdMetadata(hold) {
+				metadata[k] = v
+			}
+		}
+
 		// Use recurring joining fee (annual/monthly) - existing function handles this
-		return stripe.CreateSubscriptionWithMetadata(ctx, requirements.StripePriceID, requirements.StripeJoiningFeeID, stripeCouponID, metadata, successURL, cancelURL, existingCustomerID)
+		url, checkoutErr := stripe.CreateSubscriptionWithMetadata(ctx, requirements.StripePriceID, requirements.StripeJoiningFeeID, int64(hold.AmountCents), stripeCouponID, metadata, successURL, cancelURL, existingCustomerID, taxRateIDs)
+		if checkoutErr != nil {
+			s.releaseBalanceHold(ctx, hold)
+		}
+		return url, checkoutErr
 	} else if requirements.JoiningFee > 0 {
 		// Use one-time setup fee
-		return stripe.CreateSubscriptionWithSetupFeeAndMetadata(ctx, requirements.StripePriceID, requirements.JoiningFee, metadata, successURL, cancelURL, existingCustomerID)
+		return stripe.CreateSubscriptionWithSetupFeeAndMetadata(ctx, requirements.StripePriceID, requirements.JoiningFee, metadata, successURL, cancelURL, existingCustomerID, taxRateIDs)
 	} else {
 		// No joining fee - just regular subscription
-		return stripe.CreateSubscriptionWithMetadata(ctx, requirements.StripePriceID, "", stripeCouponID, metadata, successURL, cancelURL, existingCustomerID)
+		return stripe.CreateSubscriptionWithMetadata(ctx, requirements.StripePriceID, "", 0, stripeCouponID, metadata, successURL, cancelURL, existingCustomerID, taxRateIDs)
 	}
 }
 
@@ -278,12 +286,17 @@ func (s *Service) AdminSendMembershipCheckoutLink(ctx context.Context, customerI
 		}
 	}
 
+	taxRateIDs, err := s.TaxService.TaxRateIDsForLocation(ctx, uuid.NullUUID{})
+	if err != nil {
+		return "", err
+	}
+
 	// Create checkout session using the customer-explicit variant
 	var checkoutURL string
 	if requirements.StripeJoiningFeeID != "" {
-		checkoutURL, err = stripe.CreateSubscriptionCheckoutForCustomer(customerID, requirements.StripePriceID, requirements.StripeJoiningFeeID, metadata, successURL, cancelURL, existingCustomerID)
+		checkoutURL, err = stripe.CreateSubscriptionCheckoutForCustomer(customerID, requirements.StripePriceID, requirements.StripeJoiningFeeID, metadata, successURL, cancelURL, existingCustomerID, taxRateIDs)
 	} else {
-		checkoutURL, err = stripe.CreateSubscriptionCheckoutForCustomer(customerID, requirements.StripePriceID, "", metadata, successURL, cancelURL, existingCustomerID)
+		checkoutURL, err = stripe.CreateSubscriptionCheckoutForCustomer(customerID, requirements.StripePriceID, "", metadata, successURL, cancelURL, existingCustomerID, taxRateIDs)
 	}
 	if err != nil {
 		return "", err
@@ -319,6 +332,10 @@ func (s *Service) CheckoutProgram(ctx context.Context, programID uuid.UUID, disc
 		return "", ctxErr
 	}
 
+	if err := s.Dunning.CheckBookingAllowed(ctx, customerID); err != nil {
+		return "", err
+	}
+
 	// Prevent double-click duplicate checkouts
 	if err := s.tryAcquireCheckoutLock(customerID, "program", programID); err != nil {
 		return "", err
@@ -334,20 +351,16 @@ func (s *Service) CheckoutProgram(ctx context.Context, programID uuid.UUID, disc
 		return "", errLib.New("program is not pay-per-event", http.StatusBadRequest)
 	}
 
-	// Handle discount code if provided — validate only, don't increment usage until checkout succeeds
-	var stripeCouponID *string
-	if discountCode != nil {
-		validated, err := s.DiscountService.ValidateDiscount(ctx, *discountCode, nil)
-		if err != nil {
-			return "", err
-		}
-
-		// Validate that discount applies to one-time payments
-		if validated.AppliesTo != "one_time" && validated.AppliesTo != "both" {
-			return "", errLib.New("This discount code does not apply to one-time payments", http.StatusBadRequest)
-		}
+	// Automatic discounts, or the discount code when it saves more
+	stripeCouponID, err := s.checkoutCoupon(ctx, discountValues.PriceItem{Type: discountValues.ItemProgram, ID: programID}, priceID, discountCode, nil)
+	if err != nil {
+		return "", err
+	}
 
-		stripeCouponID = validated.StripeCouponID
+	// Programs are not tied to a facility, so they are taxed in the default province
+	taxRateIDs, err := s.TaxService.TaxRateIDsForLocation(ctx, uuid.NullUUID{})
+	if err != nil {
+		return "", err
 	}
 
 	// reserve seat so that the database can assume that the customer is enrolled
@@ -367,15 +380,19 @@ func (s *Service) CheckoutProgram(ctx context.Context, programID uuid.UUID, disc
 	existingCustomerID := s.getExistingStripeCustomerID(ctx, customerID)
 
 	programIDStr := programID.String()
-	return stripe.CreateOneTimePayment(ctx, priceID, 1, &programIDStr, stripeCouponID, successURL, cancelURL, existingCustomerID)
+	return stripe.CreateOneTimePayment(ctx, priceID, 1, &programIDStr, stripeCouponID, successURL, cancelURL, existingCustomerID, taxRateIDs)
 }
 
-func (s *Service) CheckoutEvent(ctx context.Context, eventID uuid.UUID, discountCode *string, successURL string, cancelURL string) (string, *errLib.CommonError) {
+func (s *Service) CheckoutEvent(ctx context.Context, eventID uuid.UUID, discountCode *string, useBalance bool, successURL string, cancelURL string) (string, *errLib.CommonError) {
 	customerID, ctxErr := contextUtils.GetUserID(ctx)
 	if ctxErr != nil {
 		return "", ctxErr
 	}
 
+	if err := s.Dunning.CheckBookingAllowed(ctx, customerID); err != nil {
+		return "", err
+	}
+
 	// Prevent double-click duplicate checkouts
 	if err := s.tryAcquireCheckoutLock(customerID, "event", eventID); err != nil {
 		return "", err
@@ -396,20 +413,21 @@ func (s *Service) CheckoutEvent(ctx context.Context, eventID uuid.UUID, discount
 		return "", errLib.New("event is pay-per-program", http.StatusBadRequest)
 	}
 
-	// Handle discount code if provided — validate only, don't increment usage until checkout succeeds
-	var stripeCouponID *string
-	if discountCode != nil {
-		validated, err := s.DiscountService.ValidateDiscount(ctx, *discountCode, nil)
-		if err != nil {
-			return "", err
-		}
-
-		// Validate that discount applies to one-time payments
-		if validated.AppliesTo != "one_time" && validated.AppliesTo != "both" {
-			return "", errLib.New("This discount code does not apply to one-time payments", http.StatusBadRequest)
+	// Automatic discounts, or the discount code when it saves more, plus any gift card balance
+	stripeCouponID, hold, err := s.balanceCheckoutCoupon(ctx, customerID, discountValues.PriceItem{Type: discountValues.ItemEvent, ID: eventID}, priceID, discountCode, nil, useBalance, "Event registration")
+	if err != nil {
+		return "", err
+	}
+	checkoutStarted := false
+	defer func() {
+		if !checkoutStarted {
+			s.releaseBalanceHold(ctx, hold)
 		}
+	}()
 
-		stripeCouponID = validated.StripeCouponID
+	taxRateIDs, err := s.eventTaxRateIDs(ctx, eventID)
+	if err != nil {
+		return "", err
 	}
 
 	// reserve seat so that the database can assume that the customer is enrolled
@@ -427,7 +445,9 @@ func (s *Service) CheckoutEvent(ctx context.Context, eventID uuid.UUID, discount
 	existingCustomerID := s.getExistingStripeCustomerID(ctx, customerID)
 
 	eventIDStr := eventID.String()
-	return stripe.CreateOneTimePayment(ctx, priceID, 1, &eventIDStr, stripeCouponID, successURL, cancelURL, existingCustomerID)
+	url, err := stripe.CreateOneTimePaymentWithMetadata(ctx, priceID, 1, &eventIDStr, stripeCouponID, holdMetadata(hold), hold.ExpiresAt, successURL, cancelURL, existingCustomerID, taxRateIDs)
+	checkoutStarted = err == nil
+	return url, err
 }
 
 // CheckEventEnrollmentOptions returns available enrollment options for a customer and event
@@ -512,6 +532,10 @@ func (s *Service) CheckoutEventWithCredits(ctx context.Context, eventID uuid.UUI
 		return ctxErr
 	}
 
+	if err := s.Dunning.CheckBookingAllowed(ctx, customerID); err != nil {
+		return err
+	}
+
 	// Prevent double-click duplicate checkouts
 	if err := s.tryAcquireCheckoutLock(customerID, "event-credits", eventID); err != nil {
 		return err
@@ -543,37 +567,32 @@ func (s *Service) CheckoutEventWithCredits(ctx context.Context, eventID uuid.UUI
 		return errLib.New("Event is free for your membership level", http.StatusBadRequest)
 	}
 
-	// CRITICAL FIX: Process credit payment FIRST (includes all validations like weekly limit)
+	// Hold the credits FIRST (includes all validations like weekly limit)
 	// This ensures we don't reserve a seat if payment will fail
-	if err := s.CreditService.EnrollWithCredits(ctx, eventID, customerID); err != nil {
-		log.Printf("Credit enrollment failed for customer %s, event %s: %v", customerID, eventID, err)
+	holdID, err := s.CreditService.HoldCreditsForEvent(ctx, eventID, customerID)
+	if err != nil {
+		log.Printf("Credit hold failed for customer %s, event %s: %v", customerID, eventID, err)
 		return err
 	}
 
-	// Reserve seat AFTER credits are successfully deducted
+	// Reserve seat while the credits are held
 	if err := s.EnrollmentService.ReserveSeatInEvent(ctx, eventID, customerID); err != nil {
-		// Credits were deducted but seat reservation failed - need to refund
-		log.Printf("Seat reservation failed after credit deduction, attempting refund for customer %s, event %s", customerID, eventID)
-		var refundErr *errLib.CommonError
-		for attempt := 1; attempt <= 3; attempt++ {
-			refundErr = s.CreditService.RefundCreditsForCancellation(ctx, eventID, customerID)
-			if refundErr == nil {
-				log.Printf("Credit refund succeeded on attempt %d for customer %s, event %s", attempt, customerID, eventID)
-				break
-			}
-			log.Printf("Credit refund attempt %d/3 failed for customer %s, event %s: %v", attempt, customerID, eventID, refundErr)
-			if attempt < 3 {
-				time.Sleep(time.Duration(attempt) * 500 * time.Millisecond)
-			}
-		}
-		if refundErr != nil {
-			log.Printf("CRITICAL: All 3 refund attempts failed for customer %s, event %s: %v", customerID, eventID, refundErr)
-			// Queue the failed refund for manual recovery
-			s.queueFailedRefund(ctx, customerID, eventID, *options.CreditCost, refundErr)
+		// No seat, so give the held credits back. A hold that can't be released now
+		// expires and is released by the credit reconciliation job.
+		if releaseErr := s.CreditService.ReleaseEventHold(ctx, holdID); releaseErr != nil {
+			log.Printf("Failed to release credit hold %s for customer %s, event %s; it will be released when it expires: %v",
+				holdID, customerID, eventID, releaseErr)
 		}
 		return err
 	}
 
+	// The seat is reserved, so spend the held credits. A hold that can't be captured now
+	// expires and is captured by the credit reconciliation job, since the seat is taken.
+	if err := s.CreditService.CaptureEventHold(ctx, holdID); err != nil {
+		log.Printf("Failed to capture credit hold %s for customer %s, event %s; it will be captured when it expires: %v",
+			holdID, customerID, eventID, err)
+	}
+
 	// Update payment status to 'paid' since credit payment was successful
 	if err := s.EnrollmentService.UpdateReservationStatusInEvent(ctx, eventID, customerID, dbEnrollment.PaymentStatusPaid); err != nil {
 		log.Printf("Failed to update reservation status after credit payment: %v", err)
@@ -584,12 +603,16 @@ func (s *Service) CheckoutEventWithCredits(ctx context.Context, eventID uuid.UUI
 }
 
 // Enhanced CheckoutEvent with membership validation
-func (s *Service) CheckoutEventEnhanced(ctx context.Context, eventID uuid.UUID, discountCode *string, successURL string, cancelURL string) (string, *errLib.CommonError) {
+func (s *Service) CheckoutEventEnhanced(ctx context.Context, eventID uuid.UUID, discountCode *string, useBalance bool, successURL string, cancelURL string) (string, *errLib.CommonError) {
 	customerID, ctxErr := contextUtils.GetUserID(ctx)
 	if ctxErr != nil {
 		return "", ctxErr
 	}
 
+	if err := s.Dunning.CheckBookingAllowed(ctx, customerID); err != nil {
+		return "", err
+	}
+
 	// Prevent double-click duplicate checkouts
 	if err := s.tryAcquireCheckoutLock(customerID, "event-enhanced", eventID); err != nil {
 		return "", err
@@ -626,20 +649,21 @@ func (s *Service) CheckoutEventEnhanced(ctx context.Context, eventID uuid.UUID,
 		return "", errLib.New("Event requires membership or is not available for purchase", http.StatusBadRequest)
 	}
 
-	// Handle discount code if provided — validate only, don't increment usage until checkout succeeds
-	var stripeCouponID *string
-	if discountCode != nil {
-		validated, err := s.DiscountService.ValidateDiscount(ctx, *discountCode, nil)
-		if err != nil {
-			return "", err
-		}
-
-		// Validate that discount applies to one-time payments
-		if validated.AppliesTo != "one_time" && validated.AppliesTo != "both" {
-			return "", errLib.New("This discount code does not apply to one-time payments", http.StatusBadRequest)
+	// Automatic discounts, or the discount code when it saves more, plus any gift card balance
+	stripeCouponID, hold, err := s.balanceCheckoutCoupon(ctx, customerID, discountValues.PriceItem{Type: discountValues.ItemEvent, ID: eventID}, *options.StripePriceID, discountCode, nil, useBalance, "Event registration")
+	if err != nil {
+		return "", err
+	}
+	checkoutStarted := false
+	defer func() {
+		if !checkoutStarted {
+			s.releaseBalanceHold(ctx, hold)
 		}
+	}()
 
-		stripeCouponID = validated.StripeCouponID
+	taxRateIDs, err := s.eventTaxRateIDs(ctx, eventID)
+	if err != nil {
+		return "", err
 	}
 
 	// Proceed with existing Stripe checkout logic
@@ -651,5 +675,131 @@ func (s *Service) CheckoutEventEnhanced(ctx context.Context, eventID uuid.UUID,
 	existingCustomerID := s.getExistingStripeCustomerID(ctx, customerID)
 
 	eventIDStr := eventID.String()
-	return stripe.CreateOneTimePayment(ctx, *options.StripePriceID, 1, &eventIDStr, stripeCouponID, successURL, cancelURL, existingCustomerID)
+	url, err := stripe.CreateOneTimePaymentWithMetadata(ctx, *options.StripePriceID, 1, &eventIDStr, stripeCouponID, holdMetadata(hold), hold.ExpiresAt, successURL, cancelURL, existingCustomerID, taxRateIDs)
+	checkoutStarted = err == nil
+	return url, err
+}
+
+// eventTaxRateIDs returns the tax rates charged at the event's facility. Events whose
+// facility cannot be read are taxed in the default province.
+func (s *Service) eventTaxRateIDs(ctx context.Context, eventID uuid.UUID) ([]string, *errLib.CommonError) {
+	locationID := uuid.NullUUID{}
+	if event, err := s.EventService.GetEvent(ctx, eventID); err == nil {
+		locationID = uuid.NullUUID{UUID: event.Location.ID, Valid: event.Location.ID != uuid.Nil}
+	}
+	return s.TaxService.TaxRateIDsForLocation(ctx, locationID)
+}
+
+// CheckoutCourtRental rents a court for the logged-in customer. Credit payments are
+// settled immediately and return no URL. Card payments hold the court and return a
+// Stripe checkout URL; the webhook confirms the rental once paid and an unpaid hold
+// lapses with the session.
+func (s *Service) CheckoutCourtRental(ctx context.Context, req courtRentalValues.HoldRequest, successURL string, cancelURL string) (string, courtRentalValues.Rental, *errLib.CommonError) {
+	customerID, ctxErr := contextUtils.GetUserID(ctx)
+	if ctxErr != nil {
+		return "", courtRentalValues.Rental{}, ctxErr
+	}
+	req.CustomerID = customerID
+
+	if err := s.Dunning.CheckBookingAllowed(ctx, customerID); err != nil {
+		return "", courtRentalValues.Rental{}, err
+	}
+
+	// Prevent double-click duplicate checkouts
+	if err := s.tryAcquireCheckoutLock(customerID, "court-rental", req.CourtID); err != nil {
+		return "", courtRentalValues.Rental{}, err
+	}
+	defer s.releaseCheckoutLock(customerID, "court-rental", req.CourtID)
+
+	if req.PaymentMethod == courtRentalValues.PaymentCredits {
+		rental, err := s.CourtRentalService.BookWithCredits(ctx, req)
+		return "", rental, err
+	}
+
+	rental, err := s.CourtRentalService.HoldForCheckout(ctx, req)
+	if err != nil {
+		return "", courtRentalValues.Rental{}, err
+	}
+
+	// Free rentals are confirmed without payment
+	if rental.Status == courtRentalValues.StatusConfirmed {
+		return "", rental, nil
+	}
+
+	loc := timezone.ForLocation(ctx, s.DB, rental.LocationID)
+	description := fmt.Sprintf("%s rental, %s", rental.CourtName, rental.StartAt.In(loc).Format("Mon Jan 2 3:04 PM"))
+	existingCustomerID := s.getExistingStripeCustomerID(ctx, customerID)
+
+	var paymentURL string
+	taxRateIDs, err := s.TaxService.TaxRateIDsForLocation(ctx, uuid.NullUUID{UUID: rental.LocationID, Valid: true})
+	if err == nil {
+		paymentURL, err = stripe.CreateCourtRentalPayment(ctx, rental.ID, description, int64(rental.AmountCents), rental.Currency,
+			rental.HoldExpiresAt, successURL, cancelURL, existingCustomerID, taxRateIDs)
+	}
+	if err != nil {
+		// Free the court rather than leave it held until the hold lapses
+		if releaseErr := s.CourtRentalService.ReleaseHold(ctx, rental.ID); releaseErr != nil {
+			log.Printf("Failed to release court rental hold %s after checkout error: %v", rental.ID, releaseErr)
+		}
+		return "", courtRentalValues.Rental{}, err
+	}
+
+	return paymentURL, rental, nil
+}
+
+// CheckoutPlaygroundSession books a playground session for the logged-in customer. Credit
+// payments are settled immediately and return no URL. Card payments hold the system and
+// return a Stripe checkout URL; the webhook confirms the session once paid and an unpaid
+// hold lapses with the checkout session.
+func (s *Service) CheckoutPlaygroundSession(ctx context.Context, req playgroundValues.BookSessionValue, successURL string, cancelURL string) (string, playgroundValues.Session, *errLib.CommonError) {
+	customerID, ctxErr := contextUtils.GetUserID(ctx)
+	if ctxErr != nil {
+		return "", playgroundValues.Session{}, ctxErr
+	}
+	req.CustomerID = customerID
+
+	if err := s.Dunning.CheckBookingAllowed(ctx, customerID); err != nil {
+		return "", playgroundValues.Session{}, err
+	}
+
+	// Prevent double-click duplicate checkouts
+	if err := s.tryAcquireCheckoutLock(customerID, "playground-session", req.SystemID); err != nil {
+		return "", playgroundValues.Session{}, err
+	}
+	defer s.releaseCheckoutLock(customerID, "playground-session", req.SystemID)
+
+	if req.PaymentMethod == playgroundValues.PaymentCredits {
+		session, err := s.PlaygroundService.BookWithCredits(ctx, req)
+		return "", session, err
+	}
+
+	session, err := s.PlaygroundService.HoldForCheckout(ctx, req)
+	if err != nil {
+		return "", playgroundValues.Session{}, err
+	}
+
+	// Free sessions are confirmed without payment
+	if session.Status == playgroundValues.StatusConfirmed {
+		return "", session, nil
+	}
+
+	loc := timezone.ForLocation(ctx, s.DB, session.LocationID)
+	description := fmt.Sprintf("%s session, %s", session.SystemName, session.StartTime.In(loc).Format("Mon Jan 2 3:04 PM"))
+	existingCustomerID := s.getExistingStripeCustomerID(ctx, customerID)
+
+	var paymentURL string
+	taxRateIDs, err := s.TaxService.TaxRateIDsForLocation(ctx, uuid.NullUUID{UUID: session.LocationID, Valid: true})
+	if err == nil {
+		paymentURL, err = stripe.CreatePlaygroundSessionPayment(ctx, session.ID, description, int64(session.AmountCents), session.Currency,
+			*session.HoldExpiresAt, successURL, cancelURL, existingCustomerID, taxRateIDs)
+	}
+	if err != nil {
+		// Free the system rather than leave it held until the hold lapses
+		if releaseErr := s.PlaygroundService.ReleaseHold(ctx, session.ID); releaseErr != nil {
+			log.Printf("Failed to release playground session hold %s after checkout error: %v", session.ID, releaseErr)
+		}
+		return "", playgroundValues.Session{}, err
+	}
+
+	return paymentURL, session, nil
 }
@@ -1,6 +1,7 @@
 package payment
 
 import (
+	playgroundValues "api/internal/domains/playground/values"
 	"context"
 	"database/sql"
 	"log"
@@ -8,8 +9,12 @@ import (
 	"time"
 
 	"api/internal/di"
+	courtRentalService "api/internal/domains/court_rental/service"
+	courtRentalValues "api/internal/domains/court_rental/values"
+	playgroundService "api/internal/domains/playground/services"
 	creditPackageRepo "api/internal/domains/credit_package/persistence/repository"
 	enrollment "api/internal/domains/enrollment/service"
+	giftCardService "api/internal/domains/gift_card/service"
 	repository "api/internal/domains/payment/persistence/repositories"
 	"api/internal/domains/payment/services/stripe"
 	userServices "api/internal/domains/user/services"
@@ -27,6 +32,10 @@ type CheckoutVerificationService struct {
 	EnrollmentService      *enrollment.CustomerEnrollmentService
 	CreditPackageRepo      *creditPackageRepo.CreditPackageRepository
 	CustomerCreditService  *userServices.CustomerCreditService
+	CourtRentalService     *courtRentalService.Service
+	PlaygroundService      *playgroundService.Service
+	GiftCardService        *giftCardService.Service
+	RefundsService         *RefundsService
 	db                     *sql.DB
 	logger                 *logger.StructuredLogger
 }
@@ -38,6 +47,10 @@ func NewCheckoutVerificationService(container *di.Container) *CheckoutVerificati
 		EnrollmentService:      enrollment.NewCustomerEnrollmentService(container),
 		CreditPackageRepo:      creditPackageRepo.NewCreditPackageRepository(container),
 		CustomerCreditService:  userServices.NewCustomerCreditService(container),
+		CourtRentalService:     courtRentalService.NewService(container),
+		PlaygroundService:      playgroundService.NewService(container),
+		GiftCardService:        giftCardService.NewService(container),
+		RefundsService:         NewRefundsService(container),
 		db:                     container.DB,
 		logger:                 logger.WithComponent("checkout-verification"),
 	}
@@ -206,6 +219,17 @@ func (s *CheckoutVerificationService) checkCreditPackageActive(ctx context.Conte
 func (s *CheckoutVerificationService) reconcileCheckout(ctx context.Context, session *stripeLib.CheckoutSession, userID uuid.UUID) *errLib.CommonError {
 	eventCreatedAt := time.Unix(session.Created, 0)
 
+	// Spend any gift card balance the checkout held; capturing is idempotent
+	if holdIDStr := session.Metadata[stripe.StoredValueHoldMetadataKey]; holdIDStr != "" {
+		holdID, err := uuid.Parse(holdIDStr)
+		if err != nil {
+			return errLib.New("Invalid balance hold ID in session", http.StatusBadRequest)
+		}
+		if captureErr := s.GiftCardService.CaptureHold(ctx, holdID); captureErr != nil {
+			return captureErr
+		}
+	}
+
 	// Handle subscription checkout (membership)
 	if session.Mode == stripeLib.CheckoutSessionModeSubscription {
 		return s.reconcileMembershipCheckout(ctx, session, userID, eventCreatedAt)
@@ -318,6 +342,47 @@ func (s *CheckoutVerificationService) reconcileMembershipCheckout(ctx context.Co
 
 // reconcileOneTimeCheckout reconciles a missed one-time payment checkout
 func (s *CheckoutVerificationService) reconcileOneTimeCheckout(ctx context.Context, session *stripeLib.CheckoutSession, userID uuid.UUID, eventCreatedAt time.Time) *errLib.CommonError {
+	// Gift cards, court rentals and playground sessions are charged an ad-hoc amount;
+	// issuing or confirming one is idempotent
+	if cardIDStr := session.Metadata["giftCardID"]; cardIDStr != "" {
+		cardID, err := uuid.Parse(cardIDStr)
+		if err != nil {
+			return errLib.New("Invalid gift card ID in session", http.StatusBadRequest)
+		}
+		log.Printf("[RECONCILE] Issuing gift card %s bought by customer %s", cardID, userID)
+		return s.GiftCardService.IssuePurchased(ctx, cardID, session.ID)
+	}
+	if rentalIDStr := session.Metadata["courtRentalID"]; rentalIDStr != "" {
+		rentalID, err := uuid.Parse(rentalIDStr)
+		if err != nil {
+			return errLib.New("Invalid court rental ID in session", http.StatusBadRequest)
+		}
+		log.Printf("[RECONCILE] Confirming court rental %s for customer %s", rentalID, userID)
+		status, confirmErr := s.CourtRentalService.ConfirmPaid(ctx, rentalID, userID)
+		if confirmErr != nil {
+			return confirmErr
+		}
+		if status == courtRentalValues.StatusRefundDue {
+			s.RefundsService.RefundUnconfirmedBooking(ctx, session, "court_rental", rentalID, nil, s.CourtRentalService.MarkRefunded)
+		}
+		return nil
+	}
+	if sessionIDStr := session.Metadata["playgroundSessionID"]; sessionIDStr != "" {
+		sessionID, err := uuid.Parse(sessionIDStr)
+		if err != nil {
+			return errLib.New("Invalid playground session ID in session", http.StatusBadRequest)
+		}
+		log.Printf("[RECONCILE] Confirming playground session %s for customer %s", sessionID, userID)
+		status, confirmErr := s.PlaygroundService.ConfirmPaid(ctx, sessionID, userID)
+		if confirmErr != nil {
+			return confirmErr
+		}
+		if status == playgroundValues.StatusRefundDue {
+			s.RefundsService.RefundUnconfirmedBooking(ctx, session, "playground_session", sessionID, nil, s.PlaygroundService.MarkRefunded)
+		}
+		return nil
+	}
+
 	if session.LineItems == nil || len(session.LineItems.Data) == 0 {
 		return errLib.New("No line items in checkout session", http.StatusBadRequest)
 	}
@@ -334,7 +399,7 @@ func (s *CheckoutVerificationService) reconcileOneTimeCheckout(ctx context.Conte
 		if pkg != nil {
 			// Process credit package purchase
 			log.Printf("[RECONCILE] Adding %d credits to customer %s from package %s", pkg.CreditAllocation, userID, pkg.ID)
-			if err := s.CustomerCreditService.AddCredits(ctx, userID, pkg.CreditAllocation, "Credit package purchase (reconciled)"); err != nil {
+			if err := s.CustomerCreditService.AddPurchasedCredits(ctx, userID, pkg.CreditAllocation, pkg.CreditValidityDays, "Credit package purchase (reconciled)"); err != nil {
 				return errLib.New("Failed to add credits: "+err.Error(), http.StatusInternalServerError)
 			}
 
@@ -8,8 +8,11 @@ import (
 	"time"
 
 	"api/internal/di"
+	auditValues "api/internal/domains/audit/audit_logs/values"
+	staffActivityLogs "api/internal/domains/audit/staff_activity_logs/service"
 	db "api/internal/domains/payment/persistence/sqlc/generated"
 	errLib "api/internal/libs/errors"
+	txUtils "api/utils/db"
 	"api/utils/email"
 
 	"github.com/google/uuid"
@@ -25,19 +28,53 @@ import (
 )
 
 type CollectionsService struct {
-	queries   *db.Queries
-	db        *sql.DB
-	container *di.Container
+	queries                  *db.Queries
+	db                       *sql.DB
+	container                *di.Container
+	staffActivityLogsService *staffActivityLogs.Service
 }
 
 func NewCollectionsService(container *di.Container) *CollectionsService {
 	return &CollectionsService{
-		queries:   db.New(container.DB),
-		db:        container.DB,
-		container: container,
+		queries:                  db.New(container.DB),
+		db:                       container.DB,
+		container:                container,
+		staffActivityLogsService: staffActivityLogs.NewService(container),
 	}
 }
 
+// collectionAuditState is what the audit log records about a collection attempt
+type collectionAuditState struct {
+	CustomerID      uuid.UUID `json:"customer_id"`
+	Method          string    `json:"collection_method"`
+	Status          string    `json:"status"`
+	AmountAttempted float64   `json:"amount_attempted"`
+	AmountCollected float64   `json:"amount_collected"`
+	PreviousBalance float64   `json:"previous_balance"`
+	NewBalance      *float64  `json:"new_balance,omitempty"`
+	FailureReason   string    `json:"failure_reason,omitempty"`
+	PaymentLinkURL  string    `json:"payment_link_url,omitempty"`
+}
+
+// recordCollection records a collection attempt in the audit log. Card charges and
+// payment links have already reached Stripe by the time they are recorded, so pass a
+// nil tx for those and only log a failure; it must not turn into an error that gets
+// the collection retried.
+func (s *CollectionsService) recordCollection(ctx context.Context, tx *sql.Tx, adminID, attemptID uuid.UUID, state collectionAuditState, description string) *errLib.CommonError {
+	err := s.staffActivityLogsService.RecordChange(ctx, tx, adminID, auditValues.Entry{
+		EntityType:  "collection_attempt",
+		EntityID:    attemptID.String(),
+		Action:      state.Method,
+		After:       state,
+		Description: description,
+	})
+	if err != nil && tx == nil {
+		log.Printf("[COLLECTIONS] Warning: Failed to record collection attempt %s in the audit log: %v", attemptID, err.Message)
+		return nil
+	}
+	return err
+}
+
 // PaymentMethodInfo represents a saved payment method for a customer
 type PaymentMethodInfo struct {
 	ID          string `json:"id"`
@@ -199,6 +236,15 @@ func (s *CollectionsService) ChargeCard(ctx context.Context, adminID uuid.UUID,
 		return nil, errLib.New("Failed to create collection record", 500)
 	}
 
+	audit := collectionAuditState{
+		CustomerID:      req.CustomerID,
+		Method:          "card_charge",
+		Status:          "failed",
+		AmountAttempted: req.Amount,
+		PreviousBalance: previousBalance,
+	}
+	auditDesc := fmt.Sprintf("Charged $%.2f to %s of customer %s", req.Amount, paymentMethodDetails, req.CustomerID)
+
 	// Create PaymentIntent
 	amountInCents := int64(req.Amount * 100)
 	piParams := &stripe.PaymentIntentParams{
@@ -226,6 +272,10 @@ func (s *CollectionsService) ChargeCard(ctx context.Context, adminID uuid.UUID,
 		})
 
 		log.Printf("[COLLECTIONS] Card charge failed for customer %s: %v", req.CustomerID, piErr)
+
+		audit.FailureReason = piErr.Error()
+		s.recordCollection(ctx, nil, adminID, attempt.ID, audit, "Failed: "+auditDesc)
+
 		return &CollectionResult{
 			Success:      false,
 			CollectionID: attempt.ID,
@@ -257,6 +307,11 @@ func (s *CollectionsService) ChargeCard(ctx context.Context, adminID uuid.UUID,
 
 		log.Printf("[COLLECTIONS] Successfully charged $%.2f for customer %s", req.Amount, req.CustomerID)
 
+		audit.Status = "success"
+		audit.AmountCollected = req.Amount
+		audit.NewBalance = &newBalance
+		s.recordCollection(ctx, nil, adminID, attempt.ID, audit, auditDesc)
+
 		return &CollectionResult{
 			Success:         true,
 			CollectionID:    attempt.ID,
@@ -272,6 +327,9 @@ func (s *CollectionsService) ChargeCard(ctx context.Context, adminID uuid.UUID,
 		FailureReason: sql.NullString{String: fmt.Sprintf("Payment status: %s", pi.Status), Valid: true},
 	})
 
+	audit.FailureReason = fmt.Sprintf("Payment status: %s", pi.Status)
+	s.recordCollection(ctx, nil, adminID, attempt.ID, audit, "Failed: "+auditDesc)
+
 	return &CollectionResult{
 		Success:      false,
 		CollectionID: attempt.ID,
@@ -427,6 +485,15 @@ func (s *CollectionsService) SendPaymentLink(ctx context.Context, adminID uuid.U
 
 	log.Printf("[COLLECTIONS] Created payment link for customer %s: %s", req.CustomerID, pl.URL)
 
+	s.recordCollection(ctx, nil, adminID, attempt.ID, collectionAuditState{
+		CustomerID:      req.CustomerID,
+		Method:          "payment_link",
+		Status:          "pending",
+		AmountAttempted: req.Amount,
+		PreviousBalance: previousBalance,
+		PaymentLinkURL:  pl.URL,
+	}, fmt.Sprintf("Sent $%.2f payment link to customer %s", req.Amount, req.CustomerID))
+
 	return &CollectionResult{
 		Success:        true,
 		CollectionID:   attempt.ID,
@@ -459,24 +526,42 @@ func (s *CollectionsService) RecordManualPayment(ctx context.Context, adminID uu
 		newBalance = 0
 	}
 
-	// Create collection attempt record (already successful since it's manual)
-	attempt, createErr := s.queries.CreateCollectionAttempt(ctx, db.CreateCollectionAttemptParams{
-		CustomerID:           req.CustomerID,
-		AdminID:              adminID,
-		AmountAttempted:      decimal.NewFromFloat(req.Amount),
-		AmountCollected:      sql.NullString{String: fmt.Sprintf("%.2f", req.Amount), Valid: true},
-		CollectionMethod:     "manual_entry",
-		PaymentMethodDetails: sql.NullString{String: req.PaymentMethod, Valid: req.PaymentMethod != ""},
-		Status:               "success",
-		StripeCustomerID:     stripeCustomerID,
-		Notes:                sql.NullString{String: req.Notes, Valid: req.Notes != ""},
-		PreviousBalance:      sql.NullString{String: fmt.Sprintf("%.2f", previousBalance), Valid: true},
-		NewBalance:           sql.NullString{String: fmt.Sprintf("%.2f", newBalance), Valid: true},
-		CompletedAt:          sql.NullTime{Time: time.Now(), Valid: true},
+	// Create collection attempt record (already successful since it's manual) together
+	// with its audit entry
+	var attempt db.PaymentsCollectionAttempt
+	txErr := txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		var createErr error
+		attempt, createErr = s.queries.WithTx(tx).CreateCollectionAttempt(ctx, db.CreateCollectionAttemptParams{
+			CustomerID:           req.CustomerID,
+			AdminID:              adminID,
+			AmountAttempted:      decimal.NewFromFloat(req.Amount),
+			AmountCollected:      sql.NullString{String: fmt.Sprintf("%.2f", req.Amount), Valid: true},
+			CollectionMethod:     "manual_entry",
+			PaymentMethodDetails: sql.NullString{String: req.PaymentMethod, Valid: req.PaymentMethod != ""},
+			Status:               "success",
+			StripeCustomerID:     stripeCustomerID,
+			Notes:                sql.NullString{String: req.Notes, Valid: req.Notes != ""},
+			PreviousBalance:      sql.NullString{String: fmt.Sprintf("%.2f", previousBalance), Valid: true},
+			NewBalance:           sql.NullString{String: fmt.Sprintf("%.2f", newBalance), Valid: true},
+			CompletedAt:          sql.NullTime{Time: time.Now(), Valid: true},
+		})
+		if createErr != nil {
+			log.Printf("[COLLECTIONS] Error creating manual payment record: %v", createErr)
+			return errLib.New("Failed to record payment", 500)
+		}
+
+		return s.recordCollection(ctx, tx, adminID, attempt.ID, collectionAuditState{
+			CustomerID:      req.CustomerID,
+			Method:          "manual_entry",
+			Status:          "success",
+			AmountAttempted: req.Amount,
+			AmountCollected: req.Amount,
+			PreviousBalance: previousBalance,
+			NewBalance:      &newBalance,
+		}, fmt.Sprintf("Recorded $%.2f %s payment for customer %s", req.Amount, req.PaymentMethod, req.CustomerID))
 	})
-	if createErr != nil {
-		log.Printf("[COLLECTIONS] Error creating manual payment record: %v", createErr)
-		return nil, errLib.New("Failed to record payment", 500)
+	if txErr != nil {
+		return nil, txErr
 	}
 
 	log.Printf("[COLLECTIONS] Recorded manual payment of $%.2f for customer %s (method: %s)",
@@ -0,0 +1,580 @@
+package payment
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"api/internal/di"
+	staffActivityLogs "api/internal/domains/audit/staff_activity_logs/service"
+	db "api/internal/domains/payment/persistence/sqlc/generated"
+	userServices "api/internal/domains/user/services"
+	errLib "api/internal/libs/errors"
+	contextUtils "api/utils/context"
+	txUtils "api/utils/db"
+	"api/utils/email"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stripe/stripe-go/v81"
+)
+
+// Dunning step actions, in the order they run when due on the same day
+const (
+	DunningActionRemind          = "remind"
+	DunningActionRestrictBooking = "restrict_booking"
+	DunningActionSuspend         = "suspend"
+	DunningActionCollections     = "collections"
+
+	dunningActionPaymentFailed = "payment_failed"
+	dunningActionResolved      = "resolved"
+)
+
+var dunningActionOrder = map[string]int{
+	DunningActionRemind:          0,
+	DunningActionRestrictBooking: 1,
+	DunningActionSuspend:         2,
+	DunningActionCollections:     3,
+}
+
+// dunningBatchSize caps how many open cases one run works through
+const dunningBatchSize = 200
+
+const updatePaymentURL = "https://www.risesportscomplex.com/account/billing"
+
+// DunningService chases failed membership payments. Each failed invoice opens a case
+// that a scheduled job moves through the configured steps (reminders, booking
+// restriction, suspension, hand-off to collections) until the invoice is paid.
+type DunningService struct {
+	queries                  *db.Queries
+	db                       *sql.DB
+	suspensionService        *userServices.SuspensionService
+	staffActivityLogsService *staffActivityLogs.Service
+}
+
+func NewDunningService(container *di.Container) *DunningService {
+	return &DunningService{
+		queries:                  db.New(container.DB),
+		db:                       container.DB,
+		suspensionService:        userServices.NewSuspensionService(container),
+		staffActivityLogsService: staffActivityLogs.NewService(container),
+	}
+}
+
+// OpenCase starts dunning for a failed subscription invoice. Later failures of the
+// same invoice (Stripe retries) keep the original case and schedule.
+func (s *DunningService) OpenCase(ctx context.Context, customerID uuid.UUID, invoice *stripe.Invoice, failedAt time.Time) *errLib.CommonError {
+	if invoice == nil || invoice.Subscription == nil || invoice.Subscription.ID == "" {
+		return nil
+	}
+
+	currency := string(invoice.Currency)
+	if currency == "" {
+		currency = "cad"
+	}
+
+	var created bool
+	txErr := txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		q := s.queries.WithTx(tx)
+
+		dunningCase, err := q.CreateDunningCase(ctx, db.CreateDunningCaseParams{
+			CustomerID:           customerID,
+			StripeInvoiceID:      invoice.ID,
+			StripeSubscriptionID: sql.NullString{String: invoice.Subscription.ID, Valid: true},
+			AmountDue:            decimal.New(invoice.AmountDue, -2),
+			Currency:             currency,
+			FailedAt:             failedAt,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			log.Printf("[DUNNING] Failed to open case for invoice %s: %v", invoice.ID, err)
+			return errLib.New("Failed to open dunning case", http.StatusInternalServerError)
+		}
+		created = true
+
+		detail := fmt.Sprintf("Payment of $%s for invoice %s failed", dunningCase.AmountDue.StringFixed(2), invoice.ID)
+		if _, err := q.CreateDunningEvent(ctx, db.CreateDunningEventParams{
+			CaseID: dunningCase.ID,
+			Action: dunningActionPaymentFailed,
+			Detail: sql.NullString{String: detail, Valid: true},
+		}); err != nil {
+			log.Printf("[DUNNING] Failed to record failure of invoice %s: %v", invoice.ID, err)
+			return errLib.New("Failed to open dunning case", http.StatusInternalServerError)
+		}
+		return nil
+	})
+	if txErr != nil {
+		return txErr
+	}
+
+	if created {
+		log.Printf("[DUNNING] Opened case for customer %s, invoice %s", customerID, invoice.ID)
+	}
+	return nil
+}
+
+// ResolveInvoice closes the cases settled by a paid invoice: booking is allowed again
+// and a customer suspended by dunning is unsuspended once no unpaid case remains.
+func (s *DunningService) ResolveInvoice(ctx context.Context, invoiceID string, subscriptionID string) *errLib.CommonError {
+	resolved, err := s.queries.ResolveDunningCases(ctx, db.ResolveDunningCasesParams{
+		StripeInvoiceID:      invoiceID,
+		StripeSubscriptionID: sql.NullString{String: subscriptionID, Valid: subscriptionID != ""},
+	})
+	if err != nil {
+		log.Printf("[DUNNING] Failed to resolve cases for invoice %s: %v", invoiceID, err)
+		return errLib.New("Failed to resolve dunning cases", http.StatusInternalServerError)
+	}
+
+	unsuspend := make(map[uuid.UUID]bool)
+	for _, dunningCase := range resolved {
+		if _, err := s.queries.CreateDunningEvent(ctx, db.CreateDunningEventParams{
+			CaseID: dunningCase.ID,
+			Action: dunningActionResolved,
+			Detail: sql.NullString{String: "Paid by invoice " + invoiceID, Valid: true},
+		}); err != nil {
+			log.Printf("[DUNNING] Failed to record resolution of case %s: %v", dunningCase.ID, err)
+		}
+		if dunningCase.Suspended {
+			unsuspend[dunningCase.CustomerID] = true
+		}
+		log.Printf("[DUNNING] Resolved case %s (invoice %s) for customer %s", dunningCase.ID, dunningCase.StripeInvoiceID, dunningCase.CustomerID)
+	}
+
+	for customerID := range unsuspend {
+		stillOwing, err := s.queries.HasUnresolvedDunningSuspension(ctx, customerID)
+		if err != nil || stillOwing {
+			continue
+		}
+
+		contact, err := s.queries.GetDunningContact(ctx, db.GetDunningContactParams{CustomerID: customerID})
+		if err != nil || !contact.SuspendedAt.Valid {
+			continue
+		}
+
+		if unsuspendErr := s.suspensionService.UnsuspendUser(ctx, userServices.UnsuspendUserParams{UserID: customerID}); unsuspendErr != nil {
+			log.Printf("[DUNNING] Failed to unsuspend customer %s after payment: %v", customerID, unsuspendErr)
+		}
+	}
+
+	return nil
+}
+
+// CheckBookingAllowed rejects new bookings from customers whose overdue membership
+// payment has reached the booking restriction step.
+func (s *DunningService) CheckBookingAllowed(ctx context.Context, customerID uuid.UUID) *errLib.CommonError {
+	restricted, err := s.queries.HasDunningBookingRestriction(ctx, customerID)
+	if err != nil {
+		// Don't block bookings because the check itself failed
+		log.Printf("[DUNNING] Failed to check booking restriction for customer %s: %v", customerID, err)
+		return nil
+	}
+	if restricted {
+		return errLib.New("Bookings are paused until your overdue membership payment is settled. Please update your payment method.", http.StatusForbidden)
+	}
+	return nil
+}
+
+// RunDueSteps applies every step that has come due on open cases. It is safe to run
+// concurrently: each step is claimed before it runs.
+func (s *DunningService) RunDueSteps(ctx context.Context) error {
+	steps, err := s.queries.ListDunningSteps(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list dunning steps: %w", err)
+	}
+	if len(steps) == 0 {
+		return nil
+	}
+
+	cases, err := s.queries.ListOpenDunningCases(ctx, dunningBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to list open dunning cases: %w", err)
+	}
+
+	now := time.Now()
+	applied, failed := 0, 0
+	for _, dunningCase := range cases {
+		events, err := s.queries.ListDunningEvents(ctx, dunningCase.ID)
+		if err != nil {
+			log.Printf("[DUNNING] Failed to list events of case %s: %v", dunningCase.ID, err)
+			failed++
+			continue
+		}
+
+		days := daysOverdue(dunningCase.FailedAt, now)
+		for _, planned := range planDunningSteps(steps, completedDunningSteps(events), days) {
+			if err := s.applyStep(ctx, dunningCase, steps, planned, days); err != nil {
+				log.Printf("[DUNNING] Failed to %s for case %s (day %d): %v", planned.Step.Action, dunningCase.ID, planned.Step.DayOffset, err)
+				failed++
+				break
+			}
+			applied++
+			if planned.Step.Action == DunningActionCollections {
+				break
+			}
+		}
+	}
+
+	log.Printf("[DUNNING] Summary: cases=%d, steps_applied=%d, failures=%d", len(cases), applied, failed)
+	return nil
+}
+
+func (s *DunningService) applyStep(ctx context.Context, dunningCase db.PaymentsDunningCase, steps []db.PaymentsDunningStep, planned plannedDunningStep, days int) error {
+	step := planned.Step
+	dayOffset := sql.NullInt32{Int32: step.DayOffset, Valid: true}
+
+	contact, err := s.queries.GetDunningContact(ctx, db.GetDunningContactParams{
+		StripeSubscriptionID: dunningCase.StripeSubscriptionID,
+		CustomerID:           dunningCase.CustomerID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get customer: %w", err)
+	}
+
+	detail := s.describeStep(dunningCase, step, planned, contact)
+
+	claimed, err := s.queries.CreateDunningEvent(ctx, db.CreateDunningEventParams{
+		CaseID:    dunningCase.ID,
+		Action:    step.Action,
+		DayOffset: dayOffset,
+		Detail:    sql.NullString{String: detail, Valid: detail != ""},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record step: %w", err)
+	}
+	if claimed == 0 {
+		return nil // another run got here first
+	}
+
+	if runErr := s.runStep(ctx, dunningCase, steps, planned, contact, days); runErr != nil {
+		if releaseErr := s.queries.ReleaseDunningEvent(ctx, db.ReleaseDunningEventParams{
+			CaseID:    dunningCase.ID,
+			Action:    step.Action,
+			DayOffset: dayOffset,
+		}); releaseErr != nil {
+			log.Printf("[DUNNING] Failed to release step of case %s: %v", dunningCase.ID, releaseErr)
+		}
+		return runErr
+	}
+
+	log.Printf("[DUNNING] Case %s day %d: %s", dunningCase.ID, step.DayOffset, detail)
+	return nil
+}
+
+func (s *DunningService) runStep(ctx context.Context, dunningCase db.PaymentsDunningCase, steps []db.PaymentsDunningStep, planned plannedDunningStep, contact db.GetDunningContactRow, days int) error {
+	switch planned.Step.Action {
+	case DunningActionRemind:
+		if !planned.Notify || !contact.Email.Valid || contact.Email.String == "" {
+			return nil
+		}
+		plan := contact.MembershipPlan
+		if plan == "" {
+			plan = "your membership"
+		}
+		if suspendDay, ok := dunningSuspensionDay(steps); ok {
+			email.SendPaymentFailedReminderEmail(contact.Email.String, contact.FirstName, plan, updatePaymentURL, max(suspendDay-days, 0))
+		} else {
+			email.SendPaymentFailedEmail(contact.Email.String, contact.FirstName, plan, updatePaymentURL)
+		}
+		return nil
+
+	case DunningActionRestrictBooking:
+		return s.queries.RestrictDunningCaseBooking(ctx, dunningCase.ID)
+
+	case DunningActionSuspend:
+		if contact.SuspendedAt.Valid {
+			return nil // suspended by staff already; resolving the case must not lift that
+		}
+		if err := s.suspensionService.SuspendUser(ctx, userServices.SuspendUserParams{
+			UserID:           dunningCase.CustomerID,
+			SuspensionReason: fmt.Sprintf("Non-payment: membership invoice %s unpaid for %d days", dunningCase.StripeInvoiceID, days),
+		}); err != nil {
+			return errors.New(err.Message)
+		}
+		return s.queries.MarkDunningCaseSuspended(ctx, dunningCase.ID)
+
+	case DunningActionCollections:
+		return s.queries.MoveDunningCaseToCollections(ctx, dunningCase.ID)
+	}
+
+	return fmt.Errorf("unknown dunning action %q", planned.Step.Action)
+}
+
+func (s *DunningService) describeStep(dunningCase db.PaymentsDunningCase, step db.PaymentsDunningStep, planned plannedDunningStep, contact db.GetDunningContactRow) string {
+	switch step.Action {
+	case DunningActionRemind:
+		switch {
+		case !planned.Notify:
+			return "Reminder skipped, a later reminder is due"
+		case !contact.Email.Valid || contact.Email.String == "":
+			return "Reminder skipped, customer has no email"
+		default:
+			return "Payment reminder emailed to " + contact.Email.String
+		}
+	case DunningActionRestrictBooking:
+		return "New bookings restricted"
+	case DunningActionSuspend:
+		if contact.SuspendedAt.Valid {
+			return "Customer was already suspended"
+		}
+		return "Customer suspended for non-payment"
+	case DunningActionCollections:
+		return fmt.Sprintf("Handed to collections ($%s owed)", dunningCase.AmountDue.StringFixed(2))
+	}
+	return ""
+}
+
+// plannedDunningStep is a due step. Notify is false for reminders overtaken by a later
+// reminder that is due in the same run, so customers get one email, not a burst.
+type plannedDunningStep struct {
+	Step   db.PaymentsDunningStep
+	Notify bool
+}
+
+// planDunningSteps returns the steps due daysOverdue days after a payment failed that
+// have not run yet, in the order they run.
+func planDunningSteps(steps []db.PaymentsDunningStep, completed map[string]bool, daysOverdue int) []plannedDunningStep {
+	ordered := sortDunningSteps(steps)
+
+	var planned []plannedDunningStep
+	lastReminder := -1
+	for _, step := range ordered {
+		if int(step.DayOffset) > daysOverdue || completed[dunningStepKey(step.Action, step.DayOffset)] {
+			continue
+		}
+		if step.Action == DunningActionRemind {
+			lastReminder = len(planned)
+		}
+		planned = append(planned, plannedDunningStep{Step: step})
+	}
+
+	for i := range planned {
+		planned[i].Notify = planned[i].Step.Action != DunningActionRemind || i == lastReminder
+	}
+	return planned
+}
+
+// nextDunningStep returns the first step of the schedule that has not run yet
+func nextDunningStep(steps []db.PaymentsDunningStep, completed map[string]bool) (db.PaymentsDunningStep, bool) {
+	for _, step := range sortDunningSteps(steps) {
+		if !completed[dunningStepKey(step.Action, step.DayOffset)] {
+			return step, true
+		}
+	}
+	return db.PaymentsDunningStep{}, false
+}
+
+func sortDunningSteps(steps []db.PaymentsDunningStep) []db.PaymentsDunningStep {
+	ordered := append([]db.PaymentsDunningStep(nil), steps...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].DayOffset != ordered[j].DayOffset {
+			return ordered[i].DayOffset < ordered[j].DayOffset
+		}
+		return dunningActionOrder[ordered[i].Action] < dunningActionOrder[ordered[j].Action]
+	})
+	return ordered
+}
+
+func completedDunningSteps(events []db.PaymentsDunningEvent) map[string]bool {
+	completed := make(map[string]bool, len(events))
+	for _, event := range events {
+		if event.DayOffset.Valid {
+			completed[dunningStepKey(event.Action, event.DayOffset.Int32)] = true
+		}
+	}
+	return completed
+}
+
+func dunningStepKey(action string, dayOffset int32) string {
+	return fmt.Sprintf("%s:%d", action, dayOffset)
+}
+
+func dunningSuspensionDay(steps []db.PaymentsDunningStep) (int, bool) {
+	for _, step := range sortDunningSteps(steps) {
+		if step.Action == DunningActionSuspend {
+			return int(step.DayOffset), true
+		}
+	}
+	return 0, false
+}
+
+// daysOverdue counts whole days since the payment failed
+func daysOverdue(failedAt, now time.Time) int {
+	if now.Before(failedAt) {
+		return 0
+	}
+	return int(now.Sub(failedAt) / (24 * time.Hour))
+}
+
+// DunningStepInput is one step of the dunning schedule
+type DunningStepInput struct {
+	DayOffset int32  `json:"day_offset" example:"3"`  // Days after the payment failed
+	Action    string `json:"action" example:"remind"` // remind, restrict_booking, suspend or collections
+}
+
+// ReplaceDunningStepsRequest is the full dunning schedule
+type ReplaceDunningStepsRequest struct {
+	Steps []DunningStepInput `json:"steps"`
+}
+
+func (s *DunningService) ListSteps(ctx context.Context) ([]db.PaymentsDunningStep, *errLib.CommonError) {
+	steps, err := s.queries.ListDunningSteps(ctx)
+	if err != nil {
+		log.Printf("[DUNNING] Failed to list steps: %v", err)
+		return nil, errLib.New("Failed to list dunning steps", http.StatusInternalServerError)
+	}
+	return sortDunningSteps(steps), nil
+}
+
+// ReplaceSteps replaces the whole dunning schedule. Open cases follow the new schedule
+// from their next run; steps that already ran are not repeated.
+func (s *DunningService) ReplaceSteps(ctx context.Context, inputs []DunningStepInput) ([]db.PaymentsDunningStep, *errLib.CommonError) {
+	staffID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateDunningSteps(inputs); err != nil {
+		return nil, err
+	}
+
+	var steps []db.PaymentsDunningStep
+	txErr := txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		q := s.queries.WithTx(tx)
+
+		if err := q.DeleteDunningSteps(ctx); err != nil {
+			log.Printf("[DUNNING] Failed to clear steps: %v", err)
+			return errLib.New("Failed to update dunning steps", http.StatusInternalServerError)
+		}
+
+		summary := make([]string, 0, len(inputs))
+		for _, input := range inputs {
+			step, err := q.CreateDunningStep(ctx, db.CreateDunningStepParams{DayOffset: input.DayOffset, Action: input.Action})
+			if err != nil {
+				log.Printf("[DUNNING] Failed to create step: %v", err)
+				return errLib.New("Failed to update dunning steps", http.StatusInternalServerError)
+			}
+			steps = append(steps, step)
+			summary = append(summary, fmt.Sprintf("day %d %s", step.DayOffset, step.Action))
+		}
+
+		return s.staffActivityLogsService.InsertStaffActivity(ctx, tx, staffID,
+			"Updated dunning schedule: "+strings.Join(summary, ", "))
+	})
+	if txErr != nil {
+		return nil, txErr
+	}
+
+	return sortDunningSteps(steps), nil
+}
+
+func validateDunningSteps(inputs []DunningStepInput) *errLib.CommonError {
+	seen := make(map[string]bool)
+	once := make(map[string]bool)
+	collectionsDay := int32(-1)
+
+	for _, input := range inputs {
+		if _, ok := dunningActionOrder[input.Action]; !ok {
+			return errLib.New("action must be one of remind, restrict_booking, suspend, collections", http.StatusBadRequest)
+		}
+		if input.DayOffset < 0 || input.DayOffset > 365 {
+			return errLib.New("day_offset must be between 0 and 365", http.StatusBadRequest)
+		}
+
+		key := dunningStepKey(input.Action, input.DayOffset)
+		if seen[key] {
+			return errLib.New(fmt.Sprintf("Duplicate step: %s on day %d", input.Action, input.DayOffset), http.StatusBadRequest)
+		}
+		seen[key] = true
+
+		if input.Action != DunningActionRemind {
+			if once[input.Action] {
+				return errLib.New(fmt.Sprintf("Only one %s step is allowed", input.Action), http.StatusBadRequest)
+			}
+			once[input.Action] = true
+		}
+		if input.Action == DunningActionCollections {
+			collectionsDay = input.DayOffset
+		}
+	}
+
+	// Cases stop following the schedule once they are handed to collections
+	if collectionsDay >= 0 {
+		for _, input := range inputs {
+			if input.DayOffset > collectionsDay {
+				return errLib.New("No step can come after the collections hand-off", http.StatusBadRequest)
+			}
+		}
+	}
+
+	return nil
+}
+
+// DunningCaseTimeline is a case with everything that has happened to it and, while it
+// is open, the next step due.
+type DunningCaseTimeline struct {
+	Case      db.PaymentsDunningCase
+	Events    []db.PaymentsDunningEvent
+	NextStep  *db.PaymentsDunningStep
+	NextDueAt *time.Time
+}
+
+// CustomerTimeline returns a customer's dunning cases, most recent first
+func (s *DunningService) CustomerTimeline(ctx context.Context, customerID uuid.UUID) ([]DunningCaseTimeline, *errLib.CommonError) {
+	cases, err := s.queries.ListCustomerDunningCases(ctx, customerID)
+	if err != nil {
+		log.Printf("[DUNNING] Failed to list cases of customer %s: %v", customerID, err)
+		return nil, errLib.New("Failed to get dunning timeline", http.StatusInternalServerError)
+	}
+
+	events, err := s.queries.ListCustomerDunningEvents(ctx, customerID)
+	if err != nil {
+		log.Printf("[DUNNING] Failed to list events of customer %s: %v", customerID, err)
+		return nil, errLib.New("Failed to get dunning timeline", http.StatusInternalServerError)
+	}
+
+	steps, err := s.queries.ListDunningSteps(ctx)
+	if err != nil {
+		log.Printf("[DUNNING] Failed to list steps: %v", err)
+		return nil, errLib.New("Failed to get dunning timeline", http.StatusInternalServerError)
+	}
+
+	eventsByCase := make(map[uuid.UUID][]db.PaymentsDunningEvent)
+	for _, event := range events {
+		eventsByCase[event.CaseID] = append(eventsByCase[event.CaseID], event)
+	}
+
+	timelines := make([]DunningCaseTimeline, 0, len(cases))
+	for _, dunningCase := range cases {
+		timeline := DunningCaseTimeline{Case: dunningCase, Events: eventsByCase[dunningCase.ID]}
+
+		if dunningCase.Status == "open" {
+			if next, ok := nextDunningStep(steps, completedDunningSteps(timeline.Events)); ok {
+				dueAt := dunningCase.FailedAt.Add(time.Duration(next.DayOffset) * 24 * time.Hour)
+				timeline.NextStep = &next
+				timeline.NextDueAt = &dueAt
+			}
+		}
+
+		timelines = append(timelines, timeline)
+	}
+
+	return timelines, nil
+}
+
+// CollectionsQueue lists cases handed to collections, oldest first
+func (s *DunningService) CollectionsQueue(ctx context.Context) ([]db.ListDunningCollectionsQueueRow, *errLib.CommonError) {
+	queue, err := s.queries.ListDunningCollectionsQueue(ctx)
+	if err != nil {
+		log.Printf("[DUNNING] Failed to list collections queue: %v", err)
+		return nil, errLib.New("Failed to list collections queue", http.StatusInternalServerError)
+	}
+	return queue, nil
+}
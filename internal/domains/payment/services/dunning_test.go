@@ -0,0 +1,87 @@
+package payment
+
+import (
+	"testing"
+
+	db "api/internal/domains/payment/persistence/sqlc/generated"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanDunningSteps(t *testing.T) {
+	steps := []db.PaymentsDunningStep{
+		{DayOffset: 14, Action: DunningActionSuspend},
+		{DayOffset: 1, Action: DunningActionRemind},
+		{DayOffset: 3, Action: DunningActionRemind},
+		{DayOffset: 7, Action: DunningActionRemind},
+		{DayOffset: 10, Action: DunningActionRestrictBooking},
+		{DayOffset: 21, Action: DunningActionCollections},
+		{DayOffset: 14, Action: DunningActionRemind},
+	}
+
+	t.Run("Nothing is due on the day of the failure", func(t *testing.T) {
+		assert.Empty(t, planDunningSteps(steps, nil, 0))
+	})
+
+	t.Run("Skips steps that already ran", func(t *testing.T) {
+		completed := map[string]bool{"remind:1": true}
+
+		planned := planDunningSteps(steps, completed, 3)
+
+		require.Len(t, planned, 1)
+		assert.Equal(t, int32(3), planned[0].Step.DayOffset)
+		assert.True(t, planned[0].Notify)
+	})
+
+	t.Run("Only the latest overdue reminder is emailed", func(t *testing.T) {
+		planned := planDunningSteps(steps, nil, 8)
+
+		require.Len(t, planned, 3)
+		assert.False(t, planned[0].Notify)
+		assert.False(t, planned[1].Notify)
+		assert.True(t, planned[2].Notify)
+		assert.Equal(t, int32(7), planned[2].Step.DayOffset)
+	})
+
+	t.Run("Runs same-day steps in escalation order", func(t *testing.T) {
+		completed := map[string]bool{"remind:1": true, "remind:3": true, "remind:7": true, "restrict_booking:10": true}
+
+		planned := planDunningSteps(steps, completed, 30)
+
+		require.Len(t, planned, 3)
+		assert.Equal(t, DunningActionRemind, planned[0].Step.Action)
+		assert.Equal(t, DunningActionSuspend, planned[1].Step.Action)
+		assert.Equal(t, DunningActionCollections, planned[2].Step.Action)
+		assert.True(t, planned[1].Notify)
+	})
+}
+
+func TestValidateDunningSteps(t *testing.T) {
+	t.Run("Accepts the default schedule", func(t *testing.T) {
+		assert.Nil(t, validateDunningSteps([]DunningStepInput{
+			{DayOffset: 1, Action: DunningActionRemind},
+			{DayOffset: 3, Action: DunningActionRemind},
+			{DayOffset: 10, Action: DunningActionRestrictBooking},
+			{DayOffset: 14, Action: DunningActionSuspend},
+			{DayOffset: 21, Action: DunningActionCollections},
+		}))
+	})
+
+	t.Run("Rejects a second suspension step", func(t *testing.T) {
+		err := validateDunningSteps([]DunningStepInput{
+			{DayOffset: 7, Action: DunningActionSuspend},
+			{DayOffset: 14, Action: DunningActionSuspend},
+		})
+		require.NotNil(t, err)
+		assert.Equal(t, 400, err.HTTPCode)
+	})
+
+	t.Run("Rejects steps after the collections hand-off", func(t *testing.T) {
+		err := validateDunningSteps([]DunningStepInput{
+			{DayOffset: 14, Action: DunningActionCollections},
+			{DayOffset: 21, Action: DunningActionRemind},
+		})
+		require.NotNil(t, err)
+	})
+}
@@ -184,6 +184,51 @@ func (s *WebhookService) trackEventRegistration(session *stripe.CheckoutSession,
 	}
 }
 
+// trackBookingPayment tracks a court rental or playground session paid by card. The
+// booking's ID is recorded in the metadata under idKey.
+func (s *WebhookService) trackBookingPayment(session *stripe.CheckoutSession, customerID uuid.UUID, transactionType, description, idKey string, bookingID uuid.UUID, transactionDate time.Time, receiptURL string) {
+	ctx := context.Background()
+
+	user, err := s.UserRepo.GetUserInfo(ctx, "", customerID)
+	if err != nil {
+		log.Printf("[PAYMENT-TRACKING] Failed to get user info for %s: %v", customerID, err)
+		return
+	}
+
+	customerEmail := ""
+	if user.Email != nil {
+		customerEmail = *user.Email
+	}
+
+	stripeCustomerID := ""
+	if session.Customer != nil {
+		stripeCustomerID = session.Customer.ID
+	}
+
+	amount := float64(session.AmountTotal) / 100.0
+
+	_, trackingErr := s.PaymentTracking.TrackPayment(ctx, tracking.TrackPaymentParams{
+		CustomerID:              customerID,
+		CustomerEmail:           customerEmail,
+		CustomerName:            user.FirstName + " " + user.LastName,
+		TransactionType:         transactionType,
+		TransactionDate:         transactionDate,
+		OriginalAmount:          amount,
+		CustomerPaid:            amount,
+		StripeCustomerID:        stripeCustomerID,
+		StripeCheckoutSessionID: session.ID,
+		PaymentStatus:           "completed",
+		Currency:                string(session.Currency),
+		Description:             description,
+		Metadata:                map[string]interface{}{idKey: bookingID.String()},
+		ReceiptURL:              receiptURL,
+	})
+
+	if trackingErr != nil {
+		log.Printf("[PAYMENT-TRACKING] Failed to track %s: %v", transactionType, trackingErr)
+	}
+}
+
 // trackMembershipSubscription tracks a membership subscription payment
 func (s *WebhookService) trackMembershipSubscription(session *stripe.CheckoutSession, customerID, planID uuid.UUID, transactionDate time.Time) {
 	ctx := context.Background()
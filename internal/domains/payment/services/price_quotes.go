@@ -0,0 +1,117 @@
+package payment
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	discountValues "api/internal/domains/discount/values"
+	"api/internal/domains/payment/services/stripe"
+	errLib "api/internal/libs/errors"
+)
+
+// PreviewPrice shows the logged-in customer what they would pay for a membership plan,
+// program or event at checkout, with the automatic discounts and discount code that
+// apply. Amounts are before tax.
+func (s *Service) PreviewPrice(ctx context.Context, item discountValues.PriceItem, discountCode *string) (discountValues.PriceQuote, *errLib.CommonError) {
+	var stripePriceID string
+
+	switch item.Type {
+	case discountValues.ItemMembership:
+		requirements, err := s.CheckoutRepo.GetMembershipPlanJoiningRequirement(ctx, item.ID)
+		if err != nil {
+			return discountValues.PriceQuote{}, err
+		}
+		stripePriceID = requirements.StripePriceID
+	case discountValues.ItemProgram:
+		isPayPerEvent, priceID, err := s.CheckoutRepo.GetRegistrationPriceIdForCustomerByProgramID(ctx, item.ID)
+		if err != nil {
+			return discountValues.PriceQuote{}, err
+		}
+		if isPayPerEvent {
+			return discountValues.PriceQuote{}, errLib.New("program is paid per event", http.StatusBadRequest)
+		}
+		stripePriceID = priceID
+	case discountValues.ItemEvent:
+		options, err := s.CheckEventEnrollmentOptions(ctx, item.ID)
+		if err != nil {
+			return discountValues.PriceQuote{}, err
+		}
+		if !options.RegistrationRequired {
+			return discountValues.PriceQuote{}, errLib.New("This event does not require registration", http.StatusBadRequest)
+		}
+		if options.CanEnrollFree {
+			return discountValues.PriceQuote{Item: item}, nil
+		}
+		if options.StripePriceID == nil {
+			return discountValues.PriceQuote{}, errLib.New("Event requires membership or is not available for purchase", http.StatusBadRequest)
+		}
+		stripePriceID = *options.StripePriceID
+	default:
+		return discountValues.PriceQuote{}, errLib.New("item type must be membership, program or event", http.StatusBadRequest)
+	}
+
+	return s.quoteItem(ctx, item, stripePriceID, discountCode)
+}
+
+// quoteItem prices an item at its Stripe price for the logged-in customer
+func (s *Service) quoteItem(ctx context.Context, item discountValues.PriceItem, stripePriceID string, discountCode *string) (discountValues.PriceQuote, *errLib.CommonError) {
+	stripePrice, err := stripe.NewPriceService().GetPrice(stripePriceID)
+	if err != nil {
+		return discountValues.PriceQuote{}, err
+	}
+	return s.DiscountService.QuotePrice(ctx, item, stripePrice.UnitAmount, strings.ToUpper(string(stripePrice.Currency)), discountCode)
+}
+
+// checkoutCoupon returns the Stripe coupon for the automatic discounts a checkout qualifies
+// for, or for the discount code when it saves more. The code is only validated; metadata,
+// when given, records it so the webhook increments its usage once payment succeeds.
+func (s *Service) checkoutCoupon(ctx context.Context, item discountValues.PriceItem, stripePriceID string, discountCode *string, metadata map[string]string) (*string, *errLib.CommonError) {
+	quote, err := s.quoteItem(ctx, item, stripePriceID, discountCode)
+	if err != nil {
+		return nil, err
+	}
+	return s.quoteCoupon(ctx, item, quote, metadata)
+}
+
+// quoteCoupon returns the Stripe coupon giving a customer the discounts of quote.
+func (s *Service) quoteCoupon(ctx context.Context, item discountValues.PriceItem, quote discountValues.PriceQuote, metadata map[string]string) (*string, *errLib.CommonError) {
+	recordQuoteDiscounts(quote, metadata)
+
+	if quote.Code != nil {
+		return quote.Code.StripeCouponID, nil
+	}
+
+	if quote.DiscountTotal <= 0 {
+		return nil, nil
+	}
+
+	// Discounts on memberships stay on every renewal
+	couponID, err := stripe.CreateAutomaticDiscountCoupon(ctx, quote.DiscountTotal, quote.Currency, item.Type == discountValues.ItemMembership)
+	if err != nil {
+		return nil, err
+	}
+	return &couponID, nil
+}
+
+// recordQuoteDiscounts records the discount code or automatic discount rules of quote in
+// metadata, when given, for the webhook.
+func recordQuoteDiscounts(quote discountValues.PriceQuote, metadata map[string]string) {
+	if metadata == nil {
+		return
+	}
+
+	if quote.Code != nil {
+		metadata["discount_code"] = quote.Code.Name
+		metadata["discount_id"] = quote.Code.ID.String()
+		return
+	}
+
+	if len(quote.Applied) > 0 {
+		ruleIDs := make([]string, len(quote.Applied))
+		for i, adjustment := range quote.Applied {
+			ruleIDs[i] = adjustment.ID.String()
+		}
+		metadata["discount_rule_ids"] = strings.Join(ruleIDs, ",")
+	}
+}
@@ -0,0 +1,123 @@
+package payment
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"api/config"
+	"api/internal/di"
+	db "api/internal/domains/payment/persistence/sqlc/generated"
+	errLib "api/internal/libs/errors"
+	"api/utils/timezone"
+
+	"github.com/google/uuid"
+)
+
+// ReceiptService builds downloadable PDF receipts and annual statements from tracked
+// payments and their tax breakdowns.
+type ReceiptService struct {
+	queries *db.Queries
+}
+
+func NewReceiptService(container *di.Container) *ReceiptService {
+	return &ReceiptService{
+		queries: db.New(container.DB),
+	}
+}
+
+// TransactionReceipt renders the receipt of a payment. When customerID is set the
+// payment must belong to that customer, otherwise it is reported as not found.
+func (s *ReceiptService) TransactionReceipt(ctx context.Context, transactionID uuid.UUID, customerID *uuid.UUID) ([]byte, *errLib.CommonError) {
+	transaction, err := s.queries.GetPaymentTransaction(ctx, transactionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errLib.New("Payment not found", http.StatusNotFound)
+		}
+		log.Printf("[RECEIPTS] Failed to get transaction %s: %v", transactionID, err)
+		return nil, errLib.New("Failed to get payment", http.StatusInternalServerError)
+	}
+
+	if customerID != nil && transaction.CustomerID != *customerID {
+		return nil, errLib.New("Payment not found", http.StatusNotFound)
+	}
+
+	switch transaction.PaymentStatus {
+	case "completed", "refunded", "partially_refunded":
+	default:
+		return nil, errLib.New("Receipts are only available for completed payments", http.StatusConflict)
+	}
+
+	taxes, err := s.queries.ListTransactionTaxes(ctx, transactionID)
+	if err != nil {
+		log.Printf("[RECEIPTS] Failed to get taxes of transaction %s: %v", transactionID, err)
+		return nil, errLib.New("Failed to get payment", http.StatusInternalServerError)
+	}
+
+	issuer, issuerErr := s.issuer(ctx)
+	if issuerErr != nil {
+		return nil, issuerErr
+	}
+
+	return renderReceipt(issuer, transaction, taxes), nil
+}
+
+// AnnualStatement renders every payment a customer made in a calendar year, in the
+// facility time zone, with the tax paid.
+func (s *ReceiptService) AnnualStatement(ctx context.Context, customerID uuid.UUID, year int) ([]byte, *errLib.CommonError) {
+	if year < 2000 || year > time.Now().Year() {
+		return nil, errLib.New("Invalid year", http.StatusBadRequest)
+	}
+
+	loc := timezone.Default()
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	end := start.AddDate(1, 0, 0)
+
+	transactions, err := s.queries.ListCustomerStatementTransactions(ctx, db.ListCustomerStatementTransactionsParams{
+		CustomerID: customerID,
+		StartDate:  start,
+		EndDate:    end,
+	})
+	if err != nil {
+		log.Printf("[RECEIPTS] Failed to list %d payments of customer %s: %v", year, customerID, err)
+		return nil, errLib.New("Failed to build statement", http.StatusInternalServerError)
+	}
+
+	taxes, err := s.queries.ListCustomerStatementTaxes(ctx, db.ListCustomerStatementTaxesParams{
+		CustomerID: customerID,
+		StartDate:  start,
+		EndDate:    end,
+	})
+	if err != nil {
+		log.Printf("[RECEIPTS] Failed to list %d taxes of customer %s: %v", year, customerID, err)
+		return nil, errLib.New("Failed to build statement", http.StatusInternalServerError)
+	}
+
+	issuer, issuerErr := s.issuer(ctx)
+	if issuerErr != nil {
+		return nil, issuerErr
+	}
+
+	return renderStatement(issuer, year, transactions, taxes), nil
+}
+
+// issuer is the business named at the top of receipts and statements
+func (s *ReceiptService) issuer(ctx context.Context) (receiptIssuer, *errLib.CommonError) {
+	numbers, err := s.queries.ListRegistrationNumbers(ctx)
+	if err != nil {
+		log.Printf("[RECEIPTS] Failed to list tax registration numbers: %v", err)
+		return receiptIssuer{}, errLib.New("Failed to get tax registration numbers", http.StatusInternalServerError)
+	}
+
+	issuer := receiptIssuer{
+		Name:    config.Env.BusinessName,
+		Address: config.Env.BusinessAddress,
+	}
+	for _, number := range numbers {
+		issuer.Registrations = append(issuer.Registrations, number.Name+" "+number.RegistrationNumber)
+	}
+	return issuer, nil
+}
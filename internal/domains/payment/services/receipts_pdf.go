@@ -0,0 +1,299 @@
+package payment
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	db "api/internal/domains/payment/persistence/sqlc/generated"
+	"api/internal/libs/pdf"
+	"api/utils/timezone"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// receiptIssuer is the business printed at the top of receipts and statements
+type receiptIssuer struct {
+	Name          string
+	Address       string
+	Registrations []string // e.g. "GST 123456789RT0001"
+}
+
+const (
+	marginLeft   = 54.0
+	marginRight  = pdf.PageWidth - 54.0
+	marginTop    = pdf.PageHeight - 54.0
+	marginBottom = 72.0
+	lineHeight   = 16.0
+)
+
+// receiptAmounts splits a payment into the figures printed on its receipt. Tracked
+// amounts include tax, so tax is taken back out of the price and the subtotal.
+type receiptAmounts struct {
+	Price    decimal.Decimal // before discounts and subsidies, without tax
+	Discount decimal.Decimal
+	Subsidy  decimal.Decimal
+	Subtotal decimal.Decimal // what was taxed
+	Tax      decimal.Decimal
+	Total    decimal.Decimal // what the customer paid
+	Refunded decimal.Decimal
+}
+
+func amountsFor(transaction db.PaymentsPaymentTransaction, taxes []db.PaymentsTransactionTax) receiptAmounts {
+	tax := decimal.Zero
+	for _, t := range taxes {
+		tax = tax.Add(t.Amount)
+	}
+
+	return receiptAmounts{
+		Price:    transaction.OriginalAmount.Sub(tax),
+		Discount: transaction.DiscountAmount,
+		Subsidy:  transaction.SubsidyAmount,
+		Subtotal: transaction.CustomerPaid.Sub(tax),
+		Tax:      tax,
+		Total:    transaction.CustomerPaid,
+		Refunded: transaction.RefundedAmount,
+	}
+}
+
+func renderReceipt(issuer receiptIssuer, transaction db.PaymentsPaymentTransaction, taxes []db.PaymentsTransactionTax) []byte {
+	doc := pdf.New()
+	page := doc.AddPage()
+	y := drawIssuer(page, issuer)
+
+	page.Text(marginLeft, y, pdf.Bold, 16, "Receipt")
+	page.TextRight(marginRight, y, pdf.Regular, 10, "Receipt no. "+receiptNumber(transaction.ID))
+	y -= lineHeight
+	page.TextRight(marginRight, y, pdf.Regular, 10, "Date: "+formatDate(transaction.TransactionDate))
+	y -= lineHeight * 1.5
+
+	page.Text(marginLeft, y, pdf.Bold, 10, "Billed to")
+	y -= lineHeight
+	page.Text(marginLeft, y, pdf.Regular, 10, transaction.CustomerName)
+	y -= lineHeight
+	page.Text(marginLeft, y, pdf.Regular, 10, transaction.CustomerEmail)
+	y -= lineHeight * 2
+
+	page.Text(marginLeft, y, pdf.Bold, 10, "Description")
+	page.TextRight(marginRight, y, pdf.Bold, 10, "Amount")
+	y -= 6
+	page.Line(marginLeft, y, marginRight, y)
+	y -= lineHeight
+
+	amounts := amountsFor(transaction, taxes)
+	row := func(label string, amount string, font pdf.Font) {
+		page.Text(marginLeft, y, font, 10, fit(label, font, 10, marginRight-marginLeft-100))
+		page.TextRight(marginRight, y, font, 10, amount)
+		y -= lineHeight
+	}
+
+	row(describe(transaction), formatMoney(amounts.Price), pdf.Regular)
+	if amounts.Discount.IsPositive() {
+		row("Discount", "-"+formatMoney(amounts.Discount), pdf.Regular)
+	}
+	if amounts.Subsidy.IsPositive() {
+		row("Subsidy", "-"+formatMoney(amounts.Subsidy), pdf.Regular)
+	}
+
+	y += lineHeight - 6
+	page.Line(marginRight-200, y, marginRight, y)
+	y -= lineHeight
+
+	row("Subtotal", formatMoney(amounts.Subtotal), pdf.Regular)
+	for _, tax := range taxes {
+		row(taxLabel(tax), formatMoney(tax.Amount), pdf.Regular)
+	}
+	row("Total paid", formatMoney(amounts.Total), pdf.Bold)
+
+	if amounts.Refunded.IsPositive() {
+		row("Refunded", "-"+formatMoney(amounts.Refunded), pdf.Regular)
+		row("Net paid", formatMoney(amounts.Total.Sub(amounts.Refunded)), pdf.Bold)
+	}
+
+	y -= lineHeight
+	if transaction.PaymentMethod.Valid && transaction.PaymentMethod.String != "" {
+		page.Text(marginLeft, y, pdf.Regular, 9, "Paid by "+transaction.PaymentMethod.String)
+		y -= lineHeight
+	}
+	if len(taxes) == 0 {
+		page.Text(marginLeft, y, pdf.Regular, 9, "No sales tax was charged on this payment.")
+	}
+
+	return doc.Bytes()
+}
+
+func renderStatement(issuer receiptIssuer, year int, transactions []db.PaymentsPaymentTransaction, taxes []db.PaymentsTransactionTax) []byte {
+	doc := pdf.New()
+	page := doc.AddPage()
+	y := drawIssuer(page, issuer)
+
+	page.Text(marginLeft, y, pdf.Bold, 16, fmt.Sprintf("Annual statement %d", year))
+	y -= lineHeight * 1.5
+
+	if len(transactions) > 0 {
+		page.Text(marginLeft, y, pdf.Regular, 10, transactions[0].CustomerName)
+		y -= lineHeight
+		page.Text(marginLeft, y, pdf.Regular, 10, transactions[0].CustomerEmail)
+		y -= lineHeight * 2
+	}
+
+	const (
+		dateX        = marginLeft
+		descriptionX = marginLeft + 80
+		paidRight    = marginRight - 160
+		taxRight     = marginRight - 80
+		refundRight  = marginRight
+	)
+
+	header := func() {
+		page.Text(dateX, y, pdf.Bold, 9, "Date")
+		page.Text(descriptionX, y, pdf.Bold, 9, "Description")
+		page.TextRight(paidRight, y, pdf.Bold, 9, "Paid")
+		page.TextRight(taxRight, y, pdf.Bold, 9, "Tax")
+		page.TextRight(refundRight, y, pdf.Bold, 9, "Refunded")
+		y -= 6
+		page.Line(marginLeft, y, marginRight, y)
+		y -= lineHeight
+	}
+	newPageIfFull := func(needed float64) {
+		if y-needed < marginBottom {
+			page = doc.AddPage()
+			y = marginTop
+			header()
+		}
+	}
+
+	taxByTransaction := make(map[uuid.UUID]decimal.Decimal)
+	for _, tax := range taxes {
+		taxByTransaction[tax.TransactionID] = taxByTransaction[tax.TransactionID].Add(tax.Amount)
+	}
+
+	if len(transactions) == 0 {
+		page.Text(marginLeft, y, pdf.Regular, 10, fmt.Sprintf("No payments were made in %d.", year))
+		return doc.Bytes()
+	}
+
+	header()
+
+	totalPaid, totalTax, totalRefunded := decimal.Zero, decimal.Zero, decimal.Zero
+	for _, transaction := range transactions {
+		newPageIfFull(lineHeight)
+
+		tax := taxByTransaction[transaction.ID]
+		page.Text(dateX, y, pdf.Regular, 9, formatDate(transaction.TransactionDate))
+		page.Text(descriptionX, y, pdf.Regular, 9, fit(describe(transaction), pdf.Regular, 9, paidRight-descriptionX-70))
+		page.TextRight(paidRight, y, pdf.Regular, 9, formatMoney(transaction.CustomerPaid))
+		page.TextRight(taxRight, y, pdf.Regular, 9, formatMoney(tax))
+		if transaction.RefundedAmount.IsPositive() {
+			page.TextRight(refundRight, y, pdf.Regular, 9, formatMoney(transaction.RefundedAmount))
+		}
+		y -= lineHeight
+
+		totalPaid = totalPaid.Add(transaction.CustomerPaid)
+		totalTax = totalTax.Add(tax)
+		totalRefunded = totalRefunded.Add(transaction.RefundedAmount)
+	}
+
+	newPageIfFull(lineHeight * 2)
+	y += lineHeight - 6
+	page.Line(marginLeft, y, marginRight, y)
+	y -= lineHeight
+	page.Text(dateX, y, pdf.Bold, 9, "Total")
+	page.TextRight(paidRight, y, pdf.Bold, 9, formatMoney(totalPaid))
+	page.TextRight(taxRight, y, pdf.Bold, 9, formatMoney(totalTax))
+	page.TextRight(refundRight, y, pdf.Bold, 9, formatMoney(totalRefunded))
+	y -= lineHeight * 2
+
+	// Tax paid per tax, e.g. GST and PST separately, for the customer's own records
+	summary := make(map[string]decimal.Decimal)
+	var labels []string
+	for _, tax := range taxes {
+		label := taxLabel(tax)
+		if _, ok := summary[label]; !ok {
+			labels = append(labels, label)
+		}
+		summary[label] = summary[label].Add(tax.Amount)
+	}
+
+	if len(labels) > 0 {
+		newPageIfFull(lineHeight * float64(len(labels)+1))
+		page.Text(marginLeft, y, pdf.Bold, 10, "Sales tax paid")
+		y -= lineHeight
+		for _, label := range labels {
+			page.Text(marginLeft, y, pdf.Regular, 9, label)
+			page.TextRight(taxRight, y, pdf.Regular, 9, formatMoney(summary[label]))
+			y -= lineHeight
+		}
+	}
+
+	return doc.Bytes()
+}
+
+// drawIssuer prints the business name, address and tax registrations and returns
+// where the rest of the page starts.
+func drawIssuer(page *pdf.Page, issuer receiptIssuer) float64 {
+	y := marginTop
+	page.Text(marginLeft, y, pdf.Bold, 14, issuer.Name)
+	y -= lineHeight
+
+	for _, line := range strings.Split(issuer.Address, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			page.Text(marginLeft, y, pdf.Regular, 9, line)
+			y -= lineHeight * 0.8
+		}
+	}
+	for _, registration := range issuer.Registrations {
+		page.Text(marginLeft, y, pdf.Regular, 9, registration)
+		y -= lineHeight * 0.8
+	}
+
+	return y - lineHeight*1.5
+}
+
+func describe(transaction db.PaymentsPaymentTransaction) string {
+	if transaction.Description.Valid && strings.TrimSpace(transaction.Description.String) != "" {
+		return transaction.Description.String
+	}
+	kind := strings.ReplaceAll(transaction.TransactionType, "_", " ")
+	if kind == "" {
+		return "Payment"
+	}
+	return strings.ToUpper(kind[:1]) + kind[1:]
+}
+
+func taxLabel(tax db.PaymentsTransactionTax) string {
+	label := fmt.Sprintf("%s %s%%", tax.Name, tax.Rate.String())
+	if tax.Province != "" {
+		label += " (" + tax.Province + ")"
+	}
+	return label
+}
+
+// receiptNumber is a short, stable reference for a payment
+func receiptNumber(id uuid.UUID) string {
+	return strings.ToUpper(strings.ReplaceAll(id.String(), "-", "")[:12])
+}
+
+func formatDate(t time.Time) string {
+	return t.In(timezone.Default()).Format("Jan 2, 2006")
+}
+
+func formatMoney(amount decimal.Decimal) string {
+	if amount.IsNegative() {
+		return "-$" + amount.Neg().StringFixed(2)
+	}
+	return "$" + amount.StringFixed(2)
+}
+
+// fit shortens s with an ellipsis so it is at most width points wide
+func fit(s string, font pdf.Font, size float64, width float64) string {
+	if pdf.TextWidth(font, size, s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && pdf.TextWidth(font, size, string(runes)+"...") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return strings.TrimSpace(string(runes)) + "..."
+}
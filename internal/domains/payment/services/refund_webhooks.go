@@ -0,0 +1,144 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	errLib "api/internal/libs/errors"
+	"api/internal/libs/logger"
+
+	"github.com/stripe/stripe-go/v81"
+)
+
+// HandleChargeRefunded reconciles refunds made in the Stripe dashboard into the
+// payment's transaction. Access is left alone, so staff are alerted to review it.
+func (s *WebhookService) HandleChargeRefunded(ctx context.Context, event stripe.Event) *errLib.CommonError {
+	claimed, claimErr := s.Idempotency.TryClaimEvent(event.ID, string(event.Type))
+	if claimErr != nil {
+		log.Printf("[IDEMPOTENCY] DB error claiming event %s, failing closed: %v", event.ID, claimErr)
+		return errLib.New("Idempotency check unavailable, will retry", http.StatusInternalServerError)
+	}
+	if !claimed {
+		log.Printf("[REFUNDS] Event %s already claimed, skipping", event.ID)
+		return nil
+	}
+
+	var charge stripe.Charge
+	if err := json.Unmarshal(event.Data.Raw, &charge); err != nil {
+		log.Printf("[REFUNDS] Failed to parse charge.refunded: %v", err)
+		return errLib.New("Failed to parse charge", http.StatusBadRequest)
+	}
+
+	refund, payment, err := s.RefundsService.ReconcileChargeRefund(ctx, &charge)
+	if err != nil {
+		s.Idempotency.MarkEventFailed(event.ID, err.Error())
+		return err
+	}
+	s.Idempotency.MarkEventComplete(event.ID)
+
+	if refund == nil {
+		log.Printf("[REFUNDS] Charge %s refund already recorded or not a tracked payment", charge.ID)
+		return nil
+	}
+
+	log.Printf("[REFUNDS] Reconciled $%s refund of charge %s into transaction %s", refund.Amount.StringFixed(2), charge.ID, payment.ID)
+	if refund.Source == refundSourceSystem {
+		// The booking was never confirmed, so there is no access to review
+		return nil
+	}
+
+	logger.SendSlackAlertAsync("REFUND_RECONCILED",
+		fmt.Sprintf("$%s was refunded in Stripe for %s's %s payment. Access was not revoked; review it if needed.",
+			refund.Amount.StringFixed(2), payment.CustomerName, payment.TransactionType),
+		map[string]interface{}{
+			"Transaction ID": payment.ID.String(),
+			"Customer":       fmt.Sprintf("%s (%s)", payment.CustomerName, payment.CustomerEmail),
+			"Charge ID":      charge.ID,
+			"Total Refunded": "$" + payment.RefundedAmount.StringFixed(2),
+			"Payment Status": payment.PaymentStatus,
+		})
+
+	return nil
+}
+
+// HandleChargeDisputeCreated records a new dispute and alerts staff with the deadline
+// for submitting evidence in Stripe.
+func (s *WebhookService) HandleChargeDisputeCreated(ctx context.Context, event stripe.Event) *errLib.CommonError {
+	return s.handleDispute(ctx, event, false)
+}
+
+// HandleChargeDisputeClosed records a dispute's outcome and alerts staff.
+func (s *WebhookService) HandleChargeDisputeClosed(ctx context.Context, event stripe.Event) *errLib.CommonError {
+	return s.handleDispute(ctx, event, true)
+}
+
+func (s *WebhookService) handleDispute(ctx context.Context, event stripe.Event, closed bool) *errLib.CommonError {
+	claimed, claimErr := s.Idempotency.TryClaimEvent(event.ID, string(event.Type))
+	if claimErr != nil {
+		log.Printf("[IDEMPOTENCY] DB error claiming event %s, failing closed: %v", event.ID, claimErr)
+		return errLib.New("Idempotency check unavailable, will retry", http.StatusInternalServerError)
+	}
+	if !claimed {
+		log.Printf("[DISPUTES] Event %s already claimed, skipping", event.ID)
+		return nil
+	}
+
+	var dispute stripe.Dispute
+	if err := json.Unmarshal(event.Data.Raw, &dispute); err != nil {
+		log.Printf("[DISPUTES] Failed to parse %s: %v", event.Type, err)
+		return errLib.New("Failed to parse dispute", http.StatusBadRequest)
+	}
+
+	var closedAt *time.Time
+	if closed {
+		eventTime := time.Unix(event.Created, 0)
+		closedAt = &eventTime
+	}
+
+	row, payment, err := s.RefundsService.RecordDispute(ctx, &dispute, closedAt)
+	if err != nil {
+		s.Idempotency.MarkEventFailed(event.ID, err.Error())
+		return err
+	}
+	s.Idempotency.MarkEventComplete(event.ID)
+
+	log.Printf("[DISPUTES] Dispute %s recorded with status %s", dispute.ID, dispute.Status)
+
+	customer := "Unknown (payment not tracked)"
+	transactionID := "-"
+	if payment != nil {
+		customer = fmt.Sprintf("%s (%s)", payment.CustomerName, payment.CustomerEmail)
+		transactionID = payment.ID.String()
+	}
+
+	fields := map[string]interface{}{
+		"Dispute":     fmt.Sprintf("https://dashboard.stripe.com/disputes/%s", dispute.ID),
+		"Customer":    customer,
+		"Transaction": transactionID,
+		"Amount":      fmt.Sprintf("$%s %s", row.Amount.StringFixed(2), row.Currency),
+		"Reason":      row.Reason,
+		"Status":      row.Status,
+	}
+
+	if closed {
+		logger.SendSlackAlertAsync("DISPUTE_CLOSED",
+			fmt.Sprintf("Dispute of $%s closed with status %s.", row.Amount.StringFixed(2), row.Status), fields)
+		return nil
+	}
+
+	deadline := "Not provided"
+	if row.EvidenceDueBy.Valid {
+		deadline = row.EvidenceDueBy.Time.UTC().Format("Mon Jan 2 2006 15:04 MST")
+	}
+	fields["Evidence Due By"] = deadline
+
+	logger.SendSlackAlertAsync("DISPUTE_OPENED",
+		fmt.Sprintf("A customer disputed a $%s payment. Submit evidence in Stripe before %s or the dispute is lost.",
+			row.Amount.StringFixed(2), deadline), fields)
+
+	return nil
+}
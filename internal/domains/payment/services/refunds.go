@@ -0,0 +1,470 @@
+package payment
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"api/internal/di"
+	staffActivityLogs "api/internal/domains/audit/staff_activity_logs/service"
+	courtRentalService "api/internal/domains/court_rental/service"
+	enrollmentRepo "api/internal/domains/enrollment/persistence/repository"
+	db "api/internal/domains/payment/persistence/sqlc/generated"
+	stripeService "api/internal/domains/payment/services/stripe"
+	"api/internal/domains/payment/tracking"
+	playgroundService "api/internal/domains/playground/services"
+	errLib "api/internal/libs/errors"
+	contextUtils "api/utils/context"
+	txUtils "api/utils/db"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stripe/stripe-go/v81"
+)
+
+// RefundsService refunds tracked payments through Stripe and, per the refund policy,
+// takes back what the payment bought.
+type RefundsService struct {
+	queries                  *db.Queries
+	db                       *sql.DB
+	paymentTracking          *tracking.PaymentTrackingService
+	enrollmentRepo           *enrollmentRepo.CustomerEnrollmentRepository
+	subscriptionService      *stripeService.SubscriptionService
+	courtRentalService       *courtRentalService.Service
+	playgroundService        *playgroundService.Service
+	staffActivityLogsService *staffActivityLogs.Service
+}
+
+func NewRefundsService(container *di.Container) *RefundsService {
+	return &RefundsService{
+		queries:                  db.New(container.DB),
+		db:                       container.DB,
+		paymentTracking:          tracking.NewPaymentTrackingService(container),
+		enrollmentRepo:           enrollmentRepo.NewEnrollmentRepository(container),
+		subscriptionService:      stripeService.NewSubscriptionService(container),
+		courtRentalService:       courtRentalService.NewService(container),
+		playgroundService:        playgroundService.NewService(container),
+		staffActivityLogsService: staffActivityLogs.NewService(container),
+	}
+}
+
+// RefundRequest represents a staff refund of a tracked payment
+type RefundRequest struct {
+	Amount       *float64 `json:"amount,omitempty"`        // Omit to refund everything not yet refunded
+	Reason       string   `json:"reason"`                  // Required, shown in reports and the staff activity log
+	RevokeAccess *bool    `json:"revoke_access,omitempty"` // Defaults to true for full refunds, false for partial ones
+}
+
+// RefundResult is the outcome of a refund. RevocationError is set when the money was
+// refunded but the enrollment, membership or booking could not be revoked.
+type RefundResult struct {
+	Refund          db.PaymentsRefund
+	Transaction     db.PaymentsPaymentTransaction
+	RevocationError string
+}
+
+// RefundTransaction refunds all or part of a payment. The transaction row stays locked
+// while Stripe is called so two staff cannot refund the same money twice, and the
+// idempotency key makes a retried request return the same Stripe refund. If Stripe
+// refunds but recording fails, the charge.refunded webhook reconciles it.
+func (s *RefundsService) RefundTransaction(ctx context.Context, transactionID uuid.UUID, req RefundRequest) (*RefundResult, *errLib.CommonError) {
+	staffID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, errLib.New("reason is required", http.StatusBadRequest)
+	}
+
+	var result RefundResult
+	var revoke bool
+
+	err = txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		q := s.queries.WithTx(tx)
+
+		payment, dbErr := q.GetPaymentTransactionForUpdate(ctx, transactionID)
+		if dbErr != nil {
+			if errors.Is(dbErr, sql.ErrNoRows) {
+				return errLib.New("Payment transaction not found", http.StatusNotFound)
+			}
+			log.Printf("[REFUNDS] Failed to lock transaction %s: %v", transactionID, dbErr)
+			return errLib.New("Failed to load payment transaction", http.StatusInternalServerError)
+		}
+
+		if payment.PaymentStatus != "completed" && payment.PaymentStatus != "partially_refunded" {
+			return errLib.New(fmt.Sprintf("Only completed payments can be refunded (status: %s)", payment.PaymentStatus), http.StatusConflict)
+		}
+
+		cents, full, err := refundAmountCents(payment.CustomerPaid, payment.RefundedAmount, req.Amount)
+		if err != nil {
+			return err
+		}
+		revoke = revokesAccess(full, req.RevokeAccess)
+
+		paymentIntentID := payment.StripePaymentIntentID.String
+		if paymentIntentID == "" {
+			if paymentIntentID, err = stripeService.FindPaymentIntentID(payment.StripeCheckoutSessionID.String, payment.StripeInvoiceID.String); err != nil {
+				return err
+			}
+		}
+
+		// Keyed on what was already refunded, so a retry cannot refund twice but a later
+		// partial refund of the same amount still goes through
+		key := fmt.Sprintf("%s:%s:%d", payment.ID, payment.RefundedAmount.StringFixed(2), cents)
+		stripeRefund, err := stripeService.CreateRefund(ctx, paymentIntentID, cents, reason, map[string]string{
+			"transactionID": payment.ID.String(),
+			"issuedBy":      staffID.String(),
+		}, key)
+		if err != nil {
+			return err
+		}
+
+		amount := decimal.New(cents, -2)
+		refund, dbErr := q.CreateRefund(ctx, db.CreateRefundParams{
+			TransactionID:  payment.ID,
+			StripeRefundID: sql.NullString{String: stripeRefund.ID, Valid: true},
+			Amount:         amount,
+			Reason:         sql.NullString{String: reason, Valid: true},
+			Source:         "admin",
+			IssuedBy:       uuid.NullUUID{UUID: staffID, Valid: true},
+		})
+		if dbErr != nil {
+			log.Printf("[REFUNDS] Stripe refund %s issued but not recorded for transaction %s: %v", stripeRefund.ID, payment.ID, dbErr)
+			return errLib.New("Refund was issued in Stripe but could not be recorded; it will be reconciled from Stripe", http.StatusInternalServerError)
+		}
+
+		total, _ := payment.RefundedAmount.Add(amount).Float64()
+		if trackErr := s.paymentTracking.WithTx(tx).RecordRefund(ctx, payment.ID, total, reason); trackErr != nil {
+			return errLib.New("Failed to record refund", http.StatusInternalServerError)
+		}
+
+		updated, dbErr := q.GetPaymentTransaction(ctx, payment.ID)
+		if dbErr != nil {
+			log.Printf("[REFUNDS] Failed to reload transaction %s: %v", payment.ID, dbErr)
+			return errLib.New("Failed to load payment transaction", http.StatusInternalServerError)
+		}
+		result = RefundResult{Refund: refund, Transaction: updated}
+
+		return s.staffActivityLogsService.InsertStaffActivity(ctx, tx, staffID,
+			fmt.Sprintf("Refunded $%s of %s's %s payment: %s", amount.StringFixed(2), payment.CustomerName,
+				strings.ReplaceAll(payment.TransactionType, "_", " "), reason))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[REFUNDS] Staff %s refunded $%s of transaction %s (revoke access: %v)",
+		staffID, result.Refund.Amount.StringFixed(2), transactionID, revoke)
+
+	if !revoke {
+		return &result, nil
+	}
+
+	if err = s.revokeAccess(ctx, result.Transaction); err != nil {
+		log.Printf("[REFUNDS] Refunded transaction %s but could not revoke access: %s", transactionID, err.Message)
+		result.RevocationError = err.Message
+		return &result, nil
+	}
+
+	if dbErr := s.queries.MarkRefundAccessRevoked(ctx, result.Refund.ID); dbErr != nil {
+		log.Printf("[REFUNDS] Failed to mark refund %s as revoked: %v", result.Refund.ID, dbErr)
+	}
+	result.Refund.AccessRevoked = true
+
+	return &result, nil
+}
+
+// revokeAccess takes back what a payment bought. Credit packages are never revoked
+// automatically since their credits may already be spent; staff adjust them by hand.
+func (s *RefundsService) revokeAccess(ctx context.Context, payment db.PaymentsPaymentTransaction) *errLib.CommonError {
+	switch payment.TransactionType {
+	case "program_enrollment":
+		if !payment.ProgramID.Valid {
+			return errLib.New("Payment is not linked to a program", http.StatusUnprocessableEntity)
+		}
+		return s.enrollmentRepo.UnEnrollCustomerFromProgram(ctx, payment.ProgramID.UUID, payment.CustomerID)
+
+	case "event_registration":
+		if !payment.EventID.Valid {
+			return errLib.New("Payment is not linked to an event", http.StatusUnprocessableEntity)
+		}
+		return s.enrollmentRepo.UnEnrollCustomerFromEvent(ctx, payment.EventID.UUID, payment.CustomerID)
+
+	case "membership_subscription", "membership_renewal":
+		if !payment.StripeSubscriptionID.Valid {
+			return errLib.New("Payment is not linked to a subscription", http.StatusUnprocessableEntity)
+		}
+		subscriptionID := payment.StripeSubscriptionID.String
+		if _, err := s.subscriptionService.AdminCancelSubscription(ctx, subscriptionID, true); err != nil && err.HTTPCode != http.StatusConflict {
+			return err
+		}
+		return s.enrollmentRepo.UpdateStripeSubscriptionStatusByID(ctx, payment.CustomerID, subscriptionID, "canceled")
+
+	case "court_rental":
+		rentalID, err := metadataID(payment, "courtRentalID")
+		if err != nil {
+			return err
+		}
+		return s.courtRentalService.CancelRental(ctx, rentalID)
+
+	case "playground_session":
+		sessionID, err := metadataID(payment, "playgroundSessionID")
+		if err != nil {
+			return err
+		}
+		return s.playgroundService.CancelSession(ctx, sessionID)
+
+	default:
+		return errLib.New(fmt.Sprintf("Access bought with a %s payment cannot be revoked automatically",
+			strings.ReplaceAll(payment.TransactionType, "_", " ")), http.StatusUnprocessableEntity)
+	}
+}
+
+// ReconcileChargeRefund records refunds made outside the refund API, such as in the
+// Stripe dashboard, and booking refunds that were issued but not recorded. Stripe reports the charge's running refunded total, so only the
+// part not yet recorded is added; refunds issued through the API are already counted.
+// It returns nil when the charge is not a tracked payment or nothing new was refunded.
+func (s *RefundsService) ReconcileChargeRefund(ctx context.Context, charge *stripe.Charge) (*db.PaymentsRefund, *db.PaymentsPaymentTransaction, *errLib.CommonError) {
+	payment, err := s.findTransaction(ctx, chargePaymentIntentID(charge), chargeInvoiceID(charge))
+	if err != nil || payment == nil {
+		return nil, nil, err
+	}
+
+	var refund *db.PaymentsRefund
+	var updated db.PaymentsPaymentTransaction
+
+	err = txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		q := s.queries.WithTx(tx)
+
+		locked, dbErr := q.GetPaymentTransactionForUpdate(ctx, payment.ID)
+		if dbErr != nil {
+			log.Printf("[REFUNDS] Failed to lock transaction %s: %v", payment.ID, dbErr)
+			return errLib.New("Failed to load payment transaction", http.StatusInternalServerError)
+		}
+
+		total := decimal.New(charge.AmountRefunded, -2)
+		unrecorded := total.Sub(locked.RefundedAmount)
+		if !unrecorded.IsPositive() {
+			return nil
+		}
+
+		reason := "Refunded in Stripe"
+		source := "stripe"
+		var stripeRefundID sql.NullString
+		if charge.Refunds != nil && len(charge.Refunds.Data) > 0 {
+			latest := charge.Refunds.Data[0]
+			if decimal.New(latest.Amount, -2).Equal(unrecorded) {
+				stripeRefundID = sql.NullString{String: latest.ID, Valid: true}
+				if latest.Metadata["reason"] != "" {
+					reason = latest.Metadata["reason"]
+				}
+				// A booking refund the server issued but could not record
+				if latest.Metadata["bookingType"] != "" {
+					source = refundSourceSystem
+				}
+			}
+		}
+
+		row, dbErr := q.CreateRefund(ctx, db.CreateRefundParams{
+			TransactionID:  locked.ID,
+			StripeRefundID: stripeRefundID,
+			Amount:         unrecorded,
+			Reason:         sql.NullString{String: reason, Valid: true},
+			Source:         source,
+		})
+		if dbErr != nil {
+			log.Printf("[REFUNDS] Failed to record Stripe refund for transaction %s: %v", locked.ID, dbErr)
+			return errLib.New("Failed to record refund", http.StatusInternalServerError)
+		}
+
+		totalFloat, _ := total.Float64()
+		if trackErr := s.paymentTracking.WithTx(tx).RecordRefund(ctx, locked.ID, totalFloat, reason); trackErr != nil {
+			return errLib.New("Failed to record refund", http.StatusInternalServerError)
+		}
+
+		if updated, dbErr = q.GetPaymentTransaction(ctx, locked.ID); dbErr != nil {
+			log.Printf("[REFUNDS] Failed to reload transaction %s: %v", locked.ID, dbErr)
+			return errLib.New("Failed to load payment transaction", http.StatusInternalServerError)
+		}
+		refund = &row
+		return nil
+	})
+	if err != nil || refund == nil {
+		return nil, nil, err
+	}
+	return refund, &updated, nil
+}
+
+// RecordDispute creates or updates a dispute from Stripe. closedAt is set once Stripe
+// reports the dispute closed.
+func (s *RefundsService) RecordDispute(ctx context.Context, dispute *stripe.Dispute, closedAt *time.Time) (db.PaymentsDispute, *db.PaymentsPaymentTransaction, *errLib.CommonError) {
+	var paymentIntentID, chargeID, invoiceID string
+	if dispute.PaymentIntent != nil {
+		paymentIntentID = dispute.PaymentIntent.ID
+	}
+	if dispute.Charge != nil {
+		chargeID = dispute.Charge.ID
+		invoiceID = chargeInvoiceID(dispute.Charge)
+	}
+
+	payment, err := s.findTransaction(ctx, paymentIntentID, invoiceID)
+	if err != nil {
+		return db.PaymentsDispute{}, nil, err
+	}
+
+	params := db.UpsertDisputeParams{
+		StripeDisputeID:       dispute.ID,
+		StripeChargeID:        chargeID,
+		StripePaymentIntentID: sql.NullString{String: paymentIntentID, Valid: paymentIntentID != ""},
+		Amount:                decimal.New(dispute.Amount, -2),
+		Currency:              string(dispute.Currency),
+		Reason:                string(dispute.Reason),
+		Status:                string(dispute.Status),
+	}
+	if payment != nil {
+		params.TransactionID = uuid.NullUUID{UUID: payment.ID, Valid: true}
+	}
+	if dispute.EvidenceDetails != nil && dispute.EvidenceDetails.DueBy > 0 {
+		params.EvidenceDueBy = sql.NullTime{Time: time.Unix(dispute.EvidenceDetails.DueBy, 0), Valid: true}
+	}
+	if closedAt != nil {
+		params.ClosedAt = sql.NullTime{Time: *closedAt, Valid: true}
+	}
+
+	row, dbErr := s.queries.UpsertDispute(ctx, params)
+	if dbErr != nil {
+		log.Printf("[REFUNDS] Failed to record dispute %s: %v", dispute.ID, dbErr)
+		return db.PaymentsDispute{}, nil, errLib.New("Failed to record dispute", http.StatusInternalServerError)
+	}
+	return row, payment, nil
+}
+
+// findTransaction finds the tracked payment behind a Stripe payment intent or invoice.
+// Checkout payments only store their session, so that is looked up as a last resort.
+func (s *RefundsService) findTransaction(ctx context.Context, paymentIntentID, invoiceID string) (*db.PaymentsPaymentTransaction, *errLib.CommonError) {
+	lookups := []func() (db.PaymentsPaymentTransaction, error){}
+	if paymentIntentID != "" {
+		lookups = append(lookups, func() (db.PaymentsPaymentTransaction, error) {
+			return s.queries.GetPaymentTransactionByStripePaymentIntent(ctx, sql.NullString{String: paymentIntentID, Valid: true})
+		})
+	}
+	if invoiceID != "" {
+		lookups = append(lookups, func() (db.PaymentsPaymentTransaction, error) {
+			return s.queries.GetPaymentTransactionByStripeInvoice(ctx, sql.NullString{String: invoiceID, Valid: true})
+		})
+	}
+	if paymentIntentID != "" {
+		lookups = append(lookups, func() (db.PaymentsPaymentTransaction, error) {
+			sessionID, err := stripeService.FindCheckoutSessionID(paymentIntentID)
+			if err != nil {
+				return db.PaymentsPaymentTransaction{}, err
+			}
+			if sessionID == "" {
+				return db.PaymentsPaymentTransaction{}, sql.ErrNoRows
+			}
+			return s.queries.GetPaymentTransactionByStripeCheckoutSession(ctx, sql.NullString{String: sessionID, Valid: true})
+		})
+	}
+
+	for _, lookup := range lookups {
+		payment, err := lookup()
+		if err == nil {
+			return &payment, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("[REFUNDS] Failed to look up transaction for payment intent %q, invoice %q: %v", paymentIntentID, invoiceID, err)
+			return nil, errLib.New("Failed to look up payment transaction", http.StatusInternalServerError)
+		}
+	}
+	return nil, nil
+}
+
+// ListTransactionRefunds lists the refunds of a payment, oldest first
+func (s *RefundsService) ListTransactionRefunds(ctx context.Context, transactionID uuid.UUID) ([]db.PaymentsRefund, *errLib.CommonError) {
+	refunds, err := s.queries.ListTransactionRefunds(ctx, transactionID)
+	if err != nil {
+		log.Printf("[REFUNDS] Failed to list refunds for transaction %s: %v", transactionID, err)
+		return nil, errLib.New("Failed to list refunds", http.StatusInternalServerError)
+	}
+	return refunds, nil
+}
+
+// ListDisputes lists disputes by evidence deadline, optionally only those still open
+func (s *RefundsService) ListDisputes(ctx context.Context, openOnly bool) ([]db.PaymentsDispute, *errLib.CommonError) {
+	disputes, err := s.queries.ListDisputes(ctx, openOnly)
+	if err != nil {
+		log.Printf("[REFUNDS] Failed to list disputes: %v", err)
+		return nil, errLib.New("Failed to list disputes", http.StatusInternalServerError)
+	}
+	return disputes, nil
+}
+
+// refundAmountCents works out how much to refund and whether that refunds the payment
+// in full. A nil request refunds everything not yet refunded.
+func refundAmountCents(paid, refunded decimal.Decimal, requested *float64) (int64, bool, *errLib.CommonError) {
+	hundred := decimal.NewFromInt(100)
+
+	remaining := paid.Sub(refunded).Mul(hundred).Round(0).IntPart()
+	if remaining <= 0 {
+		return 0, false, errLib.New("This payment has already been fully refunded", http.StatusConflict)
+	}
+	if requested == nil {
+		return remaining, true, nil
+	}
+
+	amount := decimal.NewFromFloat(*requested).Mul(hundred).Round(0).IntPart()
+	if amount <= 0 {
+		return 0, false, errLib.New("amount must be greater than 0", http.StatusBadRequest)
+	}
+	if amount > remaining {
+		return 0, false, errLib.New(fmt.Sprintf("amount cannot exceed the $%s left to refund",
+			decimal.New(remaining, -2).StringFixed(2)), http.StatusBadRequest)
+	}
+	return amount, amount == remaining, nil
+}
+
+// revokesAccess applies the refund policy: a full refund takes back what was bought
+// unless staff say otherwise, a partial refund only when they ask for it.
+func revokesAccess(full bool, override *bool) bool {
+	if override != nil {
+		return *override
+	}
+	return full
+}
+
+// metadataID reads a booking ID stored in a transaction's metadata
+func metadataID(payment db.PaymentsPaymentTransaction, key string) (uuid.UUID, *errLib.CommonError) {
+	var metadata map[string]interface{}
+	if payment.Metadata.Valid {
+		_ = json.Unmarshal(payment.Metadata.RawMessage, &metadata)
+	}
+
+	raw, _ := metadata[key].(string)
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.Nil, errLib.New("Payment is not linked to a booking", http.StatusUnprocessableEntity)
+	}
+	return id, nil
+}
+
+func chargePaymentIntentID(charge *stripe.Charge) string {
+	if charge.PaymentIntent == nil {
+		return ""
+	}
+	return charge.PaymentIntent.ID
+}
+
+func chargeInvoiceID(charge *stripe.Charge) string {
+	if charge.Invoice == nil {
+		return ""
+	}
+	return charge.Invoice.ID
+}
@@ -0,0 +1,62 @@
+package payment
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefundAmountCents(t *testing.T) {
+	paid := decimal.RequireFromString("120.00")
+	amount := func(v float64) *float64 { return &v }
+
+	t.Run("Refunds what is left by default", func(t *testing.T) {
+		cents, full, err := refundAmountCents(paid, decimal.RequireFromString("20.50"), nil)
+
+		require.Nil(t, err)
+		assert.Equal(t, int64(9950), cents)
+		assert.True(t, full)
+	})
+
+	t.Run("Partial refund", func(t *testing.T) {
+		cents, full, err := refundAmountCents(paid, decimal.Zero, amount(19.99))
+
+		require.Nil(t, err)
+		assert.Equal(t, int64(1999), cents)
+		assert.False(t, full)
+	})
+
+	t.Run("Refunding the rest counts as full", func(t *testing.T) {
+		cents, full, err := refundAmountCents(paid, decimal.RequireFromString("100.00"), amount(20))
+
+		require.Nil(t, err)
+		assert.Equal(t, int64(2000), cents)
+		assert.True(t, full)
+	})
+
+	t.Run("Rejects more than is left", func(t *testing.T) {
+		_, _, err := refundAmountCents(paid, decimal.RequireFromString("100.00"), amount(20.01))
+
+		require.NotNil(t, err)
+		assert.Equal(t, http.StatusBadRequest, err.HTTPCode)
+	})
+
+	t.Run("Rejects an already refunded payment", func(t *testing.T) {
+		_, _, err := refundAmountCents(paid, paid, nil)
+
+		require.NotNil(t, err)
+		assert.Equal(t, http.StatusConflict, err.HTTPCode)
+	})
+}
+
+func TestRevokesAccess(t *testing.T) {
+	yes, no := true, false
+
+	assert.True(t, revokesAccess(true, nil))
+	assert.False(t, revokesAccess(false, nil))
+	assert.False(t, revokesAccess(true, &no))
+	assert.True(t, revokesAccess(false, &yes))
+}
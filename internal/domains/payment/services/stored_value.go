@@ -0,0 +1,94 @@
+package payment
+
+import (
+	"context"
+	"log"
+
+	discountValues "api/internal/domains/discount/values"
+	giftCardValues "api/internal/domains/gift_card/values"
+	"api/internal/domains/payment/services/stripe"
+	errLib "api/internal/libs/errors"
+
+	"github.com/google/uuid"
+)
+
+// balanceCheckoutCoupon returns the Stripe coupon for a one-time item's discounts, like
+// checkoutCoupon, and when useBalance is set also holds as much of the customer's gift
+// card balance as covers the rest of the price. Stripe takes one coupon per session, so
+// a hold gets a single coupon worth the discounts and the balance. The caller records
+// the hold in the session metadata and releases it if the checkout cannot be started.
+func (s *Service) balanceCheckoutCoupon(ctx context.Context, customerID uuid.UUID, item discountValues.PriceItem, stripePriceID string, discountCode *string, metadata map[string]string, useBalance bool, description string) (*string, giftCardValues.Hold, *errLib.CommonError) {
+	if !useBalance {
+		couponID, err := s.checkoutCoupon(ctx, item, stripePriceID, discountCode, metadata)
+		return couponID, giftCardValues.Hold{}, err
+	}
+
+	quote, err := s.quoteItem(ctx, item, stripePriceID, discountCode)
+	if err != nil {
+		return nil, giftCardValues.Hold{}, err
+	}
+
+	hold, err := s.GiftCardService.HoldBalance(ctx, customerID, int32(quote.Total), description)
+	if err != nil {
+		return nil, giftCardValues.Hold{}, err
+	}
+	if hold.ID == uuid.Nil {
+		couponID, err := s.quoteCoupon(ctx, item, quote, metadata)
+		return couponID, hold, err
+	}
+
+	recordQuoteDiscounts(quote, metadata)
+	couponID, err := stripe.CreateStoredValueCoupon(ctx, quote.DiscountTotal+int64(hold.AmountCents), quote.Currency, hold.ExpiresAt)
+	if err != nil {
+		s.releaseBalanceHold(ctx, hold)
+		return nil, giftCardValues.Hold{}, err
+	}
+	return &couponID, hold, nil
+}
+
+// holdJoiningFeeBalance holds as much of the customer's gift card balance as covers a
+// membership's joining fee. Only joining fees that are a Stripe price can be paid from
+// the balance.
+func (s *Service) holdJoiningFeeBalance(ctx context.Context, customerID uuid.UUID, joiningFeePriceID string) (giftCardValues.Hold, *errLib.CommonError) {
+	if joiningFeePriceID == "" {
+		return giftCardValues.Hold{}, nil
+	}
+
+	fee, err := stripe.NewPriceService().GetPrice(joiningFeePriceID)
+	if err != nil {
+		return giftCardValues.Hold{}, err
+	}
+	return s.GiftCardService.HoldBalance(ctx, customerID, int32(fee.UnitAmount), "Membership joining fee")
+}
+
+// holdMetadata is the session metadata recording a balance hold, or nil without one.
+func holdMetadata(hold giftCardValues.Hold) map[string]string {
+	if hold.ID == uuid.Nil {
+		return nil
+	}
+	return map[string]string{stripe.StoredValueHoldMetadataKey: hold.ID.String()}
+}
+
+// releaseBalanceHold returns balance held for a checkout that could not be started. The
+// hold expires on its own if this fails.
+func (s *Service) releaseBalanceHold(ctx context.Context, hold giftCardValues.Hold) {
+	if hold.ID == uuid.Nil {
+		return
+	}
+	if err := s.GiftCardService.ReleaseHold(ctx, hold.ID); err != nil {
+		log.Printf("Failed to release gift card balance hold %s after checkout error: %v", hold.ID, err)
+	}
+}
+
+// CheckoutGiftCard creates a pending gift card and returns the Stripe payment link for it.
+// The webhook issues the card and emails its code once the payment completes; cards
+// never paid for are deleted by the gift card job.
+func (s *Service) CheckoutGiftCard(ctx context.Context, req giftCardValues.PurchaseRequest, successURL string, cancelURL string) (string, *errLib.CommonError) {
+	card, err := s.GiftCardService.CreatePurchase(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	existingCustomerID := s.getExistingStripeCustomerID(ctx, req.PurchaserID)
+	return stripe.CreateGiftCardPayment(ctx, card.ID, "Rise gift card", int64(card.InitialCents), card.Currency, successURL, cancelURL, existingCustomerID)
+}
@@ -0,0 +1,66 @@
+package stripe
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+
+	errLib "api/internal/libs/errors"
+
+	"github.com/stripe/stripe-go/v81"
+	"github.com/stripe/stripe-go/v81/coupon"
+)
+
+// automaticDiscountCouponCache caches automatic discount coupon IDs by amount, currency
+// and duration, so abandoned checkouts reuse coupons instead of leaking new ones.
+var automaticDiscountCouponCache sync.Map
+
+// CreateAutomaticDiscountCoupon returns a fixed-amount coupon for the automatic discount
+// rules a customer qualifies for at checkout. Recurring coupons keep applying to every
+// renewal of a subscription; the others apply once. amountOff is in cents.
+func CreateAutomaticDiscountCoupon(ctx context.Context, amountOff int64, currency string, recurring bool) (string, *errLib.CommonError) {
+	if amountOff <= 0 {
+		return "", errLib.New("Discount amount must be positive", http.StatusBadRequest)
+	}
+	if strings.TrimSpace(currency) == "" {
+		currency = "cad"
+	}
+	currency = strings.ToLower(currency)
+
+	duration := stripe.CouponDurationOnce
+	if recurring {
+		duration = stripe.CouponDurationForever
+	}
+	cacheKey := fmt.Sprintf("%d:%s:%s", amountOff, currency, duration)
+
+	if cached, ok := automaticDiscountCouponCache.Load(cacheKey); ok {
+		couponID := cached.(string)
+		// Verify it still exists in Stripe (may have been deleted)
+		if _, getErr := coupon.Get(couponID, nil); getErr == nil {
+			return couponID, nil
+		}
+		automaticDiscountCouponCache.Delete(cacheKey)
+		log.Printf("[DISCOUNT] Cached coupon %s no longer valid, creating new one", couponID)
+	}
+
+	couponParams := &stripe.CouponParams{
+		Duration:  stripe.String(string(duration)),
+		AmountOff: stripe.Int64(amountOff),
+		Currency:  stripe.String(currency),
+		Name:      stripe.String(fmt.Sprintf("Automatic discount: $%.2f", float64(amountOff)/100)),
+	}
+
+	c, err := coupon.New(couponParams)
+	if err != nil {
+		log.Printf("[DISCOUNT] Failed to create automatic discount coupon: %v", err)
+		status, msg := classifyStripeError(err)
+		return "", errLib.New("Failed to create discount coupon: "+msg, status)
+	}
+
+	automaticDiscountCouponCache.Store(cacheKey, c.ID)
+	log.Printf("[DISCOUNT] Created and cached Stripe coupon %s for automatic discount of $%.2f (%s)", c.ID, float64(amountOff)/100, duration)
+	return c.ID, nil
+}
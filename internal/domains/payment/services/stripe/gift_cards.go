@@ -0,0 +1,176 @@
+package stripe
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	errLib "api/internal/libs/errors"
+	contextUtils "api/utils/context"
+
+	"github.com/google/uuid"
+	"github.com/stripe/stripe-go/v81"
+	"github.com/stripe/stripe-go/v81/checkout/session"
+	"github.com/stripe/stripe-go/v81/coupon"
+)
+
+// StoredValueHoldMetadataKey is the checkout session metadata key holding the ID of the
+// gift card balance hold the webhook captures once the checkout is paid.
+const StoredValueHoldMetadataKey = "storedValueHoldID"
+
+// CreateGiftCardPayment creates a Stripe Checkout Session for a gift card bought online.
+// Stored value is taxed when it is spent, not when it is sold, so no tax is charged.
+// The card's ID is stored in the metadata for the webhook to issue it.
+func CreateGiftCardPayment(
+	ctx context.Context,
+	giftCardID uuid.UUID,
+	description string, // Line item name shown on the checkout page
+	amountCents int64,
+	currency string,
+	successURL string,
+	cancelURL string,
+	existingCustomerID *string,
+) (string, *errLib.CommonError) {
+	timeoutCtx, cancel := withCriticalTimeout(ctx)
+	defer cancel()
+
+	if strings.ReplaceAll(stripe.Key, " ", "") == "" {
+		return "", errLib.New("Stripe not initialized", http.StatusInternalServerError)
+	}
+
+	if amountCents <= 0 {
+		return "", errLib.New("amount must be positive", http.StatusBadRequest)
+	}
+
+	if successURL == "" || cancelURL == "" {
+		return "", errLib.New("success and cancel URLs cannot be empty", http.StatusBadRequest)
+	}
+
+	userID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	metadata := map[string]string{
+		"userID":     userID.String(),
+		"giftCardID": giftCardID.String(),
+	}
+
+	params := &stripe.CheckoutSessionParams{
+		Metadata: metadata,
+		PaymentIntentData: &stripe.CheckoutSessionPaymentIntentDataParams{
+			Metadata: metadata,
+		},
+		LineItems: []*stripe.CheckoutSessionLineItemParams{
+			{
+				PriceData: &stripe.CheckoutSessionLineItemPriceDataParams{
+					Currency:   stripe.String(currency),
+					UnitAmount: stripe.Int64(amountCents),
+					ProductData: &stripe.CheckoutSessionLineItemPriceDataProductDataParams{
+						Name: stripe.String(description),
+					},
+				},
+				Quantity: stripe.Int64(1),
+			},
+		},
+		Mode:       stripe.String("payment"),
+		SuccessURL: stripe.String(successURL),
+		CancelURL:  stripe.String(cancelURL),
+	}
+
+	if existingCustomerID != nil && *existingCustomerID != "" {
+		params.Customer = stripe.String(*existingCustomerID)
+	}
+
+	// One session per purchase; a retried request gets the same session back
+	params.IdempotencyKey = idempotencyKey("checkout-gift-card", giftCardID.String())
+
+	type sessionResult struct {
+		session *stripe.CheckoutSession
+		err     error
+	}
+
+	resultChan := make(chan sessionResult, 1)
+
+	go func() {
+		s, err := session.New(params)
+		resultChan <- sessionResult{session: s, err: err}
+	}()
+
+	select {
+	case <-timeoutCtx.Done():
+		if timeoutCtx.Err() == context.DeadlineExceeded {
+			return "", errLib.New("Stripe API timeout while creating payment", http.StatusRequestTimeout)
+		}
+		return "", errLib.New("Request cancelled during payment creation", http.StatusRequestTimeout)
+	case result := <-resultChan:
+		if result.err != nil {
+			return "", errLib.New("Payment session failed: "+result.err.Error(), http.StatusInternalServerError)
+		}
+		return result.session.URL, nil
+	}
+}
+
+// CreateStoredValueCoupon returns a single-use coupon taking gift card balance, plus any
+// discount it replaces, off a one-time checkout. Stripe applies one coupon per session,
+// so the two are combined. The coupon stops working when the balance hold expires.
+// amountOff is in cents.
+func CreateStoredValueCoupon(ctx context.Context, amountOff int64, currency string, redeemBy time.Time) (string, *errLib.CommonError) {
+	if amountOff <= 0 {
+		return "", errLib.New("Coupon amount must be positive", http.StatusBadRequest)
+	}
+	if strings.TrimSpace(currency) == "" {
+		currency = "cad"
+	}
+
+	c, err := coupon.New(&stripe.CouponParams{
+		Duration:       stripe.String(string(stripe.CouponDurationOnce)),
+		AmountOff:      stripe.Int64(amountOff),
+		Currency:       stripe.String(strings.ToLower(currency)),
+		MaxRedemptions: stripe.Int64(1),
+		RedeemBy:       stripe.Int64(redeemBy.Unix()),
+		Name:           stripe.String(fmt.Sprintf("Gift card balance: $%.2f", float64(amountOff)/100)),
+	})
+	if err != nil {
+		log.Printf("[GIFT-CARD] Failed to create stored value coupon: %v", err)
+		status, msg := classifyStripeError(err)
+		return "", errLib.New("Failed to apply gift card balance: "+msg, status)
+	}
+	return c.ID, nil
+}
+
+// joiningFeeLineItem is the joining fee line item of a membership checkout with
+// creditCents of gift card balance taken off. It is nil when the balance covers the
+// whole fee.
+func joiningFeeLineItem(joiningFeePriceID string, creditCents int64) (*stripe.CheckoutSessionLineItemParams, *errLib.CommonError) {
+	if creditCents <= 0 {
+		return &stripe.CheckoutSessionLineItemParams{
+			Price:    stripe.String(joiningFeePriceID),
+			Quantity: stripe.Int64(1),
+		}, nil
+	}
+
+	fee, err := NewPriceService().GetPrice(joiningFeePriceID)
+	if err != nil {
+		return nil, err
+	}
+	if fee.Product == nil {
+		return nil, errLib.New("Joining fee price has no product", http.StatusInternalServerError)
+	}
+	if creditCents >= fee.UnitAmount {
+		return nil, nil
+	}
+
+	priceData := &stripe.CheckoutSessionLineItemPriceDataParams{
+		Currency:   stripe.String(string(fee.Currency)),
+		UnitAmount: stripe.Int64(fee.UnitAmount - creditCents),
+		Product:    stripe.String(fee.Product.ID),
+	}
+	if fee.TaxBehavior != "" {
+		priceData.TaxBehavior = stripe.String(string(fee.TaxBehavior))
+	}
+	return &stripe.CheckoutSessionLineItemParams{PriceData: priceData, Quantity: stripe.Int64(1)}, nil
+}
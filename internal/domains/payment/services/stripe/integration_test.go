@@ -35,7 +35,7 @@ func TestStripeIntegration(t *testing.T) {
 		t.Skip("Skipping Stripe API call - requires valid price IDs in test account")
 
 		// Test creating a one-time payment checkout session
-		checkoutURL, err := stripe.CreateOneTimePayment(ctx, "price_test_example", 1, nil, nil, "https://www.rise-basketball.com/success", "https://www.rise-basketball.com/cancel", nil)
+		checkoutURL, err := stripe.CreateOneTimePayment(ctx, "price_test_example", 1, nil, nil, "https://www.rise-basketball.com/success", "https://www.rise-basketball.com/cancel", nil, nil)
 
 		// Should succeed with valid inputs
 		assert.NoError(t, err)
@@ -49,7 +49,7 @@ func TestStripeIntegration(t *testing.T) {
 
 		// Test creating a one-time payment with event ID for event enrollment
 		eventID := uuid.New().String()
-		checkoutURL, err := stripe.CreateOneTimePayment(ctx, "price_test_example", 1, &eventID, nil, "https://www.rise-basketball.com/success", "https://www.rise-basketball.com/cancel", nil)
+		checkoutURL, err := stripe.CreateOneTimePayment(ctx, "price_test_example", 1, &eventID, nil, "https://www.rise-basketball.com/success", "https://www.rise-basketball.com/cancel", nil, nil)
 
 		// Should succeed with valid inputs including event ID
 		assert.NoError(t, err)
@@ -61,17 +61,17 @@ func TestStripeIntegration(t *testing.T) {
 
 	t.Run("CreateOneTimePayment_InvalidInputs", func(t *testing.T) {
 		// Test with empty price ID
-		_, err := stripe.CreateOneTimePayment(ctx, "", 1, nil, nil, "https://www.rise-basketball.com/success", "https://www.rise-basketball.com/cancel", nil)
+		_, err := stripe.CreateOneTimePayment(ctx, "", 1, nil, nil, "https://www.rise-basketball.com/success", "https://www.rise-basketball.com/cancel", nil, nil)
 		assert.Error(t, err)
 		assert.Equal(t, 400, err.HTTPCode)
 
 		// Test with invalid quantity
-		_, err = stripe.CreateOneTimePayment(ctx, "price_test_example", 0, nil, nil, "https://www.rise-basketball.com/success", "https://www.rise-basketball.com/cancel", nil)
+		_, err = stripe.CreateOneTimePayment(ctx, "price_test_example", 0, nil, nil, "https://www.rise-basketball.com/success", "https://www.rise-basketball.com/cancel", nil, nil)
 		assert.Error(t, err)
 		assert.Equal(t, 400, err.HTTPCode)
 
 		// Test with empty success URL
-		_, err = stripe.CreateOneTimePayment(ctx, "price_test_example", 1, nil, nil, "", "https://www.rise-basketball.com/cancel", nil)
+		_, err = stripe.CreateOneTimePayment(ctx, "price_test_example", 1, nil, nil, "", "https://www.rise-basketball.com/cancel", nil, nil)
 		assert.Error(t, err)
 		assert.Equal(t, 400, err.HTTPCode)
 	})
@@ -239,7 +239,7 @@ func TestStripeConfiguration(t *testing.T) {
 		userID := uuid.New()
 		ctx := context.WithValue(context.Background(), contextUtils.UserIDKey, userID)
 
-		_, err := stripe.CreateOneTimePayment(ctx, "price_test", 1, nil, nil, "https://www.rise-basketball.com/success", "https://www.rise-basketball.com/cancel", nil)
+		_, err := stripe.CreateOneTimePayment(ctx, "price_test", 1, nil, nil, "https://www.rise-basketball.com/success", "https://www.rise-basketball.com/cancel", nil, nil)
 		assert.Error(t, err)
 		assert.Equal(t, 500, err.HTTPCode)
 		assert.Contains(t, err.Message, "Stripe not initialized")
@@ -278,7 +278,7 @@ func TestAuthenticationValidation(t *testing.T) {
 		// Test without user ID in context
 		ctx := context.Background()
 
-		_, err := stripe.CreateOneTimePayment(ctx, "price_test", 1, nil, nil, "https://www.rise-basketball.com/success", "https://www.rise-basketball.com/cancel", nil)
+		_, err := stripe.CreateOneTimePayment(ctx, "price_test", 1, nil, nil, "https://www.rise-basketball.com/success", "https://www.rise-basketball.com/cancel", nil, nil)
 		assert.Error(t, err)
 		// Should fail with authentication error
 	})
@@ -308,7 +308,7 @@ func BenchmarkStripeOperations(b *testing.B) {
 
 		b.ResetTimer()
 		for i := 0; i < b.N; i++ {
-			_, err := stripe.CreateOneTimePayment(ctx, "price_test_benchmark", 1, nil, nil, "https://www.rise-basketball.com/success", "https://www.rise-basketball.com/cancel", nil)
+			_, err := stripe.CreateOneTimePayment(ctx, "price_test_benchmark", 1, nil, nil, "https://www.rise-basketball.com/success", "https://www.rise-basketball.com/cancel", nil, nil)
 			if err != nil {
 				b.Fatalf("CreateOneTimePayment failed: %v", err)
 			}
@@ -0,0 +1,268 @@
+package stripe
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	errLib "api/internal/libs/errors"
+
+	"github.com/stripe/stripe-go/v81"
+	"github.com/stripe/stripe-go/v81/price"
+	"github.com/stripe/stripe-go/v81/subscription"
+	"github.com/stripe/stripe-go/v81/subscriptionschedule"
+)
+
+// CreateRecurringPrice creates a new recurring Price for an existing Product, such as a
+// new price version of a membership plan. Returns the stripe_price_id.
+func (s *ProductService) CreateRecurringPrice(
+	stripeProductID string,
+	unitAmount int64,
+	currency string,
+	interval string,
+	intervalCount int64,
+	nickname string,
+) (stripePriceID string, err *errLib.CommonError) {
+	if strings.TrimSpace(stripeProductID) == "" {
+		return "", errLib.New("product ID cannot be empty", http.StatusBadRequest)
+	}
+	if unitAmount <= 0 {
+		return "", errLib.New("unit amount must be positive", http.StatusBadRequest)
+	}
+	if strings.TrimSpace(currency) == "" {
+		currency = "cad"
+	}
+	if intervalCount <= 0 {
+		intervalCount = 1
+	}
+
+	if strings.ReplaceAll(stripe.Key, " ", "") == "" {
+		return "", errLib.New("Stripe not initialized", http.StatusInternalServerError)
+	}
+
+	priceParams := &stripe.PriceParams{
+		Product:    stripe.String(stripeProductID),
+		UnitAmount: stripe.Int64(unitAmount),
+		Currency:   stripe.String(strings.ToLower(currency)),
+		Recurring: &stripe.PriceRecurringParams{
+			Interval:      stripe.String(interval),
+			IntervalCount: stripe.Int64(intervalCount),
+		},
+		// Sales tax is added on top of the price at checkout
+		TaxBehavior: stripe.String(string(stripe.PriceTaxBehaviorExclusive)),
+	}
+	if nickname != "" {
+		priceParams.Nickname = stripe.String(nickname)
+	}
+
+	stripePrice, priceErr := price.New(priceParams)
+	if priceErr != nil {
+		log.Printf("[STRIPE] Failed to create recurring price for product '%s': %v", stripeProductID, priceErr)
+		return "", errLib.New("Failed to create Stripe price: "+priceErr.Error(), http.StatusInternalServerError)
+	}
+
+	log.Printf("[STRIPE] Created recurring price %s ($%d %s/%d %s) for product %s",
+		stripePrice.ID, unitAmount, currency, intervalCount, interval, stripeProductID)
+
+	return stripePrice.ID, nil
+}
+
+// SchedulePriceChange moves a subscription to newPriceID at its first renewal on or after
+// effectiveAt, through a subscription schedule that is released once the new price starts.
+// The current phase keeps the subscription's price, tax rates and discounts. Returns the
+// schedule and the time of the switch. Subscriptions that are already on the new price or
+// already managed by a schedule are rejected with 409.
+func SchedulePriceChange(ctx context.Context, subscriptionID string, newPriceID string, effectiveAt time.Time, key string) (*stripe.SubscriptionSchedule, time.Time, *errLib.CommonError) {
+	timeoutCtx, cancel := withCriticalTimeout(ctx)
+	defer cancel()
+
+	if strings.ReplaceAll(stripe.Key, " ", "") == "" {
+		return nil, time.Time{}, errLib.New("Stripe not initialized", http.StatusInternalServerError)
+	}
+
+	type scheduleResult struct {
+		schedule *stripe.SubscriptionSchedule
+		switchAt time.Time
+		err      *errLib.CommonError
+	}
+
+	resultChan := make(chan scheduleResult, 1)
+
+	go func() {
+		schedule, switchAt, err := schedulePriceChange(subscriptionID, newPriceID, effectiveAt, key)
+		resultChan <- scheduleResult{schedule: schedule, switchAt: switchAt, err: err}
+	}()
+
+	select {
+	case <-timeoutCtx.Done():
+		return nil, time.Time{}, errLib.New("Stripe API timeout while scheduling price change", http.StatusRequestTimeout)
+	case result := <-resultChan:
+		return result.schedule, result.switchAt, result.err
+	}
+}
+
+func schedulePriceChange(subscriptionID string, newPriceID string, effectiveAt time.Time, key string) (*stripe.SubscriptionSchedule, time.Time, *errLib.CommonError) {
+	sub, err := subscription.Get(subscriptionID, nil)
+	if err != nil {
+		log.Printf("[STRIPE] Failed to get subscription %s: %v", subscriptionID, err)
+		status, msg := classifyStripeError(err)
+		return nil, time.Time{}, errLib.New("Failed to get subscription: "+msg, status)
+	}
+
+	if sub.Status == stripe.SubscriptionStatusCanceled || sub.Status == stripe.SubscriptionStatusIncompleteExpired {
+		return nil, time.Time{}, errLib.New("Subscription has ended", http.StatusConflict)
+	}
+	if sub.Schedule != nil {
+		return nil, time.Time{}, errLib.New("Subscription is already managed by a schedule", http.StatusConflict)
+	}
+	if sub.Items == nil || len(sub.Items.Data) != 1 || sub.Items.Data[0].Price == nil || sub.Items.Data[0].Price.Recurring == nil {
+		return nil, time.Time{}, errLib.New("Subscription must have exactly one recurring item", http.StatusConflict)
+	}
+
+	item := sub.Items.Data[0]
+	if item.Price.ID == newPriceID {
+		return nil, time.Time{}, errLib.New("Subscription is already on the new price", http.StatusConflict)
+	}
+
+	switchAt := RenewalOnOrAfter(
+		time.Unix(sub.CurrentPeriodEnd, 0).UTC(),
+		string(item.Price.Recurring.Interval),
+		item.Price.Recurring.IntervalCount,
+		effectiveAt,
+	)
+
+	createParams := &stripe.SubscriptionScheduleParams{
+		FromSubscription: stripe.String(subscriptionID),
+	}
+	createParams.IdempotencyKey = idempotencyKey("price-change-schedule", key)
+
+	schedule, err := subscriptionschedule.New(createParams)
+	if err != nil {
+		log.Printf("[STRIPE] Failed to create schedule for subscription %s: %v", subscriptionID, err)
+		status, msg := classifyStripeError(err)
+		return nil, time.Time{}, errLib.New("Failed to create subscription schedule: "+msg, status)
+	}
+
+	current := schedule.Phases[0]
+	taxRateIDs := make([]*string, 0, len(item.TaxRates))
+	for _, rate := range item.TaxRates {
+		taxRateIDs = append(taxRateIDs, stripe.String(rate.ID))
+	}
+	defaultTaxRateIDs := make([]*string, 0, len(current.DefaultTaxRates))
+	for _, rate := range current.DefaultTaxRates {
+		defaultTaxRateIDs = append(defaultTaxRateIDs, stripe.String(rate.ID))
+	}
+	var discounts []*stripe.SubscriptionSchedulePhaseDiscountParams
+	for _, discount := range current.Discounts {
+		switch {
+		case discount.Discount != nil:
+			discounts = append(discounts, &stripe.SubscriptionSchedulePhaseDiscountParams{Discount: stripe.String(discount.Discount.ID)})
+		case discount.Coupon != nil:
+			discounts = append(discounts, &stripe.SubscriptionSchedulePhaseDiscountParams{Coupon: stripe.String(discount.Coupon.ID)})
+		case discount.PromotionCode != nil:
+			discounts = append(discounts, &stripe.SubscriptionSchedulePhaseDiscountParams{PromotionCode: stripe.String(discount.PromotionCode.ID)})
+		}
+	}
+
+	updateParams := &stripe.SubscriptionScheduleParams{
+		EndBehavior: stripe.String(string(stripe.SubscriptionScheduleEndBehaviorRelease)),
+		Phases: []*stripe.SubscriptionSchedulePhaseParams{
+			{
+				StartDate: stripe.Int64(current.StartDate),
+				EndDate:   stripe.Int64(switchAt.Unix()),
+				Items: []*stripe.SubscriptionSchedulePhaseItemParams{{
+					Price:    stripe.String(item.Price.ID),
+					Quantity: stripe.Int64(item.Quantity),
+					TaxRates: taxRateIDs,
+				}},
+				DefaultTaxRates:   defaultTaxRateIDs,
+				Discounts:         discounts,
+				Metadata:          sub.Metadata,
+				ProrationBehavior: stripe.String(string(stripe.SubscriptionSchedulePhaseProrationBehaviorNone)),
+			},
+			{
+				Items: []*stripe.SubscriptionSchedulePhaseItemParams{{
+					Price:    stripe.String(newPriceID),
+					Quantity: stripe.Int64(item.Quantity),
+					TaxRates: taxRateIDs,
+				}},
+				DefaultTaxRates:   defaultTaxRateIDs,
+				Discounts:         discounts,
+				Iterations:        stripe.Int64(1),
+				Metadata:          sub.Metadata,
+				ProrationBehavior: stripe.String(string(stripe.SubscriptionSchedulePhaseProrationBehaviorNone)),
+			},
+		},
+	}
+	updateParams.IdempotencyKey = idempotencyKey("price-change-phases", key)
+
+	updated, err := subscriptionschedule.Update(schedule.ID, updateParams)
+	if err != nil {
+		log.Printf("[STRIPE] Failed to set price change phases on schedule %s: %v", schedule.ID, err)
+		// Hand the subscription back so it keeps billing as before
+		ReleaseSubscriptionSchedule(schedule.ID)
+		status, msg := classifyStripeError(err)
+		return nil, time.Time{}, errLib.New("Failed to schedule price change: "+msg, status)
+	}
+
+	log.Printf("[STRIPE] Scheduled subscription %s to move from %s to %s on %s (schedule %s)",
+		subscriptionID, item.Price.ID, newPriceID, switchAt.Format(time.RFC3339), updated.ID)
+	return updated, switchAt, nil
+}
+
+// ReleaseSubscriptionSchedule detaches a schedule from its subscription, which keeps
+// billing at its current price. Phases that haven't started are dropped.
+func ReleaseSubscriptionSchedule(scheduleID string) *errLib.CommonError {
+	if strings.TrimSpace(scheduleID) == "" {
+		return nil
+	}
+
+	if _, err := subscriptionschedule.Release(scheduleID, nil); err != nil {
+		log.Printf("[STRIPE] Failed to release subscription schedule %s: %v", scheduleID, err)
+		status, msg := classifyStripeError(err)
+		return errLib.New("Failed to release subscription schedule: "+msg, status)
+	}
+
+	log.Printf("[STRIPE] Released subscription schedule %s", scheduleID)
+	return nil
+}
+
+// RenewalOnOrAfter returns the first renewal of a billing cycle that is on or after t,
+// counting from the end of the current period. Monthly and yearly renewals keep the
+// day of the month of periodEnd, falling back to the month's last day like Stripe does.
+func RenewalOnOrAfter(periodEnd time.Time, interval string, intervalCount int64, t time.Time) time.Time {
+	if intervalCount <= 0 {
+		intervalCount = 1
+	}
+
+	renewal := periodEnd
+	for n := 1; renewal.Before(t); n++ {
+		step := int(intervalCount) * n
+		switch interval {
+		case "day":
+			renewal = periodEnd.AddDate(0, 0, step)
+		case "week":
+			renewal = periodEnd.AddDate(0, 0, 7*step)
+		case "year":
+			renewal = addMonthsClamped(periodEnd, 12*step)
+		default:
+			renewal = addMonthsClamped(periodEnd, step)
+		}
+	}
+	return renewal
+}
+
+// addMonthsClamped adds months to t, keeping its day unless the target month is shorter
+func addMonthsClamped(t time.Time, months int) time.Time {
+	firstOfMonth := time.Date(t.Year(), t.Month(), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	target := firstOfMonth.AddDate(0, months, 0)
+	lastDay := target.AddDate(0, 1, -1).Day()
+
+	day := t.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(target.Year(), target.Month(), day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
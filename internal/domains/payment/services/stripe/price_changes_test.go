@@ -0,0 +1,29 @@
+package stripe
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenewalOnOrAfter(t *testing.T) {
+	date := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 9, 30, 0, 0, time.UTC) }
+
+	t.Run("Current period end when it is already on or after the date", func(t *testing.T) {
+		assert.Equal(t, date(2026, 5, 15), RenewalOnOrAfter(date(2026, 5, 15), "month", 1, date(2026, 5, 1)))
+		assert.Equal(t, date(2026, 5, 15), RenewalOnOrAfter(date(2026, 5, 15), "month", 1, date(2026, 5, 15)))
+	})
+
+	t.Run("Steps whole billing periods", func(t *testing.T) {
+		assert.Equal(t, date(2026, 7, 15), RenewalOnOrAfter(date(2026, 5, 15), "month", 1, date(2026, 6, 20)))
+		assert.Equal(t, date(2026, 9, 15), RenewalOnOrAfter(date(2026, 5, 15), "month", 2, date(2026, 7, 16)))
+		assert.Equal(t, date(2026, 5, 29), RenewalOnOrAfter(date(2026, 5, 15), "week", 2, date(2026, 5, 20)))
+		assert.Equal(t, date(2027, 5, 15), RenewalOnOrAfter(date(2026, 5, 15), "year", 1, date(2026, 6, 1)))
+	})
+
+	t.Run("Keeps the billing day through short months", func(t *testing.T) {
+		assert.Equal(t, date(2026, 2, 28), RenewalOnOrAfter(date(2026, 1, 31), "month", 1, date(2026, 2, 10)))
+		assert.Equal(t, date(2026, 3, 31), RenewalOnOrAfter(date(2026, 1, 31), "month", 1, date(2026, 3, 1)))
+	})
+}
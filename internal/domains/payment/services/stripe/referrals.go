@@ -0,0 +1,63 @@
+package stripe
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	errLib "api/internal/libs/errors"
+
+	"github.com/stripe/stripe-go/v81"
+	"github.com/stripe/stripe-go/v81/invoice"
+	"github.com/stripe/stripe-go/v81/paymentmethod"
+)
+
+// InvoiceCardFingerprint returns the fingerprint of the card that paid an invoice, or
+// "" when it was not paid by card. The same card has the same fingerprint on every
+// Stripe customer, which is how a self-referral paying with the referrer's card shows.
+func InvoiceCardFingerprint(invoiceID string) (string, *errLib.CommonError) {
+	if strings.TrimSpace(invoiceID) == "" {
+		return "", errLib.New("invoice ID cannot be empty", http.StatusBadRequest)
+	}
+
+	params := &stripe.InvoiceParams{}
+	params.AddExpand("charge")
+	inv, err := invoice.Get(invoiceID, params)
+	if err != nil {
+		log.Printf("[STRIPE] Failed to retrieve invoice %s: %v", invoiceID, err)
+		return "", errLib.New("Failed to retrieve invoice: "+err.Error(), http.StatusInternalServerError)
+	}
+
+	if inv.Charge == nil || inv.Charge.PaymentMethodDetails == nil || inv.Charge.PaymentMethodDetails.Card == nil {
+		return "", nil
+	}
+	return inv.Charge.PaymentMethodDetails.Card.Fingerprint, nil
+}
+
+// CustomerCardFingerprints returns the fingerprints of the cards saved on a Stripe
+// customer.
+func CustomerCardFingerprints(customerID string) ([]string, *errLib.CommonError) {
+	if strings.TrimSpace(customerID) == "" {
+		return nil, nil
+	}
+
+	params := &stripe.PaymentMethodListParams{
+		Customer: stripe.String(customerID),
+		Type:     stripe.String(string(stripe.PaymentMethodTypeCard)),
+	}
+
+	var fingerprints []string
+	iter := paymentmethod.List(params)
+	for iter.Next() {
+		if pm := iter.PaymentMethod(); pm.Card != nil && pm.Card.Fingerprint != "" {
+			fingerprints = append(fingerprints, pm.Card.Fingerprint)
+		}
+	}
+
+	if iter.Err() != nil {
+		log.Printf("[STRIPE] Failed to list payment methods for customer %s: %v", customerID, iter.Err())
+		return nil, errLib.New("Failed to list payment methods: "+iter.Err().Error(), http.StatusInternalServerError)
+	}
+
+	return fingerprints, nil
+}
@@ -11,6 +11,8 @@ import (
 	"time"
 
 	"api/internal/di"
+	auditValues "api/internal/domains/audit/audit_logs/values"
+	staffActivityLogs "api/internal/domains/audit/staff_activity_logs/service"
 	errLib "api/internal/libs/errors"
 	contextUtils "api/utils/context"
 
@@ -20,8 +22,10 @@ import (
 	"github.com/stripe/stripe-go/v81/checkout/session"
 	"github.com/stripe/stripe-go/v81/coupon"
 	"github.com/stripe/stripe-go/v81/customer"
+	"github.com/stripe/stripe-go/v81/invoice"
 	"github.com/stripe/stripe-go/v81/price"
 	"github.com/stripe/stripe-go/v81/product"
+	"github.com/stripe/stripe-go/v81/refund"
 	"github.com/stripe/stripe-go/v81/subscription"
 	"github.com/stripe/stripe-go/v81/webhook"
 )
@@ -70,6 +74,27 @@ func CreateOneTimePayment(
 	successURL string, // Success redirect URL after payment
 	cancelURL string, // Cancel redirect URL when user aborts checkout
 	existingCustomerID *string, // Optional: Existing Stripe customer ID to reuse
+	taxRateIDs []string, // Optional: Stripe tax rates charged on the item
+) (string, *errLib.CommonError) {
+	return CreateOneTimePaymentWithMetadata(ctx, itemStripePriceID, quantity, eventID, stripeCouponID, nil, time.Time{},
+		successURL, cancelURL, existingCustomerID, taxRateIDs)
+}
+
+// CreateOneTimePaymentWithMetadata creates a Stripe Checkout Session for a one-time payment
+// with extra metadata for the webhook, e.g. a gift card balance hold. A non-zero expiresAt
+// expires the session with whatever it holds.
+func CreateOneTimePaymentWithMetadata(
+	ctx context.Context, // request-scoped context (for userID, cancellation, etc.)
+	itemStripePriceID string, // Stripe Price ID of the item being purchased
+	quantity int, // Number of items
+	eventID *string, // Optional: Event ID for event enrollment payments
+	stripeCouponID *string, // Optional: Stripe coupon ID for discounts
+	extraMetadata map[string]string, // Optional: Metadata added to the session and payment
+	expiresAt time.Time, // Optional: When the session expires (Stripe's default when zero)
+	successURL string, // Success redirect URL after payment
+	cancelURL string, // Cancel redirect URL when user aborts checkout
+	existingCustomerID *string, // Optional: Existing Stripe customer ID to reuse
+	taxRateIDs []string, // Optional: Stripe tax rates charged on the item
 ) (string, *errLib.CommonError) {
 	// Create a timeout context for this operation
 	timeoutCtx, cancel := withCriticalTimeout(ctx)
@@ -123,6 +148,9 @@ func CreateOneTimePayment(
 	if eventID != nil && *eventID != "" {
 		metadata["eventID"] = *eventID
 	}
+	for key, value := range extraMetadata {
+		metadata[key] = value
+	}
 
 	params := &stripe.CheckoutSessionParams{
 		Metadata: metadata,
@@ -166,8 +194,18 @@ func CreateOneTimePayment(
 		params.PaymentIntentData.Metadata["stripeCouponID"] = *stripeCouponID
 	}
 
-	// Idempotency key prevents duplicate sessions on network retry
+	if !expiresAt.IsZero() {
+		params.ExpiresAt = stripe.Int64(expiresAt.Unix())
+	}
+
+	applyTaxRates(params, taxRateIDs)
+
+	// Idempotency key prevents duplicate sessions on network retry. A checkout holding
+	// gift card balance gets its own session, since its coupon differs.
 	params.IdempotencyKey = idempotencyKey("checkout-onetime", userID.String(), itemStripePriceID)
+	if holdID := metadata[StoredValueHoldMetadataKey]; holdID != "" {
+		params.IdempotencyKey = idempotencyKey("checkout-onetime", userID.String(), itemStripePriceID, holdID)
+	}
 
 	// Create Stripe session with timeout handling
 	type sessionResult struct {
@@ -196,6 +234,147 @@ func CreateOneTimePayment(
 	}
 }
 
+// CreateCourtRentalPayment creates a Stripe Checkout Session charging a court rental's
+// quoted amount. The session expires with the rental's hold so the customer cannot pay
+// for a court that has been released.
+func CreateCourtRentalPayment(
+	ctx context.Context,
+	rentalID uuid.UUID,
+	description string, // Line item name shown on the checkout page
+	amountCents int64,
+	currency string,
+	expiresAt time.Time,
+	successURL string,
+	cancelURL string,
+	existingCustomerID *string,
+	taxRateIDs []string,
+) (string, *errLib.CommonError) {
+	return createBookingPayment(ctx, "courtRentalID", "checkout-court-rental", rentalID, description, amountCents,
+		currency, expiresAt, successURL, cancelURL, existingCustomerID, taxRateIDs)
+}
+
+// CreatePlaygroundSessionPayment creates a Stripe Checkout Session charging a playground
+// session. The session expires with the playground hold.
+func CreatePlaygroundSessionPayment(
+	ctx context.Context,
+	sessionID uuid.UUID,
+	description string, // Line item name shown on the checkout page
+	amountCents int64,
+	currency string,
+	expiresAt time.Time,
+	successURL string,
+	cancelURL string,
+	existingCustomerID *string,
+	taxRateIDs []string,
+) (string, *errLib.CommonError) {
+	return createBookingPayment(ctx, "playgroundSessionID", "checkout-playground-session", sessionID, description, amountCents,
+		currency, expiresAt, successURL, cancelURL, existingCustomerID, taxRateIDs)
+}
+
+// createBookingPayment charges a held booking. Bookings are priced per request, so the
+// line item is built from the amount rather than a pre-created Price. The booking's ID
+// is stored in the metadata under metadataKey for the webhook to confirm it.
+func createBookingPayment(
+	ctx context.Context,
+	metadataKey string,
+	idempotencyPrefix string,
+	bookingID uuid.UUID,
+	description string,
+	amountCents int64,
+	currency string,
+	expiresAt time.Time,
+	successURL string,
+	cancelURL string,
+	existingCustomerID *string,
+	taxRateIDs []string,
+) (string, *errLib.CommonError) {
+	timeoutCtx, cancel := withCriticalTimeout(ctx)
+	defer cancel()
+
+	if strings.ReplaceAll(stripe.Key, " ", "") == "" {
+		return "", errLib.New("Stripe not initialized", http.StatusInternalServerError)
+	}
+
+	if amountCents <= 0 {
+		return "", errLib.New("amount must be positive", http.StatusBadRequest)
+	}
+
+	if successURL == "" || cancelURL == "" {
+		return "", errLib.New("success and cancel URLs cannot be empty", http.StatusBadRequest)
+	}
+
+	userID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	metadata := map[string]string{
+		"userID":    userID.String(),
+		metadataKey: bookingID.String(),
+	}
+
+	params := &stripe.CheckoutSessionParams{
+		Metadata: metadata,
+		PaymentIntentData: &stripe.CheckoutSessionPaymentIntentDataParams{
+			Metadata: metadata,
+		},
+		LineItems: []*stripe.CheckoutSessionLineItemParams{
+			{
+				PriceData: &stripe.CheckoutSessionLineItemPriceDataParams{
+					Currency:    stripe.String(currency),
+					UnitAmount:  stripe.Int64(amountCents),
+					TaxBehavior: stripe.String(string(stripe.PriceTaxBehaviorExclusive)),
+					ProductData: &stripe.CheckoutSessionLineItemPriceDataProductDataParams{
+						Name: stripe.String(description),
+					},
+				},
+				Quantity: stripe.Int64(1),
+			},
+		},
+		Mode:       stripe.String("payment"),
+		SuccessURL: stripe.String(successURL),
+		CancelURL:  stripe.String(cancelURL),
+		ExpiresAt:  stripe.Int64(expiresAt.Unix()),
+		AutomaticTax: &stripe.CheckoutSessionAutomaticTaxParams{
+			Enabled: stripe.Bool(true),
+		},
+	}
+
+	if existingCustomerID != nil && *existingCustomerID != "" {
+		params.Customer = stripe.String(*existingCustomerID)
+	}
+
+	applyTaxRates(params, taxRateIDs)
+
+	// One session per hold; a retried request gets the same session back
+	params.IdempotencyKey = idempotencyKey(idempotencyPrefix, bookingID.String())
+
+	type sessionResult struct {
+		session *stripe.CheckoutSession
+		err     error
+	}
+
+	resultChan := make(chan sessionResult, 1)
+
+	go func() {
+		s, err := session.New(params)
+		resultChan <- sessionResult{session: s, err: err}
+	}()
+
+	select {
+	case <-timeoutCtx.Done():
+		if timeoutCtx.Err() == context.DeadlineExceeded {
+			return "", errLib.New("Stripe API timeout while creating payment", http.StatusRequestTimeout)
+		}
+		return "", errLib.New("Request cancelled during payment creation", http.StatusRequestTimeout)
+	case result := <-resultChan:
+		if result.err != nil {
+			return "", errLib.New("Payment session failed: "+result.err.Error(), http.StatusInternalServerError)
+		}
+		return result.session.URL, nil
+	}
+}
+
 // CreateSubscriptionWithSetupFeeAndMetadata creates a Stripe Checkout Session for a recurring subscription with optional setup fee and metadata
 func CreateSubscriptionWithSetupFeeAndMetadata(
 	ctx context.Context,
@@ -205,6 +384,7 @@ func CreateSubscriptionWithSetupFeeAndMetadata(
 	successURL string,              // Success redirect URL after payment
 	cancelURL string,               // Cancel redirect URL when user aborts checkout
 	existingCustomerID *string,     // Optional: Existing Stripe customer ID to reuse
+	taxRateIDs []string,            // Optional: Stripe tax rates charged on the plan
 ) (string, *errLib.CommonError) {
 	// Create a timeout context for this operation
 	timeoutCtx, cancel := withCriticalTimeout(ctx)
@@ -298,6 +478,8 @@ func CreateSubscriptionWithSetupFeeAndMetadata(
 	params.AddExpand("line_items.data.price")
 	params.AddExpand("subscription")
 
+	applyTaxRates(params, taxRateIDs)
+
 	// Idempotency key prevents duplicate sessions on network retry
 	params.IdempotencyKey = idempotencyKey("checkout-sub-setup", userID.String(), stripePlanPriceID)
 
@@ -590,11 +772,13 @@ func CreateSubscriptionWithMetadata(
 	ctx context.Context,
 	stripePlanPriceID string,       // Stripe Price ID for the recurring plan
 	stripeJoiningFeesID string,     // Optional one-time joining fee
+	joiningFeeCreditCents int64,    // Optional: gift card balance taken off the joining fee
 	stripeCouponID *string,         // Optional: Stripe coupon ID for discounts
 	metadata map[string]string,     // Metadata to attach to subscription
 	successURL string,              // Success redirect URL after payment
 	cancelURL string,               // Cancel redirect URL when user aborts checkout
 	existingCustomerID *string,     // Optional: Existing Stripe customer ID to reuse
+	taxRateIDs []string,            // Optional: Stripe tax rates charged on the plan
 ) (string, *errLib.CommonError) {
 	// Create a timeout context for this operation
 	timeoutCtx, cancel := withCriticalTimeout(ctx)
@@ -674,12 +858,16 @@ func CreateSubscriptionWithMetadata(
 	params.AddExpand("line_items.data.price")
 	params.AddExpand("subscription")
 
-	// If there's a joining fee, add it as a second line item
+	// If there's a joining fee, add it as a second line item, less any gift card balance
+	// applied to it. A fee the balance covers in full is left out.
 	if stripeJoiningFeesID != "" {
-		params.LineItems = append(params.LineItems, &stripe.CheckoutSessionLineItemParams{
-			Price:    stripe.String(stripeJoiningFeesID),
-			Quantity: stripe.Int64(1),
-		})
+		joiningFee, feeErr := joiningFeeLineItem(stripeJoiningFeesID, joiningFeeCreditCents)
+		if feeErr != nil {
+			return "", feeErr
+		}
+		if joiningFee != nil {
+			params.LineItems = append(params.LineItems, joiningFee)
+		}
 	}
 
 	// Add discount coupon if provided
@@ -695,8 +883,13 @@ func CreateSubscriptionWithMetadata(
 		params.SubscriptionData.Metadata["stripeCouponID"] = *stripeCouponID
 	}
 
+	applyTaxRates(params, taxRateIDs)
+
 	// Idempotency key prevents duplicate sessions on network retry
 	params.IdempotencyKey = idempotencyKey("checkout-sub-meta", userID.String(), stripePlanPriceID)
+	if holdID := metadata[StoredValueHoldMetadataKey]; holdID != "" {
+		params.IdempotencyKey = idempotencyKey("checkout-sub-meta", userID.String(), stripePlanPriceID, holdID)
+	}
 
 	// Create Stripe session with timeout handling
 	type subscriptionResult struct {
@@ -737,6 +930,7 @@ func CreateSubscriptionCheckoutForCustomer(
 	successURL string,
 	cancelURL string,
 	existingCustomerID *string,
+	taxRateIDs []string,
 ) (string, *errLib.CommonError) {
 	// Create a timeout context for this operation
 	timeoutCtx, cancel := withCriticalTimeout(context.Background())
@@ -816,6 +1010,8 @@ func CreateSubscriptionCheckoutForCustomer(
 		})
 	}
 
+	applyTaxRates(params, taxRateIDs)
+
 	// Idempotency key prevents duplicate sessions on network retry
 	params.IdempotencyKey = idempotencyKey("checkout-admin", customerUserID.String(), stripePlanPriceID)
 
@@ -932,16 +1128,24 @@ func CreateSubscriptionWithDiscountPercent(
 
 // SubscriptionService provides secure subscription management operations
 type SubscriptionService struct {
-	db *sql.DB
+	db                       *sql.DB
+	staffActivityLogsService *staffActivityLogs.Service
 }
 
 // NewSubscriptionService creates a new instance of SubscriptionService
 func NewSubscriptionService(container *di.Container) *SubscriptionService {
 	return &SubscriptionService{
-		db: container.DB,
+		db:                       container.DB,
+		staffActivityLogsService: staffActivityLogs.NewService(container),
 	}
 }
 
+// subscriptionAuditState is what the audit log records about a subscription an admin changes
+type subscriptionAuditState struct {
+	Status            stripe.SubscriptionStatus `json:"status"`
+	CancelAtPeriodEnd bool                      `json:"cancel_at_period_end"`
+}
+
 // getDB returns the database connection
 func (s *SubscriptionService) getDB() *sql.DB {
 	return s.db
@@ -1106,6 +1310,20 @@ func (s *SubscriptionService) AdminCancelSubscription(ctx context.Context, subsc
 	}
 
 	log.Printf("[STRIPE] Admin successfully cancelled subscription %s (immediate: %v) - New status: %s", subscriptionID, cancelImmediately, cancelledSub.Status)
+
+	// The cancellation already happened in Stripe; a failure to record it is only logged
+	adminID, _ := contextUtils.GetUserID(ctx)
+	if auditErr := s.staffActivityLogsService.RecordChange(ctx, nil, adminID, auditValues.Entry{
+		EntityType:  "subscription",
+		EntityID:    subscriptionID,
+		Action:      "cancel",
+		Before:      subscriptionAuditState{Status: sub.Status, CancelAtPeriodEnd: sub.CancelAtPeriodEnd},
+		After:       subscriptionAuditState{Status: cancelledSub.Status, CancelAtPeriodEnd: cancelledSub.CancelAtPeriodEnd},
+		Description: fmt.Sprintf("Cancelled subscription %s (immediate: %v)", subscriptionID, cancelImmediately),
+	}); auditErr != nil {
+		log.Printf("[STRIPE] Warning: Failed to record cancellation of subscription %s in the audit log: %v", subscriptionID, auditErr.Message)
+	}
+
 	return cancelledSub, nil
 }
 
@@ -1157,7 +1375,10 @@ func (s *SubscriptionService) AdminUpgradeSubscription(ctx context.Context, subs
 	// Look up the current plan's price from our database
 	currentPriceID := sub.Items.Data[0].Price.ID
 	var currentUnitAmount sql.NullInt32
-	currentQuery := "SELECT unit_amount FROM membership.membership_plans WHERE stripe_price_id = $1"
+	// Grandfathered subscribers can be on an earlier price version of their plan
+	currentQuery := `SELECT unit_amount FROM membership.membership_plans WHERE stripe_price_id = $1
+		UNION ALL SELECT unit_amount FROM membership.plan_prices WHERE stripe_price_id = $1
+		LIMIT 1`
 	if dbErr := s.getDB().QueryRowContext(ctx, currentQuery, currentPriceID).Scan(&currentUnitAmount); dbErr != nil {
 		if dbErr == sql.ErrNoRows {
 			return nil, errLib.New("Current membership plan not found in database", http.StatusInternalServerError)
@@ -1249,7 +1470,10 @@ func (s *SubscriptionService) UpgradeSubscription(ctx context.Context, subscript
 	// Look up the current plan's price from our database using the current subscription's price ID
 	currentPriceID := sub.Items.Data[0].Price.ID
 	var currentUnitAmount sql.NullInt32
-	currentQuery := "SELECT unit_amount FROM membership.membership_plans WHERE stripe_price_id = $1"
+	// Grandfathered subscribers can be on an earlier price version of their plan
+	currentQuery := `SELECT unit_amount FROM membership.membership_plans WHERE stripe_price_id = $1
+		UNION ALL SELECT unit_amount FROM membership.plan_prices WHERE stripe_price_id = $1
+		LIMIT 1`
 	if dbErr := s.getDB().QueryRowContext(ctx, currentQuery, currentPriceID).Scan(&currentUnitAmount); dbErr != nil {
 		if dbErr == sql.ErrNoRows {
 			return nil, errLib.New("Current membership plan not found in database", http.StatusInternalServerError)
@@ -1382,6 +1606,77 @@ func (s *SubscriptionService) ResumeSubscription(ctx context.Context, subscripti
 	return resumedSub, nil
 }
 
+// FreezeSubscription stops billing a subscription until resumesAt, when Stripe resumes it
+// by itself. Invoices due while frozen are voided, so the customer isn't charged for the
+// freeze. The freeze ID is kept in the metadata so webhooks can tell a freeze from a pause.
+func (s *SubscriptionService) FreezeSubscription(ctx context.Context, subscriptionID string, freezeID string, resumesAt time.Time) (*stripe.Subscription, *errLib.CommonError) {
+	if strings.TrimSpace(subscriptionID) == "" {
+		return nil, errLib.New("subscription ID cannot be empty", http.StatusBadRequest)
+	}
+
+	// First verify ownership
+	sub, err := s.GetSubscription(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if sub.Status != stripe.SubscriptionStatusActive {
+		return nil, errLib.New("Only active subscriptions can be frozen", http.StatusConflict)
+	}
+
+	params := &stripe.SubscriptionParams{
+		PauseCollection: &stripe.SubscriptionPauseCollectionParams{
+			Behavior:  stripe.String(string(stripe.SubscriptionPauseCollectionBehaviorVoid)),
+			ResumesAt: stripe.Int64(resumesAt.Unix()),
+		},
+		Metadata: map[string]string{
+			"freeze_id": freezeID,
+			"frozen_at": time.Now().UTC().Format(time.RFC3339),
+		},
+	}
+	params.IdempotencyKey = idempotencyKey("freeze-sub", subscriptionID, freezeID)
+
+	frozenSub, stripeErr := subscription.Update(subscriptionID, params)
+	if stripeErr != nil {
+		log.Printf("[STRIPE] Failed to freeze subscription %s: %v", subscriptionID, stripeErr)
+		return nil, errLib.New("Failed to freeze subscription: "+stripeErr.Error(), http.StatusInternalServerError)
+	}
+
+	log.Printf("[STRIPE] Froze subscription %s until %s", subscriptionID, resumesAt.Format(time.RFC3339))
+	return frozenSub, nil
+}
+
+// UnfreezeSubscription ends a freeze: billing resumes if Stripe hasn't already resumed it,
+// and the freeze is cleared from the metadata.
+func (s *SubscriptionService) UnfreezeSubscription(ctx context.Context, subscriptionID string, freezeID string) (*stripe.Subscription, *errLib.CommonError) {
+	if strings.TrimSpace(subscriptionID) == "" {
+		return nil, errLib.New("subscription ID cannot be empty", http.StatusBadRequest)
+	}
+
+	// First verify ownership
+	if _, err := s.GetSubscription(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+
+	params := &stripe.SubscriptionParams{
+		PauseCollection: &stripe.SubscriptionPauseCollectionParams{},
+		Metadata: map[string]string{
+			"freeze_id":   "", // an empty value removes the key
+			"unfrozen_at": time.Now().UTC().Format(time.RFC3339),
+		},
+	}
+	params.IdempotencyKey = idempotencyKey("unfreeze-sub", subscriptionID, freezeID)
+
+	unfrozenSub, stripeErr := subscription.Update(subscriptionID, params)
+	if stripeErr != nil {
+		log.Printf("[STRIPE] Failed to unfreeze subscription %s: %v", subscriptionID, stripeErr)
+		return nil, errLib.New("Failed to unfreeze subscription: "+stripeErr.Error(), http.StatusInternalServerError)
+	}
+
+	log.Printf("[STRIPE] Unfroze subscription %s", subscriptionID)
+	return unfrozenSub, nil
+}
+
 // GetCustomerSubscriptions retrieves all subscriptions for a customer with security validation
 func (s *SubscriptionService) GetCustomerSubscriptions(ctx context.Context) ([]*stripe.Subscription, *errLib.CommonError) {
 	userID, err := contextUtils.GetUserID(ctx)
@@ -1706,6 +2001,8 @@ func (s *ProductService) CreateProductWithRecurringPrice(
 			Interval:      stripe.String(interval),
 			IntervalCount: stripe.Int64(intervalCount),
 		},
+		// Sales tax is added on top of the price at checkout
+		TaxBehavior: stripe.String(string(stripe.PriceTaxBehaviorExclusive)),
 	}
 
 	stripePrice, priceErr := price.New(priceParams)
@@ -1764,9 +2061,10 @@ func (s *ProductService) CreateProductWithOneTimePrice(
 
 	// Create the one-time Price attached to the Product
 	priceParams := &stripe.PriceParams{
-		Product:    stripe.String(stripeProduct.ID),
-		UnitAmount: stripe.Int64(unitAmount),
-		Currency:   stripe.String(currency),
+		Product:     stripe.String(stripeProduct.ID),
+		UnitAmount:  stripe.Int64(unitAmount),
+		Currency:    stripe.String(currency),
+		TaxBehavior: stripe.String(string(stripe.PriceTaxBehaviorExclusive)),
 	}
 
 	stripePrice, priceErr := price.New(priceParams)
@@ -1807,9 +2105,10 @@ func (s *ProductService) CreateOneTimePrice(
 
 	// Create the one-time Price attached to the existing Product
 	priceParams := &stripe.PriceParams{
-		Product:    stripe.String(stripeProductID),
-		UnitAmount: stripe.Int64(unitAmount),
-		Currency:   stripe.String(currency),
+		Product:     stripe.String(stripeProductID),
+		UnitAmount:  stripe.Int64(unitAmount),
+		Currency:    stripe.String(currency),
+		TaxBehavior: stripe.String(string(stripe.PriceTaxBehaviorExclusive)),
 	}
 	if nickname != "" {
 		priceParams.Nickname = stripe.String(nickname)
@@ -1981,3 +2280,108 @@ func ListRecentCheckoutSessions(sinceTime time.Time, limit int64) ([]*stripe.Che
 
 	return sessions, nil
 }
+
+// FindPaymentIntentID returns the payment intent behind a checkout session or, failing
+// that, an invoice. Payments tracked from checkout only store the session ID.
+func FindPaymentIntentID(checkoutSessionID, invoiceID string) (string, *errLib.CommonError) {
+	if checkoutSessionID != "" {
+		s, err := session.Get(checkoutSessionID, nil)
+		if err != nil {
+			log.Printf("[STRIPE] Failed to retrieve checkout session %s: %v", checkoutSessionID, err)
+			return "", errLib.New("Failed to retrieve checkout session: "+err.Error(), http.StatusInternalServerError)
+		}
+		if s.PaymentIntent != nil && s.PaymentIntent.ID != "" {
+			return s.PaymentIntent.ID, nil
+		}
+		// Subscription checkouts are paid through their first invoice
+		if invoiceID == "" && s.Invoice != nil {
+			invoiceID = s.Invoice.ID
+		}
+	}
+
+	if invoiceID != "" {
+		inv, err := invoice.Get(invoiceID, nil)
+		if err != nil {
+			log.Printf("[STRIPE] Failed to retrieve invoice %s: %v", invoiceID, err)
+			return "", errLib.New("Failed to retrieve invoice: "+err.Error(), http.StatusInternalServerError)
+		}
+		if inv.PaymentIntent != nil && inv.PaymentIntent.ID != "" {
+			return inv.PaymentIntent.ID, nil
+		}
+	}
+
+	return "", errLib.New("No Stripe payment found for this transaction", http.StatusUnprocessableEntity)
+}
+
+// FindCheckoutSessionID returns the checkout session that created a payment intent, or
+// "" if the payment did not come from checkout.
+func FindCheckoutSessionID(paymentIntentID string) (string, *errLib.CommonError) {
+	params := &stripe.CheckoutSessionListParams{
+		PaymentIntent: stripe.String(paymentIntentID),
+	}
+	params.Limit = stripe.Int64(1)
+
+	iter := session.List(params)
+	if iter.Next() {
+		return iter.CheckoutSession().ID, nil
+	}
+	if iter.Err() != nil {
+		log.Printf("[STRIPE] Failed to list checkout sessions for payment intent %s: %v", paymentIntentID, iter.Err())
+		return "", errLib.New("Failed to list checkout sessions: "+iter.Err().Error(), http.StatusInternalServerError)
+	}
+	return "", nil
+}
+
+// CreateRefund refunds amountCents of a payment intent. The free-text reason is kept in
+// the refund's metadata; Stripe itself only accepts a fixed set of reasons.
+func CreateRefund(ctx context.Context, paymentIntentID string, amountCents int64, reason string, metadata map[string]string, key string) (*stripe.Refund, *errLib.CommonError) {
+	timeoutCtx, cancel := withCriticalTimeout(ctx)
+	defer cancel()
+
+	if strings.ReplaceAll(stripe.Key, " ", "") == "" {
+		return nil, errLib.New("Stripe not initialized", http.StatusInternalServerError)
+	}
+
+	if amountCents <= 0 {
+		return nil, errLib.New("amount must be positive", http.StatusBadRequest)
+	}
+
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+	if reason != "" {
+		metadata["reason"] = reason
+	}
+
+	params := &stripe.RefundParams{
+		PaymentIntent: stripe.String(paymentIntentID),
+		Amount:        stripe.Int64(amountCents),
+		Reason:        stripe.String(string(stripe.RefundReasonRequestedByCustomer)),
+		Metadata:      metadata,
+	}
+	params.IdempotencyKey = idempotencyKey("refund", key)
+
+	type refundResult struct {
+		refund *stripe.Refund
+		err    error
+	}
+
+	resultChan := make(chan refundResult, 1)
+
+	go func() {
+		r, err := refund.New(params)
+		resultChan <- refundResult{refund: r, err: err}
+	}()
+
+	select {
+	case <-timeoutCtx.Done():
+		return nil, errLib.New("Stripe API timeout while creating refund", http.StatusRequestTimeout)
+	case result := <-resultChan:
+		if result.err != nil {
+			log.Printf("[STRIPE] Refund of %d cents on %s failed: %v", amountCents, paymentIntentID, result.err)
+			code, msg := classifyStripeError(result.err)
+			return nil, errLib.New(msg, code)
+		}
+		return result.refund, nil
+	}
+}
@@ -60,7 +60,7 @@ func TestCreateOneTimePayment(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			paymentLink, err := payment.CreateOneTimePayment(ctx, tt.priceID, tt.quantity, nil, nil, "https://www.rise-basketball.com/success", "https://www.rise-basketball.com/cancel", nil)
+			paymentLink, err := payment.CreateOneTimePayment(ctx, tt.priceID, tt.quantity, nil, nil, "https://www.rise-basketball.com/success", "https://www.rise-basketball.com/cancel", nil, nil)
 
 			if tt.wantErr {
 				if err == nil {
@@ -0,0 +1,90 @@
+package stripe
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+
+	errLib "api/internal/libs/errors"
+
+	"github.com/stripe/stripe-go/v81"
+	"github.com/stripe/stripe-go/v81/taxrate"
+)
+
+// applyTaxRates charges the given Stripe tax rates on every line item of a checkout.
+// Manual tax rates cannot be combined with Stripe Tax, so automatic tax is turned off
+// when rates are given. Without rates the checkout is left unchanged.
+func applyTaxRates(params *stripe.CheckoutSessionParams, taxRateIDs []string) {
+	if len(taxRateIDs) == 0 {
+		return
+	}
+
+	params.AutomaticTax = nil
+	for _, item := range params.LineItems {
+		item.TaxRates = stripe.StringSlice(taxRateIDs)
+	}
+}
+
+// CreateTaxRate creates an exclusive Canadian sales tax rate in Stripe, such as
+// "GST" at 5% in AB, and returns its ID. name is the tax (GST, PST, HST, QST or RST).
+func CreateTaxRate(ctx context.Context, name string, province string, percentage float64, key string) (string, *errLib.CommonError) {
+	timeoutCtx, cancel := withCriticalTimeout(ctx)
+	defer cancel()
+
+	if strings.ReplaceAll(stripe.Key, " ", "") == "" {
+		return "", errLib.New("Stripe not initialized", http.StatusInternalServerError)
+	}
+
+	params := &stripe.TaxRateParams{
+		DisplayName:  stripe.String(name),
+		Percentage:   stripe.Float64(percentage),
+		Inclusive:    stripe.Bool(false),
+		Country:      stripe.String("CA"),
+		State:        stripe.String(province),
+		Jurisdiction: stripe.String(province),
+		TaxType:      stripe.String(strings.ToLower(name)),
+	}
+	params.IdempotencyKey = idempotencyKey("tax-rate", key)
+
+	type taxRateResult struct {
+		rate *stripe.TaxRate
+		err  error
+	}
+
+	resultChan := make(chan taxRateResult, 1)
+
+	go func() {
+		r, err := taxrate.New(params)
+		resultChan <- taxRateResult{rate: r, err: err}
+	}()
+
+	select {
+	case <-timeoutCtx.Done():
+		return "", errLib.New("Stripe API timeout while creating tax rate", http.StatusRequestTimeout)
+	case result := <-resultChan:
+		if result.err != nil {
+			status, msg := classifyStripeError(result.err)
+			return "", errLib.New("Failed to create tax rate: "+msg, status)
+		}
+		log.Printf("[STRIPE] Created tax rate %s (%s %.3f%% %s)", result.rate.ID, name, percentage, province)
+		return result.rate.ID, nil
+	}
+}
+
+// ArchiveTaxRate stops a Stripe tax rate from being offered on new checkouts.
+// Subscriptions already charging the rate keep charging it until they are updated.
+func ArchiveTaxRate(taxRateID string) *errLib.CommonError {
+	if strings.TrimSpace(taxRateID) == "" {
+		return nil
+	}
+
+	if _, err := taxrate.Update(taxRateID, &stripe.TaxRateParams{Active: stripe.Bool(false)}); err != nil {
+		log.Printf("[STRIPE] Failed to archive tax rate %s: %v", taxRateID, err)
+		status, msg := classifyStripeError(err)
+		return errLib.New("Failed to archive tax rate: "+msg, status)
+	}
+
+	log.Printf("[STRIPE] Archived tax rate %s", taxRateID)
+	return nil
+}
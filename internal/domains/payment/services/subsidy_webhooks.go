@@ -503,6 +503,11 @@ func (s *WebhookService) HandleInvoicePaymentSucceededWithSubsidy(ctx context.Co
 
 	log.Printf("[WEBHOOK] Successfully activated membership for user %s after invoice payment %s", userID, invoice.ID)
 
+	// A paid invoice ends any dunning of it, lifting booking restrictions and dunning suspensions
+	if dunningErr := s.Dunning.ResolveInvoice(ctx, invoice.ID, subscriptionID); dunningErr != nil {
+		log.Printf("[WEBHOOK] Failed to resolve dunning for invoice %s: %s", invoice.ID, dunningErr.Message)
+	}
+
 	// Track payment — skip initial subscription invoice (already tracked by checkout)
 	if invoice.BillingReason != stripe.InvoiceBillingReasonSubscriptionCreate {
 		if trackErr := s.trackMembershipRenewal(&invoice, userID, eventTime); trackErr != nil {
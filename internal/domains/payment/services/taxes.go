@@ -0,0 +1,287 @@
+package payment
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	databaseErrors "api/internal/constants"
+	"api/internal/di"
+	staffActivityLogs "api/internal/domains/audit/staff_activity_logs/service"
+	locationValues "api/internal/domains/location/values"
+	db "api/internal/domains/payment/persistence/sqlc/generated"
+	stripeService "api/internal/domains/payment/services/stripe"
+	errLib "api/internal/libs/errors"
+	contextUtils "api/utils/context"
+	txUtils "api/utils/db"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
+)
+
+// TaxService manages the sales tax rates charged at checkout and reports the tax
+// collected for remittance.
+type TaxService struct {
+	queries                  *db.Queries
+	db                       *sql.DB
+	staffActivityLogsService *staffActivityLogs.Service
+}
+
+func NewTaxService(container *di.Container) *TaxService {
+	return &TaxService{
+		queries:                  db.New(container.DB),
+		db:                       container.DB,
+		staffActivityLogsService: staffActivityLogs.NewService(container),
+	}
+}
+
+// CreateTaxRateRequest adds a sales tax charged in a province
+type CreateTaxRateRequest struct {
+	Province           string  `json:"province" example:"BC"`                       // Two-letter province or territory code
+	Name               string  `json:"name" example:"PST"`                          // GST, PST, HST, QST or RST
+	Rate               float64 `json:"rate" example:"7"`                            // Percentage, e.g. 5 for 5%
+	RegistrationNumber string  `json:"registration_number" example:"PST-1234-5678"` // Printed on receipts
+}
+
+var (
+	validTaxProvinces = []string{"AB", "BC", "MB", "NB", "NL", "NS", "NT", "NU", "ON", "PE", "QC", "SK", "YT"}
+	validTaxNames     = []string{"GST", "PST", "HST", "QST", "RST"}
+)
+
+func (r *CreateTaxRateRequest) validate() *errLib.CommonError {
+	r.Province = strings.ToUpper(strings.TrimSpace(r.Province))
+	r.Name = strings.ToUpper(strings.TrimSpace(r.Name))
+	r.RegistrationNumber = strings.TrimSpace(r.RegistrationNumber)
+
+	if !slices.Contains(validTaxProvinces, r.Province) {
+		return errLib.New("province must be one of "+strings.Join(validTaxProvinces, ", "), http.StatusBadRequest)
+	}
+	if !slices.Contains(validTaxNames, r.Name) {
+		return errLib.New("name must be one of "+strings.Join(validTaxNames, ", "), http.StatusBadRequest)
+	}
+	if r.Rate <= 0 || r.Rate >= 100 {
+		return errLib.New("rate must be a percentage between 0 and 100", http.StatusBadRequest)
+	}
+	return nil
+}
+
+// CreateTaxRate creates the rate in Stripe and starts charging it on new checkouts in
+// the province. A province can charge each tax once; to change a rate, deactivate the
+// current one first.
+func (s *TaxService) CreateTaxRate(ctx context.Context, req CreateTaxRateRequest) (db.PaymentsTaxRate, *errLib.CommonError) {
+	staffID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return db.PaymentsTaxRate{}, err
+	}
+
+	if err := req.validate(); err != nil {
+		return db.PaymentsTaxRate{}, err
+	}
+
+	rate := decimal.NewFromFloat(req.Rate).Round(3)
+	percentage, _ := rate.Float64()
+
+	stripeTaxRateID, err := stripeService.CreateTaxRate(ctx, req.Name, req.Province, percentage, uuid.New().String())
+	if err != nil {
+		return db.PaymentsTaxRate{}, err
+	}
+
+	var created db.PaymentsTaxRate
+	txErr := txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		var dbErr error
+		created, dbErr = s.queries.WithTx(tx).CreateTaxRate(ctx, db.CreateTaxRateParams{
+			Province:           req.Province,
+			Name:               req.Name,
+			Rate:               rate,
+			RegistrationNumber: sql.NullString{String: req.RegistrationNumber, Valid: req.RegistrationNumber != ""},
+			StripeTaxRateID:    stripeTaxRateID,
+		})
+		if dbErr != nil {
+			var pqErr *pq.Error
+			if errors.As(dbErr, &pqErr) && pqErr.Code == databaseErrors.UniqueViolation {
+				return errLib.New(fmt.Sprintf("%s is already charged in %s; deactivate it first", req.Name, req.Province), http.StatusConflict)
+			}
+			log.Printf("[TAXES] Failed to save tax rate: %v", dbErr)
+			return errLib.New("Failed to save tax rate", http.StatusInternalServerError)
+		}
+
+		return s.staffActivityLogsService.InsertStaffActivity(ctx, tx, staffID,
+			fmt.Sprintf("Added %s %s%% in %s", created.Name, created.Rate.String(), created.Province))
+	})
+	if txErr != nil {
+		// Nothing charges the Stripe rate yet, so archive it rather than leave it offered
+		_ = stripeService.ArchiveTaxRate(stripeTaxRateID)
+		return db.PaymentsTaxRate{}, txErr
+	}
+
+	return created, nil
+}
+
+// DeactivateTaxRate stops charging a rate on new checkouts. Receipts and reports for
+// payments that were charged the rate are unaffected.
+func (s *TaxService) DeactivateTaxRate(ctx context.Context, id uuid.UUID) *errLib.CommonError {
+	staffID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	rate, dbErr := s.queries.GetTaxRate(ctx, id)
+	if dbErr != nil {
+		if errors.Is(dbErr, sql.ErrNoRows) {
+			return errLib.New("Tax rate not found", http.StatusNotFound)
+		}
+		log.Printf("[TAXES] Failed to get tax rate %s: %v", id, dbErr)
+		return errLib.New("Failed to get tax rate", http.StatusInternalServerError)
+	}
+
+	txErr := txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		rows, dbErr := s.queries.WithTx(tx).DeactivateTaxRate(ctx, id)
+		if dbErr != nil {
+			log.Printf("[TAXES] Failed to deactivate tax rate %s: %v", id, dbErr)
+			return errLib.New("Failed to deactivate tax rate", http.StatusInternalServerError)
+		}
+		if rows == 0 {
+			return errLib.New("Tax rate is already inactive", http.StatusConflict)
+		}
+
+		return s.staffActivityLogsService.InsertStaffActivity(ctx, tx, staffID,
+			fmt.Sprintf("Deactivated %s %s%% in %s", rate.Name, rate.Rate.String(), rate.Province))
+	})
+	if txErr != nil {
+		return txErr
+	}
+
+	// The rate is already off our checkouts; a failed archive only leaves it listed in Stripe
+	_ = stripeService.ArchiveTaxRate(rate.StripeTaxRateID)
+	return nil
+}
+
+func (s *TaxService) ListTaxRates(ctx context.Context, includeInactive bool) ([]db.PaymentsTaxRate, *errLib.CommonError) {
+	rates, err := s.queries.ListTaxRates(ctx, includeInactive)
+	if err != nil {
+		log.Printf("[TAXES] Failed to list tax rates: %v", err)
+		return nil, errLib.New("Failed to list tax rates", http.StatusInternalServerError)
+	}
+	return rates, nil
+}
+
+// TaxRateIDsForLocation returns the Stripe tax rates to charge on a purchase at a
+// facility. Purchases not tied to a facility are taxed in the default province. An
+// empty result leaves the checkout to Stripe's automatic tax.
+func (s *TaxService) TaxRateIDsForLocation(ctx context.Context, locationID uuid.NullUUID) ([]string, *errLib.CommonError) {
+	rates, err := s.queries.ListActiveTaxRatesForLocation(ctx, db.ListActiveTaxRatesForLocationParams{
+		LocationID:      locationID,
+		DefaultProvince: locationValues.DefaultProvince,
+	})
+	if err != nil {
+		log.Printf("[TAXES] Failed to look up tax rates for location %v: %v", locationID, err)
+		return nil, errLib.New("Failed to look up sales tax", http.StatusInternalServerError)
+	}
+
+	ids := make([]string, 0, len(rates))
+	for _, rate := range rates {
+		ids = append(ids, rate.StripeTaxRateID)
+	}
+	return ids, nil
+}
+
+// RemittanceLine is the tax owed for one tax over a reporting period
+type RemittanceLine struct {
+	Province         string          `json:"province"`
+	Name             string          `json:"name"`
+	Rate             decimal.Decimal `json:"rate"`
+	TransactionCount int64           `json:"transaction_count"`
+	TaxableAmount    decimal.Decimal `json:"taxable_amount"`
+	TaxCollected     decimal.Decimal `json:"tax_collected"`
+	TaxRefunded      decimal.Decimal `json:"tax_refunded"`
+	NetTax           decimal.Decimal `json:"net_tax"`
+}
+
+// RemittanceReport is the tax to remit for sales and refunds in [StartDate, EndDate)
+type RemittanceReport struct {
+	StartDate time.Time        `json:"start_date"`
+	EndDate   time.Time        `json:"end_date"`
+	Lines     []RemittanceLine `json:"lines"`
+	NetTax    decimal.Decimal  `json:"net_tax"`
+}
+
+// GetRemittanceReport totals the tax charged on sales in the period, less the tax
+// given back by refunds issued in the period, whenever the original sale was made.
+func (s *TaxService) GetRemittanceReport(ctx context.Context, start, end time.Time) (*RemittanceReport, *errLib.CommonError) {
+	if !end.After(start) {
+		return nil, errLib.New("end_date must be after start_date", http.StatusBadRequest)
+	}
+
+	collected, err := s.queries.GetTaxCollected(ctx, db.GetTaxCollectedParams{StartDate: start, EndDate: end})
+	if err != nil {
+		log.Printf("[TAXES] Failed to total tax collected: %v", err)
+		return nil, errLib.New("Failed to build remittance report", http.StatusInternalServerError)
+	}
+
+	refunded, err := s.queries.GetTaxRefunded(ctx, db.GetTaxRefundedParams{StartDate: start, EndDate: end})
+	if err != nil {
+		log.Printf("[TAXES] Failed to total tax refunded: %v", err)
+		return nil, errLib.New("Failed to build remittance report", http.StatusInternalServerError)
+	}
+
+	report := buildRemittanceReport(collected, refunded)
+	report.StartDate = start
+	report.EndDate = end
+	return report, nil
+}
+
+// buildRemittanceReport merges tax collected and tax refunded into one line per tax.
+// A tax can appear only in refunds when the sale was made in an earlier period.
+func buildRemittanceReport(collected []db.GetTaxCollectedRow, refunded []db.GetTaxRefundedRow) *RemittanceReport {
+	report := &RemittanceReport{Lines: []RemittanceLine{}, NetTax: decimal.Zero}
+	index := make(map[string]int)
+
+	key := func(province, name string, rate decimal.Decimal) string {
+		return province + "|" + name + "|" + rate.String()
+	}
+
+	for _, row := range collected {
+		index[key(row.Province, row.Name, row.Rate)] = len(report.Lines)
+		report.Lines = append(report.Lines, RemittanceLine{
+			Province:         row.Province,
+			Name:             row.Name,
+			Rate:             row.Rate,
+			TransactionCount: row.TransactionCount,
+			TaxableAmount:    row.TaxableAmount,
+			TaxCollected:     row.TaxAmount,
+			TaxRefunded:      decimal.Zero,
+		})
+	}
+
+	for _, row := range refunded {
+		k := key(row.Province, row.Name, row.Rate)
+		i, ok := index[k]
+		if !ok {
+			i = len(report.Lines)
+			index[k] = i
+			report.Lines = append(report.Lines, RemittanceLine{
+				Province:      row.Province,
+				Name:          row.Name,
+				Rate:          row.Rate,
+				TaxableAmount: decimal.Zero,
+				TaxCollected:  decimal.Zero,
+				TaxRefunded:   decimal.Zero,
+			})
+		}
+		report.Lines[i].TaxRefunded = report.Lines[i].TaxRefunded.Add(row.TaxAmount)
+	}
+
+	for i := range report.Lines {
+		report.Lines[i].NetTax = report.Lines[i].TaxCollected.Sub(report.Lines[i].TaxRefunded)
+		report.NetTax = report.NetTax.Add(report.Lines[i].NetTax)
+	}
+
+	return report
+}
@@ -0,0 +1,58 @@
+package payment
+
+import (
+	"testing"
+
+	db "api/internal/domains/payment/persistence/sqlc/generated"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildRemittanceReport(t *testing.T) {
+	d := decimal.RequireFromString
+
+	t.Run("Deducts refunded tax from tax collected", func(t *testing.T) {
+		collected := []db.GetTaxCollectedRow{
+			{Province: "AB", Name: "GST", Rate: d("5.000"), TransactionCount: 3, TaxableAmount: d("300.00"), TaxAmount: d("15.00")},
+			{Province: "BC", Name: "PST", Rate: d("7.000"), TransactionCount: 1, TaxableAmount: d("100.00"), TaxAmount: d("7.00")},
+		}
+		refunded := []db.GetTaxRefundedRow{
+			{Province: "AB", Name: "GST", Rate: d("5.000"), TaxAmount: d("2.50")},
+		}
+
+		report := buildRemittanceReport(collected, refunded)
+
+		require.Len(t, report.Lines, 2)
+		assert.True(t, report.Lines[0].TaxRefunded.Equal(d("2.50")))
+		assert.True(t, report.Lines[0].NetTax.Equal(d("12.50")))
+		assert.True(t, report.Lines[1].NetTax.Equal(d("7.00")))
+		assert.True(t, report.NetTax.Equal(d("19.50")))
+	})
+
+	t.Run("Reports refunds of sales made in an earlier period", func(t *testing.T) {
+		refunded := []db.GetTaxRefundedRow{
+			{Province: "AB", Name: "GST", Rate: d("5.000"), TaxAmount: d("1.00")},
+		}
+
+		report := buildRemittanceReport(nil, refunded)
+
+		require.Len(t, report.Lines, 1)
+		assert.True(t, report.Lines[0].TaxCollected.IsZero())
+		assert.True(t, report.Lines[0].NetTax.Equal(d("-1.00")))
+		assert.True(t, report.NetTax.Equal(d("-1.00")))
+	})
+
+	t.Run("Keeps old and new rates of a tax apart", func(t *testing.T) {
+		collected := []db.GetTaxCollectedRow{
+			{Province: "BC", Name: "PST", Rate: d("7.000"), TransactionCount: 1, TaxableAmount: d("100.00"), TaxAmount: d("7.00")},
+			{Province: "BC", Name: "PST", Rate: d("8.000"), TransactionCount: 1, TaxableAmount: d("100.00"), TaxAmount: d("8.00")},
+		}
+
+		report := buildRemittanceReport(collected, nil)
+
+		assert.Len(t, report.Lines, 2)
+		assert.True(t, report.NetTax.Equal(d("15.00")))
+	})
+}
@@ -244,6 +244,12 @@ func (r *WebhookRetryService) processRetryAttempt(attempt *RetryAttempt) {
 		err = r.webhookService.HandleInvoicePaymentSucceededWithSubsidy(ctx, attempt.Event)
 	case "invoice.payment_failed":
 		err = r.webhookService.HandleInvoicePaymentFailed(ctx, attempt.Event)
+	case "charge.refunded":
+		err = r.webhookService.HandleChargeRefunded(ctx, attempt.Event)
+	case "charge.dispute.created":
+		err = r.webhookService.HandleChargeDisputeCreated(ctx, attempt.Event)
+	case "charge.dispute.closed":
+		err = r.webhookService.HandleChargeDisputeClosed(ctx, attempt.Event)
 	default:
 		retryLogger.Warn("Unknown event type for retry, removing from queue")
 		r.RemoveRetry(attempt.EventID)
@@ -2,6 +2,9 @@ package payment
 
 import (
 	"api/internal/di"
+	courtRentalService "api/internal/domains/court_rental/service"
+	courtRentalValues "api/internal/domains/court_rental/values"
+	playgroundService "api/internal/domains/playground/services"
 	creditPackageRepo "api/internal/domains/credit_package/persistence/repository"
 	dbEnrollment "api/internal/domains/enrollment/persistence/sqlc/generated"
 	enrollment "api/internal/domains/enrollment/service"
@@ -9,6 +12,9 @@ import (
 	repository "api/internal/domains/payment/persistence/repositories"
 	"api/internal/domains/payment/tracking"
 	discountService "api/internal/domains/discount/service"
+	giftCardService "api/internal/domains/gift_card/service"
+	referralService "api/internal/domains/referral/service"
+	stripeService "api/internal/domains/payment/services/stripe"
 	"api/internal/domains/subsidy/dto"
 	subsidyService "api/internal/domains/subsidy/service"
 	userServices "api/internal/domains/user/services"
@@ -26,6 +32,7 @@ import (
 
 	identityRepo "api/internal/domains/identity/persistence/repository/user"
 	membershipRepo "api/internal/domains/membership/persistence/repositories"
+	playgroundValues "api/internal/domains/playground/values"
 	"api/utils/email"
 
 	"github.com/google/uuid"
@@ -48,6 +55,12 @@ type WebhookService struct {
 	SubsidyService         *subsidyService.SubsidyService
 	DiscountService        *discountService.Service
 	PaymentTracking        *tracking.PaymentTrackingService
+	CourtRentalService     *courtRentalService.Service
+	PlaygroundService      *playgroundService.Service
+	GiftCardService        *giftCardService.Service
+	ReferralService        *referralService.Service
+	RefundsService         *RefundsService
+	Dunning                *DunningService
 	Idempotency            *WebhookIdempotency
 	logger                 *logger.StructuredLogger
 	db                     *sql.DB
@@ -82,6 +95,12 @@ func NewWebhookService(container *di.Container) *WebhookService {
 		SubsidyService:         subsidyService.NewSubsidyService(container),
 		DiscountService:        discountService.NewService(container),
 		PaymentTracking:        tracking.NewPaymentTrackingService(container),
+		CourtRentalService:     courtRentalService.NewService(container),
+		PlaygroundService:      playgroundService.NewService(container),
+		GiftCardService:        giftCardService.NewService(container),
+		ReferralService:        referralService.NewService(container),
+		RefundsService:         NewRefundsService(container),
+		Dunning:                NewDunningService(container),
 		Idempotency:            NewWebhookIdempotencyWithDB(container.DB, 24*time.Hour, 10000), // Database-backed with cache
 		logger:                 logger.WithComponent("stripe-webhooks"),
 		db:                     container.DB,
@@ -185,6 +204,22 @@ func (s *WebhookService) handleItemCheckoutComplete(ctx context.Context, checkou
 		return errLib.New("Invalid user ID format", http.StatusBadRequest)
 	}
 
+	if err := s.captureBalanceHold(ctx, fullSession.Metadata); err != nil {
+		return err
+	}
+
+	// Gift cards, court rentals and playground sessions are charged an ad-hoc amount, so
+	// their line items carry no known price
+	if cardIDStr := fullSession.Metadata["giftCardID"]; cardIDStr != "" {
+		return s.handleGiftCardCheckoutComplete(ctx, fullSession, customerID, cardIDStr, eventCreatedAt, receiptURL)
+	}
+	if rentalIDStr := fullSession.Metadata["courtRentalID"]; rentalIDStr != "" {
+		return s.handleCourtRentalCheckoutComplete(ctx, fullSession, customerID, rentalIDStr, eventCreatedAt, receiptURL)
+	}
+	if sessionIDStr := fullSession.Metadata["playgroundSessionID"]; sessionIDStr != "" {
+		return s.handlePlaygroundCheckoutComplete(ctx, fullSession, customerID, sessionIDStr, eventCreatedAt, receiptURL)
+	}
+
 	priceIDs, err := s.validateLineItems(fullSession.LineItems)
 	if err != nil {
 		log.Printf("validateLineItems failed: %v", err)
@@ -254,7 +289,7 @@ func (s *WebhookService) handleItemCheckoutComplete(ctx context.Context, checkou
 
 		// Add credits to customer balance
 		log.Printf("Adding %d credits to customer %s balance", creditPackage.CreditAllocation, customerID)
-		if err := s.CustomerCreditService.AddCredits(ctx, customerID, creditPackage.CreditAllocation, "Credit package purchase"); err != nil {
+		if err := s.CustomerCreditService.AddPurchasedCredits(ctx, customerID, creditPackage.CreditAllocation, creditPackage.CreditValidityDays, "Credit package purchase"); err != nil {
 			log.Printf("FAILED to add credits to customer %s: %v", customerID, err)
 			return errLib.New(fmt.Sprintf("failed to add credits: %v", err), http.StatusInternalServerError)
 		}
@@ -332,6 +367,11 @@ func (s *WebhookService) handleSubscriptionCheckoutComplete(ctx context.Context,
 		"customer_id": userID,
 	})
 
+	if err := s.captureBalanceHold(ctx, fullSession.Metadata); err != nil {
+		webhookLogger.Error("Failed to capture gift card balance hold", err)
+		return err
+	}
+
 	// Get plan ID from metadata (preferred) or fall back to price lookup for backwards compatibility
 	var planID uuid.UUID
 	var amtPeriods *int32
@@ -444,6 +484,13 @@ func (s *WebhookService) handleSubscriptionCheckoutComplete(ctx context.Context,
 	// Increment discount usage now that checkout succeeded
 	s.incrementDiscountUsageFromMetadata(ctx, fullSession.Metadata, userID)
 
+	// invoice.payment_succeeded can arrive before the Stripe customer ID above was stored
+	// and miss the customer, so the first invoice qualifies a referral here as well
+	if fullSession.PaymentStatus == stripe.CheckoutSessionPaymentStatusPaid && fullSession.AmountTotal > 0 &&
+		fullSession.Subscription != nil && fullSession.Subscription.LatestInvoice != nil {
+		s.qualifyReferral(ctx, userID, fullSession.Subscription.LatestInvoice.ID)
+	}
+
 	// Track payment in centralized system
 	safeGo("trackMembershipSubscription", func() { s.trackMembershipSubscription(fullSession, userID, planID, eventCreatedAt) })
 
@@ -451,6 +498,14 @@ func (s *WebhookService) handleSubscriptionCheckoutComplete(ctx context.Context,
 	return nil
 }
 
+// qualifyReferral qualifies the customer's pending referral, if any, and issues its
+// rewards. Rewards that fail are retried by the referral job, so errors only get logged.
+func (s *WebhookService) qualifyReferral(ctx context.Context, userID uuid.UUID, invoiceID string) {
+	if err := s.ReferralService.QualifyReferral(ctx, userID, invoiceID); err != nil {
+		log.Printf("[WEBHOOK] Failed to qualify referral of %s for invoice %s: %s", userID, invoiceID, err.Message)
+	}
+}
+
 // incrementDiscountUsageFromMetadata increments discount usage if discount metadata is present in the checkout session.
 // Called after a successful checkout to ensure usage is only counted when payment actually happened.
 func (s *WebhookService) incrementDiscountUsageFromMetadata(ctx context.Context, metadata map[string]string, customerID uuid.UUID) {
@@ -600,6 +655,135 @@ func (s *WebhookService) getExpandedSession(sessionID string) (*stripe.CheckoutS
 	return checkoutSession, nil
 }
 
+// handleCourtRentalCheckoutComplete confirms a court rental once its card payment completes.
+func (s *WebhookService) handleCourtRentalCheckoutComplete(ctx context.Context, fullSession *stripe.CheckoutSession, customerID uuid.UUID, rentalIDStr string, eventCreatedAt time.Time, receiptURL string) *errLib.CommonError {
+	rentalID, uuidErr := uuid.Parse(rentalIDStr)
+	if uuidErr != nil {
+		log.Printf("Invalid court rental ID in metadata: %v", uuidErr)
+		return errLib.New("Invalid court rental ID format", http.StatusBadRequest)
+	}
+
+	log.Printf("Confirming court rental %s for user %s", rentalID, customerID)
+	status, err := s.CourtRentalService.ConfirmPaid(ctx, rentalID, customerID)
+	if err != nil {
+		log.Printf("Failed to confirm court rental %s: %v", rentalID, err)
+		return errLib.New(fmt.Sprintf("failed to confirm court rental (customer: %s, rental: %s): %v", customerID, rentalID, err), err.HTTPCode)
+	}
+
+	if fullSession.Customer != nil && fullSession.Customer.ID != "" {
+		if err := s.storeStripeCustomerID(customerID, fullSession.Customer.ID); err != nil {
+			log.Printf("WARNING: Failed to store Stripe customer ID for court rental: %v", err)
+		}
+	}
+
+	track := func() {
+		s.trackBookingPayment(fullSession, customerID, "court_rental", "Court rental payment", "court_rental_id", rentalID, eventCreatedAt, receiptURL)
+	}
+
+	switch status {
+	case courtRentalValues.StatusRefundDue:
+		// The court was taken after the hold lapsed; give the money back. The payment is
+		// tracked first so the refund is recorded against it.
+		s.RefundsService.RefundUnconfirmedBooking(ctx, fullSession, "court_rental", rentalID, track, s.CourtRentalService.MarkRefunded)
+	case courtRentalValues.StatusRefunded:
+		// Refunded by an earlier delivery or by checkout verification
+	default:
+		// Track payment in centralized system
+		safeGo("trackCourtRental", track)
+	}
+
+	return nil
+}
+
+// handleGiftCardCheckoutComplete issues a gift card bought online once its payment completes.
+func (s *WebhookService) handleGiftCardCheckoutComplete(ctx context.Context, fullSession *stripe.CheckoutSession, customerID uuid.UUID, cardIDStr string, eventCreatedAt time.Time, receiptURL string) *errLib.CommonError {
+	cardID, uuidErr := uuid.Parse(cardIDStr)
+	if uuidErr != nil {
+		log.Printf("Invalid gift card ID in metadata: %v", uuidErr)
+		return errLib.New("Invalid gift card ID format", http.StatusBadRequest)
+	}
+
+	log.Printf("Issuing gift card %s bought by user %s", cardID, customerID)
+	if err := s.GiftCardService.IssuePurchased(ctx, cardID, fullSession.ID); err != nil {
+		log.Printf("Failed to issue gift card %s: %v", cardID, err)
+		return errLib.New(fmt.Sprintf("failed to issue gift card (customer: %s, card: %s): %v", customerID, cardID, err), err.HTTPCode)
+	}
+
+	if fullSession.Customer != nil && fullSession.Customer.ID != "" {
+		if err := s.storeStripeCustomerID(customerID, fullSession.Customer.ID); err != nil {
+			log.Printf("WARNING: Failed to store Stripe customer ID for gift card: %v", err)
+		}
+	}
+
+	// Track payment in centralized system
+	safeGo("trackGiftCard", func() {
+		s.trackBookingPayment(fullSession, customerID, "gift_card", "Gift card purchase", "gift_card_id", cardID, eventCreatedAt, receiptURL)
+	})
+
+	return nil
+}
+
+// captureBalanceHold spends the gift card balance a paid checkout held, if any.
+// Capturing is idempotent, so retried webhooks are safe.
+func (s *WebhookService) captureBalanceHold(ctx context.Context, metadata map[string]string) *errLib.CommonError {
+	holdIDStr := metadata[stripeService.StoredValueHoldMetadataKey]
+	if holdIDStr == "" {
+		return nil
+	}
+
+	holdID, uuidErr := uuid.Parse(holdIDStr)
+	if uuidErr != nil {
+		log.Printf("Invalid gift card balance hold ID in metadata: %v", uuidErr)
+		return errLib.New("Invalid balance hold ID format", http.StatusBadRequest)
+	}
+
+	if err := s.GiftCardService.CaptureHold(ctx, holdID); err != nil {
+		log.Printf("Failed to capture gift card balance hold %s: %v", holdID, err)
+		return err
+	}
+	return nil
+}
+
+// handlePlaygroundCheckoutComplete confirms a playground session once its card payment completes.
+func (s *WebhookService) handlePlaygroundCheckoutComplete(ctx context.Context, fullSession *stripe.CheckoutSession, customerID uuid.UUID, sessionIDStr string, eventCreatedAt time.Time, receiptURL string) *errLib.CommonError {
+	sessionID, uuidErr := uuid.Parse(sessionIDStr)
+	if uuidErr != nil {
+		log.Printf("Invalid playground session ID in metadata: %v", uuidErr)
+		return errLib.New("Invalid playground session ID format", http.StatusBadRequest)
+	}
+
+	log.Printf("Confirming playground session %s for user %s", sessionID, customerID)
+	status, err := s.PlaygroundService.ConfirmPaid(ctx, sessionID, customerID)
+	if err != nil {
+		log.Printf("Failed to confirm playground session %s: %v", sessionID, err)
+		return errLib.New(fmt.Sprintf("failed to confirm playground session (customer: %s, session: %s): %v", customerID, sessionID, err), err.HTTPCode)
+	}
+
+	if fullSession.Customer != nil && fullSession.Customer.ID != "" {
+		if err := s.storeStripeCustomerID(customerID, fullSession.Customer.ID); err != nil {
+			log.Printf("WARNING: Failed to store Stripe customer ID for playground session: %v", err)
+		}
+	}
+
+	track := func() {
+		s.trackBookingPayment(fullSession, customerID, "playground_session", "Playground session payment", "playground_session_id", sessionID, eventCreatedAt, receiptURL)
+	}
+
+	switch status {
+	case playgroundValues.StatusRefundDue:
+		// The session could not be booked after its hold lapsed; give the money back. The
+		// payment is tracked first so the refund is recorded against it.
+		s.RefundsService.RefundUnconfirmedBooking(ctx, fullSession, "playground_session", sessionID, track, s.PlaygroundService.MarkRefunded)
+	case playgroundValues.StatusRefunded:
+		// Refunded by an earlier delivery or by checkout verification
+	default:
+		// Track payment in centralized system
+		safeGo("trackPlaygroundSession", track)
+	}
+
+	return nil
+}
+
 func (s *WebhookService) validateLineItems(lineItems *stripe.LineItemList) ([]string, *errLib.CommonError) {
 	if lineItems == nil || len(lineItems.Data) == 0 {
 		return nil, errLib.New("No line items found in session", http.StatusBadRequest)
@@ -890,6 +1074,12 @@ func (s *WebhookService) HandleSubscriptionUpdated(ctx context.Context, event st
 	} else {
 		switch sub.Status {
 		case stripe.SubscriptionStatusActive:
+			// A frozen membership stays active in Stripe with its collection paused
+			if sub.PauseCollection != nil && sub.Metadata["freeze_id"] != "" {
+				log.Printf("[WEBHOOK] Subscription %s is frozen (freeze %s)", sub.ID, sub.Metadata["freeze_id"])
+				dbStatus = "paused"
+				break
+			}
 			log.Printf("[WEBHOOK] Subscription %s is now active", sub.ID)
 			dbStatus = "active"
 		case stripe.SubscriptionStatusPastDue:
@@ -1061,6 +1251,16 @@ func (s *WebhookService) HandleInvoicePaymentSucceeded(ctx context.Context, even
 
 	log.Printf("[WEBHOOK] Successfully activated membership for user %s after invoice payment %s", userID, invoice.ID)
 
+	// A paid invoice ends any dunning of it, lifting booking restrictions and dunning suspensions
+	if dunningErr := s.Dunning.ResolveInvoice(ctx, invoice.ID, subscriptionID); dunningErr != nil {
+		log.Printf("[WEBHOOK] Failed to resolve dunning for invoice %s: %s", invoice.ID, dunningErr.Message)
+	}
+
+	// The customer's first paid membership invoice qualifies the referral they signed up with
+	if subscriptionID != "" && invoice.AmountPaid > 0 {
+		s.qualifyReferral(ctx, userID, invoice.ID)
+	}
+
 	// Track payment in centralized system — but skip the initial subscription invoice
 	// because checkout.session.completed already tracks that via trackMembershipSubscription.
 	// Only track actual renewals to avoid duplicate payment records.
@@ -1141,6 +1341,11 @@ func (s *WebhookService) HandleInvoicePaymentFailed(ctx context.Context, event s
 
 	log.Printf("[WEBHOOK] Successfully marked subscription %s as past_due for user %s after payment failure %s", subscriptionID, userID, invoice.ID)
 
+	// Start the dunning schedule; Stripe retries of the same invoice keep the first case
+	if dunningErr := s.Dunning.OpenCase(ctx, userID, &invoice, time.Unix(event.Created, 0)); dunningErr != nil {
+		log.Printf("[WEBHOOK] Failed to open dunning case for invoice %s: %s", invoice.ID, dunningErr.Message)
+	}
+
 	// Track the failed payment in the centralized payment system
 	safeGo("trackFailedPayment", func() { s.trackFailedPayment(&invoice, userID, time.Unix(event.Created, 0)) })
 
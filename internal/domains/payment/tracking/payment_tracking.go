@@ -33,6 +33,14 @@ func NewPaymentTrackingService(container *di.Container) *PaymentTrackingService
 	}
 }
 
+// WithTx returns a copy of the service whose queries run inside tx
+func (s *PaymentTrackingService) WithTx(tx *sql.Tx) *PaymentTrackingService {
+	return &PaymentTrackingService{
+		queries: s.queries.WithTx(tx),
+		db:      s.db,
+	}
+}
+
 // TrackPaymentParams contains all the details needed to track a payment
 type TrackPaymentParams struct {
 	CustomerID    uuid.UUID
@@ -152,6 +160,10 @@ func (s *PaymentTrackingService) TrackPayment(ctx context.Context, params TrackP
 	// Auto-backfill URLs (same logic as POST /admin/payments/backfill-urls)
 	go s.BackfillTransactionURLs(transaction.ID)
 
+	if params.PaymentStatus == "completed" {
+		go s.BackfillTransactionTaxes(transaction.ID)
+	}
+
 	return &transaction, nil
 }
 
@@ -332,6 +344,86 @@ func (s *PaymentTrackingService) BackfillTransactionURLs(transactionID uuid.UUID
 	}
 }
 
+// BackfillTransactionTaxes records the tax breakdown of a payment from its Stripe
+// invoice or checkout session. Taxes already recorded for the payment are kept.
+func (s *PaymentTrackingService) BackfillTransactionTaxes(transactionID uuid.UUID) {
+	ctx := context.Background()
+
+	tx, err := s.queries.GetPaymentTransaction(ctx, transactionID)
+	if err != nil {
+		log.Printf("[PAYMENT-TAXES] Error fetching transaction %s: %v", transactionID, err)
+		return
+	}
+
+	type taxLine struct {
+		rate          *stripe.TaxRate
+		taxableAmount int64
+		amount        int64
+	}
+	var lines []taxLine
+
+	switch {
+	case tx.StripeInvoiceID.Valid && tx.StripeInvoiceID.String != "":
+		inv, invErr := invoice.Get(tx.StripeInvoiceID.String, &stripe.InvoiceParams{
+			Expand: []*string{stripe.String("total_tax_amounts.tax_rate")},
+		})
+		if invErr != nil {
+			log.Printf("[PAYMENT-TAXES] Error fetching Invoice %s: %v", tx.StripeInvoiceID.String, invErr)
+			return
+		}
+		for _, tax := range inv.TotalTaxAmounts {
+			lines = append(lines, taxLine{rate: tax.TaxRate, taxableAmount: tax.TaxableAmount, amount: tax.Amount})
+		}
+	case tx.StripeCheckoutSessionID.Valid && tx.StripeCheckoutSessionID.String != "":
+		sess, sessErr := session.Get(tx.StripeCheckoutSessionID.String, &stripe.CheckoutSessionParams{
+			Expand: []*string{stripe.String("total_details.breakdown")},
+		})
+		if sessErr != nil {
+			log.Printf("[PAYMENT-TAXES] Error fetching CheckoutSession %s: %v", tx.StripeCheckoutSessionID.String, sessErr)
+			return
+		}
+		if sess.TotalDetails != nil && sess.TotalDetails.Breakdown != nil {
+			for _, tax := range sess.TotalDetails.Breakdown.Taxes {
+				lines = append(lines, taxLine{rate: tax.Rate, taxableAmount: tax.TaxableAmount, amount: tax.Amount})
+			}
+		}
+	default:
+		return
+	}
+
+	for _, line := range lines {
+		if line.rate == nil || line.amount == 0 {
+			continue
+		}
+
+		params := db.CreateTransactionTaxParams{
+			TransactionID: tx.ID,
+			Name:          line.rate.DisplayName,
+			Province:      line.rate.State,
+			Rate:          decimal.NewFromFloat(line.rate.Percentage).Round(3),
+			TaxableAmount: decimal.New(line.taxableAmount, -2),
+			Amount:        decimal.New(line.amount, -2),
+		}
+
+		// Rates we configured carry their own name and province
+		if rate, rateErr := s.queries.GetTaxRateByStripeID(ctx, line.rate.ID); rateErr == nil {
+			params.TaxRateID = uuid.NullUUID{UUID: rate.ID, Valid: true}
+			params.Name = rate.Name
+			params.Province = rate.Province
+			params.Rate = rate.Rate
+		}
+
+		if err := s.queries.CreateTransactionTax(ctx, params); err != nil {
+			log.Printf("[PAYMENT-TAXES] Error recording %s for transaction %s: %v", params.Name, tx.ID, err)
+			return
+		}
+	}
+
+	if len(lines) > 0 {
+		log.Printf("✅ [PAYMENT-TAXES] Recorded %d tax line(s) for transaction %s", len(lines), tx.ID)
+	}
+}
+
 // Helper functions
 func uuidToNullUUID(u *uuid.UUID) uuid.NullUUID {
 	if u == nil {
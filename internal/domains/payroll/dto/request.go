@@ -0,0 +1,194 @@
+package payroll
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	values "api/internal/domains/payroll/values"
+	errLib "api/internal/libs/errors"
+	"api/internal/libs/validators"
+
+	"github.com/google/uuid"
+)
+
+const dateLayout = "2006-01-02"
+
+func parseDate(field, value string) (time.Time, *errLib.CommonError) {
+	parsed, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return time.Time{}, errLib.New("Invalid "+field+": must be a YYYY-MM-DD date", http.StatusBadRequest)
+	}
+	return parsed, nil
+}
+
+// PayRateRequestDto sets what a staff member is paid, for all their sessions or only
+// for one program's.
+type PayRateRequestDto struct {
+	StaffID       uuid.UUID  `json:"staff_id" validate:"required" example:"f0e21457-75d4-4de6-b765-5ee13221fd72"` // Ignored on update
+	RateType      string     `json:"rate_type" validate:"required,oneof=hourly per_session" example:"hourly"`
+	ProgramID     *uuid.UUID `json:"program_id,omitempty"` // Ignored on update; omit for the default rate
+	AmountCents   int32      `json:"amount_cents" validate:"gte=0" example:"2500"`
+	EffectiveFrom string     `json:"effective_from" validate:"required" example:"2026-04-01"`
+	EffectiveTo   *string    `json:"effective_to,omitempty" example:"2026-12-31"` // Last day, inclusive; omit while current
+}
+
+func (dto *PayRateRequestDto) ToValues() (values.PayRateValues, *errLib.CommonError) {
+	if err := validators.ValidateDto(dto); err != nil {
+		return values.PayRateValues{}, err
+	}
+	from, err := parseDate("effective_from", dto.EffectiveFrom)
+	if err != nil {
+		return values.PayRateValues{}, err
+	}
+	rate := values.PayRateValues{
+		StaffID:       dto.StaffID,
+		RateType:      dto.RateType,
+		ProgramID:     dto.ProgramID,
+		AmountCents:   dto.AmountCents,
+		EffectiveFrom: from,
+	}
+	if dto.EffectiveTo != nil {
+		to, err := parseDate("effective_to", *dto.EffectiveTo)
+		if err != nil {
+			return values.PayRateValues{}, err
+		}
+		rate.EffectiveTo = &to
+	}
+	return rate, nil
+}
+
+// EmployeeRequestDto sets the ID the payroll provider knows a staff member by.
+type EmployeeRequestDto struct {
+	ExternalEmployeeID string `json:"external_employee_id" validate:"required,max=50" example:"E1042"`
+}
+
+func (dto *EmployeeRequestDto) Validate() *errLib.CommonError {
+	return validators.ValidateDto(dto)
+}
+
+// PayPeriodRequestDto creates a pay period. Later periods of the same length are
+// created automatically as time passes.
+type PayPeriodRequestDto struct {
+	StartsOn string `json:"starts_on" validate:"required" example:"2026-04-06"`
+	EndsOn   string `json:"ends_on" validate:"required" example:"2026-04-19"` // Inclusive
+}
+
+func (dto *PayPeriodRequestDto) ToDates() (time.Time, time.Time, *errLib.CommonError) {
+	if err := validators.ValidateDto(dto); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	startsOn, err := parseDate("starts_on", dto.StartsOn)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	endsOn, err := parseDate("ends_on", dto.EndsOn)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return startsOn, endsOn, nil
+}
+
+// RejectTimesheetRequestDto says why a timesheet was sent back.
+type RejectTimesheetRequestDto struct {
+	Reason string `json:"reason" validate:"required,max=1000" example:"Missing Saturday's practice"`
+}
+
+func (dto *RejectTimesheetRequestDto) ToValues(timesheetID, reviewerID uuid.UUID) (values.ReviewValues, *errLib.CommonError) {
+	if err := validators.ValidateDto(dto); err != nil {
+		return values.ReviewValues{}, err
+	}
+	return values.ReviewValues{
+		TimesheetID: timesheetID,
+		ReviewerID:  reviewerID,
+		Reason:      &dto.Reason,
+	}, nil
+}
+
+// AdjustmentRequestDto corrects a timesheet's pay. Negative amounts are deductions.
+type AdjustmentRequestDto struct {
+	AmountCents int32  `json:"amount_cents" validate:"required" example:"1500"`
+	Reason      string `json:"reason" validate:"required,max=500" example:"Set up the gym before the tournament"`
+}
+
+func (dto *AdjustmentRequestDto) ToValues(timesheetID uuid.UUID) (values.AdjustmentValues, *errLib.CommonError) {
+	if err := validators.ValidateDto(dto); err != nil {
+		return values.AdjustmentValues{}, err
+	}
+	return values.AdjustmentValues{
+		TimesheetID: timesheetID,
+		AmountCents: dto.AmountCents,
+		Reason:      dto.Reason,
+	}, nil
+}
+
+// CheckInRequestDto names the session a staff member is checking in to or out of.
+type CheckInRequestDto struct {
+	SourceType string    `json:"source_type" validate:"required,oneof=event practice game" example:"event"`
+	SourceID   uuid.UUID `json:"source_id" validate:"required"`
+}
+
+func (dto *CheckInRequestDto) ToValues(staffID uuid.UUID) (values.CheckInValues, *errLib.CommonError) {
+	if err := validators.ValidateDto(dto); err != nil {
+		return values.CheckInValues{}, err
+	}
+	return values.CheckInValues{
+		StaffID:    staffID,
+		SourceType: dto.SourceType,
+		SourceID:   dto.SourceID,
+	}, nil
+}
+
+func parsePage(query url.Values, limit int32) (int32, int32) {
+	offset := int32(0)
+	if limitStr := query.Get("limit"); limitStr != "" {
+		if parsed, parseErr := strconv.Atoi(limitStr); parseErr == nil && parsed > 0 && parsed <= 100 {
+			limit = int32(parsed)
+		}
+	}
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		if parsed, parseErr := strconv.Atoi(offsetStr); parseErr == nil && parsed >= 0 {
+			offset = int32(parsed)
+		}
+	}
+	return limit, offset
+}
+
+// ParsePeriodListQuery reads the pay period list filter.
+func ParsePeriodListQuery(query url.Values) (string, int32, int32, *errLib.CommonError) {
+	status := query.Get("status")
+	switch status {
+	case "", values.PeriodOpen, values.PeriodClosed:
+	default:
+		return "", 0, 0, errLib.New("Invalid status", http.StatusBadRequest)
+	}
+	limit, offset := parsePage(query, 20)
+	return status, limit, offset, nil
+}
+
+// ParseTimesheetListQuery reads the timesheet list filter.
+func ParseTimesheetListQuery(query url.Values) (values.TimesheetFilter, *errLib.CommonError) {
+	filter := values.TimesheetFilter{Status: query.Get("status")}
+	switch filter.Status {
+	case "", values.TimesheetDraft, values.TimesheetSubmitted, values.TimesheetApproved, values.TimesheetRejected:
+	default:
+		return values.TimesheetFilter{}, errLib.New("Invalid status", http.StatusBadRequest)
+	}
+	if periodStr := query.Get("pay_period_id"); periodStr != "" {
+		id, err := validators.ParseUUID(periodStr)
+		if err != nil {
+			return values.TimesheetFilter{}, err
+		}
+		filter.PayPeriodID = id
+	}
+	if staffStr := query.Get("staff_id"); staffStr != "" {
+		id, err := validators.ParseUUID(staffStr)
+		if err != nil {
+			return values.TimesheetFilter{}, err
+		}
+		filter.StaffID = id
+	}
+	filter.Limit, filter.Offset = parsePage(query, 50)
+	return filter, nil
+}
@@ -0,0 +1,225 @@
+package payroll
+
+import (
+	"time"
+
+	values "api/internal/domains/payroll/values"
+
+	"github.com/google/uuid"
+)
+
+type PayRateResponseDto struct {
+	ID            uuid.UUID  `json:"id"`
+	StaffID       uuid.UUID  `json:"staff_id"`
+	RateType      string     `json:"rate_type" example:"hourly"`
+	ProgramID     *uuid.UUID `json:"program_id,omitempty"`
+	AmountCents   int32      `json:"amount_cents" example:"2500"`
+	EffectiveFrom string     `json:"effective_from" example:"2026-04-01"`
+	EffectiveTo   *string    `json:"effective_to,omitempty" example:"2026-12-31"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+type PayPeriodResponseDto struct {
+	ID        uuid.UUID  `json:"id"`
+	StartsOn  string     `json:"starts_on" example:"2026-04-06"`
+	EndsOn    string     `json:"ends_on" example:"2026-04-19"`
+	Status    string     `json:"status" example:"open"`
+	ClosedAt  *time.Time `json:"closed_at,omitempty"`
+	ClosedBy  *uuid.UUID `json:"closed_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type GenerateResponseDto struct {
+	Generated int `json:"generated" example:"12"` // Timesheets regenerated
+}
+
+type TimesheetResponseDto struct {
+	ID                 uuid.UUID  `json:"id"`
+	PayPeriodID        uuid.UUID  `json:"pay_period_id"`
+	StartsOn           string     `json:"starts_on" example:"2026-04-06"`
+	EndsOn             string     `json:"ends_on" example:"2026-04-19"`
+	StaffID            uuid.UUID  `json:"staff_id"`
+	FirstName          string     `json:"first_name"`
+	LastName           string     `json:"last_name"`
+	ExternalEmployeeID *string    `json:"external_employee_id,omitempty"`
+	Status             string     `json:"status" example:"submitted"`
+	SubmittedAt        *time.Time `json:"submitted_at,omitempty"`
+	ReviewedAt         *time.Time `json:"reviewed_at,omitempty"`
+	ReviewedBy         *uuid.UUID `json:"reviewed_by,omitempty"`
+	RejectionReason    *string    `json:"rejection_reason,omitempty"`
+	GeneratedAt        time.Time  `json:"generated_at"`
+	WorkedMinutes      int32      `json:"worked_minutes" example:"1230"`
+	EntriesCents       int32      `json:"entries_cents" example:"51250"`
+	AdjustmentsCents   int32      `json:"adjustments_cents" example:"1500"`
+	TotalCents         int32      `json:"total_cents" example:"52750"`
+}
+
+type TimesheetEntryResponseDto struct {
+	ID             uuid.UUID  `json:"id"`
+	SourceType     string     `json:"source_type" example:"event"`
+	SourceID       uuid.UUID  `json:"source_id"`
+	ProgramID      *uuid.UUID `json:"program_id,omitempty"`
+	Description    string     `json:"description" example:"U14 Skills Clinic"`
+	ScheduledStart time.Time  `json:"scheduled_start"`
+	ScheduledEnd   time.Time  `json:"scheduled_end"`
+	CheckedInAt    *time.Time `json:"checked_in_at,omitempty"`
+	CheckedOutAt   *time.Time `json:"checked_out_at,omitempty"`
+	WorkedMinutes  int32      `json:"worked_minutes" example:"90"`
+	RateType       *string    `json:"rate_type,omitempty" example:"hourly"` // Omitted when no rate applied
+	RateCents      *int32     `json:"rate_cents,omitempty" example:"2500"`
+	AmountCents    int32      `json:"amount_cents" example:"3750"`
+}
+
+type AdjustmentResponseDto struct {
+	ID          uuid.UUID  `json:"id"`
+	TimesheetID uuid.UUID  `json:"timesheet_id"`
+	AmountCents int32      `json:"amount_cents" example:"1500"`
+	Reason      string     `json:"reason"`
+	CreatedBy   *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type TimesheetDetailResponseDto struct {
+	TimesheetResponseDto
+	Entries     []TimesheetEntryResponseDto `json:"entries"`
+	Adjustments []AdjustmentResponseDto     `json:"adjustments"`
+}
+
+type CheckInResponseDto struct {
+	SourceType   string     `json:"source_type" example:"event"`
+	SourceID     uuid.UUID  `json:"source_id"`
+	CheckedInAt  time.Time  `json:"checked_in_at"`
+	CheckedOutAt *time.Time `json:"checked_out_at,omitempty"`
+}
+
+func formatDate(t time.Time) string {
+	return t.Format(dateLayout)
+}
+
+func NewPayRateResponse(rate values.PayRate) PayRateResponseDto {
+	response := PayRateResponseDto{
+		ID:            rate.ID,
+		StaffID:       rate.StaffID,
+		RateType:      rate.RateType,
+		ProgramID:     rate.ProgramID,
+		AmountCents:   rate.AmountCents,
+		EffectiveFrom: formatDate(rate.EffectiveFrom),
+		CreatedAt:     rate.CreatedAt,
+		UpdatedAt:     rate.UpdatedAt,
+	}
+	if rate.EffectiveTo != nil {
+		effectiveTo := formatDate(*rate.EffectiveTo)
+		response.EffectiveTo = &effectiveTo
+	}
+	return response
+}
+
+func NewPayRateResponses(rates []values.PayRate) []PayRateResponseDto {
+	responses := make([]PayRateResponseDto, len(rates))
+	for i, rate := range rates {
+		responses[i] = NewPayRateResponse(rate)
+	}
+	return responses
+}
+
+func NewPayPeriodResponse(period values.PayPeriod) PayPeriodResponseDto {
+	return PayPeriodResponseDto{
+		ID:        period.ID,
+		StartsOn:  formatDate(period.StartsOn),
+		EndsOn:    formatDate(period.EndsOn),
+		Status:    period.Status,
+		ClosedAt:  period.ClosedAt,
+		ClosedBy:  period.ClosedBy,
+		CreatedAt: period.CreatedAt,
+	}
+}
+
+func NewPayPeriodResponses(periods []values.PayPeriod) []PayPeriodResponseDto {
+	responses := make([]PayPeriodResponseDto, len(periods))
+	for i, period := range periods {
+		responses[i] = NewPayPeriodResponse(period)
+	}
+	return responses
+}
+
+func NewTimesheetResponse(timesheet values.Timesheet) TimesheetResponseDto {
+	return TimesheetResponseDto{
+		ID:                 timesheet.ID,
+		PayPeriodID:        timesheet.PayPeriodID,
+		StartsOn:           formatDate(timesheet.StartsOn),
+		EndsOn:             formatDate(timesheet.EndsOn),
+		StaffID:            timesheet.StaffID,
+		FirstName:          timesheet.FirstName,
+		LastName:           timesheet.LastName,
+		ExternalEmployeeID: timesheet.ExternalEmployeeID,
+		Status:             timesheet.Status,
+		SubmittedAt:        timesheet.SubmittedAt,
+		ReviewedAt:         timesheet.ReviewedAt,
+		ReviewedBy:         timesheet.ReviewedBy,
+		RejectionReason:    timesheet.RejectionReason,
+		GeneratedAt:        timesheet.GeneratedAt,
+		WorkedMinutes:      timesheet.WorkedMinutes,
+		EntriesCents:       timesheet.EntriesCents,
+		AdjustmentsCents:   timesheet.AdjustmentsCents,
+		TotalCents:         timesheet.TotalCents(),
+	}
+}
+
+func NewTimesheetResponses(timesheets []values.Timesheet) []TimesheetResponseDto {
+	responses := make([]TimesheetResponseDto, len(timesheets))
+	for i, timesheet := range timesheets {
+		responses[i] = NewTimesheetResponse(timesheet)
+	}
+	return responses
+}
+
+func NewAdjustmentResponse(adjustment values.Adjustment) AdjustmentResponseDto {
+	return AdjustmentResponseDto{
+		ID:          adjustment.ID,
+		TimesheetID: adjustment.TimesheetID,
+		AmountCents: adjustment.AmountCents,
+		Reason:      adjustment.Reason,
+		CreatedBy:   adjustment.CreatedBy,
+		CreatedAt:   adjustment.CreatedAt,
+	}
+}
+
+func NewTimesheetDetailResponse(detail values.TimesheetDetail) TimesheetDetailResponseDto {
+	entries := make([]TimesheetEntryResponseDto, len(detail.Entries))
+	for i, entry := range detail.Entries {
+		entries[i] = TimesheetEntryResponseDto{
+			ID:             entry.ID,
+			SourceType:     entry.SourceType,
+			SourceID:       entry.SourceID,
+			ProgramID:      entry.ProgramID,
+			Description:    entry.Description,
+			ScheduledStart: entry.ScheduledStart,
+			ScheduledEnd:   entry.ScheduledEnd,
+			CheckedInAt:    entry.CheckedInAt,
+			CheckedOutAt:   entry.CheckedOutAt,
+			WorkedMinutes:  entry.WorkedMinutes,
+			RateType:       entry.RateType,
+			RateCents:      entry.RateCents,
+			AmountCents:    entry.AmountCents,
+		}
+	}
+	adjustments := make([]AdjustmentResponseDto, len(detail.Adjustments))
+	for i, adjustment := range detail.Adjustments {
+		adjustments[i] = NewAdjustmentResponse(adjustment)
+	}
+	return TimesheetDetailResponseDto{
+		TimesheetResponseDto: NewTimesheetResponse(detail.Timesheet),
+		Entries:              entries,
+		Adjustments:          adjustments,
+	}
+}
+
+func NewCheckInResponse(checkIn values.CheckIn) CheckInResponseDto {
+	return CheckInResponseDto{
+		SourceType:   checkIn.SourceType,
+		SourceID:     checkIn.SourceID,
+		CheckedInAt:  checkIn.CheckedInAt,
+		CheckedOutAt: checkIn.CheckedOutAt,
+	}
+}
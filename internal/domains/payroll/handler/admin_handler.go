@@ -0,0 +1,560 @@
+package payroll
+
+import (
+	"net/http"
+
+	dto "api/internal/domains/payroll/dto"
+	values "api/internal/domains/payroll/values"
+	errLib "api/internal/libs/errors"
+	responseHandlers "api/internal/libs/responses"
+	"api/internal/libs/validators"
+	contextUtils "api/utils/context"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+// GetPayRates lists pay rates, optionally for one staff member.
+// @Tags Payroll - Admin
+// @Produce json
+// @Security Bearer
+// @Param staff_id query string false "Staff ID"
+// @Success 200 {array} dto.PayRateResponseDto "Pay rates"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid staff ID"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /admin/payroll/rates [get]
+func (h *Handler) GetPayRates(w http.ResponseWriter, r *http.Request) {
+	var staffID uuid.UUID
+	if staffStr := r.URL.Query().Get("staff_id"); staffStr != "" {
+		id, err := validators.ParseUUID(staffStr)
+		if err != nil {
+			responseHandlers.RespondWithError(w, err)
+			return
+		}
+		staffID = id
+	}
+
+	rates, err := h.Service.ListRates(r.Context(), staffID)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewPayRateResponses(rates), http.StatusOK)
+}
+
+// CreatePayRate sets a staff member's pay from a date on.
+// @Description A rate with a program_id applies to that program's sessions and takes precedence over the staff member's default rate. Rates of the same staff member and program cannot overlap.
+// @Tags Payroll - Admin
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param rate body dto.PayRateRequestDto true "Pay rate"
+// @Success 201 {object} dto.PayRateResponseDto "Pay rate created"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid input or unknown staff or program"
+// @Failure 409 {object} map[string]interface{} "Conflict: Overlaps another rate"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /admin/payroll/rates [post]
+func (h *Handler) CreatePayRate(w http.ResponseWriter, r *http.Request) {
+	var req dto.PayRateRequestDto
+	if err := validators.ParseJSON(r.Body, &req); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	rate, err := req.ToValues()
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	created, err := h.Service.CreateRate(r.Context(), rate)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewPayRateResponse(created), http.StatusCreated)
+}
+
+// UpdatePayRate changes a pay rate's type, amount or dates. Ending a rate and creating
+// a new one keeps past timesheets' history intact.
+// @Tags Payroll - Admin
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Pay rate ID"
+// @Param rate body dto.PayRateRequestDto true "Pay rate"
+// @Success 200 {object} dto.PayRateResponseDto "Pay rate updated"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid input"
+// @Failure 404 {object} map[string]interface{} "Not Found: Pay rate not found"
+// @Failure 409 {object} map[string]interface{} "Conflict: Overlaps another rate"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /admin/payroll/rates/{id} [put]
+func (h *Handler) UpdatePayRate(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	var req dto.PayRateRequestDto
+	if err := validators.ParseJSON(r.Body, &req); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	rate, err := req.ToValues()
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	updated, err := h.Service.UpdateRate(r.Context(), id, rate)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewPayRateResponse(updated), http.StatusOK)
+}
+
+// DeletePayRate deletes a pay rate.
+// @Tags Payroll - Admin
+// @Security Bearer
+// @Param id path string true "Pay rate ID"
+// @Success 204 "Pay rate deleted"
+// @Failure 404 {object} map[string]interface{} "Not Found: Pay rate not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /admin/payroll/rates/{id} [delete]
+func (h *Handler) DeletePayRate(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	if err := h.Service.DeleteRate(r.Context(), id); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, nil, http.StatusNoContent)
+}
+
+// SetEmployeeID sets the ID the payroll provider knows a staff member by, used in exports.
+// @Tags Payroll - Admin
+// @Accept json
+// @Security Bearer
+// @Param staff_id path string true "Staff ID"
+// @Param employee body dto.EmployeeRequestDto true "Provider employee ID"
+// @Success 204 "Employee ID set"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid input or unknown staff"
+// @Failure 409 {object} map[string]interface{} "Conflict: Employee ID already in use"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /admin/payroll/employees/{staff_id} [put]
+func (h *Handler) SetEmployeeID(w http.ResponseWriter, r *http.Request) {
+	staffID, err := validators.ParseUUID(chi.URLParam(r, "staff_id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	var req dto.EmployeeRequestDto
+	if err := validators.ParseJSON(r.Body, &req); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	if err := h.Service.SetEmployeeID(r.Context(), staffID, req.ExternalEmployeeID); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, nil, http.StatusNoContent)
+}
+
+// GetPayPeriods lists pay periods, newest first.
+// @Tags Payroll - Admin
+// @Produce json
+// @Security Bearer
+// @Param status query string false "open or closed"
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Param offset query int false "Offset"
+// @Success 200 {array} dto.PayPeriodResponseDto "Pay periods"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid status"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /admin/payroll/periods [get]
+func (h *Handler) GetPayPeriods(w http.ResponseWriter, r *http.Request) {
+	status, limit, offset, err := dto.ParsePeriodListQuery(r.URL.Query())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	periods, err := h.Service.ListPeriods(r.Context(), status, limit, offset)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewPayPeriodResponses(periods), http.StatusOK)
+}
+
+// CreatePayPeriod creates a pay period. The nightly payroll job creates the following
+// periods with the same length and keeps open periods' timesheets up to date.
+// @Tags Payroll - Admin
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param period body dto.PayPeriodRequestDto true "Pay period"
+// @Success 201 {object} dto.PayPeriodResponseDto "Pay period created"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid dates"
+// @Failure 409 {object} map[string]interface{} "Conflict: Overlaps another pay period"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /admin/payroll/periods [post]
+func (h *Handler) CreatePayPeriod(w http.ResponseWriter, r *http.Request) {
+	var req dto.PayPeriodRequestDto
+	if err := validators.ParseJSON(r.Body, &req); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	startsOn, endsOn, err := req.ToDates()
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	period, err := h.Service.CreatePeriod(r.Context(), startsOn, endsOn)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewPayPeriodResponse(period), http.StatusCreated)
+}
+
+// GeneratePayPeriod regenerates an open pay period's draft and rejected timesheets from
+// the sessions staff were assigned to. Submitted and approved timesheets are kept as they are.
+// @Tags Payroll - Admin
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Pay period ID"
+// @Success 200 {object} dto.GenerateResponseDto "Timesheets generated"
+// @Failure 404 {object} map[string]interface{} "Not Found: Pay period not found"
+// @Failure 409 {object} map[string]interface{} "Conflict: Pay period closed"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /admin/payroll/periods/{id}/generate [post]
+func (h *Handler) GeneratePayPeriod(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	generated, err := h.Service.GenerateTimesheets(r.Context(), id)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.GenerateResponseDto{Generated: generated}, http.StatusOK)
+}
+
+// ClosePayPeriod closes a pay period once all its timesheets are approved, freezing
+// them for export.
+// @Tags Payroll - Admin
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Pay period ID"
+// @Success 200 {object} dto.PayPeriodResponseDto "Pay period closed"
+// @Failure 404 {object} map[string]interface{} "Not Found: Pay period not found"
+// @Failure 409 {object} map[string]interface{} "Conflict: Already closed or timesheets awaiting approval"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /admin/payroll/periods/{id}/close [post]
+func (h *Handler) ClosePayPeriod(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	period, err := h.Service.ClosePeriod(r.Context(), id)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewPayPeriodResponse(period), http.StatusOK)
+}
+
+// ReopenPayPeriod reopens a closed pay period so its timesheets can be corrected.
+// @Tags Payroll - Admin
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Pay period ID"
+// @Success 200 {object} dto.PayPeriodResponseDto "Pay period reopened"
+// @Failure 404 {object} map[string]interface{} "Not Found: Pay period not found"
+// @Failure 409 {object} map[string]interface{} "Conflict: Pay period already open"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /admin/payroll/periods/{id}/reopen [post]
+func (h *Handler) ReopenPayPeriod(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	period, err := h.Service.ReopenPeriod(r.Context(), id)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewPayPeriodResponse(period), http.StatusOK)
+}
+
+// ExportPayPeriod exports a closed pay period for the payroll provider.
+// @Description format=provider (default) gives one earnings line per staff member, earning code and rate in the provider's import layout. format=csv gives every session and adjustment for reconciliation.
+// @Tags Payroll - Admin
+// @Produce text/csv
+// @Security Bearer
+// @Param id path string true "Pay period ID"
+// @Param format query string false "provider (default) or csv"
+// @Success 200 {file} file "CSV file"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid format"
+// @Failure 404 {object} map[string]interface{} "Not Found: Pay period not found"
+// @Failure 409 {object} map[string]interface{} "Conflict: Pay period not closed"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /admin/payroll/periods/{id}/export [get]
+func (h *Handler) ExportPayPeriod(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = exportProvider
+	}
+	if format != exportProvider && format != exportDetail {
+		responseHandlers.RespondWithError(w, errLib.New("Invalid format: use provider or csv", http.StatusBadRequest))
+		return
+	}
+
+	export, err := h.Service.ExportPeriod(r.Context(), id)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	writeExport(w, export, format)
+}
+
+// GetTimesheets lists timesheets, newest pay period first.
+// @Tags Payroll - Admin
+// @Produce json
+// @Security Bearer
+// @Param pay_period_id query string false "Pay period ID"
+// @Param staff_id query string false "Staff ID"
+// @Param status query string false "draft, submitted, approved or rejected"
+// @Param limit query int false "Page size (max 100)" default(50)
+// @Param offset query int false "Offset"
+// @Success 200 {array} dto.TimesheetResponseDto "Timesheets"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid filter"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /admin/payroll/timesheets [get]
+func (h *Handler) GetTimesheets(w http.ResponseWriter, r *http.Request) {
+	filter, err := dto.ParseTimesheetListQuery(r.URL.Query())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	timesheets, err := h.Service.ListTimesheets(r.Context(), filter)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewTimesheetResponses(timesheets), http.StatusOK)
+}
+
+// GetTimesheet returns a timesheet with its sessions and adjustments.
+// @Tags Payroll - Admin
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Timesheet ID"
+// @Success 200 {object} dto.TimesheetDetailResponseDto "Timesheet"
+// @Failure 404 {object} map[string]interface{} "Not Found: Timesheet not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /admin/payroll/timesheets/{id} [get]
+func (h *Handler) GetTimesheet(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	timesheet, err := h.Service.GetTimesheet(r.Context(), id, false)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewTimesheetDetailResponse(timesheet), http.StatusOK)
+}
+
+// ApproveTimesheet approves a submitted timesheet.
+// @Tags Payroll - Admin
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Timesheet ID"
+// @Success 200 {object} dto.TimesheetDetailResponseDto "Timesheet approved"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Own timesheet"
+// @Failure 404 {object} map[string]interface{} "Not Found: Timesheet not found"
+// @Failure 409 {object} map[string]interface{} "Conflict: Not submitted or pay period closed"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /admin/payroll/timesheets/{id}/approve [post]
+func (h *Handler) ApproveTimesheet(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	reviewerID, err := contextUtils.GetUserID(r.Context())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	timesheet, err := h.Service.ReviewTimesheet(r.Context(), values.ReviewValues{
+		TimesheetID: id,
+		ReviewerID:  reviewerID,
+		Approve:     true,
+	})
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewTimesheetDetailResponse(timesheet), http.StatusOK)
+}
+
+// RejectTimesheet sends a submitted timesheet back to the staff member with a reason.
+// It is regenerated again until it is resubmitted.
+// @Tags Payroll - Admin
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Timesheet ID"
+// @Param review body dto.RejectTimesheetRequestDto true "Reason"
+// @Success 200 {object} dto.TimesheetDetailResponseDto "Timesheet rejected"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Missing reason"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Own timesheet"
+// @Failure 404 {object} map[string]interface{} "Not Found: Timesheet not found"
+// @Failure 409 {object} map[string]interface{} "Conflict: Not submitted or pay period closed"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /admin/payroll/timesheets/{id}/reject [post]
+func (h *Handler) RejectTimesheet(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	reviewerID, err := contextUtils.GetUserID(r.Context())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	var req dto.RejectTimesheetRequestDto
+	if err := validators.ParseJSON(r.Body, &req); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	review, err := req.ToValues(id, reviewerID)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	timesheet, err := h.Service.ReviewTimesheet(r.Context(), review)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewTimesheetDetailResponse(timesheet), http.StatusOK)
+}
+
+// ReopenTimesheet turns a submitted, approved or rejected timesheet back into a draft,
+// so it is regenerated and goes through approval again.
+// @Tags Payroll - Admin
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Timesheet ID"
+// @Success 200 {object} dto.TimesheetDetailResponseDto "Timesheet reopened"
+// @Failure 404 {object} map[string]interface{} "Not Found: Timesheet not found"
+// @Failure 409 {object} map[string]interface{} "Conflict: Already a draft or pay period closed"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /admin/payroll/timesheets/{id}/reopen [post]
+func (h *Handler) ReopenTimesheet(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	timesheet, err := h.Service.ReopenTimesheet(r.Context(), id)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewTimesheetDetailResponse(timesheet), http.StatusOK)
+}
+
+// AddAdjustment adds a one-off amount to a timesheet, e.g. a bonus or a correction.
+// @Tags Payroll - Admin
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Timesheet ID"
+// @Param adjustment body dto.AdjustmentRequestDto true "Adjustment"
+// @Success 201 {object} dto.AdjustmentResponseDto "Adjustment added"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid input"
+// @Failure 404 {object} map[string]interface{} "Not Found: Timesheet not found"
+// @Failure 409 {object} map[string]interface{} "Conflict: Timesheet approved or pay period closed"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /admin/payroll/timesheets/{id}/adjustments [post]
+func (h *Handler) AddAdjustment(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	var req dto.AdjustmentRequestDto
+	if err := validators.ParseJSON(r.Body, &req); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	adjustment, err := req.ToValues(id)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	created, err := h.Service.AddAdjustment(r.Context(), adjustment)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewAdjustmentResponse(created), http.StatusCreated)
+}
+
+// DeleteAdjustment removes an adjustment from a timesheet that is not yet approved.
+// @Tags Payroll - Admin
+// @Security Bearer
+// @Param id path string true "Adjustment ID"
+// @Success 204 "Adjustment deleted"
+// @Failure 404 {object} map[string]interface{} "Not Found: Adjustment not found"
+// @Failure 409 {object} map[string]interface{} "Conflict: Timesheet approved or pay period closed"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /admin/payroll/adjustments/{id} [delete]
+func (h *Handler) DeleteAdjustment(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	if err := h.Service.DeleteAdjustment(r.Context(), id); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, nil, http.StatusNoContent)
+}
@@ -0,0 +1,123 @@
+package payroll
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	values "api/internal/domains/payroll/values"
+	"api/utils/timezone"
+
+	"github.com/google/uuid"
+)
+
+// Export formats of ExportPayPeriod.
+const (
+	exportProvider = "provider"
+	exportDetail   = "csv"
+)
+
+const exportTimeLayout = "2006-01-02 15:04"
+
+func writeExport(w http.ResponseWriter, export values.PeriodExport, format string) {
+	startsOn := export.Period.StartsOn.Format("2006-01-02")
+	endsOn := export.Period.EndsOn.Format("2006-01-02")
+	name := "payroll"
+	if format == exportDetail {
+		name = "payroll_detail"
+	}
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s_%s_%s.csv", name, startsOn, endsOn))
+
+	writer := csv.NewWriter(w)
+	if format == exportDetail {
+		writeDetail(writer, export)
+	} else {
+		writeEarnings(writer, export, startsOn, endsOn)
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		log.Printf("[PAYROLL] Failed to write export of pay period %s: %v", export.Period.ID, err)
+		return
+	}
+	log.Printf("[PAYROLL] Exported pay period %s to %s (%s)", startsOn, endsOn, format)
+}
+
+// writeEarnings writes the payroll provider's import layout: one line per staff member,
+// earning code and rate.
+func writeEarnings(writer *csv.Writer, export values.PeriodExport, startsOn, endsOn string) {
+	writer.Write([]string{"Employee ID", "Last Name", "First Name", "Earning Code", "Units", "Rate", "Amount", "Period Start", "Period End"})
+	for _, line := range values.EarningLines(export) {
+		rate := ""
+		if line.RateCents != nil {
+			rate = values.FormatAmount(*line.RateCents)
+		}
+		writer.Write([]string{
+			line.EmployeeID,
+			line.LastName,
+			line.FirstName,
+			line.EarningCode,
+			line.Units,
+			rate,
+			values.FormatAmount(line.AmountCents),
+			startsOn,
+			endsOn,
+		})
+	}
+}
+
+// writeDetail writes every session and adjustment of the period, for reconciling the
+// provider export against the schedule.
+func writeDetail(writer *csv.Writer, export values.PeriodExport) {
+	loc := timezone.Default()
+	formatTime := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.In(loc).Format(exportTimeLayout)
+	}
+
+	timesheets := make(map[uuid.UUID]values.Timesheet, len(export.Timesheets))
+	for _, timesheet := range export.Timesheets {
+		timesheets[timesheet.ID] = timesheet
+	}
+	employee := func(timesheet values.Timesheet) []string {
+		employeeID := ""
+		if timesheet.ExternalEmployeeID != nil {
+			employeeID = *timesheet.ExternalEmployeeID
+		}
+		return []string{employeeID, timesheet.LastName, timesheet.FirstName}
+	}
+
+	writer.Write([]string{"Employee ID", "Last Name", "First Name", "Type", "Description", "Scheduled Start", "Scheduled End", "Checked In", "Checked Out", "Minutes", "Rate Type", "Rate", "Amount"})
+	for _, entry := range export.Entries {
+		rateType, rate := "", ""
+		if entry.RateType != nil && entry.RateCents != nil {
+			rateType = *entry.RateType
+			rate = values.FormatAmount(*entry.RateCents)
+		}
+		writer.Write(append(employee(timesheets[entry.TimesheetID]),
+			entry.SourceType,
+			entry.Description,
+			formatTime(&entry.ScheduledStart),
+			formatTime(&entry.ScheduledEnd),
+			formatTime(entry.CheckedInAt),
+			formatTime(entry.CheckedOutAt),
+			strconv.Itoa(int(entry.WorkedMinutes)),
+			rateType,
+			rate,
+			values.FormatAmount(entry.AmountCents),
+		))
+	}
+	for _, adjustment := range export.Adjustments {
+		writer.Write(append(employee(timesheets[adjustment.TimesheetID]),
+			"adjustment",
+			adjustment.Reason,
+			"", "", "", "", "", "", "",
+			values.FormatAmount(adjustment.AmountCents),
+		))
+	}
+}
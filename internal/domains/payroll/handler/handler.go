@@ -0,0 +1,177 @@
+package payroll
+
+import (
+	"net/http"
+	"time"
+
+	"api/internal/di"
+	dto "api/internal/domains/payroll/dto"
+	service "api/internal/domains/payroll/service"
+	values "api/internal/domains/payroll/values"
+	errLib "api/internal/libs/errors"
+	responseHandlers "api/internal/libs/responses"
+	"api/internal/libs/validators"
+	contextUtils "api/utils/context"
+
+	"github.com/go-chi/chi"
+)
+
+type Handler struct {
+	Service *service.Service
+}
+
+func NewHandler(container *di.Container) *Handler {
+	return &Handler{Service: service.NewService(container)}
+}
+
+// GetMyTimesheets lists the logged-in staff member's timesheets, newest pay period first.
+// @Tags Timesheets
+// @Produce json
+// @Security Bearer
+// @Param status query string false "draft, submitted, approved or rejected"
+// @Param limit query int false "Page size (max 100)" default(50)
+// @Param offset query int false "Offset"
+// @Success 200 {array} dto.TimesheetResponseDto "Timesheets"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid filter"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /secure/timesheets [get]
+func (h *Handler) GetMyTimesheets(w http.ResponseWriter, r *http.Request) {
+	staffID, err := contextUtils.GetUserID(r.Context())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	filter, err := dto.ParseTimesheetListQuery(r.URL.Query())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	filter.StaffID = staffID
+
+	timesheets, err := h.Service.ListTimesheets(r.Context(), filter)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewTimesheetResponses(timesheets), http.StatusOK)
+}
+
+// GetMyTimesheet returns one of the logged-in staff member's timesheets with its sessions and adjustments.
+// @Tags Timesheets
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Timesheet ID"
+// @Success 200 {object} dto.TimesheetDetailResponseDto "Timesheet"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Not Found: Timesheet not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /secure/timesheets/{id} [get]
+func (h *Handler) GetMyTimesheet(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	timesheet, err := h.Service.GetTimesheet(r.Context(), id, true)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewTimesheetDetailResponse(timesheet), http.StatusOK)
+}
+
+// SubmitTimesheet sends the logged-in staff member's timesheet for approval.
+// @Description Draft and rejected timesheets can be submitted while their pay period is open. Submitted timesheets are no longer regenerated.
+// @Tags Timesheets
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Timesheet ID"
+// @Success 200 {object} dto.TimesheetDetailResponseDto "Submitted timesheet"
+// @Failure 404 {object} map[string]interface{} "Not Found: Timesheet not found"
+// @Failure 409 {object} map[string]interface{} "Conflict: Already submitted or pay period closed"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /secure/timesheets/{id}/submit [post]
+func (h *Handler) SubmitTimesheet(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	timesheet, err := h.Service.SubmitTimesheet(r.Context(), id)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewTimesheetDetailResponse(timesheet), http.StatusOK)
+}
+
+// CheckIn records the logged-in staff member arriving at a session they are assigned to.
+// @Description Staff are assigned to events they were added to, practices they booked and games they scheduled. Check-in opens an hour before the session starts and closes when it ends.
+// @Tags Timesheets
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param session body dto.CheckInRequestDto true "Session"
+// @Success 201 {object} dto.CheckInResponseDto "Checked in"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid input"
+// @Failure 404 {object} map[string]interface{} "Not Found: Not assigned to this session"
+// @Failure 409 {object} map[string]interface{} "Conflict: Already checked in or outside the check-in window"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /secure/timesheets/check-in [post]
+func (h *Handler) CheckIn(w http.ResponseWriter, r *http.Request) {
+	session, err := parseCheckIn(r)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	checkIn, err := h.Service.CheckIn(r.Context(), session, time.Now())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewCheckInResponse(checkIn), http.StatusCreated)
+}
+
+// CheckOut records the logged-in staff member leaving a session they checked in to.
+// @Description Check-out closes four hours after the session was scheduled to end; later corrections are made by an admin.
+// @Tags Timesheets
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param session body dto.CheckInRequestDto true "Session"
+// @Success 200 {object} dto.CheckInResponseDto "Checked out"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid input"
+// @Failure 404 {object} map[string]interface{} "Not Found: Not assigned to this session"
+// @Failure 409 {object} map[string]interface{} "Conflict: Not checked in or check-out closed"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /secure/timesheets/check-out [post]
+func (h *Handler) CheckOut(w http.ResponseWriter, r *http.Request) {
+	session, err := parseCheckIn(r)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	checkIn, err := h.Service.CheckOut(r.Context(), session, time.Now())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewCheckInResponse(checkIn), http.StatusOK)
+}
+
+func parseCheckIn(r *http.Request) (values.CheckInValues, *errLib.CommonError) {
+	staffID, err := contextUtils.GetUserID(r.Context())
+	if err != nil {
+		return values.CheckInValues{}, err
+	}
+	var req dto.CheckInRequestDto
+	if err := validators.ParseJSON(r.Body, &req); err != nil {
+		return values.CheckInValues{}, err
+	}
+	return req.ToValues(staffID)
+}
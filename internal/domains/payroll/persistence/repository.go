@@ -0,0 +1,679 @@
+package payroll
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	databaseErrors "api/internal/constants"
+	"api/internal/di"
+	db "api/internal/domains/payroll/persistence/sqlc/generated"
+	values "api/internal/domains/payroll/values"
+	errLib "api/internal/libs/errors"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type Repository struct {
+	Queries *db.Queries
+	Tx      *sql.Tx
+}
+
+func NewRepository(container *di.Container) *Repository {
+	return &Repository{Queries: container.Queries.PayrollDb}
+}
+
+func (r *Repository) GetTx() *sql.Tx { return r.Tx }
+
+func (r *Repository) WithTx(tx *sql.Tx) *Repository {
+	return &Repository{Queries: r.Queries.WithTx(tx), Tx: tx}
+}
+
+func nullUUID(id uuid.UUID) uuid.NullUUID {
+	return uuid.NullUUID{UUID: id, Valid: id != uuid.Nil}
+}
+
+func nullUUIDPtr(id *uuid.UUID) uuid.NullUUID {
+	if id == nil {
+		return uuid.NullUUID{}
+	}
+	return uuid.NullUUID{UUID: *id, Valid: true}
+}
+
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
+
+func nullString(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *s, Valid: true}
+}
+
+func nullInt32(i *int32) sql.NullInt32 {
+	if i == nil {
+		return sql.NullInt32{}
+	}
+	return sql.NullInt32{Int32: *i, Valid: true}
+}
+
+func uuidPtr(id uuid.NullUUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
+	}
+	return &id.UUID
+}
+
+func timePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func stringPtr(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
+}
+
+func int32Ptr(i sql.NullInt32) *int32 {
+	if !i.Valid {
+		return nil
+	}
+	return &i.Int32
+}
+
+func pqCode(err error) string {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return string(pqErr.Code)
+	}
+	return ""
+}
+
+func mapRate(row db.PayrollPayRate) values.PayRate {
+	return values.PayRate{
+		ID:            row.ID,
+		StaffID:       row.StaffID,
+		RateType:      row.RateType,
+		ProgramID:     uuidPtr(row.ProgramID),
+		AmountCents:   row.AmountCents,
+		EffectiveFrom: row.EffectiveFrom,
+		EffectiveTo:   timePtr(row.EffectiveTo),
+		CreatedBy:     uuidPtr(row.CreatedBy),
+		CreatedAt:     row.CreatedAt,
+		UpdatedAt:     row.UpdatedAt,
+	}
+}
+
+func mapRates(rows []db.PayrollPayRate) []values.PayRate {
+	rates := make([]values.PayRate, len(rows))
+	for i, row := range rows {
+		rates[i] = mapRate(row)
+	}
+	return rates
+}
+
+func mapPeriod(row db.PayrollPayPeriod) values.PayPeriod {
+	return values.PayPeriod{
+		ID:        row.ID,
+		StartsOn:  row.StartsOn,
+		EndsOn:    row.EndsOn,
+		Status:    row.Status,
+		ClosedAt:  timePtr(row.ClosedAt),
+		ClosedBy:  uuidPtr(row.ClosedBy),
+		CreatedBy: uuidPtr(row.CreatedBy),
+		CreatedAt: row.CreatedAt,
+	}
+}
+
+func mapAssignment(row db.ListStaffAssignmentsRow) values.Assignment {
+	return values.Assignment{
+		StaffID:        row.StaffID,
+		SourceType:     row.SourceType,
+		SourceID:       row.SourceID,
+		ProgramID:      uuidPtr(row.ProgramID),
+		Description:    row.Description,
+		ScheduledStart: row.ScheduledStart,
+		ScheduledEnd:   row.ScheduledEnd,
+		CheckedInAt:    timePtr(row.CheckedInAt),
+		CheckedOutAt:   timePtr(row.CheckedOutAt),
+	}
+}
+
+func mapCheckIn(row db.PayrollCheckIn) values.CheckIn {
+	return values.CheckIn{
+		ID:           row.ID,
+		StaffID:      row.StaffID,
+		SourceType:   row.SourceType,
+		SourceID:     row.SourceID,
+		CheckedInAt:  row.CheckedInAt,
+		CheckedOutAt: timePtr(row.CheckedOutAt),
+	}
+}
+
+func mapTimesheet(row db.ListTimesheetsRow) values.Timesheet {
+	return values.Timesheet{
+		ID:                 row.ID,
+		PayPeriodID:        row.PayPeriodID,
+		StaffID:            row.StaffID,
+		Status:             row.Status,
+		SubmittedAt:        timePtr(row.SubmittedAt),
+		ReviewedAt:         timePtr(row.ReviewedAt),
+		ReviewedBy:         uuidPtr(row.ReviewedBy),
+		RejectionReason:    stringPtr(row.RejectionReason),
+		GeneratedAt:        row.GeneratedAt,
+		StartsOn:           row.StartsOn,
+		EndsOn:             row.EndsOn,
+		FirstName:          row.FirstName,
+		LastName:           row.LastName,
+		ExternalEmployeeID: stringPtr(row.ExternalEmployeeID),
+		WorkedMinutes:      row.WorkedMinutes,
+		EntriesCents:       row.EntriesCents,
+		AdjustmentsCents:   row.AdjustmentsCents,
+	}
+}
+
+func mapEntries(rows []db.PayrollTimesheetEntry) []values.TimesheetEntry {
+	entries := make([]values.TimesheetEntry, len(rows))
+	for i, row := range rows {
+		entries[i] = values.TimesheetEntry{
+			ID:             row.ID,
+			TimesheetID:    row.TimesheetID,
+			SourceType:     row.SourceType,
+			SourceID:       row.SourceID,
+			ProgramID:      uuidPtr(row.ProgramID),
+			Description:    row.Description,
+			ScheduledStart: row.ScheduledStart,
+			ScheduledEnd:   row.ScheduledEnd,
+			CheckedInAt:    timePtr(row.CheckedInAt),
+			CheckedOutAt:   timePtr(row.CheckedOutAt),
+			WorkedMinutes:  row.WorkedMinutes,
+			RateID:         uuidPtr(row.RateID),
+			RateType:       stringPtr(row.RateType),
+			RateCents:      int32Ptr(row.RateCents),
+			AmountCents:    row.AmountCents,
+		}
+	}
+	return entries
+}
+
+func mapAdjustment(row db.PayrollAdjustment) values.Adjustment {
+	return values.Adjustment{
+		ID:          row.ID,
+		TimesheetID: row.TimesheetID,
+		AmountCents: row.AmountCents,
+		Reason:      row.Reason,
+		CreatedBy:   uuidPtr(row.CreatedBy),
+		CreatedAt:   row.CreatedAt,
+	}
+}
+
+func mapAdjustments(rows []db.PayrollAdjustment) []values.Adjustment {
+	adjustments := make([]values.Adjustment, len(rows))
+	for i, row := range rows {
+		adjustments[i] = mapAdjustment(row)
+	}
+	return adjustments
+}
+
+// rateWriteError explains constraint violations when saving a pay rate.
+func rateWriteError(err error) *errLib.CommonError {
+	switch pqCode(err) {
+	case databaseErrors.ExclusionViolation:
+		return errLib.New("The staff member already has a rate for this program in effect on some of these days", http.StatusConflict)
+	case databaseErrors.ForeignKeyViolation:
+		return errLib.New("Staff member or program not found", http.StatusNotFound)
+	case databaseErrors.CheckViolation:
+		return errLib.New("Invalid pay rate", http.StatusBadRequest)
+	}
+	log.Printf("Failed to save pay rate: %v", err)
+	return errLib.New("Failed to save pay rate", http.StatusInternalServerError)
+}
+
+func (r *Repository) CreateRate(ctx context.Context, rate values.PayRateValues, createdBy uuid.UUID) (values.PayRate, *errLib.CommonError) {
+	row, err := r.Queries.CreatePayRate(ctx, db.CreatePayRateParams{
+		StaffID:       rate.StaffID,
+		RateType:      rate.RateType,
+		ProgramID:     nullUUIDPtr(rate.ProgramID),
+		AmountCents:   rate.AmountCents,
+		EffectiveFrom: rate.EffectiveFrom,
+		EffectiveTo:   nullTime(rate.EffectiveTo),
+		CreatedBy:     nullUUID(createdBy),
+	})
+	if err != nil {
+		return values.PayRate{}, rateWriteError(err)
+	}
+	return mapRate(row), nil
+}
+
+func (r *Repository) GetRate(ctx context.Context, id uuid.UUID) (values.PayRate, *errLib.CommonError) {
+	row, err := r.Queries.GetPayRateById(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return values.PayRate{}, errLib.New("Pay rate not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to get pay rate %s: %v", id, err)
+		return values.PayRate{}, errLib.New("Failed to get pay rate", http.StatusInternalServerError)
+	}
+	return mapRate(row), nil
+}
+
+func (r *Repository) UpdateRate(ctx context.Context, id uuid.UUID, rate values.PayRateValues) (values.PayRate, *errLib.CommonError) {
+	row, err := r.Queries.UpdatePayRate(ctx, db.UpdatePayRateParams{
+		ID:            id,
+		RateType:      rate.RateType,
+		AmountCents:   rate.AmountCents,
+		EffectiveFrom: rate.EffectiveFrom,
+		EffectiveTo:   nullTime(rate.EffectiveTo),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return values.PayRate{}, errLib.New("Pay rate not found", http.StatusNotFound)
+		}
+		return values.PayRate{}, rateWriteError(err)
+	}
+	return mapRate(row), nil
+}
+
+func (r *Repository) DeleteRate(ctx context.Context, id uuid.UUID) *errLib.CommonError {
+	affected, err := r.Queries.DeletePayRate(ctx, id)
+	if err != nil {
+		log.Printf("Failed to delete pay rate %s: %v", id, err)
+		return errLib.New("Failed to delete pay rate", http.StatusInternalServerError)
+	}
+	if affected == 0 {
+		return errLib.New("Pay rate not found", http.StatusNotFound)
+	}
+	return nil
+}
+
+// ListRates lists pay rates, only the staff member's unless staffID is uuid.Nil.
+func (r *Repository) ListRates(ctx context.Context, staffID uuid.UUID) ([]values.PayRate, *errLib.CommonError) {
+	rows, err := r.Queries.ListPayRates(ctx, nullUUID(staffID))
+	if err != nil {
+		log.Printf("Failed to list pay rates: %v", err)
+		return nil, errLib.New("Failed to list pay rates", http.StatusInternalServerError)
+	}
+	return mapRates(rows), nil
+}
+
+// ListRatesInEffect lists the rates in effect on any day of the period.
+func (r *Repository) ListRatesInEffect(ctx context.Context, period values.PayPeriod) ([]values.PayRate, *errLib.CommonError) {
+	rows, err := r.Queries.ListPayRatesInRange(ctx, db.ListPayRatesInRangeParams{
+		StartsOn: period.StartsOn,
+		EndsOn:   period.EndsOn,
+	})
+	if err != nil {
+		log.Printf("Failed to list pay rates for pay period %s: %v", period.ID, err)
+		return nil, errLib.New("Failed to list pay rates", http.StatusInternalServerError)
+	}
+	return mapRates(rows), nil
+}
+
+func (r *Repository) UpsertEmployee(ctx context.Context, staffID uuid.UUID, externalID string, updatedBy uuid.UUID) *errLib.CommonError {
+	if _, err := r.Queries.UpsertEmployee(ctx, db.UpsertEmployeeParams{
+		StaffID:            staffID,
+		ExternalEmployeeID: externalID,
+		UpdatedBy:          nullUUID(updatedBy),
+	}); err != nil {
+		switch pqCode(err) {
+		case databaseErrors.UniqueViolation:
+			return errLib.New("Another staff member already has this employee ID", http.StatusConflict)
+		case databaseErrors.ForeignKeyViolation:
+			return errLib.New("Staff member not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to save payroll employee ID of %s: %v", staffID, err)
+		return errLib.New("Failed to save employee ID", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (r *Repository) CreatePeriod(ctx context.Context, startsOn, endsOn time.Time, createdBy uuid.UUID) (values.PayPeriod, *errLib.CommonError) {
+	row, err := r.Queries.CreatePayPeriod(ctx, db.CreatePayPeriodParams{
+		StartsOn:  startsOn,
+		EndsOn:    endsOn,
+		CreatedBy: nullUUID(createdBy),
+	})
+	if err != nil {
+		if pqCode(err) == databaseErrors.ExclusionViolation {
+			return values.PayPeriod{}, errLib.New("The pay period overlaps another pay period", http.StatusConflict)
+		}
+		log.Printf("Failed to create pay period: %v", err)
+		return values.PayPeriod{}, errLib.New("Failed to create pay period", http.StatusInternalServerError)
+	}
+	return mapPeriod(row), nil
+}
+
+func periodNotFound(err error, id uuid.UUID) *errLib.CommonError {
+	if errors.Is(err, sql.ErrNoRows) {
+		return errLib.New("Pay period not found", http.StatusNotFound)
+	}
+	log.Printf("Failed to get pay period %s: %v", id, err)
+	return errLib.New("Failed to get pay period", http.StatusInternalServerError)
+}
+
+func (r *Repository) GetPeriod(ctx context.Context, id uuid.UUID) (values.PayPeriod, *errLib.CommonError) {
+	row, err := r.Queries.GetPayPeriodById(ctx, id)
+	if err != nil {
+		return values.PayPeriod{}, periodNotFound(err, id)
+	}
+	return mapPeriod(row), nil
+}
+
+// LockPeriod reads a pay period's row for update.
+func (r *Repository) LockPeriod(ctx context.Context, id uuid.UUID) (values.PayPeriod, *errLib.CommonError) {
+	row, err := r.Queries.GetPayPeriodByIdForUpdate(ctx, id)
+	if err != nil {
+		return values.PayPeriod{}, periodNotFound(err, id)
+	}
+	return mapPeriod(row), nil
+}
+
+// GetLatestPeriod returns the pay period that ends last, or nil when there are none.
+func (r *Repository) GetLatestPeriod(ctx context.Context) (*values.PayPeriod, *errLib.CommonError) {
+	row, err := r.Queries.GetLatestPayPeriod(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		log.Printf("Failed to get latest pay period: %v", err)
+		return nil, errLib.New("Failed to get pay period", http.StatusInternalServerError)
+	}
+	period := mapPeriod(row)
+	return &period, nil
+}
+
+func (r *Repository) ListPeriods(ctx context.Context, status string, limit, offset int32) ([]values.PayPeriod, *errLib.CommonError) {
+	rows, err := r.Queries.ListPayPeriods(ctx, db.ListPayPeriodsParams{
+		Status: sql.NullString{String: status, Valid: status != ""},
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		log.Printf("Failed to list pay periods: %v", err)
+		return nil, errLib.New("Failed to list pay periods", http.StatusInternalServerError)
+	}
+	periods := make([]values.PayPeriod, len(rows))
+	for i, row := range rows {
+		periods[i] = mapPeriod(row)
+	}
+	return periods, nil
+}
+
+func (r *Repository) ClosePeriod(ctx context.Context, id, closedBy uuid.UUID) *errLib.CommonError {
+	if err := r.Queries.ClosePayPeriod(ctx, db.ClosePayPeriodParams{ID: id, ClosedBy: nullUUID(closedBy)}); err != nil {
+		log.Printf("Failed to close pay period %s: %v", id, err)
+		return errLib.New("Failed to close pay period", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (r *Repository) ReopenPeriod(ctx context.Context, id uuid.UUID) *errLib.CommonError {
+	if err := r.Queries.ReopenPayPeriod(ctx, id); err != nil {
+		log.Printf("Failed to reopen pay period %s: %v", id, err)
+		return errLib.New("Failed to reopen pay period", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// ListAssignments lists the sessions staff were assigned to that start in [from, to).
+func (r *Repository) ListAssignments(ctx context.Context, from, to time.Time) ([]values.Assignment, *errLib.CommonError) {
+	rows, err := r.Queries.ListStaffAssignments(ctx, db.ListStaffAssignmentsParams{From: from, To: to})
+	if err != nil {
+		log.Printf("Failed to list staff assignments: %v", err)
+		return nil, errLib.New("Failed to list staff assignments", http.StatusInternalServerError)
+	}
+	assignments := make([]values.Assignment, len(rows))
+	for i, row := range rows {
+		assignments[i] = mapAssignment(row)
+	}
+	return assignments, nil
+}
+
+func (r *Repository) GetAssignment(ctx context.Context, session values.CheckInValues) (values.Assignment, *errLib.CommonError) {
+	row, err := r.Queries.GetStaffAssignment(ctx, db.GetStaffAssignmentParams{
+		StaffID:    session.StaffID,
+		SourceType: session.SourceType,
+		SourceID:   session.SourceID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return values.Assignment{}, errLib.New("You are not assigned to this session", http.StatusNotFound)
+		}
+		log.Printf("Failed to get %s %s assignment of %s: %v", session.SourceType, session.SourceID, session.StaffID, err)
+		return values.Assignment{}, errLib.New("Failed to get assignment", http.StatusInternalServerError)
+	}
+	return mapAssignment(db.ListStaffAssignmentsRow(row)), nil
+}
+
+func (r *Repository) CheckIn(ctx context.Context, session values.CheckInValues, at time.Time) (values.CheckIn, *errLib.CommonError) {
+	row, err := r.Queries.CreateCheckIn(ctx, db.CreateCheckInParams{
+		StaffID:     session.StaffID,
+		SourceType:  session.SourceType,
+		SourceID:    session.SourceID,
+		CheckedInAt: at,
+	})
+	if err != nil {
+		if pqCode(err) == databaseErrors.UniqueViolation {
+			return values.CheckIn{}, errLib.New("You have already checked in to this session", http.StatusConflict)
+		}
+		log.Printf("Failed to check %s in to %s %s: %v", session.StaffID, session.SourceType, session.SourceID, err)
+		return values.CheckIn{}, errLib.New("Failed to check in", http.StatusInternalServerError)
+	}
+	return mapCheckIn(row), nil
+}
+
+func (r *Repository) CheckOut(ctx context.Context, session values.CheckInValues, at time.Time) (values.CheckIn, *errLib.CommonError) {
+	row, err := r.Queries.CheckOut(ctx, db.CheckOutParams{
+		CheckedOutAt: sql.NullTime{Time: at, Valid: true},
+		StaffID:      session.StaffID,
+		SourceType:   session.SourceType,
+		SourceID:     session.SourceID,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return values.CheckIn{}, errLib.New("You are not checked in to this session", http.StatusConflict)
+		case pqCode(err) == databaseErrors.CheckViolation:
+			return values.CheckIn{}, errLib.New("Check-out must be after check-in", http.StatusBadRequest)
+		}
+		log.Printf("Failed to check %s out of %s %s: %v", session.StaffID, session.SourceType, session.SourceID, err)
+		return values.CheckIn{}, errLib.New("Failed to check out", http.StatusInternalServerError)
+	}
+	return mapCheckIn(row), nil
+}
+
+// UpsertTimesheet creates the staff member's timesheet for the period, or returns false
+// when it exists and was already submitted or approved.
+func (r *Repository) UpsertTimesheet(ctx context.Context, periodID, staffID uuid.UUID) (uuid.UUID, bool, *errLib.CommonError) {
+	row, err := r.Queries.UpsertTimesheet(ctx, db.UpsertTimesheetParams{PayPeriodID: periodID, StaffID: staffID})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, false, nil
+		}
+		log.Printf("Failed to create timesheet of %s for pay period %s: %v", staffID, periodID, err)
+		return uuid.Nil, false, errLib.New("Failed to generate timesheet", http.StatusInternalServerError)
+	}
+	return row.ID, true, nil
+}
+
+func timesheetNotFound(err error, id uuid.UUID) *errLib.CommonError {
+	if errors.Is(err, sql.ErrNoRows) {
+		return errLib.New("Timesheet not found", http.StatusNotFound)
+	}
+	log.Printf("Failed to get timesheet %s: %v", id, err)
+	return errLib.New("Failed to get timesheet", http.StatusInternalServerError)
+}
+
+func (r *Repository) GetTimesheet(ctx context.Context, id uuid.UUID) (values.Timesheet, *errLib.CommonError) {
+	row, err := r.Queries.GetTimesheetById(ctx, id)
+	if err != nil {
+		return values.Timesheet{}, timesheetNotFound(err, id)
+	}
+	return mapTimesheet(db.ListTimesheetsRow(row)), nil
+}
+
+// LockTimesheet reads a timesheet's row for update and returns its status and owner.
+func (r *Repository) LockTimesheet(ctx context.Context, id uuid.UUID) (db.PayrollTimesheet, *errLib.CommonError) {
+	row, err := r.Queries.GetTimesheetByIdForUpdate(ctx, id)
+	if err != nil {
+		return row, timesheetNotFound(err, id)
+	}
+	return row, nil
+}
+
+func (r *Repository) ListTimesheets(ctx context.Context, filter values.TimesheetFilter) ([]values.Timesheet, *errLib.CommonError) {
+	rows, err := r.Queries.ListTimesheets(ctx, db.ListTimesheetsParams{
+		PayPeriodID: nullUUID(filter.PayPeriodID),
+		StaffID:     nullUUID(filter.StaffID),
+		Status:      sql.NullString{String: filter.Status, Valid: filter.Status != ""},
+		Limit:       filter.Limit,
+		Offset:      filter.Offset,
+	})
+	if err != nil {
+		log.Printf("Failed to list timesheets: %v", err)
+		return nil, errLib.New("Failed to list timesheets", http.StatusInternalServerError)
+	}
+	timesheets := make([]values.Timesheet, len(rows))
+	for i, row := range rows {
+		timesheets[i] = mapTimesheet(row)
+	}
+	return timesheets, nil
+}
+
+func (r *Repository) ListTimesheetStaff(ctx context.Context, periodID uuid.UUID) ([]uuid.UUID, *errLib.CommonError) {
+	ids, err := r.Queries.ListPeriodTimesheetStaff(ctx, periodID)
+	if err != nil {
+		log.Printf("Failed to list timesheets of pay period %s: %v", periodID, err)
+		return nil, errLib.New("Failed to list timesheets", http.StatusInternalServerError)
+	}
+	return ids, nil
+}
+
+func (r *Repository) CountUnapproved(ctx context.Context, periodID uuid.UUID) (int64, *errLib.CommonError) {
+	count, err := r.Queries.CountUnapprovedTimesheets(ctx, periodID)
+	if err != nil {
+		log.Printf("Failed to count unapproved timesheets of pay period %s: %v", periodID, err)
+		return 0, errLib.New("Failed to check timesheets", http.StatusInternalServerError)
+	}
+	return count, nil
+}
+
+func (r *Repository) UpdateTimesheetStatus(ctx context.Context, params db.UpdateTimesheetStatusParams) *errLib.CommonError {
+	if err := r.Queries.UpdateTimesheetStatus(ctx, params); err != nil {
+		log.Printf("Failed to update timesheet %s: %v", params.ID, err)
+		return errLib.New("Failed to update timesheet", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// ReplaceEntries swaps a timesheet's entries for newly generated ones.
+func (r *Repository) ReplaceEntries(ctx context.Context, timesheetID uuid.UUID, entries []values.TimesheetEntry) *errLib.CommonError {
+	if err := r.Queries.DeleteTimesheetEntries(ctx, timesheetID); err != nil {
+		log.Printf("Failed to clear entries of timesheet %s: %v", timesheetID, err)
+		return errLib.New("Failed to generate timesheet", http.StatusInternalServerError)
+	}
+	for _, entry := range entries {
+		if err := r.Queries.CreateTimesheetEntry(ctx, db.CreateTimesheetEntryParams{
+			TimesheetID:    timesheetID,
+			SourceType:     entry.SourceType,
+			SourceID:       entry.SourceID,
+			ProgramID:      nullUUIDPtr(entry.ProgramID),
+			Description:    entry.Description,
+			ScheduledStart: entry.ScheduledStart,
+			ScheduledEnd:   entry.ScheduledEnd,
+			CheckedInAt:    nullTime(entry.CheckedInAt),
+			CheckedOutAt:   nullTime(entry.CheckedOutAt),
+			WorkedMinutes:  entry.WorkedMinutes,
+			RateID:         nullUUIDPtr(entry.RateID),
+			RateType:       nullString(entry.RateType),
+			RateCents:      nullInt32(entry.RateCents),
+			AmountCents:    entry.AmountCents,
+		}); err != nil {
+			log.Printf("Failed to write entry of timesheet %s: %v", timesheetID, err)
+			return errLib.New("Failed to generate timesheet", http.StatusInternalServerError)
+		}
+	}
+	return nil
+}
+
+func (r *Repository) ListEntries(ctx context.Context, timesheetID uuid.UUID) ([]values.TimesheetEntry, *errLib.CommonError) {
+	rows, err := r.Queries.ListTimesheetEntries(ctx, timesheetID)
+	if err != nil {
+		log.Printf("Failed to list entries of timesheet %s: %v", timesheetID, err)
+		return nil, errLib.New("Failed to get timesheet", http.StatusInternalServerError)
+	}
+	return mapEntries(rows), nil
+}
+
+func (r *Repository) ListPeriodEntries(ctx context.Context, periodID uuid.UUID) ([]values.TimesheetEntry, *errLib.CommonError) {
+	rows, err := r.Queries.ListPeriodEntries(ctx, periodID)
+	if err != nil {
+		log.Printf("Failed to list timesheet entries of pay period %s: %v", periodID, err)
+		return nil, errLib.New("Failed to export pay period", http.StatusInternalServerError)
+	}
+	return mapEntries(rows), nil
+}
+
+func (r *Repository) CreateAdjustment(ctx context.Context, adjustment values.AdjustmentValues, createdBy uuid.UUID) (values.Adjustment, *errLib.CommonError) {
+	row, err := r.Queries.CreateAdjustment(ctx, db.CreateAdjustmentParams{
+		TimesheetID: adjustment.TimesheetID,
+		AmountCents: adjustment.AmountCents,
+		Reason:      adjustment.Reason,
+		CreatedBy:   nullUUID(createdBy),
+	})
+	if err != nil {
+		log.Printf("Failed to adjust timesheet %s: %v", adjustment.TimesheetID, err)
+		return values.Adjustment{}, errLib.New("Failed to add adjustment", http.StatusInternalServerError)
+	}
+	return mapAdjustment(row), nil
+}
+
+func (r *Repository) GetAdjustment(ctx context.Context, id uuid.UUID) (values.Adjustment, *errLib.CommonError) {
+	row, err := r.Queries.GetAdjustmentById(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return values.Adjustment{}, errLib.New("Adjustment not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to get adjustment %s: %v", id, err)
+		return values.Adjustment{}, errLib.New("Failed to get adjustment", http.StatusInternalServerError)
+	}
+	return mapAdjustment(row), nil
+}
+
+func (r *Repository) DeleteAdjustment(ctx context.Context, id uuid.UUID) *errLib.CommonError {
+	if err := r.Queries.DeleteAdjustment(ctx, id); err != nil {
+		log.Printf("Failed to delete adjustment %s: %v", id, err)
+		return errLib.New("Failed to delete adjustment", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (r *Repository) ListAdjustments(ctx context.Context, timesheetID uuid.UUID) ([]values.Adjustment, *errLib.CommonError) {
+	rows, err := r.Queries.ListTimesheetAdjustments(ctx, timesheetID)
+	if err != nil {
+		log.Printf("Failed to list adjustments of timesheet %s: %v", timesheetID, err)
+		return nil, errLib.New("Failed to get timesheet", http.StatusInternalServerError)
+	}
+	return mapAdjustments(rows), nil
+}
+
+func (r *Repository) ListPeriodAdjustments(ctx context.Context, periodID uuid.UUID) ([]values.Adjustment, *errLib.CommonError) {
+	rows, err := r.Queries.ListPeriodAdjustments(ctx, periodID)
+	if err != nil {
+		log.Printf("Failed to list adjustments of pay period %s: %v", periodID, err)
+		return nil, errLib.New("Failed to export pay period", http.StatusInternalServerError)
+	}
+	return mapAdjustments(rows), nil
+}
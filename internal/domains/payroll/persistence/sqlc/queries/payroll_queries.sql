@@ -0,0 +1,288 @@
+-- name: CreatePayRate :one
+INSERT INTO payroll.pay_rates (staff_id, rate_type, program_id, amount_cents, effective_from, effective_to, created_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetPayRateById :one
+SELECT *
+FROM payroll.pay_rates
+WHERE id = $1;
+
+-- name: UpdatePayRate :one
+UPDATE payroll.pay_rates
+SET rate_type      = $2,
+    amount_cents   = $3,
+    effective_from = $4,
+    effective_to   = $5,
+    updated_at     = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
+
+-- name: DeletePayRate :execrows
+DELETE
+FROM payroll.pay_rates
+WHERE id = $1;
+
+-- name: ListPayRates :many
+SELECT *
+FROM payroll.pay_rates
+WHERE (sqlc.narg('staff_id')::uuid IS NULL OR staff_id = sqlc.narg('staff_id'))
+ORDER BY staff_id, program_id NULLS FIRST, effective_from DESC;
+
+-- Rates in effect on any day from starts_on to ends_on.
+-- name: ListPayRatesInRange :many
+SELECT *
+FROM payroll.pay_rates
+WHERE effective_from <= sqlc.arg('ends_on')::date
+  AND (effective_to IS NULL OR effective_to >= sqlc.arg('starts_on')::date)
+ORDER BY staff_id, effective_from;
+
+-- name: UpsertEmployee :one
+INSERT INTO payroll.employees (staff_id, external_employee_id, updated_by)
+VALUES ($1, $2, $3)
+ON CONFLICT (staff_id) DO UPDATE
+    SET external_employee_id = EXCLUDED.external_employee_id,
+        updated_by           = EXCLUDED.updated_by,
+        updated_at           = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: CreatePayPeriod :one
+INSERT INTO payroll.pay_periods (starts_on, ends_on, created_by)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetPayPeriodById :one
+SELECT *
+FROM payroll.pay_periods
+WHERE id = $1;
+
+-- name: GetPayPeriodByIdForUpdate :one
+SELECT *
+FROM payroll.pay_periods
+WHERE id = $1
+    FOR UPDATE;
+
+-- name: GetLatestPayPeriod :one
+SELECT *
+FROM payroll.pay_periods
+ORDER BY ends_on DESC
+LIMIT 1;
+
+-- name: ListPayPeriods :many
+SELECT *
+FROM payroll.pay_periods
+WHERE (sqlc.narg('status')::text IS NULL OR status = sqlc.narg('status'))
+ORDER BY starts_on DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ClosePayPeriod :exec
+UPDATE payroll.pay_periods
+SET status    = 'closed',
+    closed_at = CURRENT_TIMESTAMP,
+    closed_by = $2
+WHERE id = $1;
+
+-- name: ReopenPayPeriod :exec
+UPDATE payroll.pay_periods
+SET status    = 'open',
+    closed_at = NULL,
+    closed_by = NULL
+WHERE id = $1;
+
+-- Sessions staff were assigned to that start in [from, to), with their check-in times.
+-- name: ListStaffAssignments :many
+SELECT a.staff_id::uuid         AS staff_id,
+       a.source_type::text      AS source_type,
+       a.source_id::uuid        AS source_id,
+       a.program_id,
+       a.description::text      AS description,
+       a.scheduled_start::timestamptz AS scheduled_start,
+       a.scheduled_end::timestamptz   AS scheduled_end,
+       c.checked_in_at,
+       c.checked_out_at
+FROM payroll.staff_assignments a
+         LEFT JOIN payroll.check_ins c
+                   ON c.staff_id = a.staff_id AND c.source_type = a.source_type AND c.source_id = a.source_id
+WHERE a.scheduled_start >= sqlc.arg('from')::timestamptz
+  AND a.scheduled_start < sqlc.arg('to')::timestamptz
+ORDER BY a.staff_id, a.scheduled_start;
+
+-- name: GetStaffAssignment :one
+SELECT a.staff_id::uuid         AS staff_id,
+       a.source_type::text      AS source_type,
+       a.source_id::uuid        AS source_id,
+       a.program_id,
+       a.description::text      AS description,
+       a.scheduled_start::timestamptz AS scheduled_start,
+       a.scheduled_end::timestamptz   AS scheduled_end,
+       c.checked_in_at,
+       c.checked_out_at
+FROM payroll.staff_assignments a
+         LEFT JOIN payroll.check_ins c
+                   ON c.staff_id = a.staff_id AND c.source_type = a.source_type AND c.source_id = a.source_id
+WHERE a.staff_id = sqlc.arg('staff_id')::uuid
+  AND a.source_type = sqlc.arg('source_type')::text
+  AND a.source_id = sqlc.arg('source_id')::uuid
+LIMIT 1;
+
+-- name: CreateCheckIn :one
+INSERT INTO payroll.check_ins (staff_id, source_type, source_id, checked_in_at)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: CheckOut :one
+UPDATE payroll.check_ins
+SET checked_out_at = sqlc.arg('checked_out_at')
+WHERE staff_id = sqlc.arg('staff_id')
+  AND source_type = sqlc.arg('source_type')
+  AND source_id = sqlc.arg('source_id')
+  AND checked_out_at IS NULL
+RETURNING *;
+
+-- Creates the staff member's timesheet for the period, or marks it regenerated. No row
+-- comes back for timesheets that were submitted or approved, which keep their entries.
+-- name: UpsertTimesheet :one
+INSERT INTO payroll.timesheets (pay_period_id, staff_id)
+VALUES ($1, $2)
+ON CONFLICT (pay_period_id, staff_id) DO UPDATE
+    SET generated_at = CURRENT_TIMESTAMP,
+        updated_at   = CURRENT_TIMESTAMP
+WHERE payroll.timesheets.status IN ('draft', 'rejected')
+RETURNING *;
+
+-- name: GetTimesheetByIdForUpdate :one
+SELECT *
+FROM payroll.timesheets
+WHERE id = $1
+    FOR UPDATE;
+
+-- name: GetTimesheetById :one
+SELECT t.id,
+       t.pay_period_id,
+       t.staff_id,
+       t.status,
+       t.submitted_at,
+       t.reviewed_at,
+       t.reviewed_by,
+       t.rejection_reason,
+       t.generated_at,
+       p.starts_on,
+       p.ends_on,
+       u.first_name,
+       u.last_name,
+       e.external_employee_id,
+       COALESCE((SELECT SUM(te.worked_minutes) FROM payroll.timesheet_entries te WHERE te.timesheet_id = t.id),
+                0)::int AS worked_minutes,
+       COALESCE((SELECT SUM(te.amount_cents) FROM payroll.timesheet_entries te WHERE te.timesheet_id = t.id),
+                0)::int AS entries_cents,
+       COALESCE((SELECT SUM(a.amount_cents) FROM payroll.adjustments a WHERE a.timesheet_id = t.id),
+                0)::int AS adjustments_cents
+FROM payroll.timesheets t
+         JOIN payroll.pay_periods p ON p.id = t.pay_period_id
+         JOIN users.users u ON u.id = t.staff_id
+         LEFT JOIN payroll.employees e ON e.staff_id = t.staff_id
+WHERE t.id = $1;
+
+-- name: ListTimesheets :many
+SELECT t.id,
+       t.pay_period_id,
+       t.staff_id,
+       t.status,
+       t.submitted_at,
+       t.reviewed_at,
+       t.reviewed_by,
+       t.rejection_reason,
+       t.generated_at,
+       p.starts_on,
+       p.ends_on,
+       u.first_name,
+       u.last_name,
+       e.external_employee_id,
+       COALESCE((SELECT SUM(te.worked_minutes) FROM payroll.timesheet_entries te WHERE te.timesheet_id = t.id),
+                0)::int AS worked_minutes,
+       COALESCE((SELECT SUM(te.amount_cents) FROM payroll.timesheet_entries te WHERE te.timesheet_id = t.id),
+                0)::int AS entries_cents,
+       COALESCE((SELECT SUM(a.amount_cents) FROM payroll.adjustments a WHERE a.timesheet_id = t.id),
+                0)::int AS adjustments_cents
+FROM payroll.timesheets t
+         JOIN payroll.pay_periods p ON p.id = t.pay_period_id
+         JOIN users.users u ON u.id = t.staff_id
+         LEFT JOIN payroll.employees e ON e.staff_id = t.staff_id
+WHERE (sqlc.narg('pay_period_id')::uuid IS NULL OR t.pay_period_id = sqlc.narg('pay_period_id'))
+  AND (sqlc.narg('staff_id')::uuid IS NULL OR t.staff_id = sqlc.narg('staff_id'))
+  AND (sqlc.narg('status')::text IS NULL OR t.status = sqlc.narg('status'))
+ORDER BY p.starts_on DESC, u.last_name, u.first_name
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListPeriodTimesheetStaff :many
+SELECT staff_id
+FROM payroll.timesheets
+WHERE pay_period_id = $1;
+
+-- name: CountUnapprovedTimesheets :one
+SELECT COUNT(*)
+FROM payroll.timesheets
+WHERE pay_period_id = $1
+  AND status <> 'approved';
+
+-- name: UpdateTimesheetStatus :exec
+UPDATE payroll.timesheets
+SET status           = $2,
+    submitted_at     = $3,
+    reviewed_at      = $4,
+    reviewed_by      = $5,
+    rejection_reason = $6,
+    updated_at       = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: DeleteTimesheetEntries :exec
+DELETE
+FROM payroll.timesheet_entries
+WHERE timesheet_id = $1;
+
+-- name: CreateTimesheetEntry :exec
+INSERT INTO payroll.timesheet_entries (timesheet_id, source_type, source_id, program_id, description, scheduled_start,
+                                       scheduled_end, checked_in_at, checked_out_at, worked_minutes, rate_id,
+                                       rate_type, rate_cents, amount_cents)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14);
+
+-- name: ListTimesheetEntries :many
+SELECT *
+FROM payroll.timesheet_entries
+WHERE timesheet_id = $1
+ORDER BY scheduled_start;
+
+-- name: ListPeriodEntries :many
+SELECT te.*
+FROM payroll.timesheet_entries te
+         JOIN payroll.timesheets t ON t.id = te.timesheet_id
+WHERE t.pay_period_id = $1
+ORDER BY t.staff_id, te.scheduled_start;
+
+-- name: CreateAdjustment :one
+INSERT INTO payroll.adjustments (timesheet_id, amount_cents, reason, created_by)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetAdjustmentById :one
+SELECT *
+FROM payroll.adjustments
+WHERE id = $1;
+
+-- name: DeleteAdjustment :exec
+DELETE
+FROM payroll.adjustments
+WHERE id = $1;
+
+-- name: ListTimesheetAdjustments :many
+SELECT *
+FROM payroll.adjustments
+WHERE timesheet_id = $1
+ORDER BY created_at;
+
+-- name: ListPeriodAdjustments :many
+SELECT a.*
+FROM payroll.adjustments a
+         JOIN payroll.timesheets t ON t.id = a.timesheet_id
+WHERE t.pay_period_id = $1
+ORDER BY t.staff_id, a.created_at;
@@ -0,0 +1,750 @@
+package payroll
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"api/internal/di"
+	auditValues "api/internal/domains/audit/audit_logs/values"
+	staffActivityLogs "api/internal/domains/audit/staff_activity_logs/service"
+	repo "api/internal/domains/payroll/persistence"
+	db "api/internal/domains/payroll/persistence/sqlc/generated"
+	values "api/internal/domains/payroll/values"
+	errLib "api/internal/libs/errors"
+	contextUtils "api/utils/context"
+	txUtils "api/utils/db"
+	"api/utils/timezone"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// CheckInOpens is how long before a session starts staff can check in to it.
+	CheckInOpens = time.Hour
+	// CheckOutCloses is how long after a session was scheduled to end staff can still
+	// check out of it. Later corrections are made by an admin with an adjustment.
+	CheckOutCloses = 4 * time.Hour
+
+	// exportLimit bounds the timesheets read for one pay period.
+	exportLimit = 10000
+	// maxPeriodsCreated bounds how many periods one run creates to catch up.
+	maxPeriodsCreated = 6
+)
+
+// Service pays staff for the sessions they were assigned to. Timesheets are generated
+// from assignments, check-ins and pay rates for each open pay period; staff submit
+// them, admins approve them, and closed periods are exported for the payroll provider.
+type Service struct {
+	repo                     *repo.Repository
+	staffActivityLogsService *staffActivityLogs.Service
+	db                       *sql.DB
+}
+
+func NewService(container *di.Container) *Service {
+	return &Service{
+		repo:                     repo.NewRepository(container),
+		staffActivityLogsService: staffActivityLogs.NewService(container),
+		db:                       container.DB,
+	}
+}
+
+func validateRate(rate values.PayRateValues) *errLib.CommonError {
+	if rate.RateType != values.RateHourly && rate.RateType != values.RatePerSession {
+		return errLib.New("rate_type must be hourly or per_session", http.StatusBadRequest)
+	}
+	if rate.AmountCents < 0 {
+		return errLib.New("amount_cents cannot be negative", http.StatusBadRequest)
+	}
+	if rate.EffectiveTo != nil && rate.EffectiveTo.Before(rate.EffectiveFrom) {
+		return errLib.New("effective_to cannot be before effective_from", http.StatusBadRequest)
+	}
+	return nil
+}
+
+func (s *Service) CreateRate(ctx context.Context, rate values.PayRateValues) (values.PayRate, *errLib.CommonError) {
+	if err := validateRate(rate); err != nil {
+		return values.PayRate{}, err
+	}
+	staffID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return values.PayRate{}, err
+	}
+
+	var created values.PayRate
+	err = txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		var err *errLib.CommonError
+		if created, err = s.repo.WithTx(tx).CreateRate(ctx, rate, staffID); err != nil {
+			return err
+		}
+		return s.staffActivityLogsService.RecordChange(ctx, tx, staffID, auditValues.Entry{
+			EntityType:  "pay_rate",
+			EntityID:    created.ID.String(),
+			Action:      auditValues.ActionCreate,
+			After:       created,
+			Description: fmt.Sprintf("Set %s pay rate of %s for staff %s", rate.RateType, formatCents(rate.AmountCents), rate.StaffID),
+		})
+	})
+	return created, err
+}
+
+// UpdateRate changes a rate's amount or dates. Timesheets already generated keep the
+// amount they were generated with until they are regenerated.
+func (s *Service) UpdateRate(ctx context.Context, id uuid.UUID, rate values.PayRateValues) (values.PayRate, *errLib.CommonError) {
+	if err := validateRate(rate); err != nil {
+		return values.PayRate{}, err
+	}
+	staffID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return values.PayRate{}, err
+	}
+
+	var updated values.PayRate
+	err = txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		r := s.repo.WithTx(tx)
+		before, err := r.GetRate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if updated, err = r.UpdateRate(ctx, id, rate); err != nil {
+			return err
+		}
+		return s.staffActivityLogsService.RecordChange(ctx, tx, staffID, auditValues.Entry{
+			EntityType:  "pay_rate",
+			EntityID:    id.String(),
+			Action:      auditValues.ActionUpdate,
+			Before:      before,
+			After:       updated,
+			Description: fmt.Sprintf("Updated pay rate of staff %s to %s %s", before.StaffID, formatCents(rate.AmountCents), rate.RateType),
+		})
+	})
+	return updated, err
+}
+
+func (s *Service) DeleteRate(ctx context.Context, id uuid.UUID) *errLib.CommonError {
+	staffID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		r := s.repo.WithTx(tx)
+		before, err := r.GetRate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err = r.DeleteRate(ctx, id); err != nil {
+			return err
+		}
+		return s.staffActivityLogsService.RecordChange(ctx, tx, staffID, auditValues.Entry{
+			EntityType:  "pay_rate",
+			EntityID:    id.String(),
+			Action:      auditValues.ActionDelete,
+			Before:      before,
+			Description: fmt.Sprintf("Deleted %s pay rate of staff %s", before.RateType, before.StaffID),
+		})
+	})
+}
+
+func (s *Service) ListRates(ctx context.Context, staffID uuid.UUID) ([]values.PayRate, *errLib.CommonError) {
+	return s.repo.ListRates(ctx, staffID)
+}
+
+// SetEmployeeID records the ID the payroll provider knows the staff member by.
+func (s *Service) SetEmployeeID(ctx context.Context, staffID uuid.UUID, externalID string) *errLib.CommonError {
+	adminID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		if err := s.repo.WithTx(tx).UpsertEmployee(ctx, staffID, externalID, adminID); err != nil {
+			return err
+		}
+		return s.staffActivityLogsService.RecordChange(ctx, tx, adminID, auditValues.Entry{
+			EntityType:  "payroll_employee",
+			EntityID:    staffID.String(),
+			Action:      auditValues.ActionUpdate,
+			After:       map[string]string{"external_employee_id": externalID},
+			Description: fmt.Sprintf("Set payroll employee ID of staff %s to %s", staffID, externalID),
+		})
+	})
+}
+
+func (s *Service) CreatePeriod(ctx context.Context, startsOn, endsOn time.Time) (values.PayPeriod, *errLib.CommonError) {
+	if endsOn.Before(startsOn) {
+		return values.PayPeriod{}, errLib.New("ends_on cannot be before starts_on", http.StatusBadRequest)
+	}
+	staffID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return values.PayPeriod{}, err
+	}
+
+	var period values.PayPeriod
+	err = txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		var err *errLib.CommonError
+		if period, err = s.repo.WithTx(tx).CreatePeriod(ctx, startsOn, endsOn, staffID); err != nil {
+			return err
+		}
+		return s.staffActivityLogsService.RecordChange(ctx, tx, staffID, auditValues.Entry{
+			EntityType:  "pay_period",
+			EntityID:    period.ID.String(),
+			Action:      auditValues.ActionCreate,
+			After:       period,
+			Description: fmt.Sprintf("Created pay period %s", periodLabel(period)),
+		})
+	})
+	return period, err
+}
+
+func (s *Service) ListPeriods(ctx context.Context, status string, limit, offset int32) ([]values.PayPeriod, *errLib.CommonError) {
+	return s.repo.ListPeriods(ctx, status, limit, offset)
+}
+
+func (s *Service) GetPeriod(ctx context.Context, id uuid.UUID) (values.PayPeriod, *errLib.CommonError) {
+	return s.repo.GetPeriod(ctx, id)
+}
+
+// GenerateTimesheets regenerates the draft and rejected timesheets of an open pay period
+// from the sessions staff were assigned to, their check-ins and their pay rates. Staff
+// without a rate in effect during the period get no timesheet. It returns how many
+// timesheets were generated.
+func (s *Service) GenerateTimesheets(ctx context.Context, periodID uuid.UUID) (int, *errLib.CommonError) {
+	loc := timezone.Default()
+	generated := 0
+
+	err := txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		r := s.repo.WithTx(tx)
+		generated = 0
+
+		period, err := r.LockPeriod(ctx, periodID)
+		if err != nil {
+			return err
+		}
+		if period.Status != values.PeriodOpen {
+			return errLib.New("Timesheets of a closed pay period cannot be regenerated", http.StatusConflict)
+		}
+
+		rates, err := r.ListRatesInEffect(ctx, period)
+		if err != nil {
+			return err
+		}
+		from, to := period.Bounds(loc)
+		assignments, err := r.ListAssignments(ctx, from, to)
+		if err != nil {
+			return err
+		}
+		existing, err := r.ListTimesheetStaff(ctx, period.ID)
+		if err != nil {
+			return err
+		}
+
+		ratesByStaff := make(map[uuid.UUID][]values.PayRate)
+		for _, rate := range rates {
+			ratesByStaff[rate.StaffID] = append(ratesByStaff[rate.StaffID], rate)
+		}
+		assignmentsByStaff := make(map[uuid.UUID][]values.Assignment)
+		var staffIDs []uuid.UUID
+		for _, assignment := range assignments {
+			if _, paid := ratesByStaff[assignment.StaffID]; !paid {
+				continue
+			}
+			if _, seen := assignmentsByStaff[assignment.StaffID]; !seen {
+				staffIDs = append(staffIDs, assignment.StaffID)
+			}
+			assignmentsByStaff[assignment.StaffID] = append(assignmentsByStaff[assignment.StaffID], assignment)
+		}
+		// Timesheets whose sessions were all cancelled or reassigned are emptied
+		for _, staffID := range existing {
+			if _, seen := assignmentsByStaff[staffID]; !seen {
+				staffIDs = append(staffIDs, staffID)
+			}
+		}
+
+		for _, staffID := range staffIDs {
+			timesheetID, ok, err := r.UpsertTimesheet(ctx, period.ID, staffID)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+			entries := make([]values.TimesheetEntry, 0, len(assignmentsByStaff[staffID]))
+			for _, assignment := range assignmentsByStaff[staffID] {
+				entries = append(entries, values.PriceAssignment(assignment, ratesByStaff[staffID], loc))
+			}
+			if err = r.ReplaceEntries(ctx, timesheetID, entries); err != nil {
+				return err
+			}
+			generated++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return generated, nil
+}
+
+// ExtendPeriods creates the pay periods that follow the latest one, each as long as
+// the one before it, until one covers today. The first period is created by an admin.
+func (s *Service) ExtendPeriods(ctx context.Context, now time.Time) (int, *errLib.CommonError) {
+	latest, err := s.repo.GetLatestPeriod(ctx)
+	if err != nil || latest == nil {
+		return 0, err
+	}
+
+	today := values.DateOf(now, timezone.Default())
+	created := 0
+	for created < maxPeriodsCreated && today.After(latest.EndsOn) {
+		startsOn, endsOn := latest.Next()
+		period, err := s.repo.CreatePeriod(ctx, startsOn, endsOn, uuid.Nil)
+		if err != nil {
+			return created, err
+		}
+		log.Printf("[PAYROLL] Created pay period %s", periodLabel(period))
+		latest = &period
+		created++
+	}
+	return created, nil
+}
+
+// GenerateOpenPeriods regenerates the timesheets of every open pay period.
+func (s *Service) GenerateOpenPeriods(ctx context.Context) (int, *errLib.CommonError) {
+	periods, err := s.repo.ListPeriods(ctx, values.PeriodOpen, 100, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	generated := 0
+	for _, period := range periods {
+		count, err := s.GenerateTimesheets(ctx, period.ID)
+		if err != nil {
+			log.Printf("[PAYROLL] Failed to generate timesheets for pay period %s: %s", periodLabel(period), err.Message)
+			continue
+		}
+		generated += count
+	}
+	return generated, nil
+}
+
+// ClosePeriod closes a pay period once all of its timesheets are approved, freezing
+// them for export.
+func (s *Service) ClosePeriod(ctx context.Context, id uuid.UUID) (values.PayPeriod, *errLib.CommonError) {
+	staffID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return values.PayPeriod{}, err
+	}
+
+	err = txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		r := s.repo.WithTx(tx)
+		period, err := r.LockPeriod(ctx, id)
+		if err != nil {
+			return err
+		}
+		unapproved, err := r.CountUnapproved(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err = checkClose(period, unapproved); err != nil {
+			return err
+		}
+		if err = r.ClosePeriod(ctx, id, staffID); err != nil {
+			return err
+		}
+		return s.staffActivityLogsService.RecordChange(ctx, tx, staffID, auditValues.Entry{
+			EntityType:  "pay_period",
+			EntityID:    id.String(),
+			Action:      "close",
+			Before:      map[string]string{"status": period.Status},
+			After:       map[string]string{"status": values.PeriodClosed},
+			Description: fmt.Sprintf("Closed pay period %s", periodLabel(period)),
+		})
+	})
+	if err != nil {
+		return values.PayPeriod{}, err
+	}
+	return s.repo.GetPeriod(ctx, id)
+}
+
+// ReopenPeriod reopens a closed pay period so its timesheets can be corrected.
+func (s *Service) ReopenPeriod(ctx context.Context, id uuid.UUID) (values.PayPeriod, *errLib.CommonError) {
+	staffID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return values.PayPeriod{}, err
+	}
+
+	err = txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		r := s.repo.WithTx(tx)
+		period, err := r.LockPeriod(ctx, id)
+		if err != nil {
+			return err
+		}
+		if period.Status == values.PeriodOpen {
+			return errLib.New("Pay period is already open", http.StatusConflict)
+		}
+		if err = r.ReopenPeriod(ctx, id); err != nil {
+			return err
+		}
+		return s.staffActivityLogsService.RecordChange(ctx, tx, staffID, auditValues.Entry{
+			EntityType:  "pay_period",
+			EntityID:    id.String(),
+			Action:      "reopen",
+			Before:      map[string]string{"status": period.Status},
+			After:       map[string]string{"status": values.PeriodOpen},
+			Description: fmt.Sprintf("Reopened pay period %s", periodLabel(period)),
+		})
+	})
+	if err != nil {
+		return values.PayPeriod{}, err
+	}
+	return s.repo.GetPeriod(ctx, id)
+}
+
+// ExportPeriod reads a closed pay period's approved timesheets for export.
+func (s *Service) ExportPeriod(ctx context.Context, id uuid.UUID) (values.PeriodExport, *errLib.CommonError) {
+	period, err := s.repo.GetPeriod(ctx, id)
+	if err != nil {
+		return values.PeriodExport{}, err
+	}
+	if period.Status != values.PeriodClosed {
+		return values.PeriodExport{}, errLib.New("Close the pay period before exporting it", http.StatusConflict)
+	}
+
+	export := values.PeriodExport{Period: period}
+	if export.Timesheets, err = s.repo.ListTimesheets(ctx, values.TimesheetFilter{PayPeriodID: id, Limit: exportLimit}); err != nil {
+		return values.PeriodExport{}, err
+	}
+	if export.Entries, err = s.repo.ListPeriodEntries(ctx, id); err != nil {
+		return values.PeriodExport{}, err
+	}
+	if export.Adjustments, err = s.repo.ListPeriodAdjustments(ctx, id); err != nil {
+		return values.PeriodExport{}, err
+	}
+	return export, nil
+}
+
+func (s *Service) ListTimesheets(ctx context.Context, filter values.TimesheetFilter) ([]values.Timesheet, *errLib.CommonError) {
+	return s.repo.ListTimesheets(ctx, filter)
+}
+
+// GetTimesheet returns a timesheet with its entries and adjustments. Staff other than
+// admins only see their own.
+func (s *Service) GetTimesheet(ctx context.Context, id uuid.UUID, ownerOnly bool) (values.TimesheetDetail, *errLib.CommonError) {
+	timesheet, err := s.repo.GetTimesheet(ctx, id)
+	if err != nil {
+		return values.TimesheetDetail{}, err
+	}
+	if ownerOnly {
+		staffID, err := contextUtils.GetUserID(ctx)
+		if err != nil {
+			return values.TimesheetDetail{}, err
+		}
+		if timesheet.StaffID != staffID {
+			return values.TimesheetDetail{}, errLib.New("Timesheet not found", http.StatusNotFound)
+		}
+	}
+
+	detail := values.TimesheetDetail{Timesheet: timesheet}
+	if detail.Entries, err = s.repo.ListEntries(ctx, id); err != nil {
+		return values.TimesheetDetail{}, err
+	}
+	if detail.Adjustments, err = s.repo.ListAdjustments(ctx, id); err != nil {
+		return values.TimesheetDetail{}, err
+	}
+	return detail, nil
+}
+
+// lockOpenTimesheet locks a timesheet of an open pay period.
+func lockOpenTimesheet(ctx context.Context, r *repo.Repository, id uuid.UUID) (db.PayrollTimesheet, *errLib.CommonError) {
+	timesheet, err := r.LockTimesheet(ctx, id)
+	if err != nil {
+		return timesheet, err
+	}
+	period, err := r.GetPeriod(ctx, timesheet.PayPeriodID)
+	if err != nil {
+		return timesheet, err
+	}
+	if period.Status != values.PeriodOpen {
+		return timesheet, errLib.New("The pay period of this timesheet is closed", http.StatusConflict)
+	}
+	return timesheet, nil
+}
+
+// checkSubmit reports why staffID cannot submit a timesheet, if anything. Only the
+// staff member's own drafts and rejected timesheets can be submitted.
+func checkSubmit(timesheet db.PayrollTimesheet, staffID uuid.UUID) *errLib.CommonError {
+	if timesheet.StaffID != staffID {
+		return errLib.New("Timesheet not found", http.StatusNotFound)
+	}
+	if timesheet.Status != values.TimesheetDraft && timesheet.Status != values.TimesheetRejected {
+		return errLib.New("Timesheet was already submitted", http.StatusConflict)
+	}
+	return nil
+}
+
+// checkReview reports why reviewerID cannot review a timesheet, if anything. Only
+// submitted timesheets are reviewed, and never by the staff member they pay.
+func checkReview(timesheet db.PayrollTimesheet, reviewerID uuid.UUID) *errLib.CommonError {
+	if timesheet.StaffID == reviewerID {
+		return errLib.New("You cannot review your own timesheet", http.StatusForbidden)
+	}
+	if timesheet.Status != values.TimesheetSubmitted {
+		return errLib.New("Only submitted timesheets can be reviewed", http.StatusConflict)
+	}
+	return nil
+}
+
+// checkClose reports why a pay period cannot be closed, if anything. Every timesheet in
+// it has to be approved first.
+func checkClose(period values.PayPeriod, unapproved int64) *errLib.CommonError {
+	if period.Status == values.PeriodClosed {
+		return errLib.New("Pay period is already closed", http.StatusConflict)
+	}
+	if unapproved > 0 {
+		return errLib.New(fmt.Sprintf("%d timesheets in this pay period are not approved yet", unapproved), http.StatusConflict)
+	}
+	return nil
+}
+
+// SubmitTimesheet sends the logged-in staff member's timesheet for approval.
+func (s *Service) SubmitTimesheet(ctx context.Context, id uuid.UUID) (values.TimesheetDetail, *errLib.CommonError) {
+	staffID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return values.TimesheetDetail{}, err
+	}
+
+	err = txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		r := s.repo.WithTx(tx)
+		timesheet, err := lockOpenTimesheet(ctx, r, id)
+		if err != nil {
+			return err
+		}
+		if err = checkSubmit(timesheet, staffID); err != nil {
+			return err
+		}
+		if err = r.UpdateTimesheetStatus(ctx, db.UpdateTimesheetStatusParams{
+			ID:          id,
+			Status:      values.TimesheetSubmitted,
+			SubmittedAt: sql.NullTime{Time: time.Now(), Valid: true},
+		}); err != nil {
+			return err
+		}
+		return s.staffActivityLogsService.RecordChange(ctx, tx, staffID, auditValues.Entry{
+			EntityType:  "timesheet",
+			EntityID:    id.String(),
+			Action:      "submit",
+			Before:      map[string]string{"status": timesheet.Status},
+			After:       map[string]string{"status": values.TimesheetSubmitted},
+			Description: "Submitted timesheet " + id.String(),
+		})
+	})
+	if err != nil {
+		return values.TimesheetDetail{}, err
+	}
+	return s.GetTimesheet(ctx, id, false)
+}
+
+// ReviewTimesheet approves or rejects a submitted timesheet. Staff cannot review their own.
+func (s *Service) ReviewTimesheet(ctx context.Context, review values.ReviewValues) (values.TimesheetDetail, *errLib.CommonError) {
+	if !review.Approve && (review.Reason == nil || *review.Reason == "") {
+		return values.TimesheetDetail{}, errLib.New("A reason is required when rejecting a timesheet", http.StatusBadRequest)
+	}
+
+	err := txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		r := s.repo.WithTx(tx)
+		timesheet, err := lockOpenTimesheet(ctx, r, review.TimesheetID)
+		if err != nil {
+			return err
+		}
+		if err = checkReview(timesheet, review.ReviewerID); err != nil {
+			return err
+		}
+
+		status, action, verb := values.TimesheetApproved, "approve", "Approved"
+		params := db.UpdateTimesheetStatusParams{
+			ID:          timesheet.ID,
+			SubmittedAt: timesheet.SubmittedAt,
+			ReviewedAt:  sql.NullTime{Time: time.Now(), Valid: true},
+			ReviewedBy:  uuid.NullUUID{UUID: review.ReviewerID, Valid: true},
+		}
+		if !review.Approve {
+			status, action, verb = values.TimesheetRejected, "reject", "Rejected"
+			params.RejectionReason = sql.NullString{String: *review.Reason, Valid: true}
+		}
+		params.Status = status
+		if err = r.UpdateTimesheetStatus(ctx, params); err != nil {
+			return err
+		}
+
+		description := fmt.Sprintf("%s timesheet %s of staff %s", verb, timesheet.ID, timesheet.StaffID)
+		if !review.Approve {
+			description += ": " + *review.Reason
+		}
+		return s.staffActivityLogsService.RecordChange(ctx, tx, review.ReviewerID, auditValues.Entry{
+			EntityType:  "timesheet",
+			EntityID:    timesheet.ID.String(),
+			Action:      action,
+			Before:      map[string]string{"status": timesheet.Status},
+			After:       map[string]string{"status": status},
+			Description: description,
+		})
+	})
+	if err != nil {
+		return values.TimesheetDetail{}, err
+	}
+	return s.GetTimesheet(ctx, review.TimesheetID, false)
+}
+
+// ReopenTimesheet turns a submitted, approved or rejected timesheet back into a draft,
+// so it is regenerated and goes through approval again.
+func (s *Service) ReopenTimesheet(ctx context.Context, id uuid.UUID) (values.TimesheetDetail, *errLib.CommonError) {
+	adminID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return values.TimesheetDetail{}, err
+	}
+
+	err = txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		r := s.repo.WithTx(tx)
+		timesheet, err := lockOpenTimesheet(ctx, r, id)
+		if err != nil {
+			return err
+		}
+		if timesheet.Status == values.TimesheetDraft {
+			return errLib.New("Timesheet is already a draft", http.StatusConflict)
+		}
+		if err = r.UpdateTimesheetStatus(ctx, db.UpdateTimesheetStatusParams{ID: id, Status: values.TimesheetDraft}); err != nil {
+			return err
+		}
+		return s.staffActivityLogsService.RecordChange(ctx, tx, adminID, auditValues.Entry{
+			EntityType:  "timesheet",
+			EntityID:    id.String(),
+			Action:      "reopen",
+			Before:      map[string]string{"status": timesheet.Status},
+			After:       map[string]string{"status": values.TimesheetDraft},
+			Description: fmt.Sprintf("Reopened timesheet %s of staff %s", id, timesheet.StaffID),
+		})
+	})
+	if err != nil {
+		return values.TimesheetDetail{}, err
+	}
+	return s.GetTimesheet(ctx, id, false)
+}
+
+// AddAdjustment corrects a timesheet's pay by a signed amount. Approved timesheets have
+// to be reopened first.
+func (s *Service) AddAdjustment(ctx context.Context, adjustment values.AdjustmentValues) (values.Adjustment, *errLib.CommonError) {
+	if adjustment.AmountCents == 0 {
+		return values.Adjustment{}, errLib.New("amount_cents cannot be zero", http.StatusBadRequest)
+	}
+	adminID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return values.Adjustment{}, err
+	}
+
+	var created values.Adjustment
+	err = txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		r := s.repo.WithTx(tx)
+		timesheet, err := lockOpenTimesheet(ctx, r, adjustment.TimesheetID)
+		if err != nil {
+			return err
+		}
+		if timesheet.Status == values.TimesheetApproved {
+			return errLib.New("Reopen the timesheet to adjust it", http.StatusConflict)
+		}
+		if created, err = r.CreateAdjustment(ctx, adjustment, adminID); err != nil {
+			return err
+		}
+		return s.staffActivityLogsService.RecordChange(ctx, tx, adminID, auditValues.Entry{
+			EntityType: "timesheet_adjustment",
+			EntityID:   created.ID.String(),
+			Action:     auditValues.ActionCreate,
+			After:      created,
+			Description: fmt.Sprintf("Adjusted timesheet %s of staff %s by %s: %s",
+				timesheet.ID, timesheet.StaffID, formatCents(adjustment.AmountCents), adjustment.Reason),
+		})
+	})
+	return created, err
+}
+
+func (s *Service) DeleteAdjustment(ctx context.Context, id uuid.UUID) *errLib.CommonError {
+	adminID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		r := s.repo.WithTx(tx)
+		adjustment, err := r.GetAdjustment(ctx, id)
+		if err != nil {
+			return err
+		}
+		timesheet, err := lockOpenTimesheet(ctx, r, adjustment.TimesheetID)
+		if err != nil {
+			return err
+		}
+		if timesheet.Status == values.TimesheetApproved {
+			return errLib.New("Reopen the timesheet to adjust it", http.StatusConflict)
+		}
+		if err = r.DeleteAdjustment(ctx, id); err != nil {
+			return err
+		}
+		return s.staffActivityLogsService.RecordChange(ctx, tx, adminID, auditValues.Entry{
+			EntityType: "timesheet_adjustment",
+			EntityID:   id.String(),
+			Action:     auditValues.ActionDelete,
+			Before:     adjustment,
+			Description: fmt.Sprintf("Removed %s adjustment from timesheet %s of staff %s",
+				formatCents(adjustment.AmountCents), timesheet.ID, timesheet.StaffID),
+		})
+	})
+}
+
+// CheckIn records the logged-in staff member arriving at a session they are assigned
+// to, from an hour before it starts until it ends.
+func (s *Service) CheckIn(ctx context.Context, session values.CheckInValues, now time.Time) (values.CheckIn, *errLib.CommonError) {
+	assignment, err := s.repo.GetAssignment(ctx, session)
+	if err != nil {
+		return values.CheckIn{}, err
+	}
+	if err = checkInWindow(assignment, now); err != nil {
+		return values.CheckIn{}, err
+	}
+	return s.repo.CheckIn(ctx, session, now)
+}
+
+// CheckOut records the logged-in staff member leaving a session they checked in to.
+func (s *Service) CheckOut(ctx context.Context, session values.CheckInValues, now time.Time) (values.CheckIn, *errLib.CommonError) {
+	assignment, err := s.repo.GetAssignment(ctx, session)
+	if err != nil {
+		return values.CheckIn{}, err
+	}
+	if now.After(assignment.ScheduledEnd.Add(CheckOutCloses)) {
+		return values.CheckIn{}, errLib.New("Check-out for this session has closed; ask an admin to adjust your timesheet", http.StatusConflict)
+	}
+	return s.repo.CheckOut(ctx, session, now)
+}
+
+func checkInWindow(assignment values.Assignment, now time.Time) *errLib.CommonError {
+	if now.Before(assignment.ScheduledStart.Add(-CheckInOpens)) {
+		return errLib.New("Check-in opens an hour before the session starts", http.StatusConflict)
+	}
+	if !now.Before(assignment.ScheduledEnd) {
+		return errLib.New("This session has already ended", http.StatusConflict)
+	}
+	return nil
+}
+
+func periodLabel(period values.PayPeriod) string {
+	return period.StartsOn.Format("2006-01-02") + " to " + period.EndsOn.Format("2006-01-02")
+}
+
+func formatCents(cents int32) string {
+	if cents < 0 {
+		return "-$" + values.FormatAmount(-cents)
+	}
+	return "$" + values.FormatAmount(cents)
+}
@@ -0,0 +1,70 @@
+package payroll
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	db "api/internal/domains/payroll/persistence/sqlc/generated"
+	values "api/internal/domains/payroll/values"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckInWindow(t *testing.T) {
+	start := time.Date(2026, 4, 1, 18, 0, 0, 0, time.UTC)
+	assignment := values.Assignment{ScheduledStart: start, ScheduledEnd: start.Add(90 * time.Minute)}
+
+	assert.NotNil(t, checkInWindow(assignment, start.Add(-CheckInOpens-time.Minute)), "too early")
+	assert.Nil(t, checkInWindow(assignment, start.Add(-CheckInOpens)))
+	assert.Nil(t, checkInWindow(assignment, start.Add(30*time.Minute)), "late arrivals still check in")
+	assert.NotNil(t, checkInWindow(assignment, assignment.ScheduledEnd), "session over")
+}
+
+func TestTimesheetTransitions(t *testing.T) {
+	staffID, adminID := uuid.New(), uuid.New()
+	timesheet := db.PayrollTimesheet{ID: uuid.New(), StaffID: staffID, Status: values.TimesheetDraft}
+	period := values.PayPeriod{Status: values.PeriodOpen}
+
+	err := checkReview(timesheet, adminID)
+	require.NotNil(t, err, "a draft cannot be reviewed")
+	assert.Equal(t, http.StatusConflict, err.HTTPCode)
+	err = checkClose(period, 1)
+	require.NotNil(t, err, "a period with an unapproved timesheet cannot be closed")
+	assert.Equal(t, http.StatusConflict, err.HTTPCode)
+
+	err = checkSubmit(timesheet, adminID)
+	require.NotNil(t, err, "only the staff member submits their timesheet")
+	assert.Equal(t, http.StatusNotFound, err.HTTPCode)
+	require.Nil(t, checkSubmit(timesheet, staffID))
+	timesheet.Status = values.TimesheetSubmitted
+
+	err = checkSubmit(timesheet, staffID)
+	require.NotNil(t, err, "a submitted timesheet is not submitted twice")
+	assert.Equal(t, http.StatusConflict, err.HTTPCode)
+
+	err = checkReview(timesheet, staffID)
+	require.NotNil(t, err, "staff cannot approve their own timesheet")
+	assert.Equal(t, http.StatusForbidden, err.HTTPCode)
+
+	// Rejected timesheets go back to the staff member to submit again
+	require.Nil(t, checkReview(timesheet, adminID))
+	timesheet.Status = values.TimesheetRejected
+	require.Nil(t, checkSubmit(timesheet, staffID))
+	timesheet.Status = values.TimesheetSubmitted
+
+	require.Nil(t, checkReview(timesheet, adminID))
+	timesheet.Status = values.TimesheetApproved
+
+	err = checkReview(timesheet, adminID)
+	require.NotNil(t, err, "an approved timesheet is not reviewed again")
+	assert.Equal(t, http.StatusConflict, err.HTTPCode)
+
+	require.Nil(t, checkClose(period, 0))
+	period.Status = values.PeriodClosed
+	err = checkClose(period, 0)
+	require.NotNil(t, err, "a closed period is not closed again")
+	assert.Equal(t, http.StatusConflict, err.HTTPCode)
+}
@@ -0,0 +1,229 @@
+package values
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Earning codes used in the payroll provider export.
+const (
+	EarningRegular    = "REG"
+	EarningSession    = "SESSION"
+	EarningAdjustment = "ADJ"
+)
+
+// DateOf returns the calendar date of t in loc, as midnight UTC like dates read from
+// the database.
+func DateOf(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func sameDate(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// AppliesOn reports whether the rate is in effect on the calendar date of day.
+func (r PayRate) AppliesOn(day time.Time) bool {
+	day = sameDate(day)
+	if day.Before(sameDate(r.EffectiveFrom)) {
+		return false
+	}
+	return r.EffectiveTo == nil || !day.After(sameDate(*r.EffectiveTo))
+}
+
+// Bounds returns the [from, to) instants the period covers in loc.
+func (p PayPeriod) Bounds(loc *time.Location) (time.Time, time.Time) {
+	from := time.Date(p.StartsOn.Year(), p.StartsOn.Month(), p.StartsOn.Day(), 0, 0, 0, 0, loc)
+	to := time.Date(p.EndsOn.Year(), p.EndsOn.Month(), p.EndsOn.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, 1)
+	return from, to
+}
+
+// Next returns the first and last day of the period of the same length that follows.
+func (p PayPeriod) Next() (time.Time, time.Time) {
+	days := int(sameDate(p.EndsOn).Sub(sameDate(p.StartsOn)).Hours()/24) + 1
+	startsOn := sameDate(p.EndsOn).AddDate(0, 0, 1)
+	return startsOn, startsOn.AddDate(0, 0, days-1)
+}
+
+// ResolveRate returns the staff member's rate for a session of the program on day: their
+// rate for the program if they have one, otherwise their default rate. It returns nil
+// when neither is in effect.
+func ResolveRate(rates []PayRate, programID *uuid.UUID, day time.Time) *PayRate {
+	var fallback *PayRate
+	for i := range rates {
+		rate := &rates[i]
+		if !rate.AppliesOn(day) {
+			continue
+		}
+		if rate.ProgramID == nil {
+			fallback = rate
+			continue
+		}
+		if programID != nil && *rate.ProgramID == *programID {
+			return rate
+		}
+	}
+	return fallback
+}
+
+// WorkedMinutes is the time between checking in and out of the session. The scheduled
+// start or end stands in for a missing check-in or check-out.
+func (a Assignment) WorkedMinutes() int32 {
+	start, end := a.ScheduledStart, a.ScheduledEnd
+	if a.CheckedInAt != nil {
+		start = *a.CheckedInAt
+	}
+	if a.CheckedOutAt != nil {
+		end = *a.CheckedOutAt
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return int32(end.Sub(start).Round(time.Minute) / time.Minute)
+}
+
+// Pay is what a session pays at a rate: the minutes worked at an hourly rate, rounded to
+// the cent, or the rate itself per session.
+func Pay(rateType string, rateCents, minutes int32) int32 {
+	if rateType == RatePerSession {
+		return rateCents
+	}
+	return int32(math.Round(float64(rateCents) * float64(minutes) / 60))
+}
+
+// PriceAssignment turns an assignment into a timesheet entry, paid at the rate in effect
+// on the day the session was scheduled in loc.
+func PriceAssignment(a Assignment, rates []PayRate, loc *time.Location) TimesheetEntry {
+	entry := TimesheetEntry{
+		SourceType:     a.SourceType,
+		SourceID:       a.SourceID,
+		ProgramID:      a.ProgramID,
+		Description:    a.Description,
+		ScheduledStart: a.ScheduledStart,
+		ScheduledEnd:   a.ScheduledEnd,
+		CheckedInAt:    a.CheckedInAt,
+		CheckedOutAt:   a.CheckedOutAt,
+		WorkedMinutes:  a.WorkedMinutes(),
+	}
+
+	rate := ResolveRate(rates, a.ProgramID, DateOf(a.ScheduledStart, loc))
+	if rate == nil {
+		return entry
+	}
+	rateType, rateCents := rate.RateType, rate.AmountCents
+	entry.RateID = &rate.ID
+	entry.RateType = &rateType
+	entry.RateCents = &rateCents
+	entry.AmountCents = Pay(rate.RateType, rate.AmountCents, entry.WorkedMinutes)
+	return entry
+}
+
+// EarningLine is one line of the payroll provider export.
+type EarningLine struct {
+	EmployeeID  string
+	FirstName   string
+	LastName    string
+	EarningCode string
+	Units       string // Hours for REG, sessions for SESSION, empty for ADJ
+	RateCents   *int32
+	AmountCents int32
+}
+
+// EarningLines sums a period's timesheets into earnings per staff member: one REG line
+// per hourly rate, one SESSION line per session rate and one ADJ line for the
+// adjustments. Sessions no rate applied to are left out.
+func EarningLines(export PeriodExport) []EarningLine {
+	type key struct {
+		timesheetID uuid.UUID
+		code        string
+		rateCents   int32
+	}
+	type total struct {
+		units  int32
+		amount int32
+	}
+	totals := make(map[key]*total)
+	var keys []key
+	add := func(k key, units, amount int32) {
+		t, ok := totals[k]
+		if !ok {
+			t = &total{}
+			totals[k] = t
+			keys = append(keys, k)
+		}
+		t.units += units
+		t.amount += amount
+	}
+
+	for _, entry := range export.Entries {
+		if entry.RateType == nil || entry.RateCents == nil {
+			continue
+		}
+		if *entry.RateType == RatePerSession {
+			add(key{entry.TimesheetID, EarningSession, *entry.RateCents}, 1, entry.AmountCents)
+		} else {
+			add(key{entry.TimesheetID, EarningRegular, *entry.RateCents}, entry.WorkedMinutes, entry.AmountCents)
+		}
+	}
+	for _, adjustment := range export.Adjustments {
+		add(key{adjustment.TimesheetID, EarningAdjustment, 0}, 0, adjustment.AmountCents)
+	}
+
+	codeOrder := map[string]int{EarningRegular: 0, EarningSession: 1, EarningAdjustment: 2}
+	sort.SliceStable(keys, func(i, j int) bool {
+		if keys[i].code != keys[j].code {
+			return codeOrder[keys[i].code] < codeOrder[keys[j].code]
+		}
+		return keys[i].rateCents < keys[j].rateCents
+	})
+
+	var lines []EarningLine
+	for _, timesheet := range export.Timesheets {
+		for _, k := range keys {
+			if k.timesheetID != timesheet.ID {
+				continue
+			}
+			t := totals[k]
+			if t.amount == 0 && k.code == EarningAdjustment {
+				continue
+			}
+			line := EarningLine{
+				FirstName:   timesheet.FirstName,
+				LastName:    timesheet.LastName,
+				EarningCode: k.code,
+				AmountCents: t.amount,
+			}
+			if timesheet.ExternalEmployeeID != nil {
+				line.EmployeeID = *timesheet.ExternalEmployeeID
+			}
+			switch k.code {
+			case EarningRegular:
+				line.Units = fmt.Sprintf("%.2f", float64(t.units)/60)
+			case EarningSession:
+				line.Units = fmt.Sprintf("%d", t.units)
+			}
+			if k.code != EarningAdjustment {
+				rateCents := k.rateCents
+				line.RateCents = &rateCents
+			}
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// FormatAmount formats cents as a plain decimal amount, e.g. -12.50.
+func FormatAmount(cents int32) string {
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
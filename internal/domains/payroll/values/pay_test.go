@@ -0,0 +1,132 @@
+package values
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestResolveRate(t *testing.T) {
+	program := uuid.New()
+	otherProgram := uuid.New()
+	march31 := date(2026, 3, 31)
+	rates := []PayRate{
+		{ID: uuid.New(), RateType: RateHourly, AmountCents: 2000, EffectiveFrom: date(2026, 1, 1), EffectiveTo: &march31},
+		{ID: uuid.New(), RateType: RateHourly, AmountCents: 2200, EffectiveFrom: date(2026, 4, 1)},
+		{ID: uuid.New(), RateType: RatePerSession, ProgramID: &program, AmountCents: 5000, EffectiveFrom: date(2026, 2, 1)},
+	}
+
+	t.Run("Prefers the program's rate", func(t *testing.T) {
+		rate := ResolveRate(rates, &program, date(2026, 2, 10))
+		require.NotNil(t, rate)
+		assert.Equal(t, int32(5000), rate.AmountCents)
+	})
+
+	t.Run("Falls back to the default rate for other programs", func(t *testing.T) {
+		rate := ResolveRate(rates, &otherProgram, date(2026, 2, 10))
+		require.NotNil(t, rate)
+		assert.Equal(t, int32(2000), rate.AmountCents)
+	})
+
+	t.Run("Uses the rate in effect on the day, ends included", func(t *testing.T) {
+		assert.Equal(t, int32(2000), ResolveRate(rates, nil, march31).AmountCents)
+		assert.Equal(t, int32(2200), ResolveRate(rates, nil, date(2026, 4, 1)).AmountCents)
+	})
+
+	t.Run("Returns nil before any rate applies", func(t *testing.T) {
+		assert.Nil(t, ResolveRate(rates, &program, date(2025, 12, 31)))
+	})
+}
+
+func TestPriceAssignment(t *testing.T) {
+	loc, err := time.LoadLocation("America/Edmonton")
+	require.NoError(t, err)
+	start := time.Date(2026, 4, 1, 18, 0, 0, 0, loc)
+	rates := []PayRate{{ID: uuid.New(), RateType: RateHourly, AmountCents: 2500, EffectiveFrom: date(2026, 4, 1)}}
+
+	t.Run("Pays the scheduled time without check-ins", func(t *testing.T) {
+		entry := PriceAssignment(Assignment{ScheduledStart: start, ScheduledEnd: start.Add(90 * time.Minute)}, rates, loc)
+		assert.Equal(t, int32(90), entry.WorkedMinutes)
+		assert.Equal(t, int32(3750), entry.AmountCents)
+		require.NotNil(t, entry.RateID)
+	})
+
+	t.Run("Pays from check-in to check-out", func(t *testing.T) {
+		in, out := start.Add(10*time.Minute), start.Add(70*time.Minute)
+		entry := PriceAssignment(Assignment{ScheduledStart: start, ScheduledEnd: start.Add(time.Hour), CheckedInAt: &in, CheckedOutAt: &out}, rates, loc)
+		assert.Equal(t, int32(60), entry.WorkedMinutes)
+		assert.Equal(t, int32(2500), entry.AmountCents)
+	})
+
+	t.Run("Uses the local date of the session", func(t *testing.T) {
+		// 23:00 on March 31st in Edmonton is already April 1st in UTC
+		late := time.Date(2026, 3, 31, 23, 0, 0, 0, loc)
+		entry := PriceAssignment(Assignment{ScheduledStart: late, ScheduledEnd: late.Add(time.Hour)}, rates, loc)
+		assert.Nil(t, entry.RateID)
+		assert.Equal(t, int32(0), entry.AmountCents)
+	})
+
+	t.Run("Pays a flat amount per session", func(t *testing.T) {
+		assert.Equal(t, int32(4000), Pay(RatePerSession, 4000, 15))
+		assert.Equal(t, int32(1667), Pay(RateHourly, 2000, 50))
+	})
+}
+
+func TestPayPeriod(t *testing.T) {
+	period := PayPeriod{StartsOn: date(2026, 3, 2), EndsOn: date(2026, 3, 15)}
+
+	startsOn, endsOn := period.Next()
+	assert.Equal(t, date(2026, 3, 16), startsOn)
+	assert.Equal(t, date(2026, 3, 29), endsOn)
+
+	loc, err := time.LoadLocation("America/Edmonton")
+	require.NoError(t, err)
+	from, to := period.Bounds(loc)
+	assert.Equal(t, time.Date(2026, 3, 2, 0, 0, 0, 0, loc), from)
+	assert.Equal(t, time.Date(2026, 3, 16, 0, 0, 0, 0, loc), to)
+	// The period spans the switch to daylight saving time
+	assert.Equal(t, 14*24*time.Hour-time.Hour, to.Sub(from))
+}
+
+func TestEarningLines(t *testing.T) {
+	employeeID := "E100"
+	timesheet := Timesheet{ID: uuid.New(), FirstName: "Sam", LastName: "Lee", ExternalEmployeeID: &employeeID}
+	hourly, perSession := RateHourly, RatePerSession
+	rate2000, rate5000 := int32(2000), int32(5000)
+
+	lines := EarningLines(PeriodExport{
+		Timesheets: []Timesheet{timesheet},
+		Entries: []TimesheetEntry{
+			{TimesheetID: timesheet.ID, RateType: &perSession, RateCents: &rate5000, WorkedMinutes: 60, AmountCents: 5000},
+			{TimesheetID: timesheet.ID, RateType: &hourly, RateCents: &rate2000, WorkedMinutes: 90, AmountCents: 3000},
+			{TimesheetID: timesheet.ID, RateType: &hourly, RateCents: &rate2000, WorkedMinutes: 60, AmountCents: 2000},
+			{TimesheetID: timesheet.ID, WorkedMinutes: 60},
+		},
+		Adjustments: []Adjustment{
+			{TimesheetID: timesheet.ID, AmountCents: 1500},
+			{TimesheetID: timesheet.ID, AmountCents: -500},
+		},
+	})
+
+	require.Len(t, lines, 3)
+	assert.Equal(t, EarningLine{EmployeeID: "E100", FirstName: "Sam", LastName: "Lee", EarningCode: EarningRegular, Units: "2.50", RateCents: &rate2000, AmountCents: 5000}, lines[0])
+	assert.Equal(t, EarningSession, lines[1].EarningCode)
+	assert.Equal(t, "1", lines[1].Units)
+	assert.Equal(t, int32(5000), lines[1].AmountCents)
+	assert.Equal(t, EarningAdjustment, lines[2].EarningCode)
+	assert.Nil(t, lines[2].RateCents)
+	assert.Equal(t, int32(1000), lines[2].AmountCents)
+}
+
+func TestFormatAmount(t *testing.T) {
+	assert.Equal(t, "12.50", FormatAmount(1250))
+	assert.Equal(t, "-0.05", FormatAmount(-5))
+	assert.Equal(t, "0.00", FormatAmount(0))
+}
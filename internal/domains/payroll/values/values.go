@@ -0,0 +1,191 @@
+package values
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Rate types. Hourly rates pay for the minutes worked, per-session rates a flat amount
+// per session.
+const (
+	RateHourly     = "hourly"
+	RatePerSession = "per_session"
+)
+
+// Pay period statuses. Only open periods have their timesheets regenerated, and only
+// closed periods are exported.
+const (
+	PeriodOpen   = "open"
+	PeriodClosed = "closed"
+)
+
+// Timesheet statuses. Staff submit draft (or rejected) timesheets and an admin approves
+// or rejects them.
+const (
+	TimesheetDraft     = "draft"
+	TimesheetSubmitted = "submitted"
+	TimesheetApproved  = "approved"
+	TimesheetRejected  = "rejected"
+)
+
+// Where a timesheet entry comes from.
+const (
+	SourceEvent    = "event"
+	SourcePractice = "practice"
+	SourceGame     = "game"
+)
+
+type PayRate struct {
+	ID            uuid.UUID
+	StaffID       uuid.UUID
+	RateType      string
+	ProgramID     *uuid.UUID // nil for the staff member's default rate
+	AmountCents   int32
+	EffectiveFrom time.Time
+	EffectiveTo   *time.Time // Last day the rate applies, nil while current
+	CreatedBy     *uuid.UUID
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// PayRateValues creates or updates a pay rate. StaffID and ProgramID are ignored on update.
+type PayRateValues struct {
+	StaffID       uuid.UUID
+	RateType      string
+	ProgramID     *uuid.UUID
+	AmountCents   int32
+	EffectiveFrom time.Time
+	EffectiveTo   *time.Time
+}
+
+type PayPeriod struct {
+	ID        uuid.UUID
+	StartsOn  time.Time
+	EndsOn    time.Time
+	Status    string
+	ClosedAt  *time.Time
+	ClosedBy  *uuid.UUID
+	CreatedBy *uuid.UUID
+	CreatedAt time.Time
+}
+
+// Assignment is a session a staff member was assigned to, with when they checked in
+// and out of it if they did.
+type Assignment struct {
+	StaffID        uuid.UUID
+	SourceType     string
+	SourceID       uuid.UUID
+	ProgramID      *uuid.UUID
+	Description    string
+	ScheduledStart time.Time
+	ScheduledEnd   time.Time
+	CheckedInAt    *time.Time
+	CheckedOutAt   *time.Time
+}
+
+type CheckIn struct {
+	ID           uuid.UUID
+	StaffID      uuid.UUID
+	SourceType   string
+	SourceID     uuid.UUID
+	CheckedInAt  time.Time
+	CheckedOutAt *time.Time
+}
+
+type TimesheetEntry struct {
+	ID             uuid.UUID
+	TimesheetID    uuid.UUID
+	SourceType     string
+	SourceID       uuid.UUID
+	ProgramID      *uuid.UUID
+	Description    string
+	ScheduledStart time.Time
+	ScheduledEnd   time.Time
+	CheckedInAt    *time.Time
+	CheckedOutAt   *time.Time
+	WorkedMinutes  int32
+	// Rate fields are nil for sessions no rate applied to, which pay nothing.
+	RateID      *uuid.UUID
+	RateType    *string
+	RateCents   *int32
+	AmountCents int32
+}
+
+type Adjustment struct {
+	ID          uuid.UUID
+	TimesheetID uuid.UUID
+	AmountCents int32  // Negative for deductions
+	Reason      string `audit:"personal"`
+	CreatedBy   *uuid.UUID
+	CreatedAt   time.Time
+}
+
+// Timesheet is a staff member's timesheet for a pay period with its totals.
+type Timesheet struct {
+	ID                 uuid.UUID
+	PayPeriodID        uuid.UUID
+	StaffID            uuid.UUID
+	Status             string
+	SubmittedAt        *time.Time
+	ReviewedAt         *time.Time
+	ReviewedBy         *uuid.UUID
+	RejectionReason    *string
+	GeneratedAt        time.Time
+	StartsOn           time.Time
+	EndsOn             time.Time
+	FirstName          string
+	LastName           string
+	ExternalEmployeeID *string
+	WorkedMinutes      int32
+	EntriesCents       int32
+	AdjustmentsCents   int32
+}
+
+func (t Timesheet) TotalCents() int32 {
+	return t.EntriesCents + t.AdjustmentsCents
+}
+
+// TimesheetDetail is a timesheet with its entries and adjustments.
+type TimesheetDetail struct {
+	Timesheet
+	Entries     []TimesheetEntry
+	Adjustments []Adjustment
+}
+
+type TimesheetFilter struct {
+	PayPeriodID uuid.UUID
+	StaffID     uuid.UUID
+	Status      string
+	Limit       int32
+	Offset      int32
+}
+
+// ReviewValues approves or rejects a submitted timesheet. Rejections need a reason.
+type ReviewValues struct {
+	TimesheetID uuid.UUID
+	ReviewerID  uuid.UUID
+	Approve     bool
+	Reason      *string
+}
+
+type AdjustmentValues struct {
+	TimesheetID uuid.UUID
+	AmountCents int32
+	Reason      string
+}
+
+// CheckInValues checks a staff member in or out of a session they were assigned to.
+type CheckInValues struct {
+	StaffID    uuid.UUID
+	SourceType string
+	SourceID   uuid.UUID
+}
+
+// PeriodExport is everything needed to export a closed pay period.
+type PeriodExport struct {
+	Period      PayPeriod
+	Timesheets  []Timesheet
+	Entries     []TimesheetEntry
+	Adjustments []Adjustment
+}
@@ -42,3 +42,49 @@ func (dto RequestDto) ToCreateValue(customerID uuid.UUID) (values.CreateSessionV
 		EndTime:    end,
 	}, nil
 }
+
+// BookRequestDto asks to book a session on a system and pay for it by card or credits.
+type BookRequestDto struct {
+	SystemID      uuid.UUID `json:"system_id" validate:"required"`
+	StartTime     string    `json:"start_time" validate:"required" example:"2026-03-06T18:00:00-05:00"`
+	EndTime       string    `json:"end_time" validate:"required" example:"2026-03-06T19:00:00-05:00"`
+	PaymentMethod string    `json:"payment_method" validate:"required,oneof=stripe credits" example:"stripe"`
+}
+
+// ToBookValue converts the BookRequestDto to a BookSessionValue for the given customer.
+func (dto BookRequestDto) ToBookValue(customerID uuid.UUID) (values.BookSessionValue, *errLib.CommonError) {
+	start, end, err := RequestDto{SystemID: dto.SystemID, StartTime: dto.StartTime, EndTime: dto.EndTime}.toTimes()
+	if err != nil {
+		return values.BookSessionValue{}, err
+	}
+	if err = validators.ValidateDto(&dto); err != nil {
+		return values.BookSessionValue{}, err
+	}
+	return values.BookSessionValue{
+		SystemID:      dto.SystemID,
+		CustomerID:    customerID,
+		StartTime:     start,
+		EndTime:       end,
+		PaymentMethod: values.PaymentMethod(dto.PaymentMethod),
+	}, nil
+}
+
+// ExtendRequestDto adds time to the end of a session.
+type ExtendRequestDto struct {
+	Minutes int `json:"minutes" validate:"required,gt=0" example:"30"`
+}
+
+// WalkInRequestDto starts a session now for a customer at the front desk.
+type WalkInRequestDto struct {
+	SystemID   uuid.UUID `json:"system_id" validate:"required"`
+	CustomerID uuid.UUID `json:"customer_id" validate:"required"`
+	Minutes    int       `json:"minutes" validate:"required,gt=0" example:"60"`
+}
+
+// ToWalkInValue converts the WalkInRequestDto to a WalkInValue.
+func (dto WalkInRequestDto) ToWalkInValue() (values.WalkInValue, *errLib.CommonError) {
+	if err := validators.ValidateDto(&dto); err != nil {
+		return values.WalkInValue{}, err
+	}
+	return values.WalkInValue{SystemID: dto.SystemID, CustomerID: dto.CustomerID, Minutes: dto.Minutes}, nil
+}
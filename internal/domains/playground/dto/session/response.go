@@ -4,31 +4,66 @@ import (
 	values "api/internal/domains/playground/values"
 	"time"
 )
+
 // ResponseDto represents the data transfer object for a session response.
 type ResponseDto struct {
-	ID         string `json:"id"`
-	SystemID   string `json:"system_id"`
-	SystemName string `json:"system_name"`
-	CustomerID string `json:"customer_id"`
-	CustomerFirstName string `json:"customer_first_name"`
-	CustomerLastName  string `json:"customer_last_name"`
-	StartTime  string `json:"start_time"`
-	EndTime    string `json:"end_time"`
-	CreatedAt  string `json:"created_at"`
-	UpdatedAt  string `json:"updated_at"`
+	ID                string  `json:"id"`
+	SystemID          string  `json:"system_id"`
+	SystemName        string  `json:"system_name"`
+	CustomerID        string  `json:"customer_id"`
+	CustomerFirstName string  `json:"customer_first_name"`
+	CustomerLastName  string  `json:"customer_last_name"`
+	StartTime         string  `json:"start_time"`
+	EndTime           string  `json:"end_time"`
+	Status            string  `json:"status"`
+	PaymentMethod     string  `json:"payment_method"`
+	AmountCents       int32   `json:"amount_cents"`
+	CreditsUsed       int32   `json:"credits_used"`
+	Currency          string  `json:"currency"`
+	HoldExpiresAt     *string `json:"hold_expires_at,omitempty"`
+	ActualStartAt     *string `json:"actual_start_at,omitempty"`
+	ActualEndAt       *string `json:"actual_end_at,omitempty"`
+	ActualMinutes     *int    `json:"actual_minutes,omitempty"`
+	CreatedAt         string  `json:"created_at"`
+	UpdatedAt         string  `json:"updated_at"`
+}
+
+func formatTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.Format(time.RFC3339)
+	return &s
 }
+
 // NewResponse creates a new ResponseDto from a Session value.
 func NewResponse(session values.Session) ResponseDto {
-	return ResponseDto{
-		ID:         session.ID.String(),
-		SystemID:   session.SystemID.String(),
-		SystemName: session.SystemName,
-		CustomerID: session.CustomerID.String(),
+	res := ResponseDto{
+		ID:                session.ID.String(),
+		SystemID:          session.SystemID.String(),
+		SystemName:        session.SystemName,
+		CustomerID:        session.CustomerID.String(),
 		CustomerFirstName: session.CustomerFirstName,
 		CustomerLastName:  session.CustomerLastName,
-		StartTime:  session.StartTime.Format(time.RFC3339),
-		EndTime:    session.EndTime.Format(time.RFC3339),
-		CreatedAt:  session.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:  session.UpdatedAt.Format(time.RFC3339),
+		StartTime:         session.StartTime.Format(time.RFC3339),
+		EndTime:           session.EndTime.Format(time.RFC3339),
+		Status:            session.Status,
+		PaymentMethod:     string(session.PaymentMethod),
+		AmountCents:       session.AmountCents,
+		CreditsUsed:       session.CreditsUsed,
+		Currency:          session.Currency,
+		ActualStartAt:     formatTime(session.ActualStartAt),
+		ActualEndAt:       formatTime(session.ActualEndAt),
+		CreatedAt:         session.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:         session.UpdatedAt.Format(time.RFC3339),
+	}
+	// Only an unpaid hold has an expiry the customer needs to know about
+	if session.Status == values.StatusHeld {
+		res.HoldExpiresAt = formatTime(session.HoldExpiresAt)
+	}
+	if session.ActualStartAt != nil && session.ActualEndAt != nil {
+		minutes := int(session.ActualEndAt.Sub(*session.ActualStartAt).Minutes())
+		res.ActualMinutes = &minutes
 	}
+	return res
 }
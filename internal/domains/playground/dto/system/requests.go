@@ -4,17 +4,72 @@ import (
 	values "api/internal/domains/playground/values"
 	errLib "api/internal/libs/errors"
 	"api/internal/libs/validators"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
 )
 
+// RequestDto creates or updates a playground system and its booking rules. Omitted
+// rules take their defaults: 30-minute slots, up to 4 per session, open 09:00-21:00,
+// 2 upcoming sessions per customer, free of charge.
 type RequestDto struct {
-	Name string `json:"name" validate:"required,notwhitespace"`
+	Name              string     `json:"name" validate:"required,notwhitespace"`
+	LocationID        *uuid.UUID `json:"location_id,omitempty"`
+	SlotMinutes       *int32     `json:"slot_minutes,omitempty" validate:"omitempty,min=5,max=240" example:"30"`
+	MaxSlots          *int32     `json:"max_slots,omitempty" validate:"omitempty,min=1" example:"4"`
+	OpensAt           string     `json:"opens_at,omitempty" example:"09:00"`  // HH:MM facility time
+	ClosesAt          string     `json:"closes_at,omitempty" example:"21:00"` // HH:MM facility time
+	MaxActiveBookings *int32     `json:"max_active_bookings,omitempty" validate:"omitempty,min=1" example:"2"`
+	PricePerSlotCents *int32     `json:"price_per_slot_cents,omitempty" validate:"omitempty,min=0" example:"1500"`
+	CreditsPerSlot    *int32     `json:"credits_per_slot,omitempty" validate:"omitempty,gt=0" example:"1"`
+}
+
+func parseClock(value, fallback, field string) (time.Time, *errLib.CommonError) {
+	if value == "" {
+		value = fallback
+	}
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return time.Time{}, errLib.New("invalid "+field+" format, expected HH:MM", http.StatusBadRequest)
+	}
+	return t, nil
+}
+
+func orDefault(v *int32, fallback int32) int32 {
+	if v == nil {
+		return fallback
+	}
+	return *v
 }
 
 func (dto RequestDto) toValue() (values.CreateSystemValue, *errLib.CommonError) {
 	if err := validators.ValidateDto(&dto); err != nil {
 		return values.CreateSystemValue{}, err
 	}
-	return values.CreateSystemValue{Name: dto.Name}, nil
+
+	opensAt, err := parseClock(dto.OpensAt, "09:00", "opens_at")
+	if err != nil {
+		return values.CreateSystemValue{}, err
+	}
+	closesAt, err := parseClock(dto.ClosesAt, "21:00", "closes_at")
+	if err != nil {
+		return values.CreateSystemValue{}, err
+	}
+
+	rules := values.Rules{
+		SlotMinutes:       orDefault(dto.SlotMinutes, 30),
+		MaxSlots:          orDefault(dto.MaxSlots, 4),
+		OpensAt:           opensAt,
+		ClosesAt:          closesAt,
+		MaxActiveBookings: orDefault(dto.MaxActiveBookings, 2),
+		PricePerSlotCents: orDefault(dto.PricePerSlotCents, 0),
+		CreditsPerSlot:    dto.CreditsPerSlot,
+	}
+	if dto.LocationID != nil {
+		rules.LocationID = *dto.LocationID
+	}
+	return values.CreateSystemValue{Name: dto.Name, Rules: rules}, nil
 }
 
 func (dto RequestDto) ToCreateValue() (values.CreateSystemValue, *errLib.CommonError) {
@@ -26,8 +81,9 @@ func (dto RequestDto) ToUpdateValue(idStr string) (values.UpdateSystemValue, *er
 	if err != nil {
 		return values.UpdateSystemValue{}, err
 	}
-	if err = validators.ValidateDto(&dto); err != nil {
+	v, err := dto.toValue()
+	if err != nil {
 		return values.UpdateSystemValue{}, err
 	}
-	return values.UpdateSystemValue{ID: id, Name: dto.Name}, nil
+	return values.UpdateSystemValue{ID: id, Name: v.Name, Rules: v.Rules}, nil
 }
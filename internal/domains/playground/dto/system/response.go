@@ -1,23 +1,43 @@
 package playground
 
-
 import (
 	values "api/internal/domains/playground/values"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type ResponseDto struct {
-	ID        string `json:"id"`
-	Name      string `json:"name"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+	ID                string     `json:"id"`
+	Name              string     `json:"name"`
+	LocationID        *uuid.UUID `json:"location_id,omitempty"`
+	SlotMinutes       int32      `json:"slot_minutes"`
+	MaxSlots          int32      `json:"max_slots"`
+	OpensAt           string     `json:"opens_at"`
+	ClosesAt          string     `json:"closes_at"`
+	MaxActiveBookings int32      `json:"max_active_bookings"`
+	PricePerSlotCents int32      `json:"price_per_slot_cents"`
+	CreditsPerSlot    *int32     `json:"credits_per_slot,omitempty"`
+	CreatedAt         string     `json:"created_at"`
+	UpdatedAt         string     `json:"updated_at"`
 }
 
 func NewResponse(v values.System) ResponseDto {
-	return ResponseDto{
-		ID:        v.ID.String(),
-		Name:      v.Name,
-		CreatedAt: v.CreatedAt.Format(time.RFC3339),
-		UpdatedAt: v.UpdatedAt.Format(time.RFC3339),
+	res := ResponseDto{
+		ID:                v.ID.String(),
+		Name:              v.Name,
+		SlotMinutes:       v.SlotMinutes,
+		MaxSlots:          v.MaxSlots,
+		OpensAt:           v.OpensAt.Format("15:04"),
+		ClosesAt:          v.ClosesAt.Format("15:04"),
+		MaxActiveBookings: v.MaxActiveBookings,
+		PricePerSlotCents: v.PricePerSlotCents,
+		CreditsPerSlot:    v.CreditsPerSlot,
+		CreatedAt:         v.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:         v.UpdatedAt.Format(time.RFC3339),
 	}
-}
\ No newline at end of file
+	if v.LocationID != uuid.Nil {
+		res.LocationID = &v.LocationID
+	}
+	return res
+}
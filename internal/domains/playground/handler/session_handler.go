@@ -23,13 +23,16 @@ func NewHandler(container *di.Container) *Handler {
 
 // CreateSession handles the creation of a new session.
 // @Summary Create a new session
+// @Description Books a free system for the logged-in customer. Paid systems are booked through /checkout/playground_sessions.
+// @Description The session must follow the system's slot length, maximum length, opening hours and per-customer booking limit.
 // @Tags playground
 // @Accept json
 // @Produce json
 // @Param session body dto.RequestDto true "Session request body"
 // @Success 201 {object} dto.ResponseDto "Session created successfully"
-// @Failure 400 {object} map[string]interface{} "Invalid input"
+// @Failure 400 {object} map[string]interface{} "Invalid input or breaks the system's rules"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 409 {object} map[string]interface{} "Overlaps another session or too many upcoming sessions"
 // @Failure 500 {object} map[string]interface{} "Server error"
 // @Security Bearer
 // @Router /playground [post]
@@ -135,3 +138,194 @@ func (h *Handler) DeleteSession(w http.ResponseWriter, r *http.Request) {
 	}
 	responseHandlers.RespondWithSuccess(w, nil, http.StatusNoContent)
 }
+
+// GetMySessions retrieves the logged-in customer's upcoming sessions, including unpaid holds.
+// @Summary Get my upcoming sessions
+// @Tags playground
+// @Produce json
+// @Success 200 {array} dto.ResponseDto "Upcoming sessions"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Server error"
+// @Security Bearer
+// @Router /playground/me [get]
+
+func (h *Handler) GetMySessions(w http.ResponseWriter, r *http.Request) {
+	customerID, err := contextUtils.GetUserID(r.Context())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	sessions, err := h.service.GetMyUpcomingSessions(r.Context(), customerID)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	resp := make([]dto.ResponseDto, len(sessions))
+	for i, s := range sessions {
+		resp[i] = dto.NewResponse(s)
+	}
+	responseHandlers.RespondWithSuccess(w, resp, http.StatusOK)
+}
+
+// CancelSession cancels a session.
+// @Summary Cancel a session
+// @Description Customers can cancel their own unpaid hold, or a free or credit-paid session before it starts; credits are refunded.
+// @Description Staff can cancel any session that has not started.
+// @Tags playground
+// @Param id path string true "Session ID" example("f47ac10b-58cc-4372-a567-0e02b2c3d479")
+// @Success 204 "Canceled successfully"
+// @Failure 400 {object} map[string]interface{} "Session has started"
+// @Failure 403 {object} map[string]interface{} "Paid sessions are canceled by staff"
+// @Failure 404 {object} map[string]interface{} "Session not found"
+// @Failure 409 {object} map[string]interface{} "Session is not held or confirmed"
+// @Failure 500 {object} map[string]interface{} "Server error"
+// @Security Bearer
+// @Router /playground/{id}/cancel [post]
+
+func (h *Handler) CancelSession(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	if err = h.service.CancelSession(r.Context(), id); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, nil, http.StatusNoContent)
+}
+
+// ExtendSession adds time to the end of a session.
+// @Summary Extend a session
+// @Description The system must be free and the longer session must follow its rules. Customers can extend free and credit-paid sessions; the extra slots are charged in credits. Staff extensions are collected in person.
+// @Tags playground
+// @Accept json
+// @Produce json
+// @Param id path string true "Session ID" example("f47ac10b-58cc-4372-a567-0e02b2c3d479")
+// @Param extension body dto.ExtendRequestDto true "Minutes to add"
+// @Success 200 {object} dto.ResponseDto "Session extended"
+// @Failure 400 {object} map[string]interface{} "Invalid input or breaks the system's rules"
+// @Failure 404 {object} map[string]interface{} "Session not found"
+// @Failure 409 {object} map[string]interface{} "System is booked right after the session"
+// @Failure 500 {object} map[string]interface{} "Server error"
+// @Security Bearer
+// @Router /playground/{id}/extend [post]
+
+func (h *Handler) ExtendSession(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	var req dto.ExtendRequestDto
+	if err = validators.ParseJSON(r.Body, &req); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	if err = validators.ValidateDto(&req); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	session, err := h.service.ExtendSession(r.Context(), id, req.Minutes)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewResponse(session), http.StatusOK)
+}
+
+// StartSession checks a customer in for their booked session.
+// @Summary Start a session
+// @Description Records when the customer actually started.
+// @Tags playground
+// @Produce json
+// @Param id path string true "Session ID" example("f47ac10b-58cc-4372-a567-0e02b2c3d479")
+// @Success 200 {object} dto.ResponseDto "Session in progress"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 409 {object} map[string]interface{} "Session is not confirmed"
+// @Failure 500 {object} map[string]interface{} "Server error"
+// @Security Bearer
+// @Router /playground/{id}/start [post]
+
+func (h *Handler) StartSession(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	session, err := h.service.StartSession(r.Context(), id)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewResponse(session), http.StatusOK)
+}
+
+// StopSession ends a session in progress.
+// @Summary Stop a session
+// @Description Records when the customer actually stopped; the response includes the minutes used.
+// @Tags playground
+// @Produce json
+// @Param id path string true "Session ID" example("f47ac10b-58cc-4372-a567-0e02b2c3d479")
+// @Success 200 {object} dto.ResponseDto "Session completed"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 409 {object} map[string]interface{} "Session is not in progress"
+// @Failure 500 {object} map[string]interface{} "Server error"
+// @Security Bearer
+// @Router /playground/{id}/stop [post]
+
+func (h *Handler) StopSession(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	session, err := h.service.StopSession(r.Context(), id)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewResponse(session), http.StatusOK)
+}
+
+// WalkIn starts a session now for a customer at the front desk.
+// @Summary Start a walk-in session
+// @Description Follows the system's session length and opening hours, and is paid in person.
+// @Tags playground
+// @Accept json
+// @Produce json
+// @Param walk_in body dto.WalkInRequestDto true "Walk-in details"
+// @Success 201 {object} dto.ResponseDto "Session in progress"
+// @Failure 400 {object} map[string]interface{} "Invalid input or breaks the system's rules"
+// @Failure 404 {object} map[string]interface{} "System or customer not found"
+// @Failure 409 {object} map[string]interface{} "System is in use"
+// @Failure 500 {object} map[string]interface{} "Server error"
+// @Security Bearer
+// @Router /playground/walk-in [post]
+
+func (h *Handler) WalkIn(w http.ResponseWriter, r *http.Request) {
+	var req dto.WalkInRequestDto
+	if err := validators.ParseJSON(r.Body, &req); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	value, err := req.ToWalkInValue()
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	session, err := h.service.WalkIn(r.Context(), value)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewResponse(session), http.StatusCreated)
+}
@@ -11,6 +11,7 @@ import (
 	"errors"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/lib/pq"
@@ -20,7 +21,6 @@ import (
 type Repository struct {
 	Queries *db.Queries
 	Tx      *sql.Tx
-	Db      *sql.DB
 }
 
 // GetTx returns the current transaction of the repository.
@@ -38,21 +38,49 @@ func (r *Repository) WithTx(tx *sql.Tx) *Repository {
 
 // NewRepository initializes a new Repository with the provided DI container.
 func NewRepository(container *di.Container) *Repository {
-	return &Repository{Queries: container.Queries.PlaygroundDb, Db: container.DB}
+	return &Repository{Queries: container.Queries.PlaygroundDb}
+}
+
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
+
+func timePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
 }
 
 // CreateSession creates a new session in the database.
 func (r *Repository) CreateSession(ctx context.Context, v values.CreateSessionValue) (values.Session, *errLib.CommonError) {
+	status, method := v.Status, v.PaymentMethod
+	if status == "" {
+		status = values.StatusConfirmed
+	}
+	if method == "" {
+		method = values.PaymentFree
+	}
+
 	params := db.CreateSessionParams{
-		SystemID:   v.SystemID,
-		CustomerID: v.CustomerID,
-		StartTime:  v.StartTime,
-		EndTime:    v.EndTime,
+		SystemID:      v.SystemID,
+		CustomerID:    v.CustomerID,
+		StartTime:     v.StartTime,
+		EndTime:       v.EndTime,
+		Status:        status,
+		PaymentMethod: string(method),
+		AmountCents:   v.AmountCents,
+		CreditsUsed:   v.CreditsUsed,
+		HoldExpiresAt: nullTime(v.HoldExpiresAt),
+		ActualStartAt: nullTime(v.ActualStartAt),
 	}
 	row, err := r.Queries.CreateSession(ctx, params)
 	if err != nil {
 		var pqErr *pq.Error
-		if errors.As(err, &pqErr) && pqErr.Code == databaseErrors.UniqueViolation {
+		if errors.As(err, &pqErr) && pqErr.Code == databaseErrors.ExclusionViolation {
 			return values.Session{}, errLib.New("A session at this schedule overlaps with an existing session", http.StatusConflict)
 		}
 		if errors.As(err, &pqErr) {
@@ -68,7 +96,7 @@ func (r *Repository) CreateSession(ctx context.Context, v values.CreateSessionVa
 		log.Println("Failed to create session:", err)
 		return values.Session{}, errLib.New("Internal server error", http.StatusInternalServerError)
 	}
-	return mapDbCreateSessionToValue(row), nil
+	return mapDbSessionToValue(db.GetSessionRow(row)), nil
 }
 
 // GetSessions retrieves all sessions.
@@ -80,7 +108,25 @@ func (r *Repository) GetSessions(ctx context.Context) ([]values.Session, *errLib
 	}
 	list := make([]values.Session, len(dbSessions))
 	for i, s := range dbSessions {
-		list[i] = mapDbSessionsRowToValue(s)
+		list[i] = mapDbSessionToValue(db.GetSessionRow(s))
+	}
+	return list, nil
+}
+
+// GetCustomerUpcomingSessions retrieves the customer's held, confirmed and in-progress
+// sessions that end after the given time.
+func (r *Repository) GetCustomerUpcomingSessions(ctx context.Context, customerID uuid.UUID, after time.Time) ([]values.Session, *errLib.CommonError) {
+	dbSessions, err := r.Queries.GetCustomerUpcomingSessions(ctx, db.GetCustomerUpcomingSessionsParams{
+		CustomerID: customerID,
+		EndTime:    after,
+	})
+	if err != nil {
+		log.Println("Failed to get customer sessions:", err)
+		return nil, errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+	list := make([]values.Session, len(dbSessions))
+	for i, s := range dbSessions {
+		list[i] = mapDbSessionToValue(db.GetSessionRow(s))
 	}
 	return list, nil
 }
@@ -111,115 +157,294 @@ func (r *Repository) DeleteSession(ctx context.Context, id uuid.UUID) *errLib.Co
 	return nil
 }
 
+// CountActiveCustomerSessions counts the customer's sessions on a system that have not
+// ended and still occupy it.
+func (r *Repository) CountActiveCustomerSessions(ctx context.Context, customerID, systemID uuid.UUID) (int64, *errLib.CommonError) {
+	count, err := r.Queries.CountActiveCustomerSessions(ctx, db.CountActiveCustomerSessionsParams{
+		CustomerID: customerID,
+		SystemID:   systemID,
+	})
+	if err != nil {
+		log.Println("Failed to count customer sessions:", err)
+		return 0, errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+	return count, nil
+}
+
+// LockSession reads a session's row for update.
+func (r *Repository) LockSession(ctx context.Context, id uuid.UUID) (db.PlaygroundSession, *errLib.CommonError) {
+	row, err := r.Queries.GetSessionForUpdate(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return row, errLib.New("Session not found", http.StatusNotFound)
+		}
+		log.Println("Failed to lock session:", err)
+		return row, errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+	return row, nil
+}
+
+// ExpireStaleHolds marks unpaid holds past their expiry as expired so they stop
+// blocking the system. systemID uuid.Nil expires holds on every system.
+func (r *Repository) ExpireStaleHolds(ctx context.Context, systemID uuid.UUID) (int64, *errLib.CommonError) {
+	affected, err := r.Queries.ExpireStaleHolds(ctx, uuid.NullUUID{UUID: systemID, Valid: systemID != uuid.Nil})
+	if err != nil {
+		log.Println("Failed to expire playground session holds:", err)
+		return 0, errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+	return affected, nil
+}
+
+// ConfirmSession confirms a held (or lapsed) session once it is paid for.
+func (r *Repository) ConfirmSession(ctx context.Context, id uuid.UUID) *errLib.CommonError {
+	affected, err := r.Queries.ConfirmSession(ctx, id)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == databaseErrors.ExclusionViolation {
+			return errLib.New("The system is no longer free for this session", http.StatusConflict)
+		}
+		log.Println("Failed to confirm session:", err)
+		return errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+	if affected == 0 {
+		return errLib.New("Session is not awaiting payment", http.StatusConflict)
+	}
+	return nil
+}
+
+// MarkSessionRefundDue records that a session was paid for but could not be confirmed,
+// so its payment has to be refunded.
+func (r *Repository) MarkSessionRefundDue(ctx context.Context, id uuid.UUID) *errLib.CommonError {
+	affected, err := r.Queries.MarkSessionRefundDue(ctx, id)
+	if err != nil {
+		log.Println("Failed to mark session as refund due:", err)
+		return errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+	if affected == 0 {
+		return errLib.New("Session is not awaiting payment", http.StatusConflict)
+	}
+	return nil
+}
+
+// MarkSessionRefunded records that the payment of a refund_due session was refunded.
+// It is a no-op for any other session.
+func (r *Repository) MarkSessionRefunded(ctx context.Context, id uuid.UUID) *errLib.CommonError {
+	if _, err := r.Queries.MarkSessionRefunded(ctx, id); err != nil {
+		log.Println("Failed to mark session as refunded:", err)
+		return errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// CancelSession cancels a held or confirmed session.
+func (r *Repository) CancelSession(ctx context.Context, id uuid.UUID) *errLib.CommonError {
+	affected, err := r.Queries.CancelSession(ctx, id)
+	if err != nil {
+		log.Println("Failed to cancel session:", err)
+		return errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+	if affected == 0 {
+		return errLib.New("Only held or confirmed sessions can be canceled", http.StatusConflict)
+	}
+	return nil
+}
+
+// StartSession marks a confirmed session as in progress from the given time.
+func (r *Repository) StartSession(ctx context.Context, id uuid.UUID, at time.Time) *errLib.CommonError {
+	affected, err := r.Queries.StartSession(ctx, db.StartSessionParams{ID: id, ActualStartAt: nullTime(&at)})
+	if err != nil {
+		log.Println("Failed to start session:", err)
+		return errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+	if affected == 0 {
+		return errLib.New("Only confirmed sessions can be started", http.StatusConflict)
+	}
+	return nil
+}
+
+// CompleteSession marks an in-progress session as completed at the given time.
+func (r *Repository) CompleteSession(ctx context.Context, id uuid.UUID, at time.Time) *errLib.CommonError {
+	affected, err := r.Queries.CompleteSession(ctx, db.CompleteSessionParams{ID: id, ActualEndAt: nullTime(&at)})
+	if err != nil {
+		log.Println("Failed to complete session:", err)
+		return errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+	if affected == 0 {
+		return errLib.New("Only sessions in progress can be stopped", http.StatusConflict)
+	}
+	return nil
+}
+
+// ExtendSession moves a confirmed or in-progress session's end time and adds what the
+// extra time cost.
+func (r *Repository) ExtendSession(ctx context.Context, params db.ExtendSessionParams) *errLib.CommonError {
+	affected, err := r.Queries.ExtendSession(ctx, params)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == databaseErrors.ExclusionViolation {
+			return errLib.New("The system is booked right after this session", http.StatusConflict)
+		}
+		log.Println("Failed to extend session:", err)
+		return errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+	if affected == 0 {
+		return errLib.New("Only confirmed or in-progress sessions can be extended", http.StatusConflict)
+	}
+	return nil
+}
+
 // mapDbSessionToValue maps a database row to a Session value.
 func mapDbSessionToValue(dbRow db.GetSessionRow) values.Session {
 	return values.Session{
 		ID:                dbRow.ID,
 		SystemID:          dbRow.SystemID,
 		SystemName:        dbRow.SystemName,
+		LocationID:        dbRow.LocationID.UUID,
 		CustomerID:        dbRow.CustomerID,
 		CustomerFirstName: dbRow.CustomerFirstName,
 		CustomerLastName:  dbRow.CustomerLastName,
 		StartTime:         dbRow.StartTime,
 		EndTime:           dbRow.EndTime,
+		Status:            dbRow.Status,
+		PaymentMethod:     values.PaymentMethod(dbRow.PaymentMethod),
+		AmountCents:       dbRow.AmountCents,
+		CreditsUsed:       dbRow.CreditsUsed,
+		Currency:          dbRow.Currency,
+		HoldExpiresAt:     timePtr(dbRow.HoldExpiresAt),
+		PaidAt:            timePtr(dbRow.PaidAt),
+		ActualStartAt:     timePtr(dbRow.ActualStartAt),
+		ActualEndAt:       timePtr(dbRow.ActualEndAt),
+		CanceledAt:        timePtr(dbRow.CanceledAt),
 		CreatedAt:         dbRow.CreatedAt,
 		UpdatedAt:         dbRow.UpdatedAt,
 	}
 }
 
-// mapDbCreateSessionToValue maps a database row to a CreateSession value.
-func mapDbCreateSessionToValue(dbRow db.CreateSessionRow) values.Session {
-	return values.Session{
-		ID:         dbRow.ID,
-		SystemID:   dbRow.SystemID,
-		CustomerID: dbRow.CustomerID,
-		StartTime:  dbRow.StartTime,
-		EndTime:    dbRow.EndTime,
-		CreatedAt:  dbRow.CreatedAt,
-		UpdatedAt:  dbRow.UpdatedAt,
+// mapDbSystemToValue maps a database row to a System value.
+func mapDbSystemToValue(dbRow db.PlaygroundSystem) values.System {
+	sys := values.System{
+		ID:        dbRow.ID,
+		Name:      dbRow.Name,
+		CreatedAt: dbRow.CreatedAt,
+		UpdatedAt: dbRow.UpdatedAt,
+		Rules: values.Rules{
+			LocationID:        dbRow.LocationID.UUID,
+			SlotMinutes:       dbRow.SlotMinutes,
+			MaxSlots:          dbRow.MaxSlots,
+			OpensAt:           dbRow.OpensAt,
+			ClosesAt:          dbRow.ClosesAt,
+			MaxActiveBookings: dbRow.MaxActiveBookings,
+			PricePerSlotCents: dbRow.PricePerSlotCents,
+		},
 	}
+	if dbRow.CreditsPerSlot.Valid {
+		credits := dbRow.CreditsPerSlot.Int32
+		sys.CreditsPerSlot = &credits
+	}
+	return sys
 }
 
-// mapDbSessionsRowToValue maps a database row to a Session value.
-func mapDbSessionsRowToValue(dbRow db.GetSessionsRow) values.Session {
-	return values.Session{
-		ID:                dbRow.ID,
-		SystemID:          dbRow.SystemID,
-		SystemName:        dbRow.SystemName,
-		CustomerID:        dbRow.CustomerID,
-		CustomerFirstName: dbRow.CustomerFirstName,
-		CustomerLastName:  dbRow.CustomerLastName,
-		StartTime:         dbRow.StartTime,
-		EndTime:           dbRow.EndTime,
-		CreatedAt:         dbRow.CreatedAt,
-		UpdatedAt:         dbRow.UpdatedAt,
+// systemError maps constraint violations on playground.systems to client errors.
+func systemError(err error, action string) *errLib.CommonError {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case databaseErrors.ForeignKeyViolation:
+			return errLib.New("Location with the associated ID doesn't exist", http.StatusNotFound)
+		case databaseErrors.CheckViolation:
+			return errLib.New("Invalid booking rules: "+pqErr.Constraint, http.StatusBadRequest)
+		}
 	}
+	log.Printf("Failed to %s system: %v", action, err)
+	return errLib.New("Internal server error", http.StatusInternalServerError)
+}
+
+func systemParams(name string, rules values.Rules) db.CreateSystemParams {
+	params := db.CreateSystemParams{
+		Name:              name,
+		LocationID:        uuid.NullUUID{UUID: rules.LocationID, Valid: rules.LocationID != uuid.Nil},
+		SlotMinutes:       rules.SlotMinutes,
+		MaxSlots:          rules.MaxSlots,
+		OpensAt:           rules.OpensAt,
+		ClosesAt:          rules.ClosesAt,
+		MaxActiveBookings: rules.MaxActiveBookings,
+		PricePerSlotCents: rules.PricePerSlotCents,
+	}
+	if rules.CreditsPerSlot != nil {
+		params.CreditsPerSlot = sql.NullInt32{Int32: *rules.CreditsPerSlot, Valid: true}
+	}
+	return params
 }
 
 // CreateSystem inserts a new system into playground.systems.
 func (r *Repository) CreateSystem(ctx context.Context, v values.CreateSystemValue) (values.System, *errLib.CommonError) {
-	query := `INSERT INTO playground.systems (name) VALUES ($1) RETURNING id, name, created_at, updated_at`
-	row := r.Db.QueryRowContext(ctx, query, v.Name)
-
-	var sys values.System
-	if err := row.Scan(&sys.ID, &sys.Name, &sys.CreatedAt, &sys.UpdatedAt); err != nil {
-		log.Println("Failed to create system:", err)
-		return values.System{}, errLib.New("Internal server error", http.StatusInternalServerError)
+	row, err := r.Queries.CreateSystem(ctx, systemParams(v.Name, v.Rules))
+	if err != nil {
+		return values.System{}, systemError(err, "create")
 	}
-	return sys, nil
+	return mapDbSystemToValue(row), nil
 }
 
 // GetSystems retrieves all playground systems ordered by name.
 func (r *Repository) GetSystems(ctx context.Context) ([]values.System, *errLib.CommonError) {
-	query := `SELECT id, name, created_at, updated_at FROM playground.systems ORDER BY name`
-	rows, err := r.Db.QueryContext(ctx, query)
+	rows, err := r.Queries.GetSystems(ctx)
 	if err != nil {
 		log.Println("Failed to get systems:", err)
 		return nil, errLib.New("Internal server error", http.StatusInternalServerError)
 	}
-	defer rows.Close()
 
-	var list []values.System
-	for rows.Next() {
-		var sys values.System
-		if err := rows.Scan(&sys.ID, &sys.Name, &sys.CreatedAt, &sys.UpdatedAt); err != nil {
-			log.Println("Failed to scan system:", err)
-			return nil, errLib.New("Internal server error", http.StatusInternalServerError)
-		}
-		list = append(list, sys)
-	}
-	if err := rows.Err(); err != nil {
-		log.Println("Rows error when getting systems:", err)
-		return nil, errLib.New("Internal server error", http.StatusInternalServerError)
+	list := make([]values.System, len(rows))
+	for i, row := range rows {
+		list[i] = mapDbSystemToValue(row)
 	}
 	return list, nil
 }
 
-// UpdateSystem updates a system's name by ID.
+// GetSystem retrieves a system and its booking rules by ID.
+func (r *Repository) GetSystem(ctx context.Context, id uuid.UUID) (values.System, *errLib.CommonError) {
+	row, err := r.Queries.GetSystemById(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return values.System{}, errLib.New("System not found", http.StatusNotFound)
+		}
+		log.Println("Failed to get system:", err)
+		return values.System{}, errLib.New("Internal server error", http.StatusInternalServerError)
+	}
+	return mapDbSystemToValue(row), nil
+}
+
+// UpdateSystem updates a system's name and booking rules by ID.
 func (r *Repository) UpdateSystem(ctx context.Context, v values.UpdateSystemValue) (values.System, *errLib.CommonError) {
-	query := `UPDATE playground.systems SET name = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2 RETURNING id, name, created_at, updated_at`
-	row := r.Db.QueryRowContext(ctx, query, v.Name, v.ID)
+	p := systemParams(v.Name, v.Rules)
 
-	var sys values.System
-	if err := row.Scan(&sys.ID, &sys.Name, &sys.CreatedAt, &sys.UpdatedAt); err != nil {
+	row, err := r.Queries.UpdateSystem(ctx, db.UpdateSystemParams{
+		Name:              p.Name,
+		LocationID:        p.LocationID,
+		SlotMinutes:       p.SlotMinutes,
+		MaxSlots:          p.MaxSlots,
+		OpensAt:           p.OpensAt,
+		ClosesAt:          p.ClosesAt,
+		MaxActiveBookings: p.MaxActiveBookings,
+		PricePerSlotCents: p.PricePerSlotCents,
+		CreditsPerSlot:    p.CreditsPerSlot,
+		ID:                v.ID,
+	})
+	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return values.System{}, errLib.New("System not found", http.StatusNotFound)
 		}
-		log.Println("Failed to update system:", err)
-		return values.System{}, errLib.New("Internal server error", http.StatusInternalServerError)
+		return values.System{}, systemError(err, "update")
 	}
-	return sys, nil
+	return mapDbSystemToValue(row), nil
 }
 
 // DeleteSystem removes a system by ID.
 func (r *Repository) DeleteSystem(ctx context.Context, id uuid.UUID) *errLib.CommonError {
-	query := `DELETE FROM playground.systems WHERE id = $1`
-	result, err := r.Db.ExecContext(ctx, query, id)
+	affected, err := r.Queries.DeleteSystem(ctx, id)
 	if err != nil {
 		log.Println("Failed to delete system:", err)
 		return errLib.New("Internal server error", http.StatusInternalServerError)
 	}
-	affected, _ := result.RowsAffected()
 	if affected == 0 {
 		return errLib.New("System not found", http.StatusNotFound)
 	}
@@ -488,20 +488,38 @@ type MembershipMembershipPlan struct {
 }
 
 type PlaygroundSession struct {
-	ID         uuid.UUID `json:"id"`
-	SystemID   uuid.UUID `json:"system_id"`
-	CustomerID uuid.UUID `json:"customer_id"`
-	StartTime  time.Time `json:"start_time"`
-	EndTime    time.Time `json:"end_time"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID            uuid.UUID    `json:"id"`
+	SystemID      uuid.UUID    `json:"system_id"`
+	CustomerID    uuid.UUID    `json:"customer_id"`
+	StartTime     time.Time    `json:"start_time"`
+	EndTime       time.Time    `json:"end_time"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+	Status        string       `json:"status"`
+	PaymentMethod string       `json:"payment_method"`
+	AmountCents   int32        `json:"amount_cents"`
+	CreditsUsed   int32        `json:"credits_used"`
+	Currency      string       `json:"currency"`
+	HoldExpiresAt sql.NullTime `json:"hold_expires_at"`
+	PaidAt        sql.NullTime `json:"paid_at"`
+	ActualStartAt sql.NullTime `json:"actual_start_at"`
+	ActualEndAt   sql.NullTime `json:"actual_end_at"`
+	CanceledAt    sql.NullTime `json:"canceled_at"`
 }
 
 type PlaygroundSystem struct {
-	ID        uuid.UUID `json:"id"`
-	Name      string    `json:"name"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID                uuid.UUID     `json:"id"`
+	Name              string        `json:"name"`
+	CreatedAt         time.Time     `json:"created_at"`
+	UpdatedAt         time.Time     `json:"updated_at"`
+	LocationID        uuid.NullUUID `json:"location_id"`
+	SlotMinutes       int32         `json:"slot_minutes"`
+	MaxSlots          int32         `json:"max_slots"`
+	OpensAt           time.Time     `json:"opens_at"`
+	ClosesAt          time.Time     `json:"closes_at"`
+	MaxActiveBookings int32         `json:"max_active_bookings"`
+	PricePerSlotCents int32         `json:"price_per_slot_cents"`
+	CreditsPerSlot    sql.NullInt32 `json:"credits_per_slot"`
 }
 
 type ProgramCustomerEnrollment struct {
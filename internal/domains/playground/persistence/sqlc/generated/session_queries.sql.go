@@ -7,16 +7,95 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+const cancelSession = `-- name: CancelSession :execrows
+UPDATE playground.sessions
+SET status      = 'canceled',
+    canceled_at = CURRENT_TIMESTAMP,
+    updated_at  = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND status IN ('held', 'confirmed')
+`
+
+func (q *Queries) CancelSession(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, cancelSession, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const completeSession = `-- name: CompleteSession :execrows
+UPDATE playground.sessions
+SET status        = 'completed',
+    actual_end_at = $2,
+    updated_at    = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND status = 'in_progress'
+`
+
+type CompleteSessionParams struct {
+	ID          uuid.UUID    `json:"id"`
+	ActualEndAt sql.NullTime `json:"actual_end_at"`
+}
+
+func (q *Queries) CompleteSession(ctx context.Context, arg CompleteSessionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, completeSession, arg.ID, arg.ActualEndAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const confirmSession = `-- name: ConfirmSession :execrows
+UPDATE playground.sessions
+SET status     = 'confirmed',
+    paid_at    = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND status IN ('held', 'expired')
+`
+
+func (q *Queries) ConfirmSession(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, confirmSession, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const countActiveCustomerSessions = `-- name: CountActiveCustomerSessions :one
+SELECT COUNT(*)
+FROM playground.sessions
+WHERE customer_id = $1
+  AND system_id = $2
+  AND end_time > CURRENT_TIMESTAMP
+  AND (status IN ('confirmed', 'in_progress') OR (status = 'held' AND hold_expires_at > CURRENT_TIMESTAMP))
+`
+
+type CountActiveCustomerSessionsParams struct {
+	CustomerID uuid.UUID `json:"customer_id"`
+	SystemID   uuid.UUID `json:"system_id"`
+}
+
+func (q *Queries) CountActiveCustomerSessions(ctx context.Context, arg CountActiveCustomerSessionsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countActiveCustomerSessions, arg.CustomerID, arg.SystemID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createSession = `-- name: CreateSession :one
 WITH inserted AS (
-    INSERT INTO playground.sessions (system_id, customer_id, start_time, end_time)
-    VALUES ($1, $2, $3, $4)
-    RETURNING id, system_id, customer_id, start_time, end_time, created_at, updated_at
+    INSERT INTO playground.sessions (system_id, customer_id, start_time, end_time, status, payment_method,
+                                     amount_cents, credits_used, hold_expires_at, actual_start_at)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+    RETURNING id, system_id, customer_id, start_time, end_time, created_at, updated_at, status, payment_method, amount_cents, credits_used, currency, hold_expires_at, paid_at, actual_start_at, actual_end_at, canceled_at
 )
 SELECT i.id,
        i.system_id,
@@ -27,30 +106,58 @@ SELECT i.id,
        i.start_time,
        i.end_time,
        i.created_at,
-       i.updated_at
+       i.updated_at,
+       i.status,
+       i.payment_method,
+       i.amount_cents,
+       i.credits_used,
+       i.currency,
+       i.hold_expires_at,
+       i.paid_at,
+       i.actual_start_at,
+       i.actual_end_at,
+       i.canceled_at,
+       sys.location_id
 FROM inserted i
          JOIN playground.systems sys ON sys.id = i.system_id
          JOIN users.users u ON u.id = i.customer_id
 `
 
 type CreateSessionParams struct {
-	SystemID   uuid.UUID `json:"system_id"`
-	CustomerID uuid.UUID `json:"customer_id"`
-	StartTime  time.Time `json:"start_time"`
-	EndTime    time.Time `json:"end_time"`
+	SystemID      uuid.UUID    `json:"system_id"`
+	CustomerID    uuid.UUID    `json:"customer_id"`
+	StartTime     time.Time    `json:"start_time"`
+	EndTime       time.Time    `json:"end_time"`
+	Status        string       `json:"status"`
+	PaymentMethod string       `json:"payment_method"`
+	AmountCents   int32        `json:"amount_cents"`
+	CreditsUsed   int32        `json:"credits_used"`
+	HoldExpiresAt sql.NullTime `json:"hold_expires_at"`
+	ActualStartAt sql.NullTime `json:"actual_start_at"`
 }
 
 type CreateSessionRow struct {
-	ID                uuid.UUID `json:"id"`
-	SystemID          uuid.UUID `json:"system_id"`
-	SystemName        string    `json:"system_name"`
-	CustomerID        uuid.UUID `json:"customer_id"`
-	CustomerFirstName string    `json:"customer_first_name"`
-	CustomerLastName  string    `json:"customer_last_name"`
-	StartTime         time.Time `json:"start_time"`
-	EndTime           time.Time `json:"end_time"`
-	CreatedAt         time.Time `json:"created_at"`
-	UpdatedAt         time.Time `json:"updated_at"`
+	ID                uuid.UUID     `json:"id"`
+	SystemID          uuid.UUID     `json:"system_id"`
+	SystemName        string        `json:"system_name"`
+	CustomerID        uuid.UUID     `json:"customer_id"`
+	CustomerFirstName string        `json:"customer_first_name"`
+	CustomerLastName  string        `json:"customer_last_name"`
+	StartTime         time.Time     `json:"start_time"`
+	EndTime           time.Time     `json:"end_time"`
+	CreatedAt         time.Time     `json:"created_at"`
+	UpdatedAt         time.Time     `json:"updated_at"`
+	Status            string        `json:"status"`
+	PaymentMethod     string        `json:"payment_method"`
+	AmountCents       int32         `json:"amount_cents"`
+	CreditsUsed       int32         `json:"credits_used"`
+	Currency          string        `json:"currency"`
+	HoldExpiresAt     sql.NullTime  `json:"hold_expires_at"`
+	PaidAt            sql.NullTime  `json:"paid_at"`
+	ActualStartAt     sql.NullTime  `json:"actual_start_at"`
+	ActualEndAt       sql.NullTime  `json:"actual_end_at"`
+	CanceledAt        sql.NullTime  `json:"canceled_at"`
+	LocationID        uuid.NullUUID `json:"location_id"`
 }
 
 func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (CreateSessionRow, error) {
@@ -59,6 +166,12 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (C
 		arg.CustomerID,
 		arg.StartTime,
 		arg.EndTime,
+		arg.Status,
+		arg.PaymentMethod,
+		arg.AmountCents,
+		arg.CreditsUsed,
+		arg.HoldExpiresAt,
+		arg.ActualStartAt,
 	)
 	var i CreateSessionRow
 	err := row.Scan(
@@ -72,6 +185,17 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (C
 		&i.EndTime,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.PaymentMethod,
+		&i.AmountCents,
+		&i.CreditsUsed,
+		&i.Currency,
+		&i.HoldExpiresAt,
+		&i.PaidAt,
+		&i.ActualStartAt,
+		&i.ActualEndAt,
+		&i.CanceledAt,
+		&i.LocationID,
 	)
 	return i, err
 }
@@ -88,6 +212,158 @@ func (q *Queries) DeleteSession(ctx context.Context, id uuid.UUID) (int64, error
 	return result.RowsAffected()
 }
 
+const expireStaleHolds = `-- name: ExpireStaleHolds :execrows
+UPDATE playground.sessions
+SET status     = 'expired',
+    updated_at = CURRENT_TIMESTAMP
+WHERE status = 'held'
+  AND hold_expires_at <= CURRENT_TIMESTAMP
+  AND ($1::uuid IS NULL OR system_id = $1::uuid)
+`
+
+func (q *Queries) ExpireStaleHolds(ctx context.Context, systemID uuid.NullUUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, expireStaleHolds, systemID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const extendSession = `-- name: ExtendSession :execrows
+UPDATE playground.sessions
+SET end_time     = $2,
+    amount_cents = amount_cents + $3,
+    credits_used = credits_used + $4,
+    updated_at   = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND status IN ('confirmed', 'in_progress')
+`
+
+type ExtendSessionParams struct {
+	ID               uuid.UUID `json:"id"`
+	EndTime          time.Time `json:"end_time"`
+	AddedAmountCents int32     `json:"added_amount_cents"`
+	AddedCredits     int32     `json:"added_credits"`
+}
+
+func (q *Queries) ExtendSession(ctx context.Context, arg ExtendSessionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, extendSession,
+		arg.ID,
+		arg.EndTime,
+		arg.AddedAmountCents,
+		arg.AddedCredits,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getCustomerUpcomingSessions = `-- name: GetCustomerUpcomingSessions :many
+SELECT s.id,
+       s.system_id,
+       sys.name  AS system_name,
+       s.customer_id,
+       u.first_name AS customer_first_name,
+       u.last_name  AS customer_last_name,
+       s.start_time,
+       s.end_time,
+       s.created_at,
+       s.updated_at,
+       s.status,
+       s.payment_method,
+       s.amount_cents,
+       s.credits_used,
+       s.currency,
+       s.hold_expires_at,
+       s.paid_at,
+       s.actual_start_at,
+       s.actual_end_at,
+       s.canceled_at,
+       sys.location_id
+FROM playground.sessions s
+         JOIN playground.systems sys ON sys.id = s.system_id
+         JOIN users.users u ON u.id = s.customer_id
+WHERE s.customer_id = $1
+  AND s.end_time > $2
+  AND (s.status IN ('confirmed', 'in_progress') OR (s.status = 'held' AND s.hold_expires_at > CURRENT_TIMESTAMP))
+ORDER BY s.start_time
+`
+
+type GetCustomerUpcomingSessionsParams struct {
+	CustomerID uuid.UUID `json:"customer_id"`
+	EndTime    time.Time `json:"end_time"`
+}
+
+type GetCustomerUpcomingSessionsRow struct {
+	ID                uuid.UUID     `json:"id"`
+	SystemID          uuid.UUID     `json:"system_id"`
+	SystemName        string        `json:"system_name"`
+	CustomerID        uuid.UUID     `json:"customer_id"`
+	CustomerFirstName string        `json:"customer_first_name"`
+	CustomerLastName  string        `json:"customer_last_name"`
+	StartTime         time.Time     `json:"start_time"`
+	EndTime           time.Time     `json:"end_time"`
+	CreatedAt         time.Time     `json:"created_at"`
+	UpdatedAt         time.Time     `json:"updated_at"`
+	Status            string        `json:"status"`
+	PaymentMethod     string        `json:"payment_method"`
+	AmountCents       int32         `json:"amount_cents"`
+	CreditsUsed       int32         `json:"credits_used"`
+	Currency          string        `json:"currency"`
+	HoldExpiresAt     sql.NullTime  `json:"hold_expires_at"`
+	PaidAt            sql.NullTime  `json:"paid_at"`
+	ActualStartAt     sql.NullTime  `json:"actual_start_at"`
+	ActualEndAt       sql.NullTime  `json:"actual_end_at"`
+	CanceledAt        sql.NullTime  `json:"canceled_at"`
+	LocationID        uuid.NullUUID `json:"location_id"`
+}
+
+func (q *Queries) GetCustomerUpcomingSessions(ctx context.Context, arg GetCustomerUpcomingSessionsParams) ([]GetCustomerUpcomingSessionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getCustomerUpcomingSessions, arg.CustomerID, arg.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetCustomerUpcomingSessionsRow
+	for rows.Next() {
+		var i GetCustomerUpcomingSessionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SystemID,
+			&i.SystemName,
+			&i.CustomerID,
+			&i.CustomerFirstName,
+			&i.CustomerLastName,
+			&i.StartTime,
+			&i.EndTime,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.PaymentMethod,
+			&i.AmountCents,
+			&i.CreditsUsed,
+			&i.Currency,
+			&i.HoldExpiresAt,
+			&i.PaidAt,
+			&i.ActualStartAt,
+			&i.ActualEndAt,
+			&i.CanceledAt,
+			&i.LocationID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getSession = `-- name: GetSession :one
 SELECT s.id,
        s.system_id,
@@ -98,7 +374,18 @@ SELECT s.id,
        s.start_time,
        s.end_time,
        s.created_at,
-       s.updated_at
+       s.updated_at,
+       s.status,
+       s.payment_method,
+       s.amount_cents,
+       s.credits_used,
+       s.currency,
+       s.hold_expires_at,
+       s.paid_at,
+       s.actual_start_at,
+       s.actual_end_at,
+       s.canceled_at,
+       sys.location_id
 FROM playground.sessions s
          JOIN playground.systems sys ON sys.id = s.system_id
          JOIN users.users u ON u.id = s.customer_id
@@ -106,16 +393,27 @@ WHERE s.id = $1
 `
 
 type GetSessionRow struct {
-	ID                uuid.UUID `json:"id"`
-	SystemID          uuid.UUID `json:"system_id"`
-	SystemName        string    `json:"system_name"`
-	CustomerID        uuid.UUID `json:"customer_id"`
-	CustomerFirstName string    `json:"customer_first_name"`
-	CustomerLastName  string    `json:"customer_last_name"`
-	StartTime         time.Time `json:"start_time"`
-	EndTime           time.Time `json:"end_time"`
-	CreatedAt         time.Time `json:"created_at"`
-	UpdatedAt         time.Time `json:"updated_at"`
+	ID                uuid.UUID     `json:"id"`
+	SystemID          uuid.UUID     `json:"system_id"`
+	SystemName        string        `json:"system_name"`
+	CustomerID        uuid.UUID     `json:"customer_id"`
+	CustomerFirstName string        `json:"customer_first_name"`
+	CustomerLastName  string        `json:"customer_last_name"`
+	StartTime         time.Time     `json:"start_time"`
+	EndTime           time.Time     `json:"end_time"`
+	CreatedAt         time.Time     `json:"created_at"`
+	UpdatedAt         time.Time     `json:"updated_at"`
+	Status            string        `json:"status"`
+	PaymentMethod     string        `json:"payment_method"`
+	AmountCents       int32         `json:"amount_cents"`
+	CreditsUsed       int32         `json:"credits_used"`
+	Currency          string        `json:"currency"`
+	HoldExpiresAt     sql.NullTime  `json:"hold_expires_at"`
+	PaidAt            sql.NullTime  `json:"paid_at"`
+	ActualStartAt     sql.NullTime  `json:"actual_start_at"`
+	ActualEndAt       sql.NullTime  `json:"actual_end_at"`
+	CanceledAt        sql.NullTime  `json:"canceled_at"`
+	LocationID        uuid.NullUUID `json:"location_id"`
 }
 
 func (q *Queries) GetSession(ctx context.Context, id uuid.UUID) (GetSessionRow, error) {
@@ -132,6 +430,49 @@ func (q *Queries) GetSession(ctx context.Context, id uuid.UUID) (GetSessionRow,
 		&i.EndTime,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.PaymentMethod,
+		&i.AmountCents,
+		&i.CreditsUsed,
+		&i.Currency,
+		&i.HoldExpiresAt,
+		&i.PaidAt,
+		&i.ActualStartAt,
+		&i.ActualEndAt,
+		&i.CanceledAt,
+		&i.LocationID,
+	)
+	return i, err
+}
+
+const getSessionForUpdate = `-- name: GetSessionForUpdate :one
+SELECT id, system_id, customer_id, start_time, end_time, created_at, updated_at, status, payment_method, amount_cents, credits_used, currency, hold_expires_at, paid_at, actual_start_at, actual_end_at, canceled_at
+FROM playground.sessions
+WHERE id = $1
+    FOR UPDATE
+`
+
+func (q *Queries) GetSessionForUpdate(ctx context.Context, id uuid.UUID) (PlaygroundSession, error) {
+	row := q.db.QueryRowContext(ctx, getSessionForUpdate, id)
+	var i PlaygroundSession
+	err := row.Scan(
+		&i.ID,
+		&i.SystemID,
+		&i.CustomerID,
+		&i.StartTime,
+		&i.EndTime,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.PaymentMethod,
+		&i.AmountCents,
+		&i.CreditsUsed,
+		&i.Currency,
+		&i.HoldExpiresAt,
+		&i.PaidAt,
+		&i.ActualStartAt,
+		&i.ActualEndAt,
+		&i.CanceledAt,
 	)
 	return i, err
 }
@@ -146,7 +487,18 @@ SELECT s.id,
        s.start_time,
        s.end_time,
        s.created_at,
-       s.updated_at
+       s.updated_at,
+       s.status,
+       s.payment_method,
+       s.amount_cents,
+       s.credits_used,
+       s.currency,
+       s.hold_expires_at,
+       s.paid_at,
+       s.actual_start_at,
+       s.actual_end_at,
+       s.canceled_at,
+       sys.location_id
 FROM playground.sessions s
          JOIN playground.systems sys ON sys.id = s.system_id
          JOIN users.users u ON u.id = s.customer_id
@@ -154,16 +506,27 @@ ORDER BY s.start_time
 `
 
 type GetSessionsRow struct {
-	ID                uuid.UUID `json:"id"`
-	SystemID          uuid.UUID `json:"system_id"`
-	SystemName        string    `json:"system_name"`
-	CustomerID        uuid.UUID `json:"customer_id"`
-	CustomerFirstName string    `json:"customer_first_name"`
-	CustomerLastName  string    `json:"customer_last_name"`
-	StartTime         time.Time `json:"start_time"`
-	EndTime           time.Time `json:"end_time"`
-	CreatedAt         time.Time `json:"created_at"`
-	UpdatedAt         time.Time `json:"updated_at"`
+	ID                uuid.UUID     `json:"id"`
+	SystemID          uuid.UUID     `json:"system_id"`
+	SystemName        string        `json:"system_name"`
+	CustomerID        uuid.UUID     `json:"customer_id"`
+	CustomerFirstName string        `json:"customer_first_name"`
+	CustomerLastName  string        `json:"customer_last_name"`
+	StartTime         time.Time     `json:"start_time"`
+	EndTime           time.Time     `json:"end_time"`
+	CreatedAt         time.Time     `json:"created_at"`
+	UpdatedAt         time.Time     `json:"updated_at"`
+	Status            string        `json:"status"`
+	PaymentMethod     string        `json:"payment_method"`
+	AmountCents       int32         `json:"amount_cents"`
+	CreditsUsed       int32         `json:"credits_used"`
+	Currency          string        `json:"currency"`
+	HoldExpiresAt     sql.NullTime  `json:"hold_expires_at"`
+	PaidAt            sql.NullTime  `json:"paid_at"`
+	ActualStartAt     sql.NullTime  `json:"actual_start_at"`
+	ActualEndAt       sql.NullTime  `json:"actual_end_at"`
+	CanceledAt        sql.NullTime  `json:"canceled_at"`
+	LocationID        uuid.NullUUID `json:"location_id"`
 }
 
 func (q *Queries) GetSessions(ctx context.Context) ([]GetSessionsRow, error) {
@@ -186,6 +549,17 @@ func (q *Queries) GetSessions(ctx context.Context) ([]GetSessionsRow, error) {
 			&i.EndTime,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Status,
+			&i.PaymentMethod,
+			&i.AmountCents,
+			&i.CreditsUsed,
+			&i.Currency,
+			&i.HoldExpiresAt,
+			&i.PaidAt,
+			&i.ActualStartAt,
+			&i.ActualEndAt,
+			&i.CanceledAt,
+			&i.LocationID,
 		); err != nil {
 			return nil, err
 		}
@@ -199,3 +573,58 @@ func (q *Queries) GetSessions(ctx context.Context) ([]GetSessionsRow, error) {
 	}
 	return items, nil
 }
+
+const markSessionRefundDue = `-- name: MarkSessionRefundDue :execrows
+UPDATE playground.sessions
+SET status     = 'refund_due',
+    paid_at    = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND status IN ('held', 'expired', 'canceled')
+`
+
+func (q *Queries) MarkSessionRefundDue(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, markSessionRefundDue, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const markSessionRefunded = `-- name: MarkSessionRefunded :execrows
+UPDATE playground.sessions
+SET status     = 'refunded',
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND status = 'refund_due'
+`
+
+func (q *Queries) MarkSessionRefunded(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, markSessionRefunded, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const startSession = `-- name: StartSession :execrows
+UPDATE playground.sessions
+SET status          = 'in_progress',
+    actual_start_at = $2,
+    updated_at      = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND status = 'confirmed'
+`
+
+type StartSessionParams struct {
+	ID            uuid.UUID    `json:"id"`
+	ActualStartAt sql.NullTime `json:"actual_start_at"`
+}
+
+func (q *Queries) StartSession(ctx context.Context, arg StartSessionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, startSession, arg.ID, arg.ActualStartAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
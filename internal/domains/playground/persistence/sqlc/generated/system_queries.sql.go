@@ -7,24 +7,57 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 const createSystem = `-- name: CreateSystem :one
-INSERT INTO playground.systems (name)
-VALUES ($1)
-RETURNING id, name, created_at, updated_at
+INSERT INTO playground.systems (name, location_id, slot_minutes, max_slots, opens_at, closes_at,
+                                max_active_bookings, price_per_slot_cents, credits_per_slot)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING id, name, created_at, updated_at, location_id, slot_minutes, max_slots, opens_at, closes_at, max_active_bookings, price_per_slot_cents, credits_per_slot
 `
 
-func (q *Queries) CreateSystem(ctx context.Context, name string) (PlaygroundSystem, error) {
-	row := q.db.QueryRowContext(ctx, createSystem, name)
+type CreateSystemParams struct {
+	Name              string        `json:"name"`
+	LocationID        uuid.NullUUID `json:"location_id"`
+	SlotMinutes       int32         `json:"slot_minutes"`
+	MaxSlots          int32         `json:"max_slots"`
+	OpensAt           time.Time     `json:"opens_at"`
+	ClosesAt          time.Time     `json:"closes_at"`
+	MaxActiveBookings int32         `json:"max_active_bookings"`
+	PricePerSlotCents int32         `json:"price_per_slot_cents"`
+	CreditsPerSlot    sql.NullInt32 `json:"credits_per_slot"`
+}
+
+func (q *Queries) CreateSystem(ctx context.Context, arg CreateSystemParams) (PlaygroundSystem, error) {
+	row := q.db.QueryRowContext(ctx, createSystem,
+		arg.Name,
+		arg.LocationID,
+		arg.SlotMinutes,
+		arg.MaxSlots,
+		arg.OpensAt,
+		arg.ClosesAt,
+		arg.MaxActiveBookings,
+		arg.PricePerSlotCents,
+		arg.CreditsPerSlot,
+	)
 	var i PlaygroundSystem
 	err := row.Scan(
 		&i.ID,
 		&i.Name,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.LocationID,
+		&i.SlotMinutes,
+		&i.MaxSlots,
+		&i.OpensAt,
+		&i.ClosesAt,
+		&i.MaxActiveBookings,
+		&i.PricePerSlotCents,
+		&i.CreditsPerSlot,
 	)
 	return i, err
 }
@@ -41,8 +74,32 @@ func (q *Queries) DeleteSystem(ctx context.Context, id uuid.UUID) (int64, error)
 	return result.RowsAffected()
 }
 
+const getSystemById = `-- name: GetSystemById :one
+SELECT id, name, created_at, updated_at, location_id, slot_minutes, max_slots, opens_at, closes_at, max_active_bookings, price_per_slot_cents, credits_per_slot FROM playground.systems WHERE id = $1
+`
+
+func (q *Queries) GetSystemById(ctx context.Context, id uuid.UUID) (PlaygroundSystem, error) {
+	row := q.db.QueryRowContext(ctx, getSystemById, id)
+	var i PlaygroundSystem
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LocationID,
+		&i.SlotMinutes,
+		&i.MaxSlots,
+		&i.OpensAt,
+		&i.ClosesAt,
+		&i.MaxActiveBookings,
+		&i.PricePerSlotCents,
+		&i.CreditsPerSlot,
+	)
+	return i, err
+}
+
 const getSystems = `-- name: GetSystems :many
-SELECT id, name, created_at, updated_at FROM playground.systems ORDER BY name
+SELECT id, name, created_at, updated_at, location_id, slot_minutes, max_slots, opens_at, closes_at, max_active_bookings, price_per_slot_cents, credits_per_slot FROM playground.systems ORDER BY name
 `
 
 func (q *Queries) GetSystems(ctx context.Context) ([]PlaygroundSystem, error) {
@@ -59,6 +116,14 @@ func (q *Queries) GetSystems(ctx context.Context) ([]PlaygroundSystem, error) {
 			&i.Name,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.LocationID,
+			&i.SlotMinutes,
+			&i.MaxSlots,
+			&i.OpensAt,
+			&i.ClosesAt,
+			&i.MaxActiveBookings,
+			&i.PricePerSlotCents,
+			&i.CreditsPerSlot,
 		); err != nil {
 			return nil, err
 		}
@@ -75,25 +140,60 @@ func (q *Queries) GetSystems(ctx context.Context) ([]PlaygroundSystem, error) {
 
 const updateSystem = `-- name: UpdateSystem :one
 UPDATE playground.systems
-SET name = $1,
-    updated_at = CURRENT_TIMESTAMP
-WHERE id = $2
-RETURNING id, name, created_at, updated_at
+SET name                 = $1,
+    location_id          = $2,
+    slot_minutes         = $3,
+    max_slots            = $4,
+    opens_at             = $5,
+    closes_at            = $6,
+    max_active_bookings  = $7,
+    price_per_slot_cents = $8,
+    credits_per_slot     = $9,
+    updated_at           = CURRENT_TIMESTAMP
+WHERE id = $10
+RETURNING id, name, created_at, updated_at, location_id, slot_minutes, max_slots, opens_at, closes_at, max_active_bookings, price_per_slot_cents, credits_per_slot
 `
 
 type UpdateSystemParams struct {
-	Name string    `json:"name"`
-	ID   uuid.UUID `json:"id"`
+	Name              string        `json:"name"`
+	LocationID        uuid.NullUUID `json:"location_id"`
+	SlotMinutes       int32         `json:"slot_minutes"`
+	MaxSlots          int32         `json:"max_slots"`
+	OpensAt           time.Time     `json:"opens_at"`
+	ClosesAt          time.Time     `json:"closes_at"`
+	MaxActiveBookings int32         `json:"max_active_bookings"`
+	PricePerSlotCents int32         `json:"price_per_slot_cents"`
+	CreditsPerSlot    sql.NullInt32 `json:"credits_per_slot"`
+	ID                uuid.UUID     `json:"id"`
 }
 
 func (q *Queries) UpdateSystem(ctx context.Context, arg UpdateSystemParams) (PlaygroundSystem, error) {
-	row := q.db.QueryRowContext(ctx, updateSystem, arg.Name, arg.ID)
+	row := q.db.QueryRowContext(ctx, updateSystem,
+		arg.Name,
+		arg.LocationID,
+		arg.SlotMinutes,
+		arg.MaxSlots,
+		arg.OpensAt,
+		arg.ClosesAt,
+		arg.MaxActiveBookings,
+		arg.PricePerSlotCents,
+		arg.CreditsPerSlot,
+		arg.ID,
+	)
 	var i PlaygroundSystem
 	err := row.Scan(
 		&i.ID,
 		&i.Name,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.LocationID,
+		&i.SlotMinutes,
+		&i.MaxSlots,
+		&i.OpensAt,
+		&i.ClosesAt,
+		&i.MaxActiveBookings,
+		&i.PricePerSlotCents,
+		&i.CreditsPerSlot,
 	)
 	return i, err
 }
@@ -1,8 +1,9 @@
 -- name: CreateSession :one
 WITH inserted AS (
-    INSERT INTO playground.sessions (system_id, customer_id, start_time, end_time)
-    VALUES ($1, $2, $3, $4)
-    RETURNING id, system_id, customer_id, start_time, end_time, created_at, updated_at
+    INSERT INTO playground.sessions (system_id, customer_id, start_time, end_time, status, payment_method,
+                                     amount_cents, credits_used, hold_expires_at, actual_start_at)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+    RETURNING *
 )
 SELECT i.id,
        i.system_id,
@@ -13,7 +14,18 @@ SELECT i.id,
        i.start_time,
        i.end_time,
        i.created_at,
-       i.updated_at
+       i.updated_at,
+       i.status,
+       i.payment_method,
+       i.amount_cents,
+       i.credits_used,
+       i.currency,
+       i.hold_expires_at,
+       i.paid_at,
+       i.actual_start_at,
+       i.actual_end_at,
+       i.canceled_at,
+       sys.location_id
 FROM inserted i
          JOIN playground.systems sys ON sys.id = i.system_id
          JOIN users.users u ON u.id = i.customer_id;
@@ -28,7 +40,18 @@ SELECT s.id,
        s.start_time,
        s.end_time,
        s.created_at,
-       s.updated_at
+       s.updated_at,
+       s.status,
+       s.payment_method,
+       s.amount_cents,
+       s.credits_used,
+       s.currency,
+       s.hold_expires_at,
+       s.paid_at,
+       s.actual_start_at,
+       s.actual_end_at,
+       s.canceled_at,
+       sys.location_id
 FROM playground.sessions s
          JOIN playground.systems sys ON sys.id = s.system_id
          JOIN users.users u ON u.id = s.customer_id
@@ -44,11 +67,130 @@ SELECT s.id,
        s.start_time,
        s.end_time,
        s.created_at,
-       s.updated_at
+       s.updated_at,
+       s.status,
+       s.payment_method,
+       s.amount_cents,
+       s.credits_used,
+       s.currency,
+       s.hold_expires_at,
+       s.paid_at,
+       s.actual_start_at,
+       s.actual_end_at,
+       s.canceled_at,
+       sys.location_id
 FROM playground.sessions s
          JOIN playground.systems sys ON sys.id = s.system_id
          JOIN users.users u ON u.id = s.customer_id
 WHERE s.id = $1;
 
+-- name: GetCustomerUpcomingSessions :many
+SELECT s.id,
+       s.system_id,
+       sys.name  AS system_name,
+       s.customer_id,
+       u.first_name AS customer_first_name,
+       u.last_name  AS customer_last_name,
+       s.start_time,
+       s.end_time,
+       s.created_at,
+       s.updated_at,
+       s.status,
+       s.payment_method,
+       s.amount_cents,
+       s.credits_used,
+       s.currency,
+       s.hold_expires_at,
+       s.paid_at,
+       s.actual_start_at,
+       s.actual_end_at,
+       s.canceled_at,
+       sys.location_id
+FROM playground.sessions s
+         JOIN playground.systems sys ON sys.id = s.system_id
+         JOIN users.users u ON u.id = s.customer_id
+WHERE s.customer_id = $1
+  AND s.end_time > $2
+  AND (s.status IN ('confirmed', 'in_progress') OR (s.status = 'held' AND s.hold_expires_at > CURRENT_TIMESTAMP))
+ORDER BY s.start_time;
+
+-- name: CountActiveCustomerSessions :one
+SELECT COUNT(*)
+FROM playground.sessions
+WHERE customer_id = $1
+  AND system_id = $2
+  AND end_time > CURRENT_TIMESTAMP
+  AND (status IN ('confirmed', 'in_progress') OR (status = 'held' AND hold_expires_at > CURRENT_TIMESTAMP));
+
+-- name: GetSessionForUpdate :one
+SELECT *
+FROM playground.sessions
+WHERE id = $1
+    FOR UPDATE;
+
+-- name: ExpireStaleHolds :execrows
+UPDATE playground.sessions
+SET status     = 'expired',
+    updated_at = CURRENT_TIMESTAMP
+WHERE status = 'held'
+  AND hold_expires_at <= CURRENT_TIMESTAMP
+  AND (sqlc.narg('system_id')::uuid IS NULL OR system_id = sqlc.narg('system_id')::uuid);
+
+-- name: ConfirmSession :execrows
+UPDATE playground.sessions
+SET status     = 'confirmed',
+    paid_at    = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND status IN ('held', 'expired');
+
+-- name: MarkSessionRefundDue :execrows
+UPDATE playground.sessions
+SET status     = 'refund_due',
+    paid_at    = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND status IN ('held', 'expired', 'canceled');
+
+-- name: MarkSessionRefunded :execrows
+UPDATE playground.sessions
+SET status     = 'refunded',
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND status = 'refund_due';
+
+-- name: CancelSession :execrows
+UPDATE playground.sessions
+SET status      = 'canceled',
+    canceled_at = CURRENT_TIMESTAMP,
+    updated_at  = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND status IN ('held', 'confirmed');
+
+-- name: StartSession :execrows
+UPDATE playground.sessions
+SET status          = 'in_progress',
+    actual_start_at = $2,
+    updated_at      = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND status = 'confirmed';
+
+-- name: CompleteSession :execrows
+UPDATE playground.sessions
+SET status        = 'completed',
+    actual_end_at = $2,
+    updated_at    = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND status = 'in_progress';
+
+-- name: ExtendSession :execrows
+UPDATE playground.sessions
+SET end_time     = $2,
+    amount_cents = amount_cents + sqlc.arg('added_amount_cents'),
+    credits_used = credits_used + sqlc.arg('added_credits'),
+    updated_at   = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND status IN ('confirmed', 'in_progress');
+
 -- name: DeleteSession :execrows
-DELETE FROM playground.sessions WHERE id = $1;
\ No newline at end of file
+DELETE FROM playground.sessions WHERE id = $1;
@@ -1,17 +1,29 @@
 -- name: CreateSystem :one
-INSERT INTO playground.systems (name)
-VALUES ($1)
+INSERT INTO playground.systems (name, location_id, slot_minutes, max_slots, opens_at, closes_at,
+                                max_active_bookings, price_per_slot_cents, credits_per_slot)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 RETURNING *;
 
 -- name: GetSystems :many
 SELECT * FROM playground.systems ORDER BY name;
 
+-- name: GetSystemById :one
+SELECT * FROM playground.systems WHERE id = $1;
+
 -- name: UpdateSystem :one
 UPDATE playground.systems
-SET name = $1,
-    updated_at = CURRENT_TIMESTAMP
-WHERE id = $2
+SET name                 = $1,
+    location_id          = $2,
+    slot_minutes         = $3,
+    max_slots            = $4,
+    opens_at             = $5,
+    closes_at            = $6,
+    max_active_bookings  = $7,
+    price_per_slot_cents = $8,
+    credits_per_slot     = $9,
+    updated_at           = CURRENT_TIMESTAMP
+WHERE id = $10
 RETURNING *;
 
 -- name: DeleteSystem :execrows
-DELETE FROM playground.systems WHERE id = $1;
\ No newline at end of file
+DELETE FROM playground.systems WHERE id = $1;
@@ -2,26 +2,641 @@ package playground
 
 import (
 	"api/internal/di"
+	staffActivityLogs "api/internal/domains/audit/staff_activity_logs/service"
+	bookingService "api/internal/domains/booking/service"
+	bookingValues "api/internal/domains/booking/values"
 	repo "api/internal/domains/playground/persistence"
+	db "api/internal/domains/playground/persistence/sqlc/generated"
 	values "api/internal/domains/playground/values"
+	creditRepo "api/internal/domains/user/persistence/repositories"
+	dbUser "api/internal/domains/user/persistence/sqlc/generated"
+	userValues "api/internal/domains/user/values"
 	errLib "api/internal/libs/errors"
+	contextUtils "api/utils/context"
+	txUtils "api/utils/db"
+	"api/utils/timezone"
 	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+// HoldDuration is how long a system stays held while the customer pays by card. It
+// matches the shortest expiry Stripe allows on a checkout session.
+const HoldDuration = 30 * time.Minute
+
 type Service struct {
-	repo *repo.Repository
+	repo                     *repo.Repository
+	creditRepo               *creditRepo.CustomerCreditRepository
+	bookingService           *bookingService.Service
+	staffActivityLogsService *staffActivityLogs.Service
+	db                       *sql.DB
 }
 
 // NewService initializes a new Service for the playground domain.
 func NewService(container *di.Container) *Service {
-	return &Service{repo: repo.NewRepository(container)}
+	return &Service{
+		repo:                     repo.NewRepository(container),
+		creditRepo:               creditRepo.NewCustomerCreditRepository(container),
+		bookingService:           bookingService.NewService(container),
+		staffActivityLogsService: staffActivityLogs.NewService(container),
+		db:                       container.DB,
+	}
 }
 
-// CreateSession creates a new session in the playground domain.
+// CreateSession books a session on a free system. Paid systems are booked through checkout.
 func (s *Service) CreateSession(ctx context.Context, v values.CreateSessionValue) (values.Session, *errLib.CommonError) {
-	return s.repo.CreateSession(ctx, v)
+	var session values.Session
+	err := txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		id, _, err := s.book(ctx, tx, values.BookSessionValue{
+			SystemID:      v.SystemID,
+			CustomerID:    v.CustomerID,
+			StartTime:     v.StartTime,
+			EndTime:       v.EndTime,
+			PaymentMethod: values.PaymentFree,
+		})
+		if err != nil {
+			return err
+		}
+		session, err = s.repo.WithTx(tx).GetSession(ctx, id)
+		return err
+	})
+	if err != nil {
+		return values.Session{}, err
+	}
+	return session, nil
+}
+
+// HoldForCheckout holds a system for the customer to pay for by card. A session that
+// costs nothing is confirmed straight away.
+func (s *Service) HoldForCheckout(ctx context.Context, req values.BookSessionValue) (values.Session, *errLib.CommonError) {
+	req.PaymentMethod = values.PaymentStripe
+
+	var session values.Session
+	err := txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		id, _, err := s.book(ctx, tx, req)
+		if err != nil {
+			return err
+		}
+		session, err = s.repo.WithTx(tx).GetSession(ctx, id)
+		return err
+	})
+	if err != nil {
+		return values.Session{}, err
+	}
+	return session, nil
+}
+
+// BookWithCredits books a session and pays for it from the customer's credit balance,
+// counting toward their weekly credit limit.
+func (s *Service) BookWithCredits(ctx context.Context, req values.BookSessionValue) (values.Session, *errLib.CommonError) {
+	req.PaymentMethod = values.PaymentCredits
+
+	var session values.Session
+	err := txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		r := s.repo.WithTx(tx)
+
+		id, loc, err := s.book(ctx, tx, req)
+		if err != nil {
+			return err
+		}
+		session, err = r.GetSession(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err = s.chargeCredits(ctx, tx, req.CustomerID, session.CreditsUsed, loc, userValues.PlaygroundSessionCreditReference(id),
+			fmt.Sprintf("Playground session payment (%s)", id)); err != nil {
+			return err
+		}
+
+		if err = r.ConfirmSession(ctx, id); err != nil {
+			return err
+		}
+		session, err = r.GetSession(ctx, id)
+		return err
+	})
+	if err != nil {
+		return values.Session{}, err
+	}
+	return session, nil
+}
+
+// book checks the request against the system's booking rules and writes the session
+// inside tx. Card sessions are held until paid unless they cost nothing; credit
+// sessions are held for the caller to charge and confirm. It returns the session's ID
+// and the system's timezone.
+func (s *Service) book(ctx context.Context, tx *sql.Tx, req values.BookSessionValue) (uuid.UUID, *time.Location, *errLib.CommonError) {
+	r := s.repo.WithTx(tx)
+
+	if _, err := r.ExpireStaleHolds(ctx, req.SystemID); err != nil {
+		return uuid.Nil, nil, err
+	}
+
+	system, err := r.GetSystem(ctx, req.SystemID)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+	loc := timezone.ForLocation(ctx, tx, system.LocationID)
+
+	slots, err := validateSession(system.Rules, req.StartTime, req.EndTime, loc, time.Now())
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+
+	active, err := r.CountActiveCustomerSessions(ctx, req.CustomerID, req.SystemID)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+	if active >= int64(system.MaxActiveBookings) {
+		return uuid.Nil, nil, errLib.New(fmt.Sprintf("You can have at most %d upcoming sessions on %s",
+			system.MaxActiveBookings, system.Name), http.StatusConflict)
+	}
+
+	session := values.CreateSessionValue{
+		SystemID:      req.SystemID,
+		CustomerID:    req.CustomerID,
+		StartTime:     req.StartTime,
+		EndTime:       req.EndTime,
+		Status:        values.StatusHeld,
+		PaymentMethod: req.PaymentMethod,
+	}
+	holdExpiresAt := time.Now().Add(HoldDuration)
+	session.HoldExpiresAt = &holdExpiresAt
+
+	switch req.PaymentMethod {
+	case values.PaymentCredits:
+		if system.CreditsPerSlot == nil {
+			return uuid.Nil, nil, errLib.New(fmt.Sprintf("%s cannot be paid for with credits", system.Name), http.StatusBadRequest)
+		}
+		session.CreditsUsed = slots * *system.CreditsPerSlot
+	case values.PaymentStripe:
+		session.AmountCents = slots * system.PricePerSlotCents
+	}
+
+	if session.AmountCents == 0 && session.CreditsUsed == 0 {
+		if req.PaymentMethod == values.PaymentFree && system.PricePerSlotCents > 0 {
+			return uuid.Nil, nil, errLib.New(fmt.Sprintf("%s is paid; book it through checkout", system.Name), http.StatusBadRequest)
+		}
+		session.Status, session.PaymentMethod, session.HoldExpiresAt = values.StatusConfirmed, values.PaymentFree, nil
+	}
+
+	if err = s.bookingService.Reserve(ctx, tx, bookingValues.Request{
+		ResourceType: bookingValues.ResourcePlaygroundSystem,
+		ResourceID:   req.SystemID,
+		ActivityType: bookingValues.ActivityPlaygroundSession,
+		StartAt:      req.StartTime,
+		EndAt:        req.EndTime,
+	}); err != nil {
+		return uuid.Nil, nil, err
+	}
+
+	created, err := r.CreateSession(ctx, session)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+	return created.ID, loc, nil
+}
+
+// ConfirmPaid confirms a session once its card payment completes, and returns the
+// session's status. It is safe to call more than once. A lapsed hold stops blocking
+// the system and stops counting toward the customer's limit of upcoming sessions, so
+// both are checked again before it is confirmed. When either check fails, or the hold
+// was canceled, the session is marked refund_due: the caller refunds the payment and
+// then calls MarkRefunded. A refunded session was already refunded and needs nothing
+// more.
+func (s *Service) ConfirmPaid(ctx context.Context, sessionID, customerID uuid.UUID) (status string, err *errLib.CommonError) {
+	err = txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		r := s.repo.WithTx(tx)
+
+		session, err := r.LockSession(ctx, sessionID)
+		if err != nil {
+			return err
+		}
+		if session.CustomerID != customerID {
+			return errLib.New("Playground session belongs to another customer", http.StatusBadRequest)
+		}
+
+		switch session.Status {
+		case values.StatusHeld, values.StatusExpired:
+		case values.StatusCanceled:
+			log.Printf("[PLAYGROUND] Session %s was paid for after its hold was canceled; refund due", sessionID)
+			status = values.StatusRefundDue
+			return r.MarkSessionRefundDue(ctx, sessionID)
+		default:
+			// Confirmed, or already started, completed, refund_due or refunded
+			status = session.Status
+			return nil
+		}
+
+		if session.Status == values.StatusExpired || !session.HoldExpiresAt.Time.After(time.Now()) {
+			reason, err := s.rebookLapsedHold(ctx, tx, session)
+			if err != nil {
+				return err
+			}
+			if reason != "" {
+				log.Printf("[PLAYGROUND] Session %s was paid for after its hold lapsed: %s; refund due", sessionID, reason)
+				status = values.StatusRefundDue
+				return r.MarkSessionRefundDue(ctx, sessionID)
+			}
+		}
+
+		if err = r.ConfirmSession(ctx, sessionID); err != nil {
+			return err
+		}
+		status = values.StatusConfirmed
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return status, nil
+}
+
+// rebookLapsedHold checks that a session whose hold lapsed could still be booked: the
+// system is free and the customer is under the system's limit of upcoming sessions.
+// It returns why not, or an empty reason when the session can be confirmed.
+func (s *Service) rebookLapsedHold(ctx context.Context, tx *sql.Tx, session db.PlaygroundSession) (string, *errLib.CommonError) {
+	r := s.repo.WithTx(tx)
+
+	system, err := r.GetSystem(ctx, session.SystemID)
+	if err != nil {
+		return "", err
+	}
+
+	active, err := r.CountActiveCustomerSessions(ctx, session.CustomerID, session.SystemID)
+	if err != nil {
+		return "", err
+	}
+	if active >= int64(system.MaxActiveBookings) {
+		return fmt.Sprintf("the customer already has %d upcoming sessions on %s", active, system.Name), nil
+	}
+
+	if err = s.bookingService.Reserve(ctx, tx, bookingValues.Request{
+		ResourceType: bookingValues.ResourcePlaygroundSystem,
+		ResourceID:   session.SystemID,
+		ActivityType: bookingValues.ActivityPlaygroundSession,
+		ActivityID:   session.ID,
+		StartAt:      session.StartTime,
+		EndAt:        session.EndTime,
+	}); err != nil {
+		if err.HTTPCode != http.StatusConflict {
+			return "", err
+		}
+		return err.Message, nil
+	}
+	return "", nil
+}
+
+// MarkRefunded records that the payment of a session ConfirmPaid could not confirm was
+// refunded.
+func (s *Service) MarkRefunded(ctx context.Context, sessionID uuid.UUID) *errLib.CommonError {
+	return s.repo.MarkSessionRefunded(ctx, sessionID)
+}
+
+// ReleaseHold frees a held system when checkout could not be started.
+func (s *Service) ReleaseHold(ctx context.Context, sessionID uuid.UUID) *errLib.CommonError {
+	return txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		r := s.repo.WithTx(tx)
+
+		session, err := r.LockSession(ctx, sessionID)
+		if err != nil {
+			return err
+		}
+		if session.Status != values.StatusHeld {
+			return nil
+		}
+		return r.CancelSession(ctx, sessionID)
+	})
+}
+
+// CancelSession cancels a session. Customers can drop their own unpaid hold or a future
+// free or credit-paid session, whose credits are refunded; staff can cancel any session
+// that has not started.
+func (s *Service) CancelSession(ctx context.Context, id uuid.UUID) *errLib.CommonError {
+	userID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return err
+	}
+	isStaff, err := contextUtils.IsStaff(ctx)
+	if err != nil {
+		return err
+	}
+
+	return txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		r := s.repo.WithTx(tx)
+
+		row, err := r.LockSession(ctx, id)
+		if err != nil {
+			return err
+		}
+		if !isStaff && row.CustomerID != userID {
+			return errLib.New("Session not found", http.StatusNotFound)
+		}
+
+		paid := row.Status == values.StatusConfirmed
+		if !isStaff && paid {
+			if row.PaymentMethod == string(values.PaymentStripe) || row.PaymentMethod == string(values.PaymentInPerson) {
+				return errLib.New("Paid playground sessions can only be canceled by staff", http.StatusForbidden)
+			}
+			if !row.StartTime.After(time.Now()) {
+				return errLib.New("Playground sessions cannot be canceled once they have started", http.StatusBadRequest)
+			}
+		}
+
+		if err = r.CancelSession(ctx, id); err != nil {
+			return err
+		}
+
+		session, err := r.GetSession(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if paid && row.CreditsUsed > 0 {
+			if err = s.refundCredits(ctx, tx, session); err != nil {
+				return err
+			}
+		}
+
+		if !isStaff {
+			return nil
+		}
+		return s.staffActivityLogsService.InsertStaffActivity(ctx, tx, userID,
+			fmt.Sprintf("Canceled playground session of %s %s on %s (%s)", session.CustomerFirstName,
+				session.CustomerLastName, session.SystemName, session.StartTime.UTC().Format("Jan 2 15:04 MST")))
+	})
+}
+
+// ExtendSession adds minutes to a confirmed or in-progress session if the system is free
+// and the longer session still follows the system's rules. Customers can extend free
+// sessions and sessions paid with credits, which are charged for the extra slots; staff
+// extensions are collected in person.
+func (s *Service) ExtendSession(ctx context.Context, id uuid.UUID, minutes int) (values.Session, *errLib.CommonError) {
+	userID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return values.Session{}, err
+	}
+	isStaff, err := contextUtils.IsStaff(ctx)
+	if err != nil {
+		return values.Session{}, err
+	}
+
+	var session values.Session
+	err = txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		r := s.repo.WithTx(tx)
+
+		row, err := r.LockSession(ctx, id)
+		if err != nil {
+			return err
+		}
+		if !isStaff && row.CustomerID != userID {
+			return errLib.New("Session not found", http.StatusNotFound)
+		}
+
+		system, err := r.GetSystem(ctx, row.SystemID)
+		if err != nil {
+			return err
+		}
+		loc := timezone.ForLocation(ctx, tx, system.LocationID)
+
+		newEnd := row.EndTime.Add(time.Duration(minutes) * time.Minute)
+		if !newEnd.After(time.Now()) {
+			return errLib.New("Sessions that have ended cannot be extended", http.StatusBadRequest)
+		}
+		if _, err = validateLength(system.Rules, newEnd.Sub(row.StartTime)); err != nil {
+			return err
+		}
+		if err = validateHours(system.Rules, row.StartTime, newEnd, loc); err != nil {
+			return err
+		}
+		addedSlots := int32(minutes) / system.SlotMinutes
+
+		params := db.ExtendSessionParams{ID: id, EndTime: newEnd}
+		if isStaff {
+			params.AddedAmountCents = addedSlots * system.PricePerSlotCents
+		} else if system.PricePerSlotCents > 0 || row.PaymentMethod != string(values.PaymentFree) {
+			if row.PaymentMethod != string(values.PaymentCredits) || system.CreditsPerSlot == nil {
+				return errLib.New("Ask the front desk to extend a paid session", http.StatusBadRequest)
+			}
+			params.AddedCredits = addedSlots * *system.CreditsPerSlot
+		}
+
+		if err = s.bookingService.Reserve(ctx, tx, bookingValues.Request{
+			ResourceType: bookingValues.ResourcePlaygroundSystem,
+			ResourceID:   row.SystemID,
+			ActivityType: bookingValues.ActivityPlaygroundSession,
+			ActivityID:   id,
+			StartAt:      row.EndTime,
+			EndAt:        newEnd,
+		}); err != nil {
+			return err
+		}
+
+		if err = r.ExtendSession(ctx, params); err != nil {
+			return err
+		}
+
+		if params.AddedCredits > 0 {
+			if err = s.chargeCredits(ctx, tx, row.CustomerID, params.AddedCredits, loc, userValues.PlaygroundSessionCreditReference(id),
+				fmt.Sprintf("Playground session extension (%s)", id)); err != nil {
+				return err
+			}
+		}
+
+		session, err = r.GetSession(ctx, id)
+		if err != nil || !isStaff {
+			return err
+		}
+		return s.staffActivityLogsService.InsertStaffActivity(ctx, tx, userID,
+			fmt.Sprintf("Extended playground session of %s %s on %s by %d minutes", session.CustomerFirstName,
+				session.CustomerLastName, session.SystemName, minutes))
+	})
+	if err != nil {
+		return values.Session{}, err
+	}
+	return session, nil
+}
+
+// StartSession checks a customer in for their booked session, recording when they
+// actually started.
+func (s *Service) StartSession(ctx context.Context, id uuid.UUID) (values.Session, *errLib.CommonError) {
+	return s.transition(ctx, id, func(r *repo.Repository, now time.Time) *errLib.CommonError {
+		return r.StartSession(ctx, id, now)
+	}, func(v values.Session) string {
+		return fmt.Sprintf("Started playground session of %s %s on %s", v.CustomerFirstName, v.CustomerLastName, v.SystemName)
+	})
+}
+
+// StopSession ends a session in progress, recording when the customer actually stopped.
+func (s *Service) StopSession(ctx context.Context, id uuid.UUID) (values.Session, *errLib.CommonError) {
+	return s.transition(ctx, id, func(r *repo.Repository, now time.Time) *errLib.CommonError {
+		return r.CompleteSession(ctx, id, now)
+	}, func(v values.Session) string {
+		return fmt.Sprintf("Stopped playground session of %s %s on %s after %d minutes", v.CustomerFirstName,
+			v.CustomerLastName, v.SystemName, int(v.ActualEndAt.Sub(*v.ActualStartAt).Minutes()))
+	})
+}
+
+func (s *Service) transition(ctx context.Context, id uuid.UUID, apply func(*repo.Repository, time.Time) *errLib.CommonError, describe func(values.Session) string) (values.Session, *errLib.CommonError) {
+	staffID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return values.Session{}, err
+	}
+
+	var session values.Session
+	err = txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		r := s.repo.WithTx(tx)
+
+		if err := apply(r, time.Now()); err != nil {
+			return err
+		}
+
+		var err *errLib.CommonError
+		if session, err = r.GetSession(ctx, id); err != nil {
+			return err
+		}
+		return s.staffActivityLogsService.InsertStaffActivity(ctx, tx, staffID, describe(session))
+	})
+	if err != nil {
+		return values.Session{}, err
+	}
+	return session, nil
+}
+
+// WalkIn starts a session now for a customer at the front desk. It follows the system's
+// length and opening hours but not the slot grid, and is paid in person.
+func (s *Service) WalkIn(ctx context.Context, v values.WalkInValue) (values.Session, *errLib.CommonError) {
+	staffID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return values.Session{}, err
+	}
+
+	var session values.Session
+	err = txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		r := s.repo.WithTx(tx)
+
+		if _, err := r.ExpireStaleHolds(ctx, v.SystemID); err != nil {
+			return err
+		}
+
+		system, err := r.GetSystem(ctx, v.SystemID)
+		if err != nil {
+			return err
+		}
+		loc := timezone.ForLocation(ctx, tx, system.LocationID)
+
+		now := time.Now().Truncate(time.Minute)
+		end := now.Add(time.Duration(v.Minutes) * time.Minute)
+		slots, err := validateLength(system.Rules, end.Sub(now))
+		if err != nil {
+			return err
+		}
+		if err = validateHours(system.Rules, now, end, loc); err != nil {
+			return err
+		}
+
+		if err = s.bookingService.Reserve(ctx, tx, bookingValues.Request{
+			ResourceType: bookingValues.ResourcePlaygroundSystem,
+			ResourceID:   v.SystemID,
+			ActivityType: bookingValues.ActivityPlaygroundSession,
+			StartAt:      now,
+			EndAt:        end,
+		}); err != nil {
+			return err
+		}
+
+		session, err = r.CreateSession(ctx, values.CreateSessionValue{
+			SystemID:      v.SystemID,
+			CustomerID:    v.CustomerID,
+			StartTime:     now,
+			EndTime:       end,
+			Status:        values.StatusInProgress,
+			PaymentMethod: values.PaymentInPerson,
+			AmountCents:   slots * system.PricePerSlotCents,
+			ActualStartAt: &now,
+		})
+		if err != nil {
+			return err
+		}
+
+		return s.staffActivityLogsService.InsertStaffActivity(ctx, tx, staffID,
+			fmt.Sprintf("Started %d-minute walk-in playground session for %s %s on %s", v.Minutes,
+				session.CustomerFirstName, session.CustomerLastName, session.SystemName))
+	})
+	if err != nil {
+		return values.Session{}, err
+	}
+	return session, nil
+}
+
+func (s *Service) chargeCredits(ctx context.Context, tx *sql.Tx, customerID uuid.UUID, cost int32, loc *time.Location, reference, description string) *errLib.CommonError {
+	credits := s.creditRepo.WithTx(tx)
+
+	hasSufficient, err := credits.HasSufficientCredits(ctx, customerID, cost)
+	if err != nil {
+		return err
+	}
+	if !hasSufficient {
+		return errLib.New("Insufficient credits", http.StatusBadRequest)
+	}
+
+	// Weekly limits reset at Monday midnight in the system's facility timezone
+	weekStart := timezone.WeekStart(time.Now(), loc)
+
+	canUseCredits, err := credits.CanUseCreditsWithinWeeklyLimit(ctx, customerID, cost, weekStart)
+	if err != nil {
+		return err
+	}
+	if !canUseCredits {
+		return errLib.New("Weekly credit limit exceeded", http.StatusBadRequest)
+	}
+
+	if err = credits.DeductCredits(ctx, customerID, cost, reference, description); err != nil {
+		return err
+	}
+
+	if err = credits.LogCreditTransaction(ctx, customerID, -cost, dbUser.CreditTransactionTypePlaygroundSession, nil, description); err != nil {
+		log.Printf("Failed to log credit transaction: %v", err)
+	}
+
+	if err = credits.UpdateWeeklyUsage(ctx, customerID, cost, weekStart); err != nil {
+		log.Printf("Failed to update weekly usage tracking: %v", err)
+	}
+	return nil
+}
+
+func (s *Service) refundCredits(ctx context.Context, tx *sql.Tx, session values.Session) *errLib.CommonError {
+	credits := s.creditRepo.WithTx(tx)
+
+	description := fmt.Sprintf("Refund for canceled playground session (%s)", session.ID)
+	refunded, err := credits.RefundCredits(ctx, session.CustomerID, session.CreditsUsed,
+		userValues.PlaygroundSessionCreditReference(session.ID), description)
+	if err != nil {
+		return err
+	}
+	if refunded == 0 {
+		return nil // refunded already
+	}
+
+	if err := credits.LogCreditTransaction(ctx, session.CustomerID, refunded, dbUser.CreditTransactionTypeRefund, nil, description); err != nil {
+		log.Printf("Failed to log credit refund transaction: %v", err)
+	}
+
+	// Give the credits back to the week they were spent in
+	spentAt := session.CreatedAt
+	if session.PaidAt != nil {
+		spentAt = *session.PaidAt
+	}
+	weekStart := timezone.WeekStart(spentAt, timezone.ForLocation(ctx, tx, session.LocationID))
+	if err := credits.ReduceWeeklyUsage(ctx, session.CustomerID, refunded, weekStart); err != nil {
+		log.Printf("Failed to reduce weekly usage tracking: %v", err)
+	}
+	return nil
 }
 
 // GetSessions retrieves all sessions in the playground domain.
@@ -29,6 +644,12 @@ func (s *Service) GetSessions(ctx context.Context) ([]values.Session, *errLib.Co
 	return s.repo.GetSessions(ctx)
 }
 
+// GetMyUpcomingSessions lists the customer's held, confirmed and in-progress sessions
+// that have not ended.
+func (s *Service) GetMyUpcomingSessions(ctx context.Context, customerID uuid.UUID) ([]values.Session, *errLib.CommonError) {
+	return s.repo.GetCustomerUpcomingSessions(ctx, customerID, time.Now())
+}
+
 // GetSession retrieves a specific session by its ID.
 func (s *Service) GetSession(ctx context.Context, id uuid.UUID) (values.Session, *errLib.CommonError) {
 	return s.repo.GetSession(ctx, id)
@@ -41,6 +662,9 @@ func (s *Service) DeleteSession(ctx context.Context, id uuid.UUID) *errLib.Commo
 
 // CreateSystem creates a new system entry.
 func (s *Service) CreateSystem(ctx context.Context, v values.CreateSystemValue) (values.System, *errLib.CommonError) {
+	if err := validateRules(v.Rules); err != nil {
+		return values.System{}, err
+	}
 	return s.repo.CreateSystem(ctx, v)
 }
 
@@ -49,8 +673,11 @@ func (s *Service) GetSystems(ctx context.Context) ([]values.System, *errLib.Comm
 	return s.repo.GetSystems(ctx)
 }
 
-// UpdateSystem updates a system by ID.
+// UpdateSystem updates a system by ID. Existing sessions keep the price they were booked at.
 func (s *Service) UpdateSystem(ctx context.Context, v values.UpdateSystemValue) (values.System, *errLib.CommonError) {
+	if err := validateRules(v.Rules); err != nil {
+		return values.System{}, err
+	}
 	return s.repo.UpdateSystem(ctx, v)
 }
 
@@ -58,3 +685,73 @@ func (s *Service) UpdateSystem(ctx context.Context, v values.UpdateSystemValue)
 func (s *Service) DeleteSystem(ctx context.Context, id uuid.UUID) *errLib.CommonError {
 	return s.repo.DeleteSystem(ctx, id)
 }
+
+// validateSession checks a booking against the system's rules and returns how many
+// slots it spans. Bookings start in the future, on the slot grid counted from opening.
+func validateSession(rules values.Rules, start, end time.Time, loc *time.Location, now time.Time) (int32, *errLib.CommonError) {
+	if !end.After(start) {
+		return 0, errLib.New("end_time must be after start_time", http.StatusBadRequest)
+	}
+	if !start.After(now) {
+		return 0, errLib.New("Sessions must start in the future", http.StatusBadRequest)
+	}
+
+	slots, err := validateLength(rules, end.Sub(start))
+	if err != nil {
+		return 0, err
+	}
+	if err = validateHours(rules, start, end, loc); err != nil {
+		return 0, err
+	}
+
+	slot := time.Duration(rules.SlotMinutes) * time.Minute
+	if start.Sub(openingOn(rules, start, loc))%slot != 0 {
+		return 0, errLib.New(fmt.Sprintf("Sessions start every %d minutes from opening", rules.SlotMinutes), http.StatusBadRequest)
+	}
+	return slots, nil
+}
+
+// validateLength checks a session lasts whole slots, up to the system's maximum, and
+// returns how many.
+func validateLength(rules values.Rules, d time.Duration) (int32, *errLib.CommonError) {
+	slot := time.Duration(rules.SlotMinutes) * time.Minute
+	slots := int32(d / slot)
+	if d%slot != 0 || slots < 1 || slots > rules.MaxSlots {
+		return 0, errLib.New(fmt.Sprintf("Sessions last a multiple of %d minutes, up to %d minutes",
+			rules.SlotMinutes, rules.SlotMinutes*rules.MaxSlots), http.StatusBadRequest)
+	}
+	return slots, nil
+}
+
+// validateHours checks [start, end) falls inside one day's opening hours in loc.
+func validateHours(rules values.Rules, start, end time.Time, loc *time.Location) *errLib.CommonError {
+	opens := openingOn(rules, start, loc)
+	closes := timezone.At(start.In(loc), rules.ClosesAt.Hour(), rules.ClosesAt.Minute(), 0, loc)
+	if start.Before(opens) || end.After(closes) {
+		return errLib.New(fmt.Sprintf("Sessions must be between %s and %s",
+			rules.OpensAt.Format("15:04"), rules.ClosesAt.Format("15:04")), http.StatusBadRequest)
+	}
+	return nil
+}
+
+func openingOn(rules values.Rules, t time.Time, loc *time.Location) time.Time {
+	return timezone.At(t.In(loc), rules.OpensAt.Hour(), rules.OpensAt.Minute(), 0, loc)
+}
+
+func validateRules(rules values.Rules) *errLib.CommonError {
+	switch {
+	case rules.SlotMinutes < 5 || rules.SlotMinutes > 240:
+		return errLib.New("slot_minutes must be between 5 and 240", http.StatusBadRequest)
+	case rules.MaxSlots < 1:
+		return errLib.New("max_slots must be at least 1", http.StatusBadRequest)
+	case !rules.ClosesAt.After(rules.OpensAt):
+		return errLib.New("closes_at must be after opens_at", http.StatusBadRequest)
+	case rules.MaxActiveBookings < 1:
+		return errLib.New("max_active_bookings must be at least 1", http.StatusBadRequest)
+	case rules.PricePerSlotCents < 0:
+		return errLib.New("price_per_slot_cents cannot be negative", http.StatusBadRequest)
+	case rules.CreditsPerSlot != nil && *rules.CreditsPerSlot <= 0:
+		return errLib.New("credits_per_slot must be greater than 0", http.StatusBadRequest)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package playground
+
+import (
+	"testing"
+	"time"
+
+	values "api/internal/domains/playground/values"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSession(t *testing.T) {
+	loc, err := time.LoadLocation("America/Toronto")
+	require.NoError(t, err)
+
+	rules := values.Rules{
+		SlotMinutes: 30,
+		MaxSlots:    4,
+		OpensAt:     time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC),
+		ClosesAt:    time.Date(0, 1, 1, 21, 0, 0, 0, time.UTC),
+	}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, loc)
+
+	t.Run("Counts slots in local time", func(t *testing.T) {
+		start := time.Date(2026, 3, 2, 10, 30, 0, 0, loc)
+
+		slots, err := validateSession(rules, start.UTC(), start.Add(90*time.Minute).UTC(), loc, now)
+
+		require.Nil(t, err)
+		assert.Equal(t, int32(3), slots)
+	})
+
+	cases := map[string]struct{ start, end time.Time }{
+		"Off the slot grid":   {time.Date(2026, 3, 2, 10, 15, 0, 0, loc), time.Date(2026, 3, 2, 10, 45, 0, 0, loc)},
+		"Partial slot":        {time.Date(2026, 3, 2, 10, 0, 0, 0, loc), time.Date(2026, 3, 2, 10, 40, 0, 0, loc)},
+		"Longer than allowed": {time.Date(2026, 3, 2, 10, 0, 0, 0, loc), time.Date(2026, 3, 2, 12, 30, 0, 0, loc)},
+		"Past closing":        {time.Date(2026, 3, 2, 20, 30, 0, 0, loc), time.Date(2026, 3, 2, 21, 30, 0, 0, loc)},
+		"In the past":         {time.Date(2026, 2, 28, 10, 0, 0, 0, loc), time.Date(2026, 2, 28, 10, 30, 0, 0, loc)},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := validateSession(rules, c.start, c.end, loc, now)
+
+			require.NotNil(t, err)
+			assert.Equal(t, 400, err.HTTPCode)
+		})
+	}
+}
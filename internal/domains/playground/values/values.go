@@ -6,12 +6,58 @@ import (
 	"github.com/google/uuid"
 )
 
+// PaymentMethod records how a session was paid for.
+type PaymentMethod string
+
+const (
+	PaymentFree     PaymentMethod = "free"
+	PaymentStripe   PaymentMethod = "stripe"
+	PaymentCredits  PaymentMethod = "credits"
+	PaymentInPerson PaymentMethod = "in_person"
+)
+
+// Session statuses. A card payment that completes after its hold lapsed and the
+// session could no longer be booked leaves it refund_due until the payment is refunded.
+const (
+	StatusHeld       = "held"
+	StatusConfirmed  = "confirmed"
+	StatusInProgress = "in_progress"
+	StatusCompleted  = "completed"
+	StatusCanceled   = "canceled"
+	StatusExpired    = "expired"
+	StatusRefundDue  = "refund_due"
+	StatusRefunded   = "refunded"
+)
+
 // CreateSessionValue represents the data required to create a new session.
+// Status and PaymentMethod default to a confirmed, free session.
 type CreateSessionValue struct {
+	SystemID      uuid.UUID
+	CustomerID    uuid.UUID
+	StartTime     time.Time
+	EndTime       time.Time
+	Status        string
+	PaymentMethod PaymentMethod
+	AmountCents   int32
+	CreditsUsed   int32
+	HoldExpiresAt *time.Time
+	ActualStartAt *time.Time
+}
+
+// BookSessionValue is a customer's request to book a system, paid by card or credits.
+type BookSessionValue struct {
+	SystemID      uuid.UUID
+	CustomerID    uuid.UUID
+	StartTime     time.Time
+	EndTime       time.Time
+	PaymentMethod PaymentMethod
+}
+
+// WalkInValue starts a session at the front desk for a customer who is already there.
+type WalkInValue struct {
 	SystemID   uuid.UUID
 	CustomerID uuid.UUID
-	StartTime  time.Time
-	EndTime    time.Time
+	Minutes    int
 }
 
 // Session represents a session in the playground domain.
@@ -19,30 +65,58 @@ type Session struct {
 	ID                uuid.UUID
 	SystemID          uuid.UUID
 	SystemName        string
+	LocationID        uuid.UUID
 	CustomerID        uuid.UUID
 	CustomerFirstName string
 	CustomerLastName  string
 	StartTime         time.Time
 	EndTime           time.Time
+	Status            string
+	PaymentMethod     PaymentMethod
+	AmountCents       int32
+	CreditsUsed       int32
+	Currency          string
+	HoldExpiresAt     *time.Time
+	PaidAt            *time.Time
+	ActualStartAt     *time.Time
+	ActualEndAt       *time.Time
+	CanceledAt        *time.Time
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
 }
 
+// Rules are the booking rules of a playground system. Sessions start on the slot grid
+// counted from OpensAt, last a whole number of slots up to MaxSlots, and fit inside
+// the opening hours in the system location's timezone.
+type Rules struct {
+	LocationID        uuid.UUID
+	SlotMinutes       int32
+	MaxSlots          int32
+	OpensAt           time.Time
+	ClosesAt          time.Time
+	MaxActiveBookings int32
+	PricePerSlotCents int32
+	CreditsPerSlot    *int32
+}
+
 // CreateSystemValue represents the data required to create a playground system.
 type CreateSystemValue struct {
 	Name string
+	Rules
 }
 
 // UpdateSystemValue represents the data required to update a playground system.
 type UpdateSystemValue struct {
 	ID   uuid.UUID
 	Name string
+	Rules
 }
 
 // System represents a playground system entry.
 type System struct {
-	ID        uuid.UUID
-	Name      string
+	ID   uuid.UUID
+	Name string
+	Rules
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -1,25 +1,30 @@
 package practice
 
 import (
+	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
 	values "api/internal/domains/practice/values"
 	errLib "api/internal/libs/errors"
 	"api/internal/libs/validators"
+	"api/utils/recurrence"
 
 	"github.com/google/uuid"
 )
 
 type RequestDto struct {
-	TeamID           uuid.UUID  `json:"team_id" validate:"required"`
-	StartTime        time.Time  `json:"start_time" validate:"required"`
-	EndTime          *time.Time `json:"end_time"`
-	LocationID       uuid.UUID  `json:"location_id" validate:"required"`
-	CourtID          uuid.UUID  `json:"court_id" validate:"required"`
-	Status           string     `json:"status" validate:"oneof=scheduled completed canceled"`
-	BookedBy         *uuid.UUID `json:"booked_by"`
-	SkipNotification *bool      `json:"skip_notification"` // Skip auto-notification on update
+	TeamID              uuid.UUID  `json:"team_id" validate:"required"`
+	StartTime           time.Time  `json:"start_time" validate:"required"`
+	EndTime             *time.Time `json:"end_time"`
+	LocationID          uuid.UUID  `json:"location_id" validate:"required"`
+	CourtID             uuid.UUID  `json:"court_id" validate:"required"`
+	Status              string     `json:"status" validate:"oneof=scheduled completed canceled"`
+	BookedBy            *uuid.UUID `json:"booked_by"`
+	SkipNotification    *bool      `json:"skip_notification"`     // Skip auto-notification on update
+	OverrideReason      string     `json:"override_reason"`       // Admin only: book over conflicting events or games
+	StaffOverrideReason string     `json:"staff_override_reason"` // Admin only: book the coach over schedule conflicts
 }
 
 func (dto *RequestDto) ToCreateValue() (values.CreatePracticeValue, *errLib.CommonError) {
@@ -34,6 +39,9 @@ func (dto *RequestDto) ToCreateValue() (values.CreatePracticeValue, *errLib.Comm
 		CourtID:    dto.CourtID,
 		Status:     dto.Status,
 		BookedBy:   dto.BookedBy,
+
+		OverrideReason:      dto.OverrideReason,
+		StaffOverrideReason: dto.StaffOverrideReason,
 	}, nil
 }
 
@@ -63,6 +71,9 @@ func (dto *RequestDto) ToUpdateValue(idStr string) (values.UpdatePracticeValue,
 			CourtID:    dto.CourtID,
 			Status:     dto.Status,
 			BookedBy:   dto.BookedBy,
+
+			OverrideReason:      dto.OverrideReason,
+			StaffOverrideReason: dto.StaffOverrideReason,
 		},
 	}, nil
 }
@@ -70,13 +81,15 @@ func (dto *RequestDto) ToUpdateValue(idStr string) (values.UpdatePracticeValue,
 type RecurrenceRequestDto struct {
 	Day               string    `json:"day"`
 	RecurrenceStartAt string    `json:"recurrence_start_at" validate:"required"`
-	RecurrenceEndAt   string    `json:"recurrence_end_at" validate:"required"`
+	RecurrenceEndAt   string    `json:"recurrence_end_at"`
 	PracticeStartTime string    `json:"practice_start_at" validate:"required"`
 	PracticeEndTime   string    `json:"practice_end_at" validate:"required"`
 	TeamID            uuid.UUID `json:"team_id" validate:"required"`
 	LocationID        uuid.UUID `json:"location_id" validate:"required"`
 	CourtID           uuid.UUID `json:"court_id" validate:"required"`
 	Status            string    `json:"status" validate:"oneof=scheduled completed canceled"`
+	RRule             string    `json:"rrule" example:"FREQ=WEEKLY;BYDAY=TU,TH"`
+	ExDates           []string  `json:"exdates" example:"[\"2025-12-23\"]"`
 }
 
 func (dto *RecurrenceRequestDto) ToRecurrenceValues() (values.RecurrenceValues, *errLib.CommonError) {
@@ -87,10 +100,25 @@ func (dto *RecurrenceRequestDto) ToRecurrenceValues() (values.RecurrenceValues,
 	if err != nil {
 		return values.RecurrenceValues{}, err
 	}
-	end, err := validators.ParseDateTime(dto.RecurrenceEndAt)
-	if err != nil {
-		return values.RecurrenceValues{}, err
+
+	var rule recurrence.Rule
+	if dto.RRule != "" {
+		parsed, parseErr := recurrence.Parse(dto.RRule)
+		if parseErr != nil {
+			return values.RecurrenceValues{}, errLib.New(fmt.Sprintf("Invalid rrule: %s", parseErr.Error()), http.StatusBadRequest)
+		}
+		rule = parsed
+	}
+
+	var end time.Time
+	if dto.RecurrenceEndAt != "" {
+		if end, err = validators.ParseDateTime(dto.RecurrenceEndAt); err != nil {
+			return values.RecurrenceValues{}, err
+		}
+	} else if rule.Count == 0 && rule.Until.IsZero() {
+		return values.RecurrenceValues{}, errLib.New("recurrence_end_at is required unless the rrule has COUNT or UNTIL", http.StatusBadRequest)
 	}
+
 	startTime, err := validators.ParseTime(dto.PracticeStartTime)
 	if err != nil {
 		return values.RecurrenceValues{}, err
@@ -99,16 +127,36 @@ func (dto *RecurrenceRequestDto) ToRecurrenceValues() (values.RecurrenceValues,
 	if err != nil {
 		return values.RecurrenceValues{}, err
 	}
-	weekday, err := validateWeekday(dto.Day)
-	if err != nil {
-		return values.RecurrenceValues{}, err
+
+	var weekday time.Weekday
+	if dto.RRule == "" || dto.Day != "" {
+		if weekday, err = validateWeekday(dto.Day); err != nil {
+			return values.RecurrenceValues{}, err
+		}
+	}
+
+	exDates := make([]time.Time, 0, len(dto.ExDates))
+	for _, str := range dto.ExDates {
+		date, err := validators.ParseDate(str)
+		if err != nil {
+			return values.RecurrenceValues{}, err
+		}
+		exDates = append(exDates, date)
+	}
+
+	rrule := ""
+	if dto.RRule != "" {
+		rrule = rule.String()
 	}
+
 	return values.RecurrenceValues{
 		DayOfWeek:       weekday,
 		FirstOccurrence: start,
 		LastOccurrence:  end,
 		StartTime:       startTime,
 		EndTime:         endTime,
+		RRule:           rrule,
+		ExDates:         exDates,
 	}, nil
 }
 
@@ -23,6 +23,7 @@ type ResponseDto struct {
 	BookedByName string      `json:"booked_by_name,omitempty"`
 	CreatedAt    *time.Time  `json:"created_at,omitempty"`
 	UpdatedAt    *time.Time  `json:"updated_at,omitempty"`
+	BlackoutID   *uuid.UUID  `json:"blackout_id,omitempty"`
 }
 
 func NewResponse(v values.ReadPracticeValue) ResponseDto {
@@ -42,5 +43,6 @@ func NewResponse(v values.ReadPracticeValue) ResponseDto {
 		BookedByName: v.BookedByName,
 		CreatedAt:    v.CreatedAt,
 		UpdatedAt:    v.UpdatedAt,
+		BlackoutID:   v.BlackoutID,
 	}
 }
\ No newline at end of file
@@ -99,6 +99,7 @@ func (r *Repository) Create(ctx context.Context, val values.CreatePracticeValue)
 		LocationID: val.LocationID,
 		Status:     toNullString(val.Status),
 		BookedBy:   toNullUUID(val.BookedBy),
+		BlackoutID: uuid.NullUUID{UUID: val.BlackoutID, Valid: val.BlackoutID != uuid.Nil},
 	}
 	if err := r.Queries.CreatePractice(ctx, params); err != nil {
 		// Check if it's an exclusion constraint violation (double booking within practices)
@@ -173,6 +174,7 @@ func mapDbPracticeToValue(row db.GetPracticeByIDRow) values.ReadPracticeValue {
 		BookedByName: unwrapInterfaceToString(row.BookedByName),
 		CreatedAt:    unwrapNullTime(row.CreatedAt),
 		UpdatedAt:    unwrapNullTime(row.UpdatedAt),
+		BlackoutID:   unwrapNullUUID(row.BlackoutID),
 	}
 }
 
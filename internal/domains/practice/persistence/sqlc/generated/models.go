@@ -455,6 +455,7 @@ type PracticePractice struct {
 	CreatedAt  sql.NullTime   `json:"created_at"`
 	UpdatedAt  sql.NullTime   `json:"updated_at"`
 	BookedBy   uuid.NullUUID  `json:"booked_by"`
+	BlackoutID uuid.NullUUID  `json:"blackout_id"`
 }
 
 type ProgramCustomerEnrollment struct {
@@ -15,9 +15,9 @@ import (
 
 const createPractice = `-- name: CreatePractice :exec
 INSERT INTO practice.practices (
-    team_id, start_time, end_time, court_id, location_id, status, booked_by
+    team_id, start_time, end_time, court_id, location_id, status, booked_by, blackout_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
+    $1, $2, $3, $4, $5, $6, $7, $8
 )
 `
 
@@ -29,6 +29,7 @@ type CreatePracticeParams struct {
 	LocationID uuid.UUID      `json:"location_id"`
 	Status     sql.NullString `json:"status"`
 	BookedBy   uuid.NullUUID  `json:"booked_by"`
+	BlackoutID uuid.NullUUID  `json:"blackout_id"`
 }
 
 func (q *Queries) CreatePractice(ctx context.Context, arg CreatePracticeParams) error {
@@ -40,6 +41,7 @@ func (q *Queries) CreatePractice(ctx context.Context, arg CreatePracticeParams)
 		arg.LocationID,
 		arg.Status,
 		arg.BookedBy,
+		arg.BlackoutID,
 	)
 	return err
 }
@@ -68,7 +70,8 @@ SELECT p.id,
        p.booked_by,
        u.first_name || ' ' || u.last_name AS booked_by_name,
        p.created_at,
-       p.updated_at
+       p.updated_at,
+       p.blackout_id
 FROM practice.practices p
          JOIN athletic.teams t ON p.team_id = t.id
          JOIN location.locations l ON p.location_id = l.id
@@ -93,6 +96,7 @@ type GetPracticeByIDRow struct {
 	BookedByName interface{}    `json:"booked_by_name"`
 	CreatedAt    sql.NullTime   `json:"created_at"`
 	UpdatedAt    sql.NullTime   `json:"updated_at"`
+	BlackoutID   uuid.NullUUID  `json:"blackout_id"`
 }
 
 func (q *Queries) GetPracticeByID(ctx context.Context, id uuid.UUID) (GetPracticeByIDRow, error) {
@@ -114,6 +118,7 @@ func (q *Queries) GetPracticeByID(ctx context.Context, id uuid.UUID) (GetPractic
 		&i.BookedByName,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.BlackoutID,
 	)
 	return i, err
 }
@@ -133,7 +138,8 @@ SELECT p.id,
        p.booked_by,
        u.first_name || ' ' || u.last_name AS booked_by_name,
        p.created_at,
-       p.updated_at
+       p.updated_at,
+       p.blackout_id
 FROM practice.practices p
          JOIN athletic.teams t ON p.team_id = t.id
          JOIN location.locations l ON p.location_id = l.id
@@ -169,6 +175,7 @@ type ListPracticesRow struct {
 	BookedByName interface{}    `json:"booked_by_name"`
 	CreatedAt    sql.NullTime   `json:"created_at"`
 	UpdatedAt    sql.NullTime   `json:"updated_at"`
+	BlackoutID   uuid.NullUUID  `json:"blackout_id"`
 }
 
 func (q *Queries) ListPractices(ctx context.Context, arg ListPracticesParams) ([]ListPracticesRow, error) {
@@ -196,6 +203,7 @@ func (q *Queries) ListPractices(ctx context.Context, arg ListPracticesParams) ([
 			&i.BookedByName,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.BlackoutID,
 		); err != nil {
 			return nil, err
 		}
@@ -1,8 +1,8 @@
 -- name: CreatePractice :exec
 INSERT INTO practice.practices (
-    team_id, start_time, end_time, court_id, location_id, status, booked_by
+    team_id, start_time, end_time, court_id, location_id, status, booked_by, blackout_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
+    $1, $2, $3, $4, $5, $6, $7, $8
 );
 
 -- name: UpdatePractice :exec
@@ -35,7 +35,8 @@ SELECT p.id,
        p.booked_by,
        u.first_name || ' ' || u.last_name AS booked_by_name,
        p.created_at,
-       p.updated_at
+       p.updated_at,
+       p.blackout_id
 FROM practice.practices p
          JOIN athletic.teams t ON p.team_id = t.id
          JOIN location.locations l ON p.location_id = l.id
@@ -58,7 +59,8 @@ SELECT p.id,
        p.booked_by,
        u.first_name || ' ' || u.last_name AS booked_by_name,
        p.created_at,
-       p.updated_at
+       p.updated_at,
+       p.blackout_id
 FROM practice.practices p
          JOIN athletic.teams t ON p.team_id = t.id
          JOIN location.locations l ON p.location_id = l.id
@@ -3,13 +3,20 @@ package practice
 import (
 	"api/internal/di"
 	staffActivityLogs "api/internal/domains/audit/staff_activity_logs/service"
+	bookingService "api/internal/domains/booking/service"
+	bookingValues "api/internal/domains/booking/values"
 	notificationService "api/internal/domains/notification/services"
+	locationRepo "api/internal/domains/location/persistence"
 	notificationValues "api/internal/domains/notification/values"
 	repo "api/internal/domains/practice/persistence"
 	values "api/internal/domains/practice/values"
+	staffAvailability "api/internal/domains/staff_availability/service"
+	staffAvailabilityValues "api/internal/domains/staff_availability/values"
 	errLib "api/internal/libs/errors"
 	contextUtils "api/utils/context"
 	txUtils "api/utils/db"
+	"api/utils/recurrence"
+	"api/utils/timezone"
 	"context"
 	"database/sql"
 	"fmt"
@@ -25,6 +32,9 @@ type Service struct {
 	repo                     *repo.Repository
 	staffActivityLogsService *staffActivityLogs.Service
 	notificationService      *notificationService.NotificationService
+	locationRepository       *locationRepo.Repository
+	bookingService           *bookingService.Service
+	availabilityService      *staffAvailability.Service
 	db                       *sql.DB
 }
 
@@ -33,6 +43,9 @@ func NewService(container *di.Container) *Service {
 		repo:                     repo.NewRepository(container),
 		staffActivityLogsService: staffActivityLogs.NewService(container),
 		notificationService:      notificationService.NewNotificationService(container),
+		locationRepository:       locationRepo.NewLocationRepository(container),
+		bookingService:           bookingService.NewService(container),
+		availabilityService:      staffAvailability.NewService(container),
 		db:                       container.DB,
 	}
 }
@@ -70,6 +83,12 @@ func (s *Service) lookupNames(ctx context.Context, teamID, locationID uuid.UUID)
 
 func (s *Service) CreatePractice(ctx context.Context, val values.CreatePracticeValue) *errLib.CommonError {
 	return s.executeInTx(ctx, func(r *repo.Repository) *errLib.CommonError {
+		if err := s.bookingService.Reserve(ctx, r.GetTx(), courtBooking(uuid.Nil, val)); err != nil {
+			return err
+		}
+		if err := s.availabilityService.Check(ctx, r.GetTx(), staffSchedule(uuid.Nil, val)); err != nil {
+			return err
+		}
 		if err := r.Create(ctx, val); err != nil {
 			return err
 		}
@@ -79,10 +98,7 @@ func (s *Service) CreatePractice(ctx context.Context, val values.CreatePracticeV
 		}
 		
 		// Log the activity with human-readable format
-		loc, _ := time.LoadLocation("America/Edmonton")
-		if loc == nil {
-			loc = time.FixedZone("MST", -7*60*60)
-		}
+		loc := timezone.ForLocation(ctx, r.GetTx(), val.LocationID)
 		teamName, locationName := s.lookupNames(ctx, val.TeamID, val.LocationID)
 		activityDesc := fmt.Sprintf("Created practice for %s at %s on %s",
 			teamName,
@@ -105,13 +121,8 @@ func (s *Service) sendPracticeNotification(ctx context.Context, practice values.
 	startTime := "TBD"
 	startTimeISO := ""
 	if !practice.StartTime.IsZero() {
-		// The incoming time already has the correct timezone from the request
-		// Use America/Edmonton for Calgary timezone (handles MST/MDT automatically)
-		loc, err := time.LoadLocation("America/Edmonton")
-		if err != nil {
-			// Fallback: use fixed MST offset (UTC-7) if timezone data unavailable
-			loc = time.FixedZone("MST", -7*60*60)
-		}
+		// Format in the facility's timezone (handles DST automatically)
+		loc := timezone.ForLocation(ctx, s.db, practice.LocationID)
 		localTime := practice.StartTime.In(loc)
 
 		startTime = localTime.Format("January 2, 2006 at 3:04 PM")
@@ -135,10 +146,7 @@ func (s *Service) sendPracticeNotification(ctx context.Context, practice values.
 }
 
 func (s *Service) sendPracticeUpdateNotification(ctx context.Context, existing values.ReadPracticeValue, updated values.UpdatePracticeValue) {
-	loc, _ := time.LoadLocation("America/Edmonton")
-	if loc == nil {
-		loc = time.FixedZone("MST", -7*60*60)
-	}
+	loc := timezone.ForLocation(ctx, s.db, updated.LocationID)
 
 	// Build change message
 	var changes []string
@@ -198,6 +206,12 @@ func (s *Service) UpdatePractice(ctx context.Context, val values.UpdatePracticeV
 	}
 
 	updateErr := s.executeInTx(ctx, func(r *repo.Repository) *errLib.CommonError {
+		if err := s.bookingService.Reserve(ctx, r.GetTx(), courtBooking(val.ID, val.CreatePracticeValue)); err != nil {
+			return err
+		}
+		if err := s.availabilityService.Check(ctx, r.GetTx(), staffSchedule(val.ID, val.CreatePracticeValue)); err != nil {
+			return err
+		}
 		if err := r.Update(ctx, val); err != nil {
 			return err
 		}
@@ -205,10 +219,7 @@ func (s *Service) UpdatePractice(ctx context.Context, val values.UpdatePracticeV
 		if err != nil {
 			return err
 		}
-		loc, _ := time.LoadLocation("America/Edmonton")
-		if loc == nil {
-			loc = time.FixedZone("MST", -7*60*60)
-		}
+		loc := timezone.ForLocation(ctx, r.GetTx(), val.LocationID)
 		teamName, locationName := s.lookupNames(ctx, val.TeamID, val.LocationID)
 		activityDesc := fmt.Sprintf("Updated practice for %s at %s on %s",
 			teamName,
@@ -243,10 +254,7 @@ func (s *Service) DeletePractice(ctx context.Context, id uuid.UUID) *errLib.Comm
 
 	teamName, locationName := s.lookupNames(ctx, practice.TeamID, practice.LocationID)
 
-	loc, _ := time.LoadLocation("America/Edmonton")
-	if loc == nil {
-		loc = time.FixedZone("MST", -7*60*60)
-	}
+	loc := timezone.ForLocation(ctx, s.db, practice.LocationID)
 
 	return s.executeInTx(ctx, func(r *repo.Repository) *errLib.CommonError {
 		if err := r.Delete(ctx, id); err != nil {
@@ -275,12 +283,27 @@ func (s *Service) GetPractices(ctx context.Context, teamID uuid.UUID, limit, off
 }
 
 func (s *Service) CreateRecurringPractices(ctx context.Context, rec values.RecurrenceValues, base values.CreatePracticeValue) *errLib.CommonError {
-	practices, err := generatePracticesFromRecurrence(rec, base)
+	blackouts, err := s.getBlackouts(ctx, base.LocationID, rec.FirstOccurrence, rec.LastOccurrence)
+	if err != nil {
+		return err
+	}
+	practices, err := generatePracticesFromRecurrence(rec, blackouts, base, timezone.ForLocation(ctx, s.db, base.LocationID))
 	if err != nil {
 		return err
 	}
+	if len(practices) == 0 {
+		return errLib.New("the recurrence does not produce any practices", http.StatusBadRequest)
+	}
 	return s.executeInTx(ctx, func(r *repo.Repository) *errLib.CommonError {
 		for _, p := range practices {
+			p.OverrideReason = ""
+			p.StaffOverrideReason = ""
+			if err := s.bookingService.Reserve(ctx, r.GetTx(), courtBooking(uuid.Nil, p)); err != nil {
+				return err
+			}
+			if err := s.availabilityService.Check(ctx, r.GetTx(), staffSchedule(uuid.Nil, p)); err != nil {
+				return err
+			}
 			if err := r.Create(ctx, p); err != nil {
 				return err
 			}
@@ -293,36 +316,102 @@ func (s *Service) CreateRecurringPractices(ctx context.Context, rec values.Recur
 	})
 }
 
-func generatePracticesFromRecurrence(rec values.RecurrenceValues, base values.CreatePracticeValue) ([]values.CreatePracticeValue, *errLib.CommonError) {
-	const layout = "15:04:05Z07:00"
-	startTime, err := time.Parse(layout, rec.StartTime)
+// courtBooking describes a practice's court booking. Practices without an end time
+// hold the court for two hours and canceled practices hold nothing, matching the
+// database check.
+func courtBooking(practiceID uuid.UUID, val values.CreatePracticeValue) bookingValues.Request {
+	end := val.StartTime.Add(2 * time.Hour)
+	if val.EndTime != nil {
+		end = *val.EndTime
+	}
+	courtID := val.CourtID
+	if val.Status == "canceled" {
+		courtID = uuid.Nil
+	}
+	return bookingValues.Request{
+		ResourceType:   bookingValues.ResourceCourt,
+		ResourceID:     courtID,
+		ActivityType:   bookingValues.ActivityPractice,
+		ActivityID:     practiceID,
+		StartAt:        val.StartTime,
+		EndAt:          end,
+		OverrideReason: val.OverrideReason,
+	}
+}
+
+// staffSchedule describes the booking coach's time on a practice, held for the same
+// span as the court. Canceled practices and practices nobody booked are not checked.
+func staffSchedule(practiceID uuid.UUID, val values.CreatePracticeValue) staffAvailabilityValues.Request {
+	court := courtBooking(practiceID, val)
+	req := staffAvailabilityValues.Request{
+		ActivityType:   staffAvailabilityValues.ActivityPractice,
+		ActivityID:     practiceID,
+		LocationID:     val.LocationID,
+		StartAt:        court.StartAt,
+		EndAt:          court.EndAt,
+		OverrideReason: val.StaffOverrideReason,
+	}
+	if val.BookedBy != nil && val.Status != "canceled" {
+		req.StaffID = *val.BookedBy
+	}
+	return req
+}
+
+// getBlackouts loads the location's blackout dates overlapping the recurrence bounds.
+func (s *Service) getBlackouts(ctx context.Context, locationID uuid.UUID, first, last time.Time) ([]recurrence.Blackout, *errLib.CommonError) {
+	to := time.Time{}
+	if !last.IsZero() {
+		to = recurrence.Date(last)
+	}
+	dates, err := s.locationRepository.GetBlackoutDates(ctx, locationID, recurrence.Date(first), to)
+	if err != nil {
+		return nil, err
+	}
+	blackouts := make([]recurrence.Blackout, len(dates))
+	for i, date := range dates {
+		blackouts[i] = date.ToRecurrence()
+	}
+	return blackouts, nil
+}
+
+// generatePracticesFromRecurrence expands a recurrence rule into practices whose
+// start and end times are wall-clock times in the facility's timezone, so they do
+// not shift by an hour when DST begins or ends. Practices on a skip blackout are
+// dropped; those on a flag blackout keep the blackout's ID.
+func generatePracticesFromRecurrence(rec values.RecurrenceValues, blackouts []recurrence.Blackout, base values.CreatePracticeValue, loc *time.Location) ([]values.CreatePracticeValue, *errLib.CommonError) {
+	startHour, startMinute, _, startDays, err := timezone.ParseWallClock(rec.StartTime, rec.FirstOccurrence, loc)
 	if err != nil {
 		return nil, errLib.New("invalid start time", http.StatusBadRequest)
 	}
-	endTime, err := time.Parse(layout, rec.EndTime)
+	endHour, endMinute, _, _, err := timezone.ParseWallClock(rec.EndTime, rec.FirstOccurrence, loc)
 	if err != nil {
 		return nil, errLib.New("invalid end time", http.StatusBadRequest)
 	}
-	if endTime.Before(startTime) {
-		endTime = endTime.AddDate(0, 0, 1)
+	rule := recurrence.WeeklyOn(rec.DayOfWeek)
+	if rec.RRule != "" {
+		if rule, err = recurrence.Parse(rec.RRule); err != nil {
+			return nil, errLib.New(fmt.Sprintf("invalid rrule: %s", err.Error()), http.StatusBadRequest)
+		}
 	}
-	adjust := func(date time.Time, t time.Time) time.Time {
-		return time.Date(date.Year(), date.Month(), date.Day(), t.Hour(), t.Minute(), 0, 0, date.Location())
+	dates, err := rule.Expand(rec.FirstOccurrence, rec.LastOccurrence, rec.ExDates)
+	if err != nil {
+		return nil, errLib.New(fmt.Sprintf("invalid recurrence: %s", err.Error()), http.StatusBadRequest)
+	}
+	for i := range dates {
+		dates[i] = dates[i].AddDate(0, 0, startDays)
 	}
 	var practices []values.CreatePracticeValue
-	for date := rec.FirstOccurrence; !date.After(rec.LastOccurrence); date = date.AddDate(0, 0, 1) {
-		if date.Weekday() == rec.DayOfWeek {
-			st := adjust(date, startTime)
-			et := adjust(date, endTime)
-			if et.Before(st) {
-				et = et.AddDate(0, 0, 1)
-			}
-			p := base
-			p.StartTime = st
-			p.EndTime = &et
-			practices = append(practices, p)
-			date = date.AddDate(0, 0, 6)
+	for _, occurrence := range recurrence.ApplyBlackouts(dates, blackouts) {
+		st := timezone.At(occurrence.Date, startHour, startMinute, 0, loc)
+		et := timezone.At(occurrence.Date, endHour, endMinute, 0, loc)
+		if !et.After(st) {
+			et = timezone.At(occurrence.Date.AddDate(0, 0, 1), endHour, endMinute, 0, loc)
 		}
+		p := base
+		p.StartTime = st
+		p.EndTime = &et
+		p.BlackoutID = occurrence.BlackoutID
+		practices = append(practices, p)
 	}
 	return practices, nil
 }
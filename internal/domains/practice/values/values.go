@@ -14,6 +14,12 @@ type CreatePracticeValue struct {
 	CourtID    uuid.UUID
 	Status     string
 	BookedBy   *uuid.UUID
+	// BlackoutID is set when the practice falls on a flag-type blackout date.
+	BlackoutID uuid.UUID
+	// OverrideReason lets an admin book the court over an event or game.
+	OverrideReason string
+	// StaffOverrideReason lets an admin book the coach over schedule conflicts.
+	StaffOverrideReason string
 }
 
 type UpdatePracticeValue struct {
@@ -38,6 +44,7 @@ type ReadPracticeValue struct {
 	BookedByName   string
 	CreatedAt      *time.Time
 	UpdatedAt      *time.Time
+	BlackoutID     *uuid.UUID
 }
 
 type RecurrenceValues struct {
@@ -46,4 +53,7 @@ type RecurrenceValues struct {
 	LastOccurrence  time.Time
 	StartTime       string
 	EndTime         string
+	// RRule is an RFC 5545 rule; when empty the practice repeats weekly on DayOfWeek.
+	RRule   string
+	ExDates []time.Time
 }
@@ -0,0 +1,78 @@
+package privacy
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	values "api/internal/domains/privacy/values"
+	errLib "api/internal/libs/errors"
+	"api/internal/libs/validators"
+
+	"github.com/google/uuid"
+)
+
+// ErasureRequestDto asks for the caller's personal data to be erased.
+type ErasureRequestDto struct {
+	Reason *string `json:"reason,omitempty" validate:"omitempty,max=1000" example:"I no longer use Rise"`
+}
+
+func (dto *ErasureRequestDto) Validate() *errLib.CommonError {
+	return validators.ValidateDto(dto)
+}
+
+// ReviewRequestDto approves or rejects an erasure. Rejections need notes explaining why,
+// e.g. an unpaid balance that has to be settled first.
+type ReviewRequestDto struct {
+	Notes *string `json:"notes,omitempty" validate:"omitempty,max=1000" example:"Outstanding balance must be paid first"`
+}
+
+func (dto *ReviewRequestDto) ToValues(requestID, reviewerID uuid.UUID, approve bool) (values.ReviewValues, *errLib.CommonError) {
+	if err := validators.ValidateDto(dto); err != nil {
+		return values.ReviewValues{}, err
+	}
+	if !approve && (dto.Notes == nil || *dto.Notes == "") {
+		return values.ReviewValues{}, errLib.New("notes are required when rejecting a request", http.StatusBadRequest)
+	}
+	return values.ReviewValues{
+		RequestID:  requestID,
+		ReviewerID: reviewerID,
+		Approve:    approve,
+		Notes:      dto.Notes,
+	}, nil
+}
+
+// ParseListQuery reads the staff data request queue filter.
+func ParseListQuery(query url.Values) (values.ListFilter, *errLib.CommonError) {
+	filter := values.ListFilter{Status: query.Get("status"), Type: query.Get("type"), Limit: 50}
+
+	switch filter.Status {
+	case "", values.StatusPendingReview, values.StatusPending, values.StatusProcessing, values.StatusCompleted,
+		values.StatusFailed, values.StatusRejected, values.StatusExpired:
+	default:
+		return values.ListFilter{}, errLib.New("Invalid status", http.StatusBadRequest)
+	}
+	switch filter.Type {
+	case "", values.TypeExport, values.TypeErasure:
+	default:
+		return values.ListFilter{}, errLib.New("Invalid type", http.StatusBadRequest)
+	}
+	if userStr := query.Get("user_id"); userStr != "" {
+		id, err := validators.ParseUUID(userStr)
+		if err != nil {
+			return values.ListFilter{}, err
+		}
+		filter.UserID = id
+	}
+	if limitStr := query.Get("limit"); limitStr != "" {
+		if parsed, parseErr := strconv.Atoi(limitStr); parseErr == nil && parsed > 0 && parsed <= 100 {
+			filter.Limit = int32(parsed)
+		}
+	}
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		if parsed, parseErr := strconv.Atoi(offsetStr); parseErr == nil && parsed >= 0 {
+			filter.Offset = int32(parsed)
+		}
+	}
+	return filter, nil
+}
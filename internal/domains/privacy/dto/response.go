@@ -0,0 +1,116 @@
+package privacy
+
+import (
+	"time"
+
+	values "api/internal/domains/privacy/values"
+
+	"github.com/google/uuid"
+)
+
+type ErasureStepResponseDto struct {
+	Step      string    `json:"step"`
+	Status    string    `json:"status"`
+	Detail    *string   `json:"detail,omitempty"`
+	Attempts  int32     `json:"attempts"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DataRequestResponseDto is a data request as its user sees it.
+type DataRequestResponseDto struct {
+	ID              uuid.UUID  `json:"id"`
+	Type            string     `json:"type"`
+	Status          string     `json:"status"`
+	Reason          *string    `json:"reason,omitempty"`
+	ReviewNotes     *string    `json:"review_notes,omitempty"`
+	DownloadReady   bool       `json:"download_ready"`
+	ExportExpiresAt *time.Time `json:"export_expires_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+}
+
+func NewDataRequestResponse(request values.DataRequest) DataRequestResponseDto {
+	return DataRequestResponseDto{
+		ID:              request.ID,
+		Type:            request.Type,
+		Status:          request.Status,
+		Reason:          request.Reason,
+		ReviewNotes:     request.ReviewNotes,
+		DownloadReady:   request.Status == values.StatusCompleted && request.ExportObject != nil,
+		ExportExpiresAt: request.ExportExpiresAt,
+		CreatedAt:       request.CreatedAt,
+		CompletedAt:     request.CompletedAt,
+	}
+}
+
+func NewDataRequestResponses(requests []values.DataRequest) []DataRequestResponseDto {
+	response := make([]DataRequestResponseDto, len(requests))
+	for i, request := range requests {
+		response[i] = NewDataRequestResponse(request)
+	}
+	return response
+}
+
+// AdminDataRequestResponseDto is a data request in the staff queue.
+type AdminDataRequestResponseDto struct {
+	ID              uuid.UUID                `json:"id"`
+	UserID          uuid.UUID                `json:"user_id"`
+	UserFirstName   *string                  `json:"user_first_name,omitempty"`
+	UserLastName    *string                  `json:"user_last_name,omitempty"`
+	UserEmail       *string                  `json:"user_email,omitempty"`
+	Type            string                   `json:"type"`
+	Status          string                   `json:"status"`
+	RequestedBy     *uuid.UUID               `json:"requested_by,omitempty"` // Empty when opened by the system
+	Reason          *string                  `json:"reason,omitempty"`
+	ReviewedBy      *uuid.UUID               `json:"reviewed_by,omitempty"`
+	ReviewedAt      *time.Time               `json:"reviewed_at,omitempty"`
+	ReviewNotes     *string                  `json:"review_notes,omitempty"`
+	ExportExpiresAt *time.Time               `json:"export_expires_at,omitempty"`
+	Error           *string                  `json:"error,omitempty"`
+	Attempts        int32                    `json:"attempts"`
+	StartedAt       *time.Time               `json:"started_at,omitempty"`
+	CompletedAt     *time.Time               `json:"completed_at,omitempty"`
+	CreatedAt       time.Time                `json:"created_at"`
+	Steps           []ErasureStepResponseDto `json:"steps,omitempty"`
+}
+
+func NewAdminDataRequestResponse(request values.DataRequest) AdminDataRequestResponseDto {
+	response := AdminDataRequestResponseDto{
+		ID:              request.ID,
+		UserID:          request.UserID,
+		UserFirstName:   request.UserFirstName,
+		UserLastName:    request.UserLastName,
+		UserEmail:       request.UserEmail,
+		Type:            request.Type,
+		Status:          request.Status,
+		RequestedBy:     request.RequestedBy,
+		Reason:          request.Reason,
+		ReviewedBy:      request.ReviewedBy,
+		ReviewedAt:      request.ReviewedAt,
+		ReviewNotes:     request.ReviewNotes,
+		ExportExpiresAt: request.ExportExpiresAt,
+		Error:           request.Error,
+		Attempts:        request.Attempts,
+		StartedAt:       request.StartedAt,
+		CompletedAt:     request.CompletedAt,
+		CreatedAt:       request.CreatedAt,
+	}
+	for _, step := range request.Steps {
+		response.Steps = append(response.Steps, ErasureStepResponseDto{
+			Step:      step.Step,
+			Status:    step.Status,
+			Detail:    step.Detail,
+			Attempts:  step.Attempts,
+			UpdatedAt: step.UpdatedAt,
+		})
+	}
+	return response
+}
+
+func NewAdminDataRequestResponses(requests []values.DataRequest) []AdminDataRequestResponseDto {
+	response := make([]AdminDataRequestResponseDto, len(requests))
+	for i, request := range requests {
+		response[i] = NewAdminDataRequestResponse(request)
+	}
+	return response
+}
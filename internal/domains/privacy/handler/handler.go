@@ -0,0 +1,301 @@
+package privacy
+
+import (
+	"net/http"
+	"strconv"
+
+	"api/internal/di"
+	dto "api/internal/domains/privacy/dto"
+	service "api/internal/domains/privacy/service"
+	responseHandlers "api/internal/libs/responses"
+	"api/internal/libs/validators"
+	contextUtils "api/utils/context"
+
+	"github.com/go-chi/chi"
+)
+
+type Handler struct {
+	Service *service.Service
+}
+
+func NewHandler(container *di.Container) *Handler {
+	return &Handler{Service: service.NewService(container)}
+}
+
+// RequestExport asks for a copy of the logged-in user's personal data.
+// @Description The export is built in the background. A notification email is sent when the ZIP can be downloaded.
+// @Tags privacy
+// @Produce json
+// @Security Bearer
+// @Success 201 {object} dto.DataRequestResponseDto "Export requested"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 409 {object} map[string]interface{} "Conflict: An export is already in progress"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /secure/privacy/exports [post]
+func (h *Handler) RequestExport(w http.ResponseWriter, r *http.Request) {
+	userID, err := contextUtils.GetUserID(r.Context())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	request, err := h.Service.RequestExport(r.Context(), userID)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewDataRequestResponse(request), http.StatusCreated)
+}
+
+// RequestErasure asks for the logged-in user's personal data to be erased.
+// @Description The request waits for staff review before anything is deleted. Staff accounts cannot request erasure of themselves.
+// @Tags privacy
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body dto.ErasureRequestDto false "Optional reason"
+// @Success 201 {object} dto.DataRequestResponseDto "Erasure requested"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid request body"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Staff accounts cannot be erased this way"
+// @Failure 409 {object} map[string]interface{} "Conflict: An erasure is already in progress"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /secure/privacy/erasure [post]
+func (h *Handler) RequestErasure(w http.ResponseWriter, r *http.Request) {
+	userID, err := contextUtils.GetUserID(r.Context())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	var requestDto dto.ErasureRequestDto
+	if r.ContentLength != 0 {
+		if err = validators.ParseJSON(r.Body, &requestDto); err != nil {
+			responseHandlers.RespondWithError(w, err)
+			return
+		}
+	}
+	if err = requestDto.Validate(); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	request, err := h.Service.RequestErasure(r.Context(), userID, requestDto.Reason)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewDataRequestResponse(request), http.StatusCreated)
+}
+
+// GetMyRequests lists the logged-in user's export and erasure requests.
+// @Tags privacy
+// @Produce json
+// @Security Bearer
+// @Success 200 {array} dto.DataRequestResponseDto "Requests"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /secure/privacy/requests [get]
+func (h *Handler) GetMyRequests(w http.ResponseWriter, r *http.Request) {
+	userID, err := contextUtils.GetUserID(r.Context())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	requests, err := h.Service.ListMyRequests(r.Context(), userID)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewDataRequestResponses(requests), http.StatusOK)
+}
+
+// DownloadExport returns a finished export as a ZIP file.
+// @Tags privacy
+// @Produce application/zip
+// @Security Bearer
+// @Param id path string true "Request ID" format(uuid)
+// @Success 200 {file} file "ZIP archive"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Export is not ready"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Not Found: Request not found"
+// @Failure 410 {object} map[string]interface{} "Gone: Export has expired"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /secure/privacy/requests/{id}/download [get]
+func (h *Handler) DownloadExport(w http.ResponseWriter, r *http.Request) {
+	userID, err := contextUtils.GetUserID(r.Context())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	requestID, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	archive, filename, err := h.Service.DownloadExport(r.Context(), userID, requestID)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", "attachment; filename="+filename)
+	w.Header().Set("Content-Length", strconv.Itoa(len(archive)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(archive)
+}
+
+// GetDataRequests lists export and erasure requests for staff.
+// @Tags privacy
+// @Produce json
+// @Security Bearer
+// @Param status query string false "pending_review, pending, processing, completed, failed, rejected or expired"
+// @Param type query string false "export or erasure"
+// @Param user_id query string false "User ID" format(uuid)
+// @Param limit query int false "Page size (max 100)"
+// @Param offset query int false "Offset"
+// @Success 200 {array} dto.AdminDataRequestResponseDto "Requests"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid filter"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /admin/privacy-requests [get]
+func (h *Handler) GetDataRequests(w http.ResponseWriter, r *http.Request) {
+	filter, err := dto.ParseListQuery(r.URL.Query())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	requests, err := h.Service.ListRequests(r.Context(), filter)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewAdminDataRequestResponses(requests), http.StatusOK)
+}
+
+// GetDataRequest returns one request, including the progress of each erasure step.
+// @Tags privacy
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Request ID" format(uuid)
+// @Success 200 {object} dto.AdminDataRequestResponseDto "Request"
+// @Failure 404 {object} map[string]interface{} "Not Found: Request not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /admin/privacy-requests/{id} [get]
+func (h *Handler) GetDataRequest(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	request, err := h.Service.GetRequest(r.Context(), id)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewAdminDataRequestResponse(request), http.StatusOK)
+}
+
+// ApproveErasure releases an erasure request to the background processor.
+// @Tags privacy
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Request ID" format(uuid)
+// @Param review body dto.ReviewRequestDto false "Optional notes"
+// @Success 200 {object} dto.AdminDataRequestResponseDto "Approved"
+// @Failure 404 {object} map[string]interface{} "Not Found: Request not found"
+// @Failure 409 {object} map[string]interface{} "Conflict: Request is not awaiting review"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /admin/privacy-requests/{id}/approve [post]
+func (h *Handler) ApproveErasure(w http.ResponseWriter, r *http.Request) {
+	h.review(w, r, true)
+}
+
+// RejectErasure declines an erasure request. Notes are required and shown to the user.
+// @Tags privacy
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Request ID" format(uuid)
+// @Param review body dto.ReviewRequestDto true "Reason for rejecting"
+// @Success 200 {object} dto.AdminDataRequestResponseDto "Rejected"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Notes are required"
+// @Failure 404 {object} map[string]interface{} "Not Found: Request not found"
+// @Failure 409 {object} map[string]interface{} "Conflict: Request is not awaiting review"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /admin/privacy-requests/{id}/reject [post]
+func (h *Handler) RejectErasure(w http.ResponseWriter, r *http.Request) {
+	h.review(w, r, false)
+}
+
+func (h *Handler) review(w http.ResponseWriter, r *http.Request, approve bool) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	staffID, err := contextUtils.GetUserID(r.Context())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	var reviewDto dto.ReviewRequestDto
+	if r.ContentLength != 0 {
+		if err = validators.ParseJSON(r.Body, &reviewDto); err != nil {
+			responseHandlers.RespondWithError(w, err)
+			return
+		}
+	}
+
+	review, err := reviewDto.ToValues(id, staffID, approve)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	request, err := h.Service.ReviewErasure(r.Context(), review)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewAdminDataRequestResponse(request), http.StatusOK)
+}
+
+// RetryDataRequest puts a failed request back in the queue. Erasures resume from the step that failed.
+// @Tags privacy
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Request ID" format(uuid)
+// @Success 200 {object} dto.AdminDataRequestResponseDto "Queued again"
+// @Failure 404 {object} map[string]interface{} "Not Found: Request not found"
+// @Failure 409 {object} map[string]interface{} "Conflict: Only failed requests can be retried"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /admin/privacy-requests/{id}/retry [post]
+func (h *Handler) RetryDataRequest(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	staffID, err := contextUtils.GetUserID(r.Context())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	request, err := h.Service.RetryRequest(r.Context(), id, staffID)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewAdminDataRequestResponse(request), http.StatusOK)
+}
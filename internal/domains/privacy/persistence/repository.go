@@ -0,0 +1,488 @@
+package privacy
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	databaseErrors "api/internal/constants"
+	"api/internal/di"
+	db "api/internal/domains/privacy/persistence/sqlc/generated"
+	values "api/internal/domains/privacy/values"
+	errLib "api/internal/libs/errors"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type Repository struct {
+	Queries *db.Queries
+	Tx      *sql.Tx
+}
+
+func NewRepository(container *di.Container) *Repository {
+	return &Repository{Queries: container.Queries.PrivacyDb}
+}
+
+func (r *Repository) WithTx(tx *sql.Tx) *Repository {
+	return &Repository{Queries: r.Queries.WithTx(tx), Tx: tx}
+}
+
+func nullUUID(id uuid.UUID) uuid.NullUUID {
+	return uuid.NullUUID{UUID: id, Valid: id != uuid.Nil}
+}
+
+func nullString(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *s, Valid: true}
+}
+
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
+
+func uuidPtr(id uuid.NullUUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
+	}
+	return &id.UUID
+}
+
+func stringPtr(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
+}
+
+func timePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func mapRequest(row db.PrivacyDataRequest) values.DataRequest {
+	return values.DataRequest{
+		ID:              row.ID,
+		UserID:          row.UserID,
+		Type:            row.RequestType,
+		Status:          row.Status,
+		RequestedBy:     uuidPtr(row.RequestedBy),
+		Reason:          stringPtr(row.Reason),
+		ReviewedBy:      uuidPtr(row.ReviewedBy),
+		ReviewedAt:      timePtr(row.ReviewedAt),
+		ReviewNotes:     stringPtr(row.ReviewNotes),
+		ExportObject:    stringPtr(row.ExportObject),
+		ExportExpiresAt: timePtr(row.ExportExpiresAt),
+		Error:           stringPtr(row.Error),
+		Attempts:        row.Attempts,
+		StartedAt:       timePtr(row.StartedAt),
+		CompletedAt:     timePtr(row.CompletedAt),
+		CreatedAt:       row.CreatedAt,
+		UpdatedAt:       row.UpdatedAt,
+	}
+}
+
+func mapRequests(rows []db.PrivacyDataRequest) []values.DataRequest {
+	requests := make([]values.DataRequest, len(rows))
+	for i, row := range rows {
+		requests[i] = mapRequest(row)
+	}
+	return requests
+}
+
+func (r *Repository) CreateRequest(ctx context.Context, userID uuid.UUID, requestType, status string, requestedBy uuid.UUID, reason *string) (values.DataRequest, *errLib.CommonError) {
+	row, err := r.Queries.CreateDataRequest(ctx, db.CreateDataRequestParams{
+		UserID:      userID,
+		RequestType: requestType,
+		Status:      status,
+		RequestedBy: nullUUID(requestedBy),
+		Reason:      nullString(reason),
+	})
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == databaseErrors.UniqueViolation {
+			return values.DataRequest{}, errLib.New("There is already an open "+requestType+" request for this account", http.StatusConflict)
+		}
+		log.Printf("Failed to create %s request for user %s: %v", requestType, userID, err)
+		return values.DataRequest{}, errLib.New("Internal server error when creating data request", http.StatusInternalServerError)
+	}
+	return mapRequest(row), nil
+}
+
+func (r *Repository) GetRequest(ctx context.Context, id uuid.UUID) (values.DataRequest, *errLib.CommonError) {
+	row, err := r.Queries.GetDataRequest(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return values.DataRequest{}, errLib.New("Data request not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to get data request %s: %v", id, err)
+		return values.DataRequest{}, errLib.New("Internal server error when getting data request", http.StatusInternalServerError)
+	}
+	return mapRequest(row), nil
+}
+
+// GetOpenRequest returns the user's open request of requestType, or nil when there is none.
+func (r *Repository) GetOpenRequest(ctx context.Context, userID uuid.UUID, requestType string) (*values.DataRequest, *errLib.CommonError) {
+	row, err := r.Queries.GetOpenDataRequest(ctx, db.GetOpenDataRequestParams{UserID: userID, RequestType: requestType})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		log.Printf("Failed to get open %s request for user %s: %v", requestType, userID, err)
+		return nil, errLib.New("Internal server error when getting data request", http.StatusInternalServerError)
+	}
+	request := mapRequest(row)
+	return &request, nil
+}
+
+func (r *Repository) ListUserRequests(ctx context.Context, userID uuid.UUID) ([]values.DataRequest, *errLib.CommonError) {
+	rows, err := r.Queries.ListUserDataRequests(ctx, userID)
+	if err != nil {
+		log.Printf("Failed to list data requests for user %s: %v", userID, err)
+		return nil, errLib.New("Internal server error when listing data requests", http.StatusInternalServerError)
+	}
+	return mapRequests(rows), nil
+}
+
+func (r *Repository) ListRequests(ctx context.Context, filter values.ListFilter) ([]values.DataRequest, *errLib.CommonError) {
+	rows, err := r.Queries.ListDataRequests(ctx, db.ListDataRequestsParams{
+		Status:      sql.NullString{String: filter.Status, Valid: filter.Status != ""},
+		RequestType: sql.NullString{String: filter.Type, Valid: filter.Type != ""},
+		UserID:      nullUUID(filter.UserID),
+		Limit:       filter.Limit,
+		Offset:      filter.Offset,
+	})
+	if err != nil {
+		log.Printf("Failed to list data requests: %v", err)
+		return nil, errLib.New("Internal server error when listing data requests", http.StatusInternalServerError)
+	}
+
+	requests := make([]values.DataRequest, len(rows))
+	for i, row := range rows {
+		requests[i] = mapRequest(db.PrivacyDataRequest{
+			ID:              row.ID,
+			UserID:          row.UserID,
+			RequestType:     row.RequestType,
+			Status:          row.Status,
+			RequestedBy:     row.RequestedBy,
+			Reason:          row.Reason,
+			ReviewedBy:      row.ReviewedBy,
+			ReviewedAt:      row.ReviewedAt,
+			ReviewNotes:     row.ReviewNotes,
+			ExportObject:    row.ExportObject,
+			ExportExpiresAt: row.ExportExpiresAt,
+			Error:           row.Error,
+			Attempts:        row.Attempts,
+			StartedAt:       row.StartedAt,
+			CompletedAt:     row.CompletedAt,
+			CreatedAt:       row.CreatedAt,
+			UpdatedAt:       row.UpdatedAt,
+		})
+		requests[i].UserFirstName = stringPtr(row.UserFirstName)
+		requests[i].UserLastName = stringPtr(row.UserLastName)
+		requests[i].UserEmail = stringPtr(row.UserEmail)
+	}
+	return requests, nil
+}
+
+// Review moves a request waiting for review to status. reviewerID is uuid.Nil when the
+// system approved it.
+func (r *Repository) Review(ctx context.Context, id uuid.UUID, status string, reviewerID uuid.UUID, notes *string) (values.DataRequest, *errLib.CommonError) {
+	row, err := r.Queries.ReviewDataRequest(ctx, db.ReviewDataRequestParams{
+		ID:          id,
+		Status:      status,
+		ReviewedBy:  nullUUID(reviewerID),
+		ReviewNotes: nullString(notes),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return values.DataRequest{}, errLib.New("Data request is not waiting for review", http.StatusConflict)
+		}
+		log.Printf("Failed to review data request %s: %v", id, err)
+		return values.DataRequest{}, errLib.New("Internal server error when reviewing data request", http.StatusInternalServerError)
+	}
+	return mapRequest(row), nil
+}
+
+func (r *Repository) Retry(ctx context.Context, id uuid.UUID) (values.DataRequest, *errLib.CommonError) {
+	row, err := r.Queries.RetryDataRequest(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return values.DataRequest{}, errLib.New("Only failed data requests can be retried", http.StatusConflict)
+		}
+		log.Printf("Failed to retry data request %s: %v", id, err)
+		return values.DataRequest{}, errLib.New("Internal server error when retrying data request", http.StatusInternalServerError)
+	}
+	return mapRequest(row), nil
+}
+
+// ClaimPending marks up to batchSize pending requests as processing and returns them.
+// Requests processing since before staleBefore are claimed again.
+func (r *Repository) ClaimPending(ctx context.Context, staleBefore time.Time, batchSize int32) ([]values.DataRequest, *errLib.CommonError) {
+	rows, err := r.Queries.ClaimDataRequests(ctx, db.ClaimDataRequestsParams{StaleBefore: staleBefore, BatchSize: batchSize})
+	if err != nil {
+		log.Printf("Failed to claim data requests: %v", err)
+		return nil, errLib.New("Internal server error when claiming data requests", http.StatusInternalServerError)
+	}
+	return mapRequests(rows), nil
+}
+
+// Claim marks a pending request as processing. It returns nil when the request is not
+// pending, e.g. because a job run already claimed it.
+func (r *Repository) Claim(ctx context.Context, id uuid.UUID) (*values.DataRequest, *errLib.CommonError) {
+	row, err := r.Queries.ClaimDataRequest(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		log.Printf("Failed to claim data request %s: %v", id, err)
+		return nil, errLib.New("Internal server error when claiming data request", http.StatusInternalServerError)
+	}
+	request := mapRequest(row)
+	return &request, nil
+}
+
+func (r *Repository) Complete(ctx context.Context, id uuid.UUID, exportObject *string, exportExpiresAt *time.Time) *errLib.CommonError {
+	if err := r.Queries.CompleteDataRequest(ctx, db.CompleteDataRequestParams{
+		ID:              id,
+		ExportObject:    nullString(exportObject),
+		ExportExpiresAt: nullTime(exportExpiresAt),
+	}); err != nil {
+		log.Printf("Failed to complete data request %s: %v", id, err)
+		return errLib.New("Internal server error when completing data request", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (r *Repository) Fail(ctx context.Context, id uuid.UUID, message string) *errLib.CommonError {
+	if err := r.Queries.FailDataRequest(ctx, db.FailDataRequestParams{
+		ID:    id,
+		Error: sql.NullString{String: message, Valid: true},
+	}); err != nil {
+		log.Printf("Failed to mark data request %s as failed: %v", id, err)
+		return errLib.New("Internal server error when updating data request", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (r *Repository) ListExpiredExports(ctx context.Context, now time.Time) ([]values.DataRequest, *errLib.CommonError) {
+	rows, err := r.Queries.ListExpiredExports(ctx, sql.NullTime{Time: now, Valid: true})
+	if err != nil {
+		log.Printf("Failed to list expired exports: %v", err)
+		return nil, errLib.New("Internal server error when listing expired exports", http.StatusInternalServerError)
+	}
+	return mapRequests(rows), nil
+}
+
+func (r *Repository) ExpireExport(ctx context.Context, id uuid.UUID) *errLib.CommonError {
+	if err := r.Queries.ExpireDataRequestExport(ctx, id); err != nil {
+		log.Printf("Failed to expire export %s: %v", id, err)
+		return errLib.New("Internal server error when expiring export", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// InitSteps adds the erasure steps a request does not have yet as pending.
+func (r *Repository) InitSteps(ctx context.Context, requestID uuid.UUID) *errLib.CommonError {
+	if err := r.Queries.InitErasureSteps(ctx, db.InitErasureStepsParams{RequestID: requestID, Steps: values.ErasureSteps}); err != nil {
+		log.Printf("Failed to create erasure steps for request %s: %v", requestID, err)
+		return errLib.New("Internal server error when creating erasure steps", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// ListSteps returns the erasure steps of a request in the order they run.
+func (r *Repository) ListSteps(ctx context.Context, requestID uuid.UUID) ([]values.ErasureStep, *errLib.CommonError) {
+	rows, err := r.Queries.ListErasureSteps(ctx, requestID)
+	if err != nil {
+		log.Printf("Failed to list erasure steps for request %s: %v", requestID, err)
+		return nil, errLib.New("Internal server error when listing erasure steps", http.StatusInternalServerError)
+	}
+
+	order := make(map[string]int, len(values.ErasureSteps))
+	for i, step := range values.ErasureSteps {
+		order[step] = i
+	}
+	sort.Slice(rows, func(i, j int) bool { return order[rows[i].Step] < order[rows[j].Step] })
+
+	steps := make([]values.ErasureStep, len(rows))
+	for i, row := range rows {
+		steps[i] = values.ErasureStep{
+			Step:      row.Step,
+			Status:    row.Status,
+			Detail:    stringPtr(row.Detail),
+			Attempts:  row.Attempts,
+			UpdatedAt: row.UpdatedAt,
+		}
+	}
+	return steps, nil
+}
+
+func (r *Repository) UpdateStep(ctx context.Context, requestID uuid.UUID, step, status, detail string) *errLib.CommonError {
+	if err := r.Queries.UpdateErasureStep(ctx, db.UpdateErasureStepParams{
+		RequestID: requestID,
+		Step:      step,
+		Status:    status,
+		Detail:    sql.NullString{String: detail, Valid: detail != ""},
+	}); err != nil {
+		log.Printf("Failed to update erasure step %s of request %s: %v", step, requestID, err)
+		return errLib.New("Internal server error when updating erasure step", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (r *Repository) ListUserFiles(ctx context.Context, userID uuid.UUID) ([]values.UserFile, *errLib.CommonError) {
+	rows, err := r.Queries.ListUserFiles(ctx, userID)
+	if err != nil {
+		log.Printf("Failed to list files of user %s: %v", userID, err)
+		return nil, errLib.New("Internal server error when listing user files", http.StatusInternalServerError)
+	}
+
+	files := make([]values.UserFile, len(rows))
+	for i, row := range rows {
+		files[i] = values.UserFile{Category: row.Category, URL: row.Url}
+	}
+	return files, nil
+}
+
+// GetExportData collects the personal data held about a user, apart from the contents
+// of their uploaded files.
+func (r *Repository) GetExportData(ctx context.Context, userID uuid.UUID) (values.ExportData, *errLib.CommonError) {
+	var (
+		data values.ExportData
+		err  error
+	)
+
+	if data.Profile, err = r.Queries.GetExportProfile(ctx, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return values.ExportData{}, errLib.New("User not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to export profile of user %s: %v", userID, err)
+		return values.ExportData{}, errLib.New("Internal server error when exporting user data", http.StatusInternalServerError)
+	}
+
+	sections := []struct {
+		name  string
+		query func(context.Context, uuid.UUID) (json.RawMessage, error)
+		dest  *json.RawMessage
+	}{
+		{"enrollments", r.Queries.GetExportEnrollments, &data.Enrollments},
+		{"payments", r.Queries.GetExportPayments, &data.Payments},
+		{"credits", r.Queries.GetExportCredits, &data.Credits},
+		{"waivers", r.Queries.GetExportWaivers, &data.Waivers},
+	}
+	for _, section := range sections {
+		if *section.dest, err = section.query(ctx, userID); err != nil {
+			log.Printf("Failed to export %s of user %s: %v", section.name, userID, err)
+			return values.ExportData{}, errLib.New("Internal server error when exporting user data", http.StatusInternalServerError)
+		}
+	}
+
+	files, filesErr := r.ListUserFiles(ctx, userID)
+	if filesErr != nil {
+		return values.ExportData{}, filesErr
+	}
+	data.Files = files
+
+	return data, nil
+}
+
+func (r *Repository) GetDataSubject(ctx context.Context, userID uuid.UUID) (values.DataSubject, *errLib.CommonError) {
+	row, err := r.Queries.GetDataSubject(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return values.DataSubject{}, errLib.New("User not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to get data subject %s: %v", userID, err)
+		return values.DataSubject{}, errLib.New("Internal server error when getting user", http.StatusInternalServerError)
+	}
+	return values.DataSubject{
+		UserID:           row.ID,
+		FirstName:        row.FirstName,
+		Email:            row.Email.String,
+		HubspotID:        row.HubspotID.String,
+		StripeCustomerID: row.StripeCustomerID.String,
+	}, nil
+}
+
+func (r *Repository) ClearStripeCustomer(ctx context.Context, userID uuid.UUID) *errLib.CommonError {
+	if err := r.Queries.ClearUserStripeCustomer(ctx, userID); err != nil {
+		log.Printf("Failed to clear Stripe customer of user %s: %v", userID, err)
+		return errLib.New("Internal server error when updating user", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (r *Repository) ClearHubspotID(ctx context.Context, userID uuid.UUID) *errLib.CommonError {
+	if err := r.Queries.ClearUserHubspotID(ctx, userID); err != nil {
+		log.Printf("Failed to clear HubSpot ID of user %s: %v", userID, err)
+		return errLib.New("Internal server error when updating user", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// DeletePushTokens removes the user's devices and the delivery tickets that name them.
+// Run it on a repository bound to a transaction.
+func (r *Repository) DeletePushTokens(ctx context.Context, userID uuid.UUID) *errLib.CommonError {
+	if err := r.Queries.DeleteUserPushTickets(ctx, userID); err != nil {
+		log.Printf("Failed to delete push tickets of user %s: %v", userID, err)
+		return errLib.New("Internal server error when deleting push tokens", http.StatusInternalServerError)
+	}
+	if err := r.Queries.DeleteUserPushTokens(ctx, userID); err != nil {
+		log.Printf("Failed to delete push tokens of user %s: %v", userID, err)
+		return errLib.New("Internal server error when deleting push tokens", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// EraseUserData deletes the user's personal records and anonymizes the ones kept for
+// accounting, including the account row itself. Run it on a repository bound to a
+// transaction so a failure leaves nothing half erased.
+func (r *Repository) EraseUserData(ctx context.Context, userID uuid.UUID) *errLib.CommonError {
+	userRef := uuid.NullUUID{UUID: userID, Valid: true}
+
+	statements := []struct {
+		description string
+		exec        func() error
+	}{
+		{"delete attendance records", func() error { return r.Queries.DeleteUserAttendance(ctx, userID) }},
+		{"delete event enrollments", func() error { return r.Queries.DeleteUserEventEnrollments(ctx, userID) }},
+		{"delete program enrollments", func() error { return r.Queries.DeleteUserProgramEnrollments(ctx, userID) }},
+		{"delete waiver signings", func() error { return r.Queries.DeleteUserWaiverSignings(ctx, userID) }},
+		{"delete waiver uploads", func() error { return r.Queries.DeleteUserWaiverUploads(ctx, userID) }},
+		{"delete medical info", func() error { return r.Queries.DeleteUserMedicalInfo(ctx, userID) }},
+		{"delete athlete record", func() error { return r.Queries.DeleteUserAthlete(ctx, userID) }},
+		{"delete referral devices", func() error { return r.Queries.DeleteUserReferralDevices(ctx, userID) }},
+		{"delete parent link requests", func() error { return r.Queries.DeleteUserParentLinkRequests(ctx, userID) }},
+		{"orphan child accounts", func() error { return r.Queries.OrphanChildAccounts(ctx, userRef) }},
+		{"anonymize memberships", func() error { return r.Queries.AnonymizeUserMemberships(ctx, userID) }},
+		{"anonymize membership freezes", func() error { return r.Queries.AnonymizeUserMembershipFreezes(ctx, userID) }},
+		{"anonymize payments", func() error { return r.Queries.AnonymizeUserPayments(ctx, userID) }},
+		{"anonymize payment links", func() error { return r.Queries.AnonymizeUserPaymentLinks(ctx, userID) }},
+		{"anonymize collection attempts", func() error { return r.Queries.AnonymizeUserCollectionAttempts(ctx, userID) }},
+		{"anonymize gift cards", func() error { return r.Queries.AnonymizeUserGiftCards(ctx, userRef) }},
+		{"anonymize staff activities", func() error { return r.Queries.AnonymizeUserStaffActivities(ctx, userID.String()) }},
+		{"expire data exports", func() error { return r.Queries.ExpireUserExports(ctx, userID) }},
+		{"anonymize account", func() error { return r.Queries.AnonymizeUser(ctx, userID) }},
+	}
+
+	for _, statement := range statements {
+		if err := statement.exec(); err != nil {
+			log.Printf("Failed to %s of user %s: %v", statement.description, userID, err)
+			return errLib.New("Failed to "+statement.description, http.StatusInternalServerError)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+-- name: CreateDataRequest :one
+INSERT INTO privacy.data_requests (user_id, request_type, status, requested_by, reason)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetDataRequest :one
+SELECT *
+FROM privacy.data_requests
+WHERE id = $1;
+
+-- name: GetOpenDataRequest :one
+-- Returns the user's open request of a type. A failed erasure counts as open: it is
+-- retried rather than replaced, so its completed steps are not run again.
+SELECT *
+FROM privacy.data_requests
+WHERE user_id = $1
+  AND request_type = $2
+  AND (status IN ('pending_review', 'pending', 'processing')
+    OR (request_type = 'erasure' AND status = 'failed'))
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: ListUserDataRequests :many
+SELECT *
+FROM privacy.data_requests
+WHERE user_id = $1
+ORDER BY created_at DESC;
+
+-- name: ListDataRequests :many
+SELECT dr.id,
+       dr.user_id,
+       dr.request_type,
+       dr.status,
+       dr.requested_by,
+       dr.reason,
+       dr.reviewed_by,
+       dr.reviewed_at,
+       dr.review_notes,
+       dr.export_object,
+       dr.export_expires_at,
+       dr.error,
+       dr.attempts,
+       dr.started_at,
+       dr.completed_at,
+       dr.created_at,
+       dr.updated_at,
+       u.first_name AS user_first_name,
+       u.last_name  AS user_last_name,
+       u.email      AS user_email
+FROM privacy.data_requests dr
+         LEFT JOIN users.users u ON u.id = dr.user_id
+WHERE (sqlc.narg('status')::text IS NULL OR dr.status = sqlc.narg('status')::text)
+  AND (sqlc.narg('request_type')::text IS NULL OR dr.request_type = sqlc.narg('request_type')::text)
+  AND (sqlc.narg('user_id')::uuid IS NULL OR dr.user_id = sqlc.narg('user_id')::uuid)
+ORDER BY dr.created_at
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ReviewDataRequest :one
+-- Moves a request waiting for review to status: pending when approved, rejected otherwise.
+-- reviewed_by is NULL when the system approved it.
+UPDATE privacy.data_requests
+SET status       = $2,
+    reviewed_by  = $3,
+    reviewed_at  = CURRENT_TIMESTAMP,
+    review_notes = $4,
+    updated_at   = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND status = 'pending_review'
+RETURNING *;
+
+-- name: RetryDataRequest :one
+UPDATE privacy.data_requests
+SET status     = 'pending',
+    error      = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND status = 'failed'
+RETURNING *;
+
+-- name: ClaimDataRequests :many
+-- Claims pending requests for processing, oldest first. Requests left processing since
+-- before stale_before belonged to a run that died and are claimed again.
+UPDATE privacy.data_requests
+SET status     = 'processing',
+    attempts   = attempts + 1,
+    started_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id IN (SELECT id
+             FROM privacy.data_requests
+             WHERE status = 'pending'
+                OR (status = 'processing' AND started_at < sqlc.arg('stale_before')::timestamptz)
+             ORDER BY created_at
+             LIMIT sqlc.arg('batch_size')::int FOR UPDATE SKIP LOCKED)
+RETURNING *;
+
+-- name: ClaimDataRequest :one
+UPDATE privacy.data_requests
+SET status     = 'processing',
+    attempts   = attempts + 1,
+    started_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND status = 'pending'
+RETURNING *;
+
+-- name: CompleteDataRequest :exec
+UPDATE privacy.data_requests
+SET status            = 'completed',
+    export_object     = $2,
+    export_expires_at = $3,
+    error             = NULL,
+    completed_at      = CURRENT_TIMESTAMP,
+    updated_at        = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: FailDataRequest :exec
+UPDATE privacy.data_requests
+SET status     = 'failed',
+    error      = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: ListExpiredExports :many
+SELECT *
+FROM privacy.data_requests
+WHERE request_type = 'export'
+  AND status = 'completed'
+  AND export_object IS NOT NULL
+  AND export_expires_at < $1
+ORDER BY export_expires_at
+LIMIT 100;
+
+-- name: ExpireDataRequestExport :exec
+UPDATE privacy.data_requests
+SET status        = 'expired',
+    export_object = NULL,
+    updated_at    = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: ExpireUserExports :exec
+UPDATE privacy.data_requests
+SET status        = 'expired',
+    export_object = NULL,
+    updated_at    = CURRENT_TIMESTAMP
+WHERE user_id = $1
+  AND export_object IS NOT NULL;
+
+-- name: InitErasureSteps :exec
+INSERT INTO privacy.erasure_steps (request_id, step)
+SELECT sqlc.arg('request_id')::uuid, unnest(sqlc.arg('steps')::text[])
+ON CONFLICT (request_id, step) DO NOTHING;
+
+-- name: ListErasureSteps :many
+SELECT *
+FROM privacy.erasure_steps
+WHERE request_id = $1;
+
+-- name: UpdateErasureStep :exec
+UPDATE privacy.erasure_steps
+SET status     = $3,
+    detail     = $4,
+    attempts   = attempts + 1,
+    updated_at = CURRENT_TIMESTAMP
+WHERE request_id = $1
+  AND step = $2;
@@ -0,0 +1,153 @@
+-- name: GetDataSubject :one
+SELECT id, first_name, email, hubspot_id, stripe_customer_id
+FROM users.users
+WHERE id = $1;
+
+-- name: ClearUserStripeCustomer :exec
+UPDATE users.users
+SET stripe_customer_id = NULL
+WHERE id = $1;
+
+-- name: ClearUserHubspotID :exec
+UPDATE users.users
+SET hubspot_id = NULL
+WHERE id = $1;
+
+-- name: DeleteUserPushTickets :exec
+DELETE
+FROM notifications.push_tickets
+WHERE push_token_id IN (SELECT id FROM notifications.push_tokens WHERE user_id = $1);
+
+-- name: DeleteUserPushTokens :exec
+DELETE
+FROM notifications.push_tokens
+WHERE user_id = $1;
+
+-- name: DeleteUserAttendance :exec
+DELETE
+FROM events.attendance
+WHERE user_id = $1;
+
+-- name: DeleteUserEventEnrollments :exec
+DELETE
+FROM events.customer_enrollment
+WHERE customer_id = $1;
+
+-- name: DeleteUserProgramEnrollments :exec
+DELETE
+FROM program.customer_enrollment
+WHERE customer_id = $1;
+
+-- name: DeleteUserWaiverSignings :exec
+DELETE
+FROM waiver.waiver_signing
+WHERE user_id = $1;
+
+-- name: DeleteUserWaiverUploads :exec
+DELETE
+FROM waiver.waiver_uploads
+WHERE user_id = $1;
+
+-- name: DeleteUserMedicalInfo :exec
+DELETE
+FROM users.medical_info
+WHERE user_id = $1;
+
+-- name: DeleteUserAthlete :exec
+DELETE
+FROM athletic.athletes
+WHERE id = $1;
+
+-- name: DeleteUserReferralDevices :exec
+DELETE
+FROM referrals.devices
+WHERE customer_id = $1;
+
+-- name: DeleteUserParentLinkRequests :exec
+DELETE
+FROM users.parent_link_requests
+WHERE child_id = $1
+   OR new_parent_id = $1
+   OR old_parent_id = $1;
+
+-- name: OrphanChildAccounts :exec
+UPDATE users.users
+SET parent_id = NULL
+WHERE parent_id = $1;
+
+-- name: AnonymizeUserMemberships :exec
+UPDATE users.customer_membership_plans
+SET photo_url = NULL
+WHERE customer_id = $1;
+
+-- name: AnonymizeUserMembershipFreezes :exec
+UPDATE users.membership_freezes
+SET notes            = NULL,
+    medical_note_url = NULL
+WHERE customer_id = $1;
+
+-- name: AnonymizeUserPayments :exec
+-- Payments are kept for accounting; only who paid is removed from them.
+UPDATE payments.payment_transactions
+SET customer_email = '',
+    customer_name  = 'Deleted User'
+WHERE customer_id = $1;
+
+-- name: AnonymizeUserPaymentLinks :exec
+UPDATE payments.payment_links
+SET sent_to_email = NULL,
+    sent_to_phone = NULL
+WHERE customer_id = $1;
+
+-- name: AnonymizeUserCollectionAttempts :exec
+UPDATE payments.collection_attempts
+SET notes = NULL
+WHERE customer_id = $1;
+
+-- name: AnonymizeUserGiftCards :exec
+UPDATE gift_cards.gift_cards
+SET recipient_name  = NULL,
+    recipient_email = NULL,
+    message         = NULL
+WHERE purchaser_id = $1;
+
+-- name: AnonymizeUserStaffActivities :exec
+-- Staff activity descriptions name the user they were about by ID and may hold the
+-- reasons staff gave. Audit log entries only point at them, so they stay intact.
+UPDATE audit.staff_activity_logs
+SET activity_description = 'Erased activity about a deleted user'
+WHERE activity_description LIKE '%' || sqlc.arg('user_id')::text || '%';
+
+-- name: AnonymizeUser :exec
+-- Keeps the account row, and so the financial records that reference it, but removes
+-- everything that identifies the person. The row stays deleted and is no longer
+-- scheduled for deletion or archived.
+UPDATE users.users
+SET first_name                          = 'Deleted',
+    last_name                           = 'User',
+    email                               = NULL,
+    phone                               = NULL,
+    gender                              = NULL,
+    dob                                 = DATE '1900-01-01',
+    notes                               = NULL,
+    parent_id                           = NULL,
+    hubspot_id                          = NULL,
+    square_customer_id                  = NULL,
+    stripe_customer_id                  = NULL,
+    has_marketing_email_consent         = FALSE,
+    has_sms_consent                     = FALSE,
+    emergency_contact_name              = NULL,
+    emergency_contact_phone             = NULL,
+    emergency_contact_relationship      = NULL,
+    email_verification_token            = NULL,
+    email_verification_token_expires_at = NULL,
+    pending_email                       = NULL,
+    pending_email_token                 = NULL,
+    pending_email_token_expires_at      = NULL,
+    suspension_reason                   = NULL,
+    deleted_at                          = COALESCE(deleted_at, CURRENT_TIMESTAMP),
+    scheduled_deletion_at               = NULL,
+    is_archived                         = FALSE,
+    archived_at                         = NULL,
+    updated_at                          = CURRENT_TIMESTAMP
+WHERE id = $1;
@@ -0,0 +1,151 @@
+-- name: GetExportProfile :one
+SELECT json_build_object(
+               'id', u.id,
+               'first_name', u.first_name,
+               'last_name', u.last_name,
+               'email', u.email,
+               'phone', u.phone,
+               'date_of_birth', u.dob,
+               'gender', u.gender,
+               'country', u.country_alpha2_code,
+               'parent_id', u.parent_id,
+               'has_marketing_email_consent', u.has_marketing_email_consent,
+               'has_sms_consent', u.has_sms_consent,
+               'emergency_contact_name', u.emergency_contact_name,
+               'emergency_contact_phone', u.emergency_contact_phone,
+               'emergency_contact_relationship', u.emergency_contact_relationship,
+               'notes', u.notes,
+               'created_at', u.created_at,
+               'updated_at', u.updated_at,
+               'deleted_at', u.deleted_at,
+               'scheduled_deletion_at', u.scheduled_deletion_at,
+               'stored_value_balance_cents', u.stored_value_balance,
+               'children', COALESCE((SELECT json_agg(json_build_object('id', c.id, 'first_name', c.first_name, 'last_name', c.last_name))
+                                     FROM users.users c
+                                     WHERE c.parent_id = u.id), '[]'::json),
+               'athlete', (SELECT json_build_object('wins', a.wins, 'losses', a.losses, 'points', a.points,
+                                                    'steals', a.steals, 'assists', a.assists, 'rebounds', a.rebounds,
+                                                    'photo_url', a.photo_url)
+                           FROM athletic.athletes a
+                           WHERE a.id = u.id),
+               'medical_info', (SELECT json_build_object('allergies', m.allergies,
+                                                         'medical_conditions', m.medical_conditions,
+                                                         'medications', m.medications,
+                                                         'physician_name', m.physician_name,
+                                                         'physician_phone', m.physician_phone,
+                                                         'notes', m.notes, 'updated_at', m.updated_at)
+                                FROM users.medical_info m
+                                WHERE m.user_id = u.id)
+       )::json AS profile
+FROM users.users u
+WHERE u.id = $1;
+
+-- name: GetExportEnrollments :one
+SELECT json_build_object(
+               'events', COALESCE((SELECT json_agg(json_build_object(
+                       'event_id', e.id, 'program', p.name, 'start_at', e.start_at, 'end_at', e.end_at,
+                       'enrolled_at', ce.created_at, 'checked_in_at', ce.checked_in_at,
+                       'is_cancelled', ce.is_cancelled, 'payment_status', ce.payment_status) ORDER BY ce.created_at)
+                                   FROM events.customer_enrollment ce
+                                            JOIN events.events e ON e.id = ce.event_id
+                                            LEFT JOIN program.programs p ON p.id = e.program_id
+                                   WHERE ce.customer_id = $1), '[]'::json),
+               'programs', COALESCE((SELECT json_agg(json_build_object(
+                       'program_id', p.id, 'program', p.name, 'enrolled_at', pe.created_at,
+                       'is_cancelled', pe.is_cancelled, 'payment_status', pe.payment_status) ORDER BY pe.created_at)
+                                     FROM program.customer_enrollment pe
+                                              JOIN program.programs p ON p.id = pe.program_id
+                                     WHERE pe.customer_id = $1), '[]'::json),
+               'memberships', COALESCE((SELECT json_agg(json_build_object(
+                       'membership_plan', mp.name, 'status', cmp.status, 'start_date', cmp.start_date,
+                       'renewal_date', cmp.renewal_date, 'subscription_status', cmp.subscription_status,
+                       'next_billing_date', cmp.next_billing_date, 'created_at', cmp.created_at) ORDER BY cmp.created_at)
+                                        FROM users.customer_membership_plans cmp
+                                                 JOIN membership.membership_plans mp ON mp.id = cmp.membership_plan_id
+                                        WHERE cmp.customer_id = $1), '[]'::json),
+               'membership_freezes', COALESCE((SELECT json_agg(json_build_object(
+                       'reason', mf.reason, 'notes', mf.notes, 'start_date', mf.start_date, 'end_date', mf.end_date,
+                       'status', mf.status, 'fee_cents', mf.fee_amount, 'credits_forfeited', mf.credits_forfeited,
+                       'created_at', mf.created_at) ORDER BY mf.created_at)
+                                               FROM users.membership_freezes mf
+                                               WHERE mf.customer_id = $1), '[]'::json)
+       )::json AS enrollments;
+
+-- name: GetExportPayments :one
+SELECT json_build_object(
+               'payments', COALESCE((SELECT json_agg(json_build_object(
+                       'id', pt.id, 'date', pt.transaction_date, 'type', pt.transaction_type,
+                       'description', pt.description, 'original_amount', pt.original_amount,
+                       'discount_amount', pt.discount_amount, 'subsidy_amount', pt.subsidy_amount,
+                       'amount_paid', pt.customer_paid, 'currency', pt.currency, 'status', pt.payment_status,
+                       'payment_method', pt.payment_method) ORDER BY pt.transaction_date)
+                                     FROM payments.payment_transactions pt
+                                     WHERE pt.customer_id = $1), '[]'::json),
+               'refunds', COALESCE((SELECT json_agg(json_build_object(
+                       'payment_id', r.transaction_id, 'amount', r.amount, 'reason', r.reason,
+                       'created_at', r.created_at) ORDER BY r.created_at)
+                                    FROM payments.refunds r
+                                             JOIN payments.payment_transactions pt ON pt.id = r.transaction_id
+                                    WHERE pt.customer_id = $1), '[]'::json),
+               'gift_cards_purchased', COALESCE((SELECT json_agg(json_build_object(
+                       'initial_cents', gc.initial_cents, 'remaining_cents', gc.remaining_cents,
+                       'status', gc.status, 'recipient_name', gc.recipient_name,
+                       'recipient_email', gc.recipient_email, 'created_at', gc.created_at) ORDER BY gc.created_at)
+                                                 FROM gift_cards.gift_cards gc
+                                                 WHERE gc.purchaser_id = $1), '[]'::json)
+       )::json AS payments;
+
+-- name: GetExportCredits :one
+SELECT json_build_object(
+               'balance', COALESCE((SELECT cc.credits FROM users.customer_credits cc WHERE cc.customer_id = $1), 0),
+               'grants', COALESCE((SELECT json_agg(json_build_object(
+                       'source', cg.source, 'amount', cg.amount, 'remaining', cg.remaining,
+                       'description', cg.description, 'expires_at', cg.expires_at,
+                       'created_at', cg.created_at) ORDER BY cg.created_at)
+                                   FROM users.credit_grants cg
+                                   WHERE cg.customer_id = $1), '[]'::json),
+               'transactions', COALESCE((SELECT json_agg(json_build_object(
+                       'amount', ct.amount, 'type', ct.transaction_type, 'event_id', ct.event_id,
+                       'description', ct.description, 'created_at', ct.created_at) ORDER BY ct.created_at)
+                                         FROM users.credit_transactions ct
+                                         WHERE ct.customer_id = $1), '[]'::json),
+               'subsidies', COALESCE((SELECT json_agg(row_to_json(cs))
+                                      FROM subsidies.customer_subsidies cs
+                                      WHERE cs.customer_id = $1), '[]'::json)
+       )::json AS credits;
+
+-- name: GetExportWaivers :one
+SELECT json_build_object(
+               'signatures', COALESCE((SELECT json_agg(json_build_object(
+                       'waiver', w.waiver_name, 'is_signed', ws.is_signed, 'updated_at', ws.updated_at)
+                                                       ORDER BY ws.updated_at)
+                                       FROM waiver.waiver_signing ws
+                                                JOIN waiver.waiver w ON w.id = ws.waiver_id
+                                       WHERE ws.user_id = $1), '[]'::json),
+               'uploads', COALESCE((SELECT json_agg(json_build_object(
+                       'file_name', wu.file_name, 'file_type', wu.file_type, 'notes', wu.notes,
+                       'created_at', wu.created_at) ORDER BY wu.created_at)
+                                    FROM waiver.waiver_uploads wu
+                                    WHERE wu.user_id = $1), '[]'::json)
+       )::json AS waivers;
+
+-- name: ListUserFiles :many
+-- Files the user uploaded or that were uploaded about them, with what each one is.
+SELECT 'athlete_photo'::text AS category, a.photo_url::text AS url
+FROM athletic.athletes a
+WHERE a.id = $1
+  AND a.photo_url IS NOT NULL
+UNION ALL
+SELECT 'membership_photo'::text, cmp.photo_url::text
+FROM users.customer_membership_plans cmp
+WHERE cmp.customer_id = $1
+  AND cmp.photo_url IS NOT NULL
+UNION ALL
+SELECT 'medical_note'::text, mf.medical_note_url::text
+FROM users.membership_freezes mf
+WHERE mf.customer_id = $1
+  AND mf.medical_note_url IS NOT NULL
+UNION ALL
+SELECT 'waiver'::text, wu.file_url::text
+FROM waiver.waiver_uploads wu
+WHERE wu.user_id = $1;
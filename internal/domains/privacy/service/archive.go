@@ -0,0 +1,108 @@
+package privacy
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	values "api/internal/domains/privacy/values"
+)
+
+// archiveFile is a file to put in an export ZIP
+type archiveFile struct {
+	Path string
+	Data []byte
+}
+
+// uploadPath is where an uploaded file goes in an export ZIP. The index keeps files
+// with the same name apart.
+func uploadPath(file values.UserFile, index int) string {
+	name := "file"
+	if parsed, err := url.Parse(file.URL); err == nil {
+		if base := path.Base(parsed.Path); base != "." && base != "/" {
+			name = base
+		}
+	}
+	return fmt.Sprintf("uploads/%s/%d-%s", file.Category, index+1, name)
+}
+
+// buildArchive packs an export into a ZIP: one JSON file per section of data, the
+// user's uploaded files under uploads/ and a README describing them. missing lists
+// the uploads that could not be read, so the user knows the export is incomplete.
+func buildArchive(data values.ExportData, uploads []archiveFile, missing []values.UserFile, generatedAt time.Time) ([]byte, error) {
+	sections := []struct {
+		name string
+		data json.RawMessage
+	}{
+		{"profile.json", data.Profile},
+		{"enrollments.json", data.Enrollments},
+		{"payments.json", data.Payments},
+		{"credits.json", data.Credits},
+		{"waivers.json", data.Waivers},
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+
+	write := func(name string, content []byte) error {
+		w, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: generatedAt})
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(content)
+		return err
+	}
+
+	if err := write("README.txt", []byte(archiveReadme(uploads, missing, generatedAt))); err != nil {
+		return nil, err
+	}
+
+	for _, section := range sections {
+		content := []byte("null")
+		if len(section.data) > 0 {
+			var indented bytes.Buffer
+			if err := json.Indent(&indented, section.data, "", "  "); err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", section.name, err)
+			}
+			content = indented.Bytes()
+		}
+		if err := write(section.name, content); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, upload := range uploads {
+		if err := write(upload.Path, upload.Data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func archiveReadme(uploads []archiveFile, missing []values.UserFile, generatedAt time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Rise Sports Complex - personal data export\nGenerated %s\n\n", generatedAt.UTC().Format(time.RFC1123))
+	b.WriteString("profile.json      Your account details, family links and athlete stats\n")
+	b.WriteString("enrollments.json  Event and program enrollments, memberships and freezes\n")
+	b.WriteString("payments.json     Payments, refunds and gift cards you bought\n")
+	b.WriteString("credits.json      Credit balance, grants, transactions and subsidies\n")
+	b.WriteString("waivers.json      Waivers you signed and waiver files on record\n")
+	fmt.Fprintf(&b, "uploads/          %d uploaded files (photos, waivers, medical notes)\n", len(uploads))
+
+	if len(missing) > 0 {
+		b.WriteString("\nThese files could not be read when the export was made. Contact us for a copy:\n")
+		for _, file := range missing {
+			fmt.Fprintf(&b, "- %s: %s\n", file.Category, file.URL)
+		}
+	}
+	return b.String()
+}
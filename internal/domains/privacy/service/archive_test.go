@@ -0,0 +1,63 @@
+package privacy
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	values "api/internal/domains/privacy/values"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUploadPath(t *testing.T) {
+	file := values.UserFile{
+		Category: "waiver",
+		URL:      "https://storage.googleapis.com/rise-sports/waivers/Jane_Doe_1234abcd/waiver_2025%20signed.pdf",
+	}
+	require.Equal(t, "uploads/waiver/1-waiver_2025 signed.pdf", uploadPath(file, 0))
+
+	require.Equal(t, "uploads/athlete_photo/3-file", uploadPath(values.UserFile{Category: "athlete_photo", URL: ""}, 2))
+}
+
+func TestBuildArchive(t *testing.T) {
+	data := values.ExportData{
+		Profile:     json.RawMessage(`{"first_name":"Jane"}`),
+		Enrollments: json.RawMessage(`{"events":[]}`),
+		Payments:    json.RawMessage(`{"payments":[]}`),
+		Credits:     json.RawMessage(`{"balance":4}`),
+	}
+	uploads := []archiveFile{{Path: "uploads/waiver/1-waiver.pdf", Data: []byte("%PDF")}}
+	missing := []values.UserFile{{Category: "medical_note", URL: "https://example.com/note.pdf"}}
+
+	content, err := buildArchive(data, uploads, missing, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	require.NoError(t, err)
+
+	reader, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	require.NoError(t, err)
+
+	files := map[string]string{}
+	for _, f := range reader.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		body, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		files[f.Name] = string(body)
+	}
+
+	require.Len(t, files, 7)
+	require.Equal(t, "{\n  \"first_name\": \"Jane\"\n}", files["profile.json"])
+	require.Equal(t, "null", files["waivers.json"])
+	require.Equal(t, "%PDF", files["uploads/waiver/1-waiver.pdf"])
+	require.Contains(t, files["README.txt"], "1 uploaded files")
+	require.Contains(t, files["README.txt"], "- medical_note: https://example.com/note.pdf")
+}
+
+func TestBuildArchiveRejectsInvalidJSON(t *testing.T) {
+	_, err := buildArchive(values.ExportData{Profile: json.RawMessage(`{`)}, nil, nil, time.Now())
+	require.Error(t, err)
+}
@@ -0,0 +1,507 @@
+package privacy
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"path"
+	"time"
+
+	"api/internal/di"
+	auditValues "api/internal/domains/audit/audit_logs/values"
+	staffActivityLogs "api/internal/domains/audit/staff_activity_logs/service"
+	firebaseService "api/internal/domains/identity/service/firebase"
+	repo "api/internal/domains/privacy/persistence"
+	values "api/internal/domains/privacy/values"
+	errLib "api/internal/libs/errors"
+	"api/internal/services/gcp"
+	"api/internal/services/hubspot"
+	contextUtils "api/utils/context"
+	txUtils "api/utils/db"
+	"api/utils/email"
+
+	"github.com/google/uuid"
+	"github.com/stripe/stripe-go/v81"
+	"github.com/stripe/stripe-go/v81/customer"
+)
+
+const (
+	// batchSize is how many requests one job run claims.
+	batchSize = 10
+	// staleAfter is how long a request can stay processing before it is assumed its run
+	// died and it is claimed again.
+	staleAfter = time.Hour
+)
+
+// Service handles data subject requests: exports of a user's personal data and
+// erasures that remove it from every system it is kept in. Requests are processed by
+// the data request job; erasures of accounts whose deletion grace period ended are
+// processed straight away by the account deletion job.
+//
+// Erasure keeps the account row and the financial records that reference it (payments,
+// refunds, credits, gift cards, subsidies), which have to be retained, but strips
+// everything that identifies the person from them. Entries of the audit log are never
+// changed; they only hold IDs, the fields staff changed with personal values redacted,
+// and a reference to the staff activity describing the change, which can be erased.
+type Service struct {
+	repo                     *repo.Repository
+	staffActivityLogsService *staffActivityLogs.Service
+	firebaseService          *firebaseService.Service
+	hubspotService           *hubspot.Service
+	db                       *sql.DB
+}
+
+func NewService(container *di.Container) *Service {
+	return &Service{
+		repo:                     repo.NewRepository(container),
+		staffActivityLogsService: staffActivityLogs.NewService(container),
+		firebaseService:          firebaseService.NewFirebaseService(container),
+		hubspotService:           container.HubspotService,
+		db:                       container.DB,
+	}
+}
+
+// RequestExport queues an export of the user's personal data.
+func (s *Service) RequestExport(ctx context.Context, userID uuid.UUID) (values.DataRequest, *errLib.CommonError) {
+	return s.repo.CreateRequest(ctx, userID, values.TypeExport, values.StatusPending, userID, nil)
+}
+
+// RequestErasure asks for the user's personal data to be erased. The request waits for
+// staff to review it. Staff accounts have to be removed by an administrator first.
+func (s *Service) RequestErasure(ctx context.Context, userID uuid.UUID, reason *string) (values.DataRequest, *errLib.CommonError) {
+	if isStaff, err := contextUtils.IsStaff(ctx); err != nil {
+		return values.DataRequest{}, err
+	} else if isStaff {
+		return values.DataRequest{}, errLib.New("Staff accounts must be removed by an administrator before their data can be erased", http.StatusForbidden)
+	}
+
+	open, err := s.repo.GetOpenRequest(ctx, userID, values.TypeErasure)
+	if err != nil {
+		return values.DataRequest{}, err
+	}
+	if open != nil {
+		return values.DataRequest{}, errLib.New("There is already an open erasure request for this account", http.StatusConflict)
+	}
+
+	return s.repo.CreateRequest(ctx, userID, values.TypeErasure, values.StatusPendingReview, userID, reason)
+}
+
+func (s *Service) ListMyRequests(ctx context.Context, userID uuid.UUID) ([]values.DataRequest, *errLib.CommonError) {
+	return s.repo.ListUserRequests(ctx, userID)
+}
+
+// DownloadExport returns the ZIP of one of the user's finished exports and its file name.
+func (s *Service) DownloadExport(ctx context.Context, userID, requestID uuid.UUID) ([]byte, string, *errLib.CommonError) {
+	request, err := s.repo.GetRequest(ctx, requestID)
+	if err != nil {
+		return nil, "", err
+	}
+	if request.UserID != userID || request.Type != values.TypeExport {
+		return nil, "", errLib.New("Data request not found", http.StatusNotFound)
+	}
+	if request.Status == values.StatusExpired {
+		return nil, "", errLib.New("This export has expired; request a new one", http.StatusGone)
+	}
+	if request.Status != values.StatusCompleted || request.ExportObject == nil {
+		return nil, "", errLib.New("This export is not ready yet", http.StatusConflict)
+	}
+
+	data, err := gcp.ReadObjectFromGCP(*request.ExportObject)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, path.Base(*request.ExportObject), nil
+}
+
+func (s *Service) ListRequests(ctx context.Context, filter values.ListFilter) ([]values.DataRequest, *errLib.CommonError) {
+	return s.repo.ListRequests(ctx, filter)
+}
+
+// GetRequest returns a request with the progress of each step for erasures.
+func (s *Service) GetRequest(ctx context.Context, id uuid.UUID) (values.DataRequest, *errLib.CommonError) {
+	request, err := s.repo.GetRequest(ctx, id)
+	if err != nil {
+		return values.DataRequest{}, err
+	}
+	if request.Type == values.TypeErasure {
+		if request.Steps, err = s.repo.ListSteps(ctx, id); err != nil {
+			return values.DataRequest{}, err
+		}
+	}
+	return request, nil
+}
+
+// ReviewErasure approves or rejects an erasure waiting for review. An approved erasure
+// is run by the next data request job.
+func (s *Service) ReviewErasure(ctx context.Context, review values.ReviewValues) (values.DataRequest, *errLib.CommonError) {
+	status, action, verb := values.StatusRejected, "reject", "Rejected"
+	if review.Approve {
+		status, action, verb = values.StatusPending, "approve", "Approved"
+	}
+
+	var reviewed values.DataRequest
+	txErr := txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		var err *errLib.CommonError
+		if reviewed, err = s.repo.WithTx(tx).Review(ctx, review.RequestID, status, review.ReviewerID, review.Notes); err != nil {
+			return err
+		}
+		return s.staffActivityLogsService.RecordChange(ctx, tx, review.ReviewerID, auditValues.Entry{
+			EntityType:  "data_request",
+			EntityID:    reviewed.ID.String(),
+			Action:      action,
+			Before:      map[string]interface{}{"status": values.StatusPendingReview},
+			After:       map[string]interface{}{"status": reviewed.Status, "review_notes": reviewed.ReviewNotes},
+			Description: fmt.Sprintf("%s erasure request %s for user %s", verb, reviewed.ID, reviewed.UserID),
+		})
+	})
+	if txErr != nil {
+		return values.DataRequest{}, txErr
+	}
+	return reviewed, nil
+}
+
+// RetryRequest queues a failed request again. A retried erasure skips the steps that
+// already finished.
+func (s *Service) RetryRequest(ctx context.Context, id, staffID uuid.UUID) (values.DataRequest, *errLib.CommonError) {
+	var retried values.DataRequest
+	txErr := txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		var err *errLib.CommonError
+		if retried, err = s.repo.WithTx(tx).Retry(ctx, id); err != nil {
+			return err
+		}
+		return s.staffActivityLogsService.RecordChange(ctx, tx, staffID, auditValues.Entry{
+			EntityType:  "data_request",
+			EntityID:    retried.ID.String(),
+			Action:      "retry",
+			Before:      map[string]interface{}{"status": values.StatusFailed},
+			After:       map[string]interface{}{"status": retried.Status},
+			Description: fmt.Sprintf("Retried %s request %s for user %s", retried.Type, retried.ID, retried.UserID),
+		})
+	})
+	if txErr != nil {
+		return values.DataRequest{}, txErr
+	}
+	return retried, nil
+}
+
+// ScheduleErasure makes sure the user has an erasure queued without waiting for review,
+// for accounts whose deletion is due. An erasure the user asked for is approved, and
+// a failed one is retried instead of opening another.
+func (s *Service) ScheduleErasure(ctx context.Context, userID uuid.UUID, reason string) (values.DataRequest, *errLib.CommonError) {
+	open, err := s.repo.GetOpenRequest(ctx, userID, values.TypeErasure)
+	if err != nil {
+		return values.DataRequest{}, err
+	}
+	if open == nil {
+		return s.repo.CreateRequest(ctx, userID, values.TypeErasure, values.StatusPending, uuid.Nil, &reason)
+	}
+
+	switch open.Status {
+	case values.StatusPendingReview:
+		return s.repo.Review(ctx, open.ID, values.StatusPending, uuid.Nil, &reason)
+	case values.StatusFailed:
+		return s.repo.Retry(ctx, open.ID)
+	default:
+		return *open, nil
+	}
+}
+
+// ProcessRequest runs a pending request now. It does nothing when the request is not
+// pending, e.g. because the data request job is already running it.
+func (s *Service) ProcessRequest(ctx context.Context, id uuid.UUID) *errLib.CommonError {
+	request, err := s.repo.Claim(ctx, id)
+	if err != nil || request == nil {
+		return err
+	}
+	return s.process(ctx, *request)
+}
+
+// ProcessPending runs a batch of pending requests and returns how many completed and
+// how many failed.
+func (s *Service) ProcessPending(ctx context.Context, now time.Time) (completed, failed int, err *errLib.CommonError) {
+	requests, err := s.repo.ClaimPending(ctx, now.Add(-staleAfter), batchSize)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, request := range requests {
+		if processErr := s.process(ctx, request); processErr != nil {
+			failed++
+			continue
+		}
+		completed++
+	}
+	return completed, failed, nil
+}
+
+// ExpireExports deletes the ZIPs of exports past their expiry and returns how many it
+// deleted.
+func (s *Service) ExpireExports(ctx context.Context, now time.Time) (int, *errLib.CommonError) {
+	requests, err := s.repo.ListExpiredExports(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, request := range requests {
+		if deleteErr := gcp.DeleteObjectFromGCP(*request.ExportObject); deleteErr != nil {
+			log.Printf("[PRIVACY] Failed to delete export %s: %s", request.ID, deleteErr.Message)
+			continue
+		}
+		if expireErr := s.repo.ExpireExport(ctx, request.ID); expireErr != nil {
+			continue
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+// process runs a claimed request and records how it ended.
+func (s *Service) process(ctx context.Context, request values.DataRequest) *errLib.CommonError {
+	var err *errLib.CommonError
+	switch request.Type {
+	case values.TypeExport:
+		err = s.runExport(ctx, request)
+	case values.TypeErasure:
+		err = s.runErasure(ctx, request)
+	default:
+		err = errLib.New("Unknown request type "+request.Type, http.StatusInternalServerError)
+	}
+
+	if err != nil {
+		log.Printf("[PRIVACY] %s request %s for user %s failed: %s", request.Type, request.ID, request.UserID, err.Message)
+		if failErr := s.repo.Fail(ctx, request.ID, err.Message); failErr != nil {
+			return failErr
+		}
+		return err
+	}
+
+	log.Printf("[PRIVACY] Completed %s request %s for user %s", request.Type, request.ID, request.UserID)
+	return nil
+}
+
+// runExport builds the user's export ZIP, stores it and tells the user it is ready.
+// Uploads that cannot be read are listed in the ZIP's README instead of failing the
+// export.
+func (s *Service) runExport(ctx context.Context, request values.DataRequest) *errLib.CommonError {
+	subject, err := s.repo.GetDataSubject(ctx, request.UserID)
+	if err != nil {
+		return err
+	}
+	data, err := s.repo.GetExportData(ctx, request.UserID)
+	if err != nil {
+		return err
+	}
+
+	var (
+		uploads []archiveFile
+		missing []values.UserFile
+	)
+	for i, file := range data.Files {
+		content, readErr := gcp.ReadFileFromGCP(file.URL)
+		if readErr != nil {
+			log.Printf("[PRIVACY] Could not read %s for export %s: %s", file.URL, request.ID, readErr.Message)
+			missing = append(missing, file)
+			continue
+		}
+		uploads = append(uploads, archiveFile{Path: uploadPath(file, i), Data: content})
+	}
+
+	now := time.Now()
+	archive, archiveErr := buildArchive(data, uploads, missing, now)
+	if archiveErr != nil {
+		log.Printf("[PRIVACY] Failed to build export %s: %v", request.ID, archiveErr)
+		return errLib.New("Failed to build export archive", http.StatusInternalServerError)
+	}
+
+	objectPath := fmt.Sprintf("exports/%s/%s.zip", request.UserID, request.ID)
+	if err = gcp.UploadFileToGCP(archive, objectPath, "application/zip"); err != nil {
+		return err
+	}
+
+	expiresAt := now.Add(values.ExportRetention)
+	if err = s.repo.Complete(ctx, request.ID, &objectPath, &expiresAt); err != nil {
+		return err
+	}
+
+	if subject.Email != "" {
+		go email.SendDataExportReadyEmail(subject.Email, subject.FirstName, expiresAt.Format("January 2, 2006"))
+	}
+	return nil
+}
+
+// runErasure runs the steps of an erasure that have not finished yet, in order, and
+// stops at the first one that fails so it can be retried.
+func (s *Service) runErasure(ctx context.Context, request values.DataRequest) *errLib.CommonError {
+	if err := s.repo.InitSteps(ctx, request.ID); err != nil {
+		return err
+	}
+	steps, err := s.repo.ListSteps(ctx, request.ID)
+	if err != nil {
+		return err
+	}
+	subject, err := s.repo.GetDataSubject(ctx, request.UserID)
+	if err != nil {
+		return err
+	}
+
+	runners := map[string]func(context.Context, values.DataRequest, values.DataSubject) (string, string, *errLib.CommonError){
+		values.StepStripe:     s.eraseStripe,
+		values.StepHubspot:    s.eraseHubspot,
+		values.StepStorage:    s.eraseStorage,
+		values.StepPushTokens: s.erasePushTokens,
+		values.StepFirebase:   s.eraseFirebase,
+		values.StepDatabase:   s.eraseDatabase,
+	}
+
+	for _, step := range steps {
+		if step.Status == values.StepCompleted || step.Status == values.StepSkipped {
+			continue
+		}
+
+		status, detail, stepErr := runners[step.Step](ctx, request, subject)
+		if stepErr != nil {
+			status, detail = values.StepFailed, stepErr.Message
+		}
+		if err := s.repo.UpdateStep(ctx, request.ID, step.Step, status, detail); err != nil {
+			return err
+		}
+		if stepErr != nil {
+			return errLib.New(fmt.Sprintf("Erasure step %s failed: %s", step.Step, stepErr.Message), stepErr.HTTPCode)
+		}
+	}
+
+	return s.repo.Complete(ctx, request.ID, nil, nil)
+}
+
+// eraseStripe deletes the Stripe customer, which also cancels their subscriptions.
+// Stripe keeps the customer's invoices and charges for accounting.
+func (s *Service) eraseStripe(ctx context.Context, _ values.DataRequest, subject values.DataSubject) (string, string, *errLib.CommonError) {
+	if subject.StripeCustomerID == "" {
+		return values.StepSkipped, "No Stripe customer", nil
+	}
+
+	detail := "Deleted Stripe customer"
+	if _, err := customer.Del(subject.StripeCustomerID, nil); err != nil {
+		var stripeErr *stripe.Error
+		if !errors.As(err, &stripeErr) || stripeErr.Code != stripe.ErrorCodeResourceMissing {
+			log.Printf("[PRIVACY] Failed to delete Stripe customer of user %s: %v", subject.UserID, err)
+			return "", "", errLib.New("Failed to delete Stripe customer", http.StatusBadGateway)
+		}
+		detail = "Stripe customer was already deleted"
+	}
+
+	if err := s.repo.ClearStripeCustomer(ctx, subject.UserID); err != nil {
+		return "", "", err
+	}
+	return values.StepCompleted, detail, nil
+}
+
+func (s *Service) eraseHubspot(ctx context.Context, _ values.DataRequest, subject values.DataSubject) (string, string, *errLib.CommonError) {
+	if subject.HubspotID == "" {
+		return values.StepSkipped, "No HubSpot contact", nil
+	}
+	if s.hubspotService == nil {
+		return "", "", errLib.New("HubSpot is not configured", http.StatusServiceUnavailable)
+	}
+
+	if err := s.hubspotService.DeleteUser(subject.HubspotID); err != nil {
+		return "", "", err
+	}
+	if err := s.repo.ClearHubspotID(ctx, subject.UserID); err != nil {
+		return "", "", err
+	}
+	return values.StepCompleted, "Deleted HubSpot contact", nil
+}
+
+// eraseStorage deletes the user's uploads and export ZIPs. Files stored outside the
+// bucket are left alone; the database step removes the links to them.
+func (s *Service) eraseStorage(ctx context.Context, _ values.DataRequest, subject values.DataSubject) (string, string, *errLib.CommonError) {
+	files, err := s.repo.ListUserFiles(ctx, subject.UserID)
+	if err != nil {
+		return "", "", err
+	}
+	requests, err := s.repo.ListUserRequests(ctx, subject.UserID)
+	if err != nil {
+		return "", "", err
+	}
+
+	var objects []string
+	external := 0
+	for _, file := range files {
+		objectPath, pathErr := gcp.ObjectPathFromURL(file.URL)
+		if pathErr != nil {
+			external++
+			continue
+		}
+		objects = append(objects, objectPath)
+	}
+	for _, request := range requests {
+		if request.ExportObject != nil {
+			objects = append(objects, *request.ExportObject)
+		}
+	}
+
+	if len(objects) == 0 && external == 0 {
+		return values.StepSkipped, "No stored files", nil
+	}
+
+	for _, objectPath := range objects {
+		if err := gcp.DeleteObjectFromGCP(objectPath); err != nil {
+			return "", "", err
+		}
+	}
+
+	detail := fmt.Sprintf("Deleted %d files", len(objects))
+	if external > 0 {
+		detail += fmt.Sprintf("; %d files stored outside our storage were not deleted", external)
+	}
+	return values.StepCompleted, detail, nil
+}
+
+func (s *Service) erasePushTokens(ctx context.Context, _ values.DataRequest, subject values.DataSubject) (string, string, *errLib.CommonError) {
+	err := txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		return s.repo.WithTx(tx).DeletePushTokens(ctx, subject.UserID)
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return values.StepCompleted, "Deleted push tokens", nil
+}
+
+func (s *Service) eraseFirebase(ctx context.Context, _ values.DataRequest, subject values.DataSubject) (string, string, *errLib.CommonError) {
+	if subject.Email == "" {
+		return values.StepSkipped, "No login account", nil
+	}
+
+	if err := s.firebaseService.DeleteUser(ctx, subject.Email); err != nil {
+		if err.HTTPCode == http.StatusNotFound {
+			return values.StepSkipped, "No login account", nil
+		}
+		return "", "", err
+	}
+	return values.StepCompleted, "Deleted login account", nil
+}
+
+// eraseDatabase deletes and anonymizes the user's records in one transaction and
+// records the erasure in the audit log.
+func (s *Service) eraseDatabase(ctx context.Context, request values.DataRequest, subject values.DataSubject) (string, string, *errLib.CommonError) {
+	err := txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		if err := s.repo.WithTx(tx).EraseUserData(ctx, subject.UserID); err != nil {
+			return err
+		}
+		return s.staffActivityLogsService.RecordChange(ctx, tx, uuid.Nil, auditValues.Entry{
+			EntityType:  "user",
+			EntityID:    subject.UserID.String(),
+			Action:      "erase",
+			Description: fmt.Sprintf("Erased personal data of user %s (request %s)", subject.UserID, request.ID),
+		})
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return values.StepCompleted, "Deleted personal records and anonymized retained financial records", nil
+}
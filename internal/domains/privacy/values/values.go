@@ -0,0 +1,134 @@
+package values
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Request types. An export gives the user a ZIP of their personal data; an erasure
+// removes it everywhere it is kept.
+const (
+	TypeExport  = "export"
+	TypeErasure = "erasure"
+)
+
+// Request statuses. A customer's erasure waits in pending_review until staff approve
+// or reject it. An export becomes expired once its ZIP has been deleted.
+const (
+	StatusPendingReview = "pending_review"
+	StatusPending       = "pending"
+	StatusProcessing    = "processing"
+	StatusCompleted     = "completed"
+	StatusFailed        = "failed"
+	StatusRejected      = "rejected"
+	StatusExpired       = "expired"
+)
+
+// Erasure steps, one per system personal data is removed from.
+const (
+	StepStripe     = "stripe"
+	StepHubspot    = "hubspot"
+	StepStorage    = "storage"
+	StepPushTokens = "push_tokens"
+	StepFirebase   = "firebase"
+	StepDatabase   = "database"
+)
+
+// ErasureSteps is the order an erasure runs its steps in. The external systems go
+// first because the database step clears the IDs they are looked up by, and the
+// uploaded files go before the rows that point at them.
+var ErasureSteps = []string{StepStripe, StepHubspot, StepStorage, StepPushTokens, StepFirebase, StepDatabase}
+
+// Erasure step statuses. A step is skipped when there was nothing to remove, e.g. a
+// user who never had a Stripe customer.
+const (
+	StepPending   = "pending"
+	StepCompleted = "completed"
+	StepFailed    = "failed"
+	StepSkipped   = "skipped"
+)
+
+// ExportRetention is how long a finished export can be downloaded before its ZIP is
+// deleted.
+const ExportRetention = 7 * 24 * time.Hour
+
+type DataRequest struct {
+	ID              uuid.UUID
+	UserID          uuid.UUID
+	Type            string
+	Status          string
+	RequestedBy     *uuid.UUID
+	Reason          *string
+	ReviewedBy      *uuid.UUID
+	ReviewedAt      *time.Time
+	ReviewNotes     *string
+	ExportObject    *string
+	ExportExpiresAt *time.Time
+	Error           *string
+	Attempts        int32
+	StartedAt       *time.Time
+	CompletedAt     *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+
+	// Set on the admin queue only
+	UserFirstName *string
+	UserLastName  *string
+	UserEmail     *string
+
+	// Set for erasures when the request is fetched on its own
+	Steps []ErasureStep
+}
+
+type ErasureStep struct {
+	Step      string
+	Status    string
+	Detail    *string
+	Attempts  int32
+	UpdatedAt time.Time
+}
+
+// ListFilter filters the admin queue. Empty fields match everything.
+type ListFilter struct {
+	Status string
+	Type   string
+	UserID uuid.UUID
+	Limit  int32
+	Offset int32
+}
+
+// ReviewValues approves or rejects an erasure waiting for review.
+type ReviewValues struct {
+	RequestID  uuid.UUID
+	ReviewerID uuid.UUID
+	Approve    bool
+	Notes      *string
+}
+
+// UserFile is an uploaded file kept about a user, e.g. a waiver or a medical note.
+type UserFile struct {
+	Category string
+	URL      string
+}
+
+// ExportData is the personal data held about a user, one JSON document per section.
+type ExportData struct {
+	Profile     json.RawMessage
+	Enrollments json.RawMessage
+	Payments    json.RawMessage
+	Credits     json.RawMessage
+	Waivers     json.RawMessage
+	Files       []UserFile
+}
+
+// DataSubject is who a request is about and how to find them in other systems.
+// Empty fields mean the user has no record there.
+type DataSubject struct {
+	UserID           uuid.UUID
+	FirstName        string
+	Email            string
+	HubspotID        string
+	StripeCustomerID string
+}
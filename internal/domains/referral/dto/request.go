@@ -0,0 +1,92 @@
+package referral
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	values "api/internal/domains/referral/values"
+	errLib "api/internal/libs/errors"
+	"api/internal/libs/validators"
+)
+
+// SettingsRequestDto sets what each side of a qualified referral earns. Amounts are a
+// number of credits for credits rewards and cents for coupon and balance rewards.
+type SettingsRequestDto struct {
+	Enabled              bool   `json:"enabled"`
+	ReferrerRewardType   string `json:"referrer_reward_type" validate:"required,oneof=none credits coupon balance" example:"balance"`
+	ReferrerRewardAmount int32  `json:"referrer_reward_amount" validate:"min=0,max=100000" example:"2000"`
+	RefereeRewardType    string `json:"referee_reward_type" validate:"required,oneof=none credits coupon balance" example:"coupon"`
+	RefereeRewardAmount  int32  `json:"referee_reward_amount" validate:"min=0,max=100000" example:"1000"`
+}
+
+func (dto *SettingsRequestDto) ToValues() (values.UpdateSettingsValues, *errLib.CommonError) {
+	if err := validators.ValidateDto(dto); err != nil {
+		return values.UpdateSettingsValues{}, err
+	}
+	v := values.UpdateSettingsValues{
+		Enabled:              dto.Enabled,
+		ReferrerRewardType:   values.RewardType(dto.ReferrerRewardType),
+		ReferrerRewardAmount: dto.ReferrerRewardAmount,
+		RefereeRewardType:    values.RewardType(dto.RefereeRewardType),
+		RefereeRewardAmount:  dto.RefereeRewardAmount,
+	}
+	if v.ReferrerRewardType != values.RewardNone && v.ReferrerRewardAmount == 0 {
+		return values.UpdateSettingsValues{}, errLib.New("referrer_reward_amount is required for a referrer reward", http.StatusBadRequest)
+	}
+	if v.RefereeRewardType != values.RewardNone && v.RefereeRewardAmount == 0 {
+		return values.UpdateSettingsValues{}, errLib.New("referee_reward_amount is required for a referee reward", http.StatusBadRequest)
+	}
+	return v, nil
+}
+
+// ParseListQuery reads the staff referral list filter.
+func ParseListQuery(query url.Values) (values.ListFilter, *errLib.CommonError) {
+	filter := values.ListFilter{Status: query.Get("status"), Limit: 20}
+
+	switch filter.Status {
+	case "", values.StatusPending, values.StatusQualified, values.StatusRewarded, values.StatusRejected:
+	default:
+		return values.ListFilter{}, errLib.New("Invalid status", http.StatusBadRequest)
+	}
+	if referrerStr := query.Get("referrer_id"); referrerStr != "" {
+		id, err := validators.ParseUUID(referrerStr)
+		if err != nil {
+			return values.ListFilter{}, err
+		}
+		filter.ReferrerID = id
+	}
+	if limitStr := query.Get("limit"); limitStr != "" {
+		if parsed, parseErr := strconv.Atoi(limitStr); parseErr == nil && parsed > 0 && parsed <= 100 {
+			filter.Limit = int32(parsed)
+		}
+	}
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		if parsed, parseErr := strconv.Atoi(offsetStr); parseErr == nil && parsed >= 0 {
+			filter.Offset = int32(parsed)
+		}
+	}
+	return filter, nil
+}
+
+// ParseStatsWindow reads the referral stats window. It defaults to the 30 days up to now.
+func ParseStatsWindow(query url.Values) (time.Time, time.Time, *errLib.CommonError) {
+	to := time.Now()
+	if toStr := query.Get("to"); toStr != "" {
+		parsed, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, errLib.New("Invalid to: must be an RFC 3339 timestamp", http.StatusBadRequest)
+		}
+		to = parsed
+	}
+	from := to.AddDate(0, 0, -30)
+	if fromStr := query.Get("from"); fromStr != "" {
+		parsed, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, errLib.New("Invalid from: must be an RFC 3339 timestamp", http.StatusBadRequest)
+		}
+		from = parsed
+	}
+	return from, to, nil
+}
@@ -0,0 +1,192 @@
+package referral
+
+import (
+	"time"
+
+	values "api/internal/domains/referral/values"
+
+	"github.com/google/uuid"
+)
+
+type RewardResponseDto struct {
+	ID           uuid.UUID  `json:"id"`
+	Recipient    string     `json:"recipient" example:"referrer"`
+	RewardType   string     `json:"reward_type" example:"balance"`
+	Amount       int32      `json:"amount" example:"2000"` // Credits, or cents for coupon and balance rewards
+	Status       string     `json:"status" example:"issued"`
+	DiscountCode *string    `json:"discount_code,omitempty"`
+	IssuedAt     *time.Time `json:"issued_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// SummaryResponseDto is the caller's referral code with how their invites are doing.
+type SummaryResponseDto struct {
+	Code      string              `json:"code" example:"K7QX3MPA"`
+	Link      string              `json:"link" example:"https://app.example.com/register?ref=K7QX3MPA"`
+	Invited   int64               `json:"invited"`
+	Pending   int64               `json:"pending"`   // Registered, no membership paid yet
+	Qualified int64               `json:"qualified"` // Paid, rewards being issued
+	Rewarded  int64               `json:"rewarded"`
+	Rejected  int64               `json:"rejected"`
+	Rewards   []RewardResponseDto `json:"rewards"`
+}
+
+// ReferrerResponseDto says who a referral code belongs to.
+type ReferrerResponseDto struct {
+	Code      string `json:"code"`
+	FirstName string `json:"first_name"`
+}
+
+type ReferralResponseDto struct {
+	ID                  uuid.UUID  `json:"id"`
+	ReferrerID          uuid.UUID  `json:"referrer_id"`
+	ReferrerName        string     `json:"referrer_name"`
+	RefereeID           uuid.UUID  `json:"referee_id"`
+	RefereeName         string     `json:"referee_name"`
+	Code                string     `json:"code"`
+	Status              string     `json:"status"`
+	RejectionReason     *string    `json:"rejection_reason,omitempty" example:"same_device"`
+	QualifyingInvoiceID *string    `json:"qualifying_invoice_id,omitempty"`
+	QualifiedAt         *time.Time `json:"qualified_at,omitempty"`
+	RewardedAt          *time.Time `json:"rewarded_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
+
+type SettingsResponseDto struct {
+	Enabled              bool       `json:"enabled"`
+	ReferrerRewardType   string     `json:"referrer_reward_type"`
+	ReferrerRewardAmount int32      `json:"referrer_reward_amount"`
+	RefereeRewardType    string     `json:"referee_reward_type"`
+	RefereeRewardAmount  int32      `json:"referee_reward_amount"`
+	UpdatedBy            *uuid.UUID `json:"updated_by,omitempty"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+}
+
+type StatusCountResponseDto struct {
+	Status          string `json:"status"`
+	RejectionReason string `json:"rejection_reason,omitempty"`
+	Referrals       int64  `json:"referrals"`
+}
+
+type RewardTotalResponseDto struct {
+	RewardType string `json:"reward_type"`
+	Rewards    int64  `json:"rewards"`
+	Amount     int64  `json:"amount"` // Credits, or cents for coupon and balance rewards
+}
+
+type TopReferrerResponseDto struct {
+	CustomerID uuid.UUID `json:"customer_id"`
+	FirstName  string    `json:"first_name"`
+	LastName   string    `json:"last_name"`
+	Referrals  int64     `json:"referrals"`
+	Qualified  int64     `json:"qualified"`
+}
+
+type StatsResponseDto struct {
+	From         time.Time                `json:"from"`
+	To           time.Time                `json:"to"`
+	Referrals    int64                    `json:"referrals"` // Created in [from, to)
+	Qualified    int64                    `json:"qualified"` // Of those, qualified or rewarded
+	Rejected     int64                    `json:"rejected"`
+	Statuses     []StatusCountResponseDto `json:"statuses"`      // By status and rejection reason
+	Rewards      []RewardTotalResponseDto `json:"rewards"`       // Issued in [from, to), by type
+	TopReferrers []TopReferrerResponseDto `json:"top_referrers"` // Most qualified referrals
+}
+
+func NewRewardResponses(rewards []values.Reward) []RewardResponseDto {
+	resp := make([]RewardResponseDto, len(rewards))
+	for i, r := range rewards {
+		resp[i] = RewardResponseDto{
+			ID:           r.ID,
+			Recipient:    r.Recipient,
+			RewardType:   string(r.RewardType),
+			Amount:       r.Amount,
+			Status:       r.Status,
+			DiscountCode: r.DiscountCode,
+			IssuedAt:     r.IssuedAt,
+			CreatedAt:    r.CreatedAt,
+		}
+	}
+	return resp
+}
+
+func NewSummaryResponse(v values.Summary) SummaryResponseDto {
+	return SummaryResponseDto{
+		Code:      v.Code,
+		Link:      v.Link,
+		Invited:   v.Invited,
+		Pending:   v.Pending,
+		Qualified: v.Qualified,
+		Rewarded:  v.Rewarded,
+		Rejected:  v.Rejected,
+		Rewards:   NewRewardResponses(v.Rewards),
+	}
+}
+
+func NewReferrerResponse(v values.Referrer) ReferrerResponseDto {
+	return ReferrerResponseDto{Code: v.Code, FirstName: v.FirstName}
+}
+
+func NewReferralResponses(referrals []values.Referral) []ReferralResponseDto {
+	resp := make([]ReferralResponseDto, len(referrals))
+	for i, r := range referrals {
+		resp[i] = ReferralResponseDto{
+			ID:                  r.ID,
+			ReferrerID:          r.ReferrerID,
+			ReferrerName:        r.ReferrerName,
+			RefereeID:           r.RefereeID,
+			RefereeName:         r.RefereeName,
+			Code:                r.Code,
+			Status:              r.Status,
+			RejectionReason:     r.RejectionReason,
+			QualifyingInvoiceID: r.QualifyingInvoiceID,
+			QualifiedAt:         r.QualifiedAt,
+			RewardedAt:          r.RewardedAt,
+			CreatedAt:           r.CreatedAt,
+		}
+	}
+	return resp
+}
+
+func NewSettingsResponse(v values.Settings) SettingsResponseDto {
+	return SettingsResponseDto{
+		Enabled:              v.Enabled,
+		ReferrerRewardType:   string(v.ReferrerRewardType),
+		ReferrerRewardAmount: v.ReferrerRewardAmount,
+		RefereeRewardType:    string(v.RefereeRewardType),
+		RefereeRewardAmount:  v.RefereeRewardAmount,
+		UpdatedBy:            v.UpdatedBy,
+		UpdatedAt:            v.UpdatedAt,
+	}
+}
+
+func NewStatsResponse(v values.Stats) StatsResponseDto {
+	statuses := make([]StatusCountResponseDto, len(v.Statuses))
+	for i, s := range v.Statuses {
+		statuses[i] = StatusCountResponseDto{Status: s.Status, RejectionReason: s.RejectionReason, Referrals: s.Referrals}
+	}
+	rewards := make([]RewardTotalResponseDto, len(v.Rewards))
+	for i, r := range v.Rewards {
+		rewards[i] = RewardTotalResponseDto{RewardType: string(r.RewardType), Rewards: r.Rewards, Amount: r.Amount}
+	}
+	top := make([]TopReferrerResponseDto, len(v.TopReferrers))
+	for i, t := range v.TopReferrers {
+		top[i] = TopReferrerResponseDto{
+			CustomerID: t.CustomerID,
+			FirstName:  t.FirstName,
+			LastName:   t.LastName,
+			Referrals:  t.Referrals,
+			Qualified:  t.Qualified,
+		}
+	}
+	return StatsResponseDto{
+		From:         v.From,
+		To:           v.To,
+		Referrals:    v.Referrals,
+		Qualified:    v.Qualified,
+		Rejected:     v.Rejected,
+		Statuses:     statuses,
+		Rewards:      rewards,
+		TopReferrers: top,
+	}
+}
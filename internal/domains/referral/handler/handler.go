@@ -0,0 +1,163 @@
+package referral
+
+import (
+	"net/http"
+
+	"api/internal/di"
+	dto "api/internal/domains/referral/dto"
+	service "api/internal/domains/referral/service"
+	responseHandlers "api/internal/libs/responses"
+	"api/internal/libs/validators"
+	contextUtils "api/utils/context"
+
+	"github.com/go-chi/chi"
+)
+
+type Handler struct {
+	Service *service.Service
+}
+
+func NewHandler(container *di.Container) *Handler {
+	return &Handler{Service: service.NewService(container)}
+}
+
+// GetMyReferrals returns the logged-in customer's referral code and link with how their invites are doing.
+// @Description The code is created the first time it is asked for. Both sides are rewarded once the invited customer's first membership invoice is paid.
+// @Tags referrals
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} dto.SummaryResponseDto "Referral code and invites"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /referrals/me [get]
+func (h *Handler) GetMyReferrals(w http.ResponseWriter, r *http.Request) {
+	customerID, err := contextUtils.GetUserID(r.Context())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	summary, err := h.Service.GetSummary(r.Context(), customerID)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewSummaryResponse(summary), http.StatusOK)
+}
+
+// GetReferrer checks a referral code before sign-up and returns who it belongs to.
+// @Tags referrals
+// @Produce json
+// @Param code path string true "Referral code"
+// @Success 200 {object} dto.ReferrerResponseDto "Referrer"
+// @Failure 404 {object} map[string]interface{} "Not Found: Unknown code or the program is off"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /referrals/codes/{code} [get]
+func (h *Handler) GetReferrer(w http.ResponseWriter, r *http.Request) {
+	referrer, err := h.Service.GetReferrer(r.Context(), chi.URLParam(r, "code"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewReferrerResponse(referrer), http.StatusOK)
+}
+
+// GetReferrals lists referrals for staff.
+// @Tags referrals
+// @Produce json
+// @Security Bearer
+// @Param status query string false "pending, qualified, rewarded or rejected"
+// @Param referrer_id query string false "Only referrals by this customer"
+// @Param limit query int false "Number of records (default 20, max 100)"
+// @Param offset query int false "Number of records to skip"
+// @Success 200 {array} dto.ReferralResponseDto "Referrals"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid query"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /referrals [get]
+func (h *Handler) GetReferrals(w http.ResponseWriter, r *http.Request) {
+	filter, err := dto.ParseListQuery(r.URL.Query())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	referrals, err := h.Service.ListReferrals(r.Context(), filter)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewReferralResponses(referrals), http.StatusOK)
+}
+
+// GetStats reports on the referral program.
+// @Description Counts referrals created between from and to by status and rejection reason, totals the rewards issued in the window, and lists the top referrers.
+// @Tags referrals
+// @Produce json
+// @Security Bearer
+// @Param from query string false "Window start (RFC 3339, default 30 days before to)"
+// @Param to query string false "Window end (RFC 3339, default now)"
+// @Success 200 {object} dto.StatsResponseDto "Referral stats"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid window"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /referrals/stats [get]
+func (h *Handler) GetStats(w http.ResponseWriter, r *http.Request) {
+	from, to, err := dto.ParseStatsWindow(r.URL.Query())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	stats, err := h.Service.GetStats(r.Context(), from, to)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewStatsResponse(stats), http.StatusOK)
+}
+
+// GetSettings returns the referral program's rewards.
+// @Tags referrals
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} dto.SettingsResponseDto "Referral settings"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /referrals/settings [get]
+func (h *Handler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	settings, err := h.Service.GetSettings(r.Context())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewSettingsResponse(settings), http.StatusOK)
+}
+
+// UpdateSettings turns the referral program on or off and sets what each side earns.
+// @Description Amounts are a number of credits for credits rewards and cents for coupon and balance rewards. Coupons are single-use fixed-amount discounts valid for 90 days.
+// @Tags referrals
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param settings body dto.SettingsRequestDto true "Referral settings"
+// @Success 200 {object} dto.SettingsResponseDto "Referral settings updated"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid input"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /referrals/settings [put]
+func (h *Handler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	var req dto.SettingsRequestDto
+	if err := validators.ParseJSON(r.Body, &req); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	v, err := req.ToValues()
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	settings, err := h.Service.UpdateSettings(r.Context(), v)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewSettingsResponse(settings), http.StatusOK)
+}
@@ -0,0 +1,455 @@
+package referral
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	databaseErrors "api/internal/constants"
+	"api/internal/di"
+	db "api/internal/domains/referral/persistence/sqlc/generated"
+	values "api/internal/domains/referral/values"
+	errLib "api/internal/libs/errors"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type Repository struct {
+	Queries *db.Queries
+	Tx      *sql.Tx
+}
+
+func NewRepository(container *di.Container) *Repository {
+	return &Repository{Queries: container.Queries.ReferralDb}
+}
+
+func (r *Repository) GetTx() *sql.Tx { return r.Tx }
+
+func (r *Repository) WithTx(tx *sql.Tx) *Repository {
+	return &Repository{Queries: r.Queries.WithTx(tx), Tx: tx}
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func stringPtr(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
+}
+
+func timePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func uuidPtr(id uuid.NullUUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
+	}
+	return &id.UUID
+}
+
+func mapSettings(row db.ReferralsSetting) values.Settings {
+	return values.Settings{
+		Enabled:              row.Enabled,
+		ReferrerRewardType:   values.RewardType(row.ReferrerRewardType),
+		ReferrerRewardAmount: row.ReferrerRewardAmount,
+		RefereeRewardType:    values.RewardType(row.RefereeRewardType),
+		RefereeRewardAmount:  row.RefereeRewardAmount,
+		UpdatedBy:            uuidPtr(row.UpdatedBy),
+		UpdatedAt:            row.UpdatedAt,
+	}
+}
+
+func mapReferral(row db.ReferralsReferral) values.Referral {
+	return values.Referral{
+		ID:                  row.ID,
+		ReferrerID:          row.ReferrerID,
+		RefereeID:           row.RefereeID,
+		Code:                row.Code,
+		Status:              row.Status,
+		RejectionReason:     stringPtr(row.RejectionReason),
+		QualifyingInvoiceID: stringPtr(row.QualifyingInvoiceID),
+		QualifiedAt:         timePtr(row.QualifiedAt),
+		RewardedAt:          timePtr(row.RewardedAt),
+		CreatedAt:           row.CreatedAt,
+	}
+}
+
+func mapReward(row db.ReferralsReward) values.Reward {
+	return values.Reward{
+		ID:           row.ID,
+		ReferralID:   row.ReferralID,
+		CustomerID:   row.CustomerID,
+		Recipient:    row.Recipient,
+		RewardType:   values.RewardType(row.RewardType),
+		Amount:       row.Amount,
+		Status:       row.Status,
+		DiscountCode: stringPtr(row.DiscountCode),
+		Attempts:     row.Attempts,
+		LastError:    stringPtr(row.LastError),
+		IssuedAt:     timePtr(row.IssuedAt),
+		CreatedAt:    row.CreatedAt,
+	}
+}
+
+func (r *Repository) GetSettings(ctx context.Context) (values.Settings, *errLib.CommonError) {
+	row, err := r.Queries.GetReferralSettings(ctx)
+	if err != nil {
+		log.Printf("Failed to get referral settings: %v", err)
+		return values.Settings{}, errLib.New("Failed to get referral settings", http.StatusInternalServerError)
+	}
+	return mapSettings(row), nil
+}
+
+func (r *Repository) UpdateSettings(ctx context.Context, v values.UpdateSettingsValues, staffID uuid.UUID) (values.Settings, *errLib.CommonError) {
+	row, err := r.Queries.UpdateReferralSettings(ctx, db.UpdateReferralSettingsParams{
+		Enabled:              v.Enabled,
+		ReferrerRewardType:   string(v.ReferrerRewardType),
+		ReferrerRewardAmount: v.ReferrerRewardAmount,
+		RefereeRewardType:    string(v.RefereeRewardType),
+		RefereeRewardAmount:  v.RefereeRewardAmount,
+		UpdatedBy:            uuid.NullUUID{UUID: staffID, Valid: staffID != uuid.Nil},
+	})
+	if err != nil {
+		log.Printf("Failed to update referral settings: %v", err)
+		return values.Settings{}, errLib.New("Failed to update referral settings", http.StatusInternalServerError)
+	}
+	return mapSettings(row), nil
+}
+
+// GetCode returns a customer's referral code, or "" when they have none yet.
+func (r *Repository) GetCode(ctx context.Context, customerID uuid.UUID) (string, *errLib.CommonError) {
+	row, err := r.Queries.GetReferralCodeByCustomer(ctx, customerID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		log.Printf("Failed to get referral code of %s: %v", customerID, err)
+		return "", errLib.New("Failed to get referral code", http.StatusInternalServerError)
+	}
+	return row.Code, nil
+}
+
+// CreateCode stores a customer's referral code. It reports false without error when
+// the code is taken by someone else, and returns the customer's existing code when
+// they already have one.
+func (r *Repository) CreateCode(ctx context.Context, customerID uuid.UUID, code string) (string, bool, *errLib.CommonError) {
+	row, err := r.Queries.CreateReferralCode(ctx, db.CreateReferralCodeParams{CustomerID: customerID, Code: code})
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == databaseErrors.UniqueViolation {
+			return "", false, nil
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			existing, getErr := r.GetCode(ctx, customerID)
+			return existing, getErr == nil, getErr
+		}
+		if errors.As(err, &pqErr) && pqErr.Code == databaseErrors.ForeignKeyViolation {
+			return "", false, errLib.New("Customer not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to create referral code for %s: %v", customerID, err)
+		return "", false, errLib.New("Failed to create referral code", http.StatusInternalServerError)
+	}
+	return row.Code, true, nil
+}
+
+// GetReferrer returns the customer a normalized referral code belongs to.
+func (r *Repository) GetReferrer(ctx context.Context, code string) (db.GetReferrerByCodeRow, *errLib.CommonError) {
+	row, err := r.Queries.GetReferrerByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return row, errLib.New("Referral code not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to look up referral code: %v", err)
+		return row, errLib.New("Failed to look up referral code", http.StatusInternalServerError)
+	}
+	return row, nil
+}
+
+func (r *Repository) AddDevice(ctx context.Context, customerID uuid.UUID, deviceID string) *errLib.CommonError {
+	if err := r.Queries.AddReferralDevice(ctx, db.AddReferralDeviceParams{CustomerID: customerID, DeviceID: deviceID}); err != nil {
+		log.Printf("Failed to record registration device of %s: %v", customerID, err)
+		return errLib.New("Failed to record registration device", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (r *Repository) HasDevice(ctx context.Context, customerID uuid.UUID, deviceID string) (bool, *errLib.CommonError) {
+	found, err := r.Queries.HasReferralDevice(ctx, db.HasReferralDeviceParams{CustomerID: customerID, DeviceID: deviceID})
+	if err != nil {
+		log.Printf("Failed to check registration devices of %s: %v", customerID, err)
+		return false, errLib.New("Failed to check registration device", http.StatusInternalServerError)
+	}
+	return found, nil
+}
+
+func (r *Repository) GetCustomer(ctx context.Context, customerID uuid.UUID) (db.GetReferralCustomerRow, *errLib.CommonError) {
+	row, err := r.Queries.GetReferralCustomer(ctx, customerID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return row, errLib.New("Customer not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to get customer %s: %v", customerID, err)
+		return row, errLib.New("Failed to get customer", http.StatusInternalServerError)
+	}
+	return row, nil
+}
+
+func (r *Repository) CreateReferral(ctx context.Context, params db.CreateReferralParams) (values.Referral, *errLib.CommonError) {
+	row, err := r.Queries.CreateReferral(ctx, params)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == databaseErrors.UniqueViolation {
+			return values.Referral{}, errLib.New("Customer was already referred", http.StatusConflict)
+		}
+		log.Printf("Failed to create referral of %s: %v", params.RefereeID, err)
+		return values.Referral{}, errLib.New("Failed to create referral", http.StatusInternalServerError)
+	}
+	return mapReferral(row), nil
+}
+
+// GetPendingReferral returns the pending referral of a referee, reporting false when
+// there is none.
+func (r *Repository) GetPendingReferral(ctx context.Context, refereeID uuid.UUID) (values.Referral, bool, *errLib.CommonError) {
+	row, err := r.Queries.GetPendingReferralByReferee(ctx, refereeID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return values.Referral{}, false, nil
+		}
+		log.Printf("Failed to get pending referral of %s: %v", refereeID, err)
+		return values.Referral{}, false, errLib.New("Failed to get referral", http.StatusInternalServerError)
+	}
+	return mapReferral(row), true, nil
+}
+
+// LockPendingReferral reads a referral's row for update, reporting false when it is
+// no longer pending.
+func (r *Repository) LockPendingReferral(ctx context.Context, id uuid.UUID) (values.Referral, bool, *errLib.CommonError) {
+	row, err := r.Queries.GetPendingReferralForUpdate(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return values.Referral{}, false, nil
+		}
+		log.Printf("Failed to lock referral %s: %v", id, err)
+		return values.Referral{}, false, errLib.New("Failed to get referral", http.StatusInternalServerError)
+	}
+	return mapReferral(row), true, nil
+}
+
+func (r *Repository) QualifyReferral(ctx context.Context, id uuid.UUID, invoiceID string) *errLib.CommonError {
+	if err := r.Queries.QualifyReferral(ctx, db.QualifyReferralParams{
+		ID:                  id,
+		QualifyingInvoiceID: nullString(invoiceID),
+	}); err != nil {
+		log.Printf("Failed to qualify referral %s: %v", id, err)
+		return errLib.New("Failed to qualify referral", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// RejectReferral rejects a pending referral, reporting false when it was not pending.
+func (r *Repository) RejectReferral(ctx context.Context, id uuid.UUID, reason, invoiceID string) (bool, *errLib.CommonError) {
+	affected, err := r.Queries.RejectReferral(ctx, db.RejectReferralParams{
+		ID:                  id,
+		RejectionReason:     nullString(reason),
+		QualifyingInvoiceID: nullString(invoiceID),
+	})
+	if err != nil {
+		log.Printf("Failed to reject referral %s: %v", id, err)
+		return false, errLib.New("Failed to reject referral", http.StatusInternalServerError)
+	}
+	return affected > 0, nil
+}
+
+func (r *Repository) MarkReferralRewarded(ctx context.Context, id uuid.UUID) *errLib.CommonError {
+	if err := r.Queries.MarkReferralRewarded(ctx, id); err != nil {
+		log.Printf("Failed to mark referral %s rewarded: %v", id, err)
+		return errLib.New("Failed to update referral", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (r *Repository) ListReferrals(ctx context.Context, filter values.ListFilter) ([]values.Referral, *errLib.CommonError) {
+	rows, err := r.Queries.ListReferrals(ctx, db.ListReferralsParams{
+		Status:     nullString(filter.Status),
+		ReferrerID: uuid.NullUUID{UUID: filter.ReferrerID, Valid: filter.ReferrerID != uuid.Nil},
+		Limit:      filter.Limit,
+		Offset:     filter.Offset,
+	})
+	if err != nil {
+		log.Printf("Failed to list referrals: %v", err)
+		return nil, errLib.New("Failed to list referrals", http.StatusInternalServerError)
+	}
+	referrals := make([]values.Referral, len(rows))
+	for i, row := range rows {
+		referrals[i] = values.Referral{
+			ID:                  row.ID,
+			ReferrerID:          row.ReferrerID,
+			ReferrerName:        row.ReferrerFirstName + " " + row.ReferrerLastName,
+			RefereeID:           row.RefereeID,
+			RefereeName:         row.RefereeFirstName + " " + row.RefereeLastName,
+			Code:                row.Code,
+			Status:              row.Status,
+			RejectionReason:     stringPtr(row.RejectionReason),
+			QualifyingInvoiceID: stringPtr(row.QualifyingInvoiceID),
+			QualifiedAt:         timePtr(row.QualifiedAt),
+			RewardedAt:          timePtr(row.RewardedAt),
+			CreatedAt:           row.CreatedAt,
+		}
+	}
+	return referrals, nil
+}
+
+func (r *Repository) CountByStatus(ctx context.Context, from, to time.Time) ([]values.StatusCount, *errLib.CommonError) {
+	rows, err := r.Queries.CountReferralsByStatus(ctx, db.CountReferralsByStatusParams{From: from, To: to})
+	if err != nil {
+		log.Printf("Failed to count referrals: %v", err)
+		return nil, errLib.New("Failed to build referral stats", http.StatusInternalServerError)
+	}
+	counts := make([]values.StatusCount, len(rows))
+	for i, row := range rows {
+		counts[i] = values.StatusCount{
+			Status:          row.Status,
+			RejectionReason: row.RejectionReason,
+			Referrals:       row.Referrals,
+		}
+	}
+	return counts, nil
+}
+
+func (r *Repository) CountCustomerReferrals(ctx context.Context, referrerID uuid.UUID) (map[string]int64, *errLib.CommonError) {
+	rows, err := r.Queries.CountCustomerReferralsByStatus(ctx, referrerID)
+	if err != nil {
+		log.Printf("Failed to count referrals of %s: %v", referrerID, err)
+		return nil, errLib.New("Failed to get referrals", http.StatusInternalServerError)
+	}
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Referrals
+	}
+	return counts, nil
+}
+
+func (r *Repository) SumIssuedRewards(ctx context.Context, from, to time.Time) ([]values.RewardTotal, *errLib.CommonError) {
+	rows, err := r.Queries.SumIssuedRewards(ctx, db.SumIssuedRewardsParams{From: from, To: to})
+	if err != nil {
+		log.Printf("Failed to sum referral rewards: %v", err)
+		return nil, errLib.New("Failed to build referral stats", http.StatusInternalServerError)
+	}
+	totals := make([]values.RewardTotal, len(rows))
+	for i, row := range rows {
+		totals[i] = values.RewardTotal{
+			RewardType: values.RewardType(row.RewardType),
+			Rewards:    row.Rewards,
+			Amount:     row.Amount,
+		}
+	}
+	return totals, nil
+}
+
+func (r *Repository) ListTopReferrers(ctx context.Context, from, to time.Time, limit int32) ([]values.TopReferrer, *errLib.CommonError) {
+	rows, err := r.Queries.ListTopReferrers(ctx, db.ListTopReferrersParams{From: from, To: to, Limit: limit})
+	if err != nil {
+		log.Printf("Failed to list top referrers: %v", err)
+		return nil, errLib.New("Failed to build referral stats", http.StatusInternalServerError)
+	}
+	referrers := make([]values.TopReferrer, len(rows))
+	for i, row := range rows {
+		referrers[i] = values.TopReferrer{
+			CustomerID: row.ReferrerID,
+			FirstName:  row.FirstName,
+			LastName:   row.LastName,
+			Referrals:  row.Referrals,
+			Qualified:  row.Qualified,
+		}
+	}
+	return referrers, nil
+}
+
+func (r *Repository) CreateReward(ctx context.Context, params db.CreateReferralRewardParams) *errLib.CommonError {
+	if err := r.Queries.CreateReferralReward(ctx, params); err != nil {
+		log.Printf("Failed to create %s reward of referral %s: %v", params.Recipient, params.ReferralID, err)
+		return errLib.New("Failed to create referral reward", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (r *Repository) ListPendingRewardIDs(ctx context.Context, referralID uuid.UUID) ([]uuid.UUID, *errLib.CommonError) {
+	ids, err := r.Queries.ListPendingRewardIdsForReferral(ctx, referralID)
+	if err != nil {
+		log.Printf("Failed to list pending rewards of referral %s: %v", referralID, err)
+		return nil, errLib.New("Failed to list referral rewards", http.StatusInternalServerError)
+	}
+	return ids, nil
+}
+
+func (r *Repository) ListRetryableRewardIDs(ctx context.Context, createdBefore time.Time, maxAttempts int32) ([]uuid.UUID, *errLib.CommonError) {
+	ids, err := r.Queries.ListRetryableRewardIds(ctx, db.ListRetryableRewardIdsParams{
+		CreatedBefore: createdBefore,
+		MaxAttempts:   maxAttempts,
+	})
+	if err != nil {
+		log.Printf("Failed to list pending referral rewards: %v", err)
+		return nil, errLib.New("Failed to list referral rewards", http.StatusInternalServerError)
+	}
+	return ids, nil
+}
+
+// LockPendingReward reads a pending reward's row for update, reporting false when it
+// was issued or is being issued by another process.
+func (r *Repository) LockPendingReward(ctx context.Context, id uuid.UUID) (values.Reward, bool, *errLib.CommonError) {
+	row, err := r.Queries.GetPendingRewardForUpdate(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return values.Reward{}, false, nil
+		}
+		log.Printf("Failed to lock referral reward %s: %v", id, err)
+		return values.Reward{}, false, errLib.New("Failed to get referral reward", http.StatusInternalServerError)
+	}
+	return mapReward(row), true, nil
+}
+
+func (r *Repository) MarkRewardIssued(ctx context.Context, id uuid.UUID, discountCode string) *errLib.CommonError {
+	if err := r.Queries.MarkRewardIssued(ctx, db.MarkRewardIssuedParams{
+		ID:           id,
+		DiscountCode: nullString(discountCode),
+	}); err != nil {
+		log.Printf("Failed to mark referral reward %s issued: %v", id, err)
+		return errLib.New("Failed to update referral reward", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (r *Repository) MarkRewardFailed(ctx context.Context, id uuid.UUID, reason string) *errLib.CommonError {
+	if err := r.Queries.MarkRewardFailed(ctx, db.MarkRewardFailedParams{
+		ID:        id,
+		LastError: nullString(reason),
+	}); err != nil {
+		log.Printf("Failed to record failure of referral reward %s: %v", id, err)
+		return errLib.New("Failed to update referral reward", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (r *Repository) ListCustomerRewards(ctx context.Context, customerID uuid.UUID) ([]values.Reward, *errLib.CommonError) {
+	rows, err := r.Queries.ListCustomerRewards(ctx, customerID)
+	if err != nil {
+		log.Printf("Failed to list referral rewards of %s: %v", customerID, err)
+		return nil, errLib.New("Failed to list referral rewards", http.StatusInternalServerError)
+	}
+	rewards := make([]values.Reward, len(rows))
+	for i, row := range rows {
+		rewards[i] = mapReward(row)
+	}
+	return rewards, nil
+}
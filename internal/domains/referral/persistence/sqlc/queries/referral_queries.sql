@@ -0,0 +1,219 @@
+-- name: GetReferralSettings :one
+SELECT *
+FROM referrals.settings
+WHERE id;
+
+-- name: UpdateReferralSettings :one
+UPDATE referrals.settings
+SET enabled                = $1,
+    referrer_reward_type   = $2,
+    referrer_reward_amount = $3,
+    referee_reward_type    = $4,
+    referee_reward_amount  = $5,
+    updated_by             = $6,
+    updated_at             = CURRENT_TIMESTAMP
+WHERE id
+RETURNING *;
+
+-- name: GetReferralCodeByCustomer :one
+SELECT *
+FROM referrals.codes
+WHERE customer_id = $1;
+
+-- name: CreateReferralCode :one
+-- Returns no row when the customer already has a code.
+INSERT INTO referrals.codes (customer_id, code)
+VALUES ($1, $2)
+ON CONFLICT (customer_id) DO NOTHING
+RETURNING *;
+
+-- name: GetReferrerByCode :one
+SELECT c.customer_id, c.code, u.first_name, u.email
+FROM referrals.codes c
+         JOIN users.users u ON u.id = c.customer_id
+WHERE c.code = $1
+  AND u.deleted_at IS NULL;
+
+-- name: AddReferralDevice :exec
+INSERT INTO referrals.devices (customer_id, device_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: HasReferralDevice :one
+SELECT EXISTS (SELECT 1
+               FROM referrals.devices
+               WHERE customer_id = $1
+                 AND device_id = $2)::bool AS has_device;
+
+-- name: GetReferralCustomer :one
+SELECT id, first_name, email, stripe_customer_id
+FROM users.users
+WHERE id = $1;
+
+-- name: CreateReferral :one
+INSERT INTO referrals.referrals (referrer_id, referee_id, code, status, rejection_reason)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetPendingReferralByReferee :one
+SELECT *
+FROM referrals.referrals
+WHERE referee_id = $1
+  AND status = 'pending';
+
+-- name: GetPendingReferralForUpdate :one
+SELECT *
+FROM referrals.referrals
+WHERE id = $1
+  AND status = 'pending'
+    FOR UPDATE;
+
+-- name: QualifyReferral :exec
+UPDATE referrals.referrals
+SET status                = 'qualified',
+    qualifying_invoice_id = $2,
+    qualified_at          = CURRENT_TIMESTAMP,
+    updated_at            = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: RejectReferral :execrows
+UPDATE referrals.referrals
+SET status                = 'rejected',
+    rejection_reason      = $2,
+    qualifying_invoice_id = $3,
+    updated_at            = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND status = 'pending';
+
+-- name: MarkReferralRewarded :exec
+-- Marks a qualified referral rewarded once none of its rewards is pending.
+UPDATE referrals.referrals r
+SET status      = 'rewarded',
+    rewarded_at = CURRENT_TIMESTAMP,
+    updated_at  = CURRENT_TIMESTAMP
+WHERE r.id = $1
+  AND r.status = 'qualified'
+  AND NOT EXISTS (SELECT 1
+                  FROM referrals.rewards w
+                  WHERE w.referral_id = r.id
+                    AND w.status = 'pending');
+
+-- name: ListReferrals :many
+SELECT r.id,
+       r.referrer_id,
+       referrer.first_name AS referrer_first_name,
+       referrer.last_name  AS referrer_last_name,
+       r.referee_id,
+       referee.first_name  AS referee_first_name,
+       referee.last_name   AS referee_last_name,
+       r.code,
+       r.status,
+       r.rejection_reason,
+       r.qualifying_invoice_id,
+       r.qualified_at,
+       r.rewarded_at,
+       r.created_at
+FROM referrals.referrals r
+         JOIN users.users referrer ON referrer.id = r.referrer_id
+         JOIN users.users referee ON referee.id = r.referee_id
+WHERE (sqlc.narg('status')::text IS NULL OR r.status = sqlc.narg('status')::text)
+  AND (sqlc.narg('referrer_id')::uuid IS NULL OR r.referrer_id = sqlc.narg('referrer_id')::uuid)
+ORDER BY r.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountReferralsByStatus :many
+-- Referrals created in [from, to), by status and rejection reason
+SELECT status,
+       COALESCE(rejection_reason, '')::text AS rejection_reason,
+       COUNT(*)::bigint                     AS referrals
+FROM referrals.referrals
+WHERE created_at >= sqlc.arg('from')
+  AND created_at < sqlc.arg('to')
+GROUP BY status, rejection_reason
+ORDER BY status, rejection_reason;
+
+-- name: CountCustomerReferralsByStatus :many
+SELECT status,
+       COUNT(*)::bigint AS referrals
+FROM referrals.referrals
+WHERE referrer_id = $1
+GROUP BY status
+ORDER BY status;
+
+-- name: SumIssuedRewards :many
+-- Rewards issued in [from, to), by type
+SELECT reward_type,
+       COUNT(*)::bigint                 AS rewards,
+       COALESCE(SUM(amount), 0)::bigint AS amount
+FROM referrals.rewards
+WHERE status = 'issued'
+  AND issued_at >= sqlc.arg('from')::timestamptz
+  AND issued_at < sqlc.arg('to')::timestamptz
+GROUP BY reward_type
+ORDER BY reward_type;
+
+-- name: ListTopReferrers :many
+-- Customers with the most qualified referrals created in [from, to)
+SELECT r.referrer_id,
+       u.first_name,
+       u.last_name,
+       COUNT(*)::bigint                                                        AS referrals,
+       (COUNT(*) FILTER (WHERE r.status IN ('qualified', 'rewarded')))::bigint AS qualified
+FROM referrals.referrals r
+         JOIN users.users u ON u.id = r.referrer_id
+WHERE r.created_at >= sqlc.arg('from')
+  AND r.created_at < sqlc.arg('to')
+GROUP BY r.referrer_id, u.first_name, u.last_name
+ORDER BY qualified DESC, referrals DESC
+LIMIT sqlc.arg('limit');
+
+-- name: CreateReferralReward :exec
+INSERT INTO referrals.rewards (referral_id, customer_id, recipient, reward_type, amount)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (referral_id, recipient) DO NOTHING;
+
+-- name: ListPendingRewardIdsForReferral :many
+SELECT id
+FROM referrals.rewards
+WHERE referral_id = $1
+  AND status = 'pending'
+ORDER BY recipient;
+
+-- name: ListRetryableRewardIds :many
+-- Pending rewards created before created_before that have not used up their attempts
+SELECT id
+FROM referrals.rewards
+WHERE status = 'pending'
+  AND created_at < sqlc.arg('created_before')
+  AND attempts < sqlc.arg('max_attempts')
+ORDER BY created_at
+LIMIT 100;
+
+-- name: GetPendingRewardForUpdate :one
+-- Skips a reward another process is issuing.
+SELECT *
+FROM referrals.rewards
+WHERE id = $1
+  AND status = 'pending'
+    FOR UPDATE SKIP LOCKED;
+
+-- name: MarkRewardIssued :exec
+UPDATE referrals.rewards
+SET status        = 'issued',
+    discount_code = $2,
+    attempts      = attempts + 1,
+    last_error    = NULL,
+    issued_at     = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: MarkRewardFailed :exec
+UPDATE referrals.rewards
+SET attempts   = attempts + 1,
+    last_error = $2
+WHERE id = $1;
+
+-- name: ListCustomerRewards :many
+SELECT *
+FROM referrals.rewards
+WHERE customer_id = $1
+ORDER BY created_at DESC;
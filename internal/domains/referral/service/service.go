@@ -0,0 +1,580 @@
+package referral
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"api/config"
+	"api/internal/di"
+	staffActivityLogs "api/internal/domains/audit/staff_activity_logs/service"
+	discountService "api/internal/domains/discount/service"
+	discountValues "api/internal/domains/discount/values"
+	giftCardService "api/internal/domains/gift_card/service"
+	"api/internal/domains/payment/services/stripe"
+	repo "api/internal/domains/referral/persistence"
+	db "api/internal/domains/referral/persistence/sqlc/generated"
+	values "api/internal/domains/referral/values"
+	userServices "api/internal/domains/user/services"
+	userValues "api/internal/domains/user/values"
+	errLib "api/internal/libs/errors"
+	contextUtils "api/utils/context"
+	txUtils "api/utils/db"
+	"api/utils/email"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxRewardAttempts is how many times a reward is tried before it is left for staff.
+	MaxRewardAttempts = 10
+	// RewardRetryDelay is how old a pending reward must be before the referral job
+	// retries it, so the job does not race the webhook issuing it.
+	RewardRetryDelay = 10 * time.Minute
+	// CouponValidity is how long a coupon reward can be used.
+	CouponValidity = 90 * 24 * time.Hour
+
+	codeLength       = 8
+	codeAlphabet     = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	codeAttempts     = 5
+	couponPrefix     = "REF"
+	rewardNote       = "Referral reward"
+	topReferrerLimit = 10
+)
+
+// Service runs the referral program. Customers share a code, new customers register
+// with it, and both sides are rewarded once the new customer's first membership
+// invoice is paid. Referrals that look like someone referring themselves are rejected.
+type Service struct {
+	repo                     *repo.Repository
+	staffActivityLogsService *staffActivityLogs.Service
+	creditService            *userServices.CustomerCreditService
+	giftCardService          *giftCardService.Service
+	discountService          *discountService.Service
+	db                       *sql.DB
+}
+
+func NewService(container *di.Container) *Service {
+	return &Service{
+		repo:                     repo.NewRepository(container),
+		staffActivityLogsService: staffActivityLogs.NewService(container),
+		creditService:            userServices.NewCustomerCreditService(container),
+		giftCardService:          giftCardService.NewService(container),
+		discountService:          discountService.NewService(container),
+		db:                       container.DB,
+	}
+}
+
+// GetSummary returns the customer's referral code and link, creating the code the
+// first time, with how their invites are doing and the rewards they earned.
+func (s *Service) GetSummary(ctx context.Context, customerID uuid.UUID) (values.Summary, *errLib.CommonError) {
+	code, err := s.getOrCreateCode(ctx, customerID)
+	if err != nil {
+		return values.Summary{}, err
+	}
+	counts, err := s.repo.CountCustomerReferrals(ctx, customerID)
+	if err != nil {
+		return values.Summary{}, err
+	}
+	rewards, err := s.repo.ListCustomerRewards(ctx, customerID)
+	if err != nil {
+		return values.Summary{}, err
+	}
+
+	summary := values.Summary{
+		Code:      code,
+		Link:      referralLink(config.Env.FrontendBaseURL, code),
+		Pending:   counts[values.StatusPending],
+		Qualified: counts[values.StatusQualified],
+		Rewarded:  counts[values.StatusRewarded],
+		Rejected:  counts[values.StatusRejected],
+		Rewards:   rewards,
+	}
+	summary.Invited = summary.Pending + summary.Qualified + summary.Rewarded + summary.Rejected
+	return summary, nil
+}
+
+func (s *Service) getOrCreateCode(ctx context.Context, customerID uuid.UUID) (string, *errLib.CommonError) {
+	code, err := s.repo.GetCode(ctx, customerID)
+	if err != nil || code != "" {
+		return code, err
+	}
+
+	for i := 0; i < codeAttempts; i++ {
+		if code, err = generateCode(codeLength); err != nil {
+			return "", err
+		}
+		stored, created, err := s.repo.CreateCode(ctx, customerID, code)
+		if err != nil {
+			return "", err
+		}
+		if created {
+			return stored, nil
+		}
+	}
+	log.Printf("Failed to find a free referral code for %s after %d attempts", customerID, codeAttempts)
+	return "", errLib.New("Failed to create referral code", http.StatusInternalServerError)
+}
+
+// GetReferrer returns who a referral code belongs to, so the sign-up page can say who
+// invited the customer.
+func (s *Service) GetReferrer(ctx context.Context, code string) (values.Referrer, *errLib.CommonError) {
+	settings, err := s.repo.GetSettings(ctx)
+	if err != nil {
+		return values.Referrer{}, err
+	}
+	if !settings.Enabled {
+		return values.Referrer{}, errLib.New("Referral code not found", http.StatusNotFound)
+	}
+
+	row, err := s.repo.GetReferrer(ctx, values.NormalizeCode(code))
+	if err != nil {
+		return values.Referrer{}, err
+	}
+	return values.Referrer{CustomerID: row.CustomerID, Code: row.Code, FirstName: row.FirstName}, nil
+}
+
+// RecordRegistration remembers the device a new customer registered from and, when
+// they signed up with a referral code, records the referral. A referral from the
+// same email or device as its referrer is recorded as rejected.
+func (s *Service) RecordRegistration(ctx context.Context, reg values.Registration) *errLib.CommonError {
+	deviceID := ""
+	if reg.DeviceID != nil {
+		deviceID = strings.TrimSpace(*reg.DeviceID)
+	}
+	if deviceID != "" {
+		if err := s.repo.AddDevice(ctx, reg.CustomerID, deviceID); err != nil {
+			return err
+		}
+	}
+
+	if reg.Code == nil || strings.TrimSpace(*reg.Code) == "" {
+		return nil
+	}
+
+	settings, err := s.repo.GetSettings(ctx)
+	if err != nil {
+		return err
+	}
+	if !settings.Enabled {
+		log.Printf("Referral program is off, ignoring referral code of new customer %s", reg.CustomerID)
+		return nil
+	}
+
+	referrer, err := s.repo.GetReferrer(ctx, values.NormalizeCode(*reg.Code))
+	if err != nil {
+		return err
+	}
+
+	reason := ""
+	if referrer.Email.Valid && NormalizeEmail(referrer.Email.String) == NormalizeEmail(reg.Email) {
+		reason = values.RejectSameEmail
+	} else if deviceID != "" {
+		shared, err := s.repo.HasDevice(ctx, referrer.CustomerID, deviceID)
+		if err != nil {
+			return err
+		}
+		if shared {
+			reason = values.RejectSameDevice
+		}
+	}
+
+	status := values.StatusPending
+	if reason != "" {
+		status = values.StatusRejected
+		log.Printf("Rejected referral of %s by %s: %s", reg.CustomerID, referrer.CustomerID, reason)
+	}
+	_, err = s.repo.CreateReferral(ctx, db.CreateReferralParams{
+		ReferrerID:      referrer.CustomerID,
+		RefereeID:       reg.CustomerID,
+		Code:            referrer.Code,
+		Status:          status,
+		RejectionReason: sql.NullString{String: reason, Valid: reason != ""},
+	})
+	return err
+}
+
+// QualifyReferral qualifies the pending referral of a customer whose membership
+// invoice was paid and issues its rewards. A referee who paid with a card saved on
+// their referrer's account is rejected instead. It does nothing when the customer has
+// no pending referral, so it can run for every paid invoice. Referrals made while the
+// program was on are still rewarded after it is turned off.
+func (s *Service) QualifyReferral(ctx context.Context, refereeID uuid.UUID, invoiceID string) *errLib.CommonError {
+	referral, found, err := s.repo.GetPendingReferral(ctx, refereeID)
+	if err != nil || !found {
+		return err
+	}
+
+	reason, err := s.paymentMethodCheck(ctx, referral.ReferrerID, invoiceID)
+	if err != nil {
+		return err
+	}
+	settings, err := s.repo.GetSettings(ctx)
+	if err != nil {
+		return err
+	}
+
+	var qualified, rejected bool
+	err = txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		r := s.repo.WithTx(tx)
+
+		locked, found, err := r.LockPendingReferral(ctx, referral.ID)
+		if err != nil || !found {
+			return err
+		}
+		if reason != "" {
+			rejected, err = r.RejectReferral(ctx, locked.ID, reason, invoiceID)
+			return err
+		}
+
+		if err = r.QualifyReferral(ctx, locked.ID, invoiceID); err != nil {
+			return err
+		}
+		for _, reward := range rewardsFor(locked, settings) {
+			if err = r.CreateReward(ctx, reward); err != nil {
+				return err
+			}
+		}
+		qualified = true
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if rejected {
+		log.Printf("Rejected referral %s of %s: %s", referral.ID, refereeID, reason)
+	}
+	if qualified {
+		log.Printf("Referral %s of %s qualified by invoice %s", referral.ID, refereeID, invoiceID)
+		return s.issueRewards(ctx, referral.ID)
+	}
+	return nil
+}
+
+// paymentMethodCheck returns RejectSamePaymentMethod when the card that paid the
+// invoice is saved on the referrer's Stripe customer.
+func (s *Service) paymentMethodCheck(ctx context.Context, referrerID uuid.UUID, invoiceID string) (string, *errLib.CommonError) {
+	fingerprint, err := stripe.InvoiceCardFingerprint(invoiceID)
+	if err != nil || fingerprint == "" {
+		return "", err
+	}
+
+	referrer, err := s.repo.GetCustomer(ctx, referrerID)
+	if err != nil {
+		return "", err
+	}
+	if !referrer.StripeCustomerID.Valid {
+		return "", nil
+	}
+
+	fingerprints, err := stripe.CustomerCardFingerprints(referrer.StripeCustomerID.String)
+	if err != nil {
+		return "", err
+	}
+	for _, f := range fingerprints {
+		if f == fingerprint {
+			return values.RejectSamePaymentMethod, nil
+		}
+	}
+	return "", nil
+}
+
+// rewardsFor lists the rewards a qualified referral earns under the settings.
+func rewardsFor(referral values.Referral, settings values.Settings) []db.CreateReferralRewardParams {
+	sides := []struct {
+		recipient  string
+		customerID uuid.UUID
+		rewardType values.RewardType
+		amount     int32
+	}{
+		{values.RecipientReferrer, referral.ReferrerID, settings.ReferrerRewardType, settings.ReferrerRewardAmount},
+		{values.RecipientReferee, referral.RefereeID, settings.RefereeRewardType, settings.RefereeRewardAmount},
+	}
+
+	var rewards []db.CreateReferralRewardParams
+	for _, side := range sides {
+		if side.rewardType == values.RewardNone || side.amount <= 0 {
+			continue
+		}
+		rewards = append(rewards, db.CreateReferralRewardParams{
+			ReferralID: referral.ID,
+			CustomerID: side.customerID,
+			Recipient:  side.recipient,
+			RewardType: string(side.rewardType),
+			Amount:     side.amount,
+		})
+	}
+	return rewards
+}
+
+// issueRewards issues a qualified referral's pending rewards. Rewards that fail are
+// left pending for the referral job. A referral that earns no rewards is marked
+// rewarded right away.
+func (s *Service) issueRewards(ctx context.Context, referralID uuid.UUID) *errLib.CommonError {
+	ids, err := s.repo.ListPendingRewardIDs(ctx, referralID)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if _, err = s.IssueReward(ctx, id); err != nil {
+			log.Printf("Failed to issue referral reward %s, it will be retried: %s", id, err.Message)
+		}
+	}
+	return s.repo.MarkReferralRewarded(ctx, referralID)
+}
+
+// IssueReward gives a pending reward to its customer and emails them about it,
+// reporting whether it was issued. The referral is marked rewarded once none of its
+// rewards is pending. A reward that was issued or is being issued by
+// another process is skipped. A failure is recorded on the reward.
+func (s *Service) IssueReward(ctx context.Context, rewardID uuid.UUID) (bool, *errLib.CommonError) {
+	var reward values.Reward
+	var issued bool
+	var grantErr *errLib.CommonError
+
+	err := txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		r := s.repo.WithTx(tx)
+
+		var found bool
+		var err *errLib.CommonError
+		if reward, found, err = r.LockPendingReward(ctx, rewardID); err != nil || !found {
+			return err
+		}
+
+		var couponCode string
+		if couponCode, grantErr = s.grant(ctx, reward); grantErr != nil {
+			return r.MarkRewardFailed(ctx, reward.ID, grantErr.Message)
+		}
+		if err = r.MarkRewardIssued(ctx, reward.ID, couponCode); err != nil {
+			return err
+		}
+		if couponCode != "" {
+			reward.DiscountCode = &couponCode
+		}
+		issued = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	if grantErr != nil {
+		return false, grantErr
+	}
+	if issued {
+		s.sendRewardEmail(ctx, reward)
+		if err = s.repo.MarkReferralRewarded(ctx, reward.ReferralID); err != nil {
+			log.Printf("Failed to mark referral %s rewarded: %s", reward.ReferralID, err.Message)
+		}
+	}
+	return issued, nil
+}
+
+// grant gives the customer their reward and returns the discount code of a coupon.
+func (s *Service) grant(ctx context.Context, reward values.Reward) (string, *errLib.CommonError) {
+	switch reward.RewardType {
+	case values.RewardCredits:
+		return "", s.creditService.GrantCredits(ctx, reward.CustomerID, reward.Amount, userValues.CreditSourceReward, nil, rewardNote)
+	case values.RewardBalance:
+		_, err := s.giftCardService.CreditBalance(ctx, reward.CustomerID, reward.Amount, rewardNote)
+		return "", err
+	case values.RewardCoupon:
+		return s.issueCoupon(ctx, reward)
+	}
+	return "", errLib.New(fmt.Sprintf("Unknown referral reward type %s", reward.RewardType), http.StatusInternalServerError)
+}
+
+// issueCoupon creates a single-use discount worth the reward's amount.
+func (s *Service) issueCoupon(ctx context.Context, reward values.Reward) (string, *errLib.CommonError) {
+	suffix, err := generateCode(codeLength)
+	if err != nil {
+		return "", err
+	}
+	code := couponPrefix + suffix
+	amount := float64(reward.Amount) / 100
+	maxRedemptions := 1
+	now := time.Now()
+
+	discount, err := s.discountService.IssueDiscount(ctx, discountValues.CreateValues{
+		Name:           code,
+		Description:    rewardNote,
+		DiscountAmount: &amount,
+		DiscountType:   discountValues.TypeFixedAmount,
+		UsePerClient:   1,
+		IsActive:       true,
+		ValidFrom:      now,
+		ValidTo:        now.Add(CouponValidity),
+		DurationType:   discountValues.DurationOnce,
+		AppliesTo:      discountValues.AppliesToBoth,
+		MaxRedemptions: &maxRedemptions,
+	})
+	if err != nil {
+		return "", err
+	}
+	return discount.Name, nil
+}
+
+func (s *Service) sendRewardEmail(ctx context.Context, reward values.Reward) {
+	customer, err := s.repo.GetCustomer(ctx, reward.CustomerID)
+	if err != nil || !customer.Email.Valid {
+		return
+	}
+
+	reason := "Thanks for joining Rise with a friend's invite!"
+	if reward.Recipient == values.RecipientReferrer {
+		reason = "Someone you invited just became a Rise member."
+	}
+	couponCode := ""
+	if reward.DiscountCode != nil {
+		couponCode = *reward.DiscountCode
+	}
+	email.SendReferralRewardEmail(customer.Email.String, customer.FirstName, reason, describeReward(reward.RewardType, reward.Amount), couponCode)
+}
+
+// RetryPendingRewards retries rewards that could not be issued and returns how many
+// were issued.
+func (s *Service) RetryPendingRewards(ctx context.Context, now time.Time) (int, *errLib.CommonError) {
+	ids, err := s.repo.ListRetryableRewardIDs(ctx, now.Add(-RewardRetryDelay), MaxRewardAttempts)
+	if err != nil {
+		return 0, err
+	}
+
+	issued := 0
+	for _, id := range ids {
+		ok, err := s.IssueReward(ctx, id)
+		if err != nil {
+			log.Printf("Failed to issue referral reward %s: %s", id, err.Message)
+			continue
+		}
+		if ok {
+			issued++
+		}
+	}
+	return issued, nil
+}
+
+func (s *Service) ListReferrals(ctx context.Context, filter values.ListFilter) ([]values.Referral, *errLib.CommonError) {
+	return s.repo.ListReferrals(ctx, filter)
+}
+
+// GetStats reports on the referrals created in [from, to) and the rewards issued in it.
+func (s *Service) GetStats(ctx context.Context, from, to time.Time) (values.Stats, *errLib.CommonError) {
+	if !from.Before(to) {
+		return values.Stats{}, errLib.New("from must be before to", http.StatusBadRequest)
+	}
+
+	statuses, err := s.repo.CountByStatus(ctx, from, to)
+	if err != nil {
+		return values.Stats{}, err
+	}
+	rewards, err := s.repo.SumIssuedRewards(ctx, from, to)
+	if err != nil {
+		return values.Stats{}, err
+	}
+	top, err := s.repo.ListTopReferrers(ctx, from, to, topReferrerLimit)
+	if err != nil {
+		return values.Stats{}, err
+	}
+
+	stats := values.Stats{From: from, To: to, Statuses: statuses, Rewards: rewards, TopReferrers: top}
+	for _, count := range statuses {
+		stats.Referrals += count.Referrals
+		switch count.Status {
+		case values.StatusQualified, values.StatusRewarded:
+			stats.Qualified += count.Referrals
+		case values.StatusRejected:
+			stats.Rejected += count.Referrals
+		}
+	}
+	return stats, nil
+}
+
+func (s *Service) GetSettings(ctx context.Context) (values.Settings, *errLib.CommonError) {
+	return s.repo.GetSettings(ctx)
+}
+
+// UpdateSettings changes the referral program's rewards. Referrals that already
+// qualified keep the rewards they were given.
+func (s *Service) UpdateSettings(ctx context.Context, v values.UpdateSettingsValues) (values.Settings, *errLib.CommonError) {
+	staffID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return values.Settings{}, err
+	}
+
+	var settings values.Settings
+	err = txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		var err *errLib.CommonError
+		if settings, err = s.repo.WithTx(tx).UpdateSettings(ctx, v, staffID); err != nil {
+			return err
+		}
+		state := "off"
+		if v.Enabled {
+			state = "on"
+		}
+		return s.staffActivityLogsService.InsertStaffActivity(ctx, tx, staffID,
+			fmt.Sprintf("Updated referral program settings: %s, referrer earns %s, referee earns %s", state,
+				describeReward(v.ReferrerRewardType, v.ReferrerRewardAmount), describeReward(v.RefereeRewardType, v.RefereeRewardAmount)))
+	})
+	if err != nil {
+		return values.Settings{}, err
+	}
+	return settings, nil
+}
+
+// NormalizeEmail reduces an address to the mailbox it delivers to: lowercased, without
+// a +tag, and for Gmail without dots, so aliases of one inbox compare equal.
+func NormalizeEmail(address string) string {
+	address = strings.ToLower(strings.TrimSpace(address))
+	at := strings.LastIndex(address, "@")
+	if at < 0 {
+		return address
+	}
+
+	local, domain := address[:at], address[at+1:]
+	if plus := strings.Index(local, "+"); plus >= 0 {
+		local = local[:plus]
+	}
+	if domain == "googlemail.com" {
+		domain = "gmail.com"
+	}
+	if domain == "gmail.com" {
+		local = strings.ReplaceAll(local, ".", "")
+	}
+	return local + "@" + domain
+}
+
+func referralLink(baseURL, code string) string {
+	return strings.TrimRight(baseURL, "/") + "/register?ref=" + code
+}
+
+func describeReward(rewardType values.RewardType, amount int32) string {
+	switch rewardType {
+	case values.RewardCredits:
+		return fmt.Sprintf("%d credits", amount)
+	case values.RewardCoupon:
+		return fmt.Sprintf("$%.2f off coupon", float64(amount)/100)
+	case values.RewardBalance:
+		return fmt.Sprintf("$%.2f account balance", float64(amount)/100)
+	}
+	return "nothing"
+}
+
+func generateCode(length int) (string, *errLib.CommonError) {
+	max := big.NewInt(int64(len(codeAlphabet)))
+	code := make([]byte, length)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			log.Printf("Failed to generate referral code: %v", err)
+			return "", errLib.New("Failed to generate referral code", http.StatusInternalServerError)
+		}
+		code[i] = codeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
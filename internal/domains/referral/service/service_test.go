@@ -0,0 +1,73 @@
+package referral
+
+import (
+	"testing"
+
+	values "api/internal/domains/referral/values"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeEmail(t *testing.T) {
+	t.Run("Ignores case, spaces and tags", func(t *testing.T) {
+		assert.Equal(t, "jordan@example.com", NormalizeEmail(" Jordan+rise@Example.com "))
+	})
+
+	t.Run("Ignores dots in Gmail addresses only", func(t *testing.T) {
+		assert.Equal(t, "jordansmith@gmail.com", NormalizeEmail("jordan.smith@googlemail.com"))
+		assert.Equal(t, "jordan.smith@example.com", NormalizeEmail("jordan.smith@example.com"))
+	})
+
+	t.Run("Leaves addresses without a domain alone", func(t *testing.T) {
+		assert.Equal(t, "jordan", NormalizeEmail("Jordan"))
+	})
+}
+
+func TestCodes(t *testing.T) {
+	t.Run("Generates codes that survive normalizing", func(t *testing.T) {
+		code, err := generateCode(codeLength)
+		require.Nil(t, err)
+		assert.Len(t, code, codeLength)
+		assert.Equal(t, code, values.NormalizeCode(code))
+	})
+
+	t.Run("Normalizes what customers type", func(t *testing.T) {
+		assert.Equal(t, "K7QX3MPA", values.NormalizeCode(" k7qx-3mpa "))
+	})
+
+	t.Run("Builds the sign-up link", func(t *testing.T) {
+		assert.Equal(t, "https://app.example.com/register?ref=K7QX3MPA", referralLink("https://app.example.com/", "K7QX3MPA"))
+	})
+}
+
+func TestRewardsFor(t *testing.T) {
+	referral := values.Referral{ID: uuid.New(), ReferrerID: uuid.New(), RefereeID: uuid.New()}
+
+	t.Run("Rewards both sides", func(t *testing.T) {
+		rewards := rewardsFor(referral, values.Settings{
+			ReferrerRewardType:   values.RewardBalance,
+			ReferrerRewardAmount: 2000,
+			RefereeRewardType:    values.RewardCoupon,
+			RefereeRewardAmount:  1000,
+		})
+		require.Len(t, rewards, 2)
+		assert.Equal(t, referral.ReferrerID, rewards[0].CustomerID)
+		assert.Equal(t, values.RecipientReferrer, rewards[0].Recipient)
+		assert.Equal(t, string(values.RewardBalance), rewards[0].RewardType)
+		assert.Equal(t, referral.RefereeID, rewards[1].CustomerID)
+		assert.Equal(t, int32(1000), rewards[1].Amount)
+	})
+
+	t.Run("Skips sides that earn nothing", func(t *testing.T) {
+		rewards := rewardsFor(referral, values.Settings{
+			ReferrerRewardType:   values.RewardCredits,
+			ReferrerRewardAmount: 5,
+			RefereeRewardType:    values.RewardNone,
+			RefereeRewardAmount:  1000,
+		})
+		require.Len(t, rewards, 1)
+		assert.Equal(t, values.RecipientReferrer, rewards[0].Recipient)
+	})
+}
@@ -0,0 +1,183 @@
+package values
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Referral statuses. A referral is pending until the referee's first membership
+// invoice is paid, qualified while its rewards are being issued, and rewarded once
+// they all are.
+const (
+	StatusPending   = "pending"
+	StatusQualified = "qualified"
+	StatusRewarded  = "rewarded"
+	StatusRejected  = "rejected"
+)
+
+// Why a referral was rejected as a self-referral.
+const (
+	RejectSameEmail         = "same_email"
+	RejectSameDevice        = "same_device"
+	RejectSamePaymentMethod = "same_payment_method"
+)
+
+// Who a reward is for.
+const (
+	RecipientReferrer = "referrer"
+	RecipientReferee  = "referee"
+)
+
+// Reward statuses.
+const (
+	RewardPending = "pending"
+	RewardIssued  = "issued"
+)
+
+// RewardType is what a side of a referral earns. Amounts are a number of credits for
+// RewardCredits and cents for RewardCoupon and RewardBalance.
+type RewardType string
+
+const (
+	RewardNone    RewardType = "none"
+	RewardCredits RewardType = "credits"
+	RewardCoupon  RewardType = "coupon"
+	RewardBalance RewardType = "balance"
+)
+
+func (t RewardType) IsValid() bool {
+	switch t {
+	case RewardNone, RewardCredits, RewardCoupon, RewardBalance:
+		return true
+	}
+	return false
+}
+
+// Settings are the referral program's rewards.
+type Settings struct {
+	Enabled              bool
+	ReferrerRewardType   RewardType
+	ReferrerRewardAmount int32
+	RefereeRewardType    RewardType
+	RefereeRewardAmount  int32
+	UpdatedBy            *uuid.UUID
+	UpdatedAt            time.Time
+}
+
+type UpdateSettingsValues struct {
+	Enabled              bool
+	ReferrerRewardType   RewardType
+	ReferrerRewardAmount int32
+	RefereeRewardType    RewardType
+	RefereeRewardAmount  int32
+}
+
+// Registration is a new customer who may have signed up with a referral code.
+type Registration struct {
+	CustomerID uuid.UUID
+	Email      string
+	Code       *string
+	DeviceID   *string
+}
+
+// Referrer is the customer a referral code belongs to.
+type Referrer struct {
+	CustomerID uuid.UUID
+	Code       string
+	FirstName  string
+}
+
+type Referral struct {
+	ID                  uuid.UUID
+	ReferrerID          uuid.UUID
+	ReferrerName        string
+	RefereeID           uuid.UUID
+	RefereeName         string
+	Code                string
+	Status              string
+	RejectionReason     *string
+	QualifyingInvoiceID *string
+	QualifiedAt         *time.Time
+	RewardedAt          *time.Time
+	CreatedAt           time.Time
+}
+
+type Reward struct {
+	ID           uuid.UUID
+	ReferralID   uuid.UUID
+	CustomerID   uuid.UUID
+	Recipient    string
+	RewardType   RewardType
+	Amount       int32
+	Status       string
+	DiscountCode *string
+	Attempts     int32
+	LastError    *string
+	IssuedAt     *time.Time
+	CreatedAt    time.Time
+}
+
+// Summary is a customer's referral code with how their invites are doing.
+type Summary struct {
+	Code      string
+	Link      string
+	Invited   int64
+	Pending   int64
+	Qualified int64
+	Rewarded  int64
+	Rejected  int64
+	Rewards   []Reward
+}
+
+type ListFilter struct {
+	Status     string
+	ReferrerID uuid.UUID
+	Limit      int32
+	Offset     int32
+}
+
+// StatusCount counts the referrals with a status, and for rejected referrals a reason.
+type StatusCount struct {
+	Status          string
+	RejectionReason string
+	Referrals       int64
+}
+
+// RewardTotal totals the rewards of one type issued in a reporting period.
+type RewardTotal struct {
+	RewardType RewardType
+	Rewards    int64
+	Amount     int64
+}
+
+type TopReferrer struct {
+	CustomerID uuid.UUID
+	FirstName  string
+	LastName   string
+	Referrals  int64
+	Qualified  int64
+}
+
+// Stats reports on the referrals created in [From, To) and the rewards issued in it.
+type Stats struct {
+	From         time.Time
+	To           time.Time
+	Referrals    int64
+	Qualified    int64
+	Rejected     int64
+	Statuses     []StatusCount
+	Rewards      []RewardTotal
+	TopReferrers []TopReferrer
+}
+
+// NormalizeCode uppercases a referral code and strips spaces and dashes.
+func NormalizeCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(code)))
+}
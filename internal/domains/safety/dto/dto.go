@@ -0,0 +1,338 @@
+package safety
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	values "api/internal/domains/safety/values"
+	errLib "api/internal/libs/errors"
+	"api/internal/libs/validators"
+
+	"github.com/google/uuid"
+)
+
+type AthleteRequestDto struct {
+	AthleteID uuid.UUID `json:"athlete_id" validate:"required"`
+	Injury    *string   `json:"injury,omitempty" validate:"omitempty,max=2000" example:"Rolled left ankle landing a rebound"`
+	BodyPart  *string   `json:"body_part,omitempty" validate:"omitempty,max=50" example:"Left ankle"`
+	Treatment *string   `json:"treatment,omitempty" validate:"omitempty,max=2000" example:"Ice and rest, sat out the second half"`
+}
+
+// IncidentRequestDto reports or edits an incident. Link at most one of event_id,
+// game_id and practice_id; location_id defaults to that activity's location.
+// Injuries and illnesses need at least one athlete. Set notify_parents to email the
+// families of the athletes involved once the report is saved.
+type IncidentRequestDto struct {
+	Type          string              `json:"type" validate:"required,oneof=injury illness behavioral property other" example:"injury"`
+	Severity      string              `json:"severity" validate:"required,oneof=minor moderate serious critical" example:"moderate"`
+	OccurredAt    time.Time           `json:"occurred_at" validate:"required" example:"2026-10-14T18:45:00Z"`
+	EventID       *uuid.UUID          `json:"event_id,omitempty"`
+	GameID        *uuid.UUID          `json:"game_id,omitempty"`
+	PracticeID    *uuid.UUID          `json:"practice_id,omitempty"`
+	LocationID    *uuid.UUID          `json:"location_id,omitempty"`
+	Summary       string              `json:"summary" validate:"required,notwhitespace,max=200" example:"Ankle sprain during scrimmage"`
+	Description   *string             `json:"description,omitempty" validate:"omitempty,max=5000"`
+	ActionTaken   *string             `json:"action_taken,omitempty" validate:"omitempty,max=5000" example:"First aid given, parent picked up athlete"`
+	Athletes      []AthleteRequestDto `json:"athletes" validate:"omitempty,dive"`
+	NotifyParents bool                `json:"notify_parents" example:"true"`
+}
+
+// FollowUpRequestDto adds a note to an incident's timeline, optionally moving it to
+// a new status.
+type FollowUpRequestDto struct {
+	Note   string `json:"note" validate:"required,notwhitespace,max=5000" example:"Cleared to return by physician"`
+	Status string `json:"status,omitempty" validate:"omitempty,oneof=open follow_up resolved closed" example:"resolved"`
+}
+
+type MedicalInfoRequestDto struct {
+	Allergies         *string `json:"allergies,omitempty" validate:"omitempty,max=2000" example:"Peanuts"`
+	MedicalConditions *string `json:"medical_conditions,omitempty" validate:"omitempty,max=2000" example:"Asthma"`
+	Medications       *string `json:"medications,omitempty" validate:"omitempty,max=2000" example:"Inhaler before exercise"`
+	PhysicianName     *string `json:"physician_name,omitempty" validate:"omitempty,max=100" example:"Dr. Jane Smith"`
+	PhysicianPhone    *string `json:"physician_phone,omitempty" validate:"omitempty,max=25" example:"+14035551234"`
+	Notes             *string `json:"notes,omitempty" validate:"omitempty,max=2000"`
+}
+
+func (dto IncidentRequestDto) ToValues() (values.IncidentDetails, *errLib.CommonError) {
+	if err := validators.ValidateDto(&dto); err != nil {
+		return values.IncidentDetails{}, err
+	}
+
+	linked := 0
+	for _, id := range []*uuid.UUID{dto.EventID, dto.GameID, dto.PracticeID} {
+		if id != nil {
+			linked++
+		}
+	}
+	if linked > 1 {
+		return values.IncidentDetails{}, errLib.New("An incident can be linked to one event, game or practice", http.StatusBadRequest)
+	}
+	if dto.OccurredAt.After(time.Now()) {
+		return values.IncidentDetails{}, errLib.New("occurred_at cannot be in the future", http.StatusBadRequest)
+	}
+
+	incidentType := values.IncidentType(dto.Type)
+	if len(dto.Athletes) == 0 && (incidentType == values.IncidentInjury || incidentType == values.IncidentIllness) {
+		return values.IncidentDetails{}, errLib.New("Injuries and illnesses need at least one athlete", http.StatusBadRequest)
+	}
+
+	athletes := make([]values.AthleteDetails, len(dto.Athletes))
+	seen := make(map[uuid.UUID]bool, len(dto.Athletes))
+	for i, athlete := range dto.Athletes {
+		if seen[athlete.AthleteID] {
+			return values.IncidentDetails{}, errLib.New(fmt.Sprintf("athletes[%d]: athlete is listed more than once", i), http.StatusBadRequest)
+		}
+		seen[athlete.AthleteID] = true
+		athletes[i] = values.AthleteDetails{
+			AthleteID: athlete.AthleteID,
+			Injury:    athlete.Injury,
+			BodyPart:  athlete.BodyPart,
+			Treatment: athlete.Treatment,
+		}
+	}
+
+	return values.IncidentDetails{
+		Type:          incidentType,
+		Severity:      values.Severity(dto.Severity),
+		OccurredAt:    dto.OccurredAt,
+		EventID:       dto.EventID,
+		GameID:        dto.GameID,
+		PracticeID:    dto.PracticeID,
+		LocationID:    dto.LocationID,
+		Summary:       dto.Summary,
+		Description:   dto.Description,
+		ActionTaken:   dto.ActionTaken,
+		Athletes:      athletes,
+		NotifyParents: dto.NotifyParents,
+	}, nil
+}
+
+// ToValues returns the note and the new status, which is empty to keep the current one.
+func (dto FollowUpRequestDto) ToValues() (string, values.IncidentStatus, *errLib.CommonError) {
+	if err := validators.ValidateDto(&dto); err != nil {
+		return "", "", err
+	}
+	return dto.Note, values.IncidentStatus(dto.Status), nil
+}
+
+func (dto MedicalInfoRequestDto) ToValues() (values.MedicalDetails, *errLib.CommonError) {
+	if err := validators.ValidateDto(&dto); err != nil {
+		return values.MedicalDetails{}, err
+	}
+	return values.MedicalDetails{
+		Allergies:         dto.Allergies,
+		MedicalConditions: dto.MedicalConditions,
+		Medications:       dto.Medications,
+		PhysicianName:     dto.PhysicianName,
+		PhysicianPhone:    dto.PhysicianPhone,
+		Notes:             dto.Notes,
+	}, nil
+}
+
+type AthleteResponseDto struct {
+	AthleteID        uuid.UUID  `json:"athlete_id"`
+	FirstName        string     `json:"first_name"`
+	LastName         string     `json:"last_name"`
+	Injury           *string    `json:"injury,omitempty"`
+	BodyPart         *string    `json:"body_part,omitempty"`
+	Treatment        *string    `json:"treatment,omitempty"`
+	ParentNotifiedAt *time.Time `json:"parent_notified_at,omitempty"`
+}
+
+type FollowUpResponseDto struct {
+	ID         uuid.UUID  `json:"id"`
+	Status     string     `json:"status"`
+	Note       string     `json:"note"`
+	CreatedBy  *uuid.UUID `json:"created_by,omitempty"`
+	AuthorName string     `json:"author_name,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type AttachmentResponseDto struct {
+	ID            uuid.UUID  `json:"id"`
+	FileName      string     `json:"file_name" audit:"personal"`
+	ContentType   string     `json:"content_type"`
+	FileSizeBytes int64      `json:"file_size_bytes"`
+	UploadedBy    *uuid.UUID `json:"uploaded_by,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// IncidentSummaryResponseDto is an incident as listed. Injury details are only
+// returned when a single incident is read, so that every read of them is logged.
+type IncidentSummaryResponseDto struct {
+	ID           uuid.UUID   `json:"id"`
+	Type         string      `json:"type"`
+	Severity     string      `json:"severity"`
+	Status       string      `json:"status"`
+	OccurredAt   time.Time   `json:"occurred_at"`
+	EventID      *uuid.UUID  `json:"event_id,omitempty"`
+	GameID       *uuid.UUID  `json:"game_id,omitempty"`
+	PracticeID   *uuid.UUID  `json:"practice_id,omitempty"`
+	LocationID   *uuid.UUID  `json:"location_id,omitempty"`
+	LocationName *string     `json:"location_name,omitempty"`
+	Summary      string      `json:"summary" audit:"personal"`
+	AthleteIDs   []uuid.UUID `json:"athlete_ids"`
+	ReportedBy   *uuid.UUID  `json:"reported_by,omitempty"`
+	ReporterName string      `json:"reporter_name,omitempty" audit:"personal"`
+	ResolvedAt   *time.Time  `json:"resolved_at,omitempty"`
+	CreatedAt    time.Time   `json:"created_at"`
+}
+
+type IncidentResponseDto struct {
+	IncidentSummaryResponseDto
+	Description *string                 `json:"description,omitempty"`
+	ActionTaken *string                 `json:"action_taken,omitempty"`
+	Athletes    []AthleteResponseDto    `json:"athletes"`
+	FollowUps   []FollowUpResponseDto   `json:"follow_ups"`
+	Attachments []AttachmentResponseDto `json:"attachments"`
+	UpdatedAt   time.Time               `json:"updated_at"`
+}
+
+type MedicalInfoResponseDto struct {
+	UserID                       uuid.UUID  `json:"user_id"`
+	FirstName                    string     `json:"first_name"`
+	LastName                     string     `json:"last_name"`
+	EmergencyContactName         *string    `json:"emergency_contact_name,omitempty"`
+	EmergencyContactPhone        *string    `json:"emergency_contact_phone,omitempty"`
+	EmergencyContactRelationship *string    `json:"emergency_contact_relationship,omitempty"`
+	Allergies                    *string    `json:"allergies,omitempty"`
+	MedicalConditions            *string    `json:"medical_conditions,omitempty"`
+	Medications                  *string    `json:"medications,omitempty"`
+	PhysicianName                *string    `json:"physician_name,omitempty"`
+	PhysicianPhone               *string    `json:"physician_phone,omitempty"`
+	Notes                        *string    `json:"notes,omitempty"`
+	UpdatedAt                    *time.Time `json:"updated_at,omitempty"`
+}
+
+type AccessLogResponseDto struct {
+	ID         int64      `json:"id"`
+	ViewerID   *uuid.UUID `json:"viewer_id,omitempty"`
+	ViewerName string     `json:"viewer_name,omitempty"`
+	ViewerRole string     `json:"viewer_role,omitempty"`
+	IPAddress  string     `json:"ip_address,omitempty"`
+	Resource   string     `json:"resource" example:"medical_info"`
+	ResourceID *uuid.UUID `json:"resource_id,omitempty"`
+	AccessedAt time.Time  `json:"accessed_at"`
+}
+
+func NewIncidentSummaryResponse(i values.Incident) IncidentSummaryResponseDto {
+	athleteIDs := make([]uuid.UUID, len(i.Athletes))
+	for j, a := range i.Athletes {
+		athleteIDs[j] = a.AthleteID
+	}
+	return IncidentSummaryResponseDto{
+		ID:           i.ID,
+		Type:         string(i.Type),
+		Severity:     string(i.Severity),
+		Status:       string(i.Status),
+		OccurredAt:   i.OccurredAt,
+		EventID:      i.EventID,
+		GameID:       i.GameID,
+		PracticeID:   i.PracticeID,
+		LocationID:   i.LocationID,
+		LocationName: i.LocationName,
+		Summary:      i.Summary,
+		AthleteIDs:   athleteIDs,
+		ReportedBy:   i.ReportedBy,
+		ReporterName: i.ReporterName,
+		ResolvedAt:   i.ResolvedAt,
+		CreatedAt:    i.CreatedAt,
+	}
+}
+
+func NewIncidentSummaryResponses(incidents []values.Incident) []IncidentSummaryResponseDto {
+	resp := make([]IncidentSummaryResponseDto, len(incidents))
+	for i, incident := range incidents {
+		resp[i] = NewIncidentSummaryResponse(incident)
+	}
+	return resp
+}
+
+func NewIncidentResponse(i values.Incident) IncidentResponseDto {
+	athletes := make([]AthleteResponseDto, len(i.Athletes))
+	for j, a := range i.Athletes {
+		athletes[j] = AthleteResponseDto{
+			AthleteID:        a.AthleteID,
+			FirstName:        a.FirstName,
+			LastName:         a.LastName,
+			Injury:           a.Injury,
+			BodyPart:         a.BodyPart,
+			Treatment:        a.Treatment,
+			ParentNotifiedAt: a.ParentNotifiedAt,
+		}
+	}
+
+	followUps := make([]FollowUpResponseDto, len(i.FollowUps))
+	for j, f := range i.FollowUps {
+		followUps[j] = FollowUpResponseDto{
+			ID:         f.ID,
+			Status:     string(f.Status),
+			Note:       f.Note,
+			CreatedBy:  f.CreatedBy,
+			AuthorName: f.AuthorName,
+			CreatedAt:  f.CreatedAt,
+		}
+	}
+
+	attachments := make([]AttachmentResponseDto, len(i.Attachments))
+	for j, a := range i.Attachments {
+		attachments[j] = NewAttachmentResponse(a)
+	}
+
+	return IncidentResponseDto{
+		IncidentSummaryResponseDto: NewIncidentSummaryResponse(i),
+		Description:                i.Description,
+		ActionTaken:                i.ActionTaken,
+		Athletes:                   athletes,
+		FollowUps:                  followUps,
+		Attachments:                attachments,
+		UpdatedAt:                  i.UpdatedAt,
+	}
+}
+
+func NewAttachmentResponse(a values.Attachment) AttachmentResponseDto {
+	return AttachmentResponseDto{
+		ID:            a.ID,
+		FileName:      a.FileName,
+		ContentType:   a.ContentType,
+		FileSizeBytes: a.FileSizeBytes,
+		UploadedBy:    a.UploadedBy,
+		CreatedAt:     a.CreatedAt,
+	}
+}
+
+func NewMedicalInfoResponse(m values.MedicalInfo) MedicalInfoResponseDto {
+	return MedicalInfoResponseDto{
+		UserID:                       m.UserID,
+		FirstName:                    m.FirstName,
+		LastName:                     m.LastName,
+		EmergencyContactName:         m.EmergencyContactName,
+		EmergencyContactPhone:        m.EmergencyContactPhone,
+		EmergencyContactRelationship: m.EmergencyContactRelationship,
+		Allergies:                    m.Allergies,
+		MedicalConditions:            m.MedicalConditions,
+		Medications:                  m.Medications,
+		PhysicianName:                m.PhysicianName,
+		PhysicianPhone:               m.PhysicianPhone,
+		Notes:                        m.Notes,
+		UpdatedAt:                    m.UpdatedAt,
+	}
+}
+
+func NewAccessLogResponses(entries []values.AccessLogEntry) []AccessLogResponseDto {
+	resp := make([]AccessLogResponseDto, len(entries))
+	for i, e := range entries {
+		resp[i] = AccessLogResponseDto{
+			ID:         e.ID,
+			ViewerID:   e.ViewerID,
+			ViewerName: e.ViewerName,
+			ViewerRole: e.ViewerRole,
+			IPAddress:  e.IPAddress,
+			Resource:   string(e.Resource),
+			ResourceID: e.ResourceID,
+			AccessedAt: e.AccessedAt,
+		}
+	}
+	return resp
+}
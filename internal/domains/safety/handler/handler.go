@@ -0,0 +1,537 @@
+package safety
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"api/internal/di"
+	dto "api/internal/domains/safety/dto"
+	service "api/internal/domains/safety/service"
+	values "api/internal/domains/safety/values"
+	errLib "api/internal/libs/errors"
+	responseHandlers "api/internal/libs/responses"
+	"api/internal/libs/validators"
+	"api/utils/timezone"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+// maxAttachmentSize is the largest photo or document accepted on an incident
+const maxAttachmentSize = 20 << 20
+
+// attachmentTypes are the file types accepted as incident attachments
+var attachmentTypes = map[string]string{
+	".pdf":  "application/pdf",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+}
+
+type Handler struct {
+	Service *service.Service
+}
+
+func NewHandler(container *di.Container) *Handler {
+	return &Handler{Service: service.NewService(container)}
+}
+
+func paging(r *http.Request) (int32, int32) {
+	limit := 20
+	offset := 0
+
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsed, parseErr := strconv.Atoi(limitStr); parseErr == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if parsed, parseErr := strconv.Atoi(offsetStr); parseErr == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+	return int32(limit), int32(offset)
+}
+
+// GetIncidents lists incidents, most recent first.
+// @Description Admins see every incident; coaches the ones they reported or that involve athletes
+// @Description they coach. Injury details are left out; read a single incident to see them.
+// @Tags Safety
+// @Produce json
+// @Security Bearer
+// @Param status query string false "open, follow_up, resolved or closed"
+// @Param type query string false "injury, illness, behavioral, property or other"
+// @Param athlete_id query string false "Only incidents involving this athlete"
+// @Param from query string false "Occurred on or after this date (YYYY-MM-DD)"
+// @Param to query string false "Occurred on or before this date (YYYY-MM-DD)"
+// @Param limit query int false "Number of records (default 20, max 100)"
+// @Param offset query int false "Number of records to skip"
+// @Success 200 {array} dto.IncidentSummaryResponseDto "Incidents"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid filter"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /safety/incidents [get]
+func (h *Handler) GetIncidents(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := values.IncidentFilter{}
+	filter.Limit, filter.Offset = paging(r)
+
+	if statusStr := query.Get("status"); statusStr != "" {
+		status := values.IncidentStatus(statusStr)
+		if !status.Valid() {
+			responseHandlers.RespondWithError(w, errLib.New("Invalid status", http.StatusBadRequest))
+			return
+		}
+		filter.Status = &status
+	}
+
+	if typeStr := query.Get("type"); typeStr != "" {
+		incidentType := values.IncidentType(typeStr)
+		switch incidentType {
+		case values.IncidentInjury, values.IncidentIllness, values.IncidentBehavioral, values.IncidentProperty, values.IncidentOther:
+		default:
+			responseHandlers.RespondWithError(w, errLib.New("Invalid incident type", http.StatusBadRequest))
+			return
+		}
+		filter.Type = &incidentType
+	}
+
+	if idStr := query.Get("athlete_id"); idStr != "" {
+		id, err := validators.ParseUUID(idStr)
+		if err != nil {
+			responseHandlers.RespondWithError(w, err)
+			return
+		}
+		filter.AthleteID = &id
+	}
+
+	if fromStr := query.Get("from"); fromStr != "" {
+		from, err := validators.ParseDate(fromStr)
+		if err != nil {
+			responseHandlers.RespondWithError(w, err)
+			return
+		}
+		start := timezone.At(from, 0, 0, 0, timezone.Default())
+		filter.From = &start
+	}
+
+	if toStr := query.Get("to"); toStr != "" {
+		to, err := validators.ParseDate(toStr)
+		if err != nil {
+			responseHandlers.RespondWithError(w, err)
+			return
+		}
+		end := timezone.At(to.AddDate(0, 0, 1), 0, 0, 0, timezone.Default())
+		filter.To = &end
+	}
+
+	incidents, err := h.Service.ListIncidents(r.Context(), filter)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewIncidentSummaryResponses(incidents), http.StatusOK)
+}
+
+// GetIncident returns an incident with injury details, follow-ups and attachments.
+// @Description The read is recorded in the medical access log of each athlete involved.
+// @Tags Safety
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Incident ID"
+// @Success 200 {object} dto.IncidentResponseDto "Incident"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Not the coach's incident"
+// @Failure 404 {object} map[string]interface{} "Not Found: Incident not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /safety/incidents/{id} [get]
+func (h *Handler) GetIncident(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	incident, err := h.Service.GetIncident(r.Context(), id)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewIncidentResponse(incident), http.StatusOK)
+}
+
+// CreateIncident reports an injury or other incident.
+// @Description Link the incident to at most one event, game or practice; its location is used unless
+// @Description location_id is given. Set notify_parents to email the families of the athletes involved.
+// @Tags Safety
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body dto.IncidentRequestDto true "Incident"
+// @Success 201 {object} dto.IncidentResponseDto "Incident reported"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid incident"
+// @Failure 404 {object} map[string]interface{} "Not Found: Event, game, practice, location or athlete not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /safety/incidents [post]
+func (h *Handler) CreateIncident(w http.ResponseWriter, r *http.Request) {
+	var body dto.IncidentRequestDto
+	if err := validators.ParseJSON(r.Body, &body); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	details, err := body.ToValues()
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	created, err := h.Service.CreateIncident(r.Context(), details)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewIncidentResponse(created), http.StatusCreated)
+}
+
+// UpdateIncident edits an incident and replaces the athletes involved.
+// @Description Only the staff member who reported the incident or an admin can edit it. Athletes whose
+// @Description families were already notified are not emailed again.
+// @Tags Safety
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Incident ID"
+// @Param request body dto.IncidentRequestDto true "Incident"
+// @Success 200 {object} dto.IncidentResponseDto "Incident updated"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid incident"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Not the reporter"
+// @Failure 404 {object} map[string]interface{} "Not Found: Incident not found"
+// @Failure 409 {object} map[string]interface{} "Conflict: Incident is closed"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /safety/incidents/{id} [put]
+func (h *Handler) UpdateIncident(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	var body dto.IncidentRequestDto
+	if err = validators.ParseJSON(r.Body, &body); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	details, err := body.ToValues()
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	updated, err := h.Service.UpdateIncident(r.Context(), id, details)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewIncidentResponse(updated), http.StatusOK)
+}
+
+// AddFollowUp adds a note to an incident's timeline, optionally changing its status.
+// @Description Resolving or closing an incident records when it was resolved. Closed incidents can
+// @Description only be followed up or reopened by an admin.
+// @Tags Safety
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Incident ID"
+// @Param request body dto.FollowUpRequestDto true "Follow-up"
+// @Success 200 {object} dto.IncidentResponseDto "Follow-up added"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid follow-up"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Not the coach's incident"
+// @Failure 404 {object} map[string]interface{} "Not Found: Incident not found"
+// @Failure 409 {object} map[string]interface{} "Conflict: Incident is closed"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /safety/incidents/{id}/follow-ups [post]
+func (h *Handler) AddFollowUp(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	var body dto.FollowUpRequestDto
+	if err = validators.ParseJSON(r.Body, &body); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	note, status, err := body.ToValues()
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	updated, err := h.Service.AddFollowUp(r.Context(), id, note, status)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewIncidentResponse(updated), http.StatusOK)
+}
+
+// NotifyParents emails the families of athletes on an incident who were not told yet.
+// @Description Each athlete's parent is emailed, or the athlete when they have no parent. Athletes with
+// @Description no email address on file stay un-notified.
+// @Tags Safety
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Incident ID"
+// @Success 200 {object} map[string]interface{} "Number of families notified and the updated incident"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Not the coach's incident"
+// @Failure 404 {object} map[string]interface{} "Not Found: Incident not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /safety/incidents/{id}/notify [post]
+func (h *Handler) NotifyParents(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	incident, notified, err := h.Service.NotifyParents(r.Context(), id)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, map[string]interface{}{
+		"notified": notified,
+		"incident": dto.NewIncidentResponse(incident),
+	}, http.StatusOK)
+}
+
+// UploadAttachment attaches a photo or document to an incident.
+// @Description Accepts pdf, jpg, jpeg and png files up to 20MB. Files are stored privately.
+// @Tags Safety
+// @Accept multipart/form-data
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Incident ID"
+// @Param file formData file true "Photo or document"
+// @Success 201 {object} dto.AttachmentResponseDto "Attachment uploaded"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Missing, invalid or too large file"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Not the coach's incident"
+// @Failure 404 {object} map[string]interface{} "Not Found: Incident not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /safety/incidents/{id}/attachments [post]
+func (h *Handler) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxAttachmentSize+1<<20)
+	if parseErr := r.ParseMultipartForm(maxAttachmentSize); parseErr != nil {
+		responseHandlers.RespondWithError(w, errLib.New("Invalid form data or file too large (20MB limit)", http.StatusBadRequest))
+		return
+	}
+
+	file, header, fileErr := r.FormFile("file")
+	if fileErr == http.ErrMissingFile {
+		responseHandlers.RespondWithError(w, errLib.New("file is required", http.StatusBadRequest))
+		return
+	}
+	if fileErr != nil {
+		responseHandlers.RespondWithError(w, errLib.New("Invalid file upload", http.StatusBadRequest))
+		return
+	}
+	defer file.Close()
+
+	ext := strings.ToLower(filepath.Ext(header.Filename))
+	contentType, ok := attachmentTypes[ext]
+	if !ok {
+		responseHandlers.RespondWithError(w, errLib.New("Invalid file type. Only pdf, jpg, jpeg, and png are allowed", http.StatusBadRequest))
+		return
+	}
+
+	data, readErr := io.ReadAll(file)
+	if readErr != nil {
+		responseHandlers.RespondWithError(w, errLib.New("Failed to read uploaded file", http.StatusBadRequest))
+		return
+	}
+
+	attachment, err := h.Service.UploadAttachment(r.Context(), id, data, filepath.Base(header.Filename), ext, contentType)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewAttachmentResponse(attachment), http.StatusCreated)
+}
+
+// GetAttachment downloads an incident attachment.
+// @Description The download is recorded in the medical access log of each athlete involved.
+// @Tags Safety
+// @Produce application/octet-stream
+// @Security Bearer
+// @Param id path string true "Incident ID"
+// @Param attachment_id path string true "Attachment ID"
+// @Success 200 {file} file "Attachment"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Not the coach's incident"
+// @Failure 404 {object} map[string]interface{} "Not Found: Incident or attachment not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /safety/incidents/{id}/attachments/{attachment_id} [get]
+func (h *Handler) GetAttachment(w http.ResponseWriter, r *http.Request) {
+	id, attachmentID, err := attachmentIDs(r)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	attachment, data, err := h.Service.GetAttachment(r.Context(), id, attachmentID)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", attachment.FileName))
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// DeleteAttachment removes an incident attachment.
+// @Description Staff can remove attachments they uploaded; admins any attachment.
+// @Tags Safety
+// @Security Bearer
+// @Param id path string true "Incident ID"
+// @Param attachment_id path string true "Attachment ID"
+// @Success 204 "Attachment removed"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Not the uploader"
+// @Failure 404 {object} map[string]interface{} "Not Found: Incident or attachment not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /safety/incidents/{id}/attachments/{attachment_id} [delete]
+func (h *Handler) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	id, attachmentID, err := attachmentIDs(r)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	if err = h.Service.DeleteAttachment(r.Context(), id, attachmentID); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, nil, http.StatusNoContent)
+}
+
+func attachmentIDs(r *http.Request) (uuid.UUID, uuid.UUID, *errLib.CommonError) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	attachmentID, err := validators.ParseUUID(chi.URLParam(r, "attachment_id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	return id, attachmentID, nil
+}
+
+// GetMedicalInfo returns a user's emergency contact and medical details.
+// @Description Admins can read any user's details; coaches only those of athletes they coach. The read
+// @Description is recorded in the user's medical access log.
+// @Tags Safety
+// @Produce json
+// @Security Bearer
+// @Param user_id path string true "User ID"
+// @Success 200 {object} dto.MedicalInfoResponseDto "Medical information"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Not one of the coach's athletes"
+// @Failure 404 {object} map[string]interface{} "Not Found: User not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /safety/medical/{user_id} [get]
+func (h *Handler) GetMedicalInfo(w http.ResponseWriter, r *http.Request) {
+	userID, err := validators.ParseUUID(chi.URLParam(r, "user_id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	info, err := h.Service.GetMedicalInfo(r.Context(), userID)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewMedicalInfoResponse(info), http.StatusOK)
+}
+
+// UpdateMedicalInfo replaces a user's medical details.
+// @Description Admins only. The emergency contact is edited on the user's profile.
+// @Tags Safety
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param user_id path string true "User ID"
+// @Param request body dto.MedicalInfoRequestDto true "Medical details"
+// @Success 200 {object} dto.MedicalInfoResponseDto "Medical information updated"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid medical details"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Admins only"
+// @Failure 404 {object} map[string]interface{} "Not Found: User not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /safety/medical/{user_id} [put]
+func (h *Handler) UpdateMedicalInfo(w http.ResponseWriter, r *http.Request) {
+	userID, err := validators.ParseUUID(chi.URLParam(r, "user_id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	var body dto.MedicalInfoRequestDto
+	if err = validators.ParseJSON(r.Body, &body); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	details, err := body.ToValues()
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	info, err := h.Service.UpdateMedicalInfo(r.Context(), userID, details)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewMedicalInfoResponse(info), http.StatusOK)
+}
+
+// GetAccessLog lists who read a user's medical details and incident reports.
+// @Tags Safety
+// @Produce json
+// @Security Bearer
+// @Param user_id path string true "User ID"
+// @Param limit query int false "Number of records (default 20, max 100)"
+// @Param offset query int false "Number of records to skip"
+// @Success 200 {array} dto.AccessLogResponseDto "Access log, most recent first"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Admins only"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /safety/medical/{user_id}/access-log [get]
+func (h *Handler) GetAccessLog(w http.ResponseWriter, r *http.Request) {
+	userID, err := validators.ParseUUID(chi.URLParam(r, "user_id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	limit, offset := paging(r)
+	entries, err := h.Service.ListAccessLog(r.Context(), userID, limit, offset)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewAccessLogResponses(entries), http.StatusOK)
+}
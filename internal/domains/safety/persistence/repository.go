@@ -0,0 +1,533 @@
+package safety
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"api/internal/di"
+	db "api/internal/domains/safety/persistence/sqlc/generated"
+	values "api/internal/domains/safety/values"
+	errLib "api/internal/libs/errors"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Repository stores incident reports, users' medical details and the log of who
+// read them.
+type Repository struct {
+	Queries *db.Queries
+	Tx      *sql.Tx
+}
+
+func NewRepository(container *di.Container) *Repository {
+	return &Repository{Queries: container.Queries.SafetyDb}
+}
+
+func (r *Repository) GetTx() *sql.Tx { return r.Tx }
+
+func (r *Repository) WithTx(tx *sql.Tx) *Repository {
+	return &Repository{Queries: r.Queries.WithTx(tx), Tx: tx}
+}
+
+func nullUUID(id *uuid.UUID) uuid.NullUUID {
+	if id == nil {
+		return uuid.NullUUID{}
+	}
+	return uuid.NullUUID{UUID: *id, Valid: true}
+}
+
+func optionalUUID(id uuid.NullUUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
+	}
+	return &id.UUID
+}
+
+func nullString(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *s, Valid: true}
+}
+
+func optionalString(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
+}
+
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
+
+func optionalTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+// incidentError maps constraint violations from saving an incident to client errors.
+func incidentError(err error, action string) *errLib.CommonError {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Constraint {
+		case "incidents_event_id_fkey":
+			return errLib.New("Event not found", http.StatusNotFound)
+		case "incidents_game_id_fkey":
+			return errLib.New("Game not found", http.StatusNotFound)
+		case "incidents_practice_id_fkey":
+			return errLib.New("Practice not found", http.StatusNotFound)
+		case "incidents_location_id_fkey":
+			return errLib.New("Location not found", http.StatusNotFound)
+		case "incident_athletes_athlete_id_fkey":
+			return errLib.New("Athlete not found", http.StatusNotFound)
+		case "check_incident_single_activity":
+			return errLib.New("An incident can be linked to one event, game or practice", http.StatusBadRequest)
+		}
+	}
+	log.Printf("Failed to %s: %v", action, err)
+	return errLib.New("Failed to "+action, http.StatusInternalServerError)
+}
+
+// CoachIsAssigned reports whether a coach coaches the athlete's team or is staffed
+// on a current or upcoming event of a program the athlete is enrolled in.
+func (r *Repository) CoachIsAssigned(ctx context.Context, coachID, athleteID uuid.UUID) (bool, *errLib.CommonError) {
+	assigned, err := r.Queries.CoachIsAssigned(ctx, db.CoachIsAssignedParams{AthleteID: athleteID, CoachID: coachID})
+	if err != nil {
+		log.Printf("Failed to check coach %s assignment to athlete %s: %v", coachID, athleteID, err)
+		return false, errLib.New("Failed to check access to athlete", http.StatusInternalServerError)
+	}
+	return assigned, nil
+}
+
+// ----- Incidents -----
+
+func (r *Repository) CreateIncident(ctx context.Context, details values.IncidentDetails, reportedBy uuid.UUID) (uuid.UUID, *errLib.CommonError) {
+	created, err := r.Queries.CreateIncident(ctx, db.CreateIncidentParams{
+		IncidentType: string(details.Type),
+		Severity:     string(details.Severity),
+		OccurredAt:   details.OccurredAt,
+		EventID:      nullUUID(details.EventID),
+		GameID:       nullUUID(details.GameID),
+		PracticeID:   nullUUID(details.PracticeID),
+		LocationID:   nullUUID(details.LocationID),
+		Summary:      details.Summary,
+		Description:  nullString(details.Description),
+		ActionTaken:  nullString(details.ActionTaken),
+		ReportedBy:   uuid.NullUUID{UUID: reportedBy, Valid: reportedBy != uuid.Nil},
+	})
+	if err != nil {
+		return uuid.Nil, incidentError(err, "create incident")
+	}
+	return created.ID, nil
+}
+
+func (r *Repository) UpdateIncident(ctx context.Context, id uuid.UUID, details values.IncidentDetails) *errLib.CommonError {
+	_, err := r.Queries.UpdateIncident(ctx, db.UpdateIncidentParams{
+		ID:           id,
+		IncidentType: string(details.Type),
+		Severity:     string(details.Severity),
+		OccurredAt:   details.OccurredAt,
+		EventID:      nullUUID(details.EventID),
+		GameID:       nullUUID(details.GameID),
+		PracticeID:   nullUUID(details.PracticeID),
+		LocationID:   nullUUID(details.LocationID),
+		Summary:      details.Summary,
+		Description:  nullString(details.Description),
+		ActionTaken:  nullString(details.ActionTaken),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errLib.New("Incident not found", http.StatusNotFound)
+		}
+		return incidentError(err, "update incident")
+	}
+	return nil
+}
+
+// ReplaceAthletes sets the athletes involved in an incident. Athletes who stay on
+// the incident keep their parent notification time.
+func (r *Repository) ReplaceAthletes(ctx context.Context, incidentID uuid.UUID, athletes []values.AthleteDetails) *errLib.CommonError {
+	keep := make([]uuid.UUID, len(athletes))
+	for i, athlete := range athletes {
+		keep[i] = athlete.AthleteID
+	}
+
+	if err := r.Queries.DeleteIncidentAthletesExcept(ctx, db.DeleteIncidentAthletesExceptParams{
+		IncidentID: incidentID,
+		KeepIds:    keep,
+	}); err != nil {
+		return incidentError(err, "update incident athletes")
+	}
+
+	for _, athlete := range athletes {
+		if err := r.Queries.UpsertIncidentAthlete(ctx, db.UpsertIncidentAthleteParams{
+			IncidentID: incidentID,
+			AthleteID:  athlete.AthleteID,
+			Injury:     nullString(athlete.Injury),
+			BodyPart:   nullString(athlete.BodyPart),
+			Treatment:  nullString(athlete.Treatment),
+		}); err != nil {
+			return incidentError(err, "update incident athletes")
+		}
+	}
+	return nil
+}
+
+func (r *Repository) UpdateStatus(ctx context.Context, id uuid.UUID, status values.IncidentStatus, resolvedAt *time.Time) *errLib.CommonError {
+	_, err := r.Queries.UpdateIncidentStatus(ctx, db.UpdateIncidentStatusParams{
+		ID:         id,
+		Status:     string(status),
+		ResolvedAt: nullTime(resolvedAt),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errLib.New("Incident not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to update status of incident %s: %v", id, err)
+		return errLib.New("Failed to update incident status", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (r *Repository) AddFollowUp(ctx context.Context, incidentID uuid.UUID, status values.IncidentStatus, note string, createdBy uuid.UUID) *errLib.CommonError {
+	if err := r.Queries.CreateFollowUp(ctx, db.CreateFollowUpParams{
+		IncidentID: incidentID,
+		Status:     string(status),
+		Note:       note,
+		CreatedBy:  uuid.NullUUID{UUID: createdBy, Valid: createdBy != uuid.Nil},
+	}); err != nil {
+		log.Printf("Failed to add follow-up to incident %s: %v", incidentID, err)
+		return errLib.New("Failed to add follow-up", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func incidentFromRow(row db.GetIncidentByIdRow) values.Incident {
+	return values.Incident{
+		ID:           row.ID,
+		Type:         values.IncidentType(row.IncidentType),
+		Severity:     values.Severity(row.Severity),
+		Status:       values.IncidentStatus(row.Status),
+		OccurredAt:   row.OccurredAt,
+		EventID:      optionalUUID(row.EventID),
+		GameID:       optionalUUID(row.GameID),
+		PracticeID:   optionalUUID(row.PracticeID),
+		LocationID:   optionalUUID(row.LocationID),
+		LocationName: optionalString(row.LocationName),
+		Summary:      row.Summary,
+		Description:  optionalString(row.Description),
+		ActionTaken:  optionalString(row.ActionTaken),
+		ReportedBy:   optionalUUID(row.ReportedBy),
+		ReporterName: row.ReporterName,
+		ResolvedAt:   optionalTime(row.ResolvedAt),
+		CreatedAt:    row.CreatedAt,
+		UpdatedAt:    row.UpdatedAt,
+		Athletes:     []values.IncidentAthlete{},
+		FollowUps:    []values.FollowUp{},
+		Attachments:  []values.Attachment{},
+	}
+}
+
+func attachmentFromRow(row db.SafetyIncidentAttachment) values.Attachment {
+	return values.Attachment{
+		ID:            row.ID,
+		IncidentID:    row.IncidentID,
+		ObjectPath:    row.ObjectPath,
+		FileName:      row.FileName,
+		ContentType:   row.ContentType,
+		FileSizeBytes: row.FileSizeBytes,
+		UploadedBy:    optionalUUID(row.UploadedBy),
+		CreatedAt:     row.CreatedAt,
+	}
+}
+
+// GetIncident returns an incident with its athletes, follow-ups and attachments.
+func (r *Repository) GetIncident(ctx context.Context, id uuid.UUID) (values.Incident, *errLib.CommonError) {
+	row, err := r.Queries.GetIncidentById(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return values.Incident{}, errLib.New("Incident not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to get incident %s: %v", id, err)
+		return values.Incident{}, errLib.New("Failed to get incident", http.StatusInternalServerError)
+	}
+	incident := incidentFromRow(row)
+
+	incidents := []values.Incident{incident}
+	if err := r.attachAthletes(ctx, incidents); err != nil {
+		return values.Incident{}, err
+	}
+	incident = incidents[0]
+
+	followUps, dbErr := r.Queries.ListFollowUps(ctx, id)
+	if dbErr != nil {
+		log.Printf("Failed to list follow-ups of incident %s: %v", id, dbErr)
+		return values.Incident{}, errLib.New("Failed to get incident", http.StatusInternalServerError)
+	}
+	for _, f := range followUps {
+		incident.FollowUps = append(incident.FollowUps, values.FollowUp{
+			ID:         f.ID,
+			Status:     values.IncidentStatus(f.Status),
+			Note:       f.Note,
+			CreatedBy:  optionalUUID(f.CreatedBy),
+			AuthorName: f.AuthorName,
+			CreatedAt:  f.CreatedAt,
+		})
+	}
+
+	attachments, dbErr := r.Queries.ListAttachments(ctx, id)
+	if dbErr != nil {
+		log.Printf("Failed to list attachments of incident %s: %v", id, dbErr)
+		return values.Incident{}, errLib.New("Failed to get incident", http.StatusInternalServerError)
+	}
+	for _, a := range attachments {
+		incident.Attachments = append(incident.Attachments, attachmentFromRow(a))
+	}
+
+	return incident, nil
+}
+
+// ListIncidents returns incidents, most recent first, with the athletes involved.
+func (r *Repository) ListIncidents(ctx context.Context, filter values.IncidentFilter) ([]values.Incident, *errLib.CommonError) {
+	params := db.ListIncidentsParams{
+		AthleteID: nullUUID(filter.AthleteID),
+		ViewerID:  nullUUID(filter.ViewerID),
+		From:      nullTime(filter.From),
+		To:        nullTime(filter.To),
+		Limit:     filter.Limit,
+		Offset:    filter.Offset,
+	}
+	if filter.Status != nil {
+		params.Status = sql.NullString{String: string(*filter.Status), Valid: true}
+	}
+	if filter.Type != nil {
+		params.IncidentType = sql.NullString{String: string(*filter.Type), Valid: true}
+	}
+
+	rows, err := r.Queries.ListIncidents(ctx, params)
+	if err != nil {
+		log.Printf("Failed to list incidents: %v", err)
+		return nil, errLib.New("Failed to list incidents", http.StatusInternalServerError)
+	}
+
+	incidents := make([]values.Incident, len(rows))
+	for i, row := range rows {
+		incidents[i] = incidentFromRow(db.GetIncidentByIdRow(row))
+	}
+	if err := r.attachAthletes(ctx, incidents); err != nil {
+		return nil, err
+	}
+	return incidents, nil
+}
+
+func (r *Repository) attachAthletes(ctx context.Context, incidents []values.Incident) *errLib.CommonError {
+	if len(incidents) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(incidents))
+	index := make(map[uuid.UUID]int, len(incidents))
+	for i, incident := range incidents {
+		ids[i] = incident.ID
+		index[incident.ID] = i
+	}
+
+	rows, err := r.Queries.ListIncidentAthletes(ctx, ids)
+	if err != nil {
+		log.Printf("Failed to list incident athletes: %v", err)
+		return errLib.New("Failed to list incident athletes", http.StatusInternalServerError)
+	}
+	for _, row := range rows {
+		i := index[row.IncidentID]
+		incidents[i].Athletes = append(incidents[i].Athletes, values.IncidentAthlete{
+			AthleteID:        row.AthleteID,
+			FirstName:        row.FirstName,
+			LastName:         row.LastName,
+			Injury:           optionalString(row.Injury),
+			BodyPart:         optionalString(row.BodyPart),
+			Treatment:        optionalString(row.Treatment),
+			ParentNotifiedAt: optionalTime(row.ParentNotifiedAt),
+		})
+	}
+	return nil
+}
+
+func (r *Repository) ListContacts(ctx context.Context, incidentID uuid.UUID) ([]values.IncidentContact, *errLib.CommonError) {
+	rows, err := r.Queries.ListIncidentContacts(ctx, incidentID)
+	if err != nil {
+		log.Printf("Failed to list contacts of incident %s: %v", incidentID, err)
+		return nil, errLib.New("Failed to look up who to notify", http.StatusInternalServerError)
+	}
+
+	contacts := make([]values.IncidentContact, len(rows))
+	for i, row := range rows {
+		contacts[i] = values.IncidentContact{
+			AthleteID:        row.AthleteID,
+			FirstName:        row.FirstName,
+			Email:            optionalString(row.Email),
+			ParentFirstName:  optionalString(row.ParentFirstName),
+			ParentEmail:      optionalString(row.ParentEmail),
+			Injury:           optionalString(row.Injury),
+			Treatment:        optionalString(row.Treatment),
+			ParentNotifiedAt: optionalTime(row.ParentNotifiedAt),
+		}
+	}
+	return contacts, nil
+}
+
+func (r *Repository) MarkParentNotified(ctx context.Context, incidentID, athleteID uuid.UUID) *errLib.CommonError {
+	if err := r.Queries.MarkParentNotified(ctx, db.MarkParentNotifiedParams{IncidentID: incidentID, AthleteID: athleteID}); err != nil {
+		log.Printf("Failed to mark parent of athlete %s notified about incident %s: %v", athleteID, incidentID, err)
+		return errLib.New("Failed to record parent notification", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// ----- Attachments -----
+
+func (r *Repository) CreateAttachment(ctx context.Context, attachment values.Attachment) (values.Attachment, *errLib.CommonError) {
+	created, err := r.Queries.CreateAttachment(ctx, db.CreateAttachmentParams{
+		ID:            attachment.ID,
+		IncidentID:    attachment.IncidentID,
+		ObjectPath:    attachment.ObjectPath,
+		FileName:      attachment.FileName,
+		ContentType:   attachment.ContentType,
+		FileSizeBytes: attachment.FileSizeBytes,
+		UploadedBy:    nullUUID(attachment.UploadedBy),
+	})
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Constraint == "incident_attachments_incident_id_fkey" {
+			return values.Attachment{}, errLib.New("Incident not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to save attachment of incident %s: %v", attachment.IncidentID, err)
+		return values.Attachment{}, errLib.New("Failed to save attachment", http.StatusInternalServerError)
+	}
+	return attachmentFromRow(created), nil
+}
+
+func (r *Repository) GetAttachment(ctx context.Context, id uuid.UUID) (values.Attachment, *errLib.CommonError) {
+	row, err := r.Queries.GetAttachmentById(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return values.Attachment{}, errLib.New("Attachment not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to get attachment %s: %v", id, err)
+		return values.Attachment{}, errLib.New("Failed to get attachment", http.StatusInternalServerError)
+	}
+	return attachmentFromRow(row), nil
+}
+
+func (r *Repository) DeleteAttachment(ctx context.Context, id uuid.UUID) *errLib.CommonError {
+	affected, err := r.Queries.DeleteAttachment(ctx, id)
+	if err != nil {
+		log.Printf("Failed to delete attachment %s: %v", id, err)
+		return errLib.New("Failed to delete attachment", http.StatusInternalServerError)
+	}
+	if affected == 0 {
+		return errLib.New("Attachment not found", http.StatusNotFound)
+	}
+	return nil
+}
+
+// ----- Medical info -----
+
+func (r *Repository) GetMedicalInfo(ctx context.Context, userID uuid.UUID) (values.MedicalInfo, *errLib.CommonError) {
+	row, err := r.Queries.GetMedicalInfo(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return values.MedicalInfo{}, errLib.New("User not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to get medical info of user %s: %v", userID, err)
+		return values.MedicalInfo{}, errLib.New("Failed to get medical info", http.StatusInternalServerError)
+	}
+	return values.MedicalInfo{
+		UserID:                       row.ID,
+		FirstName:                    row.FirstName,
+		LastName:                     row.LastName,
+		EmergencyContactName:         optionalString(row.EmergencyContactName),
+		EmergencyContactPhone:        optionalString(row.EmergencyContactPhone),
+		EmergencyContactRelationship: optionalString(row.EmergencyContactRelationship),
+		Allergies:                    optionalString(row.Allergies),
+		MedicalConditions:            optionalString(row.MedicalConditions),
+		Medications:                  optionalString(row.Medications),
+		PhysicianName:                optionalString(row.PhysicianName),
+		PhysicianPhone:               optionalString(row.PhysicianPhone),
+		Notes:                        optionalString(row.Notes),
+		UpdatedAt:                    optionalTime(row.UpdatedAt),
+	}, nil
+}
+
+func (r *Repository) UpsertMedicalInfo(ctx context.Context, userID uuid.UUID, details values.MedicalDetails, updatedBy uuid.UUID) *errLib.CommonError {
+	err := r.Queries.UpsertMedicalInfo(ctx, db.UpsertMedicalInfoParams{
+		UserID:            userID,
+		Allergies:         nullString(details.Allergies),
+		MedicalConditions: nullString(details.MedicalConditions),
+		Medications:       nullString(details.Medications),
+		PhysicianName:     nullString(details.PhysicianName),
+		PhysicianPhone:    nullString(details.PhysicianPhone),
+		Notes:             nullString(details.Notes),
+		UpdatedBy:         uuid.NullUUID{UUID: updatedBy, Valid: updatedBy != uuid.Nil},
+	})
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Constraint == "medical_info_user_id_fkey" {
+			return errLib.New("User not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to save medical info of user %s: %v", userID, err)
+		return errLib.New("Failed to save medical info", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// LogAccess records a read of a user's medical details.
+func (r *Repository) LogAccess(ctx context.Context, entry values.AccessLogEntry) *errLib.CommonError {
+	err := r.Queries.InsertAccessLog(ctx, db.InsertAccessLogParams{
+		ViewerID:   nullUUID(entry.ViewerID),
+		ViewerRole: sql.NullString{String: entry.ViewerRole, Valid: entry.ViewerRole != ""},
+		IpAddress:  sql.NullString{String: entry.IPAddress, Valid: entry.IPAddress != ""},
+		SubjectID:  entry.SubjectID,
+		Resource:   string(entry.Resource),
+		ResourceID: nullUUID(entry.ResourceID),
+	})
+	if err != nil {
+		log.Printf("Failed to log %s access to user %s: %v", entry.Resource, entry.SubjectID, err)
+		return errLib.New("Failed to record access to medical information", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (r *Repository) ListAccessLog(ctx context.Context, subjectID uuid.UUID, limit, offset int32) ([]values.AccessLogEntry, *errLib.CommonError) {
+	rows, err := r.Queries.ListAccessLog(ctx, db.ListAccessLogParams{SubjectID: subjectID, Limit: limit, Offset: offset})
+	if err != nil {
+		log.Printf("Failed to list medical access log of user %s: %v", subjectID, err)
+		return nil, errLib.New("Failed to list medical access log", http.StatusInternalServerError)
+	}
+
+	entries := make([]values.AccessLogEntry, len(rows))
+	for i, row := range rows {
+		entries[i] = values.AccessLogEntry{
+			ID:         row.ID,
+			ViewerID:   optionalUUID(row.ViewerID),
+			ViewerName: row.ViewerName,
+			ViewerRole: row.ViewerRole.String,
+			IPAddress:  row.IpAddress.String,
+			SubjectID:  row.SubjectID,
+			Resource:   values.Resource(row.Resource),
+			ResourceID: optionalUUID(row.ResourceID),
+			AccessedAt: row.AccessedAt,
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,184 @@
+-- name: CoachIsAssigned :one
+-- A coach is assigned to athletes on a team they coach, and to athletes enrolled in
+-- a program whose current or upcoming events they are staffed on.
+SELECT EXISTS (SELECT 1
+               FROM athletic.athletes a
+                        JOIN athletic.teams t ON t.id = a.team_id
+               WHERE a.id = sqlc.arg('athlete_id')
+                 AND t.coach_id = sqlc.arg('coach_id'))
+           OR EXISTS (SELECT 1
+                      FROM program.customer_enrollment ce
+                               JOIN events.events e ON e.program_id = ce.program_id
+                               JOIN events.staff es ON es.event_id = e.id
+                      WHERE ce.customer_id = sqlc.arg('athlete_id')
+                        AND ce.is_cancelled = false
+                        AND es.staff_id = sqlc.arg('coach_id')
+                        AND e.end_at >= CURRENT_TIMESTAMP);
+
+-- name: CreateIncident :one
+-- Without a location the incident takes the location of the event, game or
+-- practice it happened at.
+INSERT INTO safety.incidents (incident_type, severity, occurred_at, event_id, game_id, practice_id, location_id,
+                              summary, description, action_taken, reported_by)
+VALUES (sqlc.arg('incident_type'), sqlc.arg('severity'), sqlc.arg('occurred_at'), sqlc.narg('event_id'),
+        sqlc.narg('game_id'), sqlc.narg('practice_id'),
+        COALESCE(sqlc.narg('location_id'),
+                 (SELECT e.location_id FROM events.events e WHERE e.id = sqlc.narg('event_id')),
+                 (SELECT g.location_id FROM game.games g WHERE g.id = sqlc.narg('game_id')),
+                 (SELECT p.location_id FROM practice.practices p WHERE p.id = sqlc.narg('practice_id'))),
+        sqlc.arg('summary'), sqlc.narg('description'), sqlc.narg('action_taken'), sqlc.narg('reported_by'))
+RETURNING *;
+
+-- name: UpdateIncident :one
+UPDATE safety.incidents
+SET incident_type = sqlc.arg('incident_type'),
+    severity      = sqlc.arg('severity'),
+    occurred_at   = sqlc.arg('occurred_at'),
+    event_id      = sqlc.narg('event_id'),
+    game_id       = sqlc.narg('game_id'),
+    practice_id   = sqlc.narg('practice_id'),
+    location_id   = COALESCE(sqlc.narg('location_id'),
+                             (SELECT e.location_id FROM events.events e WHERE e.id = sqlc.narg('event_id')),
+                             (SELECT g.location_id FROM game.games g WHERE g.id = sqlc.narg('game_id')),
+                             (SELECT p.location_id FROM practice.practices p WHERE p.id = sqlc.narg('practice_id'))),
+    summary       = sqlc.arg('summary'),
+    description   = sqlc.narg('description'),
+    action_taken  = sqlc.narg('action_taken'),
+    updated_at    = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: UpdateIncidentStatus :one
+UPDATE safety.incidents
+SET status      = $2,
+    resolved_at = $3,
+    updated_at  = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
+
+-- name: GetIncidentById :one
+SELECT i.*,
+       l.name                                                 AS location_name,
+       COALESCE(r.first_name || ' ' || r.last_name, '')::text AS reporter_name
+FROM safety.incidents i
+         LEFT JOIN location.locations l ON l.id = i.location_id
+         LEFT JOIN users.users r ON r.id = i.reported_by
+WHERE i.id = $1;
+
+-- name: ListIncidents :many
+-- With a viewer, only incidents they reported or that involve an athlete they are
+-- assigned to are listed.
+SELECT i.*,
+       l.name                                                 AS location_name,
+       COALESCE(r.first_name || ' ' || r.last_name, '')::text AS reporter_name
+FROM safety.incidents i
+         LEFT JOIN location.locations l ON l.id = i.location_id
+         LEFT JOIN users.users r ON r.id = i.reported_by
+WHERE (sqlc.narg('status')::varchar IS NULL OR i.status = sqlc.narg('status')::varchar)
+  AND (sqlc.narg('incident_type')::varchar IS NULL OR i.incident_type = sqlc.narg('incident_type')::varchar)
+  AND (sqlc.narg('from')::timestamptz IS NULL OR i.occurred_at >= sqlc.narg('from')::timestamptz)
+  AND (sqlc.narg('to')::timestamptz IS NULL OR i.occurred_at < sqlc.narg('to')::timestamptz)
+  AND (sqlc.narg('athlete_id')::uuid IS NULL OR EXISTS (SELECT 1
+                                                         FROM safety.incident_athletes ia
+                                                         WHERE ia.incident_id = i.id
+                                                           AND ia.athlete_id = sqlc.narg('athlete_id')::uuid))
+  AND (sqlc.narg('viewer_id')::uuid IS NULL
+    OR i.reported_by = sqlc.narg('viewer_id')::uuid
+    OR EXISTS (SELECT 1
+               FROM safety.incident_athletes ia
+                        JOIN athletic.athletes a ON a.id = ia.athlete_id
+                        JOIN athletic.teams t ON t.id = a.team_id
+               WHERE ia.incident_id = i.id
+                 AND t.coach_id = sqlc.narg('viewer_id')::uuid)
+    OR EXISTS (SELECT 1
+               FROM safety.incident_athletes ia
+                        JOIN program.customer_enrollment ce ON ce.customer_id = ia.athlete_id
+                        JOIN events.events e ON e.program_id = ce.program_id
+                        JOIN events.staff es ON es.event_id = e.id
+               WHERE ia.incident_id = i.id
+                 AND ce.is_cancelled = false
+                 AND es.staff_id = sqlc.narg('viewer_id')::uuid
+                 AND e.end_at >= CURRENT_TIMESTAMP))
+ORDER BY i.occurred_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListIncidentAthletes :many
+SELECT ia.*,
+       u.first_name,
+       u.last_name
+FROM safety.incident_athletes ia
+         JOIN users.users u ON u.id = ia.athlete_id
+WHERE ia.incident_id = ANY (sqlc.arg('incident_ids')::uuid[])
+ORDER BY ia.incident_id, u.first_name, u.last_name;
+
+-- name: UpsertIncidentAthlete :exec
+-- Keeps parent_notified_at when an athlete already on the incident is edited.
+INSERT INTO safety.incident_athletes (incident_id, athlete_id, injury, body_part, treatment)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (incident_id, athlete_id) DO UPDATE
+    SET injury    = EXCLUDED.injury,
+        body_part = EXCLUDED.body_part,
+        treatment = EXCLUDED.treatment;
+
+-- name: DeleteIncidentAthletesExcept :exec
+DELETE
+FROM safety.incident_athletes
+WHERE incident_id = sqlc.arg('incident_id')
+  AND NOT (athlete_id = ANY (sqlc.arg('keep_ids')::uuid[]));
+
+-- name: ListIncidentContacts :many
+-- Who to tell about each athlete on an incident: the parent, or the athlete when
+-- they have no parent.
+SELECT ia.athlete_id,
+       ia.injury,
+       ia.treatment,
+       ia.parent_notified_at,
+       u.first_name,
+       u.email,
+       p.first_name AS parent_first_name,
+       p.email      AS parent_email
+FROM safety.incident_athletes ia
+         JOIN users.users u ON u.id = ia.athlete_id
+         LEFT JOIN users.users p ON p.id = u.parent_id
+WHERE ia.incident_id = $1
+ORDER BY u.first_name, u.last_name;
+
+-- name: MarkParentNotified :exec
+UPDATE safety.incident_athletes
+SET parent_notified_at = CURRENT_TIMESTAMP
+WHERE incident_id = $1
+  AND athlete_id = $2;
+
+-- name: CreateFollowUp :exec
+INSERT INTO safety.incident_follow_ups (incident_id, status, note, created_by)
+VALUES ($1, $2, $3, $4);
+
+-- name: ListFollowUps :many
+SELECT f.*,
+       COALESCE(u.first_name || ' ' || u.last_name, '')::text AS author_name
+FROM safety.incident_follow_ups f
+         LEFT JOIN users.users u ON u.id = f.created_by
+WHERE f.incident_id = $1
+ORDER BY f.created_at;
+
+-- name: CreateAttachment :one
+INSERT INTO safety.incident_attachments (id, incident_id, object_path, file_name, content_type, file_size_bytes,
+                                         uploaded_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: ListAttachments :many
+SELECT *
+FROM safety.incident_attachments
+WHERE incident_id = $1
+ORDER BY created_at;
+
+-- name: GetAttachmentById :one
+SELECT *
+FROM safety.incident_attachments
+WHERE id = $1;
+
+-- name: DeleteAttachment :execrows
+DELETE
+FROM safety.incident_attachments
+WHERE id = $1;
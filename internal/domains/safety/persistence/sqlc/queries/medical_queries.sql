@@ -0,0 +1,44 @@
+-- name: GetMedicalInfo :one
+SELECT u.id,
+       u.first_name,
+       u.last_name,
+       u.emergency_contact_name,
+       u.emergency_contact_phone,
+       u.emergency_contact_relationship,
+       m.allergies,
+       m.medical_conditions,
+       m.medications,
+       m.physician_name,
+       m.physician_phone,
+       m.notes,
+       m.updated_at
+FROM users.users u
+         LEFT JOIN users.medical_info m ON m.user_id = u.id
+WHERE u.id = $1;
+
+-- name: UpsertMedicalInfo :exec
+INSERT INTO users.medical_info (user_id, allergies, medical_conditions, medications, physician_name,
+                                physician_phone, notes, updated_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (user_id) DO UPDATE
+    SET allergies          = EXCLUDED.allergies,
+        medical_conditions = EXCLUDED.medical_conditions,
+        medications        = EXCLUDED.medications,
+        physician_name     = EXCLUDED.physician_name,
+        physician_phone    = EXCLUDED.physician_phone,
+        notes              = EXCLUDED.notes,
+        updated_by         = EXCLUDED.updated_by,
+        updated_at         = CURRENT_TIMESTAMP;
+
+-- name: InsertAccessLog :exec
+INSERT INTO safety.medical_access_log (viewer_id, viewer_role, ip_address, subject_id, resource, resource_id)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: ListAccessLog :many
+SELECT l.*,
+       COALESCE(u.first_name || ' ' || u.last_name, '')::text AS viewer_name
+FROM safety.medical_access_log l
+         LEFT JOIN users.users u ON u.id = l.viewer_id
+WHERE l.subject_id = $1
+ORDER BY l.accessed_at DESC, l.id DESC
+LIMIT $2 OFFSET $3;
@@ -0,0 +1,109 @@
+package safety
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	auditValues "api/internal/domains/audit/audit_logs/values"
+	values "api/internal/domains/safety/values"
+	errLib "api/internal/libs/errors"
+	txUtils "api/utils/db"
+
+	"github.com/google/uuid"
+)
+
+// GetMedicalInfo returns a user's emergency contact and medical details to an
+// admin, or to a coach assigned to the athlete, and logs the read.
+func (s *Service) GetMedicalInfo(ctx context.Context, userID uuid.UUID) (values.MedicalInfo, *errLib.CommonError) {
+	v, err := currentViewer(ctx)
+	if err != nil {
+		return values.MedicalInfo{}, err
+	}
+	if !v.Admin {
+		assigned, err := s.records.CoachIsAssigned(ctx, v.ID, userID)
+		if err != nil {
+			return values.MedicalInfo{}, err
+		}
+		if !assigned {
+			return values.MedicalInfo{}, errLib.New("You can only see medical information of athletes you coach", http.StatusForbidden)
+		}
+	}
+	return s.readMedicalInfo(ctx, v, userID)
+}
+
+// GetFamilyMedicalInfo is GetMedicalInfo for the user themselves or their parent.
+// Callers must have checked the family's access; the read is still logged.
+func (s *Service) GetFamilyMedicalInfo(ctx context.Context, userID uuid.UUID) (values.MedicalInfo, *errLib.CommonError) {
+	v, err := currentViewer(ctx)
+	if err != nil {
+		return values.MedicalInfo{}, err
+	}
+	return s.readMedicalInfo(ctx, v, userID)
+}
+
+func (s *Service) readMedicalInfo(ctx context.Context, v viewer, userID uuid.UUID) (values.MedicalInfo, *errLib.CommonError) {
+	info, err := s.records.GetMedicalInfo(ctx, userID)
+	if err != nil {
+		return values.MedicalInfo{}, err
+	}
+	if err = s.logAccess(ctx, v, userID, values.ResourceMedicalInfo, nil); err != nil {
+		return values.MedicalInfo{}, err
+	}
+	return info, nil
+}
+
+// UpdateMedicalInfo replaces a user's medical details on behalf of an admin. The
+// audit log records that the details changed, not what they are.
+func (s *Service) UpdateMedicalInfo(ctx context.Context, userID uuid.UUID, details values.MedicalDetails) (values.MedicalInfo, *errLib.CommonError) {
+	v, err := currentViewer(ctx)
+	if err != nil {
+		return values.MedicalInfo{}, err
+	}
+	if !v.Admin {
+		return values.MedicalInfo{}, errLib.New("Only admins can edit medical information", http.StatusForbidden)
+	}
+
+	err = txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		if txErr := s.repo.WithTx(tx).UpsertMedicalInfo(ctx, userID, details, v.ID); txErr != nil {
+			return txErr
+		}
+
+		return s.staffActivityLogsService.RecordChange(ctx, tx, v.ID, auditValues.Entry{
+			EntityType:  entityMedicalInfo,
+			EntityID:    userID.String(),
+			Action:      auditValues.ActionUpdate,
+			Description: fmt.Sprintf("Updated medical information of user %s", userID),
+		})
+	})
+	if err != nil {
+		return values.MedicalInfo{}, err
+	}
+	return s.readMedicalInfo(ctx, v, userID)
+}
+
+// UpdateFamilyMedicalInfo is UpdateMedicalInfo for the user themselves or their
+// parent. Callers must have checked the family's access.
+func (s *Service) UpdateFamilyMedicalInfo(ctx context.Context, userID uuid.UUID, details values.MedicalDetails) (values.MedicalInfo, *errLib.CommonError) {
+	v, err := currentViewer(ctx)
+	if err != nil {
+		return values.MedicalInfo{}, err
+	}
+	if err = s.repo.UpsertMedicalInfo(ctx, userID, details, v.ID); err != nil {
+		return values.MedicalInfo{}, err
+	}
+	return s.readMedicalInfo(ctx, v, userID)
+}
+
+// ListAccessLog returns who read a user's medical details, most recent first.
+func (s *Service) ListAccessLog(ctx context.Context, userID uuid.UUID, limit, offset int32) ([]values.AccessLogEntry, *errLib.CommonError) {
+	v, err := currentViewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !v.Admin {
+		return nil, errLib.New("Only admins can see who accessed medical information", http.StatusForbidden)
+	}
+	return s.repo.ListAccessLog(ctx, userID, limit, offset)
+}
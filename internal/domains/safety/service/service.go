@@ -0,0 +1,577 @@
+package safety
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"api/internal/di"
+	auditValues "api/internal/domains/audit/audit_logs/values"
+	staffActivityLogs "api/internal/domains/audit/staff_activity_logs/service"
+	dto "api/internal/domains/safety/dto"
+	repo "api/internal/domains/safety/persistence"
+	values "api/internal/domains/safety/values"
+	errLib "api/internal/libs/errors"
+	"api/internal/services/gcp"
+	contextUtils "api/utils/context"
+	txUtils "api/utils/db"
+	"api/utils/email"
+	"api/utils/timezone"
+
+	"github.com/google/uuid"
+)
+
+const (
+	entityIncident    = "incident"
+	entityAttachment  = "incident_attachment"
+	entityMedicalInfo = "medical_info"
+	actionNotify      = "notify"
+)
+
+// Service manages incident reports and users' medical details.
+//
+// Admins can see every incident and every user's medical details. Coaches can see
+// incidents they reported or that involve athletes they are assigned to (on a team
+// they coach, or enrolled in a program whose current or upcoming events they are
+// staffed on), and only those
+// athletes' medical details. Every read of injury or medical details is recorded
+// in the medical access log; a read that cannot be logged fails.
+type Service struct {
+	repo                     *repo.Repository
+	records                  safetyRecords
+	staffActivityLogsService *staffActivityLogs.Service
+	db                       *sql.DB
+}
+
+// safetyRecords is the part of the repository that decides and logs who reads
+// medical details.
+type safetyRecords interface {
+	CoachIsAssigned(ctx context.Context, coachID, athleteID uuid.UUID) (bool, *errLib.CommonError)
+	GetMedicalInfo(ctx context.Context, userID uuid.UUID) (values.MedicalInfo, *errLib.CommonError)
+	LogAccess(ctx context.Context, entry values.AccessLogEntry) *errLib.CommonError
+}
+
+func NewService(container *di.Container) *Service {
+	r := repo.NewRepository(container)
+	return &Service{
+		repo:                     r,
+		records:                  r,
+		staffActivityLogsService: staffActivityLogs.NewService(container),
+		db:                       container.DB,
+	}
+}
+
+// viewer is the user in the request context.
+type viewer struct {
+	ID    uuid.UUID
+	Role  contextUtils.CtxRole
+	Admin bool
+}
+
+func currentViewer(ctx context.Context) (viewer, *errLib.CommonError) {
+	userID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return viewer{}, err
+	}
+	role, err := contextUtils.GetUserRole(ctx)
+	if err != nil {
+		return viewer{}, err
+	}
+	admin := role == contextUtils.RoleAdmin || role == contextUtils.RoleSuperAdmin || role == contextUtils.RoleIT
+	return viewer{ID: userID, Role: role, Admin: admin}, nil
+}
+
+func isReporter(incident values.Incident, userID uuid.UUID) bool {
+	return incident.ReportedBy != nil && *incident.ReportedBy == userID
+}
+
+// authorizeIncident checks the viewer may see an incident: admins, the staff member
+// who reported it, and coaches assigned to any athlete involved.
+func (s *Service) authorizeIncident(ctx context.Context, v viewer, incident values.Incident) *errLib.CommonError {
+	if v.Admin || isReporter(incident, v.ID) {
+		return nil
+	}
+	for _, athlete := range incident.Athletes {
+		assigned, err := s.records.CoachIsAssigned(ctx, v.ID, athlete.AthleteID)
+		if err != nil {
+			return err
+		}
+		if assigned {
+			return nil
+		}
+	}
+	return errLib.New("You can only see incidents you reported or that involve athletes you coach", http.StatusForbidden)
+}
+
+// logIncidentRead records a read of an incident's details against each athlete involved.
+func (s *Service) logIncidentRead(ctx context.Context, v viewer, incident values.Incident, resource values.Resource, resourceID uuid.UUID) *errLib.CommonError {
+	for _, athlete := range incident.Athletes {
+		if err := s.logAccess(ctx, v, athlete.AthleteID, resource, &resourceID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) logAccess(ctx context.Context, v viewer, subjectID uuid.UUID, resource values.Resource, resourceID *uuid.UUID) *errLib.CommonError {
+	return s.records.LogAccess(ctx, values.AccessLogEntry{
+		ViewerID:   &v.ID,
+		ViewerRole: string(v.Role),
+		IPAddress:  contextUtils.GetIPAddress(ctx),
+		SubjectID:  subjectID,
+		Resource:   resource,
+		ResourceID: resourceID,
+	})
+}
+
+// ----- Incidents -----
+
+// ListIncidents returns incidents the caller can see, most recent first. Listing
+// does not include injury details and is not logged.
+func (s *Service) ListIncidents(ctx context.Context, filter values.IncidentFilter) ([]values.Incident, *errLib.CommonError) {
+	v, err := currentViewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !v.Admin {
+		filter.ViewerID = &v.ID
+	}
+	return s.repo.ListIncidents(ctx, filter)
+}
+
+// GetIncident returns an incident with its injury details, follow-ups and
+// attachments, and logs the read against each athlete involved.
+func (s *Service) GetIncident(ctx context.Context, id uuid.UUID) (values.Incident, *errLib.CommonError) {
+	v, err := currentViewer(ctx)
+	if err != nil {
+		return values.Incident{}, err
+	}
+	incident, err := s.repo.GetIncident(ctx, id)
+	if err != nil {
+		return values.Incident{}, err
+	}
+	if err = s.authorizeIncident(ctx, v, incident); err != nil {
+		return values.Incident{}, err
+	}
+	if err = s.logIncidentRead(ctx, v, incident, values.ResourceIncident, incident.ID); err != nil {
+		return values.Incident{}, err
+	}
+	return incident, nil
+}
+
+// CreateIncident records an incident reported by the caller, and emails the
+// athletes' families when asked to.
+func (s *Service) CreateIncident(ctx context.Context, details values.IncidentDetails) (values.Incident, *errLib.CommonError) {
+	v, err := currentViewer(ctx)
+	if err != nil {
+		return values.Incident{}, err
+	}
+
+	var created values.Incident
+	err = txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		txRepo := s.repo.WithTx(tx)
+
+		id, txErr := txRepo.CreateIncident(ctx, details, v.ID)
+		if txErr != nil {
+			return txErr
+		}
+		if txErr = txRepo.ReplaceAthletes(ctx, id, details.Athletes); txErr != nil {
+			return txErr
+		}
+		if created, txErr = txRepo.GetIncident(ctx, id); txErr != nil {
+			return txErr
+		}
+
+		// The audit log keeps the summary only, so injury details stay behind the access log.
+		return s.staffActivityLogsService.RecordChange(ctx, tx, v.ID, auditValues.Entry{
+			EntityType:  entityIncident,
+			EntityID:    id.String(),
+			Action:      auditValues.ActionCreate,
+			After:       dto.NewIncidentSummaryResponse(created),
+			Description: fmt.Sprintf("Reported %s %s incident %q involving %d athletes", created.Severity, created.Type, created.Summary, len(created.Athletes)),
+		})
+	})
+	if err != nil {
+		return values.Incident{}, err
+	}
+
+	if details.NotifyParents {
+		return s.notifyAndReload(ctx, v, created)
+	}
+	return created, nil
+}
+
+// UpdateIncident edits an incident's details and replaces the athletes involved.
+// Only the reporter or an admin can edit an incident, and closed incidents only
+// by an admin.
+func (s *Service) UpdateIncident(ctx context.Context, id uuid.UUID, details values.IncidentDetails) (values.Incident, *errLib.CommonError) {
+	v, err := currentViewer(ctx)
+	if err != nil {
+		return values.Incident{}, err
+	}
+	existing, err := s.repo.GetIncident(ctx, id)
+	if err != nil {
+		return values.Incident{}, err
+	}
+	if !v.Admin && !isReporter(existing, v.ID) {
+		return values.Incident{}, errLib.New("Only the staff member who reported this incident can edit it", http.StatusForbidden)
+	}
+	if !v.Admin && existing.Status == values.StatusClosed {
+		return values.Incident{}, errLib.New("Closed incidents can only be edited by an admin", http.StatusConflict)
+	}
+
+	var updated values.Incident
+	err = txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		txRepo := s.repo.WithTx(tx)
+
+		if txErr := txRepo.UpdateIncident(ctx, id, details); txErr != nil {
+			return txErr
+		}
+		if txErr := txRepo.ReplaceAthletes(ctx, id, details.Athletes); txErr != nil {
+			return txErr
+		}
+
+		var txErr *errLib.CommonError
+		if updated, txErr = txRepo.GetIncident(ctx, id); txErr != nil {
+			return txErr
+		}
+
+		return s.staffActivityLogsService.RecordChange(ctx, tx, v.ID, auditValues.Entry{
+			EntityType:  entityIncident,
+			EntityID:    id.String(),
+			Action:      auditValues.ActionUpdate,
+			Before:      dto.NewIncidentSummaryResponse(existing),
+			After:       dto.NewIncidentSummaryResponse(updated),
+			Description: fmt.Sprintf("Updated incident %q", updated.Summary),
+		})
+	})
+	if err != nil {
+		return values.Incident{}, err
+	}
+
+	if details.NotifyParents {
+		return s.notifyAndReload(ctx, v, updated)
+	}
+	return updated, nil
+}
+
+// AddFollowUp adds a note to an incident's timeline and moves it to status, if set.
+// Anyone who can see the incident can follow up on it; once closed, only an admin
+// can add to it or reopen it.
+func (s *Service) AddFollowUp(ctx context.Context, id uuid.UUID, note string, status values.IncidentStatus) (values.Incident, *errLib.CommonError) {
+	v, err := currentViewer(ctx)
+	if err != nil {
+		return values.Incident{}, err
+	}
+	existing, err := s.repo.GetIncident(ctx, id)
+	if err != nil {
+		return values.Incident{}, err
+	}
+	if err = s.authorizeIncident(ctx, v, existing); err != nil {
+		return values.Incident{}, err
+	}
+	next, err := validateStatusChange(existing.Status, status, v.Admin)
+	if err != nil {
+		return values.Incident{}, err
+	}
+
+	var updated values.Incident
+	err = txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		txRepo := s.repo.WithTx(tx)
+
+		if txErr := txRepo.AddFollowUp(ctx, id, next, note, v.ID); txErr != nil {
+			return txErr
+		}
+		if next != existing.Status {
+			if txErr := txRepo.UpdateStatus(ctx, id, next, resolvedAt(next, existing.ResolvedAt, time.Now())); txErr != nil {
+				return txErr
+			}
+		}
+
+		var txErr *errLib.CommonError
+		if updated, txErr = txRepo.GetIncident(ctx, id); txErr != nil {
+			return txErr
+		}
+
+		description := fmt.Sprintf("Added a follow-up to incident %q", existing.Summary)
+		if next != existing.Status {
+			description = fmt.Sprintf("Moved incident %q from %s to %s", existing.Summary, existing.Status, next)
+		}
+		return s.staffActivityLogsService.RecordChange(ctx, tx, v.ID, auditValues.Entry{
+			EntityType:  entityIncident,
+			EntityID:    id.String(),
+			Action:      auditValues.ActionUpdate,
+			Before:      dto.NewIncidentSummaryResponse(existing),
+			After:       dto.NewIncidentSummaryResponse(updated),
+			Description: description,
+		})
+	})
+	if err != nil {
+		return values.Incident{}, err
+	}
+	return updated, nil
+}
+
+// validateStatusChange returns the status an incident moves to when a follow-up
+// asks for next, which is empty to keep the current status.
+func validateStatusChange(current, next values.IncidentStatus, admin bool) (values.IncidentStatus, *errLib.CommonError) {
+	if next == "" {
+		next = current
+	}
+	if !next.Valid() {
+		return "", errLib.New(fmt.Sprintf("Unknown incident status %q", next), http.StatusBadRequest)
+	}
+	if current == values.StatusClosed && !admin {
+		return "", errLib.New("Closed incidents can only be followed up or reopened by an admin", http.StatusConflict)
+	}
+	return next, nil
+}
+
+// resolvedAt is when an incident in status was resolved: kept from before if it was
+// already resolved, now if it is being resolved, and cleared when it is reopened.
+func resolvedAt(status values.IncidentStatus, current *time.Time, now time.Time) *time.Time {
+	if status != values.StatusResolved && status != values.StatusClosed {
+		return nil
+	}
+	if current != nil {
+		return current
+	}
+	return &now
+}
+
+// NotifyParents emails the families of athletes on an incident who have not been
+// told about it yet, and returns how many were emailed.
+func (s *Service) NotifyParents(ctx context.Context, id uuid.UUID) (values.Incident, int, *errLib.CommonError) {
+	v, err := currentViewer(ctx)
+	if err != nil {
+		return values.Incident{}, 0, err
+	}
+	incident, err := s.repo.GetIncident(ctx, id)
+	if err != nil {
+		return values.Incident{}, 0, err
+	}
+	if err = s.authorizeIncident(ctx, v, incident); err != nil {
+		return values.Incident{}, 0, err
+	}
+
+	notified, err := s.notifyParents(ctx, v, incident)
+	if err != nil {
+		return values.Incident{}, 0, err
+	}
+	if incident, err = s.repo.GetIncident(ctx, id); err != nil {
+		return values.Incident{}, 0, err
+	}
+	return incident, notified, nil
+}
+
+func (s *Service) notifyAndReload(ctx context.Context, v viewer, incident values.Incident) (values.Incident, *errLib.CommonError) {
+	if _, err := s.notifyParents(ctx, v, incident); err != nil {
+		log.Printf("[SAFETY] Failed to notify families about incident %s: %s", incident.ID, err.Message)
+		return incident, nil
+	}
+	return s.repo.GetIncident(ctx, incident.ID)
+}
+
+// notifyParents emails the parent of each athlete not yet notified, or the athlete
+// when they have no parent. Athletes with nobody to email stay un-notified so
+// staff can see the family still has to be called.
+func (s *Service) notifyParents(ctx context.Context, v viewer, incident values.Incident) (int, *errLib.CommonError) {
+	contacts, err := s.repo.ListContacts(ctx, incident.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	occurredOn := incident.OccurredAt.In(timezone.Default()).Format("January 2, 2006")
+	location := ""
+	if incident.LocationName != nil {
+		location = *incident.LocationName
+	}
+
+	notified := 0
+	for _, contact := range contacts {
+		if contact.ParentNotifiedAt != nil {
+			continue
+		}
+
+		to, firstName := recipient(contact)
+		if to == "" {
+			log.Printf("[SAFETY] No email address to notify about athlete %s on incident %s", contact.AthleteID, incident.ID)
+			continue
+		}
+		if err := email.SendIncidentNotificationEmail(to, firstName, contact.FirstName, occurredOn, location,
+			incident.Summary, valueOf(contact.Injury), valueOf(contact.Treatment)); err != nil {
+			continue
+		}
+		if err := s.repo.MarkParentNotified(ctx, incident.ID, contact.AthleteID); err != nil {
+			return notified, err
+		}
+		notified++
+	}
+
+	if notified > 0 {
+		if err := s.staffActivityLogsService.RecordChange(ctx, nil, v.ID, auditValues.Entry{
+			EntityType:  entityIncident,
+			EntityID:    incident.ID.String(),
+			Action:      actionNotify,
+			Description: fmt.Sprintf("Notified %d families about incident %q", notified, incident.Summary),
+		}); err != nil {
+			return notified, err
+		}
+	}
+	return notified, nil
+}
+
+// recipient is who to email about an athlete: their parent, or the athlete
+// themselves when they have no parent.
+func recipient(contact values.IncidentContact) (string, string) {
+	if contact.ParentEmail != nil && *contact.ParentEmail != "" {
+		return *contact.ParentEmail, valueOf(contact.ParentFirstName)
+	}
+	return valueOf(contact.Email), contact.FirstName
+}
+
+func valueOf(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// ----- Attachments -----
+
+// UploadAttachment stores a photo or document for an incident. Files are kept
+// private and only served through GetAttachment.
+func (s *Service) UploadAttachment(ctx context.Context, incidentID uuid.UUID, data []byte, fileName, ext, contentType string) (values.Attachment, *errLib.CommonError) {
+	v, err := currentViewer(ctx)
+	if err != nil {
+		return values.Attachment{}, err
+	}
+	incident, err := s.repo.GetIncident(ctx, incidentID)
+	if err != nil {
+		return values.Attachment{}, err
+	}
+	if err = s.authorizeIncident(ctx, v, incident); err != nil {
+		return values.Attachment{}, err
+	}
+
+	attachment := values.Attachment{
+		ID:            uuid.New(),
+		IncidentID:    incidentID,
+		FileName:      fileName,
+		ContentType:   contentType,
+		FileSizeBytes: int64(len(data)),
+		UploadedBy:    &v.ID,
+	}
+	attachment.ObjectPath = fmt.Sprintf("incidents/%s/%s%s", incidentID, attachment.ID, ext)
+
+	if err = gcp.UploadFileToGCP(data, attachment.ObjectPath, contentType); err != nil {
+		return values.Attachment{}, err
+	}
+
+	var created values.Attachment
+	err = txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		var txErr *errLib.CommonError
+		if created, txErr = s.repo.WithTx(tx).CreateAttachment(ctx, attachment); txErr != nil {
+			return txErr
+		}
+
+		return s.staffActivityLogsService.RecordChange(ctx, tx, v.ID, auditValues.Entry{
+			EntityType:  entityAttachment,
+			EntityID:    created.ID.String(),
+			Action:      auditValues.ActionCreate,
+			After:       dto.NewAttachmentResponse(created),
+			Description: fmt.Sprintf("Attached %q to incident %q", fileName, incident.Summary),
+		})
+	})
+	if err != nil {
+		if deleteErr := gcp.DeleteObjectFromGCP(attachment.ObjectPath); deleteErr != nil {
+			log.Printf("Warning: Failed to delete incident attachment %s: %s", attachment.ObjectPath, deleteErr.Message)
+		}
+		return values.Attachment{}, err
+	}
+	return created, nil
+}
+
+// GetAttachment returns an attachment's file, and logs the read against each
+// athlete on the incident.
+func (s *Service) GetAttachment(ctx context.Context, incidentID, attachmentID uuid.UUID) (values.Attachment, []byte, *errLib.CommonError) {
+	v, err := currentViewer(ctx)
+	if err != nil {
+		return values.Attachment{}, nil, err
+	}
+	incident, attachment, err := s.incidentAttachment(ctx, incidentID, attachmentID)
+	if err != nil {
+		return values.Attachment{}, nil, err
+	}
+	if err = s.authorizeIncident(ctx, v, incident); err != nil {
+		return values.Attachment{}, nil, err
+	}
+
+	data, err := gcp.ReadObjectFromGCP(attachment.ObjectPath)
+	if err != nil {
+		return values.Attachment{}, nil, err
+	}
+	if err = s.logIncidentRead(ctx, v, incident, values.ResourceAttachment, attachment.ID); err != nil {
+		return values.Attachment{}, nil, err
+	}
+	return attachment, data, nil
+}
+
+// DeleteAttachment removes an attachment. Staff can remove what they uploaded;
+// admins any attachment.
+func (s *Service) DeleteAttachment(ctx context.Context, incidentID, attachmentID uuid.UUID) *errLib.CommonError {
+	v, err := currentViewer(ctx)
+	if err != nil {
+		return err
+	}
+	incident, attachment, err := s.incidentAttachment(ctx, incidentID, attachmentID)
+	if err != nil {
+		return err
+	}
+	uploader := attachment.UploadedBy != nil && *attachment.UploadedBy == v.ID
+	if !v.Admin && !uploader {
+		return errLib.New("Only the staff member who uploaded this attachment can delete it", http.StatusForbidden)
+	}
+
+	err = txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		if txErr := s.repo.WithTx(tx).DeleteAttachment(ctx, attachment.ID); txErr != nil {
+			return txErr
+		}
+
+		return s.staffActivityLogsService.RecordChange(ctx, tx, v.ID, auditValues.Entry{
+			EntityType:  entityAttachment,
+			EntityID:    attachment.ID.String(),
+			Action:      auditValues.ActionDelete,
+			Before:      dto.NewAttachmentResponse(attachment),
+			Description: fmt.Sprintf("Removed %q from incident %q", attachment.FileName, incident.Summary),
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	if deleteErr := gcp.DeleteObjectFromGCP(attachment.ObjectPath); deleteErr != nil {
+		log.Printf("Warning: Failed to delete incident attachment %s: %s", attachment.ObjectPath, deleteErr.Message)
+	}
+	return nil
+}
+
+// incidentAttachment loads an attachment and the incident it belongs to. An
+// attachment of another incident is not found.
+func (s *Service) incidentAttachment(ctx context.Context, incidentID, attachmentID uuid.UUID) (values.Incident, values.Attachment, *errLib.CommonError) {
+	attachment, err := s.repo.GetAttachment(ctx, attachmentID)
+	if err != nil {
+		return values.Incident{}, values.Attachment{}, err
+	}
+	if attachment.IncidentID != incidentID {
+		return values.Incident{}, values.Attachment{}, errLib.New("Attachment not found", http.StatusNotFound)
+	}
+	incident, err := s.repo.GetIncident(ctx, incidentID)
+	if err != nil {
+		return values.Incident{}, values.Attachment{}, err
+	}
+	return incident, attachment, nil
+}
@@ -0,0 +1,151 @@
+package safety
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	values "api/internal/domains/safety/values"
+	errLib "api/internal/libs/errors"
+	contextUtils "api/utils/context"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRecords assigns each athlete in coaches to one coach, and keeps the access
+// log in memory, failing every write when logErr is set.
+type fakeRecords struct {
+	coaches map[uuid.UUID]uuid.UUID
+	logged  []values.AccessLogEntry
+	logErr  *errLib.CommonError
+}
+
+func (f *fakeRecords) CoachIsAssigned(_ context.Context, coachID, athleteID uuid.UUID) (bool, *errLib.CommonError) {
+	coach, ok := f.coaches[athleteID]
+	return ok && coach == coachID, nil
+}
+
+func (f *fakeRecords) GetMedicalInfo(_ context.Context, userID uuid.UUID) (values.MedicalInfo, *errLib.CommonError) {
+	return values.MedicalInfo{UserID: userID}, nil
+}
+
+func (f *fakeRecords) LogAccess(_ context.Context, entry values.AccessLogEntry) *errLib.CommonError {
+	if f.logErr != nil {
+		return f.logErr
+	}
+	f.logged = append(f.logged, entry)
+	return nil
+}
+
+func asUser(userID uuid.UUID, role contextUtils.CtxRole) context.Context {
+	ctx := context.WithValue(context.Background(), contextUtils.UserIDKey, userID)
+	return context.WithValue(ctx, contextUtils.RoleKey, role)
+}
+
+func TestValidateStatusChange(t *testing.T) {
+	next, err := validateStatusChange(values.StatusOpen, "", false)
+	assert.Nil(t, err)
+	assert.Equal(t, values.StatusOpen, next, "no status keeps the current one")
+
+	next, err = validateStatusChange(values.StatusFollowUp, values.StatusResolved, false)
+	assert.Nil(t, err)
+	assert.Equal(t, values.StatusResolved, next)
+
+	_, err = validateStatusChange(values.StatusOpen, "pending", true)
+	assert.Equal(t, http.StatusBadRequest, err.HTTPCode)
+
+	_, err = validateStatusChange(values.StatusClosed, values.StatusOpen, false)
+	assert.Equal(t, http.StatusConflict, err.HTTPCode, "only admins can reopen")
+	_, err = validateStatusChange(values.StatusClosed, "", false)
+	assert.Equal(t, http.StatusConflict, err.HTTPCode, "only admins can add to a closed incident")
+
+	next, err = validateStatusChange(values.StatusClosed, values.StatusOpen, true)
+	assert.Nil(t, err)
+	assert.Equal(t, values.StatusOpen, next)
+}
+
+func TestResolvedAt(t *testing.T) {
+	now := time.Date(2026, 10, 14, 18, 0, 0, 0, time.UTC)
+	earlier := now.Add(-48 * time.Hour)
+
+	assert.Equal(t, &now, resolvedAt(values.StatusResolved, nil, now))
+	assert.Equal(t, &earlier, resolvedAt(values.StatusClosed, &earlier, now), "closing a resolved incident keeps when it was resolved")
+	assert.Nil(t, resolvedAt(values.StatusFollowUp, &earlier, now), "reopening clears it")
+	assert.Nil(t, resolvedAt(values.StatusOpen, nil, now))
+}
+
+func TestGetMedicalInfo(t *testing.T) {
+	athlete, coach := uuid.New(), uuid.New()
+	records := &fakeRecords{coaches: map[uuid.UUID]uuid.UUID{athlete: coach}}
+	service := &Service{records: records}
+
+	_, err := service.GetMedicalInfo(asUser(uuid.New(), contextUtils.RoleCoach), athlete)
+	require.NotNil(t, err, "a coach not assigned to the athlete")
+	assert.Equal(t, http.StatusForbidden, err.HTTPCode)
+	assert.Empty(t, records.logged)
+
+	info, err := service.GetMedicalInfo(asUser(coach, contextUtils.RoleCoach), athlete)
+	require.Nil(t, err)
+	assert.Equal(t, athlete, info.UserID)
+	require.Len(t, records.logged, 1, "the read is logged")
+	assert.Equal(t, coach, *records.logged[0].ViewerID)
+	assert.Equal(t, athlete, records.logged[0].SubjectID)
+	assert.Equal(t, values.ResourceMedicalInfo, records.logged[0].Resource)
+
+	_, err = service.GetMedicalInfo(asUser(uuid.New(), contextUtils.RoleAdmin), athlete)
+	assert.Nil(t, err, "admins see every athlete")
+}
+
+func TestMedicalInfoReadFailsWhenNotLogged(t *testing.T) {
+	athlete := uuid.New()
+	service := &Service{records: &fakeRecords{
+		logErr: errLib.New("Failed to log access", http.StatusInternalServerError),
+	}}
+
+	_, err := service.GetMedicalInfo(asUser(uuid.New(), contextUtils.RoleAdmin), athlete)
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusInternalServerError, err.HTTPCode)
+
+	_, err = service.GetFamilyMedicalInfo(asUser(athlete, contextUtils.RoleAthlete), athlete)
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusInternalServerError, err.HTTPCode)
+}
+
+func TestAuthorizeIncident(t *testing.T) {
+	athlete, coach, reporter := uuid.New(), uuid.New(), uuid.New()
+	service := &Service{records: &fakeRecords{coaches: map[uuid.UUID]uuid.UUID{athlete: coach}}}
+	incident := values.Incident{
+		ID:         uuid.New(),
+		ReportedBy: &reporter,
+		Athletes:   []values.IncidentAthlete{{AthleteID: uuid.New()}, {AthleteID: athlete}},
+	}
+	authorize := func(userID uuid.UUID, role contextUtils.CtxRole) *errLib.CommonError {
+		v, err := currentViewer(asUser(userID, role))
+		require.Nil(t, err)
+		return service.authorizeIncident(context.Background(), v, incident)
+	}
+
+	assert.Nil(t, authorize(reporter, contextUtils.RoleCoach), "the reporter")
+	assert.Nil(t, authorize(coach, contextUtils.RoleCoach), "a coach assigned to an athlete involved")
+	assert.Nil(t, authorize(uuid.New(), contextUtils.RoleAdmin))
+
+	err := authorize(uuid.New(), contextUtils.RoleCoach)
+	require.NotNil(t, err, "any other coach")
+	assert.Equal(t, http.StatusForbidden, err.HTTPCode)
+}
+
+func TestIncidentReadFailsWhenNotLogged(t *testing.T) {
+	service := &Service{records: &fakeRecords{
+		logErr: errLib.New("Failed to log access", http.StatusInternalServerError),
+	}}
+	v, err := currentViewer(asUser(uuid.New(), contextUtils.RoleAdmin))
+	require.Nil(t, err)
+	incident := values.Incident{ID: uuid.New(), Athletes: []values.IncidentAthlete{{AthleteID: uuid.New()}}}
+
+	err = service.logIncidentRead(context.Background(), v, incident, values.ResourceIncident, incident.ID)
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusInternalServerError, err.HTTPCode)
+}
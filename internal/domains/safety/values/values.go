@@ -0,0 +1,196 @@
+package values
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type IncidentType string
+
+const (
+	IncidentInjury     IncidentType = "injury"
+	IncidentIllness    IncidentType = "illness"
+	IncidentBehavioral IncidentType = "behavioral"
+	IncidentProperty   IncidentType = "property"
+	IncidentOther      IncidentType = "other"
+)
+
+type Severity string
+
+const (
+	SeverityMinor    Severity = "minor"
+	SeverityModerate Severity = "moderate"
+	SeveritySerious  Severity = "serious"
+	SeverityCritical Severity = "critical"
+)
+
+// IncidentStatus tracks an incident after it is reported. follow_up means someone
+// still has to act on it, e.g. a doctor's clearance before the athlete returns.
+type IncidentStatus string
+
+const (
+	StatusOpen     IncidentStatus = "open"
+	StatusFollowUp IncidentStatus = "follow_up"
+	StatusResolved IncidentStatus = "resolved"
+	StatusClosed   IncidentStatus = "closed"
+)
+
+func (s IncidentStatus) Valid() bool {
+	switch s {
+	case StatusOpen, StatusFollowUp, StatusResolved, StatusClosed:
+		return true
+	}
+	return false
+}
+
+// Resource is what kind of medical record a read was of.
+type Resource string
+
+const (
+	ResourceMedicalInfo Resource = "medical_info"
+	ResourceIncident    Resource = "incident"
+	ResourceAttachment  Resource = "incident_attachment"
+)
+
+// IncidentAthlete is an athlete involved in an incident and how they were hurt.
+type IncidentAthlete struct {
+	AthleteID        uuid.UUID
+	FirstName        string
+	LastName         string
+	Injury           *string
+	BodyPart         *string
+	Treatment        *string
+	ParentNotifiedAt *time.Time
+}
+
+type FollowUp struct {
+	ID         uuid.UUID
+	Status     IncidentStatus
+	Note       string
+	CreatedBy  *uuid.UUID
+	AuthorName string
+	CreatedAt  time.Time
+}
+
+type Attachment struct {
+	ID            uuid.UUID
+	IncidentID    uuid.UUID
+	ObjectPath    string
+	FileName      string
+	ContentType   string
+	FileSizeBytes int64
+	UploadedBy    *uuid.UUID
+	CreatedAt     time.Time
+}
+
+type Incident struct {
+	ID           uuid.UUID
+	Type         IncidentType
+	Severity     Severity
+	Status       IncidentStatus
+	OccurredAt   time.Time
+	EventID      *uuid.UUID
+	GameID       *uuid.UUID
+	PracticeID   *uuid.UUID
+	LocationID   *uuid.UUID
+	LocationName *string
+	Summary      string
+	Description  *string
+	ActionTaken  *string
+	ReportedBy   *uuid.UUID
+	ReporterName string
+	ResolvedAt   *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Athletes     []IncidentAthlete
+	FollowUps    []FollowUp
+	Attachments  []Attachment
+}
+
+// AthleteDetails is how an athlete was involved, as reported by staff.
+type AthleteDetails struct {
+	AthleteID uuid.UUID
+	Injury    *string
+	BodyPart  *string
+	Treatment *string
+}
+
+// IncidentDetails is what staff report. At most one of EventID, GameID and
+// PracticeID is set; without a LocationID the activity's location is used.
+type IncidentDetails struct {
+	Type          IncidentType
+	Severity      Severity
+	OccurredAt    time.Time
+	EventID       *uuid.UUID
+	GameID        *uuid.UUID
+	PracticeID    *uuid.UUID
+	LocationID    *uuid.UUID
+	Summary       string
+	Description   *string
+	ActionTaken   *string
+	Athletes      []AthleteDetails
+	NotifyParents bool
+}
+
+type IncidentFilter struct {
+	Status    *IncidentStatus
+	Type      *IncidentType
+	From      *time.Time
+	To        *time.Time
+	AthleteID *uuid.UUID
+	ViewerID  *uuid.UUID // only incidents this coach reported or is assigned to
+	Limit     int32
+	Offset    int32
+}
+
+// IncidentContact is who is told about an athlete's part in an incident.
+type IncidentContact struct {
+	AthleteID        uuid.UUID
+	FirstName        string
+	Email            *string
+	ParentFirstName  *string
+	ParentEmail      *string
+	Injury           *string
+	Treatment        *string
+	ParentNotifiedAt *time.Time
+}
+
+// MedicalInfo is a user's emergency contact and the medical details on file.
+type MedicalInfo struct {
+	UserID                       uuid.UUID
+	FirstName                    string
+	LastName                     string
+	EmergencyContactName         *string
+	EmergencyContactPhone        *string
+	EmergencyContactRelationship *string
+	Allergies                    *string
+	MedicalConditions            *string
+	Medications                  *string
+	PhysicianName                *string
+	PhysicianPhone               *string
+	Notes                        *string
+	UpdatedAt                    *time.Time
+}
+
+type MedicalDetails struct {
+	Allergies         *string
+	MedicalConditions *string
+	Medications       *string
+	PhysicianName     *string
+	PhysicianPhone    *string
+	Notes             *string
+}
+
+// AccessLogEntry is one read of a user's medical details.
+type AccessLogEntry struct {
+	ID         int64
+	ViewerID   *uuid.UUID
+	ViewerName string
+	ViewerRole string
+	IPAddress  string
+	SubjectID  uuid.UUID
+	Resource   Resource
+	ResourceID *uuid.UUID
+	AccessedAt time.Time
+}
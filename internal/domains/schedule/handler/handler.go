@@ -2,8 +2,12 @@ package schedule
 
 import (
 	"net/http"
+	"time"
 
 	"api/internal/di"
+	courtRentalDto "api/internal/domains/court_rental/dto"
+	courtRentalService "api/internal/domains/court_rental/service"
+	courtRentalValues "api/internal/domains/court_rental/values"
 	eventDto "api/internal/domains/event/dto"
 	eventService "api/internal/domains/event/service"
 	eventValues "api/internal/domains/event/values"
@@ -25,6 +29,7 @@ type Handler struct {
 	gameSvc     *gameService.Service
 	practiceSvc *practiceService.Service
 	familySvc   *familyService.Service
+	rentalSvc   *courtRentalService.Service
 }
 
 func NewHandler(c *di.Container) *Handler {
@@ -33,6 +38,7 @@ func NewHandler(c *di.Container) *Handler {
 		gameSvc:     gameService.NewService(c),
 		practiceSvc: practiceService.NewService(c),
 		familySvc:   familyService.NewService(c),
+		rentalSvc:   courtRentalService.NewService(c),
 	}
 }
 
@@ -40,12 +46,14 @@ type Response struct {
 	Events    []eventDto.EventResponseDto `json:"events"`
 	Games     []gameDto.ResponseDto       `json:"games"`
 	Practices []practiceDto.ResponseDto   `json:"practices"`
+	// CourtRentals are confirmed rentals that have not ended yet
+	CourtRentals []courtRentalDto.RentalResponseDto `json:"court_rentals"`
 }
 
-// GetMySchedule retrieves user's personalized schedule including events, games, and practices.
+// GetMySchedule retrieves user's personalized schedule including events, games, practices and court rentals.
 // Parents can view their child's schedule by passing the child_id query parameter.
 // @Summary Get my schedule
-// @Description Retrieves a consolidated schedule of events, games, and practices based on user role and associations.
+// @Description Retrieves a consolidated schedule of events, games, practices and court rentals based on user role and associations.
 // @Tags schedule
 // @Accept json
 // @Produce json
@@ -145,11 +153,23 @@ func (h *Handler) GetMySchedule(w http.ResponseWriter, r *http.Request) {
 		practiceDtos[i] = practiceDto.NewResponse(p)
 	}
 
-	// Results are already sorted by start time within events, games and practices
+	// Court rentals
+	rentalFilter := courtRentalValues.ListFilter{Status: courtRentalValues.StatusConfirmed, After: time.Now(), Limit: 1000}
+	if viewingChild || !(role == contextUtils.RoleAdmin || role == contextUtils.RoleSuperAdmin || role == contextUtils.RoleIT || role == contextUtils.RoleReceptionist) {
+		rentalFilter.CustomerID = targetUserID
+	}
+	rentals, err := h.rentalSvc.GetRentals(ctx, rentalFilter)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	// Results are already sorted by start time within events, games, practices and rentals
 	resp := Response{
-		Events:    eventDtos,
-		Games:     gameDtos,
-		Practices: practiceDtos,
+		Events:       eventDtos,
+		Games:        gameDtos,
+		Practices:    practiceDtos,
+		CourtRentals: courtRentalDto.NewRentalResponses(rentals),
 	}
 	responseHandlers.RespondWithSuccess(w, resp, http.StatusOK)
 }
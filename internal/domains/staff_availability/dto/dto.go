@@ -0,0 +1,326 @@
+package staff_availability
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	values "api/internal/domains/staff_availability/values"
+	errLib "api/internal/libs/errors"
+	"api/internal/libs/validators"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxConflictWindow bounds how far apart from and to may be in a conflict query.
+	MaxConflictWindow = 31 * 24 * time.Hour
+	// MaxSessionLength bounds the slot a suggestion query can ask about.
+	MaxSessionLength = 24 * time.Hour
+)
+
+const wallClockLayout = "15:04"
+
+// WindowRequestDto is one weekly period a staff member can be scheduled.
+type WindowRequestDto struct {
+	DayOfWeek  int        `json:"day_of_week" validate:"min=0,max=6" example:"1"` // 0=Sunday, 6=Saturday
+	StartTime  string     `json:"start_time" validate:"required" example:"15:00"` // HH:MM, facility local time
+	EndTime    string     `json:"end_time" validate:"required" example:"21:00"`   // HH:MM, facility local time
+	LocationID *uuid.UUID `json:"location_id,omitempty"`                          // Only sessions at this location
+}
+
+// ReplaceWindowsRequestDto replaces every weekly window. An empty list clears them.
+type ReplaceWindowsRequestDto struct {
+	Windows []WindowRequestDto `json:"windows" validate:"dive"`
+}
+
+type TimeOffRequestDto struct {
+	StartAt time.Time `json:"start_at" validate:"required" example:"2026-07-01T00:00:00Z"`
+	EndAt   time.Time `json:"end_at" validate:"required" example:"2026-07-08T00:00:00Z"`
+	Reason  *string   `json:"reason,omitempty" validate:"omitempty,max=500" example:"Vacation"`
+}
+
+// OverrideRequestDto is the optional body of a staff assignment, letting an admin
+// schedule the staff member over conflicts.
+type OverrideRequestDto struct {
+	StaffOverrideReason string `json:"staff_override_reason" validate:"omitempty,max=500"`
+}
+
+func (dto ReplaceWindowsRequestDto) ToValues() ([]values.Window, *errLib.CommonError) {
+	if err := validators.ValidateDto(&dto); err != nil {
+		return nil, err
+	}
+
+	windows := make([]values.Window, len(dto.Windows))
+	for i, w := range dto.Windows {
+		start, err := time.Parse(wallClockLayout, w.StartTime)
+		if err != nil {
+			return nil, errLib.New(fmt.Sprintf("windows[%d]: invalid start_time format, expected HH:MM", i), http.StatusBadRequest)
+		}
+		end, err := time.Parse(wallClockLayout, w.EndTime)
+		if err != nil {
+			return nil, errLib.New(fmt.Sprintf("windows[%d]: invalid end_time format, expected HH:MM", i), http.StatusBadRequest)
+		}
+		if !end.After(start) {
+			return nil, errLib.New(fmt.Sprintf("windows[%d]: end_time must be after start_time", i), http.StatusBadRequest)
+		}
+
+		windows[i] = values.Window{
+			DayOfWeek:  time.Weekday(w.DayOfWeek),
+			StartTime:  start,
+			EndTime:    end,
+			LocationID: w.LocationID,
+		}
+	}
+	return windows, nil
+}
+
+func (dto TimeOffRequestDto) ToValues(staffID uuid.UUID) (values.TimeOff, *errLib.CommonError) {
+	if err := validators.ValidateDto(&dto); err != nil {
+		return values.TimeOff{}, err
+	}
+	if !dto.EndAt.After(dto.StartAt) {
+		return values.TimeOff{}, errLib.New("end_at must be after start_at", http.StatusBadRequest)
+	}
+	if !dto.EndAt.After(time.Now()) {
+		return values.TimeOff{}, errLib.New("Time off cannot end in the past", http.StatusBadRequest)
+	}
+
+	return values.TimeOff{
+		StaffID: staffID,
+		StartAt: dto.StartAt,
+		EndAt:   dto.EndAt,
+		Reason:  dto.Reason,
+	}, nil
+}
+
+type WindowResponseDto struct {
+	ID         uuid.UUID  `json:"id"`
+	DayOfWeek  int        `json:"day_of_week"`
+	StartTime  string     `json:"start_time"`
+	EndTime    string     `json:"end_time"`
+	LocationID *uuid.UUID `json:"location_id,omitempty"`
+}
+
+type TimeOffResponseDto struct {
+	ID        uuid.UUID  `json:"id"`
+	StaffID   uuid.UUID  `json:"staff_id"`
+	StartAt   time.Time  `json:"start_at"`
+	EndAt     time.Time  `json:"end_at"`
+	Reason    *string    `json:"reason,omitempty" audit:"personal"`
+	CreatedBy *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type AvailabilityResponseDto struct {
+	StaffID uuid.UUID            `json:"staff_id"`
+	Windows []WindowResponseDto  `json:"windows"`
+	TimeOff []TimeOffResponseDto `json:"time_off"`
+}
+
+// ConflictResponseDto is one reason a staff member cannot take a session. Activity
+// fields are set for assignment conflicts and time_off_id for time off.
+type ConflictResponseDto struct {
+	Kind         values.ConflictKind `json:"kind"`
+	ActivityType values.ActivityType `json:"activity_type,omitempty"`
+	ActivityID   *uuid.UUID          `json:"activity_id,omitempty"`
+	Title        string              `json:"title,omitempty"`
+	TimeOffID    *uuid.UUID          `json:"time_off_id,omitempty"`
+	StartAt      time.Time           `json:"start_at"`
+	EndAt        time.Time           `json:"end_at"`
+}
+
+type SuggestionResponseDto struct {
+	StaffID           uuid.UUID `json:"staff_id"`
+	FirstName         string    `json:"first_name"`
+	LastName          string    `json:"last_name"`
+	Role              string    `json:"role"`
+	DeclaredAvailable bool      `json:"declared_available"`
+	AtLocation        bool      `json:"at_location"`
+	SessionsThatDay   int       `json:"sessions_that_day"`
+}
+
+type ScheduleOverrideResponseDto struct {
+	ID               uuid.UUID             `json:"id"`
+	StaffID          uuid.UUID             `json:"staff_id"`
+	StaffName        string                `json:"staff_name"`
+	ActivityType     values.ActivityType   `json:"activity_type"`
+	ActivityID       *uuid.UUID            `json:"activity_id,omitempty"`
+	StartAt          time.Time             `json:"start_at"`
+	EndAt            time.Time             `json:"end_at"`
+	Conflicts        []ConflictResponseDto `json:"conflicts"`
+	Reason           string                `json:"reason"`
+	OverriddenBy     *uuid.UUID            `json:"overridden_by,omitempty"`
+	OverriddenByName string                `json:"overridden_by_name,omitempty"`
+	CreatedAt        time.Time             `json:"created_at"`
+}
+
+func NewWindowResponses(windows []values.Window) []WindowResponseDto {
+	res := make([]WindowResponseDto, len(windows))
+	for i, w := range windows {
+		res[i] = WindowResponseDto{
+			ID:         w.ID,
+			DayOfWeek:  int(w.DayOfWeek),
+			StartTime:  w.StartTime.Format(wallClockLayout),
+			EndTime:    w.EndTime.Format(wallClockLayout),
+			LocationID: w.LocationID,
+		}
+	}
+	return res
+}
+
+func NewTimeOffResponse(t values.TimeOff) TimeOffResponseDto {
+	return TimeOffResponseDto{
+		ID:        t.ID,
+		StaffID:   t.StaffID,
+		StartAt:   t.StartAt,
+		EndAt:     t.EndAt,
+		Reason:    t.Reason,
+		CreatedBy: t.CreatedBy,
+		CreatedAt: t.CreatedAt,
+	}
+}
+
+func NewAvailabilityResponse(a values.Availability) AvailabilityResponseDto {
+	timeOff := make([]TimeOffResponseDto, len(a.TimeOff))
+	for i, t := range a.TimeOff {
+		timeOff[i] = NewTimeOffResponse(t)
+	}
+	return AvailabilityResponseDto{
+		StaffID: a.StaffID,
+		Windows: NewWindowResponses(a.Windows),
+		TimeOff: timeOff,
+	}
+}
+
+func NewConflictResponses(conflicts []values.Conflict) []ConflictResponseDto {
+	res := make([]ConflictResponseDto, len(conflicts))
+	for i, c := range conflicts {
+		res[i] = ConflictResponseDto{Kind: c.Kind, StartAt: c.StartAt, EndAt: c.EndAt}
+		if c.Booking != nil {
+			res[i].ActivityType = c.Booking.ActivityType
+			res[i].ActivityID = &c.Booking.ActivityID
+			res[i].Title = c.Booking.Title
+		}
+		if c.TimeOff != nil {
+			res[i].TimeOffID = &c.TimeOff.ID
+		}
+	}
+	return res
+}
+
+func NewSuggestionResponses(suggestions []values.Suggestion) []SuggestionResponseDto {
+	res := make([]SuggestionResponseDto, len(suggestions))
+	for i, s := range suggestions {
+		res[i] = SuggestionResponseDto{
+			StaffID:           s.ID,
+			FirstName:         s.FirstName,
+			LastName:          s.LastName,
+			Role:              s.Role,
+			DeclaredAvailable: s.DeclaredAvailable,
+			AtLocation:        s.AtLocation,
+			SessionsThatDay:   s.SessionsThatDay,
+		}
+	}
+	return res
+}
+
+func NewScheduleOverrideResponse(o values.ScheduleOverride) ScheduleOverrideResponseDto {
+	return ScheduleOverrideResponseDto{
+		ID:               o.ID,
+		StaffID:          o.StaffID,
+		StaffName:        o.StaffName,
+		ActivityType:     o.ActivityType,
+		ActivityID:       o.ActivityID,
+		StartAt:          o.StartAt,
+		EndAt:            o.EndAt,
+		Conflicts:        NewConflictResponses(o.Conflicts),
+		Reason:           o.Reason,
+		OverriddenBy:     o.OverriddenBy,
+		OverriddenByName: o.OverriddenByName,
+		CreatedAt:        o.CreatedAt,
+	}
+}
+
+// ParseConflictQuery reads staff_id, from, to (RFC 3339), the optional location_id
+// of the session and the optional activity_type and activity_id of an activity being
+// assigned or rescheduled.
+func ParseConflictQuery(query url.Values) (values.Request, *errLib.CommonError) {
+	from, to, err := parseWindow(query, MaxConflictWindow, "31 days")
+	if err != nil {
+		return values.Request{}, err
+	}
+
+	staffID, err := validators.ParseUUID(query.Get("staff_id"))
+	if err != nil {
+		return values.Request{}, err
+	}
+
+	req := values.Request{StaffID: staffID, StartAt: from, EndAt: to}
+	if locationStr := query.Get("location_id"); locationStr != "" {
+		if req.LocationID, err = validators.ParseUUID(locationStr); err != nil {
+			return values.Request{}, err
+		}
+	}
+	if req.ActivityType, req.ActivityID, err = parseActivity(query); err != nil {
+		return values.Request{}, err
+	}
+	return req, nil
+}
+
+// ParseSuggestionQuery reads from, to (RFC 3339), location_id and the optional
+// activity_type and activity_id of an activity being staffed or rescheduled.
+func ParseSuggestionQuery(query url.Values) (values.SuggestionFilter, *errLib.CommonError) {
+	from, to, err := parseWindow(query, MaxSessionLength, "24 hours")
+	if err != nil {
+		return values.SuggestionFilter{}, err
+	}
+
+	locationID, err := validators.ParseUUID(query.Get("location_id"))
+	if err != nil {
+		return values.SuggestionFilter{}, err
+	}
+
+	filter := values.SuggestionFilter{LocationID: locationID, From: from, To: to}
+	if filter.ActivityType, filter.ActivityID, err = parseActivity(query); err != nil {
+		return values.SuggestionFilter{}, err
+	}
+	return filter, nil
+}
+
+func parseWindow(query url.Values, maxWindow time.Duration, maxLabel string) (time.Time, time.Time, *errLib.CommonError) {
+	from, err := time.Parse(time.RFC3339, query.Get("from"))
+	if err != nil {
+		return time.Time{}, time.Time{}, errLib.New("Invalid from: must be an RFC 3339 timestamp", http.StatusBadRequest)
+	}
+	to, err := time.Parse(time.RFC3339, query.Get("to"))
+	if err != nil {
+		return time.Time{}, time.Time{}, errLib.New("Invalid to: must be an RFC 3339 timestamp", http.StatusBadRequest)
+	}
+	if !to.After(from) {
+		return time.Time{}, time.Time{}, errLib.New("to must be after from", http.StatusBadRequest)
+	}
+	if to.Sub(from) > maxWindow {
+		return time.Time{}, time.Time{}, errLib.New("The requested window cannot exceed "+maxLabel, http.StatusBadRequest)
+	}
+	return from, to, nil
+}
+
+func parseActivity(query url.Values) (values.ActivityType, uuid.UUID, *errLib.CommonError) {
+	activityType := values.ActivityType(query.Get("activity_type"))
+	if activityType != "" && !activityType.Valid() {
+		return "", uuid.Nil, errLib.New(fmt.Sprintf("Invalid activity_type. Expected one of: %s, %s, %s",
+			values.ActivityEvent, values.ActivityPractice, values.ActivityGame), http.StatusBadRequest)
+	}
+
+	activityID := uuid.Nil
+	if activityStr := query.Get("activity_id"); activityStr != "" {
+		id, err := validators.ParseUUID(activityStr)
+		if err != nil {
+			return "", uuid.Nil, err
+		}
+		activityID = id
+	}
+	return activityType, activityID, nil
+}
@@ -0,0 +1,256 @@
+package staff_availability
+
+import (
+	"net/http"
+	"strconv"
+
+	dto "api/internal/domains/staff_availability/dto"
+	responseHandlers "api/internal/libs/responses"
+	"api/internal/libs/validators"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+// GetStaffAvailability returns a coach or instructor's weekly availability and upcoming time off.
+// @Tags Staff Availability
+// @Produce json
+// @Security Bearer
+// @Param staff_id path string true "Staff ID"
+// @Success 200 {object} dto.AvailabilityResponseDto "Availability"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid staff ID"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /admin/staff-availability/{staff_id} [get]
+func (h *Handler) GetStaffAvailability(w http.ResponseWriter, r *http.Request) {
+	staffID, err := validators.ParseUUID(chi.URLParam(r, "staff_id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	availability, err := h.Service.GetAvailability(r.Context(), staffID)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewAvailabilityResponse(availability), http.StatusOK)
+}
+
+// SetStaffWeeklyAvailability replaces a coach or instructor's weekly availability.
+// @Tags Staff Availability
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param staff_id path string true "Staff ID"
+// @Param request body dto.ReplaceWindowsRequestDto true "Weekly windows"
+// @Success 200 {array} dto.WindowResponseDto "Weekly windows"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid windows"
+// @Failure 404 {object} map[string]interface{} "Not Found: Staff member or location not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /admin/staff-availability/{staff_id}/weekly [put]
+func (h *Handler) SetStaffWeeklyAvailability(w http.ResponseWriter, r *http.Request) {
+	staffID, err := validators.ParseUUID(chi.URLParam(r, "staff_id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	h.replaceWindows(w, r, staffID)
+}
+
+// AddStaffTimeOff records time off for a coach or instructor.
+// @Tags Staff Availability
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param staff_id path string true "Staff ID"
+// @Param request body dto.TimeOffRequestDto true "Time off"
+// @Success 201 {object} dto.TimeOffResponseDto "Time off created"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid time off"
+// @Failure 404 {object} map[string]interface{} "Not Found: Staff member not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /admin/staff-availability/{staff_id}/time-off [post]
+func (h *Handler) AddStaffTimeOff(w http.ResponseWriter, r *http.Request) {
+	staffID, err := validators.ParseUUID(chi.URLParam(r, "staff_id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	h.createTimeOff(w, r, staffID)
+}
+
+// DeleteStaffTimeOff removes a time off entry.
+// @Tags Staff Availability
+// @Security Bearer
+// @Param id path string true "Time off ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} map[string]interface{} "Not Found: Time off not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /admin/staff-availability/time-off/{id} [delete]
+func (h *Handler) DeleteStaffTimeOff(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	if err = h.Service.DeleteTimeOff(r.Context(), id, uuid.Nil); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, nil, http.StatusNoContent)
+}
+
+// GetStaffConflicts returns what would stop a coach or instructor taking a session.
+// @Description Lists overlapping events, practices and games, time off, and whether the session falls outside
+// @Description the staff member's declared weekly availability. Pass activity_type and activity_id when
+// @Description assigning to or rescheduling an existing activity so it does not clash with itself.
+// @Tags Staff Availability
+// @Produce json
+// @Security Bearer
+// @Param staff_id query string true "Staff ID"
+// @Param from query string true "Session start (RFC 3339)"
+// @Param to query string true "Session end (RFC 3339)"
+// @Param location_id query string false "Location of the session"
+// @Param activity_type query string false "event, practice or game"
+// @Param activity_id query string false "ID of the activity being assigned or rescheduled"
+// @Success 200 {array} dto.ConflictResponseDto "Conflicts (empty when available)"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid query"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /admin/staff-availability/conflicts [get]
+func (h *Handler) GetStaffConflicts(w http.ResponseWriter, r *http.Request) {
+	req, err := dto.ParseConflictQuery(r.URL.Query())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	conflicts, err := h.Service.GetConflicts(r.Context(), req)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewConflictResponses(conflicts), http.StatusOK)
+}
+
+// SuggestStaff lists the coaches and instructors available for a time slot at a location.
+// @Description Staff who are booked, on time off or outside their declared availability are left out.
+// @Description The rest are ordered by declared availability, then by already working at the location
+// @Description that day, then by fewest sessions that day.
+// @Tags Staff Availability
+// @Produce json
+// @Security Bearer
+// @Param from query string true "Session start (RFC 3339)"
+// @Param to query string true "Session end (RFC 3339), at most 24 hours after from"
+// @Param location_id query string true "Location of the session"
+// @Param activity_type query string false "event, practice or game"
+// @Param activity_id query string false "ID of the activity being staffed or rescheduled"
+// @Success 200 {array} dto.SuggestionResponseDto "Available staff, best fit first"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid query"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /admin/staff-availability/suggestions [get]
+func (h *Handler) SuggestStaff(w http.ResponseWriter, r *http.Request) {
+	filter, err := dto.ParseSuggestionQuery(r.URL.Query())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	suggestions, err := h.Service.SuggestStaff(r.Context(), filter)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewSuggestionResponses(suggestions), http.StatusOK)
+}
+
+// GetScheduleOverrides lists the staff scheduling conflicts admins have overridden, newest first.
+// @Tags Staff Availability
+// @Produce json
+// @Security Bearer
+// @Param staff_id query string false "Only overrides for this staff member"
+// @Param limit query int false "Number of records (default 20, max 100)"
+// @Param offset query int false "Number of records to skip"
+// @Success 200 {array} dto.ScheduleOverrideResponseDto "Override audit trail"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid staff_id"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /admin/staff-availability/overrides [get]
+func (h *Handler) GetScheduleOverrides(w http.ResponseWriter, r *http.Request) {
+	staffID := uuid.Nil
+	if idStr := r.URL.Query().Get("staff_id"); idStr != "" {
+		id, err := validators.ParseUUID(idStr)
+		if err != nil {
+			responseHandlers.RespondWithError(w, err)
+			return
+		}
+		staffID = id
+	}
+
+	limit := 20
+	offset := 0
+
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsed, parseErr := strconv.Atoi(limitStr); parseErr == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if parsed, parseErr := strconv.Atoi(offsetStr); parseErr == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+
+	overrides, err := h.Service.GetScheduleOverrides(r.Context(), staffID, int32(limit), int32(offset))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	resp := make([]dto.ScheduleOverrideResponseDto, len(overrides))
+	for i, o := range overrides {
+		resp[i] = dto.NewScheduleOverrideResponse(o)
+	}
+	responseHandlers.RespondWithSuccess(w, resp, http.StatusOK)
+}
+
+func (h *Handler) replaceWindows(w http.ResponseWriter, r *http.Request, staffID uuid.UUID) {
+	var body dto.ReplaceWindowsRequestDto
+	if err := validators.ParseJSON(r.Body, &body); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	windows, err := body.ToValues()
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	updated, err := h.Service.ReplaceWindows(r.Context(), staffID, windows)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewWindowResponses(updated), http.StatusOK)
+}
+
+func (h *Handler) createTimeOff(w http.ResponseWriter, r *http.Request, staffID uuid.UUID) {
+	var body dto.TimeOffRequestDto
+	if err := validators.ParseJSON(r.Body, &body); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	timeOff, err := body.ToValues(staffID)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	created, err := h.Service.CreateTimeOff(r.Context(), timeOff)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewTimeOffResponse(created), http.StatusCreated)
+}
@@ -0,0 +1,117 @@
+package staff_availability
+
+import (
+	"net/http"
+
+	"api/internal/di"
+	dto "api/internal/domains/staff_availability/dto"
+	service "api/internal/domains/staff_availability/service"
+	responseHandlers "api/internal/libs/responses"
+	"api/internal/libs/validators"
+	contextUtils "api/utils/context"
+
+	"github.com/go-chi/chi"
+)
+
+type Handler struct {
+	Service *service.Service
+}
+
+func NewHandler(container *di.Container) *Handler {
+	return &Handler{Service: service.NewService(container)}
+}
+
+// GetMyAvailability returns the logged-in coach's weekly availability and upcoming time off.
+// @Tags Staff Availability
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} dto.AvailabilityResponseDto "Availability"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /secure/availability [get]
+func (h *Handler) GetMyAvailability(w http.ResponseWriter, r *http.Request) {
+	staffID, err := contextUtils.GetUserID(r.Context())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	availability, err := h.Service.GetAvailability(r.Context(), staffID)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, dto.NewAvailabilityResponse(availability), http.StatusOK)
+}
+
+// SetMyWeeklyAvailability replaces the logged-in coach's weekly availability.
+// @Description Times are HH:MM in the facility's local time. Once any window is declared, sessions
+// @Description outside every window conflict; an empty list makes the coach available at any time.
+// @Tags Staff Availability
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body dto.ReplaceWindowsRequestDto true "Weekly windows"
+// @Success 200 {array} dto.WindowResponseDto "Weekly windows"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid windows"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Not Found: Location not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /secure/availability/weekly [put]
+func (h *Handler) SetMyWeeklyAvailability(w http.ResponseWriter, r *http.Request) {
+	staffID, err := contextUtils.GetUserID(r.Context())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	h.replaceWindows(w, r, staffID)
+}
+
+// AddMyTimeOff records time off for the logged-in coach.
+// @Tags Staff Availability
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body dto.TimeOffRequestDto true "Time off"
+// @Success 201 {object} dto.TimeOffResponseDto "Time off created"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid time off"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /secure/availability/time-off [post]
+func (h *Handler) AddMyTimeOff(w http.ResponseWriter, r *http.Request) {
+	staffID, err := contextUtils.GetUserID(r.Context())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	h.createTimeOff(w, r, staffID)
+}
+
+// DeleteMyTimeOff removes one of the logged-in coach's time off entries.
+// @Tags Staff Availability
+// @Security Bearer
+// @Param id path string true "Time off ID"
+// @Success 204 "No Content"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Not Found: Time off not found"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /secure/availability/time-off/{id} [delete]
+func (h *Handler) DeleteMyTimeOff(w http.ResponseWriter, r *http.Request) {
+	staffID, err := contextUtils.GetUserID(r.Context())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	id, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	if err = h.Service.DeleteTimeOff(r.Context(), id, staffID); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+	responseHandlers.RespondWithSuccess(w, nil, http.StatusNoContent)
+}
@@ -0,0 +1,374 @@
+package staff_availability
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"api/internal/di"
+	db "api/internal/domains/staff_availability/persistence/sqlc/generated"
+	values "api/internal/domains/staff_availability/values"
+	errLib "api/internal/libs/errors"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Repository stores staff weekly availability, time off and schedule overrides, and
+// reads what staff are booked for across events, practices and games.
+type Repository struct {
+	Queries *db.Queries
+	Tx      *sql.Tx
+}
+
+func NewRepository(container *di.Container) *Repository {
+	return &Repository{Queries: container.Queries.StaffAvailabilityDb}
+}
+
+func (r *Repository) GetTx() *sql.Tx { return r.Tx }
+
+func (r *Repository) WithTx(tx *sql.Tx) *Repository {
+	return &Repository{Queries: r.Queries.WithTx(tx), Tx: tx}
+}
+
+func nullUUID(id uuid.UUID) uuid.NullUUID {
+	return uuid.NullUUID{UUID: id, Valid: id != uuid.Nil}
+}
+
+func optionalUUID(id uuid.NullUUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
+	}
+	return &id.UUID
+}
+
+// IsSchedulable reports whether a staff member is a coach or instructor, the roles
+// whose schedules are checked for conflicts.
+func (r *Repository) IsSchedulable(ctx context.Context, staffID uuid.UUID) (bool, *errLib.CommonError) {
+	schedulable, err := r.Queries.IsSchedulableStaff(ctx, staffID)
+	if err != nil {
+		log.Printf("Failed to check staff role of %s: %v", staffID, err)
+		return false, errLib.New("Failed to check staff role", http.StatusInternalServerError)
+	}
+	return schedulable, nil
+}
+
+func (r *Repository) ListSchedulableStaff(ctx context.Context) ([]values.StaffMember, *errLib.CommonError) {
+	rows, err := r.Queries.ListSchedulableStaff(ctx)
+	if err != nil {
+		log.Printf("Failed to list coaches and instructors: %v", err)
+		return nil, errLib.New("Failed to list coaches and instructors", http.StatusInternalServerError)
+	}
+
+	staff := make([]values.StaffMember, len(rows))
+	for i, row := range rows {
+		staff[i] = values.StaffMember{ID: row.ID, FirstName: row.FirstName, LastName: row.LastName, Role: row.Role}
+	}
+	return staff, nil
+}
+
+// LockSchedule serialises schedule changes for one staff member until the
+// transaction ends, so two sessions cannot both pass the conflict check.
+func (r *Repository) LockSchedule(ctx context.Context, staffID uuid.UUID) *errLib.CommonError {
+	if err := r.Queries.LockStaffSchedule(ctx, staffID); err != nil {
+		log.Printf("Failed to lock schedule of staff %s: %v", staffID, err)
+		return errLib.New("Failed to check staff schedule", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// GetBookings returns the events, practices and games overlapping [from, to) for one
+// staff member, or for every staff member when staffID is uuid.Nil. The activity
+// identified by excludeType and excludeID is left out.
+func (r *Repository) GetBookings(ctx context.Context, staffID uuid.UUID, from, to time.Time, excludeType values.ActivityType, excludeID uuid.UUID) ([]values.Booking, *errLib.CommonError) {
+	rows, err := r.Queries.GetStaffBookings(ctx, db.GetStaffBookingsParams{
+		StaffID:     nullUUID(staffID),
+		From:        from,
+		To:          to,
+		ExcludeType: string(excludeType),
+		ExcludeID:   excludeID,
+	})
+	if err != nil {
+		log.Printf("Failed to get staff bookings: %v", err)
+		return nil, errLib.New("Failed to get staff bookings", http.StatusInternalServerError)
+	}
+
+	bookings := make([]values.Booking, len(rows))
+	for i, row := range rows {
+		bookings[i] = values.Booking{
+			StaffID:      row.StaffID,
+			ActivityType: values.ActivityType(row.ActivityType),
+			ActivityID:   row.ActivityID,
+			LocationID:   row.LocationID,
+			Title:        row.Title,
+			StartAt:      row.StartAt,
+			EndAt:        row.EndAt,
+		}
+	}
+	return bookings, nil
+}
+
+// ListWindows returns the weekly windows of one staff member, or of everyone when
+// staffID is uuid.Nil.
+func (r *Repository) ListWindows(ctx context.Context, staffID uuid.UUID) ([]values.Window, *errLib.CommonError) {
+	rows, err := r.Queries.ListAvailability(ctx, nullUUID(staffID))
+	if err != nil {
+		log.Printf("Failed to list staff availability: %v", err)
+		return nil, errLib.New("Failed to list staff availability", http.StatusInternalServerError)
+	}
+
+	windows := make([]values.Window, len(rows))
+	for i, row := range rows {
+		windows[i] = mapWindow(row)
+	}
+	return windows, nil
+}
+
+// ReplaceWindows swaps a staff member's weekly windows for a new set.
+func (r *Repository) ReplaceWindows(ctx context.Context, staffID uuid.UUID, windows []values.Window) ([]values.Window, *errLib.CommonError) {
+	if err := r.Queries.DeleteStaffAvailability(ctx, staffID); err != nil {
+		log.Printf("Failed to clear availability of staff %s: %v", staffID, err)
+		return nil, errLib.New("Failed to update availability", http.StatusInternalServerError)
+	}
+
+	created := make([]values.Window, len(windows))
+	for i, w := range windows {
+		params := db.CreateAvailabilityParams{
+			StaffID:   staffID,
+			DayOfWeek: int32(w.DayOfWeek),
+			StartTime: w.StartTime,
+			EndTime:   w.EndTime,
+		}
+		if w.LocationID != nil {
+			params.LocationID = uuid.NullUUID{UUID: *w.LocationID, Valid: true}
+		}
+
+		row, err := r.Queries.CreateAvailability(ctx, params)
+		if err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) {
+				if pqErr.Code == "23503" && pqErr.Constraint == "availability_staff_id_fkey" {
+					return nil, errLib.New("Staff member not found", http.StatusNotFound)
+				}
+				if pqErr.Code == "23503" {
+					return nil, errLib.New("Location not found", http.StatusNotFound)
+				}
+				if pqErr.Constraint == "check_availability_time_order" {
+					return nil, errLib.New("End time must be after start time", http.StatusBadRequest)
+				}
+			}
+			log.Printf("Failed to create availability for staff %s: %v", staffID, err)
+			return nil, errLib.New("Failed to update availability", http.StatusInternalServerError)
+		}
+		created[i] = mapWindow(row)
+	}
+	return created, nil
+}
+
+func mapWindow(row db.StaffAvailability) values.Window {
+	return values.Window{
+		ID:         row.ID,
+		StaffID:    row.StaffID,
+		DayOfWeek:  time.Weekday(row.DayOfWeek),
+		StartTime:  row.StartTime,
+		EndTime:    row.EndTime,
+		LocationID: optionalUUID(row.LocationID),
+	}
+}
+
+// ListTimeOff returns time off ending after from, and starting before to unless to is
+// zero, for one staff member or for everyone when staffID is uuid.Nil.
+func (r *Repository) ListTimeOff(ctx context.Context, staffID uuid.UUID, from, to time.Time) ([]values.TimeOff, *errLib.CommonError) {
+	rows, err := r.Queries.ListTimeOff(ctx, db.ListTimeOffParams{
+		StaffID: nullUUID(staffID),
+		From:    from,
+		To:      sql.NullTime{Time: to, Valid: !to.IsZero()},
+	})
+	if err != nil {
+		log.Printf("Failed to list staff time off: %v", err)
+		return nil, errLib.New("Failed to list time off", http.StatusInternalServerError)
+	}
+
+	timeOff := make([]values.TimeOff, len(rows))
+	for i, row := range rows {
+		timeOff[i] = mapTimeOff(row)
+	}
+	return timeOff, nil
+}
+
+func (r *Repository) GetTimeOff(ctx context.Context, id uuid.UUID) (values.TimeOff, *errLib.CommonError) {
+	row, err := r.Queries.GetTimeOffById(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return values.TimeOff{}, errLib.New("Time off not found", http.StatusNotFound)
+		}
+		log.Printf("Failed to get time off %s: %v", id, err)
+		return values.TimeOff{}, errLib.New("Failed to get time off", http.StatusInternalServerError)
+	}
+	return mapTimeOff(row), nil
+}
+
+func (r *Repository) CreateTimeOff(ctx context.Context, timeOff values.TimeOff) (values.TimeOff, *errLib.CommonError) {
+	params := db.CreateTimeOffParams{
+		StaffID:  timeOff.StaffID,
+		StartsAt: timeOff.StartAt,
+		EndsAt:   timeOff.EndAt,
+	}
+	if timeOff.Reason != nil {
+		params.Reason = sql.NullString{String: *timeOff.Reason, Valid: true}
+	}
+	if timeOff.CreatedBy != nil {
+		params.CreatedBy = uuid.NullUUID{UUID: *timeOff.CreatedBy, Valid: true}
+	}
+
+	row, err := r.Queries.CreateTimeOff(ctx, params)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) {
+			if pqErr.Constraint == "time_off_staff_id_fkey" {
+				return values.TimeOff{}, errLib.New("Staff member not found", http.StatusNotFound)
+			}
+			if pqErr.Constraint == "check_time_off_order" {
+				return values.TimeOff{}, errLib.New("Time off must end after it starts", http.StatusBadRequest)
+			}
+		}
+		log.Printf("Failed to create time off for staff %s: %v", timeOff.StaffID, err)
+		return values.TimeOff{}, errLib.New("Failed to create time off", http.StatusInternalServerError)
+	}
+	return mapTimeOff(row), nil
+}
+
+func (r *Repository) DeleteTimeOff(ctx context.Context, id uuid.UUID) *errLib.CommonError {
+	affected, err := r.Queries.DeleteTimeOff(ctx, id)
+	if err != nil {
+		log.Printf("Failed to delete time off %s: %v", id, err)
+		return errLib.New("Failed to delete time off", http.StatusInternalServerError)
+	}
+	if affected == 0 {
+		return errLib.New("Time off not found", http.StatusNotFound)
+	}
+	return nil
+}
+
+func mapTimeOff(row db.StaffTimeOff) values.TimeOff {
+	timeOff := values.TimeOff{
+		ID:        row.ID,
+		StaffID:   row.StaffID,
+		StartAt:   row.StartsAt,
+		EndAt:     row.EndsAt,
+		CreatedBy: optionalUUID(row.CreatedBy),
+		CreatedAt: row.CreatedAt,
+	}
+	if row.Reason.Valid {
+		timeOff.Reason = &row.Reason.String
+	}
+	return timeOff
+}
+
+// conflictRecord is the JSON shape of a conflict stored with an override.
+type conflictRecord struct {
+	Kind         string     `json:"kind"`
+	ActivityType string     `json:"activity_type,omitempty"`
+	ActivityID   *uuid.UUID `json:"activity_id,omitempty"`
+	Title        string     `json:"title,omitempty"`
+	TimeOffID    *uuid.UUID `json:"time_off_id,omitempty"`
+	StartAt      time.Time  `json:"start_at"`
+	EndAt        time.Time  `json:"end_at"`
+}
+
+func (r *Repository) CreateScheduleOverride(ctx context.Context, override values.ScheduleOverride) *errLib.CommonError {
+	records := make([]conflictRecord, len(override.Conflicts))
+	for i, c := range override.Conflicts {
+		records[i] = conflictRecord{Kind: string(c.Kind), StartAt: c.StartAt, EndAt: c.EndAt}
+		if c.Booking != nil {
+			records[i].ActivityType = string(c.Booking.ActivityType)
+			records[i].ActivityID = &c.Booking.ActivityID
+			records[i].Title = c.Booking.Title
+		}
+		if c.TimeOff != nil {
+			records[i].TimeOffID = &c.TimeOff.ID
+		}
+	}
+	conflicts, err := json.Marshal(records)
+	if err != nil {
+		return errLib.New("Failed to record schedule override", http.StatusInternalServerError)
+	}
+
+	params := db.CreateScheduleOverrideParams{
+		StaffID:      override.StaffID,
+		ActivityType: string(override.ActivityType),
+		StartsAt:     override.StartAt,
+		EndsAt:       override.EndAt,
+		Conflicts:    conflicts,
+		Reason:       override.Reason,
+	}
+	if override.ActivityID != nil {
+		params.ActivityID = uuid.NullUUID{UUID: *override.ActivityID, Valid: true}
+	}
+	if override.OverriddenBy != nil {
+		params.OverriddenBy = uuid.NullUUID{UUID: *override.OverriddenBy, Valid: true}
+	}
+
+	if _, err = r.Queries.CreateScheduleOverride(ctx, params); err != nil {
+		log.Printf("Failed to record schedule override: %v", err)
+		return errLib.New("Failed to record schedule override", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (r *Repository) ListScheduleOverrides(ctx context.Context, staffID uuid.UUID, limit, offset int32) ([]values.ScheduleOverride, *errLib.CommonError) {
+	rows, err := r.Queries.ListScheduleOverrides(ctx, db.ListScheduleOverridesParams{
+		StaffID: nullUUID(staffID),
+		Limit:   limit,
+		Offset:  offset,
+	})
+	if err != nil {
+		log.Printf("Failed to list schedule overrides: %v", err)
+		return nil, errLib.New("Failed to list schedule overrides", http.StatusInternalServerError)
+	}
+
+	overrides := make([]values.ScheduleOverride, len(rows))
+	for i, row := range rows {
+		var records []conflictRecord
+		if err := json.Unmarshal(row.Conflicts, &records); err != nil {
+			log.Printf("Failed to decode conflicts of schedule override %s: %v", row.ID, err)
+		}
+		conflicts := make([]values.Conflict, len(records))
+		for j, rec := range records {
+			conflicts[j] = values.Conflict{Kind: values.ConflictKind(rec.Kind), StartAt: rec.StartAt, EndAt: rec.EndAt}
+			if rec.ActivityID != nil {
+				conflicts[j].Booking = &values.Booking{
+					StaffID:      row.StaffID,
+					ActivityType: values.ActivityType(rec.ActivityType),
+					ActivityID:   *rec.ActivityID,
+					Title:        rec.Title,
+					StartAt:      rec.StartAt,
+					EndAt:        rec.EndAt,
+				}
+			}
+			if rec.TimeOffID != nil {
+				conflicts[j].TimeOff = &values.TimeOff{ID: *rec.TimeOffID, StaffID: row.StaffID, StartAt: rec.StartAt, EndAt: rec.EndAt}
+			}
+		}
+
+		overrides[i] = values.ScheduleOverride{
+			ID:               row.ID,
+			StaffID:          row.StaffID,
+			StaffName:        row.StaffName,
+			ActivityType:     values.ActivityType(row.ActivityType),
+			ActivityID:       optionalUUID(row.ActivityID),
+			StartAt:          row.StartsAt,
+			EndAt:            row.EndsAt,
+			Conflicts:        conflicts,
+			Reason:           row.Reason,
+			OverriddenBy:     optionalUUID(row.OverriddenBy),
+			OverriddenByName: row.OverriddenByName,
+			CreatedAt:        row.CreatedAt,
+		}
+	}
+	return overrides, nil
+}
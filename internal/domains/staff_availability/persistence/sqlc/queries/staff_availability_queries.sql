@@ -0,0 +1,135 @@
+-- name: IsSchedulableStaff :one
+SELECT EXISTS (SELECT 1
+               FROM staff.staff s
+                        JOIN staff.staff_roles sr ON sr.id = s.role_id
+               WHERE s.id = $1
+                 AND LOWER(sr.role_name) IN ('coach', 'instructor'))::bool;
+
+-- name: ListSchedulableStaff :many
+SELECT s.id,
+       u.first_name,
+       u.last_name,
+       LOWER(sr.role_name)::text AS role
+FROM staff.staff s
+         JOIN staff.staff_roles sr ON sr.id = s.role_id
+         JOIN users.users u ON u.id = s.id
+WHERE s.is_active
+  AND LOWER(sr.role_name) IN ('coach', 'instructor')
+ORDER BY u.last_name, u.first_name;
+
+-- name: LockStaffSchedule :exec
+SELECT pg_advisory_xact_lock(hashtext('staff_schedule:' || sqlc.arg('staff_id')::uuid::text));
+
+-- name: GetStaffBookings :many
+SELECT b.staff_id,
+       b.activity_type::text AS activity_type,
+       b.activity_id,
+       b.location_id,
+       b.title::text         AS title,
+       b.start_at,
+       b.end_at
+FROM (SELECT es.staff_id,
+             'event'                   AS activity_type,
+             e.id                      AS activity_id,
+             e.location_id,
+             COALESCE(p.name, 'Event') AS title,
+             e.start_at,
+             e.end_at
+      FROM events.staff es
+               JOIN events.events e ON e.id = es.event_id
+               LEFT JOIN program.programs p ON p.id = e.program_id
+      WHERE e.is_cancelled = false
+      UNION ALL
+      SELECT pr.booked_by,
+             'practice',
+             pr.id,
+             pr.location_id,
+             'Practice: ' || t.name,
+             pr.start_time,
+             COALESCE(pr.end_time, pr.start_time + interval '2 hours')
+      FROM practice.practices pr
+               JOIN athletic.teams t ON t.id = pr.team_id
+      WHERE pr.booked_by IS NOT NULL
+        AND (pr.status IS NULL OR pr.status != 'canceled')
+      UNION ALL
+      SELECT g.created_by,
+             'game',
+             g.id,
+             g.location_id,
+             ht.name || ' vs ' || at.name,
+             g.start_time,
+             COALESCE(g.end_time, g.start_time + interval '2 hours')
+      FROM game.games g
+               JOIN athletic.teams ht ON ht.id = g.home_team_id
+               JOIN athletic.teams at ON at.id = g.away_team_id
+      WHERE g.created_by IS NOT NULL
+        AND (g.status IS NULL OR g.status != 'canceled')) b
+WHERE (sqlc.narg('staff_id')::uuid IS NULL OR b.staff_id = sqlc.narg('staff_id')::uuid)
+  AND tstzrange(b.start_at, b.end_at, '[)') && tstzrange(sqlc.arg('from')::timestamptz, sqlc.arg('to')::timestamptz, '[)')
+  AND NOT (b.activity_type = sqlc.arg('exclude_type')::text AND b.activity_id = sqlc.arg('exclude_id')::uuid)
+ORDER BY b.start_at;
+
+-- name: ListAvailability :many
+SELECT *
+FROM staff.availability
+WHERE (sqlc.narg('staff_id')::uuid IS NULL OR staff_id = sqlc.narg('staff_id')::uuid)
+ORDER BY staff_id, day_of_week, start_time;
+
+-- name: DeleteStaffAvailability :exec
+DELETE
+FROM staff.availability
+WHERE staff_id = $1;
+
+-- name: CreateAvailability :one
+INSERT INTO staff.availability (staff_id, day_of_week, start_time, end_time, location_id)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: CreateTimeOff :one
+INSERT INTO staff.time_off (staff_id, starts_at, ends_at, reason, created_by)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetTimeOffById :one
+SELECT *
+FROM staff.time_off
+WHERE id = $1;
+
+-- name: DeleteTimeOff :execrows
+DELETE
+FROM staff.time_off
+WHERE id = $1;
+
+-- name: ListTimeOff :many
+SELECT *
+FROM staff.time_off
+WHERE (sqlc.narg('staff_id')::uuid IS NULL OR staff_id = sqlc.narg('staff_id')::uuid)
+  AND ends_at > sqlc.arg('from')::timestamptz
+  AND (sqlc.narg('to')::timestamptz IS NULL OR starts_at < sqlc.narg('to')::timestamptz)
+ORDER BY starts_at;
+
+-- name: CreateScheduleOverride :one
+INSERT INTO staff.schedule_overrides (staff_id, activity_type, activity_id, starts_at, ends_at, conflicts, reason,
+                                      overridden_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: ListScheduleOverrides :many
+SELECT o.id,
+       o.staff_id,
+       (su.first_name || ' ' || su.last_name)::text            AS staff_name,
+       o.activity_type,
+       o.activity_id,
+       o.starts_at,
+       o.ends_at,
+       o.conflicts,
+       o.reason,
+       o.overridden_by,
+       COALESCE(u.first_name || ' ' || u.last_name, '')::text AS overridden_by_name,
+       o.created_at
+FROM staff.schedule_overrides o
+         JOIN users.users su ON su.id = o.staff_id
+         LEFT JOIN users.users u ON u.id = o.overridden_by
+WHERE (sqlc.narg('staff_id')::uuid IS NULL OR o.staff_id = sqlc.narg('staff_id')::uuid)
+ORDER BY o.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
@@ -0,0 +1,441 @@
+package staff_availability
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"api/internal/di"
+	auditValues "api/internal/domains/audit/audit_logs/values"
+	staffActivityLogs "api/internal/domains/audit/staff_activity_logs/service"
+	dto "api/internal/domains/staff_availability/dto"
+	repo "api/internal/domains/staff_availability/persistence"
+	values "api/internal/domains/staff_availability/values"
+	errLib "api/internal/libs/errors"
+	contextUtils "api/utils/context"
+	txUtils "api/utils/db"
+	"api/utils/timezone"
+
+	"github.com/google/uuid"
+)
+
+const (
+	entityAvailability = "staff_availability"
+	entityTimeOff      = "staff_time_off"
+	entitySchedule     = "staff_schedule"
+	actionOverride     = "override"
+)
+
+// Service keeps coaches and instructors from being double-booked. Activity services
+// call Check inside their write transaction when they assign staff to an event,
+// practice or game, so clashes with other sessions, time off or declared weekly
+// availability come back as a structured 409.
+type Service struct {
+	repo                     *repo.Repository
+	staffActivityLogsService *staffActivityLogs.Service
+	db                       *sql.DB
+}
+
+func NewService(container *di.Container) *Service {
+	return &Service{
+		repo:                     repo.NewRepository(container),
+		staffActivityLogsService: staffActivityLogs.NewService(container),
+		db:                       container.DB,
+	}
+}
+
+// GetAvailability returns a staff member's weekly windows and time off that has not
+// ended yet.
+func (s *Service) GetAvailability(ctx context.Context, staffID uuid.UUID) (values.Availability, *errLib.CommonError) {
+	windows, err := s.repo.ListWindows(ctx, staffID)
+	if err != nil {
+		return values.Availability{}, err
+	}
+
+	timeOff, err := s.repo.ListTimeOff(ctx, staffID, time.Now(), time.Time{})
+	if err != nil {
+		return values.Availability{}, err
+	}
+
+	return values.Availability{StaffID: staffID, Windows: windows, TimeOff: timeOff}, nil
+}
+
+// ReplaceWindows replaces a staff member's weekly windows. An empty set clears them,
+// making the staff member schedulable at any time outside their time off.
+func (s *Service) ReplaceWindows(ctx context.Context, staffID uuid.UUID, windows []values.Window) ([]values.Window, *errLib.CommonError) {
+	actorID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var updated []values.Window
+	err = txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		txRepo := s.repo.WithTx(tx)
+
+		before, txErr := txRepo.ListWindows(ctx, staffID)
+		if txErr != nil {
+			return txErr
+		}
+
+		if updated, txErr = txRepo.ReplaceWindows(ctx, staffID, windows); txErr != nil {
+			return txErr
+		}
+
+		return s.staffActivityLogsService.RecordChange(ctx, tx, actorID, auditValues.Entry{
+			EntityType:  entityAvailability,
+			EntityID:    staffID.String(),
+			Action:      auditValues.ActionUpdate,
+			Before:      dto.NewWindowResponses(before),
+			After:       dto.NewWindowResponses(updated),
+			Description: fmt.Sprintf("Set weekly availability of staff %s (%d windows)", staffID, len(updated)),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// CreateTimeOff records a period the staff member cannot be scheduled. Sessions they
+// are already booked for are left alone; GetConflicts reports them.
+func (s *Service) CreateTimeOff(ctx context.Context, timeOff values.TimeOff) (values.TimeOff, *errLib.CommonError) {
+	actorID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return values.TimeOff{}, err
+	}
+	timeOff.CreatedBy = &actorID
+
+	var created values.TimeOff
+	err = txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		var txErr *errLib.CommonError
+		if created, txErr = s.repo.WithTx(tx).CreateTimeOff(ctx, timeOff); txErr != nil {
+			return txErr
+		}
+
+		return s.staffActivityLogsService.RecordChange(ctx, tx, actorID, auditValues.Entry{
+			EntityType: entityTimeOff,
+			EntityID:   created.ID.String(),
+			Action:     auditValues.ActionCreate,
+			After:      dto.NewTimeOffResponse(created),
+			Description: fmt.Sprintf("Added time off for staff %s from %s to %s", created.StaffID,
+				created.StartAt.UTC().Format(time.RFC3339), created.EndAt.UTC().Format(time.RFC3339)),
+		})
+	})
+	if err != nil {
+		return values.TimeOff{}, err
+	}
+	return created, nil
+}
+
+// DeleteTimeOff removes time off. When ownerID is set the entry must belong to that
+// staff member, so coaches can only remove their own.
+func (s *Service) DeleteTimeOff(ctx context.Context, id, ownerID uuid.UUID) *errLib.CommonError {
+	actorID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		txRepo := s.repo.WithTx(tx)
+
+		existing, txErr := txRepo.GetTimeOff(ctx, id)
+		if txErr != nil {
+			return txErr
+		}
+		if ownerID != uuid.Nil && existing.StaffID != ownerID {
+			return errLib.New("Time off not found", http.StatusNotFound)
+		}
+
+		if txErr = txRepo.DeleteTimeOff(ctx, id); txErr != nil {
+			return txErr
+		}
+
+		return s.staffActivityLogsService.RecordChange(ctx, tx, actorID, auditValues.Entry{
+			EntityType:  entityTimeOff,
+			EntityID:    id.String(),
+			Action:      auditValues.ActionDelete,
+			Before:      dto.NewTimeOffResponse(existing),
+			Description: fmt.Sprintf("Removed time off for staff %s", existing.StaffID),
+		})
+	})
+}
+
+// GetConflicts returns what would stop the staff member taking the session. Staff
+// who are not coaches or instructors never conflict.
+func (s *Service) GetConflicts(ctx context.Context, req values.Request) ([]values.Conflict, *errLib.CommonError) {
+	schedulable, err := s.repo.IsSchedulable(ctx, req.StaffID)
+	if err != nil || !schedulable {
+		return nil, err
+	}
+	return findConflicts(ctx, s.repo, s.db, req)
+}
+
+// Check verifies a staff assignment inside the caller's transaction, before the
+// activity row is written, and holds a per-staff lock until it commits. Conflicts
+// are returned as a 409 whose details name each clash. An admin can pass an
+// OverrideReason to schedule the staff member anyway; the override is recorded with
+// the conflicts it overrode and logged.
+func (s *Service) Check(ctx context.Context, tx *sql.Tx, req values.Request) *errLib.CommonError {
+	if req.StaffID == uuid.Nil {
+		return nil
+	}
+	if !req.EndAt.After(req.StartAt) {
+		return errLib.New("Session end time must be after start time", http.StatusBadRequest)
+	}
+
+	txRepo := s.repo.WithTx(tx)
+
+	schedulable, err := txRepo.IsSchedulable(ctx, req.StaffID)
+	if err != nil || !schedulable {
+		return err
+	}
+	if err = txRepo.LockSchedule(ctx, req.StaffID); err != nil {
+		return err
+	}
+
+	conflicts, err := findConflicts(ctx, txRepo, tx, req)
+	if err != nil {
+		return err
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	if req.OverrideReason == "" {
+		return conflictError(conflicts)
+	}
+
+	role, err := contextUtils.GetUserRole(ctx)
+	if err != nil {
+		return err
+	}
+	if role != contextUtils.RoleAdmin && role != contextUtils.RoleSuperAdmin {
+		return errLib.New("Only admins can override staff scheduling conflicts", http.StatusForbidden)
+	}
+
+	actorID, err := contextUtils.GetUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	override := values.ScheduleOverride{
+		StaffID:      req.StaffID,
+		ActivityType: req.ActivityType,
+		StartAt:      req.StartAt,
+		EndAt:        req.EndAt,
+		Conflicts:    conflicts,
+		Reason:       req.OverrideReason,
+		OverriddenBy: &actorID,
+	}
+	if req.ActivityID != uuid.Nil {
+		override.ActivityID = &req.ActivityID
+	}
+
+	if err = txRepo.CreateScheduleOverride(ctx, override); err != nil {
+		return err
+	}
+
+	return s.staffActivityLogsService.RecordChange(ctx, tx, actorID, auditValues.Entry{
+		EntityType: entitySchedule,
+		EntityID:   req.StaffID.String(),
+		Action:     actionOverride,
+		After:      dto.NewConflictResponses(conflicts),
+		Description: fmt.Sprintf("Scheduled staff %s for a %s over %s: %s",
+			req.StaffID, req.ActivityType, describe(conflicts), req.OverrideReason),
+	})
+}
+
+// SuggestStaff lists the coaches and instructors free for a session, best fits first.
+func (s *Service) SuggestStaff(ctx context.Context, filter values.SuggestionFilter) ([]values.Suggestion, *errLib.CommonError) {
+	staff, err := s.repo.ListSchedulableStaff(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := timezone.ForLocation(ctx, s.db, filter.LocationID)
+	dayStart, dayEnd := timezone.DayBounds(filter.From, loc)
+	if filter.To.After(dayEnd) {
+		dayEnd = filter.To
+	}
+
+	bookings, err := s.repo.GetBookings(ctx, uuid.Nil, dayStart, dayEnd, filter.ActivityType, filter.ActivityID)
+	if err != nil {
+		return nil, err
+	}
+	timeOff, err := s.repo.ListTimeOff(ctx, uuid.Nil, filter.From, filter.To)
+	if err != nil {
+		return nil, err
+	}
+	windows, err := s.repo.ListWindows(ctx, uuid.Nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return rankSuggestions(filter, staff, bookings, timeOff, windows, loc), nil
+}
+
+// GetScheduleOverrides lists recorded overrides, newest first.
+func (s *Service) GetScheduleOverrides(ctx context.Context, staffID uuid.UUID, limit, offset int32) ([]values.ScheduleOverride, *errLib.CommonError) {
+	return s.repo.ListScheduleOverrides(ctx, staffID, limit, offset)
+}
+
+func findConflicts(ctx context.Context, r *repo.Repository, q timezone.RowQuerier, req values.Request) ([]values.Conflict, *errLib.CommonError) {
+	bookings, err := r.GetBookings(ctx, req.StaffID, req.StartAt, req.EndAt, req.ActivityType, req.ActivityID)
+	if err != nil {
+		return nil, err
+	}
+	timeOff, err := r.ListTimeOff(ctx, req.StaffID, req.StartAt, req.EndAt)
+	if err != nil {
+		return nil, err
+	}
+	windows, err := r.ListWindows(ctx, req.StaffID)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := timezone.ForLocation(ctx, q, req.LocationID)
+	return toConflicts(req, bookings, timeOff, windows, loc), nil
+}
+
+// toConflicts lists the sessions and time off overlapping the request, and flags a
+// request falling outside the staff member's declared weekly windows.
+func toConflicts(req values.Request, bookings []values.Booking, timeOff []values.TimeOff, windows []values.Window, loc *time.Location) []values.Conflict {
+	var conflicts []values.Conflict
+	for i := range bookings {
+		b := bookings[i]
+		conflicts = append(conflicts, values.Conflict{Kind: values.ConflictAssignment, Booking: &b, StartAt: b.StartAt, EndAt: b.EndAt})
+	}
+	for i := range timeOff {
+		t := timeOff[i]
+		conflicts = append(conflicts, values.Conflict{Kind: values.ConflictTimeOff, TimeOff: &t, StartAt: t.StartAt, EndAt: t.EndAt})
+	}
+	if declared, covered := windowsCover(windows, req.LocationID, req.StartAt, req.EndAt, loc); declared && !covered {
+		conflicts = append(conflicts, values.Conflict{Kind: values.ConflictUnavailable, StartAt: req.StartAt, EndAt: req.EndAt})
+	}
+	return conflicts
+}
+
+// windowsCover reports whether a staff member has declared any weekly windows and,
+// if so, whether one of them covers [start, end) at the location. Windows are wall-
+// clock times in loc, so a session must fit inside a single window on the day it
+// starts; windows tied to another location do not count.
+func windowsCover(windows []values.Window, locationID uuid.UUID, start, end time.Time, loc *time.Location) (declared, covered bool) {
+	if len(windows) == 0 {
+		return false, true
+	}
+
+	dayStart, _ := timezone.DayBounds(start, loc)
+	weekday := start.In(loc).Weekday()
+	for _, w := range windows {
+		if w.DayOfWeek != weekday {
+			continue
+		}
+		if w.LocationID != nil && *w.LocationID != locationID {
+			continue
+		}
+		windowStart := timezone.At(dayStart, w.StartTime.Hour(), w.StartTime.Minute(), 0, loc)
+		windowEnd := timezone.At(dayStart, w.EndTime.Hour(), w.EndTime.Minute(), 0, loc)
+		if !start.Before(windowStart) && !end.After(windowEnd) {
+			return true, true
+		}
+	}
+	return true, false
+}
+
+// rankSuggestions drops staff who are booked, on time off or outside their declared
+// windows for the session, then orders the rest: declared availability first, then
+// staff already working at the location that day, then the lightest day, then name.
+// bookings must cover the whole day of the session.
+func rankSuggestions(filter values.SuggestionFilter, staff []values.StaffMember, bookings []values.Booking, timeOff []values.TimeOff, windows []values.Window, loc *time.Location) []values.Suggestion {
+	bookingsByStaff := make(map[uuid.UUID][]values.Booking)
+	for _, b := range bookings {
+		bookingsByStaff[b.StaffID] = append(bookingsByStaff[b.StaffID], b)
+	}
+	offStaff := make(map[uuid.UUID]bool)
+	for _, t := range timeOff {
+		if t.StartAt.Before(filter.To) && t.EndAt.After(filter.From) {
+			offStaff[t.StaffID] = true
+		}
+	}
+	windowsByStaff := make(map[uuid.UUID][]values.Window)
+	for _, w := range windows {
+		windowsByStaff[w.StaffID] = append(windowsByStaff[w.StaffID], w)
+	}
+
+	suggestions := make([]values.Suggestion, 0, len(staff))
+	for _, member := range staff {
+		if offStaff[member.ID] {
+			continue
+		}
+
+		suggestion := values.Suggestion{StaffMember: member}
+		busy := false
+		for _, b := range bookingsByStaff[member.ID] {
+			if b.StartAt.Before(filter.To) && b.EndAt.After(filter.From) {
+				busy = true
+				break
+			}
+			suggestion.SessionsThatDay++
+			if b.LocationID == filter.LocationID {
+				suggestion.AtLocation = true
+			}
+		}
+		if busy {
+			continue
+		}
+
+		declared, covered := windowsCover(windowsByStaff[member.ID], filter.LocationID, filter.From, filter.To, loc)
+		if !covered {
+			continue
+		}
+		suggestion.DeclaredAvailable = declared
+
+		suggestions = append(suggestions, suggestion)
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		a, b := suggestions[i], suggestions[j]
+		if a.DeclaredAvailable != b.DeclaredAvailable {
+			return a.DeclaredAvailable
+		}
+		if a.AtLocation != b.AtLocation {
+			return a.AtLocation
+		}
+		if a.SessionsThatDay != b.SessionsThatDay {
+			return a.SessionsThatDay < b.SessionsThatDay
+		}
+		if a.LastName != b.LastName {
+			return a.LastName < b.LastName
+		}
+		return a.FirstName < b.FirstName
+	})
+	return suggestions
+}
+
+func conflictError(conflicts []values.Conflict) *errLib.CommonError {
+	return errLib.NewWithDetails(
+		fmt.Sprintf("This staff member is not available: %s", describe(conflicts)),
+		http.StatusConflict,
+		dto.NewConflictResponses(conflicts),
+	)
+}
+
+func describe(conflicts []values.Conflict) string {
+	names := make([]string, len(conflicts))
+	for i, c := range conflicts {
+		span := fmt.Sprintf("%s - %s", c.StartAt.UTC().Format("Jan 2 15:04"), c.EndAt.UTC().Format("Jan 2 15:04 MST"))
+		switch c.Kind {
+		case values.ConflictAssignment:
+			names[i] = fmt.Sprintf("booked for %s %q (%s)", c.Booking.ActivityType, c.Booking.Title, span)
+		case values.ConflictTimeOff:
+			names[i] = fmt.Sprintf("time off (%s)", span)
+		default:
+			names[i] = "outside their declared weekly availability"
+		}
+	}
+	return strings.Join(names, ", ")
+}
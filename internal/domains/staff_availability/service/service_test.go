@@ -0,0 +1,142 @@
+package staff_availability
+
+import (
+	"testing"
+	"time"
+
+	values "api/internal/domains/staff_availability/values"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func wallClock(t *testing.T, value string) time.Time {
+	parsed, err := time.Parse("15:04", value)
+	require.NoError(t, err)
+	return parsed
+}
+
+func TestWindowsCover(t *testing.T) {
+	loc, err := time.LoadLocation("America/Edmonton")
+	require.NoError(t, err)
+	gym, otherGym := uuid.New(), uuid.New()
+
+	// Monday March 2nd 2026, 18:00-19:30 in Edmonton
+	start := time.Date(2026, 3, 2, 18, 0, 0, 0, loc)
+	end := start.Add(90 * time.Minute)
+
+	monday := values.Window{DayOfWeek: time.Monday, StartTime: wallClock(t, "17:00"), EndTime: wallClock(t, "21:00")}
+
+	t.Run("No declared windows means always available", func(t *testing.T) {
+		declared, covered := windowsCover(nil, gym, start, end, loc)
+
+		assert.False(t, declared)
+		assert.True(t, covered)
+	})
+
+	t.Run("A window on the session's local day covers it", func(t *testing.T) {
+		declared, covered := windowsCover([]values.Window{monday}, gym, start, end, loc)
+
+		assert.True(t, declared)
+		assert.True(t, covered)
+	})
+
+	t.Run("Windows are wall-clock times in the location's timezone", func(t *testing.T) {
+		// 18:00 in Edmonton is 01:00 UTC on Tuesday
+		declared, covered := windowsCover([]values.Window{monday}, gym, start.UTC(), end.UTC(), loc)
+
+		assert.True(t, declared)
+		assert.True(t, covered)
+	})
+
+	t.Run("A session running past the window is not covered", func(t *testing.T) {
+		_, covered := windowsCover([]values.Window{monday}, gym, start.Add(3*time.Hour), end.Add(3*time.Hour), loc)
+
+		assert.False(t, covered)
+	})
+
+	t.Run("Windows at another location do not count", func(t *testing.T) {
+		elsewhere := monday
+		elsewhere.LocationID = &otherGym
+
+		declared, covered := windowsCover([]values.Window{elsewhere}, gym, start, end, loc)
+
+		assert.True(t, declared)
+		assert.False(t, covered)
+	})
+
+	t.Run("Windows on another day do not count", func(t *testing.T) {
+		tuesday := monday
+		tuesday.DayOfWeek = time.Tuesday
+
+		_, covered := windowsCover([]values.Window{tuesday}, gym, start, end, loc)
+
+		assert.False(t, covered)
+	})
+}
+
+func TestToConflicts(t *testing.T) {
+	start := time.Date(2026, 3, 2, 18, 0, 0, 0, time.UTC)
+	req := values.Request{StaffID: uuid.New(), ActivityType: values.ActivityPractice, StartAt: start, EndAt: start.Add(time.Hour)}
+
+	bookings := []values.Booking{{ActivityType: values.ActivityGame, ActivityID: uuid.New(), Title: "Hawks vs Owls", StartAt: start, EndAt: start.Add(2 * time.Hour)}}
+	timeOff := []values.TimeOff{{ID: uuid.New(), StartAt: start.Add(-time.Hour), EndAt: start.Add(30 * time.Minute)}}
+	windows := []values.Window{{DayOfWeek: time.Saturday, StartTime: wallClock(t, "09:00"), EndTime: wallClock(t, "12:00")}}
+
+	conflicts := toConflicts(req, bookings, timeOff, windows, time.UTC)
+
+	require.Len(t, conflicts, 3)
+	assert.Equal(t, values.ConflictAssignment, conflicts[0].Kind)
+	assert.Equal(t, "Hawks vs Owls", conflicts[0].Booking.Title)
+	assert.Equal(t, values.ConflictTimeOff, conflicts[1].Kind)
+	assert.Equal(t, timeOff[0].ID, conflicts[1].TimeOff.ID)
+	assert.Equal(t, values.ConflictUnavailable, conflicts[2].Kind)
+
+	err := conflictError(conflicts)
+
+	assert.Equal(t, 409, err.HTTPCode)
+	assert.Contains(t, err.Message, `booked for game "Hawks vs Owls"`)
+	assert.Contains(t, err.Message, "outside their declared weekly availability")
+}
+
+func TestRankSuggestions(t *testing.T) {
+	gym, otherGym := uuid.New(), uuid.New()
+	start := time.Date(2026, 3, 2, 18, 0, 0, 0, time.UTC)
+	filter := values.SuggestionFilter{LocationID: gym, From: start, To: start.Add(time.Hour)}
+
+	member := func(last string) values.StaffMember {
+		return values.StaffMember{ID: uuid.New(), FirstName: "Coach", LastName: last, Role: "coach"}
+	}
+	busy, off, unavailable := member("Busy"), member("Off"), member("Unavailable")
+	declared, atGym, light, heavy := member("Declared"), member("AtGym"), member("Adams"), member("Baker")
+
+	session := func(staff values.StaffMember, location uuid.UUID, from time.Time) values.Booking {
+		return values.Booking{StaffID: staff.ID, ActivityType: values.ActivityPractice, LocationID: location, StartAt: from, EndAt: from.Add(time.Hour)}
+	}
+	bookings := []values.Booking{
+		session(busy, gym, start.Add(30*time.Minute)),
+		session(atGym, gym, start.Add(-3*time.Hour)),
+		session(heavy, otherGym, start.Add(-3*time.Hour)),
+		session(heavy, otherGym, start.Add(2*time.Hour)),
+		session(light, otherGym, start.Add(-3*time.Hour)),
+	}
+	timeOff := []values.TimeOff{{StaffID: off.ID, StartAt: start.Add(-24 * time.Hour), EndAt: start.Add(24 * time.Hour)}}
+	windows := []values.Window{
+		{StaffID: declared.ID, DayOfWeek: time.Monday, StartTime: wallClock(t, "17:00"), EndTime: wallClock(t, "21:00")},
+		{StaffID: unavailable.ID, DayOfWeek: time.Monday, StartTime: wallClock(t, "08:00"), EndTime: wallClock(t, "12:00")},
+	}
+
+	staff := []values.StaffMember{busy, off, unavailable, heavy, light, atGym, declared}
+	suggestions := rankSuggestions(filter, staff, bookings, timeOff, windows, time.UTC)
+
+	names := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		names[i] = s.LastName
+	}
+	assert.Equal(t, []string{"Declared", "AtGym", "Adams", "Baker"}, names)
+	assert.True(t, suggestions[0].DeclaredAvailable)
+	assert.True(t, suggestions[1].AtLocation)
+	assert.Equal(t, 1, suggestions[2].SessionsThatDay)
+	assert.Equal(t, 2, suggestions[3].SessionsThatDay)
+}
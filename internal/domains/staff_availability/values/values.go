@@ -0,0 +1,143 @@
+package values
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ActivityType identifies what a coach or instructor is scheduled for.
+type ActivityType string
+
+const (
+	ActivityEvent    ActivityType = "event"
+	ActivityPractice ActivityType = "practice"
+	ActivityGame     ActivityType = "game"
+)
+
+func (t ActivityType) Valid() bool {
+	switch t {
+	case ActivityEvent, ActivityPractice, ActivityGame:
+		return true
+	}
+	return false
+}
+
+// ConflictKind is why a staff member cannot take a session.
+type ConflictKind string
+
+const (
+	// ConflictAssignment is another event, practice or game overlapping the session.
+	ConflictAssignment ConflictKind = "assignment"
+	// ConflictTimeOff is declared time off overlapping the session.
+	ConflictTimeOff ConflictKind = "time_off"
+	// ConflictUnavailable is a session outside every declared weekly window.
+	ConflictUnavailable ConflictKind = "unavailable"
+)
+
+// Window is a weekly period a staff member can be scheduled. StartTime and EndTime
+// are wall-clock times (the date part is ignored) in the session location's timezone.
+// A window with a LocationID only covers sessions at that location.
+type Window struct {
+	ID         uuid.UUID
+	StaffID    uuid.UUID
+	DayOfWeek  time.Weekday
+	StartTime  time.Time
+	EndTime    time.Time
+	LocationID *uuid.UUID
+}
+
+// TimeOff is a period a staff member cannot be scheduled at all.
+type TimeOff struct {
+	ID        uuid.UUID
+	StaffID   uuid.UUID
+	StartAt   time.Time
+	EndAt     time.Time
+	Reason    *string
+	CreatedBy *uuid.UUID
+	CreatedAt time.Time
+}
+
+// Availability is a staff member's weekly windows and upcoming time off.
+type Availability struct {
+	StaffID uuid.UUID
+	Windows []Window
+	TimeOff []TimeOff
+}
+
+// Booking is an event, practice or game a staff member is scheduled for.
+type Booking struct {
+	StaffID      uuid.UUID
+	ActivityType ActivityType
+	ActivityID   uuid.UUID
+	LocationID   uuid.UUID
+	Title        string
+	StartAt      time.Time
+	EndAt        time.Time
+}
+
+// Conflict is a reason a staff member cannot take a session. Booking is set for
+// assignment conflicts and TimeOff for time off; unavailable conflicts carry neither.
+type Conflict struct {
+	Kind    ConflictKind
+	Booking *Booking
+	TimeOff *TimeOff
+	StartAt time.Time
+	EndAt   time.Time
+}
+
+// Request describes a session to schedule a staff member for. ActivityID is the
+// activity being assigned or rescheduled, if it already exists, so it does not
+// conflict with itself.
+type Request struct {
+	StaffID      uuid.UUID
+	ActivityType ActivityType
+	ActivityID   uuid.UUID
+	LocationID   uuid.UUID
+	StartAt      time.Time
+	EndAt        time.Time
+	// OverrideReason, when set by an admin, schedules the staff member over conflicts.
+	OverrideReason string
+}
+
+// SuggestionFilter is the session to find available coaches and instructors for.
+type SuggestionFilter struct {
+	LocationID   uuid.UUID
+	From         time.Time
+	To           time.Time
+	ActivityType ActivityType
+	ActivityID   uuid.UUID
+}
+
+// StaffMember is a coach or instructor who can be scheduled.
+type StaffMember struct {
+	ID        uuid.UUID
+	FirstName string
+	LastName  string
+	Role      string
+}
+
+// Suggestion is a coach or instructor free for a session. DeclaredAvailable is set
+// when one of their weekly windows covers it, as opposed to having declared none.
+type Suggestion struct {
+	StaffMember
+	DeclaredAvailable bool
+	AtLocation        bool
+	SessionsThatDay   int
+}
+
+// ScheduleOverride is an audit record of an admin scheduling staff over conflicts.
+type ScheduleOverride struct {
+	ID               uuid.UUID
+	StaffID          uuid.UUID
+	StaffName        string
+	ActivityType     ActivityType
+	ActivityID       *uuid.UUID
+	StartAt          time.Time
+	EndAt            time.Time
+	Conflicts        []Conflict
+	Reason           string
+	OverriddenBy     *uuid.UUID
+	OverriddenByName string
+	CreatedAt        time.Time
+}
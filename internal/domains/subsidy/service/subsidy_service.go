@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"api/internal/di"
+	auditValues "api/internal/domains/audit/audit_logs/values"
+	staffActivityLogs "api/internal/domains/audit/staff_activity_logs/service"
 	"api/internal/domains/subsidy/dto"
 	repo "api/internal/domains/subsidy/persistence/repository"
 	db "api/internal/domains/subsidy/persistence/sqlc/generated"
@@ -28,16 +30,18 @@ const (
 )
 
 type SubsidyService struct {
-	repo            *repo.SubsidyRepository
-	db              *sql.DB
-	paymentTracking *tracking.PaymentTrackingService
+	repo                     *repo.SubsidyRepository
+	db                       *sql.DB
+	paymentTracking          *tracking.PaymentTrackingService
+	staffActivityLogsService *staffActivityLogs.Service
 }
 
 func NewSubsidyService(container *di.Container) *SubsidyService {
 	return &SubsidyService{
-		repo:            repo.NewSubsidyRepository(container),
-		db:              container.DB,
-		paymentTracking: tracking.NewPaymentTrackingService(container),
+		repo:                     repo.NewSubsidyRepository(container),
+		db:                       container.DB,
+		paymentTracking:          tracking.NewPaymentTrackingService(container),
+		staffActivityLogsService: staffActivityLogs.NewService(container),
 	}
 }
 
@@ -221,7 +225,14 @@ func (s *SubsidyService) CreateSubsidy(ctx context.Context, req *dto.CreateSubsi
 		}
 
 		result = mapSubsidyToResponse(fullSubsidy)
-		return nil
+
+		return s.staffActivityLogsService.RecordChange(ctx, tx, staffID, auditValues.Entry{
+			EntityType:  "subsidy",
+			EntityID:    subsidy.ID.String(),
+			Action:      auditValues.ActionCreate,
+			After:       result,
+			Description: fmt.Sprintf("Created $%.2f subsidy %s for customer %s. Provider: %s, Reason: %s", req.ApprovedAmount, subsidy.ID, req.CustomerID, provider.Name, req.Reason),
+		})
 	})
 
 	if err != nil {
@@ -449,7 +460,14 @@ func (s *SubsidyService) DeactivateSubsidy(ctx context.Context, subsidyID, staff
 		log.Printf("✅ [AUDIT] Deactivated subsidy: ID=%s, Customer=%s, RemainingBalance=$%.2f, Staff=%s, Reason=%s",
 			subsidyID, subsidy.CustomerID, remainingBalance, staffID, reason)
 
-		return nil
+		return s.staffActivityLogsService.RecordChange(ctx, tx, staffID, auditValues.Entry{
+			EntityType:  "subsidy",
+			EntityID:    subsidyID.String(),
+			Action:      "deactivate",
+			Before:      map[string]string{"status": previousStatus},
+			After:       map[string]string{"status": "expired"},
+			Description: fmt.Sprintf("Deactivated subsidy %s for customer %s. Reason: %s", subsidyID, subsidy.CustomerID, reason),
+		})
 	})
 }
 
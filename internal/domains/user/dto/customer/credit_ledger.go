@@ -0,0 +1,96 @@
+package customer
+
+import (
+	"time"
+
+	values "api/internal/domains/user/values"
+
+	"github.com/google/uuid"
+)
+
+type CreditGrantResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	Source      string     `json:"source"`
+	Amount      int32      `json:"amount"`
+	Remaining   int32      `json:"remaining"`
+	Description string     `json:"description"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func CreditGrantValueToResponse(v values.CreditGrant) CreditGrantResponse {
+	return CreditGrantResponse{
+		ID:          v.ID,
+		Source:      v.Source,
+		Amount:      v.Amount,
+		Remaining:   v.Remaining,
+		Description: v.Description,
+		ExpiresAt:   v.ExpiresAt,
+		CreatedAt:   v.CreatedAt,
+	}
+}
+
+type CreditLedgerEntryResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	TransactionID uuid.UUID  `json:"transaction_id"`
+	Account       string     `json:"account"`
+	EntryType     string     `json:"entry_type"`
+	Amount        int32      `json:"amount"`
+	GrantID       *uuid.UUID `json:"grant_id,omitempty"`
+	HoldID        *uuid.UUID `json:"hold_id,omitempty"`
+	Reference     *string    `json:"reference,omitempty"`
+	Description   string     `json:"description"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+func CreditLedgerEntryValueToResponse(v values.CreditLedgerEntry) CreditLedgerEntryResponse {
+	return CreditLedgerEntryResponse{
+		ID:            v.ID,
+		TransactionID: v.TransactionID,
+		Account:       v.Account,
+		EntryType:     v.EntryType,
+		Amount:        v.Amount,
+		GrantID:       v.GrantID,
+		HoldID:        v.HoldID,
+		Reference:     v.Reference,
+		Description:   v.Description,
+		CreatedAt:     v.CreatedAt,
+	}
+}
+
+type CreditReconciliationIssueResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	CustomerID    uuid.UUID  `json:"customer_id"`
+	CustomerName  string     `json:"customer_name,omitempty"`
+	Kind          string     `json:"kind"`
+	TransactionID *uuid.UUID `json:"transaction_id,omitempty"`
+	Expected      int32      `json:"expected"`
+	Actual        int32      `json:"actual"`
+	DetectedAt    time.Time  `json:"detected_at"`
+	ResolvedBy    *uuid.UUID `json:"resolved_by,omitempty"`
+	ResolvedAt    *time.Time `json:"resolved_at,omitempty"`
+	Resolution    *string    `json:"resolution,omitempty"`
+}
+
+func CreditReconciliationIssueValueToResponse(v values.CreditReconciliationIssue) CreditReconciliationIssueResponse {
+	response := CreditReconciliationIssueResponse{
+		ID:            v.ID,
+		CustomerID:    v.CustomerID,
+		Kind:          v.Kind,
+		TransactionID: v.TransactionID,
+		Expected:      v.Expected,
+		Actual:        v.Actual,
+		DetectedAt:    v.DetectedAt,
+		ResolvedBy:    v.ResolvedBy,
+		ResolvedAt:    v.ResolvedAt,
+		Resolution:    v.Resolution,
+	}
+	if v.FirstName != "" || v.LastName != "" {
+		response.CustomerName = v.FirstName + " " + v.LastName
+	}
+	return response
+}
+
+type ResolveCreditReconciliationIssueRequest struct {
+	Resolution string `json:"resolution" validate:"required,notwhitespace"`
+}
@@ -0,0 +1,48 @@
+package customer
+
+import (
+	"fmt"
+
+	db "api/internal/domains/user/persistence/sqlc/generated"
+	values "api/internal/domains/user/values"
+
+	"github.com/google/uuid"
+)
+
+type MembershipFreezeResponse struct {
+	ID               uuid.UUID `json:"id"`
+	Reason           string    `json:"reason"`
+	StartDate        string    `json:"start_date"`
+	EndDate          string    `json:"end_date"`
+	Status           string    `json:"status"`
+	Fee              string    `json:"fee"`
+	CreditsForfeited int32     `json:"credits_forfeited"`
+	HasMedicalNote   bool      `json:"has_medical_note"`
+}
+
+func MembershipFreezeValueToResponse(v values.MembershipFreezeValue) MembershipFreezeResponse {
+	return MembershipFreezeResponse{
+		ID:               v.ID,
+		Reason:           v.Reason,
+		StartDate:        v.StartDate.Format("2006-01-02"),
+		EndDate:          v.EndDate.Format("2006-01-02"),
+		Status:           v.Status,
+		Fee:              fmt.Sprintf("$%.2f", float64(v.FeeAmount)/100),
+		CreditsForfeited: v.CreditsForfeited,
+		HasMedicalNote:   v.HasMedicalNote,
+	}
+}
+
+// NewMembershipFreezeResponse converts a freeze record to a response DTO
+func NewMembershipFreezeResponse(freeze db.UsersMembershipFreeze) MembershipFreezeResponse {
+	return MembershipFreezeValueToResponse(values.MembershipFreezeValue{
+		ID:               freeze.ID,
+		Reason:           freeze.Reason,
+		StartDate:        freeze.StartDate,
+		EndDate:          freeze.EndDate,
+		Status:           freeze.Status,
+		FeeAmount:        int(freeze.FeeAmount),
+		CreditsForfeited: freeze.CreditsForfeited,
+		HasMedicalNote:   freeze.MedicalNoteUrl.Valid,
+	})
+}
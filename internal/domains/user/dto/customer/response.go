@@ -9,28 +9,25 @@ import (
 )
 
 type Response struct {
-	UserID                       uuid.UUID              `json:"user_id"`
-	DOB                          string                 `json:"dob"`
-	FirstName                    string                 `json:"first_name"`
-	LastName                     string                 `json:"last_name"`
-	Email                        *string                `json:"email,omitempty"`
-	Phone                        *string                `json:"phone,omitempty"`
-	HubspotId                    *string                `json:"hubspot_id,omitempty"`
-	CountryCode                  string                 `json:"country_code"`
-	Notes                        *string                `json:"notes,omitempty"`
-	EmergencyContactName         *string                `json:"emergency_contact_name,omitempty"`
-	EmergencyContactPhone        *string                `json:"emergency_contact_phone,omitempty"`
-	EmergencyContactRelationship *string                `json:"emergency_contact_relationship,omitempty"`
-	LastMobileLoginAt            *time.Time             `json:"last_mobile_login_at,omitempty"`
-	PendingEmail                 *string                `json:"pending_email,omitempty"`
-	MembershipInfo               *MembershipResponseDto  `json:"membership_info,omitempty"`
-	Memberships                  []MembershipResponseDto `json:"memberships,omitempty"`
-	PhotoURL                     *string                `json:"photo_url,omitempty"`
-	IsArchived                   bool                   `json:"is_archived"`
-	ArchivedAt                   *time.Time             `json:"archived_at,omitempty"`
-	DeletedAt                    *time.Time             `json:"deleted_at,omitempty"`
-	ScheduledDeletionAt          *time.Time             `json:"scheduled_deletion_at,omitempty"`
-	DaysUntilDeletion            *int                   `json:"days_until_deletion,omitempty"`
+	UserID              uuid.UUID               `json:"user_id"`
+	DOB                 string                  `json:"dob"`
+	FirstName           string                  `json:"first_name"`
+	LastName            string                  `json:"last_name"`
+	Email               *string                 `json:"email,omitempty"`
+	Phone               *string                 `json:"phone,omitempty"`
+	HubspotId           *string                 `json:"hubspot_id,omitempty"`
+	CountryCode         string                  `json:"country_code"`
+	Notes               *string                 `json:"notes,omitempty"`
+	LastMobileLoginAt   *time.Time              `json:"last_mobile_login_at,omitempty"`
+	PendingEmail        *string                 `json:"pending_email,omitempty"`
+	MembershipInfo      *MembershipResponseDto  `json:"membership_info,omitempty"`
+	Memberships         []MembershipResponseDto `json:"memberships,omitempty"`
+	PhotoURL            *string                 `json:"photo_url,omitempty"`
+	IsArchived          bool                    `json:"is_archived"`
+	ArchivedAt          *time.Time              `json:"archived_at,omitempty"`
+	DeletedAt           *time.Time              `json:"deleted_at,omitempty"`
+	ScheduledDeletionAt *time.Time              `json:"scheduled_deletion_at,omitempty"`
+	DaysUntilDeletion   *int                    `json:"days_until_deletion,omitempty"`
 }
 
 type MembershipResponseDto struct {
@@ -45,24 +42,21 @@ type MembershipResponseDto struct {
 
 func UserReadValueToResponse(customer values.ReadValue) Response {
 	response := Response{
-		UserID:                       customer.ID,
-		DOB:                          customer.DOB.Format("2006-01-02"),
-		FirstName:                    customer.FirstName,
-		LastName:                     customer.LastName,
-		Email:                        customer.Email,
-		Phone:                        customer.Phone,
-		CountryCode:                  customer.CountryCode,
-		HubspotId:                    customer.HubspotID,
-		Notes:                        customer.Notes,
-		EmergencyContactName:         customer.EmergencyContactName,
-		EmergencyContactPhone:        customer.EmergencyContactPhone,
-		EmergencyContactRelationship: customer.EmergencyContactRelationship,
-		LastMobileLoginAt:            customer.LastMobileLoginAt,
-		PendingEmail:                 customer.PendingEmail,
-		IsArchived:                   customer.IsArchived,
-		ArchivedAt:                   customer.ArchivedAt,
-		DeletedAt:                    customer.DeletedAt,
-		ScheduledDeletionAt:          customer.ScheduledDeletionAt,
+		UserID:              customer.ID,
+		DOB:                 customer.DOB.Format("2006-01-02"),
+		FirstName:           customer.FirstName,
+		LastName:            customer.LastName,
+		Email:               customer.Email,
+		Phone:               customer.Phone,
+		CountryCode:         customer.CountryCode,
+		HubspotId:           customer.HubspotID,
+		Notes:               customer.Notes,
+		LastMobileLoginAt:   customer.LastMobileLoginAt,
+		PendingEmail:        customer.PendingEmail,
+		IsArchived:          customer.IsArchived,
+		ArchivedAt:          customer.ArchivedAt,
+		DeletedAt:           customer.DeletedAt,
+		ScheduledDeletionAt: customer.ScheduledDeletionAt,
 	}
 
 	// Calculate days until deletion
@@ -127,20 +121,25 @@ func calculateDaysUntilDeletion(customer values.ReadValue) *int {
 }
 
 type MembershipHistoryResponse struct {
-	MembershipName        string     `json:"membership_name"`
-	MembershipDescription string     `json:"membership_description"`
-	MembershipPlanName    string     `json:"membership_plan_name"`
-	MembsershipBenefits   string     `json:"membership_benefits"`
-	Price                 string     `json:"price"`
-	StartDate             time.Time  `json:"start_date"`
-	RenewalDate           *time.Time `json:"renewal_date,omitempty"`
-	NextPaymentDate       *time.Time `json:"next_payment_date,omitempty"`
-	Status                string     `json:"status"`
-	StripeSubscriptionID  *string    `json:"stripe_subscription_id,omitempty"`
+	MembershipName        string                     `json:"membership_name"`
+	MembershipDescription string                     `json:"membership_description"`
+	MembershipPlanName    string                     `json:"membership_plan_name"`
+	MembsershipBenefits   string                     `json:"membership_benefits"`
+	Price                 string                     `json:"price"`
+	StartDate             time.Time                  `json:"start_date"`
+	RenewalDate           *time.Time                 `json:"renewal_date,omitempty"`
+	NextPaymentDate       *time.Time                 `json:"next_payment_date,omitempty"`
+	Status                string                     `json:"status"`
+	StripeSubscriptionID  *string                    `json:"stripe_subscription_id,omitempty"`
+	Freezes               []MembershipFreezeResponse `json:"freezes"`
 }
 
 func MembershipHistoryValueToResponse(v values.MembershipHistoryValue) MembershipHistoryResponse {
 	price := fmt.Sprintf("$%.2f", float64(v.UnitAmount)/100)
+	freezes := make([]MembershipFreezeResponse, len(v.Freezes))
+	for i, freeze := range v.Freezes {
+		freezes[i] = MembershipFreezeValueToResponse(freeze)
+	}
 	return MembershipHistoryResponse{
 		MembershipName:        v.MembershipName,
 		MembershipDescription: v.MembershipDescription,
@@ -152,5 +151,6 @@ func MembershipHistoryValueToResponse(v values.MembershipHistoryValue) Membershi
 		Status:                v.Status,
 		MembsershipBenefits:   v.MembershipBenefits,
 		StripeSubscriptionID:  v.StripeSubscriptionID,
+		Freezes:               freezes,
 	}
 }
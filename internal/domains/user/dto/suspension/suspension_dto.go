@@ -4,7 +4,10 @@ import (
 	"net/http"
 	"time"
 
+	db "api/internal/domains/user/persistence/sqlc/generated"
 	errLib "api/internal/libs/errors"
+
+	"github.com/google/uuid"
 )
 
 type SuspendUserRequestDto struct {
@@ -47,3 +50,60 @@ func (dto *SuspendUserRequestDto) ParseDuration() (*time.Duration, *errLib.Commo
 
 	return &duration, nil
 }
+
+type ScheduleSuspensionActionRequestDto struct {
+	Action             string    `json:"action" validate:"required,oneof=suspend unsuspend"`
+	RunAt              time.Time `json:"run_at" validate:"required"`
+	SuspensionReason   string    `json:"suspension_reason,omitempty" validate:"omitempty,min=10,max=500"` // required to suspend
+	SuspensionDuration *string   `json:"suspension_duration,omitempty"`                                   // suspend only, e.g. "720h", null = indefinite
+	ExtendMembership   bool      `json:"extend_membership"`                                               // unsuspend only
+	CollectArrears     bool      `json:"collect_arrears"`                                                 // unsuspend only
+}
+
+type ScheduledSuspensionActionResponseDto struct {
+	ID                 uuid.UUID  `json:"id"`
+	Action             string     `json:"action"`
+	RunAt              time.Time  `json:"run_at"`
+	SuspensionReason   *string    `json:"suspension_reason,omitempty"`
+	SuspensionDuration *string    `json:"suspension_duration,omitempty"`
+	ExtendMembership   bool       `json:"extend_membership"`
+	CollectArrears     bool       `json:"collect_arrears"`
+	Status             string     `json:"status"` // pending, completed, failed or cancelled
+	ScheduledBy        uuid.UUID  `json:"scheduled_by"`
+	ExecutedAt         *time.Time `json:"executed_at,omitempty"`
+	FailureReason      *string    `json:"failure_reason,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+}
+
+// ParseDuration converts the optional suspension duration to time.Duration
+func (dto *ScheduleSuspensionActionRequestDto) ParseDuration() (*time.Duration, *errLib.CommonError) {
+	suspend := SuspendUserRequestDto{SuspensionDuration: dto.SuspensionDuration}
+	return suspend.ParseDuration()
+}
+
+func NewScheduledSuspensionActionResponse(action db.UsersScheduledSuspensionAction) ScheduledSuspensionActionResponseDto {
+	response := ScheduledSuspensionActionResponseDto{
+		ID:               action.ID,
+		Action:           action.Action,
+		RunAt:            action.RunAt,
+		ExtendMembership: action.ExtendMembership,
+		CollectArrears:   action.CollectArrears,
+		Status:           action.Status,
+		ScheduledBy:      action.ScheduledBy,
+		CreatedAt:        action.CreatedAt,
+	}
+	if action.SuspensionReason.Valid {
+		response.SuspensionReason = &action.SuspensionReason.String
+	}
+	if action.SuspensionDurationSeconds.Valid {
+		duration := (time.Duration(action.SuspensionDurationSeconds.Int64) * time.Second).String()
+		response.SuspensionDuration = &duration
+	}
+	if action.ExecutedAt.Valid {
+		response.ExecutedAt = &action.ExecutedAt.Time
+	}
+	if action.FailureReason.Valid {
+		response.FailureReason = &action.FailureReason.String
+	}
+	return response
+}
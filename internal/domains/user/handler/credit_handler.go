@@ -3,9 +3,11 @@ package user
 import (
 	"net/http"
 	"strconv"
+	"time"
 
 	"api/internal/di"
 	familyService "api/internal/domains/family/service"
+	customerDto "api/internal/domains/user/dto/customer"
 	"api/internal/domains/user/services"
 	errLib "api/internal/libs/errors"
 	responseHandlers "api/internal/libs/responses"
@@ -214,7 +216,9 @@ func (h *CreditHandler) GetWeeklyUsage(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param id path string true "Customer ID" format(uuid)
-// @Param request body map[string]interface{} true "Credit addition request" example({"amount":100,"description":"Bonus credits"})
+// @Description Adds credits as a new grant. expires_at is optional; without it the credits never expire.
+// @Param Idempotency-Key header string true "Unique key for this submission; a retry with the same key returns the first response"
+// @Param request body map[string]interface{} true "Credit addition request" example({"amount":100,"description":"Bonus credits","expires_at":"2026-12-31T23:59:59Z"})
 // @Success 200 {object} map[string]interface{} "Credits added successfully"
 // @Failure 400 {object} map[string]interface{} "Bad Request: Invalid input"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
@@ -239,8 +243,9 @@ func (h *CreditHandler) AddCustomerCredits(w http.ResponseWriter, r *http.Reques
 
 	// Parse request body
 	var requestBody struct {
-		Amount      int32  `json:"amount" validate:"required,min=1"`
-		Description string `json:"description" validate:"required"`
+		Amount      int32      `json:"amount" validate:"required,min=1"`
+		Description string     `json:"description" validate:"required"`
+		ExpiresAt   *time.Time `json:"expires_at"`
 	}
 
 	if err := validators.ParseJSON(r.Body, &requestBody); err != nil {
@@ -253,7 +258,12 @@ func (h *CreditHandler) AddCustomerCredits(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	if err := h.CreditService.AddCredits(r.Context(), customerID, requestBody.Amount, requestBody.Description); err != nil {
+	if requestBody.ExpiresAt != nil && !requestBody.ExpiresAt.After(time.Now()) {
+		responseHandlers.RespondWithError(w, errLib.New("expires_at must be in the future", http.StatusBadRequest))
+		return
+	}
+
+	if err := h.CreditService.AddCredits(r.Context(), customerID, requestBody.Amount, requestBody.ExpiresAt, requestBody.Description); err != nil {
 		responseHandlers.RespondWithError(w, err)
 		return
 	} else {
@@ -271,6 +281,7 @@ func (h *CreditHandler) AddCustomerCredits(w http.ResponseWriter, r *http.Reques
 // @Accept json
 // @Produce json
 // @Param id path string true "Customer ID" format(uuid)
+// @Param Idempotency-Key header string true "Unique key for this submission; a retry with the same key returns the first response"
 // @Param request body map[string]interface{} true "Credit deduction request" example({"amount":50,"description":"Penalty deduction"})
 // @Success 200 {object} map[string]interface{} "Credits deducted successfully"
 // @Failure 400 {object} map[string]interface{} "Bad Request: Invalid input or insufficient credits"
@@ -660,3 +671,220 @@ func (h *CreditHandler) GetCreditRefundLogs(w http.ResponseWriter, r *http.Reque
 	}
 	responseHandlers.RespondWithSuccess(w, response, http.StatusOK)
 }
+
+// GetCustomerCreditGrants retrieves the authenticated user's credit grants with the credits left on each and when they expire
+// Parents can view their child's grants by passing the child_id query parameter.
+// @Tags credits
+// @Accept json
+// @Produce json
+// @Param include_used query bool false "Include grants with no credits left" default(false)
+// @Param limit query int false "Number of items per page" minimum(1) maximum(100) default(20)
+// @Param offset query int false "Number of items to skip" minimum(0) default(0)
+// @Param child_id query string false "Child user ID (for parent viewing child's grants)" format(uuid)
+// @Success 200 {object} map[string]interface{} "Credit grants retrieved successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Not authorized to view child's grants"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Security Bearer
+// @Router /secure/credits/grants [get]
+func (h *CreditHandler) GetCustomerCreditGrants(w http.ResponseWriter, r *http.Request) {
+	customerID, err := contextUtils.GetUserID(r.Context())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	// Check if parent is requesting child's grants
+	targetCustomerID := customerID
+	if childIDStr := r.URL.Query().Get("child_id"); childIDStr != "" {
+		childID, parseErr := validators.ParseUUID(childIDStr)
+		if parseErr != nil {
+			responseHandlers.RespondWithError(w, parseErr)
+			return
+		}
+
+		// Verify parent has access to this child
+		if verifyErr := h.FamilyService.VerifyParentChildAccess(r.Context(), customerID, childID); verifyErr != nil {
+			responseHandlers.RespondWithError(w, verifyErr)
+			return
+		}
+
+		targetCustomerID = childID
+	}
+
+	limit, offset := parseCreditPagination(r)
+	openOnly := r.URL.Query().Get("include_used") != "true"
+
+	grants, err := h.CreditService.ListCreditGrants(r.Context(), targetCustomerID, openOnly, int32(limit), int32(offset))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	grantResponses := make([]customerDto.CreditGrantResponse, len(grants))
+	for i, grant := range grants {
+		grantResponses[i] = customerDto.CreditGrantValueToResponse(grant)
+	}
+
+	response := map[string]interface{}{
+		"customer_id": targetCustomerID,
+		"grants":      grantResponses,
+		"limit":       limit,
+		"offset":      offset,
+	}
+	responseHandlers.RespondWithSuccess(w, response, http.StatusOK)
+}
+
+// GetAnyCustomerCreditLedger retrieves a customer's credit grants and ledger entries (admin only)
+// @Tags credits
+// @Accept json
+// @Produce json
+// @Param id path string true "Customer ID" format(uuid)
+// @Param limit query int false "Number of ledger entries per page" minimum(1) maximum(100) default(20)
+// @Param offset query int false "Number of ledger entries to skip" minimum(0) default(0)
+// @Success 200 {object} map[string]interface{} "Credit ledger retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid customer ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Admin access required"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Security Bearer
+// @Router /admin/customers/{id}/credits/ledger [get]
+func (h *CreditHandler) GetAnyCustomerCreditLedger(w http.ResponseWriter, r *http.Request) {
+	customerID, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	limit, offset := parseCreditPagination(r)
+
+	grants, err := h.CreditService.ListCreditGrants(r.Context(), customerID, true, 100, 0)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	entries, err := h.CreditService.ListLedgerEntries(r.Context(), customerID, int32(limit), int32(offset))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	grantResponses := make([]customerDto.CreditGrantResponse, len(grants))
+	for i, grant := range grants {
+		grantResponses[i] = customerDto.CreditGrantValueToResponse(grant)
+	}
+	entryResponses := make([]customerDto.CreditLedgerEntryResponse, len(entries))
+	for i, entry := range entries {
+		entryResponses[i] = customerDto.CreditLedgerEntryValueToResponse(entry)
+	}
+
+	response := map[string]interface{}{
+		"customer_id": customerID,
+		"open_grants": grantResponses,
+		"entries":     entryResponses,
+		"limit":       limit,
+		"offset":      offset,
+	}
+	responseHandlers.RespondWithSuccess(w, response, http.StatusOK)
+}
+
+// GetCreditReconciliationIssues lists differences the nightly credit reconciliation found between balances and the ledger (admin only)
+// @Tags credits
+// @Accept json
+// @Produce json
+// @Param include_resolved query bool false "Include resolved issues" default(false)
+// @Param limit query int false "Number of items per page" minimum(1) maximum(100) default(20)
+// @Param offset query int false "Number of items to skip" minimum(0) default(0)
+// @Success 200 {object} map[string]interface{} "Credit reconciliation issues retrieved successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Admin access required"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Security Bearer
+// @Router /admin/credit-reconciliation [get]
+func (h *CreditHandler) GetCreditReconciliationIssues(w http.ResponseWriter, r *http.Request) {
+	limit, offset := parseCreditPagination(r)
+	includeResolved := r.URL.Query().Get("include_resolved") == "true"
+
+	issues, err := h.CreditService.ListReconciliationIssues(r.Context(), includeResolved, int32(limit), int32(offset))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	issueResponses := make([]customerDto.CreditReconciliationIssueResponse, len(issues))
+	for i, issue := range issues {
+		issueResponses[i] = customerDto.CreditReconciliationIssueValueToResponse(issue)
+	}
+
+	response := map[string]interface{}{
+		"issues": issueResponses,
+		"limit":  limit,
+		"offset": offset,
+	}
+	responseHandlers.RespondWithSuccess(w, response, http.StatusOK)
+}
+
+// ResolveCreditReconciliationIssue closes a credit reconciliation issue once it has been corrected (admin only)
+// @Tags credits
+// @Accept json
+// @Produce json
+// @Param id path string true "Issue ID" format(uuid)
+// @Param request body customer.ResolveCreditReconciliationIssueRequest true "How the issue was resolved"
+// @Success 200 {object} customer.CreditReconciliationIssueResponse "Issue resolved"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid input"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Admin access required"
+// @Failure 404 {object} map[string]interface{} "Not Found: No open issue with this ID"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Security Bearer
+// @Router /admin/credit-reconciliation/{id}/resolve [post]
+func (h *CreditHandler) ResolveCreditReconciliationIssue(w http.ResponseWriter, r *http.Request) {
+	issueID, err := validators.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	staffID, err := contextUtils.GetUserID(r.Context())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	var requestBody customerDto.ResolveCreditReconciliationIssueRequest
+	if err := validators.ParseJSON(r.Body, &requestBody); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	if err := validators.ValidateDto(&requestBody); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	issue, err := h.CreditService.ResolveReconciliationIssue(r.Context(), issueID, staffID, requestBody.Resolution)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	responseHandlers.RespondWithSuccess(w, customerDto.CreditReconciliationIssueValueToResponse(issue), http.StatusOK)
+}
+
+// parseCreditPagination reads limit (1-100, default 20) and offset (default 0) from the query
+func parseCreditPagination(r *http.Request) (limit, offset int) {
+	limit = 20
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsed, parseErr := strconv.Atoi(limitStr); parseErr == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if parsed, parseErr := strconv.Atoi(offsetStr); parseErr == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+	return limit, offset
+}
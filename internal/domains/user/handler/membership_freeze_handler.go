@@ -0,0 +1,145 @@
+package user
+
+import (
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"api/internal/di"
+	customerDto "api/internal/domains/user/dto/customer"
+	"api/internal/domains/user/services"
+	errLib "api/internal/libs/errors"
+	responseHandlers "api/internal/libs/responses"
+	contextUtils "api/utils/context"
+
+	"github.com/go-chi/chi"
+)
+
+// maxMedicalNoteSize is the largest medical note accepted with a freeze request
+const maxMedicalNoteSize = 20 << 20
+
+type MembershipFreezeHandler struct {
+	freezeService *services.MembershipFreezeService
+}
+
+func NewMembershipFreezeHandler(container *di.Container) *MembershipFreezeHandler {
+	return &MembershipFreezeHandler{
+		freezeService: services.NewMembershipFreezeService(container),
+	}
+}
+
+// RequestFreeze freezes the logged-in customer's membership
+// @Summary Freeze my membership
+// @Description Schedules a freeze of a membership within its plan's freeze policy. Billing stops from start_date and resumes on end_date, the plan's freeze fee is charged straight away and the credits of the frozen days are forfeited when the freeze starts. Injury freezes need a medical note (pdf, jpg, jpeg or png) when the policy requires one.
+// @Tags customers
+// @Accept multipart/form-data
+// @Produce json
+// @Security Bearer
+// @Param subscription_id path string true "Stripe subscription ID of the membership"
+// @Param reason formData string true "vacation, injury or other"
+// @Param start_date formData string true "First frozen day (YYYY-MM-DD)"
+// @Param end_date formData string true "Day the membership resumes (YYYY-MM-DD)"
+// @Param notes formData string false "Notes for staff"
+// @Param medical_note formData file false "Medical note, for injury freezes"
+// @Success 201 {object} customer.MembershipFreezeResponse "Freeze scheduled"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid input or outside the freeze policy"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 402 {object} map[string]interface{} "Payment Required: Freeze fee could not be charged"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Plan cannot be frozen"
+// @Failure 404 {object} map[string]interface{} "Not Found: Membership not found"
+// @Failure 409 {object} map[string]interface{} "Conflict: Membership not active, already frozen or yearly limit reached"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /secure/customers/memberships/{subscription_id}/freeze [post]
+func (h *MembershipFreezeHandler) RequestFreeze(w http.ResponseWriter, r *http.Request) {
+	customerID, err := contextUtils.GetUserID(r.Context())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxMedicalNoteSize+1<<20)
+	if parseErr := r.ParseMultipartForm(maxMedicalNoteSize); parseErr != nil {
+		responseHandlers.RespondWithError(w, errLib.New("Invalid form data or file too large (20MB limit)", http.StatusBadRequest))
+		return
+	}
+
+	startDate, parseErr := time.Parse("2006-01-02", r.FormValue("start_date"))
+	if parseErr != nil {
+		responseHandlers.RespondWithError(w, errLib.New("start_date must be a date (YYYY-MM-DD)", http.StatusBadRequest))
+		return
+	}
+	endDate, parseErr := time.Parse("2006-01-02", r.FormValue("end_date"))
+	if parseErr != nil {
+		responseHandlers.RespondWithError(w, errLib.New("end_date must be a date (YYYY-MM-DD)", http.StatusBadRequest))
+		return
+	}
+
+	params := services.RequestFreezeParams{
+		CustomerID:           customerID,
+		StripeSubscriptionID: chi.URLParam(r, "subscription_id"),
+		Reason:               strings.ToLower(strings.TrimSpace(r.FormValue("reason"))),
+		Notes:                strings.TrimSpace(r.FormValue("notes")),
+		StartDate:            startDate,
+		EndDate:              endDate,
+	}
+
+	file, header, fileErr := r.FormFile("medical_note")
+	if fileErr == nil {
+		defer file.Close()
+
+		if !isValidMedicalNoteType(header.Filename) {
+			responseHandlers.RespondWithError(w, errLib.New("Invalid file type. Only pdf, jpg, jpeg, and png are allowed", http.StatusBadRequest))
+			return
+		}
+		params.MedicalNote = file
+		params.MedicalNoteFileName = header.Filename
+	} else if fileErr != http.ErrMissingFile {
+		responseHandlers.RespondWithError(w, errLib.New("Invalid medical note upload", http.StatusBadRequest))
+		return
+	}
+
+	freeze, err := h.freezeService.RequestFreeze(r.Context(), params)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	responseHandlers.RespondWithSuccess(w, customerDto.NewMembershipFreezeResponse(freeze), http.StatusCreated)
+}
+
+// EndFreeze cancels or ends the freeze of the logged-in customer's membership
+// @Summary Cancel or end my membership freeze
+// @Description Cancels a freeze that hasn't started yet, or ends a running freeze so the membership and its billing resume today. Freeze fees and forfeited credits are not returned.
+// @Tags customers
+// @Produce json
+// @Security Bearer
+// @Param subscription_id path string true "Stripe subscription ID of the membership"
+// @Success 200 {object} customer.MembershipFreezeResponse "Freeze cancelled or ended"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Not Found: No scheduled or active freeze"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /secure/customers/memberships/{subscription_id}/freeze [delete]
+func (h *MembershipFreezeHandler) EndFreeze(w http.ResponseWriter, r *http.Request) {
+	customerID, err := contextUtils.GetUserID(r.Context())
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	freeze, err := h.freezeService.EndFreeze(r.Context(), customerID, chi.URLParam(r, "subscription_id"))
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	responseHandlers.RespondWithSuccess(w, customerDto.NewMembershipFreezeResponse(freeze), http.StatusOK)
+}
+
+func isValidMedicalNoteType(filename string) bool {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".pdf", ".jpg", ".jpeg", ".png":
+		return true
+	}
+	return false
+}
@@ -308,3 +308,141 @@ func (h *SuspensionHandler) CollectArrears(w http.ResponseWriter, r *http.Reques
 		"arrears_total": arrearsTotal,
 	}, http.StatusOK)
 }
+
+// ScheduleSuspensionAction schedules a future suspension or unsuspension
+// @Summary Schedule a suspension or unsuspension
+// @Description Schedules a suspension or unsuspension of a user account to run automatically at run_at, with the same effect as the suspend and unsuspend endpoints. Requires admin role.
+// @Tags customers
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "User ID"
+// @Param schedule_body body suspensionDto.ScheduleSuspensionActionRequestDto true "Scheduled action"
+// @Success 201 {object} suspensionDto.ScheduledSuspensionActionResponseDto "Action scheduled"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid input"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Insufficient permissions"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /customers/{id}/suspension-schedule [post]
+func (h *SuspensionHandler) ScheduleSuspensionAction(w http.ResponseWriter, r *http.Request) {
+	userID, parseErr := validators.ParseUUID(chi.URLParam(r, "id"))
+	if parseErr != nil {
+		responseHandlers.RespondWithError(w, parseErr)
+		return
+	}
+
+	scheduledBy, userErr := contextUtils.GetUserID(r.Context())
+	if userErr != nil {
+		responseHandlers.RespondWithError(w, userErr)
+		return
+	}
+
+	var requestDto suspensionDto.ScheduleSuspensionActionRequestDto
+	if err := validators.ParseJSON(r.Body, &requestDto); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	if err := validators.ValidateDto(&requestDto); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	duration, durationErr := requestDto.ParseDuration()
+	if durationErr != nil {
+		responseHandlers.RespondWithError(w, durationErr)
+		return
+	}
+
+	action, err := h.suspensionService.ScheduleSuspensionAction(r.Context(), services.ScheduleSuspensionActionParams{
+		UserID:             userID,
+		ScheduledBy:        scheduledBy,
+		Action:             requestDto.Action,
+		RunAt:              requestDto.RunAt,
+		SuspensionReason:   requestDto.SuspensionReason,
+		SuspensionDuration: duration,
+		ExtendMembership:   requestDto.ExtendMembership,
+		CollectArrears:     requestDto.CollectArrears,
+	})
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	responseHandlers.RespondWithSuccess(w, suspensionDto.NewScheduledSuspensionActionResponse(action), http.StatusCreated)
+}
+
+// ListScheduledSuspensionActions lists a user's scheduled suspensions and unsuspensions
+// @Summary List scheduled suspensions and unsuspensions
+// @Description Lists the suspensions and unsuspensions scheduled for a user, latest first, including ones that already ran, failed or were cancelled. Requires admin role.
+// @Tags customers
+// @Produce json
+// @Security Bearer
+// @Param id path string true "User ID"
+// @Success 200 {array} suspensionDto.ScheduledSuspensionActionResponseDto "Scheduled actions"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid user ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Insufficient permissions"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /customers/{id}/suspension-schedule [get]
+func (h *SuspensionHandler) ListScheduledSuspensionActions(w http.ResponseWriter, r *http.Request) {
+	userID, parseErr := validators.ParseUUID(chi.URLParam(r, "id"))
+	if parseErr != nil {
+		responseHandlers.RespondWithError(w, parseErr)
+		return
+	}
+
+	actions, err := h.suspensionService.ListScheduledSuspensionActions(r.Context(), userID)
+	if err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	response := make([]suspensionDto.ScheduledSuspensionActionResponseDto, 0, len(actions))
+	for _, action := range actions {
+		response = append(response, suspensionDto.NewScheduledSuspensionActionResponse(action))
+	}
+
+	responseHandlers.RespondWithSuccess(w, response, http.StatusOK)
+}
+
+// CancelScheduledSuspensionAction cancels a pending scheduled suspension or unsuspension
+// @Summary Cancel a scheduled suspension or unsuspension
+// @Description Cancels a scheduled action that has not run yet. Requires admin role.
+// @Tags customers
+// @Security Bearer
+// @Param id path string true "User ID"
+// @Param action_id path string true "Scheduled action ID"
+// @Success 204 "Scheduled action cancelled"
+// @Failure 400 {object} map[string]interface{} "Bad Request: Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden: Insufficient permissions"
+// @Failure 404 {object} map[string]interface{} "Not Found: No pending scheduled action"
+// @Failure 500 {object} map[string]interface{} "Internal Server Error"
+// @Router /customers/{id}/suspension-schedule/{action_id} [delete]
+func (h *SuspensionHandler) CancelScheduledSuspensionAction(w http.ResponseWriter, r *http.Request) {
+	userID, parseErr := validators.ParseUUID(chi.URLParam(r, "id"))
+	if parseErr != nil {
+		responseHandlers.RespondWithError(w, parseErr)
+		return
+	}
+
+	actionID, parseErr := validators.ParseUUID(chi.URLParam(r, "action_id"))
+	if parseErr != nil {
+		responseHandlers.RespondWithError(w, parseErr)
+		return
+	}
+
+	cancelledBy, userErr := contextUtils.GetUserID(r.Context())
+	if userErr != nil {
+		responseHandlers.RespondWithError(w, userErr)
+		return
+	}
+
+	if err := h.suspensionService.CancelScheduledSuspensionAction(r.Context(), userID, actionID, cancelledBy); err != nil {
+		responseHandlers.RespondWithError(w, err)
+		return
+	}
+
+	responseHandlers.RespondWithSuccess(w, nil, http.StatusNoContent)
+}
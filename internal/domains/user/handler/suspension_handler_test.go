@@ -0,0 +1,94 @@
+package user
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"api/internal/domains/user/services"
+	contextUtils "api/utils/context"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSuspensionScheduleRequest(method string, params map[string]string, body string, withUser bool) *http.Request {
+	req := httptest.NewRequest(method, "/", strings.NewReader(body))
+
+	routeCtx := chi.NewRouteContext()
+	for key, value := range params {
+		routeCtx.URLParams.Add(key, value)
+	}
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx)
+	if withUser {
+		ctx = context.WithValue(ctx, contextUtils.UserIDKey, uuid.New())
+	}
+	return req.WithContext(ctx)
+}
+
+func TestScheduleSuspensionAction(t *testing.T) {
+	h := &SuspensionHandler{suspensionService: &services.SuspensionService{}}
+	userID := uuid.New().String()
+	future := time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339)
+	past := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+
+	tests := []struct {
+		name     string
+		id       string
+		withUser bool
+		body     string
+		want     int
+	}{
+		{"invalid user ID", "not-a-uuid", true, `{"action":"unsuspend","run_at":"` + future + `"}`, http.StatusBadRequest},
+		{"no user in context", userID, false, `{"action":"unsuspend","run_at":"` + future + `"}`, http.StatusUnauthorized},
+		{"malformed body", userID, true, `{"action":`, http.StatusBadRequest},
+		{"unknown action", userID, true, `{"action":"ban","run_at":"` + future + `"}`, http.StatusBadRequest},
+		{"invalid duration", userID, true, `{"action":"suspend","run_at":"` + future + `","suspension_reason":"Repeated no-shows at sessions","suspension_duration":"a month"}`, http.StatusBadRequest},
+		{"run_at in the past", userID, true, `{"action":"unsuspend","run_at":"` + past + `"}`, http.StatusBadRequest},
+		{"suspension without a reason", userID, true, `{"action":"suspend","run_at":"` + future + `"}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ScheduleSuspensionAction(rec, newSuspensionScheduleRequest(http.MethodPost, map[string]string{"id": tt.id}, tt.body, tt.withUser))
+			assert.Equal(t, tt.want, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func TestListScheduledSuspensionActionsInvalidID(t *testing.T) {
+	h := &SuspensionHandler{suspensionService: &services.SuspensionService{}}
+
+	rec := httptest.NewRecorder()
+	h.ListScheduledSuspensionActions(rec, newSuspensionScheduleRequest(http.MethodGet, map[string]string{"id": "not-a-uuid"}, "", true))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestCancelScheduledSuspensionAction(t *testing.T) {
+	h := &SuspensionHandler{suspensionService: &services.SuspensionService{}}
+	userID := uuid.New().String()
+
+	tests := []struct {
+		name     string
+		params   map[string]string
+		withUser bool
+		want     int
+	}{
+		{"invalid user ID", map[string]string{"id": "not-a-uuid", "action_id": uuid.New().String()}, true, http.StatusBadRequest},
+		{"invalid action ID", map[string]string{"id": userID, "action_id": "not-a-uuid"}, true, http.StatusBadRequest},
+		{"no user in context", map[string]string{"id": userID, "action_id": uuid.New().String()}, false, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.CancelScheduledSuspensionAction(rec, newSuspensionScheduleRequest(http.MethodDelete, tt.params, "", tt.withUser))
+			assert.Equal(t, tt.want, rec.Code)
+		})
+	}
+}
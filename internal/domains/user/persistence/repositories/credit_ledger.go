@@ -0,0 +1,478 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	dbUser "api/internal/domains/user/persistence/sqlc/generated"
+	values "api/internal/domains/user/values"
+	errLib "api/internal/libs/errors"
+
+	"github.com/google/uuid"
+)
+
+// The methods in this file write the credit ledger. Each one changes a customer's
+// credits in a single ledger transaction and leaves users.customer_credits equal to
+// the sum of their available entries. They must run inside ExecuteInTransaction or on
+// a repository from WithTx.
+
+// CreditGrantParams describes credits given to a customer
+type CreditGrantParams struct {
+	CustomerID  uuid.UUID
+	Amount      int32
+	Source      string
+	ExpiresAt   *time.Time // nil never expires
+	Description string
+}
+
+// CreditHoldParams describes credits to set aside for a booking
+type CreditHoldParams struct {
+	CustomerID  uuid.UUID
+	Amount      int32
+	Reference   string
+	EventID     *uuid.UUID
+	Description string
+	ExpiresAt   time.Time
+}
+
+// creditPosting moves amount from one account to another within a ledger transaction
+type creditPosting struct {
+	transactionID uuid.UUID
+	customerID    uuid.UUID
+	entryType     string
+	from          string
+	to            string
+	amount        int32
+	grantID       uuid.NullUUID
+	holdID        uuid.NullUUID
+	reference     string
+	description   string
+}
+
+// GrantCredits adds credits to the customer's balance as a new grant
+func (r *CustomerCreditRepository) GrantCredits(ctx context.Context, p CreditGrantParams) *errLib.CommonError {
+	if err := r.lockCredits(ctx, p.CustomerID); err != nil {
+		return err
+	}
+
+	var expiresAt sql.NullTime
+	if p.ExpiresAt != nil {
+		expiresAt = sql.NullTime{Time: *p.ExpiresAt, Valid: true}
+	}
+	grant, err := r.queries.CreateCreditGrant(ctx, dbUser.CreateCreditGrantParams{
+		CustomerID:  p.CustomerID,
+		Source:      p.Source,
+		Amount:      p.Amount,
+		Description: p.Description,
+		ExpiresAt:   expiresAt,
+	})
+	if err != nil {
+		log.Printf("Error creating credit grant for customer %s: %v", p.CustomerID, err)
+		return errLib.New("Failed to add credits", http.StatusInternalServerError)
+	}
+
+	if postErr := r.post(ctx, creditPosting{
+		transactionID: uuid.New(),
+		customerID:    p.CustomerID,
+		entryType:     values.CreditEntryGrant,
+		from:          values.CreditAccountIssued,
+		to:            values.CreditAccountAvailable,
+		amount:        p.Amount,
+		grantID:       uuid.NullUUID{UUID: grant.ID, Valid: true},
+		description:   p.Description,
+	}); postErr != nil {
+		return postErr
+	}
+	return r.syncCredits(ctx, p.CustomerID)
+}
+
+// DeductCredits spends credits from the customer's grants, soonest to expire first.
+// reference names what the credits paid for so they can be refunded; it may be empty.
+func (r *CustomerCreditRepository) DeductCredits(ctx context.Context, customerID uuid.UUID, amount int32, reference, description string) *errLib.CommonError {
+	if err := r.lockCredits(ctx, customerID); err != nil {
+		return err
+	}
+
+	slices, err := r.takeFromGrants(ctx, customerID, amount)
+	if err != nil {
+		return err
+	}
+
+	transactionID := uuid.New()
+	for _, slice := range slices {
+		if postErr := r.post(ctx, creditPosting{
+			transactionID: transactionID,
+			customerID:    customerID,
+			entryType:     values.CreditEntryConsume,
+			from:          values.CreditAccountAvailable,
+			to:            values.CreditAccountSpent,
+			amount:        slice.Amount,
+			grantID:       uuid.NullUUID{UUID: slice.GrantID, Valid: true},
+			reference:     reference,
+			description:   description,
+		}); postErr != nil {
+			return postErr
+		}
+	}
+	return r.syncCredits(ctx, customerID)
+}
+
+// RefundCredits gives back up to amount credits spent under reference, onto the grants
+// they came from, so refunded credits keep their original expiry. It returns how many
+// credits were refunded, which is less than amount when part was already refunded.
+// Spending recorded before the ledger has no entries to return to; it is refunded as
+// a new grant that never expires.
+func (r *CustomerCreditRepository) RefundCredits(ctx context.Context, customerID uuid.UUID, amount int32, reference, description string) (int32, *errLib.CommonError) {
+	if err := r.lockCredits(ctx, customerID); err != nil {
+		return 0, err
+	}
+
+	ref := sql.NullString{String: reference, Valid: reference != ""}
+	spent, err := r.queries.GetReferenceSpentByGrant(ctx, dbUser.GetReferenceSpentByGrantParams{
+		CustomerID: customerID,
+		Reference:  ref,
+	})
+	if err != nil {
+		log.Printf("Error getting credits spent under %q by customer %s: %v", reference, customerID, err)
+		return 0, errLib.New("Failed to refund credits", http.StatusInternalServerError)
+	}
+
+	if len(spent) == 0 {
+		hasEntries, dbErr := r.queries.HasReferenceLedgerEntries(ctx, dbUser.HasReferenceLedgerEntriesParams{
+			CustomerID: customerID,
+			Reference:  ref,
+		})
+		if dbErr != nil {
+			log.Printf("Error checking ledger entries under %q for customer %s: %v", reference, customerID, dbErr)
+			return 0, errLib.New("Failed to refund credits", http.StatusInternalServerError)
+		}
+		if hasEntries {
+			return 0, nil // already refunded in full
+		}
+		return amount, r.refundUnrecorded(ctx, customerID, amount, reference, description)
+	}
+
+	refunded := int32(0)
+	transactionID := uuid.New()
+	for _, s := range spent {
+		give := min(s.Spent, amount-refunded)
+		if give <= 0 {
+			break
+		}
+		if returnErr := r.returnToGrant(ctx, s.GrantID.UUID, give); returnErr != nil {
+			return 0, returnErr
+		}
+		if postErr := r.post(ctx, creditPosting{
+			transactionID: transactionID,
+			customerID:    customerID,
+			entryType:     values.CreditEntryRefund,
+			from:          values.CreditAccountSpent,
+			to:            values.CreditAccountAvailable,
+			amount:        give,
+			grantID:       s.GrantID,
+			reference:     reference,
+			description:   description,
+		}); postErr != nil {
+			return 0, postErr
+		}
+		refunded += give
+	}
+	return refunded, r.syncCredits(ctx, customerID)
+}
+
+// refundUnrecorded refunds spending the ledger has no record of as a new grant
+func (r *CustomerCreditRepository) refundUnrecorded(ctx context.Context, customerID uuid.UUID, amount int32, reference, description string) *errLib.CommonError {
+	grant, err := r.queries.CreateCreditGrant(ctx, dbUser.CreateCreditGrantParams{
+		CustomerID:  customerID,
+		Source:      values.CreditSourceRefund,
+		Amount:      amount,
+		Description: description,
+	})
+	if err != nil {
+		log.Printf("Error creating refund grant for customer %s: %v", customerID, err)
+		return errLib.New("Failed to refund credits", http.StatusInternalServerError)
+	}
+
+	if postErr := r.post(ctx, creditPosting{
+		transactionID: uuid.New(),
+		customerID:    customerID,
+		entryType:     values.CreditEntryRefund,
+		from:          values.CreditAccountIssued,
+		to:            values.CreditAccountAvailable,
+		amount:        amount,
+		grantID:       uuid.NullUUID{UUID: grant.ID, Valid: true},
+		reference:     reference,
+		description:   description,
+	}); postErr != nil {
+		return postErr
+	}
+	return r.syncCredits(ctx, customerID)
+}
+
+// HoldCredits sets credits aside for a booking that is not confirmed yet and returns
+// the hold's ID. The credits leave the balance straight away.
+func (r *CustomerCreditRepository) HoldCredits(ctx context.Context, p CreditHoldParams) (uuid.UUID, *errLib.CommonError) {
+	if err := r.lockCredits(ctx, p.CustomerID); err != nil {
+		return uuid.Nil, err
+	}
+
+	slices, err := r.takeFromGrants(ctx, p.CustomerID, p.Amount)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	var eventID uuid.NullUUID
+	if p.EventID != nil {
+		eventID = uuid.NullUUID{UUID: *p.EventID, Valid: true}
+	}
+	hold, dbErr := r.queries.CreateCreditHold(ctx, dbUser.CreateCreditHoldParams{
+		CustomerID:  p.CustomerID,
+		Amount:      p.Amount,
+		Reference:   p.Reference,
+		EventID:     eventID,
+		Description: p.Description,
+		ExpiresAt:   p.ExpiresAt,
+	})
+	if dbErr != nil {
+		log.Printf("Error creating credit hold for customer %s: %v", p.CustomerID, dbErr)
+		return uuid.Nil, errLib.New("Failed to hold credits", http.StatusInternalServerError)
+	}
+
+	transactionID := uuid.New()
+	for _, slice := range slices {
+		if postErr := r.post(ctx, creditPosting{
+			transactionID: transactionID,
+			customerID:    p.CustomerID,
+			entryType:     values.CreditEntryHold,
+			from:          values.CreditAccountAvailable,
+			to:            values.CreditAccountHeld,
+			amount:        slice.Amount,
+			grantID:       uuid.NullUUID{UUID: slice.GrantID, Valid: true},
+			holdID:        uuid.NullUUID{UUID: hold.ID, Valid: true},
+			reference:     p.Reference,
+			description:   p.Description,
+		}); postErr != nil {
+			return uuid.Nil, postErr
+		}
+	}
+	return hold.ID, r.syncCredits(ctx, p.CustomerID)
+}
+
+// CaptureHold spends the credits of a hold. It reports false when the hold was
+// captured already, in which case nothing changes.
+func (r *CustomerCreditRepository) CaptureHold(ctx context.Context, holdID uuid.UUID) (dbUser.UsersCreditHold, bool, *errLib.CommonError) {
+	return r.settleHold(ctx, holdID, values.CreditHoldCaptured)
+}
+
+// ReleaseHold puts the credits of a hold back on the grants they came from. It reports
+// false when the hold was released already, in which case nothing changes.
+func (r *CustomerCreditRepository) ReleaseHold(ctx context.Context, holdID uuid.UUID) (dbUser.UsersCreditHold, bool, *errLib.CommonError) {
+	return r.settleHold(ctx, holdID, values.CreditHoldReleased)
+}
+
+func (r *CustomerCreditRepository) settleHold(ctx context.Context, holdID uuid.UUID, status string) (dbUser.UsersCreditHold, bool, *errLib.CommonError) {
+	hold, err := r.queries.GetCreditHoldForUpdate(ctx, holdID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return hold, false, errLib.New("Credit hold not found", http.StatusNotFound)
+		}
+		log.Printf("Error getting credit hold %s: %v", holdID, err)
+		return hold, false, errLib.New("Failed to get credit hold", http.StatusInternalServerError)
+	}
+	if hold.Status == status {
+		return hold, false, nil
+	}
+	if hold.Status != values.CreditHoldHeld {
+		return hold, false, errLib.New("Credit hold is already "+hold.Status, http.StatusConflict)
+	}
+
+	if lockErr := r.lockCredits(ctx, hold.CustomerID); lockErr != nil {
+		return hold, false, lockErr
+	}
+
+	held, err := r.queries.GetCreditHoldGrantAmounts(ctx, uuid.NullUUID{UUID: holdID, Valid: true})
+	if err != nil {
+		log.Printf("Error getting grants of credit hold %s: %v", holdID, err)
+		return hold, false, errLib.New("Failed to get credit hold", http.StatusInternalServerError)
+	}
+
+	entryType, to := values.CreditEntryCapture, values.CreditAccountSpent
+	if status == values.CreditHoldReleased {
+		entryType, to = values.CreditEntryRelease, values.CreditAccountAvailable
+	}
+
+	transactionID := uuid.New()
+	for _, h := range held {
+		if status == values.CreditHoldReleased {
+			if returnErr := r.returnToGrant(ctx, h.GrantID.UUID, h.Held); returnErr != nil {
+				return hold, false, returnErr
+			}
+		}
+		if postErr := r.post(ctx, creditPosting{
+			transactionID: transactionID,
+			customerID:    hold.CustomerID,
+			entryType:     entryType,
+			from:          values.CreditAccountHeld,
+			to:            to,
+			amount:        h.Held,
+			grantID:       h.GrantID,
+			holdID:        uuid.NullUUID{UUID: holdID, Valid: true},
+			reference:     hold.Reference,
+			description:   hold.Description,
+		}); postErr != nil {
+			return hold, false, postErr
+		}
+	}
+
+	var rows int64
+	if status == values.CreditHoldCaptured {
+		rows, err = r.queries.CaptureCreditHold(ctx, holdID)
+	} else {
+		rows, err = r.queries.ReleaseCreditHold(ctx, holdID)
+	}
+	if err != nil || rows == 0 {
+		log.Printf("Error marking credit hold %s %s: %v", holdID, status, err)
+		return hold, false, errLib.New("Failed to update credit hold", http.StatusInternalServerError)
+	}
+	hold.Status = status
+
+	return hold, true, r.syncCredits(ctx, hold.CustomerID)
+}
+
+// ExpireLapsedGrants moves the credits left on the customer's grants past their expiry
+// out of the balance. It returns how many grants lapsed.
+func (r *CustomerCreditRepository) ExpireLapsedGrants(ctx context.Context, customerID uuid.UUID) (int, *errLib.CommonError) {
+	if err := r.lockCredits(ctx, customerID); err != nil {
+		return 0, err
+	}
+	return r.expireLapsedGrants(ctx, customerID)
+}
+
+// expireLapsedGrants expects the customer's credits to be locked already
+func (r *CustomerCreditRepository) expireLapsedGrants(ctx context.Context, customerID uuid.UUID) (int, *errLib.CommonError) {
+	grants, err := r.queries.GetLapsedCreditGrantsForUpdate(ctx, uuid.NullUUID{UUID: customerID, Valid: true})
+	if err != nil {
+		log.Printf("Error getting lapsed credit grants of customer %s: %v", customerID, err)
+		return 0, errLib.New("Failed to expire credits", http.StatusInternalServerError)
+	}
+	if len(grants) == 0 {
+		return 0, nil
+	}
+
+	transactionID := uuid.New()
+	for _, g := range grants {
+		if takeErr := r.takeFromGrant(ctx, g.ID, g.Remaining); takeErr != nil {
+			return 0, takeErr
+		}
+		if postErr := r.post(ctx, creditPosting{
+			transactionID: transactionID,
+			customerID:    customerID,
+			entryType:     values.CreditEntryExpire,
+			from:          values.CreditAccountAvailable,
+			to:            values.CreditAccountExpired,
+			amount:        g.Remaining,
+			grantID:       uuid.NullUUID{UUID: g.ID, Valid: true},
+			description:   "Credits expired: " + g.Description,
+		}); postErr != nil {
+			return 0, postErr
+		}
+	}
+	return len(grants), r.syncCredits(ctx, customerID)
+}
+
+// takeFromGrants expires lapsed grants, then takes amount from the customer's
+// spendable grants in the order credits are used
+func (r *CustomerCreditRepository) takeFromGrants(ctx context.Context, customerID uuid.UUID, amount int32) ([]values.CreditSlice, *errLib.CommonError) {
+	if amount <= 0 {
+		return nil, errLib.New("Credit amount must be positive", http.StatusBadRequest)
+	}
+	if _, err := r.expireLapsedGrants(ctx, customerID); err != nil {
+		return nil, err
+	}
+
+	grants, err := r.queries.GetSpendableCreditGrantsForUpdate(ctx, customerID)
+	if err != nil {
+		log.Printf("Error getting credit grants of customer %s: %v", customerID, err)
+		return nil, errLib.New("Failed to deduct credits", http.StatusInternalServerError)
+	}
+	open := make([]values.CreditSlice, len(grants))
+	for i, g := range grants {
+		open[i] = values.CreditSlice{GrantID: g.ID, Amount: g.Remaining}
+	}
+
+	slices := values.TakeFIFO(open, amount)
+	if slices == nil {
+		return nil, errLib.New("Insufficient credits", http.StatusBadRequest)
+	}
+	for _, slice := range slices {
+		if takeErr := r.takeFromGrant(ctx, slice.GrantID, slice.Amount); takeErr != nil {
+			return nil, takeErr
+		}
+	}
+	return slices, nil
+}
+
+func (r *CustomerCreditRepository) takeFromGrant(ctx context.Context, grantID uuid.UUID, amount int32) *errLib.CommonError {
+	rows, err := r.queries.TakeFromCreditGrant(ctx, dbUser.TakeFromCreditGrantParams{ID: grantID, Amount: amount})
+	if err != nil || rows == 0 {
+		log.Printf("Error taking %d credits from grant %s: %v", amount, grantID, err)
+		return errLib.New("Failed to deduct credits", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (r *CustomerCreditRepository) returnToGrant(ctx context.Context, grantID uuid.UUID, amount int32) *errLib.CommonError {
+	rows, err := r.queries.ReturnToCreditGrant(ctx, dbUser.ReturnToCreditGrantParams{ID: grantID, Amount: amount})
+	if err != nil || rows == 0 {
+		log.Printf("Error returning %d credits to grant %s: %v", amount, grantID, err)
+		return errLib.New("Failed to return credits", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// post writes both sides of a movement
+func (r *CustomerCreditRepository) post(ctx context.Context, p creditPosting) *errLib.CommonError {
+	reference := sql.NullString{String: p.reference, Valid: p.reference != ""}
+	for _, side := range []struct {
+		account string
+		amount  int32
+	}{{p.from, -p.amount}, {p.to, p.amount}} {
+		if err := r.queries.InsertCreditLedgerEntry(ctx, dbUser.InsertCreditLedgerEntryParams{
+			TransactionID: p.transactionID,
+			CustomerID:    p.customerID,
+			Account:       side.account,
+			EntryType:     p.entryType,
+			Amount:        side.amount,
+			GrantID:       p.grantID,
+			HoldID:        p.holdID,
+			Reference:     reference,
+			Description:   p.description,
+		}); err != nil {
+			log.Printf("Error writing %s ledger entry for customer %s: %v", p.entryType, p.customerID, err)
+			return errLib.New("Failed to record credit movement", http.StatusInternalServerError)
+		}
+	}
+	return nil
+}
+
+// lockCredits locks the customer's balance so one ledger transaction is written at a time
+func (r *CustomerCreditRepository) lockCredits(ctx context.Context, customerID uuid.UUID) *errLib.CommonError {
+	if _, err := r.queries.LockCustomerCredits(ctx, customerID); err != nil {
+		log.Printf("Error locking credits of customer %s: %v", customerID, err)
+		return errLib.New("Failed to update customer credits", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// syncCredits sets the cached balance to what the ledger says
+func (r *CustomerCreditRepository) syncCredits(ctx context.Context, customerID uuid.UUID) *errLib.CommonError {
+	if _, err := r.queries.SyncCustomerCredits(ctx, customerID); err != nil {
+		log.Printf("Error syncing credits of customer %s: %v", customerID, err)
+		return errLib.New("Failed to update customer credits", http.StatusInternalServerError)
+	}
+	return nil
+}
@@ -0,0 +1,298 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	dbUser "api/internal/domains/user/persistence/sqlc/generated"
+	values "api/internal/domains/user/values"
+	errLib "api/internal/libs/errors"
+
+	"github.com/google/uuid"
+)
+
+// ListCreditGrants retrieves the customer's credit grants, those with credits left first
+func (r *CustomerCreditRepository) ListCreditGrants(ctx context.Context, customerID uuid.UUID, openOnly bool, limit, offset int32) ([]values.CreditGrant, *errLib.CommonError) {
+	rows, err := r.queries.ListCustomerCreditGrants(ctx, dbUser.ListCustomerCreditGrantsParams{
+		CustomerID: customerID,
+		Limit:      limit,
+		Offset:     offset,
+		OpenOnly:   openOnly,
+	})
+	if err != nil {
+		log.Printf("Error listing credit grants of customer %s: %v", customerID, err)
+		return nil, errLib.New("Failed to retrieve credit grants", http.StatusInternalServerError)
+	}
+
+	grants := make([]values.CreditGrant, len(rows))
+	for i, row := range rows {
+		grants[i] = values.CreditGrant{
+			ID:          row.ID,
+			Source:      row.Source,
+			Amount:      row.Amount,
+			Remaining:   row.Remaining,
+			Description: row.Description,
+			CreatedAt:   row.CreatedAt,
+		}
+		if row.ExpiresAt.Valid {
+			grants[i].ExpiresAt = &row.ExpiresAt.Time
+		}
+	}
+	return grants, nil
+}
+
+// ListLedgerEntries retrieves the customer's credit ledger entries, newest first
+func (r *CustomerCreditRepository) ListLedgerEntries(ctx context.Context, customerID uuid.UUID, limit, offset int32) ([]values.CreditLedgerEntry, *errLib.CommonError) {
+	rows, err := r.queries.ListCustomerCreditLedgerEntries(ctx, dbUser.ListCustomerCreditLedgerEntriesParams{
+		CustomerID: customerID,
+		Limit:      limit,
+		Offset:     offset,
+	})
+	if err != nil {
+		log.Printf("Error listing credit ledger of customer %s: %v", customerID, err)
+		return nil, errLib.New("Failed to retrieve credit ledger", http.StatusInternalServerError)
+	}
+
+	entries := make([]values.CreditLedgerEntry, len(rows))
+	for i, row := range rows {
+		entries[i] = values.CreditLedgerEntry{
+			ID:            row.ID,
+			TransactionID: row.TransactionID,
+			Account:       row.Account,
+			EntryType:     row.EntryType,
+			Amount:        row.Amount,
+			Description:   row.Description,
+			CreatedAt:     row.CreatedAt,
+		}
+		if row.GrantID.Valid {
+			entries[i].GrantID = &row.GrantID.UUID
+		}
+		if row.HoldID.Valid {
+			entries[i].HoldID = &row.HoldID.UUID
+		}
+		if row.Reference.Valid {
+			entries[i].Reference = &row.Reference.String
+		}
+	}
+	return entries, nil
+}
+
+// GetLapsedCreditGrantCustomerIDs retrieves customers holding credits past their expiry
+func (r *CustomerCreditRepository) GetLapsedCreditGrantCustomerIDs(ctx context.Context) ([]uuid.UUID, *errLib.CommonError) {
+	ids, err := r.queries.GetLapsedCreditGrantCustomerIDs(ctx)
+	if err != nil {
+		log.Printf("Error getting customers with lapsed credit grants: %v", err)
+		return nil, errLib.New("Failed to get lapsed credit grants", http.StatusInternalServerError)
+	}
+	return ids, nil
+}
+
+// LapsedCreditHold is a hold past its expiry. Booked is true when the customer holds a
+// seat in the event the hold was for.
+type LapsedCreditHold struct {
+	ID     uuid.UUID
+	Booked bool
+}
+
+// GetLapsedCreditHolds retrieves up to limit holds still open at before
+func (r *CustomerCreditRepository) GetLapsedCreditHolds(ctx context.Context, before time.Time, limit int32) ([]LapsedCreditHold, *errLib.CommonError) {
+	rows, err := r.queries.GetLapsedCreditHolds(ctx, dbUser.GetLapsedCreditHoldsParams{
+		ExpiresAt: before,
+		Limit:     limit,
+	})
+	if err != nil {
+		log.Printf("Error getting lapsed credit holds: %v", err)
+		return nil, errLib.New("Failed to get lapsed credit holds", http.StatusInternalServerError)
+	}
+
+	holds := make([]LapsedCreditHold, len(rows))
+	for i, row := range rows {
+		holds[i] = LapsedCreditHold{ID: row.ID, Booked: row.Booked}
+	}
+	return holds, nil
+}
+
+// GetPendingFailedRefundIDs retrieves up to limit queued credit refunds, oldest first
+func (r *CustomerCreditRepository) GetPendingFailedRefundIDs(ctx context.Context, limit int32) ([]uuid.UUID, *errLib.CommonError) {
+	ids, err := r.queries.GetPendingFailedRefundIDs(ctx, limit)
+	if err != nil {
+		log.Printf("Error getting pending failed refunds: %v", err)
+		return nil, errLib.New("Failed to get failed refunds", http.StatusInternalServerError)
+	}
+	return ids, nil
+}
+
+// GetPendingFailedRefundForUpdate locks a queued credit refund. It returns nil when the
+// refund is no longer pending or another run holds it.
+func (r *CustomerCreditRepository) GetPendingFailedRefundForUpdate(ctx context.Context, id uuid.UUID) (*dbUser.PaymentFailedRefund, *errLib.CommonError) {
+	refund, err := r.queries.GetPendingFailedRefundForUpdate(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		log.Printf("Error getting failed refund %s: %v", id, err)
+		return nil, errLib.New("Failed to get failed refund", http.StatusInternalServerError)
+	}
+	return &refund, nil
+}
+
+// ResolveFailedRefund marks a queued credit refund done
+func (r *CustomerCreditRepository) ResolveFailedRefund(ctx context.Context, id uuid.UUID) *errLib.CommonError {
+	if err := r.queries.ResolveFailedRefund(ctx, id); err != nil {
+		log.Printf("Error resolving failed refund %s: %v", id, err)
+		return errLib.New("Failed to resolve failed refund", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// RecordFailedRefundAttempt counts a failed retry of a queued credit refund and returns
+// its status, which becomes 'failed' once maxAttempts is reached
+func (r *CustomerCreditRepository) RecordFailedRefundAttempt(ctx context.Context, id uuid.UUID, message string, maxAttempts int32) (string, *errLib.CommonError) {
+	status, err := r.queries.RecordFailedRefundAttempt(ctx, dbUser.RecordFailedRefundAttemptParams{
+		ID:           id,
+		ErrorMessage: sql.NullString{String: message, Valid: message != ""},
+		MaxAttempts:  maxAttempts,
+	})
+	if err != nil {
+		log.Printf("Error recording attempt of failed refund %s: %v", id, err)
+		return "", errLib.New("Failed to update failed refund", http.StatusInternalServerError)
+	}
+	return status.String, nil
+}
+
+// FindCreditDrift compares every customer's cached balance, grants and holds with their
+// ledger, and every ledger transaction with zero, and records the differences as
+// reconciliation issues. It returns how many new issues were recorded.
+func (r *CustomerCreditRepository) FindCreditDrift(ctx context.Context) (int, *errLib.CommonError) {
+	drift, err := r.queries.GetCreditDrift(ctx)
+	if err != nil {
+		log.Printf("Error comparing credit balances with the ledger: %v", err)
+		return 0, errLib.New("Failed to reconcile credits", http.StatusInternalServerError)
+	}
+
+	found := 0
+	record := func(customerID uuid.UUID, kind string, transactionID uuid.NullUUID, expected, actual int32) *errLib.CommonError {
+		rows, dbErr := r.queries.InsertCreditReconciliationIssue(ctx, dbUser.InsertCreditReconciliationIssueParams{
+			CustomerID:    customerID,
+			Kind:          kind,
+			TransactionID: transactionID,
+			Expected:      expected,
+			Actual:        actual,
+		})
+		if dbErr != nil {
+			log.Printf("Error recording %s credit issue for customer %s: %v", kind, customerID, dbErr)
+			return errLib.New("Failed to record credit reconciliation issue", http.StatusInternalServerError)
+		}
+		found += int(rows)
+		return nil
+	}
+
+	for _, d := range drift {
+		if d.CachedBalance != d.LedgerAvailable {
+			if recordErr := record(d.CustomerID, values.CreditIssueBalance, uuid.NullUUID{}, d.LedgerAvailable, d.CachedBalance); recordErr != nil {
+				return found, recordErr
+			}
+		}
+		if d.GrantsRemaining != d.LedgerAvailable {
+			if recordErr := record(d.CustomerID, values.CreditIssueGrants, uuid.NullUUID{}, d.LedgerAvailable, d.GrantsRemaining); recordErr != nil {
+				return found, recordErr
+			}
+		}
+		if d.HoldsOpen != d.LedgerHeld {
+			if recordErr := record(d.CustomerID, values.CreditIssueHolds, uuid.NullUUID{}, d.LedgerHeld, d.HoldsOpen); recordErr != nil {
+				return found, recordErr
+			}
+		}
+	}
+
+	unbalanced, err := r.queries.GetUnbalancedCreditTransactions(ctx)
+	if err != nil {
+		log.Printf("Error checking credit ledger transactions: %v", err)
+		return found, errLib.New("Failed to reconcile credits", http.StatusInternalServerError)
+	}
+	for _, u := range unbalanced {
+		transactionID := uuid.NullUUID{UUID: u.TransactionID, Valid: true}
+		if recordErr := record(u.CustomerID, values.CreditIssueTransaction, transactionID, 0, u.Imbalance); recordErr != nil {
+			return found, recordErr
+		}
+	}
+
+	return found, nil
+}
+
+// ListReconciliationIssues retrieves credit reconciliation issues, newest first
+func (r *CustomerCreditRepository) ListReconciliationIssues(ctx context.Context, includeResolved bool, limit, offset int32) ([]values.CreditReconciliationIssue, *errLib.CommonError) {
+	rows, err := r.queries.ListCreditReconciliationIssues(ctx, dbUser.ListCreditReconciliationIssuesParams{
+		Limit:           limit,
+		Offset:          offset,
+		IncludeResolved: includeResolved,
+	})
+	if err != nil {
+		log.Printf("Error listing credit reconciliation issues: %v", err)
+		return nil, errLib.New("Failed to retrieve credit reconciliation issues", http.StatusInternalServerError)
+	}
+
+	issues := make([]values.CreditReconciliationIssue, len(rows))
+	for i, row := range rows {
+		issues[i] = mapReconciliationIssue(dbUser.UsersCreditReconciliationIssue{
+			ID:            row.ID,
+			CustomerID:    row.CustomerID,
+			Kind:          row.Kind,
+			TransactionID: row.TransactionID,
+			Expected:      row.Expected,
+			Actual:        row.Actual,
+			DetectedAt:    row.DetectedAt,
+			ResolvedBy:    row.ResolvedBy,
+			ResolvedAt:    row.ResolvedAt,
+			Resolution:    row.Resolution,
+		})
+		issues[i].FirstName = row.FirstName
+		issues[i].LastName = row.LastName
+	}
+	return issues, nil
+}
+
+// ResolveReconciliationIssue records how staff settled an open reconciliation issue
+func (r *CustomerCreditRepository) ResolveReconciliationIssue(ctx context.Context, id, resolvedBy uuid.UUID, resolution string) (values.CreditReconciliationIssue, *errLib.CommonError) {
+	row, err := r.queries.ResolveCreditReconciliationIssue(ctx, dbUser.ResolveCreditReconciliationIssueParams{
+		ID:         id,
+		ResolvedBy: uuid.NullUUID{UUID: resolvedBy, Valid: true},
+		Resolution: sql.NullString{String: resolution, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return values.CreditReconciliationIssue{}, errLib.New("Open credit reconciliation issue not found", http.StatusNotFound)
+		}
+		log.Printf("Error resolving credit reconciliation issue %s: %v", id, err)
+		return values.CreditReconciliationIssue{}, errLib.New("Failed to resolve credit reconciliation issue", http.StatusInternalServerError)
+	}
+	return mapReconciliationIssue(row), nil
+}
+
+func mapReconciliationIssue(row dbUser.UsersCreditReconciliationIssue) values.CreditReconciliationIssue {
+	issue := values.CreditReconciliationIssue{
+		ID:         row.ID,
+		CustomerID: row.CustomerID,
+		Kind:       row.Kind,
+		Expected:   row.Expected,
+		Actual:     row.Actual,
+		DetectedAt: row.DetectedAt,
+	}
+	if row.TransactionID.Valid {
+		issue.TransactionID = &row.TransactionID.UUID
+	}
+	if row.ResolvedBy.Valid {
+		issue.ResolvedBy = &row.ResolvedBy.UUID
+	}
+	if row.ResolvedAt.Valid {
+		issue.ResolvedAt = &row.ResolvedAt.Time
+	}
+	if row.Resolution.Valid {
+		issue.Resolution = &row.Resolution.String
+	}
+	return issue
+}
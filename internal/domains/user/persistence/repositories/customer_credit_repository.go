@@ -4,6 +4,7 @@ import (
 	"api/internal/di"
 	dbUser "api/internal/domains/user/persistence/sqlc/generated"
 	errLib "api/internal/libs/errors"
+	"api/utils/timezone"
 	"context"
 	"database/sql"
 	"errors"
@@ -16,6 +17,7 @@ import (
 type CustomerCreditRepository struct {
 	queries *dbUser.Queries
 	db      *sql.DB
+	tx      *sql.Tx
 }
 
 func NewCustomerCreditRepository(container *di.Container) *CustomerCreditRepository {
@@ -30,9 +32,15 @@ func (r *CustomerCreditRepository) WithTx(tx *sql.Tx) *CustomerCreditRepository
 	return &CustomerCreditRepository{
 		queries: r.queries.WithTx(tx),
 		db:      r.db,
+		tx:      tx,
 	}
 }
 
+// Tx returns the transaction the repository runs in, nil outside ExecuteInTransaction
+func (r *CustomerCreditRepository) Tx() *sql.Tx {
+	return r.tx
+}
+
 // GetCustomerCredits retrieves the current credit balance for a customer
 func (r *CustomerCreditRepository) GetCustomerCredits(ctx context.Context, customerID uuid.UUID) (int32, *errLib.CommonError) {
 	credits, err := r.queries.GetCustomerCredits(ctx, customerID)
@@ -47,27 +55,6 @@ func (r *CustomerCreditRepository) GetCustomerCredits(ctx context.Context, custo
 	return credits, nil
 }
 
-// EnsureCustomerCreditsExist ensures a credit record exists for the customer, creating one with 0 balance if needed
-func (r *CustomerCreditRepository) EnsureCustomerCreditsExist(ctx context.Context, customerID uuid.UUID) *errLib.CommonError {
-	_, err := r.queries.GetCustomerCredits(ctx, customerID)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			// Create record with 0 balance
-			if createErr := r.queries.CreateCustomerCredits(ctx, dbUser.CreateCustomerCreditsParams{
-				CustomerID: customerID,
-				Credits:    0,
-			}); createErr != nil {
-				log.Printf("Error creating customer credits record: %v", createErr)
-				return errLib.New("Failed to initialize customer credits", http.StatusInternalServerError)
-			}
-			return nil
-		}
-		log.Printf("Error checking customer credits: %v", err)
-		return errLib.New("Failed to check customer credits", http.StatusInternalServerError)
-	}
-	return nil
-}
-
 // HasSufficientCredits checks if customer has enough credits for a transaction
 func (r *CustomerCreditRepository) HasSufficientCredits(ctx context.Context, customerID uuid.UUID, amount int32) (bool, *errLib.CommonError) {
 	result, err := r.queries.CheckCustomerHasSufficientCredits(ctx, dbUser.CheckCustomerHasSufficientCreditsParams{
@@ -85,38 +72,6 @@ func (r *CustomerCreditRepository) HasSufficientCredits(ctx context.Context, cus
 	return result, nil
 }
 
-// DeductCredits removes credits from customer's account (only if they have sufficient balance)
-func (r *CustomerCreditRepository) DeductCredits(ctx context.Context, customerID uuid.UUID, amount int32) *errLib.CommonError {
-	rowsAffected, err := r.queries.DeductCredits(ctx, dbUser.DeductCreditsParams{
-		CustomerID: customerID,
-		Credits:    amount,
-	})
-	if err != nil {
-		log.Printf("Error deducting credits: %v", err)
-		return errLib.New("Failed to deduct credits", http.StatusInternalServerError)
-	}
-	if rowsAffected == 0 {
-		return errLib.New("Insufficient credits", http.StatusBadRequest)
-	}
-	return nil
-}
-
-// RefundCredits adds credits back to customer's account
-func (r *CustomerCreditRepository) RefundCredits(ctx context.Context, customerID uuid.UUID, amount int32) *errLib.CommonError {
-	rowsAffected, err := r.queries.RefundCredits(ctx, dbUser.RefundCreditsParams{
-		CustomerID: customerID,
-		Credits:    amount,
-	})
-	if err != nil {
-		log.Printf("Error refunding credits: %v", err)
-		return errLib.New("Failed to refund credits", http.StatusInternalServerError)
-	}
-	if rowsAffected == 0 {
-		return errLib.New("Customer credits record not found", http.StatusNotFound)
-	}
-	return nil
-}
-
 // LogCreditTransaction records a credit transaction for audit purposes
 func (r *CustomerCreditRepository) LogCreditTransaction(ctx context.Context, customerID uuid.UUID, amount int32, transactionType dbUser.CreditTransactionType, eventID *uuid.UUID, description string) *errLib.CommonError {
 	var eventIDParam uuid.NullUUID
@@ -186,6 +141,16 @@ func (r *CustomerCreditRepository) GetEventCreditCost(ctx context.Context, event
 	return &cost, nil
 }
 
+// GetEventTimezone returns the timezone of the facility hosting an event.
+// Falls back to the default facility timezone if the event has no usable location.
+func (r *CustomerCreditRepository) GetEventTimezone(ctx context.Context, eventID uuid.UUID) *time.Location {
+	name, err := r.queries.GetEventTimezone(ctx, eventID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("Error getting timezone for event %s: %v", eventID, err)
+	}
+	return timezone.Load(name)
+}
+
 // UpdateEventCreditCost updates the credit cost for an event (admin function)
 func (r *CustomerCreditRepository) UpdateEventCreditCost(ctx context.Context, eventID uuid.UUID, creditCost *int32) *errLib.CommonError {
 	var costParam sql.NullInt32
@@ -234,18 +199,8 @@ func (r *CustomerCreditRepository) ExecuteInTransaction(ctx context.Context, fn
 	return nil
 }
 
-// UpdateWeeklyUsage updates the weekly credit usage tracking
-func (r *CustomerCreditRepository) UpdateWeeklyUsage(ctx context.Context, customerID uuid.UUID, creditsUsed int32) *errLib.CommonError {
-	// Calculate current week start (Monday of the current week)
-	now := time.Now()
-	weekday := now.Weekday()
-	daysSinceMonday := int(weekday-time.Monday) % 7
-	if daysSinceMonday < 0 {
-		daysSinceMonday += 7
-	}
-	monday := now.AddDate(0, 0, -daysSinceMonday)
-	weekStart := time.Date(monday.Year(), monday.Month(), monday.Day(), 0, 0, 0, 0, monday.Location())
-
+// UpdateWeeklyUsage updates the weekly credit usage tracking for the week starting at weekStart
+func (r *CustomerCreditRepository) UpdateWeeklyUsage(ctx context.Context, customerID uuid.UUID, creditsUsed int32, weekStart time.Time) *errLib.CommonError {
 	err := r.queries.UpdateWeeklyCreditsUsed(ctx, dbUser.UpdateWeeklyCreditsUsedParams{
 		CustomerID:    customerID,
 		WeekStartDate: weekStart,
@@ -260,17 +215,7 @@ func (r *CustomerCreditRepository) UpdateWeeklyUsage(ctx context.Context, custom
 }
 
 // ReduceWeeklyUsage reduces the weekly credit usage when credits are refunded
-func (r *CustomerCreditRepository) ReduceWeeklyUsage(ctx context.Context, customerID uuid.UUID, creditsToReduce int32) *errLib.CommonError {
-	// Calculate current week start (Monday of the current week)
-	now := time.Now()
-	weekday := now.Weekday()
-	daysSinceMonday := int(weekday-time.Monday) % 7
-	if daysSinceMonday < 0 {
-		daysSinceMonday += 7
-	}
-	monday := now.AddDate(0, 0, -daysSinceMonday)
-	weekStart := time.Date(monday.Year(), monday.Month(), monday.Day(), 0, 0, 0, 0, monday.Location())
-
+func (r *CustomerCreditRepository) ReduceWeeklyUsage(ctx context.Context, customerID uuid.UUID, creditsToReduce int32, weekStart time.Time) *errLib.CommonError {
 	err := r.queries.ReduceWeeklyCreditsUsed(ctx, dbUser.ReduceWeeklyCreditsUsedParams{
 		CustomerID:    customerID,
 		WeekStartDate: weekStart,
@@ -298,17 +243,7 @@ func (r *CustomerCreditRepository) IsCustomerEnrolledInEvent(ctx context.Context
 }
 
 // CanUseCreditsWithinWeeklyLimit checks if customer can use credits without exceeding weekly limit
-func (r *CustomerCreditRepository) CanUseCreditsWithinWeeklyLimit(ctx context.Context, customerID uuid.UUID, creditsToUse int32) (bool, *errLib.CommonError) {
-	// Calculate current week start (Monday of the current week)
-	now := time.Now()
-	weekday := now.Weekday()
-	daysSinceMonday := int(weekday-time.Monday) % 7
-	if daysSinceMonday < 0 {
-		daysSinceMonday += 7
-	}
-	monday := now.AddDate(0, 0, -daysSinceMonday)
-	weekStart := time.Date(monday.Year(), monday.Month(), monday.Day(), 0, 0, 0, 0, monday.Location())
-
+func (r *CustomerCreditRepository) CanUseCreditsWithinWeeklyLimit(ctx context.Context, customerID uuid.UUID, creditsToUse int32, weekStart time.Time) (bool, *errLib.CommonError) {
 	result, err := r.queries.CheckWeeklyCreditLimit(ctx, dbUser.CheckWeeklyCreditLimitParams{
 		CustomerID:    customerID,
 		WeekStartDate: weekStart,
@@ -562,6 +562,26 @@ func (r *CustomerRepository) ListMembershipHistory(ctx context.Context, customer
 		return nil, errLib.New("internal error", http.StatusInternalServerError)
 	}
 
+	dbFreezes, err := r.Queries.ListCustomerMembershipFreezes(ctx, customerID)
+	if err != nil {
+		log.Printf("error querying membership freezes: %v", err)
+		return nil, errLib.New("internal error", http.StatusInternalServerError)
+	}
+
+	freezesByMembership := make(map[uuid.UUID][]userValues.MembershipFreezeValue)
+	for _, freeze := range dbFreezes {
+		freezesByMembership[freeze.CustomerMembershipPlanID] = append(freezesByMembership[freeze.CustomerMembershipPlanID], userValues.MembershipFreezeValue{
+			ID:               freeze.ID,
+			Reason:           freeze.Reason,
+			StartDate:        freeze.StartDate,
+			EndDate:          freeze.EndDate,
+			Status:           freeze.Status,
+			FeeAmount:        int(freeze.FeeAmount),
+			CreditsForfeited: freeze.CreditsForfeited,
+			HasMedicalNote:   freeze.MedicalNoteUrl.Valid,
+		})
+	}
+
 	results := make([]userValues.MembershipHistoryValue, len(dbRows))
 	for i, row := range dbRows {
 		var renewal *time.Time
@@ -606,6 +626,7 @@ func (r *CustomerRepository) ListMembershipHistory(ctx context.Context, customer
 		if row.StripeSubscriptionID.Valid {
 			historyVal.StripeSubscriptionID = &row.StripeSubscriptionID.String
 		}
+		historyVal.Freezes = freezesByMembership[row.ID]
 		results[i] = historyVal
 	}
 
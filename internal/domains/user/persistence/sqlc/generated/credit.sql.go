@@ -87,43 +87,6 @@ func (q *Queries) CheckWeeklyCreditLimit(ctx context.Context, arg CheckWeeklyCre
 	return i, err
 }
 
-const createCustomerCredits = `-- name: CreateCustomerCredits :exec
-INSERT INTO users.customer_credits (customer_id, credits)
-VALUES ($1, $2)
-ON CONFLICT (customer_id) DO NOTHING
-`
-
-type CreateCustomerCreditsParams struct {
-	CustomerID uuid.UUID `json:"customer_id"`
-	Credits    int32     `json:"credits"`
-}
-
-// Create customer credits record with initial balance
-func (q *Queries) CreateCustomerCredits(ctx context.Context, arg CreateCustomerCreditsParams) error {
-	_, err := q.db.ExecContext(ctx, createCustomerCredits, arg.CustomerID, arg.Credits)
-	return err
-}
-
-const deductCredits = `-- name: DeductCredits :execrows
-UPDATE users.customer_credits
-SET credits = credits - $2
-WHERE customer_id = $1 AND credits >= $2
-`
-
-type DeductCreditsParams struct {
-	CustomerID uuid.UUID `json:"customer_id"`
-	Credits    int32     `json:"credits"`
-}
-
-// Deduct credits only if customer has sufficient balance
-func (q *Queries) DeductCredits(ctx context.Context, arg DeductCreditsParams) (int64, error) {
-	result, err := q.db.ExecContext(ctx, deductCredits, arg.CustomerID, arg.Credits)
-	if err != nil {
-		return 0, err
-	}
-	return result.RowsAffected()
-}
-
 const getActiveCustomerMembershipPlanID = `-- name: GetActiveCustomerMembershipPlanID :one
 SELECT membership_plan_id
 FROM users.customer_membership_plans
@@ -430,6 +393,21 @@ func (q *Queries) GetEventCreditTransactions(ctx context.Context, eventID uuid.N
 	return items, nil
 }
 
+const getEventTimezone = `-- name: GetEventTimezone :one
+SELECT l.timezone
+FROM events.events e
+JOIN location.locations l ON e.location_id = l.id
+WHERE e.id = $1
+`
+
+// Get the timezone of the facility hosting an event
+func (q *Queries) GetEventTimezone(ctx context.Context, id uuid.UUID) (string, error) {
+	row := q.db.QueryRowContext(ctx, getEventTimezone, id)
+	var timezone string
+	err := row.Scan(&timezone)
+	return timezone, err
+}
+
 const getWeeklyCreditsUsed = `-- name: GetWeeklyCreditsUsed :one
 
 SELECT COALESCE(credits_used, 0) as credits_used
@@ -560,46 +538,6 @@ func (q *Queries) ReduceWeeklyCreditsUsed(ctx context.Context, arg ReduceWeeklyC
 	return err
 }
 
-const refundCredits = `-- name: RefundCredits :execrows
-UPDATE users.customer_credits
-SET credits = credits + $2
-WHERE customer_id = $1
-`
-
-type RefundCreditsParams struct {
-	CustomerID uuid.UUID `json:"customer_id"`
-	Credits    int32     `json:"credits"`
-}
-
-// Add credits back to customer's account
-func (q *Queries) RefundCredits(ctx context.Context, arg RefundCreditsParams) (int64, error) {
-	result, err := q.db.ExecContext(ctx, refundCredits, arg.CustomerID, arg.Credits)
-	if err != nil {
-		return 0, err
-	}
-	return result.RowsAffected()
-}
-
-const updateCustomerCredits = `-- name: UpdateCustomerCredits :execrows
-UPDATE users.customer_credits
-SET credits = $2
-WHERE customer_id = $1
-`
-
-type UpdateCustomerCreditsParams struct {
-	CustomerID uuid.UUID `json:"customer_id"`
-	Credits    int32     `json:"credits"`
-}
-
-// Update customer's credit balance directly
-func (q *Queries) UpdateCustomerCredits(ctx context.Context, arg UpdateCustomerCreditsParams) (int64, error) {
-	result, err := q.db.ExecContext(ctx, updateCustomerCredits, arg.CustomerID, arg.Credits)
-	if err != nil {
-		return 0, err
-	}
-	return result.RowsAffected()
-}
-
 const updateEventCreditCost = `-- name: UpdateEventCreditCost :execrows
 UPDATE events.events
 SET credit_cost = $2
@@ -19,18 +19,20 @@ INSERT INTO users.credit_packages (
     description,
     stripe_price_id,
     credit_allocation,
-    weekly_credit_limit
+    weekly_credit_limit,
+    credit_validity_days
 ) VALUES (
-    $1, $2, $3, $4, $5
-) RETURNING id, name, description, stripe_price_id, credit_allocation, weekly_credit_limit, created_at, updated_at
+    $1, $2, $3, $4, $5, $6
+) RETURNING id, name, description, stripe_price_id, credit_allocation, weekly_credit_limit, created_at, updated_at, credit_validity_days
 `
 
 type CreateCreditPackageParams struct {
-	Name              string         `json:"name"`
-	Description       sql.NullString `json:"description"`
-	StripePriceID     string         `json:"stripe_price_id"`
-	CreditAllocation  int32          `json:"credit_allocation"`
-	WeeklyCreditLimit int32          `json:"weekly_credit_limit"`
+	Name               string         `json:"name"`
+	Description        sql.NullString `json:"description"`
+	StripePriceID      string         `json:"stripe_price_id"`
+	CreditAllocation   int32          `json:"credit_allocation"`
+	WeeklyCreditLimit  int32          `json:"weekly_credit_limit"`
+	CreditValidityDays sql.NullInt32  `json:"credit_validity_days"`
 }
 
 func (q *Queries) CreateCreditPackage(ctx context.Context, arg CreateCreditPackageParams) (UsersCreditPackage, error) {
@@ -40,6 +42,7 @@ func (q *Queries) CreateCreditPackage(ctx context.Context, arg CreateCreditPacka
 		arg.StripePriceID,
 		arg.CreditAllocation,
 		arg.WeeklyCreditLimit,
+		arg.CreditValidityDays,
 	)
 	var i UsersCreditPackage
 	err := row.Scan(
@@ -51,6 +54,7 @@ func (q *Queries) CreateCreditPackage(ctx context.Context, arg CreateCreditPacka
 		&i.WeeklyCreditLimit,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CreditValidityDays,
 	)
 	return i, err
 }
@@ -76,7 +80,7 @@ func (q *Queries) DeleteCustomerActiveCreditPackage(ctx context.Context, custome
 }
 
 const getAllCreditPackages = `-- name: GetAllCreditPackages :many
-SELECT id, name, description, stripe_price_id, credit_allocation, weekly_credit_limit, created_at, updated_at FROM users.credit_packages
+SELECT id, name, description, stripe_price_id, credit_allocation, weekly_credit_limit, created_at, updated_at, credit_validity_days FROM users.credit_packages
 ORDER BY credit_allocation ASC
 `
 
@@ -98,6 +102,7 @@ func (q *Queries) GetAllCreditPackages(ctx context.Context) ([]UsersCreditPackag
 			&i.WeeklyCreditLimit,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.CreditValidityDays,
 		); err != nil {
 			return nil, err
 		}
@@ -113,7 +118,7 @@ func (q *Queries) GetAllCreditPackages(ctx context.Context) ([]UsersCreditPackag
 }
 
 const getCreditPackageByID = `-- name: GetCreditPackageByID :one
-SELECT id, name, description, stripe_price_id, credit_allocation, weekly_credit_limit, created_at, updated_at FROM users.credit_packages
+SELECT id, name, description, stripe_price_id, credit_allocation, weekly_credit_limit, created_at, updated_at, credit_validity_days FROM users.credit_packages
 WHERE id = $1
 `
 
@@ -129,12 +134,13 @@ func (q *Queries) GetCreditPackageByID(ctx context.Context, id uuid.UUID) (Users
 		&i.WeeklyCreditLimit,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CreditValidityDays,
 	)
 	return i, err
 }
 
 const getCreditPackageByStripePriceID = `-- name: GetCreditPackageByStripePriceID :one
-SELECT id, name, description, stripe_price_id, credit_allocation, weekly_credit_limit, created_at, updated_at FROM users.credit_packages
+SELECT id, name, description, stripe_price_id, credit_allocation, weekly_credit_limit, created_at, updated_at, credit_validity_days FROM users.credit_packages
 WHERE stripe_price_id = $1
 `
 
@@ -150,6 +156,7 @@ func (q *Queries) GetCreditPackageByStripePriceID(ctx context.Context, stripePri
 		&i.WeeklyCreditLimit,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CreditValidityDays,
 	)
 	return i, err
 }
@@ -220,18 +227,20 @@ SET name = $2,
     stripe_price_id = $4,
     credit_allocation = $5,
     weekly_credit_limit = $6,
+    credit_validity_days = $7,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1
-RETURNING id, name, description, stripe_price_id, credit_allocation, weekly_credit_limit, created_at, updated_at
+RETURNING id, name, description, stripe_price_id, credit_allocation, weekly_credit_limit, created_at, updated_at, credit_validity_days
 `
 
 type UpdateCreditPackageParams struct {
-	ID                uuid.UUID      `json:"id"`
-	Name              string         `json:"name"`
-	Description       sql.NullString `json:"description"`
-	StripePriceID     string         `json:"stripe_price_id"`
-	CreditAllocation  int32          `json:"credit_allocation"`
-	WeeklyCreditLimit int32          `json:"weekly_credit_limit"`
+	ID                 uuid.UUID      `json:"id"`
+	Name               string         `json:"name"`
+	Description        sql.NullString `json:"description"`
+	StripePriceID      string         `json:"stripe_price_id"`
+	CreditAllocation   int32          `json:"credit_allocation"`
+	WeeklyCreditLimit  int32          `json:"weekly_credit_limit"`
+	CreditValidityDays sql.NullInt32  `json:"credit_validity_days"`
 }
 
 func (q *Queries) UpdateCreditPackage(ctx context.Context, arg UpdateCreditPackageParams) (UsersCreditPackage, error) {
@@ -242,6 +251,7 @@ func (q *Queries) UpdateCreditPackage(ctx context.Context, arg UpdateCreditPacka
 		arg.StripePriceID,
 		arg.CreditAllocation,
 		arg.WeeklyCreditLimit,
+		arg.CreditValidityDays,
 	)
 	var i UsersCreditPackage
 	err := row.Scan(
@@ -253,6 +263,7 @@ func (q *Queries) UpdateCreditPackage(ctx context.Context, arg UpdateCreditPacka
 		&i.WeeklyCreditLimit,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CreditValidityDays,
 	)
 	return i, err
 }
@@ -343,10 +343,12 @@ func AllCareersLocationTypeValues() []CareersLocationType {
 type CreditTransactionType string
 
 const (
-	CreditTransactionTypeEnrollment      CreditTransactionType = "enrollment"
-	CreditTransactionTypeRefund          CreditTransactionType = "refund"
-	CreditTransactionTypePurchase        CreditTransactionType = "purchase"
-	CreditTransactionTypeAdminAdjustment CreditTransactionType = "admin_adjustment"
+	CreditTransactionTypeEnrollment        CreditTransactionType = "enrollment"
+	CreditTransactionTypeRefund            CreditTransactionType = "refund"
+	CreditTransactionTypePurchase          CreditTransactionType = "purchase"
+	CreditTransactionTypeAdminAdjustment   CreditTransactionType = "admin_adjustment"
+	CreditTransactionTypeCourtRental       CreditTransactionType = "court_rental"
+	CreditTransactionTypePlaygroundSession CreditTransactionType = "playground_session"
 )
 
 func (e *CreditTransactionType) Scan(src interface{}) error {
@@ -389,7 +391,9 @@ func (e CreditTransactionType) Valid() bool {
 	case CreditTransactionTypeEnrollment,
 		CreditTransactionTypeRefund,
 		CreditTransactionTypePurchase,
-		CreditTransactionTypeAdminAdjustment:
+		CreditTransactionTypeAdminAdjustment,
+		CreditTransactionTypeCourtRental,
+		CreditTransactionTypePlaygroundSession:
 		return true
 	}
 	return false
@@ -401,6 +405,8 @@ func AllCreditTransactionTypeValues() []CreditTransactionType {
 		CreditTransactionTypeRefund,
 		CreditTransactionTypePurchase,
 		CreditTransactionTypeAdminAdjustment,
+		CreditTransactionTypeCourtRental,
+		CreditTransactionTypePlaygroundSession,
 	}
 }
 
@@ -1059,6 +1065,8 @@ type LocationLocation struct {
 	Address   string    `json:"address"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	Timezone  string    `json:"timezone"`
+	Province  string    `json:"province"`
 }
 
 type MembershipDiscountRestrictedMembershipPlan struct {
@@ -1067,6 +1075,17 @@ type MembershipDiscountRestrictedMembershipPlan struct {
 	CreatedAt        time.Time `json:"created_at"`
 }
 
+type MembershipFreezePolicy struct {
+	MembershipPlanID          uuid.UUID `json:"membership_plan_id"`
+	MinDays                   int32     `json:"min_days"`
+	MaxDays                   int32     `json:"max_days"`
+	MaxFreezesPerYear         int32     `json:"max_freezes_per_year"`
+	FeeAmount                 int32     `json:"fee_amount"`
+	InjuryRequiresMedicalNote bool      `json:"injury_requires_medical_note"`
+	CreatedAt                 time.Time `json:"created_at"`
+	UpdatedAt                 time.Time `json:"updated_at"`
+}
+
 type MembershipMembership struct {
 	ID          uuid.UUID `json:"id"`
 	Name        string    `json:"name"`
@@ -1398,6 +1417,45 @@ type SubsidiesUsageTransaction struct {
 	AppliedAt             sql.NullTime   `json:"applied_at"`
 }
 
+type UsersCreditGrant struct {
+	ID          uuid.UUID    `json:"id"`
+	CustomerID  uuid.UUID    `json:"customer_id"`
+	Source      string       `json:"source"`
+	Amount      int32        `json:"amount"`
+	Remaining   int32        `json:"remaining"`
+	Description string       `json:"description"`
+	ExpiresAt   sql.NullTime `json:"expires_at"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+type UsersCreditHold struct {
+	ID          uuid.UUID     `json:"id"`
+	CustomerID  uuid.UUID     `json:"customer_id"`
+	Amount      int32         `json:"amount"`
+	Reference   string        `json:"reference"`
+	EventID     uuid.NullUUID `json:"event_id"`
+	Description string        `json:"description"`
+	Status      string        `json:"status"`
+	ExpiresAt   time.Time     `json:"expires_at"`
+	CapturedAt  sql.NullTime  `json:"captured_at"`
+	ReleasedAt  sql.NullTime  `json:"released_at"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+type UsersCreditLedgerEntry struct {
+	ID            uuid.UUID      `json:"id"`
+	TransactionID uuid.UUID      `json:"transaction_id"`
+	CustomerID    uuid.UUID      `json:"customer_id"`
+	Account       string         `json:"account"`
+	EntryType     string         `json:"entry_type"`
+	Amount        int32          `json:"amount"`
+	GrantID       uuid.NullUUID  `json:"grant_id"`
+	HoldID        uuid.NullUUID  `json:"hold_id"`
+	Reference     sql.NullString `json:"reference"`
+	Description   string         `json:"description"`
+	CreatedAt     time.Time      `json:"created_at"`
+}
+
 // Available credit packages for one-time purchase
 type UsersCreditPackage struct {
 	ID          uuid.UUID      `json:"id"`
@@ -1411,6 +1469,21 @@ type UsersCreditPackage struct {
 	WeeklyCreditLimit int32     `json:"weekly_credit_limit"`
 	CreatedAt         time.Time `json:"created_at"`
 	UpdatedAt         time.Time `json:"updated_at"`
+	// Number of days credits bought with a package can be used. NULL never expires.
+	CreditValidityDays sql.NullInt32 `json:"credit_validity_days"`
+}
+
+type UsersCreditReconciliationIssue struct {
+	ID            uuid.UUID      `json:"id"`
+	CustomerID    uuid.UUID      `json:"customer_id"`
+	Kind          string         `json:"kind"`
+	TransactionID uuid.NullUUID  `json:"transaction_id"`
+	Expected      int32          `json:"expected"`
+	Actual        int32          `json:"actual"`
+	DetectedAt    time.Time      `json:"detected_at"`
+	ResolvedBy    uuid.NullUUID  `json:"resolved_by"`
+	ResolvedAt    sql.NullTime   `json:"resolved_at"`
+	Resolution    sql.NullString `json:"resolution"`
 }
 
 type UsersCreditTransaction struct {
@@ -1472,6 +1545,26 @@ type UsersCustomerMembershipPlan struct {
 	LastStripeEventAt sql.NullTime `json:"last_stripe_event_at"`
 }
 
+type UsersMembershipFreeze struct {
+	ID                       uuid.UUID      `json:"id"`
+	CustomerID               uuid.UUID      `json:"customer_id"`
+	CustomerMembershipPlanID uuid.UUID      `json:"customer_membership_plan_id"`
+	StripeSubscriptionID     string         `json:"stripe_subscription_id"`
+	Reason                   string         `json:"reason"`
+	Notes                    sql.NullString `json:"notes"`
+	MedicalNoteUrl           sql.NullString `json:"medical_note_url"`
+	StartDate                time.Time      `json:"start_date"`
+	EndDate                  time.Time      `json:"end_date"`
+	Status                   string         `json:"status"`
+	FeeAmount                int32          `json:"fee_amount"`
+	StripeFeeInvoiceID       sql.NullString `json:"stripe_fee_invoice_id"`
+	CreditsForfeited         int32          `json:"credits_forfeited"`
+	StartedAt                sql.NullTime   `json:"started_at"`
+	EndedAt                  sql.NullTime   `json:"ended_at"`
+	CreatedAt                time.Time      `json:"created_at"`
+	UpdatedAt                time.Time      `json:"updated_at"`
+}
+
 // Tracks parent-child link requests including transfers between parents
 type UsersParentLinkRequest struct {
 	ID          uuid.UUID     `json:"id"`
@@ -1492,6 +1585,23 @@ type UsersParentLinkRequest struct {
 	CreatedAt           time.Time      `json:"created_at"`
 }
 
+type UsersScheduledSuspensionAction struct {
+	ID                        uuid.UUID      `json:"id"`
+	UserID                    uuid.UUID      `json:"user_id"`
+	Action                    string         `json:"action"`
+	RunAt                     time.Time      `json:"run_at"`
+	SuspensionReason          sql.NullString `json:"suspension_reason"`
+	SuspensionDurationSeconds sql.NullInt64  `json:"suspension_duration_seconds"`
+	ExtendMembership          bool           `json:"extend_membership"`
+	CollectArrears            bool           `json:"collect_arrears"`
+	Status                    string         `json:"status"`
+	ScheduledBy               uuid.UUID      `json:"scheduled_by"`
+	ExecutedAt                sql.NullTime   `json:"executed_at"`
+	FailureReason             sql.NullString `json:"failure_reason"`
+	CreatedAt                 time.Time      `json:"created_at"`
+	UpdatedAt                 time.Time      `json:"updated_at"`
+}
+
 type UsersSubscriptionAutoCharging struct {
 	ID                       uuid.UUID      `json:"id"`
 	CustomerMembershipPlanID uuid.UUID      `json:"customer_membership_plan_id"`
@@ -1560,8 +1670,9 @@ type UsersUser struct {
 	// Timestamp when the user last changed their email address.
 	EmailChangedAt sql.NullTime `json:"email_changed_at"`
 	// Timestamp when account was archived. Archived accounts are permanently deleted after 30 days.
-	ArchivedAt  sql.NullTime   `json:"archived_at"`
-	AccountType sql.NullString `json:"account_type"`
+	ArchivedAt         sql.NullTime   `json:"archived_at"`
+	AccountType        sql.NullString `json:"account_type"`
+	StoredValueBalance int32          `json:"stored_value_balance"`
 }
 
 // Tracks weekly credit consumption per customer for membership limit enforcement
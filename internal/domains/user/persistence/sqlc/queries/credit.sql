@@ -6,30 +6,6 @@ SELECT credits
 FROM users.customer_credits
 WHERE customer_id = $1;
 
--- name: CreateCustomerCredits :exec
--- Create customer credits record with initial balance
-INSERT INTO users.customer_credits (customer_id, credits)
-VALUES ($1, $2)
-ON CONFLICT (customer_id) DO NOTHING;
-
--- name: UpdateCustomerCredits :execrows
--- Update customer's credit balance directly
-UPDATE users.customer_credits
-SET credits = $2
-WHERE customer_id = $1;
-
--- name: DeductCredits :execrows  
--- Deduct credits only if customer has sufficient balance
-UPDATE users.customer_credits
-SET credits = credits - $2
-WHERE customer_id = $1 AND credits >= $2;
-
--- name: RefundCredits :execrows
--- Add credits back to customer's account
-UPDATE users.customer_credits
-SET credits = credits + $2
-WHERE customer_id = $1;
-
 -- name: CheckCustomerHasSufficientCredits :one
 -- Check if customer has enough credits for a transaction
 SELECT credits >= $2 as has_sufficient
@@ -68,6 +44,13 @@ SELECT credit_cost
 FROM events.events
 WHERE id = $1;
 
+-- name: GetEventTimezone :one
+-- Get the timezone of the facility hosting an event
+SELECT l.timezone
+FROM events.events e
+JOIN location.locations l ON e.location_id = l.id
+WHERE e.id = $1;
+
 -- name: UpdateEventCreditCost :execrows
 -- Update the credit cost for an event
 UPDATE events.events
@@ -0,0 +1,247 @@
+-- Credit Ledger Queries
+
+-- name: LockCustomerCredits :one
+-- Lock the customer's balance row, creating it when missing, so their ledger is written one transaction at a time
+INSERT INTO users.customer_credits (customer_id, credits)
+VALUES ($1, 0)
+ON CONFLICT (customer_id) DO UPDATE SET credits = users.customer_credits.credits
+RETURNING credits;
+
+-- name: SyncCustomerCredits :one
+-- Set the customer's balance to the sum of their available ledger entries
+UPDATE users.customer_credits
+SET credits = (SELECT COALESCE(SUM(e.amount), 0)::int
+               FROM users.credit_ledger_entries e
+               WHERE e.customer_id = $1
+                 AND e.account = 'available')
+WHERE customer_id = $1
+RETURNING credits;
+
+-- name: InsertCreditLedgerEntry :exec
+INSERT INTO users.credit_ledger_entries (transaction_id, customer_id, account, entry_type, amount, grant_id, hold_id, reference, description)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+
+-- name: ListCustomerCreditLedgerEntries :many
+SELECT *
+FROM users.credit_ledger_entries
+WHERE customer_id = $1
+ORDER BY created_at DESC, transaction_id, amount
+LIMIT $2 OFFSET $3;
+
+-- name: GetReferenceSpentByGrant :many
+-- Credits spent under a reference and not refunded yet, per grant they came from
+SELECT grant_id, SUM(amount)::int AS spent
+FROM users.credit_ledger_entries
+WHERE customer_id = $1
+  AND reference = $2
+  AND account = 'spent'
+  AND grant_id IS NOT NULL
+GROUP BY grant_id
+HAVING SUM(amount) > 0
+ORDER BY grant_id;
+
+-- name: HasReferenceLedgerEntries :one
+SELECT EXISTS(
+    SELECT 1 FROM users.credit_ledger_entries
+    WHERE customer_id = $1
+      AND reference = $2
+) AS has_entries;
+
+-- Credit Grant Queries
+
+-- name: CreateCreditGrant :one
+INSERT INTO users.credit_grants (customer_id, source, amount, remaining, description, expires_at)
+VALUES ($1, $2, $3, $3, $4, $5)
+RETURNING *;
+
+-- name: GetSpendableCreditGrantsForUpdate :many
+-- Grants that can still be spent, in the order they are used: soonest expiry first, oldest first, never-expiring last
+SELECT *
+FROM users.credit_grants
+WHERE customer_id = $1
+  AND remaining > 0
+  AND (expires_at IS NULL OR expires_at > NOW())
+ORDER BY expires_at NULLS LAST, created_at, id
+FOR UPDATE;
+
+-- name: GetLapsedCreditGrantsForUpdate :many
+-- Grants past their expiry that still hold credits, for one customer or everyone
+SELECT *
+FROM users.credit_grants
+WHERE remaining > 0
+  AND expires_at <= NOW()
+  AND (sqlc.narg(customer_id)::uuid IS NULL OR customer_id = sqlc.narg(customer_id))
+ORDER BY customer_id, expires_at
+FOR UPDATE SKIP LOCKED;
+
+-- name: GetLapsedCreditGrantCustomerIDs :many
+SELECT DISTINCT customer_id
+FROM users.credit_grants
+WHERE remaining > 0
+  AND expires_at <= NOW();
+
+-- name: TakeFromCreditGrant :execrows
+UPDATE users.credit_grants
+SET remaining = remaining - sqlc.arg(amount)
+WHERE id = $1
+  AND remaining >= sqlc.arg(amount);
+
+-- name: ReturnToCreditGrant :execrows
+UPDATE users.credit_grants
+SET remaining = remaining + sqlc.arg(amount)
+WHERE id = $1
+  AND remaining + sqlc.arg(amount) <= users.credit_grants.amount;
+
+-- name: ListCustomerCreditGrants :many
+-- The customer's grants with credits left first, in the order they will be used
+SELECT *
+FROM users.credit_grants
+WHERE customer_id = $1
+  AND (remaining > 0 OR NOT sqlc.arg(open_only)::boolean)
+ORDER BY remaining = 0, expires_at NULLS LAST, created_at, id
+LIMIT $2 OFFSET $3;
+
+-- Credit Hold Queries
+
+-- name: CreateCreditHold :one
+INSERT INTO users.credit_holds (customer_id, amount, reference, event_id, description, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetCreditHoldForUpdate :one
+SELECT *
+FROM users.credit_holds
+WHERE id = $1
+FOR UPDATE;
+
+-- name: GetCreditHoldGrantAmounts :many
+-- Credits a hold took, per grant they came from
+SELECT grant_id, SUM(amount)::int AS held
+FROM users.credit_ledger_entries
+WHERE hold_id = $1
+  AND account = 'held'
+  AND grant_id IS NOT NULL
+GROUP BY grant_id
+HAVING SUM(amount) > 0
+ORDER BY grant_id;
+
+-- name: CaptureCreditHold :execrows
+UPDATE users.credit_holds
+SET status = 'captured', captured_at = NOW()
+WHERE id = $1 AND status = 'held';
+
+-- name: ReleaseCreditHold :execrows
+UPDATE users.credit_holds
+SET status = 'released', released_at = NOW()
+WHERE id = $1 AND status = 'held';
+
+-- name: GetLapsedCreditHolds :many
+-- Holds past their expiry. booked is true when the customer holds a seat in the event the hold was for.
+SELECT h.id,
+       EXISTS(SELECT 1
+              FROM events.customer_enrollment ce
+              WHERE ce.event_id = h.event_id
+                AND ce.customer_id = h.customer_id
+                AND ce.payment_status IN ('pending', 'paid')) AS booked
+FROM users.credit_holds h
+WHERE h.status = 'held'
+  AND h.expires_at <= $1
+ORDER BY h.expires_at
+LIMIT $2;
+
+-- Credit Reconciliation Queries
+
+-- name: GetCreditDrift :many
+-- Customers whose cached balance, grants or holds disagree with their ledger
+WITH ledger AS (SELECT customer_id,
+                       COALESCE(SUM(amount) FILTER (WHERE account = 'available'), 0)::int AS available,
+                       COALESCE(SUM(amount) FILTER (WHERE account = 'held'), 0)::int      AS held
+                FROM users.credit_ledger_entries
+                GROUP BY customer_id),
+     grants AS (SELECT customer_id, SUM(remaining)::int AS remaining
+                FROM users.credit_grants
+                GROUP BY customer_id),
+     holds AS (SELECT customer_id, SUM(amount)::int AS held
+               FROM users.credit_holds
+               WHERE status = 'held'
+               GROUP BY customer_id),
+     customers AS (SELECT customer_id FROM users.customer_credits
+                   UNION
+                   SELECT customer_id FROM ledger
+                   UNION
+                   SELECT customer_id FROM grants)
+SELECT c.customer_id,
+       COALESCE(cc.credits, 0)::int  AS cached_balance,
+       COALESCE(l.available, 0)::int AS ledger_available,
+       COALESCE(l.held, 0)::int      AS ledger_held,
+       COALESCE(g.remaining, 0)::int AS grants_remaining,
+       COALESCE(h.held, 0)::int      AS holds_open
+FROM customers c
+         LEFT JOIN users.customer_credits cc ON cc.customer_id = c.customer_id
+         LEFT JOIN ledger l ON l.customer_id = c.customer_id
+         LEFT JOIN grants g ON g.customer_id = c.customer_id
+         LEFT JOIN holds h ON h.customer_id = c.customer_id
+WHERE COALESCE(cc.credits, 0) <> COALESCE(l.available, 0)
+   OR COALESCE(g.remaining, 0) <> COALESCE(l.available, 0)
+   OR COALESCE(h.held, 0) <> COALESCE(l.held, 0);
+
+-- name: GetUnbalancedCreditTransactions :many
+-- Ledger transactions whose entries do not sum to zero
+SELECT transaction_id, customer_id, SUM(amount)::int AS imbalance
+FROM users.credit_ledger_entries
+GROUP BY transaction_id, customer_id
+HAVING SUM(amount) <> 0;
+
+-- name: InsertCreditReconciliationIssue :execrows
+-- Record a difference unless the same one is already open
+INSERT INTO users.credit_reconciliation_issues (customer_id, kind, transaction_id, expected, actual)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT DO NOTHING;
+
+-- name: ListCreditReconciliationIssues :many
+SELECT i.*, u.first_name, u.last_name
+FROM users.credit_reconciliation_issues i
+         JOIN users.users u ON u.id = i.customer_id
+WHERE (sqlc.arg(include_resolved)::boolean OR i.resolved_at IS NULL)
+ORDER BY i.detected_at DESC
+LIMIT $1 OFFSET $2;
+
+-- name: ResolveCreditReconciliationIssue :one
+UPDATE users.credit_reconciliation_issues
+SET resolved_by = $2,
+    resolved_at = NOW(),
+    resolution  = $3
+WHERE id = $1
+  AND resolved_at IS NULL
+RETURNING *;
+
+-- Failed Refund Queries
+
+-- name: GetPendingFailedRefundIDs :many
+SELECT id
+FROM payment.failed_refunds
+WHERE status = 'pending'
+ORDER BY created_at
+LIMIT $1;
+
+-- name: GetPendingFailedRefundForUpdate :one
+SELECT *
+FROM payment.failed_refunds
+WHERE id = $1
+  AND status = 'pending'
+FOR UPDATE SKIP LOCKED;
+
+-- name: ResolveFailedRefund :exec
+UPDATE payment.failed_refunds
+SET status = 'resolved', resolved_at = NOW(), updated_at = NOW()
+WHERE id = $1;
+
+-- name: RecordFailedRefundAttempt :one
+-- Count a failed retry; the refund is given up on once it has been tried max_attempts times
+UPDATE payment.failed_refunds
+SET retry_count   = COALESCE(retry_count, 0) + 1,
+    error_message = $2,
+    status        = CASE WHEN COALESCE(retry_count, 0) + 1 >= sqlc.arg(max_attempts)::int THEN 'failed' ELSE status END,
+    updated_at    = NOW()
+WHERE id = $1
+RETURNING status;
@@ -16,9 +16,10 @@ INSERT INTO users.credit_packages (
     description,
     stripe_price_id,
     credit_allocation,
-    weekly_credit_limit
+    weekly_credit_limit,
+    credit_validity_days
 ) VALUES (
-    $1, $2, $3, $4, $5
+    $1, $2, $3, $4, $5, $6
 ) RETURNING *;
 
 -- name: UpdateCreditPackage :one
@@ -28,6 +29,7 @@ SET name = $2,
     stripe_price_id = $4,
     credit_allocation = $5,
     weekly_credit_limit = $6,
+    credit_validity_days = $7,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1
 RETURNING *;
@@ -0,0 +1,108 @@
+-- name: GetFreezableMembership :one
+-- The customer's Stripe membership for a subscription, with what a freeze needs to know
+-- about its plan.
+SELECT cmp.id,
+       cmp.membership_plan_id,
+       cmp.status,
+       mp.credit_allocation
+FROM users.customer_membership_plans cmp
+         JOIN membership.membership_plans mp ON mp.id = cmp.membership_plan_id
+WHERE cmp.customer_id = $1
+  AND cmp.stripe_subscription_id = $2
+ORDER BY cmp.created_at DESC
+LIMIT 1;
+
+-- name: GetMembershipFreezePolicy :one
+SELECT *
+FROM membership.freeze_policies
+WHERE membership_plan_id = $1;
+
+-- name: CountRecentMembershipFreezes :one
+-- Freezes of a membership that count towards the yearly limit.
+SELECT COUNT(*)
+FROM users.membership_freezes
+WHERE customer_membership_plan_id = sqlc.arg(customer_membership_plan_id)
+  AND status <> 'cancelled'
+  AND start_date > sqlc.arg(since)::date;
+
+-- name: CreateMembershipFreeze :one
+INSERT INTO users.membership_freezes (customer_id, customer_membership_plan_id, stripe_subscription_id,
+                                      reason, notes, medical_note_url, start_date, end_date, fee_amount)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: SetMembershipFreezeFeeInvoice :exec
+UPDATE users.membership_freezes
+SET stripe_fee_invoice_id = $2,
+    updated_at            = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: GetOpenMembershipFreeze :one
+-- The upcoming or running freeze of a membership.
+SELECT *
+FROM users.membership_freezes
+WHERE customer_membership_plan_id = $1
+  AND status IN ('scheduled', 'active');
+
+-- name: ListCustomerMembershipFreezes :many
+SELECT *
+FROM users.membership_freezes
+WHERE customer_id = $1
+ORDER BY start_date DESC;
+
+-- name: ListDueMembershipFreezeStarts :many
+SELECT *
+FROM users.membership_freezes
+WHERE status = 'scheduled'
+  AND start_date <= sqlc.arg(today)::date
+ORDER BY start_date
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListDueMembershipFreezeEnds :many
+SELECT *
+FROM users.membership_freezes
+WHERE status = 'active'
+  AND end_date <= sqlc.arg(today)::date
+ORDER BY end_date
+LIMIT sqlc.arg(row_limit);
+
+-- name: StartMembershipFreeze :execrows
+-- Returns 0 when the freeze was cancelled or already started.
+UPDATE users.membership_freezes
+SET status            = 'active',
+    credits_forfeited = $2,
+    started_at        = CURRENT_TIMESTAMP,
+    updated_at        = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND status = 'scheduled';
+
+-- name: EndMembershipFreeze :execrows
+-- Returns 0 when the freeze is not running.
+UPDATE users.membership_freezes
+SET status     = 'completed',
+    end_date   = $2,
+    ended_at   = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND status = 'active';
+
+-- name: CancelMembershipFreeze :execrows
+UPDATE users.membership_freezes
+SET status     = 'cancelled',
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND status = 'scheduled';
+
+-- name: PauseFrozenMembership :execrows
+UPDATE users.customer_membership_plans
+SET status     = 'paused',
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND status = 'active';
+
+-- name: ResumeFrozenMembership :execrows
+UPDATE users.customer_membership_plans
+SET status     = 'active',
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND status = 'paused';
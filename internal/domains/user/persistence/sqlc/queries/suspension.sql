@@ -0,0 +1,56 @@
+-- name: CreateScheduledSuspensionAction :one
+INSERT INTO users.scheduled_suspension_actions (user_id, action, run_at, suspension_reason,
+                                                suspension_duration_seconds, extend_membership,
+                                                collect_arrears, scheduled_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: ListUserScheduledSuspensionActions :many
+SELECT * FROM users.scheduled_suspension_actions
+WHERE user_id = $1
+ORDER BY run_at DESC;
+
+-- name: ListDueScheduledSuspensionActions :many
+SELECT * FROM users.scheduled_suspension_actions
+WHERE status = 'pending'
+  AND run_at <= CURRENT_TIMESTAMP
+ORDER BY run_at
+LIMIT $1;
+
+-- name: CancelScheduledSuspensionAction :one
+UPDATE users.scheduled_suspension_actions
+SET status     = 'cancelled',
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND user_id = $2
+  AND status = 'pending'
+RETURNING *;
+
+-- name: ClaimScheduledSuspensionAction :execrows
+-- Marks a due action as done before it runs, so it never runs twice. Returns 0 when
+-- the action was cancelled or already claimed.
+UPDATE users.scheduled_suspension_actions
+SET status      = 'completed',
+    executed_at = CURRENT_TIMESTAMP,
+    updated_at  = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND status = 'pending';
+
+-- name: FailScheduledSuspensionAction :exec
+UPDATE users.scheduled_suspension_actions
+SET status         = 'failed',
+    failure_reason = $2,
+    updated_at     = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: ListExpiredSuspensions :many
+-- Timed suspensions that have run out but were never lifted, a page at a time in user
+-- ID order so users whose lift keeps failing cannot hold back the others.
+SELECT id, first_name, email, suspended_at, suspension_expires_at
+FROM users.users
+WHERE suspended_at IS NOT NULL
+  AND suspension_expires_at IS NOT NULL
+  AND suspension_expires_at <= CURRENT_TIMESTAMP
+  AND id > sqlc.arg('after_id')::uuid
+ORDER BY id
+LIMIT sqlc.arg('batch_size');
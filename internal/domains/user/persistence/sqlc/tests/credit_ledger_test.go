@@ -0,0 +1,266 @@
+package user_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"api/internal/di"
+	identityDb "api/internal/domains/identity/persistence/sqlc/generated"
+	"api/internal/domains/user/persistence/repositories"
+	"api/internal/domains/user/services"
+	values "api/internal/domains/user/values"
+	errLib "api/internal/libs/errors"
+	dbTestUtils "api/utils/test_utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const migrationsPath = "../../../../../../db/migrations"
+
+func createCustomer(t *testing.T, db *sql.DB) uuid.UUID {
+	user, err := identityDb.New(db).CreateUser(context.Background(), identityDb.CreateUserParams{
+		CountryAlpha2Code: "CA",
+		Dob:               time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+		FirstName:         "John",
+		LastName:          "Doe",
+	})
+	require.NoError(t, err)
+	return user.ID
+}
+
+func newCreditRepo(db *sql.DB) *repositories.CustomerCreditRepository {
+	return repositories.NewCustomerCreditRepository(&di.Container{DB: db, Queries: &di.QueriesType{}})
+}
+
+func newCreditService(db *sql.DB) *services.CustomerCreditService {
+	return services.NewCustomerCreditService(&di.Container{DB: db, Queries: &di.QueriesType{}})
+}
+
+func inTx(t *testing.T, repo *repositories.CustomerCreditRepository, fn func(*repositories.CustomerCreditRepository) *errLib.CommonError) {
+	require.Nil(t, repo.ExecuteInTransaction(context.Background(), fn))
+}
+
+func grant(t *testing.T, repo *repositories.CustomerCreditRepository, customerID uuid.UUID, amount int32, expiresAt *time.Time) {
+	inTx(t, repo, func(txRepo *repositories.CustomerCreditRepository) *errLib.CommonError {
+		return txRepo.GrantCredits(context.Background(), repositories.CreditGrantParams{
+			CustomerID:  customerID,
+			Amount:      amount,
+			Source:      values.CreditSourceAdjustment,
+			ExpiresAt:   expiresAt,
+			Description: "Test grant",
+		})
+	})
+}
+
+func hold(t *testing.T, repo *repositories.CustomerCreditRepository, customerID uuid.UUID, amount int32) uuid.UUID {
+	var holdID uuid.UUID
+	inTx(t, repo, func(txRepo *repositories.CustomerCreditRepository) *errLib.CommonError {
+		var err *errLib.CommonError
+		holdID, err = txRepo.HoldCredits(context.Background(), repositories.CreditHoldParams{
+			CustomerID:  customerID,
+			Amount:      amount,
+			Reference:   "test:" + uuid.NewString(),
+			Description: "Test hold",
+			ExpiresAt:   time.Now().Add(15 * time.Minute),
+		})
+		return err
+	})
+	return holdID
+}
+
+func balance(t *testing.T, repo *repositories.CustomerCreditRepository, customerID uuid.UUID) int32 {
+	credits, err := repo.GetCustomerCredits(context.Background(), customerID)
+	require.Nil(t, err)
+	return credits
+}
+
+func TestCreditHoldCaptureAndRelease(t *testing.T) {
+	db, cleanup := dbTestUtils.SetupTestDbQueries(t, migrationsPath)
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := newCreditRepo(db)
+	service := newCreditService(db)
+	customerID := createCustomer(t, db)
+	grant(t, repo, customerID, 10, nil)
+
+	captured := hold(t, repo, customerID, 4)
+	assert.Equal(t, int32(6), balance(t, repo, customerID), "held credits leave the balance")
+
+	require.Nil(t, service.CaptureEventHold(ctx, captured))
+	require.Nil(t, service.CaptureEventHold(ctx, captured), "capturing twice changes nothing")
+	assert.Equal(t, int32(6), balance(t, repo, customerID))
+
+	err := service.ReleaseEventHold(ctx, captured)
+	require.NotNil(t, err, "a captured hold cannot be released")
+	assert.Equal(t, 409, err.HTTPCode)
+
+	released := hold(t, repo, customerID, 5)
+	assert.Equal(t, int32(1), balance(t, repo, customerID))
+	require.Nil(t, service.ReleaseEventHold(ctx, released))
+	require.Nil(t, service.ReleaseEventHold(ctx, released), "releasing twice changes nothing")
+	assert.Equal(t, int32(6), balance(t, repo, customerID), "released credits go back on the balance")
+
+	grants, err := repo.ListCreditGrants(ctx, customerID, false, 10, 0)
+	require.Nil(t, err)
+	require.Len(t, grants, 1)
+	assert.Equal(t, int32(6), grants[0].Remaining, "released credits go back on their grant")
+
+	found, err := repo.FindCreditDrift(ctx)
+	require.Nil(t, err)
+	assert.Zero(t, found, "holds keep the ledger balanced")
+}
+
+func TestExpireGrants(t *testing.T) {
+	db, cleanup := dbTestUtils.SetupTestDbQueries(t, migrationsPath)
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := newCreditRepo(db)
+	customerID := createCustomer(t, db)
+
+	lapsed := time.Now().Add(-time.Hour)
+	later := time.Now().Add(24 * time.Hour)
+	grant(t, repo, customerID, 5, &lapsed)
+	grant(t, repo, customerID, 3, &later)
+	grant(t, repo, customerID, 2, nil)
+
+	expired, err := newCreditService(db).ExpireGrants(ctx)
+	require.Nil(t, err)
+	assert.Equal(t, 1, expired)
+	assert.Equal(t, int32(5), balance(t, repo, customerID))
+
+	// Credits are spent from the grant expiring first, leaving the one that never expires
+	inTx(t, repo, func(txRepo *repositories.CustomerCreditRepository) *errLib.CommonError {
+		return txRepo.DeductCredits(ctx, customerID, 3, "", "Test spend")
+	})
+	grants, err := repo.ListCreditGrants(ctx, customerID, true, 10, 0)
+	require.Nil(t, err)
+	require.Len(t, grants, 1)
+	assert.Nil(t, grants[0].ExpiresAt)
+	assert.Equal(t, int32(2), grants[0].Remaining)
+
+	found, err := repo.FindCreditDrift(ctx)
+	require.Nil(t, err)
+	assert.Zero(t, found)
+}
+
+func TestRetryFailedRefunds(t *testing.T) {
+	db, cleanup := dbTestUtils.SetupTestDbQueries(t, migrationsPath)
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := newCreditRepo(db)
+	service := newCreditService(db)
+	customerID := createCustomer(t, db)
+
+	queue := func(credits int32) uuid.UUID {
+		var id uuid.UUID
+		require.NoError(t, db.QueryRowContext(ctx,
+			"INSERT INTO payment.failed_refunds (customer_id, credit_amount) VALUES ($1, $2) RETURNING id",
+			customerID, credits).Scan(&id))
+		return id
+	}
+	status := func(id uuid.UUID) string {
+		var s string
+		require.NoError(t, db.QueryRowContext(ctx, "SELECT status FROM payment.failed_refunds WHERE id = $1", id).Scan(&s))
+		return s
+	}
+
+	refunded := queue(4)
+	broken := queue(0) // a grant of zero credits is rejected, so this refund fails every time
+
+	retried, resolved, err := service.RetryFailedRefunds(ctx)
+	require.Nil(t, err)
+	assert.Equal(t, 2, retried)
+	assert.Equal(t, 1, resolved)
+	assert.Equal(t, "resolved", status(refunded))
+	assert.Equal(t, int32(4), balance(t, repo, customerID))
+
+	for i := 1; i < 5; i++ {
+		retried, resolved, err = service.RetryFailedRefunds(ctx)
+		require.Nil(t, err)
+		assert.Equal(t, 1, retried)
+		assert.Zero(t, resolved)
+	}
+	assert.Equal(t, "failed", status(broken), "a refund is given up on after five attempts")
+
+	retried, _, err = service.RetryFailedRefunds(ctx)
+	require.Nil(t, err)
+	assert.Zero(t, retried)
+	assert.Equal(t, int32(4), balance(t, repo, customerID), "a resolved refund is not paid twice")
+}
+
+func TestFindCreditDrift(t *testing.T) {
+	db, cleanup := dbTestUtils.SetupTestDbQueries(t, migrationsPath)
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := newCreditRepo(db)
+	customerID := createCustomer(t, db)
+	grant(t, repo, customerID, 10, nil)
+
+	found, err := repo.FindCreditDrift(ctx)
+	require.Nil(t, err)
+	require.Zero(t, found)
+
+	_, dbErr := db.ExecContext(ctx, "UPDATE users.customer_credits SET credits = 7 WHERE customer_id = $1", customerID)
+	require.NoError(t, dbErr)
+	_, dbErr = db.ExecContext(ctx, "UPDATE users.credit_grants SET remaining = 8 WHERE customer_id = $1", customerID)
+	require.NoError(t, dbErr)
+	// One-sided movement between accounts the other checks do not compare
+	_, dbErr = db.ExecContext(ctx, `
+		INSERT INTO users.credit_ledger_entries (transaction_id, customer_id, account, entry_type, amount, description)
+		VALUES ($1, $2, 'issued', 'grant', -1, 'Broken'), ($1, $2, 'spent', 'grant', 2, 'Broken')`,
+		uuid.New(), customerID)
+	require.NoError(t, dbErr)
+
+	found, err = repo.FindCreditDrift(ctx)
+	require.Nil(t, err)
+	assert.Equal(t, 3, found)
+
+	issues, err := repo.ListReconciliationIssues(ctx, false, 10, 0)
+	require.Nil(t, err)
+	byKind := map[string]values.CreditReconciliationIssue{}
+	for _, issue := range issues {
+		byKind[issue.Kind] = issue
+	}
+	require.Len(t, byKind, 3)
+	assert.Equal(t, [2]int32{10, 7}, [2]int32{byKind[values.CreditIssueBalance].Expected, byKind[values.CreditIssueBalance].Actual})
+	assert.Equal(t, [2]int32{10, 8}, [2]int32{byKind[values.CreditIssueGrants].Expected, byKind[values.CreditIssueGrants].Actual})
+	assert.Equal(t, [2]int32{0, 1}, [2]int32{byKind[values.CreditIssueTransaction].Expected, byKind[values.CreditIssueTransaction].Actual})
+
+	found, err = repo.FindCreditDrift(ctx)
+	require.Nil(t, err)
+	assert.Zero(t, found, "a difference is recorded once while it is open")
+}
+
+func TestCreditLedgerIsAppendOnly(t *testing.T) {
+	db, cleanup := dbTestUtils.SetupTestDbQueries(t, migrationsPath)
+	defer cleanup()
+
+	ctx := context.Background()
+	customerID := createCustomer(t, db)
+	grant(t, newCreditRepo(db), customerID, 10, nil)
+
+	_, err := db.ExecContext(ctx, "UPDATE users.credit_ledger_entries SET amount = 100 WHERE customer_id = $1", customerID)
+	assert.Error(t, err)
+
+	_, err = db.ExecContext(ctx, "DELETE FROM users.credit_ledger_entries WHERE customer_id = $1", customerID)
+	assert.Error(t, err)
+
+	_, err = db.ExecContext(ctx, "DELETE FROM users.credit_grants WHERE customer_id = $1", customerID)
+	assert.Error(t, err, "deleting a grant must not take its entries with it")
+
+	// Deleting the customer takes their ledger with them
+	_, err = db.ExecContext(ctx, "DELETE FROM users.users WHERE id = $1", customerID)
+	require.NoError(t, err)
+
+	var entries int
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT COUNT(*) FROM users.credit_ledger_entries WHERE customer_id = $1", customerID).Scan(&entries))
+	assert.Zero(t, entries)
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"api/internal/domains/user/persistence/repositories"
+	dbUser "api/internal/domains/user/persistence/sqlc/generated"
+	values "api/internal/domains/user/values"
+	errLib "api/internal/libs/errors"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// failedRefundMaxAttempts is how often a queued credit refund is retried before it
+	// is left for staff
+	failedRefundMaxAttempts = 5
+
+	// reconciliationBatchSize caps the holds and queued refunds settled per run
+	reconciliationBatchSize = 200
+)
+
+// ListCreditGrants retrieves the customer's credit grants, those with credits left first
+func (s *CustomerCreditService) ListCreditGrants(ctx context.Context, customerID uuid.UUID, openOnly bool, limit, offset int32) ([]values.CreditGrant, *errLib.CommonError) {
+	return s.repo.ListCreditGrants(ctx, customerID, openOnly, limit, offset)
+}
+
+// ListLedgerEntries retrieves the customer's credit ledger entries, newest first
+func (s *CustomerCreditService) ListLedgerEntries(ctx context.Context, customerID uuid.UUID, limit, offset int32) ([]values.CreditLedgerEntry, *errLib.CommonError) {
+	return s.repo.ListLedgerEntries(ctx, customerID, limit, offset)
+}
+
+// ListReconciliationIssues retrieves the differences found by credit reconciliation
+func (s *CustomerCreditService) ListReconciliationIssues(ctx context.Context, includeResolved bool, limit, offset int32) ([]values.CreditReconciliationIssue, *errLib.CommonError) {
+	return s.repo.ListReconciliationIssues(ctx, includeResolved, limit, offset)
+}
+
+// ResolveReconciliationIssue closes a reconciliation issue once staff have corrected it
+func (s *CustomerCreditService) ResolveReconciliationIssue(ctx context.Context, id, staffID uuid.UUID, resolution string) (values.CreditReconciliationIssue, *errLib.CommonError) {
+	issue, err := s.repo.ResolveReconciliationIssue(ctx, id, staffID, resolution)
+	if err != nil {
+		return issue, err
+	}
+
+	activityDesc := fmt.Sprintf("Resolved %s credit reconciliation issue for customer %s: %s", issue.Kind, issue.CustomerID, resolution)
+	if logErr := s.staffActivityLogsService.InsertStaffActivity(ctx, nil, staffID, activityDesc); logErr != nil {
+		log.Printf("Failed to log staff activity for credit reconciliation issue %s: %v", id, logErr)
+	}
+	return issue, nil
+}
+
+// Reconcile expires lapsed credits, settles holds past their expiry, retries queued
+// refunds and records where balances disagree with the ledger. Failures on one customer
+// are logged and do not stop the rest.
+func (s *CustomerCreditService) Reconcile(ctx context.Context) (values.CreditReconciliationResult, *errLib.CommonError) {
+	var result values.CreditReconciliationResult
+
+	expired, err := s.ExpireGrants(ctx)
+	if err != nil {
+		return result, err
+	}
+	result.GrantsExpired = expired
+
+	if result.HoldsCaptured, result.HoldsReleased, err = s.SettleLapsedHolds(ctx); err != nil {
+		return result, err
+	}
+
+	if result.RefundsRetried, result.RefundsResolved, err = s.RetryFailedRefunds(ctx); err != nil {
+		return result, err
+	}
+
+	if result.IssuesFound, err = s.repo.FindCreditDrift(ctx); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// ExpireGrants moves credits past their grant's expiry out of every balance and returns
+// how many grants lapsed
+func (s *CustomerCreditService) ExpireGrants(ctx context.Context) (int, *errLib.CommonError) {
+	customerIDs, err := s.repo.GetLapsedCreditGrantCustomerIDs(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, customerID := range customerIDs {
+		txErr := s.repo.ExecuteInTransaction(ctx, func(txRepo *repositories.CustomerCreditRepository) *errLib.CommonError {
+			n, expireErr := txRepo.ExpireLapsedGrants(ctx, customerID)
+			if expireErr != nil {
+				return expireErr
+			}
+			expired += n
+			return nil
+		})
+		if txErr != nil {
+			log.Printf("Failed to expire credits of customer %s: %v", customerID, txErr)
+		}
+	}
+	return expired, nil
+}
+
+// SettleLapsedHolds settles holds nobody captured or released in time. A hold whose
+// customer got the seat is captured; any other is released.
+func (s *CustomerCreditService) SettleLapsedHolds(ctx context.Context) (captured, released int, err *errLib.CommonError) {
+	holds, err := s.repo.GetLapsedCreditHolds(ctx, time.Now(), reconciliationBatchSize)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, hold := range holds {
+		if hold.Booked {
+			if captureErr := s.CaptureEventHold(ctx, hold.ID); captureErr != nil {
+				log.Printf("Failed to capture lapsed credit hold %s: %v", hold.ID, captureErr)
+				continue
+			}
+			captured++
+			continue
+		}
+		if releaseErr := s.ReleaseEventHold(ctx, hold.ID); releaseErr != nil {
+			log.Printf("Failed to release lapsed credit hold %s: %v", hold.ID, releaseErr)
+			continue
+		}
+		released++
+	}
+	return captured, released, nil
+}
+
+// RetryFailedRefunds retries credit refunds queued in payment.failed_refunds. A refund is
+// marked resolved in the same transaction that returns the credits, and given up on
+// after failedRefundMaxAttempts tries.
+func (s *CustomerCreditService) RetryFailedRefunds(ctx context.Context) (retried, resolved int, err *errLib.CommonError) {
+	ids, err := s.repo.GetPendingFailedRefundIDs(ctx, reconciliationBatchSize)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, id := range ids {
+		processed := false
+		txErr := s.repo.ExecuteInTransaction(ctx, func(txRepo *repositories.CustomerCreditRepository) *errLib.CommonError {
+			refund, getErr := txRepo.GetPendingFailedRefundForUpdate(ctx, id)
+			if getErr != nil || refund == nil {
+				return getErr
+			}
+			processed = true
+
+			var eventID *uuid.UUID
+			reference := ""
+			if refund.EventID.Valid {
+				eventID = &refund.EventID.UUID
+				reference = values.EventCreditReference(refund.EventID.UUID)
+			}
+
+			description := "Refund for failed event enrollment"
+			refunded, refundErr := txRepo.RefundCredits(ctx, refund.CustomerID, refund.CreditAmount, reference, description)
+			if refundErr != nil {
+				return refundErr
+			}
+			if refunded > 0 {
+				if logErr := txRepo.LogCreditTransaction(ctx, refund.CustomerID, refunded, dbUser.CreditTransactionTypeRefund, eventID, description); logErr != nil {
+					return logErr
+				}
+			}
+			return txRepo.ResolveFailedRefund(ctx, id)
+		})
+		if !processed {
+			if txErr != nil {
+				log.Printf("Failed to load failed refund %s: %v", id, txErr)
+			}
+			continue
+		}
+
+		retried++
+		if txErr == nil {
+			resolved++
+			continue
+		}
+
+		log.Printf("Retry of failed refund %s failed: %v", id, txErr)
+		status, recordErr := s.repo.RecordFailedRefundAttempt(ctx, id, txErr.Message, failedRefundMaxAttempts)
+		if recordErr != nil {
+			continue
+		}
+		if status == "failed" {
+			log.Printf("CRITICAL: Giving up on failed refund %s after %d attempts; it needs manual resolution", id, failedRefundMaxAttempts)
+		}
+	}
+	return retried, resolved, nil
+}
@@ -2,14 +2,16 @@ package services
 
 import (
 	"context"
-	"database/sql"
 	"log"
 	"time"
 
 	"api/internal/di"
+	"api/internal/domains/user/persistence/repositories"
 	dbUser "api/internal/domains/user/persistence/sqlc/generated"
+	values "api/internal/domains/user/values"
 	dbMembership "api/internal/domains/membership/persistence/sqlc/generated"
 	errLib "api/internal/libs/errors"
+	"api/utils/timezone"
 	"net/http"
 
 	"github.com/google/uuid"
@@ -18,33 +20,22 @@ import (
 type CreditService struct {
 	UserQueries       *dbUser.Queries
 	MembershipQueries *dbMembership.Queries
+	creditRepo        *repositories.CustomerCreditRepository
 }
 
 func NewCreditService(container *di.Container) *CreditService {
 	return &CreditService{
 		UserQueries:       container.Queries.UserDb,
 		MembershipQueries: container.Queries.MembershipDb,
+		creditRepo:        repositories.NewCustomerCreditRepository(container),
 	}
 }
 
-// GetCurrentWeekStart returns the Monday of the current week (ISO week)
+// GetCurrentWeekStart returns Monday midnight of the current ISO week in the default
+// facility timezone, independent of the zone the server runs in. Enrollment paths that
+// know the event's facility compute the window in that facility's zone instead.
 func (s *CreditService) GetCurrentWeekStart() time.Time {
-	now := time.Now()
-	
-	// Get the weekday (0 = Sunday, 1 = Monday, ..., 6 = Saturday)
-	weekday := now.Weekday()
-	
-	// Calculate days since Monday (ISO week starts on Monday)
-	daysSinceMonday := int(weekday-time.Monday) % 7
-	if daysSinceMonday < 0 {
-		daysSinceMonday += 7
-	}
-	
-	// Get Monday of this week
-	monday := now.AddDate(0, 0, -daysSinceMonday)
-	
-	// Return Monday at midnight
-	return time.Date(monday.Year(), monday.Month(), monday.Day(), 0, 0, 0, 0, monday.Location())
+	return timezone.WeekStart(time.Now(), timezone.Default())
 }
 
 // CanUseCredits checks if a customer can use the specified number of credits
@@ -106,35 +97,13 @@ func (s *CreditService) UseCredits(ctx context.Context, customerID uuid.UUID, cr
 		return errLib.New("Weekly credit limit exceeded", http.StatusBadRequest)
 	}
 	
-	// Deduct from customer's credit balance
-	rowsAffected, dbErr := s.UserQueries.DeductCredits(ctx, dbUser.DeductCreditsParams{
-		CustomerID: customerID,
-		Credits:    creditsToUse,
-	})
-	
-	if dbErr != nil {
-		return errLib.New("Failed to deduct credits", http.StatusInternalServerError)
-	}
-	
-	if rowsAffected == 0 {
-		return errLib.New("Insufficient credits", http.StatusBadRequest)
-	}
-	
-	// Update weekly usage tracking
-	weekStart := s.GetCurrentWeekStart()
-	dbErr = s.UserQueries.UpdateWeeklyCreditsUsed(ctx, dbUser.UpdateWeeklyCreditsUsedParams{
-		CustomerID:    customerID,
-		WeekStartDate: weekStart,
-		CreditsUsed:   creditsToUse,
+	// Deduct from customer's credit balance and track the week's usage together
+	return s.creditRepo.ExecuteInTransaction(ctx, func(txRepo *repositories.CustomerCreditRepository) *errLib.CommonError {
+		if err := txRepo.DeductCredits(ctx, customerID, creditsToUse, "", description); err != nil {
+			return err
+		}
+		return txRepo.UpdateWeeklyUsage(ctx, customerID, creditsToUse, s.GetCurrentWeekStart())
 	})
-	
-	if dbErr != nil {
-		// Log the error but don't fail the transaction - the credits were already deducted
-		// In production, you might want to implement compensating actions
-		return errLib.New("Failed to update weekly usage tracking", http.StatusInternalServerError)
-	}
-	
-	return nil
 }
 
 // AllocateCreditsOnMembershipPurchase awards credits when a customer purchases a membership
@@ -147,31 +116,20 @@ func (s *CreditService) AllocateCreditsOnMembershipPurchase(ctx context.Context,
 	
 	// Only allocate credits if this is a credit-based membership
 	if plan.CreditAllocation.Valid && plan.CreditAllocation.Int32 > 0 {
-		// Add credits to customer's account
-		_, err = s.UserQueries.RefundCredits(ctx, dbUser.RefundCreditsParams{
-			CustomerID: customerID,
-			Credits:    plan.CreditAllocation.Int32,
-		})
-		
-		if err != nil {
-			return errLib.New("Failed to allocate membership credits", http.StatusInternalServerError)
-		}
-		
-		// Log the credit transaction
+		// Add credits to customer's account and log the credit transaction
 		description := "Credits allocated for membership purchase"
-		
-		err = s.UserQueries.LogCreditTransaction(ctx, dbUser.LogCreditTransactionParams{
-			CustomerID:      customerID,
-			Amount:          plan.CreditAllocation.Int32,
-			TransactionType: dbUser.CreditTransactionTypeEnrollment,
-			EventID:         uuid.NullUUID{Valid: false}, // No event associated
-			Description:     sql.NullString{String: description, Valid: true},
+		return s.creditRepo.ExecuteInTransaction(ctx, func(txRepo *repositories.CustomerCreditRepository) *errLib.CommonError {
+			if grantErr := txRepo.GrantCredits(ctx, repositories.CreditGrantParams{
+				CustomerID:  customerID,
+				Amount:      plan.CreditAllocation.Int32,
+				Source:      values.CreditSourceMembership,
+				Description: description,
+			}); grantErr != nil {
+				return errLib.New("Failed to allocate membership credits", http.StatusInternalServerError)
+			}
+
+			return txRepo.LogCreditTransaction(ctx, customerID, plan.CreditAllocation.Int32, dbUser.CreditTransactionTypeEnrollment, nil, description)
 		})
-		
-		if err != nil {
-			// Log error but don't fail - credits were already allocated
-			return errLib.New("Failed to log credit transaction", http.StatusInternalServerError)
-		}
 	}
 	
 	return nil
@@ -2,9 +2,13 @@ package services
 
 import (
 	"api/internal/di"
+	auditValues "api/internal/domains/audit/audit_logs/values"
+	staffActivityLogs "api/internal/domains/audit/staff_activity_logs/service"
 	dbUser "api/internal/domains/user/persistence/sqlc/generated"
 	"api/internal/domains/user/persistence/repositories"
+	values "api/internal/domains/user/values"
 	errLib "api/internal/libs/errors"
+	"api/utils/timezone"
 	"context"
 	"time"
 
@@ -28,12 +32,14 @@ type RefundResult struct {
 }
 
 type CustomerCreditService struct {
-	repo *repositories.CustomerCreditRepository
+	repo                     *repositories.CustomerCreditRepository
+	staffActivityLogsService *staffActivityLogs.Service
 }
 
 func NewCustomerCreditService(container *di.Container) *CustomerCreditService {
 	return &CustomerCreditService{
-		repo: repositories.NewCustomerCreditRepository(container),
+		repo:                     repositories.NewCustomerCreditRepository(container),
+		staffActivityLogsService: staffActivityLogs.NewService(container),
 	}
 }
 
@@ -42,9 +48,16 @@ func (s *CustomerCreditService) GetCustomerCredits(ctx context.Context, customer
 	return s.repo.GetCustomerCredits(ctx, customerID)
 }
 
-// EnrollWithCredits attempts to enroll a customer in an event using credits
-func (s *CustomerCreditService) EnrollWithCredits(ctx context.Context, eventID, customerID uuid.UUID) *errLib.CommonError {
-	return s.repo.ExecuteInTransaction(ctx, func(txRepo *repositories.CustomerCreditRepository) *errLib.CommonError {
+// eventHoldTTL is how long credits stay held for an event enrollment. A hold still
+// open after that is settled by the credit reconciliation job.
+const eventHoldTTL = 15 * time.Minute
+
+// HoldCreditsForEvent sets aside the event's credit cost from the customer's balance
+// while their seat is reserved. The hold must then be captured with CaptureEventHold or
+// released with ReleaseEventHold.
+func (s *CustomerCreditService) HoldCreditsForEvent(ctx context.Context, eventID, customerID uuid.UUID) (uuid.UUID, *errLib.CommonError) {
+	var holdID uuid.UUID
+	err := s.repo.ExecuteInTransaction(ctx, func(txRepo *repositories.CustomerCreditRepository) *errLib.CommonError {
 		// Check if customer is already enrolled in this event (prevent duplicate payments)
 		isEnrolled, err := txRepo.IsCustomerEnrolledInEvent(ctx, eventID, customerID)
 		if err != nil {
@@ -63,52 +76,92 @@ func (s *CustomerCreditService) EnrollWithCredits(ctx context.Context, eventID,
 			return errLib.New("Event does not accept credit payments", http.StatusBadRequest)
 		}
 
-		// Check if customer has sufficient credits
-		hasSufficient, err := txRepo.HasSufficientCredits(ctx, customerID, *creditCost)
+		// Weekly limits reset at Monday midnight in the event facility's timezone
+		weekStart := timezone.WeekStart(time.Now(), txRepo.GetEventTimezone(ctx, eventID))
+
+		// Check weekly credit limit
+		canUseCredits, err := txRepo.CanUseCreditsWithinWeeklyLimit(ctx, customerID, *creditCost, weekStart)
 		if err != nil {
 			return err
 		}
-		if !hasSufficient {
-			return errLib.New("Insufficient credits", http.StatusBadRequest)
+		if !canUseCredits {
+			return errLib.New("Weekly credit limit exceeded", http.StatusBadRequest)
 		}
 
-		// Check weekly credit limit
-		canUseCredits, err := txRepo.CanUseCreditsWithinWeeklyLimit(ctx, customerID, *creditCost)
+		// Hold credits; this fails with "Insufficient credits" when the balance is short
+		holdID, err = txRepo.HoldCredits(ctx, repositories.CreditHoldParams{
+			CustomerID:  customerID,
+			Amount:      *creditCost,
+			Reference:   values.EventCreditReference(eventID),
+			EventID:     &eventID,
+			Description: "Event enrollment payment",
+			ExpiresAt:   time.Now().Add(eventHoldTTL),
+		})
 		if err != nil {
 			return err
 		}
-		if !canUseCredits {
-			return errLib.New("Weekly credit limit exceeded", http.StatusBadRequest)
+
+		// Held credits count towards the weekly limit until the hold is released
+		if err := txRepo.UpdateWeeklyUsage(ctx, customerID, *creditCost, weekStart); err != nil {
+			log.Printf("Failed to update weekly usage tracking: %v", err)
+			// Continue even if weekly tracking fails - the main operation succeeded
 		}
 
-		// Deduct credits
-		if err := txRepo.DeductCredits(ctx, customerID, *creditCost); err != nil {
+		return nil
+	})
+	return holdID, err
+}
+
+// CaptureEventHold spends the credits held for an event enrollment
+func (s *CustomerCreditService) CaptureEventHold(ctx context.Context, holdID uuid.UUID) *errLib.CommonError {
+	return s.repo.ExecuteInTransaction(ctx, func(txRepo *repositories.CustomerCreditRepository) *errLib.CommonError {
+		hold, captured, err := txRepo.CaptureHold(ctx, holdID)
+		if err != nil {
 			return err
 		}
+		if !captured {
+			return nil
+		}
 
-		// Log the transaction
-		description := "Event enrollment payment"
+		var eventID *uuid.UUID
+		if hold.EventID.Valid {
+			eventID = &hold.EventID.UUID
+		}
 		if err := txRepo.LogCreditTransaction(
 			ctx,
-			customerID,
-			-*creditCost, // negative amount for deduction
+			hold.CustomerID,
+			-hold.Amount, // negative amount for deduction
 			dbUser.CreditTransactionTypeEnrollment,
-			&eventID,
-			description,
+			eventID,
+			hold.Description,
 		); err != nil {
 			log.Printf("Failed to log credit transaction: %v", err)
 			// Continue even if logging fails - the main operation succeeded
 		}
+		return nil
+	})
+}
 
-		// Update weekly usage tracking
-		log.Printf("Updating weekly usage for customer %s, credits: %d", customerID, *creditCost)
-		if err := txRepo.UpdateWeeklyUsage(ctx, customerID, *creditCost); err != nil {
-			log.Printf("Failed to update weekly usage tracking: %v", err)
-			// Continue even if weekly tracking fails - the main operation succeeded
-		} else {
-			log.Printf("Successfully updated weekly usage")
+// ReleaseEventHold gives the credits held for an event enrollment back and takes them
+// off the week's usage
+func (s *CustomerCreditService) ReleaseEventHold(ctx context.Context, holdID uuid.UUID) *errLib.CommonError {
+	return s.repo.ExecuteInTransaction(ctx, func(txRepo *repositories.CustomerCreditRepository) *errLib.CommonError {
+		hold, released, err := txRepo.ReleaseHold(ctx, holdID)
+		if err != nil {
+			return err
+		}
+		if !released {
+			return nil
 		}
 
+		loc := timezone.Default()
+		if hold.EventID.Valid {
+			loc = txRepo.GetEventTimezone(ctx, hold.EventID.UUID)
+		}
+		if err := txRepo.ReduceWeeklyUsage(ctx, hold.CustomerID, hold.Amount, timezone.WeekStart(hold.CreatedAt, loc)); err != nil {
+			log.Printf("Failed to reduce weekly usage for released hold %s: %v", holdID, err)
+			// Continue even if weekly usage reduction fails - the credits are back
+		}
 		return nil
 	})
 }
@@ -126,17 +179,21 @@ func (s *CustomerCreditService) RefundCreditsForCancellation(ctx context.Context
 			return nil
 		}
 
-		// Refund credits
-		if err := txRepo.RefundCredits(ctx, customerID, *creditCost); err != nil {
+		// Refund the credits spent on this event, if not refunded already
+		description := "Refund for cancelled event enrollment"
+		refunded, err := txRepo.RefundCredits(ctx, customerID, *creditCost, values.EventCreditReference(eventID), description)
+		if err != nil {
 			return err
 		}
+		if refunded == 0 {
+			return nil
+		}
 
 		// Log the refund transaction
-		description := "Refund for cancelled event enrollment"
 		if err := txRepo.LogCreditTransaction(
 			ctx,
 			customerID,
-			*creditCost, // positive amount for refund
+			refunded, // positive amount for refund
 			dbUser.CreditTransactionTypeRefund,
 			&eventID,
 			description,
@@ -149,38 +206,90 @@ func (s *CustomerCreditService) RefundCreditsForCancellation(ctx context.Context
 	})
 }
 
-// AddCredits adds credits to a customer's account (admin function)
-func (s *CustomerCreditService) AddCredits(ctx context.Context, customerID uuid.UUID, amount int32, description string) *errLib.CommonError {
+// AddCredits adds credits to a customer's account (admin function). expiresAt may be
+// nil for credits that never expire.
+func (s *CustomerCreditService) AddCredits(ctx context.Context, customerID uuid.UUID, amount int32, expiresAt *time.Time, description string) *errLib.CommonError {
 	return s.repo.ExecuteInTransaction(ctx, func(txRepo *repositories.CustomerCreditRepository) *errLib.CommonError {
-		// Ensure customer credit record exists (will create with 0 balance if not exists)
-		if err := txRepo.EnsureCustomerCreditsExist(ctx, customerID); err != nil {
+		before, err := txRepo.GetCustomerCredits(ctx, customerID)
+		if err != nil {
 			return err
 		}
 
-		// Add credits (using refund method for positive addition)
-		if err := txRepo.RefundCredits(ctx, customerID, amount); err != nil {
+		if err := s.grantCredits(ctx, txRepo, customerID, amount, values.CreditSourceAdjustment, expiresAt, description); err != nil {
 			return err
 		}
 
-		// Log the transaction
-		if err := txRepo.LogCreditTransaction(
-			ctx,
-			customerID,
-			amount, // positive amount for addition
-			dbUser.CreditTransactionTypeAdminAdjustment,
-			nil, // no event associated
-			description,
-		); err != nil {
-			return err
-		}
+		return s.recordAdjustment(ctx, txRepo, customerID, "add_credits", before)
+	})
+}
 
-		return nil
+// AddPurchasedCredits adds credits bought with a credit package, valid for the
+// package's validity period
+func (s *CustomerCreditService) AddPurchasedCredits(ctx context.Context, customerID uuid.UUID, amount int32, validityDays *int32, description string) *errLib.CommonError {
+	return s.GrantCredits(ctx, customerID, amount, values.CreditSourcePurchase, values.CreditExpiry(validityDays, time.Now()), description)
+}
+
+// GrantCredits adds credits from the given source to a customer's account
+func (s *CustomerCreditService) GrantCredits(ctx context.Context, customerID uuid.UUID, amount int32, source string, expiresAt *time.Time, description string) *errLib.CommonError {
+	return s.repo.ExecuteInTransaction(ctx, func(txRepo *repositories.CustomerCreditRepository) *errLib.CommonError {
+		return s.grantCredits(ctx, txRepo, customerID, amount, source, expiresAt, description)
+	})
+}
+
+func (s *CustomerCreditService) grantCredits(ctx context.Context, txRepo *repositories.CustomerCreditRepository, customerID uuid.UUID, amount int32, source string, expiresAt *time.Time, description string) *errLib.CommonError {
+	if err := txRepo.GrantCredits(ctx, repositories.CreditGrantParams{
+		CustomerID:  customerID,
+		Amount:      amount,
+		Source:      source,
+		ExpiresAt:   expiresAt,
+		Description: description,
+	}); err != nil {
+		return err
+	}
+
+	transactionType := dbUser.CreditTransactionTypeAdminAdjustment
+	if source == values.CreditSourcePurchase {
+		transactionType = dbUser.CreditTransactionTypePurchase
+	}
+
+	// Log the transaction
+	return txRepo.LogCreditTransaction(
+		ctx,
+		customerID,
+		amount, // positive amount for addition
+		transactionType,
+		nil, // no event associated
+		description,
+	)
+}
+
+// recordAdjustment records an admin change to a customer's balance in the audit log,
+// in the transaction of the change. The staff member's description stays on the credit
+// transaction: the audit log cannot be erased, so it only records the balance.
+func (s *CustomerCreditService) recordAdjustment(ctx context.Context, txRepo *repositories.CustomerCreditRepository, customerID uuid.UUID, action string, before int32) *errLib.CommonError {
+	after, err := txRepo.GetCustomerCredits(ctx, customerID)
+	if err != nil {
+		return err
+	}
+
+	return s.staffActivityLogsService.RecordChange(ctx, txRepo.Tx(), uuid.Nil, auditValues.Entry{
+		EntityType:  "customer_credits",
+		EntityID:    customerID.String(),
+		Action:      action,
+		Before:      map[string]int32{"balance": before},
+		After:       map[string]int32{"balance": after},
+		Description: "Adjusted credits of customer " + customerID.String(),
 	})
 }
 
 // DeductCredits removes credits from a customer's account (admin function)
 func (s *CustomerCreditService) DeductCredits(ctx context.Context, customerID uuid.UUID, amount int32, description string) *errLib.CommonError {
 	return s.repo.ExecuteInTransaction(ctx, func(txRepo *repositories.CustomerCreditRepository) *errLib.CommonError {
+		before, err := txRepo.GetCustomerCredits(ctx, customerID)
+		if err != nil {
+			return err
+		}
+
 		// Check if customer has sufficient credits
 		hasSufficient, err := txRepo.HasSufficientCredits(ctx, customerID, amount)
 		if err != nil {
@@ -191,7 +300,7 @@ func (s *CustomerCreditService) DeductCredits(ctx context.Context, customerID uu
 		}
 
 		// Deduct credits
-		if err := txRepo.DeductCredits(ctx, customerID, amount); err != nil {
+		if err := txRepo.DeductCredits(ctx, customerID, amount, "", description); err != nil {
 			return err
 		}
 
@@ -207,7 +316,7 @@ func (s *CustomerCreditService) DeductCredits(ctx context.Context, customerID uu
 			return err
 		}
 
-		return nil
+		return s.recordAdjustment(ctx, txRepo, customerID, "deduct_credits", before)
 	})
 }
 
@@ -283,21 +392,27 @@ func (s *CustomerCreditService) RefundCreditsWithAudit(
 	}
 
 	// Execute refund in transaction
+	description := "Admin refund for event removal"
+	if reason != "" {
+		description = "Admin refund: " + reason
+	}
+	var refunded int32
 	txErr := s.repo.ExecuteInTransaction(ctx, func(txRepo *repositories.CustomerCreditRepository) *errLib.CommonError {
-		// 1. Add credits back to customer
-		if refundErr := txRepo.RefundCredits(ctx, customerID, *creditAmount); refundErr != nil {
+		// 1. Add credits back to customer, unless they were refunded already
+		var refundErr *errLib.CommonError
+		refunded, refundErr = txRepo.RefundCredits(ctx, customerID, *creditAmount, values.EventCreditReference(eventID), description)
+		if refundErr != nil {
 			return refundErr
 		}
+		if refunded == 0 {
+			return nil
+		}
 
 		// 2. Log the transaction (type: refund)
-		description := "Admin refund for event removal"
-		if reason != "" {
-			description = "Admin refund: " + reason
-		}
 		if logErr := txRepo.LogCreditTransaction(
 			ctx,
 			customerID,
-			*creditAmount, // positive amount for refund
+			refunded, // positive amount for refund
 			dbUser.CreditTransactionTypeRefund,
 			&eventID,
 			description,
@@ -307,7 +422,7 @@ func (s *CustomerCreditService) RefundCreditsWithAudit(
 		}
 
 		// 3. Reduce weekly usage so customer can use those credits again this week
-		if reduceErr := txRepo.ReduceWeeklyUsage(ctx, customerID, *creditAmount); reduceErr != nil {
+		if reduceErr := txRepo.ReduceWeeklyUsage(ctx, customerID, refunded, timezone.WeekStart(time.Now(), txRepo.GetEventTimezone(ctx, eventID))); reduceErr != nil {
 			log.Printf("[CREDIT-REFUND] Failed to reduce weekly usage: %v", reduceErr)
 			// Continue even if weekly usage reduction fails - the refund already happened
 		}
@@ -317,7 +432,7 @@ func (s *CustomerCreditService) RefundCreditsWithAudit(
 			CustomerID:      customerID,
 			EventID:         eventID,
 			PerformedBy:     performedBy,
-			CreditsRefunded: *creditAmount,
+			CreditsRefunded: refunded,
 			EventName:       eventSnapshot.Name,
 			EventStartAt:    eventSnapshot.StartAt,
 			ProgramName:     eventSnapshot.ProgramName,
@@ -337,12 +452,16 @@ func (s *CustomerCreditService) RefundCreditsWithAudit(
 	if txErr != nil {
 		return nil, txErr
 	}
+	if refunded == 0 {
+		log.Printf("[CREDIT-REFUND] Credits for customer %s event %s were already refunded", customerID, eventID)
+		return result, nil
+	}
 
-	result.CreditsRefunded = *creditAmount
+	result.CreditsRefunded = refunded
 	result.Processed = true
 
 	log.Printf("[CREDIT-REFUND] Refunded %d credits to customer %s for event %s by %s",
-		*creditAmount, customerID, eventID, performedBy)
+		refunded, customerID, eventID, performedBy)
 
 	return result, nil
 }
@@ -0,0 +1,576 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"api/internal/di"
+	stripeService "api/internal/domains/payment/services/stripe"
+	"api/internal/domains/user/persistence/repositories"
+	repo "api/internal/domains/user/persistence/repository"
+	db "api/internal/domains/user/persistence/sqlc/generated"
+	values "api/internal/domains/user/values"
+	errLib "api/internal/libs/errors"
+	"api/internal/services/gcp"
+	contextUtils "api/utils/context"
+	txUtils "api/utils/db"
+	"api/utils/timezone"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stripe/stripe-go/v81"
+	"github.com/stripe/stripe-go/v81/invoice"
+	"github.com/stripe/stripe-go/v81/invoiceitem"
+)
+
+const (
+	FreezeReasonVacation = "vacation"
+	FreezeReasonInjury   = "injury"
+	FreezeReasonOther    = "other"
+)
+
+const (
+	FreezeStatusScheduled = "scheduled"
+	FreezeStatusActive    = "active"
+	FreezeStatusCompleted = "completed"
+	FreezeStatusCancelled = "cancelled"
+)
+
+// freezeBatchSize caps how many freezes one run of the freeze job starts or ends
+const freezeBatchSize = 100
+
+// MembershipFreezeService lets customers freeze their Stripe membership within the
+// freeze policy of its plan
+type MembershipFreezeService struct {
+	customerRepo  *repo.CustomerRepository
+	creditRepo    *repositories.CustomerCreditRepository
+	stripeService *stripeService.SubscriptionService
+	db            *sql.DB
+}
+
+func NewMembershipFreezeService(container *di.Container) *MembershipFreezeService {
+	return &MembershipFreezeService{
+		customerRepo:  repo.NewCustomerRepository(container),
+		creditRepo:    repositories.NewCustomerCreditRepository(container),
+		stripeService: stripeService.NewSubscriptionService(container),
+		db:            container.DB,
+	}
+}
+
+type RequestFreezeParams struct {
+	CustomerID           uuid.UUID
+	StripeSubscriptionID string
+	Reason               string
+	Notes                string
+	StartDate            time.Time // first frozen day
+	EndDate              time.Time // day the membership resumes
+	MedicalNote          io.Reader // nil when no note was uploaded
+	MedicalNoteFileName  string
+}
+
+// freezeRequest is what the freeze policy is checked against
+type freezeRequest struct {
+	Reason         string
+	StartDate      time.Time
+	EndDate        time.Time
+	HasMedicalNote bool
+}
+
+// RequestFreeze schedules a freeze of the customer's membership and charges the plan's
+// freeze fee. A freeze starting today starts straight away; later ones are started by
+// the freeze job.
+func (s *MembershipFreezeService) RequestFreeze(ctx context.Context, params RequestFreezeParams) (db.UsersMembershipFreeze, *errLib.CommonError) {
+	queries := s.customerRepo.Queries
+	ctx = context.WithValue(ctx, contextUtils.UserIDKey, params.CustomerID)
+
+	membership, err := s.getFreezableMembership(ctx, params.CustomerID, params.StripeSubscriptionID)
+	if err != nil {
+		return db.UsersMembershipFreeze{}, err
+	}
+	if membership.Status != db.MembershipMembershipStatusActive {
+		return db.UsersMembershipFreeze{}, errLib.New("Only active memberships can be frozen", http.StatusConflict)
+	}
+
+	policy, dbErr := queries.GetMembershipFreezePolicy(ctx, membership.MembershipPlanID)
+	if dbErr != nil {
+		if errors.Is(dbErr, sql.ErrNoRows) {
+			return db.UsersMembershipFreeze{}, errLib.New("This membership plan cannot be frozen", http.StatusForbidden)
+		}
+		log.Printf("Failed to get freeze policy of plan %s: %v", membership.MembershipPlanID, dbErr)
+		return db.UsersMembershipFreeze{}, errLib.New("Failed to get freeze policy", http.StatusInternalServerError)
+	}
+
+	if _, dbErr = queries.GetOpenMembershipFreeze(ctx, membership.ID); dbErr == nil {
+		return db.UsersMembershipFreeze{}, errLib.New("Membership already has a scheduled or active freeze", http.StatusConflict)
+	} else if !errors.Is(dbErr, sql.ErrNoRows) {
+		log.Printf("Failed to check open freezes of membership %s: %v", membership.ID, dbErr)
+		return db.UsersMembershipFreeze{}, errLib.New("Failed to check existing freezes", http.StatusInternalServerError)
+	}
+
+	recent, dbErr := queries.CountRecentMembershipFreezes(ctx, db.CountRecentMembershipFreezesParams{
+		CustomerMembershipPlanID: membership.ID,
+		Since:                    params.StartDate.AddDate(-1, 0, 0),
+	})
+	if dbErr != nil {
+		log.Printf("Failed to count freezes of membership %s: %v", membership.ID, dbErr)
+		return db.UsersMembershipFreeze{}, errLib.New("Failed to check existing freezes", http.StatusInternalServerError)
+	}
+
+	request := freezeRequest{
+		Reason:         params.Reason,
+		StartDate:      params.StartDate,
+		EndDate:        params.EndDate,
+		HasMedicalNote: params.MedicalNote != nil,
+	}
+	if err = validateFreezeRequest(policy, request, facilityToday(), recent); err != nil {
+		return db.UsersMembershipFreeze{}, err
+	}
+
+	// Checks ownership and that Stripe still bills the subscription
+	sub, err := s.stripeService.GetSubscription(ctx, params.StripeSubscriptionID)
+	if err != nil {
+		return db.UsersMembershipFreeze{}, err
+	}
+	if sub.Status != stripe.SubscriptionStatusActive || sub.PauseCollection != nil {
+		return db.UsersMembershipFreeze{}, errLib.New("Only active subscriptions can be frozen", http.StatusConflict)
+	}
+
+	var medicalNoteURL string
+	if params.MedicalNote != nil {
+		fileName := fmt.Sprintf("membership_freezes/%s/medical_note_%d%s",
+			params.CustomerID, time.Now().Unix(), strings.ToLower(filepath.Ext(params.MedicalNoteFileName)))
+		medicalNoteURL, err = gcp.UploadImageToGCP(params.MedicalNote, fileName)
+		if err != nil {
+			return db.UsersMembershipFreeze{}, err
+		}
+	}
+
+	var freeze db.UsersMembershipFreeze
+	txErr := txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		txQueries := s.customerRepo.WithTx(tx).Queries
+
+		created, dbErr := txQueries.CreateMembershipFreeze(ctx, db.CreateMembershipFreezeParams{
+			CustomerID:               params.CustomerID,
+			CustomerMembershipPlanID: membership.ID,
+			StripeSubscriptionID:     params.StripeSubscriptionID,
+			Reason:                   params.Reason,
+			Notes:                    sql.NullString{String: params.Notes, Valid: params.Notes != ""},
+			MedicalNoteUrl:           sql.NullString{String: medicalNoteURL, Valid: medicalNoteURL != ""},
+			StartDate:                params.StartDate,
+			EndDate:                  params.EndDate,
+			FeeAmount:                policy.FeeAmount,
+		})
+		if dbErr != nil {
+			var pqErr *pq.Error
+			if errors.As(dbErr, &pqErr) && pqErr.Constraint == "idx_membership_freezes_one_open" {
+				return errLib.New("Membership already has a scheduled or active freeze", http.StatusConflict)
+			}
+			log.Printf("Failed to create freeze of membership %s: %v", membership.ID, dbErr)
+			return errLib.New("Failed to create freeze", http.StatusInternalServerError)
+		}
+		freeze = created
+
+		if policy.FeeAmount == 0 {
+			return nil
+		}
+
+		// Charged last, so a failed charge rolls the freeze back
+		invoiceID, err := s.chargeFreezeFee(ctx, sub, created)
+		if err != nil {
+			return err
+		}
+		freeze.StripeFeeInvoiceID = sql.NullString{String: invoiceID, Valid: true}
+
+		if dbErr = txQueries.SetMembershipFreezeFeeInvoice(ctx, db.SetMembershipFreezeFeeInvoiceParams{
+			ID:                 created.ID,
+			StripeFeeInvoiceID: freeze.StripeFeeInvoiceID,
+		}); dbErr != nil {
+			log.Printf("Warning: Failed to record fee invoice %s of freeze %s: %v", invoiceID, created.ID, dbErr)
+		}
+		return nil
+	})
+	if txErr != nil {
+		if medicalNoteURL != "" {
+			if deleteErr := gcp.DeleteFileFromGCP(medicalNoteURL); deleteErr != nil {
+				log.Printf("Warning: Failed to delete medical note %s: %s", medicalNoteURL, deleteErr.Message)
+			}
+		}
+		return db.UsersMembershipFreeze{}, txErr
+	}
+
+	log.Printf("Customer %s scheduled a %s freeze of subscription %s from %s to %s",
+		params.CustomerID, params.Reason, params.StripeSubscriptionID,
+		params.StartDate.Format("2006-01-02"), params.EndDate.Format("2006-01-02"))
+
+	if !freeze.StartDate.After(facilityToday()) {
+		if err = s.startFreeze(ctx, freeze); err != nil {
+			// The freeze job retries on its next run
+			log.Printf("Failed to start freeze %s: %s", freeze.ID, err.Message)
+			return freeze, nil
+		}
+		if started, dbErr := queries.GetOpenMembershipFreeze(ctx, membership.ID); dbErr == nil {
+			freeze = started
+		}
+	}
+
+	return freeze, nil
+}
+
+// EndFreeze cancels the customer's upcoming freeze, or ends a running one early so the
+// membership and its billing resume today. Fees and forfeited credits are not returned.
+func (s *MembershipFreezeService) EndFreeze(ctx context.Context, customerID uuid.UUID, stripeSubscriptionID string) (db.UsersMembershipFreeze, *errLib.CommonError) {
+	ctx = context.WithValue(ctx, contextUtils.UserIDKey, customerID)
+
+	membership, err := s.getFreezableMembership(ctx, customerID, stripeSubscriptionID)
+	if err != nil {
+		return db.UsersMembershipFreeze{}, err
+	}
+
+	freeze, dbErr := s.customerRepo.Queries.GetOpenMembershipFreeze(ctx, membership.ID)
+	if dbErr != nil {
+		if errors.Is(dbErr, sql.ErrNoRows) {
+			return db.UsersMembershipFreeze{}, errLib.New("Membership has no scheduled or active freeze", http.StatusNotFound)
+		}
+		log.Printf("Failed to get open freeze of membership %s: %v", membership.ID, dbErr)
+		return db.UsersMembershipFreeze{}, errLib.New("Failed to get freeze", http.StatusInternalServerError)
+	}
+
+	if freeze.Status == FreezeStatusScheduled {
+		cancelled, dbErr := s.customerRepo.Queries.CancelMembershipFreeze(ctx, freeze.ID)
+		if dbErr != nil {
+			log.Printf("Failed to cancel freeze %s: %v", freeze.ID, dbErr)
+			return db.UsersMembershipFreeze{}, errLib.New("Failed to cancel freeze", http.StatusInternalServerError)
+		}
+		if cancelled == 0 {
+			return db.UsersMembershipFreeze{}, errLib.New("Freeze has already started, try again", http.StatusConflict)
+		}
+		freeze.Status = FreezeStatusCancelled
+		return freeze, nil
+	}
+
+	endDate := facilityToday()
+	if !endDate.After(freeze.StartDate) {
+		endDate = freeze.StartDate.AddDate(0, 0, 1)
+	}
+	if err = s.endFreeze(ctx, freeze, endDate, true); err != nil {
+		return db.UsersMembershipFreeze{}, err
+	}
+
+	freeze.Status = FreezeStatusCompleted
+	freeze.EndDate = endDate
+	return freeze, nil
+}
+
+// ListFreezes lists a customer's freezes, latest first
+func (s *MembershipFreezeService) ListFreezes(ctx context.Context, customerID uuid.UUID) ([]db.UsersMembershipFreeze, *errLib.CommonError) {
+	freezes, err := s.customerRepo.Queries.ListCustomerMembershipFreezes(ctx, customerID)
+	if err != nil {
+		log.Printf("Failed to list freezes of customer %s: %v", customerID, err)
+		return nil, errLib.New("Failed to list membership freezes", http.StatusInternalServerError)
+	}
+	return freezes, nil
+}
+
+// RunDueFreezes starts the freezes whose first day has come and ends those whose
+// membership resumes today
+func (s *MembershipFreezeService) RunDueFreezes(ctx context.Context) error {
+	today := facilityToday()
+
+	starts, err := s.customerRepo.Queries.ListDueMembershipFreezeStarts(ctx, db.ListDueMembershipFreezeStartsParams{
+		Today:    today,
+		RowLimit: freezeBatchSize,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list freezes due to start: %w", err)
+	}
+
+	started, failed := 0, 0
+	for _, freeze := range starts {
+		customerCtx := context.WithValue(ctx, contextUtils.UserIDKey, freeze.CustomerID)
+		if startErr := s.startFreeze(customerCtx, freeze); startErr != nil {
+			log.Printf("[FREEZES] Failed to start freeze %s: %s", freeze.ID, startErr.Message)
+			failed++
+
+			// The subscription can no longer be frozen, e.g. it was cancelled meanwhile
+			if startErr.HTTPCode == http.StatusConflict {
+				if _, dbErr := s.customerRepo.Queries.CancelMembershipFreeze(ctx, freeze.ID); dbErr != nil {
+					log.Printf("[FREEZES] Failed to cancel freeze %s: %v", freeze.ID, dbErr)
+				}
+			}
+			continue
+		}
+		started++
+	}
+
+	ends, err := s.customerRepo.Queries.ListDueMembershipFreezeEnds(ctx, db.ListDueMembershipFreezeEndsParams{
+		Today:    today,
+		RowLimit: freezeBatchSize,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list freezes due to end: %w", err)
+	}
+
+	ended := 0
+	for _, freeze := range ends {
+		customerCtx := context.WithValue(ctx, contextUtils.UserIDKey, freeze.CustomerID)
+		if endErr := s.endFreeze(customerCtx, freeze, freeze.EndDate, false); endErr != nil {
+			log.Printf("[FREEZES] Failed to end freeze %s: %s", freeze.ID, endErr.Message)
+			failed++
+			continue
+		}
+		ended++
+	}
+
+	log.Printf("[FREEZES] Due freezes: started=%d, ended=%d, failed=%d", started, ended, failed)
+	return nil
+}
+
+// startFreeze pauses billing until the membership resumes, marks the membership paused
+// and forfeits the credits of the frozen days. ctx must carry the customer's user ID.
+func (s *MembershipFreezeService) startFreeze(ctx context.Context, freeze db.UsersMembershipFreeze) *errLib.CommonError {
+	membership, err := s.getFreezableMembership(ctx, freeze.CustomerID, freeze.StripeSubscriptionID)
+	if err != nil {
+		return err
+	}
+
+	resumesAt := timezone.At(freeze.EndDate, 0, 0, 0, timezone.Default())
+	sub, err := s.stripeService.FreezeSubscription(ctx, freeze.StripeSubscriptionID, freeze.ID.String(), resumesAt)
+	if err != nil {
+		return err
+	}
+
+	periodDays := int((sub.CurrentPeriodEnd - sub.CurrentPeriodStart + 86399) / 86400)
+	forfeited := prorateFreezeCredits(membership.CreditAllocation.Int32, freezeDays(freeze.StartDate, freeze.EndDate), periodDays)
+
+	return txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		queries := s.customerRepo.WithTx(tx).Queries
+		credits := s.creditRepo.WithTx(tx)
+
+		if forfeited > 0 {
+			// Lapsed credits can't be forfeited; take them out of the balance first
+			if _, expireErr := credits.ExpireLapsedGrants(ctx, freeze.CustomerID); expireErr != nil {
+				return expireErr
+			}
+			balance, balanceErr := credits.GetCustomerCredits(ctx, freeze.CustomerID)
+			if balanceErr != nil {
+				return balanceErr
+			}
+			if forfeited > balance {
+				forfeited = balance
+			}
+		}
+
+		rows, dbErr := queries.StartMembershipFreeze(ctx, db.StartMembershipFreezeParams{
+			ID:               freeze.ID,
+			CreditsForfeited: forfeited,
+		})
+		if dbErr != nil {
+			log.Printf("Failed to start freeze %s: %v", freeze.ID, dbErr)
+			return errLib.New("Failed to start freeze", http.StatusInternalServerError)
+		}
+		if rows == 0 {
+			return nil // cancelled or started by another run
+		}
+
+		if _, dbErr = queries.PauseFrozenMembership(ctx, freeze.CustomerMembershipPlanID); dbErr != nil {
+			log.Printf("Failed to pause membership %s: %v", freeze.CustomerMembershipPlanID, dbErr)
+			return errLib.New("Failed to pause membership", http.StatusInternalServerError)
+		}
+
+		if forfeited > 0 {
+			description := fmt.Sprintf("Credits forfeited for membership freeze %s to %s",
+				freeze.StartDate.Format("2006-01-02"), freeze.EndDate.Format("2006-01-02"))
+			if forfeitErr := credits.DeductCredits(ctx, freeze.CustomerID, forfeited,
+				values.MembershipFreezeCreditReference(freeze.ID), description); forfeitErr != nil {
+				log.Printf("Failed to forfeit credits of customer %s: %v", freeze.CustomerID, forfeitErr)
+				return errLib.New("Failed to forfeit credits", http.StatusInternalServerError)
+			}
+			if dbErr = queries.LogCreditTransaction(ctx, db.LogCreditTransactionParams{
+				CustomerID:      freeze.CustomerID,
+				Amount:          -forfeited,
+				TransactionType: db.CreditTransactionTypeAdminAdjustment,
+				Description:     sql.NullString{String: description, Valid: true},
+			}); dbErr != nil {
+				log.Printf("Failed to log forfeited credits of customer %s: %v", freeze.CustomerID, dbErr)
+				return errLib.New("Failed to log credit transaction", http.StatusInternalServerError)
+			}
+		}
+
+		log.Printf("Started freeze %s of subscription %s (%d credits forfeited)", freeze.ID, freeze.StripeSubscriptionID, forfeited)
+		return nil
+	})
+}
+
+// endFreeze resumes the membership. Stripe resumes billing by itself on the end date, so
+// only an early end has to succeed in Stripe. ctx must carry the customer's user ID.
+func (s *MembershipFreezeService) endFreeze(ctx context.Context, freeze db.UsersMembershipFreeze, endDate time.Time, early bool) *errLib.CommonError {
+	if _, err := s.stripeService.UnfreezeSubscription(ctx, freeze.StripeSubscriptionID, freeze.ID.String()); err != nil {
+		if early {
+			return err
+		}
+		log.Printf("Warning: Failed to clear freeze %s in Stripe: %s", freeze.ID, err.Message)
+	}
+
+	return txUtils.ExecuteInTx(ctx, s.db, func(tx *sql.Tx) *errLib.CommonError {
+		queries := s.customerRepo.WithTx(tx).Queries
+
+		rows, dbErr := queries.EndMembershipFreeze(ctx, db.EndMembershipFreezeParams{
+			ID:      freeze.ID,
+			EndDate: endDate,
+		})
+		if dbErr != nil {
+			log.Printf("Failed to end freeze %s: %v", freeze.ID, dbErr)
+			return errLib.New("Failed to end freeze", http.StatusInternalServerError)
+		}
+		if rows == 0 {
+			return nil // already ended
+		}
+
+		if _, dbErr = queries.ResumeFrozenMembership(ctx, freeze.CustomerMembershipPlanID); dbErr != nil {
+			log.Printf("Failed to resume membership %s: %v", freeze.CustomerMembershipPlanID, dbErr)
+			return errLib.New("Failed to resume membership", http.StatusInternalServerError)
+		}
+
+		log.Printf("Ended freeze %s of subscription %s on %s", freeze.ID, freeze.StripeSubscriptionID, endDate.Format("2006-01-02"))
+		return nil
+	})
+}
+
+func (s *MembershipFreezeService) getFreezableMembership(ctx context.Context, customerID uuid.UUID, stripeSubscriptionID string) (db.GetFreezableMembershipRow, *errLib.CommonError) {
+	membership, err := s.customerRepo.Queries.GetFreezableMembership(ctx, db.GetFreezableMembershipParams{
+		CustomerID:           customerID,
+		StripeSubscriptionID: sql.NullString{String: stripeSubscriptionID, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.GetFreezableMembershipRow{}, errLib.New("No membership found for this subscription", http.StatusNotFound)
+		}
+		log.Printf("Failed to get membership of subscription %s: %v", stripeSubscriptionID, err)
+		return db.GetFreezableMembershipRow{}, errLib.New("Failed to get membership", http.StatusInternalServerError)
+	}
+	return membership, nil
+}
+
+// chargeFreezeFee bills the freeze fee on its own invoice, paid with the subscription's
+// payment method, and returns the invoice ID
+func (s *MembershipFreezeService) chargeFreezeFee(ctx context.Context, sub *stripe.Subscription, freeze db.UsersMembershipFreeze) (string, *errLib.CommonError) {
+	if sub.Customer == nil {
+		return "", errLib.New("Subscription has no customer", http.StatusInternalServerError)
+	}
+
+	invoiceParams := &stripe.InvoiceParams{
+		Customer:                    stripe.String(sub.Customer.ID),
+		CollectionMethod:            stripe.String(string(stripe.InvoiceCollectionMethodChargeAutomatically)),
+		AutoAdvance:                 stripe.Bool(false),
+		PendingInvoiceItemsBehavior: stripe.String("exclude"),
+		Description:                 stripe.String("Membership freeze fee"),
+		Metadata: map[string]string{
+			"freeze_id": freeze.ID.String(),
+			"userID":    freeze.CustomerID.String(),
+		},
+	}
+	if sub.DefaultPaymentMethod != nil {
+		invoiceParams.DefaultPaymentMethod = stripe.String(sub.DefaultPaymentMethod.ID)
+	}
+	invoiceParams.IdempotencyKey = stripe.String("freeze-fee-invoice:" + freeze.ID.String())
+
+	inv, err := invoice.New(invoiceParams)
+	if err != nil {
+		log.Printf("[STRIPE] Failed to create fee invoice for freeze %s: %v", freeze.ID, err)
+		return "", errLib.New("Failed to charge the freeze fee", http.StatusInternalServerError)
+	}
+
+	itemParams := &stripe.InvoiceItemParams{
+		Customer: stripe.String(sub.Customer.ID),
+		Invoice:  stripe.String(inv.ID),
+		Amount:   stripe.Int64(int64(freeze.FeeAmount)),
+		Currency: stripe.String("cad"),
+		Description: stripe.String(fmt.Sprintf("Membership freeze %s to %s",
+			freeze.StartDate.Format("2006-01-02"), freeze.EndDate.Format("2006-01-02"))),
+	}
+	itemParams.IdempotencyKey = stripe.String("freeze-fee-item:" + freeze.ID.String())
+	if _, err = invoiceitem.New(itemParams); err != nil {
+		log.Printf("[STRIPE] Failed to add fee to invoice %s: %v", inv.ID, err)
+		return "", errLib.New("Failed to charge the freeze fee", http.StatusInternalServerError)
+	}
+
+	if _, err = invoice.FinalizeInvoice(inv.ID, nil); err != nil {
+		log.Printf("[STRIPE] Failed to finalize fee invoice %s: %v", inv.ID, err)
+		return "", errLib.New("Failed to charge the freeze fee", http.StatusInternalServerError)
+	}
+
+	if _, err = invoice.Pay(inv.ID, nil); err != nil {
+		log.Printf("[STRIPE] Freeze fee payment failed on invoice %s: %v", inv.ID, err)
+		if _, voidErr := invoice.VoidInvoice(inv.ID, nil); voidErr != nil {
+			log.Printf("[STRIPE] Failed to void fee invoice %s: %v", inv.ID, voidErr)
+		}
+		return "", errLib.New("The freeze fee could not be charged to your payment method", http.StatusPaymentRequired)
+	}
+
+	log.Printf("[STRIPE] Charged freeze fee of %d cents on invoice %s", freeze.FeeAmount, inv.ID)
+	return inv.ID, nil
+}
+
+// validateFreezeRequest checks a freeze against the plan's policy. Dates are calendar days
+// at midnight UTC; freezesThisYear counts the membership's freezes that started in the
+// year before the requested start.
+func validateFreezeRequest(policy db.MembershipFreezePolicy, req freezeRequest, today time.Time, freezesThisYear int64) *errLib.CommonError {
+	switch req.Reason {
+	case FreezeReasonVacation, FreezeReasonInjury, FreezeReasonOther:
+	default:
+		return errLib.New("reason must be vacation, injury or other", http.StatusBadRequest)
+	}
+
+	if req.StartDate.Before(today) {
+		return errLib.New("start_date cannot be in the past", http.StatusBadRequest)
+	}
+	if !req.EndDate.After(req.StartDate) {
+		return errLib.New("end_date must be after start_date", http.StatusBadRequest)
+	}
+
+	days := freezeDays(req.StartDate, req.EndDate)
+	if days < int(policy.MinDays) || days > int(policy.MaxDays) {
+		return errLib.New(fmt.Sprintf("A freeze of this plan must last between %d and %d days", policy.MinDays, policy.MaxDays), http.StatusBadRequest)
+	}
+
+	if freezesThisYear >= int64(policy.MaxFreezesPerYear) {
+		return errLib.New(fmt.Sprintf("This membership can be frozen at most %d times a year", policy.MaxFreezesPerYear), http.StatusConflict)
+	}
+
+	if req.Reason == FreezeReasonInjury && policy.InjuryRequiresMedicalNote && !req.HasMedicalNote {
+		return errLib.New("A medical note is required for injury freezes", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// prorateFreezeCredits returns the share of a billing period's credit allocation that
+// falls in the frozen days, never more than one allocation
+func prorateFreezeCredits(allocation int32, frozenDays, periodDays int) int32 {
+	if allocation <= 0 || frozenDays <= 0 || periodDays <= 0 {
+		return 0
+	}
+	if frozenDays >= periodDays {
+		return allocation
+	}
+	return int32(int(allocation) * frozenDays / periodDays)
+}
+
+// freezeDays counts the frozen days between two calendar days
+func freezeDays(start, end time.Time) int {
+	return int(end.Sub(start).Hours() / 24)
+}
+
+// facilityToday returns today's date at the facility, as midnight UTC like DATE columns
+func facilityToday() time.Time {
+	now := time.Now().In(timezone.Default())
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	db "api/internal/domains/user/persistence/sqlc/generated"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateFreezeRequest(t *testing.T) {
+	policy := db.MembershipFreezePolicy{
+		MinDays:                   7,
+		MaxDays:                   60,
+		MaxFreezesPerYear:         2,
+		InjuryRequiresMedicalNote: true,
+	}
+	today := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	request := func(reason string, startOffset, days int) freezeRequest {
+		start := today.AddDate(0, 0, startOffset)
+		return freezeRequest{Reason: reason, StartDate: start, EndDate: start.AddDate(0, 0, days)}
+	}
+
+	t.Run("Accepts a freeze within the policy", func(t *testing.T) {
+		assert.Nil(t, validateFreezeRequest(policy, request(FreezeReasonVacation, 0, 7), today, 1))
+		assert.Nil(t, validateFreezeRequest(policy, request(FreezeReasonOther, 5, 60), today, 0))
+	})
+
+	t.Run("Rejects an unknown reason", func(t *testing.T) {
+		err := validateFreezeRequest(policy, request("holiday", 0, 14), today, 0)
+
+		require.NotNil(t, err)
+		assert.Equal(t, http.StatusBadRequest, err.HTTPCode)
+	})
+
+	t.Run("Rejects a start in the past", func(t *testing.T) {
+		err := validateFreezeRequest(policy, request(FreezeReasonVacation, -1, 14), today, 0)
+
+		require.NotNil(t, err)
+		assert.Equal(t, http.StatusBadRequest, err.HTTPCode)
+	})
+
+	t.Run("Rejects lengths outside the policy", func(t *testing.T) {
+		for _, days := range []int{6, 61} {
+			err := validateFreezeRequest(policy, request(FreezeReasonVacation, 0, days), today, 0)
+
+			require.NotNil(t, err, "%d days", days)
+			assert.Equal(t, http.StatusBadRequest, err.HTTPCode)
+		}
+	})
+
+	t.Run("Rejects once the yearly limit is reached", func(t *testing.T) {
+		err := validateFreezeRequest(policy, request(FreezeReasonVacation, 0, 14), today, 2)
+
+		require.NotNil(t, err)
+		assert.Equal(t, http.StatusConflict, err.HTTPCode)
+	})
+
+	t.Run("Injury freezes need a medical note", func(t *testing.T) {
+		req := request(FreezeReasonInjury, 0, 14)
+
+		err := validateFreezeRequest(policy, req, today, 0)
+		require.NotNil(t, err)
+		assert.Equal(t, http.StatusBadRequest, err.HTTPCode)
+
+		req.HasMedicalNote = true
+		assert.Nil(t, validateFreezeRequest(policy, req, today, 0))
+
+		policy.InjuryRequiresMedicalNote = false
+		assert.Nil(t, validateFreezeRequest(policy, request(FreezeReasonInjury, 0, 14), today, 0))
+	})
+}
+
+func TestProrateFreezeCredits(t *testing.T) {
+	assert.Equal(t, int32(5), prorateFreezeCredits(10, 15, 30))
+	assert.Equal(t, int32(3), prorateFreezeCredits(10, 10, 30))
+	assert.Equal(t, int32(10), prorateFreezeCredits(10, 45, 30))
+	assert.Equal(t, int32(0), prorateFreezeCredits(0, 15, 30))
+	assert.Equal(t, int32(0), prorateFreezeCredits(10, 15, 0))
+}
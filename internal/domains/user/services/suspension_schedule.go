@@ -0,0 +1,288 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	db "api/internal/domains/user/persistence/sqlc/generated"
+	errLib "api/internal/libs/errors"
+	"api/utils/email"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ScheduledActionSuspend   = "suspend"
+	ScheduledActionUnsuspend = "unsuspend"
+)
+
+// suspensionBatchSize caps how many scheduled actions one run of the job handles, and
+// how many expired suspensions it loads at a time
+const suspensionBatchSize = 100
+
+type ScheduleSuspensionActionParams struct {
+	UserID             uuid.UUID
+	ScheduledBy        uuid.UUID
+	Action             string // ScheduledActionSuspend or ScheduledActionUnsuspend
+	RunAt              time.Time
+	SuspensionReason   string         // suspend only
+	SuspensionDuration *time.Duration // suspend only, nil = indefinite
+	ExtendMembership   bool           // unsuspend only
+	CollectArrears     bool           // unsuspend only
+}
+
+// ScheduleSuspensionAction schedules a suspension or unsuspension to run at a later time
+func (s *SuspensionService) ScheduleSuspensionAction(ctx context.Context, params ScheduleSuspensionActionParams) (db.UsersScheduledSuspensionAction, *errLib.CommonError) {
+	if params.Action != ScheduledActionSuspend && params.Action != ScheduledActionUnsuspend {
+		return db.UsersScheduledSuspensionAction{}, errLib.New("action must be suspend or unsuspend", http.StatusBadRequest)
+	}
+	if !params.RunAt.After(time.Now()) {
+		return db.UsersScheduledSuspensionAction{}, errLib.New("run_at must be in the future", http.StatusBadRequest)
+	}
+
+	createParams := db.CreateScheduledSuspensionActionParams{
+		UserID:      params.UserID,
+		Action:      params.Action,
+		RunAt:       params.RunAt.UTC(),
+		ScheduledBy: params.ScheduledBy,
+	}
+	if params.Action == ScheduledActionSuspend {
+		if params.SuspensionReason == "" {
+			return db.UsersScheduledSuspensionAction{}, errLib.New("suspension_reason is required to schedule a suspension", http.StatusBadRequest)
+		}
+		createParams.SuspensionReason = sql.NullString{String: params.SuspensionReason, Valid: true}
+		if params.SuspensionDuration != nil {
+			createParams.SuspensionDurationSeconds = sql.NullInt64{Int64: int64(params.SuspensionDuration.Seconds()), Valid: true}
+		}
+	} else {
+		createParams.ExtendMembership = params.ExtendMembership
+		createParams.CollectArrears = params.CollectArrears
+	}
+
+	var action db.UsersScheduledSuspensionAction
+	txErr := s.executeInTx(ctx, func(tx *sql.Tx) *errLib.CommonError {
+		queries := s.customerRepo.WithTx(tx).Queries
+
+		created, err := queries.CreateScheduledSuspensionAction(ctx, createParams)
+		if err != nil {
+			log.Printf("Failed to schedule %s of user %s: %v", params.Action, params.UserID, err)
+			return errLib.New("Failed to schedule "+params.Action, http.StatusInternalServerError)
+		}
+		action = created
+
+		activityDesc := fmt.Sprintf("Scheduled %s of user %s for %s", params.Action, params.UserID, created.RunAt.Format(time.RFC3339))
+		if params.Action == ScheduledActionSuspend {
+			activityDesc += " - Reason: " + params.SuspensionReason
+		}
+		return s.staffActivityLogsService.InsertStaffActivity(ctx, tx, params.ScheduledBy, activityDesc)
+	})
+	if txErr != nil {
+		return db.UsersScheduledSuspensionAction{}, txErr
+	}
+
+	return action, nil
+}
+
+// ListScheduledSuspensionActions lists a user's scheduled suspensions and unsuspensions, latest first
+func (s *SuspensionService) ListScheduledSuspensionActions(ctx context.Context, userID uuid.UUID) ([]db.UsersScheduledSuspensionAction, *errLib.CommonError) {
+	actions, err := s.customerRepo.Queries.ListUserScheduledSuspensionActions(ctx, userID)
+	if err != nil {
+		log.Printf("Failed to list scheduled suspension actions for user %s: %v", userID, err)
+		return nil, errLib.New("Failed to list scheduled suspension actions", http.StatusInternalServerError)
+	}
+	return actions, nil
+}
+
+// CancelScheduledSuspensionAction cancels a scheduled action that has not run yet
+func (s *SuspensionService) CancelScheduledSuspensionAction(ctx context.Context, userID, actionID, cancelledBy uuid.UUID) *errLib.CommonError {
+	return s.executeInTx(ctx, func(tx *sql.Tx) *errLib.CommonError {
+		queries := s.customerRepo.WithTx(tx).Queries
+
+		action, err := queries.CancelScheduledSuspensionAction(ctx, db.CancelScheduledSuspensionActionParams{
+			ID:     actionID,
+			UserID: userID,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return errLib.New("No pending scheduled action found", http.StatusNotFound)
+			}
+			log.Printf("Failed to cancel scheduled suspension action %s: %v", actionID, err)
+			return errLib.New("Failed to cancel scheduled action", http.StatusInternalServerError)
+		}
+
+		activityDesc := fmt.Sprintf("Cancelled scheduled %s of user %s for %s", action.Action, userID, action.RunAt.Format(time.RFC3339))
+		return s.staffActivityLogsService.InsertStaffActivity(ctx, tx, cancelledBy, activityDesc)
+	})
+}
+
+// suspensionJobQueries are the queries the suspension job runs on
+type suspensionJobQueries interface {
+	ListDueScheduledSuspensionActions(ctx context.Context, limit int32) ([]db.UsersScheduledSuspensionAction, error)
+	ClaimScheduledSuspensionAction(ctx context.Context, id uuid.UUID) (int64, error)
+	FailScheduledSuspensionAction(ctx context.Context, arg db.FailScheduledSuspensionActionParams) error
+	ListExpiredSuspensions(ctx context.Context, arg db.ListExpiredSuspensionsParams) ([]db.ListExpiredSuspensionsRow, error)
+}
+
+// suspensionJob is one run of the suspension job. Its steps are fields so tests can
+// run the job without a database.
+type suspensionJob struct {
+	queries      suspensionJobQueries
+	runAction    func(ctx context.Context, action db.UsersScheduledSuspensionAction) *errLib.CommonError
+	unsuspend    func(ctx context.Context, params UnsuspendUserParams) *errLib.CommonError
+	notifyLifted func(to, firstName string)
+}
+
+func (s *SuspensionService) job() suspensionJob {
+	return suspensionJob{
+		queries:      s.customerRepo.Queries,
+		runAction:    s.runScheduledAction,
+		unsuspend:    s.UnsuspendUser,
+		notifyLifted: email.SendSuspensionLiftedEmail,
+	}
+}
+
+// RunScheduledSuspensionActions runs the scheduled suspensions and unsuspensions that are due
+func (s *SuspensionService) RunScheduledSuspensionActions(ctx context.Context) error {
+	return s.job().runScheduledActions(ctx)
+}
+
+func (j suspensionJob) runScheduledActions(ctx context.Context) error {
+	actions, err := j.queries.ListDueScheduledSuspensionActions(ctx, suspensionBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to list due scheduled suspension actions: %w", err)
+	}
+
+	completed, failed := 0, 0
+	for _, action := range actions {
+		claimed, err := j.queries.ClaimScheduledSuspensionAction(ctx, action.ID)
+		if err != nil {
+			log.Printf("[SUSPENSIONS] Failed to claim scheduled action %s: %v", action.ID, err)
+			failed++
+			continue
+		}
+		if claimed == 0 {
+			continue // cancelled or picked up by another run
+		}
+
+		if runErr := j.runAction(ctx, action); runErr != nil {
+			log.Printf("[SUSPENSIONS] Scheduled %s of user %s failed: %s", action.Action, action.UserID, runErr.Message)
+			if err := j.queries.FailScheduledSuspensionAction(ctx, db.FailScheduledSuspensionActionParams{
+				ID:            action.ID,
+				FailureReason: sql.NullString{String: runErr.Message, Valid: true},
+			}); err != nil {
+				log.Printf("[SUSPENSIONS] Failed to record failure of scheduled action %s: %v", action.ID, err)
+			}
+			failed++
+			continue
+		}
+		completed++
+	}
+
+	log.Printf("[SUSPENSIONS] Scheduled actions: due=%d, completed=%d, failed=%d", len(actions), completed, failed)
+	return nil
+}
+
+func (s *SuspensionService) runScheduledAction(ctx context.Context, action db.UsersScheduledSuspensionAction) *errLib.CommonError {
+	if action.Action == ScheduledActionUnsuspend {
+		if err := s.UnsuspendUser(ctx, UnsuspendUserParams{
+			UserID:           action.UserID,
+			UnsuspendedBy:    action.ScheduledBy,
+			ExtendMembership: action.ExtendMembership,
+			CollectArrears:   action.CollectArrears,
+			Reason:           fmt.Sprintf("scheduled on %s", action.CreatedAt.Format("2006-01-02")),
+		}); err != nil {
+			return err
+		}
+		s.notifySuspensionLifted(ctx, action.UserID)
+		return nil
+	}
+
+	info, err := s.customerRepo.Queries.GetSuspensionInfo(ctx, action.UserID)
+	if err != nil {
+		log.Printf("Failed to get suspension info for user %s: %v", action.UserID, err)
+		return errLib.New("Failed to get suspension info", http.StatusInternalServerError)
+	}
+	if info.SuspendedAt.Valid {
+		return errLib.New("User is already suspended", http.StatusConflict)
+	}
+
+	var duration *time.Duration
+	if action.SuspensionDurationSeconds.Valid {
+		d := time.Duration(action.SuspensionDurationSeconds.Int64) * time.Second
+		duration = &d
+	}
+
+	return s.SuspendUser(ctx, SuspendUserParams{
+		UserID:             action.UserID,
+		SuspendedBy:        action.ScheduledBy,
+		SuspensionReason:   action.SuspensionReason.String,
+		SuspensionDuration: duration,
+	})
+}
+
+// LiftExpiredSuspensions unsuspends users whose timed suspension has run out. The JWT
+// middleware already lets them back in; this resumes their memberships and billing too.
+func (s *SuspensionService) LiftExpiredSuspensions(ctx context.Context) error {
+	return s.job().liftExpiredSuspensions(ctx)
+}
+
+// liftExpiredSuspensions goes through every expired suspension a page at a time, so a
+// user whose lift keeps failing is retried each run without holding back the others.
+// Lifting is a system action: it has no actor in the audit log.
+func (j suspensionJob) liftExpiredSuspensions(ctx context.Context) error {
+	found, lifted, failed := 0, 0, 0
+	afterID := uuid.Nil
+	for {
+		expired, err := j.queries.ListExpiredSuspensions(ctx, db.ListExpiredSuspensionsParams{
+			AfterID:   afterID,
+			BatchSize: suspensionBatchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list expired suspensions: %w", err)
+		}
+
+		for _, user := range expired {
+			found++
+			afterID = user.ID
+
+			if err := j.unsuspend(ctx, UnsuspendUserParams{
+				UserID: user.ID,
+				Reason: fmt.Sprintf("suspension expired on %s", user.SuspensionExpiresAt.Time.Format(time.RFC3339)),
+			}); err != nil {
+				log.Printf("[SUSPENSIONS] Failed to lift expired suspension of user %s: %s", user.ID, err.Message)
+				failed++
+				continue
+			}
+
+			if user.Email.Valid && user.Email.String != "" {
+				j.notifyLifted(user.Email.String, user.FirstName)
+			}
+			lifted++
+		}
+
+		if len(expired) < suspensionBatchSize {
+			break
+		}
+	}
+
+	log.Printf("[SUSPENSIONS] Expired suspensions: found=%d, lifted=%d, failed=%d", found, lifted, failed)
+	return nil
+}
+
+func (s *SuspensionService) notifySuspensionLifted(ctx context.Context, userID uuid.UUID) {
+	var firstName string
+	var address sql.NullString
+	query := "SELECT first_name, email FROM users.users WHERE id = $1"
+	if err := s.db.QueryRowContext(ctx, query, userID).Scan(&firstName, &address); err != nil {
+		log.Printf("Failed to look up user %s to notify: %v", userID, err)
+		return
+	}
+	if address.Valid && address.String != "" {
+		email.SendSuspensionLiftedEmail(address.String, firstName)
+	}
+}
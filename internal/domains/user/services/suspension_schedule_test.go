@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"sort"
+	"testing"
+	"time"
+
+	db "api/internal/domains/user/persistence/sqlc/generated"
+	errLib "api/internal/libs/errors"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSuspensionJobQueries struct {
+	due     []db.UsersScheduledSuspensionAction
+	claimed map[uuid.UUID]int64
+	failed  map[uuid.UUID]string
+	expired []db.ListExpiredSuspensionsRow
+	pages   int
+}
+
+func (f *fakeSuspensionJobQueries) ListDueScheduledSuspensionActions(_ context.Context, limit int32) ([]db.UsersScheduledSuspensionAction, error) {
+	if len(f.due) > int(limit) {
+		return f.due[:limit], nil
+	}
+	return f.due, nil
+}
+
+func (f *fakeSuspensionJobQueries) ClaimScheduledSuspensionAction(_ context.Context, id uuid.UUID) (int64, error) {
+	return f.claimed[id], nil
+}
+
+func (f *fakeSuspensionJobQueries) FailScheduledSuspensionAction(_ context.Context, arg db.FailScheduledSuspensionActionParams) error {
+	f.failed[arg.ID] = arg.FailureReason.String
+	return nil
+}
+
+// ListExpiredSuspensions pages through expired in ID order like the query does
+func (f *fakeSuspensionJobQueries) ListExpiredSuspensions(_ context.Context, arg db.ListExpiredSuspensionsParams) ([]db.ListExpiredSuspensionsRow, error) {
+	f.pages++
+	var page []db.ListExpiredSuspensionsRow
+	for _, row := range f.expired {
+		if row.ID.String() > arg.AfterID.String() && len(page) < int(arg.BatchSize) {
+			page = append(page, row)
+		}
+	}
+	return page, nil
+}
+
+func TestLiftExpiredSuspensions(t *testing.T) {
+	expiredAt := sql.NullTime{Time: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+	queries := &fakeSuspensionJobQueries{}
+	for i := 0; i < suspensionBatchSize+20; i++ {
+		queries.expired = append(queries.expired, db.ListExpiredSuspensionsRow{ID: uuid.New(), FirstName: "Member", SuspensionExpiresAt: expiredAt})
+	}
+	sort.Slice(queries.expired, func(i, j int) bool {
+		return queries.expired[i].ID.String() < queries.expired[j].ID.String()
+	})
+	for i := range queries.expired {
+		if i%2 == 0 {
+			queries.expired[i].Email = sql.NullString{String: "member@example.com", Valid: true}
+		}
+	}
+
+	// The first page keeps failing, which used to hold back every user after it
+	failing := map[uuid.UUID]bool{}
+	for _, row := range queries.expired[:suspensionBatchSize] {
+		failing[row.ID] = true
+	}
+
+	var attempted []UnsuspendUserParams
+	notified := 0
+	job := suspensionJob{
+		queries: queries,
+		unsuspend: func(_ context.Context, params UnsuspendUserParams) *errLib.CommonError {
+			attempted = append(attempted, params)
+			if failing[params.UserID] {
+				return errLib.New("stripe unavailable", http.StatusBadGateway)
+			}
+			return nil
+		},
+		notifyLifted: func(to, _ string) {
+			assert.Equal(t, "member@example.com", to)
+			notified++
+		},
+	}
+
+	require.NoError(t, job.liftExpiredSuspensions(context.Background()))
+
+	assert.Equal(t, 2, queries.pages)
+	require.Len(t, attempted, len(queries.expired))
+	for i, params := range attempted {
+		assert.Equal(t, queries.expired[i].ID, params.UserID)
+		assert.Equal(t, uuid.Nil, params.UnsuspendedBy, "lifting an expired suspension is a system action")
+		assert.Contains(t, params.Reason, "suspension expired on 2026-04-01")
+	}
+	assert.Equal(t, 10, notified, "only the lifted users with an email are notified")
+}
+
+func TestRunScheduledSuspensionActions(t *testing.T) {
+	cancelled := db.UsersScheduledSuspensionAction{ID: uuid.New(), Action: ScheduledActionSuspend}
+	failing := db.UsersScheduledSuspensionAction{ID: uuid.New(), Action: ScheduledActionSuspend}
+	succeeding := db.UsersScheduledSuspensionAction{ID: uuid.New(), Action: ScheduledActionUnsuspend}
+
+	queries := &fakeSuspensionJobQueries{
+		due:     []db.UsersScheduledSuspensionAction{cancelled, failing, succeeding},
+		claimed: map[uuid.UUID]int64{failing.ID: 1, succeeding.ID: 1},
+		failed:  map[uuid.UUID]string{},
+	}
+
+	var ran []uuid.UUID
+	job := suspensionJob{
+		queries: queries,
+		runAction: func(_ context.Context, action db.UsersScheduledSuspensionAction) *errLib.CommonError {
+			ran = append(ran, action.ID)
+			if action.ID == failing.ID {
+				return errLib.New("User is already suspended", http.StatusConflict)
+			}
+			return nil
+		},
+	}
+
+	require.NoError(t, job.runScheduledActions(context.Background()))
+
+	assert.Equal(t, []uuid.UUID{failing.ID, succeeding.ID}, ran, "an action that was not claimed is skipped")
+	assert.Equal(t, map[uuid.UUID]string{failing.ID: "User is already suspended"}, queries.failed)
+}
+
+func TestRunScheduledSuspensionActionsListError(t *testing.T) {
+	job := suspensionJob{queries: failingSuspensionJobQueries{&fakeSuspensionJobQueries{}}}
+	assert.Error(t, job.runScheduledActions(context.Background()))
+	assert.Error(t, job.liftExpiredSuspensions(context.Background()))
+}
+
+type failingSuspensionJobQueries struct{ *fakeSuspensionJobQueries }
+
+func (failingSuspensionJobQueries) ListDueScheduledSuspensionActions(context.Context, int32) ([]db.UsersScheduledSuspensionAction, error) {
+	return nil, errors.New("connection refused")
+}
+
+func (failingSuspensionJobQueries) ListExpiredSuspensions(context.Context, db.ListExpiredSuspensionsParams) ([]db.ListExpiredSuspensionsRow, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestScheduleSuspensionActionValidation(t *testing.T) {
+	service := &SuspensionService{}
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name   string
+		params ScheduleSuspensionActionParams
+	}{
+		{"unknown action", ScheduleSuspensionActionParams{Action: "ban", RunAt: future}},
+		{"run_at in the past", ScheduleSuspensionActionParams{Action: ScheduledActionUnsuspend, RunAt: time.Now().Add(-time.Minute)}},
+		{"suspension without a reason", ScheduleSuspensionActionParams{Action: ScheduledActionSuspend, RunAt: future}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.ScheduleSuspensionAction(context.Background(), tt.params)
+			require.NotNil(t, err)
+			assert.Equal(t, http.StatusBadRequest, err.HTTPCode)
+		})
+	}
+}
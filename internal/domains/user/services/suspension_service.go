@@ -10,11 +10,13 @@ import (
 	"time"
 
 	"api/internal/di"
+	auditValues "api/internal/domains/audit/audit_logs/values"
 	staffActivityLogs "api/internal/domains/audit/staff_activity_logs/service"
 	stripeService "api/internal/domains/payment/services/stripe"
 	repo "api/internal/domains/user/persistence/repository"
 	db "api/internal/domains/user/persistence/sqlc/generated"
 	errLib "api/internal/libs/errors"
+	contextUtils "api/utils/context"
 	txUtils "api/utils/db"
 
 	"github.com/google/uuid"
@@ -40,16 +42,36 @@ func NewSuspensionService(container *di.Container) *SuspensionService {
 
 type SuspendUserParams struct {
 	UserID             uuid.UUID
-	SuspendedBy        uuid.UUID
+	SuspendedBy        uuid.UUID // uuid.Nil for automatic suspensions, which are not logged as staff activity
 	SuspensionReason   string
 	SuspensionDuration *time.Duration // nil = indefinite suspension
 }
 
 type UnsuspendUserParams struct {
 	UserID             uuid.UUID
-	UnsuspendedBy      uuid.UUID
+	UnsuspendedBy      uuid.UUID // uuid.Nil for automatic unsuspensions
 	ExtendMembership   bool // whether to extend renewal_date by suspension duration
 	CollectArrears     bool // whether to create invoice items for missed billing periods
+	Reason             string // recorded in the audit entry, e.g. why an automatic unsuspension ran
+}
+
+// suspensionState is the part of a user recorded in the audit log when their suspension changes
+type suspensionState struct {
+	Suspended           bool       `json:"suspended"`
+	SuspensionReason    string     `json:"suspension_reason,omitempty" audit:"personal"`
+	SuspensionExpiresAt *time.Time `json:"suspension_expires_at,omitempty"`
+}
+
+func suspensionStateOf(info db.GetSuspensionInfoRow) suspensionState {
+	state := suspensionState{
+		Suspended:        info.SuspendedAt.Valid,
+		SuspensionReason: info.SuspensionReason.String,
+	}
+	if info.SuspensionExpiresAt.Valid {
+		expiresAt := info.SuspensionExpiresAt.Time
+		state.SuspensionExpiresAt = &expiresAt
+	}
+	return state
 }
 
 func (s *SuspensionService) executeInTx(ctx context.Context, fn func(tx *sql.Tx) *errLib.CommonError) *errLib.CommonError {
@@ -69,11 +91,20 @@ func (s *SuspensionService) SuspendUser(ctx context.Context, params SuspendUserP
 
 		// 1. Suspend the user
 		queries := s.customerRepo.WithTx(tx).Queries
-		_, err := queries.SuspendUser(ctx, db.SuspendUserParams{
+		before, err := queries.GetSuspensionInfo(ctx, params.UserID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return errLib.New("User not found", http.StatusNotFound)
+			}
+			log.Printf("Failed to get suspension info for user %s: %v", params.UserID, err)
+			return errLib.New("Failed to get suspension info", http.StatusInternalServerError)
+		}
+
+		_, err = queries.SuspendUser(ctx, db.SuspendUserParams{
 			UserID:              params.UserID,
 			SuspendedAt:         sql.NullTime{Time: suspendedAt, Valid: true},
 			SuspensionReason:    sql.NullString{String: params.SuspensionReason, Valid: true},
-			SuspendedBy:         uuid.NullUUID{UUID: params.SuspendedBy, Valid: true},
+			SuspendedBy:         uuid.NullUUID{UUID: params.SuspendedBy, Valid: params.SuspendedBy != uuid.Nil},
 			SuspensionExpiresAt: sql.NullTime{Time: func() time.Time { if suspensionExpiresAt != nil { return *suspensionExpiresAt }; return time.Time{} }(), Valid: suspensionExpiresAt != nil},
 		})
 		if err != nil {
@@ -103,14 +134,28 @@ func (s *SuspensionService) SuspendUser(ctx context.Context, params SuspendUserP
 			durationStr = fmt.Sprintf("until %s", suspensionExpiresAt.Format(time.RFC3339))
 		}
 
-		activityDesc := fmt.Sprintf("Suspended user %s (%s) - Reason: %s",
-			params.UserID, durationStr, params.SuspensionReason)
+		activityDesc := fmt.Sprintf("Suspended user %s (%s)", params.UserID, durationStr)
 
-		if logErr := s.staffActivityLogsService.InsertStaffActivity(ctx, tx, params.SuspendedBy, activityDesc); logErr != nil {
-			log.Printf("Warning: Failed to log suspension activity: %v", logErr)
+		// Without a staff member the description goes into the audit log itself, which
+		// cannot be erased, so the free-text reason is only kept with the staff activity
+		if params.SuspendedBy == uuid.Nil {
+			log.Printf("Automatically %s - Reason: %s", strings.ToLower(activityDesc[:1])+activityDesc[1:], params.SuspensionReason)
+		} else {
+			activityDesc += " - Reason: " + params.SuspensionReason
 		}
 
-		return nil
+		return s.staffActivityLogsService.RecordChange(ctx, tx, params.SuspendedBy, auditValues.Entry{
+			EntityType: "user",
+			EntityID:   params.UserID.String(),
+			Action:     "suspend",
+			Before:     suspensionStateOf(before),
+			After: suspensionState{
+				Suspended:           true,
+				SuspensionReason:    params.SuspensionReason,
+				SuspensionExpiresAt: suspensionExpiresAt,
+			},
+			Description: activityDesc,
+		})
 	})
 }
 
@@ -203,16 +248,29 @@ func (s *SuspensionService) UnsuspendUser(ctx context.Context, params UnsuspendU
 
 		activityDesc := fmt.Sprintf("Unsuspended user %s%s%s", params.UserID, extensionNote, arrearsNote)
 
-		if logErr := s.staffActivityLogsService.InsertStaffActivity(ctx, tx, params.UnsuspendedBy, activityDesc); logErr != nil {
-			log.Printf("Warning: Failed to log unsuspension activity: %v", logErr)
+		if params.UnsuspendedBy == uuid.Nil {
+			log.Printf("Automatically %s - Reason: %s", strings.ToLower(activityDesc[:1])+activityDesc[1:], params.Reason)
+		} else if params.Reason != "" {
+			activityDesc += " - Reason: " + params.Reason
 		}
 
-		return nil
+		return s.staffActivityLogsService.RecordChange(ctx, tx, params.UnsuspendedBy, auditValues.Entry{
+			EntityType:  "user",
+			EntityID:    params.UserID.String(),
+			Action:      "unsuspend",
+			Before:      suspensionStateOf(suspensionInfo),
+			After:       suspensionState{Suspended: false},
+			Description: activityDesc,
+		})
 	})
 }
 
 // pauseStripeSubscriptions pauses all active Stripe subscriptions for a user
 func (s *SuspensionService) pauseStripeSubscriptions(ctx context.Context, userID uuid.UUID) error {
+	// The subscription service acts for the user in the context, which is the staff member
+	// (or nobody, for scheduled runs) rather than the suspended customer
+	ctx = context.WithValue(ctx, contextUtils.UserIDKey, userID)
+
 	subscriptions, err := s.stripeService.GetCustomerSubscriptions(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get customer subscriptions: %w", err)
@@ -232,13 +290,16 @@ func (s *SuspensionService) pauseStripeSubscriptions(ctx context.Context, userID
 
 // resumeStripeSubscriptions resumes all paused Stripe subscriptions for a user
 func (s *SuspensionService) resumeStripeSubscriptions(ctx context.Context, userID uuid.UUID) error {
+	ctx = context.WithValue(ctx, contextUtils.UserIDKey, userID)
+
 	subscriptions, err := s.stripeService.GetCustomerSubscriptions(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get customer subscriptions: %w", err)
 	}
 
 	for _, subscription := range subscriptions {
-		if subscription.Status == "paused" {
+		// Suspension pauses collection, which leaves the subscription status active
+		if subscription.PauseCollection != nil {
 			if _, err := s.stripeService.ResumeSubscription(ctx, subscription.ID); err != nil {
 				return fmt.Errorf("failed to resume subscription %s: %w", subscription.ID, err)
 			}
@@ -430,9 +491,16 @@ func (s *SuspensionService) CollectArrearsManually(ctx context.Context, userID u
 		return 0, errLib.New("Failed to collect arrears", http.StatusInternalServerError)
 	}
 
-	// Log staff activity
+	// Log staff activity. The invoice items already exist in Stripe, so a failure here
+	// must not turn into an error the caller would retry.
 	activityDesc := fmt.Sprintf("Manually collected arrears for suspended user %s: $%.2f", userID, float64(arrears)/100)
-	if logErr := s.staffActivityLogsService.InsertStaffActivity(ctx, nil, collectedBy, activityDesc); logErr != nil {
+	if logErr := s.staffActivityLogsService.RecordChange(ctx, nil, collectedBy, auditValues.Entry{
+		EntityType:  "user",
+		EntityID:    userID.String(),
+		Action:      "collect_arrears",
+		After:       map[string]int64{"arrears_collected_cents": arrears},
+		Description: activityDesc,
+	}); logErr != nil {
 		log.Printf("Warning: Failed to log arrears collection activity: %v", logErr)
 	}
 
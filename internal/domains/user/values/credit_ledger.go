@@ -0,0 +1,159 @@
+package user
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Where a credit grant came from
+const (
+	CreditSourcePurchase   = "purchase"
+	CreditSourceMembership = "membership"
+	CreditSourceRefund     = "refund"
+	CreditSourceAdjustment = "adjustment"
+	CreditSourceReward     = "reward"
+)
+
+// Ledger accounts a customer's credits move between
+const (
+	CreditAccountIssued    = "issued"
+	CreditAccountAvailable = "available"
+	CreditAccountHeld      = "held"
+	CreditAccountSpent     = "spent"
+	CreditAccountExpired   = "expired"
+)
+
+// Why credits moved
+const (
+	CreditEntryGrant   = "grant"
+	CreditEntryConsume = "consume"
+	CreditEntryHold    = "hold"
+	CreditEntryCapture = "capture"
+	CreditEntryRelease = "release"
+	CreditEntryRefund  = "refund"
+	CreditEntryExpire  = "expire"
+)
+
+// Credit hold statuses
+const (
+	CreditHoldHeld     = "held"
+	CreditHoldCaptured = "captured"
+	CreditHoldReleased = "released"
+)
+
+// What a credit reconciliation issue disagreed on
+const (
+	CreditIssueBalance     = "balance"
+	CreditIssueGrants      = "grants"
+	CreditIssueHolds       = "holds"
+	CreditIssueTransaction = "transaction"
+)
+
+// CreditSlice is part of a movement taken from or returned to one grant
+type CreditSlice struct {
+	GrantID uuid.UUID
+	Amount  int32
+}
+
+// CreditGrant is a block of credits given to a customer at once
+type CreditGrant struct {
+	ID          uuid.UUID
+	Source      string
+	Amount      int32
+	Remaining   int32
+	Description string
+	ExpiresAt   *time.Time
+	CreatedAt   time.Time
+}
+
+// CreditLedgerEntry is one side of a credit movement
+type CreditLedgerEntry struct {
+	ID            uuid.UUID
+	TransactionID uuid.UUID
+	Account       string
+	EntryType     string
+	Amount        int32
+	GrantID       *uuid.UUID
+	HoldID        *uuid.UUID
+	Reference     *string
+	Description   string
+	CreatedAt     time.Time
+}
+
+// CreditReconciliationIssue is a difference found by the credit reconciliation
+type CreditReconciliationIssue struct {
+	ID            uuid.UUID
+	CustomerID    uuid.UUID
+	FirstName     string
+	LastName      string
+	Kind          string
+	TransactionID *uuid.UUID
+	Expected      int32
+	Actual        int32
+	DetectedAt    time.Time
+	ResolvedBy    *uuid.UUID
+	ResolvedAt    *time.Time
+	Resolution    *string
+}
+
+// CreditReconciliationResult counts what one reconciliation run did
+type CreditReconciliationResult struct {
+	GrantsExpired   int
+	HoldsCaptured   int
+	HoldsReleased   int
+	RefundsRetried  int
+	RefundsResolved int
+	IssuesFound     int
+}
+
+// EventCreditReference identifies credits paid for an event enrollment
+func EventCreditReference(eventID uuid.UUID) string {
+	return "event:" + eventID.String()
+}
+
+// CourtRentalCreditReference identifies credits paid for a court rental
+func CourtRentalCreditReference(rentalID uuid.UUID) string {
+	return "court_rental:" + rentalID.String()
+}
+
+// PlaygroundSessionCreditReference identifies credits paid for a playground session
+func PlaygroundSessionCreditReference(sessionID uuid.UUID) string {
+	return "playground_session:" + sessionID.String()
+}
+
+// MembershipFreezeCreditReference identifies credits forfeited by a membership freeze
+func MembershipFreezeCreditReference(freezeID uuid.UUID) string {
+	return "membership_freeze:" + freezeID.String()
+}
+
+// CreditExpiry returns when credits granted at from with the given validity expire,
+// or nil when they never do.
+func CreditExpiry(validityDays *int32, from time.Time) *time.Time {
+	if validityDays == nil || *validityDays <= 0 {
+		return nil
+	}
+	expiresAt := from.AddDate(0, 0, int(*validityDays))
+	return &expiresAt
+}
+
+// TakeFIFO splits amount across grants in the order given, which must be the order
+// credits are used in. It returns nil when the grants do not hold enough.
+func TakeFIFO(grants []CreditSlice, amount int32) []CreditSlice {
+	var slices []CreditSlice
+	for _, g := range grants {
+		if amount == 0 {
+			break
+		}
+		take := min(g.Amount, amount)
+		if take <= 0 {
+			continue
+		}
+		slices = append(slices, CreditSlice{GrantID: g.GrantID, Amount: take})
+		amount -= take
+	}
+	if amount > 0 {
+		return nil
+	}
+	return slices
+}
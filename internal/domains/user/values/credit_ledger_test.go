@@ -0,0 +1,41 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTakeFIFO(t *testing.T) {
+	first, second, third := uuid.New(), uuid.New(), uuid.New()
+	grants := []CreditSlice{{GrantID: first, Amount: 3}, {GrantID: second, Amount: 0}, {GrantID: third, Amount: 5}}
+
+	t.Run("Takes from the first grant while it lasts", func(t *testing.T) {
+		assert.Equal(t, []CreditSlice{{GrantID: first, Amount: 2}}, TakeFIFO(grants, 2))
+	})
+
+	t.Run("Moves on to later grants, skipping empty ones", func(t *testing.T) {
+		assert.Equal(t, []CreditSlice{{GrantID: first, Amount: 3}, {GrantID: third, Amount: 4}}, TakeFIFO(grants, 7))
+	})
+
+	t.Run("Takes everything when the amount matches the total", func(t *testing.T) {
+		assert.Equal(t, []CreditSlice{{GrantID: first, Amount: 3}, {GrantID: third, Amount: 5}}, TakeFIFO(grants, 8))
+	})
+
+	t.Run("Returns nil when the grants are short", func(t *testing.T) {
+		assert.Nil(t, TakeFIFO(grants, 9))
+		assert.Nil(t, TakeFIFO(nil, 1))
+	})
+}
+
+func TestCreditExpiry(t *testing.T) {
+	from := time.Date(2026, 1, 31, 12, 0, 0, 0, time.UTC)
+	days := int32(30)
+	zero := int32(0)
+
+	assert.Equal(t, time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC), *CreditExpiry(&days, from))
+	assert.Nil(t, CreditExpiry(nil, from))
+	assert.Nil(t, CreditExpiry(&zero, from))
+}
@@ -85,6 +85,18 @@ type MembershipHistoryValue struct {
 	Interval              string
 	StripePriceID         string
 	StripeSubscriptionID  *string
+	Freezes               []MembershipFreezeValue
+}
+
+type MembershipFreezeValue struct {
+	ID               uuid.UUID
+	Reason           string
+	StartDate        time.Time
+	EndDate          time.Time // the day the membership resumes
+	Status           string
+	FeeAmount        int // in cents
+	CreditsForfeited int32
+	HasMedicalNote   bool
 }
 
 // CustomerFilterParams holds filtering options for customer queries
@@ -48,9 +48,9 @@ func (j *CheckoutReconciliationJob) Name() string {
 	return "CheckoutReconciliation"
 }
 
-// Interval returns how often this job runs (every 30 minutes)
-func (j *CheckoutReconciliationJob) Interval() time.Duration {
-	return 30 * time.Minute
+// Schedule returns when this job runs (every 30 minutes)
+func (j *CheckoutReconciliationJob) Schedule() string {
+	return "*/30 * * * *"
 }
 
 // Run executes the reconciliation logic
@@ -153,6 +153,10 @@ func (j *CheckoutReconciliationJob) Run(ctx context.Context) error {
 		"skipped":    skipped,
 		"errors":     errorCount,
 	}).Info("Checkout reconciliation job completed")
+	RecordCount(ctx, "checked", checked)
+	RecordCount(ctx, "reconciled", reconciled)
+	RecordCount(ctx, "skipped", skipped)
+	RecordCount(ctx, "errors", errorCount)
 
 	// Send ONE summary Slack alert only if there were reconciled checkouts or persistent failures
 	if reconciled > 0 || errorCount > 0 {
@@ -432,7 +436,7 @@ func (j *CheckoutReconciliationJob) reconcileOneTimeCheckout(ctx context.Context
 		if pkg != nil {
 			log.Printf("[CHECKOUT_RECONCILE] Adding %d credits to customer %s from package %s", pkg.CreditAllocation, userID, pkg.ID)
 
-			if err := j.customerCreditService.AddCredits(ctx, userID, pkg.CreditAllocation, "Credit package purchase (reconciled)"); err != nil {
+			if err := j.customerCreditService.AddPurchasedCredits(ctx, userID, pkg.CreditAllocation, pkg.CreditValidityDays, "Credit package purchase (reconciled)"); err != nil {
 				return err
 			}
 
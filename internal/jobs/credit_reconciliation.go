@@ -0,0 +1,53 @@
+package jobs
+
+import (
+	"context"
+	"log"
+
+	"api/internal/di"
+	userServices "api/internal/domains/user/services"
+)
+
+// CreditReconciliationJob expires lapsed credits, settles credit holds nobody captured or
+// released, retries queued credit refunds and flags balances that disagree with the
+// credit ledger
+type CreditReconciliationJob struct {
+	credits *userServices.CustomerCreditService
+}
+
+// NewCreditReconciliationJob creates a new credit reconciliation job
+func NewCreditReconciliationJob(container *di.Container) *CreditReconciliationJob {
+	return &CreditReconciliationJob{
+		credits: userServices.NewCustomerCreditService(container),
+	}
+}
+
+// Name returns the job name
+func (j *CreditReconciliationJob) Name() string {
+	return "CreditReconciliation"
+}
+
+// Schedule returns when this job runs (nightly at 3:00)
+func (j *CreditReconciliationJob) Schedule() string {
+	return "0 3 * * *"
+}
+
+// Run reconciles every customer's credits with the ledger
+func (j *CreditReconciliationJob) Run(ctx context.Context) error {
+	log.Printf("[CREDIT-RECONCILE] Starting credit reconciliation")
+
+	result, err := j.credits.Reconcile(ctx)
+	if err != nil {
+		log.Printf("[CREDIT-RECONCILE] Failed to reconcile credits: %v", err)
+		return err
+	}
+
+	log.Printf("[CREDIT-RECONCILE] Expired %d grants, captured %d and released %d holds, resolved %d of %d queued refunds, found %d new issues",
+		result.GrantsExpired, result.HoldsCaptured, result.HoldsReleased, result.RefundsResolved, result.RefundsRetried, result.IssuesFound)
+	RecordCount(ctx, "grants_expired", result.GrantsExpired)
+	RecordCount(ctx, "holds_captured", result.HoldsCaptured)
+	RecordCount(ctx, "holds_released", result.HoldsReleased)
+	RecordCount(ctx, "refunds_resolved", result.RefundsResolved)
+	RecordCount(ctx, "issues_found", result.IssuesFound)
+	return nil
+}
@@ -0,0 +1,68 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCronNext(t *testing.T) {
+	from := time.Date(2026, 3, 14, 10, 7, 30, 0, time.UTC) // a Saturday
+
+	cases := []struct {
+		expr string
+		want time.Time
+	}{
+		{"*/15 * * * *", time.Date(2026, 3, 14, 10, 15, 0, 0, time.UTC)},
+		{"0 * * * *", time.Date(2026, 3, 14, 11, 0, 0, 0, time.UTC)},
+		{"10 * * * *", time.Date(2026, 3, 14, 10, 10, 0, 0, time.UTC)},
+		{"5 * * * *", time.Date(2026, 3, 14, 11, 5, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2026, 3, 15, 3, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"30 9 * * MON-FRI", time.Date(2026, 3, 16, 9, 30, 0, 0, time.UTC)},
+		{"0 0 1 */3 *", time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * * 7", time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)},
+		{"5,35 10 * * *", time.Date(2026, 3, 14, 10, 35, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range cases {
+		schedule, err := ParseCron(tc.expr)
+		require.NoError(t, err, tc.expr)
+		require.Equal(t, tc.want, schedule.Next(from), tc.expr)
+	}
+}
+
+func TestCronNextIsStrictlyAfter(t *testing.T) {
+	schedule := MustParseCron("*/5 * * * *")
+	at := time.Date(2026, 3, 14, 10, 5, 0, 0, time.UTC)
+	require.Equal(t, at.Add(5*time.Minute), schedule.Next(at))
+}
+
+func TestCronDayOfMonthOrDayOfWeek(t *testing.T) {
+	// Both day fields restricted: the 13th or any Friday
+	schedule := MustParseCron("0 0 13 * FRI")
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC) // a Sunday
+	require.Equal(t, time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC), schedule.Next(from))
+	require.Equal(t, time.Date(2026, 3, 13, 0, 0, 0, 0, time.UTC), schedule.Next(time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestCronNeverMatches(t *testing.T) {
+	require.True(t, MustParseCron("0 0 30 2 *").Next(time.Now()).IsZero())
+}
+
+func TestParseCronRejectsInvalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "10-5 * * * *", "* * * FOO *"} {
+		_, err := ParseCron(expr)
+		require.Error(t, err, expr)
+	}
+}
+
+func TestBackoffDelay(t *testing.T) {
+	require.Equal(t, 1*time.Minute, backoffDelay(1, 0.5))
+	require.Equal(t, 2*time.Minute, backoffDelay(2, 0.5))
+	require.Equal(t, 4*time.Minute, backoffDelay(3, 0.5))
+	require.Equal(t, 30*time.Minute, backoffDelay(10, 0.5))
+	require.Equal(t, 48*time.Second, backoffDelay(1, 0))
+	require.Less(t, backoffDelay(1, 0.999), 72*time.Second)
+}
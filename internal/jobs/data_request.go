@@ -0,0 +1,57 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"api/internal/di"
+	privacyService "api/internal/domains/privacy/service"
+)
+
+// DataRequestJob builds personal data exports, runs approved erasures and deletes
+// export archives once their download window has passed
+type DataRequestJob struct {
+	privacy *privacyService.Service
+}
+
+// NewDataRequestJob creates a new data request job
+func NewDataRequestJob(container *di.Container) *DataRequestJob {
+	return &DataRequestJob{
+		privacy: privacyService.NewService(container),
+	}
+}
+
+// Name returns the job name
+func (j *DataRequestJob) Name() string {
+	return "DataRequest"
+}
+
+// Schedule returns when this job runs (every 5 minutes)
+func (j *DataRequestJob) Schedule() string {
+	return "*/5 * * * *"
+}
+
+// Run processes queued export and erasure requests and expires old exports
+func (j *DataRequestJob) Run(ctx context.Context) error {
+	log.Printf("[DATA-REQUESTS] Starting data request run")
+	now := time.Now()
+
+	completed, failed, err := j.privacy.ProcessPending(ctx, now)
+	if err != nil {
+		log.Printf("[DATA-REQUESTS] Failed to process pending requests: %v", err)
+		return err
+	}
+
+	expired, err := j.privacy.ExpireExports(ctx, now)
+	if err != nil {
+		log.Printf("[DATA-REQUESTS] Failed to expire exports: %v", err)
+		return err
+	}
+
+	log.Printf("[DATA-REQUESTS] Completed %d requests, %d failed, expired %d exports", completed, failed, expired)
+	RecordCount(ctx, "completed", completed)
+	RecordCount(ctx, "failed", failed)
+	RecordCount(ctx, "exports_expired", expired)
+	return nil
+}
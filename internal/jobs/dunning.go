@@ -0,0 +1,43 @@
+package jobs
+
+import (
+	"context"
+	"log"
+
+	"api/internal/di"
+	payment "api/internal/domains/payment/services"
+)
+
+// DunningJob moves failed membership payments through the dunning schedule
+type DunningJob struct {
+	dunning *payment.DunningService
+}
+
+// NewDunningJob creates a new dunning job
+func NewDunningJob(container *di.Container) *DunningJob {
+	return &DunningJob{
+		dunning: payment.NewDunningService(container),
+	}
+}
+
+// Name returns the job name
+func (j *DunningJob) Name() string {
+	return "Dunning"
+}
+
+// Schedule returns when this job runs (every hour at :10, so steps land within an hour of being due)
+func (j *DunningJob) Schedule() string {
+	return "10 * * * *"
+}
+
+// Run applies the dunning steps that have come due
+func (j *DunningJob) Run(ctx context.Context) error {
+	log.Printf("[DUNNING] Starting dunning run")
+
+	if err := j.dunning.RunDueSteps(ctx); err != nil {
+		log.Printf("[DUNNING] Run failed: %v", err)
+		return err
+	}
+
+	return nil
+}
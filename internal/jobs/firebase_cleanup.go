@@ -4,7 +4,6 @@ import (
 	"context"
 	"database/sql"
 	"log"
-	"time"
 
 	"api/internal/di"
 
@@ -51,9 +50,9 @@ func (j *FirebaseCleanupJob) Name() string {
 	return "FirebaseCleanup"
 }
 
-// Interval returns how often this job runs (every hour as a safety net)
-func (j *FirebaseCleanupJob) Interval() time.Duration {
-	return 1 * time.Hour
+// Schedule returns when this job runs (only when started from the admin jobs API, since deleting Firebase users is sensitive)
+func (j *FirebaseCleanupJob) Schedule() string {
+	return ""
 }
 
 // SetDryRun allows toggling dry run mode
@@ -70,10 +69,21 @@ func (j *FirebaseCleanupJob) Run(ctx context.Context) error {
 
 	log.Printf("[FIREBASE-CLEANUP] Summary: firebase_users=%d, db_users=%d, orphaned=%d, deleted=%d, failed=%d, dry_run=%v",
 		result.TotalFirebaseUsers, result.TotalDBUsers, result.OrphanedCount, result.DeletedCount, result.FailedCount, result.DryRun)
+	RecordCount(ctx, "firebase_users", result.TotalFirebaseUsers)
+	RecordCount(ctx, "orphaned", result.OrphanedCount)
+	RecordCount(ctx, "deleted", result.DeletedCount)
+	RecordCount(ctx, "failed", result.FailedCount)
 
 	return nil
 }
 
+// RunDry reports the orphaned Firebase users without deleting them
+func (j *FirebaseCleanupJob) RunDry(ctx context.Context) error {
+	dry := *j
+	dry.dryRun = true
+	return dry.Run(ctx)
+}
+
 // RunWithResult executes the cleanup and returns detailed results
 func (j *FirebaseCleanupJob) RunWithResult(ctx context.Context) (*FirebaseCleanupResult, error) {
 	result := &FirebaseCleanupResult{
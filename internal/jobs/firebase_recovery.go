@@ -44,6 +44,16 @@ func NewFirebaseRecoveryJob(container *di.Container) *FirebaseRecoveryJob {
 	}
 }
 
+// Name returns the job name
+func (j *FirebaseRecoveryJob) Name() string {
+	return "FirebaseRecovery"
+}
+
+// Schedule returns when this job runs (only when started from the admin jobs API)
+func (j *FirebaseRecoveryJob) Schedule() string {
+	return ""
+}
+
 // SetDryRun allows toggling dry run mode
 func (j *FirebaseRecoveryJob) SetDryRun(dryRun bool) {
 	j.dryRun = dryRun
@@ -60,7 +70,27 @@ func (j *FirebaseRecoveryJob) SetTargetEmail(email string) {
 }
 
 // Run executes the Firebase recovery logic
-func (j *FirebaseRecoveryJob) Run(ctx context.Context) (*FirebaseRecoveryResult, error) {
+func (j *FirebaseRecoveryJob) Run(ctx context.Context) error {
+	result, err := j.RunWithResult(ctx)
+	if err != nil {
+		return err
+	}
+
+	RecordCount(ctx, "missing", result.MissingInFirebase)
+	RecordCount(ctx, "recovered", result.RecoveredCount)
+	RecordCount(ctx, "failed", result.FailedCount)
+	return nil
+}
+
+// RunDry reports the users missing from Firebase without recreating them
+func (j *FirebaseRecoveryJob) RunDry(ctx context.Context) error {
+	dry := *j
+	dry.dryRun = true
+	return dry.Run(ctx)
+}
+
+// RunWithResult executes the recovery and returns detailed results
+func (j *FirebaseRecoveryJob) RunWithResult(ctx context.Context) (*FirebaseRecoveryResult, error) {
 	result := &FirebaseRecoveryResult{
 		DryRun:          j.dryRun,
 		MissingEmails:   []string{},
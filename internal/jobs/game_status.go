@@ -0,0 +1,53 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"api/internal/di"
+	gameService "api/internal/domains/game/services"
+)
+
+// GameStatusJob moves games through scheduled, in progress and completed as their
+// times pass, and commits the score of live-scored games nobody finalized
+type GameStatusJob struct {
+	games *gameService.Service
+}
+
+// NewGameStatusJob creates a new game status job
+func NewGameStatusJob(container *di.Container) *GameStatusJob {
+	return &GameStatusJob{
+		games: gameService.NewService(container),
+	}
+}
+
+// Name returns the job name
+func (j *GameStatusJob) Name() string {
+	return "GameStatus"
+}
+
+// Schedule returns when this job runs (every 5 minutes)
+func (j *GameStatusJob) Schedule() string {
+	return "*/5 * * * *"
+}
+
+// Run updates game statuses and finalizes abandoned live games
+func (j *GameStatusJob) Run(ctx context.Context) error {
+	log.Printf("[GAME-STATUS] Starting game status run")
+
+	if err := j.games.UpdateGameStatuses(ctx); err != nil {
+		log.Printf("[GAME-STATUS] Failed to update game statuses: %v", err)
+		return err
+	}
+
+	finalized, err := j.games.FinalizeAbandonedLiveGames(ctx, time.Now())
+	if err != nil {
+		log.Printf("[GAME-STATUS] Failed to finalize abandoned live games: %v", err)
+		return err
+	}
+
+	log.Printf("[GAME-STATUS] Finalized %d abandoned live games", finalized)
+	RecordCount(ctx, "live_games_finalized", finalized)
+	return nil
+}
@@ -0,0 +1,63 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"api/internal/di"
+	giftCardService "api/internal/domains/gift_card/service"
+)
+
+// GiftCardJob expires gift cards past their expiry date, returns balance held for
+// checkouts that were never paid, and deletes online purchases that were never paid for
+type GiftCardJob struct {
+	giftCards *giftCardService.Service
+}
+
+// NewGiftCardJob creates a new gift card job
+func NewGiftCardJob(container *di.Container) *GiftCardJob {
+	return &GiftCardJob{
+		giftCards: giftCardService.NewService(container),
+	}
+}
+
+// Name returns the job name
+func (j *GiftCardJob) Name() string {
+	return "GiftCard"
+}
+
+// Schedule returns when this job runs (every 15 minutes)
+func (j *GiftCardJob) Schedule() string {
+	return "*/15 * * * *"
+}
+
+// Run expires cards, releases lapsed balance holds and deletes abandoned purchases
+func (j *GiftCardJob) Run(ctx context.Context) error {
+	log.Printf("[GIFT-CARDS] Starting gift card run")
+	now := time.Now()
+
+	expired, err := j.giftCards.ExpireCards(ctx, now)
+	if err != nil {
+		log.Printf("[GIFT-CARDS] Failed to expire cards: %v", err)
+		return err
+	}
+
+	released, err := j.giftCards.ReleaseExpiredHolds(ctx, now)
+	if err != nil {
+		log.Printf("[GIFT-CARDS] Failed to release expired holds: %v", err)
+		return err
+	}
+
+	deleted, err := j.giftCards.DeleteAbandonedPurchases(ctx, now)
+	if err != nil {
+		log.Printf("[GIFT-CARDS] Failed to delete abandoned purchases: %v", err)
+		return err
+	}
+
+	log.Printf("[GIFT-CARDS] Expired %d cards, released %d holds, deleted %d abandoned purchases", expired, released, deleted)
+	RecordCount(ctx, "cards_expired", expired)
+	RecordCount(ctx, "holds_released", released)
+	RecordCount(ctx, "purchases_deleted", int(deleted))
+	return nil
+}
@@ -0,0 +1,48 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"api/internal/di"
+)
+
+// IdempotencyKeyCleanupJob deletes stored Idempotency-Key responses past their expiry
+type IdempotencyKeyCleanupJob struct {
+	db *sql.DB
+}
+
+// NewIdempotencyKeyCleanupJob creates a new idempotency key cleanup job
+func NewIdempotencyKeyCleanupJob(container *di.Container) *IdempotencyKeyCleanupJob {
+	return &IdempotencyKeyCleanupJob{
+		db: container.DB,
+	}
+}
+
+// Name returns the job name
+func (j *IdempotencyKeyCleanupJob) Name() string {
+	return "IdempotencyKeyCleanup"
+}
+
+// Schedule returns when this job runs (every hour at :40)
+func (j *IdempotencyKeyCleanupJob) Schedule() string {
+	return "40 * * * *"
+}
+
+// Run deletes expired keys
+func (j *IdempotencyKeyCleanupJob) Run(ctx context.Context) error {
+	result, err := j.db.ExecContext(ctx, `
+		DELETE FROM public.idempotency_keys
+		WHERE expires_at < CURRENT_TIMESTAMP
+	`)
+	if err != nil {
+		log.Printf("[IDEMPOTENCY-CLEANUP] Failed to delete expired idempotency keys: %v", err)
+		return err
+	}
+
+	deleted, _ := result.RowsAffected()
+	log.Printf("[IDEMPOTENCY-CLEANUP] Deleted %d expired idempotency keys", deleted)
+	RecordCount(ctx, "deleted", int(deleted))
+	return nil
+}
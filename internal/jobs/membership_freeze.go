@@ -0,0 +1,43 @@
+package jobs
+
+import (
+	"context"
+	"log"
+
+	"api/internal/di"
+	userServices "api/internal/domains/user/services"
+)
+
+// MembershipFreezeJob starts and ends membership freezes on their scheduled days
+type MembershipFreezeJob struct {
+	freezes *userServices.MembershipFreezeService
+}
+
+// NewMembershipFreezeJob creates a new membership freeze job
+func NewMembershipFreezeJob(container *di.Container) *MembershipFreezeJob {
+	return &MembershipFreezeJob{
+		freezes: userServices.NewMembershipFreezeService(container),
+	}
+}
+
+// Name returns the job name
+func (j *MembershipFreezeJob) Name() string {
+	return "MembershipFreeze"
+}
+
+// Schedule returns when this job runs (every 15 minutes)
+func (j *MembershipFreezeJob) Schedule() string {
+	return "*/15 * * * *"
+}
+
+// Run starts the freezes due today and resumes the memberships whose freeze is over
+func (j *MembershipFreezeJob) Run(ctx context.Context) error {
+	log.Printf("[FREEZES] Starting membership freeze run")
+
+	if err := j.freezes.RunDueFreezes(ctx); err != nil {
+		log.Printf("[FREEZES] Failed to run due freezes: %v", err)
+		return err
+	}
+
+	return nil
+}
@@ -31,9 +31,9 @@ func (j *MembershipReconciliationJob) Name() string {
 	return "MembershipReconciliation"
 }
 
-// Interval returns how often this job runs (every 15 minutes)
-func (j *MembershipReconciliationJob) Interval() time.Duration {
-	return 15 * time.Minute
+// Schedule returns when this job runs (every 15 minutes)
+func (j *MembershipReconciliationJob) Schedule() string {
+	return "*/15 * * * *"
 }
 
 // Run executes the reconciliation logic
@@ -125,6 +125,9 @@ func (j *MembershipReconciliationJob) Run(ctx context.Context) error {
 	}
 
 	log.Printf("[RECONCILIATION] Summary: checked=%d, fixed=%d, errors=%d", checked, fixed, errors)
+	RecordCount(ctx, "checked", checked)
+	RecordCount(ctx, "fixed", fixed)
+	RecordCount(ctx, "errors", errors)
 
 	if len(drifts) > 0 {
 		log.Printf("[RECONCILIATION] ⚠️  Drifts detected for customers: %v", drifts)
@@ -0,0 +1,55 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"api/internal/di"
+	payrollService "api/internal/domains/payroll/service"
+)
+
+// PayrollTimesheetJob creates pay periods as time passes and regenerates the open
+// periods' timesheets from staff assignments and check-ins
+type PayrollTimesheetJob struct {
+	payroll *payrollService.Service
+}
+
+// NewPayrollTimesheetJob creates a new payroll timesheet job
+func NewPayrollTimesheetJob(container *di.Container) *PayrollTimesheetJob {
+	return &PayrollTimesheetJob{
+		payroll: payrollService.NewService(container),
+	}
+}
+
+// Name returns the job name
+func (j *PayrollTimesheetJob) Name() string {
+	return "PayrollTimesheets"
+}
+
+// Schedule returns when this job runs (daily at 2 AM)
+func (j *PayrollTimesheetJob) Schedule() string {
+	return "0 2 * * *"
+}
+
+// Run extends the pay period calendar and regenerates open timesheets
+func (j *PayrollTimesheetJob) Run(ctx context.Context) error {
+	log.Printf("[PAYROLL] Starting payroll timesheet run")
+
+	created, err := j.payroll.ExtendPeriods(ctx, time.Now())
+	if err != nil {
+		log.Printf("[PAYROLL] Failed to create pay periods: %v", err)
+		return err
+	}
+
+	generated, err := j.payroll.GenerateOpenPeriods(ctx)
+	if err != nil {
+		log.Printf("[PAYROLL] Failed to generate timesheets: %v", err)
+		return err
+	}
+
+	log.Printf("[PAYROLL] Created %d pay periods, generated %d timesheets", created, generated)
+	RecordCount(ctx, "pay_periods_created", created)
+	RecordCount(ctx, "timesheets_generated", generated)
+	return nil
+}
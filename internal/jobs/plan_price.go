@@ -0,0 +1,44 @@
+package jobs
+
+import (
+	"context"
+	"log"
+
+	"api/internal/di"
+	membershipServices "api/internal/domains/membership/services"
+)
+
+// PlanPriceJob puts scheduled membership plan price changes into effect and moves
+// existing subscribers onto migrating prices
+type PlanPriceJob struct {
+	plans *membershipServices.PlanService
+}
+
+// NewPlanPriceJob creates a new plan price job
+func NewPlanPriceJob(container *di.Container) *PlanPriceJob {
+	return &PlanPriceJob{
+		plans: membershipServices.NewPlanService(container),
+	}
+}
+
+// Name returns the job name
+func (j *PlanPriceJob) Name() string {
+	return "PlanPrice"
+}
+
+// Schedule returns when this job runs (every hour at :20)
+func (j *PlanPriceJob) Schedule() string {
+	return "20 * * * *"
+}
+
+// Run applies due price changes and schedules subscriber migrations
+func (j *PlanPriceJob) Run(ctx context.Context) error {
+	log.Printf("[PRICES] Starting plan price run")
+
+	if err := j.plans.RunPriceChanges(ctx); err != nil {
+		log.Printf("[PRICES] Failed to run price changes: %v", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"api/internal/di"
+	developmentService "api/internal/domains/development/service"
+)
+
+// ProgressReportJob issues last month's athlete progress reports and emails them
+// to families
+type ProgressReportJob struct {
+	development *developmentService.Service
+}
+
+// NewProgressReportJob creates a new progress report job
+func NewProgressReportJob(container *di.Container) *ProgressReportJob {
+	return &ProgressReportJob{
+		development: developmentService.NewService(container),
+	}
+}
+
+// Name returns the job name
+func (j *ProgressReportJob) Name() string {
+	return "ProgressReports"
+}
+
+// Schedule returns when this job runs (daily at 7 AM)
+func (j *ProgressReportJob) Schedule() string {
+	return "0 7 * * *"
+}
+
+// Run issues any missing reports for the previous month and emails unsent ones
+func (j *ProgressReportJob) Run(ctx context.Context) error {
+	log.Printf("[DEVELOPMENT] Starting progress report run")
+
+	created, err := j.development.GenerateProgressReports(ctx, time.Now())
+	if err != nil {
+		log.Printf("[DEVELOPMENT] Failed to generate progress reports: %v", err)
+		return err
+	}
+
+	emailed, err := j.development.EmailProgressReports(ctx)
+	if err != nil {
+		log.Printf("[DEVELOPMENT] Failed to email progress reports: %v", err)
+		return err
+	}
+
+	log.Printf("[DEVELOPMENT] Created %d progress reports, emailed %d", created, emailed)
+	RecordCount(ctx, "progress_reports_created", created)
+	RecordCount(ctx, "progress_reports_emailed", emailed)
+	return nil
+}
@@ -0,0 +1,52 @@
+package jobs
+
+import (
+	"context"
+	"log"
+
+	"api/internal/di"
+	notificationServices "api/internal/domains/notification/services"
+)
+
+// PushReceiptJob fetches delivery receipts for push notifications Expo accepted and
+// prunes tokens of devices that are no longer registered
+type PushReceiptJob struct {
+	notifications *notificationServices.NotificationService
+}
+
+// NewPushReceiptJob creates a new push receipt job
+func NewPushReceiptJob(container *di.Container) *PushReceiptJob {
+	return &PushReceiptJob{
+		notifications: notificationServices.NewNotificationService(container),
+	}
+}
+
+// Name returns the job name
+func (j *PushReceiptJob) Name() string {
+	return "PushReceipt"
+}
+
+// Schedule returns when this job runs (every 15 minutes)
+func (j *PushReceiptJob) Schedule() string {
+	return "*/15 * * * *"
+}
+
+// Run records the receipts that are ready
+func (j *PushReceiptJob) Run(ctx context.Context) error {
+	result, err := j.notifications.CheckReceipts(ctx)
+	if err != nil {
+		log.Printf("[PUSH-RECEIPT] Failed to check push receipts: %v", err)
+		return err
+	}
+
+	RecordCount(ctx, "checked", result.Checked)
+	RecordCount(ctx, "delivered", result.Delivered)
+	RecordCount(ctx, "failed", result.Failed)
+	RecordCount(ctx, "tokens_pruned", result.Pruned)
+
+	if result.Checked > 0 {
+		log.Printf("[PUSH-RECEIPT] Checked %d receipts: %d delivered, %d failed, %d dead tokens pruned",
+			result.Checked, result.Delivered, result.Failed, result.Pruned)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"api/internal/di"
+	referralService "api/internal/domains/referral/service"
+)
+
+// ReferralRewardJob retries referral rewards that could not be issued when their
+// referral qualified
+type ReferralRewardJob struct {
+	referrals *referralService.Service
+}
+
+// NewReferralRewardJob creates a new referral reward job
+func NewReferralRewardJob(container *di.Container) *ReferralRewardJob {
+	return &ReferralRewardJob{
+		referrals: referralService.NewService(container),
+	}
+}
+
+// Name returns the job name
+func (j *ReferralRewardJob) Name() string {
+	return "ReferralReward"
+}
+
+// Schedule returns when this job runs (every 30 minutes)
+func (j *ReferralRewardJob) Schedule() string {
+	return "*/30 * * * *"
+}
+
+// Run issues pending referral rewards
+func (j *ReferralRewardJob) Run(ctx context.Context) error {
+	log.Printf("[REFERRALS] Starting referral reward run")
+
+	issued, err := j.referrals.RetryPendingRewards(ctx, time.Now())
+	if err != nil {
+		log.Printf("[REFERRALS] Failed to retry pending rewards: %v", err)
+		return err
+	}
+
+	log.Printf("[REFERRALS] Issued %d pending rewards", issued)
+	RecordCount(ctx, "rewards_issued", issued)
+	return nil
+}
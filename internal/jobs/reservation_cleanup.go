@@ -4,7 +4,6 @@ import (
 	"context"
 	"database/sql"
 	"log"
-	"time"
 
 	"api/internal/di"
 )
@@ -26,9 +25,9 @@ func (j *ReservationCleanupJob) Name() string {
 	return "ReservationCleanup"
 }
 
-// Interval returns how often this job runs (every hour)
-func (j *ReservationCleanupJob) Interval() time.Duration {
-	return 1 * time.Hour
+// Schedule returns when this job runs (every hour at :30)
+func (j *ReservationCleanupJob) Schedule() string {
+	return "30 * * * *"
 }
 
 // Run executes the cleanup logic
@@ -36,8 +35,10 @@ func (j *ReservationCleanupJob) Run(ctx context.Context) error {
 	log.Printf("[RESERVATION-CLEANUP] Starting cleanup of expired pending reservations")
 
 	var (
-		eventDeleted   int64
-		programDeleted int64
+		eventDeleted      int64
+		programDeleted    int64
+		rentalsExpired    int64
+		playgroundExpired int64
 	)
 
 	// Delete expired pending event reservations (older than 1 hour to be safe)
@@ -64,7 +65,39 @@ func (j *ReservationCleanupJob) Run(ctx context.Context) error {
 	}
 	programDeleted, _ = result.RowsAffected()
 
-	log.Printf("[RESERVATION-CLEANUP] Summary: events=%d, programs=%d deleted", eventDeleted, programDeleted)
+	// Expire unpaid court rental holds. They stop blocking the court once past
+	// hold_expires_at either way; this keeps their status accurate for reporting.
+	result, err = j.db.ExecContext(ctx, `
+		UPDATE location.court_rentals
+		SET status = 'expired', updated_at = CURRENT_TIMESTAMP
+		WHERE status = 'held'
+		  AND hold_expires_at <= CURRENT_TIMESTAMP
+	`)
+	if err != nil {
+		log.Printf("[RESERVATION-CLEANUP] Failed to expire court rental holds: %v", err)
+		return err
+	}
+	rentalsExpired, _ = result.RowsAffected()
+
+	// Same for unpaid playground session holds
+	result, err = j.db.ExecContext(ctx, `
+		UPDATE playground.sessions
+		SET status = 'expired', updated_at = CURRENT_TIMESTAMP
+		WHERE status = 'held'
+		  AND hold_expires_at <= CURRENT_TIMESTAMP
+	`)
+	if err != nil {
+		log.Printf("[RESERVATION-CLEANUP] Failed to expire playground session holds: %v", err)
+		return err
+	}
+	playgroundExpired, _ = result.RowsAffected()
+
+	log.Printf("[RESERVATION-CLEANUP] Summary: events=%d, programs=%d deleted, court rental holds=%d, playground holds=%d expired",
+		eventDeleted, programDeleted, rentalsExpired, playgroundExpired)
+	RecordCount(ctx, "event_reservations_deleted", int(eventDeleted))
+	RecordCount(ctx, "program_reservations_deleted", int(programDeleted))
+	RecordCount(ctx, "court_rental_holds_expired", int(rentalsExpired))
+	RecordCount(ctx, "playground_holds_expired", int(playgroundExpired))
 
 	return nil
 }
@@ -0,0 +1,48 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+)
+
+// DryRunner is implemented by jobs that can report what they would do without doing it.
+// Only these jobs can be triggered with dry_run from the admin jobs API.
+type DryRunner interface {
+	RunDry(ctx context.Context) error
+}
+
+type runCountsKey struct{}
+
+// runCounts collects the counts a job reports during one run.
+type runCounts struct {
+	mu     sync.Mutex
+	values map[string]int64
+}
+
+func withRunCounts(ctx context.Context) (context.Context, *runCounts) {
+	counts := &runCounts{values: map[string]int64{}}
+	return context.WithValue(ctx, runCountsKey{}, counts), counts
+}
+
+func (c *runCounts) snapshot() map[string]int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	snapshot := make(map[string]int64, len(c.values))
+	for name, value := range c.values {
+		snapshot[name] = value
+	}
+	return snapshot
+}
+
+// RecordCount adds n to a named count (e.g. "deleted") in the run history of the job
+// running with ctx. It does nothing when ctx does not belong to a scheduler run, such
+// as when an admin handler runs a job directly.
+func RecordCount(ctx context.Context, name string, n int) {
+	counts, ok := ctx.Value(runCountsKey{}).(*runCounts)
+	if !ok {
+		return
+	}
+	counts.mu.Lock()
+	counts.values[name] += int64(n)
+	counts.mu.Unlock()
+}
@@ -0,0 +1,253 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Run triggers
+const (
+	TriggerScheduled = "scheduled"
+	TriggerRetry     = "retry"
+	TriggerManual    = "manual"
+)
+
+// Run statuses
+const (
+	RunStatusRunning   = "running"
+	RunStatusSucceeded = "succeeded"
+	RunStatusFailed    = "failed"
+)
+
+// JobRun is one recorded run of a job.
+type JobRun struct {
+	ID          uuid.UUID
+	JobName     string
+	Trigger     string
+	DryRun      bool
+	Status      string
+	TriggeredBy *uuid.UUID
+	InstanceID  string
+	Counts      map[string]int64
+	Error       *string
+	StartedAt   time.Time
+	FinishedAt  *time.Time
+	DurationMs  *int64
+}
+
+// jobState is the shared state of one job.
+type jobState struct {
+	Paused              bool
+	PausedBy            *uuid.UUID
+	PausedAt            *time.Time
+	ConsecutiveFailures int
+	LastSuccessAt       *time.Time
+}
+
+const jobRunColumns = `id, job_name, trigger, dry_run, status, triggered_by, instance_id, counts, error,
+	started_at, finished_at, duration_ms`
+
+func scanJobRun(row interface{ Scan(...any) error }) (JobRun, error) {
+	var (
+		run         JobRun
+		triggeredBy uuid.NullUUID
+		counts      []byte
+		runErr      sql.NullString
+		finishedAt  sql.NullTime
+		durationMs  sql.NullInt64
+	)
+	if err := row.Scan(&run.ID, &run.JobName, &run.Trigger, &run.DryRun, &run.Status, &triggeredBy,
+		&run.InstanceID, &counts, &runErr, &run.StartedAt, &finishedAt, &durationMs); err != nil {
+		return JobRun{}, err
+	}
+	if triggeredBy.Valid {
+		run.TriggeredBy = &triggeredBy.UUID
+	}
+	if err := json.Unmarshal(counts, &run.Counts); err != nil {
+		return JobRun{}, err
+	}
+	if runErr.Valid {
+		run.Error = &runErr.String
+	}
+	if finishedAt.Valid {
+		run.FinishedAt = &finishedAt.Time
+	}
+	if durationMs.Valid {
+		run.DurationMs = &durationMs.Int64
+	}
+	return run, nil
+}
+
+func nullableUUID(id uuid.UUID) uuid.NullUUID {
+	return uuid.NullUUID{UUID: id, Valid: id != uuid.Nil}
+}
+
+// startRun records a run as started. Any run of the same job still marked running was
+// cut off (the instance running it died), since the caller holds the job's lock.
+func (s *Scheduler) startRun(ctx context.Context, name, trigger string, dryRun bool, triggeredBy uuid.UUID) (JobRun, error) {
+	if _, err := s.db.ExecContext(ctx, `
+		UPDATE scheduler.job_runs
+		SET status      = 'failed',
+		    error       = 'Run was interrupted before it finished',
+		    finished_at = CURRENT_TIMESTAMP
+		WHERE job_name = $1 AND status = 'running'
+	`, name); err != nil {
+		return JobRun{}, err
+	}
+
+	return scanJobRun(s.db.QueryRowContext(ctx, `
+		INSERT INTO scheduler.job_runs (job_name, trigger, dry_run, triggered_by, instance_id)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING `+jobRunColumns,
+		name, trigger, dryRun, nullableUUID(triggeredBy), s.instanceID))
+}
+
+// finishRun records how a run ended and updates the job's failure streak.
+func (s *Scheduler) finishRun(ctx context.Context, run JobRun, counts map[string]int64, runErr error) (JobRun, int, error) {
+	status := RunStatusSucceeded
+	var errMessage sql.NullString
+	if runErr != nil {
+		status = RunStatusFailed
+		errMessage = sql.NullString{String: runErr.Error(), Valid: true}
+	}
+	countsJSON, err := json.Marshal(counts)
+	if err != nil {
+		return run, 0, err
+	}
+
+	finished, err := scanJobRun(s.db.QueryRowContext(ctx, `
+		UPDATE scheduler.job_runs
+		SET status      = $2,
+		    counts      = $3,
+		    error       = $4,
+		    finished_at = CURRENT_TIMESTAMP,
+		    duration_ms = (EXTRACT(EPOCH FROM CURRENT_TIMESTAMP - started_at) * 1000)::BIGINT
+		WHERE id = $1
+		RETURNING `+jobRunColumns,
+		run.ID, status, countsJSON, errMessage))
+	if err != nil {
+		return run, 0, err
+	}
+
+	// Dry runs don't change anything, so they don't count towards the failure streak.
+	if run.DryRun {
+		return finished, 0, nil
+	}
+
+	var failures int
+	err = s.db.QueryRowContext(ctx, `
+		INSERT INTO scheduler.job_states (job_name, consecutive_failures, last_success_at)
+		VALUES ($1, CASE WHEN $2 THEN 0 ELSE 1 END, CASE WHEN $2 THEN CURRENT_TIMESTAMP END)
+		ON CONFLICT (job_name) DO UPDATE
+		SET consecutive_failures = CASE WHEN $2 THEN 0 ELSE scheduler.job_states.consecutive_failures + 1 END,
+		    last_success_at      = CASE WHEN $2 THEN CURRENT_TIMESTAMP ELSE scheduler.job_states.last_success_at END,
+		    updated_at           = CURRENT_TIMESTAMP
+		RETURNING consecutive_failures
+	`, run.JobName, runErr == nil).Scan(&failures)
+	return finished, failures, err
+}
+
+func (s *Scheduler) getState(ctx context.Context, name string) (jobState, error) {
+	var (
+		state    jobState
+		pausedBy uuid.NullUUID
+		pausedAt sql.NullTime
+		success  sql.NullTime
+	)
+	err := s.db.QueryRowContext(ctx, `
+		SELECT paused, paused_by, paused_at, consecutive_failures, last_success_at
+		FROM scheduler.job_states
+		WHERE job_name = $1
+	`, name).Scan(&state.Paused, &pausedBy, &pausedAt, &state.ConsecutiveFailures, &success)
+	if errors.Is(err, sql.ErrNoRows) {
+		return jobState{}, nil
+	}
+	if err != nil {
+		return jobState{}, err
+	}
+	if pausedBy.Valid {
+		state.PausedBy = &pausedBy.UUID
+	}
+	if pausedAt.Valid {
+		state.PausedAt = &pausedAt.Time
+	}
+	if success.Valid {
+		state.LastSuccessAt = &success.Time
+	}
+	return state, nil
+}
+
+func (s *Scheduler) setPaused(ctx context.Context, name string, paused bool, staffID uuid.UUID) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO scheduler.job_states (job_name, paused, paused_by, paused_at)
+		VALUES ($1, $2, CASE WHEN $2 THEN $3::uuid END, CASE WHEN $2 THEN CURRENT_TIMESTAMP END)
+		ON CONFLICT (job_name) DO UPDATE
+		SET paused     = EXCLUDED.paused,
+		    paused_by  = EXCLUDED.paused_by,
+		    paused_at  = EXCLUDED.paused_at,
+		    updated_at = CURRENT_TIMESTAMP
+	`, name, paused, nullableUUID(staffID))
+	return err
+}
+
+func (s *Scheduler) latestRuns(ctx context.Context) (map[string]JobRun, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT DISTINCT ON (job_name) `+jobRunColumns+`
+		FROM scheduler.job_runs
+		ORDER BY job_name, started_at DESC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	runs := map[string]JobRun{}
+	for rows.Next() {
+		run, err := scanJobRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs[run.JobName] = run
+	}
+	return runs, rows.Err()
+}
+
+func (s *Scheduler) listRuns(ctx context.Context, name string, limit, offset int32) ([]JobRun, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+jobRunColumns+`
+		FROM scheduler.job_runs
+		WHERE job_name = $1
+		ORDER BY started_at DESC
+		LIMIT $2 OFFSET $3
+	`, name, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []JobRun
+	for rows.Next() {
+		run, err := scanJobRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+func (s *Scheduler) pruneRuns(ctx context.Context, olderThan time.Time) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `
+		DELETE FROM scheduler.job_runs
+		WHERE started_at < $1 AND status <> 'running'
+	`, olderThan)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -2,57 +2,129 @@ package jobs
 
 import (
 	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
 	"log"
+	"math/rand/v2"
+	"net/http"
+	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"api/internal/di"
+	errLib "api/internal/libs/errors"
+
+	"github.com/google/uuid"
 )
 
-// Job represents a scheduled job that runs periodically
+// Job represents a background job the scheduler runs
 type Job interface {
 	Name() string
 	Run(ctx context.Context) error
-	Interval() time.Duration
+	// Schedule returns a cron expression (see ParseCron) in the server's time zone.
+	// An empty schedule means the job only runs when started from the admin jobs API.
+	Schedule() string
+}
+
+const (
+	leaderLockName      = "scheduler:leader"
+	leaderCheckInterval = 30 * time.Second
+	// scheduleJitter spreads runs that fall on the same minute
+	scheduleJitter = 30 * time.Second
+	// A failed run is retried up to maxRetries times with exponential backoff before
+	// the job waits for its next scheduled run.
+	maxRetries          = 3
+	retryBaseDelay      = 1 * time.Minute
+	retryMaxDelay       = 30 * time.Minute
+	runHistoryRetention = 90 * 24 * time.Hour
+	bookkeepingTimeout  = 10 * time.Second
+)
+
+type registeredJob struct {
+	job      Job
+	schedule *CronSchedule // nil for jobs that only run by hand
 }
 
-// Scheduler manages and runs scheduled jobs
+// Scheduler runs registered jobs on their cron schedules. Every instance of the API
+// runs a scheduler, but only the instance holding the leader advisory lock in Postgres
+// starts scheduled runs; the others take over when the leader's connection goes away.
+// Each run also holds a per-job advisory lock, so a job never runs twice at the same
+// time even when started by hand on another instance. Runs are recorded in
+// scheduler.job_runs.
 type Scheduler struct {
-	jobs      []Job
-	ctx       context.Context
-	cancel    context.CancelFunc
-	wg        sync.WaitGroup
-	container *di.Container
+	jobs       []*registeredJob
+	byName     map[string]*registeredJob
+	ctx        context.Context
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+	container  *di.Container
+	db         *sql.DB
+	instanceID string
+	isLeader   atomic.Bool
 }
 
 // NewScheduler creates a new job scheduler
 func NewScheduler(container *di.Container) *Scheduler {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &Scheduler{
-		jobs:      []Job{},
-		ctx:       ctx,
-		cancel:    cancel,
-		container: container,
+		jobs:       []*registeredJob{},
+		byName:     map[string]*registeredJob{},
+		ctx:        ctx,
+		cancel:     cancel,
+		container:  container,
+		db:         container.DB,
+		instanceID: newInstanceID(),
+	}
+}
+
+func newInstanceID() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "instance"
 	}
+	return host + "-" + uuid.NewString()[:8]
 }
 
-// RegisterJob adds a job to the scheduler
+// RegisterJob adds a job to the scheduler. It panics if the job's schedule is not a
+// valid cron expression or its name is already taken.
 func (s *Scheduler) RegisterJob(job Job) {
-	s.jobs = append(s.jobs, job)
-	log.Printf("[SCHEDULER] Registered job: %s (interval: %v)", job.Name(), job.Interval())
+	if _, exists := s.byName[job.Name()]; exists {
+		panic(fmt.Sprintf("job %s is registered twice", job.Name()))
+	}
+
+	registered := &registeredJob{job: job}
+	if job.Schedule() != "" {
+		registered.schedule = MustParseCron(job.Schedule())
+	}
+	s.jobs = append(s.jobs, registered)
+	s.byName[job.Name()] = registered
+
+	if registered.schedule == nil {
+		log.Printf("[SCHEDULER] Registered job: %s (manual only)", job.Name())
+		return
+	}
+	log.Printf("[SCHEDULER] Registered job: %s (schedule: %s)", job.Name(), job.Schedule())
 }
 
-// Start begins running all registered jobs
+// Start begins the leader election and the schedule of every registered job
 func (s *Scheduler) Start() {
-	log.Printf("[SCHEDULER] Starting scheduler with %d jobs", len(s.jobs))
+	log.Printf("[SCHEDULER] Starting scheduler with %d jobs on %s", len(s.jobs), s.instanceID)
+
+	s.wg.Add(1)
+	go s.campaign()
 
 	for _, job := range s.jobs {
+		if job.schedule == nil {
+			continue
+		}
 		s.wg.Add(1)
-		go s.runJob(job)
+		go s.runSchedule(job)
 	}
 }
 
-// Stop gracefully stops all running jobs
+// Stop gracefully stops all running jobs and gives up leadership
 func (s *Scheduler) Stop() {
 	log.Printf("[SCHEDULER] Stopping scheduler...")
 	s.cancel()
@@ -60,38 +132,272 @@ func (s *Scheduler) Stop() {
 	log.Printf("[SCHEDULER] All jobs stopped")
 }
 
-// runJob runs a single job on its interval
-func (s *Scheduler) runJob(job Job) {
+// IsLeader reports whether this instance currently starts scheduled runs.
+func (s *Scheduler) IsLeader() bool {
+	return s.isLeader.Load()
+}
+
+// InstanceID identifies this instance in the run history.
+func (s *Scheduler) InstanceID() string {
+	return s.instanceID
+}
+
+// campaign keeps trying to become the leader, and while leader checks that the
+// connection holding the lock is still alive. The lock is a session lock, so Postgres
+// releases it by itself if this instance dies.
+func (s *Scheduler) campaign() {
 	defer s.wg.Done()
 
-	ticker := time.NewTicker(job.Interval())
+	ticker := time.NewTicker(leaderCheckInterval)
 	defer ticker.Stop()
 
-	// Run immediately on startup
-	s.executeJob(job)
-
+	var (
+		conn      *sql.Conn
+		lastPrune time.Time
+	)
 	for {
+		conn = s.holdLeadership(conn)
+		if conn != nil && time.Since(lastPrune) > 24*time.Hour {
+			s.pruneHistory()
+			lastPrune = time.Now()
+		}
+
 		select {
 		case <-s.ctx.Done():
-			log.Printf("[SCHEDULER] Job %s stopped", job.Name())
+			if conn != nil {
+				s.isLeader.Store(false)
+				releaseAdvisoryLock(conn, leaderLockName)
+				log.Printf("[SCHEDULER] %s gave up leadership", s.instanceID)
+			}
 			return
 		case <-ticker.C:
-			s.executeJob(job)
 		}
 	}
 }
 
-// executeJob runs a job and handles errors
-func (s *Scheduler) executeJob(job Job) {
-	log.Printf("[SCHEDULER] Running job: %s", job.Name())
+// holdLeadership returns the connection holding the leader lock, or nil when another
+// instance is the leader.
+func (s *Scheduler) holdLeadership(conn *sql.Conn) *sql.Conn {
+	if conn != nil {
+		err := conn.PingContext(s.ctx)
+		if err == nil {
+			return conn
+		}
+		log.Printf("[SCHEDULER] %s lost its leader connection: %v", s.instanceID, err)
+		s.isLeader.Store(false)
+		discardConn(conn)
+	}
+
+	conn, err := s.db.Conn(s.ctx)
+	if err != nil {
+		log.Printf("[SCHEDULER] Failed to get a connection for leader election: %v", err)
+		return nil
+	}
+	acquired, err := tryAdvisoryLock(s.ctx, conn, leaderLockName)
+	if err != nil || !acquired {
+		if err != nil {
+			log.Printf("[SCHEDULER] Failed to try the leader lock: %v", err)
+		}
+		conn.Close()
+		return nil
+	}
+
+	s.isLeader.Store(true)
+	log.Printf("[SCHEDULER] %s is now the leader", s.instanceID)
+	return conn
+}
+
+func (s *Scheduler) pruneHistory() {
+	ctx, cancel := context.WithTimeout(s.ctx, bookkeepingTimeout)
+	defer cancel()
+
+	pruned, err := s.pruneRuns(ctx, time.Now().Add(-runHistoryRetention))
+	if err != nil {
+		log.Printf("[SCHEDULER] Failed to prune run history: %v", err)
+		return
+	}
+	if pruned > 0 {
+		log.Printf("[SCHEDULER] Pruned %d old job runs", pruned)
+	}
+}
+
+func tryAdvisoryLock(ctx context.Context, conn *sql.Conn, name string) (bool, error) {
+	var acquired bool
+	err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock(hashtext($1))", name).Scan(&acquired)
+	return acquired, err
+}
+
+// releaseAdvisoryLock unlocks and returns the connection to the pool. If the unlock
+// fails the connection is thrown away instead, since a pooled connection still holding
+// the lock would block the job for good.
+func releaseAdvisoryLock(conn *sql.Conn, name string) {
+	ctx, cancel := context.WithTimeout(context.Background(), bookkeepingTimeout)
+	defer cancel()
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock(hashtext($1))", name); err != nil {
+		log.Printf("[SCHEDULER] Failed to release lock %s: %v", name, err)
+		discardConn(conn)
+		return
+	}
+	conn.Close()
+}
+
+// discardConn closes the connection's underlying session rather than pooling it.
+func discardConn(conn *sql.Conn) {
+	conn.Raw(func(any) error { return driver.ErrBadConn })
+	conn.Close()
+}
+
+func jobLockName(name string) string {
+	return "scheduler:job:" + name
+}
+
+// runSchedule waits for each scheduled time of a job and runs it if this instance is
+// the leader and the job is not paused. Failed runs are retried with backoff.
+func (s *Scheduler) runSchedule(job *registeredJob) {
+	defer s.wg.Done()
+
+	var retryAt time.Time
+	for {
+		next := job.schedule.Next(time.Now())
+		trigger := TriggerScheduled
+		if !retryAt.IsZero() && (next.IsZero() || retryAt.Before(next)) {
+			next, trigger = retryAt, TriggerRetry
+		}
+		if next.IsZero() {
+			log.Printf("[SCHEDULER] Job %s has no upcoming run", job.job.Name())
+			return
+		}
+
+		wait := time.Until(next)
+		if trigger == TriggerScheduled {
+			wait += rand.N(scheduleJitter)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			log.Printf("[SCHEDULER] Job %s stopped", job.job.Name())
+			return
+		case <-timer.C:
+		}
+		retryAt = time.Time{}
+
+		if !s.isLeader.Load() {
+			continue
+		}
+		state, err := s.getState(s.ctx, job.job.Name())
+		if err != nil {
+			log.Printf("[SCHEDULER] Skipping %s: failed to load its state: %v", job.job.Name(), err)
+			continue
+		}
+		if state.Paused {
+			log.Printf("[SCHEDULER] Skipping %s: paused", job.job.Name())
+			continue
+		}
+
+		active, startErr := s.beginRun(s.ctx, job, trigger, false, uuid.Nil)
+		if startErr != nil {
+			log.Printf("[SCHEDULER] Skipping %s: %s", job.job.Name(), startErr.Message)
+			continue
+		}
+		run, failures := s.completeRun(active, job, false)
+		if run.Status == RunStatusFailed && failures > 0 && failures <= maxRetries {
+			delay := backoffDelay(failures, rand.Float64())
+			retryAt = time.Now().Add(delay)
+			log.Printf("[SCHEDULER] Retrying %s in %v (failure %d of %d)", job.job.Name(), delay.Round(time.Second), failures, maxRetries)
+		}
+	}
+}
+
+// backoffDelay is how long to wait before retrying after the given number of
+// consecutive failures. jitter in [0, 1) spreads the delay by ±20%.
+func backoffDelay(failures int, jitter float64) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < failures && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	return time.Duration(float64(delay) * (0.8 + 0.4*jitter))
+}
+
+// activeRun is a run that holds its job's lock.
+type activeRun struct {
+	conn *sql.Conn
+	run  JobRun
+}
+
+// beginRun takes the job's lock and records the run as started.
+func (s *Scheduler) beginRun(ctx context.Context, job *registeredJob, trigger string, dryRun bool, triggeredBy uuid.UUID) (*activeRun, *errLib.CommonError) {
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		log.Printf("[SCHEDULER] Failed to get a connection for %s: %v", job.job.Name(), err)
+		return nil, errLib.New("Failed to start job", http.StatusInternalServerError)
+	}
+
+	acquired, err := tryAdvisoryLock(ctx, conn, jobLockName(job.job.Name()))
+	if err != nil {
+		conn.Close()
+		log.Printf("[SCHEDULER] Failed to lock %s: %v", job.job.Name(), err)
+		return nil, errLib.New("Failed to start job", http.StatusInternalServerError)
+	}
+	if !acquired {
+		conn.Close()
+		return nil, errLib.New("Job is already running", http.StatusConflict)
+	}
+
+	run, err := s.startRun(ctx, job.job.Name(), trigger, dryRun, triggeredBy)
+	if err != nil {
+		releaseAdvisoryLock(conn, jobLockName(job.job.Name()))
+		log.Printf("[SCHEDULER] Failed to record the start of %s: %v", job.job.Name(), err)
+		return nil, errLib.New("Failed to start job", http.StatusInternalServerError)
+	}
+	return &activeRun{conn: conn, run: run}, nil
+}
+
+// completeRun runs the job, records the outcome and releases the job's lock. It
+// returns the finished run and the job's consecutive failure count.
+func (s *Scheduler) completeRun(active *activeRun, job *registeredJob, dryRun bool) (JobRun, int) {
+	defer releaseAdvisoryLock(active.conn, jobLockName(job.job.Name()))
+
+	name := job.job.Name()
+	if dryRun {
+		log.Printf("[SCHEDULER] Running job: %s (dry run)", name)
+	} else {
+		log.Printf("[SCHEDULER] Running job: %s", name)
+	}
 	start := time.Now()
 
-	if err := job.Run(s.ctx); err != nil {
-		log.Printf("[SCHEDULER] Job %s failed: %v", job.Name(), err)
-		// TODO: Add metrics for job failures
+	ctx, counts := withRunCounts(s.ctx)
+	runErr := invokeJob(ctx, job.job, dryRun)
+	if runErr != nil {
+		log.Printf("[SCHEDULER] Job %s failed: %v", name, runErr)
 	} else {
-		duration := time.Since(start)
-		log.Printf("[SCHEDULER] Job %s completed successfully (took %v)", job.Name(), duration)
-		// TODO: Add metrics for job success
+		log.Printf("[SCHEDULER] Job %s completed successfully (took %v)", name, time.Since(start))
+	}
+
+	// Record the outcome even when the scheduler is stopping.
+	ctx, cancel := context.WithTimeout(context.Background(), bookkeepingTimeout)
+	defer cancel()
+	run, failures, err := s.finishRun(ctx, active.run, counts.snapshot(), runErr)
+	if err != nil {
+		log.Printf("[SCHEDULER] Failed to record the outcome of %s: %v", name, err)
+	}
+	return run, failures
+}
+
+// invokeJob runs the job, turning a panic into an error so one bad run can't take the
+// server down.
+func invokeJob(ctx context.Context, job Job, dryRun bool) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	if dryRun {
+		return job.(DryRunner).RunDry(ctx)
 	}
+	return job.Run(ctx)
 }
@@ -0,0 +1,141 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	errLib "api/internal/libs/errors"
+
+	"github.com/google/uuid"
+)
+
+// JobInfo describes a registered job for the admin jobs API.
+type JobInfo struct {
+	Name                string
+	Schedule            string // Empty for jobs that only run by hand
+	NextRunAt           *time.Time
+	Paused              bool
+	PausedBy            *uuid.UUID
+	PausedAt            *time.Time
+	ConsecutiveFailures int
+	LastSuccessAt       *time.Time
+	SupportsDryRun      bool
+	LastRun             *JobRun
+}
+
+// ListJobs returns every registered job with its state and latest run.
+func (s *Scheduler) ListJobs(ctx context.Context) ([]JobInfo, *errLib.CommonError) {
+	latest, err := s.latestRuns(ctx)
+	if err != nil {
+		log.Printf("[SCHEDULER] Failed to load latest runs: %v", err)
+		return nil, errLib.New("Failed to load jobs", http.StatusInternalServerError)
+	}
+
+	jobs := make([]JobInfo, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		info, infoErr := s.jobInfo(ctx, job)
+		if infoErr != nil {
+			return nil, infoErr
+		}
+		if run, ok := latest[job.job.Name()]; ok {
+			info.LastRun = &run
+		}
+		jobs = append(jobs, info)
+	}
+	return jobs, nil
+}
+
+// ListRuns returns a job's run history, newest first.
+func (s *Scheduler) ListRuns(ctx context.Context, name string, limit, offset int32) ([]JobRun, *errLib.CommonError) {
+	if _, err := s.lookup(name); err != nil {
+		return nil, err
+	}
+
+	runs, err := s.listRuns(ctx, name, limit, offset)
+	if err != nil {
+		log.Printf("[SCHEDULER] Failed to list runs of %s: %v", name, err)
+		return nil, errLib.New("Failed to load job runs", http.StatusInternalServerError)
+	}
+	return runs, nil
+}
+
+// Trigger starts a run of the job in the background and returns it as started. Paused
+// jobs can still be triggered. It fails with 409 if the job is already running on any
+// instance, and with 400 if a dry run is asked of a job that doesn't support one.
+func (s *Scheduler) Trigger(ctx context.Context, name string, dryRun bool, staffID uuid.UUID) (JobRun, *errLib.CommonError) {
+	job, err := s.lookup(name)
+	if err != nil {
+		return JobRun{}, err
+	}
+	if _, ok := job.job.(DryRunner); dryRun && !ok {
+		return JobRun{}, errLib.New("This job does not support dry runs", http.StatusBadRequest)
+	}
+
+	active, err := s.beginRun(ctx, job, TriggerManual, dryRun, staffID)
+	if err != nil {
+		return JobRun{}, err
+	}
+	log.Printf("[SCHEDULER] %s started by %s", name, staffID)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.completeRun(active, job, dryRun)
+	}()
+	return active.run, nil
+}
+
+// SetPaused pauses or resumes a job's schedule on every instance.
+func (s *Scheduler) SetPaused(ctx context.Context, name string, paused bool, staffID uuid.UUID) (JobInfo, *errLib.CommonError) {
+	job, err := s.lookup(name)
+	if err != nil {
+		return JobInfo{}, err
+	}
+
+	if setErr := s.setPaused(ctx, name, paused, staffID); setErr != nil {
+		log.Printf("[SCHEDULER] Failed to update pause state of %s: %v", name, setErr)
+		return JobInfo{}, errLib.New("Failed to update job", http.StatusInternalServerError)
+	}
+	if paused {
+		log.Printf("[SCHEDULER] %s paused by %s", name, staffID)
+	} else {
+		log.Printf("[SCHEDULER] %s resumed by %s", name, staffID)
+	}
+	return s.jobInfo(ctx, job)
+}
+
+func (s *Scheduler) lookup(name string) (*registeredJob, *errLib.CommonError) {
+	job, ok := s.byName[name]
+	if !ok {
+		return nil, errLib.New("Job not found", http.StatusNotFound)
+	}
+	return job, nil
+}
+
+func (s *Scheduler) jobInfo(ctx context.Context, job *registeredJob) (JobInfo, *errLib.CommonError) {
+	state, err := s.getState(ctx, job.job.Name())
+	if err != nil {
+		log.Printf("[SCHEDULER] Failed to load state of %s: %v", job.job.Name(), err)
+		return JobInfo{}, errLib.New("Failed to load jobs", http.StatusInternalServerError)
+	}
+
+	_, supportsDryRun := job.job.(DryRunner)
+	info := JobInfo{
+		Name:                job.job.Name(),
+		Schedule:            job.job.Schedule(),
+		Paused:              state.Paused,
+		PausedBy:            state.PausedBy,
+		PausedAt:            state.PausedAt,
+		ConsecutiveFailures: state.ConsecutiveFailures,
+		LastSuccessAt:       state.LastSuccessAt,
+		SupportsDryRun:      supportsDryRun,
+	}
+	if job.schedule != nil && !state.Paused {
+		if next := job.schedule.Next(time.Now()); !next.IsZero() {
+			info.NextRunAt = &next
+		}
+	}
+	return info, nil
+}
@@ -27,9 +27,9 @@ func (j *SubsidyExpirationJob) Name() string {
 	return "SubsidyExpiration"
 }
 
-// Interval returns how often this job runs (every 30 minutes)
-func (j *SubsidyExpirationJob) Interval() time.Duration {
-	return 30 * time.Minute
+// Schedule returns when this job runs (every 30 minutes)
+func (j *SubsidyExpirationJob) Schedule() string {
+	return "*/30 * * * *"
 }
 
 // Run executes the expiration logic
@@ -98,6 +98,8 @@ func (j *SubsidyExpirationJob) Run(ctx context.Context) error {
 	}
 
 	log.Printf("[SUBSIDY-EXPIRATION] Summary: expired=%d, errors=%d", expired, errors)
+	RecordCount(ctx, "expired", expired)
+	RecordCount(ctx, "errors", errors)
 
 	// TODO: Add Prometheus metrics
 	// metrics.SubsidiesExpired.Add(float64(expired))
@@ -0,0 +1,49 @@
+package jobs
+
+import (
+	"context"
+	"log"
+
+	"api/internal/di"
+	userServices "api/internal/domains/user/services"
+)
+
+// SuspensionJob lifts expired suspensions and runs scheduled suspensions and unsuspensions
+type SuspensionJob struct {
+	suspensions *userServices.SuspensionService
+}
+
+// NewSuspensionJob creates a new suspension job
+func NewSuspensionJob(container *di.Container) *SuspensionJob {
+	return &SuspensionJob{
+		suspensions: userServices.NewSuspensionService(container),
+	}
+}
+
+// Name returns the job name
+func (j *SuspensionJob) Name() string {
+	return "Suspension"
+}
+
+// Schedule returns when this job runs (every 15 minutes)
+func (j *SuspensionJob) Schedule() string {
+	return "*/15 * * * *"
+}
+
+// Run lifts expired suspensions first, so a scheduled suspension due in the same run
+// isn't blocked by an old one that has already run out
+func (j *SuspensionJob) Run(ctx context.Context) error {
+	log.Printf("[SUSPENSIONS] Starting suspension run")
+
+	if err := j.suspensions.LiftExpiredSuspensions(ctx); err != nil {
+		log.Printf("[SUSPENSIONS] Failed to lift expired suspensions: %v", err)
+		return err
+	}
+
+	if err := j.suspensions.RunScheduledSuspensionActions(ctx); err != nil {
+		log.Printf("[SUSPENSIONS] Failed to run scheduled actions: %v", err)
+		return err
+	}
+
+	return nil
+}
@@ -3,6 +3,8 @@ package errLib
 type CommonError struct {
 	Message  string
 	HTTPCode int
+	// Details is optional structured context returned to the client alongside the message.
+	Details interface{}
 }
 
 // New creates a new CommonError
@@ -13,6 +15,15 @@ func New(message string, httpCode int) *CommonError {
 	}
 }
 
+// NewWithDetails creates a new CommonError carrying structured details for the client
+func NewWithDetails(message string, httpCode int, details interface{}) *CommonError {
+	return &CommonError{
+		Message:  message,
+		HTTPCode: httpCode,
+		Details:  details,
+	}
+}
+
 // Error implements the error interface
 func (e *CommonError) Error() string {
 	return e.Message
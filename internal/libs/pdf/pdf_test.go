@@ -0,0 +1,52 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBytesCrossReferencesEveryObject(t *testing.T) {
+	doc := New()
+	doc.AddPage().Text(72, 720, Bold, 18, "Receipt")
+	doc.AddPage().Text(72, 720, Regular, 10, "Page two")
+
+	out := doc.Bytes()
+
+	require.True(t, bytes.HasPrefix(out, []byte("%PDF-1.4\n")))
+	require.True(t, bytes.HasSuffix(out, []byte("%%EOF\n")))
+
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(out)
+	require.NotNil(t, startxref)
+	xref, err := strconv.Atoi(string(startxref[1]))
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(out[xref:], []byte("xref\n0 9\n")))
+
+	// Catalog, page tree, two fonts and two objects per page
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(out[xref:], -1)
+	require.Len(t, entries, 8)
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		assert.True(t, bytes.HasPrefix(out[offset:], []byte(fmt.Sprintf("%d 0 obj\n", i+1))), "object %d", i+1)
+	}
+	assert.Contains(t, string(out), "/Count 2")
+}
+
+func TestTextEscapesAndEncodes(t *testing.T) {
+	doc := New()
+	doc.AddPage().Text(0, 0, Regular, 10, `Léa (U12) \ 50%`)
+
+	out := doc.Bytes()
+
+	assert.Contains(t, string(out), "(L\xe9a \\(U12\\) \\\\ 50%) Tj")
+}
+
+func TestTextWidth(t *testing.T) {
+	assert.InDelta(t, 5.56*4, TextWidth(Regular, 10, "1234"), 0.001)
+	assert.Greater(t, TextWidth(Bold, 10, "Total"), TextWidth(Regular, 10, "Total"))
+}
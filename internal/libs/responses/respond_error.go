@@ -9,10 +9,14 @@ import (
 func RespondWithError(w http.ResponseWriter, err *errLib.CommonError) {
 
 	w.WriteHeader(err.HTTPCode)
+	body := map[string]interface{}{
+		"message": err.Message,
+	}
+	if err.Details != nil {
+		body["details"] = err.Details
+	}
 	response := map[string]interface{}{
-		"error": map[string]string{
-			"message": err.Message,
-		},
+		"error": body,
 	}
 
 	if err := json.NewEncoder(w).Encode(response); err != nil {
@@ -2,6 +2,7 @@ package validators
 
 import (
 	errLib "api/internal/libs/errors"
+	"api/utils/timezone"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -58,9 +59,14 @@ func notWhiteSpace(fl validator.FieldLevel) bool {
 	return strings.TrimSpace(fl.Field().String()) != ""
 }
 
+func ianaTimezone(fl validator.FieldLevel) bool {
+	return timezone.IsValid(fl.Field().String())
+}
+
 func init() {
 	validate = validator.New()
 	validate.RegisterValidation("notwhitespace", notWhiteSpace)
+	validate.RegisterValidation("timezone", ianaTimezone)
 }
 
 // ValidateDto validates the given DTO using the go-playground/validator library.
@@ -111,6 +117,8 @@ func parseValidationErrors(err error, structType reflect.Type) *errLib.CommonErr
 				customMessage = fmt.Sprintf("%s: must be greater than %s", fieldName, getJSONFieldNameForParam(e.Param(), structType))
 			case "oneof":
 				customMessage = fmt.Sprintf("%s: must be one of the following values: %s", fieldName, strings.Join(strings.Split(e.Param(), ","), ", "))
+			case "timezone":
+				customMessage = fmt.Sprintf("%s: must be a valid IANA timezone (e.g. America/Edmonton)", fieldName)
 			default:
 				customMessage = fmt.Sprintf("%s: validation failed on '%s'", fieldName, e.Tag())
 			}
@@ -37,3 +37,15 @@ func ParseTime(timeStr string) (string, *errLib.CommonError) {
 	// Return the time in the same format (15:04:05+00:00)
 	return timeParsed.Format(expectedFormat), nil
 }
+
+// ParseDate parses a calendar date in the format "2006-01-02" (YYYY-MM-DD). The result is
+// midnight UTC on that date.
+func ParseDate(str string) (time.Time, *errLib.CommonError) {
+	date, err := time.Parse("2006-01-02", str)
+	if err != nil {
+		errMsg := fmt.Sprintf("Invalid date format. Expected YYYY-MM-DD, got: %s", str)
+		return time.Time{}, errLib.New(errMsg, http.StatusBadRequest)
+	}
+
+	return date, nil
+}
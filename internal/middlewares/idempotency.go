@@ -0,0 +1,409 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	errLib "api/internal/libs/errors"
+	jwtLib "api/internal/libs/jwt"
+	responseHandlers "api/internal/libs/responses"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header clients send to make a retry safe.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed from an earlier request.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	idempotencyKeyTTL      = 24 * time.Hour
+	maxIdempotencyKeyLen   = 255
+	maxIdempotentBodyBytes = 10 << 20
+)
+
+// idempotencyLease is how long a key stays held for a request without being renewed.
+// The lease is renewed every third of it while the handler runs, however long that
+// takes, so a key is only taken again once the server holding it has died.
+var idempotencyLease = time.Minute
+
+// IdempotencyMiddleware makes POST, PUT, PATCH and DELETE requests that carry an
+// Idempotency-Key header safe to retry. The first request with a key runs normally and
+// its response is stored in Postgres; a retry with the same key and payload gets the
+// stored response back without running the handler again. Reusing a key with a
+// different payload is rejected with 422, and a retry that arrives while the first
+// request is still running gets 409.
+//
+// Server errors are stored and replayed like any other response: the handler may have
+// had effects before it failed, so running it again takes a new key. Only responses
+// the request never got past authentication or rate limiting for are not stored, so
+// the client can retry them. Keys are scoped to the caller's credentials; requests
+// without credentials, and requests without the header, are passed through unchanged.
+// Endpoints that move money add RequireIdempotencyKey so the header cannot be left out.
+func IdempotencyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" || !isMutatingMethod(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if idempotencyKeys == nil {
+			log.Printf("Warning: Database connection not set in idempotency middleware")
+			next.ServeHTTP(w, r)
+			return
+		}
+		// Without credentials there is nothing to keep one caller's keys from another's
+		scope, ok := idempotencyScope(r)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLen {
+			responseHandlers.RespondWithError(w, errLib.New("Idempotency-Key must be at most 255 characters", http.StatusBadRequest))
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxIdempotentBodyBytes+1))
+		if err != nil {
+			responseHandlers.RespondWithError(w, errLib.New("Failed to read request body", http.StatusBadRequest))
+			return
+		}
+		if len(body) > maxIdempotentBodyBytes {
+			responseHandlers.RespondWithError(w, errLib.New("Request body is too large to be used with an Idempotency-Key", http.StatusRequestEntityTooLarge))
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		// Storing the outcome must not be cut short by the client hanging up.
+		ctx := context.WithoutCancel(r.Context())
+		hash := requestFingerprint(r.Method, r.URL.RequestURI(), body)
+
+		acquired, err := idempotencyKeys.acquire(ctx, scope, key, r.Method, r.URL.Path, hash, time.Now().Add(idempotencyLease))
+		if err != nil {
+			log.Printf("Failed to acquire idempotency key: %v", err)
+			responseHandlers.RespondWithError(w, errLib.New("Failed to process Idempotency-Key", http.StatusInternalServerError))
+			return
+		}
+		if !acquired {
+			replayIdempotentResponse(ctx, w, scope, key, hash)
+			return
+		}
+
+		recorder := &recordingResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		finished := false
+		defer func() {
+			// The handler panicked part way through and may have had effects, so the key
+			// is answered with an error rather than released for the retry to run again.
+			if !finished {
+				storeFailedAttempt(ctx, scope, key)
+			}
+		}()
+		defer renewIdempotencyLease(ctx, scope, key)()
+
+		next.ServeHTTP(recorder, r)
+		finished = true
+
+		if !isStorableStatus(recorder.statusCode) {
+			if err := idempotencyKeys.release(ctx, scope, key); err != nil {
+				log.Printf("Failed to release idempotency key %s: %v", key, err)
+			}
+			return
+		}
+		headers, err := json.Marshal(replayableHeaders(recorder.Header()))
+		if err == nil {
+			err = idempotencyKeys.complete(ctx, scope, key, recorder.statusCode, headers, recorder.body.Bytes())
+		}
+		if err != nil {
+			log.Printf("Failed to store idempotent response for key %s: %v", key, err)
+		}
+	})
+}
+
+// RequireIdempotencyKey rejects requests sent without an Idempotency-Key header. It is
+// added to endpoints that move money, behind IdempotencyMiddleware, so a retried
+// submission can never run twice.
+func RequireIdempotencyKey(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(IdempotencyKeyHeader) == "" {
+			responseHandlers.RespondWithError(w, errLib.New("Idempotency-Key header is required", http.StatusBadRequest))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// renewIdempotencyLease keeps renewing the lease on a key until the returned function
+// is called, so a retry of a slow request is answered with 409 rather than run again.
+func renewIdempotencyLease(ctx context.Context, scope, key string) (stop func()) {
+	store, lease := idempotencyKeys, idempotencyLease
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(lease / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := store.renew(ctx, scope, key, time.Now().Add(lease)); err != nil {
+					log.Printf("Failed to renew lease on idempotency key %s: %v", key, err)
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// replayIdempotentResponse answers a retry from the stored response of the first request.
+func replayIdempotentResponse(ctx context.Context, w http.ResponseWriter, scope, key, hash string) {
+	stored, err := idempotencyKeys.load(ctx, scope, key)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Released by a first attempt that never got past authentication between our
+		// insert and this read.
+		w.Header().Set("Retry-After", "1")
+		responseHandlers.RespondWithError(w, errLib.New("A request with this Idempotency-Key is still being processed", http.StatusConflict))
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to load idempotent response for key %s: %v", key, err)
+		responseHandlers.RespondWithError(w, errLib.New("Failed to process Idempotency-Key", http.StatusInternalServerError))
+		return
+	}
+
+	if stored.requestHash != hash {
+		responseHandlers.RespondWithError(w, errLib.New("Idempotency-Key has already been used with a different request", http.StatusUnprocessableEntity))
+		return
+	}
+	if stored.status != "completed" || !stored.responseStatus.Valid {
+		w.Header().Set("Retry-After", "1")
+		responseHandlers.RespondWithError(w, errLib.New("A request with this Idempotency-Key is still being processed", http.StatusConflict))
+		return
+	}
+
+	var storedHeaders http.Header
+	if len(stored.responseHeaders) > 0 {
+		if err := json.Unmarshal(stored.responseHeaders, &storedHeaders); err != nil {
+			log.Printf("Failed to decode stored headers for key %s: %v", key, err)
+		}
+	}
+	for name, values := range storedHeaders {
+		w.Header()[name] = values
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(stored.responseBody)))
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(int(stored.responseStatus.Int32))
+	w.Write(stored.responseBody)
+}
+
+// storeFailedAttempt completes the key of a request whose handler did not finish with
+// a 500 asking for a new key, which retries with it are answered with.
+func storeFailedAttempt(ctx context.Context, scope, key string) {
+	body, _ := json.Marshal(map[string]interface{}{
+		"error": map[string]interface{}{
+			"message": "The request failed part way through; retry it with a new Idempotency-Key",
+		},
+	})
+	headers, _ := json.Marshal(http.Header{"Content-Type": {"application/json"}})
+	if err := idempotencyKeys.complete(ctx, scope, key, http.StatusInternalServerError, headers, body); err != nil {
+		log.Printf("Failed to store failed attempt for idempotency key %s: %v", key, err)
+	}
+}
+
+// storedIdempotencyKey is a key as stored, with the response of the request that took it
+type storedIdempotencyKey struct {
+	requestHash     string
+	status          string
+	responseStatus  sql.NullInt32
+	responseHeaders []byte
+	responseBody    []byte
+}
+
+// idempotencyStore keeps idempotency keys and the responses stored under them. It is
+// set with the database connection; tests replace it to run without one.
+type idempotencyStore interface {
+	// acquire claims the key for a request and holds it until lockedUntil. It returns
+	// false when the key is taken by an earlier request that has not expired and, if
+	// still processing, whose lease has not run out.
+	acquire(ctx context.Context, scope, key, method, path, hash string, lockedUntil time.Time) (bool, error)
+	// load returns the key, or sql.ErrNoRows when it is not taken
+	load(ctx context.Context, scope, key string) (storedIdempotencyKey, error)
+	// complete stores the response of the request holding the key
+	complete(ctx context.Context, scope, key string, status int, headers, body []byte) error
+	// release gives up a key that is still processing so it can be taken again
+	release(ctx context.Context, scope, key string) error
+	// renew extends the lease on a key that is still processing
+	renew(ctx context.Context, scope, key string, lockedUntil time.Time) error
+}
+
+var idempotencyKeys idempotencyStore
+
+type postgresIdempotencyStore struct {
+	db *sql.DB
+}
+
+func (s postgresIdempotencyStore) acquire(ctx context.Context, scope, key, method, path, hash string, lockedUntil time.Time) (bool, error) {
+	var claimed bool
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO public.idempotency_keys (scope, idempotency_key, method, path, request_hash, expires_at, locked_until)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (scope, idempotency_key) DO UPDATE
+		SET method           = EXCLUDED.method,
+		    path             = EXCLUDED.path,
+		    request_hash     = EXCLUDED.request_hash,
+		    status           = 'processing',
+		    response_status  = NULL,
+		    response_headers = NULL,
+		    response_body    = NULL,
+		    created_at       = CURRENT_TIMESTAMP,
+		    completed_at     = NULL,
+		    expires_at       = EXCLUDED.expires_at,
+		    locked_until     = EXCLUDED.locked_until
+		WHERE public.idempotency_keys.expires_at < CURRENT_TIMESTAMP
+		   OR (public.idempotency_keys.status = 'processing' AND public.idempotency_keys.locked_until < CURRENT_TIMESTAMP)
+		RETURNING TRUE
+	`, scope, key, method, path, hash, time.Now().Add(idempotencyKeyTTL), lockedUntil).Scan(&claimed)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return claimed, err
+}
+
+func (s postgresIdempotencyStore) load(ctx context.Context, scope, key string) (storedIdempotencyKey, error) {
+	var stored storedIdempotencyKey
+	err := s.db.QueryRowContext(ctx, `
+		SELECT request_hash, status, response_status, response_headers, response_body
+		FROM public.idempotency_keys
+		WHERE scope = $1 AND idempotency_key = $2
+	`, scope, key).Scan(&stored.requestHash, &stored.status, &stored.responseStatus, &stored.responseHeaders, &stored.responseBody)
+	return stored, err
+}
+
+func (s postgresIdempotencyStore) complete(ctx context.Context, scope, key string, status int, headers, body []byte) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE public.idempotency_keys
+		SET status           = 'completed',
+		    response_status  = $3,
+		    response_headers = $4,
+		    response_body    = $5,
+		    completed_at     = CURRENT_TIMESTAMP
+		WHERE scope = $1 AND idempotency_key = $2
+	`, scope, key, status, headers, body)
+	return err
+}
+
+func (s postgresIdempotencyStore) release(ctx context.Context, scope, key string) error {
+	_, err := s.db.ExecContext(ctx, `
+		DELETE FROM public.idempotency_keys
+		WHERE scope = $1 AND idempotency_key = $2 AND status = 'processing'
+	`, scope, key)
+	return err
+}
+
+func (s postgresIdempotencyStore) renew(ctx context.Context, scope, key string, lockedUntil time.Time) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE public.idempotency_keys
+		SET locked_until = $3
+		WHERE scope = $1 AND idempotency_key = $2 AND status = 'processing'
+	`, scope, key, lockedUntil)
+	return err
+}
+
+// idempotencyScope identifies who a key belongs to: the user ID from a valid token, or a
+// hash of the Authorization header otherwise (e.g. a Firebase token at registration).
+// It reports false for requests without credentials, whose keys could collide.
+func idempotencyScope(r *http.Request) (string, bool) {
+	if token, err := extractToken(r); err == nil {
+		if claims, err := jwtLib.VerifyToken(token); err == nil {
+			return "user:" + claims.UserID.String(), true
+		}
+	}
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		sum := sha256.Sum256([]byte(auth))
+		return "auth:" + hex.EncodeToString(sum[:]), true
+	}
+	return "", false
+}
+
+// requestFingerprint hashes what makes two requests the same request.
+func requestFingerprint(method, requestURI string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write([]byte(requestURI))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// isStorableStatus reports whether a response is final for the request. 401, 403 and
+// 429 are returned before the handler has done anything, so those are left for the
+// client to retry; anything else, server errors included, may follow effects.
+func isStorableStatus(status int) bool {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests:
+		return false
+	}
+	return true
+}
+
+// replayableHeaders drops headers that describe the original connection rather than
+// the response itself.
+func replayableHeaders(header http.Header) http.Header {
+	kept := header.Clone()
+	for _, name := range []string{"Content-Length", "Date", "Connection", "Transfer-Encoding", "Vary",
+		"Access-Control-Allow-Origin", "Access-Control-Allow-Credentials", "Access-Control-Expose-Headers"} {
+		kept.Del(name)
+	}
+	return kept
+}
+
+// recordingResponseWriter passes the response through while keeping a copy of it.
+type recordingResponseWriter struct {
+	http.ResponseWriter
+	statusCode    int
+	body          bytes.Buffer
+	headerWritten bool
+}
+
+func (rw *recordingResponseWriter) WriteHeader(statusCode int) {
+	if !rw.headerWritten {
+		rw.statusCode = statusCode
+		rw.headerWritten = true
+		rw.ResponseWriter.WriteHeader(statusCode)
+	}
+}
+
+func (rw *recordingResponseWriter) Write(data []byte) (int, error) {
+	if !rw.headerWritten {
+		rw.WriteHeader(http.StatusOK)
+	}
+	rw.body.Write(data)
+	return rw.ResponseWriter.Write(data)
+}
+
+func (rw *recordingResponseWriter) Flush() {
+	if f, ok := rw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,374 @@
+package middlewares
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRequestFingerprint(t *testing.T) {
+	a := requestFingerprint(http.MethodPost, "/admin/collections/charge-card", []byte(`{"amount":10}`))
+	b := requestFingerprint(http.MethodPost, "/admin/collections/charge-card", []byte(`{"amount":10}`))
+	if a != b || len(a) != 64 {
+		t.Fatalf("expected the same 64-character fingerprint, got %s and %s", a, b)
+	}
+
+	if a == requestFingerprint(http.MethodPost, "/admin/collections/charge-card", []byte(`{"amount":20}`)) {
+		t.Fatalf("expected a different body to change the fingerprint")
+	}
+	if a == requestFingerprint(http.MethodPut, "/admin/collections/charge-card", []byte(`{"amount":10}`)) {
+		t.Fatalf("expected a different method to change the fingerprint")
+	}
+}
+
+func TestIsStorableStatus(t *testing.T) {
+	for _, status := range []int{http.StatusOK, http.StatusCreated, http.StatusBadRequest, http.StatusConflict, http.StatusInternalServerError, http.StatusBadGateway} {
+		if !isStorableStatus(status) {
+			t.Fatalf("expected %d to be stored", status)
+		}
+	}
+	for _, status := range []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests} {
+		if isStorableStatus(status) {
+			t.Fatalf("expected %d not to be stored", status)
+		}
+	}
+}
+
+func TestIdempotencyScopeWithoutValidToken(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/register/athlete", nil)
+	if scope, ok := idempotencyScope(r); ok {
+		t.Fatalf("expected no scope without credentials, got %s", scope)
+	}
+
+	r.Header.Set("Authorization", "Bearer firebase-token")
+	scope, ok := idempotencyScope(r)
+	if !ok || len(scope) != len("auth:")+64 || scope[:5] != "auth:" {
+		t.Fatalf("expected a hashed credential scope, got %s", scope)
+	}
+}
+
+func TestIdempotencyMiddlewarePassesThroughWithoutKey(t *testing.T) {
+	called := false
+	handler := IdempotencyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/events", nil))
+	if !called || rec.Code != http.StatusCreated {
+		t.Fatalf("expected handler to run, called=%v status=%d", called, rec.Code)
+	}
+}
+
+// memoryIdempotencyStore keeps keys and their leases in memory the way the
+// idempotency_keys table does, without expiry
+type memoryIdempotencyStore struct {
+	mu     sync.Mutex
+	keys   map[string]storedIdempotencyKey
+	leases map[string]time.Time
+}
+
+func useMemoryIdempotencyStore(t *testing.T) *memoryIdempotencyStore {
+	store := &memoryIdempotencyStore{keys: map[string]storedIdempotencyKey{}, leases: map[string]time.Time{}}
+	previous := idempotencyKeys
+	idempotencyKeys = store
+	t.Cleanup(func() { idempotencyKeys = previous })
+	return store
+}
+
+func (s *memoryIdempotencyStore) acquire(_ context.Context, scope, key, _, _, hash string, lockedUntil time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if stored, taken := s.keys[scope+"/"+key]; taken {
+		if stored.status != "processing" || s.leases[scope+"/"+key].After(time.Now()) {
+			return false, nil
+		}
+	}
+	s.keys[scope+"/"+key] = storedIdempotencyKey{requestHash: hash, status: "processing"}
+	s.leases[scope+"/"+key] = lockedUntil
+	return true, nil
+}
+
+func (s *memoryIdempotencyStore) renew(_ context.Context, scope, key string, lockedUntil time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.keys[scope+"/"+key].status == "processing" {
+		s.leases[scope+"/"+key] = lockedUntil
+	}
+	return nil
+}
+
+func (s *memoryIdempotencyStore) load(_ context.Context, scope, key string) (storedIdempotencyKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.keys[scope+"/"+key]
+	if !ok {
+		return storedIdempotencyKey{}, sql.ErrNoRows
+	}
+	return stored, nil
+}
+
+func (s *memoryIdempotencyStore) complete(_ context.Context, scope, key string, status int, headers, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored := s.keys[scope+"/"+key]
+	stored.status = "completed"
+	stored.responseStatus = sql.NullInt32{Int32: int32(status), Valid: true}
+	stored.responseHeaders = headers
+	stored.responseBody = body
+	s.keys[scope+"/"+key] = stored
+	return nil
+}
+
+func (s *memoryIdempotencyStore) release(_ context.Context, scope, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.keys[scope+"/"+key].status == "processing" {
+		delete(s.keys, scope+"/"+key)
+	}
+	return nil
+}
+
+func newIdempotentRequest(key, body string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/admin/collections/charge-card", strings.NewReader(body))
+	r.Header.Set("Authorization", "Bearer staff-token")
+	r.Header.Set(IdempotencyKeyHeader, key)
+	return r
+}
+
+func TestIdempotencyMiddlewareReplaysResponse(t *testing.T) {
+	useMemoryIdempotencyStore(t)
+	calls := 0
+	handler := IdempotencyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"charged":10}`))
+	}))
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, newIdempotentRequest("charge-1", `{"amount":10}`))
+	retry := httptest.NewRecorder()
+	handler.ServeHTTP(retry, newIdempotentRequest("charge-1", `{"amount":10}`))
+
+	if calls != 1 {
+		t.Fatalf("expected the handler to run once, ran %d times", calls)
+	}
+	if retry.Code != http.StatusCreated || retry.Body.String() != `{"charged":10}` {
+		t.Fatalf("expected the stored response, got %d %s", retry.Code, retry.Body.String())
+	}
+	if retry.Header().Get(IdempotentReplayedHeader) != "true" || retry.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("expected replayed headers, got %v", retry.Header())
+	}
+	if first.Header().Get(IdempotentReplayedHeader) != "" {
+		t.Fatalf("expected the first response not to be marked as replayed")
+	}
+}
+
+func TestIdempotencyMiddlewareRejectsDifferentBody(t *testing.T) {
+	useMemoryIdempotencyStore(t)
+	calls := 0
+	handler := IdempotencyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), newIdempotentRequest("charge-1", `{"amount":10}`))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, newIdempotentRequest("charge-1", `{"amount":20}`))
+
+	if rec.Code != http.StatusUnprocessableEntity || calls != 1 {
+		t.Fatalf("expected 422 without running the handler again, got %d after %d calls", rec.Code, calls)
+	}
+}
+
+func TestIdempotencyMiddlewareRejectsConcurrentRetry(t *testing.T) {
+	useMemoryIdempotencyStore(t)
+	entered := make(chan struct{})
+	unblock := make(chan struct{})
+	handler := IdempotencyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(entered)
+		<-unblock
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, newIdempotentRequest("charge-1", `{"amount":10}`))
+		done <- rec
+	}()
+	<-entered
+
+	inFlight := httptest.NewRecorder()
+	handler.ServeHTTP(inFlight, newIdempotentRequest("charge-1", `{"amount":10}`))
+	if inFlight.Code != http.StatusConflict || inFlight.Header().Get("Retry-After") == "" {
+		t.Fatalf("expected 409 with Retry-After while the first request runs, got %d", inFlight.Code)
+	}
+
+	close(unblock)
+	if first := <-done; first.Code != http.StatusCreated {
+		t.Fatalf("expected the first request to finish, got %d", first.Code)
+	}
+	retry := httptest.NewRecorder()
+	handler.ServeHTTP(retry, newIdempotentRequest("charge-1", `{"amount":10}`))
+	if retry.Code != http.StatusCreated || retry.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Fatalf("expected the stored response once the first request finished, got %d", retry.Code)
+	}
+}
+
+func TestIdempotencyMiddlewareReplaysServerError(t *testing.T) {
+	useMemoryIdempotencyStore(t)
+	calls := 0
+	handler := IdempotencyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), newIdempotentRequest("charge-1", `{"amount":10}`))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, newIdempotentRequest("charge-1", `{"amount":10}`))
+
+	if calls != 1 || rec.Code != http.StatusBadGateway {
+		t.Fatalf("expected the failed response to be replayed, got %d after %d calls", rec.Code, calls)
+	}
+}
+
+func TestIdempotencyMiddlewareStoresPanickedAttempt(t *testing.T) {
+	useMemoryIdempotencyStore(t)
+	handler := IdempotencyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("charge failed after the card was charged")
+	}))
+
+	func() {
+		defer func() { recover() }()
+		handler.ServeHTTP(httptest.NewRecorder(), newIdempotentRequest("charge-1", `{"amount":10}`))
+	}()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, newIdempotentRequest("charge-1", `{"amount":10}`))
+	if rec.Code != http.StatusInternalServerError || !strings.Contains(rec.Body.String(), "new Idempotency-Key") {
+		t.Fatalf("expected a retry to be asked for a new key, got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestIdempotencyMiddlewareReleasesUnauthorized(t *testing.T) {
+	useMemoryIdempotencyStore(t)
+	calls := 0
+	handler := IdempotencyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), newIdempotentRequest("charge-1", `{"amount":10}`))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, newIdempotentRequest("charge-1", `{"amount":10}`))
+
+	if calls != 2 || rec.Code != http.StatusCreated {
+		t.Fatalf("expected the retry to run after a 401, got %d after %d calls", rec.Code, calls)
+	}
+}
+
+func TestIdempotencyMiddlewareIgnoresKeyWithoutCredentials(t *testing.T) {
+	store := useMemoryIdempotencyStore(t)
+	calls := 0
+	handler := IdempotencyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	for i := 0; i < 2; i++ {
+		r := httptest.NewRequest(http.MethodPost, "/register/athlete", strings.NewReader(`{}`))
+		r.Header.Set(IdempotencyKeyHeader, "shared-key")
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+	}
+
+	if calls != 2 || len(store.keys) != 0 {
+		t.Fatalf("expected anonymous requests to run without a key, ran %d times with %d keys", calls, len(store.keys))
+	}
+}
+
+func TestIdempotencyMiddlewareRenewsLeaseOfSlowRequest(t *testing.T) {
+	useMemoryIdempotencyStore(t)
+	previous := idempotencyLease
+	idempotencyLease = 30 * time.Millisecond
+	t.Cleanup(func() { idempotencyLease = previous })
+
+	calls := 0
+	entered := make(chan struct{})
+	unblock := make(chan struct{})
+	handler := IdempotencyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		close(entered)
+		<-unblock
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	done := make(chan struct{})
+	go func() {
+		handler.ServeHTTP(httptest.NewRecorder(), newIdempotentRequest("charge-1", `{"amount":10}`))
+		close(done)
+	}()
+	<-entered
+	time.Sleep(4 * idempotencyLease)
+
+	retry := httptest.NewRecorder()
+	handler.ServeHTTP(retry, newIdempotentRequest("charge-1", `{"amount":10}`))
+	close(unblock)
+	<-done
+
+	if retry.Code != http.StatusConflict || calls != 1 {
+		t.Fatalf("expected 409 while the slow request outlives its first lease, got %d after %d calls", retry.Code, calls)
+	}
+}
+
+func TestIdempotencyMiddlewareReclaimsExpiredLease(t *testing.T) {
+	store := useMemoryIdempotencyStore(t)
+	hash := requestFingerprint(http.MethodPost, "/admin/collections/charge-card", []byte(`{"amount":10}`))
+	scope, _ := idempotencyScope(newIdempotentRequest("charge-1", ""))
+	// Left processing by a server that died while running the request
+	store.keys[scope+"/charge-1"] = storedIdempotencyKey{requestHash: hash, status: "processing"}
+	store.leases[scope+"/charge-1"] = time.Now().Add(-time.Second)
+
+	calls := 0
+	handler := IdempotencyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, newIdempotentRequest("charge-1", `{"amount":10}`))
+	if calls != 1 || rec.Code != http.StatusCreated {
+		t.Fatalf("expected the retry to run once the lease ran out, got %d after %d calls", rec.Code, calls)
+	}
+}
+
+func TestRequireIdempotencyKey(t *testing.T) {
+	calls := 0
+	handler := RequireIdempotencyKey(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/collections/charge-card", strings.NewReader(`{}`)))
+	if rec.Code != http.StatusBadRequest || calls != 0 {
+		t.Fatalf("expected 400 without the header, got %d after %d calls", rec.Code, calls)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, newIdempotentRequest("charge-1", `{}`))
+	if rec.Code != http.StatusOK || calls != 1 {
+		t.Fatalf("expected the request to run with the header, got %d after %d calls", rec.Code, calls)
+	}
+}
@@ -16,9 +16,14 @@ import (
 
 var db *sql.DB
 
-// SetDB sets the database connection for suspension checks
+// lookupAccountStatus reports whether a user is suspended or deleted. Tests replace it
+// to run the middleware without a database.
+var lookupAccountStatus = checkUserSuspensionOrDeletion
+
+// SetDB sets the database connection for suspension checks and idempotency keys
 func SetDB(database *sql.DB) {
 	db = database
+	idempotencyKeys = postgresIdempotencyStore{db: database}
 }
 
 // JWTAuthMiddleware validates JWT tokens and checks user roles.
@@ -55,7 +60,7 @@ func JWTAuthMiddleware(isAllowAnyoneWithValidToken bool, allowedRoles ...context
 
 			// Check if user is suspended or deleted (skip for superadmin and IT)
 			if userRole != contextUtils.RoleSuperAdmin && userRole != contextUtils.RoleIT {
-				suspended, deleted, statusErr := checkUserSuspensionOrDeletion(ctx, claims.UserID.String())
+				suspended, deleted, statusErr := lookupAccountStatus(ctx, claims.UserID.String())
 				if statusErr != nil {
 					log.Printf("Error checking account status for user %s: %v", claims.UserID, statusErr)
 					// Continue despite error - don't block legitimate users if DB query fails
@@ -74,6 +79,7 @@ func JWTAuthMiddleware(isAllowAnyoneWithValidToken bool, allowedRoles ...context
 				// Add the claims to the request context for use in handlers
 				ctx = context.WithValue(ctx, contextUtils.RoleKey, userRole)
 				ctx = context.WithValue(ctx, contextUtils.UserIDKey, claims.UserID)
+				ctx = context.WithValue(ctx, contextUtils.IPAddressKey, GetRealIP(r))
 				next.ServeHTTP(w, r.WithContext(ctx))
 			} else {
 				responseHandlers.RespondWithError(w, errLib.New("You do not have permission to access this resource", http.StatusForbidden))
@@ -172,28 +178,30 @@ func checkUserSuspensionOrDeletion(ctx context.Context, userID string) (bool, bo
 		return false, false, err
 	}
 
+	suspended, deleted := accountStatus(suspendedAt, suspensionExpiresAt, deletedAt, time.Now())
+	return suspended, deleted, nil
+}
+
+// accountStatus decides from a user's row whether they are suspended or deleted at now
+func accountStatus(suspendedAt, suspensionExpiresAt, deletedAt sql.NullTime, now time.Time) (suspended, deleted bool) {
 	// Check if user account is deleted
 	if deletedAt.Valid {
-		return false, true, nil
+		return false, true
 	}
 
 	// Check if user is suspended
 	if !suspendedAt.Valid {
 		// Not suspended
-		return false, false, nil
+		return false, false
 	}
 
 	// Check if suspension has expired
-	if suspensionExpiresAt.Valid {
-		now := time.Now().UTC()
-		if now.After(suspensionExpiresAt.Time) {
-			// Suspension has expired - user should be unsuspended
-			// But we'll return false here and let them access (auto-unsuspension)
-			// Ideally you'd want a cron job to clear expired suspensions
-			return false, false, nil
-		}
+	if suspensionExpiresAt.Valid && now.After(suspensionExpiresAt.Time) {
+		// Suspension has expired - let them in. The suspension job lifts it properly
+		// (memberships, Stripe billing) within its next run.
+		return false, false
 	}
 
 	// User is currently suspended
-	return true, false, nil
+	return true, false
 }
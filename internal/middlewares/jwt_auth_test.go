@@ -0,0 +1,81 @@
+package middlewares
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"api/config"
+	jwtLib "api/internal/libs/jwt"
+	contextUtils "api/utils/context"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountStatus(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	at := func(t time.Time) sql.NullTime { return sql.NullTime{Time: t, Valid: true} }
+	none := sql.NullTime{}
+
+	tests := []struct {
+		name                 string
+		suspendedAt, expires sql.NullTime
+		deletedAt            sql.NullTime
+		suspended, deleted   bool
+	}{
+		{"active account", none, none, none, false, false},
+		{"indefinite suspension", at(now.Add(-time.Hour)), none, none, true, false},
+		{"timed suspension still running", at(now.Add(-time.Hour)), at(now.Add(time.Hour)), none, true, false},
+		{"timed suspension ran out before the job lifted it", at(now.Add(-48 * time.Hour)), at(now.Add(-time.Minute)), none, false, false},
+		{"deleted account", at(now.Add(-time.Hour)), none, at(now), false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suspended, deleted := accountStatus(tt.suspendedAt, tt.expires, tt.deletedAt, now)
+			assert.Equal(t, tt.suspended, suspended)
+			assert.Equal(t, tt.deleted, deleted)
+		})
+	}
+}
+
+func TestJWTAuthMiddlewareSuspension(t *testing.T) {
+	previousSecret := config.Env.JwtConfig.Secret
+	config.Env.JwtConfig.Secret = "jwt-auth-test-secret"
+	t.Cleanup(func() { config.Env.JwtConfig.Secret = previousSecret })
+
+	var suspended, deleted bool
+	previousLookup := lookupAccountStatus
+	lookupAccountStatus = func(context.Context, string) (bool, bool, error) { return suspended, deleted, nil }
+	t.Cleanup(func() { lookupAccountStatus = previousLookup })
+
+	handler := JWTAuthMiddleware(true)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	serve := func(role contextUtils.CtxRole) int {
+		token, err := jwtLib.SignJWT(jwtLib.CustomClaims{UserID: uuid.New(), RoleInfo: &jwtLib.RoleInfo{Role: string(role)}})
+		require.Nil(t, err)
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	suspended, deleted = true, false
+	assert.Equal(t, http.StatusForbidden, serve(contextUtils.RoleAthlete), "suspended users are paused")
+	assert.Equal(t, http.StatusNoContent, serve(contextUtils.RoleSuperAdmin), "super admins are never paused")
+
+	suspended = false
+	assert.Equal(t, http.StatusNoContent, serve(contextUtils.RoleAthlete), "lifted or expired suspensions resume access")
+
+	deleted = true
+	assert.Equal(t, http.StatusUnauthorized, serve(contextUtils.RoleAthlete))
+}
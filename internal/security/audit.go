@@ -8,12 +8,20 @@ import (
 	"strings"
 	"time"
 
+	auditValues "api/internal/domains/audit/audit_logs/values"
+	errLib "api/internal/libs/errors"
 	"api/internal/libs/logger"
 )
 
+// ChainVerifier verifies the hash chain of the audit log
+type ChainVerifier interface {
+	VerifyChain(ctx context.Context) (auditValues.ChainVerification, *errLib.CommonError)
+}
+
 // SecurityAudit performs comprehensive security validation
 type SecurityAudit struct {
-	logger *logger.StructuredLogger
+	logger        *logger.StructuredLogger
+	chainVerifier ChainVerifier
 }
 
 // AuditResult represents the result of a security audit
@@ -46,10 +54,12 @@ const (
 	SecurityLevelSecure   SecurityLevel = "SECURE"
 )
 
-// NewSecurityAudit creates a new security audit instance
-func NewSecurityAudit() *SecurityAudit {
+// NewSecurityAudit creates a new security audit instance. chainVerifier checks the
+// audit log has not been tampered with; without one log integrity fails.
+func NewSecurityAudit(chainVerifier ChainVerifier) *SecurityAudit {
 	return &SecurityAudit{
-		logger: logger.WithComponent("security-audit"),
+		logger:        logger.WithComponent("security-audit"),
+		chainVerifier: chainVerifier,
 	}
 }
 
@@ -69,7 +79,7 @@ func (s *SecurityAudit) PerformComprehensiveAudit(ctx context.Context) *AuditRes
 	result.Categories["authentication"] = s.auditAuthentication() 
 	result.Categories["authorization"] = s.auditAuthorization()
 	result.Categories["input_validation"] = s.auditInputValidation()
-	result.Categories["logging"] = s.auditLogging()
+	result.Categories["logging"] = s.auditLogging(ctx)
 	result.Categories["configuration"] = s.auditConfiguration()
 	result.Categories["network_security"] = s.auditNetworkSecurity()
 	result.Categories["data_protection"] = s.auditDataProtection()
@@ -268,7 +278,7 @@ func (s *SecurityAudit) auditInputValidation() CategoryResult {
 }
 
 // auditLogging checks logging and monitoring
-func (s *SecurityAudit) auditLogging() CategoryResult {
+func (s *SecurityAudit) auditLogging(ctx context.Context) CategoryResult {
 	result := CategoryResult{
 		Score:    0,
 		MaxScore: 8,
@@ -285,11 +295,11 @@ func (s *SecurityAudit) auditLogging() CategoryResult {
 	}
 	
 	// Check log integrity
-	if s.checkLogIntegrity() {
+	if issue := s.checkLogIntegrity(ctx); issue == "" {
 		result.Score += 2
 		result.Passed = append(result.Passed, "Log integrity protection in place")
 	} else {
-		result.Issues = append(result.Issues, "Implement log integrity protection")
+		result.Issues = append(result.Issues, issue)
 	}
 	
 	// Check log monitoring
@@ -531,8 +541,22 @@ func (s *SecurityAudit) checkSecurityLogging() bool {
 	return true // Structured logging implemented
 }
 
-func (s *SecurityAudit) checkLogIntegrity() bool {
-	return false // Log integrity protection not implemented
+// checkLogIntegrity verifies the hash chain of audit.audit_logs, which admin changes are
+// recorded in, and returns the issue found, if any
+func (s *SecurityAudit) checkLogIntegrity(ctx context.Context) string {
+	if s.chainVerifier == nil {
+		return "Implement log integrity protection"
+	}
+
+	verification, err := s.chainVerifier.VerifyChain(ctx)
+	if err != nil {
+		s.logger.Error("Failed to verify audit log chain", err)
+		return "Audit log integrity could not be verified"
+	}
+	if !verification.Valid {
+		return fmt.Sprintf("Audit log chain broken at entry %d: %s", verification.BrokenAtID, verification.Reason)
+	}
+	return ""
 }
 
 func (s *SecurityAudit) checkLogMonitoring() bool {
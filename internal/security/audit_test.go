@@ -0,0 +1,53 @@
+package security
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	auditValues "api/internal/domains/audit/audit_logs/values"
+	errLib "api/internal/libs/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeChainVerifier struct {
+	result auditValues.ChainVerification
+	err    *errLib.CommonError
+}
+
+func (f fakeChainVerifier) VerifyChain(context.Context) (auditValues.ChainVerification, *errLib.CommonError) {
+	return f.result, f.err
+}
+
+func TestCheckLogIntegrity(t *testing.T) {
+	tests := []struct {
+		name     string
+		verifier ChainVerifier
+		want     string
+	}{
+		{"intact chain", fakeChainVerifier{result: auditValues.ChainVerification{Checked: 3, Valid: true}}, ""},
+		{"broken chain", fakeChainVerifier{result: auditValues.ChainVerification{Checked: 2, BrokenAtID: 2, Reason: "hash does not match contents"}},
+			"Audit log chain broken at entry 2: hash does not match contents"},
+		{"verification failed", fakeChainVerifier{err: errLib.New("Internal server error when reading audit logs", http.StatusInternalServerError)},
+			"Audit log integrity could not be verified"},
+		{"no verifier", nil, "Implement log integrity protection"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			audit := NewSecurityAudit(tt.verifier)
+			assert.Equal(t, tt.want, audit.checkLogIntegrity(context.Background()))
+		})
+	}
+}
+
+func TestAuditLoggingScoresLogIntegrity(t *testing.T) {
+	broken := NewSecurityAudit(fakeChainVerifier{result: auditValues.ChainVerification{BrokenAtID: 7, Reason: "entry was removed"}})
+	result := broken.auditLogging(context.Background())
+	assert.Contains(t, result.Issues, "Audit log chain broken at entry 7: entry was removed")
+	assert.NotContains(t, result.Passed, "Log integrity protection in place")
+
+	intact := NewSecurityAudit(fakeChainVerifier{result: auditValues.ChainVerification{Valid: true}})
+	assert.Equal(t, result.Score+2, intact.auditLogging(context.Background()).Score)
+}
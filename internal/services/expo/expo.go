@@ -0,0 +1,221 @@
+package expo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"api/config"
+)
+
+const (
+	// maxMessagesPerRequest is the most push messages Expo accepts in one send request
+	maxMessagesPerRequest = 100
+
+	// maxReceiptIDsPerRequest is the most ticket IDs Expo accepts in one receipts request
+	maxReceiptIDsPerRequest = 1000
+
+	// maxConcurrentRequests caps how many requests to Expo are in flight at once
+	maxConcurrentRequests = 6
+)
+
+// ErrDeviceNotRegistered is the error Expo reports for a token whose app was uninstalled
+// or whose push permission was revoked. Such tokens should not be used again.
+const ErrDeviceNotRegistered = "DeviceNotRegistered"
+
+type Service struct {
+	Client      *http.Client
+	BaseURL     string
+	AccessToken string
+}
+
+// Message is a single push notification to one device
+type Message struct {
+	To    string                 `json:"to"`
+	Title string                 `json:"title"`
+	Body  string                 `json:"body"`
+	Data  map[string]interface{} `json:"data,omitempty"`
+	Sound string                 `json:"sound"`
+}
+
+// Details carries the machine readable error Expo attaches to failed tickets and receipts
+type Details struct {
+	Error string `json:"error,omitempty"`
+}
+
+// Ticket is Expo's answer for one message: whether it was accepted and, if so, the ID
+// its receipt can be fetched with
+type Ticket struct {
+	Status  string   `json:"status"`
+	ID      string   `json:"id,omitempty"`
+	Message string   `json:"message,omitempty"`
+	Details *Details `json:"details,omitempty"`
+}
+
+// Receipt tells whether Apple or Google accepted a message Expo had accepted
+type Receipt struct {
+	Status  string   `json:"status"`
+	Message string   `json:"message,omitempty"`
+	Details *Details `json:"details,omitempty"`
+}
+
+// ErrorCode returns the ticket's Expo error code, or its message when there is none
+func (t Ticket) ErrorCode() string {
+	return errorCode(t.Details, t.Message)
+}
+
+// ErrorCode returns the receipt's Expo error code, or its message when there is none
+func (r Receipt) ErrorCode() string {
+	return errorCode(r.Details, r.Message)
+}
+
+func errorCode(details *Details, message string) string {
+	if details != nil && details.Error != "" {
+		return details.Error
+	}
+	return message
+}
+
+type sendResponse struct {
+	Data []Ticket `json:"data"`
+}
+
+type receiptsResponse struct {
+	Data map[string]Receipt `json:"data"`
+}
+
+// GetExpoService initializes and returns a new Expo push service using the configured
+// base URL and access token.
+//
+// Returns:
+//   - *Service: A pointer to the initialized Expo service.
+func GetExpoService() *Service {
+	return &Service{
+		Client:      &http.Client{Timeout: 30 * time.Second},
+		BaseURL:     strings.TrimRight(config.Env.ExpoPushBaseURL, "/"),
+		AccessToken: config.Env.ExpoAccessToken,
+	}
+}
+
+// SendMessages sends the messages in chunks Expo accepts, a few chunks at a time, and
+// returns one ticket per message in the same order. Messages in a chunk whose request
+// failed get an error ticket, so every message is accounted for.
+func (s *Service) SendMessages(ctx context.Context, messages []Message) []Ticket {
+	tickets := make([]Ticket, len(messages))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentRequests)
+
+	for i, batch := range chunk(messages, maxMessagesPerRequest) {
+		offset := i * maxMessagesPerRequest
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(offset int, batch []Message) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			var response sendResponse
+			if err := s.post(ctx, "/push/send", batch, &response); err != nil {
+				log.Printf("[EXPO] Failed to send %d push messages: %v", len(batch), err)
+				for j := range batch {
+					tickets[offset+j] = Ticket{Status: "error", Message: err.Error()}
+				}
+				return
+			}
+
+			for j := range batch {
+				if j < len(response.Data) {
+					tickets[offset+j] = response.Data[j]
+				} else {
+					tickets[offset+j] = Ticket{Status: "error", Message: "Expo returned no ticket for this message"}
+				}
+			}
+		}(offset, batch)
+	}
+
+	wg.Wait()
+	return tickets
+}
+
+// GetReceipts fetches the receipts of the given tickets, keyed by ticket ID. Tickets
+// whose receipt is not ready yet, or whose request failed, are left out so they can be
+// asked for again later.
+func (s *Service) GetReceipts(ctx context.Context, ticketIDs []string) map[string]Receipt {
+	receipts := make(map[string]Receipt, len(ticketIDs))
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	sem := make(chan struct{}, maxConcurrentRequests)
+
+	for _, batch := range chunk(ticketIDs, maxReceiptIDsPerRequest) {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(batch []string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			var response receiptsResponse
+			if err := s.post(ctx, "/push/getReceipts", map[string][]string{"ids": batch}, &response); err != nil {
+				log.Printf("[EXPO] Failed to fetch %d push receipts: %v", len(batch), err)
+				return
+			}
+
+			mu.Lock()
+			for id, receipt := range response.Data {
+				receipts[id] = receipt
+			}
+			mu.Unlock()
+		}(batch)
+	}
+
+	wg.Wait()
+	return receipts
+}
+
+func (s *Service) post(ctx context.Context, path string, payload interface{}, out interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.BaseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if s.AccessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+s.AccessToken)
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("expo returned status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// chunk splits items into consecutive slices of at most size items
+func chunk[T any](items []T, size int) [][]T {
+	chunks := make([][]T, 0, (len(items)+size-1)/size)
+	for start := 0; start < len(items); start += size {
+		chunks = append(chunks, items[start:min(start+size, len(items))])
+	}
+	return chunks
+}
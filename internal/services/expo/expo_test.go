@@ -0,0 +1,158 @@
+package expo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChunk(t *testing.T) {
+	items := make([]int, 250)
+	for i := range items {
+		items[i] = i
+	}
+
+	chunks := chunk(items, 100)
+
+	require.Len(t, chunks, 3)
+	require.Len(t, chunks[0], 100)
+	require.Len(t, chunks[1], 100)
+	require.Len(t, chunks[2], 50)
+	require.Equal(t, 100, chunks[1][0])
+	require.Equal(t, 249, chunks[2][49])
+}
+
+func TestChunkExactMultiple(t *testing.T) {
+	chunks := chunk(make([]string, 200), 100)
+
+	require.Len(t, chunks, 2)
+	require.Len(t, chunks[1], 100)
+}
+
+func TestChunkEmpty(t *testing.T) {
+	require.Empty(t, chunk([]string{}, 100))
+}
+
+func TestTicketErrorCode(t *testing.T) {
+	require.Equal(t, ErrDeviceNotRegistered, Ticket{Status: "error", Message: "not registered", Details: &Details{Error: ErrDeviceNotRegistered}}.ErrorCode())
+	require.Equal(t, "expo returned status 500", Ticket{Status: "error", Message: "expo returned status 500"}.ErrorCode())
+}
+
+// newTestService returns a Service talking to handler instead of Expo
+func newTestService(t *testing.T, handler http.HandlerFunc) *Service {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return &Service{Client: server.Client(), BaseURL: server.URL, AccessToken: "test-token"}
+}
+
+func TestSendMessagesKeepsTicketOrderAcrossChunks(t *testing.T) {
+	service := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/push/send", r.URL.Path)
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+
+		var batch []Message
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&batch))
+		assert.LessOrEqual(t, len(batch), maxMessagesPerRequest)
+
+		response := sendResponse{Data: make([]Ticket, len(batch))}
+		for i, message := range batch {
+			response.Data[i] = Ticket{Status: "ok", ID: "ticket-" + message.To}
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(response))
+	})
+
+	messages := make([]Message, 2*maxMessagesPerRequest+50)
+	for i := range messages {
+		messages[i] = Message{To: fmt.Sprintf("token-%d", i)}
+	}
+
+	tickets := service.SendMessages(context.Background(), messages)
+
+	require.Len(t, tickets, len(messages))
+	for i, ticket := range tickets {
+		require.Equal(t, "ok", ticket.Status)
+		require.Equal(t, "ticket-"+messages[i].To, ticket.ID)
+	}
+}
+
+func TestSendMessagesFailedChunk(t *testing.T) {
+	service := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		var batch []Message
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&batch))
+
+		switch batch[0].To {
+		case "token-0":
+			w.WriteHeader(http.StatusInternalServerError)
+		case fmt.Sprintf("token-%d", maxMessagesPerRequest):
+			// Expo answered with a ticket missing for the last message
+			response := sendResponse{Data: make([]Ticket, len(batch)-1)}
+			for i := range response.Data {
+				response.Data[i] = Ticket{Status: "ok", ID: fmt.Sprintf("ticket-%d", i)}
+			}
+			assert.NoError(t, json.NewEncoder(w).Encode(response))
+		}
+	})
+
+	messages := make([]Message, maxMessagesPerRequest+10)
+	for i := range messages {
+		messages[i] = Message{To: fmt.Sprintf("token-%d", i)}
+	}
+
+	tickets := service.SendMessages(context.Background(), messages)
+
+	require.Len(t, tickets, len(messages))
+	for _, ticket := range tickets[:maxMessagesPerRequest] {
+		require.Equal(t, "error", ticket.Status)
+		require.Equal(t, "expo returned status 500", ticket.ErrorCode())
+	}
+	for _, ticket := range tickets[maxMessagesPerRequest : len(tickets)-1] {
+		require.Equal(t, "ok", ticket.Status)
+	}
+	require.Equal(t, "error", tickets[len(tickets)-1].Status)
+}
+
+func TestGetReceipts(t *testing.T) {
+	service := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/push/getReceipts", r.URL.Path)
+
+		var request map[string][]string
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		ids := request["ids"]
+		assert.LessOrEqual(t, len(ids), maxReceiptIDsPerRequest)
+
+		if ids[0] == "ticket-0" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		response := receiptsResponse{Data: map[string]Receipt{}}
+		for _, id := range ids {
+			if id == "ticket-not-ready" {
+				continue
+			}
+			response.Data[id] = Receipt{Status: "error", Details: &Details{Error: ErrDeviceNotRegistered}}
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(response))
+	})
+
+	ids := make([]string, maxReceiptIDsPerRequest+5)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("ticket-%d", i)
+	}
+	ids[len(ids)-1] = "ticket-not-ready"
+
+	receipts := service.GetReceipts(context.Background(), ids)
+
+	// The first chunk failed and one receipt was not ready; both are left out to be asked for again
+	require.Len(t, receipts, 4)
+	for _, id := range ids[maxReceiptIDsPerRequest : len(ids)-1] {
+		require.Equal(t, ErrDeviceNotRegistered, receipts[id].ErrorCode())
+	}
+	require.NotContains(t, receipts, "ticket-0")
+}
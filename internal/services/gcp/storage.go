@@ -5,6 +5,7 @@ import (
 	"api/internal/libs/errors"
 	"cloud.google.com/go/storage"
 	"context"
+	"errors"
 	"fmt"
 	"google.golang.org/api/option"
 	"io"
@@ -136,25 +137,35 @@ func GeneratePublicFileURL(fileName string) string {
 	return fmt.Sprintf("https://storage.googleapis.com/%s/%s", bucketName, encodedFileName)
 }
 
-// DeleteFileFromGCP deletes a single file from GCP storage by its public URL
-func DeleteFileFromGCP(publicURL string) *errLib.CommonError {
-	client, gcpClientErr := getGCPClient()
-	if gcpClientErr != nil {
-		return gcpClientErr
-	}
-
-	// Extract the file path from the public URL
+// ObjectPathFromURL returns the path in the bucket of a file from its public URL.
+// It fails for URLs that do not point into the bucket.
+func ObjectPathFromURL(publicURL string) (string, *errLib.CommonError) {
 	// URL format: https://storage.googleapis.com/rise-sports/path/to/file.ext
 	prefix := fmt.Sprintf("https://storage.googleapis.com/%s/", bucketName)
 	if !strings.HasPrefix(publicURL, prefix) {
-		return errLib.New("Invalid GCP URL format", http.StatusBadRequest)
+		return "", errLib.New("Invalid GCP URL format", http.StatusBadRequest)
 	}
 
 	// Get the object path and decode URL encoding
 	objectPath := strings.TrimPrefix(publicURL, prefix)
 	decodedPath, decodeErr := url.QueryUnescape(objectPath)
 	if decodeErr != nil {
-		return errLib.New("Failed to decode file path", http.StatusBadRequest)
+		return "", errLib.New("Failed to decode file path", http.StatusBadRequest)
+	}
+
+	return decodedPath, nil
+}
+
+// DeleteFileFromGCP deletes a single file from GCP storage by its public URL
+func DeleteFileFromGCP(publicURL string) *errLib.CommonError {
+	client, gcpClientErr := getGCPClient()
+	if gcpClientErr != nil {
+		return gcpClientErr
+	}
+
+	decodedPath, pathErr := ObjectPathFromURL(publicURL)
+	if pathErr != nil {
+		return pathErr
 	}
 
 	bucket := client.Bucket(bucketName)
@@ -200,3 +211,83 @@ func DeleteOldProfileImages(userFolder string, folder string) *errLib.CommonErro
 	
 	return nil
 }
+
+// UploadFileToGCP stores data at objectPath without handing out a public URL for it.
+// It is meant for files such as data exports that are only served through the API.
+func UploadFileToGCP(data []byte, objectPath, contentType string) *errLib.CommonError {
+	client, gcpClientErr := getGCPClient()
+	if gcpClientErr != nil {
+		return gcpClientErr
+	}
+	defer client.Close()
+
+	writer := client.Bucket(bucketName).Object(objectPath).NewWriter(context.Background())
+	writer.ContentType = contentType
+
+	if _, writerErr := writer.Write(data); writerErr != nil {
+		log.Printf("Failed to upload %s to GCP Storage: %v", objectPath, writerErr)
+		return errLib.New("Failed to upload file to storage", http.StatusInternalServerError)
+	}
+
+	if writerErr := writer.Close(); writerErr != nil {
+		log.Printf("Failed to finish uploading %s to GCP Storage: %v", objectPath, writerErr)
+		return errLib.New("Failed to upload file to storage", http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+// ReadObjectFromGCP returns the contents of the file at objectPath
+func ReadObjectFromGCP(objectPath string) ([]byte, *errLib.CommonError) {
+	client, gcpClientErr := getGCPClient()
+	if gcpClientErr != nil {
+		return nil, gcpClientErr
+	}
+	defer client.Close()
+
+	reader, readerErr := client.Bucket(bucketName).Object(objectPath).NewReader(context.Background())
+	if errors.Is(readerErr, storage.ErrObjectNotExist) {
+		return nil, errLib.New("File not found in storage", http.StatusNotFound)
+	}
+	if readerErr != nil {
+		log.Printf("Failed to open %s in GCP Storage: %v", objectPath, readerErr)
+		return nil, errLib.New("Failed to read file from storage", http.StatusInternalServerError)
+	}
+	defer reader.Close()
+
+	data, ioErr := io.ReadAll(reader)
+	if ioErr != nil {
+		log.Printf("Failed to read %s from GCP Storage: %v", objectPath, ioErr)
+		return nil, errLib.New("Failed to read file from storage", http.StatusInternalServerError)
+	}
+
+	return data, nil
+}
+
+// ReadFileFromGCP returns the contents of a file by its public URL
+func ReadFileFromGCP(publicURL string) ([]byte, *errLib.CommonError) {
+	objectPath, pathErr := ObjectPathFromURL(publicURL)
+	if pathErr != nil {
+		return nil, pathErr
+	}
+
+	return ReadObjectFromGCP(objectPath)
+}
+
+// DeleteObjectFromGCP deletes the file at objectPath. A file that is already gone is
+// not an error, so deleting can safely be retried.
+func DeleteObjectFromGCP(objectPath string) *errLib.CommonError {
+	client, gcpClientErr := getGCPClient()
+	if gcpClientErr != nil {
+		return gcpClientErr
+	}
+	defer client.Close()
+
+	deleteErr := client.Bucket(bucketName).Object(objectPath).Delete(context.Background())
+	if deleteErr != nil && !errors.Is(deleteErr, storage.ErrObjectNotExist) {
+		log.Printf("Failed to delete file from GCP: %s, error: %v", objectPath, deleteErr)
+		return errLib.New("Failed to delete file from storage", http.StatusInternalServerError)
+	}
+
+	return nil
+}
@@ -12,8 +12,9 @@ type Key string
 type CtxRole string
 
 const (
-	UserIDKey Key = "userId"
-	RoleKey   Key = "role"
+	UserIDKey    Key = "userId"
+	RoleKey      Key = "role"
+	IPAddressKey Key = "ipAddress"
 )
 
 const (
@@ -90,3 +91,14 @@ func IsStaff(ctx context.Context) (bool, *errLib.CommonError) {
 		return false, nil
 	}
 }
+
+// GetIPAddress retrieves the client IP address the request came from, or an empty string
+// when the context holds none (e.g. background jobs).
+func GetIPAddress(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	ip, _ := ctx.Value(IPAddressKey).(string)
+	return ip
+}
@@ -0,0 +1,65 @@
+package email
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"strings"
+
+	errLib "api/internal/libs/errors"
+)
+
+// SendProgressReportEmail sends a family an athlete's periodic progress report.
+// highlights and goals are preformatted lines, e.g. "Shooting: 3 → 4 (out of 5)".
+func SendProgressReportEmail(to, firstName, athleteName, period string, evaluations int, highlights, goals []string) *errLib.CommonError {
+	body := ProgressReportBody(firstName, athleteName, period, evaluations, highlights, goals)
+	if err := SendEmail(to, fmt.Sprintf("%s's Progress Report for %s - Rise", athleteName, period), body); err != nil {
+		log.Println("failed to send progress report email:", err.Message)
+		return err
+	}
+	log.Printf("Progress report email sent successfully to %s", to)
+	return nil
+}
+
+// ProgressReportBody creates the email body for a periodic progress report
+func ProgressReportBody(firstName, athleteName, period string, evaluations int, highlights, goals []string) string {
+	evaluationsLabel := "1 evaluation"
+	if evaluations != 1 {
+		evaluationsLabel = fmt.Sprintf("%d evaluations", evaluations)
+	}
+
+	var sections strings.Builder
+	if len(highlights) > 0 {
+		sections.WriteString(`
+		<div class="info-box">
+			<strong>SKILL RATINGS:</strong>` + listItems(highlights) + `
+		</div>`)
+	}
+	if len(goals) > 0 {
+		sections.WriteString(`
+		<div class="info-box">
+			<strong>CURRENT GOALS:</strong>` + listItems(goals) + `
+		</div>`)
+	}
+
+	content := fmt.Sprintf(`
+		<p>Hey %s,</p>
+		<p>Here is %s's progress report for %s. Coaches completed %s this period.</p>
+		%s
+		<p>The full report, with coach comments and rating history, can be downloaded as a PDF from %s's profile in the Rise app.</p>
+
+		<p style="margin-top: 30px;"><strong>— The Rise Team</strong></p>
+	`, html.EscapeString(firstName), html.EscapeString(athleteName), html.EscapeString(period),
+		evaluationsLabel, sections.String(), html.EscapeString(athleteName))
+	return baseTemplate("Progress Report", content)
+}
+
+func listItems(lines []string) string {
+	var b strings.Builder
+	b.WriteString(`<ul style="margin: 10px 0 0 0;">`)
+	for _, line := range lines {
+		b.WriteString("<li>" + html.EscapeString(line) + "</li>")
+	}
+	b.WriteString("</ul>")
+	return b.String()
+}
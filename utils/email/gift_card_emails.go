@@ -0,0 +1,95 @@
+package email
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"strings"
+)
+
+// SendGiftCardEmail delivers a gift card's code to its recipient
+func SendGiftCardEmail(to, recipientName, senderName, amount, code, message, expiresOn string) {
+	body := GiftCardBody(recipientName, senderName, amount, code, message, expiresOn)
+	if err := SendEmail(to, "You've Received a Rise Gift Card", body); err != nil {
+		log.Println("failed to send gift card email:", err.Message)
+	} else {
+		log.Printf("Gift card email sent successfully to %s", to)
+	}
+}
+
+// SendGiftCardReceiptEmail confirms to the buyer that their gift card was sent
+func SendGiftCardReceiptEmail(to, purchaserName, recipientName, recipientEmail, amount string) {
+	body := GiftCardReceiptBody(purchaserName, recipientName, recipientEmail, amount)
+	if err := SendEmail(to, "Your Gift Card Is On Its Way - Rise", body); err != nil {
+		log.Println("failed to send gift card receipt email:", err.Message)
+	} else {
+		log.Printf("Gift card receipt email sent successfully to %s", to)
+	}
+}
+
+// GiftCardBody creates the email body delivering a gift card. Names and the message
+// are typed in by the buyer, so they are escaped.
+func GiftCardBody(recipientName, senderName, amount, code, message, expiresOn string) string {
+	note := ""
+	if strings.TrimSpace(message) != "" {
+		note = fmt.Sprintf(`
+		<div class="info-box">
+			<strong>A NOTE FROM %s:</strong>
+			<p style="margin: 10px 0 0 0;">%s</p>
+		</div>
+`, strings.ToUpper(html.EscapeString(senderName)), html.EscapeString(message))
+	}
+	expiry := "This gift card never expires."
+	if expiresOn != "" {
+		expiry = fmt.Sprintf("Redeem this gift card before %s.", expiresOn)
+	}
+
+	content := fmt.Sprintf(`
+		<p>Hey %s,</p>
+		<p><strong>%s</strong> sent you a Rise gift card!</p>
+
+		<div class="stat-box">
+			<p class="stat-number">%s</p>
+			<p class="stat-label">Gift Card Value</p>
+		</div>
+
+		<div class="stat-box">
+			<p class="stat-number" style="letter-spacing: 4px;">%s</p>
+			<p class="stat-label">Gift Card Code</p>
+		</div>
+%s
+		<div class="alert-box">
+			<strong>HOW TO REDEEM:</strong>
+			<p style="margin: 10px 0 0 0;">Enter the code in the Rise app to add its value to your balance. Your balance can be used toward memberships, events, haircuts and credit packages. %s</p>
+		</div>
+
+		<p style="margin-top: 30px;"><strong>— The Rise Team</strong></p>
+	`, html.EscapeString(recipientName), html.EscapeString(senderName), amount, code, note, expiry)
+	return baseTemplate("Your Rise Gift Card", content)
+}
+
+// GiftCardReceiptBody creates the email body confirming a gift card purchase
+func GiftCardReceiptBody(purchaserName, recipientName, recipientEmail, amount string) string {
+	content := fmt.Sprintf(`
+		<p>Hey %s,</p>
+		<p><strong>Thanks for your purchase!</strong> Your gift card has been emailed to its recipient.</p>
+
+		<div class="stat-box">
+			<p class="stat-number">%s</p>
+			<p class="stat-label">Gift Card Value</p>
+		</div>
+
+		<div class="info-box">
+			<strong>SENT TO:</strong>
+			<ul style="margin: 15px 0 0 0; padding-left: 20px; list-style: none;">
+				<li><strong>Name:</strong> %s</li>
+				<li><strong>Email:</strong> %s</li>
+			</ul>
+		</div>
+
+		<p>You can see the gift cards you've bought in the Rise app.</p>
+
+		<p style="margin-top: 30px;"><strong>— The Rise Team</strong></p>
+	`, html.EscapeString(purchaserName), amount, html.EscapeString(recipientName), html.EscapeString(recipientEmail))
+	return baseTemplate("Gift Card Sent", content)
+}
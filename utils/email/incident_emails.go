@@ -0,0 +1,54 @@
+package email
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"strings"
+
+	errLib "api/internal/libs/errors"
+)
+
+// SendIncidentNotificationEmail tells a family that their athlete was involved in
+// an incident. injury and treatment are left out of the email when empty.
+func SendIncidentNotificationEmail(to, firstName, athleteName, occurredOn, location, summary, injury, treatment string) *errLib.CommonError {
+	body := IncidentNotificationBody(firstName, athleteName, occurredOn, location, summary, injury, treatment)
+	if err := SendEmail(to, fmt.Sprintf("Incident Report for %s - Rise", athleteName), body); err != nil {
+		log.Println("failed to send incident notification email:", err.Message)
+		return err
+	}
+	log.Printf("Incident notification email sent successfully to %s", to)
+	return nil
+}
+
+// IncidentNotificationBody creates the email body for an incident notification
+func IncidentNotificationBody(firstName, athleteName, occurredOn, location, summary, injury, treatment string) string {
+	where := ""
+	if location != "" {
+		where = " at " + html.EscapeString(location)
+	}
+
+	var details strings.Builder
+	details.WriteString(`
+		<div class="info-box">
+			<strong>WHAT HAPPENED:</strong> ` + html.EscapeString(summary))
+	if injury != "" {
+		details.WriteString(`<br><strong>INJURY:</strong> ` + html.EscapeString(injury))
+	}
+	if treatment != "" {
+		details.WriteString(`<br><strong>CARE GIVEN:</strong> ` + html.EscapeString(treatment))
+	}
+	details.WriteString(`
+		</div>`)
+
+	content := fmt.Sprintf(`
+		<p>Hey %s,</p>
+		<p>We want to let you know that %s was involved in an incident on %s%s.</p>
+		%s
+		<p>Our staff will follow up with you. If you have any questions or your athlete needs medical attention, please contact us right away.</p>
+
+		<p style="margin-top: 30px;"><strong>— The Rise Team</strong></p>
+	`, html.EscapeString(firstName), html.EscapeString(athleteName), html.EscapeString(occurredOn),
+		where, details.String())
+	return baseTemplate("Incident Report", content)
+}
@@ -0,0 +1,36 @@
+package email
+
+import (
+	"fmt"
+	"html"
+	"log"
+)
+
+// SendDataExportReadyEmail tells a customer the personal data export they asked for
+// can be downloaded
+func SendDataExportReadyEmail(to, firstName, expiresOn string) {
+	body := DataExportReadyBody(firstName, expiresOn)
+	if err := SendEmail(to, "Your Data Export Is Ready - Rise", body); err != nil {
+		log.Println("failed to send data export email:", err.Message)
+	} else {
+		log.Printf("Data export email sent successfully to %s", to)
+	}
+}
+
+// DataExportReadyBody creates the email body for a finished data export
+func DataExportReadyBody(firstName, expiresOn string) string {
+	content := fmt.Sprintf(`
+		<p>Hey %s,</p>
+		<p>The copy of your personal data you asked for is ready.</p>
+
+		<div class="info-box">
+			<strong>HOW TO GET IT:</strong>
+			<p style="margin: 10px 0 0 0;">Open the Rise app and go to your account's privacy settings to download the ZIP file. It can be downloaded until %s.</p>
+		</div>
+
+		<p>If you didn't ask for this export, please contact us right away.</p>
+
+		<p style="margin-top: 30px;"><strong>— The Rise Team</strong></p>
+	`, html.EscapeString(firstName), html.EscapeString(expiresOn))
+	return baseTemplate("Your Data Export", content)
+}
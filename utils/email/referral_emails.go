@@ -0,0 +1,51 @@
+package email
+
+import (
+	"fmt"
+	"html"
+	"log"
+)
+
+// SendReferralRewardEmail tells a customer about a referral reward they were given
+func SendReferralRewardEmail(to, firstName, reason, reward, couponCode string) {
+	body := ReferralRewardBody(firstName, reason, reward, couponCode)
+	if err := SendEmail(to, "You've Earned a Referral Reward - Rise", body); err != nil {
+		log.Println("failed to send referral reward email:", err.Message)
+	} else {
+		log.Printf("Referral reward email sent successfully to %s", to)
+	}
+}
+
+// ReferralRewardBody creates the email body for a referral reward. couponCode is set
+// for coupon rewards.
+func ReferralRewardBody(firstName, reason, reward, couponCode string) string {
+	coupon := ""
+	if couponCode != "" {
+		coupon = fmt.Sprintf(`
+		<div class="stat-box">
+			<p class="stat-number" style="letter-spacing: 4px;">%s</p>
+			<p class="stat-label">Your Discount Code</p>
+		</div>
+
+		<div class="info-box">
+			<strong>HOW TO USE IT:</strong>
+			<p style="margin: 10px 0 0 0;">Enter the code at checkout in the Rise app. It can be used once.</p>
+		</div>
+`, html.EscapeString(couponCode))
+	}
+
+	content := fmt.Sprintf(`
+		<p>Hey %s,</p>
+		<p>%s</p>
+
+		<div class="stat-box">
+			<p class="stat-number">%s</p>
+			<p class="stat-label">Referral Reward</p>
+		</div>
+%s
+		<p>Thanks for spreading the word about Rise!</p>
+
+		<p style="margin-top: 30px;"><strong>— The Rise Team</strong></p>
+	`, html.EscapeString(firstName), html.EscapeString(reason), html.EscapeString(reward), coupon)
+	return baseTemplate("Your Referral Reward", content)
+}
@@ -155,3 +155,24 @@ func SendMembershipCheckoutLinkEmail(to, firstName, planName, checkoutURL string
 		log.Printf("Membership checkout link email sent successfully to %s", to)
 	}
 }
+
+// SendSuspensionLiftedEmail lets a customer know their account suspension has ended
+func SendSuspensionLiftedEmail(to, firstName string) {
+	body := SuspensionLiftedBody(firstName)
+	if err := SendEmail(to, "Your Account Is Active Again - Rise", body); err != nil {
+		log.Println("failed to send suspension lifted email:", err.Message)
+	} else {
+		log.Printf("Suspension lifted email sent successfully to %s", to)
+	}
+}
+
+// SendPlanPriceChangeEmail gives a member advance notice of a new price for their membership
+func SendPlanPriceChangeEmail(to, firstName, planName, newPrice, effectiveDate string) *errLib.CommonError {
+	body := PlanPriceChangeBody(firstName, planName, newPrice, effectiveDate)
+	if err := SendEmail(to, "Upcoming Change to Your Membership Price - Rise", body); err != nil {
+		log.Println("failed to send plan price change email:", err.Message)
+		return err
+	}
+	log.Printf("Plan price change email sent successfully to %s", to)
+	return nil
+}
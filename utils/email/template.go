@@ -558,3 +558,39 @@ func EmailChangeVerificationBody(firstName, newEmail, verificationURL string) st
 	`, firstName, newEmail, verificationURL, verificationURL)
 	return baseTemplate("Verify Your New Email", content)
 }
+
+func SuspensionLiftedBody(firstName string) string {
+	content := fmt.Sprintf(`
+		<p>Hey %s,</p>
+		<p>Your account suspension has ended.</p>
+
+		<div class="success-box">
+			<strong>✓ YOUR ACCOUNT IS ACTIVE</strong>
+			<p style="margin: 10px 0 0 0;">You can log in, book and use your membership again. Any membership billing that was paused has resumed.</p>
+		</div>
+
+		<p>Questions? Contact us at the front desk.</p>
+
+		<p style="margin-top: 30px;"><strong>— The Rise Team</strong></p>
+	`, firstName)
+	return baseTemplate("Account Active Again", content)
+}
+
+func PlanPriceChangeBody(firstName, planName, newPrice, effectiveDate string) string {
+	content := fmt.Sprintf(`
+		<p>Hey %s,</p>
+		<p>We're writing to let you know ahead of time that the price of your <strong>%s</strong> membership is changing.</p>
+
+		<div class="stat-box">
+			<p class="stat-number">%s</p>
+			<p class="stat-label">Starting %s</p>
+		</div>
+
+		<p>The new price applies from your renewal on <strong>%s</strong>. Until then you keep paying your current price, and there's nothing you need to do.</p>
+
+		<p>Questions? Contact us at the front desk.</p>
+
+		<p style="margin-top: 30px;"><strong>— The Rise Team</strong></p>
+	`, firstName, planName, newPrice, effectiveDate, effectiveDate)
+	return baseTemplate("Membership Price Change", content)
+}
@@ -0,0 +1,72 @@
+package recurrence
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BlackoutAction controls what happens to an occurrence that falls on a blackout date.
+type BlackoutAction string
+
+const (
+	// BlackoutSkip drops the occurrence entirely (e.g. the facility is closed).
+	BlackoutSkip BlackoutAction = "skip"
+	// BlackoutFlag still schedules the occurrence but marks it for staff review.
+	BlackoutFlag BlackoutAction = "flag"
+)
+
+func (a BlackoutAction) Valid() bool {
+	return a == BlackoutSkip || a == BlackoutFlag
+}
+
+// Blackout is a range of calendar dates, inclusive on both ends, at a single location.
+type Blackout struct {
+	ID       uuid.UUID
+	Name     string
+	StartsOn time.Time
+	EndsOn   time.Time
+	Action   BlackoutAction
+}
+
+func (b Blackout) Covers(date time.Time) bool {
+	d := Date(date)
+	return !d.Before(Date(b.StartsOn)) && !d.After(Date(b.EndsOn))
+}
+
+// Occurrence is a generated calendar date together with the blackout that flagged it, if any.
+type Occurrence struct {
+	Date time.Time
+	// BlackoutID is uuid.Nil unless a flag-type blackout covers Date.
+	BlackoutID uuid.UUID
+}
+
+// ApplyBlackouts removes dates covered by a skip blackout and annotates dates covered
+// by a flag blackout. Skip wins when both kinds cover the same date.
+func ApplyBlackouts(dates []time.Time, blackouts []Blackout) []Occurrence {
+	occurrences := make([]Occurrence, 0, len(dates))
+
+	for _, date := range dates {
+		skip := false
+		flaggedBy := uuid.Nil
+
+		for _, b := range blackouts {
+			if !b.Covers(date) {
+				continue
+			}
+			if b.Action == BlackoutSkip {
+				skip = true
+				break
+			}
+			if flaggedBy == uuid.Nil {
+				flaggedBy = b.ID
+			}
+		}
+
+		if !skip {
+			occurrences = append(occurrences, Occurrence{Date: date, BlackoutID: flaggedBy})
+		}
+	}
+
+	return occurrences
+}
@@ -0,0 +1,450 @@
+// Package recurrence implements the subset of RFC 5545 recurrence rules used to
+// schedule events and practices: DAILY, WEEKLY and MONTHLY frequencies with
+// INTERVAL, BYDAY (including ordinals such as 2TU or -1FR for monthly rules),
+// BYMONTHDAY, WKST, COUNT and UNTIL, plus EXDATE handling.
+//
+// Rules are expanded into calendar dates only. Turning a date into an instant is
+// the caller's job (see timezone.At), because occurrences are wall-clock times at
+// the facility and must not drift across DST changes.
+package recurrence
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+)
+
+// MaxOccurrences caps the number of dates a single rule may expand to, so a
+// mistyped rule cannot insert years of events in one request.
+const MaxOccurrences = 730
+
+var weekdayCodes = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+var weekdayNames = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// WeekdayNum is a BYDAY entry. N is the ordinal within the month (1 = first,
+// -1 = last); zero means every such weekday.
+type WeekdayNum struct {
+	Weekday time.Weekday
+	N       int
+}
+
+func (w WeekdayNum) String() string {
+	if w.N == 0 {
+		return weekdayNames[w.Weekday]
+	}
+	return strconv.Itoa(w.N) + weekdayNames[w.Weekday]
+}
+
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	WeekStart  time.Weekday
+	Count      int
+	// Until is the last calendar date (inclusive) an occurrence may fall on.
+	Until time.Time
+}
+
+// WeeklyOn returns the rule equivalent to the legacy "every <day>" recurrence.
+func WeeklyOn(day time.Weekday) Rule {
+	return Rule{Freq: Weekly, Interval: 1, WeekStart: time.Monday, ByDay: []WeekdayNum{{Weekday: day}}}
+}
+
+// Parse parses an RRULE value such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=10".
+// A leading "RRULE:" is accepted.
+func Parse(value string) (Rule, error) {
+	value = strings.TrimSpace(value)
+	value = strings.TrimPrefix(strings.ToUpper(value), "RRULE:")
+	if value == "" {
+		return Rule{}, fmt.Errorf("rrule is empty")
+	}
+
+	rule := Rule{Interval: 1, WeekStart: time.Monday}
+
+	for _, part := range strings.Split(value, ";") {
+		if part == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(part, "=")
+		if !ok || val == "" {
+			return Rule{}, fmt.Errorf("malformed rrule part %q", part)
+		}
+
+		switch key {
+		case "FREQ":
+			switch Frequency(val) {
+			case Daily, Weekly, Monthly:
+				rule.Freq = Frequency(val)
+			default:
+				return Rule{}, fmt.Errorf("unsupported FREQ %q: expected DAILY, WEEKLY or MONTHLY", val)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return Rule{}, fmt.Errorf("INTERVAL must be a positive integer")
+			}
+			rule.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return Rule{}, fmt.Errorf("COUNT must be a positive integer")
+			}
+			rule.Count = n
+		case "UNTIL":
+			until, err := parseUntil(val)
+			if err != nil {
+				return Rule{}, err
+			}
+			rule.Until = until
+		case "BYDAY":
+			for _, code := range strings.Split(val, ",") {
+				wd, err := parseWeekdayNum(code)
+				if err != nil {
+					return Rule{}, err
+				}
+				rule.ByDay = append(rule.ByDay, wd)
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(val, ",") {
+				n, err := strconv.Atoi(day)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return Rule{}, fmt.Errorf("invalid BYMONTHDAY %q", day)
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, n)
+			}
+		case "WKST":
+			wd, ok := weekdayCodes[val]
+			if !ok {
+				return Rule{}, fmt.Errorf("invalid WKST %q", val)
+			}
+			rule.WeekStart = wd
+		default:
+			return Rule{}, fmt.Errorf("unsupported rrule part %q", key)
+		}
+	}
+
+	if rule.Freq == "" {
+		return Rule{}, fmt.Errorf("rrule must include FREQ")
+	}
+	if rule.Count > 0 && !rule.Until.IsZero() {
+		return Rule{}, fmt.Errorf("rrule must not include both COUNT and UNTIL")
+	}
+	for _, wd := range rule.ByDay {
+		if wd.N != 0 && rule.Freq != Monthly {
+			return Rule{}, fmt.Errorf("ordinal BYDAY values such as %s are only valid with FREQ=MONTHLY", wd)
+		}
+	}
+	if len(rule.ByMonthDay) > 0 && rule.Freq != Monthly {
+		return Rule{}, fmt.Errorf("BYMONTHDAY is only valid with FREQ=MONTHLY")
+	}
+
+	return rule, nil
+}
+
+func parseWeekdayNum(code string) (WeekdayNum, error) {
+	code = strings.TrimSpace(code)
+	if len(code) < 2 {
+		return WeekdayNum{}, fmt.Errorf("invalid BYDAY %q", code)
+	}
+	wd, ok := weekdayCodes[code[len(code)-2:]]
+	if !ok {
+		return WeekdayNum{}, fmt.Errorf("invalid BYDAY %q", code)
+	}
+	n := 0
+	if prefix := code[:len(code)-2]; prefix != "" {
+		var err error
+		n, err = strconv.Atoi(prefix)
+		if err != nil || n == 0 || n < -5 || n > 5 {
+			return WeekdayNum{}, fmt.Errorf("invalid BYDAY ordinal %q", code)
+		}
+	}
+	return WeekdayNum{Weekday: wd, N: n}, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return Date(t), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid UNTIL %q: expected YYYYMMDD or YYYYMMDDTHHMMSSZ", value)
+}
+
+// String formats the rule in canonical RRULE form (without the "RRULE:" prefix).
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		codes := make([]string, len(r.ByDay))
+		for i, wd := range r.ByDay {
+			codes[i] = wd.String()
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, d := range r.ByMonthDay {
+			days[i] = strconv.Itoa(d)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+weekdayNames[r.WeekStart])
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// EndingBefore returns a copy of the rule, anchored at dtstart, that stops on the
+// day before date. It is used to split a series when editing "this and following"
+// occurrences.
+func (r Rule) EndingBefore(dtstart, date time.Time) Rule {
+	until := Date(date).AddDate(0, 0, -1)
+	if !r.Until.IsZero() && !until.Before(r.Until) {
+		return r
+	}
+	if r.Count > 0 {
+		// A COUNT-bounded series that already finished before date is unchanged;
+		// otherwise COUNT is replaced by UNTIL, as the two are mutually exclusive.
+		if dates, err := r.Expand(dtstart, until, nil); err == nil && len(dates) >= r.Count {
+			return r
+		}
+		r.Count = 0
+	}
+	r.Until = until
+	return r
+}
+
+// Date normalises t to midnight UTC on its calendar date as written.
+func Date(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Expand returns the calendar dates (midnight UTC) generated by the rule starting on
+// dtstart, excluding any dates in exdates. The series ends at the rule's COUNT or
+// UNTIL, or at until when it is non-zero, whichever comes first. COUNT is applied
+// before EXDATE, as in RFC 5545.
+func (r Rule) Expand(dtstart, until time.Time, exdates []time.Time) ([]time.Time, error) {
+	start := Date(dtstart)
+
+	end := time.Time{}
+	if !until.IsZero() {
+		end = Date(until)
+	}
+	if !r.Until.IsZero() && (end.IsZero() || r.Until.Before(end)) {
+		end = r.Until
+	}
+	if end.IsZero() && r.Count == 0 {
+		return nil, fmt.Errorf("recurrence has no end: set COUNT or UNTIL in the rule, or an end date")
+	}
+
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	excluded := make(map[time.Time]bool, len(exdates))
+	for _, d := range exdates {
+		excluded[Date(d)] = true
+	}
+
+	var dates []time.Time
+	generated := 0
+	done := false
+
+	emit := func(candidates []time.Time) error {
+		sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+		for _, d := range candidates {
+			if d.Before(start) {
+				continue
+			}
+			if !end.IsZero() && d.After(end) {
+				done = true
+				return nil
+			}
+			generated++
+			if generated > MaxOccurrences {
+				return fmt.Errorf("recurrence expands to more than %d occurrences", MaxOccurrences)
+			}
+			if !excluded[d] {
+				dates = append(dates, d)
+			}
+			if r.Count > 0 && generated >= r.Count {
+				done = true
+				return nil
+			}
+		}
+		return nil
+	}
+
+	// Each period (day, week or month) is visited in order. A rule that can never
+	// match (e.g. BYMONTHDAY=31 every second month from February) would loop
+	// forever with COUNT alone, so the number of empty periods in a row is bounded.
+	idle := 0
+	for period := 0; !done && idle <= MaxOccurrences; period += interval {
+		var periodStart time.Time
+		var candidates []time.Time
+		switch r.Freq {
+		case Daily:
+			periodStart = start.AddDate(0, 0, period)
+			candidates = r.dailyCandidates(periodStart)
+		case Weekly:
+			periodStart, candidates = r.weeklyCandidates(start, period)
+		case Monthly:
+			periodStart, candidates = r.monthlyCandidates(start, period)
+		default:
+			return nil, fmt.Errorf("unsupported frequency %q", r.Freq)
+		}
+
+		if !end.IsZero() && periodStart.After(end) {
+			break
+		}
+
+		before := generated
+		if err := emit(candidates); err != nil {
+			return nil, err
+		}
+		if generated == before {
+			idle++
+		} else {
+			idle = 0
+		}
+	}
+
+	return dates, nil
+}
+
+func (r Rule) dailyCandidates(day time.Time) []time.Time {
+	if len(r.ByDay) == 0 {
+		return []time.Time{day}
+	}
+	for _, wd := range r.ByDay {
+		if wd.Weekday == day.Weekday() {
+			return []time.Time{day}
+		}
+	}
+	return nil
+}
+
+func (r Rule) weeklyCandidates(start time.Time, period int) (time.Time, []time.Time) {
+	// Weeks are anchored on the week (per WKST) containing dtstart.
+	offset := (int(start.Weekday()) - int(r.WeekStart) + 7) % 7
+	weekStart := start.AddDate(0, 0, -offset+7*period)
+
+	days := r.ByDay
+	if len(days) == 0 {
+		days = []WeekdayNum{{Weekday: start.Weekday()}}
+	}
+
+	candidates := make([]time.Time, 0, len(days))
+	for _, wd := range days {
+		delta := (int(wd.Weekday) - int(r.WeekStart) + 7) % 7
+		candidates = append(candidates, weekStart.AddDate(0, 0, delta))
+	}
+	return weekStart, candidates
+}
+
+func (r Rule) monthlyCandidates(start time.Time, period int) (time.Time, []time.Time) {
+	first := time.Date(start.Year(), start.Month()+time.Month(period), 1, 0, 0, 0, 0, time.UTC)
+	last := first.AddDate(0, 1, -1)
+
+	var monthDays, weekdays []time.Time
+
+	for _, md := range r.ByMonthDay {
+		day := md
+		if md < 0 {
+			day = last.Day() + md + 1
+		}
+		if day >= 1 && day <= last.Day() {
+			monthDays = append(monthDays, first.AddDate(0, 0, day-1))
+		}
+	}
+
+	for _, wd := range r.ByDay {
+		firstMatch := first.AddDate(0, 0, (int(wd.Weekday)-int(first.Weekday())+7)%7)
+		switch {
+		case wd.N > 0:
+			d := firstMatch.AddDate(0, 0, 7*(wd.N-1))
+			if d.Month() == first.Month() {
+				weekdays = append(weekdays, d)
+			}
+		case wd.N < 0:
+			lastMatch := last.AddDate(0, 0, -((int(last.Weekday()) - int(wd.Weekday) + 7) % 7))
+			d := lastMatch.AddDate(0, 0, 7*(wd.N+1))
+			if d.Month() == first.Month() {
+				weekdays = append(weekdays, d)
+			}
+		default:
+			for d := firstMatch; d.Month() == first.Month(); d = d.AddDate(0, 0, 7) {
+				weekdays = append(weekdays, d)
+			}
+		}
+	}
+
+	// Per RFC 5545, BYMONTHDAY narrows BYDAY when both are set
+	// (e.g. BYDAY=FR;BYMONTHDAY=13 is every Friday the 13th).
+	var candidates []time.Time
+	switch {
+	case len(r.ByMonthDay) > 0 && len(r.ByDay) > 0:
+		for _, wd := range weekdays {
+			for _, md := range monthDays {
+				if wd.Equal(md) {
+					candidates = append(candidates, wd)
+					break
+				}
+			}
+		}
+	case len(r.ByMonthDay) > 0:
+		candidates = monthDays
+	default:
+		candidates = weekdays
+	}
+
+	if len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 {
+		// Per RFC 5545, months without the start's day of month are skipped.
+		if start.Day() <= last.Day() {
+			candidates = append(candidates, first.AddDate(0, 0, start.Day()-1))
+		}
+	}
+
+	return first, dedupe(candidates)
+}
+
+func dedupe(dates []time.Time) []time.Time {
+	seen := make(map[time.Time]bool, len(dates))
+	out := dates[:0]
+	for _, d := range dates {
+		if !seen[d] {
+			seen[d] = true
+			out = append(out, d)
+		}
+	}
+	return out
+}
@@ -0,0 +1,211 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func d(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func formatDates(dates []time.Time) []string {
+	out := make([]string, len(dates))
+	for i, date := range dates {
+		out[i] = date.Format("2006-01-02")
+	}
+	return out
+}
+
+func assertDates(t *testing.T, got []time.Time, want ...string) {
+	t.Helper()
+	g := formatDates(got)
+	if len(g) != len(want) {
+		t.Fatalf("got %d dates %v; want %d %v", len(g), g, len(want), want)
+	}
+	for i := range want {
+		if g[i] != want[i] {
+			t.Fatalf("date %d = %s; want %s (all: %v)", i, g[i], want[i], g)
+		}
+	}
+}
+
+func mustParse(t *testing.T, value string) Rule {
+	t.Helper()
+	rule, err := Parse(value)
+	if err != nil {
+		t.Fatalf("Parse(%q) error: %v", value, err)
+	}
+	return rule
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"FREQ=WEEKLY;BYDAY=MO,WE", "FREQ=WEEKLY;BYDAY=MO,WE"},
+		{"RRULE:freq=weekly;interval=2;byday=tu;count=6", "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU;COUNT=6"},
+		{"FREQ=MONTHLY;BYDAY=2TU,-1FR;UNTIL=20251231T235959Z", "FREQ=MONTHLY;BYDAY=2TU,-1FR;UNTIL=20251231"},
+		{"FREQ=MONTHLY;BYMONTHDAY=-1", "FREQ=MONTHLY;BYMONTHDAY=-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := mustParse(t, tt.in).String(); got != tt.want {
+				t.Errorf("String() = %s; want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRejectsInvalidRules(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"BYDAY=MO",
+		"FREQ=YEARLY",
+		"FREQ=WEEKLY;COUNT=0",
+		"FREQ=WEEKLY;COUNT=3;UNTIL=20250101",
+		"FREQ=WEEKLY;BYDAY=2MO",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=DAILY;BYMONTHDAY=3",
+		"FREQ=WEEKLY;BYSETPOS=1",
+	} {
+		if _, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q) succeeded; want error", in)
+		}
+	}
+}
+
+func TestExpandWeeklyMultipleDays(t *testing.T) {
+	rule := mustParse(t, "FREQ=WEEKLY;BYDAY=MO,WE")
+
+	// Starts on a Wednesday; the Monday of the first week is before dtstart and skipped.
+	dates, err := rule.Expand(d(2025, 9, 3), d(2025, 9, 15), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertDates(t, dates, "2025-09-03", "2025-09-08", "2025-09-10", "2025-09-15")
+}
+
+func TestExpandEveryOtherWeekWithCount(t *testing.T) {
+	rule := mustParse(t, "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH;COUNT=5")
+
+	dates, err := rule.Expand(d(2025, 9, 2), time.Time{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertDates(t, dates, "2025-09-02", "2025-09-04", "2025-09-16", "2025-09-18", "2025-09-30")
+}
+
+func TestExpandMonthlyByWeekday(t *testing.T) {
+	rule := mustParse(t, "FREQ=MONTHLY;BYDAY=2TU,-1FR;UNTIL=20251130")
+
+	dates, err := rule.Expand(d(2025, 9, 1), time.Time{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertDates(t, dates, "2025-09-09", "2025-09-26", "2025-10-14", "2025-10-31", "2025-11-11", "2025-11-28")
+}
+
+func TestExpandMonthlyByWeekdayAndMonthDay(t *testing.T) {
+	rule := mustParse(t, "FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13;COUNT=3")
+
+	dates, err := rule.Expand(d(2026, 1, 1), time.Time{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertDates(t, dates, "2026-02-13", "2026-03-13", "2026-11-13")
+
+	// A negative month day counts from the end of the month: the last day, when it is a weekend day.
+	rule = mustParse(t, "FREQ=MONTHLY;BYDAY=SA,SU;BYMONTHDAY=-1;UNTIL=20261231")
+
+	dates, err = rule.Expand(d(2026, 1, 1), time.Time{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertDates(t, dates, "2026-01-31", "2026-02-28", "2026-05-31", "2026-10-31")
+}
+
+func TestExpandMonthlySkipsShortMonths(t *testing.T) {
+	rule := mustParse(t, "FREQ=MONTHLY;COUNT=3")
+
+	dates, err := rule.Expand(d(2025, 1, 31), time.Time{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertDates(t, dates, "2025-01-31", "2025-03-31", "2025-05-31")
+}
+
+func TestExpandCountIncludesExdates(t *testing.T) {
+	rule := mustParse(t, "FREQ=DAILY;COUNT=4")
+
+	dates, err := rule.Expand(d(2025, 12, 23), time.Time{}, []time.Time{d(2025, 12, 25)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertDates(t, dates, "2025-12-23", "2025-12-24", "2025-12-26")
+}
+
+func TestExpandEndDateBoundsRule(t *testing.T) {
+	rule := WeeklyOn(time.Monday)
+
+	dates, err := rule.Expand(d(2023, 10, 2), d(2023, 10, 30), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dates) != 5 {
+		t.Fatalf("got %d Mondays; want 5", len(dates))
+	}
+}
+
+func TestExpandRequiresAnEnd(t *testing.T) {
+	if _, err := WeeklyOn(time.Monday).Expand(d(2025, 1, 1), time.Time{}, nil); err == nil {
+		t.Fatal("expected error for unbounded rule")
+	}
+}
+
+func TestExpandCapsOccurrences(t *testing.T) {
+	rule := mustParse(t, "FREQ=DAILY")
+	if _, err := rule.Expand(d(2025, 1, 1), d(2030, 1, 1), nil); err == nil {
+		t.Fatal("expected error when exceeding MaxOccurrences")
+	}
+}
+
+func TestEndingBefore(t *testing.T) {
+	rule := mustParse(t, "FREQ=WEEKLY;BYDAY=MO;COUNT=10")
+	start := d(2025, 9, 1)
+
+	split := rule.EndingBefore(start, d(2025, 9, 22))
+	if got := split.String(); got != "FREQ=WEEKLY;BYDAY=MO;UNTIL=20250921" {
+		t.Errorf("split rule = %s", got)
+	}
+
+	// A series that already ended is left untouched.
+	short := mustParse(t, "FREQ=WEEKLY;BYDAY=MO;COUNT=2")
+	if got := short.EndingBefore(start, d(2025, 10, 20)).String(); got != short.String() {
+		t.Errorf("finished series changed to %s", got)
+	}
+}
+
+func TestApplyBlackouts(t *testing.T) {
+	flagID := uuid.New()
+	blackouts := []Blackout{
+		{ID: uuid.New(), StartsOn: d(2025, 12, 24), EndsOn: d(2025, 12, 26), Action: BlackoutSkip},
+		{ID: flagID, StartsOn: d(2025, 12, 29), EndsOn: d(2025, 12, 29), Action: BlackoutFlag},
+	}
+
+	dates := []time.Time{d(2025, 12, 22), d(2025, 12, 24), d(2025, 12, 26), d(2025, 12, 29)}
+	occurrences := ApplyBlackouts(dates, blackouts)
+
+	if len(occurrences) != 2 {
+		t.Fatalf("got %d occurrences; want 2", len(occurrences))
+	}
+	if occurrences[0].BlackoutID != uuid.Nil {
+		t.Errorf("Dec 22 should not be flagged")
+	}
+	if occurrences[1].Date != d(2025, 12, 29) || occurrences[1].BlackoutID != flagID {
+		t.Errorf("Dec 29 should be flagged by %s, got %+v", flagID, occurrences[1])
+	}
+}
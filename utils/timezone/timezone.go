@@ -0,0 +1,127 @@
+package timezone
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultZone is the IANA zone used for facilities that have not configured one.
+// All of our existing facilities are in Calgary, so this matches the behaviour
+// the API had before locations carried their own timezone.
+const DefaultZone = "America/Edmonton"
+
+// RowQuerier is satisfied by both *sql.DB and *sql.Tx.
+type RowQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// IsValid reports whether name is a loadable IANA timezone such as "America/Toronto".
+// "Local" and the empty string are rejected so that a facility never silently
+// inherits whatever zone the server happens to run in.
+func IsValid(name string) bool {
+	name = strings.TrimSpace(name)
+	if name == "" || name == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
+
+// Load returns the *time.Location for name, falling back to DefaultZone when the
+// name is empty or unknown, and to a fixed MST offset if tzdata is unavailable.
+func Load(name string) *time.Location {
+	if IsValid(name) {
+		loc, _ := time.LoadLocation(strings.TrimSpace(name))
+		return loc
+	}
+	return Default()
+}
+
+// Default returns the DefaultZone location.
+func Default() *time.Location {
+	loc, err := time.LoadLocation(DefaultZone)
+	if err != nil {
+		return time.FixedZone("MST", -7*60*60)
+	}
+	return loc
+}
+
+// ForLocation looks up the configured timezone of a facility (location.locations).
+// Unknown or nil locations resolve to the default zone so callers can always
+// format and compute times without additional error handling.
+func ForLocation(ctx context.Context, q RowQuerier, locationID uuid.UUID) *time.Location {
+	if locationID == uuid.Nil {
+		return Default()
+	}
+
+	var name sql.NullString
+	err := q.QueryRowContext(ctx, "SELECT timezone FROM location.locations WHERE id = $1", locationID).Scan(&name)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("[TIMEZONE] Failed to look up timezone for location %s: %v", locationID, err)
+		}
+		return Default()
+	}
+
+	return Load(name.String)
+}
+
+// WeekStart returns midnight on the Monday (ISO week) of the week containing t,
+// as observed in loc.
+func WeekStart(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+
+	daysSinceMonday := (int(local.Weekday()) + 6) % 7
+
+	// time.Date normalises the day overflow, and building the value from calendar
+	// fields (rather than subtracting 24h multiples) keeps it correct across DST.
+	return time.Date(local.Year(), local.Month(), local.Day()-daysSinceMonday, 0, 0, 0, 0, loc)
+}
+
+// DayBounds returns the [start, end) instants of the calendar day containing t in loc.
+// On DST transition days the span is 23 or 25 hours.
+func DayBounds(t time.Time, loc *time.Location) (time.Time, time.Time) {
+	local := t.In(loc)
+	start := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	return start, start.AddDate(0, 0, 1)
+}
+
+// ParseWallClock parses a time of day such as "18:30:00" or "18:30:00+00:00" and
+// returns the wall-clock time it names in loc on the calendar date of on.
+//
+// Without an offset the value already is a wall-clock time in loc. With one it is
+// the instant at that offset on the date of on, converted to loc: "15:00:00+00:00"
+// on a July date in Edmonton is 09:00. days is how many calendar days the converted
+// time falls after that date, negative when before, e.g. -1 for "02:00:00+00:00" in
+// Edmonton. Recurrences convert once, on their first date, and keep the wall-clock
+// time, so occurrences do not shift when DST begins or ends.
+func ParseWallClock(value string, on time.Time, loc *time.Location) (hour, minute, second, days int, err error) {
+	value = strings.TrimSpace(value)
+
+	if t, parseErr := time.Parse("15:04:05", value); parseErr == nil {
+		return t.Hour(), t.Minute(), t.Second(), 0, nil
+	}
+
+	t, parseErr := time.Parse("15:04:05Z07:00", value)
+	if parseErr != nil {
+		return 0, 0, 0, 0, fmt.Errorf("invalid time of day %q", value)
+	}
+
+	local := time.Date(on.Year(), on.Month(), on.Day(), t.Hour(), t.Minute(), t.Second(), 0, t.Location()).In(loc)
+	onDate := time.Date(on.Year(), on.Month(), on.Day(), 0, 0, 0, 0, time.UTC)
+	localDate := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	return local.Hour(), local.Minute(), local.Second(), int(localDate.Sub(onDate).Hours() / 24), nil
+}
+
+// At returns the instant at which the wall clock in loc reads hour:minute:second on
+// the calendar date of date. The calendar date is taken from date as written, so a
+// recurrence bound of "2025-11-03T00:00:00Z" means November 3rd at the facility.
+func At(date time.Time, hour, minute, second int, loc *time.Location) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), hour, minute, second, 0, loc)
+}
@@ -0,0 +1,159 @@
+package timezone
+
+import (
+	"testing"
+	"time"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("failed to load %s: %v", name, err)
+	}
+	return loc
+}
+
+func TestIsValid(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected bool
+	}{
+		{"America/Edmonton", true},
+		{"America/Toronto", true},
+		{"UTC", true},
+		{"", false},
+		{"Local", false},
+		{"Mars/Olympus_Mons", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsValid(tt.name); got != tt.expected {
+				t.Errorf("IsValid(%q) = %v; want %v", tt.name, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLoadFallsBackToDefault(t *testing.T) {
+	if got := Load("not/a-zone").String(); got != DefaultZone {
+		t.Errorf("Load fallback = %s; want %s", got, DefaultZone)
+	}
+	if got := Load("America/Vancouver").String(); got != "America/Vancouver" {
+		t.Errorf("Load = %s; want America/Vancouver", got)
+	}
+}
+
+func TestWeekStart(t *testing.T) {
+	edmonton := mustLoad(t, "America/Edmonton")
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{
+			// Sunday 23:30 in Calgary is already Monday in UTC; the week must not roll over early.
+			name: "late sunday local stays in previous week",
+			now:  time.Date(2025, 3, 17, 5, 30, 0, 0, time.UTC),
+			want: time.Date(2025, 3, 10, 0, 0, 0, 0, edmonton),
+		},
+		{
+			name: "monday midnight local starts the week",
+			now:  time.Date(2025, 3, 17, 0, 0, 0, 0, edmonton),
+			want: time.Date(2025, 3, 17, 0, 0, 0, 0, edmonton),
+		},
+		{
+			// DST starts Sunday March 9 2025 at 02:00; the Monday before is still MST.
+			name: "week spanning spring forward",
+			now:  time.Date(2025, 3, 9, 12, 0, 0, 0, edmonton),
+			want: time.Date(2025, 3, 3, 0, 0, 0, 0, edmonton),
+		},
+		{
+			// DST ends Sunday November 2 2025 at 02:00.
+			name: "week spanning fall back",
+			now:  time.Date(2025, 11, 2, 23, 59, 0, 0, edmonton),
+			want: time.Date(2025, 10, 27, 0, 0, 0, 0, edmonton),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WeekStart(tt.now, edmonton)
+			if !got.Equal(tt.want) {
+				t.Errorf("WeekStart(%s) = %s; want %s", tt.now, got, tt.want)
+			}
+			if got.Hour() != 0 || got.Weekday() != time.Monday {
+				t.Errorf("WeekStart(%s) = %s; want local Monday midnight", tt.now, got)
+			}
+		})
+	}
+}
+
+func TestDayBoundsAcrossDST(t *testing.T) {
+	edmonton := mustLoad(t, "America/Edmonton")
+
+	start, end := DayBounds(time.Date(2025, 3, 9, 15, 0, 0, 0, edmonton), edmonton)
+	if got := end.Sub(start); got != 23*time.Hour {
+		t.Errorf("spring forward day length = %s; want 23h", got)
+	}
+
+	start, end = DayBounds(time.Date(2025, 11, 2, 15, 0, 0, 0, edmonton), edmonton)
+	if got := end.Sub(start); got != 25*time.Hour {
+		t.Errorf("fall back day length = %s; want 25h", got)
+	}
+}
+
+func TestParseWallClock(t *testing.T) {
+	edmonton := mustLoad(t, "America/Edmonton")
+	july := time.Date(2026, 7, 14, 0, 0, 0, 0, time.UTC)
+	january := time.Date(2026, 1, 14, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value      string
+		on         time.Time
+		h, m, s, d int
+		wantErr    bool
+	}{
+		{"18:30:00", july, 18, 30, 0, 0, false},
+		{"07:15:30", january, 7, 15, 30, 0, false},
+		{"15:00:00+00:00", july, 9, 0, 0, 0, false},
+		{"15:00:00+00:00", january, 8, 0, 0, 0, false},
+		{"18:30:00-06:00", july, 18, 30, 0, 0, false},
+		{"02:00:00+00:00", july, 20, 0, 0, -1, false},
+		{"23:30:00-10:00", july, 3, 30, 0, 1, false},
+		{"09:00", july, 0, 0, 0, 0, true},
+		{"25:00", july, 0, 0, 0, 0, true},
+		{"noon", july, 0, 0, 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value+" "+tt.on.Month().String(), func(t *testing.T) {
+			h, m, s, d, err := ParseWallClock(tt.value, tt.on, edmonton)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseWallClock(%q) error = %v; wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !tt.wantErr && (h != tt.h || m != tt.m || s != tt.s || d != tt.d) {
+				t.Errorf("ParseWallClock(%q) = %02d:%02d:%02d %+d days; want %02d:%02d:%02d %+d days", tt.value, h, m, s, d, tt.h, tt.m, tt.s, tt.d)
+			}
+		})
+	}
+}
+
+func TestAtKeepsWallClockAcrossDST(t *testing.T) {
+	edmonton := mustLoad(t, "America/Edmonton")
+
+	before := At(time.Date(2025, 10, 27, 0, 0, 0, 0, time.UTC), 18, 0, 0, edmonton)
+	after := At(time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC), 18, 0, 0, edmonton)
+
+	if before.Hour() != 18 || after.Hour() != 18 {
+		t.Fatalf("wall clock drifted: before=%s after=%s", before, after)
+	}
+	if got := after.Sub(before); got != 7*24*time.Hour+time.Hour {
+		t.Errorf("elapsed time across fall back = %s; want 169h", got)
+	}
+	if before.UTC().Hour() != 0 || after.UTC().Hour() != 1 {
+		t.Errorf("unexpected UTC hours: before=%s after=%s", before.UTC(), after.UTC())
+	}
+}